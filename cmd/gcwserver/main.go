@@ -11,13 +11,127 @@
 package main
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
 
 	"gocomicwriter/internal/backend"
 )
 
+const usage = `Usage:
+  gcwserver [serve]                                  run the HTTP server
+  gcwserver migrate                                  apply database migrations and exit
+  gcwserver token --email x@y [--ttl 24h] [--name N] print a bearer token for a user
+  gcwserver grant --email x@y --project-slug S [--role R] [--name N]
+                                                     grant a user a role on a project
+
+Configuration is read from the same environment variables as the server
+(DATABASE_URL/GCW_PG_DSN, GCW_AUTH_MODE, GCW_AUTH_SECRET, ...).
+`
+
+// errUsage marks invalid command lines; they exit with status 2 instead of 1.
+var errUsage = errors.New("usage")
+
 func main() {
-	if err := backend.Start(); err != nil {
-		log.Fatalf("gcwserver: %v", err)
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "gcwserver: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the subcommand named by args[0]; without arguments it starts the server.
+func run(args []string, stdout, stderr io.Writer) error {
+	cmd := "serve"
+	if len(args) > 0 {
+		cmd, args = args[0], args[1:]
 	}
+	switch cmd {
+	case "serve":
+		return backend.Start()
+	case "migrate":
+		fs := newFlagSet("migrate", stderr)
+		if err := parse(fs, args); err != nil {
+			return err
+		}
+		if err := backend.Migrate(); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(stdout, "migrations applied")
+		return nil
+	case "token":
+		fs := newFlagSet("token", stderr)
+		email := fs.String("email", "", "user email (token subject)")
+		name := fs.String("name", "", "display name stored for the user (static auth mode)")
+		ttl := fs.Duration("ttl", time.Hour, "token lifetime, at most 24h")
+		if err := parse(fs, args); err != nil {
+			return err
+		}
+		if *email == "" {
+			return usageError(stderr, "token: --email is required")
+		}
+		tok, exp, err := backend.IssueToken(*email, *name, *ttl)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(stdout, tok)
+		_, _ = fmt.Fprintf(stderr, "expires at %s\n", exp.UTC().Format(time.RFC3339))
+		return nil
+	case "grant":
+		fs := newFlagSet("grant", stderr)
+		email := fs.String("email", "", "user email")
+		slug := fs.String("project-slug", "", "project slug")
+		role := fs.String("role", "owner", "role to grant, e.g. owner or editor")
+		name := fs.String("name", "", "display name stored for the user")
+		if err := parse(fs, args); err != nil {
+			return err
+		}
+		if *email == "" || *slug == "" {
+			return usageError(stderr, "grant: --email and --project-slug are required")
+		}
+		m, err := backend.GrantMembership(*email, *name, *slug, *role)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(stdout, "granted %s role %q on project %s (id %d)\n", m.User, m.Role, *slug, m.ProjectID)
+		return nil
+	case "help", "-h", "-help", "--help":
+		_, _ = fmt.Fprint(stdout, usage)
+		return nil
+	}
+	return usageError(stderr, fmt.Sprintf("unknown command %q", cmd))
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("gcwserver "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+// parse parses flags and rejects positional arguments.
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		return usageError(fs.Output(), fmt.Sprintf("%s: unexpected argument %q", fs.Name(), fs.Arg(0)))
+	}
+	return nil
+}
+
+// usageError prints msg and the usage text to stderr.
+func usageError(stderr io.Writer, msg string) error {
+	_, _ = fmt.Fprintf(stderr, "gcwserver: %s\n\n%s", msg, usage)
+	return errUsage
 }
@@ -13,21 +13,31 @@ import (
 	"os"
 	"strings"
 
+	"gocomicwriter/internal/cli"
 	applog "gocomicwriter/internal/log"
 	"gocomicwriter/internal/ui"
 )
 
 func main() {
 	// Initialize structured logging using environment defaults
-	applog.Init(applog.FromEnv())
+	opts := applog.FromEnv()
 
 	if len(os.Args) >= 2 {
 		sub := strings.ToLower(os.Args[1])
+		if sub == "export" {
+			// Headless batch export: keep stderr to progress lines unless a log level is asked for.
+			if os.Getenv("GCW_LOG_LEVEL") == "" {
+				opts.Level = "warn"
+			}
+			applog.Init(opts)
+			os.Exit(cli.RunExport(os.Args[2:], os.Stderr))
+		}
 		if strings.HasPrefix(sub, "export") {
-			fmt.Println("Command-line exports have been moved to the UI. Please use the Export menu.")
-			os.Exit(2)
+			fmt.Fprintf(os.Stderr, "Unknown command %q. Use: gocomicwriter export --project <dir> --format <pdf|png|svg|cbz|epub>\n", os.Args[1])
+			os.Exit(cli.ExitInvalidOptions)
 		}
 	}
+	applog.Init(opts)
 
 	// UI-only launcher: optional first arg is a project directory to open.
 	var dir string
@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	applog "gocomicwriter/internal/log"
+)
+
+const (
+	// devAuthSecret signs tokens when GCW_AUTH_SECRET is unset. It is only fit for local development.
+	devAuthSecret = "dev-secret-change-me"
+	// defaultTokenTTL and maxTokenTTL bound the lifetime of issued tokens.
+	defaultTokenTTL = time.Hour
+	maxTokenTTL     = 24 * time.Hour
+	// dbTimeout bounds connecting and the admin commands' queries.
+	dbTimeout = 10 * time.Second
+)
+
+// Membership is a user's role on a project, as granted by GrantMembership.
+type Membership struct {
+	ProjectID int64  `json:"project_id"`
+	User      string `json:"user"`
+	Role      string `json:"role"`
+}
+
+// openDB connects to the configured database, creating it when it does not exist yet.
+func openDB(ctx context.Context, cfg Config) (*sql.DB, error) {
+	db, err := sql.Open("pgx", cfg.DBURL)
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		if !isInvalidCatalog(err) {
+			_ = db.Close()
+			return nil, fmt.Errorf("ping db: %w", err)
+		}
+		if err2 := tryCreateMissingDatabase(ctx, cfg.DBURL); err2 != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("ping db: %w; additionally failed to create database: %v", err, err2)
+		}
+		// Retry ping after creating the database
+		if err3 := db.PingContext(ctx); err3 != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("ping db after create: %w", err3)
+		}
+	}
+	return db, nil
+}
+
+// openMigratedDB is openDB followed by applyMigrations, closing the connection on failure.
+func openMigratedDB(ctx context.Context, cfg Config) (*sql.DB, error) {
+	db, err := openDB(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyMigrations(ctx, db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	return db, nil
+}
+
+func closeDB(db *sql.DB) {
+	if err := db.Close(); err != nil {
+		applog.WithComponent("backend").Warn("db close", slog.Any("err", err))
+	}
+}
+
+// tokenTTL returns ttl, or defaultTokenTTL when it is not positive or longer than maxTokenTTL.
+func tokenTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > maxTokenTTL {
+		return defaultTokenTTL
+	}
+	return ttl
+}
+
+// upsertUser ensures a user row exists for email; a non-empty displayName replaces the stored one.
+func upsertUser(ctx context.Context, db *sql.DB, email, displayName string) error {
+	_, err := db.ExecContext(ctx, `INSERT INTO users(email, display_name) VALUES ($1, NULLIF($2,'') )
+		ON CONFLICT (email) DO UPDATE SET display_name = COALESCE(EXCLUDED.display_name, users.display_name)`, email, displayName)
+	return err
+}
+
+// grantMembership ensures the user exists and sets their role on the project given by id or, when pid
+// is 0, by slug. The role defaults to owner; an unknown or deleted project yields errProjectNotFound.
+func grantMembership(ctx context.Context, db *sql.DB, email, displayName, role string, pid int64, slug string) (Membership, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return Membership{}, errors.New("email required")
+	}
+	role = strings.TrimSpace(role)
+	if role == "" {
+		role = "owner"
+	}
+	if pid == 0 {
+		slug = strings.TrimSpace(slug)
+		if slug == "" {
+			return Membership{}, errors.New("project_id or project_slug required")
+		}
+		err := db.QueryRowContext(ctx, `SELECT id FROM projects WHERE slug = $1 AND deleted_at IS NULL`, slug).Scan(&pid)
+		if errors.Is(err, sql.ErrNoRows) {
+			return Membership{}, errProjectNotFound
+		}
+		if err != nil {
+			return Membership{}, err
+		}
+	}
+	if err := upsertUser(ctx, db, email, displayName); err != nil {
+		return Membership{}, err
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO project_members(user_id, project_id, role)
+		SELECT u.id, $2, $3 FROM users u WHERE u.email = $1
+		ON CONFLICT (user_id, project_id) DO UPDATE SET role = EXCLUDED.role`, email, pid, role); err != nil {
+		return Membership{}, err
+	}
+	return Membership{ProjectID: pid, User: email, Role: role}, nil
+}
+
+// Migrate applies pending database migrations without starting the HTTP server.
+func Migrate() error {
+	cfg := loadConfig()
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	db, err := openMigratedDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	closeDB(db)
+	return nil
+}
+
+// IssueToken signs a bearer token for email, valid for ttl (at most 24 hours), with the server's
+// secret. In static auth mode the user is created in the database first, as /api/auth/token does.
+func IssueToken(email, displayName string, ttl time.Duration) (string, time.Time, error) {
+	cfg := loadConfig()
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return "", time.Time{}, errors.New("email required")
+	}
+	if ttl <= 0 || ttl > maxTokenTTL {
+		return "", time.Time{}, fmt.Errorf("ttl must be between 1s and %s", maxTokenTTL)
+	}
+	if cfg.AuthSecret == devAuthSecret {
+		applog.WithComponent("backend").Warn("GCW_AUTH_SECRET not set; token is signed with the insecure dev secret")
+	}
+	if cfg.AuthMode == "static" {
+		ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+		defer cancel()
+		db, err := openMigratedDB(ctx, cfg)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		defer closeDB(db)
+		if err := upsertUser(ctx, db, email, displayName); err != nil {
+			return "", time.Time{}, fmt.Errorf("ensure user: %w", err)
+		}
+	}
+	exp := time.Now().Add(ttl)
+	tok, err := signToken(cfg.AuthSecret, email, exp)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return tok, exp, nil
+}
+
+// GrantMembership gives email the role on the project with the given slug, creating the user if needed.
+func GrantMembership(email, displayName, slug, role string) (Membership, error) {
+	cfg := loadConfig()
+	if strings.TrimSpace(slug) == "" {
+		return Membership{}, errors.New("project slug required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	db, err := openMigratedDB(ctx, cfg)
+	if err != nil {
+		return Membership{}, err
+	}
+	defer closeDB(db)
+	m, err := grantMembership(ctx, db, email, displayName, role, 0, slug)
+	if errors.Is(err, errProjectNotFound) {
+		return Membership{}, fmt.Errorf("project %q not found", strings.TrimSpace(slug))
+	}
+	return m, err
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTokenTTL(t *testing.T) {
+	cases := map[time.Duration]time.Duration{
+		0:                defaultTokenTTL,
+		-time.Minute:     defaultTokenTTL,
+		30 * time.Minute: 30 * time.Minute,
+		maxTokenTTL:      maxTokenTTL,
+		48 * time.Hour:   defaultTokenTTL,
+	}
+	for in, want := range cases {
+		if got := tokenTTL(in); got != want {
+			t.Errorf("tokenTTL(%s) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestIssueToken_DevMode(t *testing.T) {
+	t.Setenv("GCW_AUTH_MODE", "dev")
+	t.Setenv("GCW_AUTH_SECRET", "cli-secret")
+	tok, exp, err := IssueToken(" ops@example.com ", "", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	if d := time.Until(exp); d < 23*time.Hour || d > 24*time.Hour {
+		t.Fatalf("unexpected expiry in %s", d)
+	}
+	sub, err := verifyToken("cli-secret", tok)
+	if err != nil || sub != "ops@example.com" {
+		t.Fatalf("verify: sub=%q err=%v", sub, err)
+	}
+	if _, _, err := IssueToken("ops@example.com", "", 25*time.Hour); err == nil {
+		t.Fatalf("expected error for ttl above the limit")
+	}
+	if _, _, err := IssueToken("", "", time.Hour); err == nil {
+		t.Fatalf("expected error for missing email")
+	}
+}
+
+func TestGrantMembership_Integration(t *testing.T) {
+	db := openPGForTest(t)
+	defer func() { _ = db.Close() }()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	run := time.Now().UnixNano()
+	owner := fmt.Sprintf("grant-owner-%d@example.com", run)
+	editor := fmt.Sprintf("grant-editor-%d@example.com", run)
+	if err := upsertUser(ctx, db, owner, ""); err != nil {
+		t.Fatalf("upsert owner: %v", err)
+	}
+	p, err := createProject(ctx, db, owner, fmt.Sprintf("Grant %d", run), "")
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	m, err := grantMembership(ctx, db, editor, "Ed", "editor", 0, p.Slug)
+	if err != nil {
+		t.Fatalf("grant: %v", err)
+	}
+	if m.ProjectID != p.ID || m.Role != "editor" || m.User != editor {
+		t.Fatalf("unexpected membership: %+v", m)
+	}
+	// Granting again updates the role instead of failing
+	if m, err = grantMembership(ctx, db, editor, "", "", p.ID, ""); err != nil || m.Role != "owner" {
+		t.Fatalf("regrant: %+v, %v", m, err)
+	}
+	var role, name string
+	if err := db.QueryRowContext(ctx, `SELECT pm.role, COALESCE(u.display_name, '') FROM project_members pm
+		JOIN users u ON u.id = pm.user_id WHERE u.email = $1 AND pm.project_id = $2`, editor, p.ID).Scan(&role, &name); err != nil {
+		t.Fatalf("read membership: %v", err)
+	}
+	if role != "owner" || name != "Ed" {
+		t.Fatalf("stored role=%q name=%q", role, name)
+	}
+	if _, err := grantMembership(ctx, db, editor, "", "editor", 0, fmt.Sprintf("missing-%d", run)); !errors.Is(err, errProjectNotFound) {
+		t.Fatalf("expected errProjectNotFound, got %v", err)
+	}
+}
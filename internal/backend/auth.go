@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// defaultMaxSession is how long a session may be kept alive by refreshing when GCW_AUTH_MAX_SESSION is unset.
+const defaultMaxSession = 7 * 24 * time.Hour
+
+var (
+	errTokenExpired   = errors.New("token expired")
+	errSessionExpired = errors.New("session expired; sign in again")
+)
+
+// refreshClaims returns the claims of a token continuing c's session at now: valid for ttl, but not
+// past the end of the session. Tokens from before sessions were recorded count as started maxTokenTTL
+// before their expiry. It fails with errSessionExpired once the session is over.
+func refreshClaims(c tokenClaims, ttl, maxSession time.Duration, now time.Time) (tokenClaims, time.Time, error) {
+	start := time.Unix(c.Ses, 0)
+	if c.Ses == 0 {
+		start = time.Unix(c.Exp, 0).Add(-maxTokenTTL)
+	}
+	end := start.Add(maxSession)
+	if !now.Before(end) {
+		return tokenClaims{}, end, errSessionExpired
+	}
+	exp := now.Add(tokenTTL(ttl))
+	if exp.After(end) {
+		exp = end
+	}
+	return tokenClaims{Sub: c.Sub, Exp: exp.Unix(), Ses: start.Unix()}, end, nil
+}
+
+// serveRefresh handles POST /api/auth/refresh: it exchanges a still valid bearer token for a new one of
+// the same session (sliding sessions). The optional JSON body { "ttl_seconds": 3600 } sets the lifetime
+// as for /api/auth/token. In static mode the user must still exist.
+func serveRefresh(w http.ResponseWriter, r *http.Request, db *sql.DB, cfg Config) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	token, ok := bearerToken(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, errors.New("missing bearer token"))
+		return
+	}
+	claims, err := parseToken(cfg.AuthSecret, token)
+	switch {
+	case errors.Is(err, errTokenExpired):
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusUnauthorized, errors.New("invalid token"))
+		return
+	}
+	var req struct {
+		TTLSeconds int64 `json:"ttl_seconds"`
+	}
+	b, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
+		return
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &req); err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("invalid json"))
+			return
+		}
+	}
+	if cfg.AuthMode == "static" {
+		var x int
+		if err := db.QueryRowContext(r.Context(), `SELECT 1 FROM users WHERE email = $1`, claims.Sub).Scan(&x); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				writeError(w, http.StatusForbidden, errors.New("user not allowed"))
+				return
+			}
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	next, end, err := refreshClaims(claims, time.Duration(req.TTLSeconds)*time.Second, cfg.MaxSession, time.Now())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	tok, err := signClaims(cfg.AuthSecret, next)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"token":              tok,
+		"subject":            next.Sub,
+		"expires_at":         time.Unix(next.Exp, 0).UTC().Format(time.RFC3339),
+		"session_expires_at": end.UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRefreshClaims(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	start := now.Add(-2 * time.Hour)
+	c := tokenClaims{Sub: "a@example.com", Exp: now.Add(10 * time.Minute).Unix(), Ses: start.Unix()}
+
+	next, end, err := refreshClaims(c, time.Hour, 24*time.Hour, now)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if next.Sub != c.Sub || next.Ses != c.Ses || next.Exp != now.Add(time.Hour).Unix() || !end.Equal(start.Add(24*time.Hour)) {
+		t.Fatalf("claims = %+v, session end %v", next, end)
+	}
+	// The new token does not outlive the session
+	next, _, err = refreshClaims(c, 0, 150*time.Minute, now)
+	if err != nil || next.Exp != start.Add(150*time.Minute).Unix() {
+		t.Fatalf("capped claims = %+v, %v", next, err)
+	}
+	if _, _, err := refreshClaims(c, time.Hour, 2*time.Hour, now); !errors.Is(err, errSessionExpired) {
+		t.Fatalf("err = %v, want errSessionExpired", err)
+	}
+	// Tokens without a session start count from maxTokenTTL before their expiry
+	legacy := tokenClaims{Sub: "a@example.com", Exp: now.Add(time.Hour).Unix()}
+	if _, end, err := refreshClaims(legacy, time.Hour, 24*time.Hour, now); err != nil || !end.Equal(now.Add(time.Hour)) {
+		t.Fatalf("legacy session end = %v, %v", end, err)
+	}
+}
+
+func TestServeRefresh(t *testing.T) {
+	cfg := Config{AuthMode: "dev", AuthSecret: "s", MaxSession: 24 * time.Hour}
+	refresh := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/refresh", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		serveRefresh(rec, req, nil, cfg)
+		return rec
+	}
+	tok, err := signToken("s", "a@example.com", time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	old, _ := parseToken("s", tok)
+
+	rec := refresh(tok, `{"ttl_seconds":7200}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	var res struct {
+		Token     string    `json:"token"`
+		Subject   string    `json:"subject"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	claims, err := parseToken("s", res.Token)
+	if err != nil || claims.Sub != "a@example.com" || claims.Ses != old.Ses || res.Subject != claims.Sub {
+		t.Fatalf("refreshed claims = %+v, %v", claims, err)
+	}
+	if d := time.Until(res.ExpiresAt); d < 119*time.Minute || d > 2*time.Hour {
+		t.Fatalf("expires in %s, want about 2h", d)
+	}
+	// An empty body is fine
+	if rec := refresh(res.Token, ""); rec.Code != http.StatusOK {
+		t.Fatalf("refresh without body: status %d", rec.Code)
+	}
+
+	expired, _ := signClaims("s", tokenClaims{Sub: "a@example.com", Exp: time.Now().Add(-time.Minute).Unix(), Ses: time.Now().Unix()})
+	forged, _ := signToken("other", "a@example.com", time.Now().Add(time.Minute))
+	over, _ := signClaims("s", tokenClaims{Sub: "a@example.com", Exp: time.Now().Add(time.Minute).Unix(), Ses: time.Now().Add(-25 * time.Hour).Unix()})
+	for name, token := range map[string]string{"missing": "", "expired": expired, "forged": forged, "session over": over} {
+		if rec := refresh(token, ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s token: status %d, want 401", name, rec.Code)
+		}
+	}
+	rec = httptest.NewRecorder()
+	serveRefresh(rec, httptest.NewRequest(http.MethodGet, "/api/auth/refresh", nil), nil, cfg)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET: status %d", rec.Code)
+	}
+}
@@ -13,13 +13,20 @@ package backend
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"gocomicwriter/internal/domain"
 	"gocomicwriter/internal/storage"
 )
 
@@ -27,79 +34,186 @@ import (
 // It supports read-only operations used by the desktop app under a feature flag.
 type Client struct {
 	BaseURL     string
-	Token       string // bearer token
-	AdminAPIKey string // optional admin key for static mode admin endpoints
-	client      *http.Client
+	Token       string    // bearer token; replaced when the client renews it
+	TokenExpiry time.Time // expiry of Token; zero when unknown
+	AdminAPIKey string    // optional admin key for static mode admin endpoints
+	// Retry controls retries of idempotent requests; the zero value disables them.
+	Retry RetryPolicy
+	// RefreshMargin is how long before TokenExpiry EnsureValidToken renews the token.
+	RefreshMargin time.Duration
+	// Renew obtains a new token after a 401 response or when EnsureValidToken is called without one.
+	// When nil, the client refreshes its token and, once that is no longer possible, signs in again
+	// for the token's subject, which dev-mode servers (or static ones given AdminAPIKey) accept.
+	Renew TokenRenewer
+	// OnTokenRefreshed is called with each token the client adopts, e.g. to store it in the keyring.
+	OnTokenRefreshed func(token string, expiresAt time.Time)
+	// Outbox holds the local ops PushPending sends, usually the project's *storage.IndexHandle.
+	Outbox SyncOutbox
+
+	client   *http.Client
+	mu       sync.Mutex       // guards Token and TokenExpiry once requests are under way, and etags
+	etags    map[int64]string // ETag of the last index snapshot fetched per project
+	renewing sync.Mutex       // lets one renewal run at a time
+}
+
+// DefaultRefreshMargin is used by NewClient.
+const DefaultRefreshMargin = 5 * time.Minute
+
+// RetryPolicy configures retries after network errors and 408/429/502/503/504 responses. Only GETs, comment
+// resolves and pushes whose ops all carry an op_id are retried; the server ignores ops it has already stored.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first; values below 2 disable retries
+	BaseDelay   time.Duration // delay before the first retry, doubled for each further one
+	MaxDelay    time.Duration // upper bound for a single delay, including one requested by Retry-After
+}
+
+// DefaultRetryPolicy is used by NewClient.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 4, BaseDelay: 250 * time.Millisecond, MaxDelay: 5 * time.Second}
+
+// ErrNotModified is returned by conditional GETs such as GetIndexSnapshot when the server reports that
+// the resource has not changed since the client last fetched it.
+var ErrNotModified = errors.New("not modified")
+
+// conditionalGet carries the ETag of an earlier response into a request, and the new response's ETag out.
+type conditionalGet struct {
+	ifNoneMatch string
+	etag        string
+}
+
+// maxErrorBody bounds how much of an error response is kept in Error.Body.
+const maxErrorBody = 4 << 10
+
+// Error is returned for non-2xx responses. Message holds the "error" field of the server's JSON body.
+type Error struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Status     string // e.g. "503 Service Unavailable"
+	RequestID  string
+	Message    string
+	Body       []byte // raw response body, truncated to 4 KiB
+
+	retryAfter time.Duration // from the Retry-After header; 0 when absent
+}
+
+func (e *Error) Error() string {
+	s := fmt.Sprintf("server %s %s: %s", e.Method, e.Path, e.Status)
+	if e.Message != "" {
+		s += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		s += fmt.Sprintf(" (request id %s)", e.RequestID)
+	}
+	return s
 }
 
 // NewClient creates a new backend client. baseURL may include a trailing slash; it will be normalized.
 func NewClient(baseURL string, token string) *Client {
 	b := strings.TrimRight(baseURL, "/")
+	_, exp := tokenSubjectExpiry(token)
 	return &Client{
-		BaseURL: b,
-		Token:   token,
-		client:  &http.Client{Timeout: 10 * time.Second},
+		BaseURL:       b,
+		Token:         token,
+		TokenExpiry:   exp,
+		Retry:         DefaultRetryPolicy,
+		RefreshMargin: DefaultRefreshMargin,
+		client:        &http.Client{Timeout: 10 * time.Second},
 	}
 }
 
+// doJSON sends a request without body; GETs are retried according to c.Retry.
 func (c *Client) doJSON(ctx context.Context, method, path string, dest any) error {
+	return c.do(ctx, method, path, nil, dest, method == http.MethodGet, nil)
+}
+
+// doJSONWithBody sends body as JSON. Requests with a body are only retried when retry is set.
+func (c *Client) doJSONWithBody(ctx context.Context, method, path string, body any, dest any) error {
+	return c.doJSONWithBodyRetry(ctx, method, path, body, dest, false)
+}
+
+func (c *Client) doJSONWithBodyRetry(ctx context.Context, method, path string, body any, dest any, retry bool) error {
+	var buf bytes.Buffer
+	if body != nil {
+		enc := json.NewEncoder(&buf)
+		if err := enc.Encode(body); err != nil {
+			return err
+		}
+	}
+	return c.do(ctx, method, path, buf.Bytes(), dest, retry, nil)
+}
+
+// do performs the request, retrying transient failures when retry is set. body nil means no request body.
+// A 401 response renews the token once and repeats the request; the auth endpoints themselves are not
+// repeated. cond, when set, makes the request conditional; a 304 response then yields ErrNotModified.
+func (c *Client) do(ctx context.Context, method, path string, body []byte, dest any, retry bool, cond *conditionalGet) error {
 	u, err := url.Parse(c.BaseURL + path)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
-	if err != nil {
+	sent, _ := c.currentToken()
+	err = c.doRetry(ctx, method, u, body, dest, retry, cond)
+	var se *Error
+	if !errors.As(err, &se) || se.StatusCode != http.StatusUnauthorized || sent == "" || strings.HasPrefix(path, "/api/auth/") {
 		return err
 	}
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
-	}
-	if c.AdminAPIKey != "" {
-		req.Header.Set("X-API-Key", c.AdminAPIKey)
-	}
-	resp, err := c.client.Do(req)
-	if err != nil {
+	if rerr := c.renewToken(ctx, sent, nil); rerr != nil {
 		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("server %s %s: %s", method, u.Path, resp.Status)
-	}
-	dec := json.NewDecoder(resp.Body)
-	dec.UseNumber()
-	return dec.Decode(dest)
+	return c.doRetry(ctx, method, u, body, dest, retry, cond)
 }
 
-func (c *Client) doJSONWithBody(ctx context.Context, method, path string, body any, dest any) error {
-	u, err := url.Parse(c.BaseURL + path)
-	if err != nil {
-		return err
-	}
-	var buf bytes.Buffer
-	if body != nil {
-		enc := json.NewEncoder(&buf)
-		if err := enc.Encode(body); err != nil {
+func (c *Client) doRetry(ctx context.Context, method string, u *url.URL, body []byte, dest any, retry bool, cond *conditionalGet) error {
+	var err error
+	attempts := 1
+	if retry && c.Retry.MaxAttempts > 1 {
+		attempts = c.Retry.MaxAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		err = c.attempt(ctx, method, u, body, dest, cond)
+		if err == nil || attempt >= attempts || !retryable(ctx, err) {
+			return err
+		}
+		if werr := sleepCtx(ctx, c.Retry.delay(attempt, err)); werr != nil {
+			// Deadline would pass while waiting: report the failure that caused the retry
 			return err
 		}
 	}
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), &buf)
+}
+
+func (c *Client) attempt(ctx context.Context, method string, u *url.URL, body []byte, dest any, cond *conditionalGet) error {
+	var rd io.Reader
+	if body != nil {
+		rd = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), rd)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if tok, _ := c.currentToken(); tok != "" {
+		req.Header.Set("Authorization", "Bearer "+tok)
 	}
 	if c.AdminAPIKey != "" {
 		req.Header.Set("X-API-Key", c.AdminAPIKey)
 	}
+	if cond != nil && cond.ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", cond.ifNoneMatch)
+	}
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
+	if cond != nil && resp.StatusCode == http.StatusNotModified {
+		return ErrNotModified
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("server %s %s: %s", method, u.Path, resp.Status)
+		return serverError(method, u.Path, resp)
+	}
+	if cond != nil {
+		cond.etag = resp.Header.Get("ETag")
 	}
 	if dest == nil {
 		return nil
@@ -109,22 +223,184 @@ func (c *Client) doJSONWithBody(ctx context.Context, method, path string, body a
 	return dec.Decode(dest)
 }
 
+// serverError builds an *Error from a non-2xx response, including the server's request ID when present.
+func serverError(method, path string, resp *http.Response) error {
+	e := &Error{Method: method, Path: path, StatusCode: resp.StatusCode, Status: resp.Status, RequestID: resp.Header.Get(RequestIDHeader)}
+	e.Body, _ = io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	var payload struct {
+		Error     string `json:"error"`
+		RequestID string `json:"request_id"`
+	}
+	if json.Unmarshal(e.Body, &payload) == nil {
+		e.Message = payload.Error
+		if e.RequestID == "" {
+			e.RequestID = payload.RequestID
+		}
+	}
+	e.retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	return e
+}
+
+// retryable reports whether err is a transient failure worth another attempt. Errors caused by ctx
+// itself (cancelled or past its deadline) are final.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var se *Error
+	if errors.As(err, &se) {
+		switch se.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	// Anything else came from the transport (connection refused/reset, client timeout, EOF)
+	var ue *url.Error
+	return errors.As(err, &ue)
+}
+
+// delay returns the wait before the retry following attempt (1-based): exponential backoff with
+// jitter in [d/2, d], or the server's Retry-After when it asked for longer.
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d > 1 {
+		d = d/2 + time.Duration(rand.Int64N(int64(d/2)+1))
+	}
+	var se *Error
+	if errors.As(err, &se) && se.retryAfter > d {
+		d = se.retryAfter
+		if p.MaxDelay > 0 && d > p.MaxDelay {
+			d = p.MaxDelay
+		}
+	}
+	return d
+}
+
+// parseRetryAfter reads a Retry-After value given in seconds or as an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
+
+// sleepCtx waits for d unless ctx ends first. It returns the context error without waiting when ctx's
+// deadline falls before the wait would end.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if dl, ok := ctx.Deadline(); ok && time.Until(dl) < d {
+		return context.DeadlineExceeded
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
 // Project is a minimal projection for listing.
 type Project struct {
 	ID        int64     `json:"id"`
 	StableID  string    `json:"stable_id"`
 	Name      string    `json:"name"`
+	Slug      string    `json:"slug,omitempty"`
 	UpdatedAt time.Time `json:"updated_at"`
 	Version   int64     `json:"version"`
 }
 
-// ListProjects returns available projects (read-only).
+// ProjectPage is one page of a project listing. NextCursor is empty on the last page.
+type ProjectPage struct {
+	Projects   []Project `json:"projects"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// ErrTooManyPages is returned by the auto-paginating calls when the server keeps reporting more data
+// after maxAutoPages requests, which points at a server bug rather than a large project.
+var ErrTooManyPages = errors.New("backend: too many pages")
+
+// maxAutoPages caps the requests ListProjects and PullAllOps make for one call.
+var maxAutoPages = 1000
+
+// ListProjectsPage returns up to limit projects, most recently updated first, starting after cursor; an
+// empty cursor starts at the beginning and limit <= 0 uses the server default. A server without pagination
+// support returns all projects as a single page.
+func (c *Client) ListProjectsPage(ctx context.Context, cursor string, limit int) (*ProjectPage, error) {
+	v := url.Values{}
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+	v.Set("cursor", cursor)
+	var raw json.RawMessage
+	if err := c.doJSON(ctx, http.MethodGet, "/api/projects?"+v.Encode(), &raw); err != nil {
+		return nil, err
+	}
+	var page ProjectPage
+	if t := bytes.TrimSpace(raw); len(t) > 0 && t[0] == '[' {
+		if err := json.Unmarshal(t, &page.Projects); err != nil {
+			return nil, fmt.Errorf("decode projects: %w", err)
+		}
+		return &page, nil
+	}
+	if err := json.Unmarshal(raw, &page); err != nil {
+		return nil, fmt.Errorf("decode projects: %w", err)
+	}
+	return &page, nil
+}
+
+// ListProjects returns all available projects (read-only), following the listing's pages.
 func (c *Client) ListProjects(ctx context.Context) ([]Project, error) {
 	var list []Project
-	if err := c.doJSON(ctx, http.MethodGet, "/api/projects", &list); err != nil {
+	cursor := ""
+	for range maxAutoPages {
+		page, err := c.ListProjectsPage(ctx, cursor, 0)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, page.Projects...)
+		if page.NextCursor == "" {
+			return list, nil
+		}
+		cursor = page.NextCursor
+	}
+	return nil, ErrTooManyPages
+}
+
+// CreateProject creates a project owned by the calling user. slug may be empty to derive it from name;
+// the server appends a numeric suffix when the slug is taken, so check the returned Slug.
+func (c *Client) CreateProject(ctx context.Context, name, slug string) (*Project, error) {
+	req := struct {
+		Name string `json:"name"`
+		Slug string `json:"slug,omitempty"`
+	}{Name: name, Slug: slug}
+	var p Project
+	if err := c.doJSONWithBody(ctx, http.MethodPost, "/api/projects", req, &p); err != nil {
 		return nil, err
 	}
-	return list, nil
+	return &p, nil
+}
+
+// DeleteProject soft-deletes a project. Only owners may delete; the project disappears from listings
+// while its sync history is kept on the server.
+func (c *Client) DeleteProject(ctx context.Context, projectID int64) error {
+	path := fmt.Sprintf("/api/projects/%d", projectID)
+	return c.doJSONWithBody(ctx, http.MethodDelete, path, nil, nil)
 }
 
 // IndexSnapshotEnvelope matches the server response for latest index snapshot of a project.
@@ -135,13 +411,28 @@ type IndexSnapshotEnvelope struct {
 	Snapshot  interface{} `json:"snapshot"`
 }
 
-// GetIndexSnapshot fetches the latest index snapshot for a project.
+// GetIndexSnapshot fetches the latest index snapshot for a project. The client remembers the ETag of
+// the snapshot it last fetched per project and returns ErrNotModified, without a body to decode, while
+// the server still has that snapshot; callers polling for changes keep the envelope they have.
 func (c *Client) GetIndexSnapshot(ctx context.Context, projectID int64) (*IndexSnapshotEnvelope, error) {
 	var env IndexSnapshotEnvelope
 	path := fmt.Sprintf("/api/projects/%d/index", projectID)
-	if err := c.doJSON(ctx, http.MethodGet, path, &env); err != nil {
+	c.mu.Lock()
+	cond := conditionalGet{ifNoneMatch: c.etags[projectID]}
+	c.mu.Unlock()
+	if err := c.do(ctx, http.MethodGet, path, nil, &env, true, &cond); err != nil {
 		return nil, err
 	}
+	c.mu.Lock()
+	if c.etags == nil {
+		c.etags = map[int64]string{}
+	}
+	if cond.etag != "" {
+		c.etags[projectID] = cond.etag
+	} else {
+		delete(c.etags, projectID)
+	}
+	c.mu.Unlock()
 	return &env, nil
 }
 
@@ -169,23 +460,38 @@ type PushResult struct {
 	ProjectID     int64 `json:"project_id"`
 	ServerVersion int64 `json:"server_version"`
 	Accepted      int   `json:"accepted"`
+	// Duplicates counts ops skipped because an op with the same op_id was stored before, e.g. by a
+	// retried push whose first response was lost.
+	Duplicates int `json:"duplicates"`
 }
 
+// PullResult is the response of a sync pull. NextSince is the since value for the next pull and HasMore
+// reports that more ops follow; loop on HasMore rather than on versions, which may have gaps.
 type PullResult struct {
 	ProjectID     int64    `json:"project_id"`
 	ServerVersion int64    `json:"server_version"`
 	Ops           []SyncOp `json:"ops"`
+	NextSince     int64    `json:"next_since"`
+	HasMore       bool     `json:"has_more"`
 }
 
-// PushOps pushes a batch of ops to the server (no conflict resolution).
+// PushOps pushes a batch of ops to the server (no conflict resolution). The push is retried on transient
+// failures only when every op carries an OpID, since the server then stores each op at most once.
 func (c *Client) PushOps(ctx context.Context, projectID int64, clientVersion int64, ops []SyncOpInput) (*PushResult, error) {
 	req := struct {
 		ClientVersion int64         `json:"client_version"`
 		Ops           []SyncOpInput `json:"ops"`
 	}{ClientVersion: clientVersion, Ops: ops}
+	retry := true
+	for _, op := range ops {
+		if op.OpID == "" {
+			retry = false
+			break
+		}
+	}
 	var res PushResult
 	path := fmt.Sprintf("/api/projects/%d/sync/push", projectID)
-	if err := c.doJSONWithBody(ctx, http.MethodPost, path, req, &res); err != nil {
+	if err := c.doJSONWithBodyRetry(ctx, http.MethodPost, path, req, &res, retry); err != nil {
 		return nil, err
 	}
 	return &res, nil
@@ -201,9 +507,147 @@ func (c *Client) PullOps(ctx context.Context, projectID int64, since int64, limi
 	if err := c.doJSON(ctx, http.MethodGet, path, &res); err != nil {
 		return nil, err
 	}
+	if res.NextSince == 0 {
+		// Servers before pagination support do not send next_since
+		res.NextSince = since
+		if n := len(res.Ops); n > 0 {
+			res.NextSince = res.Ops[n-1].Version
+		}
+	}
 	return &res, nil
 }
 
+// PullAllOps pulls every op since the given version in pages of pageSize, looping until the server reports
+// no more. The result holds all ops, the last page's server version and the NextSince to resume from.
+func (c *Client) PullAllOps(ctx context.Context, projectID int64, since int64, pageSize int) (*PullResult, error) {
+	all := &PullResult{ProjectID: projectID, NextSince: since}
+	for range maxAutoPages {
+		res, err := c.PullOps(ctx, projectID, all.NextSince, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		all.ServerVersion = res.ServerVersion
+		all.Ops = append(all.Ops, res.Ops...)
+		if res.NextSince <= all.NextSince && res.HasMore {
+			return nil, fmt.Errorf("sync pull did not advance past version %d", all.NextSince)
+		}
+		all.NextSince = res.NextSince
+		if !res.HasMore {
+			return all, nil
+		}
+	}
+	return nil, ErrTooManyPages
+}
+
+// PushManifest pushes the whole project manifest as one sync op, which the server's reindex job builds
+// the project's search documents from. opID makes the push safe to retry; it may be empty.
+func (c *Client) PushManifest(ctx context.Context, projectID int64, clientVersion int64, opID string, proj domain.Project) (*PushResult, error) {
+	payload, err := json.Marshal(proj)
+	if err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+	return c.PushOps(ctx, projectID, clientVersion, []SyncOpInput{{
+		OpID: opID, OpType: "upsert", EntityType: manifestEntityType, EntityID: "comic.json", Payload: payload,
+	}})
+}
+
+// SyncOutbox is a persistent queue of local ops waiting to be pushed, together with the server version
+// each server project had after the last push. *storage.IndexHandle implements it; storage.ChangeTracker
+// fills it on save.
+type SyncOutbox interface {
+	PendingSyncOps(ctx context.Context, limit int) ([]storage.SyncOp, error)
+	AckSyncOps(ctx context.Context, throughSeq int64) error
+	SyncServerVersion(ctx context.Context, projectID int64) (int64, error)
+	SetSyncServerVersion(ctx context.Context, projectID int64, version int64) error
+}
+
+var _ SyncOutbox = (*storage.IndexHandle)(nil)
+
+// pushBatchSize bounds the ops PushPending sends per request.
+var pushBatchSize = 200
+
+// PushPending drains c.Outbox into the server project, oldest ops first and in batches. Each batch is
+// sent with the server version of the previous push as client_version and removed from the outbox once
+// the server stored it. Ops the server already has count as duplicates, so an interrupted drain is
+// simply run again. Conflicts are last-writer-wins: the server keeps the ops in push order. The result
+// sums all batches; on error it covers the batches pushed before the error.
+func (c *Client) PushPending(ctx context.Context, projectID int64) (*PushResult, error) {
+	if c.Outbox == nil {
+		return nil, errors.New("no sync outbox")
+	}
+	version, err := c.Outbox.SyncServerVersion(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("read sync version: %w", err)
+	}
+	total := &PushResult{ProjectID: projectID, ServerVersion: version}
+	for {
+		ops, err := c.Outbox.PendingSyncOps(ctx, pushBatchSize)
+		if err != nil {
+			return total, fmt.Errorf("read outbox: %w", err)
+		}
+		if len(ops) == 0 {
+			return total, nil
+		}
+		in := make([]SyncOpInput, 0, len(ops))
+		for _, op := range ops {
+			in = append(in, SyncOpInput{OpID: op.OpID, OpType: op.OpType, EntityType: op.EntityType, EntityID: op.EntityID, Payload: op.Payload})
+		}
+		res, err := c.PushOps(ctx, projectID, total.ServerVersion, in)
+		if err != nil {
+			return total, err
+		}
+		total.ServerVersion = res.ServerVersion
+		total.Accepted += res.Accepted
+		total.Duplicates += res.Duplicates
+		if err := c.Outbox.AckSyncOps(ctx, ops[len(ops)-1].Seq); err != nil {
+			return total, fmt.Errorf("ack pushed ops: %w", err)
+		}
+		if err := c.Outbox.SetSyncServerVersion(ctx, projectID, res.ServerVersion); err != nil {
+			return total, fmt.Errorf("store sync version: %w", err)
+		}
+	}
+}
+
+// ReindexJob is a server-side reindex of a project's search documents.
+type ReindexJob struct {
+	JobID           string     `json:"job_id"`
+	ProjectID       int64      `json:"project_id"`
+	RequestedBy     string     `json:"requested_by"`
+	Status          string     `json:"status"` // queued | running | done | failed
+	ManifestVersion *int64     `json:"manifest_version,omitempty"`
+	RowsDeleted     int64      `json:"rows_deleted"`
+	RowsInserted    int64      `json:"rows_inserted"`
+	DurationMS      *int64     `json:"duration_ms,omitempty"`
+	Error           string     `json:"error,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
+
+// Finished reports whether the job is done or failed.
+func (j ReindexJob) Finished() bool { return j.Status == "done" || j.Status == "failed" }
+
+// StartReindex queues a reindex of the project from its last pushed manifest. Owners may start one;
+// admins too when c.AdminAPIKey is set. Poll GetReindexJob for the outcome.
+func (c *Client) StartReindex(ctx context.Context, projectID int64) (*ReindexJob, error) {
+	var j ReindexJob
+	path := fmt.Sprintf("/api/projects/%d/reindex", projectID)
+	if err := c.doJSONWithBody(ctx, http.MethodPost, path, nil, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// GetReindexJob returns the status of a reindex job.
+func (c *Client) GetReindexJob(ctx context.Context, projectID int64, jobID string) (*ReindexJob, error) {
+	var j ReindexJob
+	path := fmt.Sprintf("/api/projects/%d/reindex/%s", projectID, url.PathEscape(jobID))
+	if err := c.doJSON(ctx, http.MethodGet, path, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
 // Search issues a search request to the backend for a given project using parameters compatible
 // with storage.SearchQuery and returns a slice of storage.SearchResult.
 func (c *Client) Search(ctx context.Context, projectID int64, q storage.SearchQuery) ([]storage.SearchResult, error) {
@@ -243,6 +687,123 @@ func (c *Client) Search(ctx context.Context, projectID int64, q storage.SearchQu
 	return res, nil
 }
 
+// --- Review comments ---
+
+// Comment is a review comment anchored to an index document path such as "issue:1/page:3/panel:p2".
+type Comment struct {
+	ID         int64      `json:"id"`
+	ProjectID  int64      `json:"project_id"`
+	Path       string     `json:"path"`
+	Author     string     `json:"author"`
+	Body       string     `json:"body"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// Resolved reports whether the comment has been resolved.
+func (c Comment) Resolved() bool { return c.ResolvedAt != nil }
+
+// CommentFilter narrows ListComments. The zero value lists all comments of the project.
+type CommentFilter struct {
+	PathPrefix string // e.g. "issue:1/page:3/" for every comment on page 3
+	Resolved   *bool  // only resolved (true) or open (false) comments
+}
+
+// ListComments returns the project's comments matching f, oldest first.
+func (c *Client) ListComments(ctx context.Context, projectID int64, f CommentFilter) ([]Comment, error) {
+	values := url.Values{}
+	if f.PathPrefix != "" {
+		values.Set("path", f.PathPrefix)
+	}
+	if f.Resolved != nil {
+		values.Set("resolved", strconv.FormatBool(*f.Resolved))
+	}
+	path := fmt.Sprintf("/api/projects/%d/comments", projectID)
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	var list []Comment
+	if err := c.doJSON(ctx, http.MethodGet, path, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// AddComment posts a comment on the document at path; the server records the caller as author.
+func (c *Client) AddComment(ctx context.Context, projectID int64, path, body string) (*Comment, error) {
+	req := struct {
+		Path string `json:"path"`
+		Body string `json:"body"`
+	}{Path: path, Body: body}
+	var cm Comment
+	if err := c.doJSONWithBody(ctx, http.MethodPost, fmt.Sprintf("/api/projects/%d/comments", projectID), req, &cm); err != nil {
+		return nil, err
+	}
+	return &cm, nil
+}
+
+// ResolveComment marks a comment resolved. Resolving is idempotent, so the request is retried like a GET.
+func (c *Client) ResolveComment(ctx context.Context, projectID, commentID int64) (*Comment, error) {
+	req := struct {
+		Resolved bool `json:"resolved"`
+	}{Resolved: true}
+	var cm Comment
+	path := fmt.Sprintf("/api/projects/%d/comments/%d", projectID, commentID)
+	if err := c.doJSONWithBodyRetry(ctx, http.MethodPatch, path, req, &cm, true); err != nil {
+		return nil, err
+	}
+	return &cm, nil
+}
+
+// --- Presence ---
+
+// PresenceMember is a client that has a project open, as announced by its heartbeats.
+type PresenceMember struct {
+	Subject   string    `json:"subject"`
+	ClientID  string    `json:"client_id"`
+	Client    string    `json:"client,omitempty"`
+	LastSeen  time.Time `json:"last_seen"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PresenceHeartbeat announces that this client has a project open. ClientID tells apart several
+// windows or machines of the same user; the server forgets the client after TTL (zero: server default).
+type PresenceHeartbeat struct {
+	ClientID string
+	Client   string // free-form, e.g. "gocomicwriter 0.9 (darwin)"
+	TTL      time.Duration
+}
+
+// Heartbeat records the caller as viewing the project. Heartbeats are idempotent and retried like a GET.
+func (c *Client) Heartbeat(ctx context.Context, projectID int64, hb PresenceHeartbeat) (*PresenceMember, error) {
+	req := struct {
+		ClientID   string `json:"client_id"`
+		Client     string `json:"client,omitempty"`
+		TTLSeconds int    `json:"ttl_seconds,omitempty"`
+	}{ClientID: hb.ClientID, Client: hb.Client, TTLSeconds: int(hb.TTL / time.Second)}
+	var m PresenceMember
+	path := fmt.Sprintf("/api/projects/%d/presence", projectID)
+	if err := c.doJSONWithBodyRetry(ctx, http.MethodPost, path, req, &m, true); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ListPresence returns the clients currently viewing the project, including the caller's.
+func (c *Client) ListPresence(ctx context.Context, projectID int64) ([]PresenceMember, error) {
+	var list []PresenceMember
+	if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/api/projects/%d/presence", projectID), &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// LeavePresence removes the caller's client from the project's viewers without waiting for its TTL.
+func (c *Client) LeavePresence(ctx context.Context, projectID int64, clientID string) error {
+	path := fmt.Sprintf("/api/projects/%d/presence?client_id=%s", projectID, url.QueryEscape(clientID))
+	return c.doJSONWithBody(ctx, http.MethodDelete, path, nil, nil)
+}
+
 // HealthStatus represents the /healthz response from the server.
 type HealthStatus struct {
 	Status  string `json:"status"`
@@ -286,3 +847,130 @@ func (c *Client) AdminGrantMembership(ctx context.Context, req GrantMembershipRe
 	}
 	return &res, nil
 }
+
+// --- Auth: tokens and their renewal ---
+
+// TokenRequest is the body of POST /api/auth/token. In static mode the server requires c.AdminAPIKey.
+type TokenRequest struct {
+	Email       string `json:"email,omitempty"`
+	Subject     string `json:"subject,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+	TTLSeconds  int64  `json:"ttl_seconds,omitempty"`
+}
+
+// TokenResponse is the server's answer to a token request or refresh.
+type TokenResponse struct {
+	Token            string    `json:"token"`
+	Subject          string    `json:"subject"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	SessionExpiresAt time.Time `json:"session_expires_at"` // refreshing is possible until then
+}
+
+// TokenRenewer obtains a new token, e.g. Client.RefreshToken or a closure calling Client.RequestToken.
+type TokenRenewer func(ctx context.Context) (*TokenResponse, error)
+
+// RequestToken signs in through POST /api/auth/token and makes the returned token the client's.
+func (c *Client) RequestToken(ctx context.Context, req TokenRequest) (*TokenResponse, error) {
+	var res TokenResponse
+	if err := c.doJSONWithBody(ctx, http.MethodPost, "/api/auth/token", req, &res); err != nil {
+		return nil, err
+	}
+	c.adoptToken(&res)
+	return &res, nil
+}
+
+// RefreshToken exchanges the client's still valid token for a new one of the same session through
+// POST /api/auth/refresh and makes it the client's. The server ends sessions after a maximum lifetime.
+func (c *Client) RefreshToken(ctx context.Context) (*TokenResponse, error) {
+	var res TokenResponse
+	if err := c.doJSONWithBody(ctx, http.MethodPost, "/api/auth/refresh", nil, &res); err != nil {
+		return nil, err
+	}
+	c.adoptToken(&res)
+	return &res, nil
+}
+
+// EnsureValidToken renews the token when it expires within c.RefreshMargin, using renew or, when
+// renew is nil, c.Renew and the client's default. It does nothing for a token of unknown expiry.
+func (c *Client) EnsureValidToken(ctx context.Context, renew TokenRenewer) error {
+	tok, exp := c.currentToken()
+	if tok == "" || exp.IsZero() || time.Until(exp) > c.RefreshMargin {
+		return nil
+	}
+	return c.renewToken(ctx, tok, renew)
+}
+
+// renewToken replaces the token seen, unless another renewal has replaced it meanwhile.
+func (c *Client) renewToken(ctx context.Context, seen string, renew TokenRenewer) error {
+	c.renewing.Lock()
+	defer c.renewing.Unlock()
+	if tok, _ := c.currentToken(); tok != seen {
+		return nil
+	}
+	if renew == nil {
+		renew = c.Renew
+	}
+	if renew == nil {
+		renew = c.defaultRenew
+	}
+	res, err := renew(ctx)
+	if err != nil {
+		return err
+	}
+	c.adoptToken(res)
+	return nil
+}
+
+// defaultRenew refreshes the token and, when the server refuses to (token expired, session over), signs
+// in again for the token's subject.
+func (c *Client) defaultRenew(ctx context.Context) (*TokenResponse, error) {
+	res, err := c.RefreshToken(ctx)
+	var se *Error
+	if err == nil || !errors.As(err, &se) || se.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+	tok, _ := c.currentToken()
+	sub, _ := tokenSubjectExpiry(tok)
+	if sub == "" {
+		return nil, err
+	}
+	return c.RequestToken(ctx, TokenRequest{Email: sub})
+}
+
+func (c *Client) currentToken() (string, time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Token, c.TokenExpiry
+}
+
+// adoptToken makes res the client's token and reports a new one to OnTokenRefreshed.
+func (c *Client) adoptToken(res *TokenResponse) {
+	if res == nil || res.Token == "" {
+		return
+	}
+	c.mu.Lock()
+	changed := c.Token != res.Token
+	c.Token, c.TokenExpiry = res.Token, res.ExpiresAt
+	c.mu.Unlock()
+	if changed && c.OnTokenRefreshed != nil {
+		c.OnTokenRefreshed(res.Token, res.ExpiresAt)
+	}
+}
+
+// tokenSubjectExpiry reads subject and expiry from a server token without checking its signature, which
+// only the server can. It returns zero values for tokens it cannot read.
+func tokenSubjectExpiry(token string) (string, time.Time) {
+	payload, _, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", time.Time{}
+	}
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", time.Time{}
+	}
+	var claims tokenClaims
+	if json.Unmarshal(b, &claims) != nil || claims.Exp == 0 {
+		return "", time.Time{}
+	}
+	return claims.Sub, time.Unix(claims.Exp, 0)
+}
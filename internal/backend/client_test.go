@@ -0,0 +1,661 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/storage"
+)
+
+// flakyServer answers the first fails requests with status and an error body, then 200 with body.
+func flakyServer(t *testing.T, fails int, status int, header http.Header, body string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if int(n) <= fails {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.Header().Set(RequestIDHeader, "req-1")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"error":"database is restarting"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func testClient(url string) *Client {
+	c := NewClient(url, "tok")
+	c.Retry = RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: 20 * time.Millisecond}
+	return c
+}
+
+func TestClient_RetriesGetAfter503(t *testing.T) {
+	srv, calls := flakyServer(t, 2, http.StatusServiceUnavailable, nil, `{"project_id":7,"server_version":3,"ops":[]}`)
+	res, err := testClient(srv.URL).PullOps(context.Background(), 7, 0, 0)
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	if res.ServerVersion != 3 || calls.Load() != 3 {
+		t.Fatalf("expected success on third call, got version %d after %d calls", res.ServerVersion, calls.Load())
+	}
+}
+
+func TestClient_TypedErrorAfterRetriesExhausted(t *testing.T) {
+	srv, calls := flakyServer(t, 100, http.StatusServiceUnavailable, nil, `[]`)
+	_, err := testClient(srv.URL).ListProjects(context.Background())
+	var se *Error
+	if !errors.As(err, &se) {
+		t.Fatalf("expected *Error, got %T %v", err, err)
+	}
+	if se.StatusCode != http.StatusServiceUnavailable || se.Message != "database is restarting" || se.RequestID != "req-1" || se.Path != "/api/projects" {
+		t.Fatalf("unexpected error fields: %+v", se)
+	}
+	if calls.Load() != 4 {
+		t.Fatalf("expected 4 attempts, got %d", calls.Load())
+	}
+	if got := se.Error(); got != "server GET /api/projects: 503 Service Unavailable: database is restarting (request id req-1)" {
+		t.Fatalf("message: %q", got)
+	}
+}
+
+func TestClient_NoRetryOnClientErrors(t *testing.T) {
+	srv, calls := flakyServer(t, 100, http.StatusNotFound, nil, `{}`)
+	if _, err := testClient(srv.URL).GetIndexSnapshot(context.Background(), 1); err == nil {
+		t.Fatalf("expected error")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("404 must not be retried, got %d calls", calls.Load())
+	}
+}
+
+func TestClient_PushRetriedOnlyWithOpIDs(t *testing.T) {
+	ok := `{"project_id":1,"server_version":2,"accepted":1}`
+	srv, calls := flakyServer(t, 1, http.StatusBadGateway, nil, ok)
+	if _, err := testClient(srv.URL).PushOps(context.Background(), 1, 1, []SyncOpInput{{OpType: "upsert", EntityType: "page", EntityID: "pg-1"}}); err == nil {
+		t.Fatalf("push without op_id should fail on the first 502")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("push without op_id was retried: %d calls", calls.Load())
+	}
+
+	srv, calls = flakyServer(t, 1, http.StatusBadGateway, nil, ok)
+	op := SyncOpInput{OpID: "7f1c2c7e-3f5a-4d5e-9d7b-0c6b1c1b2a01", OpType: "upsert", EntityType: "page", EntityID: "pg-1"}
+	res, err := testClient(srv.URL).PushOps(context.Background(), 1, 1, []SyncOpInput{op})
+	if err != nil || res.Accepted != 1 || calls.Load() != 2 {
+		t.Fatalf("push with op_id should be retried once: res=%+v err=%v calls=%d", res, err, calls.Load())
+	}
+}
+
+func TestClient_RetriesNetworkErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := srv.URL
+	srv.Close()
+	c := testClient(url)
+	c.Retry.MaxAttempts = 2
+	start := time.Now()
+	if _, err := c.Health(context.Background()); err == nil {
+		t.Fatalf("expected connection error")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("retries took too long")
+	}
+}
+
+func TestClient_RetryAfterBeyondDeadlineStopsEarly(t *testing.T) {
+	srv, calls := flakyServer(t, 100, http.StatusTooManyRequests, http.Header{"Retry-After": {"30"}}, `[]`)
+	c := testClient(srv.URL)
+	c.Retry.MaxDelay = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.ListProjects(ctx)
+	var se *Error
+	if !errors.As(err, &se) || se.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected the 429 error, got %v", err)
+	}
+	if time.Since(start) > 400*time.Millisecond || calls.Load() != 1 {
+		t.Fatalf("client waited for a retry past the deadline (%v, %d calls)", time.Since(start), calls.Load())
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	for attempt, limit := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 300 * time.Millisecond, 6: 300 * time.Millisecond} {
+		for i := 0; i < 20; i++ {
+			if d := p.delay(attempt, errors.New("x")); d < limit/2 || d > limit {
+				t.Fatalf("attempt %d: delay %v outside [%v, %v]", attempt, d, limit/2, limit)
+			}
+		}
+	}
+	// Retry-After wins when longer, but is capped by MaxDelay
+	if d := p.delay(1, &Error{retryAfter: 250 * time.Millisecond}); d != 250*time.Millisecond {
+		t.Fatalf("Retry-After not honored: %v", d)
+	}
+	if d := p.delay(1, &Error{retryAfter: time.Hour}); d != 300*time.Millisecond {
+		t.Fatalf("Retry-After not capped: %v", d)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	cases := map[string]time.Duration{
+		"":                              0,
+		"3":                             3 * time.Second,
+		"-1":                            0,
+		"soon":                          0,
+		"Thu, 01 May 2025 12:00:10 GMT": 10 * time.Second,
+		"Thu, 01 May 2025 11:00:00 GMT": 0,
+	}
+	for in, want := range cases {
+		if got := parseRetryAfter(in, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestClient_Comments(t *testing.T) {
+	var gotQuery, gotMethod, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotQuery = r.Method, r.URL.RawQuery
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`[{"id":1,"project_id":7,"path":"issue:1/page:3/panel:p1","author":"ed","body":"hi","created_at":"2025-01-02T03:04:05Z"}]`))
+		default:
+			_, _ = w.Write([]byte(`{"id":1,"project_id":7,"path":"issue:1/page:3/panel:p1","author":"ed","body":"hi","created_at":"2025-01-02T03:04:05Z","resolved_at":"2025-01-03T00:00:00Z"}`))
+		}
+	}))
+	defer srv.Close()
+	c := testClient(srv.URL)
+	open := false
+	list, err := c.ListComments(context.Background(), 7, CommentFilter{PathPrefix: "issue:1/page:3/", Resolved: &open})
+	if err != nil || len(list) != 1 || list[0].Resolved() {
+		t.Fatalf("list = %+v (%v)", list, err)
+	}
+	if gotQuery != "path=issue%3A1%2Fpage%3A3%2F&resolved=false" {
+		t.Fatalf("query = %q", gotQuery)
+	}
+	cm, err := c.ResolveComment(context.Background(), 7, 1)
+	if err != nil || !cm.Resolved() {
+		t.Fatalf("resolve = %+v (%v)", cm, err)
+	}
+	if gotMethod != http.MethodPatch || !strings.Contains(gotBody, `"resolved":true`) {
+		t.Fatalf("resolve sent %s %s", gotMethod, gotBody)
+	}
+}
+
+func TestClient_Presence(t *testing.T) {
+	reg := newPresenceRegistry()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		servePresence(w, r, reg, 7, "ed@example.com", nil)
+	}))
+	defer srv.Close()
+	c := testClient(srv.URL)
+	ctx := context.Background()
+
+	m, err := c.Heartbeat(ctx, 7, PresenceHeartbeat{ClientID: "win 1", Client: "gocomicwriter test", TTL: 45 * time.Second})
+	if err != nil || m.Subject != "ed@example.com" || m.ExpiresAt.Sub(m.LastSeen) != 45*time.Second {
+		t.Fatalf("heartbeat = %+v (%v)", m, err)
+	}
+	list, err := c.ListPresence(ctx, 7)
+	if err != nil || len(list) != 1 || list[0].ClientID != "win 1" || list[0].Client != "gocomicwriter test" {
+		t.Fatalf("list = %+v (%v)", list, err)
+	}
+	if err := c.LeavePresence(ctx, 7, "win 1"); err != nil {
+		t.Fatalf("leave: %v", err)
+	}
+	if list, err := c.ListPresence(ctx, 7); err != nil || len(list) != 0 {
+		t.Fatalf("list after leave = %+v (%v)", list, err)
+	}
+}
+
+// authServer is a server whose API accepts one token. /api/auth/refresh and /api/auth/token issue a new
+// accepted token unless switched off.
+type authServer struct {
+	mu        sync.Mutex
+	accepted  string
+	refreshOK bool
+	signInOK  bool
+	refreshes int
+	signIns   []string
+}
+
+func (a *authServer) start(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		issue := func(sub string) {
+			exp := time.Now().Add(time.Hour)
+			a.accepted, _ = signClaims("s", tokenClaims{Sub: sub, Exp: exp.Unix(), Ses: time.Now().UnixNano()})
+			writeJSON(w, http.StatusOK, map[string]any{"token": a.accepted, "subject": sub, "expires_at": exp.UTC().Format(time.RFC3339)})
+		}
+		tok, _ := bearerToken(r)
+		switch r.URL.Path {
+		case "/api/auth/refresh":
+			a.refreshes++
+			if !a.refreshOK {
+				writeError(w, http.StatusUnauthorized, errSessionExpired)
+				return
+			}
+			sub, _ := tokenSubjectExpiry(tok)
+			issue(sub)
+		case "/api/auth/token":
+			var req TokenRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			a.signIns = append(a.signIns, req.Email)
+			if !a.signInOK {
+				writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+				return
+			}
+			issue(req.Email)
+		default:
+			if tok != a.accepted {
+				writeError(w, http.StatusUnauthorized, errors.New("invalid token"))
+				return
+			}
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// staleToken is a readable token for a@example.com that authServer does not accept.
+func staleToken(t *testing.T) string {
+	t.Helper()
+	tok, err := signToken("s", "a@example.com", time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return tok
+}
+
+func TestClient_RefreshesAndRetriesAfter401(t *testing.T) {
+	a := &authServer{refreshOK: true}
+	c := testClient(a.start(t).URL)
+	c.Token = staleToken(t)
+	var stored []string
+	c.OnTokenRefreshed = func(tok string, _ time.Time) { stored = append(stored, tok) }
+	if _, err := c.ListProjects(context.Background()); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if a.refreshes != 1 || c.Token != a.accepted || len(stored) != 1 || stored[0] != a.accepted {
+		t.Fatalf("refreshes %d, token adopted %v, stored %d tokens", a.refreshes, c.Token == a.accepted, len(stored))
+	}
+	if time.Until(c.TokenExpiry) < 59*time.Minute {
+		t.Fatalf("expiry of the refreshed token = %v", c.TokenExpiry)
+	}
+}
+
+func TestClient_SignsInAgainWhenRefreshIsRefused(t *testing.T) {
+	a := &authServer{signInOK: true}
+	c := testClient(a.start(t).URL)
+	c.Token = staleToken(t)
+	if _, err := c.ListProjects(context.Background()); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(a.signIns) != 1 || a.signIns[0] != "a@example.com" || c.Token != a.accepted {
+		t.Fatalf("sign-ins %v, token adopted %v", a.signIns, c.Token == a.accepted)
+	}
+}
+
+func TestClient_Surfaces401WhenRenewalFails(t *testing.T) {
+	a := &authServer{}
+	c := testClient(a.start(t).URL)
+	c.Token = staleToken(t)
+	_, err := c.ListProjects(context.Background())
+	var se *Error
+	if !errors.As(err, &se) || se.StatusCode != http.StatusUnauthorized || se.Path != "/api/projects" {
+		t.Fatalf("err = %v, want the 401 of the request", err)
+	}
+	if a.refreshes != 1 || len(a.signIns) != 1 {
+		t.Fatalf("refreshes %d, sign-ins %d; want one renewal attempt", a.refreshes, len(a.signIns))
+	}
+}
+
+func TestClient_EnsureValidToken(t *testing.T) {
+	a := &authServer{refreshOK: true}
+	c := NewClient(a.start(t).URL, staleToken(t))
+	calls := 0
+	renew := func(ctx context.Context) (*TokenResponse, error) {
+		calls++
+		return c.RefreshToken(ctx)
+	}
+	// Expiry comes from the token itself; one minute left is within the default margin
+	if c.TokenExpiry.IsZero() || time.Until(c.TokenExpiry) > time.Minute || c.RefreshMargin != DefaultRefreshMargin {
+		t.Fatalf("expiry %v, margin %s", c.TokenExpiry, c.RefreshMargin)
+	}
+	if err := c.EnsureValidToken(context.Background(), renew); err != nil {
+		t.Fatalf("ensure: %v", err)
+	}
+	if calls != 1 || c.Token != a.accepted {
+		t.Fatalf("renewals %d, token adopted %v", calls, c.Token == a.accepted)
+	}
+	// An hour left: nothing to do
+	if err := c.EnsureValidToken(context.Background(), renew); err != nil || calls != 1 {
+		t.Fatalf("second ensure = %v after %d renewals", err, calls)
+	}
+}
+
+// memOutbox is an in-memory SyncOutbox.
+type memOutbox struct {
+	ops      []storage.SyncOp
+	versions map[int64]int64
+}
+
+func (o *memOutbox) PendingSyncOps(_ context.Context, limit int) ([]storage.SyncOp, error) {
+	return o.ops[:min(limit, len(o.ops))], nil
+}
+
+func (o *memOutbox) AckSyncOps(_ context.Context, throughSeq int64) error {
+	for len(o.ops) > 0 && o.ops[0].Seq <= throughSeq {
+		o.ops = o.ops[1:]
+	}
+	return nil
+}
+
+func (o *memOutbox) SyncServerVersion(_ context.Context, pid int64) (int64, error) {
+	return o.versions[pid], nil
+}
+
+func (o *memOutbox) SetSyncServerVersion(_ context.Context, pid int64, v int64) error {
+	o.versions[pid] = v
+	return nil
+}
+
+// syncServer is a fake push endpoint that stores each op_id once and fails the push numbered failAt.
+type syncServer struct {
+	version        int64
+	seen           map[string]bool
+	clientVersions []int64
+	pushes         int
+	failAt         int
+}
+
+func (s *syncServer) start(t *testing.T) *httptest.Server {
+	t.Helper()
+	s.seen = map[string]bool{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ClientVersion int64         `json:"client_version"`
+			Ops           []SyncOpInput `json:"ops"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		s.pushes++
+		if s.pushes == s.failAt {
+			http.Error(w, `{"error":"bad op"}`, http.StatusBadRequest)
+			return
+		}
+		s.clientVersions = append(s.clientVersions, req.ClientVersion)
+		res := PushResult{ProjectID: 5}
+		for _, op := range req.Ops {
+			if s.seen[op.OpID] {
+				res.Duplicates++
+				continue
+			}
+			s.seen[op.OpID] = true
+			s.version++
+			res.Accepted++
+		}
+		res.ServerVersion = s.version
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func pendingOps(n int) []storage.SyncOp {
+	ops := make([]storage.SyncOp, 0, n)
+	for i := 1; i <= n; i++ {
+		ops = append(ops, storage.SyncOp{
+			Seq: int64(i), OpID: fmt.Sprintf("00000000-0000-5000-8000-%012d", i), OpType: storage.SyncOpUpdate,
+			EntityType: storage.SyncEntityPage, EntityID: fmt.Sprintf("issue:1/page:%d", i), Payload: json.RawMessage(`{}`),
+		})
+	}
+	return ops
+}
+
+func TestClient_PushPendingDrainsInBatches(t *testing.T) {
+	defer func(n int) { pushBatchSize = n }(pushBatchSize)
+	pushBatchSize = 2
+	s := &syncServer{version: 10}
+	c := testClient(s.start(t).URL)
+	out := &memOutbox{ops: pendingOps(5), versions: map[int64]int64{5: 10}}
+	c.Outbox = out
+
+	res, err := c.PushPending(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("push pending: %v", err)
+	}
+	if res.Accepted != 5 || res.ServerVersion != 15 || len(out.ops) != 0 || out.versions[5] != 15 {
+		t.Fatalf("res %+v, %d ops left, stored version %d", res, len(out.ops), out.versions[5])
+	}
+	// Each batch carries the server version the previous one returned
+	if want := []int64{10, 12, 14}; fmt.Sprint(s.clientVersions) != fmt.Sprint(want) {
+		t.Fatalf("client versions %v, want %v", s.clientVersions, want)
+	}
+	// Nothing pending: no request
+	if res, err := c.PushPending(context.Background(), 5); err != nil || res.Accepted != 0 || s.pushes != 3 {
+		t.Fatalf("empty drain: %+v %v after %d pushes", res, err, s.pushes)
+	}
+}
+
+func TestClient_PushPendingResumesAfterFailure(t *testing.T) {
+	defer func(n int) { pushBatchSize = n }(pushBatchSize)
+	pushBatchSize = 2
+	s := &syncServer{failAt: 2}
+	c := testClient(s.start(t).URL)
+	out := &memOutbox{ops: pendingOps(3), versions: map[int64]int64{}}
+	c.Outbox = out
+
+	res, err := c.PushPending(context.Background(), 5)
+	var se *Error
+	if !errors.As(err, &se) || se.StatusCode != http.StatusBadRequest {
+		t.Fatalf("err = %v, want the 400 of the second batch", err)
+	}
+	if res.Accepted != 2 || len(out.ops) != 1 || out.versions[5] != 2 {
+		t.Fatalf("after failure: res %+v, %d ops left, stored version %d", res, len(out.ops), out.versions[5])
+	}
+	// An op pushed before but not acknowledged is a duplicate on the next drain
+	out.ops = append(pendingOps(2)[1:], out.ops...)
+	res, err = c.PushPending(context.Background(), 5)
+	if err != nil || res.Accepted != 1 || res.Duplicates != 1 || res.ServerVersion != 3 || len(out.ops) != 0 {
+		t.Fatalf("resume: res %+v err %v, %d ops left", res, err, len(out.ops))
+	}
+	if _, err := NewClient("http://localhost", "").PushPending(context.Background(), 5); err == nil {
+		t.Fatalf("push pending without outbox should fail")
+	}
+}
+
+// pagedProjectServer serves n projects in pages; with legacy set it ignores the paging parameters and
+// returns a plain array like servers before pagination support.
+func pagedProjectServer(t *testing.T, n int, legacy bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		all := make([]Project, n)
+		for i := range all {
+			all[i] = Project{ID: int64(i + 1), Name: fmt.Sprintf("P%d", i+1)}
+		}
+		if legacy {
+			_ = json.NewEncoder(w).Encode(all)
+			return
+		}
+		start := 0
+		if c := r.URL.Query().Get("cursor"); c != "" {
+			_, _ = fmt.Sscan(c, &start)
+		}
+		end := min(start+2, n)
+		page := ProjectPage{Projects: all[start:end]}
+		if end < n {
+			page.NextCursor = fmt.Sprint(end)
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestClient_ListProjectsFollowsPages(t *testing.T) {
+	for _, c := range []struct {
+		n      int
+		legacy bool
+		calls  int32
+	}{
+		{0, false, 1},
+		{2, false, 1}, // exactly one full page
+		{3, false, 2},
+		{4, false, 2},
+		{5, true, 1},
+	} {
+		srv, calls := pagedProjectServer(t, c.n, c.legacy)
+		list, err := testClient(srv.URL).ListProjects(context.Background())
+		if err != nil || len(list) != c.n || calls.Load() != c.calls {
+			t.Fatalf("n=%d legacy=%v: %d projects in %d calls (%v)", c.n, c.legacy, len(list), calls.Load(), err)
+		}
+		for i, p := range list {
+			if p.ID != int64(i+1) {
+				t.Fatalf("n=%d: project %d has id %d", c.n, i, p.ID)
+			}
+		}
+	}
+}
+
+// pullServer serves ops with the given sparse versions, limit per page, like the sync pull endpoint.
+func pullServer(t *testing.T, versions []int64, legacy bool) (*httptest.Server, *[]int64) {
+	t.Helper()
+	var sinces []int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var since int64
+		var limit int
+		_, _ = fmt.Sscan(r.URL.Query().Get("since"), &since)
+		_, _ = fmt.Sscan(r.URL.Query().Get("limit"), &limit)
+		sinces = append(sinces, since)
+		res := PullResult{ProjectID: 5, ServerVersion: versions[len(versions)-1], NextSince: since}
+		for _, v := range versions {
+			if v <= since {
+				continue
+			}
+			if len(res.Ops) == limit {
+				res.HasMore = true
+				break
+			}
+			res.Ops = append(res.Ops, SyncOp{Version: v})
+			res.NextSince = v
+		}
+		if legacy {
+			res.NextSince, res.HasMore = 0, false
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &sinces
+}
+
+func TestClient_PullAllOps(t *testing.T) {
+	versions := []int64{1, 2, 5, 9, 20}
+	srv, sinces := pullServer(t, versions, false)
+	res, err := testClient(srv.URL).PullAllOps(context.Background(), 5, 0, 2)
+	if err != nil {
+		t.Fatalf("pull all: %v", err)
+	}
+	if len(res.Ops) != 5 || res.NextSince != 20 || res.ServerVersion != 20 || fmt.Sprint(*sinces) != "[0 2 9]" {
+		t.Fatalf("res %+v after sinces %v", res, *sinces)
+	}
+	// Page size equal to the remaining ops ends without an extra request
+	srv, sinces = pullServer(t, versions, false)
+	if res, err := testClient(srv.URL).PullAllOps(context.Background(), 5, 5, 2); err != nil || len(res.Ops) != 2 || fmt.Sprint(*sinces) != "[5]" {
+		t.Fatalf("exact page: %+v %v after %v", res, err, *sinces)
+	}
+	// Servers without pagination support: one page, NextSince derived from the last op
+	srv, _ = pullServer(t, versions, true)
+	if res, err := testClient(srv.URL).PullOps(context.Background(), 5, 1, 2); err != nil || res.NextSince != 5 {
+		t.Fatalf("legacy pull: %+v %v", res, err)
+	}
+	// The safety cap stops a server that never runs out of pages
+	defer func(n int) { maxAutoPages = n }(maxAutoPages)
+	maxAutoPages = 2
+	srv, _ = pullServer(t, versions, false)
+	if _, err := testClient(srv.URL).PullAllOps(context.Background(), 5, 0, 1); !errors.Is(err, ErrTooManyPages) {
+		t.Fatalf("expected ErrTooManyPages, got %v", err)
+	}
+}
+
+func TestClient_GetIndexSnapshotConditional(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		version int64 = 1
+		snap          = []byte(`{"pages":1}`)
+		got304  atomic.Int32
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		v, s := version, snap
+		mu.Unlock()
+		if checkNotModified(w, r, versionETag(v, s), time.Time{}) {
+			got304.Add(1)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"project_id": 1, "version": v, "snapshot": json.RawMessage(s)})
+	}))
+	t.Cleanup(srv.Close)
+	c := testClient(srv.URL)
+	ctx := context.Background()
+
+	env, err := c.GetIndexSnapshot(ctx, 1)
+	if err != nil || env.Version != 1 {
+		t.Fatalf("first fetch: %+v %v", env, err)
+	}
+	if _, err := c.GetIndexSnapshot(ctx, 1); !errors.Is(err, ErrNotModified) || got304.Load() != 1 {
+		t.Fatalf("unchanged snapshot: err=%v, 304s=%d", err, got304.Load())
+	}
+	// Another project has no cached ETag yet
+	if _, err := c.GetIndexSnapshot(ctx, 2); err != nil {
+		t.Fatalf("other project: %v", err)
+	}
+
+	mu.Lock()
+	version, snap = 2, []byte(`{"pages":2}`)
+	mu.Unlock()
+	env, err = c.GetIndexSnapshot(ctx, 1)
+	if err != nil || env.Version != 2 {
+		t.Fatalf("changed version: %+v %v", env, err)
+	}
+	if _, err := c.GetIndexSnapshot(ctx, 1); !errors.Is(err, ErrNotModified) {
+		t.Fatalf("after the change was fetched: %v", err)
+	}
+	// A client without the ETag gets the body
+	if env, err := testClient(srv.URL).GetIndexSnapshot(ctx, 1); err != nil || env.Version != 2 {
+		t.Fatalf("fresh client: %+v %v", env, err)
+	}
+}
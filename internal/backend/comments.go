@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	applog "gocomicwriter/internal/log"
+)
+
+// Limits for review comments.
+const (
+	maxCommentBody = 8 << 10 // bytes of comment text
+	maxCommentPath = 512     // bytes of the anchoring document path
+)
+
+var (
+	errCommentNotFound = errors.New("comment not found")
+	errCommentEmpty    = errors.New("comment body required")
+	errCommentTooLong  = fmt.Errorf("comment body exceeds %d bytes", maxCommentBody)
+	errCommentPath     = fmt.Errorf("path required (at most %d bytes)", maxCommentPath)
+)
+
+// commentRow is the JSON shape of a review comment.
+type commentRow struct {
+	ID         int64      `json:"id"`
+	ProjectID  int64      `json:"project_id"`
+	Path       string     `json:"path"`
+	Author     string     `json:"author"`
+	Body       string     `json:"body"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+const commentColumns = `id, project_id, path, author, body, created_at, resolved_at`
+
+func scanComment(sc interface{ Scan(...any) error }) (commentRow, error) {
+	var c commentRow
+	var resolved sql.NullTime
+	if err := sc.Scan(&c.ID, &c.ProjectID, &c.Path, &c.Author, &c.Body, &c.CreatedAt, &resolved); err != nil {
+		return commentRow{}, err
+	}
+	if resolved.Valid {
+		c.ResolvedAt = &resolved.Time
+	}
+	return c, nil
+}
+
+// likePrefix returns a LIKE pattern (with '\' as escape character) matching strings that start with prefix.
+func likePrefix(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix) + "%"
+}
+
+// listComments returns the project's comments whose path starts with pathPrefix, oldest first.
+// A non-nil resolved keeps only resolved (true) or open (false) comments.
+func listComments(ctx context.Context, db *sql.DB, pid int64, pathPrefix string, resolved *bool) ([]commentRow, error) {
+	var state any
+	if resolved != nil {
+		state = *resolved
+	}
+	rows, err := db.QueryContext(ctx, `SELECT `+commentColumns+` FROM comments
+		WHERE project_id = $1 AND path LIKE $2 ESCAPE '\'
+		  AND ($3::boolean IS NULL OR (resolved_at IS NOT NULL) = $3::boolean)
+		ORDER BY created_at, id`, pid, likePrefix(pathPrefix), state)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	list := []commentRow{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
+
+// addComment stores a comment by author on the document at path.
+func addComment(ctx context.Context, db *sql.DB, pid int64, author, path, body string) (commentRow, error) {
+	path = strings.TrimSpace(path)
+	if path == "" || len(path) > maxCommentPath {
+		return commentRow{}, errCommentPath
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return commentRow{}, errCommentEmpty
+	}
+	if len(body) > maxCommentBody {
+		return commentRow{}, errCommentTooLong
+	}
+	return scanComment(db.QueryRowContext(ctx, `INSERT INTO comments(project_id, path, author, body)
+		VALUES ($1, $2, $3, $4) RETURNING `+commentColumns, pid, path, author, body))
+}
+
+// setCommentResolved resolves a comment of the project, or reopens it when resolved is false.
+// Resolving an already resolved comment keeps its original resolved_at.
+func setCommentResolved(ctx context.Context, db *sql.DB, pid, id int64, resolved bool) (commentRow, error) {
+	c, err := scanComment(db.QueryRowContext(ctx, `UPDATE comments
+		SET resolved_at = CASE WHEN $3 THEN COALESCE(resolved_at, now()) END
+		WHERE project_id = $1 AND id = $2 RETURNING `+commentColumns, pid, id, resolved))
+	if errors.Is(err, sql.ErrNoRows) {
+		return commentRow{}, errCommentNotFound
+	}
+	return c, err
+}
+
+// serveComments handles /api/projects/{id}/comments (GET, POST) and /comments/{cid} (PATCH). rest holds
+// the path segments after "comments"; membership has already been checked.
+func serveComments(w http.ResponseWriter, r *http.Request, db *sql.DB, pid int64, sub string, rest []string) {
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		q := r.URL.Query()
+		var resolved *bool
+		if v := q.Get("resolved"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid resolved"))
+				return
+			}
+			resolved = &b
+		}
+		list, err := listComments(r.Context(), db, pid, q.Get("path"), resolved)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, list)
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		// JSON body: { "path": "issue:1/page:3/panel:p2", "body": "Tighten this beat" }
+		var req struct {
+			Path string `json:"path"`
+			Body string `json:"body"`
+		}
+		if !readJSONBody(w, r, &req) {
+			return
+		}
+		c, err := addComment(r.Context(), db, pid, sub, req.Path, req.Body)
+		switch {
+		case errors.Is(err, errCommentTooLong):
+			writeError(w, http.StatusRequestEntityTooLarge, err)
+		case errors.Is(err, errCommentEmpty), errors.Is(err, errCommentPath):
+			writeError(w, http.StatusBadRequest, err)
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err)
+		default:
+			applog.WithComponent("backend").Info("comment added", slog.Int64("project_id", pid), slog.Int64("comment_id", c.ID), slog.String("path", c.Path), slog.String("author", sub))
+			writeJSON(w, http.StatusCreated, c)
+		}
+	case len(rest) == 1 && r.Method == http.MethodPatch:
+		id, err := strconv.ParseInt(rest[0], 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid comment id"))
+			return
+		}
+		// JSON body: { "resolved": true } (false reopens the comment)
+		var req struct {
+			Resolved *bool `json:"resolved"`
+		}
+		if !readJSONBody(w, r, &req) {
+			return
+		}
+		if req.Resolved == nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("resolved required"))
+			return
+		}
+		c, err := setCommentResolved(r.Context(), db, pid, id, *req.Resolved)
+		switch {
+		case errors.Is(err, errCommentNotFound):
+			writeError(w, http.StatusNotFound, err)
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err)
+		default:
+			writeJSON(w, http.StatusOK, c)
+		}
+	case len(rest) <= 1:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// readJSONBody decodes a request body of at most 1 MiB into dst, writing a 400 response on failure.
+func readJSONBody(w http.ResponseWriter, r *http.Request, dst any) bool {
+	b, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body"))
+		return false
+	}
+	_ = r.Body.Close()
+	if err := json.Unmarshal(b, dst); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid json"))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLikePrefix(t *testing.T) {
+	cases := map[string]string{
+		"":                 "%",
+		"issue:1/page:3/":  "issue:1/page:3/%",
+		"panel:p_1":        `panel:p\_1%`,
+		`100%\done`:        `100\%\\done%`,
+		"issue:1/page:12/": "issue:1/page:12/%",
+	}
+	for in, want := range cases {
+		if got := likePrefix(in); got != want {
+			t.Errorf("likePrefix(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// Requests rejected before any database access.
+func TestServeComments_BadRequests(t *testing.T) {
+	cases := []struct {
+		method string
+		rest   []string
+		body   string
+		want   int
+	}{
+		{http.MethodDelete, nil, "", http.StatusMethodNotAllowed},
+		{http.MethodPatch, []string{"abc"}, `{"resolved":true}`, http.StatusBadRequest},
+		{http.MethodPatch, []string{"5"}, `{}`, http.StatusBadRequest},
+		{http.MethodPost, nil, `{"path":`, http.StatusBadRequest},
+		{http.MethodGet, []string{"5", "replies"}, "", http.StatusNotFound},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(c.method, "/api/projects/1/comments", strings.NewReader(c.body))
+		serveComments(rec, req, nil, 1, "me@example.com", c.rest)
+		if rec.Code != c.want {
+			t.Errorf("%s %v: status %d, want %d", c.method, c.rest, rec.Code, c.want)
+		}
+	}
+}
+
+func TestComments_Integration(t *testing.T) {
+	db := openPGForTest(t)
+	defer func() { _ = db.Close() }()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var pid int64
+	if err := db.QueryRowContext(ctx, `INSERT INTO projects(name) VALUES ($1) RETURNING id`, fmt.Sprintf("Comments %d", time.Now().UnixNano())).Scan(&pid); err != nil {
+		t.Fatalf("insert project: %v", err)
+	}
+	p3, err := addComment(ctx, db, pid, "ed@example.com", "issue:1/page:3/panel:p1", "  Tighten this beat ")
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if p3.Body != "Tighten this beat" || p3.Author != "ed@example.com" || p3.ResolvedAt != nil {
+		t.Fatalf("unexpected comment: %+v", p3)
+	}
+	if _, err := addComment(ctx, db, pid, "ed@example.com", "issue:1/page:30/panel:p1", "Other page"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := addComment(ctx, db, pid, "ed@example.com", "issue:1/page:3/panel:p1", strings.Repeat("x", maxCommentBody+1)); !errors.Is(err, errCommentTooLong) {
+		t.Fatalf("expected errCommentTooLong, got %v", err)
+	}
+	if _, err := addComment(ctx, db, pid, "ed@example.com", " ", "text"); !errors.Is(err, errCommentPath) {
+		t.Fatalf("expected errCommentPath, got %v", err)
+	}
+
+	list, err := listComments(ctx, db, pid, "issue:1/page:3/", nil)
+	if err != nil || len(list) != 1 || list[0].ID != p3.ID {
+		t.Fatalf("page 3 comments = %+v (%v)", list, err)
+	}
+	res, err := setCommentResolved(ctx, db, pid, p3.ID, true)
+	if err != nil || res.ResolvedAt == nil {
+		t.Fatalf("resolve: %+v (%v)", res, err)
+	}
+	open := false
+	if list, err := listComments(ctx, db, pid, "", &open); err != nil || len(list) != 1 || list[0].Path != "issue:1/page:30/panel:p1" {
+		t.Fatalf("open comments = %+v (%v)", list, err)
+	}
+	if _, err := setCommentResolved(ctx, db, pid+1000000, p3.ID, true); !errors.Is(err, errCommentNotFound) {
+		t.Fatalf("resolving another project's comment: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVersionETag(t *testing.T) {
+	a := versionETag(3, []byte(`{"a":1}`))
+	if a != versionETag(3, []byte(`{"a":1}`)) {
+		t.Fatal("ETag not deterministic")
+	}
+	if a == versionETag(4, []byte(`{"a":1}`)) || a == versionETag(3, []byte(`{"a":2}`)) {
+		t.Fatal("ETag ignores the version or the content")
+	}
+	if a[0] != '"' || a[len(a)-1] != '"' {
+		t.Fatalf("ETag not quoted: %s", a)
+	}
+}
+
+func TestCheckNotModified(t *testing.T) {
+	etag := versionETag(7, []byte("snap"))
+	modified := time.Date(2025, 3, 1, 12, 0, 0, 500e6, time.UTC)
+	for _, c := range []struct {
+		name   string
+		method string
+		header map[string]string
+		want   bool
+	}{
+		{"no validators", http.MethodGet, nil, false},
+		{"etag hit", http.MethodGet, map[string]string{"If-None-Match": etag}, true},
+		{"weak etag in list", http.MethodGet, map[string]string{"If-None-Match": `"other", W/` + etag}, true},
+		{"wildcard", http.MethodGet, map[string]string{"If-None-Match": "*"}, true},
+		{"etag miss", http.MethodGet, map[string]string{"If-None-Match": `"v6-00"`}, false},
+		{"etag miss wins over date", http.MethodGet, map[string]string{"If-None-Match": `"v6-00"`, "If-Modified-Since": modified.Add(time.Hour).Format(http.TimeFormat)}, false},
+		{"not modified since", http.MethodGet, map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, true},
+		{"modified since", http.MethodGet, map[string]string{"If-Modified-Since": modified.Add(-time.Second).Format(http.TimeFormat)}, false},
+		{"bad date", http.MethodGet, map[string]string{"If-Modified-Since": "yesterday"}, false},
+		{"post", http.MethodPost, map[string]string{"If-None-Match": etag}, false},
+	} {
+		r := httptest.NewRequest(c.method, "/api/projects/1/index", nil)
+		for k, v := range c.header {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		if got := checkNotModified(w, r, etag, modified); got != c.want {
+			t.Errorf("%s: not modified = %v, want %v", c.name, got, c.want)
+		}
+		if w.Header().Get("ETag") != etag || w.Header().Get("Last-Modified") != "Sat, 01 Mar 2025 12:00:00 GMT" {
+			t.Errorf("%s: validators not set: %v", c.name, w.Header())
+		}
+		if c.want && w.Code != http.StatusNotModified {
+			t.Errorf("%s: status %d", c.name, w.Code)
+		}
+	}
+}
@@ -21,7 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
@@ -34,6 +34,7 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	_ "github.com/jackc/pgx/v5/stdlib"
 
+	applog "gocomicwriter/internal/log"
 	"gocomicwriter/internal/storage"
 )
 
@@ -49,8 +50,13 @@ type Config struct {
 	TLSKeyFile      string
 	AuthMode        string // dev | static
 	AdminAPIKey     string
-	ObjectHealthURL string // e.g., http://minio:9000/minio/health/ready
-	ObjectHealthReq bool   // if true, failing object health makes readyz fail
+	AuthSecret      string        // signs bearer tokens; devAuthSecret when GCW_AUTH_SECRET is unset
+	MaxSession      time.Duration // refreshed tokens expire at most this long after sign-in
+	ObjectHealthURL string        // e.g., http://minio:9000/minio/health/ready
+	ObjectHealthReq bool          // if true, failing object health makes readyz fail
+	// ReadyProbeInterval is how often the database and object storage are probed for /readyz.
+	ReadyProbeInterval time.Duration
+	Limits             LimitConfig // request rate and body size limits
 }
 
 func getenvBool(name string, def bool) bool {
@@ -90,6 +96,18 @@ func loadConfig() Config {
 		cfg.AuthMode = "dev"
 	}
 	cfg.AdminAPIKey = os.Getenv("GCW_ADMIN_API_KEY")
+	cfg.AuthSecret = os.Getenv("GCW_AUTH_SECRET")
+	if cfg.AuthSecret == "" {
+		cfg.AuthSecret = devAuthSecret
+	}
+	cfg.MaxSession = defaultMaxSession
+	if v := strings.TrimSpace(os.Getenv("GCW_AUTH_MAX_SESSION")); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.MaxSession = d
+		} else {
+			applog.WithComponent("backend").Warn("invalid GCW_AUTH_MAX_SESSION; using default", slog.String("value", v), slog.Duration("default", defaultMaxSession))
+		}
+	}
 	cfg.ObjectHealthURL = os.Getenv("GCW_OBJECT_HEALTH_URL")
 	if cfg.ObjectHealthURL == "" {
 		if ep := os.Getenv("GCW_MINIO_ENDPOINT"); ep != "" {
@@ -102,6 +120,16 @@ func loadConfig() Config {
 		}
 	}
 	cfg.ObjectHealthReq = getenvBool("GCW_OBJECT_HEALTH_REQUIRED", false)
+	cfg.ReadyProbeInterval = defaultReadyProbeInterval
+	if v := strings.TrimSpace(os.Getenv("GCW_READY_PROBE_INTERVAL")); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.ReadyProbeInterval = d
+		} else {
+			applog.WithComponent("backend").Warn("invalid GCW_READY_PROBE_INTERVAL; using default", slog.String("value", v), slog.Duration("default", defaultReadyProbeInterval))
+		}
+	}
+
+	cfg.Limits = loadLimitConfig()
 
 	if cfg.DBURL == "" {
 		// Reasonable local default; requires a DB set up by the developer
@@ -114,37 +142,51 @@ func loadConfig() Config {
 func Start() error {
 	cfg := loadConfig()
 
-	db, err := sql.Open("pgx", cfg.DBURL)
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	db, err := openMigratedDB(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("open db: %w", err)
+		return err
 	}
-	defer func() {
-		if err := db.Close(); err != nil {
-			log.Printf("db close: %v", err)
-		}
-	}()
+	defer closeDB(db)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	if err := db.PingContext(ctx); err != nil {
-		if isInvalidCatalog(err) {
-			if err2 := tryCreateMissingDatabase(ctx, cfg.DBURL); err2 != nil {
-				return fmt.Errorf("ping db: %w; additionally failed to create database: %v", err, err2)
-			}
-			// Retry ping after creating the database
-			if err3 := db.PingContext(ctx); err3 != nil {
-				return fmt.Errorf("ping db after create: %w", err3)
-			}
-		} else {
-			return fmt.Errorf("ping db: %w", err)
-		}
+	metrics := newHTTPMetrics()
+	// Dependencies are probed in the background; /readyz and /metrics report the last results
+	var objects probeFunc
+	if cfg.ObjectHealthURL != "" {
+		objects = httpProbe(&http.Client{Timeout: readyProbeTimeout}, cfg.ObjectHealthURL)
+	}
+	ready := newReadinessChecker(db.PingContext, objects, cfg.ObjectHealthReq, applog.WithComponent("backend"))
+	probeCtx, stopProbes := context.WithCancel(context.Background())
+	defer stopProbes()
+	ready.probe(probeCtx)
+	go ready.run(probeCtx, cfg.ReadyProbeInterval)
+	metrics.collectors = append(metrics.collectors, ready)
+	if cfg.AuthSecret == devAuthSecret {
+		applog.WithComponent("backend").Warn("GCW_AUTH_SECRET not set; using insecure dev secret")
 	}
 
-	if err := applyMigrations(ctx, db); err != nil {
-		return fmt.Errorf("migrate: %w", err)
+	server := &http.Server{
+		Addr:    cfg.Addr,
+		Handler: withRequestLogging(withLimits(newMux(cfg, db, ready, metrics), cfg.Limits, cfg.AuthSecret), metrics),
+	}
+	applog.WithComponent("backend").Info("gcwserver listening", slog.String("addr", cfg.Addr), slog.Bool("tls", cfg.TLSEnable), slog.String("mode", cfg.AuthMode))
+	if cfg.TLSEnable {
+		if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+			return fmt.Errorf("TLS enabled but GCW_TLS_CERT_FILE or GCW_TLS_KEY_FILE not set")
+		}
+		return server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
 	}
+	return server.ListenAndServe()
+}
 
+// newMux routes the server API for cfg on the migrated database db. ready answers /readyz and metrics
+// /metrics; Start wraps the result in the limits and request logging middleware.
+func newMux(cfg Config, db *sql.DB, ready http.Handler, metrics *httpMetrics) *http.ServeMux {
 	mux := http.NewServeMux()
+	presence := newPresenceRegistry()
+	// Metrics in Prometheus text format (unauthenticated, like the health endpoints)
+	mux.Handle("/metrics", metrics)
 	// Health endpoints
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusOK, map[string]any{
@@ -153,49 +195,7 @@ func Start() error {
 			"time":    time.Now().UTC().Format(time.RFC3339),
 		})
 	})
-	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
-		defer cancel()
-		status := "ready"
-		dbOK := true
-		objOK := true
-		if err := db.PingContext(ctx); err != nil {
-			dbOK = false
-			status = "degraded"
-		}
-		if cfg.ObjectHealthURL != "" {
-			client := &http.Client{Timeout: 2 * time.Second}
-			req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.ObjectHealthURL, nil)
-			if err != nil {
-				objOK = false
-				if cfg.ObjectHealthReq {
-					status = "not_ready"
-				}
-			} else {
-				resp, err := client.Do(req)
-				if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
-					objOK = false
-					if cfg.ObjectHealthReq {
-						status = "not_ready"
-					}
-				}
-				if resp != nil && resp.Body != nil {
-					_ = resp.Body.Close()
-				}
-			}
-		}
-		code := http.StatusOK
-		if !dbOK || (cfg.ObjectHealthReq && !objOK) {
-			code = http.StatusServiceUnavailable
-		}
-		writeJSON(w, code, map[string]any{
-			"status":  status,
-			"db":      dbOK,
-			"objects": objOK,
-			"version": getVersion(),
-			"time":    time.Now().UTC().Format(time.RFC3339),
-		})
-	})
+	mux.Handle("/readyz", ready)
 	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
 		ver := getVersion()
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
@@ -203,14 +203,9 @@ func Start() error {
 		_, _ = w.Write([]byte(ver))
 	})
 
-	// Auth secret (dev-friendly default)
-	secret := os.Getenv("GCW_AUTH_SECRET")
-	if secret == "" {
-		secret = "dev-secret-change-me"
-		log.Printf("WARN: GCW_AUTH_SECRET not set; using insecure dev secret")
-	}
+	secret := cfg.AuthSecret
 
-	// POST /api/auth/token → { token, expires_at }
+	// POST /api/auth/token → { token, subject, expires_at, session_expires_at }
 	mux.HandleFunc("/api/auth/token", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			w.WriteHeader(http.StatusMethodNotAllowed)
@@ -237,9 +232,7 @@ func Start() error {
 		if sub == "" {
 			sub = strings.TrimSpace(req.Subject)
 		}
-		if req.TTLSeconds <= 0 || req.TTLSeconds > 24*3600 {
-			req.TTLSeconds = 3600
-		}
+		ttl := tokenTTL(time.Duration(req.TTLSeconds) * time.Second)
 		if cfg.AuthMode == "static" {
 			if cfg.AdminAPIKey == "" || r.Header.Get("X-API-Key") != cfg.AdminAPIKey {
 				w.WriteHeader(http.StatusUnauthorized)
@@ -251,8 +244,7 @@ func Start() error {
 				return
 			}
 			// ensure user exists or upsert display_name
-			if _, err := db.ExecContext(r.Context(), `INSERT INTO users(email, display_name) VALUES ($1, NULLIF($2,'') )
-				ON CONFLICT (email) DO UPDATE SET display_name = COALESCE(EXCLUDED.display_name, users.display_name)`, sub, req.DisplayName); err != nil {
+			if err := upsertUser(r.Context(), db, sub, req.DisplayName); err != nil {
 				writeError(w, http.StatusInternalServerError, err)
 				return
 			}
@@ -260,42 +252,43 @@ func Start() error {
 		if sub == "" {
 			sub = "dev"
 		}
-		exp := time.Now().Add(time.Duration(req.TTLSeconds) * time.Second)
+		exp := time.Now().Add(ttl)
 		tok, err := signToken(secret, sub, exp)
 		if err != nil {
 			writeError(w, http.StatusInternalServerError, err)
 			return
 		}
 		writeJSON(w, http.StatusOK, map[string]any{
-			"token":      tok,
-			"subject":    sub,
-			"expires_at": exp.UTC().Format(time.RFC3339),
+			"token":              tok,
+			"subject":            sub,
+			"expires_at":         exp.UTC().Format(time.RFC3339),
+			"session_expires_at": time.Now().Add(cfg.MaxSession).UTC().Format(time.RFC3339),
 		})
 	})
 
+	// POST /api/auth/refresh (Authorization: Bearer <still valid token>) → { token, subject, expires_at, session_expires_at }
+	mux.HandleFunc("/api/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
+		serveRefresh(w, r, db, cfg)
+	})
+
 	// Auth wrapper verifying token and (in static mode) user existence
 	authWrap := func(next func(w http.ResponseWriter, r *http.Request, sub string)) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
-			auth := r.Header.Get("Authorization")
-			const prefix = "Bearer "
-			if !strings.HasPrefix(strings.ToLower(auth), strings.ToLower(prefix)) {
-				w.WriteHeader(http.StatusUnauthorized)
-				_, _ = w.Write([]byte("missing bearer token"))
+			token, ok := bearerToken(r)
+			if !ok {
+				writeError(w, http.StatusUnauthorized, errors.New("missing bearer token"))
 				return
 			}
-			token := strings.TrimSpace(auth[len(prefix):])
 			sub, err := verifyToken(secret, token)
 			if err != nil {
-				w.WriteHeader(http.StatusUnauthorized)
-				_, _ = w.Write([]byte("invalid token"))
+				writeError(w, http.StatusUnauthorized, errors.New("invalid token"))
 				return
 			}
 			if cfg.AuthMode == "static" {
 				var x int
 				if err := db.QueryRowContext(r.Context(), `SELECT 1 FROM users WHERE email = $1`, sub).Scan(&x); err != nil {
 					if errors.Is(err, sql.ErrNoRows) {
-						w.WriteHeader(http.StatusForbidden)
-						_, _ = w.Write([]byte("user not allowed"))
+						writeError(w, http.StatusForbidden, errors.New("user not allowed"))
 						return
 					}
 					writeError(w, http.StatusInternalServerError, err)
@@ -308,49 +301,85 @@ func Start() error {
 					return
 				}
 			}
+			setRequestSubject(r, sub)
 			next(w, r, sub)
 		}
 	}
-	// GET /api/projects (auth required)
+	// GET /api/projects lists the caller's projects; POST /api/projects creates one owned by the caller (auth required)
 	mux.HandleFunc("/api/projects", authWrap(func(w http.ResponseWriter, r *http.Request, sub string) {
-		rows, err := db.QueryContext(r.Context(), `SELECT p.id, p.stable_id, p.name, p.updated_at, p.version
-		FROM projects p
-		JOIN project_members pm ON pm.project_id = p.id
-		JOIN users u ON u.id = pm.user_id
-		WHERE u.email = $1
-		ORDER BY p.updated_at DESC`, sub)
-		if err != nil {
-			writeError(w, http.StatusInternalServerError, err)
-			return
-		}
-		defer func() { _ = rows.Close() }()
-		type proj struct {
-			ID        int64     `json:"id"`
-			StableID  string    `json:"stable_id"`
-			Name      string    `json:"name"`
-			UpdatedAt time.Time `json:"updated_at"`
-			Version   int64     `json:"version"`
-		}
-		var list []proj
-		for rows.Next() {
-			var p proj
-			if err := rows.Scan(&p.ID, &p.StableID, &p.Name, &p.UpdatedAt, &p.Version); err != nil {
+		switch r.Method {
+		case http.MethodGet:
+			// Without limit or cursor the response is the full list as a plain array, as before; with either
+			// it is a {projects, next_cursor} page.
+			q := r.URL.Query()
+			if !q.Has("limit") && !q.Has("cursor") {
+				list, err := listProjects(r.Context(), db, sub)
+				if err != nil {
+					writeError(w, http.StatusInternalServerError, err)
+					return
+				}
+				writeJSON(w, http.StatusOK, list)
+				return
+			}
+			limit := defaultProjectPageSize
+			if ls := q.Get("limit"); ls != "" {
+				v, err := strconv.Atoi(ls)
+				if err != nil || v <= 0 {
+					writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit"))
+					return
+				}
+				limit = min(v, maxProjectPageSize)
+			}
+			page, err := listProjectsPage(r.Context(), db, sub, q.Get("cursor"), limit)
+			if err != nil {
+				if errors.Is(err, errInvalidCursor) {
+					writeError(w, http.StatusBadRequest, err)
+					return
+				}
 				writeError(w, http.StatusInternalServerError, err)
 				return
 			}
-			list = append(list, p)
-		}
-		if err := rows.Err(); err != nil {
-			writeError(w, http.StatusInternalServerError, err)
-			return
+			writeJSON(w, http.StatusOK, page)
+		case http.MethodPost:
+			// JSON body: { "name": "My Comic", "slug": "my-comic" } (slug optional)
+			var req struct {
+				Name string `json:"name"`
+				Slug string `json:"slug"`
+			}
+			b, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body"))
+				return
+			}
+			_ = r.Body.Close()
+			if err := json.Unmarshal(b, &req); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid json"))
+				return
+			}
+			if strings.TrimSpace(req.Name) == "" {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("name required"))
+				return
+			}
+			p, err := createProject(r.Context(), db, sub, req.Name, req.Slug)
+			if err != nil {
+				if errors.Is(err, errInvalidSlug) {
+					writeError(w, http.StatusBadRequest, err)
+					return
+				}
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+			applog.WithComponent("backend").Info("project created", slog.Int64("project_id", p.ID), slog.String("slug", p.Slug), slog.String("owner", sub))
+			writeJSON(w, http.StatusCreated, p)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
-		writeJSON(w, http.StatusOK, list)
 	}))
 
-	// Project-scoped endpoints (auth required): index snapshot, sync push/pull
+	// Project-scoped endpoints (auth required): index snapshot, search, comments, sync push/pull, reindex
 	mux.HandleFunc("/api/projects/", authWrap(func(w http.ResponseWriter, r *http.Request, sub string) {
 		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-		if len(parts) < 4 || parts[0] != "api" || parts[1] != "projects" {
+		if len(parts) < 3 || parts[0] != "api" || parts[1] != "projects" {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
@@ -359,13 +388,39 @@ func Start() error {
 			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid project id"))
 			return
 		}
-		// Enforce membership: user must be a member of this project
+		// /api/projects/{id} (DELETE): soft delete, owners only
+		if len(parts) == 3 {
+			if r.Method != http.MethodDelete {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			switch err := softDeleteProject(r.Context(), db, sub, pid); {
+			case errors.Is(err, errProjectNotFound):
+				writeError(w, http.StatusNotFound, err)
+			case errors.Is(err, errNotProjectOwner):
+				writeError(w, http.StatusForbidden, err)
+			case err != nil:
+				writeError(w, http.StatusInternalServerError, err)
+			default:
+				applog.WithComponent("backend").Info("project deleted", slog.Int64("project_id", pid), slog.String("by", sub))
+				w.WriteHeader(http.StatusNoContent)
+			}
+			return
+		}
+		// /api/projects/{id}/reindex (POST) and /reindex/{job} (GET): owners, or admins by API key
+		if len(parts) >= 4 && parts[3] == "reindex" {
+			admin := cfg.AdminAPIKey != "" && r.Header.Get("X-API-Key") == cfg.AdminAPIKey
+			serveReindex(w, r, db, pid, sub, admin, parts[4:])
+			return
+		}
+		// Enforce membership: user must be a member of this (not deleted) project
 		{
 			var x int
 			if err := db.QueryRowContext(r.Context(), `SELECT 1
 				FROM project_members pm
 				JOIN users u ON u.id = pm.user_id
-				WHERE u.email = $1 AND pm.project_id = $2`, sub, pid).Scan(&x); err != nil {
+				JOIN projects p ON p.id = pm.project_id
+				WHERE u.email = $1 AND pm.project_id = $2 AND p.deleted_at IS NULL`, sub, pid).Scan(&x); err != nil {
 				if errors.Is(err, sql.ErrNoRows) {
 					w.WriteHeader(http.StatusForbidden)
 					_, _ = w.Write([]byte("forbidden"))
@@ -375,7 +430,7 @@ func Start() error {
 				return
 			}
 		}
-		// /api/projects/{id}/index (GET)
+		// /api/projects/{id}/index (GET); conditional on If-None-Match / If-Modified-Since
 		if len(parts) == 4 && parts[3] == "index" {
 			if r.Method != http.MethodGet {
 				w.WriteHeader(http.StatusMethodNotAllowed)
@@ -397,6 +452,9 @@ func Start() error {
 				writeError(w, http.StatusInternalServerError, err)
 				return
 			}
+			if checkNotModified(w, r, versionETag(version, snap), created) {
+				return
+			}
 			var raw any
 			if err := json.Unmarshal(snap, &raw); err != nil {
 				raw = json.RawMessage(snap)
@@ -462,6 +520,10 @@ func Start() error {
 				}
 			}
 			res, err := SearchPG(r.Context(), db, pid, q)
+			if errors.Is(err, storage.ErrInvalidQuery) {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
 			if err != nil {
 				writeError(w, http.StatusInternalServerError, err)
 				return
@@ -469,6 +531,16 @@ func Start() error {
 			writeJSON(w, http.StatusOK, res)
 			return
 		}
+		// /api/projects/{id}/comments (GET, POST) and /comments/{cid} (PATCH)
+		if len(parts) >= 4 && parts[3] == "comments" {
+			serveComments(w, r, db, pid, sub, parts[4:])
+			return
+		}
+		// /api/projects/{id}/presence (GET, POST heartbeat, DELETE)
+		if len(parts) >= 4 && parts[3] == "presence" {
+			servePresence(w, r, presence, pid, sub, parts[4:])
+			return
+		}
 		// /api/projects/{id}/sync/push (POST) and /sync/pull (GET)
 		if len(parts) == 5 && parts[3] == "sync" {
 			switch parts[4] {
@@ -478,14 +550,8 @@ func Start() error {
 					return
 				}
 				var req struct {
-					ClientVersion int64 `json:"client_version"`
-					Ops           []struct {
-						OpID       string          `json:"op_id"`
-						OpType     string          `json:"op_type"`
-						EntityType string          `json:"entity_type"`
-						EntityID   string          `json:"entity_id"`
-						Payload    json.RawMessage `json:"payload"`
-					} `json:"ops"`
+					ClientVersion int64         `json:"client_version"`
+					Ops           []pushOpInput `json:"ops"`
 				}
 				b, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
 				if err != nil {
@@ -497,48 +563,16 @@ func Start() error {
 					writeError(w, http.StatusBadRequest, fmt.Errorf("invalid json: %v", err))
 					return
 				}
-				ctx := r.Context()
-				tx, err := db.BeginTx(ctx, &sql.TxOptions{})
-				if err != nil {
-					writeError(w, http.StatusInternalServerError, err)
-					return
-				}
-				defer func() { _ = tx.Rollback() }()
-				var curVersion int64
-				row := tx.QueryRowContext(ctx, `SELECT version FROM projects WHERE id = $1 FOR UPDATE`, pid)
-				if err := row.Scan(&curVersion); err != nil {
-					if errors.Is(err, sql.ErrNoRows) {
-						writeError(w, http.StatusNotFound, fmt.Errorf("project not found"))
-						return
-					}
-					writeError(w, http.StatusInternalServerError, err)
+				res, err := pushOps(r.Context(), db, pid, sub, req.Ops)
+				switch {
+				case errors.Is(err, errProjectNotFound):
+					writeError(w, http.StatusNotFound, err)
 					return
-				}
-				newVersion := curVersion
-				for i, op := range req.Ops {
-					newVersion = curVersion + int64(i+1)
-					if op.Payload == nil || len(op.Payload) == 0 {
-						op.Payload = json.RawMessage("{}")
-					}
-					if _, err := tx.ExecContext(ctx, `INSERT INTO sync_ops (op_id, project_id, version, actor, op_type, entity_type, entity_id, payload) VALUES (COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()),$2,$3,$4,$5,$6,$7,$8)`,
-						op.OpID, pid, newVersion, sub, op.OpType, op.EntityType, op.EntityID, op.Payload); err != nil {
-						writeError(w, http.StatusInternalServerError, err)
-						return
-					}
-				}
-				if _, err := tx.ExecContext(ctx, `UPDATE projects SET version = $1 WHERE id = $2`, newVersion, pid); err != nil {
+				case err != nil:
 					writeError(w, http.StatusInternalServerError, err)
 					return
 				}
-				if err := tx.Commit(); err != nil {
-					writeError(w, http.StatusInternalServerError, err)
-					return
-				}
-				writeJSON(w, http.StatusOK, map[string]any{
-					"project_id":     pid,
-					"server_version": newVersion,
-					"accepted":       len(req.Ops),
-				})
+				writeJSON(w, http.StatusOK, res)
 				return
 			case "pull":
 				if r.Method != http.MethodGet {
@@ -556,63 +590,22 @@ func Start() error {
 						return
 					}
 				}
-				limit := 500
+				limit := defaultPullLimit
 				if ls := q.Get("limit"); ls != "" {
 					if v, err := strconv.Atoi(ls); err == nil && v > 0 {
-						if v > 5000 {
-							v = 5000
-						}
-						limit = v
+						limit = min(v, maxPullLimit)
 					}
 				}
-				ctx := r.Context()
-				var serverVersion int64
-				if err := db.QueryRowContext(ctx, `SELECT version FROM projects WHERE id = $1`, pid).Scan(&serverVersion); err != nil {
-					if errors.Is(err, sql.ErrNoRows) {
-						writeError(w, http.StatusNotFound, fmt.Errorf("project not found"))
-						return
-					}
-					writeError(w, http.StatusInternalServerError, err)
+				res, err := pullOps(r.Context(), db, pid, since, limit)
+				switch {
+				case errors.Is(err, errProjectNotFound):
+					writeError(w, http.StatusNotFound, err)
 					return
-				}
-				rows, err := db.QueryContext(ctx, `SELECT op_id, version, actor, op_type, entity_type, entity_id, payload, created_at FROM sync_ops WHERE project_id = $1 AND version > $2 ORDER BY version ASC LIMIT $3`, pid, since, limit)
-				if err != nil {
+				case err != nil:
 					writeError(w, http.StatusInternalServerError, err)
 					return
 				}
-				defer func() {
-					if cerr := rows.Close(); cerr != nil {
-						log.Printf("error closing rows: %v", cerr)
-					}
-				}()
-				type op struct {
-					OpID       string          `json:"op_id"`
-					Version    int64           `json:"version"`
-					Actor      string          `json:"actor"`
-					OpType     string          `json:"op_type"`
-					EntityType string          `json:"entity_type"`
-					EntityID   string          `json:"entity_id"`
-					Payload    json.RawMessage `json:"payload"`
-					CreatedAt  time.Time       `json:"created_at"`
-				}
-				var ops []op
-				for rows.Next() {
-					var o op
-					if err := rows.Scan(&o.OpID, &o.Version, &o.Actor, &o.OpType, &o.EntityType, &o.EntityID, &o.Payload, &o.CreatedAt); err != nil {
-						writeError(w, http.StatusInternalServerError, err)
-						return
-					}
-					ops = append(ops, o)
-				}
-				if err := rows.Err(); err != nil {
-					writeError(w, http.StatusInternalServerError, err)
-					return
-				}
-				writeJSON(w, http.StatusOK, map[string]any{
-					"project_id":     pid,
-					"server_version": serverVersion,
-					"ops":            ops,
-				})
+				writeJSON(w, http.StatusOK, res)
 				return
 			}
 		}
@@ -651,66 +644,33 @@ func Start() error {
 			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid json"))
 			return
 		}
-		email := strings.TrimSpace(req.Email)
-		if email == "" {
+		if strings.TrimSpace(req.Email) == "" {
 			writeError(w, http.StatusBadRequest, fmt.Errorf("email required"))
 			return
 		}
-		role := strings.TrimSpace(req.Role)
-		if role == "" {
-			role = "owner"
-		}
-		// Resolve project id
-		pid := req.ProjectID
-		if pid == 0 {
-			slug := strings.TrimSpace(req.ProjectSlug)
-			if slug == "" {
-				writeError(w, http.StatusBadRequest, fmt.Errorf("project_id or project_slug required"))
-				return
-			}
-			if err := db.QueryRowContext(r.Context(), `SELECT id FROM projects WHERE slug = $1`, slug).Scan(&pid); err != nil {
-				if errors.Is(err, sql.ErrNoRows) {
-					writeError(w, http.StatusNotFound, fmt.Errorf("project not found"))
-					return
-				}
-				writeError(w, http.StatusInternalServerError, err)
-				return
-			}
+		if req.ProjectID == 0 && strings.TrimSpace(req.ProjectSlug) == "" {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("project_id or project_slug required"))
+			return
 		}
-		// Ensure user exists or update display_name
-		if _, err := db.ExecContext(r.Context(), `INSERT INTO users(email, display_name) VALUES ($1, NULLIF($2,'') )
-			ON CONFLICT (email) DO UPDATE SET display_name = COALESCE(EXCLUDED.display_name, users.display_name)`, email, req.DisplayName); err != nil {
-			writeError(w, http.StatusInternalServerError, err)
+		m, err := grantMembership(r.Context(), db, req.Email, req.DisplayName, req.Role, req.ProjectID, req.ProjectSlug)
+		if errors.Is(err, errProjectNotFound) {
+			writeError(w, http.StatusNotFound, err)
 			return
 		}
-		// Insert or update membership
-		if _, err := db.ExecContext(r.Context(), `INSERT INTO project_members(user_id, project_id, role)
-			SELECT u.id, $2, $3 FROM users u WHERE u.email = $1
-			ON CONFLICT (user_id, project_id) DO UPDATE SET role = EXCLUDED.role`, email, pid, role); err != nil {
+		if err != nil {
 			writeError(w, http.StatusInternalServerError, err)
 			return
 		}
 		writeJSON(w, http.StatusOK, map[string]any{
-			"project_id": pid,
-			"user":       email,
-			"role":       role,
+			"project_id": m.ProjectID,
+			"user":       m.User,
+			"role":       m.Role,
 			"granted_by": sub,
 			"status":     "granted",
 		})
 	}))
 
-	server := &http.Server{
-		Addr:    cfg.Addr,
-		Handler: mux,
-	}
-	log.Printf("gcwserver listening on %s tls=%v mode=%s", cfg.Addr, cfg.TLSEnable, cfg.AuthMode)
-	if cfg.TLSEnable {
-		if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
-			return fmt.Errorf("TLS enabled but GCW_TLS_CERT_FILE or GCW_TLS_KEY_FILE not set")
-		}
-		return server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
-	}
-	return server.ListenAndServe()
+	return mux
 }
 
 func getVersion() string {
@@ -756,7 +716,7 @@ func applyMigrations(ctx context.Context, db *sql.DB) error {
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
-			log.Printf("rows close: %v", err)
+			applog.WithComponent("backend").Warn("rows close", slog.Any("err", err))
 		}
 	}()
 	for rows.Next() {
@@ -786,7 +746,7 @@ func applyMigrations(ctx context.Context, db *sql.DB) error {
 		if strings.TrimSpace(sqlText) == "" {
 			continue
 		}
-		log.Printf("applying migration %s", fname)
+		applog.WithOperation(applog.WithComponent("backend"), "migrate").Info("applying migration", slog.String("file", fname))
 		if _, err := db.ExecContext(ctx, sqlText); err != nil {
 			return fmt.Errorf("apply %s: %w", fname, err)
 		}
@@ -812,10 +772,17 @@ func parseVersion(name string) (int64, error) {
 type tokenClaims struct {
 	Sub string `json:"sub"`
 	Exp int64  `json:"exp"` // unix seconds
+	// Ses is when the session began (unix seconds). Refreshed tokens carry it over, so a session ends
+	// Config.MaxSession after sign-in however often it is refreshed.
+	Ses int64 `json:"ses,omitempty"`
 }
 
+// signToken signs a token for a new session of subject.
 func signToken(secret, subject string, exp time.Time) (string, error) {
-	claims := tokenClaims{Sub: subject, Exp: exp.Unix()}
+	return signClaims(secret, tokenClaims{Sub: subject, Exp: exp.Unix(), Ses: time.Now().Unix()})
+}
+
+func signClaims(secret string, claims tokenClaims) (string, error) {
 	b, err := json.Marshal(claims)
 	if err != nil {
 		return "", err
@@ -829,35 +796,54 @@ func signToken(secret, subject string, exp time.Time) (string, error) {
 }
 
 func verifyToken(secret, token string) (string, error) {
+	claims, err := parseToken(secret, token)
+	if err != nil {
+		return "", err
+	}
+	return claims.Sub, nil
+}
+
+// parseToken checks signature and expiry of token and returns its claims.
+func parseToken(secret, token string) (tokenClaims, error) {
 	parts := strings.Split(token, ".")
 	if len(parts) != 2 {
-		return "", fmt.Errorf("invalid token format")
+		return tokenClaims{}, fmt.Errorf("invalid token format")
 	}
 	payloadB, err := base64.RawURLEncoding.DecodeString(parts[0])
 	if err != nil {
-		return "", fmt.Errorf("invalid token payload")
+		return tokenClaims{}, fmt.Errorf("invalid token payload")
 	}
 	sigB, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
-		return "", fmt.Errorf("invalid token signature")
+		return tokenClaims{}, fmt.Errorf("invalid token signature")
 	}
 	h := hmac.New(sha256.New, []byte(secret))
 	_, _ = h.Write(payloadB)
 	expected := h.Sum(nil)
 	if !hmac.Equal(expected, sigB) {
-		return "", fmt.Errorf("bad signature")
+		return tokenClaims{}, fmt.Errorf("bad signature")
 	}
 	var claims tokenClaims
 	if err := json.Unmarshal(payloadB, &claims); err != nil {
-		return "", fmt.Errorf("bad claims")
+		return tokenClaims{}, fmt.Errorf("bad claims")
 	}
 	if claims.Exp < time.Now().Unix() {
-		return "", fmt.Errorf("token expired")
+		return tokenClaims{}, errTokenExpired
 	}
 	if claims.Sub == "" {
 		claims.Sub = "dev"
 	}
-	return claims.Sub, nil
+	return claims, nil
+}
+
+// bearerToken returns the token of the request's Authorization header.
+func bearerToken(r *http.Request) (string, bool) {
+	auth := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(strings.ToLower(auth), strings.ToLower(prefix)) {
+		return "", false
+	}
+	return strings.TrimSpace(auth[len(prefix):]), true
 }
 
 func writeJSON(w http.ResponseWriter, status int, v any) {
@@ -867,8 +853,13 @@ func writeJSON(w http.ResponseWriter, status int, v any) {
 	_ = enc.Encode(v)
 }
 
+// writeError writes {"error": ...} and, when set by the middleware, the request ID so users can report issues.
 func writeError(w http.ResponseWriter, status int, err error) {
-	writeJSON(w, status, map[string]any{"error": err.Error()})
+	body := map[string]any{"error": err.Error()}
+	if id := w.Header().Get(RequestIDHeader); id != "" {
+		body["request_id"] = id
+	}
+	writeJSON(w, status, body)
 }
 
 // isInvalidCatalog returns true if the error indicates the target database does not exist (SQLSTATE 3D000).
@@ -933,7 +924,7 @@ func tryCreateMissingDatabase(ctx context.Context, dsn string) error {
 	if _, err := adminDB.ExecContext(ctx, "CREATE DATABASE "+qname); err != nil {
 		return fmt.Errorf("create database %s: %w", dbname, err)
 	}
-	log.Printf("INFO: created database %q", dbname)
+	applog.WithComponent("backend").Info("created database", slog.String("db", dbname))
 	return nil
 }
 
@@ -0,0 +1,312 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/storage"
+)
+
+// doJSON sends body (if not nil) as JSON with the given bearer token and headers, decodes a 200
+// response into out (if not nil) and returns the status code.
+func doJSON(t *testing.T, method, u, token string, header map[string]string, body, out any) int {
+	t.Helper()
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal request: %v", err)
+		}
+		rd = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, u, rd)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, u, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if out != nil && resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("decode %s %s: %v", method, u, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestIntegration_AuthToken(t *testing.T) {
+	db := newPGDB(t)
+	f := seedFixtures(t, db)
+	admin := map[string]string{"X-API-Key": "integration-admin-key"}
+	cases := []struct {
+		name    string
+		mode    string
+		header  map[string]string
+		body    map[string]any
+		want    int
+		subject string
+	}{
+		{"dev_default_subject", "dev", nil, map[string]any{}, http.StatusOK, "dev"},
+		{"dev_email", "dev", nil, map[string]any{"email": "newcomer@example.com"}, http.StatusOK, "newcomer@example.com"},
+		{"static_without_key", "static", nil, map[string]any{"email": f.Owner}, http.StatusUnauthorized, ""},
+		{"static_wrong_key", "static", map[string]string{"X-API-Key": "nope"}, map[string]any{"email": f.Owner}, http.StatusUnauthorized, ""},
+		{"static_without_subject", "static", admin, map[string]any{}, http.StatusBadRequest, ""},
+		{"static_existing_user", "static", admin, map[string]any{"email": f.Member}, http.StatusOK, f.Member},
+		{"static_new_user", "static", admin, map[string]any{"subject": "invited@example.com", "display_name": "Invited"}, http.StatusOK, "invited@example.com"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := testServerConfig(tc.mode)
+			srv := newTestServer(t, db, cfg)
+			var got struct {
+				Token   string `json:"token"`
+				Subject string `json:"subject"`
+			}
+			if code := doJSON(t, http.MethodPost, srv.URL+"/api/auth/token", "", tc.header, tc.body, &got); code != tc.want {
+				t.Fatalf("status %d, want %d", code, tc.want)
+			}
+			if tc.want != http.StatusOK {
+				return
+			}
+			if got.Subject != tc.subject {
+				t.Fatalf("subject %q, want %q", got.Subject, tc.subject)
+			}
+			if sub, err := verifyToken(cfg.AuthSecret, got.Token); err != nil || sub != tc.subject {
+				t.Fatalf("token verifies as %q, %v", sub, err)
+			}
+			// The issued token is accepted by authenticated endpoints
+			if code := doJSON(t, http.MethodGet, srv.URL+"/api/projects", got.Token, nil, nil, nil); code != http.StatusOK {
+				t.Fatalf("list projects with issued token: status %d", code)
+			}
+		})
+	}
+
+	// Static mode only admits known users; dev mode provisions them on first use
+	stranger := "stranger@example.com"
+	static := testServerConfig("static")
+	if code := doJSON(t, http.MethodGet, newTestServer(t, db, static).URL+"/api/projects", tokenFor(t, static, stranger), nil, nil, nil); code != http.StatusForbidden {
+		t.Fatalf("static mode, unknown user: status %d, want 403", code)
+	}
+	dev := testServerConfig("dev")
+	if code := doJSON(t, http.MethodGet, newTestServer(t, db, dev).URL+"/api/projects", tokenFor(t, dev, stranger), nil, nil, nil); code != http.StatusOK {
+		t.Fatalf("dev mode, unknown user: status %d, want 200", code)
+	}
+	var n int
+	if err := db.QueryRow(`SELECT count(*) FROM users WHERE email = $1`, stranger).Scan(&n); err != nil || n != 1 {
+		t.Fatalf("dev mode did not provision %s: %d, %v", stranger, n, err)
+	}
+}
+
+func TestIntegration_MembershipEnforced(t *testing.T) {
+	db := newPGDB(t)
+	f := seedFixtures(t, db)
+	cfg := testServerConfig("static")
+	srv := newTestServer(t, db, cfg)
+	cases := []struct {
+		name    string
+		subject string // empty: no token
+		project int64
+		want    int
+	}{
+		{"owner", f.Owner, f.Project, http.StatusOK},
+		{"member", f.Member, f.Project, http.StatusOK},
+		{"outsider", f.Outsider, f.Project, http.StatusForbidden},
+		{"deleted_project", f.Owner, f.Deleted, http.StatusForbidden},
+		{"unknown_project", f.Owner, f.Project + 1000, http.StatusForbidden},
+		{"no_token", "", f.Project, http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		for _, path := range []string{"sync/pull?since=0", "search?text=page", "comments"} {
+			t.Run(tc.name+"/"+path, func(t *testing.T) {
+				token := ""
+				if tc.subject != "" {
+					token = tokenFor(t, cfg, tc.subject)
+				}
+				u := fmt.Sprintf("%s/api/projects/%d/%s", srv.URL, tc.project, path)
+				if code := doJSON(t, http.MethodGet, u, token, nil, nil, nil); code != tc.want {
+					t.Fatalf("status %d, want %d", code, tc.want)
+				}
+			})
+		}
+	}
+	// Members may push, outsiders may not
+	push := map[string]any{"ops": []pushOpInput{{OpType: "update", EntityType: "page", EntityID: "issue:1/page:1", Payload: json.RawMessage(`{}`)}}}
+	for _, sub := range []string{f.Member, f.Outsider} {
+		want := http.StatusOK
+		if sub == f.Outsider {
+			want = http.StatusForbidden
+		}
+		u := fmt.Sprintf("%s/api/projects/%d/sync/push", srv.URL, f.Project)
+		if code := doJSON(t, http.MethodPost, u, tokenFor(t, cfg, sub), nil, push, nil); code != want {
+			t.Fatalf("push as %s: status %d, want %d", sub, code, want)
+		}
+	}
+}
+
+func TestIntegration_ConcurrentPushVersions(t *testing.T) {
+	db := newPGDB(t)
+	f := seedFixtures(t, db)
+	cfg := testServerConfig("static")
+	srv := newTestServer(t, db, cfg)
+	pushURL := fmt.Sprintf("%s/api/projects/%d/sync/push", srv.URL, f.Project)
+
+	const rounds, batch = 10, 3
+	writers := []string{f.Owner, f.Member}
+	results := make([][]pushResult, len(writers))
+	errs := make(chan error, len(writers)*rounds)
+	var wg sync.WaitGroup
+	for w, sub := range writers {
+		wg.Add(1)
+		token := tokenFor(t, cfg, sub)
+		go func() {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				ops := make([]pushOpInput, batch)
+				for i := range ops {
+					ops[i] = pushOpInput{OpID: fmt.Sprintf("w%d-r%d-o%d", w, r, i), OpType: "update", EntityType: "page",
+						EntityID: fmt.Sprintf("issue:1/page:%d", i+1), Payload: json.RawMessage(`{}`)}
+				}
+				b, _ := json.Marshal(map[string]any{"ops": ops})
+				req, _ := http.NewRequest(http.MethodPost, pushURL, bytes.NewReader(b))
+				req.Header.Set("Authorization", "Bearer "+token)
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					errs <- err
+					return
+				}
+				var res pushResult
+				err = json.NewDecoder(resp.Body).Decode(&res)
+				_ = resp.Body.Close()
+				if resp.StatusCode != http.StatusOK || err != nil {
+					errs <- fmt.Errorf("%s round %d: status %d, %v", sub, r, resp.StatusCode, err)
+					return
+				}
+				results[w] = append(results[w], res)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	// Every batch is accepted whole and lands on a distinct server version
+	seen := map[int64]bool{}
+	for _, list := range results {
+		for _, res := range list {
+			if res.Accepted != batch || res.Duplicates != 0 {
+				t.Fatalf("push result %+v, want %d accepted", res, batch)
+			}
+			if seen[res.ServerVersion] {
+				t.Fatalf("server version %d returned to two pushes", res.ServerVersion)
+			}
+			seen[res.ServerVersion] = true
+		}
+	}
+
+	// The op log holds the seeded ops plus every pushed op, numbered 1..n without duplicates or gaps
+	total := f.Ops + len(writers)*rounds*batch
+	var pulled pullResult
+	u := fmt.Sprintf("%s/api/projects/%d/sync/pull?since=0&limit=%d", srv.URL, f.Project, total+10)
+	if code := doJSON(t, http.MethodGet, u, tokenFor(t, cfg, f.Owner), nil, nil, &pulled); code != http.StatusOK {
+		t.Fatalf("pull: status %d", code)
+	}
+	if len(pulled.Ops) != total || pulled.ServerVersion != int64(total) {
+		t.Fatalf("pulled %d ops at server version %d, want %d", len(pulled.Ops), pulled.ServerVersion, total)
+	}
+	for i, op := range pulled.Ops {
+		if op.Version != int64(i+1) {
+			t.Fatalf("op %d has version %d, want %d", i, op.Version, i+1)
+		}
+	}
+}
+
+func TestIntegration_SearchParity(t *testing.T) {
+	db := newPGDB(t)
+	f := seedFixtures(t, db)
+	cfg := testServerConfig("static")
+	srv := newTestServer(t, db, cfg)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	proj := parityManifest("Fixture Comic")
+
+	// The server indexes the manifest as a reindex job would
+	if _, _, err := replaceDocuments(ctx, db, f.Project, storage.ManifestDocuments(proj)); err != nil {
+		t.Fatalf("index manifest: %v", err)
+	}
+	// The local index of the same manifest
+	root := t.TempDir()
+	ph, err := storage.InitProject(root, proj)
+	if err != nil {
+		t.Fatalf("InitProject: %v", err)
+	}
+	defer func() { _ = ph.Close() }()
+	// Let the background build finish, then rebuild so the index holds exactly this manifest
+	time.Sleep(300 * time.Millisecond)
+	if err := storage.RebuildIndex(ctx, root, proj); err != nil {
+		t.Fatalf("RebuildIndex: %v", err)
+	}
+
+	token := tokenFor(t, cfg, f.Member)
+	cases := []struct {
+		name   string
+		q      storage.SearchQuery
+		params url.Values
+		min    int // results expected at least, so an empty result on both sides does not pass
+	}{
+		{"text", storage.SearchQuery{Text: "lighthouse"}, url.Values{"text": {"lighthouse"}}, 2},
+		{"text_types", storage.SearchQuery{Text: "storm", Types: []string{"balloon", "panel_notes"}},
+			url.Values{"text": {"storm"}, "types": {"balloon,panel_notes"}}, 2},
+		{"pages", storage.SearchQuery{PageFrom: 2, PageTo: 3}, url.Values{"page_from": {"2"}, "page_to": {"3"}}, 3},
+		{"tags", storage.SearchQuery{Tags: []string{"greet"}}, url.Values{"tags": {"greet"}}, 2},
+		{"character", storage.SearchQuery{Character: "bob"}, url.Values{"character": {"bob"}}, 3},
+		{"scene", storage.SearchQuery{Scene: "beach"}, url.Values{"scene": {"beach"}}, 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			local, err := storage.Search(ctx, root, tc.q)
+			if err != nil {
+				t.Fatalf("sqlite search: %v", err)
+			}
+			var server []storage.SearchResult
+			u := fmt.Sprintf("%s/api/projects/%d/search?%s", srv.URL, f.Project, tc.params.Encode())
+			if code := doJSON(t, http.MethodGet, u, token, nil, nil, &server); code != http.StatusOK {
+				t.Fatalf("search: status %d", code)
+			}
+			lk, sk := resultKeys(local), resultKeys(server)
+			if len(lk) < tc.min || fmt.Sprint(lk) != fmt.Sprint(sk) {
+				t.Fatalf("results differ (want at least %d)\nsqlite: %v\npg:     %v", tc.min, lk, sk)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	applog "gocomicwriter/internal/log"
+)
+
+// LimitConfig holds the request rate and body size limits applied by withLimits.
+// A rate of 0 disables that rate limit; a body limit of 0 disables that body limit.
+type LimitConfig struct {
+	IPRate       float64       // requests per second per client IP
+	IPBurst      int           // requests a client IP may send at once
+	SubjectRate  float64       // requests per second per authenticated subject
+	SubjectBurst int           // requests a subject may send at once
+	AuthCost     int           // requests one call to /api/auth/ counts as
+	BucketTTL    time.Duration // idle buckets are dropped after this long
+	TrustProxy   bool          // take the client IP from the last X-Forwarded-For entry
+	MaxBody      int64         // default request body limit in bytes
+	MaxAuthBody  int64         // body limit of /api/auth/
+	MaxPushBody  int64         // body limit of sync push
+}
+
+// DefaultLimitConfig is used for limits whose environment variable is unset or invalid.
+var DefaultLimitConfig = LimitConfig{
+	IPRate:       20,
+	IPBurst:      40,
+	SubjectRate:  10,
+	SubjectBurst: 60,
+	AuthCost:     5,
+	BucketTTL:    10 * time.Minute,
+	MaxBody:      1 << 20,
+	MaxAuthBody:  64 << 10,
+	MaxPushBody:  10 << 20,
+}
+
+// limitExempt are the paths neither rate nor body limits apply to, so probes never get a 429.
+var limitExempt = map[string]bool{"/healthz": true, "/readyz": true}
+
+// loadLimitConfig reads the GCW_RATE_* and GCW_MAX_*_BYTES variables over DefaultLimitConfig.
+func loadLimitConfig() LimitConfig {
+	c := DefaultLimitConfig
+	envFloat("GCW_RATE_IP_RPS", &c.IPRate)
+	envInt("GCW_RATE_IP_BURST", &c.IPBurst)
+	envFloat("GCW_RATE_SUBJECT_RPS", &c.SubjectRate)
+	envInt("GCW_RATE_SUBJECT_BURST", &c.SubjectBurst)
+	envInt("GCW_RATE_AUTH_COST", &c.AuthCost)
+	if v := strings.TrimSpace(os.Getenv("GCW_RATE_BUCKET_TTL")); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			c.BucketTTL = d
+		} else {
+			applog.WithComponent("backend").Warn("invalid GCW_RATE_BUCKET_TTL; using default", slog.String("value", v), slog.Duration("default", c.BucketTTL))
+		}
+	}
+	c.TrustProxy = getenvBool("GCW_TRUST_PROXY", false)
+	envInt64("GCW_MAX_BODY_BYTES", &c.MaxBody)
+	envInt64("GCW_MAX_AUTH_BODY_BYTES", &c.MaxAuthBody)
+	envInt64("GCW_MAX_PUSH_BODY_BYTES", &c.MaxPushBody)
+	return c
+}
+
+func envFloat(name string, dst *float64) {
+	if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f >= 0 {
+			*dst = f
+		} else {
+			applog.WithComponent("backend").Warn("invalid "+name+"; using default", slog.String("value", v), slog.Float64("default", *dst))
+		}
+	}
+}
+
+func envInt(name string, dst *int) {
+	n := int64(*dst)
+	envInt64(name, &n)
+	*dst = int(n)
+}
+
+func envInt64(name string, dst *int64) {
+	if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n >= 0 {
+			*dst = n
+		} else {
+			applog.WithComponent("backend").Warn("invalid "+name+"; using default", slog.String("value", v), slog.Int64("default", *dst))
+		}
+	}
+}
+
+// bodyLimit returns the body size limit of a request path.
+func (c LimitConfig) bodyLimit(path string) int64 {
+	switch {
+	case strings.HasPrefix(path, "/api/auth/"):
+		return c.MaxAuthBody
+	case routeLabel(path) == "/api/projects/:id/sync/push":
+		return c.MaxPushBody
+	}
+	return c.MaxBody
+}
+
+// tokenBucket holds up to burst tokens and refills at rate tokens per second.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter keeps one token bucket per key in memory. Buckets idle for longer than ttl are swept
+// while taking, at most once per ttl, so the store stays bounded without a background goroutine.
+type rateLimiter struct {
+	rate  float64
+	burst float64
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	swept   time.Time
+}
+
+func newRateLimiter(rate float64, burst int, ttl time.Duration, now func() time.Time) *rateLimiter {
+	return &rateLimiter{rate: rate, burst: float64(max(burst, 1)), ttl: ttl, now: now, buckets: map[string]*tokenBucket{}}
+}
+
+// take removes cost tokens from key's bucket. When there are not enough it takes none and returns
+// how long until there will be.
+func (l *rateLimiter) take(key string, cost int) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if l.ttl > 0 && now.Sub(l.swept) >= l.ttl {
+		for k, b := range l.buckets {
+			if now.Sub(b.last) >= l.ttl {
+				delete(l.buckets, k)
+			}
+		}
+		l.swept = now
+	}
+	b := l.buckets[key]
+	if b == nil {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	need := math.Min(float64(cost), l.burst) // a cost above the burst would never pass
+	if b.tokens >= need {
+		b.tokens -= need
+		return true, 0
+	}
+	return false, time.Duration((need - b.tokens) / l.rate * float64(time.Second))
+}
+
+// size returns the number of buckets held (for tests).
+func (l *rateLimiter) size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
+
+// limiter applies a LimitConfig to requests.
+type limiter struct {
+	cfg      LimitConfig
+	secret   string // verifies bearer tokens to find the subject
+	ips      *rateLimiter
+	subjects *rateLimiter
+}
+
+func newLimiter(cfg LimitConfig, secret string, now func() time.Time) *limiter {
+	lim := &limiter{cfg: cfg, secret: secret}
+	if cfg.IPRate > 0 {
+		lim.ips = newRateLimiter(cfg.IPRate, cfg.IPBurst, cfg.BucketTTL, now)
+	}
+	if cfg.SubjectRate > 0 {
+		lim.subjects = newRateLimiter(cfg.SubjectRate, cfg.SubjectBurst, cfg.BucketTTL, now)
+	}
+	return lim
+}
+
+// withLimits rate-limits requests per client IP and per authenticated subject and caps request
+// bodies, answering 429 with Retry-After or 413. The health endpoints are exempt. Handlers need no
+// changes: a body over the limit fails their read like any broken body.
+func withLimits(next http.Handler, cfg LimitConfig, secret string) http.Handler {
+	return newLimiter(cfg, secret, time.Now).wrap(next)
+}
+
+func (lim *limiter) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if limitExempt[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cost := 1
+		if strings.HasPrefix(r.URL.Path, "/api/auth/") {
+			cost = max(lim.cfg.AuthCost, 1)
+		}
+		if lim.ips != nil {
+			if ok, wait := lim.ips.take(clientIP(r, lim.cfg.TrustProxy), cost); !ok {
+				tooManyRequests(w, wait)
+				return
+			}
+		}
+		if lim.subjects != nil {
+			if token, ok := bearerToken(r); ok {
+				if sub, err := verifyToken(lim.secret, token); err == nil {
+					if ok, wait := lim.subjects.take(sub, cost); !ok {
+						tooManyRequests(w, wait)
+						return
+					}
+				}
+			}
+		}
+		if n := lim.cfg.bodyLimit(r.URL.Path); n > 0 && r.Body != nil {
+			if r.ContentLength > n {
+				writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body larger than %d bytes", n))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tooManyRequests answers 429 asking the client to wait at least wait, in whole seconds.
+func tooManyRequests(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(max(int(math.Ceil(wait.Seconds())), 1)))
+	writeError(w, http.StatusTooManyRequests, errors.New("rate limit exceeded"))
+}
+
+// clientIP returns the address the request came from: the last X-Forwarded-For entry when the
+// server sits behind a trusted proxy, otherwise the connection's remote host. The proxy appends the
+// address it saw to the end; earlier entries come from the client and may be forged.
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+			last := xff[len(xff)-1]
+			if i := strings.LastIndexByte(last, ','); i >= 0 {
+				last = last[i+1:]
+			}
+			if ip := strings.TrimSpace(last); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
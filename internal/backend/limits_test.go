@@ -0,0 +1,214 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable time source for the rate limiter.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestRateLimiterTokenBucket(t *testing.T) {
+	clk := &fakeClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := newRateLimiter(1, 2, time.Minute, clk.now)
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.take("a", 1); !ok {
+			t.Fatalf("burst request %d denied", i)
+		}
+	}
+	if ok, wait := l.take("a", 1); ok || wait != time.Second {
+		t.Fatalf("over burst: ok=%v wait=%v, want denied for 1s", ok, wait)
+	}
+	clk.advance(500 * time.Millisecond)
+	if ok, wait := l.take("a", 1); ok || wait != 500*time.Millisecond {
+		t.Fatalf("half refilled: ok=%v wait=%v", ok, wait)
+	}
+	clk.advance(500 * time.Millisecond)
+	if ok, _ := l.take("a", 1); !ok {
+		t.Fatal("refilled token denied")
+	}
+	if ok, _ := l.take("b", 1); !ok {
+		t.Fatal("other key shares the bucket")
+	}
+	// A cost above the burst needs a full bucket instead of never passing
+	clk.advance(2 * time.Second)
+	if ok, _ := l.take("a", 5); !ok {
+		t.Fatal("cost above burst denied on a full bucket")
+	}
+
+	// Idle buckets are swept once the TTL has passed
+	clk.advance(time.Minute)
+	l.take("c", 1)
+	if n := l.size(); n != 1 {
+		t.Fatalf("buckets after sweep = %d, want 1", n)
+	}
+}
+
+// limitedServer wraps a handler that reports how many body bytes it could read, or 400 when the
+// read failed, in a limiter on clk.
+func limitedServer(cfg LimitConfig, clk *fakeClock) http.Handler {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(strconv.Itoa(len(b))))
+	})
+	return newLimiter(cfg, "secret", clk.now).wrap(h)
+}
+
+func limitedRequest(h http.Handler, method, path, ip, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.RemoteAddr = ip + ":40000"
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWithLimitsRatePerIP(t *testing.T) {
+	clk := &fakeClock{t: time.Now()}
+	h := limitedServer(LimitConfig{IPRate: 1, IPBurst: 2, AuthCost: 2, BucketTTL: time.Minute}, clk)
+	for i := 0; i < 2; i++ {
+		if rec := limitedRequest(h, http.MethodGet, "/api/projects", "10.0.0.1", "", ""); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: %d", i, rec.Code)
+		}
+	}
+	rec := limitedRequest(h, http.MethodGet, "/api/projects", "10.0.0.1", "", "")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "1" || !strings.Contains(rec.Body.String(), "rate limit") {
+		t.Fatalf("over limit: %d Retry-After=%q %s", rec.Code, rec.Header().Get("Retry-After"), rec.Body)
+	}
+	// Probes are exempt; other clients have their own bucket
+	for _, p := range []string{"/healthz", "/readyz"} {
+		for i := 0; i < 5; i++ {
+			if rec := limitedRequest(h, http.MethodGet, p, "10.0.0.1", "", ""); rec.Code != http.StatusOK {
+				t.Fatalf("%s limited: %d", p, rec.Code)
+			}
+		}
+	}
+	if rec := limitedRequest(h, http.MethodGet, "/api/projects", "10.0.0.2", "", ""); rec.Code != http.StatusOK {
+		t.Fatalf("other IP limited: %d", rec.Code)
+	}
+	// A token request costs AuthCost
+	if rec := limitedRequest(h, http.MethodPost, "/api/auth/token", "10.0.0.3", "", "{}"); rec.Code != http.StatusOK {
+		t.Fatalf("first token request: %d", rec.Code)
+	}
+	rec = limitedRequest(h, http.MethodPost, "/api/auth/token", "10.0.0.3", "", "{}")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "2" {
+		t.Fatalf("second token request: %d Retry-After=%q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	clk.advance(2 * time.Second)
+	if rec := limitedRequest(h, http.MethodPost, "/api/auth/token", "10.0.0.3", "", "{}"); rec.Code != http.StatusOK {
+		t.Fatalf("token request after waiting: %d", rec.Code)
+	}
+}
+
+func TestWithLimitsRatePerSubject(t *testing.T) {
+	clk := &fakeClock{t: time.Now()}
+	h := limitedServer(LimitConfig{SubjectRate: 1, SubjectBurst: 1, BucketTTL: time.Minute}, clk)
+	alice, _ := signToken("secret", "alice@example.com", time.Now().Add(time.Hour))
+	bob, _ := signToken("secret", "bob@example.com", time.Now().Add(time.Hour))
+	forged, _ := signToken("other", "alice@example.com", time.Now().Add(time.Hour))
+	if rec := limitedRequest(h, http.MethodGet, "/api/projects", "10.0.0.1", alice, ""); rec.Code != http.StatusOK {
+		t.Fatalf("alice: %d", rec.Code)
+	}
+	// The subject's bucket follows it across addresses
+	if rec := limitedRequest(h, http.MethodGet, "/api/projects", "10.0.0.2", alice, ""); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("alice from another IP: %d", rec.Code)
+	}
+	if rec := limitedRequest(h, http.MethodGet, "/api/projects", "10.0.0.1", bob, ""); rec.Code != http.StatusOK {
+		t.Fatalf("bob: %d", rec.Code)
+	}
+	// Tokens that do not verify are left to the handlers' authentication
+	for i := 0; i < 3; i++ {
+		if rec := limitedRequest(h, http.MethodGet, "/api/projects", "10.0.0.1", forged, ""); rec.Code != http.StatusOK {
+			t.Fatalf("forged token limited: %d", rec.Code)
+		}
+	}
+}
+
+func TestClientIPTrustProxy(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.9, 203.0.113.7")
+	if got := clientIP(req, false); got != "192.0.2.1" {
+		t.Errorf("untrusted proxy: client %q", got)
+	}
+	if got := clientIP(req, true); got != "203.0.113.7" {
+		t.Errorf("trusted proxy: client %q", got)
+	}
+	// A second header line is appended after the first
+	req.Header.Add("X-Forwarded-For", "203.0.113.8")
+	if got := clientIP(req, true); got != "203.0.113.8" {
+		t.Errorf("trusted proxy, two headers: client %q", got)
+	}
+}
+
+func TestWithLimitsIgnoresSpoofedForwardedFor(t *testing.T) {
+	clk := &fakeClock{t: time.Now()}
+	h := limitedServer(LimitConfig{IPRate: 1, IPBurst: 1, AuthCost: 1, BucketTTL: time.Minute, TrustProxy: true}, clk)
+	send := func(spoofed string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/token", strings.NewReader("{}"))
+		req.RemoteAddr = "192.0.2.1:40000"
+		req.Header.Set("X-Forwarded-For", spoofed+", 203.0.113.7")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := send("10.0.0.1"); code != http.StatusOK {
+		t.Fatalf("first request: %d", code)
+	}
+	// A new leading entry does not buy a fresh bucket
+	if code := send("10.0.0.2"); code != http.StatusTooManyRequests {
+		t.Fatalf("spoofed leading entry: %d", code)
+	}
+}
+
+func TestWithLimitsBodySize(t *testing.T) {
+	h := limitedServer(LimitConfig{MaxBody: 10, MaxAuthBody: 5, MaxPushBody: 100}, &fakeClock{t: time.Now()})
+	cases := []struct {
+		path string
+		size int
+		want int
+	}{
+		{"/api/projects/1/comments", 10, http.StatusOK},
+		{"/api/projects/1/comments", 11, http.StatusRequestEntityTooLarge},
+		{"/api/auth/token", 6, http.StatusRequestEntityTooLarge},
+		{"/api/projects/1/sync/push", 100, http.StatusOK},
+		{"/api/projects/1/sync/push", 101, http.StatusRequestEntityTooLarge},
+	}
+	for _, c := range cases {
+		if rec := limitedRequest(h, http.MethodPost, c.path, "10.0.0.1", "", strings.Repeat("x", c.size)); rec.Code != c.want {
+			t.Errorf("POST %s with %d bytes: %d, want %d", c.path, c.size, rec.Code, c.want)
+		}
+	}
+	// Without a Content-Length the limit stops the handler's read
+	req := httptest.NewRequest(http.MethodPost, "/api/projects/1/comments", io.MultiReader(strings.NewReader(strings.Repeat("x", 20))))
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("chunked body over limit: %d", rec.Code)
+	}
+}
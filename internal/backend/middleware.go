@@ -0,0 +1,287 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	applog "gocomicwriter/internal/log"
+)
+
+// RequestIDHeader carries the per-request correlation ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+type ctxKey int
+
+const reqInfoKey ctxKey = iota
+
+// reqInfo is attached to the request context so inner handlers (e.g. authWrap) can
+// report details such as the authenticated subject back to the logging middleware.
+type reqInfo struct {
+	id      string
+	subject string
+}
+
+// RequestIDFromContext returns the request ID assigned by the logging middleware, or "".
+func RequestIDFromContext(ctx context.Context) string {
+	if ri, ok := ctx.Value(reqInfoKey).(*reqInfo); ok {
+		return ri.id
+	}
+	return ""
+}
+
+// setRequestSubject records the authenticated subject for the access log line.
+func setRequestSubject(r *http.Request, sub string) {
+	if ri, ok := r.Context().Value(reqInfoKey).(*reqInfo); ok {
+		ri.subject = sub
+	}
+}
+
+// newRequestID returns a random 16-byte hex ID.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// validRequestID accepts client-supplied IDs that are short and header-safe.
+func validRequestID(s string) bool {
+	if s == "" || len(s) > 128 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// statusRecorder captures the response status code for logging and metrics.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if s.status == 0 {
+		s.status = code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += n
+	return n, err
+}
+
+// withRequestLogging assigns/propagates X-Request-ID, logs one line per request and records metrics.
+func withRequestLogging(next http.Handler, m *httpMetrics) http.Handler {
+	l := applog.WithComponent("backend")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		ri := &reqInfo{id: id}
+		w.Header().Set(RequestIDHeader, id)
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), reqInfoKey, ri)))
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		dur := time.Since(start)
+		route := routeLabel(r.URL.Path)
+		if m != nil {
+			m.observe(r.Method, route, rec.status, dur)
+		}
+		lvl := slog.LevelInfo
+		if rec.status >= 500 {
+			lvl = slog.LevelError
+		} else if rec.status >= 400 {
+			lvl = slog.LevelWarn
+		}
+		l.LogAttrs(r.Context(), lvl, "http request",
+			slog.String("request_id", id),
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.status),
+			slog.Int("bytes", rec.bytes),
+			slog.Duration("duration", dur),
+			slog.String("subject", ri.subject),
+		)
+	})
+}
+
+// knownRoutes are the route labels the server answers; any other path is counted as "other".
+var knownRoutes = map[string]bool{
+	"/metrics":                       true,
+	"/healthz":                       true,
+	"/readyz":                        true,
+	"/version":                       true,
+	"/api/auth/token":                true,
+	"/api/auth/refresh":              true,
+	"/api/admin/membership/grant":    true,
+	"/api/projects":                  true,
+	"/api/projects/:id":              true,
+	"/api/projects/:id/index":        true,
+	"/api/projects/:id/search":       true,
+	"/api/projects/:id/reindex":      true,
+	"/api/projects/:id/reindex/:job": true,
+	"/api/projects/:id/comments":     true,
+	"/api/projects/:id/comments/:id": true,
+	"/api/projects/:id/presence":     true,
+	"/api/projects/:id/sync/push":    true,
+	"/api/projects/:id/sync/pull":    true,
+}
+
+// routeLabel collapses numeric path segments (project IDs) and reindex job IDs, and maps paths that
+// are not known routes to "other", so metrics keep a bounded label set whatever clients request.
+func routeLabel(p string) string {
+	segs := strings.Split(p, "/")
+	for i, s := range segs {
+		if s == "" {
+			continue
+		}
+		if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+			segs[i] = ":id"
+		} else if i == 5 && segs[4] == "reindex" {
+			segs[i] = ":job"
+		}
+	}
+	if route := strings.Join(segs, "/"); knownRoutes[route] {
+		return route
+	}
+	return "other"
+}
+
+// latencyBuckets are the histogram upper bounds in seconds.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type metricKey struct {
+	method string
+	route  string
+	status int
+}
+
+type latencyHist struct {
+	counts []uint64 // per bucket, non-cumulative
+	sum    float64
+	count  uint64
+}
+
+// httpMetrics is a tiny in-process request counter and latency histogram exposed in
+// Prometheus text format at /metrics.
+type httpMetrics struct {
+	mu       sync.Mutex
+	requests map[metricKey]uint64
+	latency  map[string]*latencyHist // by route
+	// collectors append their own metrics to the endpoint; they are registered before serving.
+	collectors []interface{ writePrometheus(io.Writer) error }
+}
+
+func newHTTPMetrics() *httpMetrics {
+	return &httpMetrics{requests: map[metricKey]uint64{}, latency: map[string]*latencyHist{}}
+}
+
+func (m *httpMetrics) observe(method, route string, status int, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[metricKey{method: method, route: route, status: status}]++
+	h := m.latency[route]
+	if h == nil {
+		h = &latencyHist{counts: make([]uint64, len(latencyBuckets))}
+		m.latency[route] = h
+	}
+	sec := d.Seconds()
+	for i, ub := range latencyBuckets {
+		if sec <= ub {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += sec
+	h.count++
+}
+
+// writePrometheus renders the metrics in Prometheus text exposition format (sorted for stable output).
+func (m *httpMetrics) writePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var sb strings.Builder
+	sb.WriteString("# HELP gcw_http_requests_total Total HTTP requests by method, route and status.\n")
+	sb.WriteString("# TYPE gcw_http_requests_total counter\n")
+	keys := make([]metricKey, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "gcw_http_requests_total{method=%q,route=%q,status=\"%d\"} %d\n", k.method, k.route, k.status, m.requests[k])
+	}
+	sb.WriteString("# HELP gcw_http_request_duration_seconds HTTP request latency by route.\n")
+	sb.WriteString("# TYPE gcw_http_request_duration_seconds histogram\n")
+	routes := make([]string, 0, len(m.latency))
+	for r := range m.latency {
+		routes = append(routes, r)
+	}
+	sort.Strings(routes)
+	for _, r := range routes {
+		h := m.latency[r]
+		var cum uint64
+		for i, ub := range latencyBuckets {
+			cum += h.counts[i]
+			fmt.Fprintf(&sb, "gcw_http_request_duration_seconds_bucket{route=%q,le=\"%g\"} %d\n", r, ub, cum)
+		}
+		fmt.Fprintf(&sb, "gcw_http_request_duration_seconds_bucket{route=%q,le=\"+Inf\"} %d\n", r, h.count)
+		fmt.Fprintf(&sb, "gcw_http_request_duration_seconds_sum{route=%q} %g\n", r, h.sum)
+		fmt.Fprintf(&sb, "gcw_http_request_duration_seconds_count{route=%q} %d\n", r, h.count)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// ServeHTTP exposes the metrics endpoint.
+func (m *httpMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.writePrometheus(w)
+	for _, c := range m.collectors {
+		_ = c.writePrometheus(w)
+	}
+}
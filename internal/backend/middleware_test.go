@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestMux(m *httpMetrics) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	mux.HandleFunc("/api/projects/", func(w http.ResponseWriter, r *http.Request) {
+		setRequestSubject(r, "alice@example.com")
+		writeJSON(w, http.StatusOK, map[string]any{"id": RequestIDFromContext(r.Context())})
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusUnauthorized, errors.New("invalid token"))
+	})
+	return withRequestLogging(mux, m)
+}
+
+func TestRequestIDHeaderGeneratedAndPropagated(t *testing.T) {
+	h := newTestMux(newHTTPMetrics())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/projects/1", nil))
+	id := rec.Header().Get(RequestIDHeader)
+	if len(id) != 32 {
+		t.Fatalf("expected generated request id, got %q", id)
+	}
+	var body map[string]string
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	if body["id"] != id {
+		t.Fatalf("context id %q != header id %q", body["id"], id)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/1", nil)
+	req.Header.Set(RequestIDHeader, "client-abc-123")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get(RequestIDHeader); got != "client-abc-123" {
+		t.Fatalf("expected propagated id, got %q", got)
+	}
+
+	// Unsafe IDs are replaced.
+	req = httptest.NewRequest(http.MethodGet, "/api/projects/1", nil)
+	req.Header.Set(RequestIDHeader, strings.Repeat("x", 200))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get(RequestIDHeader); len(got) != 32 {
+		t.Fatalf("expected regenerated id for oversize input, got %q", got)
+	}
+}
+
+func TestErrorResponseIncludesRequestID(t *testing.T) {
+	h := newTestMux(newHTTPMetrics())
+	req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+	req.Header.Set(RequestIDHeader, "report-me")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body["error"] != "invalid token" || body["request_id"] != "report-me" {
+		t.Fatalf("unexpected body: %v", body)
+	}
+}
+
+func TestMetricsIncrement(t *testing.T) {
+	m := newHTTPMetrics()
+	h := newTestMux(m)
+	for _, p := range []string{"/api/projects/1", "/api/projects/2", "/fail"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, p, nil))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	out := rec.Body.String()
+	for _, want := range []string{
+		`gcw_http_requests_total{method="GET",route="/api/projects/:id",status="200"} 2`,
+		`gcw_http_requests_total{method="GET",route="other",status="401"} 1`,
+		`gcw_http_request_duration_seconds_count{route="/api/projects/:id"} 2`,
+		`gcw_http_request_duration_seconds_bucket{route="other",le="+Inf"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("metrics missing %q:\n%s", want, out)
+		}
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestRouteLabel(t *testing.T) {
+	cases := map[string]string{
+		"/api/projects":                     "/api/projects",
+		"/api/projects/42/comments/7":       "/api/projects/:id/comments/:id",
+		"/api/projects/7/reindex/0b5c-uuid": "/api/projects/:id/reindex/:job",
+		"/api/projects/7/sync/push":         "/api/projects/:id/sync/push",
+		"/api/projects/my-slug":             "other",
+		"/api/projects/7/index/12345":       "other",
+		"/wp-login.php":                     "other",
+	}
+	for in, want := range cases {
+		if got := routeLabel(in); got != want {
+			t.Errorf("routeLabel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMetricsBoundedForUnknownPaths(t *testing.T) {
+	m := newHTTPMetrics()
+	h := newTestMux(m)
+	for i := 0; i < 500; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, fmt.Sprintf("/scan/%d/x%d", i, i), nil))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.requests) != 1 || len(m.latency) != 1 {
+		t.Fatalf("series: %d counters, %d histograms; want 1 each", len(m.requests), len(m.latency))
+	}
+}
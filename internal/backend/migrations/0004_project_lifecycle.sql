@@ -0,0 +1,27 @@
+-- Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+-- This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+-- in compliance with the License.  You may obtain a copy of the License at
+--   http://www.apache.org/licenses/LICENSE-2.0
+-- Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+-- "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+--  specific language governing permissions and limitations under the License.
+
+
+-- 0004_project_lifecycle.sql
+-- Project creation/deletion via the API:
+--  - slug becomes a regular column so collisions can be resolved with a numeric suffix (existing values are kept)
+--  - deleted_at marks soft-deleted projects; their rows (and sync_ops) stay for recovery
+
+BEGIN;
+
+ALTER TABLE projects ALTER COLUMN slug DROP EXPRESSION IF EXISTS;
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS ix_projects_live ON projects(updated_at) WHERE deleted_at IS NULL;
+
+INSERT INTO schema_migrations(version, name)
+SELECT 4, '0004_project_lifecycle'
+WHERE NOT EXISTS (SELECT 1 FROM schema_migrations WHERE version = 4);
+
+COMMIT;
@@ -0,0 +1,32 @@
+-- Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+-- This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+-- in compliance with the License.  You may obtain a copy of the License at
+--   http://www.apache.org/licenses/LICENSE-2.0
+-- Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+-- "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+--  specific language governing permissions and limitations under the License.
+
+
+-- 0005_comments.sql
+-- Review comments anchored to index document paths (e.g. 'issue:1/page:3/panel:p2')
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS comments (
+    id           BIGSERIAL PRIMARY KEY,
+    project_id   BIGINT      NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    path         TEXT        NOT NULL, -- same scheme as the index documents
+    author       TEXT        NOT NULL, -- user email
+    body         TEXT        NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    resolved_at  TIMESTAMPTZ           -- NULL while open
+);
+
+-- text_pattern_ops lets path prefix filters (LIKE 'prefix%') use the index
+CREATE INDEX IF NOT EXISTS ix_comments_project_path ON comments(project_id, path text_pattern_ops);
+
+INSERT INTO schema_migrations(version, name)
+SELECT 5, '0005_comments'
+WHERE NOT EXISTS (SELECT 1 FROM schema_migrations WHERE version = 5);
+
+COMMIT;
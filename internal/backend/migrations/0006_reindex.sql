@@ -0,0 +1,40 @@
+-- Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+-- This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+-- in compliance with the License.  You may obtain a copy of the License at
+--   http://www.apache.org/licenses/LICENSE-2.0
+-- Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+-- "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+--  specific language governing permissions and limitations under the License.
+
+
+-- 0006_reindex.sql
+-- Reindex jobs: rebuild a project's search documents from its last pushed manifest
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS reindex_log (
+    id               BIGSERIAL PRIMARY KEY,
+    job_id           UUID        NOT NULL DEFAULT gen_random_uuid(),
+    project_id       BIGINT      NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    requested_by     TEXT        NOT NULL,
+    status           TEXT        NOT NULL DEFAULT 'queued', -- queued | running | done | failed
+    manifest_version BIGINT,                                -- sync_ops version of the manifest used
+    rows_deleted     BIGINT      NOT NULL DEFAULT 0,
+    rows_inserted    BIGINT      NOT NULL DEFAULT 0,
+    duration_ms      BIGINT,
+    error            TEXT,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at       TIMESTAMPTZ,
+    finished_at      TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_reindex_log_job_id ON reindex_log(job_id);
+CREATE INDEX IF NOT EXISTS ix_reindex_log_project_created ON reindex_log(project_id, created_at DESC);
+
+-- Finds the latest pushed manifest of a project
+CREATE INDEX IF NOT EXISTS ix_sync_ops_project_entity_version ON sync_ops(project_id, entity_type, version DESC);
+
+INSERT INTO schema_migrations(version, name)
+SELECT 6, '0006_reindex'
+WHERE NOT EXISTS (SELECT 1 FROM schema_migrations WHERE version = 6);
+
+COMMIT;
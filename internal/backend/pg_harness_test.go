@@ -0,0 +1,213 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+
+	applog "gocomicwriter/internal/log"
+)
+
+// The integration harness runs one PostgreSQL 17 container per test binary, started on first use and
+// removed by TestMain. Each test gets its own database in it, with the embedded migrations applied,
+// so tests neither see each other's rows nor depend on the order they run in. Without a reachable
+// Docker daemon the tests that need it are skipped.
+var (
+	pgOnce     sync.Once
+	pgPool     *dockertest.Pool
+	pgResource *dockertest.Resource
+	pgBaseDSN  string // maintenance database of the container
+	pgSkip     string // why the container is unavailable
+	pgDBSeq    atomic.Int64
+)
+
+// pgStartTimeout bounds pulling the image and waiting for the server to accept connections.
+const pgStartTimeout = 3 * time.Minute
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if pgResource != nil {
+		_ = pgPool.Purge(pgResource)
+	}
+	os.Exit(code)
+}
+
+// startPG starts the shared container. It sets pgSkip instead of failing when Docker is unavailable.
+func startPG() {
+	pool, err := dockertest.NewPool("")
+	if err == nil {
+		err = pool.Client.Ping()
+	}
+	if err != nil {
+		pgSkip = fmt.Sprintf("docker not available: %v", err)
+		return
+	}
+	res, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "postgres",
+		Tag:        "17-alpine",
+		Env:        []string{"POSTGRES_USER=postgres", "POSTGRES_PASSWORD=postgres", "POSTGRES_DB=postgres"},
+	}, func(hc *docker.HostConfig) {
+		hc.AutoRemove = true
+		hc.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		pgSkip = fmt.Sprintf("cannot start postgres container: %v", err)
+		return
+	}
+	// A crashed test binary leaves the container behind; it removes itself after this long
+	_ = res.Expire(uint(pgStartTimeout.Seconds()) + 600)
+	pgPool, pgResource = pool, res
+	pgBaseDSN = fmt.Sprintf("postgres://postgres:postgres@%s/postgres?sslmode=disable", res.GetHostPort("5432/tcp"))
+	pool.MaxWait = pgStartTimeout
+	if err := pool.Retry(func() error {
+		db, err := sql.Open("pgx", pgBaseDSN)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = db.Close() }()
+		return db.Ping()
+	}); err != nil {
+		pgSkip = fmt.Sprintf("postgres container not ready: %v", err)
+	}
+}
+
+// newPGDB returns a fresh, migrated database in the harness container, dropped when the test ends.
+func newPGDB(t *testing.T) *sql.DB {
+	t.Helper()
+	pgOnce.Do(startPG)
+	if pgSkip != "" {
+		t.Skip(pgSkip)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	admin, err := sql.Open("pgx", pgBaseDSN)
+	if err != nil {
+		t.Fatalf("open postgres: %v", err)
+	}
+	defer func() { _ = admin.Close() }()
+	name := fmt.Sprintf("gcw_test_%d", pgDBSeq.Add(1))
+	if _, err := admin.ExecContext(ctx, "CREATE DATABASE "+name); err != nil {
+		t.Fatalf("create database: %v", err)
+	}
+	u, _ := url.Parse(pgBaseDSN)
+	u.Path = "/" + name
+	db, err := sql.Open("pgx", u.String())
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+		dctx, dcancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer dcancel()
+		if a, err := sql.Open("pgx", pgBaseDSN); err == nil {
+			_, _ = a.ExecContext(dctx, "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)")
+			_ = a.Close()
+		}
+	})
+	if err := applyMigrations(ctx, db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	return db
+}
+
+// pgFixtures are the rows seedFixtures inserts. Owner owns Project and Deleted (soft-deleted), Member
+// is an editor of Project and Outsider a user without projects. Project holds Ops sync ops at versions
+// 1..Ops.
+type pgFixtures struct {
+	Owner, Member, Outsider string
+	Project, Deleted        int64
+	Ops                     int
+}
+
+// seedFixtures inserts the users, projects, memberships and sync ops the integration tests start from.
+func seedFixtures(t *testing.T, db *sql.DB) pgFixtures {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f := pgFixtures{Owner: "owner@example.com", Member: "member@example.com", Outsider: "outsider@example.com", Ops: 3}
+	users := map[string]int64{}
+	for _, email := range []string{f.Owner, f.Member, f.Outsider} {
+		var id int64
+		if err := db.QueryRowContext(ctx, `INSERT INTO users(email, display_name) VALUES ($1, $1) RETURNING id`, email).Scan(&id); err != nil {
+			t.Fatalf("seed user %s: %v", email, err)
+		}
+		users[email] = id
+	}
+	project := func(name, slug string) int64 {
+		var id int64
+		if err := db.QueryRowContext(ctx, `INSERT INTO projects(name, slug) VALUES ($1, $2) RETURNING id`, name, slug).Scan(&id); err != nil {
+			t.Fatalf("seed project %s: %v", name, err)
+		}
+		return id
+	}
+	f.Project, f.Deleted = project("Fixture Comic", "fixture-comic"), project("Deleted Comic", "deleted-comic")
+	members := []struct {
+		email string
+		pid   int64
+		role  string
+	}{{f.Owner, f.Project, "owner"}, {f.Member, f.Project, "editor"}, {f.Owner, f.Deleted, "owner"}}
+	for _, m := range members {
+		if _, err := db.ExecContext(ctx, `INSERT INTO project_members(user_id, project_id, role) VALUES ($1, $2, $3)`, users[m.email], m.pid, m.role); err != nil {
+			t.Fatalf("seed membership: %v", err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE projects SET deleted_at = now() WHERE id = $1`, f.Deleted); err != nil {
+		t.Fatalf("soft-delete project: %v", err)
+	}
+	for v := 1; v <= f.Ops; v++ {
+		if _, err := db.ExecContext(ctx, `INSERT INTO sync_ops(project_id, version, actor, op_type, entity_type, entity_id, payload)
+			VALUES ($1, $2, $3, 'create', 'page', $4, '{}')`, f.Project, v, f.Owner, fmt.Sprintf("issue:1/page:%d", v)); err != nil {
+			t.Fatalf("seed op %d: %v", v, err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE projects SET version = $1 WHERE id = $2`, f.Ops, f.Project); err != nil {
+		t.Fatalf("seed project version: %v", err)
+	}
+	return f
+}
+
+// testServerConfig is a server configuration for the harness: dev or static auth, a fixed secret and
+// admin key.
+func testServerConfig(authMode string) Config {
+	return Config{AuthMode: authMode, AuthSecret: "integration-secret", AdminAPIKey: "integration-admin-key", MaxSession: defaultMaxSession}
+}
+
+// newTestServer serves the full API for cfg on db, as Start does, until the test ends.
+func newTestServer(t *testing.T, db *sql.DB, cfg Config) *httptest.Server {
+	t.Helper()
+	metrics := newHTTPMetrics()
+	ready := newReadinessChecker(db.PingContext, nil, false, applog.WithComponent("backend"))
+	srv := httptest.NewServer(withRequestLogging(newMux(cfg, db, ready, metrics), metrics))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// tokenFor signs a bearer token for subject with the secret of cfg, valid for an hour.
+func tokenFor(t *testing.T, cfg Config, subject string) string {
+	t.Helper()
+	tok, err := signToken(cfg.AuthSecret, subject, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return tok
+}
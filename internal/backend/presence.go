@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits for presence heartbeats. A client that stops sending heartbeats drops out after its TTL.
+const (
+	defaultPresenceTTL = 90 * time.Second
+	minPresenceTTL     = 10 * time.Second
+	maxPresenceTTL     = 10 * time.Minute
+	maxPresenceClient  = 128 // bytes of client_id and of client
+)
+
+var errPresenceClientID = fmt.Errorf("client_id required (at most %d bytes)", maxPresenceClient)
+
+// presenceMember is the JSON shape of one client that has a project open.
+type presenceMember struct {
+	Subject   string    `json:"subject"`
+	ClientID  string    `json:"client_id"`
+	Client    string    `json:"client,omitempty"`
+	LastSeen  time.Time `json:"last_seen"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// presenceRegistry keeps the heartbeats of open projects in memory. Presence is advisory, so it is not
+// persisted and starts empty after a restart; clients re-announce themselves with the next heartbeat.
+type presenceRegistry struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	projects map[int64]map[string]presenceMember // project -> subject+"\x00"+client_id -> member
+}
+
+func newPresenceRegistry() *presenceRegistry {
+	return &presenceRegistry{now: time.Now, projects: map[int64]map[string]presenceMember{}}
+}
+
+func presenceKey(subject, clientID string) string { return subject + "\x00" + clientID }
+
+// heartbeat records that subject has the project open in the client identified by clientID until ttl
+// from now. A zero ttl uses the default; others are clamped to the allowed range.
+func (p *presenceRegistry) heartbeat(pid int64, subject, clientID, client string, ttl time.Duration) (presenceMember, error) {
+	clientID = strings.TrimSpace(clientID)
+	if clientID == "" || len(clientID) > maxPresenceClient {
+		return presenceMember{}, errPresenceClientID
+	}
+	client = strings.TrimSpace(client)
+	if len(client) > maxPresenceClient {
+		// Cut at a rune boundary so a multi-byte character at the limit is dropped whole
+		cut := maxPresenceClient
+		for cut > 0 && !utf8.RuneStart(client[cut]) {
+			cut--
+		}
+		client = client[:cut]
+	}
+	if ttl <= 0 {
+		ttl = defaultPresenceTTL
+	}
+	ttl = min(max(ttl, minPresenceTTL), maxPresenceTTL)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	p.pruneLocked(pid, now)
+	m := presenceMember{Subject: subject, ClientID: clientID, Client: client, LastSeen: now.UTC(), ExpiresAt: now.Add(ttl).UTC()}
+	members := p.projects[pid]
+	if members == nil {
+		members = map[string]presenceMember{}
+		p.projects[pid] = members
+	}
+	members[presenceKey(subject, clientID)] = m
+	return m, nil
+}
+
+// leave removes a client of subject from the project, e.g. when it closes the project.
+func (p *presenceRegistry) leave(pid int64, subject, clientID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if members := p.projects[pid]; members != nil {
+		delete(members, presenceKey(subject, strings.TrimSpace(clientID)))
+		if len(members) == 0 {
+			delete(p.projects, pid)
+		}
+	}
+}
+
+// active returns the unexpired members of the project, ordered by subject and client id.
+func (p *presenceRegistry) active(pid int64) []presenceMember {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pruneLocked(pid, p.now())
+	list := []presenceMember{}
+	for _, m := range p.projects[pid] {
+		list = append(list, m)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Subject != list[j].Subject {
+			return list[i].Subject < list[j].Subject
+		}
+		return list[i].ClientID < list[j].ClientID
+	})
+	return list
+}
+
+// pruneLocked drops the project's expired members. The caller holds p.mu.
+func (p *presenceRegistry) pruneLocked(pid int64, now time.Time) {
+	members := p.projects[pid]
+	for k, m := range members {
+		if !now.Before(m.ExpiresAt) {
+			delete(members, k)
+		}
+	}
+	if members != nil && len(members) == 0 {
+		delete(p.projects, pid)
+	}
+}
+
+// servePresence handles /api/projects/{id}/presence: GET lists the active members, POST records a
+// heartbeat of the caller and DELETE removes one of the caller's clients. Membership has already been
+// checked.
+func servePresence(w http.ResponseWriter, r *http.Request, reg *presenceRegistry, pid int64, sub string, rest []string) {
+	if len(rest) > 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, reg.active(pid))
+	case http.MethodPost:
+		// JSON body: { "client_id": "3f2a…", "client": "gocomicwriter 0.9 (darwin)", "ttl_seconds": 90 }
+		var req struct {
+			ClientID   string `json:"client_id"`
+			Client     string `json:"client"`
+			TTLSeconds int    `json:"ttl_seconds"`
+		}
+		if !readJSONBody(w, r, &req) {
+			return
+		}
+		m, err := reg.heartbeat(pid, sub, req.ClientID, req.Client, time.Duration(req.TTLSeconds)*time.Second)
+		switch {
+		case errors.Is(err, errPresenceClientID):
+			writeError(w, http.StatusBadRequest, err)
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err)
+		default:
+			writeJSON(w, http.StatusOK, m)
+		}
+	case http.MethodDelete:
+		id := r.URL.Query().Get("client_id")
+		if strings.TrimSpace(id) == "" {
+			writeError(w, http.StatusBadRequest, errPresenceClientID)
+			return
+		}
+		reg.leave(pid, sub, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestPresenceRegistry(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	reg := newPresenceRegistry()
+	reg.now = func() time.Time { return now }
+
+	if _, err := reg.heartbeat(1, "ed@example.com", " ", "", 0); !errors.Is(err, errPresenceClientID) {
+		t.Fatalf("empty client id: %v", err)
+	}
+	m, err := reg.heartbeat(1, "ed@example.com", "laptop", "gocomicwriter", 0)
+	if err != nil || !m.ExpiresAt.Equal(now.Add(defaultPresenceTTL)) {
+		t.Fatalf("heartbeat = %+v (%v)", m, err)
+	}
+	if m, _ := reg.heartbeat(1, "ann@example.com", "desk", "", time.Second); !m.ExpiresAt.Equal(now.Add(minPresenceTTL)) {
+		t.Fatalf("ttl should be clamped to the minimum: %+v", m)
+	}
+	if m, _ := reg.heartbeat(1, "ann@example.com", "tablet", "", time.Hour); !m.ExpiresAt.Equal(now.Add(maxPresenceTTL)) {
+		t.Fatalf("ttl should be clamped to the maximum: %+v", m)
+	}
+	_, _ = reg.heartbeat(2, "bo@example.com", "x", "", 0)
+
+	list := reg.active(1)
+	if len(list) != 3 || list[0].Subject != "ann@example.com" || list[0].ClientID != "desk" || list[2].Subject != "ed@example.com" {
+		t.Fatalf("active = %+v", list)
+	}
+
+	now = now.Add(30 * time.Second) // ann's desk expires
+	reg.leave(1, "ann@example.com", "tablet")
+	if list := reg.active(1); len(list) != 1 || list[0].ClientID != "laptop" {
+		t.Fatalf("after expiry and leave = %+v", list)
+	}
+	reg.leave(1, "other@example.com", "laptop") // someone else's client is not removed
+	if len(reg.active(1)) != 1 {
+		t.Fatal("leave removed another subject's client")
+	}
+
+	now = now.Add(time.Hour)
+	if list := reg.active(1); len(list) != 0 {
+		t.Fatalf("all heartbeats expired: %+v", list)
+	}
+	if _, ok := reg.projects[1]; ok {
+		t.Fatal("empty project should be dropped")
+	}
+	if len(reg.active(2)) != 0 {
+		t.Fatal("project 2 should have expired too")
+	}
+}
+
+func TestPresenceClientTruncatedAtRuneBoundary(t *testing.T) {
+	reg := newPresenceRegistry()
+	// The two bytes of "é" straddle the limit
+	client := strings.Repeat("a", maxPresenceClient-1) + "é" + "tail"
+	m, err := reg.heartbeat(1, "ed@example.com", "laptop", client, 0)
+	if err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	if !utf8.ValidString(m.Client) || m.Client != strings.Repeat("a", maxPresenceClient-1) {
+		t.Fatalf("client = %q, want the name cut before the split rune", m.Client)
+	}
+	if got := reg.active(1)[0].Client; got != m.Client {
+		t.Fatalf("stored client = %q", got)
+	}
+}
+
+func TestServePresence(t *testing.T) {
+	reg := newPresenceRegistry()
+	call := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		servePresence(rec, req, reg, 4, "ed@example.com", nil)
+		return rec
+	}
+	if rec := call(http.MethodPost, "/api/projects/4/presence", `{"client_id":"a","client":"test","ttl_seconds":60}`); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"subject":"ed@example.com"`) {
+		t.Fatalf("heartbeat: %d %s", rec.Code, rec.Body)
+	}
+	if rec := call(http.MethodGet, "/api/projects/4/presence", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"client_id":"a"`) {
+		t.Fatalf("list: %d %s", rec.Code, rec.Body)
+	}
+	if rec := call(http.MethodDelete, "/api/projects/4/presence?client_id=a", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("leave: %d", rec.Code)
+	}
+	if rec := call(http.MethodGet, "/api/projects/4/presence", ""); strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Fatalf("list after leave: %s", rec.Body)
+	}
+
+	cases := []struct {
+		method, target, body string
+		want                 int
+	}{
+		{http.MethodPost, "/api/projects/4/presence", `{"client":"x"}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/projects/4/presence", `{"client_id":`, http.StatusBadRequest},
+		{http.MethodDelete, "/api/projects/4/presence", "", http.StatusBadRequest},
+		{http.MethodPut, "/api/projects/4/presence", "", http.StatusMethodNotAllowed},
+	}
+	for _, c := range cases {
+		if rec := call(c.method, c.target, c.body); rec.Code != c.want {
+			t.Errorf("%s %s %s: status %d, want %d", c.method, c.target, c.body, rec.Code, c.want)
+		}
+	}
+	rec := httptest.NewRecorder()
+	servePresence(rec, httptest.NewRequest(http.MethodGet, "/api/projects/4/presence/x", nil), reg, 4, "ed@example.com", []string{"x"})
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("sub path: status %d", rec.Code)
+	}
+}
@@ -0,0 +1,303 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	errProjectNotFound = errors.New("project not found")
+	errNotProjectOwner = errors.New("only project owners can delete a project")
+	errInvalidSlug     = errors.New("invalid slug: use lowercase letters, digits and single dashes")
+	errInvalidCursor   = errors.New("invalid cursor")
+)
+
+// maxSlugAttempts bounds retries when concurrent creates race for the same slug.
+const maxSlugAttempts = 5
+
+// Page sizes of the paginated project listing.
+const (
+	defaultProjectPageSize = 100
+	maxProjectPageSize     = 500
+)
+
+// projectRow is the JSON shape of a project in listings and create responses.
+type projectRow struct {
+	ID        int64     `json:"id"`
+	StableID  string    `json:"stable_id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   int64     `json:"version"`
+}
+
+// slugify lower-cases name and collapses runs of other characters into single dashes.
+// It returns "project" when nothing usable remains.
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	s := strings.TrimRight(b.String(), "-")
+	if s == "" {
+		return "project"
+	}
+	return s
+}
+
+// nextFreeSlug returns base, or base-N with the smallest N >= 2 not present in taken.
+func nextFreeSlug(base string, taken map[string]bool) string {
+	if !taken[base] {
+		return base
+	}
+	for n := 2; ; n++ {
+		if s := base + "-" + strconv.Itoa(n); !taken[s] {
+			return s
+		}
+	}
+}
+
+// listProjects returns the live (not deleted) projects the user is a member of, most recently updated first.
+// Projects updated at the same instant are ordered by descending id, the same order listProjectsPage uses.
+func listProjects(ctx context.Context, db *sql.DB, email string) ([]projectRow, error) {
+	return queryProjects(ctx, db, email, nil, 0)
+}
+
+// projectPage is the JSON shape of a paginated project listing. NextCursor is empty on the last page.
+type projectPage struct {
+	Projects   []projectRow `json:"projects"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+// listProjectsPage returns at most limit projects in listProjects order, starting after the project the
+// cursor was issued for. An empty cursor starts at the first project. Projects updated while a client pages
+// move to the front of the order, so a client sees each project at most once per pass.
+func listProjectsPage(ctx context.Context, db *sql.DB, email, cursor string, limit int) (projectPage, error) {
+	var after *projectKey
+	if cursor != "" {
+		k, err := decodeProjectCursor(cursor)
+		if err != nil {
+			return projectPage{}, err
+		}
+		after = &k
+	}
+	list, err := queryProjects(ctx, db, email, after, limit+1)
+	if err != nil {
+		return projectPage{}, err
+	}
+	list, more := cutPage(list, limit)
+	page := projectPage{Projects: list}
+	if page.Projects == nil {
+		page.Projects = []projectRow{}
+	}
+	if more {
+		last := list[len(list)-1]
+		page.NextCursor = encodeProjectCursor(projectKey{UpdatedAt: last.UpdatedAt, ID: last.ID})
+	}
+	return page, nil
+}
+
+// queryProjects lists the user's live projects ordered by (updated_at, id) descending, starting after the
+// given key when set. limit <= 0 means no limit.
+func queryProjects(ctx context.Context, db *sql.DB, email string, after *projectKey, limit int) ([]projectRow, error) {
+	q := `SELECT p.id, p.stable_id, p.name, COALESCE(p.slug, ''), p.updated_at, p.version
+		FROM projects p
+		JOIN project_members pm ON pm.project_id = p.id
+		JOIN users u ON u.id = pm.user_id
+		WHERE u.email = $1 AND p.deleted_at IS NULL`
+	args := []any{email}
+	if after != nil {
+		q += ` AND (p.updated_at, p.id) < ($2, $3)`
+		args = append(args, after.UpdatedAt, after.ID)
+	}
+	q += ` ORDER BY p.updated_at DESC, p.id DESC`
+	if limit > 0 {
+		q += fmt.Sprintf(` LIMIT %d`, limit)
+	}
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var list []projectRow
+	for rows.Next() {
+		var p projectRow
+		if err := rows.Scan(&p.ID, &p.StableID, &p.Name, &p.Slug, &p.UpdatedAt, &p.Version); err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+// projectKey is the position of a project in the listing order.
+type projectKey struct {
+	UpdatedAt time.Time
+	ID        int64
+}
+
+// encodeProjectCursor returns an opaque cursor for the position after k.
+func encodeProjectCursor(k projectKey) string {
+	raw := strconv.FormatInt(k.UpdatedAt.UnixNano(), 10) + ":" + strconv.FormatInt(k.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeProjectCursor parses a cursor made by encodeProjectCursor. Anything else yields errInvalidCursor.
+func decodeProjectCursor(cursor string) (projectKey, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return projectKey{}, errInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return projectKey{}, errInvalidCursor
+	}
+	nanos, err1 := strconv.ParseInt(ts, 10, 64)
+	pid, err2 := strconv.ParseInt(id, 10, 64)
+	if err1 != nil || err2 != nil || pid <= 0 {
+		return projectKey{}, errInvalidCursor
+	}
+	return projectKey{UpdatedAt: time.Unix(0, nanos).UTC(), ID: pid}, nil
+}
+
+// cutPage trims rows fetched with one extra row beyond limit back to limit and reports whether the
+// extra row was there, i.e. whether another page follows.
+func cutPage[T any](rows []T, limit int) ([]T, bool) {
+	if len(rows) > limit {
+		return rows[:limit], true
+	}
+	return rows, false
+}
+
+// createProject inserts a project and makes the creator its owner. An empty slug is derived from the name;
+// a slug already in use (including by deleted projects) gets a numeric suffix.
+func createProject(ctx context.Context, db *sql.DB, ownerEmail, name, slug string) (projectRow, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return projectRow{}, errors.New("name required")
+	}
+	base := strings.TrimSpace(slug)
+	if base == "" {
+		base = slugify(name)
+	} else if slugify(base) != base {
+		return projectRow{}, errInvalidSlug
+	}
+	var lastErr error
+	for attempt := 0; attempt < maxSlugAttempts; attempt++ {
+		p, err := insertProject(ctx, db, ownerEmail, name, base)
+		if err == nil {
+			return p, nil
+		}
+		var pe *pgconn.PgError
+		if !errors.As(err, &pe) || pe.Code != "23505" { // unique_violation: lost a race for the slug, retry
+			return projectRow{}, err
+		}
+		lastErr = err
+	}
+	return projectRow{}, fmt.Errorf("allocate slug: %w", lastErr)
+}
+
+func insertProject(ctx context.Context, db *sql.DB, ownerEmail, name, base string) (projectRow, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return projectRow{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	rows, err := tx.QueryContext(ctx, `SELECT slug FROM projects WHERE slug = $1 OR slug LIKE $2`, base, base+"-%")
+	if err != nil {
+		return projectRow{}, err
+	}
+	taken := map[string]bool{}
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			_ = rows.Close()
+			return projectRow{}, err
+		}
+		taken[s] = true
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return projectRow{}, err
+	}
+	_ = rows.Close()
+	p := projectRow{Name: name, Slug: nextFreeSlug(base, taken)}
+	if err := tx.QueryRowContext(ctx, `INSERT INTO projects(name, slug) VALUES ($1, $2)
+		RETURNING id, stable_id, updated_at, version`, p.Name, p.Slug).Scan(&p.ID, &p.StableID, &p.UpdatedAt, &p.Version); err != nil {
+		return projectRow{}, err
+	}
+	res, err := tx.ExecContext(ctx, `INSERT INTO project_members(user_id, project_id, role)
+		SELECT u.id, $2, 'owner' FROM users u WHERE u.email = $1`, ownerEmail, p.ID)
+	if err != nil {
+		return projectRow{}, err
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		return projectRow{}, fmt.Errorf("unknown user %q", ownerEmail)
+	}
+	if err := tx.Commit(); err != nil {
+		return projectRow{}, err
+	}
+	return p, nil
+}
+
+// softDeleteProject marks a live project deleted. Only owners may delete; non-members get errProjectNotFound
+// so project ids cannot be probed. Sync ops and snapshots are kept.
+func softDeleteProject(ctx context.Context, db *sql.DB, email string, pid int64) error {
+	role, err := memberRole(ctx, db, email, pid)
+	if err != nil {
+		return err
+	}
+	if role != "owner" {
+		return errNotProjectOwner
+	}
+	res, err := db.ExecContext(ctx, `UPDATE projects SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL`, pid)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errProjectNotFound
+	}
+	return nil
+}
+
+// memberRole returns the user's role on a live project, or errProjectNotFound when the user is no member
+// or the project is deleted.
+func memberRole(ctx context.Context, db *sql.DB, email string, pid int64) (string, error) {
+	var role string
+	err := db.QueryRowContext(ctx, `SELECT pm.role
+		FROM project_members pm
+		JOIN users u ON u.id = pm.user_id
+		JOIN projects p ON p.id = pm.project_id
+		WHERE u.email = $1 AND pm.project_id = $2 AND p.deleted_at IS NULL`, email, pid).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", errProjectNotFound
+	}
+	return role, err
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"My Comic":           "my-comic",
+		"  Hello, World!  ":  "hello-world",
+		"Issue #12 -- Redux": "issue-12-redux",
+		"Überschall":         "berschall",
+		"***":                "project",
+		"a_b":                "a-b",
+	}
+	for in, want := range cases {
+		if got := slugify(in); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNextFreeSlug(t *testing.T) {
+	if got := nextFreeSlug("comic", map[string]bool{}); got != "comic" {
+		t.Fatalf("free slug changed: %q", got)
+	}
+	taken := map[string]bool{"comic": true, "comic-2": true, "comic-4": true}
+	if got := nextFreeSlug("comic", taken); got != "comic-3" {
+		t.Fatalf("expected comic-3, got %q", got)
+	}
+}
+
+func TestProjectLifecycle_Integration(t *testing.T) {
+	db := openPGForTest(t)
+	defer func() { _ = db.Close() }()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	run := time.Now().UnixNano()
+	owner := fmt.Sprintf("owner-%d@example.com", run)
+	editor := fmt.Sprintf("editor-%d@example.com", run)
+	outsider := fmt.Sprintf("outsider-%d@example.com", run)
+	for _, u := range []string{owner, editor, outsider} {
+		if _, err := db.ExecContext(ctx, `INSERT INTO users(email) VALUES ($1)`, u); err != nil {
+			t.Fatalf("insert user: %v", err)
+		}
+	}
+
+	name := fmt.Sprintf("Lifecycle %d", run)
+	p1, err := createProject(ctx, db, owner, name, "")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if p1.Slug != slugify(name) {
+		t.Fatalf("expected slug from name, got %q", p1.Slug)
+	}
+	p2, err := createProject(ctx, db, owner, name, "")
+	if err != nil {
+		t.Fatalf("create duplicate: %v", err)
+	}
+	if p2.Slug != p1.Slug+"-2" {
+		t.Fatalf("expected numeric suffix, got %q", p2.Slug)
+	}
+	if _, err := createProject(ctx, db, owner, "Bad", "Not A Slug"); !errors.Is(err, errInvalidSlug) {
+		t.Fatalf("expected invalid slug error, got %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO project_members(user_id, project_id, role)
+		SELECT id, $2, 'editor' FROM users WHERE email = $1`, editor, p1.ID); err != nil {
+		t.Fatalf("add editor: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO sync_ops(project_id, version, actor, op_type, entity_type, entity_id) VALUES ($1, 1, $2, 'upsert', 'page', 'pg-1')`, p1.ID, owner); err != nil {
+		t.Fatalf("insert sync op: %v", err)
+	}
+
+	// Membership enforcement on delete
+	if err := softDeleteProject(ctx, db, outsider, p1.ID); !errors.Is(err, errProjectNotFound) {
+		t.Fatalf("non-member delete: expected not found, got %v", err)
+	}
+	if err := softDeleteProject(ctx, db, editor, p1.ID); !errors.Is(err, errNotProjectOwner) {
+		t.Fatalf("editor delete: expected forbidden, got %v", err)
+	}
+	if err := softDeleteProject(ctx, db, owner, p1.ID); err != nil {
+		t.Fatalf("owner delete: %v", err)
+	}
+	if err := softDeleteProject(ctx, db, owner, p1.ID); !errors.Is(err, errProjectNotFound) {
+		t.Fatalf("second delete: expected not found, got %v", err)
+	}
+
+	list, err := listProjects(ctx, db, owner)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != p2.ID {
+		t.Fatalf("expected only the live project, got %+v", list)
+	}
+	if list, _ := listProjects(ctx, db, editor); len(list) != 0 {
+		t.Fatalf("deleted project still listed for editor: %+v", list)
+	}
+	var ops int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sync_ops WHERE project_id = $1`, p1.ID).Scan(&ops); err != nil || ops != 1 {
+		t.Fatalf("sync ops of deleted project should remain: n=%d err=%v", ops, err)
+	}
+	// The deleted project's slug stays reserved.
+	p3, err := createProject(ctx, db, owner, name, "")
+	if err != nil || p3.Slug != p1.Slug+"-3" {
+		t.Fatalf("expected reserved slugs to be skipped, got %q (%v)", p3.Slug, err)
+	}
+}
+
+func TestProjectCursor(t *testing.T) {
+	k := projectKey{UpdatedAt: time.Date(2025, 3, 4, 5, 6, 7, 123456000, time.UTC), ID: 42}
+	got, err := decodeProjectCursor(encodeProjectCursor(k))
+	if err != nil || !got.UpdatedAt.Equal(k.UpdatedAt) || got.ID != k.ID {
+		t.Fatalf("round trip: %+v, %v", got, err)
+	}
+	for _, bad := range []string{"!!", "bm9jb2xvbg", "MTIzOmFiYw", "MTIzOjA"} { // not base64, "nocolon", "123:abc", "123:0"
+		if _, err := decodeProjectCursor(bad); !errors.Is(err, errInvalidCursor) {
+			t.Fatalf("%q: expected errInvalidCursor, got %v", bad, err)
+		}
+	}
+}
+
+func TestCutPage(t *testing.T) {
+	for _, c := range []struct {
+		n, limit, want int
+		more           bool
+	}{
+		{0, 3, 0, false},
+		{2, 3, 2, false},
+		{3, 3, 3, false},
+		{4, 3, 3, true},
+	} {
+		rows := make([]int, c.n)
+		got, more := cutPage(rows, c.limit)
+		if len(got) != c.want || more != c.more {
+			t.Fatalf("%d rows, limit %d: got %d rows, more=%v", c.n, c.limit, len(got), more)
+		}
+	}
+}
+
+func TestListProjectsPage_Integration(t *testing.T) {
+	db := openPGForTest(t)
+	defer func() { _ = db.Close() }()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	owner := fmt.Sprintf("pager-%d@example.com", time.Now().UnixNano())
+	if _, err := db.ExecContext(ctx, `INSERT INTO users(email) VALUES ($1)`, owner); err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	var ids []int64
+	for i := 0; i < 5; i++ {
+		p, err := createProject(ctx, db, owner, fmt.Sprintf("Pager %d", i), "")
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		ids = append(ids, p.ID)
+	}
+	// Two projects share a timestamp, so the id tie-break decides their order
+	if _, err := db.ExecContext(ctx, `UPDATE projects SET updated_at = '2025-01-01T00:00:00Z' WHERE id = ANY($1)`, ids[1:3]); err != nil {
+		t.Fatalf("tie timestamps: %v", err)
+	}
+	full, err := listProjects(ctx, db, owner)
+	if err != nil || len(full) != 5 {
+		t.Fatalf("full list: %d, %v", len(full), err)
+	}
+	for _, limit := range []int{1, 2, 4, 5, 6} {
+		var got []int64
+		cursor, pages := "", 0
+		for {
+			page, err := listProjectsPage(ctx, db, owner, cursor, limit)
+			if err != nil {
+				t.Fatalf("limit %d: %v", limit, err)
+			}
+			pages++
+			if len(page.Projects) > limit || (page.NextCursor != "" && len(page.Projects) != limit) {
+				t.Fatalf("limit %d: page of %d with cursor %q", limit, len(page.Projects), page.NextCursor)
+			}
+			for _, p := range page.Projects {
+				got = append(got, p.ID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+		// An exactly full last page carries no cursor, so no empty page is ever fetched
+		if want := (5 + limit - 1) / limit; pages != want {
+			t.Fatalf("limit %d: %d pages, want %d", limit, pages, want)
+		}
+		for i, p := range full {
+			if got[i] != p.ID {
+				t.Fatalf("limit %d: order %v differs from full list at %d", limit, got, i)
+			}
+		}
+	}
+	if _, err := listProjectsPage(ctx, db, owner, "garbage!", 2); !errors.Is(err, errInvalidCursor) {
+		t.Fatalf("expected errInvalidCursor, got %v", err)
+	}
+}
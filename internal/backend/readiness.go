@@ -0,0 +1,272 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Readiness probing. /readyz serves the last result of a background checker instead of probing the
+// dependencies on every request, so load-balancer health checks do not add load to them.
+const (
+	defaultReadyProbeInterval = 10 * time.Second
+	readyProbeTimeout         = 2 * time.Second
+)
+
+// Overall readiness states, as reported by /readyz.
+const (
+	readyStatus    = "ready"
+	degradedStatus = "degraded"  // the database is unreachable
+	notReadyStatus = "not_ready" // required object storage is unreachable
+)
+
+// probeFunc checks one dependency; nil means healthy.
+type probeFunc func(ctx context.Context) error
+
+// probeState is the last result of one dependency's probe.
+type probeState struct {
+	OK          bool          `json:"ok"`
+	CheckedAt   time.Time     `json:"checked_at"`
+	LastSuccess time.Time     `json:"last_success,omitzero"`
+	Duration    time.Duration `json:"-"`
+	Failures    int           `json:"consecutive_failures"`
+	Error       string        `json:"error,omitempty"`
+	total       [2]uint64     // probes by result: [0] failed, [1] succeeded
+}
+
+// readinessChecker probes the database and, when configured, object storage on an interval and keeps
+// the results. Status changes are logged once per transition.
+type readinessChecker struct {
+	probes          map[string]probeFunc // "db", and "objects" when object storage is configured
+	objectsRequired bool
+	now             func() time.Time
+	log             *slog.Logger
+
+	mu     sync.Mutex
+	state  map[string]*probeState
+	status string // "" until the first probe
+}
+
+func newReadinessChecker(db, objects probeFunc, objectsRequired bool, l *slog.Logger) *readinessChecker {
+	c := &readinessChecker{
+		probes:          map[string]probeFunc{"db": db},
+		objectsRequired: objectsRequired,
+		now:             time.Now,
+		log:             l,
+		state:           map[string]*probeState{},
+	}
+	if objects != nil {
+		c.probes["objects"] = objects
+	}
+	return c
+}
+
+// httpProbe expects a 2xx answer to a GET of url, e.g. MinIO's /minio/health/ready.
+func httpProbe(client *http.Client, url string) probeFunc {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// probe runs every probe once, concurrently, and records the results.
+func (c *readinessChecker) probe(ctx context.Context) {
+	type result struct {
+		name string
+		err  error
+		at   time.Time
+		dur  time.Duration
+	}
+	results := make(chan result, len(c.probes))
+	for name, fn := range c.probes {
+		go func() {
+			pctx, cancel := context.WithTimeout(ctx, readyProbeTimeout)
+			defer cancel()
+			start := c.now()
+			err := fn(pctx)
+			results <- result{name: name, err: err, at: start, dur: c.now().Sub(start)}
+		}()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for range c.probes {
+		r := <-results
+		st := c.state[r.name]
+		if st == nil {
+			st = &probeState{}
+			c.state[r.name] = st
+		}
+		st.CheckedAt, st.Duration = r.at.UTC(), r.dur
+		st.OK = r.err == nil
+		if st.OK {
+			st.LastSuccess, st.Failures, st.Error = st.CheckedAt, 0, ""
+			st.total[1]++
+		} else {
+			st.Failures++
+			st.Error = r.err.Error()
+			st.total[0]++
+		}
+	}
+	prev := c.status
+	c.status = c.statusLocked()
+	if c.status == prev {
+		return
+	}
+	attrs := []any{slog.String("from", prev), slog.String("to", c.status)}
+	for _, name := range []string{"db", "objects"} {
+		if st := c.state[name]; st != nil && !st.OK {
+			attrs = append(attrs, slog.String(name+"_err", st.Error))
+		}
+	}
+	if c.status == readyStatus {
+		c.log.Info("readiness changed", attrs...)
+	} else {
+		c.log.Warn("readiness changed", attrs...)
+	}
+}
+
+// statusLocked derives the overall status from the probe states.
+func (c *readinessChecker) statusLocked() string {
+	if st := c.state["objects"]; st != nil && !st.OK && c.objectsRequired {
+		return notReadyStatus
+	}
+	if st := c.state["db"]; st == nil || !st.OK {
+		return degradedStatus
+	}
+	return readyStatus
+}
+
+// run probes every interval until ctx ends. The caller runs the first probe before serving.
+func (c *readinessChecker) run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			c.probe(ctx)
+		}
+	}
+}
+
+// ServeHTTP answers /readyz from the last probe: 503 while the database, or required object storage,
+// is unreachable.
+func (c *readinessChecker) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	c.mu.Lock()
+	status := c.status
+	if status == "" {
+		status = c.statusLocked()
+	}
+	body := map[string]any{
+		"status":  status,
+		"db":      c.okLocked("db"),
+		"objects": c.okLocked("objects"),
+		"version": getVersion(),
+		"time":    c.now().UTC().Format(time.RFC3339),
+	}
+	probes := map[string]probeState{}
+	for name, st := range c.state {
+		probes[name] = *st
+	}
+	body["probes"] = probes
+	c.mu.Unlock()
+
+	code := http.StatusOK
+	if status != readyStatus {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, body)
+}
+
+// okLocked reports the last result of a probe; unconfigured probes count as healthy.
+func (c *readinessChecker) okLocked(name string) bool {
+	if _, ok := c.probes[name]; !ok {
+		return true
+	}
+	st := c.state[name]
+	return st != nil && st.OK
+}
+
+// writePrometheus renders the probe results for /metrics.
+func (c *readinessChecker) writePrometheus(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var sb strings.Builder
+	ready := 0
+	if c.status == readyStatus {
+		ready = 1
+	}
+	sb.WriteString("# HELP gcw_ready Whether the server reports ready on /readyz.\n")
+	sb.WriteString("# TYPE gcw_ready gauge\n")
+	fmt.Fprintf(&sb, "gcw_ready %d\n", ready)
+	names := make([]string, 0, len(c.state))
+	for _, name := range []string{"db", "objects"} {
+		if c.state[name] != nil {
+			names = append(names, name)
+		}
+	}
+	sb.WriteString("# HELP gcw_ready_probe_up Whether the last readiness probe of a dependency succeeded.\n")
+	sb.WriteString("# TYPE gcw_ready_probe_up gauge\n")
+	for _, name := range names {
+		up := 0
+		if c.state[name].OK {
+			up = 1
+		}
+		fmt.Fprintf(&sb, "gcw_ready_probe_up{dependency=%q} %d\n", name, up)
+	}
+	sb.WriteString("# HELP gcw_ready_probe_duration_seconds Duration of the last readiness probe of a dependency.\n")
+	sb.WriteString("# TYPE gcw_ready_probe_duration_seconds gauge\n")
+	for _, name := range names {
+		fmt.Fprintf(&sb, "gcw_ready_probe_duration_seconds{dependency=%q} %g\n", name, c.state[name].Duration.Seconds())
+	}
+	sb.WriteString("# HELP gcw_ready_probe_consecutive_failures Failed readiness probes of a dependency since its last success.\n")
+	sb.WriteString("# TYPE gcw_ready_probe_consecutive_failures gauge\n")
+	for _, name := range names {
+		fmt.Fprintf(&sb, "gcw_ready_probe_consecutive_failures{dependency=%q} %d\n", name, c.state[name].Failures)
+	}
+	sb.WriteString("# HELP gcw_ready_probe_last_success_timestamp_seconds Unix time of a dependency's last successful readiness probe.\n")
+	sb.WriteString("# TYPE gcw_ready_probe_last_success_timestamp_seconds gauge\n")
+	for _, name := range names {
+		var ts int64
+		if st := c.state[name]; !st.LastSuccess.IsZero() {
+			ts = st.LastSuccess.Unix()
+		}
+		fmt.Fprintf(&sb, "gcw_ready_probe_last_success_timestamp_seconds{dependency=%q} %d\n", name, ts)
+	}
+	sb.WriteString("# HELP gcw_ready_probes_total Readiness probes by dependency and result.\n")
+	sb.WriteString("# TYPE gcw_ready_probes_total counter\n")
+	for _, name := range names {
+		st := c.state[name]
+		fmt.Fprintf(&sb, "gcw_ready_probes_total{dependency=%q,result=\"failure\"} %d\n", name, st.total[0])
+		fmt.Fprintf(&sb, "gcw_ready_probes_total{dependency=%q,result=\"success\"} %d\n", name, st.total[1])
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
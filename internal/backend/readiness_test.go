@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// switchProbe fails while err is set and counts its calls.
+type switchProbe struct {
+	mu    sync.Mutex
+	err   error
+	calls int
+}
+
+func (p *switchProbe) probe(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	return p.err
+}
+
+func (p *switchProbe) set(err error) {
+	p.mu.Lock()
+	p.err = err
+	p.mu.Unlock()
+}
+
+func readyzBody(t *testing.T, c *readinessChecker) (int, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v\n%s", err, rec.Body.String())
+	}
+	return rec.Code, body
+}
+
+func TestReadinessChecker_CachesAndLogsTransitions(t *testing.T) {
+	var logs bytes.Buffer
+	db, obj := &switchProbe{}, &switchProbe{}
+	c := newReadinessChecker(db.probe, obj.probe, false, slog.New(slog.NewTextHandler(&logs, nil)))
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	c.probe(context.Background())
+	for range 3 {
+		if code, body := readyzBody(t, c); code != http.StatusOK || body["status"] != readyStatus {
+			t.Fatalf("ready: %d %v", code, body)
+		}
+	}
+	if db.calls != 1 {
+		t.Fatalf("/readyz probed the database: %d calls", db.calls)
+	}
+
+	// Object storage is optional: its failure is reported but the server stays ready
+	obj.set(errors.New("connection refused"))
+	c.probe(context.Background())
+	if code, body := readyzBody(t, c); code != http.StatusOK || body["objects"] != false || body["db"] != true {
+		t.Fatalf("optional objects down: %d %v", code, body)
+	}
+
+	db.set(errors.New("db down"))
+	for range 3 {
+		now = now.Add(10 * time.Second)
+		c.probe(context.Background())
+	}
+	code, body := readyzBody(t, c)
+	if code != http.StatusServiceUnavailable || body["status"] != degradedStatus {
+		t.Fatalf("db down: %d %v", code, body)
+	}
+	probes := body["probes"].(map[string]any)
+	dbState := probes["db"].(map[string]any)
+	if dbState["consecutive_failures"] != float64(3) || dbState["error"] != "db down" || dbState["last_success"] != "2025-06-01T12:00:00Z" {
+		t.Fatalf("db probe state = %v", dbState)
+	}
+
+	db.set(nil)
+	c.probe(context.Background())
+	if code, _ := readyzBody(t, c); code != http.StatusOK {
+		t.Fatalf("recovered: %d", code)
+	}
+	// unknown→ready, ready→degraded, degraded→ready: one line each, not one per probe
+	if n := strings.Count(logs.String(), "readiness changed"); n != 3 {
+		t.Fatalf("logged %d transitions:\n%s", n, logs.String())
+	}
+	if !strings.Contains(logs.String(), "level=WARN msg=\"readiness changed\" from=ready to=degraded db_err=\"db down\" objects_err=\"connection refused\"") {
+		t.Errorf("degraded transition not logged with causes:\n%s", logs.String())
+	}
+}
+
+func TestReadinessChecker_RequiredObjectsAndMetrics(t *testing.T) {
+	db, obj := &switchProbe{}, &switchProbe{err: errors.New("503")}
+	c := newReadinessChecker(db.probe, obj.probe, true, slog.New(slog.DiscardHandler))
+	c.probe(context.Background())
+	if code, body := readyzBody(t, c); code != http.StatusServiceUnavailable || body["status"] != notReadyStatus {
+		t.Fatalf("required objects down: %d %v", code, body)
+	}
+	obj.set(nil)
+	c.probe(context.Background())
+
+	m := newHTTPMetrics()
+	m.collectors = append(m.collectors, c)
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	out := rec.Body.String()
+	for _, want := range []string{
+		"gcw_ready 1\n",
+		`gcw_ready_probe_up{dependency="db"} 1`,
+		`gcw_ready_probe_up{dependency="objects"} 1`,
+		`gcw_ready_probe_duration_seconds{dependency="objects"} `,
+		`gcw_ready_probe_consecutive_failures{dependency="objects"} 0`,
+		`gcw_ready_probes_total{dependency="objects",result="failure"} 1`,
+		`gcw_ready_probes_total{dependency="objects",result="success"} 1`,
+		`gcw_ready_probes_total{dependency="db",result="success"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestReadinessChecker_RunAndHTTPProbe(t *testing.T) {
+	var healthy sync.Mutex
+	up := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		healthy.Lock()
+		defer healthy.Unlock()
+		if !up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	objects := httpProbe(srv.Client(), srv.URL)
+	if err := objects(context.Background()); err != nil {
+		t.Fatalf("healthy object storage: %v", err)
+	}
+	healthy.Lock()
+	up = false
+	healthy.Unlock()
+	if err := objects(context.Background()); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("unhealthy object storage: %v", err)
+	}
+
+	db := &switchProbe{}
+	c := newReadinessChecker(db.probe, nil, false, slog.New(slog.DiscardHandler))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { c.run(ctx, 5*time.Millisecond); close(done) }()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		db.mu.Lock()
+		n := db.calls
+		db.mu.Unlock()
+		if n >= 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("run did not probe on its interval")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+	if _, body := readyzBody(t, c); body["objects"] != true {
+		t.Errorf("unconfigured object storage should count as healthy: %v", body)
+	}
+}
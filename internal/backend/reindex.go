@@ -0,0 +1,281 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	applog "gocomicwriter/internal/log"
+	"gocomicwriter/internal/storage"
+)
+
+// manifestEntityType marks sync ops whose payload is the full project manifest (comic.json). A reindex
+// job reads the one with the highest version.
+const manifestEntityType = "manifest"
+
+// reindexTimeout bounds one reindex job.
+const reindexTimeout = 5 * time.Minute
+
+// Reindex job states.
+const (
+	reindexQueued  = "queued"
+	reindexRunning = "running"
+	reindexDone    = "done"
+	reindexFailed  = "failed"
+)
+
+var (
+	errNoManifest        = errors.New("no manifest pushed for this project")
+	errReindexNotAllowed = errors.New("only project owners or admins can reindex a project")
+	errReindexNotFound   = errors.New("reindex job not found")
+)
+
+var jobIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// reindexJob is the JSON shape of a reindex_log row.
+type reindexJob struct {
+	JobID           string     `json:"job_id"`
+	ProjectID       int64      `json:"project_id"`
+	RequestedBy     string     `json:"requested_by"`
+	Status          string     `json:"status"`
+	ManifestVersion *int64     `json:"manifest_version,omitempty"`
+	RowsDeleted     int64      `json:"rows_deleted"`
+	RowsInserted    int64      `json:"rows_inserted"`
+	DurationMS      *int64     `json:"duration_ms,omitempty"`
+	Error           string     `json:"error,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
+
+// createReindexJob records a queued job for a live project.
+func createReindexJob(ctx context.Context, db *sql.DB, pid int64, requestedBy string) (reindexJob, error) {
+	j := reindexJob{ProjectID: pid, RequestedBy: requestedBy, Status: reindexQueued}
+	err := db.QueryRowContext(ctx, `INSERT INTO reindex_log(project_id, requested_by, status)
+		SELECT id, $2, $3 FROM projects WHERE id = $1 AND deleted_at IS NULL
+		RETURNING job_id::text, created_at`, pid, requestedBy, reindexQueued).Scan(&j.JobID, &j.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return reindexJob{}, errProjectNotFound
+	}
+	if err != nil {
+		return reindexJob{}, err
+	}
+	return j, nil
+}
+
+// getReindexJob returns a job of the project.
+func getReindexJob(ctx context.Context, db *sql.DB, pid int64, jobID string) (reindexJob, error) {
+	if !jobIDPattern.MatchString(jobID) {
+		return reindexJob{}, errReindexNotFound
+	}
+	var (
+		j                   reindexJob
+		version, duration   sql.NullInt64
+		errText             sql.NullString
+		started, finishedAt sql.NullTime
+	)
+	err := db.QueryRowContext(ctx, `SELECT job_id::text, project_id, requested_by, status, manifest_version,
+		rows_deleted, rows_inserted, duration_ms, error, created_at, started_at, finished_at
+		FROM reindex_log WHERE project_id = $1 AND job_id = $2::uuid`, pid, jobID).Scan(
+		&j.JobID, &j.ProjectID, &j.RequestedBy, &j.Status, &version,
+		&j.RowsDeleted, &j.RowsInserted, &duration, &errText, &j.CreatedAt, &started, &finishedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return reindexJob{}, errReindexNotFound
+	}
+	if err != nil {
+		return reindexJob{}, err
+	}
+	if version.Valid {
+		j.ManifestVersion = &version.Int64
+	}
+	if duration.Valid {
+		j.DurationMS = &duration.Int64
+	}
+	j.Error = errText.String
+	if started.Valid {
+		j.StartedAt = &started.Time
+	}
+	if finishedAt.Valid {
+		j.FinishedAt = &finishedAt.Time
+	}
+	return j, nil
+}
+
+// runReindexJob rebuilds the project's documents from its latest manifest and records the outcome in
+// reindex_log. It returns the job's error, which is also stored with the job.
+func runReindexJob(ctx context.Context, db *sql.DB, pid int64, jobID string) error {
+	lg := applog.WithOperation(applog.WithComponent("backend"), "reindex")
+	if _, err := db.ExecContext(ctx, `UPDATE reindex_log SET status = $2, started_at = now() WHERE job_id = $1::uuid`, jobID, reindexRunning); err != nil {
+		return err
+	}
+	start := time.Now()
+	var deleted, inserted int64
+	proj, version, err := loadManifest(ctx, db, pid)
+	if err == nil {
+		deleted, inserted, err = replaceDocuments(ctx, db, pid, storage.ManifestDocuments(proj))
+	}
+	status, errText := reindexDone, sql.NullString{}
+	if err != nil {
+		status, errText = reindexFailed, sql.NullString{String: err.Error(), Valid: true}
+	}
+	manifestVersion := sql.NullInt64{Int64: version, Valid: version > 0}
+	duration := time.Since(start).Milliseconds()
+	// The outcome is recorded even when the job's context ran out
+	uctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), dbTimeout)
+	defer cancel()
+	if _, uerr := db.ExecContext(uctx, `UPDATE reindex_log SET status = $2, manifest_version = $3, rows_deleted = $4,
+		rows_inserted = $5, duration_ms = $6, error = $7, finished_at = now() WHERE job_id = $1::uuid`,
+		jobID, status, manifestVersion, deleted, inserted, duration, errText); uerr != nil {
+		lg.Error("record reindex result failed", slog.String("job_id", jobID), slog.Any("err", uerr))
+	}
+	if err != nil {
+		lg.Warn("reindex failed", slog.Int64("project_id", pid), slog.String("job_id", jobID), slog.Any("err", err))
+		return err
+	}
+	lg.Info("reindex done", slog.Int64("project_id", pid), slog.String("job_id", jobID),
+		slog.Int64("rows_deleted", deleted), slog.Int64("rows_inserted", inserted), slog.Int64("duration_ms", duration))
+	return nil
+}
+
+// loadManifest decodes the project's most recently pushed manifest and returns it with its op version.
+func loadManifest(ctx context.Context, db *sql.DB, pid int64) (domain.Project, int64, error) {
+	var (
+		version int64
+		payload []byte
+	)
+	err := db.QueryRowContext(ctx, `SELECT version, payload FROM sync_ops
+		WHERE project_id = $1 AND entity_type = $2 AND op_type <> 'delete'
+		ORDER BY version DESC LIMIT 1`, pid, manifestEntityType).Scan(&version, &payload)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Project{}, 0, errNoManifest
+	}
+	if err != nil {
+		return domain.Project{}, 0, err
+	}
+	var proj domain.Project
+	if err := json.Unmarshal(payload, &proj); err != nil {
+		return domain.Project{}, version, fmt.Errorf("decode manifest version %d: %w", version, err)
+	}
+	return proj, version, nil
+}
+
+// replaceDocuments swaps the project's documents for docs in one transaction, so searches see either
+// the old or the new set. Cross references of the old documents go with them.
+func replaceDocuments(ctx context.Context, db *sql.DB, pid int64, docs []storage.IndexDocument) (deleted, inserted int64, err error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	// Serialize jobs of the same project
+	var x int
+	if err := tx.QueryRowContext(ctx, `SELECT 1 FROM projects WHERE id = $1 FOR UPDATE`, pid).Scan(&x); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, 0, errProjectNotFound
+		}
+		return 0, 0, err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM documents WHERE project_id = $1`, pid)
+	if err != nil {
+		return 0, 0, fmt.Errorf("clear documents: %w", err)
+	}
+	deleted, _ = res.RowsAffected()
+	ins, err := tx.PrepareContext(ctx, `INSERT INTO documents(project_id, doc_type, external_ref, raw_text, page_num, meta)
+		VALUES ($1, $2, $3, $4, $5, $6)`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("prepare insert: %w", err)
+	}
+	defer func() { _ = ins.Close() }()
+	for _, d := range docs {
+		page := sql.NullInt64{Int64: int64(d.PageID), Valid: d.PageID != 0}
+		meta := "{}"
+		if d.Character != "" {
+			b, err := json.Marshal(map[string]string{"character": d.Character})
+			if err != nil {
+				return 0, 0, err
+			}
+			meta = string(b)
+		}
+		if _, err := ins.ExecContext(ctx, pid, d.Type, d.Path, d.Text, page, meta); err != nil {
+			return 0, 0, fmt.Errorf("insert document: %w", err)
+		}
+		inserted++
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+	return deleted, inserted, nil
+}
+
+// serveReindex handles /api/projects/{id}/reindex (POST) and /reindex/{job} (GET). rest holds the path
+// segments after "reindex". Owners of the project and admins (admin API key) may use it; admin is true
+// when the request carries the admin key.
+func serveReindex(w http.ResponseWriter, r *http.Request, db *sql.DB, pid int64, sub string, admin bool, rest []string) {
+	if len(rest) > 1 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if (len(rest) == 0 && r.Method != http.MethodPost) || (len(rest) == 1 && r.Method != http.MethodGet) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !admin {
+		role, err := memberRole(r.Context(), db, sub, pid)
+		switch {
+		case errors.Is(err, errProjectNotFound):
+			writeError(w, http.StatusNotFound, err)
+			return
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		case role != "owner":
+			writeError(w, http.StatusForbidden, errReindexNotAllowed)
+			return
+		}
+	}
+	if len(rest) == 1 {
+		j, err := getReindexJob(r.Context(), db, pid, rest[0])
+		switch {
+		case errors.Is(err, errReindexNotFound):
+			writeError(w, http.StatusNotFound, err)
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err)
+		default:
+			writeJSON(w, http.StatusOK, j)
+		}
+		return
+	}
+	j, err := createReindexJob(r.Context(), db, pid, sub)
+	switch {
+	case errors.Is(err, errProjectNotFound):
+		writeError(w, http.StatusNotFound, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	applog.WithComponent("backend").Info("reindex queued", slog.Int64("project_id", pid), slog.String("job_id", j.JobID), slog.String("by", sub))
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), reindexTimeout)
+		defer cancel()
+		_ = runReindexJob(ctx, db, pid, j.JobID)
+	}()
+	writeJSON(w, http.StatusAccepted, j)
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func TestServeReindex_RejectsWrongMethodsAndPaths(t *testing.T) {
+	cases := []struct {
+		method string
+		rest   []string
+		want   int
+	}{
+		{http.MethodGet, nil, http.StatusMethodNotAllowed},
+		{http.MethodPost, []string{"0b5e0bb4-2a1c-4d55-9d0e-2a7d6a3b8f10"}, http.StatusMethodNotAllowed},
+		{http.MethodGet, []string{"a", "b"}, http.StatusNotFound},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		serveReindex(rec, httptest.NewRequest(c.method, "/api/projects/1/reindex", nil), nil, 1, "x@example.com", true, c.rest)
+		if rec.Code != c.want {
+			t.Errorf("%s %v: status %d, want %d", c.method, c.rest, rec.Code, c.want)
+		}
+	}
+}
+
+// parityManifest is the manifest both sides of the parity test index.
+func parityManifest(name string) domain.Project {
+	return domain.Project{
+		Name:     name,
+		Metadata: domain.Metadata{Series: "Tidewater", Creators: "A Drost"},
+		Bible: domain.Bible{
+			Characters: []domain.BibleCharacter{{Name: "Bob", Aliases: []string{"Bobby"}, Notes: "Lighthouse keeper"}},
+			Locations:  []domain.BibleLocation{{Name: "Beach", Notes: "Storm season"}},
+			Tags:       []domain.BibleTag{{Name: "greet"}},
+		},
+		Issues: []domain.Issue{{Pages: []domain.Page{
+			{Number: 1, Panels: []domain.Panel{{
+				ID: "P1", Notes: "Wide shot of the beach at dawn",
+				Balloons: []domain.Balloon{{ID: "B1", Character: "Bob", TextRuns: []domain.TextRun{{Content: "Hello there @greet"}}}},
+				Captions: []domain.Caption{{ID: "C1", Text: "Meanwhile at the lighthouse"}},
+			}}},
+			{Number: 2, Panels: []domain.Panel{{
+				ID: "P2", Notes: "BOB: waves at the storm",
+				SFX: []domain.SFXItem{{ID: "S1", Text: "KRAKOOM"}},
+			}}},
+			{Number: 3, Panels: []domain.Panel{{
+				ID:       "P3",
+				Balloons: []domain.Balloon{{ID: "B2", TextRuns: []domain.TextRun{{Content: "Hello storm @greet"}}}},
+			}}},
+		}}},
+	}
+}
+
+// resultKeys identifies results independently of the document IDs, which differ between the stores.
+func resultKeys(list []storage.SearchResult) []string {
+	out := make([]string, 0, len(list))
+	for _, r := range list {
+		out = append(out, fmt.Sprintf("%s|%s|%d", r.Type, r.Path, r.PageID))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestReindexParity_SQLite_vs_Postgres(t *testing.T) {
+	db := openPGForTest(t)
+	defer func() { _ = db.Close() }()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	run := time.Now().UnixNano()
+	owner := fmt.Sprintf("reindex-owner-%d@example.com", run)
+	editor := fmt.Sprintf("reindex-editor-%d@example.com", run)
+	for _, u := range []string{owner, editor} {
+		if _, err := db.ExecContext(ctx, `INSERT INTO users(email) VALUES ($1)`, u); err != nil {
+			t.Fatalf("insert user: %v", err)
+		}
+	}
+	p, err := createProject(ctx, db, owner, fmt.Sprintf("Reindex %d", run), "")
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO project_members(user_id, project_id, role)
+		SELECT id, $2, 'editor' FROM users WHERE email = $1`, editor, p.ID); err != nil {
+		t.Fatalf("add editor: %v", err)
+	}
+	proj := parityManifest(p.Name)
+
+	// Without a pushed manifest the job fails and says so
+	j, err := createReindexJob(ctx, db, p.ID, owner)
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	if err := runReindexJob(ctx, db, p.ID, j.JobID); !errors.Is(err, errNoManifest) {
+		t.Fatalf("reindex without manifest: %v", err)
+	}
+	if got, _ := getReindexJob(ctx, db, p.ID, j.JobID); got.Status != reindexFailed || got.Error == "" || got.FinishedAt == nil {
+		t.Fatalf("failed job = %+v", got)
+	}
+
+	// An outdated manifest followed by the current one: the job uses the latest
+	old := proj
+	old.Issues = nil
+	for _, m := range []domain.Project{old, proj} {
+		payload, _ := json.Marshal(m)
+		if _, err := pushOps(ctx, db, p.ID, owner, []pushOpInput{{OpType: "upsert", EntityType: manifestEntityType, EntityID: "comic.json", Payload: payload}}); err != nil {
+			t.Fatalf("push manifest: %v", err)
+		}
+	}
+	// Documents of an earlier index are replaced, not added to
+	if _, err := db.ExecContext(ctx, `INSERT INTO documents(project_id, doc_type, external_ref, raw_text) VALUES ($1, 'balloon', 'stale', 'Hello stale')`, p.ID); err != nil {
+		t.Fatalf("insert stale document: %v", err)
+	}
+	j, err = createReindexJob(ctx, db, p.ID, owner)
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	if err := runReindexJob(ctx, db, p.ID, j.JobID); err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+	done, err := getReindexJob(ctx, db, p.ID, j.JobID)
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	wantRows := int64(len(storage.ManifestDocuments(proj)))
+	if done.Status != reindexDone || done.RowsDeleted != 1 || done.RowsInserted != wantRows || done.DurationMS == nil || done.ManifestVersion == nil {
+		t.Fatalf("job = %+v, want done with 1 deleted and %d inserted", done, wantRows)
+	}
+
+	// Local index of the same manifest
+	root := t.TempDir()
+	ph, err := storage.InitProject(root, proj)
+	if err != nil {
+		t.Fatalf("InitProject: %v", err)
+	}
+	defer func() { _ = ph.Close() }()
+	// Let the background build finish, then rebuild so the index holds exactly this manifest
+	time.Sleep(300 * time.Millisecond)
+	if err := storage.RebuildIndex(ctx, root, proj); err != nil {
+		t.Fatalf("RebuildIndex: %v", err)
+	}
+
+	queries := []struct {
+		name string
+		q    storage.SearchQuery
+		min  int // results expected at least, so an empty result on both sides does not pass
+	}{
+		{"fts_hello", storage.SearchQuery{Text: "Hello"}, 2},
+		{"fts_lighthouse", storage.SearchQuery{Text: "lighthouse"}, 2},
+		{"fts_storm_types", storage.SearchQuery{Text: "storm", Types: []string{"balloon", "panel_notes"}}, 2},
+		{"sfx", storage.SearchQuery{Text: "krakoom"}, 1},
+		{"pages_2_3", storage.SearchQuery{PageFrom: 2, PageTo: 3}, 3},
+		{"tags_greet", storage.SearchQuery{Tags: []string{"greet"}}, 2},
+		{"character_bob", storage.SearchQuery{Character: "bob"}, 3},
+		{"scene_beach", storage.SearchQuery{Scene: "beach"}, 2},
+		{"metadata", storage.SearchQuery{Text: "Tidewater"}, 1},
+	}
+	for _, tc := range queries {
+		t.Run(tc.name, func(t *testing.T) {
+			local, err := storage.Search(ctx, root, tc.q)
+			if err != nil {
+				t.Fatalf("sqlite search: %v", err)
+			}
+			server, err := SearchPG(ctx, db, p.ID, tc.q)
+			if err != nil {
+				t.Fatalf("pg search: %v", err)
+			}
+			lk, sk := resultKeys(local), resultKeys(server)
+			if len(lk) < tc.min || fmt.Sprint(lk) != fmt.Sprint(sk) {
+				t.Fatalf("results differ (want at least %d)\nsqlite: %v\npg:     %v", tc.min, lk, sk)
+			}
+		})
+	}
+
+	// Editors may not reindex; jobs of another project are not found
+	rec := httptest.NewRecorder()
+	serveReindex(rec, httptest.NewRequest(http.MethodPost, "/", nil), db, p.ID, editor, false, nil)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("editor reindex: status %d", rec.Code)
+	}
+	if _, err := getReindexJob(ctx, db, p.ID+1, j.JobID); !errors.Is(err, errReindexNotFound) {
+		t.Fatalf("job of another project: %v", err)
+	}
+}
@@ -20,7 +20,11 @@ import (
 )
 
 // SearchPG executes a search over the Postgres documents table using tsvector and filters
-// and returns results mapped to storage.SearchResult to ease parity checks.
+// and returns results mapped to storage.SearchResult to ease parity checks. It follows the local
+// search (storage.Search) for the same query: the text syntax (see storage.TSQuery), the character,
+// scene and tag filters, snippets with the query's match markers, and the order by page, then
+// document ID. The server has no script, so scenes are only linked to bible locations through
+// cross references stored with the documents.
 func SearchPG(ctx context.Context, db *sql.DB, projectID int64, q storage.SearchQuery) ([]storage.SearchResult, error) {
 	var (
 		args []any
@@ -28,10 +32,18 @@ func SearchPG(ctx context.Context, db *sql.DB, projectID int64, q storage.Search
 	)
 	useFTS := strings.TrimSpace(q.Text) != ""
 	if useFTS {
+		expr, err := storage.TSQuery(q.Text)
+		if err != nil {
+			return nil, err
+		}
+		if expr == "" {
+			// Only punctuation was typed; nothing can match.
+			return []storage.SearchResult{}, nil
+		}
 		b.WriteString("SELECT d.id AS doc_id, d.doc_type AS type, COALESCE(d.external_ref,'') AS path, COALESCE(d.page_num,0) AS page_id, ")
-		b.WriteString("COALESCE(ts_headline('simple', COALESCE(d.raw_text,''), plainto_tsquery('simple', $1), 'StartSel=[, StopSel=], MaxFragments=1, MaxWords=12'), '') AS snippet ")
-		b.WriteString("FROM documents d WHERE d.project_id = $2 AND d.search_vector @@ plainto_tsquery('simple', $1) ")
-		args = append(args, q.Text, projectID)
+		b.WriteString("COALESCE(ts_headline('simple', COALESCE(d.raw_text,''), to_tsquery('simple', $1), $3), '') AS snippet ")
+		b.WriteString("FROM documents d WHERE d.project_id = $2 AND d.search_vector @@ to_tsquery('simple', $1) ")
+		args = append(args, expr, projectID, headlineOptions(q))
 	} else {
 		b.WriteString("SELECT d.id AS doc_id, d.doc_type AS type, COALESCE(d.external_ref,'') AS path, COALESCE(d.page_num,0) AS page_id, '' AS snippet ")
 		b.WriteString("FROM documents d WHERE d.project_id = $1 ")
@@ -56,15 +68,30 @@ func SearchPG(ctx context.Context, db *sql.DB, projectID int64, q storage.Search
 	} else if q.PageTo > 0 {
 		b.WriteString(" AND d.page_num <= " + place(q.PageTo) + " ")
 	}
-	// Character filter
+	// Character filter: exact match on the speaking character stored by a reindex (character_id
+	// locally), else text/path contains
 	if s := strings.TrimSpace(q.Character); s != "" {
 		ss := strings.ToLower(s)
-		b.WriteString(" AND ( lower(COALESCE(d.raw_text,'')) LIKE " + place("%"+ss+":%") + " OR lower(COALESCE(d.external_ref,'')) LIKE " + place("%character:"+ss+"%") + " ) ")
+		b.WriteString(" AND ( (d.meta->>'character' IS NOT NULL AND lower(d.meta->>'character') = " + place(ss) + ") OR lower(COALESCE(d.raw_text,'')) LIKE " + place("%"+ss+":%") + " OR lower(COALESCE(d.external_ref,'')) LIKE " + place("%character:"+ss+"%") + " ) ")
 	}
-	// Scene filter
+	// Scene filter: the bible location of that name or alias and the documents linked to it, else
+	// location-related content or text containing the scene token
 	if s := strings.TrimSpace(q.Scene); s != "" {
-		ss := strings.ToLower(s)
-		b.WriteString(" AND ( lower(COALESCE(d.external_ref,'')) LIKE " + place("%location:"+ss+"%") + " OR lower(COALESCE(d.raw_text,'')) LIKE " + place("%"+ss+"%") + " ) ")
+		ids, names, err := locationDocsNamedPG(ctx, db, projectID, s)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) > 0 {
+			var paths []string
+			for _, n := range names {
+				paths = append(paths, "bible:location_aliases:"+n, "bible:location_notes:"+n)
+			}
+			in := place(ids)
+			b.WriteString(" AND ( d.id = ANY (" + in + ") OR d.id IN (SELECT from_doc_id FROM cross_refs WHERE to_doc_id = ANY (" + in + ")) OR d.external_ref = ANY (" + place(paths) + ") ) ")
+		} else {
+			ss := strings.ToLower(s)
+			b.WriteString(" AND ( lower(COALESCE(d.external_ref,'')) LIKE " + place("%location:"+ss+"%") + " OR lower(COALESCE(d.raw_text,'')) LIKE " + place("%"+ss+"%") + " ) ")
+		}
 	}
 	// Tags: require all tags to appear as @tag tokens in raw_text
 	for _, t := range q.Tags {
@@ -101,3 +128,44 @@ func SearchPG(ctx context.Context, db *sql.DB, projectID int64, q storage.Search
 	}
 	return out, rows.Err()
 }
+
+// headlineOptions returns the ts_headline options for q's match markers ([ and ] unless set) and an
+// excerpt of about the length the local search shows.
+func headlineOptions(q storage.SearchQuery) string {
+	start, end := q.MarkStart, q.MarkEnd
+	if start == "" && end == "" {
+		start, end = "[", "]"
+	}
+	return fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxWords=10, MinWords=5`, start, end)
+}
+
+// locationDocsNamedPG returns the project's bible location documents whose name or one of its
+// aliases equals name (case-insensitive), and those locations' names, like the local search.
+func locationDocsNamedPG(ctx context.Context, db *sql.DB, projectID int64, name string) ([]int64, []string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT d.id, COALESCE(d.raw_text,''), COALESCE(a.raw_text,'') FROM documents d
+		LEFT JOIN documents a ON a.project_id = d.project_id AND a.doc_type = 'location_aliases'
+			AND a.external_ref = 'bible:location_aliases:' || d.raw_text
+		WHERE d.project_id = $1 AND d.doc_type = 'location'`, projectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("scene locations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	var ids []int64
+	var names []string
+	for rows.Next() {
+		var id int64
+		var loc, aliases string
+		if err := rows.Scan(&id, &loc, &aliases); err != nil {
+			return nil, nil, fmt.Errorf("scan location: %w", err)
+		}
+		match := strings.EqualFold(strings.TrimSpace(loc), name)
+		for _, a := range strings.Split(aliases, ",") {
+			match = match || strings.EqualFold(strings.TrimSpace(a), name)
+		}
+		if match {
+			ids = append(ids, id)
+			names = append(names, loc)
+		}
+	}
+	return ids, names, rows.Err()
+}
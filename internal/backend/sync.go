@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// pushOpInput is one op in a sync push request.
+type pushOpInput struct {
+	OpID       string          `json:"op_id"`
+	OpType     string          `json:"op_type"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// pushResult is the JSON response of a sync push.
+type pushResult struct {
+	ProjectID     int64 `json:"project_id"`
+	ServerVersion int64 `json:"server_version"`
+	Accepted      int   `json:"accepted"`
+	Duplicates    int   `json:"duplicates"`
+}
+
+// pushOps appends ops to the project's op log, giving each stored op the next project version.
+// Ops whose op_id is already stored are skipped without using up a version, so a client may safely
+// retry a push whose response it never received. Ops without op_id get a fresh one.
+func pushOps(ctx context.Context, db *sql.DB, pid int64, actor string, ops []pushOpInput) (pushResult, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return pushResult{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	var curVersion int64
+	if err := tx.QueryRowContext(ctx, `SELECT version FROM projects WHERE id = $1 FOR UPDATE`, pid).Scan(&curVersion); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return pushResult{}, errProjectNotFound
+		}
+		return pushResult{}, err
+	}
+	res := pushResult{ProjectID: pid, ServerVersion: curVersion}
+	for _, op := range ops {
+		if len(op.Payload) == 0 {
+			op.Payload = json.RawMessage("{}")
+		}
+		r, err := tx.ExecContext(ctx, `INSERT INTO sync_ops (op_id, project_id, version, actor, op_type, entity_type, entity_id, payload)
+			VALUES (COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()), $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (op_id) DO NOTHING`,
+			op.OpID, pid, res.ServerVersion+1, actor, op.OpType, op.EntityType, op.EntityID, op.Payload)
+		if err != nil {
+			return pushResult{}, err
+		}
+		if n, _ := r.RowsAffected(); n == 1 {
+			res.ServerVersion++
+			res.Accepted++
+		} else {
+			res.Duplicates++
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE projects SET version = $1 WHERE id = $2`, res.ServerVersion, pid); err != nil {
+		return pushResult{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return pushResult{}, err
+	}
+	return res, nil
+}
+
+// Page sizes of a sync pull.
+const (
+	defaultPullLimit = 500
+	maxPullLimit     = 5000
+)
+
+// pulledOp is one op in a sync pull response.
+type pulledOp struct {
+	OpID       string          `json:"op_id"`
+	Version    int64           `json:"version"`
+	Actor      string          `json:"actor"`
+	OpType     string          `json:"op_type"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Payload    json.RawMessage `json:"payload"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// pullResult is the JSON response of a sync pull. NextSince is the since value for the next request: the
+// version of the last returned op, or the requested since when there were none. HasMore reports that ops
+// beyond this page exist, so a client loops until it is false instead of comparing versions, which may
+// have gaps.
+type pullResult struct {
+	ProjectID     int64      `json:"project_id"`
+	ServerVersion int64      `json:"server_version"`
+	Ops           []pulledOp `json:"ops"`
+	NextSince     int64      `json:"next_since"`
+	HasMore       bool       `json:"has_more"`
+}
+
+// pullOps returns up to limit ops of the project with a version above since, oldest first.
+func pullOps(ctx context.Context, db *sql.DB, pid, since int64, limit int) (pullResult, error) {
+	res := pullResult{ProjectID: pid, NextSince: since}
+	if err := db.QueryRowContext(ctx, `SELECT version FROM projects WHERE id = $1`, pid).Scan(&res.ServerVersion); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return pullResult{}, errProjectNotFound
+		}
+		return pullResult{}, err
+	}
+	rows, err := db.QueryContext(ctx, `SELECT op_id, version, actor, op_type, entity_type, entity_id, payload, created_at
+		FROM sync_ops WHERE project_id = $1 AND version > $2 ORDER BY version ASC LIMIT $3`, pid, since, limit+1)
+	if err != nil {
+		return pullResult{}, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var o pulledOp
+		if err := rows.Scan(&o.OpID, &o.Version, &o.Actor, &o.OpType, &o.EntityType, &o.EntityID, &o.Payload, &o.CreatedAt); err != nil {
+			return pullResult{}, err
+		}
+		res.Ops = append(res.Ops, o)
+	}
+	if err := rows.Err(); err != nil {
+		return pullResult{}, err
+	}
+	res.Ops, res.HasMore = cutPage(res.Ops, limit)
+	if n := len(res.Ops); n > 0 {
+		res.NextSince = res.Ops[n-1].Version
+	} else {
+		res.Ops = []pulledOp{}
+	}
+	return res, nil
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPushOps_DeduplicatesByOpID_Integration(t *testing.T) {
+	db := openPGForTest(t)
+	defer func() { _ = db.Close() }()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var pid int64
+	if err := db.QueryRowContext(ctx, `INSERT INTO projects(name) VALUES ($1) RETURNING id`, fmt.Sprintf("Push %d", time.Now().UnixNano())).Scan(&pid); err != nil {
+		t.Fatalf("insert project: %v", err)
+	}
+	var start int64
+	if err := db.QueryRowContext(ctx, `SELECT version FROM projects WHERE id = $1`, pid).Scan(&start); err != nil {
+		t.Fatalf("version: %v", err)
+	}
+	var opID string
+	if err := db.QueryRowContext(ctx, `SELECT gen_random_uuid()::text`).Scan(&opID); err != nil {
+		t.Fatalf("uuid: %v", err)
+	}
+	ops := []pushOpInput{
+		{OpID: opID, OpType: "upsert", EntityType: "page", EntityID: "pg-1"},
+		{OpType: "upsert", EntityType: "page", EntityID: "pg-2"},
+	}
+	first, err := pushOps(ctx, db, pid, "tester", ops)
+	if err != nil {
+		t.Fatalf("push: %v", err)
+	}
+	if first.Accepted != 2 || first.Duplicates != 0 || first.ServerVersion != start+2 {
+		t.Fatalf("first push: %+v", first)
+	}
+	// A retry of the same batch stores only the op without op_id again
+	again, err := pushOps(ctx, db, pid, "tester", ops)
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if again.Accepted != 1 || again.Duplicates != 1 || again.ServerVersion != start+3 {
+		t.Fatalf("retried push: %+v", again)
+	}
+	var n int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sync_ops WHERE op_id = $1`, opID).Scan(&n); err != nil || n != 1 {
+		t.Fatalf("op stored %d times (%v)", n, err)
+	}
+	if _, err := pushOps(ctx, db, -1, "tester", ops); !errors.Is(err, errProjectNotFound) {
+		t.Fatalf("expected errProjectNotFound, got %v", err)
+	}
+}
+
+func TestPullOps_Pages_Integration(t *testing.T) {
+	db := openPGForTest(t)
+	defer func() { _ = db.Close() }()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var pid int64
+	if err := db.QueryRowContext(ctx, `INSERT INTO projects(name) VALUES ($1) RETURNING id`, fmt.Sprintf("Pull %d", time.Now().UnixNano())).Scan(&pid); err != nil {
+		t.Fatalf("insert project: %v", err)
+	}
+	// Sparse versions: 1, 2, 5, 9
+	for _, v := range []int64{1, 2, 5, 9} {
+		if _, err := db.ExecContext(ctx, `INSERT INTO sync_ops(project_id, version, actor, op_type, entity_type, entity_id) VALUES ($1, $2, 'tester', 'upsert', 'page', 'pg')`, pid, v); err != nil {
+			t.Fatalf("insert op: %v", err)
+		}
+	}
+	for _, c := range []struct {
+		since     int64
+		limit     int
+		versions  string
+		nextSince int64
+		more      bool
+	}{
+		{0, 2, "[1 2]", 2, true},
+		{2, 2, "[5 9]", 9, false}, // exactly the rest: no further page
+		{0, 3, "[1 2 5]", 5, true},
+		{5, 3, "[9]", 9, false},
+		{0, 4, "[1 2 5 9]", 9, false},
+		{9, 4, "[]", 9, false},
+		{3, 1, "[5]", 5, true}, // since between versions
+	} {
+		res, err := pullOps(ctx, db, pid, c.since, c.limit)
+		if err != nil {
+			t.Fatalf("pull %d/%d: %v", c.since, c.limit, err)
+		}
+		var vs []int64
+		for _, o := range res.Ops {
+			vs = append(vs, o.Version)
+		}
+		if fmt.Sprint(vs) != c.versions || res.NextSince != c.nextSince || res.HasMore != c.more {
+			t.Fatalf("pull since %d limit %d: versions %v next %d more %v", c.since, c.limit, vs, res.NextSince, res.HasMore)
+		}
+	}
+	if _, err := pullOps(ctx, db, -1, 0, 10); !errors.Is(err, errProjectNotFound) {
+		t.Fatalf("expected errProjectNotFound, got %v", err)
+	}
+}
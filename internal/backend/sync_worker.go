@@ -0,0 +1,303 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gocomicwriter/internal/storage"
+)
+
+// SyncQueue is the persistent sync state of a project the sync worker runs on: the outbox with its
+// per-op states and backoff, the pull cursor and the conflicts. *storage.IndexHandle implements it.
+type SyncQueue interface {
+	SyncOutbox
+	RecoverSyncOps(ctx context.Context) (int, error)
+	DueSyncOps(ctx context.Context, now time.Time, limit int) ([]storage.SyncOp, error)
+	MarkSyncOpsSent(ctx context.Context, seqs []int64) error
+	AckSyncOpSeqs(ctx context.Context, seqs []int64, serverVersion int64) error
+	FailSyncOps(ctx context.Context, seqs []int64, cause error, next time.Time) error
+	SyncPulledVersion(ctx context.Context, projectID int64) (int64, error)
+	SetSyncPulledVersion(ctx context.Context, projectID int64, version int64) error
+	ReconcilePulledOps(ctx context.Context, ops []storage.RemoteSyncOp) ([]storage.RemoteSyncOp, int, error)
+	SyncQueueStats(ctx context.Context) (storage.SyncQueueStats, error)
+	SetSyncLastSuccess(ctx context.Context, t time.Time) error
+	PruneSyncOps(ctx context.Context, cutoff time.Time) error
+}
+
+var _ SyncQueue = (*storage.IndexHandle)(nil)
+
+// SyncWorkerConfig tunes the sync worker. Backoff spaces the retries of a failed push per op and the
+// rounds after a failed round; its MaxAttempts is not used, the worker retries until it is closed.
+type SyncWorkerConfig struct {
+	Interval  time.Duration // between rounds while the server is reachable
+	Timeout   time.Duration // bounds one round
+	Backoff   RetryPolicy
+	Retention time.Duration // how long acked ops and resolved conflicts are kept
+	PageSize  int           // ops per pull request
+}
+
+// DefaultSyncWorkerConfig is used for zero fields of the config passed to StartSyncWorker.
+var DefaultSyncWorkerConfig = SyncWorkerConfig{
+	Interval:  15 * time.Second,
+	Timeout:   60 * time.Second,
+	Backoff:   RetryPolicy{BaseDelay: 2 * time.Second, MaxDelay: 5 * time.Minute},
+	Retention: 7 * 24 * time.Hour,
+	PageSize:  500,
+}
+
+// SyncStatus is the state of the sync after a round: the queue counts, and Err when the round failed,
+// e.g. because the server is unreachable.
+type SyncStatus struct {
+	storage.SyncQueueStats
+	Err error
+	At  time.Time
+}
+
+// Online reports whether the last round reached the server.
+func (s SyncStatus) Online() bool { return s.Err == nil }
+
+// SyncHandlers connect the worker to the open project. Apply receives the pulled ops to apply to the
+// manifest and returns once they are applied and saved; the pull is committed only when it returns nil,
+// so ops it could not take are pulled again. Status receives the state after each round. Both run on
+// the worker goroutine and may be nil.
+type SyncHandlers struct {
+	Apply  func(ctx context.Context, ops []storage.RemoteSyncOp) error
+	Status func(SyncStatus)
+}
+
+// SyncWorker keeps a project in sync with its server project in the background: each round pulls the
+// ops other clients pushed, records conflicts with local changes and hands the rest to Apply, then
+// pushes the due ops of the outbox. Sent ops left over from a previous session are recovered on start.
+// Rounds run every Interval, after Trigger, and after a failed round with growing backoff.
+type SyncWorker struct {
+	c       *Client
+	q       SyncQueue
+	pid     int64
+	cfg     SyncWorkerConfig
+	h       SyncHandlers
+	l       *slog.Logger
+	now     func() time.Time
+	trigger chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{}
+	once    sync.Once
+}
+
+// StartSyncWorker starts the worker for server project pid and runs the first round right away.
+func StartSyncWorker(c *Client, q SyncQueue, pid int64, cfg SyncWorkerConfig, h SyncHandlers, l *slog.Logger) *SyncWorker {
+	d := DefaultSyncWorkerConfig
+	if cfg.Interval <= 0 {
+		cfg.Interval = d.Interval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = d.Timeout
+	}
+	if cfg.Backoff.BaseDelay <= 0 {
+		cfg.Backoff = d.Backoff
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = d.Retention
+	}
+	if cfg.PageSize <= 0 {
+		cfg.PageSize = d.PageSize
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &SyncWorker{
+		c: c, q: q, pid: pid, cfg: cfg, h: h, now: time.Now,
+		l:       l.With(slog.String("component", "sync"), slog.Int64("project_id", pid)),
+		trigger: make(chan struct{}, 1),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go w.run(ctx)
+	return w
+}
+
+// Trigger asks for a round as soon as the current one, if any, is done, e.g. after a save.
+func (w *SyncWorker) Trigger() {
+	if w == nil {
+		return
+	}
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Close stops the worker and waits for it; a running round is cancelled. Ops it was pushing stay sent
+// and are recovered by the next worker. Close is safe to call more than once and on a nil worker.
+func (w *SyncWorker) Close() {
+	if w == nil {
+		return
+	}
+	w.once.Do(w.cancel)
+	<-w.done
+}
+
+func (w *SyncWorker) run(ctx context.Context) {
+	defer close(w.done)
+	if n, err := w.q.RecoverSyncOps(ctx); err != nil {
+		w.l.Warn("recover sync ops failed", slog.Any("err", err))
+	} else if n > 0 {
+		w.l.Info("recovered unacknowledged sync ops", slog.Int("count", n))
+	}
+	failures := 0
+	for {
+		err := w.round(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		wait := w.cfg.Interval
+		if err != nil {
+			if failures == 0 {
+				w.l.Warn("sync round failed", slog.Any("err", err))
+			}
+			failures++
+			wait = w.cfg.Backoff.delay(failures, err)
+		} else {
+			if failures > 0 {
+				w.l.Info("sync recovered", slog.Int("failed_rounds", failures))
+			}
+			failures = 0
+		}
+		w.report(ctx, err)
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-w.trigger:
+			t.Stop()
+		case <-t.C:
+		}
+	}
+}
+
+func (w *SyncWorker) report(ctx context.Context, err error) {
+	if w.h.Status == nil {
+		return
+	}
+	st, serr := w.q.SyncQueueStats(ctx)
+	if serr != nil {
+		w.l.Warn("read sync queue stats failed", slog.Any("err", serr))
+	}
+	w.h.Status(SyncStatus{SyncQueueStats: st, Err: err, At: w.now()})
+}
+
+// round pulls, then pushes. A failed pull skips the push: the server is most likely unreachable, and
+// local ops are only pushed once the remote ops before them were checked for conflicts.
+func (w *SyncWorker) round(parent context.Context) error {
+	ctx, cancel := context.WithTimeout(parent, w.cfg.Timeout)
+	defer cancel()
+	if err := w.pull(ctx); err != nil {
+		return err
+	}
+	if err := w.push(ctx); err != nil {
+		return err
+	}
+	if err := w.q.SetSyncLastSuccess(ctx, w.now()); err != nil {
+		w.l.Warn("record sync time failed", slog.Any("err", err))
+	}
+	if err := w.q.PruneSyncOps(ctx, w.now().Add(-w.cfg.Retention)); err != nil {
+		w.l.Warn("prune sync ops failed", slog.Any("err", err))
+	}
+	return nil
+}
+
+func (w *SyncWorker) pull(ctx context.Context) error {
+	since, err := w.q.SyncPulledVersion(ctx, w.pid)
+	if err != nil {
+		return fmt.Errorf("read pull version: %w", err)
+	}
+	res, err := w.c.PullAllOps(ctx, w.pid, since, w.cfg.PageSize)
+	if err != nil {
+		return fmt.Errorf("pull: %w", err)
+	}
+	if res.NextSince <= since {
+		return nil
+	}
+	ops := make([]storage.RemoteSyncOp, 0, len(res.Ops))
+	for _, op := range res.Ops {
+		ops = append(ops, storage.RemoteSyncOp{
+			SyncOp:  storage.SyncOp{OpID: op.OpID, OpType: op.OpType, EntityType: op.EntityType, EntityID: op.EntityID, Payload: op.Payload},
+			Version: op.Version,
+			Actor:   op.Actor,
+		})
+	}
+	apply, conflicts, err := w.q.ReconcilePulledOps(ctx, ops)
+	if err != nil {
+		return fmt.Errorf("reconcile pulled ops: %w", err)
+	}
+	if len(apply) > 0 && w.h.Apply != nil {
+		if err := w.h.Apply(ctx, apply); err != nil {
+			return fmt.Errorf("apply pulled ops: %w", err)
+		}
+	}
+	if err := w.q.SetSyncPulledVersion(ctx, w.pid, res.NextSince); err != nil {
+		return fmt.Errorf("store pull version: %w", err)
+	}
+	w.l.Debug("pulled ops", slog.Int("ops", len(ops)), slog.Int("applied", len(apply)), slog.Int("conflicts", conflicts), slog.Int64("version", res.NextSince))
+	return nil
+}
+
+// push sends the due ops in batches. A failed batch is rescheduled with the backoff of its next attempt
+// and ends the round; the ops after it wait for the next round so that they stay in order.
+func (w *SyncWorker) push(ctx context.Context) error {
+	for {
+		ops, err := w.q.DueSyncOps(ctx, w.now(), pushBatchSize)
+		if err != nil {
+			return fmt.Errorf("read outbox: %w", err)
+		}
+		if len(ops) == 0 {
+			return nil
+		}
+		seqs := make([]int64, len(ops))
+		in := make([]SyncOpInput, len(ops))
+		attempts := 0
+		for i, op := range ops {
+			seqs[i] = op.Seq
+			in[i] = SyncOpInput{OpID: op.OpID, OpType: op.OpType, EntityType: op.EntityType, EntityID: op.EntityID, Payload: op.Payload}
+			attempts = max(attempts, op.Attempts)
+		}
+		version, err := w.q.SyncServerVersion(ctx, w.pid)
+		if err != nil {
+			return fmt.Errorf("read sync version: %w", err)
+		}
+		if err := w.q.MarkSyncOpsSent(ctx, seqs); err != nil {
+			return fmt.Errorf("mark ops sent: %w", err)
+		}
+		res, err := w.c.PushOps(ctx, w.pid, version, in)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return err // closing; the ops stay sent and are recovered on the next start
+			}
+			next := w.now().Add(w.cfg.Backoff.delay(attempts+1, err))
+			// The failure is recorded even when the round's context is done
+			if ferr := w.q.FailSyncOps(context.WithoutCancel(ctx), seqs, err, next); ferr != nil {
+				w.l.Warn("record failed push failed", slog.Any("err", ferr))
+			}
+			return fmt.Errorf("push: %w", err)
+		}
+		if err := w.q.AckSyncOpSeqs(ctx, seqs, res.ServerVersion); err != nil {
+			return fmt.Errorf("ack pushed ops: %w", err)
+		}
+		if err := w.q.SetSyncServerVersion(ctx, w.pid, res.ServerVersion); err != nil {
+			return fmt.Errorf("store sync version: %w", err)
+		}
+		w.l.Debug("pushed ops", slog.Int("accepted", res.Accepted), slog.Int("duplicates", res.Duplicates), slog.Int64("server_version", res.ServerVersion))
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// opLogServer is a fake sync server with a shared op log for push and pull. While offline it answers
+// every request with 503; push answers pushStatus instead when that is set.
+type opLogServer struct {
+	mu         sync.Mutex
+	ops        []SyncOp
+	offline    bool
+	pushStatus int
+}
+
+func (s *opLogServer) start(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.offline {
+			http.Error(w, `{"error":"unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		if r.Method == http.MethodGet {
+			var since int64
+			_, _ = fmt.Sscan(r.URL.Query().Get("since"), &since)
+			res := PullResult{ProjectID: 5, ServerVersion: int64(len(s.ops)), NextSince: since}
+			for _, op := range s.ops {
+				if op.Version > since {
+					res.Ops = append(res.Ops, op)
+					res.NextSince = op.Version
+				}
+			}
+			_ = json.NewEncoder(w).Encode(res)
+			return
+		}
+		if s.pushStatus != 0 {
+			http.Error(w, `{"error":"rejected"}`, s.pushStatus)
+			return
+		}
+		var req struct {
+			Ops []SyncOpInput `json:"ops"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		for _, in := range req.Ops {
+			s.add(SyncOp{OpID: in.OpID, Actor: "me", OpType: in.OpType, EntityType: in.EntityType, EntityID: in.EntityID, Payload: in.Payload})
+		}
+		_ = json.NewEncoder(w).Encode(PushResult{ProjectID: 5, ServerVersion: int64(len(s.ops)), Accepted: len(req.Ops)})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// add appends op at the next version; the caller holds mu unless the server is not started yet.
+func (s *opLogServer) add(op SyncOp) {
+	op.Version = int64(len(s.ops)) + 1
+	s.ops = append(s.ops, op)
+}
+
+func (s *opLogServer) set(f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f()
+}
+
+// queueGridChange records a grid change of page 1 in the outbox of ix.
+func queueGridChange(t *testing.T, ix *storage.IndexHandle, grid string) {
+	t.Helper()
+	base := domain.Project{Name: "Sync", Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Grid: "3x3"}}}}}
+	cur := base
+	cur.Issues = []domain.Issue{{Pages: []domain.Page{{Number: 1, Grid: grid}}}}
+	if _, err := storage.NewChangeTracker(base).Record(context.Background(), ix, cur); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+}
+
+var testSyncConfig = SyncWorkerConfig{
+	Interval: time.Hour, // rounds run on Trigger
+	Timeout:  5 * time.Second,
+	Backoff:  RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond},
+}
+
+// startTestWorker starts a worker whose statuses are sent on the returned channel.
+func startTestWorker(t *testing.T, url string, ix *storage.IndexHandle, apply func(context.Context, []storage.RemoteSyncOp) error) (*SyncWorker, chan SyncStatus) {
+	t.Helper()
+	statuses := make(chan SyncStatus, 100)
+	w := StartSyncWorker(testClient(url), ix, 5, testSyncConfig, SyncHandlers{Apply: apply, Status: func(s SyncStatus) { statuses <- s }}, slog.New(slog.DiscardHandler))
+	t.Cleanup(w.Close)
+	return w, statuses
+}
+
+// waitStatus returns the first status accepted by ok.
+func waitStatus(t *testing.T, statuses chan SyncStatus, ok func(SyncStatus) bool) SyncStatus {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case s := <-statuses:
+			if ok(s) {
+				return s
+			}
+		case <-deadline:
+			t.Fatal("timed out waiting for sync status")
+		}
+	}
+}
+
+func TestSyncWorker_OfflineQueueSurvivesRestart(t *testing.T) {
+	root := t.TempDir()
+	s := &opLogServer{offline: true}
+	srv := s.start(t)
+	ix := storage.NewIndexHandle(root)
+	queueGridChange(t, ix, "1x1")
+	queueGridChange(t, ix, "2x2")
+
+	w, statuses := startTestWorker(t, srv.URL, ix, nil)
+	st := waitStatus(t, statuses, func(s SyncStatus) bool { return !s.Online() })
+	if st.Pending != 2 || !st.LastSuccess.IsZero() {
+		t.Fatalf("offline status %+v", st)
+	}
+	// Back online, the server rejects the push: the ops fail and wait for their backoff
+	s.set(func() { s.offline, s.pushStatus = false, http.StatusBadRequest })
+	st = waitStatus(t, statuses, func(s SyncStatus) bool { return s.Failed == 2 })
+	if st.LastError == "" || st.Online() {
+		t.Fatalf("failed push status %+v", st)
+	}
+	w.Close()
+	_ = ix.Close()
+
+	// The app restarts with the server healthy again
+	s.set(func() { s.pushStatus = 0 })
+	ix = storage.NewIndexHandle(root)
+	defer func() { _ = ix.Close() }()
+	var applied int
+	w, statuses = startTestWorker(t, srv.URL, ix, func(_ context.Context, ops []storage.RemoteSyncOp) error {
+		applied += len(ops)
+		return nil
+	})
+	st = waitStatus(t, statuses, func(s SyncStatus) bool { return s.Online() && s.Unsent() == 0 })
+	if st.LastSuccess.IsZero() {
+		t.Fatalf("successful round not recorded: %+v", st)
+	}
+	s.set(func() {
+		if len(s.ops) != 2 || s.ops[0].EntityID != "issue:1/page:1" {
+			t.Fatalf("server ops %+v", s.ops)
+		}
+	})
+	// Our own ops pulled back are neither applied nor reported as conflicts
+	w.Trigger()
+	st = waitStatus(t, statuses, func(s SyncStatus) bool { return s.Online() })
+	if v, _ := ix.SyncPulledVersion(context.Background(), 5); v != 2 || applied != 0 || st.Conflicts != 0 {
+		t.Fatalf("pulled version %d, %d applied, status %+v", v, applied, st)
+	}
+}
+
+func TestSyncWorker_PullAppliesAndHoldsConflicts(t *testing.T) {
+	s := &opLogServer{}
+	s.add(SyncOp{OpID: "r-1", Actor: "bob", OpType: storage.SyncOpUpdate, EntityType: storage.SyncEntityPage, EntityID: "issue:1/page:1",
+		Payload: json.RawMessage(`{"grid":"4x4","number":1,"parent":{"issue":1}}`)})
+	s.add(SyncOp{OpID: "r-2", Actor: "bob", OpType: storage.SyncOpCreate, EntityType: storage.SyncEntityBibleTag, EntityID: "Noir",
+		Payload: json.RawMessage(`{"name":"Noir"}`)})
+	srv := s.start(t)
+	ix := storage.NewIndexHandle(t.TempDir())
+	defer func() { _ = ix.Close() }()
+	queueGridChange(t, ix, "1x1") // based on version 0, concurrent with r-1
+
+	fail := true
+	var applied []string
+	apply := func(_ context.Context, ops []storage.RemoteSyncOp) error {
+		if fail {
+			fail = false
+			return fmt.Errorf("project busy")
+		}
+		for _, op := range ops {
+			applied = append(applied, op.OpID)
+		}
+		return nil
+	}
+	w, statuses := startTestWorker(t, srv.URL, ix, apply)
+	// A failed apply keeps the pull cursor, so the ops come again in the next round
+	waitStatus(t, statuses, func(s SyncStatus) bool { return !s.Online() })
+	st := waitStatus(t, statuses, func(s SyncStatus) bool { return s.Online() })
+	if st.Conflicts != 1 || st.Pending != 1 || fmt.Sprint(applied) != "[r-2]" {
+		t.Fatalf("status %+v, applied %v", st, applied)
+	}
+	if v, _ := ix.SyncPulledVersion(context.Background(), 5); v != 2 {
+		t.Fatalf("pulled version %d", v)
+	}
+	s.set(func() {
+		if len(s.ops) != 2 {
+			t.Fatalf("held op was pushed: %+v", s.ops)
+		}
+	})
+
+	// Keeping the local change releases it for the next push
+	list, _ := ix.OpenSyncConflicts(context.Background())
+	if len(list) != 1 || list[0].Remote.OpID != "r-1" {
+		t.Fatalf("conflicts %+v", list)
+	}
+	if _, err := ix.ResolveSyncConflict(context.Background(), list[0].ID, storage.SyncKeepLocal); err != nil {
+		t.Fatal(err)
+	}
+	w.Trigger()
+	waitStatus(t, statuses, func(s SyncStatus) bool { return s.Online() && s.Conflicts == 0 && s.Unsent() == 0 })
+	s.set(func() {
+		if len(s.ops) != 3 || s.ops[2].Actor != "me" {
+			t.Fatalf("server ops %+v", s.ops)
+		}
+	})
+}
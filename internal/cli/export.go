@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+// Package cli implements the headless subcommands of the gocomicwriter binary. It builds on the
+// storage and export packages only, so it works in builds without the fyne UI.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/export"
+	"gocomicwriter/internal/storage"
+)
+
+// Exit codes of the export subcommand.
+const (
+	ExitOK              = 0
+	ExitExportFailed    = 1
+	ExitInvalidOptions  = 2
+	ExitProjectNotFound = 3
+)
+
+// ErrUsage marks command-line arguments that cannot be used.
+var ErrUsage = errors.New("invalid options")
+
+// ExportFlags are the options of `gocomicwriter export`.
+type ExportFlags struct {
+	Project    string
+	Format     string // pdf | png | svg | cbz | epub
+	Out        string // file for pdf, cbz and epub; folder for png and svg
+	Issue      int    // 1-based
+	Pages      string // page numbers, e.g. "1-20,25"
+	DPI        int
+	Guides     bool
+	Reflowable bool   // epub: reflowable text instead of page images
+	Preset     string // name of a project export preset supplying the defaults
+
+	set map[string]bool // flags given on the command line
+}
+
+// newExportFlagSet declares the export flags on f.
+func newExportFlagSet(f *ExportFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.StringVar(&f.Project, "project", "", "project folder (containing comic.json)")
+	fs.StringVar(&f.Format, "format", "", "pdf, png, svg, cbz or epub")
+	fs.StringVar(&f.Out, "out", "", "output file (pdf, cbz, epub) or folder (png, svg); default: the project's exports folder")
+	fs.IntVar(&f.Issue, "issue", 1, "issue number, starting at 1")
+	fs.StringVar(&f.Pages, "pages", "", `page numbers to export, e.g. "1-20" or "3,5,8-"; default: all`)
+	fs.IntVar(&f.DPI, "dpi", 0, "raster resolution for png, cbz and epub; default: the issue's DPI")
+	fs.BoolVar(&f.Guides, "guides", false, "draw trim and bleed guides")
+	fs.BoolVar(&f.Reflowable, "reflowable", false, "epub: reflowable text instead of fixed-layout page images")
+	fs.StringVar(&f.Preset, "preset", "", "start from the named export preset of the project; other flags override it")
+	return fs
+}
+
+// ParseExportFlags parses the arguments following `export`. Errors wrap ErrUsage, except flag.ErrHelp
+// for -h/-help.
+func ParseExportFlags(args []string) (ExportFlags, error) {
+	var f ExportFlags
+	fs := newExportFlagSet(&f)
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return f, err
+		}
+		return f, fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+	if fs.NArg() > 0 {
+		return f, fmt.Errorf("%w: unexpected argument %q", ErrUsage, fs.Arg(0))
+	}
+	f.set = map[string]bool{}
+	fs.Visit(func(fl *flag.Flag) { f.set[fl.Name] = true })
+	f.Format = strings.ToLower(strings.TrimSpace(f.Format))
+	switch {
+	case strings.TrimSpace(f.Project) == "":
+		return f, fmt.Errorf("%w: --project is required", ErrUsage)
+	case f.Format == "" && f.Preset == "":
+		return f, fmt.Errorf("%w: --format or --preset is required", ErrUsage)
+	case f.Issue < 1:
+		return f, fmt.Errorf("%w: --issue must be 1 or more", ErrUsage)
+	case f.DPI < 0:
+		return f, fmt.Errorf("%w: --dpi must not be negative", ErrUsage)
+	}
+	return f, nil
+}
+
+// ExportPreset turns the flags into the export settings for ph: the named project preset, if any, with
+// every flag given on the command line taking precedence. The result is validated, including the page
+// selection against the chosen issue.
+func (f ExportFlags) ExportPreset(ph *storage.ProjectHandle) (domain.ExportPreset, error) {
+	p := domain.ExportPreset{Name: "command line"}
+	if f.Preset != "" {
+		stored, ok := storage.FindExportPreset(ph, f.Preset)
+		if !ok {
+			return p, fmt.Errorf("%w: the project has no export preset %q", ErrUsage, f.Preset)
+		}
+		p = stored
+		if p.EPUB != nil {
+			epub := *p.EPUB
+			p.EPUB = &epub
+		}
+	}
+	if f.set["format"] {
+		p.Format = f.Format
+	}
+	if f.set["pages"] {
+		p.Pages = strings.TrimSpace(f.Pages)
+	}
+	if f.set["dpi"] {
+		p.DPI = f.DPI
+	}
+	if f.set["guides"] {
+		p.IncludeGuides = f.Guides
+	}
+	if f.set["reflowable"] {
+		if p.EPUB == nil {
+			p.EPUB = &domain.EPUBMetadata{}
+		}
+		p.EPUB.Reflowable = f.Reflowable
+	}
+	if err := storage.ValidateExportPreset(p); err != nil {
+		return p, fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+	if f.Issue > len(ph.Project.Issues) {
+		return p, fmt.Errorf("%w: the project has %d issue(s), not %d", ErrUsage, len(ph.Project.Issues), f.Issue)
+	}
+	if _, err := storage.ParsePageSelection(p.Pages, ph.Project.Issues[f.Issue-1]); err != nil {
+		return p, fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+	return p, nil
+}
+
+// OutPath returns where an export in format goes: --out made absolute against the working directory,
+// or by default issue-<n>.<format> (a folder issue-<n>-<format> for png and svg) in the exports
+// folder of the project at root.
+func (f ExportFlags) OutPath(root, format string) (string, error) {
+	if strings.TrimSpace(f.Out) != "" {
+		return filepath.Abs(f.Out)
+	}
+	name := fmt.Sprintf("issue-%d.%s", f.Issue, format)
+	if format == "png" || format == "svg" {
+		name = fmt.Sprintf("issue-%d-%s", f.Issue, format)
+	}
+	return filepath.Join(root, "exports", name), nil
+}
+
+// RunExport runs `gocomicwriter export` with the arguments following the subcommand and returns the
+// process exit code. Progress and errors go to stderr as plain lines.
+func RunExport(args []string, stderr io.Writer) int {
+	f, err := ParseExportFlags(args)
+	if errors.Is(err, flag.ErrHelp) {
+		printExportUsage(stderr)
+		return ExitOK
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		printExportUsage(stderr)
+		return ExitInvalidOptions
+	}
+	if st, serr := os.Stat(filepath.Join(f.Project, storage.ManifestFileName)); serr != nil || st.IsDir() {
+		fmt.Fprintf(stderr, "error: no project found at %s (missing %s)\n", f.Project, storage.ManifestFileName)
+		return ExitProjectNotFound
+	}
+	fmt.Fprintf(stderr, "opening project %s\n", f.Project)
+	ph, err := storage.Open(f.Project)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: open project: %v\n", err)
+		return ExitProjectNotFound
+	}
+	p, err := f.ExportPreset(ph)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitInvalidOptions
+	}
+	out, err := f.OutPath(ph.Root, p.Format)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitInvalidOptions
+	}
+	pages := p.Pages
+	if pages == "" {
+		pages = "all"
+	}
+	fmt.Fprintf(stderr, "exporting issue %d as %s (pages %s) to %s\n", f.Issue, p.Format, pages, out)
+	start := time.Now()
+	if err := export.ExportWithPreset(ph, f.Issue-1, p, out); err != nil {
+		fmt.Fprintf(stderr, "error: export failed: %v\n", err)
+		return ExitExportFailed
+	}
+	fmt.Fprintf(stderr, "done in %s\n", time.Since(start).Round(time.Millisecond))
+	return ExitOK
+}
+
+func printExportUsage(w io.Writer) {
+	var f ExportFlags
+	fs := newExportFlagSet(&f)
+	fs.SetOutput(w)
+	fmt.Fprintln(w, "usage: gocomicwriter export --project <dir> --format <pdf|png|svg|cbz|epub> [options]")
+	fs.PrintDefaults()
+	fmt.Fprintf(w, "exit codes: %d ok, %d export failed, %d invalid options, %d project not found\n",
+		ExitOK, ExitExportFailed, ExitInvalidOptions, ExitProjectNotFound)
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package cli
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func cliTestProject() domain.Project {
+	page := func(n int) domain.Page {
+		return domain.Page{Number: n, Panels: []domain.Panel{{ID: "p1", Geometry: domain.Rect{X: 18, Y: 18, Width: 324, Height: 504}}}}
+	}
+	return domain.Project{
+		Name: "CLI",
+		Issues: []domain.Issue{{
+			TrimWidth: 360, TrimHeight: 540, Bleed: 18, DPI: 72,
+			Pages: []domain.Page{page(1), page(2), page(3)},
+		}},
+		ExportPresets: []domain.ExportPreset{{Name: "Web", Format: "png", DPI: 50, Pages: "2", IncludeGuides: true}},
+	}
+}
+
+func TestParseExportFlags(t *testing.T) {
+	f, err := ParseExportFlags([]string{"--project", "p", "--format", "CBZ", "--out", "n.cbz", "--pages", "1-20", "--dpi", "150"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if f.Project != "p" || f.Format != "cbz" || f.Out != "n.cbz" || f.Pages != "1-20" || f.DPI != 150 || f.Issue != 1 {
+		t.Fatalf("unexpected flags: %+v", f)
+	}
+	bad := [][]string{
+		{"--format", "pdf"},
+		{"--project", "p"},
+		{"--project", "p", "--format", "pdf", "--issue", "0"},
+		{"--project", "p", "--format", "pdf", "--dpi", "-1"},
+		{"--project", "p", "--format", "pdf", "extra"},
+		{"--project", "p", "--bogus"},
+	}
+	for _, args := range bad {
+		if _, err := ParseExportFlags(args); !errors.Is(err, ErrUsage) {
+			t.Errorf("%v: want ErrUsage, got %v", args, err)
+		}
+	}
+	if _, err := ParseExportFlags([]string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("-h: want flag.ErrHelp, got %v", err)
+	}
+}
+
+func TestExportFlagsPreset(t *testing.T) {
+	ph := &storage.ProjectHandle{Root: t.TempDir(), Project: cliTestProject()}
+
+	f, _ := ParseExportFlags([]string{"--project", "p", "--preset", "Web", "--dpi", "100"})
+	p, err := f.ExportPreset(ph)
+	if err != nil {
+		t.Fatalf("preset: %v", err)
+	}
+	if p.Format != "png" || p.DPI != 100 || p.Pages != "2" || !p.IncludeGuides {
+		t.Fatalf("flags should override only what was given: %+v", p)
+	}
+	f, _ = ParseExportFlags([]string{"--project", "p", "--preset", "Web", "--guides=false", "--format", "epub", "--reflowable"})
+	p, err = f.ExportPreset(ph)
+	if err != nil {
+		t.Fatalf("preset: %v", err)
+	}
+	if p.IncludeGuides || p.Format != "epub" || p.EPUB == nil || !p.EPUB.Reflowable {
+		t.Fatalf("unexpected preset: %+v", p)
+	}
+
+	for _, args := range [][]string{
+		{"--project", "p", "--preset", "Missing"},
+		{"--project", "p", "--format", "tiff"},
+		{"--project", "p", "--format", "pdf", "--pages", "9"},
+		{"--project", "p", "--format", "pdf", "--issue", "2"},
+	} {
+		f, err := ParseExportFlags(args)
+		if err != nil {
+			t.Fatalf("%v: parse: %v", args, err)
+		}
+		if _, err := f.ExportPreset(ph); !errors.Is(err, ErrUsage) {
+			t.Errorf("%v: want ErrUsage, got %v", args, err)
+		}
+	}
+}
+
+func TestExportFlagsOutPath(t *testing.T) {
+	root := "proj"
+	f := ExportFlags{Issue: 2}
+	if got, _ := f.OutPath(root, "cbz"); got != filepath.Join(root, "exports", "issue-2.cbz") {
+		t.Errorf("default file: %s", got)
+	}
+	if got, _ := f.OutPath(root, "png"); got != filepath.Join(root, "exports", "issue-2-png") {
+		t.Errorf("default folder: %s", got)
+	}
+	f.Out = "nightly.cbz"
+	got, _ := f.OutPath(root, "cbz")
+	if !filepath.IsAbs(got) || filepath.Base(got) != "nightly.cbz" {
+		t.Errorf("--out should be made absolute: %s", got)
+	}
+}
+
+func TestRunExport(t *testing.T) {
+	root := t.TempDir()
+	if _, err := storage.InitProject(root, cliTestProject()); err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	out := filepath.Join(t.TempDir(), "nightly.cbz")
+
+	var stderr bytes.Buffer
+	code := RunExport([]string{"--project", root, "--format", "cbz", "--out", out, "--pages", "1-2", "--dpi", "50"}, &stderr)
+	if code != ExitOK {
+		t.Fatalf("exit %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "exporting issue 1 as cbz (pages 1-2)") {
+		t.Errorf("missing progress line: %s", stderr.String())
+	}
+	zr, err := zip.OpenReader(out)
+	if err != nil {
+		t.Fatalf("open cbz: %v", err)
+	}
+	images := 0
+	for _, zf := range zr.File {
+		if strings.HasSuffix(zf.Name, ".png") {
+			images++
+		}
+	}
+	_ = zr.Close()
+	if images != 2 {
+		t.Errorf("want 2 page images, got %d", images)
+	}
+
+	code = RunExport([]string{"--project", root, "--preset", "Web"}, &stderr)
+	if code != ExitOK {
+		t.Fatalf("preset export: exit %d: %s", code, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(root, "exports", "issue-1-png")); err != nil {
+		t.Errorf("default output folder: %v", err)
+	}
+
+	cases := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"--project", filepath.Join(root, "missing"), "--format", "pdf"}, ExitProjectNotFound},
+		{[]string{"--project", root, "--format", "tiff"}, ExitInvalidOptions},
+		{[]string{"--project", root, "--format", "pdf", "--pages", "7"}, ExitInvalidOptions},
+		{[]string{"--format", "pdf"}, ExitInvalidOptions},
+		{[]string{"--help"}, ExitOK},
+		// The parent of the output is a file, so the exporter cannot create it.
+		{[]string{"--project", root, "--format", "pdf", "--out", filepath.Join(out, "x.pdf")}, ExitExportFailed},
+	}
+	for _, c := range cases {
+		stderr.Reset()
+		if got := RunExport(c.args, &stderr); got != c.want {
+			t.Errorf("%v: exit %d, want %d (%s)", c.args, got, c.want, stderr.String())
+		}
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -38,8 +39,38 @@ type BackendConfig struct {
 
 type GeneralConfig struct {
 	TelemetryOptIn bool   `yaml:"telemetry_opt_in"`
-	Theme          string `yaml:"theme"` // "system" | "light" | "dark" (informational for now)
+	Theme          string `yaml:"theme"` // ThemeSystem, ThemeLight or ThemeDark
 	EnableServer   bool   `yaml:"enable_server"`
+	Language       string `yaml:"language"` // UI language such as "de"; "" or "auto" follows the system
+	Units          string `yaml:"units"`    // UnitsMM or UnitsInch for size fields; lengths are stored in points either way
+}
+
+// UI theme settings; anything else follows the system like ThemeSystem.
+const (
+	ThemeSystem = "system"
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+)
+
+// Length units for size entry fields and labels; anything else is treated like UnitsMM.
+const (
+	UnitsMM   = "mm"
+	UnitsInch = "in"
+)
+
+// CanvasConfig controls how the page canvas draws the area around the page and its guides. Colors are
+// #rrggbb; an empty or unreadable color uses the theme's default.
+type CanvasConfig struct {
+	HighContrast   bool   `yaml:"high_contrast"`   // thicker, fully opaque guide strokes
+	InvertSurround bool   `yaml:"invert_surround"` // a light surround in the dark theme, a dark one in the light theme
+	GuideColor     string `yaml:"guide_color"`     // snap guides shown while dragging
+	TrimColor      string `yaml:"trim_color"`
+	BleedColor     string `yaml:"bleed_color"`
+}
+
+// ExportConfig holds limits for raster exports.
+type ExportConfig struct {
+	MaxPixels int `yaml:"max_pixels"` // longest side of a rendered image; 0 uses the exporter's default
 }
 
 type LoggingConfig struct {
@@ -49,20 +80,35 @@ type LoggingConfig struct {
 	File   string `yaml:"file"`
 }
 
+// BackupsConfig controls retention of project backup and recovery files.
+type BackupsConfig struct {
+	CrashRetentionDays int `yaml:"crash_retention_days"` // crash autosave snapshots older than this are pruned
+	KeepLast           int `yaml:"keep_last"`            // newest manifest backups always kept
+	KeepDailyDays      int `yaml:"keep_daily_days"`      // beyond KeepLast, one backup per day is kept for this many days
+	FullEvery          int `yaml:"full_every"`           // every Nth manifest backup is a full copy, the rest are deltas
+	ScriptKeepLast     int `yaml:"script_keep_last"`     // script history snapshots kept at most
+	ScriptMaxAgeDays   int `yaml:"script_max_age_days"`  // script history snapshots older than this are pruned
+	IndexKeepLast      int `yaml:"index_keep_last"`      // search index snapshots taken before rebuilds kept at most
+}
+
 type AppConfig struct {
 	ConfigVersion int           `yaml:"config_version"`
 	General       GeneralConfig `yaml:"general"`
 	Backend       BackendConfig `yaml:"backend"`
 	Logging       LoggingConfig `yaml:"logging"`
+	Backups       BackupsConfig `yaml:"backups"`
+	Canvas        CanvasConfig  `yaml:"canvas"`
+	Export        ExportConfig  `yaml:"export"`
 }
 
 // Defaults returns the application defaults.
 func Defaults() AppConfig {
 	return AppConfig{
 		ConfigVersion: 1,
-		General:       GeneralConfig{TelemetryOptIn: false, Theme: "system", EnableServer: false},
+		General:       GeneralConfig{TelemetryOptIn: false, Theme: ThemeSystem, EnableServer: false, Units: UnitsMM},
 		Backend:       BackendConfig{BaseURL: "http://localhost:8080", TimeoutMs: 15000, TLSInsecure: false},
 		Logging:       LoggingConfig{Level: "info", Format: "console", Source: false, File: ""},
+		Backups:       BackupsConfig{CrashRetentionDays: 14, KeepLast: 50, KeepDailyDays: 30, FullEvery: 10, ScriptKeepLast: 200, ScriptMaxAgeDays: 90, IndexKeepLast: 5},
 	}
 }
 
@@ -215,6 +261,14 @@ func Save(cfg AppConfig, token string) error {
 	return nil
 }
 
+// SaveToken persists a backend token into the OS keyring, e.g. one the backend client has refreshed.
+func SaveToken(token string) error {
+	if token == "" {
+		return errors.New("empty token")
+	}
+	return tokenStore.Set(keyringService, keyringToken, token)
+}
+
 func mergeInto(dst *AppConfig, src *AppConfig) {
 	if src.ConfigVersion != 0 {
 		dst.ConfigVersion = src.ConfigVersion
@@ -222,6 +276,12 @@ func mergeInto(dst *AppConfig, src *AppConfig) {
 	if src.General.Theme != "" {
 		dst.General.Theme = src.General.Theme
 	}
+	if strings.TrimSpace(src.General.Language) != "" {
+		dst.General.Language = strings.TrimSpace(src.General.Language)
+	}
+	if u := strings.ToLower(strings.TrimSpace(src.General.Units)); u != "" {
+		dst.General.Units = u
+	}
 	// booleans: copy directly from src (file) so user preferences persist
 	dst.General.TelemetryOptIn = src.General.TelemetryOptIn
 	dst.General.EnableServer = src.General.EnableServer
@@ -243,6 +303,38 @@ func mergeInto(dst *AppConfig, src *AppConfig) {
 	if strings.TrimSpace(src.Logging.File) != "" {
 		dst.Logging.File = strings.TrimSpace(src.Logging.File)
 	}
+	// backups
+	if src.Backups.CrashRetentionDays > 0 {
+		dst.Backups.CrashRetentionDays = src.Backups.CrashRetentionDays
+	}
+	if src.Backups.KeepLast > 0 {
+		dst.Backups.KeepLast = src.Backups.KeepLast
+	}
+	if src.Backups.KeepDailyDays > 0 {
+		dst.Backups.KeepDailyDays = src.Backups.KeepDailyDays
+	}
+	if src.Backups.FullEvery > 0 {
+		dst.Backups.FullEvery = src.Backups.FullEvery
+	}
+	if src.Backups.ScriptKeepLast > 0 {
+		dst.Backups.ScriptKeepLast = src.Backups.ScriptKeepLast
+	}
+	if src.Backups.ScriptMaxAgeDays > 0 {
+		dst.Backups.ScriptMaxAgeDays = src.Backups.ScriptMaxAgeDays
+	}
+	if src.Backups.IndexKeepLast > 0 {
+		dst.Backups.IndexKeepLast = src.Backups.IndexKeepLast
+	}
+	// canvas
+	dst.Canvas.HighContrast = src.Canvas.HighContrast
+	dst.Canvas.InvertSurround = src.Canvas.InvertSurround
+	dst.Canvas.GuideColor = strings.TrimSpace(src.Canvas.GuideColor)
+	dst.Canvas.TrimColor = strings.TrimSpace(src.Canvas.TrimColor)
+	dst.Canvas.BleedColor = strings.TrimSpace(src.Canvas.BleedColor)
+	// export
+	if src.Export.MaxPixels > 0 {
+		dst.Export.MaxPixels = src.Export.MaxPixels
+	}
 }
 
 func applyEnvOverrides(cfg *AppConfig) {
@@ -325,6 +417,24 @@ func EnvOverrideFor(key string) (string, bool) {
 	return "", false
 }
 
+// CrashRetention returns how long crash autosave snapshots are kept (default 14 days).
+func (b BackupsConfig) CrashRetention() time.Duration {
+	days := b.CrashRetentionDays
+	if days <= 0 {
+		days = Defaults().Backups.CrashRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// ScriptMaxAge returns how long script history snapshots are kept (default 90 days).
+func (b BackupsConfig) ScriptMaxAge() time.Duration {
+	days := b.ScriptMaxAgeDays
+	if days <= 0 {
+		days = Defaults().Backups.ScriptMaxAgeDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
 // EffectiveTimeout returns the backend timeout as a duration-like milliseconds string for http.Client.
 func (b BackendConfig) EffectiveTimeout() string {
 	if b.TimeoutMs <= 0 {
@@ -13,6 +13,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestEnvOverridesBackendURL(t *testing.T) {
@@ -52,6 +53,22 @@ func TestMergeIncludesEnableServer(t *testing.T) {
 	}
 }
 
+func TestMergeIncludesLanguage(t *testing.T) {
+	dst := Defaults()
+	src := Defaults()
+	src.General.Language = " de "
+	mergeInto(&dst, &src)
+	if dst.General.Language != "de" {
+		t.Fatalf("Language not merged from file config: %q", dst.General.Language)
+	}
+	// An unset language keeps the previous value
+	src.General.Language = ""
+	mergeInto(&dst, &src)
+	if dst.General.Language != "de" {
+		t.Fatalf("empty Language overwrote the merged value: %q", dst.General.Language)
+	}
+}
+
 func TestMergeIncludesLogging(t *testing.T) {
 	dst := Defaults()
 	src := Defaults()
@@ -88,3 +105,109 @@ func TestEnvOverridesLogging(t *testing.T) {
 		t.Fatalf("env overrides not applied to logging: %#v", cfg.Logging)
 	}
 }
+
+func TestMergeIncludesBackupsRetention(t *testing.T) {
+	dst := Defaults()
+	if got := dst.Backups.CrashRetention(); got != 14*24*time.Hour {
+		t.Fatalf("default crash retention = %v", got)
+	}
+	src := Defaults()
+	src.Backups.CrashRetentionDays = 3
+	mergeInto(&dst, &src)
+	if dst.Backups.CrashRetention() != 3*24*time.Hour {
+		t.Fatalf("crash retention not merged: %#v", dst.Backups)
+	}
+	if dst.Backups.KeepLast != 50 || dst.Backups.KeepDailyDays != 30 {
+		t.Fatalf("unexpected backup retention defaults: %#v", dst.Backups)
+	}
+	src = AppConfig{Backups: BackupsConfig{KeepLast: 10}}
+	mergeInto(&dst, &src)
+	if dst.Backups.KeepLast != 10 || dst.Backups.KeepDailyDays != 30 || dst.Backups.FullEvery != 10 {
+		t.Fatalf("backup retention not merged: %#v", dst.Backups)
+	}
+	if dst.Backups.ScriptKeepLast != 200 || dst.Backups.ScriptMaxAge() != 90*24*time.Hour {
+		t.Fatalf("unexpected script history defaults: %#v", dst.Backups)
+	}
+	src = AppConfig{Backups: BackupsConfig{ScriptMaxAgeDays: 7}}
+	mergeInto(&dst, &src)
+	if dst.Backups.ScriptMaxAge() != 7*24*time.Hour || dst.Backups.ScriptKeepLast != 200 {
+		t.Fatalf("script history retention not merged: %#v", dst.Backups)
+	}
+	if dst.Backups.IndexKeepLast != 5 {
+		t.Fatalf("unexpected index snapshot default: %#v", dst.Backups)
+	}
+	src = AppConfig{Backups: BackupsConfig{IndexKeepLast: 2}}
+	mergeInto(&dst, &src)
+	if dst.Backups.IndexKeepLast != 2 || dst.Backups.ScriptKeepLast != 200 {
+		t.Fatalf("index snapshot retention not merged: %#v", dst.Backups)
+	}
+}
+
+func TestMergeIncludesCanvas(t *testing.T) {
+	dst := Defaults()
+	if dst.General.Theme != ThemeSystem || dst.Canvas != (CanvasConfig{}) {
+		t.Fatalf("unexpected defaults: theme %q, canvas %#v", dst.General.Theme, dst.Canvas)
+	}
+	src := Defaults()
+	src.General.Theme = ThemeDark
+	src.Canvas = CanvasConfig{HighContrast: true, InvertSurround: true, GuideColor: " #ff00ff ", TrimColor: "#ff0000"}
+	mergeInto(&dst, &src)
+	want := CanvasConfig{HighContrast: true, InvertSurround: true, GuideColor: "#ff00ff", TrimColor: "#ff0000"}
+	if dst.General.Theme != ThemeDark || dst.Canvas != want {
+		t.Fatalf("canvas not merged: theme %q, canvas %#v", dst.General.Theme, dst.Canvas)
+	}
+	// Clearing a color in the file restores the default
+	src.Canvas.TrimColor = ""
+	mergeInto(&dst, &src)
+	if dst.Canvas.TrimColor != "" {
+		t.Fatalf("cleared trim color kept: %#v", dst.Canvas)
+	}
+}
+
+func TestMergeIncludesUnitsAndExport(t *testing.T) {
+	dst := Defaults()
+	if dst.General.Units != UnitsMM || dst.Export.MaxPixels != 0 {
+		t.Fatalf("unexpected defaults: units %q, export %#v", dst.General.Units, dst.Export)
+	}
+	src := Defaults()
+	src.General.Units = " IN "
+	src.Export.MaxPixels = 12000
+	mergeInto(&dst, &src)
+	if dst.General.Units != UnitsInch || dst.Export.MaxPixels != 12000 {
+		t.Fatalf("units/export not merged: %q, %#v", dst.General.Units, dst.Export)
+	}
+	// An unset unit keeps the previous value
+	src.General.Units = ""
+	mergeInto(&dst, &src)
+	if dst.General.Units != UnitsInch {
+		t.Fatalf("empty Units overwrote the merged value: %q", dst.General.Units)
+	}
+}
+
+type memTokenStore map[string]string
+
+func (m memTokenStore) Get(service, key string) (string, error) { return m[service+"/"+key], nil }
+func (m memTokenStore) Set(service, key, value string) error {
+	m[service+"/"+key] = value
+	return nil
+}
+func (m memTokenStore) Delete(service, key string) error {
+	delete(m, service+"/"+key)
+	return nil
+}
+
+func TestSaveTokenUsesTokenStore(t *testing.T) {
+	prev := tokenStore
+	store := memTokenStore{}
+	tokenStore = store
+	defer func() { tokenStore = prev }()
+	if err := SaveToken("refreshed"); err != nil {
+		t.Fatalf("SaveToken: %v", err)
+	}
+	if got := store[keyringService+"/"+keyringToken]; got != "refreshed" {
+		t.Fatalf("stored token = %q", got)
+	}
+	if err := SaveToken(""); err == nil {
+		t.Fatal("expected an error for an empty token")
+	}
+}
@@ -22,14 +22,42 @@ type Project struct {
 	Issues   []Issue   `json:"issues"`
 	Bible    Bible     `json:"bible,omitempty"`
 	Comments []Comment `json:"comments,omitempty"`
+	// ExportPresets are named export settings shared by everyone working on the project.
+	ExportPresets []ExportPreset `json:"exportPresets,omitempty"`
+	// PageTemplates are named panel layouts that can be stamped onto pages.
+	PageTemplates []PageTemplate `json:"pageTemplates,omitempty"`
+	// IDCounters holds the last generated number per ID kind (panel, balloon, caption, sfx) so that IDs of deleted
+	// items are never handed out again.
+	IDCounters map[string]int `json:"idCounters,omitempty"`
+	// PanelBorder is the default frame of all panels; panels may override it field by field.
+	PanelBorder *PanelBorder `json:"panelBorder,omitempty"`
+	// DefaultStyles are the project's default lettering and guide colors, e.g. from a style pack palette.
+	DefaultStyles *DefaultStyles `json:"defaultStyles,omitempty"`
+	// BalloonStyles are named balloon presets offered when inserting a balloon.
+	BalloonStyles []BalloonStyle `json:"balloonStyles,omitempty"`
+}
+
+// DefaultStyles are project-wide default colors, typically applied from a style pack palette. Unset
+// colors use the exporters' built-in defaults. The panel stroke color is kept in Project.PanelBorder.
+type DefaultStyles struct {
+	Palette       string `json:"palette,omitempty"` // name of the palette applied last
+	BalloonFill   *Color `json:"balloonFill,omitempty"`
+	BalloonStroke *Color `json:"balloonStroke,omitempty"`
+	CaptionFill   *Color `json:"captionFill,omitempty"`
+	Guide         *Color `json:"guide,omitempty"`
 }
 
 // Metadata contains optional descriptive metadata for a project.
+// Genre, AgeRating, Web and LanguageISO go into ComicInfo.xml and the EPUB package metadata.
 type Metadata struct {
-	Series     string `json:"series,omitempty"`
-	IssueTitle string `json:"issueTitle,omitempty"`
-	Creators   string `json:"creators,omitempty"`
-	Notes      string `json:"notes,omitempty"`
+	Series      string `json:"series,omitempty"`
+	IssueTitle  string `json:"issueTitle,omitempty"`
+	Creators    string `json:"creators,omitempty"`
+	Notes       string `json:"notes,omitempty"`
+	Genre       string `json:"genre,omitempty"`
+	AgeRating   string `json:"ageRating,omitempty"`
+	Web         string `json:"web,omitempty"`         // http(s) URL of the series or publisher
+	LanguageISO string `json:"languageISO,omitempty"` // BCP 47 tag, e.g. en or de-DE
 }
 
 // Issue captures configuration that applies to the whole comic issue.
@@ -40,6 +68,18 @@ type Issue struct {
 	DPI              int     `json:"dpi"`
 	ReadingDirection string  `json:"readingDirection"` // ltr or rtl
 	Pages            []Page  `json:"pages"`
+	// FrontMatter optionally adds a title/credits page in front of page 1 in PDF, CBZ and EPUB exports.
+	FrontMatter *FrontMatter `json:"frontMatter,omitempty"`
+	// Number is the issue number shown in exports (e.g. "12" or "1.5"); empty means the issue's position.
+	Number string `json:"number,omitempty"`
+}
+
+// FrontMatter configures the synthesized title page of an issue's exports.
+type FrontMatter struct {
+	TitlePage bool `json:"titlePage"`
+	// Credits is the text under the title; {Series}, {IssueTitle} and {Creators} are replaced with the
+	// project metadata. Empty means "{Creators}".
+	Credits string `json:"credits,omitempty"`
 }
 
 // Page represents a single page in an issue.
@@ -49,8 +89,39 @@ type Page struct {
 	Panels []Panel `json:"panels"`
 	Layers []Layer `json:"layers,omitempty"`
 	Styles []Style `json:"styles,omitempty"`
+	// SpreadWith is the number of the adjacent page this page forms a double-page spread with
+	// (set on both pages), or 0 for a single page.
+	SpreadWith int `json:"spreadWith,omitempty"`
+	// ReferenceImage is an optional sketch or thumbnail shown under the panels while editing, or the
+	// page's finished art.
+	ReferenceImage *ReferenceImage `json:"referenceImage,omitempty"`
+	// Notes are production notes for the page as a whole, e.g. for the colorist or the letterer.
+	Notes string `json:"notes,omitempty"`
 }
 
+// ReferenceImage is a tracing aid drawn under a page's panels in the editor. Only art references are
+// exported: exporters draw them at full opacity under the panels and lettering, e.g. pages imported
+// from existing artwork. Opacity 0 means DefaultReferenceOpacity; a locked reference is not replaced,
+// changed or removed until it is unlocked.
+type ReferenceImage struct {
+	Asset   string  `json:"asset"` // path relative to the project root, e.g. assets/thumbs/p03.jpg
+	Opacity float64 `json:"opacity,omitempty"`
+	Fit     string  `json:"fit,omitempty"` // contain (default) | stretch | bleed
+	Locked  bool    `json:"locked,omitempty"`
+	Art     bool    `json:"art,omitempty"`
+}
+
+// Reference image fit modes: contain keeps the aspect ratio inside the page, stretch fills the page
+// and bleed fills the page including its bleed.
+const (
+	ReferenceFitContain = "contain"
+	ReferenceFitStretch = "stretch"
+	ReferenceFitBleed   = "bleed"
+)
+
+// DefaultReferenceOpacity is the opacity of a reference image that does not set one.
+const DefaultReferenceOpacity = 0.5
+
 // Layer can be used in later phases for ordering elements or grouping.
 type Layer struct {
 	ID    string `json:"id"`
@@ -66,17 +137,109 @@ type Panel struct {
 	ZOrder   int       `json:"zOrder"`
 	BeatIDs  []string  `json:"linkedBeats,omitempty"`
 	Balloons []Balloon `json:"balloons,omitempty"`
+	Captions []Caption `json:"captions,omitempty"`
+	SFX      []SFXItem `json:"sfx,omitempty"`
 	Notes    string    `json:"notes,omitempty"`
+	// Border overrides the project's panel border for this panel.
+	Border *PanelBorder `json:"border,omitempty"`
+	// Annotations track the art production of the panel, separate from the free-text Notes.
+	Annotations *PanelAnnotations `json:"annotations,omitempty"`
+	// Locked protects an approved panel: its geometry, stacking order, ID and notes cannot be changed
+	// and it cannot be deleted until it is unlocked.
+	Locked bool `json:"locked,omitempty"`
+}
+
+// PanelAnnotations is the production checklist of a panel. Status maps an art stage to done; stages
+// missing from it are not done. Due is an optional date in YYYY-MM-DD form.
+type PanelAnnotations struct {
+	Status   map[string]bool `json:"status,omitempty"`
+	Assignee string          `json:"assignee,omitempty"`
+	Due      string          `json:"due,omitempty"`
+}
+
+// Art stages of a panel's checklist, in production order.
+const (
+	ArtStageLayout  = "layout"
+	ArtStagePencils = "pencils"
+	ArtStageInks    = "inks"
+	ArtStageLetters = "letters"
+)
+
+// ArtStages lists the checklist stages in production order.
+var ArtStages = []string{ArtStageLayout, ArtStagePencils, ArtStageInks, ArtStageLetters}
+
+// PanelBorder styles the frame drawn around a panel. Unset fields inherit: a panel's border from the
+// project's PanelBorder, the project's from the exporter's default of a solid 1pt black line.
+type PanelBorder struct {
+	Color *Color  `json:"color,omitempty"`
+	Width float64 `json:"width,omitempty"` // points; 0 inherits
+	Style string  `json:"style,omitempty"` // solid | none; empty inherits
+}
+
+// Panel border styles: none draws no frame at all, e.g. for borderless panels bleeding off the page.
+const (
+	PanelBorderSolid = "solid"
+	PanelBorderNone  = "none"
+)
+
+// BalloonStyle is a named balloon preset. Inserting a balloon with a preset copies its shape, font and
+// size into the balloon and sets Balloon.StyleRef to the preset's name; exporters stroke, fill and pad
+// the balloon by the preset its StyleRef names. Unset colors, width and stroke style use the project's
+// default styles.
+type BalloonStyle struct {
+	Name        string  `json:"name"`
+	Shape       string  `json:"shape,omitempty"`  // ellipse | roundedBox | rect | burst; empty is ellipse
+	Radius      float64 `json:"radius,omitempty"` // corner radius of a roundedBox, in points
+	Stroke      *Color  `json:"stroke,omitempty"`
+	StrokeWidth float64 `json:"strokeWidth,omitempty"` // points; 0 uses the default
+	StrokeStyle string  `json:"strokeStyle,omitempty"` // solid | dashed | none; empty is solid
+	Fill        *Color  `json:"fill,omitempty"`
+	Font        string  `json:"font,omitempty"`
+	Size        float64 `json:"size,omitempty"`    // font size in points; 0 is 12
+	Padding     float64 `json:"padding,omitempty"` // points between outline and text; 0 is 6
+}
+
+// Balloon stroke styles: dashed draws the outline in dashes, e.g. for whispers, and none leaves it out.
+const (
+	BalloonStrokeSolid  = "solid"
+	BalloonStrokeDashed = "dashed"
+	BalloonStrokeNone   = "none"
+)
+
+// Caption is a rectangular narration box placed within a panel.
+// ScriptLine optionally links the caption to a CAPTION/NARRATION line of the script (see storage.CaptionLineIDFor).
+type Caption struct {
+	ID         string  `json:"id"`
+	Text       string  `json:"text"`
+	Font       string  `json:"font,omitempty"`
+	Size       float64 `json:"size,omitempty"`
+	Rect       Rect    `json:"rect"`
+	Rotation   float64 `json:"rotation,omitempty"` // degrees, clockwise
+	ScriptLine string  `json:"scriptLine,omitempty"`
+	StyleRef   string  `json:"styleRef,omitempty"`
+}
+
+// SFXItem is free-floating sound-effect lettering (e.g., "KRAKOOM") placed within a panel.
+type SFXItem struct {
+	ID       string  `json:"id"`
+	Text     string  `json:"text"`
+	Font     string  `json:"font,omitempty"` // font hint
+	Size     float64 `json:"size,omitempty"`
+	Rect     Rect    `json:"rect"`
+	Rotation float64 `json:"rotation,omitempty"` // degrees, clockwise
+	StyleRef string  `json:"styleRef,omitempty"`
 }
 
 // Balloon is a lettering element (speech, caption, SFX, etc.).
 type Balloon struct {
-	ID       string    `json:"id"`
-	Type     string    `json:"type"` // speech, whisper, thought, caption, sfx
-	TextRuns []TextRun `json:"textRuns"`
-	Shape    Shape     `json:"shape"`
-	Tail     Tail      `json:"tail,omitempty"`
-	StyleRef string    `json:"styleRef,omitempty"`
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`                // speech, whisper, thought, caption, sfx
+	Character string    `json:"character,omitempty"` // speaking character (bible name), optional
+	TextRuns  []TextRun `json:"textRuns"`
+	Shape     Shape     `json:"shape"`
+	Tail      Tail      `json:"tail,omitempty"`
+	StyleRef  string    `json:"styleRef,omitempty"`
+	Order     int       `json:"order,omitempty"` // reading position in the panel from 1; 0 (older manifests) reads in slice order
 }
 
 // TextRun represents a run of text with typography settings.
@@ -153,6 +316,8 @@ type Bible struct {
 	Characters []BibleCharacter `json:"characters,omitempty"`
 	Locations  []BibleLocation  `json:"locations,omitempty"`
 	Tags       []BibleTag       `json:"tags,omitempty"`
+	// IgnoredCharacters are speakers accepted in dialogue without a bible entry (one-off extras).
+	IgnoredCharacters []string `json:"ignoredCharacters,omitempty"`
 }
 
 // BibleCharacter stores a character entry for the script editor.
@@ -161,6 +326,8 @@ type BibleCharacter struct {
 	Aliases []string `json:"aliases,omitempty"`
 	Tags    []string `json:"tags,omitempty"`
 	Notes   string   `json:"notes,omitempty"`
+	// Portrait is an optional image asset path relative to the project root, e.g. assets/alice.png.
+	Portrait string `json:"portrait,omitempty"`
 }
 
 // BibleLocation stores a location entry.
@@ -217,3 +384,36 @@ type Comment struct {
 	CreatedAt  time.Time     `json:"createdAt"`
 	ResolvedAt *time.Time    `json:"resolvedAt,omitempty"`
 }
+
+// ExportPreset is a named set of export settings stored with the project.
+// Fields that do not apply to Format are ignored by the exporter (e.g. DPI for PDF, EPUB for CBZ).
+type ExportPreset struct {
+	Name          string        `json:"name"`
+	Format        string        `json:"format"` // pdf | png | svg | cbz | epub
+	DPI           int           `json:"dpi,omitempty"`
+	IncludeGuides bool          `json:"includeGuides,omitempty"`
+	Pages         string        `json:"pages,omitempty"`     // page numbers, e.g. "1-3,5"; empty means all pages
+	PageOrder     string        `json:"pageOrder,omitempty"` // cbz/epub: reading | selection; empty means reading order for RTL issues
+	EPUB          *EPUBMetadata `json:"epub,omitempty"`
+}
+
+// PageTemplate is a named panel layout. Panels holds one rectangle per panel in zOrder, as fractions of
+// the issue's trim box (0,0 is the top-left trim corner, 1,1 the bottom-right one), so the layout fits any
+// trim size. Values below 0 or above 1 reach into the bleed.
+type PageTemplate struct {
+	Name   string `json:"name"`
+	Panels []Rect `json:"panels"`
+}
+
+// EPUBMetadata overrides the package metadata written by the EPUB exporter.
+// Empty fields fall back to the project metadata or exporter defaults.
+type EPUBMetadata struct {
+	Title       string `json:"title,omitempty"`
+	Author      string `json:"author,omitempty"`
+	Language    string `json:"language,omitempty"`
+	Publisher   string `json:"publisher,omitempty"`
+	Description string `json:"description,omitempty"`
+	Series      string `json:"series,omitempty"`
+	SeriesIndex int    `json:"seriesIndex,omitempty"`
+	Reflowable  bool   `json:"reflowable,omitempty"`
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany..
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package domain
+
+import "math"
+
+// Lengths in the manifest are points (1/72 inch); these helpers convert them to and from the units
+// people type and read, and to pixels at a raster resolution.
+const (
+	PointsPerInch = 72.0
+	MMPerInch     = 25.4
+)
+
+// MMToPoints converts millimetres to points.
+func MMToPoints(mm float64) float64 { return mm * PointsPerInch / MMPerInch }
+
+// PointsToMM converts points to millimetres.
+func PointsToMM(pt float64) float64 { return pt * MMPerInch / PointsPerInch }
+
+// InchesToPoints converts inches to points.
+func InchesToPoints(in float64) float64 { return in * PointsPerInch }
+
+// PointsToInches converts points to inches.
+func PointsToInches(pt float64) float64 { return pt / PointsPerInch }
+
+// PointsToPixels is the length of pt points in whole pixels at dpi, rounded as the exporters size
+// their rasters.
+func PointsToPixels(pt float64, dpi int) int {
+	return int(math.Round(pt * float64(dpi) / PointsPerInch))
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUnitConversions(t *testing.T) {
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	if got := MMToPoints(25.4); got != 72 {
+		t.Errorf("MMToPoints(25.4) = %g", got)
+	}
+	if got := InchesToPoints(6.625); got != 477 {
+		t.Errorf("InchesToPoints(6.625) = %g", got)
+	}
+	if got := PointsToInches(9); got != 0.125 {
+		t.Errorf("PointsToInches(9) = %g", got)
+	}
+	// in -> pt -> mm and back
+	if got := PointsToMM(InchesToPoints(1)); !near(got, 25.4) {
+		t.Errorf("1 in = %g mm", got)
+	}
+	if got := PointsToInches(MMToPoints(50.8)); !near(got, 2) {
+		t.Errorf("50.8 mm = %g in", got)
+	}
+	if got := PointsToMM(MMToPoints(3.175)); !near(got, 3.175) {
+		t.Errorf("mm round trip = %g", got)
+	}
+}
+
+func TestPointsToPixels(t *testing.T) {
+	cases := []struct {
+		pt   float64
+		dpi  int
+		want int
+	}{
+		{72, 300, 300},
+		{477 + 2*9, 300, 2063}, // 6.625 in trim plus 0.125 in bleed per side: 6.875 in
+		{MMToPoints(210), 300, 2480},
+		{0.1, 300, 0},
+	}
+	for _, c := range cases {
+		if got := PointsToPixels(c.pt, c.dpi); got != c.want {
+			t.Errorf("PointsToPixels(%g, %d) = %d, want %d", c.pt, c.dpi, got, c.want)
+		}
+	}
+}
@@ -12,11 +12,7 @@ import (
 	"archive/zip"
 	"bytes"
 	"fmt"
-	"image"
-	"image/color"
-	"image/draw"
 	"image/png"
-	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -37,65 +33,52 @@ type CBZOptions struct {
 	BalloonStroke domain.Stroke
 	BalloonFill   domain.Color
 	Pages         []int
+	// PageOrder is one of the PageOrder constants; by default right-to-left issues are put in reading order.
+	PageOrder string
+	// NoReport skips the <output>.export.json report and the exports log entry.
+	NoReport bool
 }
 
 // ExportIssueCBZ packages selected issue pages as PNG images into a CBZ (ZIP) archive
 // and adds a ComicInfo.xml metadata manifest for reader compatibility.
-func ExportIssueCBZ(ph *storage.ProjectHandle, issueIndex int, outPath string, opt CBZOptions) error {
+func ExportIssueCBZ(ph *storage.ProjectHandle, issueIndex int, outPath string, opt CBZOptions) (err error) {
 	if ph == nil {
 		return fmt.Errorf("project handle is nil")
 	}
+	// Ensure output path is under project exports folder if relative, with a .cbz extension
+	outPath = exportOutPath(ph, outPath, ".cbz")
+	run := beginReport(ph, "cbz", issueIndex, opt.Pages, opt, outPath+ReportSuffix, opt.NoReport)
+	defer func() { err = run.finish([]string{outPath}, err) }()
 	if issueIndex < 0 || issueIndex >= len(ph.Project.Issues) {
 		return fmt.Errorf("issue index out of range")
 	}
+	if err := validateIssue(ph, issueIndex); err != nil {
+		return err
+	}
 	iss := ph.Project.Issues[issueIndex]
 
 	// Defaults
-	guideCol := opt.GuideColor
-	if guideCol.A == 0 && guideCol.R == 0 && guideCol.G == 0 && guideCol.B == 0 {
-		guideCol = domain.Color{R: 255, G: 0, B: 0, A: 255}
-	}
-	panelStroke := opt.PanelStroke
-	if panelStroke.Width == 0 {
-		panelStroke = domain.Stroke{Color: domain.Color{R: 0, G: 0, B: 0, A: 255}, Width: 1}
-	}
-	balloonStroke := opt.BalloonStroke
-	if balloonStroke.Width == 0 {
-		balloonStroke = domain.Stroke{Color: domain.Color{R: 0, G: 0, B: 0, A: 255}, Width: 1}
-	}
-	balloonFill := opt.BalloonFill
-	if balloonFill.A == 0 && balloonFill.R == 0 && balloonFill.G == 0 && balloonFill.B == 0 {
-		balloonFill = domain.Color{R: 255, G: 255, B: 255, A: 255}
-	}
+	sty := resolveExportStyle(ph.Project, opt.GuideColor, opt.PanelStroke, opt.BalloonStroke, opt.BalloonFill)
 	// DPI
-	dpi := iss.DPI
-	if opt.DPI > 0 {
-		dpi = opt.DPI
-	}
-	if dpi <= 0 {
-		dpi = 300
-	}
+	dpi := exportDPI(iss, opt.DPI)
+	run.setDPI(dpi)
 
 	trimW := iss.TrimWidth
 	trimH := iss.TrimHeight
 	bleed := iss.Bleed
-	mediaW := trimW + 2*bleed
-	mediaH := trimH + 2*bleed
 
-	// Calculate pixel dimensions from points (1pt = 1/72")
-	scale := float64(dpi) / 72.0
-	pixW := int(math.Round(mediaW * scale))
-	pixH := int(math.Round(mediaH * scale))
-	bx := int(math.Round(bleed * scale))
-	by := int(math.Round(bleed * scale))
+	scale := pixelsPerPoint(dpi)
+	st := newRasterStyle(ph.Project, sty, opt.IncludeGuides)
+	art := newPageArt(ph, run)
 
-	// Ensure output path is under project exports folder if relative
-	if !filepath.IsAbs(outPath) {
-		outPath = filepath.Join(ph.Root, "exports", outPath)
+	pidxs, err := orderPages(iss, pageIndexes(len(iss.Pages), opt.Pages), opt.PageOrder)
+	if err != nil {
+		return err
 	}
-	// Enforce .cbz extension
-	if !strings.HasSuffix(strings.ToLower(outPath), ".cbz") {
-		outPath = outPath + ".cbz"
+	// A spread becomes one double-wide image, marked as such in ComicInfo.xml
+	sheets := issueSheets(iss, pidxs)
+	if err := checkSheetPixels(iss, sheets, dpi); err != nil {
+		return err
 	}
 
 	// Create ZIP writer
@@ -105,10 +88,9 @@ func ExportIssueCBZ(ph *storage.ProjectHandle, issueIndex int, outPath string, o
 	}
 	defer func() { _ = f.Close() }()
 
-	pages := pageIndexes(len(iss.Pages), opt.Pages)
 	// Zero padding width based on count
 	pad := 3
-	if n := len(pages); n >= 1000 {
+	if n := len(sheets); n >= 1000 {
 		pad = 4
 	} else if n >= 100 {
 		pad = 3
@@ -119,46 +101,24 @@ func ExportIssueCBZ(ph *storage.ProjectHandle, issueIndex int, outPath string, o
 	}
 
 	imgBuf := &bytes.Buffer{}
-	for i, pidx := range pages {
-		if pidx < 0 || pidx >= len(iss.Pages) {
-			continue
+	pageCount := len(sheets)
+	var doublePages []int
+	if hasTitlePage(iss) {
+		// Named 0 so it sorts first while content pages keep their numbers
+		img := renderTitlePageImage(titlePageLines(ph.Project, issueIndex), trimW, trimH, bleed, scale)
+		if err := png.Encode(imgBuf, img); err != nil {
+			return fmt.Errorf("encode png: %w", err)
 		}
-		pg := iss.Pages[pidx]
-
-		img := image.NewRGBA(image.Rect(0, 0, pixW, pixH))
-		// Background white
-		draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{255, 255, 255, 255}}, image.Point{}, draw.Src)
-
-		// Guides
-		if opt.IncludeGuides {
-			gc := toRGBA(guideCol)
-			strokeRect(img, 0, 0, pixW-1, pixH-1, gc)
-			// trim box
-			strokeRect(img, bx, by, int(math.Round(trimW*scale))+bx-1, int(math.Round(trimH*scale))+by-1, gc)
+		if err := addZipFile(zw, fmt.Sprintf("%0*d.png", pad, 0), imgBuf.Bytes()); err != nil {
+			return fmt.Errorf("zip add title page: %w", err)
 		}
-
-		// Panels and balloons
-		pc := toRGBA(panelStroke.Color)
-		for _, pnl := range pg.Panels {
-			r := pnl.Geometry
-			x := int(math.Round((r.X + bleed) * scale))
-			y := int(math.Round((r.Y + bleed) * scale))
-			w := int(math.Round(r.Width * scale))
-			h := int(math.Round(r.Height * scale))
-			strokeRect(img, x, y, x+w-1, y+h-1, pc)
-
-			// Balloons
-			fc := toRGBA(balloonFill)
-			bc := toRGBA(balloonStroke.Color)
-			for _, b := range pnl.Balloons {
-				br := b.Shape.Rect
-				bxp := int(math.Round((br.X + bleed) * scale))
-				byp := int(math.Round((br.Y + bleed) * scale))
-				bw := int(math.Round(br.Width * scale))
-				bh := int(math.Round(br.Height * scale))
-				fillRect(img, bxp, byp, bxp+bw-1, byp+bh-1, fc)
-				strokeRect(img, bxp, byp, bxp+bw-1, byp+bh-1, bc)
-			}
+		pageCount++
+	}
+	for i, sh := range sheets {
+		img := renderSheetImage(iss, sh, scale, st, art)
+		if sh.spread() {
+			// ComicInfo image indexes are zero-based over the archive's images
+			doublePages = append(doublePages, pageCount-len(sheets)+i)
 		}
 
 		imgBuf.Reset()
@@ -172,7 +132,7 @@ func ExportIssueCBZ(ph *storage.ProjectHandle, issueIndex int, outPath string, o
 	}
 
 	// Add ComicInfo.xml manifest
-	manifest, merr := buildComicInfoXML(ph, issueIndex, len(pages))
+	manifest, merr := buildComicInfoXML(ph, issueIndex, pageCount, doublePages)
 	if merr != nil {
 		return fmt.Errorf("build manifest: %w", merr)
 	}
@@ -208,17 +168,13 @@ func addZipFile(zw *zip.Writer, name string, data []byte) error {
 	return err
 }
 
-func buildComicInfoXML(ph *storage.ProjectHandle, issueIndex, pageCount int) (string, error) {
+// buildComicInfoXML writes the ComicInfo.xml manifest. doublePages lists the zero-based indexes of
+// images showing a double-page spread.
+func buildComicInfoXML(ph *storage.ProjectHandle, issueIndex, pageCount int, doublePages []int) (string, error) {
 	proj := ph.Project
 	iss := proj.Issues[issueIndex]
-	series := proj.Metadata.Series
-	if series == "" {
-		series = proj.Name
-	}
-	title := proj.Metadata.IssueTitle
-	if title == "" {
-		title = fmt.Sprintf("Issue %d", issueIndex+1)
-	}
+	series := issueSeries(proj)
+	title := issueTitle(proj, issueIndex)
 	writer := proj.Metadata.Creators
 	summary := proj.Metadata.Notes
 	reading := iss.ReadingDirection
@@ -239,7 +195,7 @@ func buildComicInfoXML(ph *storage.ProjectHandle, issueIndex, pageCount int) (st
 	wf("<ComicInfo xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\">\n")
 	wf("  <Series>%s</Series>\n", xmlEsc(series))
 	wf("  <Title>%s</Title>\n", xmlEsc(title))
-	wf("  <Number>%d</Number>\n", issueIndex+1)
+	wf("  <Number>%s</Number>\n", xmlEsc(issueNumber(proj, issueIndex)))
 	wf("  <PageCount>%d</PageCount>\n", pageCount)
 	if writer != "" {
 		wf("  <Writer>%s</Writer>\n", xmlEsc(writer))
@@ -247,7 +203,27 @@ func buildComicInfoXML(ph *storage.ProjectHandle, issueIndex, pageCount int) (st
 	if summary != "" {
 		wf("  <Summary>%s</Summary>\n", xmlEsc(summary))
 	}
+	meta := proj.Metadata
+	if meta.Genre != "" {
+		wf("  <Genre>%s</Genre>\n", xmlEsc(meta.Genre))
+	}
+	if meta.Web != "" {
+		wf("  <Web>%s</Web>\n", xmlEsc(meta.Web))
+	}
+	if meta.LanguageISO != "" {
+		wf("  <LanguageISO>%s</LanguageISO>\n", xmlEsc(meta.LanguageISO))
+	}
 	wf("  <ReadingDirection>%s</ReadingDirection>\n", reading)
+	if meta.AgeRating != "" {
+		wf("  <AgeRating>%s</AgeRating>\n", xmlEsc(meta.AgeRating))
+	}
+	if len(doublePages) > 0 {
+		wf("  <Pages>\n")
+		for _, i := range doublePages {
+			wf("    <Page Image=\"%d\" DoublePage=\"true\"/>\n", i)
+		}
+		wf("  </Pages>\n")
+	}
 	wf("</ComicInfo>\n")
 	if werr != nil {
 		return "", fmt.Errorf("build xml: %w", werr)
@@ -13,6 +13,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"gocomicwriter/internal/domain"
@@ -106,6 +107,36 @@ func TestExportIssueCBZ(t *testing.T) {
 	}
 }
 
+func TestComicInfoMetadata(t *testing.T) {
+	ph := &storage.ProjectHandle{Project: domain.Project{
+		Name: "Meta",
+		Metadata: domain.Metadata{Series: "Moon & Back", Genre: "Sci-Fi", AgeRating: "Teen",
+			Web: "https://example.com/moon", LanguageISO: "de-DE"},
+		Issues: []domain.Issue{{Number: "1.5", Pages: []domain.Page{{Number: 1}}}},
+	}}
+	xml, err := buildComicInfoXML(ph, 0, 1, nil)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	for _, want := range []string{
+		"<Series>Moon &amp; Back</Series>", "<Number>1.5</Number>", "<Genre>Sci-Fi</Genre>",
+		"<Web>https://example.com/moon</Web>", "<LanguageISO>de-DE</LanguageISO>", "<AgeRating>Teen</AgeRating>",
+	} {
+		if !strings.Contains(xml, want) {
+			t.Errorf("ComicInfo.xml lacks %s:\n%s", want, xml)
+		}
+	}
+	ph.Project.Metadata = domain.Metadata{}
+	ph.Project.Issues[0].Number = ""
+	xml, err = buildComicInfoXML(ph, 0, 1, nil)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if !strings.Contains(xml, "<Number>1</Number>") || strings.Contains(xml, "<Genre>") || strings.Contains(xml, "<AgeRating>") {
+		t.Errorf("empty metadata written:\n%s", xml)
+	}
+}
+
 func contains(s, sub string) bool {
 	return len(s) >= len(sub) && (s == sub || (len(s) > len(sub) && (func() bool { return (string([]byte(s)[:len(sub)]) == sub) || contains(s[1:], sub) })()))
 }
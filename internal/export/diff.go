@@ -0,0 +1,295 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/render"
+	"gocomicwriter/internal/storage"
+)
+
+// DiffSummaryName is the summary file DiffIssueRender writes into its output folder.
+const DiffSummaryName = "diff.json"
+
+// Page states in a RenderDiff.
+const (
+	DiffUnchanged = "unchanged"
+	DiffChanged   = "changed"
+	DiffAdded     = "added"
+	DiffRemoved   = "removed"
+)
+
+// DiffOptions controls DiffIssueRender.
+// - DPI: when > 0 overrides the issue DPI; both versions are rendered at the same scale
+// - Threshold: largest per-channel difference (0-255) still counted as equal
+// - NoReport: skip the export report and the exports log entry
+type DiffOptions struct {
+	DPI       int
+	Threshold int
+	NoReport  bool
+}
+
+// PageDiff compares one page number of the two versions. The panel lists come from the manifests:
+// a panel is changed when any of its fields, balloons included, differ. Image is the file name of
+// the difference image in the output folder; unchanged pages have none.
+type PageDiff struct {
+	Page          int      `json:"page"`
+	Status        string   `json:"status"`
+	ChangedPixels int      `json:"changedPixels"`
+	ChangedPanels []string `json:"changedPanels,omitempty"`
+	AddedPanels   []string `json:"addedPanels,omitempty"`
+	RemovedPanels []string `json:"removedPanels,omitempty"`
+	Image         string   `json:"image,omitempty"`
+}
+
+// RenderDiff is the summary written to DiffSummaryName. Issue is numbered from 1; ChangedPages lists
+// every page that is not unchanged, in page order.
+type RenderDiff struct {
+	Issue        int        `json:"issue"`
+	DPI          int        `json:"dpi"`
+	ChangedPages []int      `json:"changedPages"`
+	Pages        []PageDiff `json:"pages"`
+}
+
+// diffHighlight marks changed pixels in difference images.
+var diffHighlight = color.RGBA{255, 0, 255, 255}
+
+// DiffIssueRender renders an issue of two versions of a project page by page with the export renderer
+// and writes a difference image per page into outDir: the new page faded, with every changed pixel in
+// magenta. Pages are matched by number; a page found in only one version is rendered with an "ADDED"
+// or "REMOVED" banner. The summary goes to DiffSummaryName. An issue missing from one version counts
+// as having no pages there. outDir is resolved against phNew like other exports.
+func DiffIssueRender(phOld, phNew *storage.ProjectHandle, issueIdx int, outDir string, opt DiffOptions) (diff *RenderDiff, err error) {
+	if phOld == nil || phNew == nil {
+		return nil, fmt.Errorf("project handle is nil")
+	}
+	outDir = exportOutPath(phNew, outDir, "")
+	var written []string
+	run := beginReport(phNew, "diff", issueIdx, nil, opt, pagesReportPath(outDir, issueIdx, "diff"), opt.NoReport)
+	defer func() { err = run.finish(written, err) }()
+	oldIss, hasOld := diffIssue(phOld, issueIdx)
+	newIss, hasNew := diffIssue(phNew, issueIdx)
+	if !hasOld && !hasNew {
+		return nil, fmt.Errorf("issue index out of range")
+	}
+	for _, v := range []struct {
+		ph  *storage.ProjectHandle
+		has bool
+	}{{phOld, hasOld}, {phNew, hasNew}} {
+		if !v.has {
+			continue
+		}
+		if err := validateIssue(v.ph, issueIdx); err != nil {
+			return nil, err
+		}
+	}
+	dpi := exportDPI(newIss, opt.DPI)
+	if !hasNew {
+		dpi = exportDPI(oldIss, opt.DPI)
+	}
+	run.setDPI(dpi)
+	scale := pixelsPerPoint(dpi)
+	for _, iss := range []domain.Issue{oldIss, newIss} {
+		single := make([]sheet, len(iss.Pages))
+		for i := range iss.Pages {
+			single[i] = sheet{left: i, right: -1}
+		}
+		if err := checkSheetPixels(iss, single, dpi); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return nil, fmt.Errorf("ensure out dir: %w", err)
+	}
+
+	oldArt, newArt := newPageArt(phOld, run), newPageArt(phNew, run)
+	oldSt, newSt := PreviewStyle(phOld.Project), PreviewStyle(phNew.Project)
+	renderPage := func(iss domain.Issue, i int, st render.Style, art *pageArt) *image.RGBA {
+		return renderSheetImage(iss, sheet{left: i, right: -1}, scale, st, art)
+	}
+	oldPages, newPages := pageIndexByNumber(oldIss), pageIndexByNumber(newIss)
+	diff = &RenderDiff{Issue: issueIdx + 1, DPI: dpi, ChangedPages: []int{}, Pages: []PageDiff{}}
+	for _, n := range unionPageNumbers(oldPages, newPages) {
+		oi, inOld := oldPages[n]
+		ni, inNew := newPages[n]
+		pd := PageDiff{Page: n, Status: DiffChanged}
+		var img *image.RGBA
+		switch {
+		case !inOld:
+			pd.Status = DiffAdded
+			pd.AddedPanels = panelIDs(newIss.Pages[ni])
+			img = renderPage(newIss, ni, newSt, newArt)
+			pd.ChangedPixels = img.Bounds().Dx() * img.Bounds().Dy()
+			drawDiffBanner(img, "ADDED", color.RGBA{0, 140, 0, 255})
+		case !inNew:
+			pd.Status = DiffRemoved
+			pd.RemovedPanels = panelIDs(oldIss.Pages[oi])
+			img = renderPage(oldIss, oi, oldSt, oldArt)
+			pd.ChangedPixels = img.Bounds().Dx() * img.Bounds().Dy()
+			drawDiffBanner(img, "REMOVED", color.RGBA{200, 0, 0, 255})
+		default:
+			pd.ChangedPanels, pd.AddedPanels, pd.RemovedPanels = diffPanels(oldIss.Pages[oi], newIss.Pages[ni])
+			img, pd.ChangedPixels = diffImages(renderPage(oldIss, oi, oldSt, oldArt), renderPage(newIss, ni, newSt, newArt), opt.Threshold)
+			if pd.ChangedPixels == 0 && !jsonDiffers(oldIss.Pages[oi], newIss.Pages[ni]) {
+				pd.Status = DiffUnchanged
+			}
+		}
+		if pd.Status != DiffUnchanged {
+			pd.Image = fmt.Sprintf("page-%02d-diff.png", n)
+			name := filepath.Join(outDir, pd.Image)
+			if err := writePNG(name, img); err != nil {
+				return nil, err
+			}
+			written = append(written, name)
+			diff.ChangedPages = append(diff.ChangedPages, n)
+		}
+		diff.Pages = append(diff.Pages, pd)
+	}
+
+	b, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode diff summary: %w", err)
+	}
+	name := filepath.Join(outDir, DiffSummaryName)
+	if err := os.WriteFile(name, b, 0o644); err != nil {
+		return nil, fmt.Errorf("write diff summary: %w", err)
+	}
+	written = append(written, name)
+	return diff, nil
+}
+
+// diffIssue returns issue issueIdx of ph, or false when the project has no such issue.
+func diffIssue(ph *storage.ProjectHandle, issueIdx int) (domain.Issue, bool) {
+	if issueIdx < 0 || issueIdx >= len(ph.Project.Issues) {
+		return domain.Issue{}, false
+	}
+	return ph.Project.Issues[issueIdx], true
+}
+
+// pageIndexByNumber maps the page numbers of iss to their index.
+func pageIndexByNumber(iss domain.Issue) map[int]int {
+	m := make(map[int]int, len(iss.Pages))
+	for i, pg := range iss.Pages {
+		m[pg.Number] = i
+	}
+	return m
+}
+
+// unionPageNumbers returns the page numbers of both maps in ascending order.
+func unionPageNumbers(a, b map[int]int) []int {
+	seen := make(map[int]bool, len(a)+len(b))
+	var out []int
+	for _, m := range []map[int]int{a, b} {
+		for n := range m {
+			if !seen[n] {
+				seen[n] = true
+				out = append(out, n)
+			}
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+func panelIDs(pg domain.Page) []string {
+	ids := make([]string, 0, len(pg.Panels))
+	for _, pn := range pg.Panels {
+		ids = append(ids, pn.ID)
+	}
+	return ids
+}
+
+// diffPanels compares the panels of two versions of a page by ID, in the order of the new page;
+// removed panels keep the order of the old one.
+func diffPanels(oldPg, newPg domain.Page) (changed, added, removed []string) {
+	old := make(map[string]domain.Panel, len(oldPg.Panels))
+	for _, pn := range oldPg.Panels {
+		old[pn.ID] = pn
+	}
+	kept := make(map[string]bool, len(newPg.Panels))
+	for _, pn := range newPg.Panels {
+		kept[pn.ID] = true
+		o, ok := old[pn.ID]
+		switch {
+		case !ok:
+			added = append(added, pn.ID)
+		case jsonDiffers(o, pn):
+			changed = append(changed, pn.ID)
+		}
+	}
+	for _, pn := range oldPg.Panels {
+		if !kept[pn.ID] {
+			removed = append(removed, pn.ID)
+		}
+	}
+	return changed, added, removed
+}
+
+// jsonDiffers reports whether a and b are stored differently in the manifest.
+func jsonDiffers(a, b any) bool {
+	ja, erra := json.Marshal(a)
+	jb, errb := json.Marshal(b)
+	return erra != nil || errb != nil || string(ja) != string(jb)
+}
+
+// diffImages returns the new image faded to a quarter of its contrast with the pixels that differ from
+// the old one by more than threshold in any channel painted magenta, and their count. Images of
+// different size are compared over both; pixels covered by only one of them count as changed.
+func diffImages(oldImg, newImg *image.RGBA, threshold int) (*image.RGBA, int) {
+	b := oldImg.Bounds().Union(newImg.Bounds())
+	out := image.NewRGBA(b)
+	changed := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			p := image.Pt(x, y)
+			inOld, inNew := p.In(oldImg.Bounds()), p.In(newImg.Bounds())
+			nc := color.RGBA{255, 255, 255, 255}
+			if inNew {
+				nc = newImg.RGBAAt(x, y)
+			}
+			if !inOld || !inNew || pixelDiffers(oldImg.RGBAAt(x, y), nc, threshold) {
+				out.SetRGBA(x, y, diffHighlight)
+				changed++
+				continue
+			}
+			out.SetRGBA(x, y, color.RGBA{fade(nc.R), fade(nc.G), fade(nc.B), 255})
+		}
+	}
+	return out, changed
+}
+
+func pixelDiffers(a, b color.RGBA, threshold int) bool {
+	d := func(x, y uint8) int {
+		if x > y {
+			return int(x - y)
+		}
+		return int(y - x)
+	}
+	return d(a.R, b.R) > threshold || d(a.G, b.G) > threshold || d(a.B, b.B) > threshold || d(a.A, b.A) > threshold
+}
+
+// fade moves a channel three quarters of the way to white.
+func fade(c uint8) uint8 { return 255 - (255-c)/4 }
+
+// drawDiffBanner draws a band across the top of img with text in white, sized to the image width.
+func drawDiffBanner(img *image.RGBA, text string, col color.RGBA) {
+	b := img.Bounds()
+	k := max(b.Dx()/(len(text)*7*3), 1) // the text spans about a third of the width
+	h := 13*k + 2*4*k
+	render.FillRect(img, b.Min.X, b.Min.Y, b.Max.X-1, b.Min.Y+h-1, col)
+	drawScaledText(img, b.Min.X+b.Dx()/2, b.Min.Y+4*k+10*k, text, color.RGBA{255, 255, 255, 255}, k)
+}
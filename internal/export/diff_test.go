@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"encoding/json"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func TestDiffIssueRender(t *testing.T) {
+	root := t.TempDir()
+	still := domain.Page{Number: 4, Panels: []domain.Panel{{ID: "s", Geometry: domain.Rect{X: 18, Y: 18, Width: 100, Height: 100}}}}
+	oldP := sampleProject()
+	oldP.Issues[0].Pages = append(oldP.Issues[0].Pages,
+		domain.Page{Number: 2, Panels: []domain.Panel{{ID: "gone", Geometry: domain.Rect{X: 18, Y: 18, Width: 200, Height: 200}}}}, still)
+	newP := sampleProject()
+	newP.Issues[0].Pages[0].Panels[0].Balloons[0].Shape.Rect.Y = 300
+	newP.Issues[0].Pages[0].Panels = append(newP.Issues[0].Pages[0].Panels, domain.Panel{ID: "p2", Geometry: domain.Rect{X: 200, Y: 400, Width: 100, Height: 100}})
+	newP.Issues[0].Pages = append(newP.Issues[0].Pages, still,
+		domain.Page{Number: 3, Panels: []domain.Panel{{ID: "new", Geometry: domain.Rect{X: 18, Y: 18, Width: 200, Height: 200}}}})
+	phOld := &storage.ProjectHandle{Root: root, Project: oldP}
+	phNew := &storage.ProjectHandle{Root: root, Project: newP}
+
+	out := filepath.Join(t.TempDir(), "diff")
+	d, err := DiffIssueRender(phOld, phNew, 0, out, DiffOptions{DPI: 36, NoReport: true})
+	if err != nil {
+		t.Fatalf("DiffIssueRender: %v", err)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(d.ChangedPages, want) {
+		t.Fatalf("changed pages = %v, want %v", d.ChangedPages, want)
+	}
+	want := []PageDiff{
+		{Page: 1, Status: DiffChanged, ChangedPanels: []string{"p1"}, AddedPanels: []string{"p2"}, Image: "page-01-diff.png"},
+		{Page: 2, Status: DiffRemoved, RemovedPanels: []string{"gone"}, Image: "page-02-diff.png"},
+		{Page: 3, Status: DiffAdded, AddedPanels: []string{"new"}, Image: "page-03-diff.png"},
+		{Page: 4, Status: DiffUnchanged},
+	}
+	for i, pd := range d.Pages {
+		if (pd.ChangedPixels > 0) != (pd.Status != DiffUnchanged) {
+			t.Errorf("page %d: %d changed pixels for status %s", pd.Page, pd.ChangedPixels, pd.Status)
+		}
+		pd.ChangedPixels = 0
+		if i >= len(want) || !reflect.DeepEqual(pd, want[i]) {
+			t.Errorf("page %d = %+v", pd.Page, pd)
+		}
+	}
+	if len(d.Pages) != len(want) {
+		t.Fatalf("got %d pages, want %d", len(d.Pages), len(want))
+	}
+
+	f, err := os.Open(filepath.Join(out, "page-01-diff.png"))
+	if err != nil {
+		t.Fatalf("open diff image: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	magenta := 0
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if r, g, bl, _ := img.At(x, y).RGBA(); r == 0xffff && g == 0 && bl == 0xffff {
+				magenta++
+			}
+		}
+	}
+	if magenta != d.Pages[0].ChangedPixels {
+		t.Errorf("magenta pixels = %d, summary says %d", magenta, d.Pages[0].ChangedPixels)
+	}
+	if _, err := os.Stat(filepath.Join(out, "page-04-diff.png")); !os.IsNotExist(err) {
+		t.Errorf("unchanged page should have no diff image")
+	}
+
+	b2, err := os.ReadFile(filepath.Join(out, DiffSummaryName))
+	if err != nil {
+		t.Fatalf("read summary: %v", err)
+	}
+	var sum RenderDiff
+	if err := json.Unmarshal(b2, &sum); err != nil || !reflect.DeepEqual(&sum, d) {
+		t.Fatalf("summary = %+v (%v), want %+v", sum, err, d)
+	}
+}
+
+func TestDiffImagesThreshold(t *testing.T) {
+	iss := sampleProject().Issues[0]
+	a := renderSheetImage(iss, sheet{left: 0, right: -1}, 0.5, PreviewStyle(sampleProject()), nil)
+	b := renderSheetImage(iss, sheet{left: 0, right: -1}, 0.5, PreviewStyle(sampleProject()), nil)
+	if _, n := diffImages(a, b, 0); n != 0 {
+		t.Fatalf("identical renders differ in %d pixels", n)
+	}
+	p := b.RGBAAt(0, 0)
+	p.R -= 10
+	b.SetRGBA(0, 0, p)
+	if _, n := diffImages(a, b, 16); n != 0 {
+		t.Fatalf("difference below the threshold counted: %d", n)
+	}
+	if _, n := diffImages(a, b, 0); n != 1 {
+		t.Fatalf("changed pixels = %d, want 1", n)
+	}
+}
@@ -12,13 +12,10 @@ import (
 	"archive/zip"
 	"bytes"
 	"fmt"
-	"image"
-	"image/color"
-	"image/draw"
 	"image/png"
-	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,7 +24,8 @@ import (
 )
 
 // EPUBOptions controls EPUB export behavior.
-// Minimal set for MVP fixed-layout EPUB.
+// FixedLayout produces pre-paginated page images; otherwise a reflowable EPUB with
+// real text per page (panel notes, captions, attributed dialogue, SFX) is written.
 //
 //nolint:revive // clarity
 type EPUBOptions struct {
@@ -42,26 +40,36 @@ type EPUBOptions struct {
 	Series        string
 	SeriesIndex   int
 	CoverIndex    int  // page index to use as cover; -1 => first page
-	FixedLayout   bool // default true
+	FixedLayout   bool // pre-paginated page images; false => reflowable text
+	// PageOrder is one of the PageOrder constants; by default right-to-left issues are put in reading order.
+	PageOrder string
+	// NoReport skips the <output>.export.json report and the exports log entry.
+	NoReport bool
 }
 
-// ExportIssueEPUB exports the specified issue as an EPUB 3 fixed-layout package.
-func ExportIssueEPUB(ph *storage.ProjectHandle, issueIndex int, outPath string, opt EPUBOptions) error {
+// ExportIssueEPUB exports the specified issue as an EPUB 3 package (fixed-layout or reflowable).
+func ExportIssueEPUB(ph *storage.ProjectHandle, issueIndex int, outPath string, opt EPUBOptions) (err error) {
 	if ph == nil {
 		return fmt.Errorf("project handle is nil")
 	}
+	// Resolve output path
+	outPath = exportOutPath(ph, outPath, ".epub")
+	run := beginReport(ph, "epub", issueIndex, opt.Pages, opt, outPath+ReportSuffix, opt.NoReport)
+	defer func() { err = run.finish([]string{outPath}, err) }()
 	if issueIndex < 0 || issueIndex >= len(ph.Project.Issues) {
 		return fmt.Errorf("issue index out of range")
 	}
+	if err := validateIssue(ph, issueIndex); err != nil {
+		return err
+	}
 	iss := ph.Project.Issues[issueIndex]
 
 	// Defaults
 	if opt.Language == "" {
-		opt.Language = "en"
+		opt.Language = ph.Project.Metadata.LanguageISO
 	}
-	if opt.FixedLayout == false {
-		// default to fixed layout
-		opt.FixedLayout = true
+	if opt.Language == "" {
+		opt.Language = "en"
 	}
 	if opt.CoverIndex < 0 {
 		opt.CoverIndex = 0
@@ -89,12 +97,19 @@ func ExportIssueEPUB(ph *storage.ProjectHandle, issueIndex int, outPath string,
 		opt.Description = proj.Metadata.Notes
 	}
 
-	// Resolve output path
-	if !filepath.IsAbs(outPath) {
-		outPath = filepath.Join(ph.Root, "exports", outPath)
+	pages, err := orderPages(iss, pageIndexes(len(iss.Pages), opt.Pages), opt.PageOrder)
+	if err != nil {
+		return err
 	}
-	if !strings.HasSuffix(strings.ToLower(outPath), ".epub") {
-		outPath += ".epub"
+	dpi := exportDPI(iss, opt.DPI)
+	run.setDPI(dpi)
+	// Pages are rendered one by one, spreads included
+	single := make([]sheet, len(pages))
+	for i, pidx := range pages {
+		single[i] = sheet{left: pidx, right: -1}
+	}
+	if err := checkSheetPixels(iss, single, dpi); err != nil {
+		return err
 	}
 
 	// Prepare ZIP writer
@@ -128,43 +143,40 @@ func ExportIssueEPUB(ph *storage.ProjectHandle, issueIndex int, outPath string,
 	}
 
 	// 3) Render pages to PNG bytes and build page XHTML/nav/manifest
-	pages := pageIndexes(len(iss.Pages), opt.Pages)
 	if len(pages) == 0 {
 		_ = zw.Close()
 		return fmt.Errorf("no pages to export")
 	}
 	// determine pixel dimensions
-	dpi := iss.DPI
-	if opt.DPI > 0 {
-		dpi = opt.DPI
-	}
-	if dpi <= 0 {
-		dpi = 300
-	}
 	trimW := iss.TrimWidth
 	trimH := iss.TrimHeight
 	bleed := iss.Bleed
-	mediaW := trimW + 2*bleed
-	mediaH := trimH + 2*bleed
-	scale := float64(dpi) / 72.0
-	pixW := int(math.Round(mediaW * scale))
-	pixH := int(math.Round(mediaH * scale))
-	bx := int(math.Round(bleed * scale))
-	by := int(math.Round(bleed * scale))
+	scale := pixelsPerPoint(dpi)
 
 	// Styling defaults consistent with PNG/CBZ
-	guideCol := domain.Color{R: 255, G: 0, B: 0, A: 255}
-	panelStroke := domain.Stroke{Color: domain.Color{R: 0, G: 0, B: 0, A: 255}, Width: 1}
-	balloonStroke := domain.Stroke{Color: domain.Color{R: 0, G: 0, B: 0, A: 255}, Width: 1}
-	balloonFill := domain.Color{R: 255, G: 255, B: 255, A: 255}
+	st := newRasterStyle(ph.Project, resolveExportStyle(ph.Project, domain.Color{}, domain.Stroke{}, domain.Stroke{}, domain.Color{}), opt.IncludeGuides)
+	art := newPageArt(ph, run)
 
 	css := "html, body, .page { margin:0; padding:0; width:100%; height:100%; }\n" +
 		"img { width:100%; height:100%; object-fit:contain; }\n" +
 		"body { background:black; }\n"
+	// Reflowable text is set in the project's fonts, which travel along; fixed-layout pages are images
+	var fonts []epubFont
+	var fontClasses map[string]string
+	if !opt.FixedLayout {
+		fonts, fontClasses = epubFonts(newLetteringFonts(ph, run, "serif"), usedFontFamilies(iss, pages))
+		css = reflowCSS + epubFontCSS(fonts, fontClasses)
+	}
 	if err := addZipFile(zw, "OEBPS/styles/epub.css", []byte(css)); err != nil {
 		_ = zw.Close()
 		return fmt.Errorf("write css: %w", err)
 	}
+	for _, ef := range fonts {
+		if err := addZipFile(zw, "OEBPS/"+ef.href, ef.data); err != nil {
+			_ = zw.Close()
+			return fmt.Errorf("zip add font: %w", err)
+		}
+	}
 
 	pad := 1
 	if n := len(pages); n >= 1000 {
@@ -175,51 +187,56 @@ func ExportIssueEPUB(ph *storage.ProjectHandle, issueIndex int, outPath string,
 		pad = 2
 	}
 
+	// Fixed-layout readers pair spread pages side by side when their spine items carry page-spread properties
+	spreadSide := map[int]string{}
+	if opt.FixedLayout {
+		for _, sh := range issueSheets(iss, pages) {
+			if sh.spread() {
+				spreadSide[sh.left] = "page-spread-left"
+				spreadSide[sh.right] = "page-spread-right"
+			}
+		}
+	}
+
 	imgIDs := make([]string, 0, len(pages))
 	pageIDs := make([]string, 0, len(pages))
+	spineProps := make([]string, 0, len(pages))
 	navBuf := &bytes.Buffer{}
 	navBuf.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n")
 	navBuf.WriteString("<html xmlns=\"http://www.w3.org/1999/xhtml\" xmlns:epub=\"http://www.idpf.org/2007/ops\">\n<head><title>Table of Contents</title></head>\n<body>\n")
 	navBuf.WriteString("<nav epub:type=\"toc\" id=\"toc\"><ol>\n")
 
 	imgBuf := &bytes.Buffer{}
+	// Title page goes first in the spine under its own name so content pages keep their numbers
+	titlePage := hasTitlePage(iss)
+	if titlePage {
+		lines := titlePageLines(proj, issueIndex)
+		imgHref := ""
+		if opt.FixedLayout {
+			imgHref = "images/title.png"
+			if err := png.Encode(imgBuf, renderTitlePageImage(lines, trimW, trimH, bleed, scale)); err != nil {
+				_ = zw.Close()
+				return fmt.Errorf("encode png: %w", err)
+			}
+			if err := addZipFile(zw, "OEBPS/"+imgHref, imgBuf.Bytes()); err != nil {
+				_ = zw.Close()
+				return fmt.Errorf("zip add image: %w", err)
+			}
+		}
+		if err := addZipFile(zw, "OEBPS/title.xhtml", []byte(titlePageXHTML(lines, opt.Language, imgHref))); err != nil {
+			_ = zw.Close()
+			return fmt.Errorf("write title page: %w", err)
+		}
+		navBuf.WriteString(fmt.Sprintf("<li><a href=\"title.xhtml\">%s</a></li>\n", titlePageLabel))
+	}
 	for i, pidx := range pages {
 		if pidx < 0 || pidx >= len(iss.Pages) {
 			continue
 		}
 		pg := iss.Pages[pidx]
 
-		// render
-		img := image.NewRGBA(image.Rect(0, 0, pixW, pixH))
-		// background white
-		draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{255, 255, 255, 255}}, image.Point{}, draw.Src)
-		// guides
-		if opt.IncludeGuides {
-			gc := toRGBA(guideCol)
-			strokeRect(img, 0, 0, pixW-1, pixH-1, gc)
-			strokeRect(img, bx, by, int(math.Round(trimW*scale))+bx-1, int(math.Round(trimH*scale))+by-1, gc)
-		}
-		// panels & balloons
-		pc := toRGBA(panelStroke.Color)
-		for _, pnl := range pg.Panels {
-			r := pnl.Geometry
-			x := int(math.Round((r.X + bleed) * scale))
-			y := int(math.Round((r.Y + bleed) * scale))
-			w := int(math.Round(r.Width * scale))
-			h := int(math.Round(r.Height * scale))
-			strokeRect(img, x, y, x+w-1, y+h-1, pc)
-			fc := toRGBA(balloonFill)
-			bc := toRGBA(balloonStroke.Color)
-			for _, b := range pnl.Balloons {
-				br := b.Shape.Rect
-				bxp := int(math.Round((br.X + bleed) * scale))
-				byp := int(math.Round((br.Y + bleed) * scale))
-				bw := int(math.Round(br.Width * scale))
-				bh := int(math.Round(br.Height * scale))
-				fillRect(img, bxp, byp, bxp+bw-1, byp+bh-1, fc)
-				strokeRect(img, bxp, byp, bxp+bw-1, byp+bh-1, bc)
-			}
-		}
+		// Pages stay separate images; spreads are paired through page-spread properties in the spine
+		img := renderSheetImage(iss, sheet{left: pidx, right: -1}, scale, st, art)
 		imgBuf.Reset()
 		if err := png.Encode(imgBuf, img); err != nil {
 			_ = zw.Close()
@@ -234,8 +251,19 @@ func ExportIssueEPUB(ph *storage.ProjectHandle, issueIndex int, outPath string,
 		pageID := fmt.Sprintf("page-%0*d", pad, i+1)
 		imgIDs = append(imgIDs, imgID)
 		pageIDs = append(pageIDs, pageID)
+		spineProps = append(spineProps, spreadSide[pidx])
 
 		// page XHTML
+		if !opt.FixedLayout {
+			imgHref := fmt.Sprintf("images/page-%0*d.png", pad, i+1)
+			xhtml := reflowPageXHTML(pg, i+1, imgHref, opt.Language, isRTLDirection(iss.ReadingDirection), proj.Bible, fontClasses)
+			if err := addZipFile(zw, fmt.Sprintf("OEBPS/page-%0*d.xhtml", pad, i+1), []byte(xhtml)); err != nil {
+				_ = zw.Close()
+				return fmt.Errorf("write page xhtml: %w", err)
+			}
+			navBuf.WriteString(fmt.Sprintf("<li><a href=\"page-%0*d.xhtml\">Page %d</a></li>\n", pad, i+1, i+1))
+			continue
+		}
 		pageXHTML := fmt.Sprintf("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n"+
 			"<html xmlns=\"http://www.w3.org/1999/xhtml\">\n<head>\n"+
 			"<meta charset=\"utf-8\"/>\n"+
@@ -250,16 +278,24 @@ func ExportIssueEPUB(ph *storage.ProjectHandle, issueIndex int, outPath string,
 		}
 		navBuf.WriteString(fmt.Sprintf("<li><a href=\"page-%0*d.xhtml\">Page %d</a></li>\n", pad, i+1, i+1))
 	}
-	navBuf.WriteString("</ol></nav>\n</body>\n</html>\n")
+	navBuf.WriteString("</ol></nav>\n")
+	if titlePage {
+		navBuf.WriteString("<nav epub:type=\"landmarks\" id=\"landmarks\" hidden=\"hidden\"><ol>\n")
+		navBuf.WriteString(fmt.Sprintf("<li><a epub:type=\"titlepage\" href=\"title.xhtml\">%s</a></li>\n", titlePageLabel))
+		if len(pageIDs) > 0 {
+			navBuf.WriteString(fmt.Sprintf("<li><a epub:type=\"bodymatter\" href=\"%s.xhtml\">Start</a></li>\n", pageIDs[0]))
+		}
+		navBuf.WriteString("</ol></nav>\n")
+	}
+	navBuf.WriteString("</body>\n</html>\n")
 	if err := addZipFile(zw, "OEBPS/nav.xhtml", navBuf.Bytes()); err != nil {
 		_ = zw.Close()
 		return fmt.Errorf("write nav.xhtml: %w", err)
 	}
 
 	// 4) content.opf
-	reading := iss.ReadingDirection
 	ppd := "ltr"
-	if reading == "rtl" || strings.EqualFold(reading, "right-to-left") {
+	if isRTLDirection(iss.ReadingDirection) {
 		ppd = "rtl"
 	}
 	mod := time.Now().UTC().Format("2006-01-02T15:04:05Z")
@@ -281,6 +317,24 @@ func ExportIssueEPUB(ph *storage.ProjectHandle, issueIndex int, outPath string,
 	if strings.TrimSpace(opt.Description) != "" {
 		manifest.WriteString(fmt.Sprintf("    <dc:description>%s</dc:description>\n", xmlEsc(opt.Description)))
 	}
+	if strings.TrimSpace(opt.Series) != "" {
+		position := issueNumber(proj, issueIndex)
+		if opt.SeriesIndex > 0 {
+			position = strconv.Itoa(opt.SeriesIndex)
+		}
+		manifest.WriteString(fmt.Sprintf("    <meta property=\"belongs-to-collection\" id=\"series\">%s</meta>\n", xmlEsc(opt.Series)))
+		manifest.WriteString("    <meta refines=\"#series\" property=\"collection-type\">series</meta>\n")
+		manifest.WriteString(fmt.Sprintf("    <meta refines=\"#series\" property=\"group-position\">%s</meta>\n", xmlEsc(position)))
+	}
+	if g := strings.TrimSpace(proj.Metadata.Genre); g != "" {
+		manifest.WriteString(fmt.Sprintf("    <dc:subject>%s</dc:subject>\n", xmlEsc(g)))
+	}
+	if web := strings.TrimSpace(proj.Metadata.Web); web != "" {
+		manifest.WriteString(fmt.Sprintf("    <dc:relation>%s</dc:relation>\n", xmlEsc(web)))
+	}
+	if r := strings.TrimSpace(proj.Metadata.AgeRating); r != "" {
+		manifest.WriteString(fmt.Sprintf("    <meta property=\"schema:contentRating\">%s</meta>\n", xmlEsc(r)))
+	}
 	manifest.WriteString(fmt.Sprintf("    <meta property=\"dcterms:modified\">%s</meta>\n", mod))
 	if opt.FixedLayout {
 		manifest.WriteString("    <meta property=\"rendition:layout\">pre-paginated</meta>\n")
@@ -291,6 +345,15 @@ func ExportIssueEPUB(ph *storage.ProjectHandle, issueIndex int, outPath string,
 	manifest.WriteString("  <manifest>\n")
 	manifest.WriteString("    <item id=\"nav\" href=\"nav.xhtml\" media-type=\"application/xhtml+xml\" properties=\"nav\"/>\n")
 	manifest.WriteString("    <item id=\"css\" href=\"styles/epub.css\" media-type=\"text/css\"/>\n")
+	for _, ef := range fonts {
+		manifest.WriteString(fmt.Sprintf("    <item id=\"%s\" href=\"%s\" media-type=\"%s\"/>\n", ef.id, ef.href, epubFontMediaType(ef.font.Format)))
+	}
+	if titlePage {
+		if opt.FixedLayout {
+			manifest.WriteString("    <item id=\"img-title\" href=\"images/title.png\" media-type=\"image/png\"/>\n")
+		}
+		manifest.WriteString("    <item id=\"title\" href=\"title.xhtml\" media-type=\"application/xhtml+xml\"/>\n")
+	}
 	for i := range imgIDs {
 		manifest.WriteString(fmt.Sprintf("    <item id=\"%s\" href=\"images/page-%0*d.png\" media-type=\"image/png\"%s/>\n",
 			imgIDs[i], pad, i+1, func() string {
@@ -303,8 +366,15 @@ func ExportIssueEPUB(ph *storage.ProjectHandle, issueIndex int, outPath string,
 	}
 	manifest.WriteString("  </manifest>\n")
 	manifest.WriteString(fmt.Sprintf("  <spine page-progression-direction=\"%s\">\n", ppd))
+	if titlePage {
+		manifest.WriteString("    <itemref idref=\"title\"/>\n")
+	}
 	for i := range pageIDs {
-		manifest.WriteString(fmt.Sprintf("    <itemref idref=\"%s\"/>\n", pageIDs[i]))
+		props := ""
+		if spineProps[i] != "" {
+			props = fmt.Sprintf(" properties=\"%s\"", spineProps[i])
+		}
+		manifest.WriteString(fmt.Sprintf("    <itemref idref=\"%s\"%s/>\n", pageIDs[i], props))
 	}
 	manifest.WriteString("  </spine>\n")
 	manifest.WriteString("</package>\n")
@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// reflowCSS styles the text-first pages of a reflowable EPUB.
+const reflowCSS = "body { margin:1em; font-family:serif; line-height:1.4; }\n" +
+	"h1 { font-size:1.3em; }\n" +
+	"h2 { font-size:1.05em; margin-top:1.2em; }\n" +
+	"figure.page-art { margin:0 0 1em 0; }\n" +
+	"figure.page-art img { max-width:100%; height:auto; }\n" +
+	".description { font-style:italic; }\n" +
+	".caption { border-left:3px solid #999; padding-left:0.5em; }\n" +
+	".speaker { font-weight:bold; font-variant:small-caps; }\n" +
+	".sfx { font-weight:bold; text-transform:uppercase; }\n" +
+	".titlepage { text-align:center; margin-top:30%; }\n" +
+	".credits { margin:0.2em 0; }\n"
+
+// isRTLDirection reports whether an issue reading direction means right-to-left.
+func isRTLDirection(dir string) bool {
+	d := strings.TrimSpace(dir)
+	return strings.EqualFold(d, "rtl") || strings.EqualFold(d, "right-to-left")
+}
+
+// reflowItem is a text element inside a panel placed by its position for reading order.
+type reflowItem struct {
+	rect    domain.Rect
+	html    string
+	balloon int // reading position of a balloon from 1, 0 for captions and SFX
+}
+
+// reflowPageXHTML renders a page as semantic XHTML: the page image (optional art) followed by
+// one section per panel in reading order with notes, captions, dialogue and SFX as real text.
+// fontClasses maps lower-cased font families to the CSS classes of the embedded project fonts.
+func reflowPageXHTML(pg domain.Page, pageNo int, imgHref, lang string, rtl bool, bible domain.Bible, fontClasses map[string]string) string {
+	buf := &bytes.Buffer{}
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n")
+	buf.WriteString("<!DOCTYPE html>\n")
+	buf.WriteString(fmt.Sprintf("<html xmlns=\"http://www.w3.org/1999/xhtml\" xmlns:epub=\"http://www.idpf.org/2007/ops\" lang=\"%s\" xml:lang=\"%s\">\n", xmlEsc(lang), xmlEsc(lang)))
+	buf.WriteString("<head>\n<meta charset=\"utf-8\"/>\n")
+	buf.WriteString(fmt.Sprintf("<title>Page %d</title>\n", pageNo))
+	buf.WriteString("<link rel=\"stylesheet\" type=\"text/css\" href=\"styles/epub.css\"/>\n</head>\n<body>\n")
+	buf.WriteString(fmt.Sprintf("<section class=\"page\" epub:type=\"chapter\" aria-labelledby=\"page-title\">\n<h1 id=\"page-title\">Page %d</h1>\n", pageNo))
+	if imgHref != "" {
+		buf.WriteString(fmt.Sprintf("<figure class=\"page-art\"><img src=\"%s\" alt=\"Artwork for page %d; the panel text follows.\"/></figure>\n", xmlEsc(imgHref), pageNo))
+	}
+	byID := map[string]domain.Panel{}
+	for _, pn := range pg.Panels {
+		byID[pn.ID] = pn
+	}
+	for n, id := range storage.SpatialReadingOrder(pg, rtl) {
+		pn := byID[id]
+		buf.WriteString(fmt.Sprintf("<section class=\"panel\" id=\"panel-%d\" aria-label=\"Panel %d\">\n<h2>Panel %d</h2>\n", n+1, n+1, n+1))
+		if s := strings.TrimSpace(pn.Notes); s != "" {
+			buf.WriteString(fmt.Sprintf("<p class=\"description\">%s</p>\n", xmlEsc(s)))
+		}
+		for _, it := range panelReflowItems(pn, rtl, bible, fontClasses) {
+			buf.WriteString(it.html)
+		}
+		buf.WriteString("</section>\n")
+	}
+	buf.WriteString("</section>\n</body>\n</html>\n")
+	return buf.String()
+}
+
+// panelReflowItems returns caption, dialogue and SFX paragraphs of a panel sorted top-to-bottom,
+// then by reading direction. Once the panel's balloons have a reading order (domain.Balloon.Order)
+// they take the balloon places in that order. Text in an embedded project font carries its font class.
+func panelReflowItems(pn domain.Panel, rtl bool, bible domain.Bible, fontClasses map[string]string) []reflowItem {
+	var items []reflowItem
+	for _, c := range pn.Captions {
+		if s := strings.TrimSpace(c.Text); s != "" {
+			items = append(items, reflowItem{rect: c.Rect, html: fmt.Sprintf("<p class=\"%s\">%s</p>\n", fontClassAttr("caption", c.Font, fontClasses), xmlEsc(s))})
+		}
+	}
+	ordered := false
+	for _, b := range pn.Balloons {
+		ordered = ordered || b.Order > 0
+	}
+	numbers := storage.BalloonOrderNumbers(pn.Balloons)
+	for _, b := range pn.Balloons {
+		speaker, text := balloonSpeaker(b, bible)
+		if text == "" {
+			continue
+		}
+		var h string
+		font := balloonFont(b)
+		switch {
+		case b.Type == "caption":
+			h = fmt.Sprintf("<p class=\"%s\">%s</p>\n", fontClassAttr("caption", font, fontClasses), xmlEsc(text))
+		case b.Type == "sfx":
+			h = fmt.Sprintf("<p class=\"%s\">%s</p>\n", fontClassAttr("sfx", font, fontClasses), xmlEsc(text))
+		case speaker != "":
+			mode := ""
+			if b.Type == "whisper" || b.Type == "thought" {
+				mode = fmt.Sprintf(" (%s)", b.Type)
+			}
+			h = fmt.Sprintf("<p class=\"%s\"><span class=\"speaker\">%s</span>%s: %s</p>\n", fontClassAttr("dialogue", font, fontClasses), xmlEsc(speaker), xmlEsc(mode), xmlEsc(text))
+		default:
+			h = fmt.Sprintf("<p class=\"%s\">%s</p>\n", fontClassAttr("dialogue", font, fontClasses), xmlEsc(text))
+		}
+		items = append(items, reflowItem{rect: b.Shape.Rect, html: h, balloon: numbers[b.ID]})
+	}
+	for _, fx := range pn.SFX {
+		if s := strings.TrimSpace(fx.Text); s != "" {
+			items = append(items, reflowItem{rect: fx.Rect, html: fmt.Sprintf("<p class=\"%s\">%s</p>\n", fontClassAttr("sfx", fx.Font, fontClasses), xmlEsc(s))})
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].rect, items[j].rect
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		if rtl {
+			return a.X+a.Width > b.X+b.Width
+		}
+		return a.X < b.X
+	})
+	if ordered {
+		var slots []int
+		var balloons []reflowItem
+		for i, it := range items {
+			if it.balloon > 0 {
+				slots = append(slots, i)
+				balloons = append(balloons, it)
+			}
+		}
+		sort.SliceStable(balloons, func(i, j int) bool { return balloons[i].balloon < balloons[j].balloon })
+		for k, i := range slots {
+			items[i] = balloons[k]
+		}
+	}
+	return items
+}
+
+// balloonSpeaker returns the speaking character and the balloon text.
+// Balloon.Character wins; otherwise a leading "NAME:" matching a bible character or alias
+// (case-insensitive) is taken as the speaker and stripped from the text.
+func balloonSpeaker(b domain.Balloon, bible domain.Bible) (string, string) {
+	parts := make([]string, 0, len(b.TextRuns))
+	for _, tr := range b.TextRuns {
+		if s := strings.TrimSpace(tr.Content); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	text := strings.Join(parts, " ")
+	if s := strings.TrimSpace(b.Character); s != "" {
+		return s, text
+	}
+	idx := strings.Index(text, ":")
+	if idx <= 0 {
+		return "", text
+	}
+	cue := strings.TrimSpace(text[:idx])
+	for _, bc := range bible.Characters {
+		names := append([]string{bc.Name}, bc.Aliases...)
+		for _, n := range names {
+			if n = strings.TrimSpace(n); n != "" && strings.EqualFold(n, cue) {
+				return bc.Name, strings.TrimSpace(text[idx+1:])
+			}
+		}
+	}
+	return "", text
+}
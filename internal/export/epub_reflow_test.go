@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func readZipEntries(t *testing.T, path string) map[string]string {
+	t.Helper()
+	rd, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("open zip: %v", err)
+	}
+	defer func() { _ = rd.Close() }()
+	out := map[string]string{}
+	for _, f := range rd.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		b, err := io.ReadAll(r)
+		_ = r.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name, err)
+		}
+		out[f.Name] = string(b)
+	}
+	return out
+}
+
+func assertWellFormedXML(t *testing.T, name, data string) {
+	t.Helper()
+	dec := xml.NewDecoder(strings.NewReader(data))
+	for {
+		_, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			t.Fatalf("%s is not well-formed XML: %v\n%s", name, err, data)
+		}
+	}
+}
+
+func TestExportIssueEPUB_Reflowable(t *testing.T) {
+	proj := domain.Project{
+		Name:  "Reflow",
+		Bible: domain.Bible{Characters: []domain.BibleCharacter{{Name: "ALICE", Aliases: []string{"Al"}}}},
+		Issues: []domain.Issue{{
+			TrimWidth: 360, TrimHeight: 540, DPI: 36, ReadingDirection: "ltr",
+			Pages: []domain.Page{
+				{Number: 1, Panels: []domain.Panel{
+					// second in reading order despite lower zOrder
+					{ID: "p2", ZOrder: 0, Geometry: domain.Rect{X: 0, Y: 300, Width: 360, Height: 200}, Notes: "Bob & Alice <argue>",
+						Balloons: []domain.Balloon{{ID: "b2", Type: "speech", Character: "BOB", Shape: domain.Shape{Rect: domain.Rect{X: 10, Y: 310, Width: 80, Height: 40}}, TextRuns: []domain.TextRun{{Content: "No way!"}}}}},
+					{ID: "p1", ZOrder: 1, Geometry: domain.Rect{X: 0, Y: 0, Width: 360, Height: 280}, Notes: "A dark alley.",
+						Captions: []domain.Caption{{ID: "c1", Text: "Meanwhile…", Rect: domain.Rect{X: 5, Y: 5, Width: 100, Height: 30}}},
+						Balloons: []domain.Balloon{
+							{ID: "b1", Type: "whisper", Shape: domain.Shape{Rect: domain.Rect{X: 50, Y: 100, Width: 80, Height: 40}}, TextRuns: []domain.TextRun{{Content: "al: Psst."}}},
+						},
+						SFX: []domain.SFXItem{{ID: "s1", Text: "Krak", Rect: domain.Rect{X: 200, Y: 200, Width: 60, Height: 30}}}},
+				}},
+				{Number: 2},
+			},
+		}},
+	}
+	root := t.TempDir()
+	ph := &storage.ProjectHandle{Root: root, Project: proj}
+	out := filepath.Join(root, "reflow.epub")
+	if err := ExportIssueEPUB(ph, 0, out, EPUBOptions{Language: "de"}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	ents := readZipEntries(t, out)
+	for name, data := range ents {
+		if strings.HasSuffix(name, ".xhtml") || strings.HasSuffix(name, ".opf") || strings.HasSuffix(name, ".xml") {
+			assertWellFormedXML(t, name, data)
+		}
+	}
+	opf := ents["OEBPS/content.opf"]
+	if strings.Contains(opf, "rendition:") {
+		t.Fatalf("reflowable opf must not carry rendition properties:\n%s", opf)
+	}
+	nav := ents["OEBPS/nav.xhtml"]
+	if !strings.Contains(nav, "page-1.xhtml") || !strings.Contains(nav, "page-2.xhtml") {
+		t.Fatalf("nav should list pages:\n%s", nav)
+	}
+	page := ents["OEBPS/page-1.xhtml"]
+	want := []string{
+		`lang="de"`,
+		`<img src="images/page-1.png"`,
+		`<p class="description">A dark alley.</p>`,
+		`<p class="caption">Meanwhile…</p>`,
+		`<span class="speaker">ALICE</span> (whisper): Psst.`,
+		`<p class="sfx">Krak</p>`,
+		`<p class="description">Bob &amp; Alice &lt;argue&gt;</p>`,
+		`<span class="speaker">BOB</span>: No way!`,
+	}
+	last := -1
+	for _, w := range want {
+		i := strings.Index(page, w)
+		if i < 0 {
+			t.Fatalf("page 1 missing %q:\n%s", w, page)
+		}
+		if i < last {
+			t.Fatalf("%q out of reading order:\n%s", w, page)
+		}
+		last = i
+	}
+	if _, ok := ents["OEBPS/images/page-1.png"]; !ok {
+		t.Fatalf("page image should still be included")
+	}
+}
+
+func TestBalloonSpeaker(t *testing.T) {
+	bible := domain.Bible{Characters: []domain.BibleCharacter{{Name: "ALICE", Aliases: []string{"Ally"}}}}
+	cases := []struct {
+		b           domain.Balloon
+		wantSpeaker string
+		wantText    string
+	}{
+		{domain.Balloon{Character: "BOB", TextRuns: []domain.TextRun{{Content: "Hi"}, {Content: "there"}}}, "BOB", "Hi there"},
+		{domain.Balloon{TextRuns: []domain.TextRun{{Content: "ally: hey"}}}, "ALICE", "hey"},
+		{domain.Balloon{TextRuns: []domain.TextRun{{Content: "Note: unknown cue"}}}, "", "Note: unknown cue"},
+		{domain.Balloon{TextRuns: []domain.TextRun{{Content: "plain"}}}, "", "plain"},
+	}
+	for _, tc := range cases {
+		sp, txt := balloonSpeaker(tc.b, bible)
+		if sp != tc.wantSpeaker || txt != tc.wantText {
+			t.Errorf("balloonSpeaker(%+v) = %q, %q; want %q, %q", tc.b.TextRuns, sp, txt, tc.wantSpeaker, tc.wantText)
+		}
+	}
+}
+
+func TestPanelReflowItems_BalloonOrder(t *testing.T) {
+	balloon := func(id, text string, x, y float64, order int) domain.Balloon {
+		return domain.Balloon{ID: id, Type: "speech", Order: order, TextRuns: []domain.TextRun{{Content: text}},
+			Shape: domain.Shape{Rect: domain.Rect{X: x, Y: y, Width: 40, Height: 20}}}
+	}
+	pn := domain.Panel{
+		Captions: []domain.Caption{{Text: "Meanwhile", Rect: domain.Rect{X: 0, Y: 0, Width: 50, Height: 10}}},
+		Balloons: []domain.Balloon{balloon("b1", "first", 100, 20, 0), balloon("b2", "second", 0, 40, 0)},
+	}
+	texts := func() []string {
+		var out []string
+		for _, it := range panelReflowItems(pn, false, domain.Bible{}, nil) {
+			for _, w := range []string{"Meanwhile", "first", "second"} {
+				if strings.Contains(it.html, w) {
+					out = append(out, w)
+				}
+			}
+		}
+		return out
+	}
+	// Without a reading order the position decides
+	if got := strings.Join(texts(), " "); got != "Meanwhile first second" {
+		t.Fatalf("positional order = %q", got)
+	}
+	// An explicit reading order wins for the balloons; the caption keeps its place
+	pn.Balloons[0].Order, pn.Balloons[1].Order = 2, 1
+	if got := strings.Join(texts(), " "); got != "Meanwhile second first" {
+		t.Fatalf("reading order = %q", got)
+	}
+}
@@ -51,7 +51,7 @@ func TestExportIssueEPUB_Structure(t *testing.T) {
 		t.Fatalf("init project: %v", err)
 	}
 	out := filepath.Join(root, "exports", "issue-1.epub")
-	if err := ExportIssueEPUB(ph, 0, out, EPUBOptions{IncludeGuides: true, Language: "en"}); err != nil {
+	if err := ExportIssueEPUB(ph, 0, out, EPUBOptions{IncludeGuides: true, Language: "en", FixedLayout: true}); err != nil {
 		t.Fatalf("export epub: %v", err)
 	}
 	st, err := os.Stat(out)
@@ -122,6 +122,31 @@ func TestExportIssueEPUB_Structure(t *testing.T) {
 	}
 }
 
+func TestExportIssueEPUB_ProjectMetadata(t *testing.T) {
+	proj := sampleProject()
+	proj.Metadata = domain.Metadata{Series: "Moon", Genre: "Sci-Fi", AgeRating: "Teen", Web: "https://example.com", LanguageISO: "fr"}
+	proj.Issues[0].Number = "7"
+	ph, err := storage.InitProject(t.TempDir(), proj)
+	if err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	out := filepath.Join(ph.Root, "exports", "meta.epub")
+	if err := ExportIssueEPUB(ph, 0, out, EPUBOptions{FixedLayout: true, NoReport: true}); err != nil {
+		t.Fatalf("export epub: %v", err)
+	}
+	opf := readZipEntries(t, out)["OEBPS/content.opf"]
+	assertWellFormedXML(t, "content.opf", opf)
+	for _, want := range []string{
+		"<dc:language>fr</dc:language>", "<dc:subject>Sci-Fi</dc:subject>", "<dc:relation>https://example.com</dc:relation>",
+		`<meta property="schema:contentRating">Teen</meta>`, `<meta property="belongs-to-collection" id="series">Moon</meta>`,
+		`<meta refines="#series" property="group-position">7</meta>`,
+	} {
+		if !stringsContains(opf, want) {
+			t.Errorf("content.opf lacks %s:\n%s", want, opf)
+		}
+	}
+}
+
 // Optional: run epubcheck if EPUBCHECK_JAR is set (path to epubcheck.jar) and Java is available.
 func TestExportIssueEPUB_WithEpubCheck(t *testing.T) {
 	jar := os.Getenv("EPUBCHECK_JAR")
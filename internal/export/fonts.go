@@ -0,0 +1,292 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// ReportFont records how an export handled a font family named by the lettering. Embedded fonts are
+// listed with their license text so distributors can check the terms; families the project has no
+// file for are listed with the font substituted for them.
+type ReportFont struct {
+	Family     string `json:"family"`
+	File       string `json:"file,omitempty"` // relative to the project root
+	License    string `json:"license,omitempty"`
+	Embedded   bool   `json:"embedded"`
+	Substitute string `json:"substitute,omitempty"`
+}
+
+// letteringFonts resolves the font families named by balloons, captions and SFX to the project's font
+// files during one export and records the outcome in the export report.
+type letteringFonts struct {
+	root       string
+	fonts      []storage.ProjectFont
+	run        *exportRun
+	substitute string          // reported for families that cannot be used
+	reported   map[string]bool // family (lower case) or file path already in the report
+}
+
+// newLetteringFonts lists the project's fonts. A fonts folder that cannot be read is a warning, not an
+// error: the export then uses substitute throughout.
+func newLetteringFonts(ph *storage.ProjectHandle, run *exportRun, substitute string) *letteringFonts {
+	lf := &letteringFonts{root: ph.Root, run: run, substitute: substitute, reported: map[string]bool{}}
+	fonts, err := storage.ListProjectFonts(ph.Root)
+	if err != nil {
+		run.warn(fmt.Sprintf("project fonts unavailable, using %s: %v", substitute, err))
+	}
+	lf.fonts = fonts
+	return lf
+}
+
+// face returns the project font for family. It reports false for an empty family or Helvetica, which
+// mean the export default, and for a family without a font file, which is warned about once.
+func (lf *letteringFonts) face(family string, bold bool) (storage.ProjectFont, bool) {
+	family = strings.TrimSpace(family)
+	if family == "" {
+		return storage.ProjectFont{}, false
+	}
+	f, ok := storage.FindFontFace(lf.fonts, family, bold)
+	if !ok && !strings.EqualFold(family, "Helvetica") {
+		lf.substituted(family, "", fmt.Sprintf("font %q is not in %s; using %s", family, path.Join("styles", storage.FontsDirName), lf.substitute))
+	}
+	return f, ok
+}
+
+// embedded records that f went into the output.
+func (lf *letteringFonts) embedded(f storage.ProjectFont) {
+	if lf.reported[f.Path] {
+		return
+	}
+	lf.reported[f.Path] = true
+	lf.run.addFont(ReportFont{Family: f.Family, File: f.Path, License: f.License, Embedded: true})
+}
+
+// substituted records and warns once that family (from file, when known) was replaced.
+func (lf *letteringFonts) substituted(family, file, warning string) {
+	key := strings.ToLower(family)
+	if file != "" {
+		key = file
+	}
+	if lf.reported[key] {
+		return
+	}
+	lf.reported[key] = true
+	lf.run.addFont(ReportFont{Family: family, File: file, Substitute: lf.substitute})
+	lf.run.warn(warning)
+}
+
+// read returns the content of a project font file.
+func (lf *letteringFonts) read(f storage.ProjectFont) ([]byte, error) {
+	return os.ReadFile(filepath.Join(lf.root, filepath.FromSlash(f.Path)))
+}
+
+// balloonFont is the font family of a balloon: that of its first run naming one.
+func balloonFont(b domain.Balloon) string {
+	for _, r := range b.TextRuns {
+		if s := strings.TrimSpace(r.Font); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// usedFontFamilies returns the distinct font families named by the lettering of the selected pages,
+// sorted case-insensitively.
+func usedFontFamilies(iss domain.Issue, pages []int) []string {
+	seen := map[string]string{}
+	add := func(f string) {
+		if f = strings.TrimSpace(f); f != "" {
+			if _, ok := seen[strings.ToLower(f)]; !ok {
+				seen[strings.ToLower(f)] = f
+			}
+		}
+	}
+	for _, i := range pages {
+		if i < 0 || i >= len(iss.Pages) {
+			continue
+		}
+		for _, pn := range iss.Pages[i].Panels {
+			for _, b := range pn.Balloons {
+				for _, r := range b.TextRuns {
+					add(r.Font)
+				}
+			}
+			for _, c := range pn.Captions {
+				add(c.Font)
+			}
+			for _, fx := range pn.SFX {
+				add(fx.Font)
+			}
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for _, f := range seen {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return strings.ToLower(out[i]) < strings.ToLower(out[j]) })
+	return out
+}
+
+// pdfFonts selects lettering fonts in a PDF, embedding each used project font on first use. gofpdf
+// embeds UTF-8 fonts as subsets of the glyphs actually drawn.
+type pdfFonts struct {
+	pdf    *gofpdf.Fpdf
+	lf     *letteringFonts
+	def    string            // built-in or bundled family for the default and substitutes
+	family map[string]string // font file → gofpdf family, "" when it could not be embedded
+}
+
+func newPDFFonts(pdf *gofpdf.Fpdf, lf *letteringFonts, def string) *pdfFonts {
+	return &pdfFonts{pdf: pdf, lf: lf, def: def, family: map[string]string{}}
+}
+
+// set makes family the current font at size pt; bold asks for a bold face. Families the PDF cannot
+// use fall back to the default family.
+func (pf *pdfFonts) set(family string, bold bool, size float64) {
+	style := ""
+	if bold {
+		style = "B"
+	}
+	f, ok := pf.lf.face(family, bold)
+	if !ok {
+		pf.pdf.SetFont(pf.def, style, size)
+		return
+	}
+	name, seen := pf.family[f.Path]
+	if !seen {
+		name = pf.embed(f)
+		pf.family[f.Path] = name
+	}
+	if name == "" {
+		pf.pdf.SetFont(pf.def, style, size)
+		return
+	}
+	// The face file already carries the weight
+	pf.pdf.SetFont(name, "", size)
+}
+
+// embed adds f to the PDF and returns its gofpdf family, or "" after recording a substitution.
+func (pf *pdfFonts) embed(f storage.ProjectFont) string {
+	if f.CFF {
+		pf.lf.substituted(f.Family, f.Path, fmt.Sprintf("font %s has PostScript outlines, which PDF export cannot embed; using %s", f.Path, pf.lf.substitute))
+		return ""
+	}
+	data, err := pf.lf.read(f)
+	if err != nil {
+		pf.lf.substituted(f.Family, f.Path, fmt.Sprintf("read font %s: %v; using %s", f.Path, err, pf.lf.substitute))
+		return ""
+	}
+	name := fmt.Sprintf("gcwfont%d", len(pf.family)+1)
+	pf.pdf.AddUTF8FontFromBytes(name, "", data)
+	if err := pf.pdf.Error(); err != nil {
+		pf.pdf.ClearError()
+		pf.lf.substituted(f.Family, f.Path, fmt.Sprintf("embed font %s: %v; using %s", f.Path, err, pf.lf.substitute))
+		return ""
+	}
+	pf.lf.embedded(f)
+	return name
+}
+
+// epubFont is a project font file packaged in a reflowable EPUB.
+type epubFont struct {
+	font storage.ProjectFont
+	id   string // manifest item id
+	href string // relative to OEBPS
+	data []byte
+}
+
+// epubFonts reads every face of the used families the project has fonts for and assigns each such
+// family a CSS class (font-1, font-2, …), keyed by the lower-cased family. Text in other families
+// keeps the stylesheet's default font.
+func epubFonts(lf *letteringFonts, families []string) ([]epubFont, map[string]string) {
+	var out []epubFont
+	classes := map[string]string{}
+	for _, fam := range families {
+		if _, ok := lf.face(fam, false); !ok {
+			continue
+		}
+		found := false
+		for _, f := range lf.fonts {
+			if !strings.EqualFold(f.Family, fam) {
+				continue
+			}
+			data, err := lf.read(f)
+			if err != nil {
+				lf.substituted(f.Family, f.Path, fmt.Sprintf("read font %s: %v; using %s", f.Path, err, lf.substitute))
+				continue
+			}
+			n := len(out) + 1
+			out = append(out, epubFont{font: f, id: fmt.Sprintf("font-file-%d", n), href: fmt.Sprintf("fonts/font-%d.%s", n, f.Format), data: data})
+			lf.embedded(f)
+			found = true
+		}
+		if found {
+			classes[strings.ToLower(fam)] = fmt.Sprintf("font-%d", len(classes)+1)
+		}
+	}
+	return out, classes
+}
+
+// epubFontCSS declares the packaged faces and the family classes for the stylesheet in styles/.
+func epubFontCSS(fonts []epubFont, classes map[string]string) string {
+	var sb strings.Builder
+	declared := map[string]bool{}
+	var rules []string
+	for _, ef := range fonts {
+		weight, style := "normal", "normal"
+		if ef.font.Bold() {
+			weight = "bold"
+		}
+		if ef.font.Italic() {
+			style = "italic"
+		}
+		fmt.Fprintf(&sb, "@font-face { font-family:%s; src:url(\"../%s\"); font-weight:%s; font-style:%s; }\n", cssString(ef.font.Family), ef.href, weight, style)
+		key := strings.ToLower(ef.font.Family)
+		if c := classes[key]; c != "" && !declared[key] {
+			declared[key] = true
+			rules = append(rules, fmt.Sprintf(".%s { font-family:%s, serif; }\n", c, cssString(ef.font.Family)))
+		}
+	}
+	for _, r := range rules {
+		sb.WriteString(r)
+	}
+	return sb.String()
+}
+
+// epubFontMediaType is the EPUB core media type of a font format.
+func epubFontMediaType(format string) string {
+	if format == "otf" {
+		return "font/otf"
+	}
+	return "font/ttf"
+}
+
+// cssString quotes s as a CSS string.
+func cssString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(s) + `"`
+}
+
+// fontClassAttr returns class extended by the font class of family, if it has one.
+func fontClassAttr(class, family string, classes map[string]string) string {
+	if c := classes[strings.ToLower(strings.TrimSpace(family))]; c != "" {
+		return class + " " + c
+	}
+	return class
+}
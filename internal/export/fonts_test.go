@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// fontSampleProject is sampleProject lettered in the Go font, with a caption in a family the project lacks.
+func fontSampleProject() domain.Project {
+	proj := sampleProject()
+	pn := &proj.Issues[0].Pages[0].Panels[0]
+	pn.Balloons[0].TextRuns[0].Font = "Go"
+	pn.Captions = []domain.Caption{{ID: "c1", Text: "Meanwhile", Font: "Blambot Pro", Rect: domain.Rect{X: 40, Y: 300, Width: 200, Height: 40}}}
+	pn.SFX = []domain.SFXItem{{ID: "s1", Text: "BOOM", Font: "go", Rect: domain.Rect{X: 40, Y: 400, Width: 200, Height: 60}}}
+	return proj
+}
+
+// fontProject is a project of fontSampleProject with the regular and bold Go faces in its fonts folder.
+func fontProject(t *testing.T) *storage.ProjectHandle {
+	t.Helper()
+	ph, err := storage.InitProject(t.TempDir(), fontSampleProject())
+	if err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	dir := storage.FontsDir(ph.Root)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string][]byte{"Go-Regular.ttf": goregular.TTF, "Go-Bold.ttf": gobold.TTF} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ph
+}
+
+// assertFontReport checks that the report lists the embedded Go faces and the substituted family.
+func assertFontReport(t *testing.T, rep Report, files []string, substitute string) {
+	t.Helper()
+	var embedded []string
+	missing := 0
+	for _, f := range rep.Fonts {
+		switch {
+		case f.Embedded:
+			if f.Family != "Go" || f.License == "" {
+				t.Fatalf("embedded font %+v lacks family or license", f)
+			}
+			embedded = append(embedded, f.File)
+		case f.Family == "Blambot Pro" && f.Substitute == substitute:
+			missing++
+		default:
+			t.Fatalf("unexpected font entry %+v", f)
+		}
+	}
+	if strings.Join(embedded, ",") != strings.Join(files, ",") || missing != 1 {
+		t.Fatalf("fonts %+v, want embedded %v and one substitute", rep.Fonts, files)
+	}
+	if len(rep.Warnings) != 1 || !strings.Contains(rep.Warnings[0], "Blambot Pro") {
+		t.Fatalf("warnings %v", rep.Warnings)
+	}
+}
+
+func TestExportIssuePDF_EmbedsProjectFonts(t *testing.T) {
+	ph := fontProject(t)
+	out := filepath.Join(ph.Root, "exports", "fonts.pdf")
+	if err := ExportIssuePDF(ph, 0, out, PDFOptions{}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(data, []byte("/FontFile2")); n != 2 {
+		t.Fatalf("expected the regular and bold Go faces embedded, found %d font files", n)
+	}
+	// Balloon text in the regular face, SFX in the bold one; the missing family falls back to Helvetica
+	assertFontReport(t, readReport(t, out+ReportSuffix), []string{"styles/fonts/Go-Regular.ttf", "styles/fonts/Go-Bold.ttf"}, "Helvetica")
+}
+
+func TestExportIssueEPUB_EmbedsProjectFonts(t *testing.T) {
+	ph := fontProject(t)
+	out := filepath.Join(ph.Root, "exports", "fonts.epub")
+	if err := ExportIssueEPUB(ph, 0, out, EPUBOptions{}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	entries := readZipEntries(t, out)
+	for _, name := range []string{"OEBPS/fonts/font-1.ttf", "OEBPS/fonts/font-2.ttf"} {
+		if _, ok := entries[name]; !ok {
+			t.Fatalf("missing %s", name)
+		}
+	}
+	css := entries["OEBPS/styles/epub.css"]
+	if !strings.Contains(css, `@font-face { font-family:"Go"; src:url("../fonts/font-2.ttf"); font-weight:bold;`) ||
+		!strings.Contains(css, `.font-1 { font-family:"Go", serif; }`) {
+		t.Fatalf("css lacks the font faces or class:\n%s", css)
+	}
+	page := entries["OEBPS/page-1.xhtml"]
+	if !strings.Contains(page, `<p class="dialogue font-1">`) || !strings.Contains(page, `<p class="sfx font-1">BOOM</p>`) ||
+		!strings.Contains(page, `<p class="caption">Meanwhile</p>`) {
+		t.Fatalf("page text lacks font classes:\n%s", page)
+	}
+	opf := entries["OEBPS/content.opf"]
+	assertWellFormedXML(t, "content.opf", opf)
+	if !strings.Contains(opf, `<item id="font-file-1" href="fonts/font-1.ttf" media-type="font/ttf"/>`) {
+		t.Fatalf("manifest lacks the font:\n%s", opf)
+	}
+	assertFontReport(t, readReport(t, out+ReportSuffix), []string{"styles/fonts/Go-Regular.ttf", "styles/fonts/Go-Bold.ttf"}, "serif")
+}
+
+func TestExportWithoutProjectFonts(t *testing.T) {
+	// Helvetica and empty families are the default and never warned about
+	ph, err := storage.InitProject(t.TempDir(), sampleProject())
+	if err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	out := filepath.Join(ph.Root, "exports", "plain.pdf")
+	if err := ExportIssuePDF(ph, 0, out, PDFOptions{}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if rep := readReport(t, out+ReportSuffix); len(rep.Fonts) != 0 || len(rep.Warnings) != 0 {
+		t.Fatalf("fonts %+v warnings %v", rep.Fonts, rep.Warnings)
+	}
+}
+
+func TestUsedFontFamilies(t *testing.T) {
+	iss := fontSampleProject().Issues[0]
+	if got := strings.Join(usedFontFamilies(iss, []int{0}), ","); got != "Blambot Pro,Go" {
+		t.Fatalf("families %q", got)
+	}
+	if got := usedFontFamilies(iss, []int{5}); len(got) != 0 {
+		t.Fatalf("out of range page: %v", got)
+	}
+}
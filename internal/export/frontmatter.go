@@ -0,0 +1,212 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strconv"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/render"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// DefaultCreditsTemplate is used when an issue enables the title page without credits text.
+const DefaultCreditsTemplate = "{Creators}"
+
+// titlePageLabel names the synthesized page in EPUB navigation and PDF page labels.
+const titlePageLabel = "Title Page"
+
+// frontLine is one centered line of the title page. Size is in points; empty text is a spacer.
+type frontLine struct {
+	text string
+	size float64
+	bold bool
+}
+
+// hasTitlePage reports whether exports of iss start with a synthesized title page.
+func hasTitlePage(iss domain.Issue) bool {
+	return iss.FrontMatter != nil && iss.FrontMatter.TitlePage
+}
+
+// issueSeries returns the series name shown for the project, falling back to the project name.
+func issueSeries(proj domain.Project) string {
+	if proj.Metadata.Series != "" {
+		return proj.Metadata.Series
+	}
+	return proj.Name
+}
+
+// issueTitle returns the issue title from the metadata, or "Issue N".
+func issueTitle(proj domain.Project, issueIndex int) string {
+	if proj.Metadata.IssueTitle != "" {
+		return proj.Metadata.IssueTitle
+	}
+	return fmt.Sprintf("Issue %d", issueIndex+1)
+}
+
+// issueNumber returns the issue's number override, or its position counted from 1.
+func issueNumber(proj domain.Project, issueIndex int) string {
+	if n := strings.TrimSpace(proj.Issues[issueIndex].Number); n != "" {
+		return n
+	}
+	return strconv.Itoa(issueIndex + 1)
+}
+
+// expandCredits replaces the {Series}, {IssueTitle} and {Creators} placeholders in tmpl.
+func expandCredits(tmpl, series, title, creators string) string {
+	return strings.NewReplacer("{Series}", series, "{IssueTitle}", title, "{Creators}", creators).Replace(tmpl)
+}
+
+// titlePageLines lays out the title page: series and issue title as headings, then the credits.
+func titlePageLines(proj domain.Project, issueIndex int) []frontLine {
+	iss := proj.Issues[issueIndex]
+	series, title := issueSeries(proj), issueTitle(proj, issueIndex)
+	tmpl := DefaultCreditsTemplate
+	if iss.FrontMatter != nil && strings.TrimSpace(iss.FrontMatter.Credits) != "" {
+		tmpl = iss.FrontMatter.Credits
+	}
+	lines := []frontLine{{text: series, size: 28, bold: true}, {text: title, size: 20, bold: true}, {size: 12}}
+	credits := strings.Split(strings.TrimRight(expandCredits(tmpl, series, title, proj.Metadata.Creators), "\n "), "\n")
+	for _, c := range credits {
+		lines = append(lines, frontLine{text: strings.TrimSpace(c), size: 12})
+	}
+	return lines
+}
+
+// frontLineHeight is the vertical advance of a title page line.
+func frontLineHeight(l frontLine) float64 { return l.size * 1.4 }
+
+// frontBlockTop returns the y (in points, trim coordinates) of the first line so the block is centered.
+func frontBlockTop(lines []frontLine, trimH float64) float64 {
+	total := 0.0
+	for _, l := range lines {
+		total += frontLineHeight(l)
+	}
+	return math.Max(0, (trimH-total)/2)
+}
+
+// addPDFTitlePage appends the title page to pdf with every line centered on the trim box.
+func addPDFTitlePage(pdf *gofpdf.Fpdf, ink pdfInk, family string, lines []frontLine, trimW, trimH, bleed float64) {
+	pdf.AddPageFormat("", gofpdf.SizeType{Wd: trimW + 2*bleed, Ht: trimH + 2*bleed})
+	ink.text()
+	y := bleed + frontBlockTop(lines, trimH)
+	for _, l := range lines {
+		y += frontLineHeight(l)
+		if l.text == "" {
+			continue
+		}
+		style := ""
+		if l.bold {
+			style = "B"
+		}
+		size := l.size
+		pdf.SetFont(family, style, size)
+		// Shrink headings that would not fit between the trim edges
+		if w := pdf.GetStringWidth(l.text); w > trimW*0.9 {
+			size *= trimW * 0.9 / w
+			pdf.SetFont(family, style, size)
+		}
+		pdf.Text(bleed+(trimW-pdf.GetStringWidth(l.text))/2, y, l.text)
+	}
+}
+
+// renderTitlePageImage draws the title page for raster exports. The 7x13 bitmap face is scaled up by
+// whole pixels so headings stay legible at print resolutions.
+func renderTitlePageImage(lines []frontLine, trimW, trimH, bleed, scale float64) *image.RGBA {
+	pixW := int(math.Round((trimW + 2*bleed) * scale))
+	pixH := int(math.Round((trimH + 2*bleed) * scale))
+	img := image.NewRGBA(image.Rect(0, 0, pixW, pixH))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{255, 255, 255, 255}}, image.Point{}, draw.Src)
+	black := color.RGBA{0, 0, 0, 255}
+	cx := int(math.Round((bleed + trimW/2) * scale))
+	maxW := trimW * 0.9 * scale
+	y := (bleed + frontBlockTop(lines, trimH)) * scale
+	for _, l := range lines {
+		y += frontLineHeight(l) * scale
+		if l.text == "" {
+			continue
+		}
+		k := int(math.Round(l.size * scale / 13))
+		if w := font.MeasureString(basicfont.Face7x13, l.text).Ceil(); w > 0 {
+			k = min(k, int(maxW)/w)
+		}
+		drawScaledText(img, cx, int(y), l.text, black, max(k, 1))
+	}
+	return img
+}
+
+// drawScaledText draws s centered on cx with its baseline at y, enlarging the bitmap face k times.
+func drawScaledText(img *image.RGBA, cx, y int, s string, col color.RGBA, k int) {
+	face := basicfont.Face7x13
+	w := font.MeasureString(face, s).Ceil()
+	m := face.Metrics()
+	asc, h := m.Ascent.Ceil(), m.Height.Ceil()
+	small := image.NewRGBA(image.Rect(0, 0, w, h))
+	d := &font.Drawer{Dst: small, Src: image.NewUniform(col), Face: face, Dot: fixed.P(0, asc)}
+	d.DrawString(s)
+	x0, y0 := cx-w*k/2, y-asc*k
+	for sy := 0; sy < h; sy++ {
+		for sx := 0; sx < w; sx++ {
+			if small.RGBAAt(sx, sy).A == 0 {
+				continue
+			}
+			render.FillRect(img, x0+sx*k, y0+sy*k, x0+sx*k+k-1, y0+sy*k+k-1, col)
+		}
+	}
+}
+
+// titlePageXHTML returns the reflowable EPUB title page, or, when imgHref is set, a fixed-layout page
+// showing the rendered title page image.
+func titlePageXHTML(lines []frontLine, lang, imgHref string) string {
+	buf := &bytes.Buffer{}
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n")
+	buf.WriteString(fmt.Sprintf("<html xmlns=\"http://www.w3.org/1999/xhtml\" xmlns:epub=\"http://www.idpf.org/2007/ops\" lang=\"%s\" xml:lang=\"%s\">\n", xmlEsc(lang), xmlEsc(lang)))
+	buf.WriteString("<head>\n<meta charset=\"utf-8\"/>\n")
+	if imgHref != "" {
+		buf.WriteString("<meta name=\"viewport\" content=\"width=device-width, height=device-height\"/>\n")
+	}
+	buf.WriteString(fmt.Sprintf("<title>%s</title>\n", titlePageLabel))
+	buf.WriteString("<link rel=\"stylesheet\" type=\"text/css\" href=\"styles/epub.css\"/>\n</head>\n<body>\n")
+	if imgHref != "" {
+		alt := make([]string, 0, len(lines))
+		for _, l := range lines {
+			if l.text != "" {
+				alt = append(alt, l.text)
+			}
+		}
+		buf.WriteString(fmt.Sprintf("<div class=\"page\" epub:type=\"titlepage\"><img src=\"%s\" alt=\"%s\"/></div>\n", xmlEsc(imgHref), xmlEsc(strings.Join(alt, " — "))))
+		buf.WriteString("</body>\n</html>\n")
+		return buf.String()
+	}
+	buf.WriteString("<section class=\"titlepage\" epub:type=\"titlepage\">\n")
+	for i, l := range lines {
+		switch {
+		case l.text == "":
+		case i == 0:
+			buf.WriteString(fmt.Sprintf("<h1>%s</h1>\n", xmlEsc(l.text)))
+		case i == 1:
+			buf.WriteString(fmt.Sprintf("<h2>%s</h2>\n", xmlEsc(l.text)))
+		default:
+			buf.WriteString(fmt.Sprintf("<p class=\"credits\">%s</p>\n", xmlEsc(l.text)))
+		}
+	}
+	buf.WriteString("</section>\n</body>\n</html>\n")
+	return buf.String()
+}
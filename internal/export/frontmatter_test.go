@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// frontMatterProject is a two-page project with the title page enabled.
+func frontMatterProject(t *testing.T) *storage.ProjectHandle {
+	p := sampleProject()
+	p.Metadata = domain.Metadata{Series: "Night Shift", IssueTitle: "Pilot", Creators: "A. Writer & B. Artist"}
+	iss := &p.Issues[0]
+	iss.Pages = append(iss.Pages, domain.Page{Number: 2})
+	iss.FrontMatter = &domain.FrontMatter{TitlePage: true, Credits: "Story and art: {Creators}\n{Series} #1"}
+	return &storage.ProjectHandle{Root: t.TempDir(), Project: p}
+}
+
+func TestTitlePageLines(t *testing.T) {
+	ph := frontMatterProject(t)
+	var got []string
+	for _, l := range titlePageLines(ph.Project, 0) {
+		got = append(got, l.text)
+	}
+	want := []string{"Night Shift", "Pilot", "", "Story and art: A. Writer & B. Artist", "Night Shift #1"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("lines = %q, want %q", got, want)
+	}
+
+	// Defaults: project name, "Issue N" and the creators
+	p := sampleProject()
+	p.Metadata.Creators = "C. Letterer"
+	p.Issues[0].FrontMatter = &domain.FrontMatter{TitlePage: true}
+	got = got[:0]
+	for _, l := range titlePageLines(p, 0) {
+		got = append(got, l.text)
+	}
+	if strings.Join(got, "|") != "Test Project|Issue 1||C. Letterer" {
+		t.Fatalf("default lines = %q", got)
+	}
+	if hasTitlePage(domain.Issue{FrontMatter: &domain.FrontMatter{Credits: "x"}}) {
+		t.Fatalf("credits alone must not enable the title page")
+	}
+}
+
+func TestExportIssueCBZ_TitlePage(t *testing.T) {
+	ph := frontMatterProject(t)
+	out := filepath.Join(ph.Root, "fm.cbz")
+	if err := ExportIssueCBZ(ph, 0, out, CBZOptions{DPI: 72}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	entries := readZipEntries(t, out)
+	for _, name := range []string{"0.png", "1.png", "2.png"} {
+		if _, ok := entries[name]; !ok {
+			t.Errorf("missing %s", name)
+		}
+	}
+	if !strings.Contains(entries["ComicInfo.xml"], "<PageCount>3</PageCount>") {
+		t.Fatalf("PageCount should include the title page:\n%s", entries["ComicInfo.xml"])
+	}
+}
+
+func TestExportIssueEPUB_TitlePage(t *testing.T) {
+	for _, fixed := range []bool{true, false} {
+		ph := frontMatterProject(t)
+		out := filepath.Join(ph.Root, "fm.epub")
+		if err := ExportIssueEPUB(ph, 0, out, EPUBOptions{DPI: 72, FixedLayout: fixed}); err != nil {
+			t.Fatalf("export (fixed=%v): %v", fixed, err)
+		}
+		entries := readZipEntries(t, out)
+		title, ok := entries["OEBPS/title.xhtml"]
+		if !ok {
+			t.Fatalf("fixed=%v: title.xhtml missing", fixed)
+		}
+		assertWellFormedXML(t, "title.xhtml", title)
+		if _, ok := entries["OEBPS/images/title.png"]; ok != fixed {
+			t.Errorf("fixed=%v: title image present=%v", fixed, ok)
+		}
+		if !fixed && !strings.Contains(title, "Story and art: A. Writer &amp; B. Artist") {
+			t.Errorf("credits missing from reflowable title page:\n%s", title)
+		}
+		nav := entries["OEBPS/nav.xhtml"]
+		assertWellFormedXML(t, "nav.xhtml", nav)
+		if !strings.Contains(nav, `epub:type="titlepage" href="title.xhtml"`) || !strings.Contains(nav, `href="page-1.xhtml">Page 1<`) {
+			t.Errorf("fixed=%v: nav does not mark the title page or renumbered pages:\n%s", fixed, nav)
+		}
+		opf := entries["OEBPS/content.opf"]
+		if i, j := strings.Index(opf, `<itemref idref="title"/>`), strings.Index(opf, `<itemref idref="page-1"/>`); i < 0 || j < i {
+			t.Errorf("fixed=%v: title page is not first in the spine:\n%s", fixed, opf)
+		}
+	}
+}
+
+func TestExportIssuePDF_TitlePage(t *testing.T) {
+	for _, profile := range []OutputProfile{OutputProfileDefault, OutputProfilePDFX1a} {
+		ph := frontMatterProject(t)
+		out := filepath.Join(ph.Root, "fm.pdf")
+		if err := ExportIssuePDF(ph, 0, out, PDFOptions{OutputProfile: profile}); err != nil {
+			t.Fatalf("export %v: %v", profile, err)
+		}
+		doc, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if n := len(regexp.MustCompile(`/Type /Page\b`).FindAll(doc, -1)); n != 3 {
+			t.Errorf("%v: expected 3 pages, got %d", profile, n)
+		}
+		if !bytes.Contains(doc, []byte("/PageLabels << /Nums [0 << /P (Title Page) >> 1 << /S /D /St 1 >>] >>")) {
+			t.Errorf("%v: page labels missing", profile)
+		}
+		// The updated catalog must be reachable through the last xref section.
+		xi := bytes.LastIndex(doc, []byte("\nxref\n"))
+		for _, m := range regexp.MustCompile(`(\d+) 1\n(\d{10}) 00000 n `).FindAllSubmatch(doc[xi:], -1) {
+			off, _ := strconv.Atoi(string(m[2]))
+			if !bytes.HasPrefix(doc[off:], []byte(string(m[1])+" 0 obj")) {
+				t.Errorf("%v: xref offset %d does not point at object %s", profile, off, m[1])
+			}
+		}
+	}
+}
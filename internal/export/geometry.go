@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"math"
+
+	"gocomicwriter/internal/domain"
+)
+
+// Units: the manifest measures pages in points (1/72 inch, see domain.PointsPerInch) with the origin
+// at the top-left corner of the trim box. Output media add the bleed on every side.
+const defaultExportDPI = 300
+
+// exportDPI is the raster resolution of an export: override when set, else the issue's DPI, else 300.
+func exportDPI(iss domain.Issue, override int) int {
+	dpi := iss.DPI
+	if override > 0 {
+		dpi = override
+	}
+	if dpi <= 0 {
+		dpi = defaultExportDPI
+	}
+	return dpi
+}
+
+// pixelsPerPoint is the raster scale at dpi.
+func pixelsPerPoint(dpi int) float64 { return float64(dpi) / domain.PointsPerInch }
+
+// sheetBoxes returns the trim box of a sheet and its media box (trim plus bleed) in trim coordinates
+// of the sheet's left page. Exporters shift both by the bleed, so the media box starts at 0,0 on the
+// output page.
+func sheetBoxes(iss domain.Issue, sh sheet) (trim, media domain.Rect) {
+	trim = domain.Rect{Width: sh.trimWidth(iss.TrimWidth), Height: iss.TrimHeight}
+	b := iss.Bleed
+	media = domain.Rect{X: -b, Y: -b, Width: trim.Width + 2*b, Height: trim.Height + 2*b}
+	return trim, media
+}
+
+// rotatedBounds is the axis-aligned bounding box of r turned by deg degrees (clockwise) around its
+// center, as captions and SFX are drawn.
+func rotatedBounds(r domain.Rect, deg float64) domain.Rect {
+	if math.Mod(deg, 360) == 0 {
+		return r
+	}
+	rad := deg * math.Pi / 180
+	sin, cos := math.Abs(math.Sin(rad)), math.Abs(math.Cos(rad))
+	w := r.Width*cos + r.Height*sin
+	h := r.Width*sin + r.Height*cos
+	return domain.Rect{X: r.X + (r.Width-w)/2, Y: r.Y + (r.Height-h)/2, Width: w, Height: h}
+}
+
+// insetRect shrinks r by d on every side.
+func insetRect(r domain.Rect, d float64) domain.Rect {
+	return domain.Rect{X: r.X + d, Y: r.Y + d, Width: r.Width - 2*d, Height: r.Height - 2*d}
+}
+
+// rectContains reports whether inner lies completely inside outer. A small tolerance absorbs rounding
+// of coordinates stored in the manifest.
+func rectContains(outer, inner domain.Rect) bool {
+	const eps = 1e-6
+	return inner.X >= outer.X-eps && inner.Y >= outer.Y-eps &&
+		inner.X+inner.Width <= outer.X+outer.Width+eps && inner.Y+inner.Height <= outer.Y+outer.Height+eps
+}
+
+// offsetRect moves r right by dx points, e.g. onto the right page of a spread sheet.
+func offsetRect(r domain.Rect, dx float64) domain.Rect {
+	r.X += dx
+	return r
+}
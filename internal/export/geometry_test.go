@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"math"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestExportDPIAndUnits(t *testing.T) {
+	if got := exportDPI(domain.Issue{DPI: 150}, 0); got != 150 {
+		t.Errorf("issue dpi: %d", got)
+	}
+	if got := exportDPI(domain.Issue{DPI: 150}, 72); got != 72 {
+		t.Errorf("override: %d", got)
+	}
+	if got := exportDPI(domain.Issue{}, 0); got != defaultExportDPI {
+		t.Errorf("default: %d", got)
+	}
+	if got := pixelsPerPoint(144); got != 2 {
+		t.Errorf("pixelsPerPoint(144) = %g", got)
+	}
+}
+
+func TestSheetBoxes(t *testing.T) {
+	iss := domain.Issue{TrimWidth: 360, TrimHeight: 540, Bleed: 18}
+	trim, media := sheetBoxes(iss, sheet{left: 0, right: -1})
+	if trim != (domain.Rect{Width: 360, Height: 540}) || media != (domain.Rect{X: -18, Y: -18, Width: 396, Height: 576}) {
+		t.Errorf("single: %+v %+v", trim, media)
+	}
+	trim, media = sheetBoxes(iss, sheet{left: 1, right: 2})
+	if trim.Width != 720 || media.Width != 756 {
+		t.Errorf("spread: %+v %+v", trim, media)
+	}
+}
+
+func TestRotatedBounds(t *testing.T) {
+	r := domain.Rect{X: 0, Y: 0, Width: 200, Height: 40}
+	if got := rotatedBounds(r, 360); got != r {
+		t.Errorf("full turn: %+v", got)
+	}
+	got := rotatedBounds(r, 90)
+	want := domain.Rect{X: 80, Y: -80, Width: 40, Height: 200}
+	if math.Abs(got.X-want.X) > 1e-9 || math.Abs(got.Y-want.Y) > 1e-9 || math.Abs(got.Width-want.Width) > 1e-9 || math.Abs(got.Height-want.Height) > 1e-9 {
+		t.Errorf("90°: %+v, want %+v", got, want)
+	}
+	if !rectContains(domain.Rect{Width: 10, Height: 10}, domain.Rect{X: 1, Y: 1, Width: 9, Height: 9}) {
+		t.Error("touching edge should be contained")
+	}
+	if rectContains(domain.Rect{Width: 10, Height: 10}, insetRect(domain.Rect{Width: 10, Height: 10}, -1)) {
+		t.Error("grown rect should not be contained")
+	}
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sync"
+)
+
+// The bundled output profile is generated rather than shipped as a binary: a small ICC v2 printer
+// profile describing the same naive CMYK model rgbToCMYK uses (no dot gain, full GCR). It makes
+// PDF/X-1a files self-consistent out of the box; real print jobs should use the printer's profile.
+
+// defaultICCDescription names the bundled profile in the OutputIntent.
+const defaultICCDescription = "Go Comic Writer naive CMYK"
+
+var (
+	defaultICCOnce sync.Once
+	defaultICC     []byte
+)
+
+// defaultCMYKProfile returns the bundled CMYK output profile.
+func defaultCMYKProfile() []byte {
+	defaultICCOnce.Do(func() { defaultICC = buildNaiveCMYKProfile() })
+	return defaultICC
+}
+
+// D50 white point used by the ICC profile connection space.
+const iccXn, iccYn, iccZn = 0.9642, 1.0, 0.8249
+
+func buildNaiveCMYKProfile() []byte {
+	a2b := lut8(4, 3, 5, func(in []float64) []float64 {
+		c, m, y, k := in[0], in[1], in[2], in[3]
+		return labEncode(rgbToLab((1-c)*(1-k), (1-m)*(1-k), (1-y)*(1-k)))
+	})
+	b2a := lut8(3, 4, 9, func(in []float64) []float64 {
+		r, g, b := labToRGB(in[0]*100, in[1]*255-128, in[2]*255-128)
+		c, m, y, k := rgbToCMYK(r, g, b)
+		return []float64{c, m, y, k}
+	})
+	gamut := lut8(3, 1, 2, func([]float64) []float64 { return []float64{0} })
+	type tag struct {
+		sig  string
+		data []byte
+	}
+	tags := []tag{
+		{"desc", textDescription(defaultICCDescription)},
+		{"cprt", textType("No copyright, use freely")},
+		{"wtpt", xyzType(iccXn, iccYn, iccZn)},
+		{"A2B0", a2b}, {"A2B1", a2b}, {"A2B2", a2b},
+		{"B2A0", b2a}, {"B2A1", b2a}, {"B2A2", b2a},
+		{"gamt", gamut},
+	}
+	// header + tag table, then tag data; identical data blocks are shared
+	offset := 128 + 4 + 12*len(tags)
+	var table, data bytes.Buffer
+	_ = binary.Write(&table, binary.BigEndian, uint32(len(tags)))
+	shared := map[*byte]uint32{}
+	for _, t := range tags {
+		off, ok := shared[&t.data[0]]
+		if !ok {
+			for (offset+data.Len())%4 != 0 {
+				data.WriteByte(0)
+			}
+			off = uint32(offset + data.Len())
+			shared[&t.data[0]] = off
+			data.Write(t.data)
+		}
+		table.WriteString(t.sig)
+		_ = binary.Write(&table, binary.BigEndian, off)
+		_ = binary.Write(&table, binary.BigEndian, uint32(len(t.data)))
+	}
+	size := offset + data.Len()
+	h := make([]byte, 128)
+	binary.BigEndian.PutUint32(h[0:], uint32(size))
+	binary.BigEndian.PutUint32(h[8:], 0x02100000) // v2.1
+	copy(h[12:], "prtr")
+	copy(h[16:], "CMYK")
+	copy(h[20:], "Lab ")
+	for i, v := range []uint16{2025, 1, 1, 0, 0, 0} {
+		binary.BigEndian.PutUint16(h[24+2*i:], v)
+	}
+	copy(h[36:], "acsp")
+	copy(h[68:], xyzNumbers(iccXn, iccYn, iccZn))
+	out := append(h, table.Bytes()...)
+	return append(out, data.Bytes()...)
+}
+
+// lut8 builds an ICC lut8Type with identity matrix and curves and a grid^in CLUT filled by f,
+// which maps normalized inputs to normalized outputs. The first input channel varies slowest.
+func lut8(in, out, grid int, f func([]float64) []float64) []byte {
+	var b bytes.Buffer
+	b.WriteString("mft1")
+	b.Write([]byte{0, 0, 0, 0, byte(in), byte(out), byte(grid), 0})
+	for i := 0; i < 9; i++ {
+		v := 0.0
+		if i%4 == 0 {
+			v = 1
+		}
+		b.Write(s15Fixed16(v))
+	}
+	for i := 0; i < in; i++ {
+		for v := 0; v < 256; v++ {
+			b.WriteByte(byte(v))
+		}
+	}
+	n := 1
+	for i := 0; i < in; i++ {
+		n *= grid
+	}
+	pt := make([]float64, in)
+	for idx := 0; idx < n; idx++ {
+		rem := idx
+		for ch := in - 1; ch >= 0; ch-- {
+			pt[ch] = float64(rem%grid) / float64(grid-1)
+			rem /= grid
+		}
+		for _, v := range f(pt) {
+			b.WriteByte(byte(math.Round(clamp01(v) * 255)))
+		}
+	}
+	for i := 0; i < out; i++ {
+		for v := 0; v < 256; v++ {
+			b.WriteByte(byte(v))
+		}
+	}
+	return b.Bytes()
+}
+
+func textDescription(s string) []byte {
+	var b bytes.Buffer
+	b.WriteString("desc")
+	b.Write(make([]byte, 4))
+	_ = binary.Write(&b, binary.BigEndian, uint32(len(s)+1))
+	b.WriteString(s)
+	b.WriteByte(0)
+	b.Write(make([]byte, 4+4+2+1+67)) // no Unicode or ScriptCode description
+	return b.Bytes()
+}
+
+func textType(s string) []byte {
+	b := append([]byte("text\x00\x00\x00\x00"), s...)
+	return append(b, 0)
+}
+
+func xyzType(x, y, z float64) []byte {
+	return append([]byte("XYZ \x00\x00\x00\x00"), xyzNumbers(x, y, z)...)
+}
+
+func xyzNumbers(x, y, z float64) []byte {
+	return append(append(s15Fixed16(x), s15Fixed16(y)...), s15Fixed16(z)...)
+}
+
+func s15Fixed16(v float64) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(int32(math.Round(v*65536))))
+	return b
+}
+
+// labEncode maps L*a*b* to the normalized lut8 PCS encoding (L 0..100, a/b -128..127).
+func labEncode(l, a, b float64) []float64 {
+	return []float64{l / 100, (a + 128) / 255, (b + 128) / 255}
+}
+
+// rgbToLab converts normalized sRGB to CIE L*a*b* relative to D50.
+func rgbToLab(r, g, b float64) (float64, float64, float64) {
+	r, g, b = srgbToLinear(r), srgbToLinear(g), srgbToLinear(b)
+	x := 0.4360747*r + 0.3850649*g + 0.1430804*b
+	y := 0.2225045*r + 0.7168786*g + 0.0606169*b
+	z := 0.0139322*r + 0.0971045*g + 0.7141733*b
+	fx, fy, fz := labF(x/iccXn), labF(y/iccYn), labF(z/iccZn)
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+// labToRGB converts CIE L*a*b* (D50) to normalized, clamped sRGB.
+func labToRGB(l, a, b float64) (float64, float64, float64) {
+	fy := (l + 16) / 116
+	fx := fy + a/500
+	fz := fy - b/200
+	x, y, z := iccXn*labFInv(fx), iccYn*labFInv(fy), iccZn*labFInv(fz)
+	r := 3.1338561*x - 1.6168667*y - 0.4906146*z
+	g := -0.9787684*x + 1.9161415*y + 0.0334540*z
+	bl := 0.0719453*x - 0.2289914*y + 1.4052427*z
+	return clamp01(linearToSRGB(r)), clamp01(linearToSRGB(g)), clamp01(linearToSRGB(bl))
+}
+
+func labF(t float64) float64 {
+	if t > 216.0/24389 {
+		return math.Cbrt(t)
+	}
+	return (24389.0/27*t + 16) / 116
+}
+
+func labFInv(t float64) float64 {
+	if t3 := t * t * t; t3 > 216.0/24389 {
+		return t3
+	}
+	return (116*t - 16) * 27 / 24389
+}
+
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(max(v, 0), 1/2.4) - 0.055
+}
+
+func clamp01(v float64) float64 { return math.Max(0, math.Min(1, v)) }
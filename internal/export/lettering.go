@@ -0,0 +1,394 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/script"
+	"gocomicwriter/internal/storage"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// LetteringFormat is the output format of a lettering script export.
+type LetteringFormat string
+
+// Lettering script formats.
+const (
+	LetteringPDF      LetteringFormat = "pdf"
+	LetteringText     LetteringFormat = "txt"
+	LetteringMarkdown LetteringFormat = "md"
+)
+
+// LetteringUnplaced is the heading of the section listing a scene's lines that are not on any panel.
+const LetteringUnplaced = "UNPLACED"
+
+// Kinds of lettering lines.
+const (
+	letteringDialogue = "dialogue"
+	letteringCaption  = "caption"
+	letteringSFX      = "sfx"
+)
+
+// letteringLine is one numbered line of the lettering script.
+type letteringLine struct {
+	N         int
+	Kind      string
+	Character string
+	Text      string
+}
+
+// letteringPanel is a panel with the script lines of the beats linked to it. Number is the panel's
+// position in zOrder on its page, the order the canvas and the panel lists use.
+type letteringPanel struct {
+	Number int
+	ID     string
+	Notes  string
+	Lines  []letteringLine
+}
+
+// letteringPage is a page of the lettering script.
+type letteringPage struct {
+	Number int
+	Notes  string
+	Panels []letteringPanel
+}
+
+// letteringScene holds the lines of a scene that are not on any panel.
+type letteringScene struct {
+	Title string
+	Lines []letteringLine
+}
+
+// letteringDoc is the content of a lettering script, independent of the output format.
+type letteringDoc struct {
+	Title    string
+	Pages    []letteringPage
+	Unplaced []letteringScene
+}
+
+// buildLetteringDoc lays out the script lines of issue iss by page and panel. A dialogue, caption or
+// SFX line belongs to the beat before it in its scene and goes to the panel that beat is linked to;
+// a beat linked from several panels counts for the first of them. Lines are numbered per page.
+// Lines before a scene's first beat or under a beat without a panel go to the scene's unplaced
+// section; a scene is only listed when none of its beats is placed in another issue, and lines
+// under beats placed in another issue are left out.
+func buildLetteringDoc(p domain.Project, issueIdx int, sc script.Script) letteringDoc {
+	iss := p.Issues[issueIdx]
+	doc := letteringDoc{Title: fmt.Sprintf("%s — Issue %d — Lettering script", p.Name, issueIdx+1)}
+	pages := append([]domain.Page(nil), iss.Pages...)
+	sort.SliceStable(pages, func(i, j int) bool { return pages[i].Number < pages[j].Number })
+	type slot struct{ page, panel int }
+	beatSlot := map[string]slot{}
+	for pi, pg := range pages {
+		panels := append([]domain.Panel(nil), pg.Panels...)
+		sort.SliceStable(panels, func(i, j int) bool { return panels[i].ZOrder < panels[j].ZOrder })
+		lp := letteringPage{Number: pg.Number, Notes: strings.TrimSpace(pg.Notes)}
+		for ni, pn := range panels {
+			lp.Panels = append(lp.Panels, letteringPanel{Number: ni + 1, ID: pn.ID, Notes: strings.TrimSpace(pn.Notes)})
+			for _, id := range pn.BeatIDs {
+				if _, ok := beatSlot[id]; id != "" && !ok {
+					beatSlot[id] = slot{pi, ni}
+				}
+			}
+		}
+		doc.Pages = append(doc.Pages, lp)
+	}
+	mapped := storage.MappedBeatSet(p)
+	for _, scn := range sc.Scenes {
+		var unplaced []letteringLine
+		elsewhere := false
+		cur, placed, other := slot{}, false, false
+		for _, ln := range scn.Lines {
+			if ln.Type == script.LineBeat {
+				id := storage.BeatIDFor(ln)
+				cur, placed = beatSlot[id]
+				_, isMapped := mapped[id]
+				other = !placed && isMapped
+				elsewhere = elsewhere || other
+				continue
+			}
+			kind := letteringKind(ln)
+			if kind == "" || other {
+				continue
+			}
+			l := letteringLine{Kind: kind, Character: ln.Character, Text: ln.Text}
+			if !placed {
+				unplaced = append(unplaced, l)
+				continue
+			}
+			pg := &doc.Pages[cur.page]
+			pn := &pg.Panels[cur.panel]
+			pn.Lines = append(pn.Lines, l)
+		}
+		if len(unplaced) > 0 && !elsewhere {
+			doc.Unplaced = append(doc.Unplaced, letteringScene{Title: strings.TrimSpace(scn.Title), Lines: unplaced})
+		}
+	}
+	// Number after placing: several scenes can put lines on one page
+	for i := range doc.Pages {
+		n := 0
+		for j := range doc.Pages[i].Panels {
+			for k := range doc.Pages[i].Panels[j].Lines {
+				n++
+				doc.Pages[i].Panels[j].Lines[k].N = n
+			}
+		}
+	}
+	for i := range doc.Unplaced {
+		for k := range doc.Unplaced[i].Lines {
+			doc.Unplaced[i].Lines[k].N = k + 1
+		}
+	}
+	return doc
+}
+
+// letteringKind returns the lettering kind of a script line, or "" for lines that are not lettered.
+// SFX are written as dialogue of the speaker "SFX".
+func letteringKind(ln script.Line) string {
+	switch ln.Type {
+	case script.LineCaption:
+		return letteringCaption
+	case script.LineDialogue:
+		if strings.EqualFold(strings.TrimSpace(ln.Character), "SFX") {
+			return letteringSFX
+		}
+		return letteringDialogue
+	}
+	return ""
+}
+
+// label is the speaker column of the line: the character name, or CAPTION/SFX.
+func (l letteringLine) label() string {
+	switch l.Kind {
+	case letteringCaption:
+		if c := strings.ToUpper(strings.TrimSpace(l.Character)); c != "" {
+			return c
+		}
+		return "CAPTION"
+	case letteringSFX:
+		return "SFX"
+	}
+	return strings.ToUpper(strings.TrimSpace(l.Character))
+}
+
+// unplacedHeading is the heading of a scene's unplaced section.
+func (s letteringScene) heading() string {
+	if s.Title == "" {
+		return LetteringUnplaced
+	}
+	return LetteringUnplaced + " — " + s.Title
+}
+
+// ExportLetteringScript writes the script of an issue as a lettering draft: per page and panel the
+// notes and the numbered dialogue, captions and SFX, followed by the lines not placed on any panel.
+// Pages and panels are numbered like the canvas and the other exports. format selects PDF, plain text
+// or Markdown; outPath gets the format's extension when it has none.
+func ExportLetteringScript(ph *storage.ProjectHandle, issueIndex int, outPath string, format LetteringFormat) (err error) {
+	if ph == nil {
+		return fmt.Errorf("project handle is nil")
+	}
+	switch format {
+	case LetteringPDF, LetteringText, LetteringMarkdown:
+	default:
+		return fmt.Errorf("unknown lettering script format %q", format)
+	}
+	outPath = exportOutPath(ph, outPath, "."+string(format))
+	run := beginReport(ph, "lettering-"+string(format), issueIndex, nil, map[string]string{"format": string(format)}, outPath+ReportSuffix, false)
+	defer func() { err = run.finish([]string{outPath}, err) }()
+	if issueIndex < 0 || issueIndex >= len(ph.Project.Issues) {
+		return fmt.Errorf("issue index out of range")
+	}
+	text, err := storage.ReadScript(ph)
+	if err != nil {
+		return fmt.Errorf("read script: %w", err)
+	}
+	sc, errs := script.Parse(text)
+	if len(errs) > 0 {
+		run.warn(fmt.Sprintf("script has %d parse errors; affected lines may be missing", len(errs)))
+	}
+	doc := buildLetteringDoc(ph.Project, issueIndex, sc)
+	var b []byte
+	switch format {
+	case LetteringPDF:
+		if b, err = letteringPDF(doc); err != nil {
+			return err
+		}
+	case LetteringMarkdown:
+		b = letteringMarkdown(doc)
+	default:
+		b = letteringText(doc)
+	}
+	if err := os.WriteFile(outPath, b, 0o644); err != nil {
+		return fmt.Errorf("write lettering script: %w", err)
+	}
+	return nil
+}
+
+// letteringText renders the lettering script as plain text. Continuation lines of a text are
+// indented under its first line.
+func letteringText(doc letteringDoc) []byte {
+	var b bytes.Buffer
+	b.WriteString(doc.Title + "\n")
+	line := func(l letteringLine) {
+		head := fmt.Sprintf("    %d. %s: ", l.N, l.label())
+		b.WriteString(head + indentLines(l.Text, strings.Repeat(" ", len([]rune(head)))) + "\n")
+	}
+	for _, pg := range doc.Pages {
+		fmt.Fprintf(&b, "\nPAGE %d\n", pg.Number)
+		if pg.Notes != "" {
+			b.WriteString("  " + indentLines(pg.Notes, "  ") + "\n")
+		}
+		for _, pn := range pg.Panels {
+			fmt.Fprintf(&b, "\n  Panel %d\n", pn.Number)
+			if pn.Notes != "" {
+				b.WriteString("    " + indentLines(pn.Notes, "    ") + "\n")
+			}
+			for _, l := range pn.Lines {
+				line(l)
+			}
+		}
+	}
+	for _, s := range doc.Unplaced {
+		fmt.Fprintf(&b, "\n%s\n", s.heading())
+		for _, l := range s.Lines {
+			line(l)
+		}
+	}
+	return b.Bytes()
+}
+
+// letteringMarkdown renders the lettering script as Markdown with a heading per page and panel.
+func letteringMarkdown(doc letteringDoc) []byte {
+	var b bytes.Buffer
+	b.WriteString("# " + doc.Title + "\n")
+	list := func(ls []letteringLine) {
+		b.WriteString("\n")
+		for _, l := range ls {
+			head := fmt.Sprintf("%d. ", l.N)
+			fmt.Fprintf(&b, "%s**%s:** %s\n", head, l.label(), indentLines(strings.ReplaceAll(l.Text, "\n", "  \n"), strings.Repeat(" ", len(head))))
+		}
+	}
+	for _, pg := range doc.Pages {
+		fmt.Fprintf(&b, "\n## Page %d\n", pg.Number)
+		if pg.Notes != "" {
+			b.WriteString("\n" + pg.Notes + "\n")
+		}
+		for _, pn := range pg.Panels {
+			fmt.Fprintf(&b, "\n### Panel %d\n", pn.Number)
+			if pn.Notes != "" {
+				b.WriteString("\n_" + strings.ReplaceAll(pn.Notes, "\n", "_  \n_") + "_\n")
+			}
+			if len(pn.Lines) > 0 {
+				list(pn.Lines)
+			}
+		}
+	}
+	for _, s := range doc.Unplaced {
+		fmt.Fprintf(&b, "\n## %s\n", s.heading())
+		list(s.Lines)
+	}
+	return b.Bytes()
+}
+
+// indentLines prefixes every line of s after the first with indent.
+func indentLines(s, indent string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+indent)
+}
+
+// letteringPDF renders the lettering script as an A4 PDF. The Go fonts are embedded so any text of
+// the script prints, not only Latin-1.
+func letteringPDF(doc letteringDoc) ([]byte, error) {
+	const (
+		family = "GoRegular"
+		margin = 56.0
+		body   = 11.0
+		lineH  = 15.0
+		numW   = 24.0
+		nameW  = 110.0
+	)
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.AddUTF8FontFromBytes(family, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(family, "B", gobold.TTF)
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.SetTitle(doc.Title, true)
+	pdf.SetAuthor("Go Comic Writer", false)
+	created := time.Now()
+	pdf.SetCreationDate(created)
+	pdf.SetModificationDate(created)
+	pageW, pageH := pdf.GetPageSize()
+	textW := pageW - 2*margin
+	pdf.AddPage()
+	pdf.SetFont(family, "B", 16)
+	pdf.MultiCell(textW, 20, doc.Title, "", "L", false)
+	heading := func(s string, size float64, newPage bool) {
+		if newPage {
+			pdf.AddPage()
+		} else {
+			pdf.Ln(lineH / 2)
+		}
+		pdf.SetFont(family, "B", size)
+		pdf.MultiCell(textW, size*1.4, s, "", "L", false)
+	}
+	notes := func(s string, indent float64) {
+		if s == "" {
+			return
+		}
+		pdf.SetFont(family, "", body-1)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.SetX(margin + indent)
+		pdf.MultiCell(textW-indent, lineH-2, s, "", "L", false)
+		pdf.SetTextColor(0, 0, 0)
+	}
+	lines := func(ls []letteringLine, indent float64) {
+		for _, l := range ls {
+			y := pdf.GetY()
+			// Keep number, name and the first line of the text on one page
+			if y+lineH > pageH-margin {
+				pdf.AddPage()
+				y = pdf.GetY()
+			}
+			pdf.SetFont(family, "", body)
+			pdf.SetXY(margin+indent, y)
+			pdf.CellFormat(numW, lineH, fmt.Sprintf("%d.", l.N), "", 0, "R", false, 0, "")
+			pdf.SetFont(family, "B", body)
+			pdf.CellFormat(nameW, lineH, " "+l.label(), "", 0, "L", false, 0, "")
+			pdf.SetFont(family, "", body)
+			pdf.MultiCell(textW-indent-numW-nameW, lineH, l.Text, "", "L", false)
+		}
+	}
+	for i, pg := range doc.Pages {
+		heading(fmt.Sprintf("PAGE %d", pg.Number), 14, i > 0)
+		notes(pg.Notes, 0)
+		for _, pn := range pg.Panels {
+			heading(fmt.Sprintf("Panel %d", pn.Number), 12, false)
+			notes(pn.Notes, 12)
+			lines(pn.Lines, 12)
+		}
+	}
+	for i, s := range doc.Unplaced {
+		heading(s.heading(), 14, i == 0 && len(doc.Pages) > 0)
+		lines(s.Lines, 0)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render lettering pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/script"
+	"gocomicwriter/internal/storage"
+)
+
+// letteringProject has two pages stored out of order; page 1's panels are stored against their zOrder.
+func letteringProject(t *testing.T) *storage.ProjectHandle {
+	p := domain.Project{Name: "Demo", Issues: []domain.Issue{{Pages: []domain.Page{
+		{Number: 2, Panels: []domain.Panel{{ID: "c", BeatIDs: []string{"b:9"}}}},
+		{Number: 1, Notes: "Night", Panels: []domain.Panel{
+			{ID: "b", ZOrder: 1, Notes: "Close on Bob", BeatIDs: []string{"b:5"}},
+			{ID: "a", ZOrder: 0, Notes: "Wide", BeatIDs: []string{"b:2"}},
+		}},
+	}}}}
+	ph := &storage.ProjectHandle{Root: t.TempDir(), Project: p}
+	text := strings.Join([]string{
+		"# Rooftop",         // 1
+		"Beat Wide",         // 2
+		"CAPTION: Later.",   // 3
+		"ALICE: Hello",      // 4
+		"Beat Close",        // 5
+		"BOB: Hi",           // 6
+		"  there",           // 7
+		"# Street",          // 8
+		"Beat Car",          // 9
+		"SFX: VROOM",        // 10
+		"Beat Not drawn",    // 11
+		"ALICE: Wait!",      // 12
+		"# Cut",             // 13
+		"CAROL: Goodbye",    // 14
+		"; a note, no line", // 15
+	}, "\n")
+	if err := os.MkdirAll(filepath.Dir(storage.ScriptFilePath(ph)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(storage.ScriptFilePath(ph), []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return ph
+}
+
+func TestBuildLetteringDoc(t *testing.T) {
+	ph := letteringProject(t)
+	text, _ := storage.ReadScript(ph)
+	sc, _ := script.Parse(text)
+	doc := buildLetteringDoc(ph.Project, 0, sc)
+
+	if len(doc.Pages) != 2 || doc.Pages[0].Number != 1 || doc.Pages[1].Number != 2 {
+		t.Fatalf("pages = %+v, want 1 and 2 in order", doc.Pages)
+	}
+	p1 := doc.Pages[0]
+	if p1.Notes != "Night" || len(p1.Panels) != 2 || p1.Panels[0].ID != "a" || p1.Panels[1].ID != "b" {
+		t.Fatalf("page 1 = %+v, want panels a, b in zOrder", p1)
+	}
+	want := []letteringLine{
+		{N: 1, Kind: letteringCaption, Character: "CAPTION", Text: "Later."},
+		{N: 2, Kind: letteringDialogue, Character: "ALICE", Text: "Hello"},
+	}
+	if got := p1.Panels[0].Lines; !equalLetteringLines(got, want) {
+		t.Errorf("panel 1 lines = %+v, want %+v", got, want)
+	}
+	want = []letteringLine{{N: 3, Kind: letteringDialogue, Character: "BOB", Text: "Hi\nthere"}}
+	if got := p1.Panels[1].Lines; !equalLetteringLines(got, want) {
+		t.Errorf("panel 2 lines = %+v, want %+v", got, want)
+	}
+	// Numbering restarts on every page
+	want = []letteringLine{{N: 1, Kind: letteringSFX, Character: "SFX", Text: "VROOM"}}
+	if got := doc.Pages[1].Panels[0].Lines; !equalLetteringLines(got, want) {
+		t.Errorf("page 2 lines = %+v, want %+v", got, want)
+	}
+	if len(doc.Unplaced) != 2 || doc.Unplaced[0].Title != "Street" || doc.Unplaced[1].Title != "Cut" {
+		t.Fatalf("unplaced = %+v, want Street and Cut", doc.Unplaced)
+	}
+	if got := doc.Unplaced[0].Lines; len(got) != 1 || got[0].Text != "Wait!" || got[0].N != 1 {
+		t.Errorf("Street unplaced = %+v", got)
+	}
+	if got := doc.Unplaced[1].Lines; len(got) != 1 || got[0].Character != "CAROL" {
+		t.Errorf("Cut unplaced = %+v", got)
+	}
+
+	// A scene placed in another issue is not listed as unplaced here, and its lines stay out
+	ph.Project.Issues = append(ph.Project.Issues, domain.Issue{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{{ID: "x", BeatIDs: []string{"b:2"}}}}}})
+	ph.Project.Issues[0].Pages[0].Panels[0].BeatIDs = nil // page 2: the Street scene is placed nowhere
+	doc = buildLetteringDoc(ph.Project, 1, sc)
+	if n := len(doc.Pages[0].Panels[0].Lines); n != 2 {
+		t.Errorf("issue 2 panel lines = %d, want 2", n)
+	}
+	if len(doc.Unplaced) != 2 || doc.Unplaced[0].Title != "Street" || len(doc.Unplaced[0].Lines) != 2 {
+		t.Errorf("issue 2 unplaced = %+v, want Street with both lines and Cut", doc.Unplaced)
+	}
+}
+
+func equalLetteringLines(a, b []letteringLine) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestExportLetteringScript(t *testing.T) {
+	ph := letteringProject(t)
+	out := filepath.Join(ph.Root, "letters")
+	if err := ExportLetteringScript(ph, 0, out, LetteringText); err != nil {
+		t.Fatalf("export txt: %v", err)
+	}
+	b, err := os.ReadFile(out + ".txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	txt := string(b)
+	for _, want := range []string{"PAGE 1\n  Night\n", "  Panel 1\n    Wide\n    1. CAPTION: Later.\n    2. ALICE: Hello\n", "    3. BOB: Hi\n            there\n", "PAGE 2", "    1. SFX: VROOM", "UNPLACED — Street\n    1. ALICE: Wait!"} {
+		if !strings.Contains(txt, want) {
+			t.Errorf("text export lacks %q:\n%s", want, txt)
+		}
+	}
+	if strings.Index(txt, "PAGE 1") > strings.Index(txt, "PAGE 2") {
+		t.Error("pages not in number order")
+	}
+	if _, err := os.Stat(out + ".txt" + ReportSuffix); err != nil {
+		t.Errorf("no export report: %v", err)
+	}
+
+	if err := ExportLetteringScript(ph, 0, out, LetteringMarkdown); err != nil {
+		t.Fatalf("export md: %v", err)
+	}
+	b, _ = os.ReadFile(out + ".md")
+	for _, want := range []string{"# Demo — Issue 1 — Lettering script", "## Page 1", "### Panel 2\n\n_Close on Bob_\n\n3. **BOB:** Hi  \n   there\n", "## UNPLACED — Cut"} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("markdown export lacks %q:\n%s", want, b)
+		}
+	}
+
+	if err := ExportLetteringScript(ph, 0, out, LetteringPDF); err != nil {
+		t.Fatalf("export pdf: %v", err)
+	}
+	b, _ = os.ReadFile(out + ".pdf")
+	if !bytes.HasPrefix(b, []byte("%PDF-")) {
+		t.Errorf("not a PDF: %q", b[:min(len(b), 16)])
+	}
+
+	if err := ExportLetteringScript(ph, 0, out, "odt"); err == nil {
+		t.Error("unknown format accepted")
+	}
+	if err := ExportLetteringScript(ph, 3, out, LetteringText); err == nil {
+		t.Error("issue out of range accepted")
+	}
+}
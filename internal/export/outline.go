@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// pdfOutlineItem is one bookmark of the PDF outline, pointing at the top of a page.
+type pdfOutlineItem struct {
+	title string
+	sheet int     // 0-based page of the PDF
+	left  float64 // x of the destination in points; the right page of a spread starts at bleed+trim width
+	kids  []pdfOutlineItem
+}
+
+var (
+	reCatalogPages = regexp.MustCompile(`/Pages (\d+) 0 R`)
+	rePagesKids    = regexp.MustCompile(`/Kids \[([^\]]*)\]`)
+	reObjectRef    = regexp.MustCompile(`(\d+) 0 R`)
+)
+
+// pdfOutline builds the outline of an exported issue: the title page, then one entry per comic page
+// ("Page 12"). A scene starts a top-level entry on the page of its first mapped beat, and the pages
+// up to the next scene are nested under it. Scenes on pages that are not exported are left out.
+func pdfOutline(iss domain.Issue, sheets []sheet, titlePage bool, scenes []storage.SceneStart, trimW, bleed float64) []pdfOutlineItem {
+	var out []pdfOutlineItem
+	k := 0
+	if titlePage {
+		out = append(out, pdfOutlineItem{title: titlePageLabel})
+		k++
+	}
+	placed := make([]bool, len(scenes))
+	inScene := false
+	for si, sh := range sheets {
+		pidxs, offsets := sh.pages(trimW)
+		order := make([]int, len(pidxs))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return iss.Pages[pidxs[order[a]]].Number < iss.Pages[pidxs[order[b]]].Number })
+		for _, i := range order {
+			num := iss.Pages[pidxs[i]].Number
+			left := 0.0
+			if offsets[i] != 0 {
+				left = bleed + offsets[i]
+			}
+			for j, sc := range scenes {
+				if !placed[j] && sc.Page == num {
+					placed[j] = true
+					out = append(out, pdfOutlineItem{title: sc.Title, sheet: k + si, left: left})
+					inScene = true
+				}
+			}
+			page := pdfOutlineItem{title: fmt.Sprintf("Page %d", num), sheet: k + si, left: left}
+			if inScene {
+				out[len(out)-1].kids = append(out[len(out)-1].kids, page)
+			} else {
+				out = append(out, page)
+			}
+		}
+	}
+	return out
+}
+
+// pdfPageRefs returns the object numbers of the pages of a finished gofpdf document in page order.
+func pdfPageRefs(doc []byte, catalog string) ([]int, error) {
+	m := reCatalogPages.FindStringSubmatch(catalog)
+	if m == nil {
+		return nil, fmt.Errorf("no page tree in generated PDF")
+	}
+	n, _ := strconv.Atoi(m[1])
+	pages, err := objectDict(doc, n)
+	if err != nil {
+		return nil, err
+	}
+	kids := rePagesKids.FindStringSubmatch(pages)
+	if kids == nil {
+		return nil, fmt.Errorf("no page list in generated PDF")
+	}
+	var out []int
+	for _, ref := range reObjectRef.FindAllStringSubmatch(kids[1], -1) {
+		k, _ := strconv.Atoi(ref[1])
+		out = append(out, k)
+	}
+	return out, nil
+}
+
+// appendPDFOutline adds the outline to a finished gofpdf document with an incremental update and
+// makes viewers open with the bookmarks shown. Every entry is open; destinations are the top of
+// their page at height mediaH.
+func appendPDFOutline(doc []byte, items []pdfOutlineItem, mediaH float64) ([]byte, error) {
+	if len(items) == 0 {
+		return doc, nil
+	}
+	t, err := readPDFTrailer(doc)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := pdfPageRefs(doc, t.catalog)
+	if err != nil {
+		return nil, err
+	}
+	u := newPDFUpdate(doc)
+	root := t.size
+	next := root + 1
+	// level writes a list of siblings under parent and returns their first and last object numbers
+	// and the number of entries including all descendants.
+	var level func(items []pdfOutlineItem, parent int) (first, last, count int, err error)
+	level = func(items []pdfOutlineItem, parent int) (int, int, int, error) {
+		nums := make([]int, len(items))
+		for i := range items {
+			nums[i] = next
+			next++
+		}
+		count := len(items)
+		for i, it := range items {
+			if it.sheet < 0 || it.sheet >= len(pages) {
+				return 0, 0, 0, fmt.Errorf("outline entry %q points past the last page", it.title)
+			}
+			body := fmt.Sprintf("<< /Title %s /Parent %d 0 R /Dest [%d 0 R /XYZ %.2f %.2f null]", pdfString(it.title), parent, pages[it.sheet], it.left, mediaH)
+			if i > 0 {
+				body += fmt.Sprintf(" /Prev %d 0 R", nums[i-1])
+			}
+			if i < len(items)-1 {
+				body += fmt.Sprintf(" /Next %d 0 R", nums[i+1])
+			}
+			if len(it.kids) > 0 {
+				first, last, n, err := level(it.kids, nums[i])
+				if err != nil {
+					return 0, 0, 0, err
+				}
+				body += fmt.Sprintf(" /First %d 0 R /Last %d 0 R /Count %d", first, last, n)
+				count += n
+			}
+			u.obj(nums[i], body+" >>", nil)
+		}
+		return nums[0], nums[len(nums)-1], count, nil
+	}
+	first, last, count, err := level(items, root)
+	if err != nil {
+		return nil, err
+	}
+	u.obj(root, fmt.Sprintf("<< /Type /Outlines /First %d 0 R /Last %d 0 R /Count %d >>", first, last, count), nil)
+	u.obj(t.root, fmt.Sprintf("<<%s\n/Outlines %d 0 R\n/PageMode /UseOutlines\n>>", t.catalog, root), nil)
+	return u.finish(t, next, ""), nil
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// outlineEntry is a bookmark read back from an exported PDF.
+type outlineEntry struct {
+	Title string
+	Page  int // 0-based position in the page tree
+	Left  float64
+	Kids  []outlineEntry
+}
+
+var (
+	reOutlineTitle = regexp.MustCompile(`/Title (\([^)]*\)|<[0-9A-F]+>)`)
+	reOutlineDest  = regexp.MustCompile(`/Dest \[(\d+) 0 R /XYZ ([\d.]+) ([\d.]+) null\]`)
+)
+
+// readPDFOutline parses the outline dictionary of doc by following /First and /Next.
+func readPDFOutline(t *testing.T, doc []byte) (entries []outlineEntry, catalog string) {
+	t.Helper()
+	tr, err := readPDFTrailer(doc)
+	if err != nil {
+		t.Fatalf("trailer: %v", err)
+	}
+	pages, err := pdfPageRefs(doc, tr.catalog)
+	if err != nil {
+		t.Fatalf("pages: %v", err)
+	}
+	pageAt := map[int]int{}
+	for i, n := range pages {
+		pageAt[n] = i
+	}
+	ref := func(dict, key string) int {
+		m := regexp.MustCompile(`/` + key + ` (\d+) 0 R`).FindStringSubmatch(dict)
+		if m == nil {
+			return 0
+		}
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	dict := func(n int) string {
+		d, err := objectDict(doc, n)
+		if err != nil {
+			t.Fatalf("outline object: %v", err)
+		}
+		return d
+	}
+	var walk func(first int) []outlineEntry
+	walk = func(first int) []outlineEntry {
+		var out []outlineEntry
+		for n := first; n != 0; {
+			d := dict(n)
+			title := reOutlineTitle.FindStringSubmatch(d)
+			dest := reOutlineDest.FindStringSubmatch(d)
+			if title == nil || dest == nil {
+				t.Fatalf("outline entry %d incomplete: %s", n, d)
+			}
+			pn, _ := strconv.Atoi(dest[1])
+			page, ok := pageAt[pn]
+			if !ok {
+				t.Fatalf("outline entry %d points at object %d, not a page", n, pn)
+			}
+			left, _ := strconv.ParseFloat(dest[2], 64)
+			out = append(out, outlineEntry{Title: title[1], Page: page, Left: left, Kids: walk(ref(d, "First"))})
+			n = ref(d, "Next")
+		}
+		return out
+	}
+	root := ref(tr.catalog, "Outlines")
+	if root == 0 {
+		return nil, tr.catalog
+	}
+	return walk(ref(dict(root), "First")), tr.catalog
+}
+
+func TestExportIssuePDF_Outline(t *testing.T) {
+	ph := spreadProject(t)
+	iss := &ph.Project.Issues[0]
+	iss.Pages[0].Panels[0].BeatIDs = []string{"b:2"}
+	iss.Pages[2].Panels = []domain.Panel{{ID: "p1", Geometry: domain.Rect{X: 18, Y: 18, Width: 100, Height: 100}, BeatIDs: []string{"b:5"}}}
+	iss.Pages = append(iss.Pages, domain.Page{Number: 7})
+	script := strings.Join([]string{
+		"# Rooftop",
+		"Beat Wide shot",
+		"ALICE: Hello",
+		"# Über chase",
+		"Beat Running",
+		"# Cut scene",
+		"Beat Never drawn",
+	}, "\n")
+	if err := os.MkdirAll(filepath.Dir(storage.ScriptFilePath(ph)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(storage.ScriptFilePath(ph), []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, profile := range []OutputProfile{OutputProfileDefault, OutputProfilePDFX1a} {
+		opt := DefaultPDFOptions()
+		opt.OutputProfile = profile
+		out := filepath.Join(ph.Root, "outline.pdf")
+		if err := ExportIssuePDF(ph, 0, out, opt); err != nil {
+			t.Fatalf("export %v: %v", profile, err)
+		}
+		doc, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		got, catalog := readPDFOutline(t, doc)
+		// Sheets: page 1, spread 2-3 (page 3 starts at bleed + trim width), page 7
+		want := []outlineEntry{
+			{Title: "(Rooftop)", Page: 0, Kids: []outlineEntry{
+				{Title: "(Page 1)", Page: 0},
+				{Title: "(Page 2)", Page: 1},
+			}},
+			{Title: pdfString("Über chase"), Page: 1, Left: 378, Kids: []outlineEntry{
+				{Title: "(Page 3)", Page: 1, Left: 378},
+				{Title: "(Page 7)", Page: 2},
+			}},
+		}
+		if !outlineEqual(got, want) {
+			t.Errorf("%v: outline = %+v, want %+v", profile, got, want)
+		}
+		if !strings.Contains(catalog, "/PageMode /UseOutlines") {
+			t.Errorf("%v: catalog does not open the outline: %s", profile, catalog)
+		}
+		if !strings.Contains(catalog, "/PageLabels << /Nums [0 << /S /D /St 1 >> 1 << /P (2-3) >> 2 << /S /D /St 7 >>] >>") {
+			t.Errorf("%v: page labels do not follow the comic's numbers: %s", profile, catalog)
+		}
+	}
+
+	out := filepath.Join(ph.Root, "plain.pdf")
+	if err := ExportIssuePDF(ph, 0, out, PDFOptions{}); err != nil {
+		t.Fatalf("export without outline: %v", err)
+	}
+	doc, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if got, _ := readPDFOutline(t, doc); got != nil {
+		t.Errorf("outline written although IncludeOutline is off: %+v", got)
+	}
+}
+
+func outlineEqual(a, b []outlineEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Title != b[i].Title || a[i].Page != b[i].Page || a[i].Left != b[i].Left || !outlineEqual(a[i].Kids, b[i].Kids) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPDFPageLabels_Numbers(t *testing.T) {
+	iss := domain.Issue{Pages: []domain.Page{{Number: 1}, {Number: 2}, {Number: 3}, {Number: 5}, {Number: 0}}}
+	all := issueSheets(iss, pageIndexes(len(iss.Pages), nil))
+	if got := pdfPageLabels(iss, issueSheets(iss, []int{0, 1, 2}), false); got != "" {
+		t.Errorf("pages 1-3 need no labels, got %q", got)
+	}
+	want := "/PageLabels << /Nums [0 << /S /D /St 1 >> 3 << /S /D /St 5 >> 4 << /P (0) >>] >>"
+	if got := pdfPageLabels(iss, all, false); got != want {
+		t.Errorf("labels = %q, want %q", got, want)
+	}
+	want = "/PageLabels << /Nums [0 << /S /D /St 2 >>] >>"
+	if got := pdfPageLabels(iss, issueSheets(iss, []int{1, 2}), false); got != want {
+		t.Errorf("selection labels = %q, want %q", got, want)
+	}
+}
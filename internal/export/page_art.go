@@ -0,0 +1,266 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // decode GIF page art
+	"math"
+	"mime"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// artImage is a decoded art file and its encoded bytes, which SVG embeds as they are.
+type artImage struct {
+	img  image.Image
+	data []byte
+	mime string
+}
+
+// pageArt loads the art references of exported pages (domain.ReferenceImage with Art set) once per
+// export. A file that cannot be read is reported once and its pages are exported without art. A nil
+// *pageArt draws nothing.
+type pageArt struct {
+	root   string
+	run    *exportRun
+	images map[string]*artImage // nil for unreadable files
+}
+
+func newPageArt(ph *storage.ProjectHandle, run *exportRun) *pageArt {
+	return &pageArt{root: ph.Root, run: run, images: map[string]*artImage{}}
+}
+
+// art returns the art image of pg and its reference, or nil when the page has none.
+func (a *pageArt) art(pg domain.Page) (*artImage, *domain.ReferenceImage) {
+	ref := pg.ReferenceImage
+	if a == nil || ref == nil || !ref.Art || strings.TrimSpace(ref.Asset) == "" {
+		return nil, nil
+	}
+	if im, ok := a.images[ref.Asset]; ok {
+		return im, ref
+	}
+	im, err := loadArtImage(storage.AssetFilePath(a.root, ref.Asset))
+	if err != nil {
+		a.run.warn(fmt.Sprintf("page %d: art %s not drawn: %v", pg.Number, ref.Asset, err))
+	}
+	a.images[ref.Asset] = im
+	return im, ref
+}
+
+func loadArtImage(path string) (*artImage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+	return &artImage{img: img, data: data, mime: mime.TypeByExtension("." + format)}, nil
+}
+
+// artPlacement returns where page k of sheet sh draws its art, in the sheet's trim coordinates. dest
+// is the rectangle the image is scaled into: the trim box for stretch, the trim box plus bleed for
+// bleed, and the largest centered rectangle of the image's aspect ratio inside the trim box for
+// contain. clip is the area the art may cover: the page's trim box with its bleed, except across a
+// spread's spine.
+func artPlacement(iss domain.Issue, sh sheet, k int, ref *domain.ReferenceImage, img image.Image) (dest, clip domain.Rect) {
+	_, offsets := sh.pages(iss.TrimWidth)
+	b := iss.Bleed
+	trim := domain.Rect{X: offsets[k], Width: iss.TrimWidth, Height: iss.TrimHeight}
+	clip = domain.Rect{X: trim.X - b, Y: -b, Width: trim.Width + 2*b, Height: trim.Height + 2*b}
+	if sh.spread() {
+		clip.Width -= b
+		if k == 1 {
+			clip.X += b
+		}
+	}
+	switch ref.Fit {
+	case domain.ReferenceFitStretch:
+		return trim, clip
+	case domain.ReferenceFitBleed:
+		return domain.Rect{X: trim.X - b, Y: -b, Width: trim.Width + 2*b, Height: trim.Height + 2*b}, clip
+	}
+	iw, ih := float64(img.Bounds().Dx()), float64(img.Bounds().Dy())
+	if iw <= 0 || ih <= 0 {
+		return trim, clip
+	}
+	s := min(trim.Width/iw, trim.Height/ih)
+	w, h := iw*s, ih*s
+	return domain.Rect{X: trim.X + (trim.Width-w)/2, Y: trim.Y + (trim.Height-h)/2, Width: w, Height: h}, clip
+}
+
+// drawSheet draws the art of the sheet's pages into img, whose origin lies at (-ox, -oy) points in
+// the sheet's trim coordinates, i.e. the trim box starts at (ox, oy) points.
+func (a *pageArt) drawSheet(img *image.RGBA, iss domain.Issue, sh sheet, ox, oy, scale float64) {
+	pidxs, _ := sh.pages(iss.TrimWidth)
+	px := func(r domain.Rect) image.Rectangle {
+		return image.Rect(int(math.Round((r.X+ox)*scale)), int(math.Round((r.Y+oy)*scale)),
+			int(math.Round((r.X+ox+r.Width)*scale)), int(math.Round((r.Y+oy+r.Height)*scale)))
+	}
+	for k, pidx := range pidxs {
+		im, ref := a.art(iss.Pages[pidx])
+		if im == nil {
+			continue
+		}
+		dest, clip := artPlacement(iss, sh, k, ref, im.img)
+		area := px(clip).Intersect(img.Bounds())
+		if area.Empty() {
+			continue
+		}
+		xdraw.ApproxBiLinear.Scale(img.SubImage(area).(*image.RGBA), px(dest), im.img, im.img.Bounds(), xdraw.Over, nil)
+	}
+}
+
+// svgSheet returns the SVG elements drawing the art of the sheet's pages, with the trim box at (ox, oy).
+// Each image is embedded as a data URI in a nested viewport that clips it to its page.
+func (a *pageArt) svgSheet(iss domain.Issue, sh sheet, ox, oy float64) string {
+	var b strings.Builder
+	pidxs, _ := sh.pages(iss.TrimWidth)
+	for k, pidx := range pidxs {
+		im, ref := a.art(iss.Pages[pidx])
+		if im == nil {
+			continue
+		}
+		dest, clip := artPlacement(iss, sh, k, ref, im.img)
+		fmt.Fprintf(&b, "  <svg x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\">\n", clip.X+ox, clip.Y+oy, clip.Width, clip.Height)
+		fmt.Fprintf(&b, "    <image x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" preserveAspectRatio=\"none\" href=\"data:%s;base64,%s\"/>\n",
+			dest.X-clip.X, dest.Y-clip.Y, dest.Width, dest.Height, im.mime, base64.StdEncoding.EncodeToString(im.data))
+		b.WriteString("  </svg>\n")
+	}
+	return b.String()
+}
+
+// pdfArt collects the art images of a PDF export. Pages draw them with raw operators naming image
+// XObjects that gofpdf does not know about; appendPDFArt adds the images to the finished document.
+type pdfArt struct {
+	*pageArt
+	names map[*artImage]int // XObject number of each image, in order of first use
+	order []*artImage
+}
+
+// pdfArtName is the resource name of the n-th art image.
+func pdfArtName(n int) string { return fmt.Sprintf("/GcwArt%d", n) }
+
+// drawSheet writes the operators drawing the art of the sheet's pages on the current page of pdf,
+// which is mediaH points tall with the trim box at (bleed, bleed).
+func (a *pdfArt) drawSheet(raw func(string), iss domain.Issue, sh sheet, mediaH float64) {
+	pidxs, _ := sh.pages(iss.TrimWidth)
+	b := iss.Bleed
+	for k, pidx := range pidxs {
+		im, ref := a.art(iss.Pages[pidx])
+		if im == nil {
+			continue
+		}
+		n, ok := a.names[im]
+		if !ok {
+			n = len(a.order)
+			a.names[im] = n
+			a.order = append(a.order, im)
+		}
+		dest, clip := artPlacement(iss, sh, k, ref, im.img)
+		// PDF user space starts at the bottom left of the page
+		raw(fmt.Sprintf("q %.2f %.2f %.2f %.2f re W n %.4f 0 0 %.4f %.4f %.4f cm %s Do Q",
+			clip.X+b, mediaH-(clip.Y+b+clip.Height), clip.Width, clip.Height,
+			dest.Width, dest.Height, dest.X+b, mediaH-(dest.Y+b+dest.Height), pdfArtName(n)))
+	}
+}
+
+var rePageResources = regexp.MustCompile(`/Resources (\d+) 0 R`)
+
+// appendPDFArt adds the collected art images to a finished gofpdf document with an incremental
+// update: one image XObject each, registered in the resources shared by all pages. Transparent
+// pixels are flattened onto white; cmyk writes DeviceCMYK images for PDF/X.
+func appendPDFArt(doc []byte, images []*artImage, cmyk bool) ([]byte, error) {
+	if len(images) == 0 {
+		return doc, nil
+	}
+	t, err := readPDFTrailer(doc)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := pdfPageRefs(doc, t.catalog)
+	if err != nil {
+		return nil, err
+	}
+	page, err := objectDict(doc, pages[0])
+	if err != nil {
+		return nil, err
+	}
+	m := rePageResources.FindStringSubmatch(page)
+	if m == nil {
+		return nil, fmt.Errorf("no page resources in generated PDF")
+	}
+	resN, _ := strconv.Atoi(m[1])
+	res, err := objectDict(doc, resN)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(res, "/XObject <<") {
+		return nil, fmt.Errorf("no XObject resources in generated PDF")
+	}
+	u := newPDFUpdate(doc)
+	var refs strings.Builder
+	for i, im := range images {
+		n := t.size + i
+		stream, space := pdfImageStream(im.img, cmyk)
+		b := im.img.Bounds()
+		u.obj(n, fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>",
+			b.Dx(), b.Dy(), space, len(stream)), stream)
+		fmt.Fprintf(&refs, "\n%s %d 0 R", pdfArtName(i), n)
+	}
+	u.obj(resN, "<<"+strings.Replace(res, "/XObject <<", "/XObject <<"+refs.String(), 1)+">>", nil)
+	return u.finish(t, t.size+len(images), ""), nil
+}
+
+// pdfImageStream returns the pixels of img flattened onto white, deflated, and their color space.
+func pdfImageStream(img image.Image, cmyk bool) ([]byte, string) {
+	b := img.Bounds()
+	comps, space := 3, "/DeviceRGB"
+	if cmyk {
+		comps, space = 4, "/DeviceCMYK"
+	}
+	row := make([]byte, 0, b.Dx()*comps)
+	var out bytes.Buffer
+	zw := zlib.NewWriter(&out)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row = row[:0]
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			a := float64(c.A) / 255
+			mix := func(v uint8) float64 { return float64(v)/255*a + 1 - a }
+			r, g, bl := mix(c.R), mix(c.G), mix(c.B)
+			if cmyk {
+				cc, mm, yy, kk := rgbToCMYK(r, g, bl)
+				row = append(row, unit8(cc), unit8(mm), unit8(yy), unit8(kk))
+			} else {
+				row = append(row, unit8(r), unit8(g), unit8(bl))
+			}
+		}
+		_, _ = zw.Write(row)
+	}
+	_ = zw.Close()
+	return out.Bytes(), space
+}
+
+// unit8 scales v in [0, 1] to a byte.
+func unit8(v float64) byte { return byte(math.Round(min(max(v, 0), 1) * 255)) }
@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// artProject is spreadProject with a red art image on page 1 and a green one on page 3.
+func artProject(t *testing.T) *storage.ProjectHandle {
+	ph := spreadProject(t)
+	dir := filepath.Join(ph.Root, "assets", "imported")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, c := range map[string]color.RGBA{"red.png": {255, 0, 0, 255}, "green.png": {0, 255, 0, 255}} {
+		img := image.NewRGBA(image.Rect(0, 0, 40, 60))
+		draw.Draw(img, img.Bounds(), &image.Uniform{C: c}, image.Point{}, draw.Src)
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pages := ph.Project.Issues[0].Pages
+	pages[0].ReferenceImage = &domain.ReferenceImage{Asset: "assets/imported/red.png", Fit: domain.ReferenceFitBleed, Art: true}
+	pages[2].ReferenceImage = &domain.ReferenceImage{Asset: "assets/imported/green.png", Fit: domain.ReferenceFitStretch, Art: true}
+	return ph
+}
+
+func TestArtPlacement(t *testing.T) {
+	iss := spreadProject(t).Project.Issues[0] // trim 360x540, bleed 18
+	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	ref := &domain.ReferenceImage{Fit: domain.ReferenceFitContain}
+	dest, clip := artPlacement(iss, sheet{left: 0, right: -1}, 0, ref, img)
+	if want := (domain.Rect{X: 0, Y: 90, Width: 360, Height: 360}); dest != want {
+		t.Errorf("contain = %+v, want %+v", dest, want)
+	}
+	if want := (domain.Rect{X: -18, Y: -18, Width: 396, Height: 576}); clip != want {
+		t.Errorf("single page clip = %+v, want %+v", clip, want)
+	}
+	ref.Fit = domain.ReferenceFitBleed
+	dest, clip = artPlacement(iss, sheet{left: 1, right: 2}, 1, ref, img)
+	if want := (domain.Rect{X: 342, Y: -18, Width: 396, Height: 576}); dest != want {
+		t.Errorf("bleed = %+v, want %+v", dest, want)
+	}
+	// The right page of a spread has no bleed at the spine
+	if want := (domain.Rect{X: 360, Y: -18, Width: 378, Height: 576}); clip != want {
+		t.Errorf("spread clip = %+v, want %+v", clip, want)
+	}
+}
+
+func TestExportPageArt_Raster(t *testing.T) {
+	ph := artProject(t)
+	dir := filepath.Join(ph.Root, "out")
+	if err := ExportIssuePNGPages(ph, 0, dir, PNGOptions{DPI: 72}); err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	read := func(name string) *image.RGBA {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = f.Close() }()
+		img, err := png.Decode(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := image.NewRGBA(img.Bounds())
+		draw.Draw(out, out.Bounds(), img, img.Bounds().Min, draw.Src)
+		return out
+	}
+	one := read("issue-1-page-1.png")
+	// Bleed fit covers the bleed; the panel frame is drawn over the art
+	for _, p := range []image.Point{{1, 1}, {200, 300}} {
+		if c := one.RGBAAt(p.X, p.Y); c.R < 250 || c.G > 5 {
+			t.Errorf("page 1 at %v = %v, want red art", p, c)
+		}
+	}
+	if c := one.RGBAAt(36, 300); c.R > 5 {
+		t.Errorf("panel frame hidden under the art: %v", c)
+	}
+	spread := read("issue-1-page-2-3.png")
+	// Stretch fit stays inside the trim box of page 3: the bleed and page 2 stay white
+	if c := spread.RGBAAt(18+360+100, 300); c.G < 250 || c.R > 5 {
+		t.Errorf("page 3 = %v, want green art", c)
+	}
+	for _, p := range []image.Point{{18 + 360 + 100, 5}, {100, 300}} {
+		if c := spread.RGBAAt(p.X, p.Y); c != (color.RGBA{255, 255, 255, 255}) {
+			t.Errorf("spread at %v = %v, want white", p, c)
+		}
+	}
+
+	svgDir := filepath.Join(ph.Root, "svg")
+	if err := ExportIssueSVGPages(ph, 0, svgDir, SVGOptions{}); err != nil {
+		t.Fatalf("svg: %v", err)
+	}
+	svg, err := os.ReadFile(filepath.Join(svgDir, "issue-1-page-1.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(svg)
+	if !strings.Contains(s, `<svg x="0" y="0" width="396" height="576">`) || !strings.Contains(s, `href="data:image/png;base64,`) {
+		t.Errorf("svg art missing:\n%.600s", s)
+	}
+	if strings.Index(s, "data:image/png") > strings.Index(s, `stroke="#000000"`) {
+		t.Error("svg art drawn over the panels")
+	}
+}
+
+func TestExportPageArt_PDF(t *testing.T) {
+	for _, profile := range []OutputProfile{OutputProfileDefault, OutputProfilePDFX1a} {
+		ph := artProject(t)
+		out := filepath.Join(ph.Root, "art.pdf")
+		if err := ExportIssuePDF(ph, 0, out, PDFOptions{OutputProfile: profile}); err != nil {
+			t.Fatalf("%v: %v", profile, err)
+		}
+		doc, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		space := "/DeviceRGB"
+		if profile == OutputProfilePDFX1a {
+			space = "/DeviceCMYK"
+		}
+		if n := bytes.Count(doc, []byte("/Subtype /Image /Width 40 /Height 60 /ColorSpace "+space)); n != 2 {
+			t.Errorf("%v: %d art images in %s, want 2", profile, n, space)
+		}
+		tr, err := readPDFTrailer(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages, _ := pdfPageRefs(doc, tr.catalog)
+		page, _ := objectDict(doc, pages[0])
+		resN, _ := strconv.Atoi(rePageResources.FindStringSubmatch(page)[1])
+		res, err := objectDict(doc, resN)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !regexp.MustCompile(`/GcwArt0 \d+ 0 R\s+/GcwArt1 \d+ 0 R`).MatchString(res) {
+			t.Errorf("%v: art not in the page resources: %s", profile, res)
+		}
+		// Every object of the last update is where the xref says
+		xi := bytes.LastIndex(doc, []byte("\nxref\n"))
+		for _, m := range regexp.MustCompile(`(\d+) 1\n(\d{10}) 00000 n `).FindAllSubmatch(doc[xi:], -1) {
+			off, _ := strconv.Atoi(string(m[2]))
+			if !bytes.HasPrefix(doc[off:], []byte(string(m[1])+" 0 obj")) {
+				t.Errorf("%v: xref offset %d does not point at object %s", profile, off, m[1])
+			}
+		}
+	}
+}
+
+func TestExportPageArt_MissingFileWarns(t *testing.T) {
+	ph := artProject(t)
+	ph.Project.Issues[0].Pages[0].ReferenceImage.Asset = "assets/imported/gone.png"
+	// A plain reference image is not art and is never exported
+	ph.Project.Issues[0].Pages[2].ReferenceImage.Art = false
+	run := &exportRun{}
+	art := newPageArt(ph, run)
+	img := image.NewRGBA(image.Rect(0, 0, 800, 600))
+	iss := ph.Project.Issues[0]
+	art.drawSheet(img, iss, sheet{left: 0, right: -1}, 18, 18, 1)
+	art.drawSheet(img, iss, sheet{left: 0, right: -1}, 18, 18, 1)
+	art.drawSheet(img, iss, sheet{left: 1, right: 2}, 18, 18, 1)
+	if len(run.report.Warnings) != 1 || !strings.Contains(run.report.Warnings[0], "gone.png") {
+		t.Errorf("warnings = %v, want one about gone.png", run.report.Warnings)
+	}
+	if c := img.RGBAAt(400, 300); c.A != 0 {
+		t.Errorf("something was drawn: %v", c)
+	}
+}
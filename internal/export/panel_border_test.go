@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// twoPanelProject is sampleProject with a second panel, p2, below p1.
+func twoPanelProject() domain.Project {
+	proj := sampleProject()
+	pg := &proj.Issues[0].Pages[0]
+	pg.Panels[0].Geometry.Height = 200
+	pg.Panels = append(pg.Panels, domain.Panel{ID: "p2", Geometry: domain.Rect{X: 18, Y: 240, Width: 324, Height: 200}, ZOrder: 1})
+	return proj
+}
+
+// panelRects returns the panel rectangles of an exported SVG page (the lines with fill="none" that
+// are not guides).
+func panelRects(t *testing.T, dir string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "issue-1-page-1.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for _, ln := range strings.Split(string(data), "\n") {
+		if strings.Contains(ln, "<rect") && strings.Contains(ln, `fill="none"`) {
+			out = append(out, strings.TrimSpace(ln))
+		}
+	}
+	return out
+}
+
+func TestExportSVG_PanelBorders(t *testing.T) {
+	root := t.TempDir()
+	export := func(name string, proj domain.Project) []string {
+		ph := &storage.ProjectHandle{Root: root, Project: proj}
+		dir := filepath.Join(root, name)
+		if err := ExportIssueSVGPages(ph, 0, dir, SVGOptions{}); err != nil {
+			t.Fatalf("export svg: %v", err)
+		}
+		return panelRects(t, dir)
+	}
+
+	def := export("default", twoPanelProject())
+	if len(def) != 2 {
+		t.Fatalf("default panels: %q", def)
+	}
+	for _, r := range def {
+		if !strings.Contains(r, `stroke="#000000" stroke-width="1"`) {
+			t.Fatalf("default border changed: %s", r)
+		}
+	}
+
+	red := domain.Color{R: 255, A: 255}
+	styled := twoPanelProject()
+	styled.PanelBorder = &domain.PanelBorder{Color: &red, Width: 2}
+	styled.Issues[0].Pages[0].Panels[1].Border = &domain.PanelBorder{Width: 4.5}
+	got := export("styled", styled)
+	if len(got) != 2 {
+		t.Fatalf("styled panels: %q", got)
+	}
+	if !strings.Contains(got[0], `stroke="#ff0000" stroke-width="2"`) {
+		t.Fatalf("project default not applied: %s", got[0])
+	}
+	if !strings.Contains(got[1], `stroke="#ff0000" stroke-width="4.5"`) {
+		t.Fatalf("panel override not applied: %s", got[1])
+	}
+	// Geometry is unaffected by styling
+	for i := range got {
+		if strings.SplitN(got[i], " fill=", 2)[0] != strings.SplitN(def[i], " fill=", 2)[0] {
+			t.Fatalf("geometry changed: %s vs %s", got[i], def[i])
+		}
+	}
+
+	borderless := twoPanelProject()
+	borderless.Issues[0].Pages[0].Panels[0].Border = &domain.PanelBorder{Style: domain.PanelBorderNone}
+	got = export("borderless", borderless)
+	if len(got) != 1 || got[0] != def[1] {
+		t.Fatalf("borderless panel still framed: %q", got)
+	}
+}
+
+func TestExportPNG_PanelBorderWidthAndNone(t *testing.T) {
+	root := t.TempDir()
+	proj := twoPanelProject()
+	blue := domain.Color{B: 255, A: 255}
+	proj.Issues[0].Pages[0].Panels[0].Border = &domain.PanelBorder{Color: &blue, Width: 4}
+	proj.Issues[0].Pages[0].Panels[1].Border = &domain.PanelBorder{Style: domain.PanelBorderNone}
+	ph := &storage.ProjectHandle{Root: root, Project: proj}
+	dir := filepath.Join(root, "png")
+	if err := ExportIssuePNGPages(ph, 0, dir, PNGOptions{DPI: 72}); err != nil {
+		t.Fatalf("export png: %v", err)
+	}
+	f, err := os.Open(filepath.Join(dir, "issue-1-page-1.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(x, y int) color.RGBA { return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA) }
+	// p1's left edge is at x=36 (bleed 18 + 18); a 4pt border at 72 DPI spans x=34..37
+	for _, x := range []int{34, 37} {
+		if c := at(x, 150); c != (color.RGBA{0, 0, 255, 255}) {
+			t.Fatalf("x=%d: got %v, want blue", x, c)
+		}
+	}
+	if c := at(39, 150); c != (color.RGBA{255, 255, 255, 255}) {
+		t.Fatalf("border too thick: %v at x=39", c)
+	}
+	// p2 is borderless
+	if c := at(36, 350); c != (color.RGBA{255, 255, 255, 255}) {
+		t.Fatalf("borderless panel drawn: %v", c)
+	}
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/render"
+	"gocomicwriter/internal/storage"
+)
+
+// PanelPNGOptions controls the export of single panels as image crops.
+// - DPI: when > 0 overrides issue DPI for output pixel size
+// - Margin: white border in points added around the panel geometry
+// - NoReport: skip the export report and the exports log entry
+type PanelPNGOptions struct {
+	DPI      int
+	Margin   float64
+	NoReport bool
+}
+
+// ExportPanelPNG renders one panel of a page, with the page art under it and its frame, balloons,
+// captions and SFX, into a PNG covering the panel geometry plus opt.Margin on every side. Content is
+// clipped to the panel rect, so neither neighbouring panels nor balloons reaching past the frame leak
+// into the crop; the trim is ignored, so a panel bleeding off the page is exported whole. outPath is resolved like other exports.
+func ExportPanelPNG(ph *storage.ProjectHandle, issueIndex, pageNumber int, panelID, outPath string, opt PanelPNGOptions) (err error) {
+	if ph == nil {
+		return fmt.Errorf("project handle is nil")
+	}
+	outPath = exportOutPath(ph, outPath, ".png")
+	var written []string
+	run := beginReport(ph, "panel-png", issueIndex, panelPageIndexes(ph, issueIndex, pageNumber), opt, outPath+ReportSuffix, opt.NoReport)
+	defer func() { err = run.finish(written, err) }()
+	iss, pageIdx, err := panelExportPage(ph, issueIndex, pageNumber)
+	if err != nil {
+		return err
+	}
+	pg := iss.Pages[pageIdx]
+	var pnl *domain.Panel
+	for i := range pg.Panels {
+		if pg.Panels[i].ID == panelID {
+			pnl = &pg.Panels[i]
+			break
+		}
+	}
+	if pnl == nil {
+		return fmt.Errorf("panel %q not found on page %d", panelID, pageNumber)
+	}
+	dpi := panelExportDPI(iss, opt)
+	run.setDPI(dpi)
+	if err := checkPanelPixels(*pnl, pageNumber, opt.Margin, dpi); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+		return fmt.Errorf("ensure out dir: %w", err)
+	}
+	scale := pixelsPerPoint(dpi)
+	img := renderPanelCrop(*pnl, scale, opt.Margin, PreviewStyle(ph.Project), newPageArt(ph, run).panelArt(iss, pageIdx, scale))
+	if err := writePNG(outPath, img); err != nil {
+		return err
+	}
+	written = append(written, outPath)
+	return nil
+}
+
+// ExportPagePanelsPNG exports every panel of a page into outDir as page-03-panel-p2.png and returns
+// the files written. Each crop is rendered as by ExportPanelPNG.
+func ExportPagePanelsPNG(ph *storage.ProjectHandle, issueIndex, pageNumber int, outDir string, opt PanelPNGOptions) (written []string, err error) {
+	if ph == nil {
+		return nil, fmt.Errorf("project handle is nil")
+	}
+	outDir = exportOutPath(ph, outDir, "")
+	run := beginReport(ph, "panel-png", issueIndex, panelPageIndexes(ph, issueIndex, pageNumber), opt,
+		filepath.Join(outDir, fmt.Sprintf("page-%02d-panels%s", pageNumber, ReportSuffix)), opt.NoReport)
+	defer func() { err = run.finish(written, err) }()
+	iss, pageIdx, err := panelExportPage(ph, issueIndex, pageNumber)
+	if err != nil {
+		return nil, err
+	}
+	pg := iss.Pages[pageIdx]
+	if len(pg.Panels) == 0 {
+		return nil, fmt.Errorf("page %d has no panels", pageNumber)
+	}
+	dpi := panelExportDPI(iss, opt)
+	run.setDPI(dpi)
+	for _, pnl := range pg.Panels {
+		if err := checkPanelPixels(pnl, pageNumber, opt.Margin, dpi); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return nil, fmt.Errorf("ensure out dir: %w", err)
+	}
+	st := PreviewStyle(ph.Project)
+	scale := pixelsPerPoint(dpi)
+	drawArt := newPageArt(ph, run).panelArt(iss, pageIdx, scale)
+	for _, pnl := range pg.Panels {
+		name := filepath.Join(outDir, PanelCropName(pageNumber, pnl.ID))
+		if err := writePNG(name, renderPanelCrop(pnl, scale, opt.Margin, st, drawArt)); err != nil {
+			return written, err
+		}
+		written = append(written, name)
+	}
+	return written, nil
+}
+
+// PanelCropName is the file name of a panel crop in a batch export, e.g. page-03-panel-p2.png.
+// Characters of the panel ID that are unsafe in file names are replaced by '_'.
+func PanelCropName(pageNumber int, panelID string) string {
+	id := strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return '_'
+	}, panelID)
+	return fmt.Sprintf("page-%02d-panel-%s.png", pageNumber, id)
+}
+
+// panelExportPage validates the issue and finds the index of the page numbered pageNumber in it.
+func panelExportPage(ph *storage.ProjectHandle, issueIndex, pageNumber int) (domain.Issue, int, error) {
+	if issueIndex < 0 || issueIndex >= len(ph.Project.Issues) {
+		return domain.Issue{}, -1, fmt.Errorf("issue index out of range")
+	}
+	if err := validateIssue(ph, issueIndex); err != nil {
+		return domain.Issue{}, -1, err
+	}
+	iss := ph.Project.Issues[issueIndex]
+	for i, pg := range iss.Pages {
+		if pg.Number == pageNumber {
+			return iss, i, nil
+		}
+	}
+	return domain.Issue{}, -1, fmt.Errorf("page %d not found", pageNumber)
+}
+
+// panelPageIndexes is the page selection recorded in the report of a panel export.
+func panelPageIndexes(ph *storage.ProjectHandle, issueIndex, pageNumber int) []int {
+	if issueIndex < 0 || issueIndex >= len(ph.Project.Issues) {
+		return nil
+	}
+	for i, pg := range ph.Project.Issues[issueIndex].Pages {
+		if pg.Number == pageNumber {
+			return []int{i}
+		}
+	}
+	return nil
+}
+
+func panelExportDPI(iss domain.Issue, opt PanelPNGOptions) int {
+	return exportDPI(iss, opt.DPI)
+}
+
+// checkPanelPixels checks the size of the crop renderPanelCrop makes of pnl at dpi.
+func checkPanelPixels(pnl domain.Panel, pageNumber int, margin float64, dpi int) error {
+	m := 2 * max(0, margin)
+	w := domain.PointsToPixels(pnl.Geometry.Width+m, dpi)
+	h := domain.PointsToPixels(pnl.Geometry.Height+m, dpi)
+	return CheckPixels(fmt.Sprintf("panel %s on page %d", pnl.ID, pageNumber), w, h, dpi)
+}
+
+// PanelPreview renders single panels for on-screen review like ExportPanelPNG crops them, with the
+// page art under the panel. Art files are decoded once per PanelPreview.
+type PanelPreview struct {
+	art *pageArt
+	st  render.Style
+}
+
+// NewPanelPreview prepares panel rendering for the project of ph as it is now.
+func NewPanelPreview(ph *storage.ProjectHandle) *PanelPreview {
+	return &PanelPreview{art: newPageArt(ph, nil), st: PreviewStyle(ph.Project)}
+}
+
+// Render draws the panel panelID of the page at index pageIdx of iss at scale pixels per point, or
+// returns nil when there is no such panel.
+func (p *PanelPreview) Render(iss domain.Issue, pageIdx int, panelID string, scale float64) *image.RGBA {
+	if pageIdx < 0 || pageIdx >= len(iss.Pages) {
+		return nil
+	}
+	for _, pnl := range iss.Pages[pageIdx].Panels {
+		if pnl.ID == panelID {
+			return renderPanelCrop(pnl, scale, 0, p.st, p.art.panelArt(iss, pageIdx, scale))
+		}
+	}
+	return nil
+}
+
+// panelArt returns the drawArt of renderPanelCrop for panels of the page at index pageIdx of iss: it
+// draws the page's art, if any, at scale pixels per point.
+func (a *pageArt) panelArt(iss domain.Issue, pageIdx int, scale float64) func(img *image.RGBA, ox, oy float64) {
+	return func(img *image.RGBA, ox, oy float64) {
+		a.drawSheet(img, iss, sheet{left: pageIdx, right: -1}, ox, oy, scale)
+	}
+}
+
+// renderPanelCrop draws pnl alone into an image of its geometry and places that on a white canvas
+// grown by margin points on every side. Drawing into the panel-sized image is what clips the content.
+// drawArt, when set, draws the page art first, with the trim box at (ox, oy) points.
+func renderPanelCrop(pnl domain.Panel, scale, margin float64, st render.Style, drawArt func(img *image.RGBA, ox, oy float64)) *image.RGBA {
+	white := &image.Uniform{C: color.RGBA{255, 255, 255, 255}}
+	g := pnl.Geometry
+	inner := image.NewRGBA(image.Rect(0, 0, max(1, int(math.Round(g.Width*scale))), max(1, int(math.Round(g.Height*scale)))))
+	draw.Draw(inner, inner.Bounds(), white, image.Point{}, draw.Src)
+	if drawArt != nil {
+		drawArt(inner, -g.X, -g.Y)
+	}
+	render.DrawPage(inner, domain.Page{Panels: []domain.Panel{pnl}}, -g.X, -g.Y, scale, st)
+
+	m := int(math.Round(max(0, margin) * scale))
+	b := inner.Bounds()
+	img := image.NewRGBA(image.Rect(0, 0, b.Dx()+2*m, b.Dy()+2*m))
+	draw.Draw(img, img.Bounds(), white, image.Point{}, draw.Src)
+	draw.Draw(img, b.Add(image.Pt(m, m)), inner, image.Point{}, draw.Src)
+	return img
+}
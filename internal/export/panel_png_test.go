@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func panelCropProject() domain.Project {
+	balloon := func(id string, r domain.Rect) domain.Balloon {
+		return domain.Balloon{ID: id, Type: "speech", Shape: domain.Shape{Kind: "rect", Rect: r}}
+	}
+	return domain.Project{
+		Name: "Crops",
+		Issues: []domain.Issue{{
+			TrimWidth: 300, TrimHeight: 300, Bleed: 18, DPI: 150,
+			Pages: []domain.Page{{
+				Number: 3,
+				Panels: []domain.Panel{
+					// Bleeds 10pt off the left trim edge; its balloon reaches into the neighbour.
+					{ID: "p1", Geometry: domain.Rect{X: -10, Y: 0, Width: 110, Height: 100},
+						Balloons: []domain.Balloon{balloon("b1", domain.Rect{X: 10, Y: 70, Width: 130, Height: 20})}},
+					// Its balloon reaches back into p1.
+					{ID: "p/2", Geometry: domain.Rect{X: 100, Y: 0, Width: 100, Height: 100},
+						Balloons: []domain.Balloon{balloon("b2", domain.Rect{X: 60, Y: 40, Width: 90, Height: 20})}},
+				},
+			}},
+		}},
+	}
+}
+
+func isWhite(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r == 0xffff && g == 0xffff && b == 0xffff
+}
+
+func TestExportPanelPNG(t *testing.T) {
+	root := t.TempDir()
+	ph, err := storage.InitProject(root, panelCropProject())
+	if err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	out := filepath.Join(root, "exports", "p1.png")
+	// 72 DPI makes one pixel one point; the margin adds 10px on every side.
+	if err := ExportPanelPNG(ph, 0, 3, "p1", out, PanelPNGOptions{DPI: 72, Margin: 10, NoReport: true}); err != nil {
+		t.Fatalf("export panel: %v", err)
+	}
+	img := decodePNG(t, out)
+	if got := img.Bounds().Size(); got != image.Pt(130, 120) {
+		t.Fatalf("size = %v, want the full 110×100 geometry plus margins", got)
+	}
+	// Panel point (x, y) is at pixel (x+10+10, y+10): the crop starts 10pt left of the trim.
+	at := func(x, y int) color.Color { return img.At(x+20, y+10) }
+	if !isWhite(img.At(5, 5)) || !isWhite(img.At(125, 60)) {
+		t.Fatal("margin is not white")
+	}
+	if isWhite(at(-10, 50)) {
+		t.Fatal("frame edge outside the trim is missing")
+	}
+	if isWhite(at(30, 70)) {
+		t.Fatal("own balloon is missing")
+	}
+	if !isWhite(at(70, 40)) {
+		t.Fatal("neighbour's balloon leaked into the crop")
+	}
+	if !isWhite(at(105, 70)) {
+		t.Fatal("own balloon is not clipped to the panel")
+	}
+
+	if err := ExportPanelPNG(ph, 0, 3, "p9", out, PanelPNGOptions{NoReport: true}); err == nil {
+		t.Fatal("expected error for unknown panel")
+	}
+	if err := ExportPanelPNG(ph, 0, 4, "p1", out, PanelPNGOptions{NoReport: true}); err == nil {
+		t.Fatal("expected error for unknown page")
+	}
+}
+
+func TestExportPagePanelsPNG(t *testing.T) {
+	root := t.TempDir()
+	ph, err := storage.InitProject(root, panelCropProject())
+	if err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	outDir := filepath.Join(root, "exports", "panels")
+	written, err := ExportPagePanelsPNG(ph, 0, 3, outDir, PanelPNGOptions{DPI: 144})
+	if err != nil {
+		t.Fatalf("export panels: %v", err)
+	}
+	want := []string{filepath.Join(outDir, "page-03-panel-p1.png"), filepath.Join(outDir, "page-03-panel-p_2.png")}
+	if !reflect.DeepEqual(written, want) {
+		t.Fatalf("written = %v, want %v", written, want)
+	}
+	if got := decodePNG(t, want[1]).Bounds().Size(); got != image.Pt(200, 200) {
+		t.Fatalf("p/2 size = %v, want 200×200 at 144 DPI", got)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "page-03-panels"+ReportSuffix)); err != nil {
+		t.Fatalf("report: %v", err)
+	}
+}
+
+func TestPanelPreview(t *testing.T) {
+	ph := artProject(t)
+	iss := ph.Project.Issues[0]
+	pv := NewPanelPreview(ph)
+	img := pv.Render(iss, 0, "p1", 1)
+	if img == nil {
+		t.Fatal("no image for p1")
+	}
+	if got := img.Bounds().Size(); got != image.Pt(324, 504) {
+		t.Fatalf("size = %v, want the panel geometry", got)
+	}
+	// Page 1 has red bleed art under the panel; the balloon is drawn over it.
+	if r, g, b, _ := img.At(160, 300).RGBA(); r != 0xffff || g != 0 || b != 0 {
+		t.Fatalf("art under the panel = %v, want red", img.At(160, 300))
+	}
+	if r, g, _, _ := img.At(60, 50).RGBA(); r == 0xffff && g == 0 {
+		t.Fatal("balloon is not drawn over the art")
+	}
+	// The splash of page 2 has no art.
+	if img := pv.Render(iss, 1, "splash", 0.5); img == nil || !isWhite(img.At(100, 100)) {
+		t.Fatal("page without art is not white")
+	}
+	if pv.Render(iss, 0, "nope", 1) != nil || pv.Render(iss, 7, "p1", 1) != nil {
+		t.Fatal("unknown panel or page rendered")
+	}
+}
+
+func TestExportPanelPNGDrawsPageArt(t *testing.T) {
+	ph := artProject(t)
+	out := filepath.Join(ph.Root, "exports", "p1.png")
+	if err := ExportPanelPNG(ph, 0, 1, "p1", out, PanelPNGOptions{DPI: 72, NoReport: true}); err != nil {
+		t.Fatalf("export panel: %v", err)
+	}
+	// Page 1 has red bleed art under the panel, as in the page exports.
+	if r, g, b, _ := decodePNG(t, out).At(160, 300).RGBA(); r != 0xffff || g != 0 || b != 0 {
+		t.Fatalf("art under the panel = %v, want red", decodePNG(t, out).At(160, 300))
+	}
+	written, err := ExportPagePanelsPNG(ph, 0, 1, filepath.Join(ph.Root, "exports", "panels"), PanelPNGOptions{DPI: 72, NoReport: true})
+	if err != nil || len(written) == 0 {
+		t.Fatalf("export panels: %v, %v", written, err)
+	}
+	if r, g, b, _ := decodePNG(t, written[0]).At(160, 300).RGBA(); r != 0xffff || g != 0 || b != 0 {
+		t.Fatal("batch crop has no art under the panel")
+	}
+}
@@ -9,20 +9,29 @@
 package export
 
 import (
+	"bytes"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/render"
+	"gocomicwriter/internal/script"
 	"gocomicwriter/internal/storage"
 
 	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
 )
 
 // PDFOptions controls PDF export behavior.
 // Units are points (pt) unless otherwise noted.
-// Vector text is used whenever possible; we rely on built-in Helvetica for portability.
-// In later phases, font embedding can be added using TTFs.
+// Vector text is used whenever possible. Lettering whose font family has a TrueType file in the
+// project's styles/fonts folder is set in that font, embedded as a subset; other text uses built-in
+// Helvetica (the bundled Go fonts for PDF/X), and missing families are warned about in the report.
 //
 // Coordinates:
 // - Page origin is top-left.
@@ -39,42 +48,60 @@ import (
 //nolint:revive // keep options grouped and explicit for clarity
 type PDFOptions struct {
 	IncludeGuides bool
-	EmbedFonts    bool // reserved; not used yet
+	EmbedFonts    bool // reserved; project fonts are always embedded
 	GuideColor    domain.Color
 	PanelStroke   domain.Stroke
 	BalloonStroke domain.Stroke
 	BalloonFill   domain.Color
 	Pages         []int // if empty, export all pages
+	// OutputProfile selects plain RGB output or print-ready PDF/X-1a.
+	OutputProfile OutputProfile
+	// ICCProfilePath is the CMYK output profile embedded for PDF/X-1a; empty uses the bundled profile.
+	ICCProfilePath string
+	// OutputCondition is the registered printing condition (e.g. "FOGRA39") named in the OutputIntent.
+	OutputCondition string
+	// NoReport skips the <output>.export.json report and the exports log entry.
+	NoReport bool
+	// IncludeOutline adds bookmarks for the pages and for the script's scenes whose beats are mapped
+	// to panels. DefaultPDFOptions turns it on.
+	IncludeOutline bool
+}
+
+// DefaultPDFOptions returns the options of a plain PDF export: RGB output with the outline.
+func DefaultPDFOptions() PDFOptions {
+	return PDFOptions{IncludeOutline: true}
 }
 
 // ExportIssuePDF exports the specified issue to a single multi-page PDF placed at outPath.
-func ExportIssuePDF(ph *storage.ProjectHandle, issueIndex int, outPath string, opt PDFOptions) error {
+func ExportIssuePDF(ph *storage.ProjectHandle, issueIndex int, outPath string, opt PDFOptions) (err error) {
 	if ph == nil {
 		return fmt.Errorf("project handle is nil")
 	}
+	// Ensure output path is under project exports folder if relative
+	outPath = exportOutPath(ph, outPath, "")
+	run := beginReport(ph, "pdf", issueIndex, opt.Pages, opt, outPath+ReportSuffix, opt.NoReport)
+	defer func() { err = run.finish([]string{outPath}, err) }()
 	if issueIndex < 0 || issueIndex >= len(ph.Project.Issues) {
 		return fmt.Errorf("issue index out of range")
 	}
+	if err := validateIssue(ph, issueIndex); err != nil {
+		return err
+	}
 	iss := ph.Project.Issues[issueIndex]
 
-	// Default styles
-	guideCol := opt.GuideColor
-	if guideCol.A == 0 && guideCol.R == 0 && guideCol.G == 0 && guideCol.B == 0 {
-		guideCol = domain.Color{R: 255, G: 0, B: 0, A: 255}
-	}
-	panelStroke := opt.PanelStroke
-	if panelStroke.Width == 0 {
-		panelStroke = domain.Stroke{Color: domain.Color{R: 0, G: 0, B: 0, A: 255}, Width: 1}
-	}
-	balloonStroke := opt.BalloonStroke
-	if balloonStroke.Width == 0 {
-		balloonStroke = domain.Stroke{Color: domain.Color{R: 0, G: 0, B: 0, A: 255}, Width: 1}
-	}
-	balloonFill := opt.BalloonFill
-	if balloonFill.A == 0 && balloonFill.R == 0 && balloonFill.G == 0 && balloonFill.B == 0 {
-		balloonFill = domain.Color{R: 255, G: 255, B: 255, A: 255}
+	// PDF/X-1a: check the profile before doing any work so misconfiguration fails loudly
+	pdfx := opt.OutputProfile == OutputProfilePDFX1a
+	var icc outputICC
+	if pdfx {
+		if icc, err = loadOutputICC(opt.ICCProfilePath); err != nil {
+			return err
+		}
 	}
 
+	// Default styles
+	sty := resolveExportStyle(ph.Project, opt.GuideColor, opt.PanelStroke, opt.BalloonStroke, opt.BalloonFill)
+	guideCol, panelStroke, balloonStroke, balloonFill := sty.guide, sty.panel, sty.balloonStroke, sty.balloonFill
+
 	trimW := iss.TrimWidth
 	trimH := iss.TrimHeight
 	bleed := iss.Bleed
@@ -88,90 +115,236 @@ func ExportIssuePDF(ph *storage.ProjectHandle, issueIndex int, outPath string, o
 		// We'll set orientation automatically by size
 		OrientationStr: "",
 	})
-	pdf.SetTitle(fmt.Sprintf("%s — Issue PDF", ph.Project.Name), false)
+	title := fmt.Sprintf("%s — Issue PDF", ph.Project.Name)
+	pdf.SetTitle(title, false)
 	pdf.SetAuthor("Go Comic Writer", false)
+	created := time.Now()
+	pdf.SetCreationDate(created)
+	pdf.SetModificationDate(created)
+	ink := pdfInk{pdf: pdf, cmyk: pdfx}
+
+	// Built-in Helvetica keeps text vector without embedding; PDF/X requires embedded fonts
+	family := "Helvetica"
+	if pdfx {
+		family = pdfxFontFamily
+		pdf.AddUTF8FontFromBytes(family, "", goregular.TTF)
+		pdf.AddUTF8FontFromBytes(family, "B", gobold.TTF)
+		pdf.SetPageBox("TrimBox", bleed, bleed, trimW, trimH)
+		pdf.SetPageBox("BleedBox", 0, 0, mediaW, mediaH)
+	}
+	pdf.SetFont(family, "", 12)
+	fonts := newPDFFonts(pdf, newLetteringFonts(ph, run, family), family)
 
-	// Built-in Helvetica keeps text vector without embedding
-	pdf.SetFont("Helvetica", "", 12)
+	titlePage := hasTitlePage(iss)
+	if titlePage {
+		addPDFTitlePage(pdf, ink, family, titlePageLines(ph.Project, issueIndex), trimW, trimH, bleed)
+	}
 
-	pages := pageIndexes(len(iss.Pages), opt.Pages)
-	for _, pidx := range pages {
-		if pidx < 0 || pidx >= len(iss.Pages) {
-			continue
+	// Spreads become one double-width sheet; the right page is shifted by one trim width
+	sheets := issueSheets(iss, pageIndexes(len(iss.Pages), opt.Pages))
+	art := &pdfArt{pageArt: newPageArt(ph, run), names: map[*artImage]int{}}
+	for _, sh := range sheets {
+		sheetTrim, sheetMedia := sheetBoxes(iss, sh)
+		sheetTrimW, sheetW := sheetTrim.Width, sheetMedia.Width
+		pdf.AddPageFormat("", gofpdf.SizeType{Wd: sheetW, Ht: mediaH})
+		if pdfx {
+			pdf.SetPageBox("TrimBox", bleed, bleed, sheetTrimW, trimH)
+			pdf.SetPageBox("BleedBox", 0, 0, sheetW, mediaH)
 		}
-		pg := iss.Pages[pidx]
-		pdf.AddPageFormat("", gofpdf.SizeType{Wd: mediaW, Ht: mediaH})
+		art.drawSheet(pdf.RawWriteStr, iss, sh, mediaH)
 
 		// Draw bleed and trim guides if requested
 		if opt.IncludeGuides {
-			setDrawColor(pdf, guideCol)
+			ink.draw(guideCol)
 			pdf.SetLineWidth(0.2)
 			// Bleed (outer border = media box)
-			pdf.Rect(0, 0, mediaW, mediaH, "D")
+			pdf.Rect(0, 0, sheetW, mediaH, "D")
 			// Trim box
-			pdf.Rect(bleed, bleed, trimW, trimH, "D")
+			pdf.Rect(bleed, bleed, sheetTrimW, trimH, "D")
+			if sh.spread() {
+				// Spine
+				pdf.Line(bleed+trimW, bleed, bleed+trimW, bleed+trimH)
+			}
 		}
 
-		// Panels
-		setDrawColor(pdf, panelStroke.Color)
-		pdf.SetLineWidth(panelStroke.Width)
-		for _, pnl := range pg.Panels {
-			r := pnl.Geometry
-			// Shift by bleed to map to media coordinates
-			x := r.X + bleed
-			y := r.Y + bleed
-			pdf.Rect(x, y, r.Width, r.Height, "D")
-
-			// Balloons within panel (coordinates assumed absolute already)
-			for _, b := range pnl.Balloons {
-				br := b.Shape.Rect
-				bx := br.X + bleed
-				by := br.Y + bleed
-				// Shape
-				setFillColor(pdf, balloonFill)
-				setDrawColor(pdf, balloonStroke.Color)
-				pdf.SetLineWidth(balloonStroke.Width)
-				switch b.Shape.Kind {
-				case "ellipse":
-					pdf.Ellipse(bx+br.Width/2, by+br.Height/2, br.Width/2, br.Height/2, 0, "FD")
-				case "roundedBox":
-					r := b.Shape.Radius
-					roundedRect(pdf, bx, by, br.Width, br.Height, r, "FD")
-				default:
-					pdf.Rect(bx, by, br.Width, br.Height, "FD")
+		pidxs, offsets := sh.pages(trimW)
+		for k, pidx := range pidxs {
+			pg := iss.Pages[pidx]
+			if offsets[k] != 0 {
+				pdf.TransformBegin()
+				pdf.TransformTranslateX(offsets[k])
+			}
+			// Panels
+			for _, pnl := range pg.Panels {
+				r := pnl.Geometry
+				// Shift by bleed to map to media coordinates
+				x := r.X + bleed
+				y := r.Y + bleed
+				if f, ok := render.ResolvePanelFrame(ph.Project.PanelBorder, pnl, panelStroke); ok {
+					ink.draw(f.Color)
+					pdf.SetLineWidth(f.Width)
+					pdf.Rect(x, y, r.Width, r.Height, "D")
+				}
+
+				// Balloons within panel (coordinates assumed absolute already)
+				for _, b := range pnl.Balloons {
+					br := b.Shape.Rect
+					bx := br.X + bleed
+					by := br.Y + bleed
+					// Shape
+					lk := render.ResolveBalloonLook(ph.Project.BalloonStyles, b, balloonStroke, balloonFill)
+					ink.fill(lk.Fill)
+					ink.draw(lk.Color)
+					pdf.SetLineWidth(lk.Width)
+					paint := "FD"
+					if lk.Hidden {
+						paint = "F"
+					}
+					if lk.Dashed {
+						on, off := lk.Dash()
+						pdf.SetDashPattern([]float64{on, off}, 0)
+					}
+					switch b.Shape.Kind {
+					case "ellipse":
+						pdf.Ellipse(bx+br.Width/2, by+br.Height/2, br.Width/2, br.Height/2, 0, paint)
+					case "roundedBox":
+						r := b.Shape.Radius
+						roundedRect(pdf, bx, by, br.Width, br.Height, r, paint)
+					case "burst", "cloud":
+						pdf.Polygon(pdfPoints(render.BalloonPolygon(b.Shape.Kind, bx, by, br.Width, br.Height)), paint)
+					default:
+						pdf.Rect(bx, by, br.Width, br.Height, paint)
+					}
+					if lk.Dashed {
+						pdf.SetDashPattern(nil, 0)
+					}
+					// Text (simple top-left flow)
+					pad := lk.Padding
+					cx := bx + pad
+					cy := by + pad + 12 // approx baseline offset for 12pt
+					ink.text()
+					for _, run := range b.TextRuns {
+						fsz := run.Size
+						if fsz <= 0 {
+							fsz = 12
+						}
+						fonts.set(run.Font, false, fsz)
+						for _, line := range strings.Split(run.Content, "\n") {
+							pdf.Text(cx, cy, line)
+							cy += fsz * 1.2
+						}
+					}
+				}
+				// Captions: filled boxes with stacked text lines
+				for _, c := range pnl.Captions {
+					cr := c.Rect
+					cx := cr.X + bleed
+					cy := cr.Y + bleed
+					fsz := c.Size
+					if fsz <= 0 {
+						fsz = 11
+					}
+					pdfRotateBegin(pdf, c.Rotation, cx+cr.Width/2, cy+cr.Height/2)
+					ink.fill(sty.captionFill)
+					ink.draw(balloonStroke.Color)
+					pdf.SetLineWidth(balloonStroke.Width)
+					pdf.Rect(cx, cy, cr.Width, cr.Height, "FD")
+					fonts.set(c.Font, false, fsz)
+					ink.text()
+					ty := cy + 4 + fsz
+					for _, line := range strings.Split(c.Text, "\n") {
+						pdf.Text(cx+4, ty, line)
+						ty += fsz * 1.2
+					}
+					pdfRotateEnd(pdf, c.Rotation)
 				}
-				// Text (simple top-left flow)
-				pad := 6.0
-				cx := bx + pad
-				cy := by + pad + 12 // approx baseline offset for 12pt
-				for _, run := range b.TextRuns {
-					fsz := run.Size
+				// SFX: plain bold text
+				for _, fx := range pnl.SFX {
+					fr := fx.Rect
+					fsz := fx.Size
 					if fsz <= 0 {
-						fsz = 12
+						fsz = 36
 					}
-					pdf.SetFont("Helvetica", "", fsz)
-					pdf.Text(cx, cy, run.Content)
-					cy += fsz * 1.2
+					x := fr.X + bleed
+					y := fr.Y + bleed
+					pdfRotateBegin(pdf, fx.Rotation, x+fr.Width/2, y+fr.Height/2)
+					fonts.set(fx.Font, true, fsz)
+					ink.text()
+					pdf.Text(x, y+fsz, fx.Text)
+					pdfRotateEnd(pdf, fx.Rotation)
 				}
 			}
+			if offsets[k] != 0 {
+				pdf.TransformEnd()
+			}
 		}
 	}
 
-	// Ensure output path is under project exports folder if relative
-	if !filepath.IsAbs(outPath) {
-		outPath = filepath.Join(ph.Root, "exports", outPath)
+	// Page labels make viewers number the sheets like the comic, behind the title page and across spreads
+	labels := pdfPageLabels(iss, sheets, titlePage)
+	var outline []pdfOutlineItem
+	if opt.IncludeOutline {
+		outline = pdfOutline(iss, sheets, titlePage, issueSceneStarts(ph, iss, run), trimW, bleed)
 	}
+
 	// Ensure directory exists
 	dir := filepath.Dir(outPath)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return fmt.Errorf("ensure out dir: %w", err)
 	}
-	if err := pdf.OutputFileAndClose(outPath); err != nil {
+	if !pdfx && labels == "" && len(outline) == 0 && len(art.order) == 0 {
+		if err := pdf.OutputFileAndClose(outPath); err != nil {
+			return fmt.Errorf("write pdf: %w", err)
+		}
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return fmt.Errorf("write pdf: %w", err)
+	}
+	doc, err := appendPDFArt(buf.Bytes(), art.order, pdfx)
+	if err != nil {
+		return err
+	}
+	if doc, err = appendPDFOutline(doc, outline, mediaH); err != nil {
+		return err
+	}
+	if pdfx {
+		doc, err = appendPDFXUpdate(doc, pdfxMeta{
+			Title:           title,
+			Author:          "Go Comic Writer",
+			OutputCondition: opt.OutputCondition,
+			ICC:             icc,
+			Created:         created,
+			Catalog:         labels,
+		})
+	} else if labels != "" {
+		doc, err = appendCatalogUpdate(doc, labels)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, doc, 0o644); err != nil {
 		return fmt.Errorf("write pdf: %w", err)
 	}
 	return nil
 }
 
+// issueSceneStarts reads the project script and returns where its scenes start in iss. A script that
+// cannot be read is reported as a warning; the outline then has no scene entries.
+func issueSceneStarts(ph *storage.ProjectHandle, iss domain.Issue, run *exportRun) []storage.SceneStart {
+	text, err := storage.ReadScript(ph)
+	if err != nil {
+		run.warn(fmt.Sprintf("script not read, PDF outline has no scenes: %v", err))
+		return nil
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	sc, _ := script.Parse(text)
+	return storage.ComputeSceneStarts(sc, iss)
+}
+
 func pageIndexes(total int, specific []int) []int {
 	if len(specific) == 0 {
 		out := make([]int, total)
@@ -191,9 +364,51 @@ func setFillColor(pdf *gofpdf.Fpdf, c domain.Color) {
 	pdf.SetFillColor(int(c.R), int(c.G), int(c.B))
 }
 
+// pdfRotateBegin starts a rotation transform of deg degrees clockwise around (cx, cy); no-op when deg is 0.
+func pdfRotateBegin(pdf *gofpdf.Fpdf, deg, cx, cy float64) {
+	if deg == 0 {
+		return
+	}
+	pdf.TransformBegin()
+	// gofpdf rotates counter-clockwise
+	pdf.TransformRotate(-deg, cx, cy)
+}
+
+func pdfRotateEnd(pdf *gofpdf.Fpdf, deg float64) {
+	if deg != 0 {
+		pdf.TransformEnd()
+	}
+}
+
+// pdfPoints converts polygon corners for gofpdf.
+func pdfPoints(pts [][2]float64) []gofpdf.PointType {
+	out := make([]gofpdf.PointType, len(pts))
+	for i, p := range pts {
+		out[i] = gofpdf.PointType{X: p[0], Y: p[1]}
+	}
+	return out
+}
+
+// roundedRect draws the box x, y, w, h with all corners rounded to r, capped at half the shorter side
+// as in raster output. Without a radius it is a plain rectangle.
 func roundedRect(pdf *gofpdf.Fpdf, x, y, w, h, r float64, style string) {
-	// parameter r reserved for future rounded corners; mark as used to avoid warnings
-	_ = r
-	// Fallback for older gofpdf versions: draw as a normal rectangle
-	pdf.Rect(x, y, w, h, style)
+	r = min(r, w/2, h/2)
+	if r <= 0 {
+		pdf.Rect(x, y, w, h, style)
+		return
+	}
+	// gofpdf's RoundedRect leaves a graphics state pushed per call, so the path is drawn here. Each
+	// corner is a quarter circle approximated by one cubic Bézier curve.
+	k := 4.0 / 3 * (math.Sqrt2 - 1) * r
+	pdf.MoveTo(x+r, y)
+	pdf.LineTo(x+w-r, y)
+	pdf.CurveBezierCubicTo(x+w-r+k, y, x+w, y+r-k, x+w, y+r)
+	pdf.LineTo(x+w, y+h-r)
+	pdf.CurveBezierCubicTo(x+w, y+h-r+k, x+w-r+k, y+h, x+w-r, y+h)
+	pdf.LineTo(x+r, y+h)
+	pdf.CurveBezierCubicTo(x+r-k, y+h, x, y+h-r+k, x, y+h-r)
+	pdf.LineTo(x, y+r)
+	pdf.CurveBezierCubicTo(x, y+r-k, x+r-k, y, x+r, y)
+	pdf.ClosePath()
+	pdf.DrawPath(style)
 }
@@ -9,12 +9,15 @@
 package export
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"gocomicwriter/internal/domain"
 	"gocomicwriter/internal/storage"
+
+	"github.com/jung-kurt/gofpdf"
 )
 
 func TestExportIssuePDF_CreatesFile(t *testing.T) {
@@ -69,3 +72,27 @@ func TestExportIssuePDF_CreatesFile(t *testing.T) {
 		t.Fatalf("pdf file empty")
 	}
 }
+
+func TestRoundedRect(t *testing.T) {
+	// curves counts the Bézier segments drawn on a page holding one rounded rectangle
+	curves := func(r float64) int {
+		pdf := gofpdf.New("P", "pt", "A4", "")
+		pdf.SetCompression(false)
+		pdf.AddPage()
+		roundedRect(pdf, 72, 72, 300, 100, r, "FD")
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			t.Fatalf("output: %v", err)
+		}
+		if bytes.Contains(buf.Bytes(), []byte("q ")) {
+			t.Errorf("r=%g: graphics state pushed", r)
+		}
+		return bytes.Count(buf.Bytes(), []byte(" c\n"))
+	}
+	if n := curves(12); n != 4 {
+		t.Errorf("rounded corners = %d curves, want 4", n)
+	}
+	if n := curves(0); n != 0 {
+		t.Errorf("no radius = %d curves, want a plain rectangle", n)
+	}
+}
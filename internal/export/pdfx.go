@@ -0,0 +1,351 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"gocomicwriter/internal/domain"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// OutputProfile selects the conformance target of a PDF export.
+type OutputProfile int
+
+const (
+	// OutputProfileDefault writes a plain RGB PDF for screen proofs.
+	OutputProfileDefault OutputProfile = iota
+	// OutputProfilePDFX1a writes PDF/X-1a:2003 for print: CMYK/gray only, no transparency,
+	// embedded fonts, a CMYK OutputIntent and XMP identification metadata.
+	OutputProfilePDFX1a
+)
+
+func (p OutputProfile) String() string {
+	switch p {
+	case OutputProfilePDFX1a:
+		return "PDF/X-1a"
+	default:
+		return "default"
+	}
+}
+
+// pdfxVersion is written to the Info dictionary and XMP metadata.
+const pdfxVersion = "PDF/X-1a:2003"
+
+// pdfxFontFamily is the embedded font family used instead of the non-embedded Helvetica.
+const pdfxFontFamily = "GoRegular"
+
+// rgbToCMYK converts normalized RGB with the naive full-GCR model also described by the bundled profile.
+func rgbToCMYK(r, g, b float64) (c, m, y, k float64) {
+	k = 1 - max(r, g, b)
+	if k >= 1 {
+		return 0, 0, 0, 1
+	}
+	return (1 - r - k) / (1 - k), (1 - g - k) / (1 - k), (1 - b - k) / (1 - k), k
+}
+
+// flattenOnPaper composites a possibly translucent color onto white paper, since PDF/X-1a
+// forbids transparency.
+func flattenOnPaper(c domain.Color) (r, g, b float64) {
+	a := float64(c.A) / 255
+	if c.A == 0 {
+		a = 1 // alpha left unset; the RGB exporter treats these colors as opaque too
+	}
+	mix := func(v uint8) float64 { return float64(v)/255*a + (1 - a) }
+	return mix(c.R), mix(c.G), mix(c.B)
+}
+
+// pdfInk sets stroke, fill and text colors. In CMYK mode colors are flattened and written as
+// DeviceCMYK operators, which gofpdf has no API for.
+type pdfInk struct {
+	pdf  *gofpdf.Fpdf
+	cmyk bool
+}
+
+func (k pdfInk) draw(c domain.Color) {
+	if !k.cmyk {
+		setDrawColor(k.pdf, c)
+		return
+	}
+	k.pdf.RawWriteStr(cmykOperator(c, "K"))
+}
+
+func (k pdfInk) fill(c domain.Color) {
+	if !k.cmyk {
+		setFillColor(k.pdf, c)
+		return
+	}
+	k.pdf.RawWriteStr(cmykOperator(c, "k"))
+}
+
+// text prepares black text. gofpdf only switches to its text color when it differs from its own
+// fill color, which raw CMYK fills bypass, so the fill is reset explicitly.
+func (k pdfInk) text() {
+	if k.cmyk {
+		k.pdf.RawWriteStr("0 0 0 1 k")
+	}
+}
+
+func cmykOperator(col domain.Color, op string) string {
+	c, m, y, k := rgbToCMYK(flattenOnPaper(col))
+	return fmt.Sprintf("%.3f %.3f %.3f %.3f %s", c, m, y, k, op)
+}
+
+// outputICC is the validated CMYK output profile for a PDF/X-1a export.
+type outputICC struct {
+	data        []byte
+	description string
+}
+
+// loadOutputICC reads and checks the ICC profile at path, or returns the bundled profile when
+// path is empty. The profile must be a CMYK output (printer) profile.
+func loadOutputICC(path string) (outputICC, error) {
+	if strings.TrimSpace(path) == "" {
+		return outputICC{data: defaultCMYKProfile(), description: defaultICCDescription}, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return outputICC{}, fmt.Errorf("PDF/X-1a: read ICC profile: %w", err)
+	}
+	if len(b) < 132 || string(b[36:40]) != "acsp" {
+		return outputICC{}, fmt.Errorf("PDF/X-1a: %s is not an ICC profile", path)
+	}
+	if cs := string(b[16:20]); cs != "CMYK" {
+		return outputICC{}, fmt.Errorf("PDF/X-1a: ICC profile %s describes %q, a CMYK profile is required", path, strings.TrimSpace(cs))
+	}
+	if class := string(b[12:16]); class != "prtr" {
+		return outputICC{}, fmt.Errorf("PDF/X-1a: ICC profile %s is a %q profile, an output (prtr) profile is required", path, class)
+	}
+	return outputICC{data: b, description: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))}, nil
+}
+
+// pdfxMeta is the identification written into the PDF/X update.
+type pdfxMeta struct {
+	Title, Author   string
+	OutputCondition string // registered identifier such as "FOGRA39"; empty for a custom condition
+	ICC             outputICC
+	Created         time.Time
+	// Catalog holds extra catalog entries written with the update, e.g. page labels.
+	Catalog string
+}
+
+var (
+	reTrailerRoot = regexp.MustCompile(`/Root (\d+) 0 R`)
+	reTrailerInfo = regexp.MustCompile(`/Info (\d+) 0 R`)
+	reTrailerSize = regexp.MustCompile(`/Size (\d+)`)
+)
+
+// pdfTrailer holds the trailer references of a finished gofpdf document that an incremental update builds on.
+type pdfTrailer struct {
+	root, info, size, prev int
+	catalog                string // inner text of the catalog dictionary
+}
+
+// readPDFTrailer parses the last trailer of doc and the catalog it points to.
+func readPDFTrailer(doc []byte) (pdfTrailer, error) {
+	ti := bytes.LastIndex(doc, []byte("trailer"))
+	sx := bytes.LastIndex(doc, []byte("startxref"))
+	if ti < 0 || sx < ti {
+		return pdfTrailer{}, fmt.Errorf("no trailer in generated PDF")
+	}
+	trailer := doc[ti:sx]
+	rootM, infoM, sizeM := reTrailerRoot.FindSubmatch(trailer), reTrailerInfo.FindSubmatch(trailer), reTrailerSize.FindSubmatch(trailer)
+	if rootM == nil || infoM == nil || sizeM == nil {
+		return pdfTrailer{}, fmt.Errorf("incomplete trailer in generated PDF")
+	}
+	var t pdfTrailer
+	t.root, _ = strconv.Atoi(string(rootM[1]))
+	t.info, _ = strconv.Atoi(string(infoM[1]))
+	t.size, _ = strconv.Atoi(string(sizeM[1]))
+	prev, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(string(doc[sx+len("startxref"):])), "%%EOF")))
+	if err != nil {
+		return pdfTrailer{}, fmt.Errorf("bad startxref: %w", err)
+	}
+	t.prev = prev
+	if t.catalog, err = objectDict(doc, t.root); err != nil {
+		return pdfTrailer{}, err
+	}
+	return t, nil
+}
+
+// pdfUpdate collects the objects of an incremental update appended to doc.
+type pdfUpdate struct {
+	out     bytes.Buffer
+	offsets map[int]int
+}
+
+func newPDFUpdate(doc []byte) *pdfUpdate {
+	u := &pdfUpdate{offsets: map[int]int{}}
+	u.out.Write(doc)
+	if !bytes.HasSuffix(doc, []byte("\n")) {
+		u.out.WriteByte('\n')
+	}
+	return u
+}
+
+// obj writes object n, replacing any earlier version; stream may be nil.
+func (u *pdfUpdate) obj(n int, body string, stream []byte) {
+	u.offsets[n] = u.out.Len()
+	fmt.Fprintf(&u.out, "%d 0 obj\n%s\n", n, body)
+	if stream != nil {
+		u.out.WriteString("stream\n")
+		u.out.Write(stream)
+		u.out.WriteString("\nendstream\n")
+	}
+	u.out.WriteString("endobj\n")
+}
+
+// finish writes the xref section and trailer; extra is added to the trailer dictionary.
+func (u *pdfUpdate) finish(t pdfTrailer, size int, extra string) []byte {
+	xref := u.out.Len()
+	nums := make([]int, 0, len(u.offsets))
+	for n := range u.offsets {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+	u.out.WriteString("xref\n")
+	for _, n := range nums {
+		fmt.Fprintf(&u.out, "%d 1\n%010d 00000 n \n", n, u.offsets[n])
+	}
+	fmt.Fprintf(&u.out, "trailer\n<<\n/Size %d\n/Root %d 0 R\n/Info %d 0 R\n/Prev %d\n%s>>\nstartxref\n%d\n%%%%EOF\n",
+		size, t.root, t.info, t.prev, extra, xref)
+	return u.out.Bytes()
+}
+
+// appendCatalogUpdate adds entries to the catalog of a finished gofpdf document with an incremental update.
+func appendCatalogUpdate(doc []byte, entries string) ([]byte, error) {
+	t, err := readPDFTrailer(doc)
+	if err != nil {
+		return nil, err
+	}
+	u := newPDFUpdate(doc)
+	u.obj(t.root, fmt.Sprintf("<<%s\n%s\n>>", t.catalog, entries), nil)
+	return u.finish(t, t.size, ""), nil
+}
+
+// appendPDFXUpdate adds an incremental update to a finished gofpdf document that embeds the
+// output profile, an OutputIntent, XMP metadata and PDF/X identification in Info and Catalog,
+// and a document ID.
+func appendPDFXUpdate(doc []byte, meta pdfxMeta) ([]byte, error) {
+	t, err := readPDFTrailer(doc)
+	if err != nil {
+		return nil, fmt.Errorf("PDF/X-1a: %w", err)
+	}
+	root, info, size := t.root, t.info, t.size
+	u := newPDFUpdate(doc)
+	obj := u.obj
+	iccN, intentN, metaN := size, size+1, size+2
+	created := meta.Created.UTC()
+	pdfDate := "D:" + created.Format("20060102150405") + "Z"
+	cond := meta.OutputCondition
+	if cond == "" {
+		cond = "Custom"
+	}
+	obj(iccN, fmt.Sprintf("<< /N 4 /Length %d >>", len(meta.ICC.data)), meta.ICC.data)
+	obj(intentN, fmt.Sprintf("<< /Type /OutputIntent /S /GTS_PDFX /OutputConditionIdentifier %s /OutputCondition %s /Info %s /RegistryName (http://www.color.org) /DestOutputProfile %d 0 R >>",
+		pdfString(cond), pdfString(meta.ICC.description), pdfString(meta.ICC.description), iccN), nil)
+	xmp := pdfxXMP(meta, created)
+	obj(metaN, fmt.Sprintf("<< /Type /Metadata /Subtype /XML /Length %d >>", len(xmp)), xmp)
+	obj(info, fmt.Sprintf("<< /Title %s /Author %s /Creator (Go Comic Writer) /Producer (Go Comic Writer) /CreationDate (%s) /ModDate (%s) /Trapped /False /GTS_PDFXVersion (%s) >>",
+		pdfString(meta.Title), pdfString(meta.Author), pdfDate, pdfDate, pdfxVersion), nil)
+	catalog := t.catalog
+	if meta.Catalog != "" {
+		catalog += "\n" + meta.Catalog
+	}
+	obj(root, fmt.Sprintf("<<%s\n/OutputIntents [%d 0 R]\n/Metadata %d 0 R\n>>", catalog, intentN, metaN), nil)
+
+	sum := md5.Sum(doc)
+	id := hex.EncodeToString(sum[:])
+	return u.finish(t, size+3, fmt.Sprintf("/ID [<%s><%s>]\n", id, id)), nil
+}
+
+// objectDict returns the inner text of the dictionary of object n (without the << >> delimiters).
+func objectDict(doc []byte, n int) (string, error) {
+	head := []byte(fmt.Sprintf("\n%d 0 obj\n", n))
+	i := bytes.LastIndex(doc, head)
+	if i < 0 {
+		return "", fmt.Errorf("object %d not found", n)
+	}
+	rest := doc[i+len(head):]
+	j := bytes.Index(rest, []byte("endobj"))
+	if j < 0 {
+		return "", fmt.Errorf("object %d not terminated", n)
+	}
+	body := strings.TrimSpace(string(rest[:j]))
+	if !strings.HasPrefix(body, "<<") || !strings.HasSuffix(body, ">>") {
+		return "", fmt.Errorf("object %d is not a dictionary", n)
+	}
+	return body[2 : len(body)-2], nil
+}
+
+// pdfString encodes s as a PDF text string: a literal for ASCII, UTF-16BE hex otherwise.
+func pdfString(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r > 0x7e || r < 0x20 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return "(" + strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(s) + ")"
+	}
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+func pdfxXMP(meta pdfxMeta, created time.Time) []byte {
+	esc := func(s string) string {
+		var b bytes.Buffer
+		_ = xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+	date := created.Format(time.RFC3339)
+	return []byte(`<?xpacket begin="` + "\ufeff" + `" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about=""
+    xmlns:dc="http://purl.org/dc/elements/1.1/"
+    xmlns:xmp="http://ns.adobe.com/xap/1.0/"
+    xmlns:pdf="http://ns.adobe.com/pdf/1.3/"
+    xmlns:pdfx="http://ns.adobe.com/pdfx/1.3/"
+    xmlns:pdfxid="http://www.npes.org/pdfx/ns/id/">
+   <dc:format>application/pdf</dc:format>
+   <dc:title><rdf:Alt><rdf:li xml:lang="x-default">` + esc(meta.Title) + `</rdf:li></rdf:Alt></dc:title>
+   <dc:creator><rdf:Seq><rdf:li>` + esc(meta.Author) + `</rdf:li></rdf:Seq></dc:creator>
+   <xmp:CreateDate>` + date + `</xmp:CreateDate>
+   <xmp:ModifyDate>` + date + `</xmp:ModifyDate>
+   <xmp:CreatorTool>Go Comic Writer</xmp:CreatorTool>
+   <pdf:Producer>Go Comic Writer</pdf:Producer>
+   <pdf:Trapped>False</pdf:Trapped>
+   <pdfx:GTS_PDFXVersion>` + pdfxVersion + `</pdfx:GTS_PDFXVersion>
+   <pdfxid:GTS_PDFXVersion>` + pdfxVersion + `</pdfxid:GTS_PDFXVersion>
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>`)
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// pdfxProject is a handle without a project folder; output paths in these tests are absolute.
+func pdfxProject(t *testing.T) *storage.ProjectHandle {
+	p := sampleProject()
+	p.Issues[0].Pages[0].Panels[0].Captions = []domain.Caption{{Text: "Meanwhile…", Rect: domain.Rect{X: 40, Y: 200, Width: 120, Height: 30}}}
+	p.Issues[0].Pages[0].Panels[0].SFX = []domain.SFXItem{{Text: "BOOM", Rect: domain.Rect{X: 40, Y: 300, Width: 120, Height: 40}}}
+	return &storage.ProjectHandle{Root: t.TempDir(), Project: p}
+}
+
+var reStream = regexp.MustCompile(`(?s)<<([^>]*?)>>\s*stream\r?\n(.*?)\r?\nendstream`)
+
+// pdfContentStreams returns the decoded streams of a PDF, skipping the embedded ICC profile.
+func pdfContentStreams(t *testing.T, doc []byte) []string {
+	var out []string
+	for _, m := range reStream.FindAllSubmatch(doc, -1) {
+		dict, data := string(m[1]), m[2]
+		if strings.Contains(dict, "/N 4") {
+			continue
+		}
+		if strings.Contains(dict, "/FlateDecode") {
+			zr, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				continue // font programs and other binary streams
+			}
+			dec, err := io.ReadAll(zr)
+			if err != nil {
+				continue
+			}
+			data = dec
+		}
+		out = append(out, string(data))
+	}
+	return out
+}
+
+func TestExportIssuePDF_X1a(t *testing.T) {
+	ph := pdfxProject(t)
+	out := filepath.Join(ph.Root, "x1a.pdf")
+	if err := ExportIssuePDF(ph, 0, out, PDFOptions{IncludeGuides: true, OutputProfile: OutputProfilePDFX1a, OutputCondition: "FOGRA39"}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	doc, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	for _, want := range []string{
+		"/Type /OutputIntent /S /GTS_PDFX",
+		"/OutputConditionIdentifier (FOGRA39)",
+		"/DestOutputProfile",
+		"/OutputIntents [",
+		"/GTS_PDFXVersion (PDF/X-1a:2003)",
+		"/Trapped /False",
+		"<pdfxid:GTS_PDFXVersion>PDF/X-1a:2003</pdfxid:GTS_PDFXVersion>",
+		"/TrimBox",
+		"/ID [<",
+		"/FontFile2",
+	} {
+		if !bytes.Contains(doc, []byte(want)) {
+			t.Errorf("missing %q", want)
+		}
+	}
+	if bytes.Contains(doc, []byte("/DeviceRGB")) {
+		t.Errorf("PDF/X-1a output references DeviceRGB")
+	}
+	if bytes.Contains(doc, []byte("/BaseFont /Helvetica")) {
+		t.Errorf("non-embedded Helvetica used")
+	}
+	rgbOp := regexp.MustCompile(`(?m)(^|\s)[\d.]+ [\d.]+ [\d.]+ (rg|RG)\b`)
+	cmyk := false
+	for _, s := range pdfContentStreams(t, doc) {
+		if rgbOp.MatchString(s) {
+			t.Errorf("RGB color operator in content stream: %q", rgbOp.FindString(s))
+		}
+		if strings.Contains(s, " k\n") || strings.Contains(s, " K\n") {
+			cmyk = true
+		}
+	}
+	if !cmyk {
+		t.Errorf("no CMYK color operators found")
+	}
+	// The update's xref entries must point at their objects.
+	xi := bytes.LastIndex(doc, []byte("\nxref\n"))
+	entry := regexp.MustCompile(`(\d+) 1\n(\d{10}) 00000 n `)
+	tail := doc[xi:]
+	matches := entry.FindAllSubmatch(tail[:bytes.Index(tail, []byte("trailer"))], -1)
+	if len(matches) != 5 {
+		t.Fatalf("expected 5 updated objects, got %d", len(matches))
+	}
+	for _, m := range matches {
+		off, _ := strconv.Atoi(string(m[2]))
+		if !bytes.HasPrefix(doc[off:], []byte(string(m[1])+" 0 obj")) {
+			t.Errorf("xref offset %d does not point at object %s", off, m[1])
+		}
+	}
+}
+
+func TestExportIssuePDF_X1aProfileErrors(t *testing.T) {
+	ph := pdfxProject(t)
+	out := filepath.Join(ph.Root, "x1a.pdf")
+	err := ExportIssuePDF(ph, 0, out, PDFOptions{OutputProfile: OutputProfilePDFX1a, ICCProfilePath: filepath.Join(ph.Root, "missing.icc")})
+	if err == nil || !strings.Contains(err.Error(), "ICC profile") {
+		t.Fatalf("expected ICC error, got %v", err)
+	}
+	if _, serr := os.Stat(out); !os.IsNotExist(serr) {
+		t.Fatalf("no output expected when the profile is missing")
+	}
+	rgb := append([]byte(nil), defaultCMYKProfile()...)
+	copy(rgb[16:], "RGB ")
+	p := filepath.Join(ph.Root, "rgb.icc")
+	if err := os.WriteFile(p, rgb, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err = ExportIssuePDF(ph, 0, out, PDFOptions{OutputProfile: OutputProfilePDFX1a, ICCProfilePath: p})
+	if err == nil || !strings.Contains(err.Error(), "CMYK profile is required") {
+		t.Fatalf("expected CMYK requirement error, got %v", err)
+	}
+	// A valid profile from disk is accepted and named in the OutputIntent.
+	good := filepath.Join(ph.Root, "Press.icc")
+	if err := os.WriteFile(good, defaultCMYKProfile(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportIssuePDF(ph, 0, out, PDFOptions{OutputProfile: OutputProfilePDFX1a, ICCProfilePath: good}); err != nil {
+		t.Fatalf("export with profile file: %v", err)
+	}
+	doc, _ := os.ReadFile(out)
+	if !bytes.Contains(doc, []byte("/OutputCondition (Press)")) {
+		t.Fatalf("profile name not used as output condition")
+	}
+}
+
+func TestDefaultCMYKProfileHeader(t *testing.T) {
+	icc := defaultCMYKProfile()
+	if int(binary.BigEndian.Uint32(icc)) != len(icc) {
+		t.Fatalf("header size %d != %d", binary.BigEndian.Uint32(icc), len(icc))
+	}
+	if string(icc[12:24]) != "prtrCMYKLab " || string(icc[36:40]) != "acsp" {
+		t.Fatalf("unexpected header %q", icc[12:40])
+	}
+	n := int(binary.BigEndian.Uint32(icc[128:]))
+	for i := 0; i < n; i++ {
+		e := icc[132+12*i:]
+		off, size := binary.BigEndian.Uint32(e[4:]), binary.BigEndian.Uint32(e[8:])
+		if off%4 != 0 || int(off+size) > len(icc) {
+			t.Fatalf("tag %q out of bounds or unaligned: %d+%d", e[:4], off, size)
+		}
+	}
+}
+
+func TestRGBToCMYK(t *testing.T) {
+	cases := []struct {
+		r, g, b    float64
+		c, m, y, k float64
+	}{
+		{0, 0, 0, 0, 0, 0, 1},
+		{1, 1, 1, 0, 0, 0, 0},
+		{1, 0, 0, 0, 1, 1, 0},
+		{0.5, 0.5, 0.5, 0, 0, 0, 0.5},
+	}
+	for _, tc := range cases {
+		c, m, y, k := rgbToCMYK(tc.r, tc.g, tc.b)
+		if c != tc.c || m != tc.m || y != tc.y || k != tc.k {
+			t.Errorf("rgbToCMYK(%v,%v,%v) = %v %v %v %v", tc.r, tc.g, tc.b, c, m, y, k)
+		}
+	}
+	// translucent black over white paper becomes grey
+	r, g, b := flattenOnPaper(domain.Color{A: 128})
+	if r < 0.49 || r > 0.51 || r != g || g != b {
+		t.Fatalf("flatten: %v %v %v", r, g, b)
+	}
+}
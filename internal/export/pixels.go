@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"gocomicwriter/internal/domain"
+)
+
+// DefaultMaxPixels is the longest side in pixels a raster export renders by default. A page of
+// 20000 px takes about 1.6 GB as RGBA, which is far above any print size but still allocates.
+const DefaultMaxPixels = 20000
+
+// ErrImageTooLarge is returned (wrapped in a *PixelSizeError) when an export would render an image
+// with a side longer than MaxPixels, typically because of a typo in the trim size or DPI.
+var ErrImageTooLarge = errors.New("image too large")
+
+// PixelSizeError describes an image that exceeds the pixel limit. What names the image, e.g. "page 3".
+type PixelSizeError struct {
+	What          string
+	Width, Height int
+	DPI           int // 0 when the size does not come from a resolution, as for webtoon strips
+	Max           int
+}
+
+func (e *PixelSizeError) Error() string {
+	if e.DPI > 0 {
+		return fmt.Sprintf("%s would be %d×%d px at %d dpi, above the limit of %d px per side", e.What, e.Width, e.Height, e.DPI, e.Max)
+	}
+	return fmt.Sprintf("%s would be %d×%d px, above the limit of %d px per side", e.What, e.Width, e.Height, e.Max)
+}
+
+// Is reports ErrImageTooLarge so callers can use errors.Is without type assertions.
+func (e *PixelSizeError) Is(target error) bool { return target == ErrImageTooLarge }
+
+var (
+	maxPixelsMu sync.Mutex
+	maxPixels   = DefaultMaxPixels
+)
+
+// SetMaxPixels sets the longest side in pixels raster exports render. Non-positive values use
+// DefaultMaxPixels.
+func SetMaxPixels(n int) {
+	if n <= 0 {
+		n = DefaultMaxPixels
+	}
+	maxPixelsMu.Lock()
+	maxPixels = n
+	maxPixelsMu.Unlock()
+}
+
+// MaxPixels is the current limit set by SetMaxPixels.
+func MaxPixels() int {
+	maxPixelsMu.Lock()
+	defer maxPixelsMu.Unlock()
+	return maxPixels
+}
+
+// CheckPixels returns a *PixelSizeError when an image of w×h pixels exceeds MaxPixels.
+func CheckPixels(what string, w, h, dpi int) error {
+	if limit := MaxPixels(); w > limit || h > limit {
+		return &PixelSizeError{What: what, Width: w, Height: h, DPI: dpi, Max: limit}
+	}
+	return nil
+}
+
+// MediaPixels is the size in pixels of a single page of iss including bleed at dpi, as the raster
+// exporters render it.
+func MediaPixels(iss domain.Issue, dpi int) (w, h int) {
+	return domain.PointsToPixels(iss.TrimWidth+2*iss.Bleed, dpi), domain.PointsToPixels(iss.TrimHeight+2*iss.Bleed, dpi)
+}
+
+// checkSheetPixels checks the media size of every sheet at dpi before anything is rendered; a spread
+// is twice as wide as a page.
+func checkSheetPixels(iss domain.Issue, sheets []sheet, dpi int) error {
+	for _, sh := range sheets {
+		w := domain.PointsToPixels(sh.trimWidth(iss.TrimWidth)+2*iss.Bleed, dpi)
+		h := domain.PointsToPixels(iss.TrimHeight+2*iss.Bleed, dpi)
+		what := "page " + sheetLabel(iss, sh)
+		if sh.spread() {
+			what = "pages " + sheetLabel(iss, sh)
+		}
+		if err := CheckPixels(what, w, h, dpi); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func TestCheckPixels(t *testing.T) {
+	t.Cleanup(func() { SetMaxPixels(0) })
+	if MaxPixels() != DefaultMaxPixels {
+		t.Fatalf("default limit = %d", MaxPixels())
+	}
+	if err := CheckPixels("page 1", DefaultMaxPixels, 100, 300); err != nil {
+		t.Fatalf("limit itself rejected: %v", err)
+	}
+	SetMaxPixels(1000)
+	err := CheckPixels("page 2", 800, 1001, 300)
+	var pe *PixelSizeError
+	if !errors.Is(err, ErrImageTooLarge) || !errors.As(err, &pe) || pe.Height != 1001 || pe.Max != 1000 {
+		t.Fatalf("err = %v", err)
+	}
+	if !strings.Contains(err.Error(), "page 2 would be 800×1001 px at 300 dpi") {
+		t.Fatalf("message = %q", err.Error())
+	}
+	SetMaxPixels(-5)
+	if MaxPixels() != DefaultMaxPixels {
+		t.Fatalf("non-positive limit not reset: %d", MaxPixels())
+	}
+}
+
+func TestMediaPixels(t *testing.T) {
+	iss := domain.Issue{TrimWidth: 477, TrimHeight: 738, Bleed: 9, Pages: []domain.Page{{Number: 1}}} // 6.625 × 10.25 in, 0.125 in bleed
+	if w, h := MediaPixels(iss, 300); w != 2063 || h != 3150 {
+		t.Fatalf("media = %d×%d", w, h)
+	}
+	img := renderSheetImage(iss, sheet{left: 0, right: -1}, pixelsPerPoint(72), newRasterStyle(domain.Project{}, exportStyle{}, false), nil)
+	if w, h := MediaPixels(iss, 72); img.Bounds().Dx() != w || img.Bounds().Dy() != h {
+		t.Fatalf("rendered %v, media %d×%d", img.Bounds(), w, h)
+	}
+}
+
+func TestExportsRejectHugeMedia(t *testing.T) {
+	root := t.TempDir()
+	proj := sampleProject()
+	proj.Issues[0].TrimWidth = domain.MMToPoints(3500) // typo for 350 mm
+	proj.Issues[0].DPI = 300
+	ph, err := storage.InitProject(root, proj)
+	if err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	outDir := filepath.Join(root, "exports", "huge")
+	if err := ExportIssuePNGPages(ph, 0, outDir, PNGOptions{NoReport: true}); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("png: %v", err)
+	}
+	if _, err := os.Stat(outDir); !os.IsNotExist(err) {
+		t.Fatalf("png export created %s: %v", outDir, err)
+	}
+	cbz := filepath.Join(root, "exports", "huge.cbz")
+	if err := ExportIssueCBZ(ph, 0, cbz, CBZOptions{NoReport: true}); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("cbz: %v", err)
+	}
+	if _, err := os.Stat(cbz); !os.IsNotExist(err) {
+		t.Fatalf("cbz export created the archive: %v", err)
+	}
+	if err := ExportIssueEPUB(ph, 0, filepath.Join(root, "exports", "huge.epub"), EPUBOptions{NoReport: true}); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("epub: %v", err)
+	}
+	// A lower DPI brings the same page under the limit
+	if err := ExportIssuePNGPages(ph, 0, outDir, PNGOptions{DPI: 10, NoReport: true}); err != nil {
+		t.Fatalf("png at 10 dpi: %v", err)
+	}
+}
+
+func TestPanelExportRejectsHugeCrop(t *testing.T) {
+	t.Cleanup(func() { SetMaxPixels(0) })
+	ph, err := storage.InitProject(t.TempDir(), sampleProject())
+	if err != nil {
+		t.Fatalf("init project: %v", err)
+	}
+	SetMaxPixels(500) // the 324 × 504 pt panel is 675 × 1050 px at 150 dpi
+	out := filepath.Join(ph.Root, "exports", "p1.png")
+	if err := ExportPanelPNG(ph, 0, 1, "p1", out, PanelPNGOptions{NoReport: true}); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("panel: %v", err)
+	}
+	if _, err := ExportPagePanelsPNG(ph, 0, 1, filepath.Join(ph.Root, "exports"), PanelPNGOptions{NoReport: true}); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("page panels: %v", err)
+	}
+}
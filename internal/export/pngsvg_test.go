@@ -9,8 +9,14 @@
 package export
 
 import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"gocomicwriter/internal/domain"
@@ -83,3 +89,171 @@ func TestExportIssueSVGPages(t *testing.T) {
 		t.Fatalf("svg empty")
 	}
 }
+
+func TestExportCaptionsAndSFX(t *testing.T) {
+	root := t.TempDir()
+	proj := sampleProject()
+	pnl := &proj.Issues[0].Pages[0].Panels[0]
+	pnl.Captions = []domain.Caption{{ID: "c1", Text: "Meanwhile <later>", Rect: domain.Rect{X: 30, Y: 200, Width: 200, Height: 40}}}
+	pnl.SFX = []domain.SFXItem{{ID: "s1", Text: "KRAKOOM", Size: 40, Rect: domain.Rect{X: 60, Y: 300, Width: 200, Height: 50}, Rotation: -12}}
+	ph := &storage.ProjectHandle{Root: root, Project: proj}
+
+	svgDir := filepath.Join(root, "svg")
+	if err := ExportIssueSVGPages(ph, 0, svgDir, SVGOptions{}); err != nil {
+		t.Fatalf("export svg: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(svgDir, "issue-1-page-1.svg"))
+	if err != nil {
+		t.Fatalf("read svg: %v", err)
+	}
+	svg := string(b)
+	for _, want := range []string{"Meanwhile &lt;later&gt;", "KRAKOOM", "rotate(-12 "} {
+		if !strings.Contains(svg, want) {
+			t.Fatalf("svg missing %q:\n%s", want, svg)
+		}
+	}
+
+	pngDir := filepath.Join(root, "png")
+	if err := ExportIssuePNGPages(ph, 0, pngDir, PNGOptions{DPI: 72}); err != nil {
+		t.Fatalf("export png: %v", err)
+	}
+	f, err := os.Open(filepath.Join(pngDir, "issue-1-page-1.png"))
+	if err != nil {
+		t.Fatalf("open png: %v", err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	// Caption box border (black stroke) at its top-left corner, offset by bleed.
+	if r, g, b, _ := img.At(30+18, 200+18).RGBA(); r != 0 || g != 0 || b != 0 {
+		t.Fatalf("expected caption border pixel to be black, got %d,%d,%d", r, g, b)
+	}
+	// Some dark text pixels must exist in the SFX area.
+	dark := 0
+	for y := 300 + 18; y < 300+18+20; y++ {
+		for x := 60 + 18; x < 60+18+60; x++ {
+			if r, _, _, _ := img.At(x, y).RGBA(); r < 0x8000 {
+				dark++
+			}
+		}
+	}
+	if dark == 0 {
+		t.Fatalf("expected SFX text pixels in raster output")
+	}
+
+	if err := ExportIssuePDF(ph, 0, filepath.Join(root, "out.pdf"), PDFOptions{}); err != nil {
+		t.Fatalf("export pdf: %v", err)
+	}
+}
+
+func TestExportBalloonStyles(t *testing.T) {
+	root := t.TempDir()
+	proj := sampleProject()
+	grey := domain.Color{R: 0xee, G: 0xee, B: 0xee, A: 255}
+	proj.BalloonStyles = []domain.BalloonStyle{
+		{Name: "Whisper", Shape: "ellipse", StrokeStyle: domain.BalloonStrokeDashed, Fill: &grey},
+		{Name: "Shout", Shape: "burst", StrokeWidth: 2, Padding: 14},
+	}
+	pnl := &proj.Issues[0].Pages[0].Panels[0]
+	pnl.Balloons = []domain.Balloon{
+		{ID: "b1", StyleRef: "Whisper", Shape: domain.Shape{Kind: "ellipse", Rect: domain.Rect{X: 40, Y: 40, Width: 120, Height: 60}}},
+		{ID: "b2", StyleRef: "Shout", Shape: domain.Shape{Kind: "burst", Rect: domain.Rect{X: 40, Y: 200, Width: 160, Height: 100}}},
+	}
+	ph := &storage.ProjectHandle{Root: root, Project: proj}
+
+	svgDir := filepath.Join(root, "svg")
+	if err := ExportIssueSVGPages(ph, 0, svgDir, SVGOptions{}); err != nil {
+		t.Fatalf("export svg: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(svgDir, "issue-1-page-1.svg"))
+	if err != nil {
+		t.Fatalf("read svg: %v", err)
+	}
+	svg := string(b)
+	for _, want := range []string{`fill="#eeeeee" stroke="#000000" stroke-width="1" stroke-dasharray="3 2"`, `<polygon points="`, `stroke-width="2"/>`} {
+		if !strings.Contains(svg, want) {
+			t.Fatalf("svg missing %q:\n%s", want, svg)
+		}
+	}
+
+	// Raster output draws the preset's fill and dashes along the balloon's shape
+	pngDir := filepath.Join(root, "png")
+	if err := ExportIssuePNGPages(ph, 0, pngDir, PNGOptions{DPI: 72}); err != nil {
+		t.Fatalf("export png: %v", err)
+	}
+	f, err := os.Open(filepath.Join(pngDir, "issue-1-page-1.png"))
+	if err != nil {
+		t.Fatalf("open png: %v", err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	if r, _, _, _ := img.At(40+18+60, 40+18+30).RGBA(); r>>8 != 0xee {
+		t.Fatalf("whisper fill red = %#x, want 0xee", r>>8)
+	}
+	// The ellipse leaves the corner of its box blank; its outline starts with a dash at the top
+	if r, _, _, _ := img.At(40+18+1, 40+18+1).RGBA(); r>>8 != 0xff {
+		t.Fatalf("whisper box corner red = %#x, want the white page", r>>8)
+	}
+	if r, _, _, _ := img.At(40+18+60, 40+18).RGBA(); r>>8 > 0x80 {
+		t.Fatalf("whisper outline should start with a dash, got red %#x", r>>8)
+	}
+	if r, _, _, _ := img.At(40+18+60+3, 40+18).RGBA(); r>>8 < 0xee {
+		t.Fatalf("whisper outline should have a gap after the first dash, got red %#x", r>>8)
+	}
+
+	if err := ExportIssuePDF(ph, 0, filepath.Join(root, "out.pdf"), PDFOptions{}); err != nil {
+		t.Fatalf("export pdf: %v", err)
+	}
+}
+
+func TestExportIgnoresReferenceImage(t *testing.T) {
+	root := t.TempDir()
+	// A solid red reference at full opacity would show in any export that drew it
+	if err := os.MkdirAll(filepath.Join(root, "assets"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	red := image.NewRGBA(image.Rect(0, 0, 8, 12))
+	draw.Draw(red, red.Bounds(), &image.Uniform{C: color.RGBA{255, 0, 0, 255}}, image.Point{}, draw.Src)
+	f, err := os.Create(filepath.Join(root, "assets", "sketch.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, red); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	export := func(name string, ref *domain.ReferenceImage) (pngData, svgData []byte) {
+		proj := sampleProject()
+		proj.Issues[0].Pages[0].ReferenceImage = ref
+		ph := &storage.ProjectHandle{Root: root, Project: proj}
+		dir := filepath.Join(root, name)
+		if err := ExportIssuePNGPages(ph, 0, dir, PNGOptions{DPI: 72}); err != nil {
+			t.Fatalf("export png: %v", err)
+		}
+		if err := ExportIssueSVGPages(ph, 0, dir, SVGOptions{}); err != nil {
+			t.Fatalf("export svg: %v", err)
+		}
+		var err error
+		if pngData, err = os.ReadFile(filepath.Join(dir, "issue-1-page-1.png")); err != nil {
+			t.Fatal(err)
+		}
+		if svgData, err = os.ReadFile(filepath.Join(dir, "issue-1-page-1.svg")); err != nil {
+			t.Fatal(err)
+		}
+		return pngData, svgData
+	}
+	plainPNG, plainSVG := export("plain", nil)
+	refPNG, refSVG := export("ref", &domain.ReferenceImage{Asset: "assets/sketch.png", Opacity: 1, Fit: domain.ReferenceFitStretch})
+	if !bytes.Equal(plainPNG, refPNG) {
+		t.Fatalf("reference image changed the PNG export")
+	}
+	if !bytes.Equal(plainSVG, refSVG) || strings.Contains(string(refSVG), "sketch") {
+		t.Fatalf("reference image changed the SVG export")
+	}
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// Preflight checks, the Check of a PreflightWarning.
+const (
+	PreflightSafeArea  = "safe-area"  // balloon or caption text close to the trim or the spine
+	PreflightMediaBox  = "media-box"  // panel extending past the bleed
+	PreflightEmptyPage = "empty-page" // page without panels
+	PreflightLowDPI    = "low-dpi"    // raster resolution too low for print
+	PreflightSpread    = "spread"     // spread whose pages do not face each other in print
+	PreflightGutters   = "gutters"    // page whose gutters between panels differ
+)
+
+// PreflightSafeMarginMM is how far inside the trim balloon and caption text should stay, so that
+// trimming tolerances do not cut it.
+const PreflightSafeMarginMM = 5.0
+
+// PreflightGutterToleranceMM is how far a gutter may differ from the page's median gutter before the
+// page is reported; hand-placed panels differing by a point already show in print.
+const PreflightGutterToleranceMM = 0.3
+
+// MinPrintDPI is the lowest raster resolution accepted for print without a warning.
+const MinPrintDPI = 300
+
+// PreflightWarning is a print problem found before exporting.
+type PreflightWarning struct {
+	Check   string // one of the Preflight* checks
+	Page    int    // page number; 0 for the whole issue
+	Element string // panel, balloon or caption ID; empty for pages and the issue
+	Message string
+}
+
+// String formats the warning with its location, e.g. "page 3: balloon b1 is 2.1 mm from the trim".
+func (w PreflightWarning) String() string {
+	if w.Page == 0 {
+		return w.Message
+	}
+	return fmt.Sprintf("page %d: %s", w.Page, w.Message)
+}
+
+// PreflightIssue checks an issue for print mistakes that exports do not refuse: text in the trim
+// margin or across a spread's spine, panels past the bleed, empty pages, uneven gutters, a resolution
+// below MinPrintDPI and spreads that do not face each other in print. Pages whose panels do not form
+// rows and columns are not checked for gutters. It uses the sheet layout and units
+// of the exporters, so findings match what the PDF and CBZ show.
+func PreflightIssue(ph *storage.ProjectHandle, issueIdx int) ([]PreflightWarning, error) {
+	if ph == nil {
+		return nil, fmt.Errorf("project handle is nil")
+	}
+	if issueIdx < 0 || issueIdx >= len(ph.Project.Issues) {
+		return nil, fmt.Errorf("issue index out of range")
+	}
+	iss := ph.Project.Issues[issueIdx]
+	var out []PreflightWarning
+	if dpi := exportDPI(iss, 0); dpi < MinPrintDPI {
+		out = append(out, PreflightWarning{Check: PreflightLowDPI,
+			Message: fmt.Sprintf("the issue's resolution is %d DPI; print needs at least %d", dpi, MinPrintDPI)})
+	}
+	out = append(out, preflightSpreads(iss)...)
+
+	margin := domain.MMToPoints(PreflightSafeMarginMM)
+	for _, sh := range issueSheets(iss, pageIndexes(len(iss.Pages), nil)) {
+		trim, media := sheetBoxes(iss, sh)
+		safe := insetRect(trim, margin)
+		pidxs, offsets := sh.pages(iss.TrimWidth)
+		for k, pidx := range pidxs {
+			pg := iss.Pages[pidx]
+			if len(pg.Panels) == 0 {
+				out = append(out, PreflightWarning{Check: PreflightEmptyPage, Page: pg.Number, Message: "the page has no panels"})
+			}
+			if w, ok := preflightGutters(pg); ok {
+				out = append(out, w)
+			}
+			text := func(kind, id string, r domain.Rect) {
+				r = offsetRect(r, offsets[k])
+				name := kind + " " + id
+				switch {
+				case !rectContains(trim, r):
+					out = append(out, PreflightWarning{Check: PreflightSafeArea, Page: pg.Number, Element: id,
+						Message: name + " crosses the trim"})
+				case !rectContains(safe, r):
+					out = append(out, PreflightWarning{Check: PreflightSafeArea, Page: pg.Number, Element: id,
+						Message: fmt.Sprintf("%s is %.1f mm from the trim (keep %.0f mm)", name, domain.PointsToMM(trimDistance(trim, r)), PreflightSafeMarginMM)})
+				case sh.spread() && r.X < iss.TrimWidth+margin && r.X+r.Width > iss.TrimWidth-margin:
+					out = append(out, PreflightWarning{Check: PreflightSafeArea, Page: pg.Number, Element: id,
+						Message: fmt.Sprintf("%s is within %.0f mm of the spine", name, PreflightSafeMarginMM)})
+				}
+			}
+			for _, pnl := range pg.Panels {
+				if !rectContains(media, offsetRect(pnl.Geometry, offsets[k])) {
+					out = append(out, PreflightWarning{Check: PreflightMediaBox, Page: pg.Number, Element: pnl.ID,
+						Message: fmt.Sprintf("panel %s extends past the bleed and will be cut off", pnl.ID)})
+				}
+				for _, b := range pnl.Balloons {
+					text("balloon", b.ID, b.Shape.Rect)
+				}
+				for _, c := range pnl.Captions {
+					text("caption", c.ID, rotatedBounds(c.Rect, c.Rotation))
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// trimDistance is the distance in points from r to the nearest edge of trim; r lies inside trim.
+func trimDistance(trim, r domain.Rect) float64 {
+	return min(r.X-trim.X, r.Y-trim.Y, trim.X+trim.Width-(r.X+r.Width), trim.Y+trim.Height-(r.Y+r.Height))
+}
+
+// preflightGutters reports a page whose gutters differ from their median by more than
+// PreflightGutterToleranceMM.
+func preflightGutters(pg domain.Page) (PreflightWarning, bool) {
+	st, err := storage.PageGutters(pg)
+	if err != nil || st.Count < 2 {
+		return PreflightWarning{}, false
+	}
+	if max(st.Max-st.Median, st.Median-st.Min) <= domain.MMToPoints(PreflightGutterToleranceMM) {
+		return PreflightWarning{}, false
+	}
+	return PreflightWarning{Check: PreflightGutters, Page: pg.Number,
+		Message: fmt.Sprintf("gutters range from %.1f to %.1f mm around a median of %.1f mm", domain.PointsToMM(max(st.Min, 0)), domain.PointsToMM(st.Max), domain.PointsToMM(st.Median))}, true
+}
+
+// preflightSpreads reports broken spread links, and spreads that start on a right-hand page (a
+// left-hand page in right-to-left issues): printed, their pages are the two sides of one leaf. The
+// first page after the optional title page is a right-hand page.
+func preflightSpreads(iss domain.Issue) []PreflightWarning {
+	var out []PreflightWarning
+	for _, pg := range iss.Pages {
+		if pg.SpreadWith != 0 && storage.SpreadPartner(iss, pg.Number) == 0 {
+			out = append(out, PreflightWarning{Check: PreflightSpread, Page: pg.Number,
+				Message: fmt.Sprintf("page %d is marked as a spread with page %d, which is missing, not adjacent or not marked back; it is exported as a single page", pg.Number, pg.SpreadWith)})
+		}
+	}
+	side, dir := "right-hand", "left-to-right"
+	if isRTLDirection(iss.ReadingDirection) {
+		side, dir = "left-hand", "right-to-left"
+	}
+	first := 0 // printed position of the issue's first page; even positions are the opening side
+	if hasTitlePage(iss) {
+		first = 1
+	}
+	for i, pg := range iss.Pages {
+		p := storage.SpreadPartner(iss, pg.Number)
+		if p == 0 || i+1 >= len(iss.Pages) || iss.Pages[i+1].Number != p {
+			continue // single page, or the second page of a spread
+		}
+		if (first+i)%2 == 0 {
+			out = append(out, PreflightWarning{Check: PreflightSpread, Page: pg.Number,
+				Message: fmt.Sprintf("spread %d-%d does not face in print: page %d is a %s page in a %s issue", pg.Number, p, pg.Number, side, dir)})
+		}
+	}
+	return out
+}
+
+// PreflightSummary counts the warnings per check in a short line, e.g. "2 text near trim, 1 empty page".
+func PreflightSummary(ws []PreflightWarning) string {
+	labels := []struct{ check, one, many string }{
+		{PreflightSafeArea, "text near trim or spine", "text near trim or spine"},
+		{PreflightMediaBox, "panel past the bleed", "panels past the bleed"},
+		{PreflightEmptyPage, "empty page", "empty pages"},
+		{PreflightLowDPI, "low resolution", "low resolution"},
+		{PreflightSpread, "spread problem", "spread problems"},
+		{PreflightGutters, "page with uneven gutters", "pages with uneven gutters"},
+	}
+	counts := map[string]int{}
+	for _, w := range ws {
+		counts[w.Check]++
+	}
+	var parts []string
+	for _, l := range labels {
+		switch n := counts[l.check]; {
+		case n == 1:
+			parts = append(parts, "1 "+l.one)
+		case n > 1:
+			parts = append(parts, fmt.Sprintf("%d %s", n, l.many))
+		}
+	}
+	if len(parts) == 0 {
+		return "no problems found"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func preflightProject() domain.Project {
+	full := domain.Rect{X: 18, Y: 18, Width: 324, Height: 504}
+	return domain.Project{Issues: []domain.Issue{{
+		TrimWidth: 360, TrimHeight: 540, Bleed: 18, DPI: 150,
+		Pages: []domain.Page{
+			{Number: 1, Panels: []domain.Panel{{ID: "p1", Geometry: full,
+				Balloons: []domain.Balloon{{ID: "b1", Shape: domain.Shape{Kind: "rect", Rect: domain.Rect{X: 5, Y: 100, Width: 100, Height: 50}}}},
+				Captions: []domain.Caption{
+					{ID: "c1", Rect: domain.Rect{X: 100, Y: 300, Width: 100, Height: 40}},
+					// 5pt from the trim unrotated, but turned upright it sits well inside
+					{ID: "c2", Rect: domain.Rect{X: 5, Y: 400, Width: 200, Height: 40}, Rotation: 90},
+					{ID: "c3", Rect: domain.Rect{X: 300, Y: 10, Width: 100, Height: 40}},
+				},
+			}}},
+			{Number: 2, SpreadWith: 3, Panels: []domain.Panel{{ID: "p2", Geometry: domain.Rect{X: 18, Y: 18, Width: 684, Height: 504},
+				Balloons: []domain.Balloon{{ID: "b2", Shape: domain.Shape{Kind: "rect", Rect: domain.Rect{X: 300, Y: 100, Width: 80, Height: 40}}}},
+			}}},
+			{Number: 3, SpreadWith: 2, Panels: []domain.Panel{{ID: "p3", Geometry: domain.Rect{X: 300, Y: 18, Width: 100, Height: 100}}}},
+			{Number: 4},
+			{Number: 5, SpreadWith: 6, Panels: []domain.Panel{{ID: "p5", Geometry: full}}},
+			{Number: 6, SpreadWith: 5, Panels: []domain.Panel{{ID: "p6", Geometry: full}}},
+			{Number: 7, SpreadWith: 9, Panels: []domain.Panel{{ID: "p7", Geometry: full}}},
+		},
+	}}}
+}
+
+func preflightKeys(ws []PreflightWarning) []string {
+	var keys []string
+	for _, w := range ws {
+		keys = append(keys, strings.TrimSpace(fmt.Sprintf("%s@%d %s", w.Check, w.Page, w.Element)))
+	}
+	return keys
+}
+
+func TestPreflightIssue(t *testing.T) {
+	ph := &storage.ProjectHandle{Project: preflightProject()}
+	ws, err := PreflightIssue(ph, 0)
+	if err != nil {
+		t.Fatalf("preflight: %v", err)
+	}
+	want := []string{
+		"low-dpi@0",
+		"spread@7",
+		"spread@5",
+		"safe-area@1 b1",
+		"safe-area@1 c3",
+		"safe-area@2 b2",
+		"media-box@3 p3",
+		"empty-page@4",
+	}
+	if got := preflightKeys(ws); !reflect.DeepEqual(got, want) {
+		t.Fatalf("warnings = %v\nwant %v\n%v", got, want, ws)
+	}
+	for _, w := range ws {
+		switch w.Element {
+		case "b1":
+			if w.Message != "balloon b1 is 1.8 mm from the trim (keep 5 mm)" {
+				t.Errorf("b1: %q", w.Message)
+			}
+		case "c3":
+			if w.Message != "caption c3 crosses the trim" {
+				t.Errorf("c3: %q", w.Message)
+			}
+		case "b2":
+			if !strings.Contains(w.Message, "spine") {
+				t.Errorf("b2: %q", w.Message)
+			}
+		}
+	}
+	if got := PreflightSummary(ws); got != "3 text near trim or spine, 1 panel past the bleed, 1 empty page, 1 low resolution, 2 spread problems" {
+		t.Errorf("summary = %q", got)
+	}
+	if got := ws[2].String(); got != "page 5: spread 5-6 does not face in print: page 5 is a right-hand page in a left-to-right issue" {
+		t.Errorf("string = %q", got)
+	}
+
+	// A title page moves every content page one position on
+	ph.Project.Issues[0].FrontMatter = &domain.FrontMatter{TitlePage: true}
+	ph.Project.Issues[0].ReadingDirection = "rtl"
+	ws, _ = PreflightIssue(ph, 0)
+	var spreads []string
+	for _, w := range ws {
+		if w.Check == PreflightSpread && w.Page != 7 {
+			spreads = append(spreads, w.Message)
+		}
+	}
+	if len(spreads) != 1 || !strings.Contains(spreads[0], "spread 2-3") || !strings.Contains(spreads[0], "left-hand page in a right-to-left issue") {
+		t.Errorf("spreads with title page = %v", spreads)
+	}
+
+	if _, err := PreflightIssue(ph, 1); err == nil {
+		t.Error("expected an error for a missing issue")
+	}
+	if got := PreflightSummary(nil); got != "no problems found" {
+		t.Errorf("empty summary = %q", got)
+	}
+}
+
+func TestPreflightGutters(t *testing.T) {
+	row := func(n int, gutters ...float64) domain.Page {
+		pg := domain.Page{Number: n}
+		x := 18.0
+		for i, g := range append([]float64{0}, gutters...) {
+			x += g
+			pg.Panels = append(pg.Panels, domain.Panel{ID: fmt.Sprintf("p%d", i+1), Geometry: domain.Rect{X: x, Y: 18, Width: 60, Height: 200}})
+			x += 60
+		}
+		return pg
+	}
+	inset := domain.Page{Number: 3, Panels: []domain.Panel{
+		{ID: "p1", Geometry: domain.Rect{X: 18, Y: 18, Width: 324, Height: 504}},
+		{ID: "p2", Geometry: domain.Rect{X: 200, Y: 300, Width: 100, Height: 100}},
+	}}
+	ph := &storage.ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{
+		TrimWidth: 360, TrimHeight: 540, Bleed: 18, DPI: 300,
+		Pages: []domain.Page{row(1, 11, 12, 13), row(2, 12, 12.5, 12), inset},
+	}}}}
+	ws, err := PreflightIssue(ph, 0)
+	if err != nil {
+		t.Fatalf("preflight: %v", err)
+	}
+	if got := preflightKeys(ws); !reflect.DeepEqual(got, []string{"gutters@1"}) {
+		t.Fatalf("warnings = %v, want only page 1", got)
+	}
+	if ws[0].Message != "gutters range from 3.9 to 4.6 mm around a median of 4.2 mm" {
+		t.Errorf("message = %q", ws[0].Message)
+	}
+	if got := PreflightSummary(ws); got != "1 page with uneven gutters" {
+		t.Errorf("summary = %q", got)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"gocomicwriter/internal/domain"
 	"gocomicwriter/internal/storage"
 )
 
@@ -41,6 +42,7 @@ type BatchOptions struct {
 	DPIOverride   int      // when > 0 overrides raster/vector viewport DPI where applicable
 	IncludeGuides *bool    // when set, overrides preset's default for guides
 	OutDir        string   // base directory for outputs (created per preset if relative)
+	NoReport      bool     // skip the per-export reports and exports log entries
 }
 
 // BatchExport runs exports according to the given preset.
@@ -95,13 +97,14 @@ func BatchExport(ph *storage.ProjectHandle, opt BatchOptions) error {
 			case "pdf":
 				// Single file per issue
 				out := filepath.Join(baseOut, "pdf", fmt.Sprintf("issue-%d.pdf", issueIdx+1))
-				po := PDFOptions{IncludeGuides: guides, Pages: nil}
+				po := DefaultPDFOptions()
+				po.IncludeGuides, po.NoReport = guides, opt.NoReport
 				if err := ExportIssuePDF(ph, issueIdx, out, po); err != nil {
 					return fmt.Errorf("pdf issue %d: %w", issueIdx+1, err)
 				}
 			case "cbz":
 				out := filepath.Join(baseOut, "cbz", fmt.Sprintf("issue-%d.cbz", issueIdx+1))
-				co := CBZOptions{IncludeGuides: guides}
+				co := CBZOptions{IncludeGuides: guides, NoReport: opt.NoReport}
 				if opt.DPIOverride > 0 {
 					co.DPI = opt.DPIOverride
 				}
@@ -110,7 +113,7 @@ func BatchExport(ph *storage.ProjectHandle, opt BatchOptions) error {
 				}
 			case "png":
 				outDir := filepath.Join(baseOut, "png")
-				po := PNGOptions{IncludeGuides: guides, Pages: opt.Pages}
+				po := PNGOptions{IncludeGuides: guides, Pages: opt.Pages, NoReport: opt.NoReport}
 				if opt.DPIOverride > 0 {
 					po.DPI = opt.DPIOverride
 				}
@@ -119,7 +122,7 @@ func BatchExport(ph *storage.ProjectHandle, opt BatchOptions) error {
 				}
 			case "svg":
 				outDir := filepath.Join(baseOut, "svg")
-				so := SVGOptions{IncludeGuides: guides, Pages: opt.Pages}
+				so := SVGOptions{IncludeGuides: guides, Pages: opt.Pages, NoReport: opt.NoReport}
 				if opt.DPIOverride > 0 {
 					so.DPI = opt.DPIOverride
 				}
@@ -134,6 +137,48 @@ func BatchExport(ph *storage.ProjectHandle, opt BatchOptions) error {
 	return nil
 }
 
+// ExportWithPreset runs the exporter for a project-level preset on one issue.
+// out is the target file for pdf/cbz/epub and the target directory for png/svg.
+// Settings that do not apply to the preset's format are ignored.
+func ExportWithPreset(ph *storage.ProjectHandle, issueIndex int, p domain.ExportPreset, out string) error {
+	if ph == nil {
+		return fmt.Errorf("project handle is nil")
+	}
+	if issueIndex < 0 || issueIndex >= len(ph.Project.Issues) {
+		return fmt.Errorf("issue index out of range")
+	}
+	if err := storage.ValidateExportPreset(p); err != nil {
+		return fmt.Errorf("preset %q: %w", p.Name, err)
+	}
+	pages, err := storage.ParsePageSelection(p.Pages, ph.Project.Issues[issueIndex])
+	if err != nil {
+		return fmt.Errorf("preset %q: %w", p.Name, err)
+	}
+	switch p.Format {
+	case "pdf":
+		po := DefaultPDFOptions()
+		po.IncludeGuides, po.Pages = p.IncludeGuides, pages
+		return ExportIssuePDF(ph, issueIndex, out, po)
+	case "png":
+		return ExportIssuePNGPages(ph, issueIndex, out, PNGOptions{IncludeGuides: p.IncludeGuides, DPI: p.DPI, Pages: pages})
+	case "svg":
+		return ExportIssueSVGPages(ph, issueIndex, out, SVGOptions{IncludeGuides: p.IncludeGuides, DPI: p.DPI, Pages: pages})
+	case "cbz":
+		return ExportIssueCBZ(ph, issueIndex, out, CBZOptions{IncludeGuides: p.IncludeGuides, DPI: p.DPI, Pages: pages, PageOrder: p.PageOrder})
+	case "epub":
+		eo := EPUBOptions{IncludeGuides: p.IncludeGuides, DPI: p.DPI, Pages: pages, FixedLayout: true, PageOrder: p.PageOrder}
+		if m := p.EPUB; m != nil {
+			eo.Title, eo.Author, eo.Language = m.Title, m.Author, m.Language
+			eo.Publisher, eo.Description = m.Publisher, m.Description
+			eo.Series, eo.SeriesIndex = m.Series, m.SeriesIndex
+			eo.FixedLayout = !m.Reflowable
+		}
+		return ExportIssueEPUB(ph, issueIndex, out, eo)
+	default:
+		return fmt.Errorf("unknown format: %s", p.Format)
+	}
+}
+
 func presetDefaultFormats(p PresetName) []string {
 	switch p {
 	case PresetWeb:
@@ -6,8 +6,10 @@ package export
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"gocomicwriter/internal/domain"
 	"gocomicwriter/internal/storage"
 )
 
@@ -59,3 +61,47 @@ func TestBatchExport_PrintPreset(t *testing.T) {
 		}
 	}
 }
+
+func TestExportWithPreset(t *testing.T) {
+	proj := sampleProject()
+	proj.Issues[0].DPI = 36
+	proj.Issues[0].Pages = append(proj.Issues[0].Pages, domain.Page{Number: 2}, domain.Page{Number: 3})
+	root := t.TempDir()
+	ph := &storage.ProjectHandle{Root: root, Project: proj}
+
+	// PNG preset with a page selection: only pages 2 and 3 are written.
+	pngDir := filepath.Join(root, "png")
+	if err := ExportWithPreset(ph, 0, domain.ExportPreset{Name: "Web", Format: "png", DPI: 36, Pages: "2-"}, pngDir); err != nil {
+		t.Fatalf("png preset: %v", err)
+	}
+	ents, _ := os.ReadDir(pngDir)
+	var names []string
+	for _, e := range ents {
+		names = append(names, e.Name())
+	}
+	if strings.Join(names, ",") != "issue-1-page-2.png,issue-1-page-3.png,issue-1-png.export.json" {
+		t.Fatalf("unexpected png outputs: %v", names)
+	}
+
+	// EPUB overrides apply; a CBZ preset ignores the EPUB block.
+	epubPath := filepath.Join(root, "store.epub")
+	meta := &domain.EPUBMetadata{Title: "Preset Title", Language: "de"}
+	if err := ExportWithPreset(ph, 0, domain.ExportPreset{Name: "Store", Format: "epub", EPUB: meta}, epubPath); err != nil {
+		t.Fatalf("epub preset: %v", err)
+	}
+	opf := readZipEntries(t, epubPath)["OEBPS/content.opf"]
+	if !strings.Contains(opf, "Preset Title") || !strings.Contains(opf, "<dc:language>de</dc:language>") {
+		t.Fatalf("epub metadata overrides missing:\n%s", opf)
+	}
+	cbzPath := filepath.Join(root, "out.cbz")
+	if err := ExportWithPreset(ph, 0, domain.ExportPreset{Name: "Zip", Format: "cbz", Pages: "1", EPUB: meta}, cbzPath); err != nil {
+		t.Fatalf("cbz preset: %v", err)
+	}
+	if n := len(readZipEntries(t, cbzPath)); n != 2 { // one page + ComicInfo.xml
+		t.Fatalf("expected 2 cbz entries, got %d", n)
+	}
+
+	if err := ExportWithPreset(ph, 0, domain.ExportPreset{Name: "Bad", Format: "pdf", Pages: "9"}, filepath.Join(root, "x.pdf")); err == nil {
+		t.Fatalf("expected error for selection matching no pages")
+	}
+}
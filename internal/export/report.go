@@ -0,0 +1,277 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	applog "gocomicwriter/internal/log"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/version"
+)
+
+// ReportSuffix is appended to an export's output name for its report, e.g. issue-1.cbz.export.json.
+// Page-per-file exports (PNG, SVG) write issue-<n>-<format>.export.json into their output folder.
+const ReportSuffix = ".export.json"
+
+// ExportsLogFileName is the run log in the project's exports folder; every export run, successful or
+// not, appends one JSON line to it.
+const ExportsLogFileName = "exports-log.jsonl"
+
+// Report status values.
+const (
+	ReportStatusOK     = "ok"
+	ReportStatusFailed = "failed"
+)
+
+// ReportFile is one file written by an export run.
+type ReportFile struct {
+	Path   string `json:"path"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+// Report records what an export run produced. Only successful runs leave a report file; failed runs
+// remove a report left by an earlier run at the same place and are only logged.
+type Report struct {
+	Format     string       `json:"format"`
+	Status     string       `json:"status"`
+	Error      string       `json:"error,omitempty"`
+	Issue      int          `json:"issue"` // 1-based
+	Pages      []int        `json:"pages"` // page numbers
+	DPI        int          `json:"dpi,omitempty"`
+	Options    any          `json:"options"`
+	Files      []ReportFile `json:"files"`
+	TotalBytes int64        `json:"totalBytes"`
+	Fonts      []ReportFont `json:"fonts,omitempty"`
+	Warnings   []string     `json:"warnings,omitempty"`
+	AppVersion string       `json:"appVersion"`
+	StartedAt  time.Time    `json:"startedAt"`
+	FinishedAt time.Time    `json:"finishedAt"`
+	DurationMS int64        `json:"durationMs"`
+}
+
+// exportsLogEntry is the line appended to the exports log for a run.
+type exportsLogEntry struct {
+	Time       time.Time `json:"time"`
+	Format     string    `json:"format"`
+	Issue      int       `json:"issue"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	Report     string    `json:"report,omitempty"`
+	Files      int       `json:"files"`
+	TotalBytes int64     `json:"totalBytes"`
+	DurationMS int64     `json:"durationMs"`
+}
+
+// exportRun collects the report of one exporter call. A nil run (reports switched off) does nothing.
+type exportRun struct {
+	ph     *storage.ProjectHandle
+	path   string // report file
+	report Report
+}
+
+// beginReport starts the report of an export of issueIndex whose report goes to reportPath. pages are
+// the selected page indexes and opt the options as given by the caller. It returns nil when off is set.
+func beginReport(ph *storage.ProjectHandle, format string, issueIndex int, pages []int, opt any, reportPath string, off bool) *exportRun {
+	if off || ph == nil {
+		return nil
+	}
+	r := &exportRun{ph: ph, path: reportPath, report: Report{
+		Format: format, Issue: issueIndex + 1, Options: opt, Files: []ReportFile{},
+		AppVersion: version.String(), StartedAt: time.Now().UTC(),
+	}}
+	if issueIndex >= 0 && issueIndex < len(ph.Project.Issues) {
+		iss := ph.Project.Issues[issueIndex]
+		r.report.Pages = reportPageNumbers(iss, pageIndexes(len(iss.Pages), pages))
+	}
+	return r
+}
+
+// reportPageNumbers maps page indexes to page numbers, skipping indexes out of range.
+func reportPageNumbers(iss domain.Issue, idx []int) []int {
+	out := []int{}
+	for _, i := range idx {
+		if i >= 0 && i < len(iss.Pages) {
+			out = append(out, iss.Pages[i].Number)
+		}
+	}
+	return out
+}
+
+// setDPI records the raster resolution of the export.
+func (r *exportRun) setDPI(dpi int) {
+	if r != nil {
+		r.report.DPI = dpi
+	}
+}
+
+// addFont records how a lettering font was handled.
+func (r *exportRun) addFont(f ReportFont) {
+	if r != nil {
+		r.report.Fonts = append(r.report.Fonts, f)
+	}
+}
+
+// warn logs a problem the export worked around and keeps it in the report.
+func (r *exportRun) warn(msg string) {
+	l := applog.WithComponent("export")
+	if r == nil {
+		l.Warn(msg)
+		return
+	}
+	l.Warn(msg, slog.String("format", r.report.Format))
+	r.report.Warnings = append(r.report.Warnings, msg)
+}
+
+// finish completes the run with the exporter's result: on success it hashes the written files and
+// writes the report, on failure it removes a stale report. Either way the run is appended to the
+// exports log. It returns the exporter's error, or the error of hashing the files or writing the report.
+func (r *exportRun) finish(files []string, err error) error {
+	if r == nil {
+		return err
+	}
+	rep := &r.report
+	if err == nil {
+		err = r.hashFiles(files)
+	}
+	if err == nil {
+		rep.Status = ReportStatusOK
+		r.stamp()
+		if werr := writeReport(r.path, *rep); werr != nil {
+			err = fmt.Errorf("write export report: %w", werr)
+		}
+	}
+	if err != nil {
+		rep.Status, rep.Error = ReportStatusFailed, err.Error()
+		r.stamp()
+		if rerr := os.Remove(r.path); rerr != nil && !errors.Is(rerr, fs.ErrNotExist) {
+			err = errors.Join(err, fmt.Errorf("remove stale export report: %w", rerr))
+		}
+	}
+	// The log is a convenience; the export and its report stand without it
+	if lerr := appendExportsLog(r.ph, r.entry()); lerr != nil {
+		applog.WithComponent("export").Warn("append exports log failed", slog.String("format", rep.Format), slog.Any("err", lerr))
+	}
+	return err
+}
+
+func (r *exportRun) stamp() {
+	r.report.FinishedAt = time.Now().UTC()
+	r.report.DurationMS = r.report.FinishedAt.Sub(r.report.StartedAt).Milliseconds()
+}
+
+func (r *exportRun) entry() exportsLogEntry {
+	e := exportsLogEntry{Time: r.report.FinishedAt, Format: r.report.Format, Issue: r.report.Issue,
+		Status: r.report.Status, Error: r.report.Error, Files: len(r.report.Files),
+		TotalBytes: r.report.TotalBytes, DurationMS: r.report.DurationMS}
+	if r.report.Status == ReportStatusOK {
+		e.Report = r.path
+	}
+	return e
+}
+
+// hashFiles records size and SHA-256 of each written file.
+func (r *exportRun) hashFiles(files []string) error {
+	for _, name := range files {
+		sum, n, err := hashFile(name)
+		if err != nil {
+			return fmt.Errorf("hash export file: %w", err)
+		}
+		r.report.Files = append(r.report.Files, ReportFile{Path: name, Bytes: n, SHA256: sum})
+		r.report.TotalBytes += n
+	}
+	return nil
+}
+
+// hashFile returns the hex SHA-256 and the size of a file.
+func hashFile(name string) (string, int64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// writeReport writes the report through a temporary file, so a report is either complete or absent.
+func writeReport(path string, rep Report) error {
+	data, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// appendExportsLog appends one line to <project>/exports/exports-log.jsonl. A handle without a project
+// root has no exports folder, so nothing is logged rather than writing relative to the working directory.
+func appendExportsLog(ph *storage.ProjectHandle, e exportsLogEntry) error {
+	if ph == nil || ph.Root == "" {
+		return nil
+	}
+	dir := filepath.Join(ph.Root, "exports")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, ExportsLogFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// exportOutPath resolves an output path relative to the project's exports folder and adds ext
+// (e.g. ".cbz") when the name does not end in it already.
+func exportOutPath(ph *storage.ProjectHandle, p, ext string) string {
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(ph.Root, "exports", p)
+	}
+	if ext != "" && !strings.HasSuffix(strings.ToLower(p), ext) {
+		p += ext
+	}
+	return p
+}
+
+// pagesReportPath is the report of a page-per-file export into dir.
+func pagesReportPath(dir string, issueIndex int, format string) string {
+	return filepath.Join(dir, fmt.Sprintf("issue-%d-%s%s", issueIndex+1, format, ReportSuffix))
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/version"
+)
+
+func readReport(t *testing.T, path string) Report {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	var rep Report
+	if err := json.Unmarshal(data, &rep); err != nil {
+		t.Fatalf("parse report: %v", err)
+	}
+	return rep
+}
+
+func readExportsLog(t *testing.T, root string) []exportsLogEntry {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, "exports", ExportsLogFileName))
+	if err != nil {
+		t.Fatalf("read exports log: %v", err)
+	}
+	var out []exportsLogEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var e exportsLogEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("parse exports log line %q: %v", line, err)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func sha256File(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestExportReportCBZ(t *testing.T) {
+	root := t.TempDir()
+	ph := &storage.ProjectHandle{Root: root, Project: sampleProject()}
+	if err := ExportIssueCBZ(ph, 0, "issue-1", CBZOptions{DPI: 72}); err != nil {
+		t.Fatalf("export cbz: %v", err)
+	}
+	out := filepath.Join(root, "exports", "issue-1.cbz")
+	rep := readReport(t, out+ReportSuffix)
+	if rep.Status != ReportStatusOK || rep.Format != "cbz" || rep.Issue != 1 || rep.DPI != 72 {
+		t.Fatalf("report header = %+v", rep)
+	}
+	if len(rep.Pages) != 1 || rep.Pages[0] != 1 {
+		t.Fatalf("pages = %v, want [1]", rep.Pages)
+	}
+	if len(rep.Files) != 1 || rep.Files[0].Path != out {
+		t.Fatalf("files = %+v, want %s", rep.Files, out)
+	}
+	st, _ := os.Stat(out)
+	if rep.Files[0].SHA256 != sha256File(t, out) || rep.Files[0].Bytes != st.Size() || rep.TotalBytes != st.Size() {
+		t.Fatalf("hash/size do not match the archive: %+v (size %d)", rep.Files[0], st.Size())
+	}
+	if rep.AppVersion != version.String() || rep.StartedAt.IsZero() || rep.FinishedAt.Before(rep.StartedAt) {
+		t.Fatalf("version/timestamps = %q %v %v", rep.AppVersion, rep.StartedAt, rep.FinishedAt)
+	}
+	log := readExportsLog(t, root)
+	if len(log) != 1 || log[0].Status != ReportStatusOK || log[0].Report != out+ReportSuffix || log[0].Files != 1 {
+		t.Fatalf("exports log = %+v", log)
+	}
+}
+
+func TestExportReportPNGPagesHashesEachFile(t *testing.T) {
+	root := t.TempDir()
+	proj := sampleProject()
+	proj.Issues[0].DPI = 36
+	ph := &storage.ProjectHandle{Root: root, Project: proj}
+	if err := ExportIssuePNGPages(ph, 0, "png", PNGOptions{}); err != nil {
+		t.Fatalf("export png: %v", err)
+	}
+	dir := filepath.Join(root, "exports", "png")
+	rep := readReport(t, filepath.Join(dir, "issue-1-png.export.json"))
+	if len(rep.Files) != 1 || rep.DPI != 36 {
+		t.Fatalf("report = %+v", rep)
+	}
+	for _, f := range rep.Files {
+		if f.SHA256 != sha256File(t, f.Path) {
+			t.Fatalf("hash of %s does not match", f.Path)
+		}
+	}
+}
+
+func TestFailedExportLeavesNoSuccessReport(t *testing.T) {
+	root := t.TempDir()
+	ph := &storage.ProjectHandle{Root: root, Project: sampleProject()}
+	if err := ExportIssuePDF(ph, 0, "issue-1.pdf", PDFOptions{}); err != nil {
+		t.Fatalf("export pdf: %v", err)
+	}
+	reportPath := filepath.Join(root, "exports", "issue-1.pdf"+ReportSuffix)
+	if _, err := os.Stat(reportPath); err != nil {
+		t.Fatalf("report of the first run missing: %v", err)
+	}
+	// The second run to the same file fails; the report of the first run must not vouch for it
+	err := ExportIssuePDF(ph, 0, "issue-1.pdf", PDFOptions{OutputProfile: OutputProfilePDFX1a, ICCProfilePath: filepath.Join(root, "missing.icc")})
+	if err == nil {
+		t.Fatal("expected the export to fail")
+	}
+	if _, serr := os.Stat(reportPath); !errors.Is(serr, fs.ErrNotExist) {
+		t.Fatalf("stale report left after a failed export: %v", serr)
+	}
+	log := readExportsLog(t, root)
+	if len(log) != 2 || log[1].Status != ReportStatusFailed || log[1].Report != "" || !strings.Contains(log[1].Error, "missing.icc") {
+		t.Fatalf("exports log = %+v", log)
+	}
+}
+
+func TestExportReportOptOut(t *testing.T) {
+	root := t.TempDir()
+	ph := &storage.ProjectHandle{Root: root, Project: sampleProject()}
+	if err := ExportIssueSVGPages(ph, 0, "svg", SVGOptions{NoReport: true}); err != nil {
+		t.Fatalf("export svg: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "exports", "svg", "issue-1-svg.export.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("report written despite NoReport: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "exports", ExportsLogFileName)); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("exports log written despite NoReport: %v", err)
+	}
+}
+
+func TestExportsLogNeedsProjectRoot(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := appendExportsLog(&storage.ProjectHandle{Project: sampleProject()}, exportsLogEntry{}); err != nil {
+		t.Fatalf("append without root: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "exports")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("exports log written relative to the working directory: %v", err)
+	}
+}
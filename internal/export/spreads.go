@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// sheet is one exported output page: a single issue page, or both pages of a double-page spread
+// side by side. The right page is drawn trimW to the right of the left one, so panels crossing
+// the spine line up without any coordinate changes.
+type sheet struct {
+	left  int // page index drawn on the left, or the only page
+	right int // page index drawn on the right; -1 for a single page
+}
+
+func (s sheet) spread() bool { return s.right >= 0 }
+
+// pages returns the page indexes of the sheet with their horizontal offset in points (trim coordinates).
+func (s sheet) pages(trimW float64) ([]int, []float64) {
+	if !s.spread() {
+		return []int{s.left}, []float64{0}
+	}
+	return []int{s.left, s.right}, []float64{0, trimW}
+}
+
+// trimWidth is the width of the sheet's trim area: one page, or two for a spread.
+func (s sheet) trimWidth(trimW float64) float64 {
+	if s.spread() {
+		return 2 * trimW
+	}
+	return trimW
+}
+
+// issueSheets groups the selected page indexes into output sheets in selection order. A spread becomes
+// one double-width sheet only when both of its pages are selected; otherwise the selected page is
+// exported on its own. Out-of-range indexes are skipped.
+func issueSheets(iss domain.Issue, pidxs []int) []sheet {
+	byNumber := make(map[int]int, len(iss.Pages))
+	for i, pg := range iss.Pages {
+		byNumber[pg.Number] = i
+	}
+	selected := make(map[int]bool, len(pidxs))
+	for _, i := range pidxs {
+		selected[i] = true
+	}
+	done := make(map[int]bool, len(pidxs))
+	out := make([]sheet, 0, len(pidxs))
+	for _, i := range pidxs {
+		if i < 0 || i >= len(iss.Pages) || done[i] {
+			continue
+		}
+		done[i] = true
+		sh := sheet{left: i, right: -1}
+		if l, r, ok := storage.SpreadSides(iss, iss.Pages[i].Number); ok {
+			li, ri := byNumber[l], byNumber[r]
+			if selected[li] && selected[ri] {
+				done[li], done[ri] = true, true
+				sh = sheet{left: li, right: ri}
+			}
+		}
+		out = append(out, sh)
+	}
+	return out
+}
+
+// Page orders of CBZ images and EPUB spine items.
+const (
+	PageOrderAuto      = ""          // reading order for right-to-left issues, selection order otherwise
+	PageOrderReading   = "reading"   // ascending page numbers, so the cover comes first
+	PageOrderSelection = "selection" // the order of the Pages option, or of the manifest
+)
+
+// orderPages returns the selected page indexes in the export's page order. Reading order is ascending page
+// numbers whatever the reading direction: page 1 is read first in a manga too. Readers turn the pages of a
+// right-to-left issue the other way by ComicInfo's ReadingDirection or the EPUB page-progression-direction,
+// and the page read first in a spread already lies on its right (see issueSheets).
+func orderPages(iss domain.Issue, pidxs []int, order string) ([]int, error) {
+	switch order {
+	case PageOrderAuto:
+		if !isRTLDirection(iss.ReadingDirection) {
+			return pidxs, nil
+		}
+	case PageOrderReading:
+	case PageOrderSelection:
+		return pidxs, nil
+	default:
+		return nil, fmt.Errorf("unknown page order %q", order)
+	}
+	out := make([]int, 0, len(pidxs))
+	for _, i := range pidxs {
+		if i >= 0 && i < len(iss.Pages) {
+			out = append(out, i)
+		}
+	}
+	sort.SliceStable(out, func(a, b int) bool { return iss.Pages[out[a]].Number < iss.Pages[out[b]].Number })
+	return out, nil
+}
+
+// sheetLabel names a sheet by its page numbers in ascending order, e.g. "4" or "2-3".
+func sheetLabel(iss domain.Issue, s sheet) string {
+	a := iss.Pages[s.left].Number
+	if !s.spread() {
+		return fmt.Sprintf("%d", a)
+	}
+	b := iss.Pages[s.right].Number
+	return fmt.Sprintf("%d-%d", min(a, b), max(a, b))
+}
+
+// hasSpreadSheet reports whether any of the sheets is a double-page spread.
+func hasSpreadSheet(sheets []sheet) bool {
+	for _, s := range sheets {
+		if s.spread() {
+			return true
+		}
+	}
+	return false
+}
+
+// pdfPageLabels returns the /PageLabels catalog entry that makes viewers number the exported sheets
+// with the comic's page numbers, or "" when they would count 1, 2, 3, … anyway. Runs of consecutive
+// page numbers share one range; spread sheets are labelled with both page numbers ("2-3") and pages
+// numbered below 1 with their number as text.
+func pdfPageLabels(iss domain.Issue, sheets []sheet, titlePage bool) string {
+	var ranges []string
+	k, prev := 0, 0 // prev is the number of the previous sheet while a decimal range runs, else 0
+	if titlePage {
+		ranges = append(ranges, fmt.Sprintf("0 << /P %s >>", pdfString(titlePageLabel)))
+		k++
+	}
+	for _, s := range sheets {
+		n := iss.Pages[s.left].Number
+		switch {
+		case s.spread():
+			ranges = append(ranges, fmt.Sprintf("%d << /P %s >>", k, pdfString(sheetLabel(iss, s))))
+			prev = 0
+		case n < 1:
+			ranges = append(ranges, fmt.Sprintf("%d << /P %s >>", k, pdfString(fmt.Sprintf("%d", n))))
+			prev = 0
+		default:
+			if prev == 0 || n != prev+1 {
+				ranges = append(ranges, fmt.Sprintf("%d << /S /D /St %d >>", k, n))
+			}
+			prev = n
+		}
+		k++
+	}
+	if len(ranges) == 0 || (len(ranges) == 1 && ranges[0] == "0 << /S /D /St 1 >>") {
+		return ""
+	}
+	return "/PageLabels << /Nums [" + strings.Join(ranges, " ") + "] >>"
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// spreadProject is a three-page project whose pages 2 and 3 form a spread with a splash panel across the spine.
+func spreadProject(t *testing.T) *storage.ProjectHandle {
+	p := sampleProject()
+	iss := &p.Issues[0]
+	iss.Pages = append(iss.Pages,
+		domain.Page{Number: 2, Panels: []domain.Panel{{ID: "splash", Geometry: domain.Rect{X: 18, Y: 18, Width: 684, Height: 504}}}},
+		domain.Page{Number: 3})
+	if err := storage.SetSpread(iss, 2, 3); err != nil {
+		t.Fatalf("SetSpread: %v", err)
+	}
+	return &storage.ProjectHandle{Root: t.TempDir(), Project: p}
+}
+
+func TestIssueSheets(t *testing.T) {
+	iss := spreadProject(t).Project.Issues[0]
+	if got, want := issueSheets(iss, []int{0, 1, 2}), []sheet{{0, -1}, {1, 2}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("sheets = %v, want %v", got, want)
+	}
+	// Only one half of the spread selected: exported as a single page.
+	if got, want := issueSheets(iss, []int{2, 7}), []sheet{{2, -1}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("partial sheets = %v, want %v", got, want)
+	}
+	iss.ReadingDirection = "rtl"
+	sheets := issueSheets(iss, []int{0, 1, 2})
+	if want := (sheet{2, 1}); sheets[1] != want {
+		t.Fatalf("rtl spread = %v, want %v", sheets[1], want)
+	}
+	if l := sheetLabel(iss, sheets[1]); l != "2-3" {
+		t.Fatalf("label = %q", l)
+	}
+	want := "/PageLabels << /Nums [0 << /S /D /St 1 >> 1 << /P (2-3) >>] >>"
+	if got := pdfPageLabels(iss, sheets, false); got != want {
+		t.Fatalf("labels = %q, want %q", got, want)
+	}
+}
+
+func TestExportSpreads_RasterAndVector(t *testing.T) {
+	ph := spreadProject(t)
+	dir := filepath.Join(ph.Root, "out")
+	if err := ExportIssuePNGPages(ph, 0, dir, PNGOptions{DPI: 72}); err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	f, err := os.Open(filepath.Join(dir, "issue-1-page-2-3.png"))
+	if err != nil {
+		t.Fatalf("spread png missing: %v", err)
+	}
+	img, err := png.Decode(f)
+	_ = f.Close()
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 2*360+2*18 || b.Dy() != 540+2*18 {
+		t.Fatalf("spread size = %v", b)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "issue-1-page-3.png")); !os.IsNotExist(err) {
+		t.Fatalf("right page written separately: %v", err)
+	}
+
+	if err := ExportIssueSVGPages(ph, 0, dir, SVGOptions{IncludeGuides: true}); err != nil {
+		t.Fatalf("svg: %v", err)
+	}
+	svg, err := os.ReadFile(filepath.Join(dir, "issue-1-page-2-3.svg"))
+	if err != nil {
+		t.Fatalf("spread svg missing: %v", err)
+	}
+	if !bytes.Contains(svg, []byte(`viewBox="0 0 756 576"`)) || !bytes.Contains(svg, []byte(`translate(360 0)`)) {
+		t.Fatalf("unexpected spread svg:\n%s", svg)
+	}
+
+	out := filepath.Join(ph.Root, "spread.pdf")
+	if err := ExportIssuePDF(ph, 0, out, PDFOptions{}); err != nil {
+		t.Fatalf("pdf: %v", err)
+	}
+	doc, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read pdf: %v", err)
+	}
+	if n := len(regexp.MustCompile(`/Type /Page\b`).FindAll(doc, -1)); n != 2 {
+		t.Errorf("expected 2 PDF pages, got %d", n)
+	}
+	if !bytes.Contains(doc, []byte("/P (2-3)")) {
+		t.Errorf("spread page label missing")
+	}
+}
+
+func TestExportSpreads_CBZAndEPUB(t *testing.T) {
+	ph := spreadProject(t)
+	cbz := filepath.Join(ph.Root, "spread.cbz")
+	if err := ExportIssueCBZ(ph, 0, cbz, CBZOptions{DPI: 72}); err != nil {
+		t.Fatalf("cbz: %v", err)
+	}
+	ents := readZipEntries(t, cbz)
+	if len(ents) != 3 {
+		t.Fatalf("expected 2 images and ComicInfo.xml, got %d entries", len(ents))
+	}
+	if info := ents["ComicInfo.xml"]; !strings.Contains(info, `<Page Image="1" DoublePage="true"/>`) || !strings.Contains(info, "<PageCount>2</PageCount>") {
+		t.Fatalf("ComicInfo.xml:\n%s", info)
+	}
+
+	ph.Project.Issues[0].ReadingDirection = "rtl"
+	epub := filepath.Join(ph.Root, "spread.epub")
+	if err := ExportIssueEPUB(ph, 0, epub, EPUBOptions{FixedLayout: true, DPI: 72}); err != nil {
+		t.Fatalf("epub: %v", err)
+	}
+	opf := readZipEntries(t, epub)["OEBPS/content.opf"]
+	for _, want := range []string{
+		`<itemref idref="page-1"/>`,
+		`<itemref idref="page-2" properties="page-spread-right"/>`,
+		`<itemref idref="page-3" properties="page-spread-left"/>`,
+	} {
+		if !strings.Contains(opf, want) {
+			t.Errorf("content.opf lacks %s:\n%s", want, opf)
+		}
+	}
+}
+
+// zipImageOrder lists the PNG entries of a zip in archive order with their pixel widths.
+func zipImageOrder(t *testing.T, path string) (names []string, widths []int) {
+	t.Helper()
+	rd, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("open zip: %v", err)
+	}
+	defer func() { _ = rd.Close() }()
+	for _, f := range rd.File {
+		if !strings.HasSuffix(f.Name, ".png") {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		cfg, err := png.DecodeConfig(r)
+		_ = r.Close()
+		if err != nil {
+			t.Fatalf("decode %s: %v", f.Name, err)
+		}
+		names = append(names, f.Name)
+		widths = append(widths, cfg.Width)
+	}
+	return names, widths
+}
+
+func TestExportSpreads_RTLReadingOrder(t *testing.T) {
+	ph := spreadProject(t)
+	iss := &ph.Project.Issues[0]
+	iss.ReadingDirection = "rtl"
+	// Pages selected back to front: reading order puts the cover first and the spread after it
+	sel := []int{2, 1, 0}
+	if got, err := orderPages(*iss, sel, PageOrderAuto); err != nil || !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Fatalf("orderPages = %v, %v", got, err)
+	}
+	if got, _ := orderPages(*iss, sel, PageOrderSelection); !reflect.DeepEqual(got, sel) {
+		t.Fatalf("selection order = %v", got)
+	}
+	if _, err := orderPages(*iss, sel, "backwards"); err == nil {
+		t.Fatal("expected an error for an unknown page order")
+	}
+
+	cbz := filepath.Join(ph.Root, "rtl.cbz")
+	if err := ExportIssueCBZ(ph, 0, cbz, CBZOptions{DPI: 72, Pages: sel}); err != nil {
+		t.Fatalf("cbz: %v", err)
+	}
+	names, widths := zipImageOrder(t, cbz)
+	if !reflect.DeepEqual(names, []string{"1.png", "2.png"}) || widths[1] <= widths[0] {
+		t.Fatalf("cbz images = %v %v, want the cover then the double-wide spread", names, widths)
+	}
+	if err := ExportIssueCBZ(ph, 0, cbz, CBZOptions{DPI: 72, Pages: sel, PageOrder: PageOrderSelection}); err != nil {
+		t.Fatalf("cbz: %v", err)
+	}
+	if _, widths := zipImageOrder(t, cbz); widths[0] <= widths[1] {
+		t.Fatalf("selection order widths = %v, want the spread first", widths)
+	}
+
+	// Spine: cover, then the page read first on the right of the spread
+	epub := filepath.Join(ph.Root, "rtl.epub")
+	if err := ExportIssueEPUB(ph, 0, epub, EPUBOptions{FixedLayout: true, DPI: 72, Pages: sel}); err != nil {
+		t.Fatalf("epub: %v", err)
+	}
+	opf := readZipEntries(t, epub)["OEBPS/content.opf"]
+	spine := regexp.MustCompile(`<itemref idref="page-\d+"( properties="[a-z-]+")?/>`).FindAllStringSubmatch(opf, -1)
+	var props []string
+	for _, m := range spine {
+		props = append(props, m[1])
+	}
+	if want := []string{"", ` properties="page-spread-right"`, ` properties="page-spread-left"`}; !reflect.DeepEqual(props, want) {
+		t.Fatalf("spine properties = %q, want %q\n%s", props, want, opf)
+	}
+	if !strings.Contains(opf, `<spine page-progression-direction="rtl">`) || !strings.Contains(opf, `id="img-1" href="images/page-1.png" media-type="image/png" properties="cover-image"`) {
+		t.Errorf("content.opf:\n%s", opf)
+	}
+}
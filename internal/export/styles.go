@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import "gocomicwriter/internal/domain"
+
+// exportStyle holds the resolved default colors of one export.
+type exportStyle struct {
+	guide         domain.Color
+	panel         domain.Stroke
+	balloonStroke domain.Stroke
+	balloonFill   domain.Color
+	captionFill   domain.Color
+}
+
+// resolveExportStyle picks each color from the export options when set, else from the project's
+// default styles, else the built-in red guides, black strokes and white fills. Captions are filled
+// like balloons unless the project sets a caption fill. Per-panel borders are resolved later by
+// render.ResolvePanelFrame on top of panel.
+func resolveExportStyle(p domain.Project, guide domain.Color, panel, balloonStroke domain.Stroke, balloonFill domain.Color) exportStyle {
+	var ds domain.DefaultStyles
+	if p.DefaultStyles != nil {
+		ds = *p.DefaultStyles
+	}
+	black := domain.Color{R: 0, G: 0, B: 0, A: 255}
+	st := exportStyle{
+		guide:         pickColor(guide, ds.Guide, domain.Color{R: 255, G: 0, B: 0, A: 255}),
+		panel:         panel,
+		balloonStroke: balloonStroke,
+		balloonFill:   pickColor(balloonFill, ds.BalloonFill, domain.Color{R: 255, G: 255, B: 255, A: 255}),
+	}
+	if st.panel.Width == 0 {
+		st.panel = domain.Stroke{Color: black, Width: 1}
+	}
+	if st.balloonStroke.Width == 0 {
+		st.balloonStroke = domain.Stroke{Color: pickColor(domain.Color{}, ds.BalloonStroke, black), Width: 1}
+	}
+	st.captionFill = pickColor(domain.Color{}, ds.CaptionFill, st.balloonFill)
+	return st
+}
+
+// pickColor returns opt unless it is the zero color, then the project color when set, then def.
+func pickColor(opt domain.Color, project *domain.Color, def domain.Color) domain.Color {
+	if opt != (domain.Color{}) {
+		return opt
+	}
+	if project != nil {
+		return *project
+	}
+	return def
+}
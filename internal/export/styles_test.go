@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func TestResolveExportStyle_Precedence(t *testing.T) {
+	black := domain.Color{A: 255}
+	white := domain.Color{R: 255, G: 255, B: 255, A: 255}
+	st := resolveExportStyle(domain.Project{}, domain.Color{}, domain.Stroke{}, domain.Stroke{}, domain.Color{})
+	if st.guide != (domain.Color{R: 255, A: 255}) || st.panel != (domain.Stroke{Color: black, Width: 1}) ||
+		st.balloonStroke != (domain.Stroke{Color: black, Width: 1}) || st.balloonFill != white || st.captionFill != white {
+		t.Fatalf("built-in defaults changed: %+v", st)
+	}
+
+	cream := domain.Color{R: 250, G: 240, B: 220, A: 255}
+	brown := domain.Color{R: 60, G: 40, B: 20, A: 255}
+	blue := domain.Color{B: 255, A: 255}
+	proj := domain.Project{DefaultStyles: &domain.DefaultStyles{BalloonFill: &cream, BalloonStroke: &brown, Guide: &blue}}
+	st = resolveExportStyle(proj, domain.Color{}, domain.Stroke{}, domain.Stroke{}, domain.Color{})
+	if st.balloonFill != cream || st.balloonStroke.Color != brown || st.guide != blue || st.captionFill != cream {
+		t.Fatalf("project defaults not used: %+v", st)
+	}
+	// Explicit options win over the project
+	st = resolveExportStyle(proj, black, domain.Stroke{}, domain.Stroke{Color: white, Width: 2}, white)
+	if st.guide != black || st.balloonStroke.Color != white || st.balloonFill != white {
+		t.Fatalf("options did not win: %+v", st)
+	}
+}
+
+func TestExportSVG_UsesProjectDefaultStyles(t *testing.T) {
+	root := t.TempDir()
+	proj := sampleProject()
+	proj.Issues[0].Pages[0].Panels[0].Captions = []domain.Caption{{ID: "c1", Text: "Meanwhile", Rect: domain.Rect{X: 40, Y: 200, Width: 100, Height: 30}}}
+	fill := domain.Color{R: 0xfb, G: 0xf3, B: 0xe4, A: 255}
+	capFill := domain.Color{R: 0xf1, G: 0xde, B: 0xb8, A: 255}
+	proj.DefaultStyles = &domain.DefaultStyles{Palette: "Sepia", BalloonFill: &fill, CaptionFill: &capFill}
+	ph := &storage.ProjectHandle{Root: root, Project: proj}
+	if err := ExportIssueSVGPages(ph, 0, "svg", SVGOptions{}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "exports", "svg", "issue-1-page-1.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(data)
+	if !strings.Contains(s, `fill="#fbf3e4" stroke="#000000"`) || !strings.Contains(s, `fill="#f1deb8" stroke="#000000"`) {
+		t.Fatalf("palette colors missing:\n%s", s)
+	}
+	if strings.Contains(s, `fill="#ffffff" stroke="#000000"`) {
+		t.Fatalf("built-in fill still used:\n%s", s)
+	}
+}
@@ -14,8 +14,11 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/render"
 	"gocomicwriter/internal/storage"
 )
 
@@ -32,70 +35,55 @@ type SVGOptions struct {
 	BalloonStroke domain.Stroke
 	BalloonFill   domain.Color
 	Pages         []int
+	// NoReport skips the issue-<n>-svg.export.json report and the exports log entry.
+	NoReport bool
 }
 
 // ExportIssueSVGPages exports each page of an issue as a separate SVG file.
 // Files will be named issue-<issue+1>-page-<pageNumber>.svg under outDir or project's exports.
-func ExportIssueSVGPages(ph *storage.ProjectHandle, issueIndex int, outDir string, opt SVGOptions) error {
+func ExportIssueSVGPages(ph *storage.ProjectHandle, issueIndex int, outDir string, opt SVGOptions) (err error) {
 	if ph == nil {
 		return fmt.Errorf("project handle is nil")
 	}
+	// Resolve output directory
+	outDir = exportOutPath(ph, outDir, "")
+	var written []string
+	run := beginReport(ph, "svg", issueIndex, opt.Pages, opt, pagesReportPath(outDir, issueIndex, "svg"), opt.NoReport)
+	defer func() { err = run.finish(written, err) }()
 	if issueIndex < 0 || issueIndex >= len(ph.Project.Issues) {
 		return fmt.Errorf("issue index out of range")
 	}
+	if err := validateIssue(ph, issueIndex); err != nil {
+		return err
+	}
 	iss := ph.Project.Issues[issueIndex]
 
 	// Defaults
-	guideCol := opt.GuideColor
-	if guideCol.A == 0 && guideCol.R == 0 && guideCol.G == 0 && guideCol.B == 0 {
-		guideCol = domain.Color{R: 255, G: 0, B: 0, A: 255}
-	}
-	panelStroke := opt.PanelStroke
-	if panelStroke.Width == 0 {
-		panelStroke = domain.Stroke{Color: domain.Color{R: 0, G: 0, B: 0, A: 255}, Width: 1}
-	}
-	balloonStroke := opt.BalloonStroke
-	if balloonStroke.Width == 0 {
-		balloonStroke = domain.Stroke{Color: domain.Color{R: 0, G: 0, B: 0, A: 255}, Width: 1}
-	}
-	balloonFill := opt.BalloonFill
-	if balloonFill.A == 0 && balloonFill.R == 0 && balloonFill.G == 0 && balloonFill.B == 0 {
-		balloonFill = domain.Color{R: 255, G: 255, B: 255, A: 255}
-	}
+	sty := resolveExportStyle(ph.Project, opt.GuideColor, opt.PanelStroke, opt.BalloonStroke, opt.BalloonFill)
+	guideCol, panelStroke, balloonStroke, balloonFill := sty.guide, sty.panel, sty.balloonStroke, sty.balloonFill
 	// DPI
-	dpi := iss.DPI
-	if opt.DPI > 0 {
-		dpi = opt.DPI
-	}
-	if dpi <= 0 {
-		dpi = 300
-	}
+	dpi := exportDPI(iss, opt.DPI)
+	run.setDPI(dpi)
 
 	trimW := iss.TrimWidth
 	trimH := iss.TrimHeight
 	bleed := iss.Bleed
-	mediaW := trimW + 2*bleed
 	mediaH := trimH + 2*bleed
 
 	// Derived pixel size for width/height attributes
-	scale := float64(dpi) / 72.0
-	pxW := int(math.Round(mediaW * scale))
+	scale := pixelsPerPoint(dpi)
 	pxH := int(math.Round(mediaH * scale))
 
-	// Resolve output directory
-	if !filepath.IsAbs(outDir) {
-		outDir = filepath.Join(ph.Root, "exports", outDir)
-	}
 	if err := os.MkdirAll(outDir, 0o755); err != nil {
 		return fmt.Errorf("ensure out dir: %w", err)
 	}
 
-	pages := pageIndexes(len(iss.Pages), opt.Pages)
-	for _, pidx := range pages {
-		if pidx < 0 || pidx >= len(iss.Pages) {
-			continue
-		}
-		pg := iss.Pages[pidx]
+	// A spread is written as one double-width drawing; the right page is translated by one trim width
+	art := newPageArt(ph, run)
+	for _, sh := range issueSheets(iss, pageIndexes(len(iss.Pages), opt.Pages)) {
+		sheetTrim, sheetMedia := sheetBoxes(iss, sh)
+		sheetTrimW, mediaW := sheetTrim.Width, sheetMedia.Width
+		pxW := int(math.Round(mediaW * scale))
 
 		var buf bytes.Buffer
 		var werr error
@@ -108,57 +96,108 @@ func ExportIssueSVGPages(ph *storage.ProjectHandle, issueIndex int, outDir strin
 
 		wf("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
 		wf("<svg xmlns=\"http://www.w3.org/2000/svg\" version=\"1.1\" width=\"%dpx\" height=\"%dpx\" viewBox=\"0 0 %g %g\">\n", pxW, pxH, mediaW, mediaH)
-		// Background white
+		// Background white, then the page art
 		wf("  <rect x=\"0\" y=\"0\" width=\"%g\" height=\"%g\" fill=\"#ffffff\"/>\n", mediaW, mediaH)
+		wf("%s", art.svgSheet(iss, sh, bleed, bleed))
 
 		if opt.IncludeGuides {
 			gc := svgColor(guideCol)
 			wf("  <rect x=\"0\" y=\"0\" width=\"%g\" height=\"%g\" fill=\"none\" stroke=\"%s\" stroke-width=\"0.2\"/>\n", mediaW, mediaH, gc)
-			wf("  <rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" fill=\"none\" stroke=\"%s\" stroke-width=\"0.2\"/>\n", bleed, bleed, trimW, trimH, gc)
+			wf("  <rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" fill=\"none\" stroke=\"%s\" stroke-width=\"0.2\"/>\n", bleed, bleed, sheetTrimW, trimH, gc)
+			if sh.spread() {
+				wf("  <line x1=\"%g\" y1=\"%g\" x2=\"%g\" y2=\"%g\" stroke=\"%s\" stroke-width=\"0.2\"/>\n", bleed+trimW, bleed, bleed+trimW, bleed+trimH, gc)
+			}
 		}
 
-		pc := svgColor(panelStroke.Color)
 		bc := svgColor(balloonStroke.Color)
-		bf := svgColor(balloonFill)
-
-		for _, pnl := range pg.Panels {
-			r := pnl.Geometry
-			wf("  <rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" fill=\"none\" stroke=\"%s\" stroke-width=\"%g\"/>\n", r.X+bleed, r.Y+bleed, r.Width, r.Height, pc, panelStroke.Width)
-			for _, b := range pnl.Balloons {
-				br := b.Shape.Rect
-				x := br.X + bleed
-				y := br.Y + bleed
-				switch b.Shape.Kind {
-				case "ellipse":
-					cx := x + br.Width/2
-					cy := y + br.Height/2
-					rx := br.Width / 2
-					ry := br.Height / 2
-					wf("  <ellipse cx=\"%g\" cy=\"%g\" rx=\"%g\" ry=\"%g\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%g\"/>\n", cx, cy, rx, ry, bf, bc, balloonStroke.Width)
-				case "roundedBox":
-					radius := b.Shape.Radius
-					wf("  <rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" rx=\"%g\" ry=\"%g\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%g\"/>\n", x, y, br.Width, br.Height, radius, radius, bf, bc, balloonStroke.Width)
-				default:
-					wf("  <rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%g\"/>\n", x, y, br.Width, br.Height, bf, bc, balloonStroke.Width)
+		cf := svgColor(sty.captionFill)
+
+		pidxs, offsets := sh.pages(trimW)
+		for k, pidx := range pidxs {
+			pg := iss.Pages[pidx]
+			if offsets[k] != 0 {
+				wf("  <g transform=\"translate(%g 0)\">\n", offsets[k])
+			}
+			for _, pnl := range pg.Panels {
+				r := pnl.Geometry
+				if f, ok := render.ResolvePanelFrame(ph.Project.PanelBorder, pnl, panelStroke); ok {
+					wf("  <rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" fill=\"none\" stroke=\"%s\" stroke-width=\"%g\"/>\n", r.X+bleed, r.Y+bleed, r.Width, r.Height, svgColor(f.Color), f.Width)
+				}
+				// Balloons in reading order, so the text reads in sequence
+				for _, b := range storage.BalloonsInOrder(pnl.Balloons) {
+					br := b.Shape.Rect
+					x := br.X + bleed
+					y := br.Y + bleed
+					lk := render.ResolveBalloonLook(ph.Project.BalloonStyles, b, balloonStroke, balloonFill)
+					paint := fmt.Sprintf("fill=\"%s\"%s", svgColor(lk.Fill), svgBalloonStroke(lk))
+					switch b.Shape.Kind {
+					case "ellipse":
+						cx := x + br.Width/2
+						cy := y + br.Height/2
+						rx := br.Width / 2
+						ry := br.Height / 2
+						wf("  <ellipse cx=\"%g\" cy=\"%g\" rx=\"%g\" ry=\"%g\" %s/>\n", cx, cy, rx, ry, paint)
+					case "roundedBox":
+						radius := b.Shape.Radius
+						wf("  <rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" rx=\"%g\" ry=\"%g\" %s/>\n", x, y, br.Width, br.Height, radius, radius, paint)
+					case "burst", "cloud":
+						wf("  <polygon points=\"%s\" %s/>\n", svgPoints(render.BalloonPolygon(b.Shape.Kind, x, y, br.Width, br.Height)), paint)
+					default:
+						wf("  <rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" %s/>\n", x, y, br.Width, br.Height, paint)
+					}
+					// Text runs: simple top-left stacking
+					pad := lk.Padding
+					cx := x + pad
+					cy := y + pad + 12
+					for _, run := range b.TextRuns {
+						fsz := run.Size
+						if fsz <= 0 {
+							fsz = 12
+						}
+						// We don't embed fonts here; the font family is a hint only.
+						font := run.Font
+						if font == "" {
+							font = "Helvetica, Arial, sans-serif"
+						}
+						for _, line := range strings.Split(run.Content, "\n") {
+							wf("  <text x=\"%g\" y=\"%g\" font-family=\"%s\" font-size=\"%g\" fill=\"#000\">%s</text>\n", cx, cy, escAttr(font), fsz, escText(line))
+							cy += fsz * 1.2
+						}
+					}
 				}
-				// Text runs: simple top-left stacking
-				pad := 6.0
-				cx := x + pad
-				cy := y + pad + 12
-				for _, run := range b.TextRuns {
-					fsz := run.Size
+				// Captions: filled boxes with stacked text lines
+				for _, c := range pnl.Captions {
+					cr := c.Rect
+					x := cr.X + bleed
+					y := cr.Y + bleed
+					fsz := c.Size
 					if fsz <= 0 {
-						fsz = 12
+						fsz = 11
 					}
-					// We don't embed fonts here; the font family is a hint only.
-					font := run.Font
-					if font == "" {
-						font = "Helvetica, Arial, sans-serif"
+					wf("  <g%s>\n", svgRotate(c.Rotation, x+cr.Width/2, y+cr.Height/2))
+					wf("    <rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%g\"/>\n", x, y, cr.Width, cr.Height, cf, bc, balloonStroke.Width)
+					cy := y + 4 + fsz
+					for _, line := range strings.Split(c.Text, "\n") {
+						wf("    <text x=\"%g\" y=\"%g\" font-family=\"%s\" font-size=\"%g\" fill=\"#000\">%s</text>\n", x+4, cy, escAttr(fontOrDefault(c.Font)), fsz, escText(line))
+						cy += fsz * 1.2
 					}
-					wf("  <text x=\"%g\" y=\"%g\" font-family=\"%s\" font-size=\"%g\" fill=\"#000\">%s</text>\n", cx, cy, escAttr(font), fsz, escText(run.Content))
-					cy += fsz * 1.2
+					wf("  </g>\n")
+				}
+				// SFX: plain text anchored at the item's rect baseline
+				for _, fx := range pnl.SFX {
+					fr := fx.Rect
+					x := fr.X + bleed
+					y := fr.Y + bleed
+					fsz := fx.Size
+					if fsz <= 0 {
+						fsz = 36
+					}
+					wf("  <text x=\"%g\" y=\"%g\" font-family=\"%s\" font-size=\"%g\" font-weight=\"bold\" fill=\"#000\"%s>%s</text>\n", x, y+fsz, escAttr(fontOrDefault(fx.Font)), fsz, svgRotate(fx.Rotation, x+fr.Width/2, y+fr.Height/2), escText(fx.Text))
 				}
 			}
+			if offsets[k] != 0 {
+				wf("  </g>\n")
+			}
 		}
 
 		wf("</svg>\n")
@@ -167,18 +206,56 @@ func ExportIssueSVGPages(ph *storage.ProjectHandle, issueIndex int, outDir strin
 			return fmt.Errorf("build svg: %w", werr)
 		}
 
-		name := filepath.Join(outDir, fmt.Sprintf("issue-%d-page-%d.svg", issueIndex+1, pg.Number))
+		name := filepath.Join(outDir, fmt.Sprintf("issue-%d-page-%s.svg", issueIndex+1, sheetLabel(iss, sh)))
 		if err := os.WriteFile(name, buf.Bytes(), 0o644); err != nil {
 			return fmt.Errorf("write svg: %w", err)
 		}
+		written = append(written, name)
 	}
 	return nil
 }
 
+// svgRotate returns a transform attribute rotating by deg degrees around (cx, cy), or "" for no rotation.
+func svgRotate(deg, cx, cy float64) string {
+	if deg == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" transform=\"rotate(%g %g %g)\"", deg, cx, cy)
+}
+
+func fontOrDefault(font string) string {
+	if font == "" {
+		return "Helvetica, Arial, sans-serif"
+	}
+	return font
+}
+
 func svgColor(c domain.Color) string {
 	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
 }
 
+// svgBalloonStroke returns the stroke attributes of a balloon outline, with a leading space.
+func svgBalloonStroke(lk render.BalloonLook) string {
+	if lk.Hidden {
+		return ` stroke="none"`
+	}
+	s := fmt.Sprintf(` stroke="%s" stroke-width="%g"`, svgColor(lk.Color), lk.Width)
+	if lk.Dashed {
+		on, off := lk.Dash()
+		s += fmt.Sprintf(` stroke-dasharray="%g %g"`, on, off)
+	}
+	return s
+}
+
+// svgPoints formats a polygon for the points attribute.
+func svgPoints(pts [][2]float64) string {
+	parts := make([]string, len(pts))
+	for i, p := range pts {
+		parts[i] = strconv.FormatFloat(p[0], 'f', 2, 64) + "," + strconv.FormatFloat(p[1], 'f', 2, 64)
+	}
+	return strings.Join(parts, " ")
+}
+
 func escAttr(s string) string {
 	// naive escaping sufficient for our simple usage
 	out := make([]byte, 0, len(s))
@@ -0,0 +1,20 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"gocomicwriter/internal/storage"
+)
+
+// validateIssue refuses to export an issue that storage.ValidateProject reports errors for, such as a
+// missing trim size or duplicate page numbers; warnings do not stop an export. The returned error is a
+// storage.ValidationErrors listing the paths into comic.json.
+func validateIssue(ph *storage.ProjectHandle, issueIndex int) error {
+	return storage.ValidationErrorsIn(storage.ValidateProject(ph.Project), issueIndex)
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gocomicwriter/internal/storage"
+)
+
+func TestExportRefusesInvalidIssue(t *testing.T) {
+	root := t.TempDir()
+	proj := sampleProject()
+	proj.Issues[0].Pages = append(proj.Issues[0].Pages, proj.Issues[0].Pages[0]) // page 1 twice
+	proj.Issues[0].DPI = 0                                                       // only a warning
+	ph := &storage.ProjectHandle{Root: root, Project: proj}
+
+	out := filepath.Join(root, "out.pdf")
+	err := ExportIssuePDF(ph, 0, out, PDFOptions{})
+	var verrs storage.ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Path != "issues[0].pages[1].number" {
+		t.Fatalf("expected a validation error for the duplicate page, got %v", err)
+	}
+	if _, statErr := os.Stat(out); statErr == nil {
+		t.Fatalf("output written despite validation error")
+	}
+
+	ph.Project.Issues[0].Pages = ph.Project.Issues[0].Pages[:1]
+	if err := ExportIssuePDF(ph, 0, out, PDFOptions{}); err != nil {
+		t.Fatalf("warnings must not block an export: %v", err)
+	}
+}
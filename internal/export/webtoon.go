@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/render"
+	"gocomicwriter/internal/storage"
+)
+
+// WebtoonMaxHeight is the default height limit of one strip image. Many decoders and webtoon platforms
+// reject PNGs taller than 65535 pixels; longer strips are split into numbered parts.
+const WebtoonMaxHeight = 65500
+
+// WebtoonOptions controls the long-strip export.
+// - Width: strip width in pixels (default 800); every page is scaled proportionally to it
+// - Gap: pixels of GapColor between pages (default white)
+// - Pages: page indexes to include; all when empty
+// - MaxHeight: height limit of one output image (default WebtoonMaxHeight)
+// - NoReport: skip the <output>.export.json report and the exports log entry
+type WebtoonOptions struct {
+	Width     int
+	Gap       int
+	GapColor  domain.Color
+	Pages     []int
+	MaxHeight int
+	NoReport  bool
+}
+
+// ExportIssueWebtoonStrip stacks the pages of an issue vertically into a single tall PNG, the format
+// webtoon platforms expect. Pages are cut at the trim (no bleed or guides) and a double-page spread
+// stays one image, scaled to the same width. The strip is encoded while it is rendered, one page at a
+// time, so memory use does not grow with the number of pages. When the strip is taller than
+// MaxHeight it is split at page boundaries into outPath-part-01.png, -part-02.png, ...; a single page
+// taller than the limit is cut. It returns the files written.
+func ExportIssueWebtoonStrip(ph *storage.ProjectHandle, issueIndex int, outPath string, opt WebtoonOptions) (written []string, err error) {
+	if ph == nil {
+		return nil, fmt.Errorf("project handle is nil")
+	}
+	outPath = exportOutPath(ph, outPath, ".png")
+	run := beginReport(ph, "webtoon", issueIndex, opt.Pages, opt, outPath+ReportSuffix, opt.NoReport)
+	defer func() { err = run.finish(written, err) }()
+	if issueIndex < 0 || issueIndex >= len(ph.Project.Issues) {
+		return nil, fmt.Errorf("issue index out of range")
+	}
+	if err := validateIssue(ph, issueIndex); err != nil {
+		return nil, err
+	}
+	iss := ph.Project.Issues[issueIndex]
+	if iss.TrimWidth <= 0 || iss.TrimHeight <= 0 {
+		return nil, fmt.Errorf("issue has no trim size")
+	}
+	width := opt.Width
+	if width <= 0 {
+		width = 800
+	}
+	maxH := opt.MaxHeight
+	if maxH <= 0 {
+		maxH = WebtoonMaxHeight
+	}
+	gapCol := opt.GapColor
+	if gapCol.A == 0 && gapCol.R == 0 && gapCol.G == 0 && gapCol.B == 0 {
+		gapCol = domain.Color{R: 255, G: 255, B: 255, A: 255}
+	}
+
+	sheets := issueSheets(iss, pageIndexes(len(iss.Pages), opt.Pages))
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("no pages to export")
+	}
+	strip := make([]stripSheet, len(sheets))
+	y := 0
+	for i, sh := range sheets {
+		scale := float64(width) / sh.trimWidth(iss.TrimWidth)
+		h := max(1, int(math.Round(iss.TrimHeight*scale)))
+		if err := CheckPixels("strip page "+sheetLabel(iss, sh), width, h, 0); err != nil {
+			return nil, err
+		}
+		strip[i] = stripSheet{sheet: sh, top: y, height: h, scale: scale}
+		y += h + max(0, opt.Gap)
+	}
+	parts := splitStrip(strip, maxH)
+
+	if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+		return nil, fmt.Errorf("ensure out dir: %w", err)
+	}
+	st := PreviewStyle(ph.Project)
+	art := newPageArt(ph, run)
+
+	for k, p := range parts {
+		name := outPath
+		if len(parts) > 1 {
+			name = fmt.Sprintf("%s-part-%02d.png", strings.TrimSuffix(outPath, filepath.Ext(outPath)), k+1)
+		}
+		img := &stripImage{iss: iss, sheets: strip, st: st, art: art, w: width, y0: p[0], y1: p[1], bg: render.RGBA(gapCol), cur: -1}
+		if err := writePNG(name, img); err != nil {
+			return written, err
+		}
+		written = append(written, name)
+	}
+	return written, nil
+}
+
+// stripSheet is one sheet placed in the strip: its first row and height in strip pixels.
+type stripSheet struct {
+	sheet
+	top, height int
+	scale       float64
+}
+
+// splitStrip divides the strip into parts of at most maxH rows, returned as [first, end) rows. Parts end
+// at the bottom of a sheet; the gap after it is dropped. Only a sheet taller than maxH is cut.
+func splitStrip(strip []stripSheet, maxH int) [][2]int {
+	var parts [][2]int
+	start, i := strip[0].top, 0
+	for i < len(strip) {
+		end, first := start, i
+		for i < len(strip) && strip[i].top+strip[i].height-start <= maxH {
+			end = strip[i].top + strip[i].height
+			i++
+		}
+		if i == first { // the next sheet alone is too tall for one part
+			end = start + maxH
+		}
+		parts = append(parts, [2]int{start, end})
+		if i < len(strip) {
+			start = max(end, strip[i].top)
+		}
+	}
+	return parts
+}
+
+// stripImage is rows y0..y1 of the strip as an image.Image. Sheets are rasterized on demand when the
+// encoder reaches them and dropped when it moves on, so only one sheet is held at a time.
+type stripImage struct {
+	iss    domain.Issue
+	sheets []stripSheet
+	st     render.Style
+	art    *pageArt
+	w      int
+	y0, y1 int
+	bg     color.RGBA
+	cur    int // index of the rendered sheet in img, -1 for none
+	img    *image.RGBA
+}
+
+func (s *stripImage) ColorModel() color.Model { return color.RGBAModel }
+func (s *stripImage) Bounds() image.Rectangle { return image.Rect(0, 0, s.w, s.y1-s.y0) }
+
+// Opaque tells the PNG encoder not to scan the whole image for transparency first.
+func (s *stripImage) Opaque() bool { return true }
+
+func (s *stripImage) At(x, y int) color.Color {
+	sy := s.y0 + y
+	// Rows arrive in order, so the sheet is almost always the current one or the next
+	i := max(s.cur, 0)
+	for i > 0 && sy < s.sheets[i].top {
+		i--
+	}
+	for i < len(s.sheets) && sy >= s.sheets[i].top+s.sheets[i].height {
+		i++
+	}
+	if i >= len(s.sheets) || sy < s.sheets[i].top {
+		return s.bg
+	}
+	if i != s.cur {
+		s.img = renderStripSheet(s.iss, s.sheets[i], s.w, s.st, s.art)
+		s.cur = i
+	}
+	return s.img.RGBAAt(x, sy-s.sheets[i].top)
+}
+
+// renderStripSheet rasterizes a sheet's trim area with its page art, without bleed or guides, w pixels wide.
+func renderStripSheet(iss domain.Issue, ss stripSheet, w int, st render.Style, art *pageArt) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, ss.height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{255, 255, 255, 255}}, image.Point{}, draw.Src)
+	art.drawSheet(img, iss, ss.sheet, 0, 0, ss.scale)
+	pidxs, offsets := ss.pages(iss.TrimWidth)
+	for k, pidx := range pidxs {
+		render.DrawPage(img, iss.Pages[pidx], offsets[k], 0, ss.scale, st)
+	}
+	return img
+}
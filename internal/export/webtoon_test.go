@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package export
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func webtoonProject(t *testing.T, pages int) *storage.ProjectHandle {
+	proj := sampleProject()
+	iss := &proj.Issues[0]
+	for n := 2; n <= pages; n++ {
+		pg := iss.Pages[0]
+		pg.Number = n
+		iss.Pages = append(iss.Pages, pg)
+	}
+	return &storage.ProjectHandle{Root: t.TempDir(), Project: proj}
+}
+
+func decodePNG(t *testing.T, path string) image.Image {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("decode %s: %v", path, err)
+	}
+	return img
+}
+
+func TestExportIssueWebtoonStrip(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "strip")
+	red := domain.Color{R: 255, A: 255}
+	// 360x540pt pages at 100px are 150px tall; two 10px gaps
+	files, err := ExportIssueWebtoonStrip(webtoonProject(t, 3), 0, out, WebtoonOptions{Width: 100, Gap: 10, GapColor: red})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if !reflect.DeepEqual(files, []string{out + ".png"}) {
+		t.Fatalf("files = %v", files)
+	}
+	img := decodePNG(t, files[0])
+	if b := img.Bounds(); b.Dx() != 100 || b.Dy() != 470 {
+		t.Fatalf("size = %v", b)
+	}
+	if c := color.RGBAModel.Convert(img.At(50, 155)).(color.RGBA); c != (color.RGBA{255, 0, 0, 255}) {
+		t.Fatalf("gap pixel = %v", c)
+	}
+	// Panel border of the second page: trim-relative x=18pt → 5px, no bleed offset
+	if c := color.RGBAModel.Convert(img.At(5, 160+50)).(color.RGBA); c != (color.RGBA{0, 0, 0, 255}) {
+		t.Fatalf("panel border pixel = %v", c)
+	}
+}
+
+func TestExportIssueWebtoonStrip_SplitsTallStrips(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "episode.png")
+	files, err := ExportIssueWebtoonStrip(webtoonProject(t, 3), 0, out, WebtoonOptions{Width: 100, Gap: 10, MaxHeight: 320})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	dir := filepath.Dir(out)
+	want := []string{filepath.Join(dir, "episode-part-01.png"), filepath.Join(dir, "episode-part-02.png")}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("files = %v", files)
+	}
+	// Parts end at page boundaries and drop the gap at the cut
+	for i, h := range []int{310, 150} {
+		if b := decodePNG(t, files[i]).Bounds(); b.Dy() != h {
+			t.Fatalf("part %d height = %d, want %d", i+1, b.Dy(), h)
+		}
+	}
+}
+
+func TestSplitStrip(t *testing.T) {
+	strip := []stripSheet{{top: 0, height: 100}, {top: 110, height: 500}, {top: 620, height: 50}}
+	got := splitStrip(strip, 200)
+	// The 500px sheet is cut; the remainder shares a part with the next sheet
+	want := [][2]int{{0, 100}, {110, 310}, {310, 510}, {510, 670}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parts = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+// Package i18n holds the message catalogs of the user interface and looks up strings in the active
+// locale. Catalogs are embedded JSON files, one per locale (locales/<lang>.json), mapping a key to a
+// format string or, for counted messages, to an object with the plural forms "one" and "other".
+// Keys missing from the active locale fall back to English; each missing key is logged once.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jeandeaual/go-locale"
+
+	applog "gocomicwriter/internal/log"
+)
+
+// DefaultLocale is the locale every key exists in and the fallback for missing keys.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+// message is a catalog entry: a single format string, or plural forms selected by a count.
+type message struct {
+	Text  string
+	One   string
+	Other string
+}
+
+func (m message) plural() bool { return m.Text == "" && m.Other != "" }
+
+func (m *message) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		return json.Unmarshal(b, &m.Text)
+	}
+	var forms struct {
+		One   string `json:"one"`
+		Other string `json:"other"`
+	}
+	if err := json.Unmarshal(b, &forms); err != nil {
+		return err
+	}
+	if forms.Other == "" {
+		return fmt.Errorf("plural message without an \"other\" form")
+	}
+	m.One, m.Other = forms.One, forms.Other
+	return nil
+}
+
+var (
+	catalogs = mustLoadCatalogs()
+
+	mu      sync.RWMutex
+	current = DefaultLocale
+
+	reported sync.Map // locale + "\x00" + key -> struct{}
+)
+
+func mustLoadCatalogs() map[string]map[string]message {
+	out, err := loadCatalogs()
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+func loadCatalogs() (map[string]map[string]message, error) {
+	files, err := localesFS.ReadDir("locales")
+	if err != nil {
+		return nil, fmt.Errorf("read locales: %w", err)
+	}
+	out := map[string]map[string]message{}
+	for _, f := range files {
+		name := f.Name()
+		if path.Ext(name) != ".json" {
+			continue
+		}
+		b, err := localesFS.ReadFile("locales/" + name)
+		if err != nil {
+			return nil, fmt.Errorf("read locale %s: %w", name, err)
+		}
+		var cat map[string]message
+		if err := json.Unmarshal(b, &cat); err != nil {
+			return nil, fmt.Errorf("parse locale %s: %w", name, err)
+		}
+		out[strings.TrimSuffix(name, ".json")] = cat
+	}
+	if _, ok := out[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("missing %s catalog", DefaultLocale)
+	}
+	return out, nil
+}
+
+// Locales returns the available locales, sorted.
+func Locales() []string {
+	out := make([]string, 0, len(catalogs))
+	for l := range catalogs {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// normalize reduces a locale such as "de_DE.UTF-8" or "de-AT" to an available catalog name, or "".
+func normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	if _, ok := catalogs[tag]; ok {
+		return tag
+	}
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		if _, ok := catalogs[tag[:i]]; ok {
+			return tag[:i]
+		}
+	}
+	return ""
+}
+
+// Detect picks the UI locale: the configured override unless it is empty or "auto", otherwise the
+// first of the operating system's preferred languages that has a catalog, otherwise English.
+func Detect(override string) string {
+	if o := strings.TrimSpace(override); o != "" && !strings.EqualFold(o, "auto") {
+		if l := normalize(o); l != "" {
+			return l
+		}
+		applog.WithComponent("i18n").Warn("unknown language in config, using the system language", slog.String("language", o))
+	}
+	tags, _ := locale.GetLocales()
+	for _, t := range tags {
+		if l := normalize(t); l != "" {
+			return l
+		}
+	}
+	return DefaultLocale
+}
+
+// SetLocale makes tag the active locale and returns the locale actually used, English when there is
+// no catalog for tag.
+func SetLocale(tag string) string {
+	l := normalize(tag)
+	if l == "" {
+		l = DefaultLocale
+	}
+	mu.Lock()
+	current = l
+	mu.Unlock()
+	return l
+}
+
+// Locale returns the active locale.
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// lookup returns the message for key in the active locale, falling back to English.
+func lookup(key string) (message, bool) {
+	loc := Locale()
+	if m, ok := catalogs[loc][key]; ok {
+		return m, true
+	}
+	m, ok := catalogs[DefaultLocale][key]
+	if _, seen := reported.LoadOrStore(loc+"\x00"+key, struct{}{}); !seen {
+		if ok {
+			applog.WithComponent("i18n").Warn("missing translation, using English", slog.String("locale", loc), slog.String("key", key))
+		} else {
+			applog.WithComponent("i18n").Error("unknown message key", slog.String("key", key))
+		}
+	}
+	return m, ok
+}
+
+// T returns the message for key in the active locale, formatted with args like fmt.Sprintf when args
+// are given. An unknown key is returned as it is.
+func T(key string, args ...any) string {
+	m, ok := lookup(key)
+	if !ok {
+		return key
+	}
+	s := m.Text
+	if m.plural() {
+		s = m.Other
+	}
+	if len(args) == 0 {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
+
+// N returns the plural form of key for count n in the active locale, formatted with args, or with n
+// alone when no args are given.
+func N(key string, n int, args ...any) string {
+	m, ok := lookup(key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		args = []any{n}
+	}
+	s := m.Text
+	if m.plural() {
+		s = m.Other
+		if pluralOne(n) && m.One != "" {
+			s = m.One
+		}
+	}
+	return fmt.Sprintf(s, args...)
+}
+
+// pluralOne reports whether n takes the "one" form. That is exactly 1 in English and German, the
+// locales so far; a locale with other plural rules needs its own case here.
+func pluralOne(n int) bool {
+	return n == 1
+}
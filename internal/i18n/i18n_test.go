@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package i18n
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+var verbRe = regexp.MustCompile(`%(\[(\d+)\])?[-+# 0]*\d*(\.\d+)?([a-zA-Z%])`)
+
+// verbs maps each argument position of a format string to its verb, so "%[2]d %[1]s" matches "%s %d".
+func verbs(s string) map[int]byte {
+	out := map[int]byte{}
+	arg := 0
+	for _, m := range verbRe.FindAllStringSubmatch(s, -1) {
+		if m[4] == "%" {
+			continue
+		}
+		if m[2] != "" {
+			arg, _ = strconv.Atoi(m[2])
+			arg--
+		}
+		out[arg] = m[4][0]
+		arg++
+	}
+	return out
+}
+
+func sameVerbs(a, b map[int]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func forms(m message) []string {
+	if m.plural() {
+		return []string{m.One, m.Other}
+	}
+	return []string{m.Text}
+}
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	en := catalogs[DefaultLocale]
+	if len(en) == 0 {
+		t.Fatal("empty English catalog")
+	}
+	for _, loc := range Locales() {
+		cat := catalogs[loc]
+		for key, m := range en {
+			tm, ok := cat[key]
+			if !ok {
+				t.Errorf("%s: missing key %s", loc, key)
+				continue
+			}
+			if tm.plural() != m.plural() {
+				t.Errorf("%s: %s: plural forms differ from English", loc, key)
+				continue
+			}
+			want := verbs(forms(m)[len(forms(m))-1])
+			for _, f := range forms(tm) {
+				if !sameVerbs(verbs(f), want) {
+					t.Errorf("%s: %s: format verbs of %q differ from English", loc, key, f)
+				}
+			}
+		}
+		for key := range cat {
+			if _, ok := en[key]; !ok {
+				t.Errorf("%s: key %s not in the English catalog", loc, key)
+			}
+		}
+	}
+}
+
+func TestFallbackToEnglishLogsOnce(t *testing.T) {
+	catalogs["xx"] = map[string]message{"button.browse": {Text: "Durchsuchen…"}}
+	defer func() {
+		delete(catalogs, "xx")
+		SetLocale(DefaultLocale)
+	}()
+	if got := SetLocale("xx-YY"); got != "xx" {
+		t.Fatalf("SetLocale = %q, want xx", got)
+	}
+	if got := T("button.browse"); got != "Durchsuchen…" {
+		t.Fatalf("translated = %q", got)
+	}
+	want := catalogs[DefaultLocale]["button.border"].Text
+	for i := 0; i < 2; i++ {
+		if got := T("button.border"); got != want {
+			t.Fatalf("fallback = %q, want %q", got, want)
+		}
+	}
+	n := 0
+	reported.Range(func(k, _ any) bool {
+		if k == "xx\x00button.border" {
+			n++
+		}
+		return true
+	})
+	if n != 1 {
+		t.Fatalf("missing key reported %d times, want once", n)
+	}
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Fatalf("unknown key = %q", got)
+	}
+}
+
+func TestPluralSelection(t *testing.T) {
+	catalogs["xx"] = map[string]message{
+		"count":  {One: "%d thing", Other: "%d things"},
+		"header": {One: "%s: %d hit", Other: "%s: %d hits"},
+	}
+	defer func() {
+		delete(catalogs, "xx")
+		SetLocale(DefaultLocale)
+	}()
+	SetLocale("xx")
+	if got := N("count", 1); got != "1 thing" {
+		t.Fatalf("N(1) = %q", got)
+	}
+	for _, n := range []int{0, 2} {
+		if got, want := N("count", n), strconv.Itoa(n)+" things"; got != want {
+			t.Fatalf("N(%d) = %q, want %q", n, got, want)
+		}
+	}
+	if got := N("header", 1, "cat", 1); got != "cat: 1 hit" {
+		t.Fatalf("N with args = %q", got)
+	}
+	if got := T("count", 3); got != "3 things" {
+		t.Fatalf("T of a plural message = %q", got)
+	}
+}
+
+func TestNormalizeAndDetect(t *testing.T) {
+	for in, want := range map[string]string{"de_DE.UTF-8": "de", "de-AT": "de", "EN": "en", "fr_FR": "", "": ""} {
+		if got := normalize(in); got != want {
+			t.Errorf("normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := Detect("de-CH"); got != "de" {
+		t.Fatalf("Detect override = %q", got)
+	}
+	if got := Detect("auto"); normalize(got) != got || got == "" {
+		t.Fatalf("Detect(auto) = %q, want an available locale", got)
+	}
+	if got := SetLocale("fr"); got != DefaultLocale || Locale() != DefaultLocale {
+		t.Fatalf("SetLocale(fr) = %q, Locale() = %q", got, Locale())
+	}
+}
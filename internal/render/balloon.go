@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// defaultBalloonPadding is the space between a balloon's outline and its text when no preset sets one.
+const defaultBalloonPadding = 6.0
+
+// BalloonLook is the resolved outline, fill and text padding of one balloon.
+type BalloonLook struct {
+	domain.Stroke
+	Fill    domain.Color
+	Dashed  bool
+	Hidden  bool // no outline at all
+	Padding float64
+	// Custom is true when the preset sets the width; raster output then scales it with the DPI like
+	// PanelFrame.
+	Custom bool
+}
+
+// ResolveBalloonLook applies the preset named by b.StyleRef over the renderer's base stroke and fill.
+// A balloon without a preset, or whose preset no longer exists, uses the base.
+func ResolveBalloonLook(styles []domain.BalloonStyle, b domain.Balloon, base domain.Stroke, fill domain.Color) BalloonLook {
+	lk := BalloonLook{Stroke: base, Fill: fill, Padding: defaultBalloonPadding}
+	s, ok := storage.FindBalloonStyle(styles, b.StyleRef)
+	if b.StyleRef == "" || !ok {
+		return lk
+	}
+	if s.Stroke != nil {
+		lk.Color = *s.Stroke
+	}
+	if s.StrokeWidth > 0 {
+		lk.Width = s.StrokeWidth
+		lk.Custom = true
+	}
+	if s.Fill != nil {
+		lk.Fill = *s.Fill
+	}
+	if s.Padding > 0 {
+		lk.Padding = s.Padding
+	}
+	lk.Dashed = s.StrokeStyle == domain.BalloonStrokeDashed
+	lk.Hidden = s.StrokeStyle == domain.BalloonStrokeNone
+	return lk
+}
+
+// Dash returns the dash and gap lengths of a dashed outline in points.
+func (lk BalloonLook) Dash() (on, off float64) {
+	return max(3, 3*lk.Width), max(2, 2*lk.Width)
+}
+
+// Pixels returns the raster line thickness of the outline at scale pixels per point.
+func (lk BalloonLook) Pixels(scale float64) int {
+	if !lk.Custom {
+		return 1
+	}
+	return max(1, int(math.Round(lk.Width*scale)))
+}
+
+// drawBalloonOutline strokes the rectangle of a balloon as its look asks: solid, dashed or not at all.
+func drawBalloonOutline(img *image.RGBA, x0, y0, x1, y1 int, lk BalloonLook, scale float64) {
+	if lk.Hidden {
+		return
+	}
+	t, col := lk.Pixels(scale), RGBA(lk.Color)
+	if !lk.Dashed {
+		StrokeRectWidth(img, x0, y0, x1, y1, t, col)
+		return
+	}
+	on, off := lk.Dash()
+	onPx, offPx := max(1, int(math.Round(on*scale))), max(1, int(math.Round(off*scale)))
+	for i := -(t / 2); i < t-t/2; i++ {
+		StrokeRectDashed(img, x0+i, y0+i, x1-i, y1-i, onPx, offPx, col)
+	}
+}
+
+// StrokeRectDashed draws a 1px rectangle border like StrokeRect in dashes of on pixels separated by
+// gaps of off pixels. The pattern runs on around the corners, clockwise from the top-left one.
+func StrokeRectDashed(img *image.RGBA, x0, y0, x1, y1, on, off int, col color.RGBA) {
+	period := on + off
+	if period <= 0 {
+		StrokeRect(img, x0, y0, x1, y1, col)
+		return
+	}
+	n := 0
+	set := func(x, y int) {
+		if n%period < on {
+			img.SetRGBA(x, y, col)
+		}
+		n++
+	}
+	for x := x0; x < x1; x++ {
+		set(x, y0)
+	}
+	for y := y0; y < y1; y++ {
+		set(x1, y)
+	}
+	for x := x1; x > x0; x-- {
+		set(x, y1)
+	}
+	for y := y1; y > y0; y-- {
+		set(x0, y)
+	}
+}
+
+// Outline detail of burst and cloud balloons: spikes of a burst and their depth, bumps of a cloud with
+// their depth and the points drawn per bump.
+const (
+	burstSpikes = 12
+	burstInner  = 0.72
+	cloudBumps  = 10
+	cloudDepth  = 0.18
+	cloudSteps  = 8
+)
+
+// BalloonPolygon returns the outline of a burst or cloud balloon in the box x, y, w, h as the corners
+// of a closed polygon, clockwise from the top. A burst alternates between the ellipse of the box and a smaller
+// inner one; a cloud runs along the ellipse in round bumps.
+func BalloonPolygon(kind string, x, y, w, h float64) [][2]float64 {
+	cx, cy, rx, ry := x+w/2, y+h/2, w/2, h/2
+	at := func(a, f float64) [2]float64 {
+		return [2]float64{cx + rx*f*math.Cos(a), cy + ry*f*math.Sin(a)}
+	}
+	var pts [][2]float64
+	if kind == storage.BalloonShapeBurst {
+		for i := 0; i < 2*burstSpikes; i++ {
+			f := 1.0
+			if i%2 == 1 {
+				f = burstInner
+			}
+			pts = append(pts, at(-math.Pi/2+float64(i)*math.Pi/burstSpikes, f))
+		}
+		return pts
+	}
+	n := cloudBumps * cloudSteps
+	for i := 0; i < n; i++ {
+		a := float64(i) * 2 * math.Pi / float64(n)
+		pts = append(pts, at(-math.Pi/2+a, 1-cloudDepth+cloudDepth*math.Abs(math.Sin(a*cloudBumps/2))))
+	}
+	return pts
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestResolveBalloonLook(t *testing.T) {
+	base := domain.Stroke{Color: domain.Color{A: 255}, Width: 1}
+	white := domain.Color{R: 255, G: 255, B: 255, A: 255}
+	grey := domain.Color{R: 200, G: 200, B: 200, A: 255}
+	styles := []domain.BalloonStyle{
+		{Name: "Whisper", StrokeStyle: domain.BalloonStrokeDashed, StrokeWidth: 2, Fill: &grey, Padding: 9},
+		{Name: "Caption", StrokeStyle: domain.BalloonStrokeNone},
+	}
+	lk := ResolveBalloonLook(styles, domain.Balloon{StyleRef: "whisper"}, base, white)
+	if !lk.Dashed || lk.Hidden || lk.Width != 2 || lk.Fill != grey || lk.Padding != 9 || lk.Pixels(2) != 4 {
+		t.Errorf("whisper look = %+v", lk)
+	}
+	if on, off := lk.Dash(); on != 6 || off != 4 {
+		t.Errorf("dash = %g/%g, want 6/4", on, off)
+	}
+	if lk := ResolveBalloonLook(styles, domain.Balloon{StyleRef: "Caption"}, base, white); !lk.Hidden {
+		t.Errorf("caption look = %+v, want no outline", lk)
+	}
+	// No preset, or a deleted one, uses the base
+	for _, ref := range []string{"", "Gone"} {
+		lk := ResolveBalloonLook(styles, domain.Balloon{StyleRef: ref}, base, white)
+		if lk.Stroke != base || lk.Fill != white || lk.Dashed || lk.Padding != defaultBalloonPadding || lk.Pixels(4) != 1 {
+			t.Errorf("look for %q = %+v", ref, lk)
+		}
+	}
+}
+
+func TestDashedBalloonOutline(t *testing.T) {
+	st := testStyle()
+	st.BalloonStyles = []domain.BalloonStyle{{Name: "Whisper", StrokeStyle: domain.BalloonStrokeDashed}}
+	pg := domain.Page{Number: 1, Panels: []domain.Panel{{
+		ID:       "p1",
+		Balloons: []domain.Balloon{{ID: "b1", StyleRef: "Whisper", Shape: domain.Shape{Rect: domain.Rect{X: 10, Y: 10, Width: 40, Height: 20}}}},
+	}}}
+	img := PageImage(pg, 100, 100, 1, st)
+	// Along the top edge the 3pt dashes alternate with 2pt gaps filled like the balloon
+	ink := color.RGBA{A: 255}
+	for x, want := range map[int]color.RGBA{10: ink, 12: ink, 13: st.BalloonFill, 14: st.BalloonFill, 15: ink} {
+		if got := img.RGBAAt(x, 10); got != want {
+			t.Errorf("top edge at x=%d = %v, want %v", x, got, want)
+		}
+	}
+}
+
+func TestStrokeRectDashedContinuesAroundCorners(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	ink := color.RGBA{A: 255}
+	StrokeRectDashed(img, 0, 0, 4, 4, 2, 2, ink)
+	// 16 outline pixels clockwise from the corner: on on off off ...
+	n := 0
+	for y := 0; y <= 4; y++ {
+		for x := 0; x <= 4; x++ {
+			if img.RGBAAt(x, y) == ink {
+				n++
+			}
+		}
+	}
+	if n != 8 {
+		t.Errorf("inked pixels = %d, want 8 of the 16 outline pixels", n)
+	}
+	if img.RGBAAt(1, 0) != ink || img.RGBAAt(2, 0) == ink || img.RGBAAt(4, 0) != ink {
+		t.Error("top edge does not start with a dash")
+	}
+}
+
+func TestBalloonPolygon(t *testing.T) {
+	burst := BalloonPolygon("burst", 0, 0, 100, 50)
+	if len(burst) != 24 {
+		t.Fatalf("burst corners = %d, want 24", len(burst))
+	}
+	if p := burst[0]; p[0] != 50 || p[1] != 0 {
+		t.Errorf("burst starts at %v, want the top of the box", p)
+	}
+	for _, p := range append(burst, BalloonPolygon("cloud", 0, 0, 100, 50)...) {
+		if p[0] < -1e-9 || p[0] > 100+1e-9 || p[1] < -1e-9 || p[1] > 50+1e-9 {
+			t.Errorf("corner %v outside the box", p)
+		}
+	}
+}
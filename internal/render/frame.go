@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package render
+
+import (
+	"math"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// PanelFrame is the resolved border of one panel.
+type PanelFrame struct {
+	domain.Stroke
+	// Custom is true when the manifest sets the width; raster output then scales it with the DPI
+	// instead of drawing the historic 1px hairline.
+	Custom bool
+}
+
+// ResolvePanelFrame applies the project's and the panel's border settings over the renderer's base
+// stroke. ok is false for borderless panels.
+func ResolvePanelFrame(projectDefault *domain.PanelBorder, pn domain.Panel, base domain.Stroke) (f PanelFrame, ok bool) {
+	b := storage.ResolvePanelBorder(projectDefault, pn.Border)
+	if b.Style == domain.PanelBorderNone {
+		return PanelFrame{}, false
+	}
+	f.Stroke = base
+	if b.Color != nil {
+		f.Color = *b.Color
+	}
+	if b.Width > 0 {
+		f.Width = b.Width
+		f.Custom = true
+	}
+	return f, true
+}
+
+// Pixels returns the raster line thickness of the frame at scale pixels per point.
+func (f PanelFrame) Pixels(scale float64) int {
+	if !f.Custom {
+		return 1
+	}
+	return max(1, int(math.Round(f.Width*scale)))
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+// Package render rasterizes pages from the model. The PNG, CBZ, EPUB and webtoon exporters draw their
+// pages with it, and so do on-screen previews, so both show the same thing.
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Style holds the resolved guide, panel and lettering colors of a raster page. Panel is the default
+// panel frame; Border the project's panel border settings applied over it. BalloonStyles are the
+// project's balloon presets, applied over the balloon colors.
+type Style struct {
+	Guides        bool
+	Guide         color.RGBA
+	Panel         domain.Stroke
+	Border        *domain.PanelBorder
+	BalloonStroke color.RGBA
+	BalloonFill   color.RGBA
+	CaptionFill   color.RGBA
+	BalloonStyles []domain.BalloonStyle
+}
+
+// PageImage rasterizes the trim area of pg, trimW×trimH points, at scale pixels per point.
+func PageImage(pg domain.Page, trimW, trimH, scale float64, st Style) *image.RGBA {
+	w := max(1, int(math.Round(trimW*scale)))
+	h := max(1, int(math.Round(trimH*scale)))
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{255, 255, 255, 255}}, image.Point{}, draw.Src)
+	DrawPage(img, pg, 0, 0, scale, st)
+	return img
+}
+
+// DrawPage draws the panels and lettering of pg with the page's trim origin at (ox, oy) points.
+func DrawPage(img *image.RGBA, pg domain.Page, ox, oy, scale float64, st Style) {
+	for _, pnl := range pg.Panels {
+		r := pnl.Geometry
+		x := int(math.Round((r.X + ox) * scale))
+		y := int(math.Round((r.Y + oy) * scale))
+		w := int(math.Round(r.Width * scale))
+		h := int(math.Round(r.Height * scale))
+		if f, ok := ResolvePanelFrame(st.Border, pnl, st.Panel); ok {
+			StrokeRectWidth(img, x, y, x+w-1, y+h-1, f.Pixels(scale), RGBA(f.Color))
+		}
+
+		// Balloons
+		for _, b := range pnl.Balloons {
+			br := b.Shape.Rect
+			bxp := int(math.Round((br.X + ox) * scale))
+			byp := int(math.Round((br.Y + oy) * scale))
+			bw := int(math.Round(br.Width * scale))
+			bh := int(math.Round(br.Height * scale))
+			lk := ResolveBalloonLook(st.BalloonStyles, b, domain.Stroke{Color: fromRGBA(st.BalloonStroke), Width: 1}, fromRGBA(st.BalloonFill))
+			// Curved and polygonal outlines run through the centers of the box's edge pixels, like the stroke of a plain box
+			if outline := balloonOutline(b.Shape.Kind, b.Shape.Radius*scale, float64(bxp)+0.5, float64(byp)+0.5, float64(bw-1), float64(bh-1), curveStep); outline != nil {
+				drawBalloonShape(img, outline, lk, scale)
+				continue
+			}
+			FillRect(img, bxp, byp, bxp+bw-1, byp+bh-1, RGBA(lk.Fill))
+			drawBalloonOutline(img, bxp, byp, bxp+bw-1, byp+bh-1, lk, scale)
+		}
+		drawCaptionsAndSFX(img, pnl, ox, oy, scale, st.CaptionFill, st.BalloonStroke)
+	}
+}
+
+// drawCaptionsAndSFX renders a panel's captions as filled boxes with text and its SFX as plain text.
+// Text uses a fixed bitmap face for now; rotation is ignored in raster output.
+func drawCaptionsAndSFX(img *image.RGBA, pnl domain.Panel, ox, oy, scale float64, fill, stroke color.RGBA) {
+	black := color.RGBA{0, 0, 0, 255}
+	lineH := basicfont.Face7x13.Metrics().Height.Ceil()
+	for _, c := range pnl.Captions {
+		r := c.Rect
+		x := int(math.Round((r.X + ox) * scale))
+		y := int(math.Round((r.Y + oy) * scale))
+		w := int(math.Round(r.Width * scale))
+		h := int(math.Round(r.Height * scale))
+		FillRect(img, x, y, x+w-1, y+h-1, fill)
+		StrokeRect(img, x, y, x+w-1, y+h-1, stroke)
+		ty := y + 4 + lineH
+		for _, line := range strings.Split(c.Text, "\n") {
+			Text(img, x+4, ty, line, black)
+			ty += lineH
+		}
+	}
+	for _, fx := range pnl.SFX {
+		r := fx.Rect
+		x := int(math.Round((r.X + ox) * scale))
+		y := int(math.Round((r.Y + oy) * scale))
+		Text(img, x, y+lineH, fx.Text, black)
+	}
+}
+
+// Text draws s with its baseline at (x, y).
+func Text(img *image.RGBA, x, y int, s string, col color.RGBA) {
+	if s == "" {
+		return
+	}
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(col), Face: basicfont.Face7x13, Dot: fixed.P(x, y)}
+	d.DrawString(s)
+}
+
+// RGBA converts a model color.
+func RGBA(c domain.Color) color.RGBA {
+	return color.RGBA{R: c.R, G: c.G, B: c.B, A: c.A}
+}
+
+// fromRGBA converts back to a model color.
+func fromRGBA(c color.RGBA) domain.Color {
+	return domain.Color{R: c.R, G: c.G, B: c.B, A: c.A}
+}
+
+// StrokeRect draws a 1px axis-aligned rectangle border inclusive of endpoints.
+func StrokeRect(img *image.RGBA, x0, y0, x1, y1 int, col color.RGBA) {
+	// top and bottom
+	for x := x0; x <= x1; x++ {
+		img.SetRGBA(x, y0, col)
+		img.SetRGBA(x, y1, col)
+	}
+	// left and right
+	for y := y0; y <= y1; y++ {
+		img.SetRGBA(x0, y, col)
+		img.SetRGBA(x1, y, col)
+	}
+}
+
+// StrokeRectWidth draws a rectangle border t pixels thick centered on the 1px outline of StrokeRect.
+func StrokeRectWidth(img *image.RGBA, x0, y0, x1, y1, t int, col color.RGBA) {
+	for i := -(t / 2); i < t-t/2; i++ {
+		StrokeRect(img, x0+i, y0+i, x1-i, y1-i, col)
+	}
+}
+
+// FillRect fills the rectangle inclusive of endpoints.
+func FillRect(img *image.RGBA, x0, y0, x1, y1 int, col color.RGBA) {
+	if x1 < x0 {
+		x0, x1 = x1, x0
+	}
+	if y1 < y0 {
+		y0, y1 = y1, y0
+	}
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			img.SetRGBA(x, y, col)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package render
+
+import (
+	"image/color"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func testStyle() Style {
+	return Style{Panel: domain.Stroke{Color: domain.Color{A: 255}, Width: 1},
+		BalloonStroke: color.RGBA{A: 255}, BalloonFill: color.RGBA{R: 200, G: 200, B: 255, A: 255}, CaptionFill: color.RGBA{R: 255, G: 255, A: 255}}
+}
+
+func TestPageImage(t *testing.T) {
+	pg := domain.Page{Number: 1, Panels: []domain.Panel{{
+		ID: "p1", Geometry: domain.Rect{X: 10, Y: 10, Width: 50, Height: 40},
+		Balloons: []domain.Balloon{{ID: "b1", Shape: domain.Shape{Rect: domain.Rect{X: 20, Y: 20, Width: 10, Height: 10}}}},
+	}}}
+	img := PageImage(pg, 100, 150, 2, testStyle())
+	if b := img.Bounds(); b.Dx() != 200 || b.Dy() != 300 {
+		t.Fatalf("size = %v, want 200x300", b)
+	}
+	white := color.RGBA{255, 255, 255, 255}
+	if got := img.RGBAAt(5, 5); got != white {
+		t.Fatalf("background = %v, want white", got)
+	}
+	if got := img.RGBAAt(20, 30); got != (color.RGBA{A: 255}) {
+		t.Fatalf("panel frame pixel = %v, want black", got)
+	}
+	if got := img.RGBAAt(50, 50); got != testStyle().BalloonFill {
+		t.Fatalf("balloon pixel = %v, want the balloon fill", got)
+	}
+	// A panel inside the frame stays white
+	if got := img.RGBAAt(100, 90); got != white {
+		t.Fatalf("panel interior = %v, want white", got)
+	}
+}
+
+func TestResolvePanelFrame(t *testing.T) {
+	base := domain.Stroke{Color: domain.Color{A: 255}, Width: 1}
+	red := domain.Color{R: 255, A: 255}
+	if _, ok := ResolvePanelFrame(&domain.PanelBorder{Style: domain.PanelBorderNone}, domain.Panel{}, base); ok {
+		t.Fatal("borderless panel got a frame")
+	}
+	f, ok := ResolvePanelFrame(nil, domain.Panel{Border: &domain.PanelBorder{Color: &red, Width: 2}}, base)
+	if !ok || f.Color != red || f.Width != 2 || f.Pixels(3) != 6 {
+		t.Fatalf("frame = %+v, %d px", f, f.Pixels(3))
+	}
+	if f, _ := ResolvePanelFrame(nil, domain.Panel{}, base); f.Pixels(4) != 1 {
+		t.Fatalf("default frame = %d px, want the 1px hairline", f.Pixels(4))
+	}
+}
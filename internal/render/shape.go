@@ -0,0 +1,245 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	"gocomicwriter/internal/storage"
+)
+
+// curveStep is the length in pixels of the segments that approximate curved balloon outlines.
+const curveStep = 2.0
+
+// balloonOutline returns the outline of a balloon of the given kind in the box x, y, w, h as the corners
+// of a closed polygon, clockwise from the top, with curves split into segments of about step. It returns
+// nil for a plain box: rect balloons, unknown kinds and rounded boxes without a radius.
+func balloonOutline(kind string, radius, x, y, w, h, step float64) [][2]float64 {
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+	switch kind {
+	case storage.BalloonShapeEllipse:
+		return ellipseOutline(x+w/2, y+h/2, w/2, h/2, step)
+	case storage.BalloonShapeRoundedBox:
+		r := min(radius, w/2, h/2)
+		if r <= 0 {
+			return nil
+		}
+		return roundedBoxOutline(x, y, w, h, r, step)
+	case storage.BalloonShapeBurst, storage.BalloonShapeCloud:
+		return BalloonPolygon(kind, x, y, w, h)
+	}
+	return nil
+}
+
+// ellipseOutline returns the ellipse around (cx, cy) with radii rx and ry, clockwise from the top.
+func ellipseOutline(cx, cy, rx, ry, step float64) [][2]float64 {
+	n := max(16, int(math.Ceil(math.Pi*(rx+ry)/step)))
+	pts := make([][2]float64, n)
+	for i := range pts {
+		a := -math.Pi/2 + 2*math.Pi*float64(i)/float64(n)
+		pts[i] = [2]float64{cx + rx*math.Cos(a), cy + ry*math.Sin(a)}
+	}
+	return pts
+}
+
+// roundedBoxOutline returns the box x, y, w, h with corners rounded to radius r, clockwise from the
+// start of the top edge.
+func roundedBoxOutline(x, y, w, h, r, step float64) [][2]float64 {
+	n := max(2, int(math.Ceil(math.Pi/2*r/step)))
+	// Corner centers clockwise from the top right, each with the angle its arc starts at
+	corners := [4][3]float64{
+		{x + w - r, y + r, -math.Pi / 2},
+		{x + w - r, y + h - r, 0},
+		{x + r, y + h - r, math.Pi / 2},
+		{x + r, y + r, math.Pi},
+	}
+	pts := make([][2]float64, 0, 4*(n+1))
+	for _, c := range corners {
+		for i := 0; i <= n; i++ {
+			a := c[2] + math.Pi/2*float64(i)/float64(n)
+			pts = append(pts, [2]float64{c[0] + r*math.Cos(a), c[1] + r*math.Sin(a)})
+		}
+	}
+	return pts
+}
+
+// drawBalloonShape fills and strokes a balloon with a curved or polygonal outline in pixels, anti-aliased,
+// with the outline solid, dashed or hidden as its look asks.
+func drawBalloonShape(img *image.RGBA, outline [][2]float64, lk BalloonLook, scale float64) {
+	t := float64(lk.Pixels(scale))
+	bounds := outlineBounds(outline, t/2+1).Intersect(img.Bounds())
+	if bounds.Empty() {
+		return
+	}
+	fill := newCoverageMask(bounds)
+	fill.fillPolygon(outline)
+	fill.blend(img, RGBA(lk.Fill))
+	if lk.Hidden {
+		return
+	}
+	var on, off float64
+	if lk.Dashed {
+		on, off = lk.Dash()
+		on, off = max(1, math.Round(on*scale)), max(1, math.Round(off*scale))
+	}
+	stroke := newCoverageMask(bounds)
+	stroke.strokePolygon(outline, t, on, off)
+	stroke.blend(img, RGBA(lk.Color))
+}
+
+// outlineBounds returns the pixels touched by the polygon pts grown by pad on every side.
+func outlineBounds(pts [][2]float64, pad float64) image.Rectangle {
+	if len(pts) == 0 {
+		return image.Rectangle{}
+	}
+	x0, y0, x1, y1 := pts[0][0], pts[0][1], pts[0][0], pts[0][1]
+	for _, p := range pts[1:] {
+		x0, y0 = min(x0, p[0]), min(y0, p[1])
+		x1, y1 = max(x1, p[0]), max(y1, p[1])
+	}
+	return image.Rect(int(math.Floor(x0-pad)), int(math.Floor(y0-pad)), int(math.Ceil(x1+pad))+1, int(math.Ceil(y1+pad))+1)
+}
+
+// coverageMask holds how much of each pixel in a rectangle a shape covers, from 0 to 1.
+type coverageMask struct {
+	r image.Rectangle
+	a []float64
+}
+
+func newCoverageMask(r image.Rectangle) *coverageMask {
+	return &coverageMask{r: r, a: make([]float64, r.Dx()*r.Dy())}
+}
+
+func (m *coverageMask) index(x, y int) int {
+	return (y-m.r.Min.Y)*m.r.Dx() + x - m.r.Min.X
+}
+
+// coverageRows is the number of scanlines sampled per pixel row when filling.
+const coverageRows = 4
+
+// fillPolygon covers the inside of the closed polygon pts by the even-odd rule. Each pixel row is
+// sampled at coverageRows scanlines whose spans count with their exact horizontal extent, which
+// smooths the edges.
+func (m *coverageMask) fillPolygon(pts [][2]float64) {
+	var xs []float64
+	for py := m.r.Min.Y; py < m.r.Max.Y; py++ {
+		for s := 0; s < coverageRows; s++ {
+			sy := float64(py) + (float64(s)+0.5)/coverageRows
+			xs = xs[:0]
+			for i, a := range pts {
+				b := pts[(i+1)%len(pts)]
+				if (a[1] <= sy) == (b[1] <= sy) {
+					continue
+				}
+				xs = append(xs, a[0]+(sy-a[1])*(b[0]-a[0])/(b[1]-a[1]))
+			}
+			sort.Float64s(xs)
+			for i := 0; i+1 < len(xs); i += 2 {
+				m.span(py, xs[i], xs[i+1], 1.0/coverageRows)
+			}
+		}
+	}
+}
+
+// span adds w times the part of each pixel of row y that lies between x0 and x1.
+func (m *coverageMask) span(y int, x0, x1, w float64) {
+	x0, x1 = max(x0, float64(m.r.Min.X)), min(x1, float64(m.r.Max.X))
+	for px := int(math.Floor(x0)); float64(px) < x1; px++ {
+		if c := min(x1, float64(px+1)) - max(x0, float64(px)); c > 0 {
+			m.a[m.index(px, y)] += c * w
+		}
+	}
+}
+
+// strokePolygon covers the closed outline pts t pixels wide. With on > 0 the outline is drawn in dashes
+// of on pixels separated by gaps of off pixels, the pattern running on around the corners.
+func (m *coverageMask) strokePolygon(pts [][2]float64, t, on, off float64) {
+	pos := 0.0 // distance along the outline, for the dash pattern
+	for i, a := range pts {
+		b := pts[(i+1)%len(pts)]
+		if on <= 0 {
+			m.strokeSegment(a, b, t, false)
+			continue
+		}
+		l := math.Hypot(b[0]-a[0], b[1]-a[1])
+		at := func(d float64) [2]float64 { return [2]float64{a[0] + (b[0]-a[0])*d/l, a[1] + (b[1]-a[1])*d/l} }
+		for d := 0.0; d < l; {
+			phase := math.Mod(pos, on+off)
+			var run float64
+			if phase < on {
+				run = min(on-phase, l-d)
+				m.strokeSegment(at(d), at(d+run), t, true)
+			} else {
+				run = min(on+off-phase, l-d)
+			}
+			d += run
+			pos += run
+		}
+	}
+}
+
+// strokeSegment covers the pixels whose centers lie within t/2 of the segment from a to b, fading
+// out over one pixel at the edge. The ends are round, so the segments of an outline join without
+// gaps, or cut square at a and b when butt is set, so the gaps between dashes stay clear.
+func (m *coverageMask) strokeSegment(a, b [2]float64, t float64, butt bool) {
+	h := t / 2
+	r := outlineBounds([][2]float64{a, b}, h+1).Intersect(m.r)
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	ll := dx*dx + dy*dy
+	l := math.Sqrt(ll)
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			cx, cy := float64(px)+0.5, float64(py)+0.5
+			u := 0.0
+			if ll > 0 {
+				u = ((cx-a[0])*dx + (cy-a[1])*dy) / ll
+			}
+			c := 1.0
+			var d float64
+			if butt && l > 0 {
+				// Distance across the segment, faded out past the ends
+				c = math.Min(1, math.Min(u, 1-u)*l+0.5)
+				d = math.Abs((cx-a[0])*dy-(cy-a[1])*dx) / l
+			} else {
+				u = math.Max(0, math.Min(1, u))
+				d = math.Hypot(cx-a[0]-u*dx, cy-a[1]-u*dy)
+			}
+			if c = math.Min(c, h+0.5-d); c > 0 {
+				i := m.index(px, py)
+				m.a[i] = math.Max(m.a[i], c)
+			}
+		}
+	}
+}
+
+// blend paints col over img in proportion to the coverage of each pixel.
+func (m *coverageMask) blend(img *image.RGBA, col color.RGBA) {
+	r := m.r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			c := min(1, m.a[m.index(x, y)])
+			if c <= 0 {
+				continue
+			}
+			if c == 1 && col.A == 255 {
+				img.SetRGBA(x, y, col)
+				continue
+			}
+			d := img.RGBAAt(x, y)
+			k := 1 - c*float64(col.A)/255
+			mix := func(s, d uint8) uint8 { return uint8(math.Round(float64(s)*c + float64(d)*k)) }
+			img.SetRGBA(x, y, color.RGBA{R: mix(col.R, d.R), G: mix(col.G, d.G), B: mix(col.B, d.B), A: mix(col.A, d.A)})
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package render
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestBalloonOutline(t *testing.T) {
+	for _, kind := range []string{"", "rect", "unknown"} {
+		if pts := balloonOutline(kind, 5, 0, 0, 100, 50, curveStep); pts != nil {
+			t.Errorf("%q outline = %d points, want a plain box", kind, len(pts))
+		}
+	}
+	if pts := balloonOutline("roundedBox", 0, 0, 0, 100, 50, curveStep); pts != nil {
+		t.Errorf("rounded box without radius = %d points, want a plain box", len(pts))
+	}
+	// The radius is capped at half the shorter side and every point stays in the box
+	for _, kind := range []string{"ellipse", "roundedBox", "burst", "cloud"} {
+		pts := balloonOutline(kind, 80, 0, 0, 100, 50, curveStep)
+		if len(pts) < 16 {
+			t.Fatalf("%s outline = %d points", kind, len(pts))
+		}
+		for _, p := range pts {
+			if p[0] < -1e-9 || p[0] > 100+1e-9 || p[1] < -1e-9 || p[1] > 50+1e-9 {
+				t.Errorf("%s point %v outside the box", kind, p)
+			}
+		}
+	}
+	if p := balloonOutline("ellipse", 0, 0, 0, 100, 50, curveStep)[0]; math.Abs(p[0]-50) > 1e-9 || math.Abs(p[1]) > 1e-9 {
+		t.Errorf("ellipse starts at %v, want the top of the box", p)
+	}
+}
+
+func TestPageImage_BalloonShapes(t *testing.T) {
+	st := testStyle()
+	st.Panel = domain.Stroke{}
+	balloon := func(kind string, x float64, radius float64) domain.Balloon {
+		return domain.Balloon{ID: kind, Shape: domain.Shape{Kind: kind, Radius: radius, Rect: domain.Rect{X: x, Y: 10, Width: 40, Height: 20}}}
+	}
+	pg := domain.Page{Number: 1, Panels: []domain.Panel{{ID: "p1", Balloons: []domain.Balloon{
+		balloon("rect", 10, 0), balloon("ellipse", 60, 0), balloon("roundedBox", 110, 6),
+	}}}}
+	scale := 300.0 / 72
+	img := PageImage(pg, 160, 40, scale, st)
+	px := func(x, y float64) color.RGBA { return img.RGBAAt(int(math.Round(x*scale)), int(math.Round(y*scale))) }
+	white := color.RGBA{255, 255, 255, 255}
+	ink := color.RGBA{A: 255}
+
+	// Rect: the corners are on the outline
+	if got := px(10, 10); got != ink {
+		t.Errorf("rect corner = %v, want the outline", got)
+	}
+	// Ellipse: the corners of its box stay background, the middle is filled and the top is outlined
+	for _, c := range [][2]float64{{60.5, 10.5}, {99.5, 10.5}, {60.5, 29.5}, {99.5, 29.5}} {
+		if got := px(c[0], c[1]); got != white {
+			t.Errorf("ellipse box corner %v = %v, want background", c, got)
+		}
+	}
+	if got := px(80, 20); got != st.BalloonFill {
+		t.Errorf("ellipse center = %v, want the fill", got)
+	}
+	if got := img.RGBAAt(int(80*scale), int(math.Round(10*scale))); got.R > 128 {
+		t.Errorf("ellipse top = %v, want the outline", got)
+	}
+	// Rounded box: the very corner is background, the box is filled just inside the rounding
+	if got := px(110.3, 10.3); got != white {
+		t.Errorf("rounded box corner = %v, want background", got)
+	}
+	if got := px(113, 13); got != st.BalloonFill {
+		t.Errorf("rounded box inside the corner = %v, want the fill", got)
+	}
+	if got := px(130, 10.1); got.R > 128 {
+		t.Errorf("rounded box top edge = %v, want the outline", got)
+	}
+	// The curved edges are smoothed: some pixels lie between background and outline
+	soft := 0
+	for y := int(10 * scale); y < int(20*scale); y++ {
+		for x := int(60 * scale); x < int(80*scale); x++ {
+			if c := img.RGBAAt(x, y); c != white && c != ink && c != st.BalloonFill {
+				soft++
+			}
+		}
+	}
+	if soft == 0 {
+		t.Error("ellipse edge is not anti-aliased")
+	}
+}
+
+func TestPageImage_DashedEllipse(t *testing.T) {
+	st := testStyle()
+	st.BalloonStyles = []domain.BalloonStyle{{Name: "Whisper", StrokeStyle: domain.BalloonStrokeDashed}}
+	pg := domain.Page{Number: 1, Panels: []domain.Panel{{ID: "p1", Balloons: []domain.Balloon{{
+		ID: "b1", StyleRef: "Whisper", Shape: domain.Shape{Kind: "ellipse", Rect: domain.Rect{X: 10, Y: 10, Width: 80, Height: 40}},
+	}}}}}
+	img := PageImage(pg, 100, 60, 4, st)
+	// Along the top of the ellipse the dashes alternate with gaps filled like the balloon
+	dark, light := 0, 0
+	for x := 4 * 40; x < 4*60; x++ {
+		if img.RGBAAt(x, 4*10).R < 100 {
+			dark++
+		} else {
+			light++
+		}
+	}
+	if dark == 0 || light == 0 {
+		t.Errorf("top of dashed ellipse: %d dark and %d light pixels, want both", dark, light)
+	}
+}
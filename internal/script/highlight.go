@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package script
+
+import "strings"
+
+// SpanKind classifies a highlighted part of a script line.
+type SpanKind int
+
+const (
+	SpanScene     SpanKind = iota + 1 // scene heading, whole line
+	SpanCharacter                     // "NAME:" cue of a dialogue line
+	SpanCaption                       // "CAPTION:" or "NARRATION:" cue
+	SpanBeat                          // "Panel 3" or "Beat" marker
+	SpanNote                          // ";" note, whole line
+	SpanTag                           // @tag in dialogue, caption or beat text
+)
+
+// Span is a highlighted part of a line as byte offsets [Start, End).
+type Span struct {
+	Kind       SpanKind
+	Start, End int
+}
+
+// HighlightLine classifies one source line the way Parse reads it. cont tells whether the line
+// above was dialogue or a caption (or a continuation of one), in which case an indented line continues
+// it; the returned value is the cont to pass for the following line. Highlighting a document
+// therefore needs only the state of the previous line, so an editor can re-highlight just the lines
+// an edit touched and stop as soon as the returned state matches what it had before.
+func HighlightLine(line string, cont bool) ([]Span, bool) {
+	line = strings.TrimRight(line, "\r")
+	if cont && strings.HasPrefix(line, "  ") {
+		return tagSpans(nil, line, 0), true
+	}
+	trim := strings.TrimSpace(line)
+	if trim == "" {
+		return nil, false
+	}
+	off := strings.Index(line, trim)
+	whole := []Span{{Start: off, End: off + len(trim)}}
+	switch {
+	case reScene.MatchString(trim), reSceneAlt.MatchString(trim):
+		whole[0].Kind = SpanScene
+		return whole, false
+	case strings.HasPrefix(trim, ";"):
+		whole[0].Kind = SpanNote
+		return whole, false
+	}
+	if m := reBeat.FindStringSubmatchIndex(trim); m != nil {
+		spans := []Span{{Kind: SpanBeat, Start: off + m[2], End: off + m[3]}}
+		return tagSpans(spans, trim[m[4]:], off+m[4]), false
+	}
+	if m := reName.FindStringSubmatchIndex(trim); m != nil {
+		kind := SpanCharacter
+		if name := strings.ToUpper(strings.TrimSpace(trim[m[2]:m[3]])); name == "CAPTION" || name == "NARRATION" {
+			kind = SpanCaption
+		}
+		spans := []Span{{Kind: kind, Start: off, End: off + strings.IndexByte(trim, ':') + 1}}
+		return tagSpans(spans, trim[m[4]:], off+m[4]), true
+	}
+	return nil, false
+}
+
+// tagSpans appends the @tags found in text, which starts at byte offset base of the line.
+func tagSpans(spans []Span, text string, base int) []Span {
+	for _, m := range reTag.FindAllStringIndex(text, -1) {
+		spans = append(spans, Span{Kind: SpanTag, Start: base + m[0], End: base + m[1]})
+	}
+	return spans
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package script
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHighlightLine(t *testing.T) {
+	src := `# Rooftop
+ALICE: Look out! @danger
+  It's falling @debris
+CAPTION: Later.
+; pencil note
+Panel 2 Wide shot @establishing
+  indented after a beat
+Just some words`
+	want := []string{
+		"scene:# Rooftop",
+		"character:ALICE: tag:@danger",
+		"tag:@debris",
+		"caption:CAPTION:",
+		"note:; pencil note",
+		"beat:Panel 2 tag:@establishing",
+		"",
+		"",
+	}
+	names := map[SpanKind]string{SpanScene: "scene", SpanCharacter: "character", SpanCaption: "caption", SpanBeat: "beat", SpanNote: "note", SpanTag: "tag"}
+	cont := false
+	for i, line := range strings.Split(src, "\n") {
+		var spans []Span
+		spans, cont = HighlightLine(line, cont)
+		var got []string
+		for _, sp := range spans {
+			got = append(got, fmt.Sprintf("%s:%s", names[sp.Kind], line[sp.Start:sp.End]))
+		}
+		if g := strings.Join(got, " "); g != want[i] {
+			t.Errorf("line %d %q: got %q, want %q", i+1, line, g, want[i])
+		}
+	}
+}
+
+func TestHighlightLineContinuationState(t *testing.T) {
+	// The state after each line must agree with how Parse groups continuation lines.
+	cases := []struct {
+		line       string
+		cont, want bool
+	}{
+		{"BOB: hi", false, true},
+		{"  more", true, true},
+		{"  ", true, true},
+		{"", true, false},
+		{"  BOB: indented cue", false, true},
+		{"# Scene", true, false},
+		{"free text", false, false},
+		{"  after free text", false, false},
+	}
+	for _, c := range cases {
+		if _, got := HighlightLine(c.line, c.cont); got != c.want {
+			t.Errorf("HighlightLine(%q, %v) state = %v, want %v", c.line, c.cont, got, c.want)
+		}
+	}
+}
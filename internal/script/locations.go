@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package script
+
+import (
+	"regexp"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// reTitleSep splits a scene title into parts that may name a location, e.g. "Scene 1: Rooftop – Night".
+var reTitleSep = regexp.MustCompile(`\s*(?:[:–—/,|]|\s-\s)\s*`)
+
+// reSlugPrefix matches screenplay-style interior/exterior prefixes such as "INT." or "EXT./INT.".
+var reSlugPrefix = regexp.MustCompile(`(?i)^(?:int|ext|i/e)\.?(?:\s*/\s*(?:int|ext)\.?)?\s+`)
+
+// locationNames maps the upper-case names and aliases of the bible's locations to their names.
+func locationNames(b domain.Bible) map[string]string {
+	out := map[string]string{}
+	for _, l := range b.Locations {
+		name := strings.TrimSpace(l.Name)
+		if name == "" {
+			continue
+		}
+		for _, n := range append([]string{name}, l.Aliases...) {
+			u := strings.ToUpper(strings.TrimSpace(n))
+			if _, dup := out[u]; u != "" && !dup {
+				out[u] = name
+			}
+		}
+	}
+	return out
+}
+
+// LinkLocations returns sc with the Location of each scene set to the bible location it refers to:
+// the one named by its "[loc:NAME]" marker, or else the first part of its title that is a location
+// name or alias ("Rooftop – Night" and "INT. ROOFTOP" both link to Rooftop). Matching is
+// case-insensitive. A marker naming no location leaves the scene unlinked; ValidateAgainstBible
+// reports it.
+func LinkLocations(sc Script, b domain.Bible) Script {
+	names := locationNames(b)
+	scenes := make([]Scene, len(sc.Scenes))
+	for i, scn := range sc.Scenes {
+		scn.Location = sceneLocation(scn, names)
+		scenes[i] = scn
+	}
+	sc.Scenes = scenes
+	return sc
+}
+
+func sceneLocation(scn Scene, names map[string]string) string {
+	if scn.LocationRef != "" {
+		return names[strings.ToUpper(scn.LocationRef)]
+	}
+	title := strings.TrimSpace(scn.Title)
+	if n, ok := names[strings.ToUpper(title)]; ok {
+		return n
+	}
+	for _, part := range reTitleSep.Split(title, -1) {
+		part = strings.TrimSpace(reSlugPrefix.ReplaceAllString(strings.TrimSpace(part), ""))
+		if n, ok := names[strings.ToUpper(part)]; ok {
+			return n
+		}
+	}
+	return ""
+}
+
+// ReplaceLocationRef changes the location marker of the scene heading at lineNo (1-based) in src
+// from one name to another, keeping the rest of the line. It reports false when that line has no
+// marker naming from.
+func ReplaceLocationRef(src string, lineNo int, from, to string) (string, bool) {
+	lines := strings.Split(src, "\n")
+	if lineNo < 1 || lineNo > len(lines) {
+		return src, false
+	}
+	ln := lines[lineNo-1]
+	m := reLocRef.FindStringSubmatchIndex(ln)
+	if m == nil || !strings.EqualFold(strings.TrimSpace(ln[m[2]:m[3]]), strings.TrimSpace(from)) {
+		return src, false
+	}
+	lines[lineNo-1] = ln[:m[0]] + "[loc:" + to + "]" + ln[m[1]:]
+	return strings.Join(lines, "\n"), true
+}
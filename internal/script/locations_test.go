@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package script
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestParseLocationMarker(t *testing.T) {
+	sc, errs := Parse("# Rooftop  [loc: Roof ]  – Night\nScene: [LOC:Docks]\n# Chase [loc:]\n")
+	if len(sc.Scenes) != 3 {
+		t.Fatalf("scenes: %+v", sc.Scenes)
+	}
+	if s := sc.Scenes[0]; s.Title != "Rooftop – Night" || s.LocationRef != "Roof" {
+		t.Fatalf("first scene: %+v", s)
+	}
+	if s := sc.Scenes[1]; s.Title != "" || s.LocationRef != "Docks" {
+		t.Fatalf("alternate heading: %+v", s)
+	}
+	if len(errs) != 1 || errs[0].Line != 3 || errs[0].Column != 9 || errs[0].Message != "location reference has no name" {
+		t.Fatalf("errors: %+v", errs)
+	}
+}
+
+func TestLinkLocations(t *testing.T) {
+	bible := domain.Bible{Locations: []domain.BibleLocation{
+		{Name: "Rooftop", Aliases: []string{"Roof"}},
+		{Name: "Back Alley"},
+	}}
+	sc, _ := Parse("# Rooftop – Night\n# Scene 2: INT. BACK ALLEY\n# Chase [loc:roof]\n# Harbor [loc:Harbour]\n# Rooftops\nALICE: Hi\n")
+	sc = LinkLocations(sc, bible)
+	want := []string{"Rooftop", "Back Alley", "Rooftop", "", ""}
+	for i, scn := range sc.Scenes {
+		if scn.Location != want[i] {
+			t.Errorf("scene %q: location %q, want %q", scn.Title, scn.Location, want[i])
+		}
+	}
+
+	ws := ValidateAgainstBible(sc, bible)
+	if len(ws) != 2 || ws[0].Line != 4 || ws[0].Location != "Harbour" || ws[0].Suggestion != "" || ws[0].Character != "" {
+		t.Fatalf("warnings: %+v", ws)
+	}
+	if ws[0].Message != "line 4: unknown location Harbour" || ws[1].Character != "ALICE" {
+		t.Fatalf("warnings: %+v", ws)
+	}
+	sc, _ = Parse("# Chase [loc:Rooftp]\n")
+	if ws := ValidateAgainstBible(sc, bible); len(ws) != 1 || ws[0].Suggestion != "Rooftop" {
+		t.Fatalf("suggestion: %+v", ws)
+	}
+}
+
+func TestReplaceLocationRef(t *testing.T) {
+	src := "# Chase [loc: Rooftp ] – Night\nALICE: Hi"
+	out, ok := ReplaceLocationRef(src, 1, "rooftp", "Rooftop")
+	if !ok || out != "# Chase [loc:Rooftop] – Night\nALICE: Hi" {
+		t.Fatalf("unexpected result %q %v", out, ok)
+	}
+	if _, ok := ReplaceLocationRef(src, 2, "Rooftp", "Rooftop"); ok {
+		t.Fatalf("replaced on a line without a marker")
+	}
+	if _, ok := ReplaceLocationRef(src, 1, "Docks", "Rooftop"); ok {
+		t.Fatalf("replaced a marker naming another location")
+	}
+}
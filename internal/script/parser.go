@@ -10,14 +10,27 @@ package script
 
 import (
 	"bufio"
+	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 )
 
+// Line patterns shared by Parse and HighlightLine. They match the trimmed line.
+var (
+	reScene    = regexp.MustCompile(`^(#+)\s*(.*)$`)
+	reSceneAlt = regexp.MustCompile(`^(?i)\s*Scene:\s*(.+)$`)
+	reName     = regexp.MustCompile(`^([A-Za-z0-9_\- ]{1,64})\s*:\s*(.*)$`)
+	reBeat     = regexp.MustCompile(`^(?i)\s*(Panel\s*\d+|Beat)\b\s*(.*)$`)
+	reTag      = regexp.MustCompile(`(?i)@([a-z0-9_\-]+)`)        // tags like @tag-name
+	reLocRef   = regexp.MustCompile(`(?i)\[\s*loc\s*:([^\]]*)\]`) // location reference in a scene heading
+)
+
 // Parse parses a script text into a structured Script.
 // Supported syntax (minimal):
 // - Scene headings:
 //   - Lines starting with "#" or "Scene:" introduce a new scene. The rest of the line is the title.
+//   - An optional "[loc:NAME]" marker in the heading names the bible location of the scene.
 //
 // - Dialogue: NAME: text  (NAME is captured as Character; converted to upper-case trim)
 //   - Continuation lines indented by 2+ spaces are appended to the previous Dialogue/Caption.
@@ -26,6 +39,10 @@ import (
 // - Beat markers: lines starting with "Panel"/"PANEL" or "Beat"/"BEAT" are classified as LineBeat.
 // - Notes: lines starting with ';' are LineNote.
 // Blank lines are preserved as separators but not represented as lines.
+//
+// Errors carry the 1-based line and column they refer to and are sorted by position. Parsing continues
+// past them: scene headings without a title and dialogue or captions without any text are reported,
+// as is a read failure (e.g. a line longer than the scanner buffer), which ends the parse.
 func Parse(input string) (Script, []Error) {
 	s := Script{Scenes: []Scene{}}
 	var errs []Error
@@ -35,13 +52,6 @@ func Parse(input string) (Script, []Error) {
 	currentScene := Scene{}
 	var lastLine *Line
 
-	// Patterns
-	reScene := regexp.MustCompile(`^(#+)\s*(.*)$`)
-	reSceneAlt := regexp.MustCompile(`^(?i)\s*Scene:\s*(.+)$`)
-	reName := regexp.MustCompile(`^([A-Za-z0-9_\- ]{1,64})\s*:\s*(.*)$`)
-	reBeat := regexp.MustCompile(`^(?i)\s*(Panel\s*\d+|Beat)\b\s*(.*)$`)
-	reTag := regexp.MustCompile(`(?i)@([a-z0-9_\-]+)`) // tags like @tag-name
-
 	extractTags := func(s string) []string {
 		found := reTag.FindAllStringSubmatch(s, -1)
 		if len(found) == 0 {
@@ -67,7 +77,7 @@ func Parse(input string) (Script, []Error) {
 	}
 
 	flushScene := func() {
-		if strings.TrimSpace(currentScene.Title) != "" || len(currentScene.Lines) > 0 {
+		if strings.TrimSpace(currentScene.Title) != "" || currentScene.LocationRef != "" || len(currentScene.Lines) > 0 {
 			s.Scenes = append(s.Scenes, currentScene)
 		}
 	}
@@ -111,13 +121,16 @@ func Parse(input string) (Script, []Error) {
 		if m := reScene.FindStringSubmatch(trim); m != nil {
 			// Flush previous scene
 			flushScene()
-			currentScene = Scene{Title: strings.TrimSpace(m[2])}
+			currentScene = sceneHeading(m[2], lineNo, indentWidth(line)+len(trim)-len(m[2]), &errs)
+			if currentScene.Title == "" && currentScene.LocationRef == "" {
+				errs = append(errs, Error{Line: lineNo, Column: indentWidth(line) + 1, Message: "scene heading has no title"})
+			}
 			lastLine = nil
 			continue
 		}
 		if m := reSceneAlt.FindStringSubmatch(trim); m != nil {
 			flushScene()
-			currentScene = Scene{Title: strings.TrimSpace(m[1])}
+			currentScene = sceneHeading(m[1], lineNo, indentWidth(line)+len(trim)-len(m[1]), &errs)
 			lastLine = nil
 			continue
 		}
@@ -148,7 +161,7 @@ func Parse(input string) (Script, []Error) {
 				lt = LineCaption
 			}
 			tags := extractTags(text)
-			ln := Line{Type: lt, Character: upper, Text: text, Tags: tags, LineNo: lineNo}
+			ln := Line{Type: lt, Character: upper, Text: text, Tags: tags, LineNo: lineNo, column: indentWidth(line) + 1}
 			currentScene.Lines = append(currentScene.Lines, ln)
 			lastLine = &currentScene.Lines[len(currentScene.Lines)-1]
 			continue
@@ -165,8 +178,43 @@ func Parse(input string) (Script, []Error) {
 	// Append last scene
 	flushScene()
 
+	// Dialogue and captions are only complete once their continuation lines are read
+	for _, scn := range s.Scenes {
+		for _, ln := range scn.Lines {
+			if (ln.Type == LineDialogue || ln.Type == LineCaption) && strings.TrimSpace(ln.Text) == "" {
+				errs = append(errs, Error{Line: ln.LineNo, Column: ln.column, Message: fmt.Sprintf("%s has no text", ln.Character)})
+			}
+		}
+	}
 	if err := scanner.Err(); err != nil {
-		errs = append(errs, Error{Line: lineNo, Column: 1, Message: err.Error()})
+		// The scanner stops on the line it could not read, which is the one after the last line counted
+		errs = append(errs, Error{Line: lineNo + 1, Column: 1, Message: err.Error()})
 	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
 	return s, errs
 }
+
+// sceneHeading builds a scene from the title part of its heading, which starts after offset bytes of
+// the line, taking out a location marker. A marker without a name is reported in errs.
+func sceneHeading(title string, lineNo, offset int, errs *[]Error) Scene {
+	scn := Scene{LineNo: lineNo}
+	if loc := reLocRef.FindStringSubmatchIndex(title); loc != nil {
+		scn.LocationRef = strings.TrimSpace(title[loc[2]:loc[3]])
+		if scn.LocationRef == "" {
+			*errs = append(*errs, Error{Line: lineNo, Column: offset + loc[0] + 1, Message: "location reference has no name"})
+		}
+		title = strings.TrimRight(title[:loc[0]], " \t") + " " + strings.TrimLeft(title[loc[1]:], " \t")
+	}
+	scn.Title = strings.TrimSpace(title)
+	return scn
+}
+
+// indentWidth returns the number of leading spaces and tabs of line.
+func indentWidth(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
@@ -8,7 +8,11 @@
 
 package script
 
-import "testing"
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
 
 func TestParseBasicScenesAndDialogue(t *testing.T) {
 	input := `# Opening Scene
@@ -49,6 +53,9 @@ BOB: Hi, Alice.`
 	if s.Scenes[1].Title != "Second Scene" {
 		t.Fatalf("unexpected scene 2 title: %q", s.Scenes[1].Title)
 	}
+	if s.Scenes[0].LineNo != 1 || s.Scenes[1].LineNo != 9 {
+		t.Fatalf("unexpected scene heading lines: %d, %d", s.Scenes[0].LineNo, s.Scenes[1].LineNo)
+	}
 	if len(s.Scenes[1].Lines) != 2 {
 		t.Fatalf("expected 2 lines in scene 2, got %d", len(s.Scenes[1].Lines))
 	}
@@ -75,6 +82,9 @@ Some freeform line`
 	if s.Scenes[0].Title != "Untitled" {
 		t.Fatalf("expected implicit Untitled scene, got %q", s.Scenes[0].Title)
 	}
+	if s.Scenes[0].LineNo != 0 {
+		t.Fatalf("implicit scene has no heading line, got %d", s.Scenes[0].LineNo)
+	}
 	if len(s.Scenes[0].Lines) != 3 { // first unknown should be captured, caption second, then unknown third
 		t.Fatalf("expected 3 lines in scene, got %d", len(s.Scenes[0].Lines))
 	}
@@ -136,3 +146,22 @@ func containsAll(haystack []string, needles []string) bool {
 	}
 	return true
 }
+
+func TestParseErrorsCarryLineNumbers(t *testing.T) {
+	input := "# Opening\nALICE: Hi\n#\n  BOB:\n\nCAPTION:\n  Later that night.\nNARRATION:   \n" + strings.Repeat("x", 70*1024) + "\nCAROL: unreachable"
+	_, errs := Parse(input)
+	want := []Error{
+		{Line: 3, Column: 1, Message: "scene heading has no title"},
+		{Line: 4, Column: 3, Message: "BOB has no text"},
+		{Line: 8, Column: 1, Message: "NARRATION has no text"},
+		{Line: 9, Column: 1, Message: bufio.ErrTooLong.Error()},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("errors = %+v, want %+v", errs, want)
+	}
+	for i := range want {
+		if errs[i] != want[i] {
+			t.Errorf("error %d = %+v, want %+v", i, errs[i], want[i])
+		}
+	}
+}
@@ -17,8 +17,13 @@ type Script struct {
 }
 
 type Scene struct {
-	Title string
-	Lines []Line
+	Title  string
+	Lines  []Line
+	LineNo int // 1-based line number of the scene heading; 0 for lines before the first heading
+	// LocationRef is the name given by a "[loc:NAME]" marker in the heading, as typed; the marker is
+	// not part of Title. Location is the bible location the scene is linked to, set by LinkLocations.
+	LocationRef string
+	Location    string
 }
 
 // LineType indicates the kind of a script line.
@@ -49,9 +54,11 @@ type Line struct {
 	Text      string
 	Tags      []string
 	LineNo    int // 1-based starting line number in the source
+
+	column int // 1-based column of the character cue, for errors
 }
 
-// Error represents a parse error with position context.
+// Error represents a parse error with position context. Line and Column are 1-based.
 
 type Error struct {
 	Line    int
@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package script
+
+import (
+	"fmt"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// Warning is a non-fatal finding about a parsed script, e.g. a speaker missing from the bible.
+type Warning struct {
+	Line       int    // 1-based source line
+	Character  string // the name as parsed (upper-case)
+	Location   string // for an unknown location marker: the name as typed; Character is empty then
+	Suggestion string // closest bible name, empty when nothing is close enough
+	Message    string
+}
+
+// ValidateAgainstBible reports dialogue lines whose speaker is neither a bible character nor one of
+// its aliases, and scene headings whose "[loc:NAME]" marker names no bible location or alias. Names
+// on the bible's ignore list are accepted as deliberate one-off speakers.
+// Comparison is case-insensitive; warnings are in script order.
+func ValidateAgainstBible(sc Script, b domain.Bible) []Warning {
+	known := map[string]bool{}
+	var candidates []string // canonical names and aliases, upper-case
+	for _, c := range b.Characters {
+		for _, n := range append([]string{c.Name}, c.Aliases...) {
+			u := strings.ToUpper(strings.TrimSpace(n))
+			if u == "" || known[u] {
+				continue
+			}
+			known[u] = true
+			candidates = append(candidates, u)
+		}
+	}
+	ignored := map[string]bool{}
+	for _, n := range b.IgnoredCharacters {
+		ignored[strings.ToUpper(strings.TrimSpace(n))] = true
+	}
+	locations := locationNames(b)
+	var locCandidates []string
+	for _, l := range b.Locations {
+		if n := strings.TrimSpace(l.Name); n != "" {
+			locCandidates = append(locCandidates, n)
+		}
+	}
+	var out []Warning
+	for _, scn := range sc.Scenes {
+		if ref := scn.LocationRef; ref != "" && sceneLocation(scn, locations) == "" {
+			w := Warning{Line: scn.LineNo, Location: ref, Suggestion: closestLocation(ref, locCandidates)}
+			if w.Suggestion != "" {
+				w.Message = fmt.Sprintf("line %d: unknown location %s (did you mean %s?)", scn.LineNo, ref, w.Suggestion)
+			} else {
+				w.Message = fmt.Sprintf("line %d: unknown location %s", scn.LineNo, ref)
+			}
+			out = append(out, w)
+		}
+		for _, ln := range scn.Lines {
+			if ln.Type != LineDialogue {
+				continue
+			}
+			name := strings.ToUpper(strings.TrimSpace(ln.Character))
+			if name == "" || known[name] || ignored[name] {
+				continue
+			}
+			w := Warning{Line: ln.LineNo, Character: name, Suggestion: closestName(name, candidates)}
+			if w.Suggestion != "" {
+				w.Message = fmt.Sprintf("line %d: unknown character %s (did you mean %s?)", ln.LineNo, name, w.Suggestion)
+			} else {
+				w.Message = fmt.Sprintf("line %d: unknown character %s", ln.LineNo, name)
+			}
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// closestName returns the candidate with the smallest edit distance to name, provided the distance
+// is small relative to the name's length (at most 2 edits and under half the length).
+func closestName(name string, candidates []string) string {
+	best, bestD := "", -1
+	for _, c := range candidates {
+		d := editDistance(name, c)
+		if bestD < 0 || d < bestD {
+			best, bestD = c, d
+		}
+	}
+	if bestD < 0 || bestD > 2 || bestD*2 >= len([]rune(name)) {
+		return ""
+	}
+	return best
+}
+
+// closestLocation is closestName for location names, which keep their case.
+func closestLocation(name string, candidates []string) string {
+	upper := make([]string, len(candidates))
+	for i, c := range candidates {
+		upper[i] = strings.ToUpper(c)
+	}
+	best := closestName(strings.ToUpper(name), upper)
+	for i, u := range upper {
+		if u == best && best != "" {
+			return candidates[i]
+		}
+	}
+	return ""
+}
+
+// editDistance is the Levenshtein distance between a and b, counted in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// ReplaceCharacter renames the speaker of the dialogue line at lineNo (1-based) in src from one
+// name to another, keeping indentation and the rest of the line. It reports false when that line
+// does not start with the expected name.
+func ReplaceCharacter(src string, lineNo int, from, to string) (string, bool) {
+	lines := strings.Split(src, "\n")
+	if lineNo < 1 || lineNo > len(lines) {
+		return src, false
+	}
+	ln := lines[lineNo-1]
+	body := strings.TrimLeft(ln, " \t")
+	indent := ln[:len(ln)-len(body)]
+	if len(body) < len(from) || !strings.EqualFold(body[:len(from)], from) {
+		return src, false
+	}
+	if !strings.HasPrefix(strings.TrimLeft(body[len(from):], " \t"), ":") {
+		return src, false
+	}
+	lines[lineNo-1] = indent + to + body[len(from):]
+	return strings.Join(lines, "\n"), true
+}
+
+// RenameCharacter renames every dialogue line spoken by from (case-insensitive) to to, using the
+// same rules as ReplaceCharacter. It returns the new source and the number of lines changed.
+func RenameCharacter(src, from, to string) (string, int) {
+	from = strings.TrimSpace(from)
+	if from == "" || strings.EqualFold(from, to) {
+		return src, 0
+	}
+	sc, _ := Parse(src)
+	n := 0
+	for _, scn := range sc.Scenes {
+		for _, ln := range scn.Lines {
+			if ln.Type != LineDialogue || !strings.EqualFold(strings.TrimSpace(ln.Character), from) {
+				continue
+			}
+			if out, ok := ReplaceCharacter(src, ln.LineNo, from, to); ok {
+				src = out
+				n++
+			}
+		}
+	}
+	return src, n
+}
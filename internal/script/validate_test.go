@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package script
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestValidateAgainstBible(t *testing.T) {
+	src := `# Scene
+ALCE: Hi there.
+Alice: Hello.
+BOBBY: Yo.
+CAPTION: Later.
+WAITER: Your soup.
+ZYX: ???`
+	sc, _ := Parse(src)
+	bible := domain.Bible{
+		Characters:        []domain.BibleCharacter{{Name: "Alice"}, {Name: "Robert", Aliases: []string{"Bobby"}}},
+		IgnoredCharacters: []string{"waiter"},
+	}
+	ws := ValidateAgainstBible(sc, bible)
+	if len(ws) != 2 {
+		t.Fatalf("expected 2 warnings, got %+v", ws)
+	}
+	if ws[0].Line != 2 || ws[0].Character != "ALCE" || ws[0].Suggestion != "ALICE" {
+		t.Fatalf("unexpected first warning: %+v", ws[0])
+	}
+	if ws[1].Line != 7 || ws[1].Suggestion != "" {
+		t.Fatalf("unexpected second warning: %+v", ws[1])
+	}
+}
+
+func TestClosestNameThreshold(t *testing.T) {
+	names := []string{"ALICE", "BOB"}
+	if got := closestName("BOX", names); got != "BOB" {
+		t.Fatalf("expected BOB, got %q", got)
+	}
+	if got := closestName("BO", names); got != "" {
+		t.Fatalf("one edit on a 2-letter name is too far, got %q", got)
+	}
+	if got := closestName("ALICEE", names); got != "ALICE" {
+		t.Fatalf("expected ALICE, got %q", got)
+	}
+	if got := editDistance("kitten", "sitting"); got != 3 {
+		t.Fatalf("editDistance = %d", got)
+	}
+}
+
+func TestReplaceCharacter(t *testing.T) {
+	src := "# S\n  alce : Hi\nALCE: again"
+	out, ok := ReplaceCharacter(src, 2, "ALCE", "ALICE")
+	if !ok || out != "# S\n  ALICE : Hi\nALCE: again" {
+		t.Fatalf("unexpected result %q %v", out, ok)
+	}
+	if _, ok := ReplaceCharacter(src, 1, "ALCE", "ALICE"); ok {
+		t.Fatalf("should not replace on a non-matching line")
+	}
+	if _, ok := ReplaceCharacter("ALCEX: hi", 1, "ALCE", "ALICE"); ok {
+		t.Fatalf("should require the name to be followed by a colon")
+	}
+}
+
+func TestRenameCharacter(t *testing.T) {
+	src := "# Scene\nBOB: Hi\nAlice: Hello\nbob: Again\n  BOB: continued\nBOBBY: Not me"
+	out, n := RenameCharacter(src, "Bob", "ROBERT")
+	if n != 2 {
+		t.Fatalf("expected 2 renamed lines, got %d: %q", n, out)
+	}
+	want := "# Scene\nROBERT: Hi\nAlice: Hello\nROBERT: Again\n  BOB: continued\nBOBBY: Not me"
+	if out != want {
+		t.Fatalf("unexpected result:\n%q\nwant\n%q", out, want)
+	}
+	if _, n := RenameCharacter(src, "BOB", "bob"); n != 0 {
+		t.Fatalf("case-only rename should be a no-op, got %d", n)
+	}
+}
@@ -0,0 +1,295 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"sort"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/script"
+)
+
+// PageRef is a page of the project: the issue index in Project.Issues and the page number.
+type PageRef struct {
+	Issue int
+	Page  int
+}
+
+// SceneRef is a scene heading of the script.
+type SceneRef struct {
+	Title  string
+	LineNo int
+}
+
+// Appearance lists where one bible character, location or tag shows up. Count is the number of
+// dialogue lines of a character, of scenes linked to a location, or of lines carrying a tag. Scenes
+// are in script order, pages by issue and page number; a page is where a beat of the scene is mapped,
+// narrowed for lines to the beat they follow when there is one.
+type Appearance struct {
+	Name   string
+	Count  int
+	Scenes []SceneRef
+	Pages  []PageRef
+}
+
+// CoAppearance is a character sharing scenes with another one.
+type CoAppearance struct {
+	Name         string
+	SharedScenes int
+}
+
+// AppearanceReport holds the appearances of every bible entity, plus speakers, locations and tags the
+// script uses without a bible entry. Each list is ordered by Count, highest first, then by name;
+// bible entries that never appear are included with a zero Count.
+type AppearanceReport struct {
+	Characters []Appearance
+	Locations  []Appearance
+	Tags       []Appearance
+
+	cast [][]int // indexes into Characters of the speakers of each scene of the script
+}
+
+// appearanceSet collects the appearances of one kind of entity by name.
+type appearanceSet struct {
+	list   []Appearance
+	byName map[string]int           // name → index in list
+	scene  map[int]int              // index → last scene line added, so a scene is listed once
+	pages  map[int]map[PageRef]bool // index → pages already listed
+}
+
+func newAppearanceSet() *appearanceSet {
+	return &appearanceSet{byName: map[string]int{}, scene: map[int]int{}, pages: map[int]map[PageRef]bool{}}
+}
+
+// entry returns the index of name, adding it when new.
+func (s *appearanceSet) entry(name string) int {
+	if i, ok := s.byName[name]; ok {
+		return i
+	}
+	s.list = append(s.list, Appearance{Name: name})
+	s.byName[name] = len(s.list) - 1
+	return len(s.list) - 1
+}
+
+// add records one appearance of entry i in scn on pages.
+func (s *appearanceSet) add(i int, scn script.Scene, pages []PageRef) {
+	a := &s.list[i]
+	a.Count++
+	if scn.LineNo > 0 && s.scene[i] != scn.LineNo {
+		s.scene[i] = scn.LineNo
+		a.Scenes = append(a.Scenes, SceneRef{Title: scn.Title, LineNo: scn.LineNo})
+	}
+	seen := s.pages[i]
+	if seen == nil {
+		seen = map[PageRef]bool{}
+		s.pages[i] = seen
+	}
+	for _, p := range pages {
+		if !seen[p] {
+			seen[p] = true
+			a.Pages = append(a.Pages, p)
+		}
+	}
+}
+
+// sorted orders the pages of every entry and returns the entries by Count, then name. The index
+// remap old → new is returned for lists that refer to entries.
+func (s *appearanceSet) sorted() ([]Appearance, []int) {
+	for i := range s.list {
+		pages := s.list[i].Pages
+		sort.Slice(pages, func(a, b int) bool {
+			if pages[a].Issue != pages[b].Issue {
+				return pages[a].Issue < pages[b].Issue
+			}
+			return pages[a].Page < pages[b].Page
+		})
+	}
+	order := make([]int, len(s.list))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		x, y := s.list[order[a]], s.list[order[b]]
+		if x.Count != y.Count {
+			return x.Count > y.Count
+		}
+		return x.Name < y.Name
+	})
+	out := make([]Appearance, len(order))
+	remap := make([]int, len(order))
+	for k, i := range order {
+		out[k] = s.list[i]
+		remap[i] = k
+	}
+	return out, remap
+}
+
+// ComputeAppearances derives where the characters, locations and tags of the bible appear from the
+// script (dialogue cues, @tags and scene location links) and the beats mapped to panels. Names and
+// aliases are matched case-insensitively. Nothing is stored; the work is linear in the size of the
+// script and the project.
+func ComputeAppearances(p domain.Project, sc script.Script) AppearanceReport {
+	sc = script.LinkLocations(sc, p.Bible)
+	beatPages := map[string][]PageRef{}
+	for ii, iss := range p.Issues {
+		for _, pg := range iss.Pages {
+			for _, pn := range pg.Panels {
+				for _, id := range pn.BeatIDs {
+					if id != "" {
+						beatPages[id] = append(beatPages[id], PageRef{Issue: ii, Page: pg.Number})
+					}
+				}
+			}
+		}
+	}
+
+	chars, locs, tags := newAppearanceSet(), newAppearanceSet(), newAppearanceSet()
+	charNames := map[string]string{} // upper-case name or alias → bible name
+	for _, c := range p.Bible.Characters {
+		name := strings.TrimSpace(c.Name)
+		if name == "" {
+			continue
+		}
+		chars.entry(name)
+		for _, n := range append([]string{name}, c.Aliases...) {
+			if u := strings.ToUpper(strings.TrimSpace(n)); u != "" {
+				if _, dup := charNames[u]; !dup {
+					charNames[u] = name
+				}
+			}
+		}
+	}
+	for _, l := range p.Bible.Locations {
+		if name := strings.TrimSpace(l.Name); name != "" {
+			locs.entry(name)
+		}
+	}
+	tagNames := map[string]string{} // lower-case name without '@' → bible name
+	for _, t := range p.Bible.Tags {
+		if name := strings.TrimSpace(t.Name); name != "" {
+			tags.entry(name)
+			tagNames[strings.ToLower(strings.TrimPrefix(name, "@"))] = name
+		}
+	}
+
+	var cast [][]int
+	for _, scn := range sc.Scenes {
+		var scenePages []PageRef
+		for _, ln := range scn.Lines {
+			if ln.Type == script.LineBeat {
+				scenePages = append(scenePages, beatPages[BeatIDFor(ln)]...)
+			}
+		}
+		if scn.Location != "" {
+			locs.add(locs.entry(scn.Location), scn, scenePages)
+		}
+		var speakers []int
+		inScene := map[int]bool{}
+		linePages := scenePages // lines before the scene's first beat belong to all its pages
+		for _, ln := range scn.Lines {
+			if ln.Type == script.LineBeat {
+				linePages = beatPages[BeatIDFor(ln)]
+			}
+			if ln.Type == script.LineDialogue {
+				cue := strings.ToUpper(strings.TrimSpace(ln.Character))
+				name, ok := charNames[cue]
+				if !ok {
+					name = cue
+				}
+				if name != "" {
+					i := chars.entry(name)
+					chars.add(i, scn, linePages)
+					if !inScene[i] {
+						inScene[i] = true
+						speakers = append(speakers, i)
+					}
+				}
+			}
+			for _, t := range ln.Tags {
+				name, ok := tagNames[t]
+				if !ok {
+					name = "@" + t
+				}
+				tags.add(tags.entry(name), scn, linePages)
+			}
+		}
+		if scn.LineNo > 0 {
+			cast = append(cast, speakers)
+		}
+	}
+
+	var r AppearanceReport
+	var remap []int
+	r.Characters, remap = chars.sorted()
+	r.Locations, _ = locs.sorted()
+	r.Tags, _ = tags.sorted()
+	for _, speakers := range cast {
+		for k, i := range speakers {
+			speakers[k] = remap[i]
+		}
+	}
+	r.cast = cast
+	return r
+}
+
+// Character returns the appearances of the character named name (case-insensitive).
+func (r AppearanceReport) Character(name string) (Appearance, bool) {
+	if i := r.characterIndex(name); i >= 0 {
+		return r.Characters[i], true
+	}
+	return Appearance{}, false
+}
+
+func (r AppearanceReport) characterIndex(name string) int {
+	for i, a := range r.Characters {
+		if strings.EqualFold(a.Name, strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// CoAppearances returns the characters speaking in the same scenes as the character named name,
+// ranked by the number of scenes they share, then by name.
+func (r AppearanceReport) CoAppearances(name string) []CoAppearance {
+	me := r.characterIndex(name)
+	if me < 0 {
+		return nil
+	}
+	shared := map[int]int{}
+	for _, speakers := range r.cast {
+		found := false
+		for _, i := range speakers {
+			if i == me {
+				found = true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+		for _, i := range speakers {
+			if i != me {
+				shared[i]++
+			}
+		}
+	}
+	out := make([]CoAppearance, 0, len(shared))
+	for i, n := range shared {
+		out = append(out, CoAppearance{Name: r.Characters[i].Name, SharedScenes: n})
+	}
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].SharedScenes != out[b].SharedScenes {
+			return out[a].SharedScenes > out[b].SharedScenes
+		}
+		return out[a].Name < out[b].Name
+	})
+	return out
+}
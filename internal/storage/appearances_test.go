@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/script"
+)
+
+func TestComputeAppearances(t *testing.T) {
+	sc, errs := script.Parse(`# Rooftop – Night
+ALICE: Hi. @storm
+Panel 1
+BOB: Hello.
+Panel 2
+ALI: Bye.
+# Cellar [loc:Basement]
+Panel 1
+BOB: Alone. @storm
+CAROL: Not quite.
+# Street
+Panel 1
+CAROL: Hey.
+ALICE: Hi.`)
+	if len(errs) > 0 {
+		t.Fatalf("parse: %+v", errs)
+	}
+	p := domain.Project{
+		Bible: domain.Bible{
+			Characters: []domain.BibleCharacter{{Name: "Alice", Aliases: []string{"Ali"}}, {Name: "Bob"}, {Name: "Dave"}},
+			Locations:  []domain.BibleLocation{{Name: "Rooftop"}, {Name: "Basement"}, {Name: "Harbor"}},
+			Tags:       []domain.BibleTag{{Name: "@storm"}},
+		},
+		Issues: []domain.Issue{{Pages: []domain.Page{
+			{Number: 1, Panels: []domain.Panel{{ID: "p1", BeatIDs: []string{"b:3"}}}},
+			{Number: 2, Panels: []domain.Panel{{ID: "p1", BeatIDs: []string{"b:5"}}, {ID: "p2", BeatIDs: []string{"b:8"}}}},
+		}}, {Pages: []domain.Page{
+			{Number: 1, Panels: []domain.Panel{{ID: "p1", BeatIDs: []string{"b:12"}}}},
+		}}},
+	}
+	rep := ComputeAppearances(p, sc)
+
+	names := func(as []Appearance) []string {
+		var out []string
+		for _, a := range as {
+			out = append(out, a.Name)
+		}
+		return out
+	}
+	if got, want := names(rep.Characters), []string{"Alice", "Bob", "CAROL", "Dave"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("characters = %v, want %v", got, want)
+	}
+	alice, ok := rep.Character("alice")
+	if !ok || alice.Count != 3 {
+		t.Fatalf("alice = %+v", alice)
+	}
+	if want := []SceneRef{{Title: "Rooftop – Night", LineNo: 1}, {Title: "Street", LineNo: 11}}; !reflect.DeepEqual(alice.Scenes, want) {
+		t.Fatalf("alice scenes = %+v", alice.Scenes)
+	}
+	// "Hi." comes before the first beat, so it counts on every page of the scene; "Bye." follows Panel 2.
+	if want := []PageRef{{0, 1}, {0, 2}, {1, 1}}; !reflect.DeepEqual(alice.Pages, want) {
+		t.Fatalf("alice pages = %+v", alice.Pages)
+	}
+	bob, _ := rep.Character("Bob")
+	if want := []PageRef{{0, 1}, {0, 2}}; bob.Count != 2 || !reflect.DeepEqual(bob.Pages, want) {
+		t.Fatalf("bob = %+v", bob)
+	}
+	if dave, ok := rep.Character("Dave"); !ok || dave.Count != 0 || dave.Pages != nil {
+		t.Fatalf("dave = %+v %v", dave, ok)
+	}
+	if got, want := rep.CoAppearances("Alice"), []CoAppearance{{"Bob", 1}, {"CAROL", 1}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("co-appearances of Alice = %+v", got)
+	}
+	if got, want := rep.CoAppearances("CAROL"), []CoAppearance{{"Alice", 1}, {"Bob", 1}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("co-appearances of Carol = %+v", got)
+	}
+	if rep.CoAppearances("nobody") != nil {
+		t.Fatalf("unknown character should have no co-appearances")
+	}
+
+	if got, want := names(rep.Locations), []string{"Basement", "Rooftop", "Harbor"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("locations = %v, want %v", got, want)
+	}
+	if l := rep.Locations[0]; l.Count != 1 || !reflect.DeepEqual(l.Pages, []PageRef{{0, 2}}) {
+		t.Fatalf("basement = %+v", l)
+	}
+	if got := rep.Tags; len(got) != 1 || got[0].Name != "@storm" || got[0].Count != 2 || len(got[0].Scenes) != 2 {
+		t.Fatalf("tags = %+v", got)
+	}
+}
+
+func TestComputeAppearancesLargeScript(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 5000; i++ {
+		b.WriteString("# Scene\nPanel 1\nALICE: a\nBOB: b\n")
+	}
+	sc, _ := script.Parse(b.String())
+	rep := ComputeAppearances(domain.Project{}, sc)
+	if got := rep.CoAppearances("ALICE"); len(got) != 1 || got[0].SharedScenes != 5000 {
+		t.Fatalf("co-appearances = %+v", got)
+	}
+}
@@ -0,0 +1,389 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	applog "gocomicwriter/internal/log"
+	"gocomicwriter/internal/version"
+)
+
+// A project archive (.gcwz) is a zip of a project for sharing: the manifest and the script, assets,
+// styles and pages folders, optionally the latest manifest backup, and a bundle manifest at the root.
+// The index (.gcw), the other backups and exports are left out; the index is rebuilt after import.
+const (
+	ArchiveExt          = ".gcwz"
+	ArchiveManifestName = "gcwz.json"
+	archiveFormat       = 1
+)
+
+// archiveDirs are the project folders an archive carries besides the manifest.
+var archiveDirs = []string{"script", "assets", "styles", "pages"}
+
+// ArchiveManifest describes a project archive. Checksum covers the name and SHA-256 of every other
+// entry, so ImportArchive notices damaged, missing and added files.
+type ArchiveManifest struct {
+	Format     int       `json:"format"`
+	AppVersion string    `json:"appVersion"`
+	Created    time.Time `json:"created"`
+	Project    string    `json:"project"`
+	Files      int       `json:"files"`
+	// Backup is the entry name of the included latest manifest backup, if any.
+	Backup   string `json:"backup,omitempty"`
+	Checksum string `json:"checksum"`
+}
+
+// ArchiveOptions selects optional archive content.
+type ArchiveOptions struct {
+	// IncludeLatestBackup adds the newest manifest backup, as a full copy even when it is stored as a delta.
+	IncludeLatestBackup bool
+}
+
+// archiveChecksum combines per-entry SHA-256 sums, keyed by entry name, into the archive checksum.
+func archiveChecksum(sums map[string]string) string {
+	names := make([]string, 0, len(sums))
+	for n := range sums {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, n := range names {
+		_, _ = fmt.Fprintf(h, "%s\x00%s\n", n, sums[n])
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// ExportArchive writes the project of ph, as it is in memory, to a project archive at dest. Files are
+// streamed into the archive, which is written to a temporary file first and then moved into place.
+func ExportArchive(ph *ProjectHandle, dest string, opts ArchiveOptions) (ArchiveManifest, error) {
+	l := applog.WithOperation(applog.WithComponent("storage"), "export_archive").With(slog.String("dest", dest))
+	if ph == nil {
+		return ArchiveManifest{}, errors.New("nil ProjectHandle")
+	}
+	if strings.TrimSpace(dest) == "" {
+		return ArchiveManifest{}, errors.New("archive path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return ArchiveManifest{}, fmt.Errorf("ensure archive dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".tmp-*")
+	if err != nil {
+		return ArchiveManifest{}, fmt.Errorf("create archive: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }() // gone after the rename
+	m, err := writeArchive(tmp, ph, opts)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		l.Error("write archive failed", slog.Any("err", err))
+		return ArchiveManifest{}, fmt.Errorf("write archive: %w", err)
+	}
+	if err := replaceFile(tmp.Name(), dest); err != nil {
+		return ArchiveManifest{}, fmt.Errorf("replace archive: %w", err)
+	}
+	l.Info("project archive exported", slog.Int("files", m.Files))
+	return m, nil
+}
+
+// writeArchive writes the archive content to w and returns its bundle manifest.
+func writeArchive(w io.Writer, ph *ProjectHandle, opts ArchiveOptions) (ArchiveManifest, error) {
+	zw := zip.NewWriter(w)
+	sums := map[string]string{}
+	add := func(name string, r io.Reader, mod time.Time) error {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: mod})
+		if err != nil {
+			return fmt.Errorf("add %s: %w", name, err)
+		}
+		h := sha256.New()
+		if _, err := io.Copy(io.MultiWriter(fw, h), r); err != nil {
+			return fmt.Errorf("add %s: %w", name, err)
+		}
+		sums[name] = hex.EncodeToString(h.Sum(nil))
+		return nil
+	}
+	now := time.Now()
+	m := ArchiveManifest{Format: archiveFormat, AppVersion: version.String(), Created: now.UTC(), Project: ph.Project.Name}
+
+	data, err := json.MarshalIndent(ph.Project, "", "  ")
+	if err != nil {
+		return m, fmt.Errorf("marshal manifest: %w", err)
+	}
+	if err := add(ManifestFileName, strings.NewReader(string(data)+"\n"), now); err != nil {
+		return m, err
+	}
+	for _, dir := range archiveDirs {
+		err := filepath.WalkDir(filepath.Join(ph.Root, dir), func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil // folders are implied; links are not followed
+			}
+			rel, err := filepath.Rel(ph.Root, p)
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			f, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			return add(filepath.ToSlash(rel), f, info.ModTime())
+		})
+		if err != nil {
+			return m, err
+		}
+	}
+	if opts.IncludeLatestBackup {
+		backups, err := ListBackups(ph.Root)
+		if err != nil {
+			return m, err
+		}
+		if len(backups) > 0 {
+			b := backups[0]
+			p, _, err := loadBackupProject(ph.Root, b.Path)
+			if err != nil {
+				return m, fmt.Errorf("read latest backup: %w", err)
+			}
+			data, err := json.MarshalIndent(p, "", "  ")
+			if err != nil {
+				return m, fmt.Errorf("marshal latest backup: %w", err)
+			}
+			m.Backup = BackupsDirName + "/" + ManifestFileName + "." + backupStamp(b.Path) + backupFullSuffix
+			if err := add(m.Backup, strings.NewReader(string(data)+"\n"), b.Time); err != nil {
+				return m, err
+			}
+		}
+	}
+
+	m.Files = len(sums)
+	m.Checksum = archiveChecksum(sums)
+	mw, err := zw.Create(ArchiveManifestName)
+	if err != nil {
+		return m, fmt.Errorf("add bundle manifest: %w", err)
+	}
+	enc := json.NewEncoder(mw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		return m, fmt.Errorf("write bundle manifest: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return m, fmt.Errorf("finish archive: %w", err)
+	}
+	return m, nil
+}
+
+// archiveEntryOK reports whether an archive entry name is a clean relative path to the manifest, a
+// file in one of archiveDirs or the bundle's backup, so extracting it cannot leave the project folder.
+func archiveEntryOK(name string, m ArchiveManifest) bool {
+	if name == "" || strings.Contains(name, "\\") || strings.Contains(name, ":") || path.IsAbs(name) || path.Clean(name) != name {
+		return false
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." || part == "." {
+			return false
+		}
+	}
+	if name == ManifestFileName || (m.Backup != "" && name == m.Backup) {
+		return true
+	}
+	top, _, _ := strings.Cut(name, "/")
+	for _, d := range archiveDirs {
+		if top == d && name != d {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadArchiveManifest returns the bundle manifest of the archive at src without checking the content.
+func ReadArchiveManifest(src string) (ArchiveManifest, error) {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return ArchiveManifest{}, fmt.Errorf("%w: %w", ErrArchiveCorrupt, err)
+	}
+	defer func() { _ = r.Close() }()
+	return readArchiveManifest(&r.Reader)
+}
+
+func readArchiveManifest(r *zip.Reader) (ArchiveManifest, error) {
+	var m ArchiveManifest
+	for _, f := range r.File {
+		if f.Name != ArchiveManifestName {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return m, fmt.Errorf("%w: %w", ErrArchiveCorrupt, err)
+		}
+		defer func() { _ = rc.Close() }()
+		if err := json.NewDecoder(rc).Decode(&m); err != nil {
+			return m, fmt.Errorf("%w: bundle manifest: %w", ErrArchiveCorrupt, err)
+		}
+		if m.Format > archiveFormat {
+			return m, fmt.Errorf("%w: format %d is newer than this app's %d", ErrArchiveCorrupt, m.Format, archiveFormat)
+		}
+		return m, nil
+	}
+	return m, fmt.Errorf("%w: no %s", ErrArchiveCorrupt, ArchiveManifestName)
+}
+
+// ImportArchive checks the project archive at src and extracts it into dest, which must be a new or
+// empty folder; open the project there afterwards. Entries that would land outside the project
+// folder are refused with ErrArchiveUnsafePath and a checksum mismatch with ErrArchiveCorrupt, both
+// before anything is written. If extracting fails, dest is emptied again.
+func ImportArchive(src, dest string) (ArchiveManifest, error) {
+	l := applog.WithOperation(applog.WithComponent("storage"), "import_archive").With(slog.String("src", src), slog.String("dest", dest))
+	if strings.TrimSpace(dest) == "" {
+		return ArchiveManifest{}, errors.New("destination folder is required")
+	}
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return ArchiveManifest{}, fmt.Errorf("%w: %w", ErrArchiveCorrupt, err)
+	}
+	defer func() { _ = r.Close() }()
+	m, err := readArchiveManifest(&r.Reader)
+	if err != nil {
+		return m, err
+	}
+
+	// Check every entry before writing anything
+	sums := map[string]string{}
+	var files []*zip.File
+	for _, f := range r.File {
+		if f.Name == ArchiveManifestName || (f.FileInfo().IsDir() && archiveEntryOK(strings.TrimSuffix(f.Name, "/"), m)) {
+			continue
+		}
+		if !archiveEntryOK(f.Name, m) {
+			return m, fmt.Errorf("%w: %q", ErrArchiveUnsafePath, f.Name)
+		}
+		if _, dup := sums[f.Name]; dup {
+			return m, fmt.Errorf("%w: duplicate entry %q", ErrArchiveCorrupt, f.Name)
+		}
+		sum, err := zipEntrySum(f)
+		if err != nil {
+			return m, fmt.Errorf("%w: %s: %w", ErrArchiveCorrupt, f.Name, err)
+		}
+		sums[f.Name] = sum
+		files = append(files, f)
+	}
+	if _, ok := sums[ManifestFileName]; !ok {
+		return m, fmt.Errorf("%w: no %s", ErrArchiveCorrupt, ManifestFileName)
+	}
+	if got := archiveChecksum(sums); got != m.Checksum {
+		return m, fmt.Errorf("%w: checksum %s, want %s", ErrArchiveCorrupt, got, m.Checksum)
+	}
+
+	if ents, err := os.ReadDir(dest); err == nil && len(ents) > 0 {
+		return m, fmt.Errorf("%w: %s is not empty", fs.ErrExist, dest)
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return m, fmt.Errorf("read destination: %w", err)
+	}
+	if err := extractArchive(files, dest); err != nil {
+		l.Error("extract archive failed", slog.Any("err", err))
+		emptyDir(dest)
+		return m, err
+	}
+	l.Info("project archive imported", slog.Int("files", len(files)))
+	return m, nil
+}
+
+// zipEntrySum returns the hex SHA-256 of an archive entry's content.
+func zipEntrySum(f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = rc.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// extractArchive writes the checked entries below dest and scaffolds the standard project folders.
+func extractArchive(files []*zip.File, dest string) error {
+	for _, d := range standardSubDirs {
+		if err := os.MkdirAll(filepath.Join(dest, d), 0o755); err != nil {
+			return fmt.Errorf("create subdir %s: %w", d, err)
+		}
+	}
+	for _, f := range files {
+		target := filepath.Join(dest, filepath.FromSlash(f.Name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return fmt.Errorf("extract %s: %w", f.Name, err)
+		}
+		if err := extractZipFile(f, target); err != nil {
+			return fmt.Errorf("extract %s: %w", f.Name, err)
+		}
+		if !f.Modified.IsZero() {
+			_ = os.Chtimes(target, f.Modified, f.Modified)
+		}
+	}
+	return nil
+}
+
+func extractZipFile(f *zip.File, target string) (err error) {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	_, err = io.Copy(out, rc)
+	return err
+}
+
+// emptyDir removes everything inside dir, best effort.
+func emptyDir(dir string) {
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range ents {
+		_ = os.RemoveAll(filepath.Join(dir, e.Name()))
+	}
+}
@@ -0,0 +1,266 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+// archiveProject creates a saved project with files in every archived folder and in the ones an
+// archive leaves out.
+func archiveProject(t *testing.T) *ProjectHandle {
+	t.Helper()
+	root := t.TempDir()
+	ph, err := InitProject(root, domain.Project{Name: "Archive", Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1,
+		Panels: []domain.Panel{{ID: "p1", Geometry: domain.Rect{X: 10, Y: 10, Width: 100, Height: 80}}}}}}}})
+	if err != nil {
+		t.Fatalf("InitProject: %v", err)
+	}
+	t.Cleanup(func() { _ = ph.Close() })
+	big := bytes.Repeat([]byte{0, 1, 2, 3, 250, 251}, 200_000)
+	files := map[string][]byte{
+		"assets/refs/big.bin":       big,
+		"assets/logo.png":           []byte("\x89PNG fake"),
+		"script/script.txt":         []byte("PAGE 1\nPanel 1"),
+		"styles/palettes.json":      []byte(`{"palettes":[]}`),
+		"pages/page-1.png":          []byte("page art"),
+		"exports/issue-1.pdf":       []byte("%PDF"),
+		".gcw/extra.txt":            []byte("index side file"),
+		"backups/unrelated-file.md": []byte("notes"),
+	}
+	for name, data := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A second save leaves a backup of the first manifest
+	ph.Project.Issues[0].Pages[0].Panels[0].Notes = "saved twice"
+	if err := Save(ph); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return ph
+}
+
+// fileHashes returns the SHA-256 of every file below root/dir, keyed by slash path relative to root.
+func fileHashes(t *testing.T, root, dir string) map[string]string {
+	t.Helper()
+	out := map[string]string{}
+	err := filepath.WalkDir(filepath.Join(root, dir), func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, p)
+		sum := sha256.Sum256(data)
+		out[filepath.ToSlash(rel)] = hex.EncodeToString(sum[:])
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("hash %s: %v", dir, err)
+	}
+	return out
+}
+
+func TestArchive_RoundTrip(t *testing.T) {
+	ph := archiveProject(t)
+	// Unsaved edits are archived as they are in memory
+	ph.Project.Issues[0].Pages[0].Panels[0].Notes = "not saved yet"
+	dest := filepath.Join(t.TempDir(), "share"+ArchiveExt)
+	m, err := ExportArchive(ph, dest, ArchiveOptions{IncludeLatestBackup: true})
+	if err != nil {
+		t.Fatalf("ExportArchive: %v", err)
+	}
+	if m.Project != "Archive" || m.AppVersion == "" || m.Backup == "" || !strings.HasPrefix(m.Checksum, "sha256:") {
+		t.Fatalf("manifest = %+v", m)
+	}
+
+	zr, err := zip.OpenReader(dest)
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	for _, f := range zr.File {
+		if strings.HasPrefix(f.Name, ".gcw/") || strings.HasPrefix(f.Name, "exports/") || f.Name == "backups/unrelated-file.md" {
+			t.Errorf("archive contains %s", f.Name)
+		}
+	}
+	if got := len(zr.File); got != m.Files+1 {
+		t.Errorf("archive has %d entries, want %d files and the bundle manifest", got, m.Files)
+	}
+	_ = zr.Close()
+
+	root := filepath.Join(t.TempDir(), "imported")
+	got, err := ImportArchive(dest, root)
+	if err != nil {
+		t.Fatalf("ImportArchive: %v", err)
+	}
+	if got.Checksum != m.Checksum {
+		t.Errorf("imported checksum %s, want %s", got.Checksum, m.Checksum)
+	}
+	imported, err := Open(root)
+	if err != nil {
+		t.Fatalf("Open imported: %v", err)
+	}
+	t.Cleanup(func() { _ = imported.Close() })
+	if !reflect.DeepEqual(imported.Project, ph.Project) {
+		t.Errorf("imported project differs:\n got %+v\nwant %+v", imported.Project, ph.Project)
+	}
+	for _, dir := range archiveDirs {
+		if want, got := fileHashes(t, ph.Root, dir), fileHashes(t, root, dir); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s differs:\n got %v\nwant %v", dir, got, want)
+		}
+	}
+	// The latest backup comes along as a full copy of the manifest saved before
+	backups, err := ListBackups(root)
+	if err != nil || len(backups) != 1 || backups[0].Delta {
+		t.Fatalf("imported backups = %+v, %v", backups, err)
+	}
+	bp, _, err := LoadBackupProject(root, backups[0].Path)
+	if err != nil || bp.Issues[0].Pages[0].Panels[0].Notes != "" {
+		t.Fatalf("imported backup = %+v, %v", bp, err)
+	}
+	for _, d := range standardSubDirs {
+		if _, err := os.Stat(filepath.Join(root, d)); err != nil {
+			t.Errorf("imported project lacks %s: %v", d, err)
+		}
+	}
+}
+
+// rewriteArchive copies the archive at src to a new file, passing every entry through edit, which may
+// change its name and content or drop it by returning an empty name.
+func rewriteArchive(t *testing.T, src string, edit func(name string, data []byte) (string, []byte)) string {
+	t.Helper()
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = zr.Close() }()
+	out := filepath.Join(t.TempDir(), "edited"+ArchiveExt)
+	f, err := os.Create(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, e := range zr.File {
+		rc, err := e.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		name, data := edit(e.Name, data)
+		if name == "" {
+			continue
+		}
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+	return out
+}
+
+func TestImportArchive_Refuses(t *testing.T) {
+	ph := archiveProject(t)
+	src := filepath.Join(t.TempDir(), "share"+ArchiveExt)
+	if _, err := ExportArchive(ph, src, ArchiveOptions{}); err != nil {
+		t.Fatalf("ExportArchive: %v", err)
+	}
+	tampered := rewriteArchive(t, src, func(name string, data []byte) (string, []byte) {
+		if name == "script/script.txt" {
+			return name, []byte("PAGE 1\nPanel 2")
+		}
+		return name, data
+	})
+	missing := rewriteArchive(t, src, func(name string, data []byte) (string, []byte) {
+		if name == "assets/logo.png" {
+			return "", nil
+		}
+		return name, data
+	})
+	noManifest := rewriteArchive(t, src, func(name string, data []byte) (string, []byte) {
+		if name == ArchiveManifestName {
+			return "", nil
+		}
+		return name, data
+	})
+	unsafe := func(bad string) string {
+		return rewriteArchive(t, src, func(name string, data []byte) (string, []byte) {
+			if name == "assets/logo.png" {
+				return bad, data
+			}
+			return name, data
+		})
+	}
+	cases := []struct {
+		name string
+		src  string
+		want error
+	}{
+		{"tampered", tampered, ErrArchiveCorrupt},
+		{"missing file", missing, ErrArchiveCorrupt},
+		{"no bundle manifest", noManifest, ErrArchiveCorrupt},
+		{"parent dir", unsafe("../evil.png"), ErrArchiveUnsafePath},
+		{"nested parent dir", unsafe("assets/../../evil.png"), ErrArchiveUnsafePath},
+		{"absolute", unsafe("/tmp/evil.png"), ErrArchiveUnsafePath},
+		{"backslash", unsafe(`assets\..\..\evil.png`), ErrArchiveUnsafePath},
+		{"outside the project folders", unsafe(".gcw/index.sqlite"), ErrArchiveUnsafePath},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			parent := t.TempDir()
+			dest := filepath.Join(parent, "imported")
+			_, err := ImportArchive(tc.src, dest)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("ImportArchive = %v, want %v", err, tc.want)
+			}
+			if _, err := os.Stat(dest); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("destination was created: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(parent, "evil.png")); err == nil {
+				t.Errorf("entry escaped the destination")
+			}
+		})
+	}
+
+	// A folder that already holds files is not overwritten
+	dest := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dest, "keep.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportArchive(src, dest); !errors.Is(err, fs.ErrExist) {
+		t.Fatalf("import into a non-empty folder = %v, want fs.ErrExist", err)
+	}
+}
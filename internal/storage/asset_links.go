@@ -0,0 +1,302 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// assetNotePrefix marks a placed asset in panel notes, one per line.
+const assetNotePrefix = "asset:"
+
+// Kinds of asset references in the manifest.
+const (
+	AssetRefPanelNote = "panel_note"      // an "asset:<path>" line in a panel's notes
+	AssetRefPageImage = "reference_image" // a page's reference or art image
+	AssetRefPortrait  = "portrait"        // a bible character's portrait
+)
+
+// AssetRef is one reference to an asset file in the manifest. Path is stored as written, which for
+// older projects saved on Windows may use backslashes.
+type AssetRef struct {
+	Kind       string
+	Path       string
+	IssueIndex int    // panel notes and page images
+	PageNumber int    // panel notes and page images
+	PanelID    string // panel notes
+	Character  string // portraits
+}
+
+// NormalizeAssetPath returns p with forward slashes and without "." or ".." detours, the form asset
+// paths are stored in. Backslashes are taken as separators on every platform, so a path written on
+// Windows resolves on macOS and Linux too.
+func NormalizeAssetPath(p string) string {
+	p = strings.TrimSpace(strings.ReplaceAll(p, `\`, "/"))
+	if p == "" {
+		return ""
+	}
+	return path.Clean(p)
+}
+
+// AssetRelPath returns the stored form of the asset file p: relative to root when p lies inside the
+// project, otherwise p itself, normalized with NormalizeAssetPath.
+func AssetRelPath(root, p string) string {
+	if filepath.IsAbs(p) {
+		if rel, err := filepath.Rel(root, p); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			p = rel
+		}
+	}
+	return NormalizeAssetPath(filepath.ToSlash(p))
+}
+
+// AssetFilePath resolves a stored asset path against the project root. Both slash styles are accepted.
+func AssetFilePath(root, rel string) string {
+	p := NormalizeAssetPath(rel)
+	if p == "" {
+		return ""
+	}
+	fp := filepath.FromSlash(p)
+	if filepath.IsAbs(fp) {
+		return fp
+	}
+	return filepath.Join(root, fp)
+}
+
+// rewriteAssetRefs calls fn for every asset reference in p; when fn returns a new path and true, the
+// reference is replaced. It returns the number of replaced references.
+func rewriteAssetRefs(p *domain.Project, fn func(AssetRef) (string, bool)) int {
+	n := 0
+	for ii := range p.Issues {
+		for pi := range p.Issues[ii].Pages {
+			pg := &p.Issues[ii].Pages[pi]
+			if ref := pg.ReferenceImage; ref != nil && strings.TrimSpace(ref.Asset) != "" {
+				if np, ok := fn(AssetRef{Kind: AssetRefPageImage, Path: ref.Asset, IssueIndex: ii, PageNumber: pg.Number}); ok {
+					ref.Asset = np
+					n++
+				}
+			}
+			for ki := range pg.Panels {
+				pn := &pg.Panels[ki]
+				if !strings.Contains(pn.Notes, assetNotePrefix) {
+					continue
+				}
+				lines := strings.Split(pn.Notes, "\n")
+				changed := false
+				for li, line := range lines {
+					// The UI writes the path right after the marker; "asset: ..." is prose
+					rel, ok := strings.CutPrefix(strings.TrimSpace(line), assetNotePrefix)
+					if !ok || rel == "" || strings.TrimLeft(rel, " \t") != rel {
+						continue
+					}
+					ref := AssetRef{Kind: AssetRefPanelNote, Path: rel, IssueIndex: ii, PageNumber: pg.Number, PanelID: pn.ID}
+					if np, ok := fn(ref); ok {
+						lines[li] = assetNotePrefix + np
+						changed = true
+						n++
+					}
+				}
+				if changed {
+					pn.Notes = strings.Join(lines, "\n")
+				}
+			}
+		}
+	}
+	for ci := range p.Bible.Characters {
+		c := &p.Bible.Characters[ci]
+		if strings.TrimSpace(c.Portrait) == "" {
+			continue
+		}
+		if np, ok := fn(AssetRef{Kind: AssetRefPortrait, Path: c.Portrait, Character: c.Name}); ok {
+			c.Portrait = np
+			n++
+		}
+	}
+	return n
+}
+
+// AssetRefs lists the asset references of p in manifest order: per page its reference image, then the
+// asset lines of its panels' notes, and finally the bible portraits.
+func AssetRefs(p domain.Project) []AssetRef {
+	var out []AssetRef
+	rewriteAssetRefs(&p, func(r AssetRef) (string, bool) {
+		out = append(out, r)
+		return "", false
+	})
+	return out
+}
+
+// NormalizeAssetRefs rewrites the asset paths of p into the stored form (see NormalizeAssetPath) and
+// returns how many changed.
+func NormalizeAssetRefs(p *domain.Project) int {
+	return rewriteAssetRefs(p, func(r AssetRef) (string, bool) {
+		np := NormalizeAssetPath(r.Path)
+		return np, np != r.Path
+	})
+}
+
+// AssetLinkReport is the result of ScanAssetLinks.
+type AssetLinkReport struct {
+	Refs         int          // asset references in the manifest
+	Broken       []AssetRef   // references whose file is missing
+	Unreferenced []AssetEntry // files in assets/ that nothing references
+}
+
+// assetKey identifies an asset file for matching references against the assets folder. It is
+// case-insensitive, as the file systems of macOS and Windows usually are.
+func assetKey(root, p string) string {
+	fp := AssetFilePath(root, p)
+	if rel, err := filepath.Rel(root, fp); err == nil {
+		fp = rel
+	}
+	return strings.ToLower(filepath.ToSlash(fp))
+}
+
+// ScanAssetLinks checks every asset reference in the manifest against the file system: references
+// whose file is missing are broken, and files in assets/ that no reference points to are unreferenced.
+func ScanAssetLinks(ph *ProjectHandle) (AssetLinkReport, error) {
+	if ph == nil {
+		return AssetLinkReport{}, errors.New("nil ProjectHandle")
+	}
+	refs := AssetRefs(ph.Project)
+	rep := AssetLinkReport{Refs: len(refs)}
+	used := map[string]bool{}
+	for _, r := range refs {
+		used[assetKey(ph.Root, r.Path)] = true
+		if !assetExists(ph.Root, r.Path) {
+			rep.Broken = append(rep.Broken, r)
+		}
+	}
+	files, err := ScanAssets(ph.Root)
+	if err != nil {
+		return AssetLinkReport{}, err
+	}
+	for _, f := range files {
+		if !used[strings.ToLower(f.Path)] {
+			rep.Unreferenced = append(rep.Unreferenced, f)
+		}
+	}
+	return rep, nil
+}
+
+func assetExists(root, rel string) bool {
+	st, err := os.Stat(AssetFilePath(root, rel))
+	return err == nil && st.Mode().IsRegular()
+}
+
+// RelinkAsset points every reference to the asset from at the file to instead, e.g. after the author
+// picked a replacement for a missing file. Both paths are compared and stored in normalized form; it
+// returns the number of references changed.
+func RelinkAsset(ph *ProjectHandle, from, to string) (int, error) {
+	if ph == nil {
+		return 0, errors.New("nil ProjectHandle")
+	}
+	from, to = NormalizeAssetPath(from), NormalizeAssetPath(to)
+	if from == "" || to == "" {
+		return 0, errors.New("asset path is required")
+	}
+	return rewriteAssetRefs(&ph.Project, func(r AssetRef) (string, bool) {
+		return to, NormalizeAssetPath(r.Path) == from
+	}), nil
+}
+
+// RewriteAssetPrefix fixes broken references after assets moved to another folder: each broken
+// reference under the folder oldPrefix gets newPrefix instead, provided the file exists there.
+// References that still resolve are left alone. It returns the number of references changed.
+func RewriteAssetPrefix(ph *ProjectHandle, oldPrefix, newPrefix string) (int, error) {
+	if ph == nil {
+		return 0, errors.New("nil ProjectHandle")
+	}
+	oldPrefix, newPrefix = NormalizeAssetPath(oldPrefix), NormalizeAssetPath(newPrefix)
+	if oldPrefix == "" || newPrefix == "" {
+		return 0, errors.New("folder is required")
+	}
+	return rewriteAssetRefs(&ph.Project, func(r AssetRef) (string, bool) {
+		p := NormalizeAssetPath(r.Path)
+		rest, ok := cutAssetPrefix(p, oldPrefix)
+		if !ok || assetExists(ph.Root, p) {
+			return "", false
+		}
+		np := path.Join(newPrefix, rest)
+		return np, assetExists(ph.Root, np)
+	}), nil
+}
+
+// cutAssetPrefix returns p relative to the folder prefix when p lies inside it.
+func cutAssetPrefix(p, prefix string) (string, bool) {
+	if prefix == "." {
+		return p, true
+	}
+	return strings.CutPrefix(p, prefix+"/")
+}
+
+// SuggestAssetPrefix guesses the folder move behind rep's broken references by finding their file
+// names among the unreferenced files: it returns the old and new folder shared by most of them, for
+// RewriteAssetPrefix. File names that occur more than once are ambiguous and not considered.
+func SuggestAssetPrefix(rep AssetLinkReport) (oldPrefix, newPrefix string, ok bool) {
+	byName := map[string][]string{}
+	for _, f := range rep.Unreferenced {
+		name := strings.ToLower(path.Base(f.Path))
+		byName[name] = append(byName[name], f.Path)
+	}
+	type move struct{ from, to string }
+	votes := map[move]int{}
+	seen := map[string]bool{}
+	for _, r := range rep.Broken {
+		p := NormalizeAssetPath(r.Path)
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		cands := byName[strings.ToLower(path.Base(p))]
+		if len(cands) != 1 {
+			continue
+		}
+		from, to := splitMovedFolder(p, cands[0])
+		if from != to {
+			votes[move{from, to}]++
+		}
+	}
+	moves := make([]move, 0, len(votes))
+	for m := range votes {
+		moves = append(moves, m)
+	}
+	sort.Slice(moves, func(i, j int) bool {
+		if votes[moves[i]] != votes[moves[j]] {
+			return votes[moves[i]] > votes[moves[j]]
+		}
+		return moves[i].from+"\x00"+moves[i].to < moves[j].from+"\x00"+moves[j].to
+	})
+	if len(moves) == 0 {
+		return "", "", false
+	}
+	return moves[0].from, moves[0].to, true
+}
+
+// splitMovedFolder drops the trailing path elements oldPath and newPath share, e.g.
+// assets/chars/hero.png and assets/art/chars/hero.png give assets and assets/art.
+func splitMovedFolder(oldPath, newPath string) (string, string) {
+	a, b := strings.Split(oldPath, "/"), strings.Split(newPath, "/")
+	for len(a) > 0 && len(b) > 0 && strings.EqualFold(a[len(a)-1], b[len(b)-1]) {
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+	join := func(s []string) string {
+		if len(s) == 0 {
+			return "."
+		}
+		return strings.Join(s, "/")
+	}
+	return join(a), join(b)
+}
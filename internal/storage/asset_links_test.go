@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestNormalizeAssetPath(t *testing.T) {
+	for in, want := range map[string]string{
+		"":                            "",
+		"  ":                          "",
+		"assets/hero.png":             "assets/hero.png",
+		`assets\chars\hero.png`:       "assets/chars/hero.png",
+		`.\assets\hero.png`:           "assets/hero.png",
+		"./assets//chars/../hero.png": "assets/hero.png",
+		` assets\mixed/hero.png `:     "assets/mixed/hero.png",
+		"/srv/comics/hero.png":        "/srv/comics/hero.png",
+		`C:\comics\hero.png`:          "C:/comics/hero.png",
+	} {
+		if got := NormalizeAssetPath(in); got != want {
+			t.Errorf("NormalizeAssetPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAssetRelPathAndFilePath(t *testing.T) {
+	root := t.TempDir()
+	abs := filepath.Join(root, AssetsDirName, "chars", "hero.png")
+	if got := AssetRelPath(root, abs); got != "assets/chars/hero.png" {
+		t.Fatalf("inside root: %q", got)
+	}
+	outside := filepath.Join(filepath.Dir(root), "elsewhere.png")
+	if got := AssetRelPath(root, outside); got != filepath.ToSlash(outside) {
+		t.Fatalf("outside root: %q", got)
+	}
+	if got := AssetRelPath(root, `assets\chars\hero.png`); got != "assets/chars/hero.png" {
+		t.Fatalf("relative backslashes: %q", got)
+	}
+	for _, rel := range []string{"assets/chars/hero.png", `assets\chars\hero.png`, `.\assets\chars\hero.png`} {
+		if got := AssetFilePath(root, rel); got != abs {
+			t.Fatalf("AssetFilePath(%q) = %q, want %q", rel, got, abs)
+		}
+	}
+	if got := AssetFilePath(root, abs); got != abs {
+		t.Fatalf("absolute path changed: %q", got)
+	}
+	if got := AssetFilePath(root, " "); got != "" {
+		t.Fatalf("empty path resolved to %q", got)
+	}
+}
+
+func assetLinkProject() domain.Project {
+	return domain.Project{
+		Issues: []domain.Issue{{Pages: []domain.Page{
+			{Number: 1, ReferenceImage: &domain.ReferenceImage{Asset: `assets\thumbs\p01.jpg`}, Panels: []domain.Panel{
+				{ID: "p1", Notes: "Wide shot\nasset:assets/chars/hero.png\n  asset:assets\\chars\\villain.png  "},
+				{ID: "p2", Notes: "asset: is only a label here"},
+			}},
+			{Number: 2, Panels: []domain.Panel{{ID: "p1", Notes: "asset:assets/chars/hero.png"}}},
+		}}},
+		Bible: domain.Bible{Characters: []domain.BibleCharacter{{Name: "Hero", Portrait: "assets/chars/hero.png"}, {Name: "Extra"}}},
+	}
+}
+
+func TestAssetRefsAndNormalize(t *testing.T) {
+	p := assetLinkProject()
+	refs := AssetRefs(p)
+	var got []string
+	for _, r := range refs {
+		got = append(got, r.Kind+" "+r.Path)
+	}
+	want := []string{
+		"reference_image " + `assets\thumbs\p01.jpg`,
+		"panel_note assets/chars/hero.png",
+		"panel_note " + `assets\chars\villain.png`,
+		"panel_note assets/chars/hero.png",
+		"portrait assets/chars/hero.png",
+	}
+	if !equalStrings(got, want) {
+		t.Fatalf("refs = %q", got)
+	}
+	if refs[2].PageNumber != 1 || refs[2].PanelID != "p1" || refs[3].PageNumber != 2 || refs[4].Character != "Hero" {
+		t.Fatalf("ref locations: %+v", refs)
+	}
+
+	if n := NormalizeAssetRefs(&p); n != 2 {
+		t.Fatalf("normalized %d refs, want 2", n)
+	}
+	if a := p.Issues[0].Pages[0].ReferenceImage.Asset; a != "assets/thumbs/p01.jpg" {
+		t.Fatalf("reference image = %q", a)
+	}
+	if n := p.Issues[0].Pages[0].Panels[0].Notes; n != "Wide shot\nasset:assets/chars/hero.png\nasset:assets/chars/villain.png" {
+		t.Fatalf("notes = %q", n)
+	}
+	if n := NormalizeAssetRefs(&p); n != 0 {
+		t.Fatalf("second normalize changed %d refs", n)
+	}
+}
+
+func TestScanAssetLinks(t *testing.T) {
+	root := t.TempDir()
+	writeAsset(t, root, "chars/Hero.png", "hero")
+	writeAsset(t, root, "thumbs/p01.jpg", "thumb")
+	writeAsset(t, root, "orphan.png", "orphan")
+	ph := &ProjectHandle{Root: root, Project: assetLinkProject()}
+
+	rep, err := ScanAssetLinks(ph)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if rep.Refs != 5 {
+		t.Fatalf("refs = %d", rep.Refs)
+	}
+	var broken []string
+	for _, r := range rep.Broken {
+		broken = append(broken, r.Path)
+	}
+	// hero.png differs in case from the file: broken on case-sensitive file systems only.
+	if _, err := os.Stat(filepath.Join(root, AssetsDirName, "chars", "hero.png")); err == nil {
+		if !equalStrings(broken, []string{`assets\chars\villain.png`}) {
+			t.Fatalf("broken = %q", broken)
+		}
+	} else if len(broken) != 4 || broken[1] != `assets\chars\villain.png` {
+		t.Fatalf("broken = %q", broken)
+	}
+	// The backslashed thumbnail reference resolves; the case mismatch still counts as a reference.
+	if len(rep.Unreferenced) != 1 || rep.Unreferenced[0].Path != "assets/orphan.png" {
+		t.Fatalf("unreferenced = %+v", rep.Unreferenced)
+	}
+}
+
+func TestRelinkAsset(t *testing.T) {
+	ph := &ProjectHandle{Root: t.TempDir(), Project: assetLinkProject()}
+	n, err := RelinkAsset(ph, `assets\chars\hero.png`, `assets\cast\hero-v2.png`)
+	if err != nil || n != 3 {
+		t.Fatalf("relink = %d, %v", n, err)
+	}
+	p := ph.Project
+	if !strings.Contains(p.Issues[0].Pages[0].Panels[0].Notes, "asset:assets/cast/hero-v2.png\n") ||
+		p.Issues[0].Pages[1].Panels[0].Notes != "asset:assets/cast/hero-v2.png" ||
+		p.Bible.Characters[0].Portrait != "assets/cast/hero-v2.png" {
+		t.Fatalf("not relinked: %+v", p)
+	}
+	if _, err := RelinkAsset(ph, "assets/x.png", " "); err == nil {
+		t.Fatalf("empty target accepted")
+	}
+}
+
+func TestRewriteAssetPrefixAndSuggestion(t *testing.T) {
+	root := t.TempDir()
+	// The artist reorganised assets/chars into assets/art/chars; villain.png was not moved.
+	writeAsset(t, root, "art/chars/hero.png", "hero")
+	writeAsset(t, root, "thumbs/p01.jpg", "thumb")
+	ph := &ProjectHandle{Root: root, Project: assetLinkProject()}
+
+	rep, err := ScanAssetLinks(ph)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	from, to, ok := SuggestAssetPrefix(rep)
+	if !ok || from != "assets" || to != "assets/art" {
+		t.Fatalf("suggestion = %q → %q (%v)", from, to, ok)
+	}
+	n, err := RewriteAssetPrefix(ph, `assets\`, "assets/art")
+	if err != nil || n != 3 {
+		t.Fatalf("rewrite = %d, %v", n, err)
+	}
+	p := ph.Project
+	if p.Issues[0].Pages[0].ReferenceImage.Asset != `assets\thumbs\p01.jpg` {
+		t.Fatalf("resolving reference was rewritten: %q", p.Issues[0].Pages[0].ReferenceImage.Asset)
+	}
+	if p.Issues[0].Pages[0].Panels[0].Notes != "Wide shot\nasset:assets/art/chars/hero.png\n  asset:assets\\chars\\villain.png  " {
+		t.Fatalf("notes = %q", p.Issues[0].Pages[0].Panels[0].Notes)
+	}
+	if rep, _ = ScanAssetLinks(ph); len(rep.Broken) != 1 || len(rep.Unreferenced) != 0 {
+		t.Fatalf("after rewrite: %+v", rep)
+	}
+	if _, _, ok := SuggestAssetPrefix(rep); ok {
+		t.Fatalf("suggested a move for a file that does not exist anywhere")
+	}
+}
+
+func TestSplitMovedFolder(t *testing.T) {
+	for _, c := range [][4]string{
+		{"assets/chars/hero.png", "assets/art/chars/hero.png", "assets", "assets/art"},
+		{"hero.png", "assets/hero.png", ".", "assets"},
+		{"assets/old/hero.png", "assets/new/hero.png", "assets/old", "assets/new"},
+	} {
+		if a, b := splitMovedFolder(c[0], c[1]); a != c[2] || b != c[3] {
+			t.Errorf("splitMovedFolder(%q, %q) = %q, %q", c[0], c[1], a, b)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AssetsDirName is the project folder holding images, fonts and reference files.
+const AssetsDirName = "assets"
+
+// AssetEntry is a file in the project's assets folder as catalogued in the index.
+type AssetEntry struct {
+	Hash string // sha256 of the content, hex encoded
+	Path string // relative to the project root, e.g. assets/hero.png
+	Type string // image | font | other
+}
+
+// AssetType classifies an asset file by extension.
+func AssetType(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".jpg", ".jpeg", ".svg", ".gif", ".webp":
+		return "image"
+	case ".ttf", ".otf", ".woff", ".woff2":
+		return "font"
+	default:
+		return "other"
+	}
+}
+
+// IsTransientAssetName reports whether a file name looks like an editor or download temp file
+// (hidden files, backups, swap and partial downloads) that should not be catalogued.
+func IsTransientAssetName(name string) bool {
+	if name == "" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "~") || strings.HasSuffix(name, "~") {
+		return true
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".tmp", ".temp", ".swp", ".part", ".crdownload", ".download":
+		return true
+	}
+	return false
+}
+
+// ScanAssets walks <root>/assets and returns the catalogued files sorted by path. Paths use forward
+// slashes on every platform, like the asset references in the manifest.
+// Files that vanish or cannot be read during the walk (e.g. while an editor is writing them) are skipped.
+func ScanAssets(root string) ([]AssetEntry, error) {
+	dir := filepath.Join(root, AssetsDirName)
+	var out []AssetEntry
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && os.IsNotExist(err) {
+				return fs.SkipAll
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || IsTransientAssetName(d.Name()) {
+			return nil
+		}
+		sum, herr := hashFile(path)
+		if herr != nil {
+			return nil
+		}
+		rel, rerr := filepath.Rel(root, path)
+		if rerr != nil {
+			return nil
+		}
+		out = append(out, AssetEntry{Hash: sum, Path: filepath.ToSlash(rel), Type: AssetType(path)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan assets: %w", err)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out, nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// assetDocPath is the index document path of an asset; panels reference placed assets with the same
+// "asset:<path>" marker in their notes.
+func assetDocPath(rel string) string { return "asset:" + rel }
@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+func writeAsset(t *testing.T, root, name, content string) {
+	t.Helper()
+	p := filepath.Join(root, AssetsDirName, name)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatalf("write asset: %v", err)
+	}
+}
+
+func TestScanAssetsSkipsTransientFiles(t *testing.T) {
+	root := t.TempDir()
+	if got, err := ScanAssets(root); err != nil || len(got) != 0 {
+		t.Fatalf("missing assets dir should yield nothing: %v %v", got, err)
+	}
+	writeAsset(t, root, "hero.png", "png")
+	writeAsset(t, root, "fonts/Comic.otf", "otf")
+	writeAsset(t, root, "notes.txt", "txt")
+	for _, tmp := range []string{".hero.png.swp", "hero.png~", "~lock.png", "download.png.part", "x.tmp", ".cache/a.png"} {
+		writeAsset(t, root, tmp, "tmp")
+	}
+	got, err := ScanAssets(root)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	want := []AssetEntry{
+		{Path: AssetsDirName + "/fonts/Comic.otf", Type: "font"},
+		{Path: AssetsDirName + "/hero.png", Type: "image"},
+		{Path: AssetsDirName + "/notes.txt", Type: "other"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Path != want[i].Path || got[i].Type != want[i].Type || len(got[i].Hash) != 64 {
+			t.Fatalf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestUpdateIndexCataloguesAssets(t *testing.T) {
+	root := t.TempDir()
+	writeAsset(t, root, "hero.png", "hero")
+	writeAsset(t, root, "copy.png", "hero") // same content as hero.png
+	writeAsset(t, root, "bg.jpg", "bg")
+	rel := AssetsDirName + "/hero.png"
+	proj := domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{
+		{ID: "p1", Notes: "Wide shot\nasset:" + rel},
+		{ID: "p2", Notes: "asset:" + rel + ".bak is not it"},
+	}}}}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := UpdateIndex(ctx, root, proj); err != nil {
+		t.Fatalf("UpdateIndex: %v", err)
+	}
+	countAssets := func() int {
+		db, err := InitOrOpenIndex(root)
+		if err != nil {
+			t.Fatalf("open index: %v", err)
+		}
+		defer func() { _ = db.Close() }()
+		var n int
+		if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM assets;").Scan(&n); err != nil {
+			t.Fatalf("count assets: %v", err)
+		}
+		return n
+	}
+	if n := countAssets(); n != 2 { // hero.png and copy.png share a hash
+		t.Fatalf("expected 2 catalogued assets, got %d", n)
+	}
+	got := whereUsedPaths(t, ctx, root, "asset:"+rel)
+	if !equalStrings(got, []string{"issue:1/page:1/panel:p1"}) {
+		t.Fatalf("where used = %v", got)
+	}
+
+	// A file dropped in later shows up on the next update; a removed one disappears.
+	writeAsset(t, root, "new.png", "new")
+	if err := os.Remove(filepath.Join(root, AssetsDirName, "bg.jpg")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := UpdateIndex(ctx, root, proj); err != nil {
+		t.Fatalf("UpdateIndex 2: %v", err)
+	}
+	if n := countAssets(); n != 2 {
+		t.Fatalf("expected 2 catalogued assets after change, got %d", n)
+	}
+	res, err := Search(ctx, root, SearchQuery{Text: "new", Types: []string{"asset"}})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(res) != 1 || res[0].Path != "asset:"+AssetsDirName+"/new.png" {
+		t.Fatalf("expected new asset to be searchable, got %+v", res)
+	}
+}
@@ -0,0 +1,273 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+// Operations recorded in the audit log.
+const (
+	AuditPageCreate  = "page.create"
+	AuditPanelAdd    = "panel.add"
+	AuditPanelUpdate = "panel.update"
+	AuditPanelZOrder = "panel.zorder"
+	AuditSave        = "save"
+	AuditScriptWrite = "script.write"
+)
+
+// auditTimeLayout is a fixed-width UTC timestamp, so stored times sort and compare as text.
+const auditTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// Bounds of the audit log: entries older than auditMaxAge and all but the newest auditMaxRows are
+// pruned whenever entries are written.
+const (
+	auditMaxRows = 5000
+	auditMaxAge  = 180 * 24 * time.Hour
+)
+
+// AuditEntry is one project change in the audit log: when and by which OS user it was made, the
+// operation, the changed entity's path (e.g. "issue:1/page:3/panel:p2"; "" for the whole project) and
+// a short human-readable summary.
+type AuditEntry struct {
+	ID        int64
+	TS        time.Time
+	User      string
+	Operation string
+	Entity    string
+	Summary   string
+}
+
+// AuditFilter selects audit entries. Zero fields do not filter: Since and Until bound the time range
+// (inclusive), Entity keeps entries for that path and the entities below it and Limit caps the result
+// (default 200).
+type AuditFilter struct {
+	Since  time.Time
+	Until  time.Time
+	Entity string
+	Limit  int
+}
+
+// language=SQL
+// dialect=SQLite
+const createAuditLogSQL = `CREATE TABLE IF NOT EXISTS audit_log (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	ts        TEXT    NOT NULL,
+	user      TEXT    NOT NULL,
+	operation TEXT    NOT NULL,
+	entity    TEXT    NOT NULL,
+	summary   TEXT    NOT NULL
+)`
+
+// language=SQL
+// dialect=SQLite
+const insertAuditSQL = `INSERT INTO audit_log(ts, user, operation, entity, summary) VALUES (?, ?, ?, ?, ?)`
+
+// language=SQL
+// dialect=SQLite
+const pruneAuditByAgeSQL = `DELETE FROM audit_log WHERE ts < ?`
+
+// language=SQL
+// dialect=SQLite
+const pruneAuditByRowsSQL = `DELETE FROM audit_log WHERE id NOT IN (
+	SELECT id FROM audit_log ORDER BY id DESC LIMIT ?
+)`
+
+// ensureAuditLog creates the audit log table and its index. It is safe to run more than once.
+func ensureAuditLog(ctx context.Context, q metaQuerier) error {
+	if _, err := q.ExecContext(ctx, createAuditLogSQL); err != nil {
+		return fmt.Errorf("create audit_log: %w", err)
+	}
+	if _, err := q.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_audit_log_ts ON audit_log(ts);`); err != nil {
+		return fmt.Errorf("create audit_log index: %w", err)
+	}
+	return nil
+}
+
+// auditUser is the name of the OS user making the changes, from the user database or else the
+// environment.
+var auditUser = sync.OnceValue(func() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	for _, k := range []string{"USER", "USERNAME"} {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+	return "unknown"
+})
+
+// auditTrail is the audit state of a ProjectHandle: entries of in-memory edits waiting for the next
+// Save and the entities as of the last save, from which Save counts the changed ones.
+type auditTrail struct {
+	pending []AuditEntry
+	base    []syncEntity
+	based   bool // base is known; before the first save it is read from the manifest on disk
+}
+
+// newAuditEntry returns an entry by the current OS user at the current time.
+func newAuditEntry(op, entity, summary string) AuditEntry {
+	return AuditEntry{TS: time.Now(), User: auditUser(), Operation: op, Entity: entity, Summary: summary}
+}
+
+// noteAudit queues an entry for an in-memory edit; the next Save writes it to the audit log.
+func (ph *ProjectHandle) noteAudit(op, entity, summary string) {
+	if ph.audit == nil {
+		ph.audit = &auditTrail{}
+	}
+	ph.audit.pending = append(ph.audit.pending, newAuditEntry(op, entity, summary))
+}
+
+// auditBaseline makes sure the handle knows the entities as of the last save, reading them from the
+// manifest on disk the first time. Call it before Save replaces the manifest.
+func (ph *ProjectHandle) auditBaseline() {
+	if ph.audit == nil {
+		ph.audit = &auditTrail{}
+	}
+	if ph.audit.based {
+		return
+	}
+	ph.audit.based = true
+	data, err := os.ReadFile(ph.ManifestPath)
+	if err != nil {
+		return // new project: everything counts as changed
+	}
+	var prev domain.Project
+	if json.Unmarshal(data, &prev) == nil {
+		ph.audit.base = syncEntities(prev)
+	}
+}
+
+// logSave writes the queued edits and a save entry with the number of entities changed since the last
+// save to the audit log. Entries that cannot be written stay queued for the next save.
+func (ph *ProjectHandle) logSave(ctx context.Context) error {
+	ph.auditBaseline()
+	cur := syncEntities(ph.Project)
+	n := len(diffSyncEntities("", 0, ph.audit.base, cur))
+	ph.audit.base = cur
+	ph.audit.pending = append(ph.audit.pending, newAuditEntry(AuditSave, "", fmt.Sprintf("%d entities changed", n)))
+	ix, done := projectIndex(ph)
+	defer done()
+	if err := ix.appendAudit(ctx, ph.audit.pending, time.Now()); err != nil {
+		return err
+	}
+	ph.audit.pending = nil
+	return nil
+}
+
+// appendAudit inserts entries into the audit log and prunes it to its bounds in one transaction.
+func (ix *IndexHandle) appendAudit(ctx context.Context, entries []AuditEntry, now time.Time) error {
+	if ix == nil {
+		return errors.New("nil IndexHandle")
+	}
+	db, err := ix.acquire()
+	if err != nil {
+		return err
+	}
+	defer ix.release()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin audit: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, e := range entries {
+		if _, err := tx.ExecContext(ctx, insertAuditSQL, e.TS.UTC().Format(auditTimeLayout), e.User, e.Operation, e.Entity, e.Summary); err != nil {
+			return fmt.Errorf("insert audit entry: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, pruneAuditByAgeSQL, now.Add(-auditMaxAge).UTC().Format(auditTimeLayout)); err != nil {
+		return fmt.Errorf("prune audit log: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, pruneAuditByRowsSQL, auditMaxRows); err != nil {
+		return fmt.Errorf("prune audit log: %w", err)
+	}
+	return tx.Commit()
+}
+
+// AuditLog returns the audit log entries of the project at root matching filter, newest first.
+// The log lives in the index database only: it is never synced, survives RebuildIndex, but is lost
+// with the file when a corrupt index is replaced.
+func AuditLog(ctx context.Context, root string, filter AuditFilter) ([]AuditEntry, error) {
+	return withIndex(root, func(ix *IndexHandle) ([]AuditEntry, error) { return ix.AuditLog(ctx, filter) })
+}
+
+// AuditLog returns the audit log entries matching filter, newest first; see the package-level AuditLog.
+func (ix *IndexHandle) AuditLog(ctx context.Context, filter AuditFilter) ([]AuditEntry, error) {
+	db, err := ix.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer ix.release()
+	var where []string
+	var args []any
+	if !filter.Since.IsZero() {
+		where = append(where, "ts >= ?")
+		args = append(args, filter.Since.UTC().Format(auditTimeLayout))
+	}
+	if !filter.Until.IsZero() {
+		where = append(where, "ts <= ?")
+		args = append(args, filter.Until.UTC().Format(auditTimeLayout))
+	}
+	if filter.Entity != "" {
+		where = append(where, "(entity = ? OR substr(entity, 1, ?) = ?)")
+		args = append(args, filter.Entity, len(filter.Entity)+1, filter.Entity+"/")
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 200
+	}
+	q := `SELECT id, ts, user, operation, entity, summary FROM audit_log`
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	q += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query audit log: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	var out []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		var ts string
+		if err := rows.Scan(&e.ID, &ts, &e.User, &e.Operation, &e.Entity, &e.Summary); err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		e.TS, _ = time.Parse(auditTimeLayout, ts)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// auditPagePath returns the audit entity path of pg, e.g. "issue:1/page:3".
+func auditPagePath(ph *ProjectHandle, pg *domain.Page) string {
+	for i := range ph.Project.Issues {
+		pages := ph.Project.Issues[i].Pages
+		for j := range pages {
+			if &pages[j] == pg {
+				return fmt.Sprintf("issue:%d/page:%d", i+1, pg.Number)
+			}
+		}
+	}
+	return fmt.Sprintf("page:%d", pg.Number)
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+// auditOps returns "operation entity" of each entry, oldest first.
+func auditOps(entries []AuditEntry) []string {
+	var out []string
+	for i := len(entries) - 1; i >= 0; i-- {
+		out = append(out, entries[i].Operation+" "+entries[i].Entity)
+	}
+	return out
+}
+
+// readAudit polls the audit log while the index update started by Save holds the database.
+func readAudit(t *testing.T, ix *IndexHandle, filter AuditFilter) []AuditEntry {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		entries, err := ix.AuditLog(context.Background(), filter)
+		if err == nil {
+			return entries
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("AuditLog: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestAuditLog_RecordsEditsAndSave(t *testing.T) {
+	root := t.TempDir()
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName), Project: handleProject()}
+	t.Cleanup(func() { _ = ph.Close() })
+
+	if _, err := AddPanel(ph, 2, domain.Panel{ID: "P2"}); err != nil {
+		t.Fatalf("AddPanel: %v", err)
+	}
+	if _, err := AddPanel(ph, 2, domain.Panel{ID: "P3"}); err != nil {
+		t.Fatalf("AddPanel: %v", err)
+	}
+	if err := UpdatePanelMeta(ph, 2, "P3", "P4", "", false); err != nil {
+		t.Fatalf("UpdatePanelMeta: %v", err)
+	}
+	if err := UpdatePanelMeta(ph, 2, "P2", "", "", false); err != nil {
+		t.Fatalf("UpdatePanelMeta: %v", err)
+	}
+	if err := MovePanelZ(ph, 2, "P4", -1, false); err != nil {
+		t.Fatalf("MovePanelZ: %v", err)
+	}
+	if err := Save(ph); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	all := readAudit(t, ph.Index(), AuditFilter{})
+	want := []string{
+		"page.create issue:1/page:2",
+		"panel.add issue:1/page:2/panel:P2",
+		"panel.add issue:1/page:2/panel:P3",
+		"panel.update issue:1/page:2/panel:P4",
+		"panel.zorder issue:1/page:2/panel:P4",
+		"save ",
+	}
+	if got := auditOps(all); !reflect.DeepEqual(got, want) {
+		t.Fatalf("entries = %v, want %v", got, want)
+	}
+	// No manifest existed yet, so every entity counts: two pages, three panels and a balloon
+	if all[0].Summary != "6 entities changed" {
+		t.Fatalf("save summary = %q", all[0].Summary)
+	}
+	if all[0].User == "" || all[0].TS.IsZero() {
+		t.Fatalf("entry without user or time: %+v", all[0])
+	}
+
+	ph.Project.Issues[0].Pages[0].Panels[0].Notes = "changed"
+	if err := Save(ph); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	latest := readAudit(t, ph.Index(), AuditFilter{Limit: 1})
+	if len(latest) != 1 || latest[0].Operation != AuditSave || latest[0].Summary != "1 entities changed" {
+		t.Fatalf("latest = %+v", latest)
+	}
+}
+
+func TestAuditLog_FiltersByTimeAndEntity(t *testing.T) {
+	ix := NewIndexHandle(t.TempDir())
+	t.Cleanup(func() { _ = ix.Close() })
+	ctx := context.Background()
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []AuditEntry{
+		{TS: base, User: "ann", Operation: AuditPanelAdd, Entity: "issue:1/page:1/panel:p1"},
+		{TS: base.Add(time.Hour), User: "ann", Operation: AuditPanelAdd, Entity: "issue:1/page:10/panel:p2"},
+		{TS: base.Add(2 * time.Hour), User: "ann", Operation: AuditPageCreate, Entity: "issue:1/page:1"},
+		{TS: base.Add(3 * time.Hour), User: "ann", Operation: AuditSave},
+	}
+	if err := ix.appendAudit(ctx, entries, base.Add(4*time.Hour)); err != nil {
+		t.Fatalf("appendAudit: %v", err)
+	}
+	cases := []struct {
+		name   string
+		filter AuditFilter
+		want   []string
+	}{
+		{"all", AuditFilter{}, []string{"panel.add issue:1/page:1/panel:p1", "panel.add issue:1/page:10/panel:p2", "page.create issue:1/page:1", "save "}},
+		{"entity", AuditFilter{Entity: "issue:1/page:1"}, []string{"panel.add issue:1/page:1/panel:p1", "page.create issue:1/page:1"}},
+		{"range", AuditFilter{Since: base.Add(time.Hour), Until: base.Add(2 * time.Hour)}, []string{"panel.add issue:1/page:10/panel:p2", "page.create issue:1/page:1"}},
+		{"limit", AuditFilter{Limit: 1}, []string{"save "}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ix.AuditLog(ctx, tc.filter)
+			if err != nil {
+				t.Fatalf("AuditLog: %v", err)
+			}
+			if ops := auditOps(got); !reflect.DeepEqual(ops, tc.want) {
+				t.Fatalf("entries = %v, want %v", ops, tc.want)
+			}
+		})
+	}
+	if got, _ := ix.AuditLog(ctx, AuditFilter{}); got[0].User != "ann" || !got[0].TS.Equal(base.Add(3*time.Hour)) {
+		t.Fatalf("newest = %+v", got[0])
+	}
+}
+
+func TestAuditLog_Bounded(t *testing.T) {
+	ix := NewIndexHandle(t.TempDir())
+	t.Cleanup(func() { _ = ix.Close() })
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := []AuditEntry{{TS: now.Add(-auditMaxAge - time.Hour), Operation: AuditSave, Summary: "old"}}
+	if err := ix.appendAudit(ctx, old, now.Add(-auditMaxAge)); err != nil {
+		t.Fatalf("appendAudit: %v", err)
+	}
+	entries := make([]AuditEntry, auditMaxRows+5)
+	for i := range entries {
+		entries[i] = AuditEntry{TS: now.Add(time.Duration(i) * time.Second), Operation: AuditSave, Summary: "new"}
+	}
+	if err := ix.appendAudit(ctx, entries, now); err != nil {
+		t.Fatalf("appendAudit: %v", err)
+	}
+	got, err := ix.AuditLog(ctx, AuditFilter{Limit: 2 * auditMaxRows})
+	if err != nil {
+		t.Fatalf("AuditLog: %v", err)
+	}
+	if len(got) != auditMaxRows {
+		t.Fatalf("kept %d entries, want %d", len(got), auditMaxRows)
+	}
+	if oldest := got[len(got)-1]; oldest.Summary != "new" || !oldest.TS.Equal(now.Add(5*time.Second)) {
+		t.Fatalf("oldest kept = %+v", oldest)
+	}
+}
+
+func TestAuditLog_SurvivesRebuild(t *testing.T) {
+	root := t.TempDir()
+	ix := NewIndexHandle(root)
+	t.Cleanup(func() { _ = ix.Close() })
+	ctx := context.Background()
+	if err := ix.appendAudit(ctx, []AuditEntry{newAuditEntry(AuditSave, "", "1 entities changed")}, time.Now()); err != nil {
+		t.Fatalf("appendAudit: %v", err)
+	}
+	if err := ix.RebuildIndex(ctx, handleProject()); err != nil {
+		t.Fatalf("RebuildIndex: %v", err)
+	}
+	got, err := ix.AuditLog(ctx, AuditFilter{})
+	if err != nil || len(got) != 1 {
+		t.Fatalf("after rebuild: %+v %v", got, err)
+	}
+}
+
+func TestWriteScript_Audited(t *testing.T) {
+	root := t.TempDir()
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName), Project: handleProject()}
+	t.Cleanup(func() { _ = ph.Close() })
+	for _, text := range []string{"PAGE 1\nPanel 1", "PAGE 1\nPanel 1", "PAGE 1\nPanel 1\nPanel 2"} {
+		if err := WriteScript(ph, text); err != nil {
+			t.Fatalf("WriteScript: %v", err)
+		}
+	}
+	got := readAudit(t, ph.Index(), AuditFilter{Entity: "script"})
+	// The unchanged second write is not logged
+	if len(got) != 2 || !strings.Contains(got[0].Summary, "3 lines") || got[1].Operation != AuditScriptWrite {
+		t.Fatalf("entries = %+v", got)
+	}
+}
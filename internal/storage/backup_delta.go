@@ -0,0 +1,486 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+// Manifest backups between two full copies are stored as deltas: a list of patch operations that turn
+// the previous backup's JSON into this one's. Documents are compared as generic JSON values (numbers
+// kept as written), so fields unknown to this version survive, and every state is identified by the
+// SHA-256 of its canonical serialization: compact, with object keys sorted.
+
+// backupPatchOp is one change of a delta. Path is a JSON pointer (RFC 6901). "set" replaces the value at
+// Path or adds an object member, "remove" deletes an object member, and "splice" replaces Del elements
+// of the array at Path, starting at At, with Values.
+type backupPatchOp struct {
+	Op     string            `json:"op"`
+	Path   string            `json:"path"`
+	Value  json.RawMessage   `json:"value,omitempty"`
+	At     int               `json:"at,omitempty"`
+	Del    int               `json:"del,omitempty"`
+	Values []json.RawMessage `json:"values,omitempty"`
+}
+
+// decodeJSONValue decodes a document into generic values with numbers kept as json.Number.
+func decodeJSONValue(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err == nil {
+		return nil, errors.New("unexpected data after the top-level value")
+	}
+	return v, nil
+}
+
+// canonicalJSON serializes v compactly with sorted object keys.
+func canonicalJSON(v any) []byte {
+	b, _ := json.Marshal(v) // generic values from decodeJSONValue always marshal
+	return b
+}
+
+// stateHash identifies a document by the SHA-256 of its canonical JSON.
+func stateHash(v any) string {
+	sum := sha256.Sum256(canonicalJSON(v))
+	return hex.EncodeToString(sum[:])
+}
+
+// diffJSON appends the operations turning a into b, both at path, to ops. Arrays are compared after
+// their common head and tail, so inserting or removing pages and panels stores only the changed range.
+func diffJSON(path string, a, b any, ops []backupPatchOp) []backupPatchOp {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, k := range sortedKeys(av) {
+			if bk, ok := bv[k]; ok {
+				ops = diffJSON(path+"/"+escapePointer(k), av[k], bk, ops)
+			} else {
+				ops = append(ops, backupPatchOp{Op: "remove", Path: path + "/" + escapePointer(k)})
+			}
+		}
+		for _, k := range sortedKeys(bv) {
+			if _, ok := av[k]; !ok {
+				ops = append(ops, backupPatchOp{Op: "set", Path: path + "/" + escapePointer(k), Value: canonicalJSON(bv[k])})
+			}
+		}
+		return ops
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		head := 0
+		for head < len(av) && head < len(bv) && reflect.DeepEqual(av[head], bv[head]) {
+			head++
+		}
+		tail := 0
+		for tail < len(av)-head && tail < len(bv)-head && reflect.DeepEqual(av[len(av)-1-tail], bv[len(bv)-1-tail]) {
+			tail++
+		}
+		am, bm := av[head:len(av)-tail], bv[head:len(bv)-tail]
+		if len(am) == len(bm) {
+			for i := range am {
+				ops = diffJSON(path+"/"+strconv.Itoa(head+i), am[i], bm[i], ops)
+			}
+			return ops
+		}
+		op := backupPatchOp{Op: "splice", Path: path, At: head, Del: len(am)}
+		for _, v := range bm {
+			op.Values = append(op.Values, canonicalJSON(v))
+		}
+		return append(ops, op)
+	}
+	if !reflect.DeepEqual(a, b) {
+		ops = append(ops, backupPatchOp{Op: "set", Path: path, Value: canonicalJSON(b)})
+	}
+	return ops
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func unescapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
+
+// applyJSONPatch applies ops to doc and returns the result. doc is modified in place where possible.
+func applyJSONPatch(doc any, ops []backupPatchOp) (any, error) {
+	for i, op := range ops {
+		var tokens []string
+		if op.Path != "" {
+			if !strings.HasPrefix(op.Path, "/") {
+				return nil, fmt.Errorf("op %d: invalid path %q", i, op.Path)
+			}
+			for _, t := range strings.Split(op.Path[1:], "/") {
+				tokens = append(tokens, unescapePointer(t))
+			}
+		}
+		var err error
+		doc, err = patchAt(doc, tokens, op)
+		if err != nil {
+			return nil, fmt.Errorf("op %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+// patchAt applies op to the value node at tokens below it and returns the new node.
+func patchAt(node any, tokens []string, op backupPatchOp) (any, error) {
+	if len(tokens) == 0 {
+		switch op.Op {
+		case "set":
+			return decodeJSONValue(op.Value)
+		case "splice":
+			arr, ok := node.([]any)
+			if !ok {
+				return nil, errors.New("splice target is not an array")
+			}
+			if op.At < 0 || op.Del < 0 || op.At+op.Del > len(arr) {
+				return nil, fmt.Errorf("splice %d+%d out of range of %d elements", op.At, op.Del, len(arr))
+			}
+			ins := make([]any, len(op.Values))
+			for i, raw := range op.Values {
+				v, err := decodeJSONValue(raw)
+				if err != nil {
+					return nil, err
+				}
+				ins[i] = v
+			}
+			return slices.Concat(arr[:op.At], ins, arr[op.At+op.Del:]), nil
+		}
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+	key, rest := tokens[0], tokens[1:]
+	switch n := node.(type) {
+	case map[string]any:
+		if len(rest) == 0 && op.Op == "remove" {
+			if _, ok := n[key]; !ok {
+				return nil, fmt.Errorf("no member %q to remove", key)
+			}
+			delete(n, key)
+			return n, nil
+		}
+		child, ok := n[key]
+		if !ok && !(len(rest) == 0 && op.Op == "set") {
+			return nil, fmt.Errorf("no member %q", key)
+		}
+		v, err := patchAt(child, rest, op)
+		if err != nil {
+			return nil, err
+		}
+		n[key] = v
+		return n, nil
+	case []any:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(n) {
+			return nil, fmt.Errorf("no array element %q", key)
+		}
+		v, err := patchAt(n[i], rest, op)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = v
+		return n, nil
+	}
+	return nil, fmt.Errorf("cannot descend into %q of a scalar", key)
+}
+
+// backupDelta is the content of a comic.json.<stamp>.delta backup.
+type backupDelta struct {
+	Parent     string          `json:"parent"`     // stamp of the backup the ops apply to
+	ParentHash string          `json:"parentHash"` // stateHash of that backup
+	Hash       string          `json:"hash"`       // stateHash of the result
+	Ops        []backupPatchOp `json:"ops"`
+}
+
+// BackupGap reports backups lost to a broken delta chain: Fallback is the full backup used instead, and
+// the backups from From to To cannot be reconstructed.
+type BackupGap struct {
+	Fallback string
+	From, To time.Time
+	Err      error
+}
+
+func (g *BackupGap) String() string {
+	return fmt.Sprintf("The backups from %s to %s cannot be reconstructed (%v); the full backup of %s was used instead.",
+		g.From.Format("2006-01-02 15:04:05"), g.To.Format("2006-01-02 15:04:05"), g.Err, backupStampTime(g.Fallback).Format("2006-01-02 15:04:05"))
+}
+
+// backupStampTime parses the stamp of a backup path, or returns the zero time.
+func backupStampTime(path string) time.Time {
+	t, _ := time.ParseInLocation(backupStampLayout, backupStamp(path), time.Local)
+	return t
+}
+
+// loadBackup reads the backup at path as a generic JSON document and returns it with its stateHash.
+// A delta is applied to its parent, which is loaded the same way back to a full copy.
+func loadBackup(path string) (any, string, error) {
+	if !strings.HasSuffix(path, backupDeltaSuffix) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("read backup: %w", backupErr(err))
+		}
+		doc, err := decodeJSONValue(b)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: parse backup %s: %w", ErrManifestCorrupt, filepath.Base(path), err)
+		}
+		return doc, stateHash(doc), nil
+	}
+	broken := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s: %s", ErrBackupChainBroken, filepath.Base(path), fmt.Sprintf(format, args...))
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("read backup: %w", backupErr(err))
+	}
+	var d backupDelta
+	if err := json.Unmarshal(b, &d); err != nil {
+		return nil, "", broken("unreadable delta: %v", err)
+	}
+	// Parents are older, which also rules out cycles
+	if d.Parent == "" || d.Parent >= backupStamp(path) {
+		return nil, "", broken("invalid parent %q", d.Parent)
+	}
+	parent := ""
+	for _, suffix := range []string{backupFullSuffix, backupDeltaSuffix} {
+		p := filepath.Join(filepath.Dir(path), ManifestFileName+"."+d.Parent+suffix)
+		if _, err := os.Stat(p); err == nil {
+			parent = p
+			break
+		}
+	}
+	if parent == "" {
+		return nil, "", broken("the backup of %s it builds on is missing", d.Parent)
+	}
+	doc, hash, err := loadBackup(parent)
+	if err != nil {
+		if errors.Is(err, ErrBackupChainBroken) {
+			return nil, "", err
+		}
+		return nil, "", broken("%v", err)
+	}
+	if hash != d.ParentHash {
+		return nil, "", broken("the backup of %s it builds on has changed", d.Parent)
+	}
+	if doc, err = applyJSONPatch(doc, d.Ops); err != nil {
+		return nil, "", broken("%v", err)
+	}
+	if hash = stateHash(doc); hash != d.Hash {
+		return nil, "", broken("reconstructed content does not match")
+	}
+	return doc, hash, nil
+}
+
+// resolveBackup loads the backup at path. When it is a delta with a broken chain, the newest full
+// backup before it that can be read is loaded instead and described by the returned gap.
+func resolveBackup(root, path string) (any, *BackupGap, error) {
+	doc, _, err := loadBackup(path)
+	if err == nil || !errors.Is(err, ErrBackupChainBroken) {
+		return doc, nil, err
+	}
+	all, lerr := ListBackups(root)
+	if lerr != nil {
+		return nil, nil, err
+	}
+	want := backupStamp(path)
+	gap := &BackupGap{To: backupStampTime(path), Err: err}
+	for i, b := range all {
+		if b.Delta || backupStamp(b.Path) >= want {
+			continue
+		}
+		fdoc, _, ferr := loadBackup(b.Path)
+		if ferr != nil {
+			continue
+		}
+		gap.Fallback = b.Path
+		gap.From = gap.To
+		if i > 0 {
+			gap.From = all[i-1].Time
+		}
+		return fdoc, gap, nil
+	}
+	return nil, nil, err
+}
+
+// projectFromBackup decodes a backup document into a project.
+func projectFromBackup(doc any) (domain.Project, error) {
+	var p domain.Project
+	if err := json.Unmarshal(canonicalJSON(doc), &p); err != nil {
+		return domain.Project{}, fmt.Errorf("%w: parse backup: %w", ErrManifestCorrupt, err)
+	}
+	return p, nil
+}
+
+// dependentBackup returns the delta that patches the backup at path, or "".
+func dependentBackup(root, path string) string {
+	all, err := ListBackups(root)
+	if err != nil {
+		return ""
+	}
+	stamp := backupStamp(path)
+	for _, b := range all {
+		if !b.Delta {
+			continue
+		}
+		raw, err := os.ReadFile(b.Path)
+		if err != nil {
+			continue
+		}
+		var d backupDelta
+		if json.Unmarshal(raw, &d) == nil && d.Parent == stamp {
+			return b.Path
+		}
+	}
+	return ""
+}
+
+// materializeBackup replaces the delta at path with a full copy of the same stamp. A delta that cannot
+// be reconstructed is left as it is.
+func materializeBackup(path string) error {
+	doc, _, err := loadBackup(path)
+	if err != nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileSync(strings.TrimSuffix(path, backupDeltaSuffix)+backupFullSuffix, append(data, '\n')); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// lastBackups remembers, per backups folder, the document of the backup Save wrote last, so the next
+// delta does not have to reconstruct it.
+var lastBackups sync.Map // backups dir -> lastBackup
+
+type lastBackup struct {
+	path string
+	size int64
+	mod  time.Time
+	doc  any
+	hash string
+}
+
+// backupManifest copies the manifest at manifestPath into the backups folder before Save replaces it:
+// as a full copy every fullEvery backups and as a delta against the newest backup in between. A full
+// copy is also written when the newest backup is of the same second or cannot be reconstructed, when
+// the manifest cannot be parsed, or when the delta would not be much smaller than the manifest.
+func backupManifest(root, manifestPath string, now time.Time, fullEvery int) (string, error) {
+	bdir := filepath.Join(root, BackupsDirName)
+	base := filepath.Join(bdir, ManifestFileName+"."+now.Format(backupStampLayout))
+	var cur any
+	var curHash string
+	full := func() (string, error) {
+		path := base + backupFullSuffix
+		if err := copyFile(manifestPath, path); err != nil {
+			return "", err
+		}
+		// A delta of the same second is superseded
+		_ = os.Remove(base + backupDeltaSuffix)
+		remember(bdir, path, cur, curHash)
+		return path, nil
+	}
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return "", err
+	}
+	if cur, err = decodeJSONValue(data); err != nil {
+		cur = nil
+		return full()
+	}
+	curHash = stateHash(cur)
+	all, err := ListBackups(root)
+	if fullEvery <= 1 || err != nil || len(all) == 0 || backupStamp(all[0].Path) >= backupStamp(base) {
+		return full()
+	}
+	deltas := 0
+	for _, b := range all {
+		if !b.Delta {
+			break
+		}
+		deltas++
+	}
+	if deltas+1 >= fullEvery {
+		return full()
+	}
+	prev, prevHash, ok := recall(bdir, all[0])
+	if !ok {
+		if prev, prevHash, err = loadBackup(all[0].Path); err != nil {
+			return full()
+		}
+	}
+	out, err := json.Marshal(backupDelta{Parent: backupStamp(all[0].Path), ParentHash: prevHash, Hash: curHash, Ops: diffJSON("", prev, cur, nil)})
+	if err != nil || len(out) > len(data)/2 {
+		return full()
+	}
+	path := base + backupDeltaSuffix
+	if err := writeFileSync(path, out); err != nil {
+		return "", err
+	}
+	remember(bdir, path, cur, curHash)
+	return path, nil
+}
+
+// remember caches doc as the content of the backup just written to path.
+func remember(bdir, path string, doc any, hash string) {
+	info, err := os.Stat(path)
+	if doc == nil || err != nil {
+		lastBackups.Delete(bdir)
+		return
+	}
+	lastBackups.Store(bdir, lastBackup{path: path, size: info.Size(), mod: info.ModTime(), doc: doc, hash: hash})
+}
+
+// recall returns the cached document of b when it is the backup Save wrote last and is unchanged.
+func recall(bdir string, b BackupInfo) (any, string, bool) {
+	v, ok := lastBackups.Load(bdir)
+	if !ok {
+		return nil, "", false
+	}
+	lb := v.(lastBackup)
+	info, err := os.Stat(b.Path)
+	if lb.path != b.Path || err != nil || info.Size() != lb.size || !info.ModTime().Equal(lb.mod) {
+		return nil, "", false
+	}
+	return lb.doc, lb.hash, true
+}
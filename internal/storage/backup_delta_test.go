@@ -0,0 +1,212 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestDiffJSONRoundTrip(t *testing.T) {
+	cases := []struct{ a, b string }{
+		{`{"a":1,"b":[1,2,3]}`, `{"a":2,"b":[1,2,3],"c":"x"}`},
+		{`{"a":{"x~/y":1},"b":[1,2,3,4]}`, `{"a":{},"b":[1,9,4]}`},
+		{`{"list":[{"id":"p1"},{"id":"p2"}]}`, `{"list":[{"id":"p0"},{"id":"p1"},{"id":"p2","n":1}]}`},
+		{`[1,2]`, `{"k":null}`},
+	}
+	for i, c := range cases {
+		a, _ := decodeJSONValue([]byte(c.a))
+		b, _ := decodeJSONValue([]byte(c.b))
+		got, err := applyJSONPatch(a, diffJSON("", a, b, nil))
+		if err != nil {
+			t.Fatalf("case %d: apply: %v", i, err)
+		}
+		if stateHash(got) != stateHash(b) {
+			t.Fatalf("case %d: got %s want %s", i, canonicalJSON(got), canonicalJSON(b))
+		}
+	}
+	x, _ := decodeJSONValue([]byte(`{"b":1,"a":[1.50,2]}`))
+	y, _ := decodeJSONValue([]byte(`{ "a":[1.50, 2], "b":1 }`))
+	if stateHash(x) != stateHash(y) {
+		t.Fatalf("canonical form should not depend on key order or whitespace")
+	}
+}
+
+// saveBackups writes one manifest per name into root and backs each up at consecutive seconds.
+func saveBackups(t *testing.T, root string, start time.Time, fullEvery int, names ...string) []string {
+	t.Helper()
+	manifest := filepath.Join(root, ManifestFileName)
+	if err := os.MkdirAll(filepath.Join(root, BackupsDirName), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	var paths []string
+	for i, name := range names {
+		p := domain.Project{Name: name, Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{{ID: "p1", Notes: strings.Repeat("long notes ", 50)}}}}}}}
+		b, _ := json.MarshalIndent(p, "", "  ")
+		if err := os.WriteFile(manifest, b, 0o644); err != nil {
+			t.Fatalf("write manifest: %v", err)
+		}
+		path, err := backupManifest(root, manifest, start.Add(time.Duration(i)*time.Second), fullEvery)
+		if err != nil {
+			t.Fatalf("backup %d: %v", i, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestBackupManifestWritesDeltasBetweenFullCopies(t *testing.T) {
+	root := t.TempDir()
+	start := time.Date(2025, 6, 30, 12, 0, 0, 0, time.Local)
+	paths := saveBackups(t, root, start, 3, "v0", "v1", "v2", "v3", "v4")
+	for i, p := range paths {
+		wantDelta := i%3 != 0
+		if strings.HasSuffix(p, backupDeltaSuffix) != wantDelta {
+			t.Fatalf("backup %d: got %s, delta=%v expected", i, filepath.Base(p), wantDelta)
+		}
+		s, err := ReadBackupSummary(p)
+		if err != nil {
+			t.Fatalf("summary %d: %v", i, err)
+		}
+		if s.Name != fmt.Sprintf("v%d", i) || s.Panels != 1 {
+			t.Fatalf("backup %d reconstructed as %+v", i, s)
+		}
+	}
+	all, _ := ListBackups(root)
+	if len(all) != 5 || !all[0].Delta || all[1].Delta {
+		t.Fatalf("unexpected listing: %+v", all)
+	}
+
+	// A backup of the same second as the newest one is always a full copy.
+	again := saveBackups(t, root, start.Add(4*time.Second), 3, "v4b")
+	if !strings.HasSuffix(again[0], backupFullSuffix) {
+		t.Fatalf("expected a full copy, got %s", filepath.Base(again[0]))
+	}
+	if _, err := os.Stat(paths[4]); !os.IsNotExist(err) {
+		t.Fatalf("superseded delta of the same second should be removed")
+	}
+}
+
+func TestRestoreBackupReconstructsDelta(t *testing.T) {
+	root := t.TempDir()
+	ph, err := InitProject(root, domain.Project{Name: "Current"})
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	paths := saveBackups(t, root, time.Now().Add(-time.Hour), 10, "v0", "v1", "v2")
+	if !strings.HasSuffix(paths[2], backupDeltaSuffix) {
+		t.Fatalf("expected a delta, got %s", paths[2])
+	}
+	gap, err := RestoreBackup(ph, paths[2])
+	if err != nil || gap != nil {
+		t.Fatalf("restore: gap=%v err=%v", gap, err)
+	}
+	if ph.Project.Name != "v2" {
+		t.Fatalf("restored %q", ph.Project.Name)
+	}
+}
+
+func TestBrokenDeltaChainFallsBackToFullBackup(t *testing.T) {
+	root := t.TempDir()
+	ph, err := InitProject(root, domain.Project{Name: "Current"})
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	paths := saveBackups(t, root, start, 10, "v0", "v1", "v2", "v3")
+
+	// Damage the middle of the chain.
+	if err := os.WriteFile(paths[2], []byte(`{"parent":"x"`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := ReadBackupSummary(paths[3]); !errors.Is(err, ErrBackupChainBroken) {
+		t.Fatalf("expected ErrBackupChainBroken, got %v", err)
+	}
+	if s, err := ReadBackupSummary(paths[1]); err != nil || s.Name != "v1" {
+		t.Fatalf("backups before the damage should still read: %+v %v", s, err)
+	}
+	gap, err := RestoreBackup(ph, paths[3])
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if gap == nil || gap.Fallback != paths[0] || !gap.From.Equal(start.Add(time.Second)) || !gap.To.Equal(start.Add(3*time.Second)) {
+		t.Fatalf("unexpected gap: %+v", gap)
+	}
+	if ph.Project.Name != "v0" {
+		t.Fatalf("expected the full backup to be restored, got %q", ph.Project.Name)
+	}
+
+	// A parent rewritten in place is detected by its hash.
+	root2 := t.TempDir()
+	paths = saveBackups(t, root2, start, 10, "v0", "v1")
+	b, _ := json.Marshal(domain.Project{Name: "tampered"})
+	_ = os.WriteFile(paths[0], b, 0o644)
+	if _, err := ReadBackupSummary(paths[1]); !errors.Is(err, ErrBackupChainBroken) {
+		t.Fatalf("expected ErrBackupChainBroken for a changed parent, got %v", err)
+	}
+}
+
+func TestDeleteBackupMaterializesDependentDelta(t *testing.T) {
+	root := t.TempDir()
+	paths := saveBackups(t, root, time.Date(2025, 6, 30, 12, 0, 0, 0, time.Local), 10, "v0", "v1", "v2")
+	if err := DeleteBackup(root, paths[0]); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	full := strings.TrimSuffix(paths[1], backupDeltaSuffix) + backupFullSuffix
+	if _, err := os.Stat(full); err != nil {
+		t.Fatalf("expected the next backup to become a full copy: %v", err)
+	}
+	for i, p := range []string{full, paths[2]} {
+		if s, err := ReadBackupSummary(p); err != nil || s.Name != fmt.Sprintf("v%d", i+1) {
+			t.Fatalf("backup %d after delete: %+v %v", i+1, s, err)
+		}
+	}
+}
+
+func TestPruneBackupsKeepsDeltaChain(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.Local)
+	paths := saveBackups(t, root, now.Add(-time.Minute), 10, "v0", "v1", "v2", "v3")
+	if _, err := PruneBackups(root, BackupRetention{KeepLast: 1, KeepDailyDays: 30}, now); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	for _, p := range paths {
+		if _, err := os.Stat(p); err != nil {
+			t.Fatalf("%s is needed by the kept delta: %v", filepath.Base(p), err)
+		}
+	}
+	if s, err := ReadBackupSummary(paths[3]); err != nil || s.Name != "v3" {
+		t.Fatalf("kept delta unreadable: %+v %v", s, err)
+	}
+}
+
+func TestLoadBackupProjectLeavesManifest(t *testing.T) {
+	root := t.TempDir()
+	paths := saveBackups(t, root, time.Now().Add(-time.Hour), 10, "v0", "v1", "v2")
+	manifest := filepath.Join(root, ManifestFileName)
+	before, _ := os.ReadFile(manifest)
+	p, gap, err := LoadBackupProject(root, paths[1])
+	if err != nil || gap != nil || p.Name != "v1" {
+		t.Fatalf("load: %q gap=%v err=%v", p.Name, gap, err)
+	}
+	if after, _ := os.ReadFile(manifest); string(after) != string(before) {
+		t.Fatalf("loading a backup changed the manifest")
+	}
+	if _, _, err := LoadBackupProject(root, filepath.Join(t.TempDir(), filepath.Base(paths[1]))); err == nil {
+		t.Fatalf("expected an error for a backup outside the project")
+	}
+}
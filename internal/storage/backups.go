@@ -0,0 +1,309 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	applog "gocomicwriter/internal/log"
+)
+
+// backupStampLayout is the timestamp format embedded in comic.json.<stamp>.bak names.
+const backupStampLayout = "20060102-150405"
+
+// A manifest backup is either a full copy of comic.json (comic.json.<stamp>.bak) or a delta
+// (comic.json.<stamp>.delta) that patches the backup before it.
+const (
+	backupFullSuffix  = ".bak"
+	backupDeltaSuffix = ".delta"
+)
+
+// BackupRetention controls how many manifest backups Save keeps and how they are stored.
+// The newest KeepLast backups are always kept; older ones are thinned to the newest backup
+// per calendar day for KeepDailyDays days and removed after that. Backups a kept delta builds on
+// are kept too. Every FullEvery-th backup is a full copy and the ones in between are deltas;
+// 1 stores only full copies.
+type BackupRetention struct {
+	KeepLast      int
+	KeepDailyDays int
+	FullEvery     int
+}
+
+// DefaultBackupRetention keeps the last 50 backups plus one per day for 30 days, with a full copy
+// every 10 backups.
+var DefaultBackupRetention = BackupRetention{KeepLast: 50, KeepDailyDays: 30, FullEvery: 10}
+
+var (
+	retentionMu     sync.Mutex
+	backupRetention = DefaultBackupRetention
+)
+
+// SetBackupRetention sets the retention policy applied by Save. Non-positive fields use the defaults.
+func SetBackupRetention(r BackupRetention) {
+	if r.KeepLast <= 0 {
+		r.KeepLast = DefaultBackupRetention.KeepLast
+	}
+	if r.KeepDailyDays <= 0 {
+		r.KeepDailyDays = DefaultBackupRetention.KeepDailyDays
+	}
+	if r.FullEvery <= 0 {
+		r.FullEvery = DefaultBackupRetention.FullEvery
+	}
+	retentionMu.Lock()
+	backupRetention = r
+	retentionMu.Unlock()
+}
+
+func currentBackupRetention() BackupRetention {
+	retentionMu.Lock()
+	defer retentionMu.Unlock()
+	return backupRetention
+}
+
+// BackupInfo describes a timestamped manifest backup in the backups folder.
+type BackupInfo struct {
+	Path  string
+	Time  time.Time
+	Size  int64
+	Delta bool // only the changes since the backup before it; see ReadBackupSummary and RestoreBackup
+}
+
+// BackupSummary is lightweight metadata parsed from a backup for previews.
+type BackupSummary struct {
+	Name   string
+	Issues int
+	Pages  int
+	Panels int
+}
+
+// isBackupName reports whether name matches comic.json.<stamp>.bak or comic.json.<stamp>.delta.
+func isBackupName(name string) bool {
+	return strings.HasPrefix(name, ManifestFileName+".") && (strings.HasSuffix(name, backupFullSuffix) || strings.HasSuffix(name, backupDeltaSuffix))
+}
+
+// backupStamp returns the <stamp> part of a backup path.
+func backupStamp(path string) string {
+	name := strings.TrimPrefix(filepath.Base(path), ManifestFileName+".")
+	return strings.TrimSuffix(strings.TrimSuffix(name, backupFullSuffix), backupDeltaSuffix)
+}
+
+// ListBackups returns all manifest backups under root, newest first.
+// The time is parsed from the file name stamp, falling back to the modification time.
+func ListBackups(root string) ([]BackupInfo, error) {
+	bdir := filepath.Join(root, BackupsDirName)
+	ents, err := os.ReadDir(bdir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read backups dir: %w", err)
+	}
+	var out []BackupInfo
+	for _, e := range ents {
+		if e.IsDir() || !isBackupName(e.Name()) {
+			continue
+		}
+		info, ierr := e.Info()
+		if ierr != nil {
+			continue
+		}
+		bi := BackupInfo{Path: filepath.Join(bdir, e.Name()), Time: info.ModTime(), Size: info.Size(), Delta: strings.HasSuffix(e.Name(), backupDeltaSuffix)}
+		if t, perr := time.ParseInLocation(backupStampLayout, backupStamp(e.Name()), time.Local); perr == nil {
+			bi.Time = t
+		}
+		out = append(out, bi)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.After(out[j].Time) })
+	return out, nil
+}
+
+// PruneBackups applies the retention policy to the manifest backups under root and returns
+// the number of files removed.
+func PruneBackups(root string, r BackupRetention, now time.Time) (int, error) {
+	all, err := ListBackups(root)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, b := range backupsBeyondRetention(all, r, now) {
+		if rerr := os.Remove(b.Path); rerr != nil && !os.IsNotExist(rerr) {
+			return n, fmt.Errorf("remove backup: %w", rerr)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// backupsBeyondRetention returns the backups of all (newest first) that the retention policy drops.
+// A delta needs every backup back to the full copy before it, so those are never dropped while the
+// delta is kept.
+func backupsBeyondRetention(all []BackupInfo, r BackupRetention, now time.Time) []BackupInfo {
+	if r.KeepLast <= 0 {
+		r.KeepLast = DefaultBackupRetention.KeepLast
+	}
+	if len(all) <= r.KeepLast {
+		return nil
+	}
+	cutoff := now.AddDate(0, 0, -r.KeepDailyDays)
+	seenDay := map[string]bool{}
+	for _, b := range all[:r.KeepLast] {
+		seenDay[b.Time.Format("2006-01-02")] = true
+	}
+	drop := map[int]bool{}
+	for i := r.KeepLast; i < len(all); i++ {
+		b := all[i]
+		day := b.Time.Format("2006-01-02")
+		if b.Time.After(cutoff) && !seenDay[day] {
+			seenDay[day] = true
+			continue
+		}
+		drop[i] = true
+	}
+	for i := 0; i < len(all); i++ {
+		if drop[i] || !all[i].Delta {
+			continue
+		}
+		for j := i + 1; j < len(all); j++ {
+			delete(drop, j)
+			if !all[j].Delta {
+				break
+			}
+		}
+	}
+	var out []BackupInfo
+	for i, b := range all {
+		if drop[i] {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// ReadBackupSummary parses a backup and returns its project name and page/panel counts. A delta is
+// reconstructed from the backups it builds on; ErrBackupChainBroken means one of them is missing or
+// damaged.
+func ReadBackupSummary(path string) (BackupSummary, error) {
+	doc, _, err := loadBackup(path)
+	if err != nil {
+		return BackupSummary{}, err
+	}
+	p, err := projectFromBackup(doc)
+	if err != nil {
+		return BackupSummary{}, err
+	}
+	s := BackupSummary{Name: p.Name, Issues: len(p.Issues)}
+	for _, iss := range p.Issues {
+		s.Pages += len(iss.Pages)
+		for _, pg := range iss.Pages {
+			s.Panels += len(pg.Panels)
+		}
+	}
+	return s, nil
+}
+
+// checkBackupPath ensures path names a manifest backup inside the project's backups folder.
+func checkBackupPath(root, path string) error {
+	if !isBackupName(filepath.Base(path)) {
+		return fmt.Errorf("not a manifest backup: %s", filepath.Base(path))
+	}
+	if filepath.Clean(filepath.Dir(path)) != filepath.Clean(filepath.Join(root, BackupsDirName)) {
+		return fmt.Errorf("backup is outside the project backups folder: %s", path)
+	}
+	return nil
+}
+
+// LoadBackupProject reads the backup at path as a project without touching the manifest, e.g. to
+// compare it with the current version. Broken delta chains fall back like RestoreBackup does.
+func LoadBackupProject(root, path string) (domain.Project, *BackupGap, error) {
+	if err := checkBackupPath(root, path); err != nil {
+		return domain.Project{}, nil, err
+	}
+	return loadBackupProject(root, path)
+}
+
+func loadBackupProject(root, path string) (domain.Project, *BackupGap, error) {
+	doc, gap, err := resolveBackup(root, path)
+	if err != nil {
+		return domain.Project{}, nil, err
+	}
+	p, err := projectFromBackup(doc)
+	if err != nil {
+		return domain.Project{}, nil, err
+	}
+	return p, gap, nil
+}
+
+// RestoreBackup replaces the project manifest with the given backup. The current manifest is
+// backed up first (via Save) so the restore itself can be undone. On success ph.Project holds
+// the restored project. When the backup is a delta that cannot be reconstructed, the newest intact
+// full backup before it is restored instead and the returned gap says which backups were lost.
+func RestoreBackup(ph *ProjectHandle, path string) (*BackupGap, error) {
+	l := applog.WithOperation(applog.WithComponent("storage"), "restore_backup").With(slog.String("backup", path))
+	if ph == nil {
+		return nil, errors.New("nil ProjectHandle")
+	}
+	if err := checkBackupPath(ph.Root, path); err != nil {
+		return nil, err
+	}
+	p, gap, err := loadBackupProject(ph.Root, path)
+	if err != nil {
+		l.Error("read backup failed", slog.Any("err", err))
+		return nil, err
+	}
+	if gap != nil {
+		l.Warn("backup chain broken", slog.String("fallback", gap.Fallback), slog.Any("err", gap.Err))
+	}
+	prev := ph.Project
+	ph.Project = p
+	if err := Save(ph); err != nil {
+		ph.Project = prev
+		l.Error("restore backup failed", slog.Any("err", err))
+		return nil, fmt.Errorf("restore backup: %w", err)
+	}
+	l.Info("backup restored", slog.String("manifest", ph.ManifestPath))
+	return gap, nil
+}
+
+// DeleteBackup removes a single manifest backup from the project's backups folder. A delta that builds
+// on it is turned into a full copy first, so the backups after it stay readable.
+func DeleteBackup(root, path string) error {
+	if err := checkBackupPath(root, path); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("remove backup: %w", backupErr(err))
+	}
+	if next := dependentBackup(root, path); next != "" {
+		if err := materializeBackup(next); err != nil {
+			return fmt.Errorf("keep the next backup readable: %w", err)
+		}
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("remove backup: %w", backupErr(err))
+	}
+	return nil
+}
+
+// backupErr marks a missing backup file with ErrBackupNotFound.
+func backupErr(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrBackupNotFound, err)
+	}
+	return err
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+func writeBackup(t *testing.T, root string, ts time.Time, p domain.Project) string {
+	t.Helper()
+	bdir := filepath.Join(root, BackupsDirName)
+	if err := os.MkdirAll(bdir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	b, _ := json.Marshal(p)
+	path := filepath.Join(bdir, ManifestFileName+"."+ts.Format(backupStampLayout)+".bak")
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatalf("write backup: %v", err)
+	}
+	return path
+}
+
+func TestPruneBackupsKeepsLastAndDaily(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.Local)
+	// 4 recent backups today (kept by KeepLast=3 except the oldest, which is today's extra)
+	for i := 0; i < 4; i++ {
+		writeBackup(t, root, now.Add(-time.Duration(i)*time.Minute), domain.Project{})
+	}
+	// 3 backups two days ago: only the newest of that day survives
+	d2 := now.AddDate(0, 0, -2)
+	keepD2 := writeBackup(t, root, d2.Add(3*time.Hour), domain.Project{})
+	writeBackup(t, root, d2.Add(2*time.Hour), domain.Project{})
+	writeBackup(t, root, d2.Add(time.Hour), domain.Project{})
+	// one backup five days ago survives; one 40 days ago is beyond KeepDailyDays
+	keepD5 := writeBackup(t, root, now.AddDate(0, 0, -5), domain.Project{})
+	old := writeBackup(t, root, now.AddDate(0, 0, -40), domain.Project{})
+
+	n, err := PruneBackups(root, BackupRetention{KeepLast: 3, KeepDailyDays: 30}, now)
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if n != 4 {
+		t.Fatalf("expected 4 removed, got %d", n)
+	}
+	left, _ := ListBackups(root)
+	if len(left) != 5 {
+		t.Fatalf("expected 5 backups left, got %d", len(left))
+	}
+	if left[0].Time != now {
+		t.Fatalf("newest backup should be first: %+v", left[0])
+	}
+	for _, p := range []string{keepD2, keepD5} {
+		if _, err := os.Stat(p); err != nil {
+			t.Fatalf("expected %s to be kept: %v", filepath.Base(p), err)
+		}
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Fatalf("expected backup beyond daily window to be removed")
+	}
+	// Idempotent
+	if n, _ := PruneBackups(root, BackupRetention{KeepLast: 3, KeepDailyDays: 30}, now); n != 0 {
+		t.Fatalf("second prune removed %d", n)
+	}
+}
+
+func TestBackupSummaryRestoreAndDelete(t *testing.T) {
+	// Save starts a background index update; use a manually cleaned dir to avoid cleanup races.
+	root, err := os.MkdirTemp("", "gcw-backups-")
+	if err != nil {
+		t.Fatalf("mkdtemp: %v", err)
+	}
+	t.Cleanup(func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = os.RemoveAll(root)
+	})
+	writeManifest(t, root, domain.Project{Name: "Current"}, time.Now())
+	old := writeBackup(t, root, time.Now().Add(-time.Hour), domain.Project{Name: "Older", Issues: []domain.Issue{{
+		Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{{ID: "p1"}, {ID: "p2"}}}, {Number: 2}},
+	}}})
+
+	sum, err := ReadBackupSummary(old)
+	if err != nil {
+		t.Fatalf("summary: %v", err)
+	}
+	if sum.Name != "Older" || sum.Issues != 1 || sum.Pages != 2 || sum.Panels != 2 {
+		t.Fatalf("unexpected summary: %+v", sum)
+	}
+
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName), Project: domain.Project{Name: "Current"}}
+	if _, err := RestoreBackup(ph, old); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if ph.Project.Name != "Older" {
+		t.Fatalf("handle not updated: %q", ph.Project.Name)
+	}
+	// The manifest that was current before the restore must now exist as a backup.
+	list, _ := ListBackups(root)
+	foundCurrent := false
+	for _, b := range list {
+		if s, _ := ReadBackupSummary(b.Path); s.Name == "Current" {
+			foundCurrent = true
+		}
+	}
+	if !foundCurrent {
+		t.Fatalf("expected snapshot of previous manifest among %d backups", len(list))
+	}
+
+	if _, err := RestoreBackup(ph, ph.ManifestPath); err == nil || !strings.Contains(err.Error(), "not a manifest backup") {
+		t.Fatalf("expected rejection of non-backup path, got %v", err)
+	}
+	outside := filepath.Join(t.TempDir(), filepath.Base(old))
+	if err := DeleteBackup(root, outside); err == nil {
+		t.Fatalf("expected rejection of backup outside project")
+	}
+	if err := DeleteBackup(root, old); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Fatalf("backup should be deleted")
+	}
+}
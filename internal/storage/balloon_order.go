@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"sort"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/vector"
+)
+
+// balloonRank is the reading position of balloons[i]: its Order, or its slice position (from 1) when
+// it has none.
+func balloonRank(balloons []domain.Balloon, i int) int {
+	if o := balloons[i].Order; o > 0 {
+		return o
+	}
+	return i + 1
+}
+
+// balloonOrderIndices returns the indices of balloons in reading order; balloons of equal rank keep
+// their slice order.
+func balloonOrderIndices(balloons []domain.Balloon) []int {
+	idx := make([]int, len(balloons))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return balloonRank(balloons, idx[a]) < balloonRank(balloons, idx[b]) })
+	return idx
+}
+
+// BalloonsInOrder returns a copy of a panel's balloons in reading order (see domain.Balloon.Order).
+// Exporters that write the lettering as text use it; drawing keeps the slice order.
+func BalloonsInOrder(balloons []domain.Balloon) []domain.Balloon {
+	out := make([]domain.Balloon, 0, len(balloons))
+	for _, i := range balloonOrderIndices(balloons) {
+		out = append(out, balloons[i])
+	}
+	return out
+}
+
+// BalloonOrderNumbers maps each balloon ID of a panel to its reading position from 1.
+func BalloonOrderNumbers(balloons []domain.Balloon) map[string]int {
+	out := make(map[string]int, len(balloons))
+	for n, i := range balloonOrderIndices(balloons) {
+		out[balloons[i].ID] = n + 1
+	}
+	return out
+}
+
+// NextBalloonOrder is the Order for a balloon added to the end of a panel's reading order.
+func NextBalloonOrder(balloons []domain.Balloon) int {
+	next := len(balloons) + 1
+	for i := range balloons {
+		if r := balloonRank(balloons, i); r >= next {
+			next = r + 1
+		}
+	}
+	return next
+}
+
+// MoveBalloonOrder moves a balloon delta places earlier (negative) or later in its panel's reading
+// order and numbers the panel's balloons from 1. It reports false, changing nothing, when the balloon
+// already is first or last. The change is in memory only.
+func MoveBalloonOrder(ph *ProjectHandle, pageNumber int, panelID, balloonID string, delta int) (bool, error) {
+	_, _, pn, err := findPanel(ph, pageNumber, panelID)
+	if err != nil {
+		return false, err
+	}
+	idx := balloonOrderIndices(pn.Balloons)
+	pos := -1
+	for n, i := range idx {
+		if pn.Balloons[i].ID == balloonID {
+			pos = n
+		}
+	}
+	if pos < 0 {
+		return false, fmt.Errorf("balloon %s not found in panel %s on page %d", balloonID, panelID, pageNumber)
+	}
+	to := min(max(pos+delta, 0), len(idx)-1)
+	if to == pos {
+		return false, nil
+	}
+	moved := idx[pos]
+	idx = append(idx[:pos], idx[pos+1:]...)
+	idx = append(idx[:to], append([]int{moved}, idx[to:]...)...)
+	for n, i := range idx {
+		pn.Balloons[i].Order = n + 1
+	}
+	return true, nil
+}
+
+// AutoOrderBalloons numbers the balloons of a panel, or of every panel of the page when panelID is
+// empty, by their position on the page: rows from top to bottom, read in the issue's reading
+// direction (see vector.ReadingOrder). It returns how many balloons got a different reading
+// position. The change is in memory only.
+func AutoOrderBalloons(ph *ProjectHandle, pageNumber int, panelID string) (int, error) {
+	if ph == nil {
+		return 0, fmt.Errorf("project handle is nil")
+	}
+	for i := range ph.Project.Issues {
+		iss := &ph.Project.Issues[i]
+		j := pageIndexByNumber(*iss, pageNumber)
+		if j < 0 {
+			continue
+		}
+		pg := &iss.Pages[j]
+		rtl := iss.ReadingDirection == "rtl"
+		changed, found := 0, panelID == ""
+		for k := range pg.Panels {
+			pn := &pg.Panels[k]
+			if panelID != "" && pn.ID != panelID {
+				continue
+			}
+			found = true
+			changed += autoOrderPanel(pn.Balloons, rtl)
+		}
+		if !found {
+			return 0, panelNotFound(panelID, pageNumber)
+		}
+		return changed, nil
+	}
+	return 0, pageNotFound(pageNumber)
+}
+
+// autoOrderPanel numbers balloons by position and returns how many changed their reading position.
+func autoOrderPanel(balloons []domain.Balloon, rtl bool) int {
+	before := BalloonOrderNumbers(balloons)
+	rects := make([]vector.Rect, len(balloons))
+	for i, b := range balloons {
+		r := b.Shape.Rect
+		rects[i] = vector.R(float32(r.X), float32(r.Y), float32(r.Width), float32(r.Height))
+	}
+	changed := 0
+	for n, i := range vector.ReadingOrder(rects, rtl) {
+		balloons[i].Order = n + 1
+		if before[balloons[i].ID] != n+1 {
+			changed++
+		}
+	}
+	return changed
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func orderedIDs(balloons []domain.Balloon) []string {
+	var out []string
+	for _, b := range BalloonsInOrder(balloons) {
+		out = append(out, b.ID)
+	}
+	return out
+}
+
+func balloonAt(id string, x, y float64) domain.Balloon {
+	return domain.Balloon{ID: id, Shape: domain.Shape{Rect: domain.Rect{X: x, Y: y, Width: 60, Height: 30}}}
+}
+
+func TestBalloonsInOrder_DefaultsToSliceOrder(t *testing.T) {
+	old := []domain.Balloon{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	if got := orderedIDs(old); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("order without Order = %v", got)
+	}
+	mixed := []domain.Balloon{{ID: "a", Order: 3}, {ID: "b"}, {ID: "c", Order: 1}}
+	if got := orderedIDs(mixed); !reflect.DeepEqual(got, []string{"c", "b", "a"}) {
+		t.Fatalf("mixed order = %v", got)
+	}
+	if n := NextBalloonOrder(mixed); n != 4 {
+		t.Fatalf("NextBalloonOrder = %d, want 4", n)
+	}
+	if got := BalloonOrderNumbers(mixed); !reflect.DeepEqual(got, map[string]int{"c": 1, "b": 2, "a": 3}) {
+		t.Fatalf("BalloonOrderNumbers = %v", got)
+	}
+}
+
+func TestMoveBalloonOrder(t *testing.T) {
+	ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{{Number: 2, Panels: []domain.Panel{{
+		ID: "p1", Balloons: []domain.Balloon{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+	}}}}}}}}
+	balloons := func() []domain.Balloon { return ph.Project.Issues[0].Pages[0].Panels[0].Balloons }
+
+	if moved, err := MoveBalloonOrder(ph, 2, "p1", "c", -1); err != nil || !moved {
+		t.Fatalf("move earlier: %v, %v", moved, err)
+	}
+	if got := orderedIDs(balloons()); !reflect.DeepEqual(got, []string{"a", "c", "b"}) {
+		t.Fatalf("after moving c earlier: %v", got)
+	}
+	if moved, err := MoveBalloonOrder(ph, 2, "p1", "a", -1); err != nil || moved {
+		t.Fatalf("moving the first balloon earlier: %v, %v", moved, err)
+	}
+	if moved, err := MoveBalloonOrder(ph, 2, "p1", "a", 1); err != nil || !moved {
+		t.Fatalf("move later: %v, %v", moved, err)
+	}
+	if got := orderedIDs(balloons()); !reflect.DeepEqual(got, []string{"c", "a", "b"}) {
+		t.Fatalf("after moving a later: %v", got)
+	}
+	// The slice, which is the drawing order, is unchanged
+	if balloons()[0].ID != "a" || balloons()[2].ID != "c" {
+		t.Fatalf("slice reordered: %+v", balloons())
+	}
+	if _, err := MoveBalloonOrder(ph, 2, "p1", "zz", 1); err == nil {
+		t.Fatal("unknown balloon accepted")
+	}
+}
+
+func TestAutoOrderBalloons(t *testing.T) {
+	panels := func() []domain.Panel {
+		return []domain.Panel{
+			{ID: "p1", Balloons: []domain.Balloon{balloonAt("low", 10, 120), balloonAt("right", 100, 12), balloonAt("left", 10, 10)}},
+			{ID: "p2", Balloons: []domain.Balloon{balloonAt("x", 10, 10)}},
+		}
+	}
+	ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{
+		{Pages: []domain.Page{{Number: 1, Panels: panels()}}},
+		{ReadingDirection: "rtl", Pages: []domain.Page{{Number: 7, Panels: panels()}}},
+	}}}
+
+	n, err := AutoOrderBalloons(ph, 1, "")
+	if err != nil || n != 2 {
+		t.Fatalf("auto-order page 1: %d, %v", n, err)
+	}
+	if got := orderedIDs(ph.Project.Issues[0].Pages[0].Panels[0].Balloons); !reflect.DeepEqual(got, []string{"left", "right", "low"}) {
+		t.Fatalf("ltr order = %v", got)
+	}
+	if n, _ := AutoOrderBalloons(ph, 1, ""); n != 0 {
+		t.Fatalf("second auto-order changed %d balloons", n)
+	}
+
+	if n, err := AutoOrderBalloons(ph, 7, "p1"); err != nil || n != 3 {
+		t.Fatalf("auto-order rtl panel: %d, %v", n, err)
+	}
+	if got := orderedIDs(ph.Project.Issues[1].Pages[0].Panels[0].Balloons); !reflect.DeepEqual(got, []string{"right", "left", "low"}) {
+		t.Fatalf("rtl order = %v", got)
+	}
+	if ph.Project.Issues[1].Pages[0].Panels[1].Balloons[0].Order != 0 {
+		t.Fatal("other panel numbered")
+	}
+	if _, err := AutoOrderBalloons(ph, 7, "nope"); err == nil {
+		t.Fatal("unknown panel accepted")
+	}
+	if _, err := AutoOrderBalloons(ph, 3, ""); err == nil {
+		t.Fatal("unknown page accepted")
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// Balloon shape kinds a preset can select. Burst and cloud are outlines of shouts and thought balloons;
+// exporters without curve support draw them as rectangles.
+const (
+	BalloonShapeEllipse    = "ellipse"
+	BalloonShapeRoundedBox = "roundedBox"
+	BalloonShapeRect       = "rect"
+	BalloonShapeBurst      = "burst"
+	BalloonShapeCloud      = "cloud"
+)
+
+// BalloonShapes lists the shape kinds of balloon presets in the order the preset dialog offers them.
+var BalloonShapes = []string{BalloonShapeEllipse, BalloonShapeRoundedBox, BalloonShapeRect, BalloonShapeBurst, BalloonShapeCloud}
+
+// BalloonStrokeStyles lists the stroke styles of balloon presets in the order the preset dialog offers them.
+var BalloonStrokeStyles = []string{domain.BalloonStrokeSolid, domain.BalloonStrokeDashed, domain.BalloonStrokeNone}
+
+// DefaultBalloonStyles returns the presets new projects start with.
+func DefaultBalloonStyles() []domain.BalloonStyle {
+	return []domain.BalloonStyle{
+		{Name: "Speech", Shape: BalloonShapeEllipse, Size: 12, Padding: 6},
+		{Name: "Thought", Shape: BalloonShapeCloud, Size: 12, Padding: 10},
+		{Name: "Shout", Shape: BalloonShapeBurst, StrokeWidth: 2, Size: 16, Padding: 14},
+		{Name: "Whisper", Shape: BalloonShapeEllipse, StrokeStyle: domain.BalloonStrokeDashed, Size: 10, Padding: 6},
+	}
+}
+
+// ValidateBalloonStyle checks that the preset has a name, a known shape and stroke style, and no
+// negative or non-finite sizes.
+func ValidateBalloonStyle(s domain.BalloonStyle) error {
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.New("balloon style name is required")
+	}
+	if s.Shape != "" && !slices.Contains(BalloonShapes, s.Shape) {
+		return fmt.Errorf("balloon style %q: unknown shape %q", s.Name, s.Shape)
+	}
+	switch s.StrokeStyle {
+	case "", domain.BalloonStrokeSolid, domain.BalloonStrokeDashed, domain.BalloonStrokeNone:
+	default:
+		return fmt.Errorf("balloon style %q: unknown stroke style %q; use solid, dashed or none", s.Name, s.StrokeStyle)
+	}
+	for _, f := range []struct {
+		name string
+		v    float64
+	}{{"radius", s.Radius}, {"stroke width", s.StrokeWidth}, {"font size", s.Size}, {"padding", s.Padding}} {
+		if f.v < 0 || math.IsNaN(f.v) || math.IsInf(f.v, 0) {
+			return fmt.Errorf("balloon style %q: %s %g must be a non-negative number", s.Name, f.name, f.v)
+		}
+	}
+	return nil
+}
+
+// FindBalloonStyle returns the preset with the given name (case-insensitive).
+func FindBalloonStyle(list []domain.BalloonStyle, name string) (domain.BalloonStyle, bool) {
+	if i := balloonStyleIndex(list, name); i >= 0 {
+		return list[i], true
+	}
+	return domain.BalloonStyle{}, false
+}
+
+// PutBalloonStyle adds the preset or replaces an existing one with the same name.
+// The change is in memory only; call Save to persist it.
+func PutBalloonStyle(ph *ProjectHandle, s domain.BalloonStyle) error {
+	if ph == nil {
+		return errors.New("nil ProjectHandle")
+	}
+	s.Name = strings.TrimSpace(s.Name)
+	if err := ValidateBalloonStyle(s); err != nil {
+		return err
+	}
+	if i := balloonStyleIndex(ph.Project.BalloonStyles, s.Name); i >= 0 {
+		ph.Project.BalloonStyles[i] = s
+		return nil
+	}
+	ph.Project.BalloonStyles = append(ph.Project.BalloonStyles, s)
+	return nil
+}
+
+// DeleteBalloonStyle removes the named preset. Balloons that use it keep their shape and fall back to
+// the default stroke and fill. The change is in memory only; call Save to persist it.
+func DeleteBalloonStyle(ph *ProjectHandle, name string) error {
+	if ph == nil {
+		return errors.New("nil ProjectHandle")
+	}
+	i := balloonStyleIndex(ph.Project.BalloonStyles, name)
+	if i < 0 {
+		return fmt.Errorf("balloon style %q not found", name)
+	}
+	ph.Project.BalloonStyles = append(ph.Project.BalloonStyles[:i], ph.Project.BalloonStyles[i+1:]...)
+	return nil
+}
+
+// MergeBalloonStyles adds the presets whose names the project does not use yet, e.g. from a style pack,
+// like MergePageTemplates. It returns the names of the added presets and of the skipped ones (existing
+// or invalid). The change is in memory only; call Save to persist it.
+func MergeBalloonStyles(ph *ProjectHandle, list []domain.BalloonStyle) (added, skipped []string) {
+	if ph == nil {
+		return nil, nil
+	}
+	for _, s := range list {
+		s.Name = strings.TrimSpace(s.Name)
+		if balloonStyleIndex(ph.Project.BalloonStyles, s.Name) >= 0 || PutBalloonStyle(ph, s) != nil {
+			skipped = append(skipped, s.Name)
+			continue
+		}
+		added = append(added, s.Name)
+	}
+	return added, skipped
+}
+
+// ApplyBalloonStyle makes b use the preset: it takes the preset's shape and, where the preset sets
+// them, its font and size for every text run, and refers to the preset for stroke, fill and padding.
+func ApplyBalloonStyle(b *domain.Balloon, s domain.BalloonStyle) {
+	b.StyleRef = s.Name
+	b.Shape.Kind = s.Shape
+	if b.Shape.Kind == "" {
+		b.Shape.Kind = BalloonShapeEllipse
+	}
+	b.Shape.Radius = s.Radius
+	for i := range b.TextRuns {
+		if s.Font != "" {
+			b.TextRuns[i].Font = s.Font
+		}
+		if s.Size > 0 {
+			b.TextRuns[i].Size = s.Size
+		}
+	}
+}
+
+func balloonStyleIndex(list []domain.BalloonStyle, name string) int {
+	name = strings.TrimSpace(name)
+	for i, s := range list {
+		if strings.EqualFold(s.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
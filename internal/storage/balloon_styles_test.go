@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestDefaultBalloonStylesAreValid(t *testing.T) {
+	var names []string
+	for _, s := range DefaultBalloonStyles() {
+		if err := ValidateBalloonStyle(s); err != nil {
+			t.Errorf("default style %q: %v", s.Name, err)
+		}
+		names = append(names, s.Name)
+	}
+	if want := []string{"Speech", "Thought", "Shout", "Whisper"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("default styles = %v, want %v", names, want)
+	}
+}
+
+func TestInitProjectAddsDefaultBalloonStyles(t *testing.T) {
+	ph, err := InitProject(t.TempDir(), domain.Project{Name: "Styles"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ph.Project.BalloonStyles, DefaultBalloonStyles()) {
+		t.Errorf("balloon styles = %+v, want the defaults", ph.Project.BalloonStyles)
+	}
+	// A project that brings its own presets keeps them
+	own := []domain.BalloonStyle{{Name: "Robot", Shape: BalloonShapeRect}}
+	ph, err = InitProject(t.TempDir(), domain.Project{Name: "Own", BalloonStyles: own})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ph.Project.BalloonStyles, own) {
+		t.Errorf("balloon styles = %+v, want %+v", ph.Project.BalloonStyles, own)
+	}
+}
+
+func TestValidateBalloonStyle(t *testing.T) {
+	for _, s := range []domain.BalloonStyle{
+		{Name: " "},
+		{Name: "X", Shape: "star"},
+		{Name: "X", StrokeStyle: "dotted"},
+		{Name: "X", StrokeWidth: -1},
+		{Name: "X", Padding: -2},
+	} {
+		if ValidateBalloonStyle(s) == nil {
+			t.Errorf("ValidateBalloonStyle(%+v) = nil, want an error", s)
+		}
+	}
+	ok := domain.BalloonStyle{Name: "Whisper", Shape: BalloonShapeEllipse, StrokeStyle: domain.BalloonStrokeDashed, StrokeWidth: 1, Size: 10, Padding: 6}
+	if err := ValidateBalloonStyle(ok); err != nil {
+		t.Errorf("ValidateBalloonStyle(%+v) = %v", ok, err)
+	}
+}
+
+func TestPutDeleteMergeBalloonStyles(t *testing.T) {
+	ph := &ProjectHandle{}
+	if err := PutBalloonStyle(ph, domain.BalloonStyle{Name: " Speech ", Size: 12}); err != nil {
+		t.Fatal(err)
+	}
+	if err := PutBalloonStyle(ph, domain.BalloonStyle{Name: "speech", Size: 14}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ph.Project.BalloonStyles) != 1 || ph.Project.BalloonStyles[0].Size != 14 {
+		t.Fatalf("put should replace by name: %+v", ph.Project.BalloonStyles)
+	}
+	added, skipped := MergeBalloonStyles(ph, []domain.BalloonStyle{{Name: "SPEECH"}, {Name: "Shout", Shape: BalloonShapeBurst}, {Name: "Bad", Shape: "star"}})
+	if !reflect.DeepEqual(added, []string{"Shout"}) || !reflect.DeepEqual(skipped, []string{"SPEECH", "Bad"}) {
+		t.Errorf("merge added %v, skipped %v", added, skipped)
+	}
+	if s, ok := FindBalloonStyle(ph.Project.BalloonStyles, "shout"); !ok || s.Shape != BalloonShapeBurst {
+		t.Errorf("FindBalloonStyle(shout) = %+v, %v", s, ok)
+	}
+	if err := DeleteBalloonStyle(ph, "Speech"); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteBalloonStyle(ph, "Speech"); err == nil {
+		t.Error("deleting a missing style should fail")
+	}
+	if len(ph.Project.BalloonStyles) != 1 {
+		t.Errorf("styles after delete = %+v", ph.Project.BalloonStyles)
+	}
+}
+
+func TestSetBalloonStyle(t *testing.T) {
+	ph := &ProjectHandle{Project: domain.Project{
+		BalloonStyles: DefaultBalloonStyles(),
+		Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{{
+			ID:       "p1",
+			Balloons: []domain.Balloon{{ID: "b1", Shape: domain.Shape{Kind: "ellipse"}, TextRuns: []domain.TextRun{{Content: "HEY", Font: "Comic", Size: 12}}}},
+		}}}}}},
+	}}
+	if err := SetBalloonStyle(ph, 1, "p1", "b1", "shout"); err != nil {
+		t.Fatal(err)
+	}
+	b := ph.Project.Issues[0].Pages[0].Panels[0].Balloons[0]
+	if b.StyleRef != "Shout" || b.Shape.Kind != BalloonShapeBurst || b.TextRuns[0].Size != 16 || b.TextRuns[0].Font != "Comic" {
+		t.Errorf("balloon after Shout = %+v", b)
+	}
+	if err := SetBalloonStyle(ph, 1, "p1", "b1", "Scream"); err == nil {
+		t.Error("expected an error for a missing style")
+	}
+	if err := SetBalloonStyle(ph, 1, "p1", "b1", ""); err != nil {
+		t.Fatal(err)
+	}
+	b = ph.Project.Issues[0].Pages[0].Panels[0].Balloons[0]
+	if b.StyleRef != "" || b.Shape.Kind != BalloonShapeBurst {
+		t.Errorf("balloon after clearing the style = %+v", b)
+	}
+}
+
+func TestValidateProjectWarnsAboutUnknownBalloonStyle(t *testing.T) {
+	p := domain.Project{
+		BalloonStyles: []domain.BalloonStyle{{Name: "Speech"}, {Name: "Broken", StrokeStyle: "wavy"}},
+		Issues: []domain.Issue{{TrimWidth: 100, TrimHeight: 100, DPI: 300, ReadingDirection: "ltr", Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{{
+			ID: "p1", Geometry: domain.Rect{Width: 10, Height: 10},
+			Balloons: []domain.Balloon{{ID: "b1", StyleRef: "speech"}, {ID: "b2", StyleRef: "Gone", Shape: domain.Shape{Kind: "cloud"}}},
+		}}}}}},
+	}
+	var paths []string
+	for _, v := range ValidateProject(p) {
+		paths = append(paths, v.Path)
+	}
+	want := []string{"issues[0].pages[0].panels[0].balloons[1].styleRef", "balloonStyles[1]"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("validation paths = %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// findBalloon returns the balloon with balloonID in a panel of the page numbered pageNumber.
+func findBalloon(ph *ProjectHandle, pageNumber int, panelID, balloonID string) (*domain.Balloon, error) {
+	_, _, pn, err := findPanel(ph, pageNumber, panelID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pn.Balloons {
+		if pn.Balloons[i].ID == balloonID {
+			return &pn.Balloons[i], nil
+		}
+	}
+	return nil, fmt.Errorf("balloon %s not found in panel %s on page %d", balloonID, panelID, pageNumber)
+}
+
+// FindBalloon returns a copy of the balloon with balloonID in a panel of the page numbered pageNumber.
+func FindBalloon(ph *ProjectHandle, pageNumber int, panelID, balloonID string) (domain.Balloon, error) {
+	b, err := findBalloon(ph, pageNumber, panelID, balloonID)
+	if err != nil {
+		return domain.Balloon{}, err
+	}
+	out := *b
+	out.TextRuns = append([]domain.TextRun(nil), b.TextRuns...)
+	return out, nil
+}
+
+// UpdateBalloonText replaces the text runs of a balloon; exporters draw each run, and each line of a
+// run, on its own line. A negative font size is rejected, 0 uses the exporters' default. The change
+// is in memory: it reaches the search index with the next UpdateIndex, usually on save.
+func UpdateBalloonText(ph *ProjectHandle, pageNumber int, panelID, balloonID string, runs []domain.TextRun) error {
+	for _, r := range runs {
+		if r.Size < 0 {
+			return fmt.Errorf("font size %g must not be negative", r.Size)
+		}
+	}
+	b, err := findBalloon(ph, pageNumber, panelID, balloonID)
+	if err != nil {
+		return err
+	}
+	b.TextRuns = append([]domain.TextRun{}, runs...)
+	return nil
+}
+
+// SetBalloonStyle makes a balloon use the named balloon preset, see ApplyBalloonStyle; an empty name
+// detaches the balloon from its preset and keeps its shape and text. The change is in memory only.
+func SetBalloonStyle(ph *ProjectHandle, pageNumber int, panelID, balloonID, name string) error {
+	b, err := findBalloon(ph, pageNumber, panelID, balloonID)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		b.StyleRef = ""
+		return nil
+	}
+	s, ok := FindBalloonStyle(ph.Project.BalloonStyles, name)
+	if !ok {
+		return fmt.Errorf("balloon style %q not found", name)
+	}
+	ApplyBalloonStyle(b, s)
+	return nil
+}
+
+// BalloonMeta is what the inspector edits of a balloon besides its text: its type, speaking
+// character and box on the page in points.
+type BalloonMeta struct {
+	Type      string
+	Character string
+	Rect      domain.Rect
+}
+
+// UpdateBalloonMeta sets the type, speaking character and box of a balloon; moving a balloon is
+// changing the position of its box. An empty type or a box without area is rejected. The change is in
+// memory only.
+func UpdateBalloonMeta(ph *ProjectHandle, pageNumber int, panelID, balloonID string, meta BalloonMeta) error {
+	if strings.TrimSpace(meta.Type) == "" {
+		return fmt.Errorf("balloon type is required")
+	}
+	if meta.Rect.Width <= 0 || meta.Rect.Height <= 0 {
+		return fmt.Errorf("balloon size %gx%g must be positive", meta.Rect.Width, meta.Rect.Height)
+	}
+	b, err := findBalloon(ph, pageNumber, panelID, balloonID)
+	if err != nil {
+		return err
+	}
+	b.Type = strings.TrimSpace(meta.Type)
+	b.Character = strings.TrimSpace(meta.Character)
+	b.Shape.Rect = meta.Rect
+	return nil
+}
+
+// DeleteBalloon removes a balloon from its panel. The remaining balloons keep their reading order and
+// are numbered from 1 again when the panel uses reading positions. The change is in memory only.
+func DeleteBalloon(ph *ProjectHandle, pageNumber int, panelID, balloonID string) error {
+	_, _, pn, err := findPanel(ph, pageNumber, panelID)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(pn.Balloons, func(b domain.Balloon) bool { return b.ID == balloonID })
+	if i < 0 {
+		return fmt.Errorf("balloon %s not found in panel %s on page %d", balloonID, panelID, pageNumber)
+	}
+	pn.Balloons = slices.Delete(pn.Balloons, i, i+1)
+	if slices.ContainsFunc(pn.Balloons, func(b domain.Balloon) bool { return b.Order > 0 }) {
+		for n, j := range balloonOrderIndices(pn.Balloons) {
+			pn.Balloons[j].Order = n + 1
+		}
+	}
+	return nil
+}
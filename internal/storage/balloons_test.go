@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestUpdateBalloonText(t *testing.T) {
+	ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{{Number: 2, Panels: []domain.Panel{{
+		ID:       "p1",
+		Balloons: []domain.Balloon{{ID: "b1", Type: "speech", TextRuns: []domain.TextRun{{Content: "", Font: "Comic", Size: 12}}}},
+	}}}}}}}}
+
+	runs := []domain.TextRun{{Content: "Where is\nthe lighthouse?", Font: "Comic", Size: 14}}
+	if err := UpdateBalloonText(ph, 2, "p1", "b1", runs); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	runs[0].Content = "changed by the caller"
+	got := ph.Project.Issues[0].Pages[0].Panels[0].Balloons[0].TextRuns
+	if want := []domain.TextRun{{Content: "Where is\nthe lighthouse?", Font: "Comic", Size: 14}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("runs = %+v, want %+v", got, want)
+	}
+
+	b, err := FindBalloon(ph, 2, "p1", "b1")
+	if err != nil || b.Type != "speech" || !reflect.DeepEqual(b.TextRuns, got) {
+		t.Fatalf("FindBalloon = %+v, %v", b, err)
+	}
+	b.TextRuns[0].Content = "edited copy"
+	if got[0].Content == "edited copy" {
+		t.Error("FindBalloon shares its runs with the project")
+	}
+
+	// The text becomes a search document of the balloon
+	found := false
+	for _, d := range ManifestDocuments(ph.Project) {
+		if d.Path == "issue:1/page:2/panel:p1/balloon:b1" && d.Text == "Where is\nthe lighthouse?" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("balloon text not indexed: %+v", ManifestDocuments(ph.Project))
+	}
+
+	if err := UpdateBalloonText(ph, 2, "p1", "b9", runs); err == nil {
+		t.Error("expected an error for a missing balloon")
+	}
+	if err := UpdateBalloonText(ph, 3, "p1", "b1", runs); err == nil {
+		t.Error("expected an error for a missing page")
+	}
+	if err := UpdateBalloonText(ph, 2, "p1", "b1", []domain.TextRun{{Content: "x", Size: -1}}); err == nil {
+		t.Error("expected an error for a negative size")
+	}
+}
+
+func TestUpdateBalloonMeta(t *testing.T) {
+	ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{{
+		ID:       "p1",
+		Balloons: []domain.Balloon{{ID: "b1", Type: "speech", Shape: domain.Shape{Kind: "ellipse", Rect: domain.Rect{X: 10, Y: 10, Width: 80, Height: 40}}}},
+	}}}}}}}}
+
+	meta := BalloonMeta{Type: " thought ", Character: "Mara", Rect: domain.Rect{X: 30, Y: 50, Width: 80, Height: 40}}
+	if err := UpdateBalloonMeta(ph, 1, "p1", "b1", meta); err != nil {
+		t.Fatalf("UpdateBalloonMeta: %v", err)
+	}
+	b := ph.Project.Issues[0].Pages[0].Panels[0].Balloons[0]
+	if b.Type != "thought" || b.Character != "Mara" || b.Shape.Rect != meta.Rect || b.Shape.Kind != "ellipse" {
+		t.Fatalf("balloon = %+v", b)
+	}
+	for name, bad := range map[string]BalloonMeta{
+		"no type": {Rect: meta.Rect},
+		"no area": {Type: "speech", Rect: domain.Rect{X: 1, Y: 1}},
+	} {
+		if err := UpdateBalloonMeta(ph, 1, "p1", "b1", bad); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+	if err := UpdateBalloonMeta(ph, 1, "p1", "nope", meta); err == nil {
+		t.Error("unknown balloon: want error")
+	}
+}
+
+func TestDeleteBalloon(t *testing.T) {
+	ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{{
+		ID: "p1",
+		Balloons: []domain.Balloon{
+			{ID: "b1", Order: 3}, {ID: "b2", Order: 1}, {ID: "b3", Order: 2},
+		},
+	}}}}}}}}
+
+	if err := DeleteBalloon(ph, 1, "p1", "b2"); err != nil {
+		t.Fatalf("DeleteBalloon: %v", err)
+	}
+	got := ph.Project.Issues[0].Pages[0].Panels[0].Balloons
+	if want := []domain.Balloon{{ID: "b1", Order: 2}, {ID: "b3", Order: 1}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("balloons = %+v, want %+v", got, want)
+	}
+	if err := DeleteBalloon(ph, 1, "p1", "b2"); err == nil {
+		t.Error("deleting twice: want error")
+	}
+	if err := DeleteBalloon(ph, 1, "p9", "b1"); err == nil {
+		t.Error("unknown panel: want error")
+	}
+}
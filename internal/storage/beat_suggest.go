@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/script"
+)
+
+// DefaultBeatsPerPanel is how many beats SuggestBeatMappings lets a panel hold when no limit is given.
+const DefaultBeatsPerPanel = 1
+
+// BeatSuggestion proposes a panel for a beat of the script that no panel is linked to.
+type BeatSuggestion struct {
+	BeatID     string
+	LineNo     int    // script line of the beat
+	Marker     string // e.g. "PANEL 3" or "BEAT"
+	Text       string // without the colon after the marker
+	Scene      string // title of the beat's scene
+	PageNumber int
+	PanelID    string
+	// Confidence is between 0 and 1: highest for beats between two mapped beats of their own scene
+	// that fill the free panels between them one to one, lowest without any mapped beat to go by.
+	Confidence float64
+}
+
+// suggestBeat is a beat of the script in script order.
+type suggestBeat struct {
+	line  script.Line
+	scene int // index into the script's scenes
+	pos   int // reading position of the panel it is linked to, -1 when unmapped
+}
+
+// suggestPanel is a panel of the issue in reading order.
+type suggestPanel struct {
+	page int // page number
+	id   string
+	free int // beats it can still take
+}
+
+// SuggestBeatMappings proposes panels of the first issue for the unmapped beats of sc, without
+// changing the project. Beats keep their script order in the issue's reading order: the mapped beats
+// are anchors, and the unmapped beats between two anchors are spread evenly over the panels between
+// them. Where a new scene starts, its first beat moves to the start of the next page when the panels
+// left allow it, so scenes cover contiguous pages. A panel takes at most maxPerPanel beats, counting
+// those already linked to it (DefaultBeatsPerPanel when maxPerPanel is 0 or less); beats that do not
+// fit get no suggestion. Existing mappings are never changed and the result, in script order, only
+// depends on proj and sc.
+func SuggestBeatMappings(proj domain.Project, sc script.Script, maxPerPanel int) []BeatSuggestion {
+	if len(proj.Issues) == 0 {
+		return nil
+	}
+	if maxPerPanel <= 0 {
+		maxPerPanel = DefaultBeatsPerPanel
+	}
+	panels, posOf := suggestPanels(proj.Issues[0], maxPerPanel)
+	var beats []suggestBeat
+	for si, scn := range sc.Scenes {
+		for _, ln := range scn.Lines {
+			if ln.Type != script.LineBeat {
+				continue
+			}
+			pos, ok := posOf[BeatIDFor(ln)]
+			if !ok {
+				pos = -1
+			}
+			beats = append(beats, suggestBeat{line: ln, scene: si, pos: pos})
+		}
+	}
+	if len(panels) == 0 || len(beats) == 0 {
+		return nil
+	}
+	// Gaps are runs of unmapped beats, between the anchors before and after them. A mapped beat
+	// that reads before the anchor preceding it is no anchor: the gaps around it are merged.
+	var out []BeatSuggestion
+	prev := -1 // index of the anchor before the gap
+	for i := 0; i < len(beats); {
+		if beats[i].pos >= 0 && (prev < 0 || beats[i].pos >= beats[prev].pos) {
+			prev = i
+			i++
+			continue
+		}
+		var gap []int
+		next := -1
+		for ; i < len(beats); i++ {
+			b := beats[i]
+			if b.pos >= 0 && (prev < 0 || b.pos >= beats[prev].pos) {
+				next = i
+				break
+			}
+			if b.pos < 0 {
+				gap = append(gap, i)
+			}
+		}
+		out = append(out, suggestGap(sc, beats, panels, gap, prev, next)...)
+	}
+	return out
+}
+
+// suggestPanels lists the panels of iss in reading order with the beats each can still take, and the
+// reading position of every beat linked from the issue (the first panel linking it).
+func suggestPanels(iss domain.Issue, maxPerPanel int) ([]suggestPanel, map[string]int) {
+	pages := append([]domain.Page(nil), iss.Pages...)
+	sort.SliceStable(pages, func(i, j int) bool { return pages[i].Number < pages[j].Number })
+	var panels []suggestPanel
+	posOf := map[string]int{}
+	for _, pg := range pages {
+		byID := make(map[string]domain.Panel, len(pg.Panels))
+		for _, pn := range pg.Panels {
+			byID[pn.ID] = pn
+		}
+		for _, id := range SpatialReadingOrder(pg, isRTL(iss)) {
+			pn := byID[id]
+			for _, b := range pn.BeatIDs {
+				if _, ok := posOf[b]; !ok && b != "" {
+					posOf[b] = len(panels)
+				}
+			}
+			panels = append(panels, suggestPanel{page: pg.Number, id: id, free: max(maxPerPanel-len(pn.BeatIDs), 0)})
+		}
+	}
+	return panels, posOf
+}
+
+// suggestGap spreads the unmapped beats gap over the free panels between the anchors prev and next
+// (-1 at the start or end of the script), taking the places it suggests from panels.
+func suggestGap(sc script.Script, beats []suggestBeat, panels []suggestPanel, gap []int, prev, next int) []BeatSuggestion {
+	lo, hi := 0, len(panels)-1
+	if prev >= 0 {
+		lo = beats[prev].pos
+	}
+	if next >= 0 {
+		hi = beats[next].pos
+	}
+	// Places are panel positions, each free panel once; when they are too few, each panel as often
+	// as it has room for beats.
+	var places []int
+	for p := lo; p <= hi; p++ {
+		if panels[p].free > 0 {
+			places = append(places, p)
+		}
+	}
+	shared := false
+	if len(places) < len(gap) {
+		places, shared = places[:0], true
+		for p := lo; p <= hi; p++ {
+			for range panels[p].free {
+				places = append(places, p)
+			}
+		}
+	}
+	if len(places) == 0 {
+		return nil
+	}
+	gap = gap[:min(len(gap), len(places))]
+
+	base := 0.3
+	switch {
+	case prev >= 0 && next >= 0:
+		base = 0.7
+		if beats[prev].scene == beats[next].scene {
+			base = 0.85
+		}
+	case prev >= 0 || next >= 0:
+		base = 0.5
+	}
+	conf := base * (0.6 + 0.4*float64(len(gap))/float64(len(places)))
+	if shared {
+		conf *= 0.9
+	}
+	conf = math.Round(conf*100) / 100
+
+	prevPage, prevScene := 0, -1
+	if prev >= 0 {
+		prevPage, prevScene = panels[beats[prev].pos].page, beats[prev].scene
+	}
+	out := make([]BeatSuggestion, 0, len(gap))
+	segStart, segBeat := 0, 0 // the places from segStart on are shared evenly by the beats from segBeat on
+	for k, bi := range gap {
+		b := beats[bi]
+		j := segStart + (k-segBeat)*(len(places)-segStart)/(len(gap)-segBeat)
+		if b.scene != prevScene && panels[places[j]].page == prevPage {
+			// A new scene starts on a new page if enough places are left after it.
+			for s := j + 1; s <= len(places)-(len(gap)-k); s++ {
+				if panels[places[s]].page != prevPage {
+					j, segStart, segBeat = s, s, k
+					break
+				}
+			}
+		}
+		p := &panels[places[j]]
+		p.free--
+		out = append(out, BeatSuggestion{
+			BeatID:     BeatIDFor(b.line),
+			LineNo:     b.line.LineNo,
+			Marker:     b.line.Character,
+			Text:       strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(b.line.Text), ":")),
+			Scene:      sc.Scenes[b.scene].Title,
+			PageNumber: p.page,
+			PanelID:    p.id,
+			Confidence: conf,
+		})
+		prevPage, prevScene = p.page, b.scene
+	}
+	return out
+}
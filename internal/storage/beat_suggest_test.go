@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/script"
+)
+
+// suggestTestIssue returns pages numbered from 1 with the given panel IDs in one row each, listed
+// out of reading order to show that geometry decides.
+func suggestTestIssue(pages ...[]string) domain.Issue {
+	var iss domain.Issue
+	for i, ids := range pages {
+		pg := domain.Page{Number: i + 1}
+		for k := len(ids) - 1; k >= 0; k-- {
+			pg.Panels = append(pg.Panels, domain.Panel{ID: ids[k], Geometry: domain.Rect{X: float64(k) * 100, Width: 90, Height: 90}})
+		}
+		iss.Pages = append(iss.Pages, pg)
+	}
+	return iss
+}
+
+func parseSuggestScript(t *testing.T, src string) script.Script {
+	t.Helper()
+	sc, errs := script.Parse(src)
+	if len(errs) > 0 {
+		t.Fatalf("parse: %+v", errs)
+	}
+	return sc
+}
+
+// placed lists suggestions as "line->page/panel".
+func placed(ss []BeatSuggestion) []string {
+	var out []string
+	for _, s := range ss {
+		out = append(out, s.BeatID+"->"+strconv.Itoa(s.PageNumber)+"/"+s.PanelID)
+	}
+	return out
+}
+
+const suggestTwoScenes = `# Harbor
+PANEL 1: Gulls over the breakwater.
+PANEL 2: Mara climbs the stairs.
+PANEL 3: The lamp room.
+# Village
+PANEL 1: The square at night.
+PANEL 2: A shutter bangs.`
+
+func TestSuggestBeatMappings_SpreadsScenesOverPages(t *testing.T) {
+	sc := parseSuggestScript(t, suggestTwoScenes)
+	proj := domain.Project{Issues: []domain.Issue{suggestTestIssue([]string{"a1", "a2", "a3"}, []string{"b1", "b2", "b3"})}}
+	got := SuggestBeatMappings(proj, sc, 0)
+	want := []string{"b:2->1/a1", "b:3->1/a2", "b:4->1/a3", "b:6->2/b1", "b:7->2/b2"}
+	if p := placed(got); !reflect.DeepEqual(p, want) {
+		t.Fatalf("suggestions = %v, want %v", p, want)
+	}
+	first := got[0]
+	if first.Marker != "PANEL 1" || first.Text != "Gulls over the breakwater." || first.Scene != "Harbor" || first.LineNo != 2 || first.Confidence != 0.28 {
+		t.Fatalf("first suggestion = %+v", first)
+	}
+	if again := SuggestBeatMappings(proj, sc, 0); !reflect.DeepEqual(again, got) {
+		t.Fatalf("suggestions differ between runs:\n%+v\n%+v", got, again)
+	}
+}
+
+func TestSuggestBeatMappings_NewSceneStartsOnNextPage(t *testing.T) {
+	sc := parseSuggestScript(t, `# Harbor
+PANEL 1: Gulls.
+# Village
+PANEL 1: The square.
+PANEL 2: A shutter.
+PANEL 3: A dog barks.`)
+	proj := domain.Project{Issues: []domain.Issue{suggestTestIssue([]string{"a1", "a2", "a3"}, []string{"b1", "b2", "b3"})}}
+	want := []string{"b:2->1/a1", "b:4->2/b1", "b:5->2/b2", "b:6->2/b3"}
+	if p := placed(SuggestBeatMappings(proj, sc, 1)); !reflect.DeepEqual(p, want) {
+		t.Fatalf("suggestions = %v, want %v", p, want)
+	}
+}
+
+func TestSuggestBeatMappings_FillsBetweenAnchors(t *testing.T) {
+	sc := parseSuggestScript(t, `# Harbor
+PANEL 1: one
+PANEL 2: two
+PANEL 3: three
+PANEL 4: four
+PANEL 5: five
+PANEL 6: six`)
+	iss := suggestTestIssue([]string{"a1", "a2", "a3"}, []string{"b1", "b2", "b3"})
+	iss.Pages[0].Panels[2].BeatIDs = []string{"b:2"} // a1
+	iss.Pages[1].Panels[0].BeatIDs = []string{"b:7"} // b3
+	proj := domain.Project{Issues: []domain.Issue{iss}}
+	got := SuggestBeatMappings(proj, sc, 1)
+	want := []string{"b:3->1/a2", "b:4->1/a3", "b:5->2/b1", "b:6->2/b2"}
+	if p := placed(got); !reflect.DeepEqual(p, want) {
+		t.Fatalf("suggestions = %v, want %v", p, want)
+	}
+	for _, s := range got {
+		if s.Confidence != 0.85 {
+			t.Errorf("%s confidence = %v, want 0.85 between anchors of its scene", s.BeatID, s.Confidence)
+		}
+	}
+	// The anchors are left alone
+	if ids := proj.Issues[0].Pages[0].Panels[2].BeatIDs; !reflect.DeepEqual(ids, []string{"b:2"}) {
+		t.Fatalf("anchor panel changed: %v", ids)
+	}
+}
+
+func TestSuggestBeatMappings_PanelCapacity(t *testing.T) {
+	sc := parseSuggestScript(t, suggestTwoScenes)
+	iss := suggestTestIssue([]string{"a1", "a2"})
+	iss.Pages[0].Panels[0].BeatIDs = []string{"b:99"} // a2 holds a beat that is not in the script
+	proj := domain.Project{Issues: []domain.Issue{iss}}
+	if got := SuggestBeatMappings(proj, sc, 1); len(got) != 1 || got[0].PanelID != "a1" {
+		t.Fatalf("one beat per panel = %+v, want only b:2 in a1", got)
+	}
+	got := SuggestBeatMappings(proj, sc, 2)
+	want := []string{"b:2->1/a1", "b:3->1/a1", "b:4->1/a2"}
+	if p := placed(got); !reflect.DeepEqual(p, want) {
+		t.Fatalf("two beats per panel = %v, want %v", p, want)
+	}
+	if got[0].Confidence != 0.27 {
+		t.Errorf("shared panel confidence = %v, want 0.27", got[0].Confidence)
+	}
+	if got := SuggestBeatMappings(domain.Project{}, sc, 0); got != nil {
+		t.Errorf("no issue = %+v", got)
+	}
+}
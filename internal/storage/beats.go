@@ -89,10 +89,108 @@ func MapBeatToPanel(ph *ProjectHandle, pageNumber int, panelID string, beatID st
 				pn.BeatIDs = append(pn.BeatIDs, beatID)
 				return nil
 			}
-			return fmt.Errorf("panel %s not found on page %d", panelID, pageNumber)
+			return panelNotFound(panelID, pageNumber)
 		}
 	}
-	return fmt.Errorf("page %d not found", pageNumber)
+	return pageNotFound(pageNumber)
+}
+
+// CaptionLineIDFor returns a stable identifier for a CAPTION/NARRATION script line.
+// Like beats, it is keyed by the absolute source line number. Format: c:<lineNo> (e.g., c:17)
+func CaptionLineIDFor(ln script.Line) string {
+	return fmt.Sprintf("c:%d", ln.LineNo)
+}
+
+// ComputeUnassignedCaptionLines returns caption line IDs present in the parsed script that are not
+// linked from any caption object in the given project.
+func ComputeUnassignedCaptionLines(sc script.Script, p domain.Project) []string {
+	assigned := make(map[string]struct{})
+	for _, iss := range p.Issues {
+		for _, pg := range iss.Pages {
+			for _, pn := range pg.Panels {
+				for _, c := range pn.Captions {
+					if c.ScriptLine != "" {
+						assigned[c.ScriptLine] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+	var out []string
+	for _, scn := range sc.Scenes {
+		for _, ln := range scn.Lines {
+			if ln.Type == script.LineCaption {
+				id := CaptionLineIDFor(ln)
+				if _, ok := assigned[id]; !ok {
+					out = append(out, id)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// AssignCaptionLine links a script caption line to a caption object in the specified panel.
+// captionID selects the caption; when empty, the first caption without a linked line is used, and a
+// new caption is created in the panel's top-left corner if none is free. When text is non-empty it
+// replaces the caption text. Returns the ID of the caption that received the line.
+func AssignCaptionLine(ph *ProjectHandle, pageNumber int, panelID, captionID, lineID, text string) (string, error) {
+	if ph == nil {
+		return "", fmt.Errorf("project handle is nil")
+	}
+	if lineID == "" {
+		return "", fmt.Errorf("lineID is empty")
+	}
+	for i := range ph.Project.Issues {
+		iss := &ph.Project.Issues[i]
+		for j := range iss.Pages {
+			pg := &iss.Pages[j]
+			if pg.Number != pageNumber {
+				continue
+			}
+			for k := range pg.Panels {
+				pn := &pg.Panels[k]
+				if pn.ID != panelID {
+					continue
+				}
+				idx := -1
+				for ci := range pn.Captions {
+					c := pn.Captions[ci]
+					if (captionID != "" && c.ID == captionID) || (captionID == "" && c.ScriptLine == "") {
+						idx = ci
+						break
+					}
+				}
+				if idx < 0 {
+					if captionID != "" {
+						return "", fmt.Errorf("caption %s not found in panel %s", captionID, panelID)
+					}
+					g := pn.Geometry
+					w := g.Width - 16
+					if w > 200 {
+						w = 200
+					}
+					if w < 0 {
+						w = 0
+					}
+					pn.Captions = append(pn.Captions, domain.Caption{
+						ID:   NewCaptionID(ph),
+						Rect: domain.Rect{X: g.X + 8, Y: g.Y + 8, Width: w, Height: 40},
+						Size: 11,
+					})
+					idx = len(pn.Captions) - 1
+				}
+				c := &pn.Captions[idx]
+				c.ScriptLine = lineID
+				if text != "" {
+					c.Text = text
+				}
+				return c.ID, nil
+			}
+			return "", panelNotFound(panelID, pageNumber)
+		}
+	}
+	return "", pageNotFound(pageNumber)
 }
 
 // PageBeatCoverage summarizes beat counts per page and per panel.
@@ -175,3 +273,43 @@ func ComputePageTurnIndicators(iss domain.Issue) []PageTurnInfo {
 	sort.Slice(out, func(i, j int) bool { return out[i].PageNumber < out[j].PageNumber })
 	return out
 }
+
+// SceneStart is where a script scene begins in an issue: the page of its first mapped beat.
+type SceneStart struct {
+	Title  string
+	LineNo int // line of the scene heading
+	Page   int // page number
+}
+
+// ComputeSceneStarts maps the scene headings of sc to pages of iss by their first beat (in script
+// order) that is linked from a panel of the issue. A beat linked from several panels counts on the
+// lowest page number. Scenes without a mapped beat, and lines before the first heading, are left out.
+// The result is in script order.
+func ComputeSceneStarts(sc script.Script, iss domain.Issue) []SceneStart {
+	beatPage := make(map[string]int)
+	for _, pg := range iss.Pages {
+		for _, pn := range pg.Panels {
+			for _, id := range pn.BeatIDs {
+				if n, ok := beatPage[id]; id != "" && (!ok || pg.Number < n) {
+					beatPage[id] = pg.Number
+				}
+			}
+		}
+	}
+	var out []SceneStart
+	for _, scn := range sc.Scenes {
+		if scn.LineNo <= 0 {
+			continue
+		}
+		for _, ln := range scn.Lines {
+			if ln.Type != script.LineBeat {
+				continue
+			}
+			if n, ok := beatPage[BeatIDFor(ln)]; ok {
+				out = append(out, SceneStart{Title: scn.Title, LineNo: scn.LineNo, Page: n})
+				break
+			}
+		}
+	}
+	return out
+}
@@ -102,3 +102,96 @@ func TestMapBeatToPanelAddsUnique(t *testing.T) {
 		t.Fatalf("unexpected mapping content: %+v", got)
 	}
 }
+
+func TestAssignCaptionLine(t *testing.T) {
+	txt := `# Scene One
+CAPTION: Meanwhile, across town
+ALICE: Hello there
+NARRATION: Later that night`
+	sc, errs := script.Parse(txt)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parse errors: %+v", errs)
+	}
+	ph := &ProjectHandle{Project: domain.Project{
+		Issues: []domain.Issue{{
+			Pages: []domain.Page{{
+				Number: 1,
+				Panels: []domain.Panel{{ID: "p1", Geometry: domain.Rect{X: 10, Y: 10, Width: 300, Height: 200}}},
+			}},
+		}},
+	}}
+	ids := ComputeUnassignedCaptionLines(sc, ph.Project)
+	if len(ids) != 2 || ids[0] != "c:2" || ids[1] != "c:4" {
+		t.Fatalf("unexpected unassigned caption lines: %v", ids)
+	}
+
+	// No caption exists yet: one is created and receives the text.
+	cid, err := AssignCaptionLine(ph, 1, "p1", "", ids[0], "Meanwhile, across town")
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	caps := ph.Project.Issues[0].Pages[0].Panels[0].Captions
+	if len(caps) != 1 || caps[0].ID != cid || caps[0].ScriptLine != "c:2" || caps[0].Text != "Meanwhile, across town" {
+		t.Fatalf("unexpected captions: %+v", caps)
+	}
+	if caps[0].Rect.Width != 200 || caps[0].Rect.X != 18 {
+		t.Fatalf("unexpected default caption rect: %+v", caps[0].Rect)
+	}
+	if ids := ComputeUnassignedCaptionLines(sc, ph.Project); len(ids) != 1 || ids[0] != "c:4" {
+		t.Fatalf("expected only c:4 unassigned, got %v", ids)
+	}
+
+	// Re-assigning an explicit caption keeps the count stable.
+	if _, err := AssignCaptionLine(ph, 1, "p1", cid, "c:4", ""); err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	caps = ph.Project.Issues[0].Pages[0].Panels[0].Captions
+	if len(caps) != 1 || caps[0].ScriptLine != "c:4" || caps[0].Text != "Meanwhile, across town" {
+		t.Fatalf("unexpected captions after reassign: %+v", caps)
+	}
+
+	if _, err := AssignCaptionLine(ph, 1, "p1", "missing", "c:2", ""); err == nil {
+		t.Fatalf("expected error for unknown caption")
+	}
+	if _, err := AssignCaptionLine(ph, 2, "p1", "", "c:2", ""); err == nil {
+		t.Fatalf("expected error for unknown page")
+	}
+}
+
+func TestComputeSceneStarts(t *testing.T) {
+	txt := `ALICE: Before any scene
+Beat Cold open
+# Rooftop
+Beat Wide shot
+Beat Close-up
+# Alley
+Beat Nothing mapped here
+# Harbor
+ALICE: No beats at all
+# Station
+Beat Train arrives`
+	sc, errs := script.Parse(txt)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parse errors: %+v", errs)
+	}
+	// Line numbers: cold open 2, wide shot 4, close-up 5, alley beat 7, train 11
+	iss := domain.Issue{Pages: []domain.Page{
+		{Number: 1, Panels: []domain.Panel{{ID: "p1", BeatIDs: []string{"b:2"}}}},
+		{Number: 2, Panels: []domain.Panel{{ID: "p1", BeatIDs: []string{"b:5"}}}},
+		{Number: 3, Panels: []domain.Panel{{ID: "p1", BeatIDs: []string{"b:11"}}, {ID: "p2", BeatIDs: []string{"b:4"}}}},
+		{Number: 4, Panels: []domain.Panel{{ID: "p1", BeatIDs: []string{"b:4"}}}},
+	}}
+	got := ComputeSceneStarts(sc, iss)
+	want := []SceneStart{
+		{Title: "Rooftop", LineNo: 3, Page: 3},
+		{Title: "Station", LineNo: 10, Page: 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("scene starts = %+v; want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("scene start %d = %+v; want %+v", i, got[i], want[i])
+		}
+	}
+}
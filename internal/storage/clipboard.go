@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gocomicwriter/internal/domain"
+)
+
+// ClipboardFormat marks clipboard data written by CopyPanel and CopyBalloon.
+const ClipboardFormat = "gocomicwriter.clipboard/1"
+
+// ClipboardPasteOffset is how far, in points, a pasted panel or balloon is moved right and down from
+// the copied one so the copy does not hide the original.
+const ClipboardPasteOffset = 12.0
+
+// ErrNotClipboardData is returned by ParseClipboard for data that is not a copied panel or balloon.
+var ErrNotClipboardData = errors.New("clipboard does not hold a panel or balloon")
+
+// Clipboard is a copied panel or balloon. The JSON holds no references to the project it came from,
+// so it can be pasted into any project open later in the same session.
+type Clipboard struct {
+	Format  string          `json:"format"`
+	Panel   *domain.Panel   `json:"panel,omitempty"`
+	Balloon *domain.Balloon `json:"balloon,omitempty"`
+}
+
+// CopyPanel serializes a panel with its balloons, captions and SFX for the clipboard.
+func CopyPanel(p domain.Panel) ([]byte, error) {
+	return json.Marshal(Clipboard{Format: ClipboardFormat, Panel: &p})
+}
+
+// CopyBalloon serializes a balloon for the clipboard.
+func CopyBalloon(b domain.Balloon) ([]byte, error) {
+	return json.Marshal(Clipboard{Format: ClipboardFormat, Balloon: &b})
+}
+
+// ParseClipboard decodes data written by CopyPanel or CopyBalloon. Exactly one of Panel and Balloon
+// is set in the result.
+func ParseClipboard(data []byte) (Clipboard, error) {
+	var c Clipboard
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Clipboard{}, fmt.Errorf("%w: %w", ErrNotClipboardData, err)
+	}
+	if c.Format != ClipboardFormat || (c.Panel == nil) == (c.Balloon == nil) {
+		return Clipboard{}, ErrNotClipboardData
+	}
+	return c, nil
+}
+
+// PastePanel adds a copy of p on top of the page numbered pageNumber, moved by ClipboardPasteOffset,
+// and returns it. The copy and its balloons get new project-unique IDs; geometry, notes, border, styles
+// and lettering are kept. Links to the script (beats and caption lines), production annotations and
+// the lock belong to the original panel and are not copied. The change is in memory only.
+func PastePanel(ph *ProjectHandle, pageNumber int, p domain.Panel) (domain.Panel, error) {
+	if ph == nil {
+		return domain.Panel{}, fmt.Errorf("project handle is nil")
+	}
+	const d = ClipboardPasteOffset
+	p.ID = ""
+	p.BeatIDs = nil
+	p.Annotations = nil
+	p.Locked = false
+	p.Geometry.X += d
+	p.Geometry.Y += d
+	p.Balloons = append([]domain.Balloon(nil), p.Balloons...)
+	for i := range p.Balloons {
+		b := &p.Balloons[i]
+		b.ID = NewBalloonID(ph)
+		b.TextRuns = append([]domain.TextRun(nil), b.TextRuns...)
+		b.Shape.Rect.X += d
+		b.Shape.Rect.Y += d
+	}
+	p.Captions = append([]domain.Caption(nil), p.Captions...)
+	for i := range p.Captions {
+		p.Captions[i].ScriptLine = ""
+		p.Captions[i].Rect.X += d
+		p.Captions[i].Rect.Y += d
+	}
+	p.SFX = append([]domain.SFXItem(nil), p.SFX...)
+	for i := range p.SFX {
+		p.SFX[i].Rect.X += d
+		p.SFX[i].Rect.Y += d
+	}
+	if p.Border != nil {
+		b := *p.Border
+		p.Border = &b
+	}
+	// AddPanel hands out the ID and stacks the copy above every panel of the page
+	return AddPanel(ph, pageNumber, p)
+}
+
+// PasteBalloon adds a copy of b to the panel, moved by ClipboardPasteOffset and read after the panel's
+// other balloons, and returns it. The copy gets a new project-unique ID. The change is in memory only.
+func PasteBalloon(ph *ProjectHandle, pageNumber int, panelID string, b domain.Balloon) (domain.Balloon, error) {
+	_, _, pn, err := findPanel(ph, pageNumber, panelID)
+	if err != nil {
+		return domain.Balloon{}, err
+	}
+	b.ID = NewBalloonID(ph)
+	b.TextRuns = append([]domain.TextRun(nil), b.TextRuns...)
+	b.Shape.Rect.X += ClipboardPasteOffset
+	b.Shape.Rect.Y += ClipboardPasteOffset
+	b.Order = NextBalloonOrder(pn.Balloons)
+	pn.Balloons = append(pn.Balloons, b)
+	return b, nil
+}
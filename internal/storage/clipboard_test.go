@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func clipboardTestPanel() domain.Panel {
+	return domain.Panel{
+		ID:       "p3",
+		Geometry: domain.Rect{X: 40, Y: 50, Width: 300, Height: 200},
+		ZOrder:   1,
+		BeatIDs:  []string{"beat-1"},
+		Notes:    "Wide shot of the harbour",
+		Border:   &domain.PanelBorder{Width: 2},
+		Locked:   true,
+		Balloons: []domain.Balloon{
+			{ID: "balloon-4", Type: "speech", Character: "Ada", StyleRef: "Shout", Order: 2,
+				TextRuns: []domain.TextRun{{Content: "Cast ", Font: "Comic", Size: 12}, {Content: "off!", Font: "Comic Bold", Size: 14, Tracking: 5}},
+				Shape:    domain.Shape{Kind: "ellipse", Rect: domain.Rect{X: 60, Y: 70, Width: 80, Height: 40}}},
+			{ID: "balloon-5", Type: "thought", Order: 1, TextRuns: []domain.TextRun{{Content: "Finally."}},
+				Shape: domain.Shape{Kind: "roundedBox", Rect: domain.Rect{X: 200, Y: 70, Width: 60, Height: 30}, Radius: 6}},
+		},
+		Captions: []domain.Caption{{ID: "caption-1", Text: "Dawn.", Rect: domain.Rect{X: 48, Y: 58, Width: 100, Height: 20}, ScriptLine: "cap-7"}},
+		SFX:      []domain.SFXItem{{ID: "sfx-1", Text: "SPLASH", Rect: domain.Rect{X: 100, Y: 200, Width: 90, Height: 30}, Rotation: 10}},
+	}
+}
+
+func TestClipboard_RoundTrip(t *testing.T) {
+	p := clipboardTestPanel()
+	data, err := CopyPanel(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := ParseClipboard(data)
+	if err != nil {
+		t.Fatalf("ParseClipboard: %v", err)
+	}
+	if c.Balloon != nil || c.Panel == nil || !reflect.DeepEqual(*c.Panel, p) {
+		t.Fatalf("panel round trip = %+v, want %+v", c.Panel, p)
+	}
+
+	b := p.Balloons[0]
+	if data, err = CopyBalloon(b); err != nil {
+		t.Fatal(err)
+	}
+	if c, err = ParseClipboard(data); err != nil {
+		t.Fatalf("ParseClipboard: %v", err)
+	}
+	if c.Panel != nil || c.Balloon == nil || !reflect.DeepEqual(*c.Balloon, b) {
+		t.Fatalf("balloon round trip = %+v, want %+v", c.Balloon, b)
+	}
+}
+
+func TestParseClipboard_Rejects(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"just some text",
+		`{"format":"other","panel":{"id":"p1"}}`,
+		`{"format":"` + ClipboardFormat + `"}`,
+		`{"format":"` + ClipboardFormat + `","panel":{"id":"p1"},"balloon":{"id":"b1"}}`,
+	} {
+		if _, err := ParseClipboard([]byte(data)); !errors.Is(err, ErrNotClipboardData) {
+			t.Errorf("ParseClipboard(%q) = %v, want ErrNotClipboardData", data, err)
+		}
+	}
+}
+
+func TestPastePanel(t *testing.T) {
+	src := clipboardTestPanel()
+	data, _ := CopyPanel(src)
+	c, _ := ParseClipboard(data)
+	// A different project than the one copied from
+	ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{
+		{ID: "p1", ZOrder: 0, Balloons: []domain.Balloon{{ID: "balloon-9"}}},
+		{ID: "p3", ZOrder: 4},
+	}}}}}}}
+	got, err := PastePanel(ph, 1, *c.Panel)
+	if err != nil {
+		t.Fatalf("PastePanel: %v", err)
+	}
+	pg := ph.Project.Issues[0].Pages[0]
+	if len(pg.Panels) != 3 || !reflect.DeepEqual(pg.Panels[2], got) {
+		t.Fatalf("pasted panel not appended: %+v", pg.Panels)
+	}
+	if got.ID == "" || got.ID == "p1" || got.ID == "p3" {
+		t.Fatalf("pasted panel ID = %q, want a new one", got.ID)
+	}
+	if got.ZOrder != 5 {
+		t.Fatalf("ZOrder = %d, want 5 (top of the stack)", got.ZOrder)
+	}
+	d := ClipboardPasteOffset
+	if got.Geometry != (domain.Rect{X: 40 + d, Y: 50 + d, Width: 300, Height: 200}) {
+		t.Fatalf("geometry = %+v", got.Geometry)
+	}
+	if got.BeatIDs != nil || got.Locked || got.Captions[0].ScriptLine != "" {
+		t.Fatalf("script links or lock copied: %+v", got)
+	}
+	if got.Notes != src.Notes || got.Border == nil || got.Border.Width != 2 || got.Captions[0].Text != "Dawn." || got.SFX[0].Rect.X != 100+d {
+		t.Fatalf("panel content not kept: %+v", got)
+	}
+	if len(got.Balloons) != 2 {
+		t.Fatalf("balloons = %+v", got.Balloons)
+	}
+	ids := map[string]bool{"balloon-9": true}
+	for i, b := range got.Balloons {
+		if ids[b.ID] || b.ID == src.Balloons[i].ID {
+			t.Fatalf("balloon ID %q not new", b.ID)
+		}
+		ids[b.ID] = true
+		want := src.Balloons[i]
+		want.ID = b.ID
+		want.Shape.Rect.X += d
+		want.Shape.Rect.Y += d
+		if !reflect.DeepEqual(b, want) {
+			t.Fatalf("balloon = %+v, want %+v", b, want)
+		}
+	}
+	// The pasted copy shares nothing with the clipboard panel
+	got.Balloons[0].TextRuns[0].Content = "changed"
+	if c.Panel.Balloons[0].TextRuns[0].Content != "Cast " {
+		t.Fatal("pasted text runs alias the clipboard")
+	}
+
+	if _, err := PastePanel(nil, 1, src); err == nil {
+		t.Fatal("expected an error without a project")
+	}
+}
+
+func TestPasteBalloon(t *testing.T) {
+	ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{{Number: 2, Panels: []domain.Panel{
+		{ID: "p1", Balloons: []domain.Balloon{{ID: "balloon-1", Order: 1}, {ID: "balloon-2", Order: 2}}},
+	}}}}}}}
+	src := clipboardTestPanel().Balloons[0]
+	got, err := PasteBalloon(ph, 2, "p1", src)
+	if err != nil {
+		t.Fatalf("PasteBalloon: %v", err)
+	}
+	if got.ID == src.ID || got.ID == "balloon-1" || got.ID == "balloon-2" || got.Order != 3 {
+		t.Fatalf("pasted balloon = %+v, want a new ID read last", got)
+	}
+	if got.Shape.Rect.X != src.Shape.Rect.X+ClipboardPasteOffset || !reflect.DeepEqual(got.TextRuns, src.TextRuns) || got.StyleRef != "Shout" {
+		t.Fatalf("pasted balloon = %+v", got)
+	}
+	if bs := ph.Project.Issues[0].Pages[0].Panels[0].Balloons; len(bs) != 3 || bs[2].ID != got.ID {
+		t.Fatalf("balloons = %+v", bs)
+	}
+	if _, err := PasteBalloon(ph, 2, "p9", src); !errors.Is(err, ErrPanelNotFound) {
+		t.Fatalf("PasteBalloon into a missing panel = %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/script"
+)
+
+// refTarget is a bible entity or asset document (by index path) and the lower-cased terms that reference it.
+// Line terms (asset markers, which the UI writes one per line) only match a whole trimmed line.
+type refTarget struct {
+	path     string
+	terms    []string
+	lineTerm bool
+}
+
+// crossRefSourceTypes are the document types scanned for references to bible entries and assets.
+var crossRefSourceTypes = map[string]bool{
+	"balloon":     true,
+	"caption":     true,
+	"page_notes":  true,
+	"panel_notes": true,
+	"scene":       true,
+	"script":      true,
+}
+
+// bibleRefTargets builds reference targets for characters (name plus aliases) and tags (@name).
+func bibleRefTargets(b domain.Bible) []refTarget {
+	var out []refTarget
+	for _, bc := range b.Characters {
+		name := stringsTrim(bc.Name)
+		if name == "" {
+			continue
+		}
+		t := refTarget{path: "bible:character:" + name}
+		for _, n := range append([]string{name}, bc.Aliases...) {
+			if n = strings.ToLower(stringsTrim(n)); n != "" {
+				t.terms = append(t.terms, n)
+			}
+		}
+		out = append(out, t)
+	}
+	for _, bt := range b.Tags {
+		name := strings.TrimPrefix(stringsTrim(bt.Name), "@")
+		if name == "" {
+			continue
+		}
+		out = append(out, refTarget{path: "bible:tag:" + stringsTrim(bt.Name), terms: []string{"@" + strings.ToLower(name)}})
+	}
+	return out
+}
+
+// sceneLocationLinks parses the script text and links its scenes to the bible's locations. It returns a
+// "scene" document per scene heading and the linked location name by document path: for each linked
+// scene, and for each panel whose first beat in a linked scene lies there (descendants of a panel are
+// looked up by panelDocPath).
+func sceneLocationLinks(proj domain.Project, src string) ([]IndexDocument, map[string]string) {
+	sc, _ := script.Parse(src)
+	sc = script.LinkLocations(sc, proj.Bible)
+	var docs []IndexDocument
+	locs := map[string]string{}
+	beatLoc := map[string]string{}
+	for _, scn := range sc.Scenes {
+		if scn.LineNo == 0 {
+			continue
+		}
+		d := IndexDocument{Type: "scene", Path: fmt.Sprintf("script:scene:%d", scn.LineNo), Text: scn.Title}
+		docs = append(docs, d)
+		if scn.Location == "" {
+			continue
+		}
+		locs[d.Path] = scn.Location
+		for _, ln := range scn.Lines {
+			if ln.Type == script.LineBeat {
+				beatLoc[BeatIDFor(ln)] = scn.Location
+			}
+		}
+	}
+	for _, iss := range proj.Issues {
+		for _, pg := range iss.Pages {
+			for _, pn := range pg.Panels {
+				for _, id := range pn.BeatIDs {
+					if l, ok := beatLoc[id]; ok {
+						locs[fmt.Sprintf("issue:1/page:%d/panel:%s", pg.Number, pn.ID)] = l
+						break
+					}
+				}
+			}
+		}
+	}
+	return docs, locs
+}
+
+// panelDocPath returns the path of the panel a balloon, caption or SFX document path belongs to, or
+// path itself.
+func panelDocPath(path string) string {
+	i := strings.Index(path, "/panel:")
+	if i < 0 {
+		return path
+	}
+	if j := strings.IndexByte(path[i+1:], '/'); j >= 0 {
+		return path[:i+1+j]
+	}
+	return path
+}
+
+// findRefTargets returns the paths of targets referenced in text (case-insensitive, word-bounded).
+func findRefTargets(text string, targets []refTarget) []string {
+	lt := strings.ToLower(text)
+	var out []string
+	for _, t := range targets {
+		for _, term := range t.terms {
+			if (t.lineTerm && containsLine(lt, term)) || (!t.lineTerm && containsWord(lt, term)) {
+				out = append(out, t.path)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// containsWord reports whether term occurs in s with non-word characters (or string edges) on both sides.
+// Both arguments are expected to be lower-cased already.
+func containsWord(s, term string) bool {
+	if term == "" {
+		return false
+	}
+	for from := 0; from <= len(s)-len(term); {
+		i := strings.Index(s[from:], term)
+		if i < 0 {
+			return false
+		}
+		start := from + i
+		end := start + len(term)
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if (start == 0 || !isWordRune(before)) && (end == len(s) || !isWordRune(after)) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(s[start:])
+		from = start + size
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'
+}
+
+// containsLine reports whether one of the lines of s, trimmed of surrounding space, equals term.
+func containsLine(s, term string) bool {
+	for _, ln := range strings.Split(s, "\n") {
+		if strings.TrimSpace(ln) == term {
+			return true
+		}
+	}
+	return false
+}
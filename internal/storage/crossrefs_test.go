@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestContainsWord(t *testing.T) {
+	cases := []struct {
+		s, term string
+		want    bool
+	}{
+		{"hello alice!", "alice", true},
+		{"alice: hi", "alice", true},
+		{"alicea: hi", "alice", false},
+		{"malice aforethought", "alice", false},
+		{"malice and alice", "alice", true},
+		{"über alice", "alice", true},
+		{"éalice", "alice", false},
+		{"see @greet now", "@greet", true},
+		{"see @greeting now", "@greet", false},
+		{"see @greet-all", "@greet", false},
+		{"mary jane was here", "mary jane", true},
+		{"", "alice", false},
+	}
+	for _, tc := range cases {
+		if got := containsWord(tc.s, tc.term); got != tc.want {
+			t.Errorf("containsWord(%q, %q) = %v, want %v", tc.s, tc.term, got, tc.want)
+		}
+	}
+}
+
+func whereUsedPaths(t *testing.T, ctx context.Context, root, path string) []string {
+	t.Helper()
+	res, err := WhereUsedByPath(ctx, root, path, 100, 0)
+	if err != nil {
+		t.Fatalf("WhereUsedByPath(%s): %v", path, err)
+	}
+	out := make([]string, 0, len(res))
+	for _, r := range res {
+		out = append(out, r.Path)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestCrossRefsFollowTextAcrossRebuilds(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "script"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "script", "script.txt"), []byte("# Scene 1\nALLY: Hi there @Night\n"), 0o644); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	proj := domain.Project{
+		Bible: domain.Bible{
+			Characters: []domain.BibleCharacter{{Name: "ALICE", Aliases: []string{"Ally"}}, {Name: "BOB"}},
+			Tags:       []domain.BibleTag{{Name: "night"}},
+		},
+		Issues: []domain.Issue{{Pages: []domain.Page{{Number: 2, Panels: []domain.Panel{{
+			ID:    "p1",
+			Notes: "Alice waits for bob",
+			Balloons: []domain.Balloon{
+				{ID: "b1", Character: "BOB", TextRuns: []domain.TextRun{{Content: "Where is everyone?"}}},
+				{ID: "b2", TextRuns: []domain.TextRun{{Content: "ALICEA is not Alice's alias"}}},
+			},
+		}}}}}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := UpdateIndex(ctx, root, proj); err != nil {
+		t.Fatalf("UpdateIndex: %v", err)
+	}
+	got := whereUsedPaths(t, ctx, root, "bible:character:ALICE")
+	want := []string{"issue:1/page:2/panel:p1", "issue:1/page:2/panel:p1/balloon:b2", "script:script.txt"}
+	if !equalStrings(got, want) {
+		t.Fatalf("ALICE refs = %v, want %v", got, want)
+	}
+	if got := whereUsedPaths(t, ctx, root, "bible:character:BOB"); !equalStrings(got, []string{"issue:1/page:2/panel:p1", "issue:1/page:2/panel:p1/balloon:b1"}) {
+		t.Fatalf("BOB refs = %v", got)
+	}
+	if got := whereUsedPaths(t, ctx, root, "bible:tag:night"); !equalStrings(got, []string{"script:script.txt"}) {
+		t.Fatalf("tag refs = %v", got)
+	}
+
+	// Change text: references must follow on the next rebuild.
+	proj.Issues[0].Pages[0].Panels[0].Notes = "An empty street"
+	proj.Issues[0].Pages[0].Panels[0].Balloons[1].TextRuns[0].Content = "ALICEA only"
+	if err := os.WriteFile(filepath.Join(root, "script", "script.txt"), []byte("# Scene 1\nBOB: Quiet\n"), 0o644); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	if err := UpdateIndex(ctx, root, proj); err != nil {
+		t.Fatalf("UpdateIndex 2: %v", err)
+	}
+	if got := whereUsedPaths(t, ctx, root, "bible:character:ALICE"); len(got) != 0 {
+		t.Fatalf("expected ALICE refs to disappear, got %v", got)
+	}
+	if got := whereUsedPaths(t, ctx, root, "bible:character:BOB"); !equalStrings(got, []string{"issue:1/page:2/panel:p1/balloon:b1", "script:script.txt"}) {
+		t.Fatalf("BOB refs after change = %v", got)
+	}
+	if got := whereUsedPaths(t, ctx, root, "bible:tag:night"); len(got) != 0 {
+		t.Fatalf("expected tag refs to disappear, got %v", got)
+	}
+}
+
+func TestSceneLocationLinks(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "script"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	src := "# Scene 1: Rooftop – Night\nBeat A kiss\n\n# The chase [loc:Back Alley]\nPanel 1 Running\n\n# Docks\nBeat Waves\n"
+	if err := os.WriteFile(filepath.Join(root, "script", "script.txt"), []byte(src), 0o644); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	proj := domain.Project{
+		Bible: domain.Bible{Locations: []domain.BibleLocation{{Name: "Rooftop", Aliases: []string{"Roof"}}, {Name: "Back Alley"}}},
+		Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{
+			{ID: "p1", BeatIDs: []string{"b:2"}, Balloons: []domain.Balloon{{ID: "b1", TextRuns: []domain.TextRun{{Content: "Finally."}}}}},
+			{ID: "p2", BeatIDs: []string{"b:5"}, Notes: "Feet on wet stones"},
+			{ID: "p3", BeatIDs: []string{"b:8"}, Notes: "Docks at dawn"},
+		}}}}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := UpdateIndex(ctx, root, proj); err != nil {
+		t.Fatalf("UpdateIndex: %v", err)
+	}
+	if got := whereUsedPaths(t, ctx, root, "bible:location:Rooftop"); !equalStrings(got, []string{"issue:1/page:1/panel:p1/balloon:b1", "script:scene:1"}) {
+		t.Fatalf("Rooftop refs = %v", got)
+	}
+	if got := whereUsedPaths(t, ctx, root, "bible:location:Back Alley"); !equalStrings(got, []string{"issue:1/page:1/panel:p2", "script:scene:4"}) {
+		t.Fatalf("Back Alley refs = %v", got)
+	}
+
+	search := func(scene string) []string {
+		res, err := Search(ctx, root, SearchQuery{Scene: scene})
+		if err != nil {
+			t.Fatalf("Search(%s): %v", scene, err)
+		}
+		var out []string
+		for _, r := range res {
+			out = append(out, r.Path)
+		}
+		sort.Strings(out)
+		return out
+	}
+	// By alias, exactly: the location's own documents and what is linked to it
+	want := []string{"bible:location:Rooftop", "bible:location_aliases:Rooftop", "issue:1/page:1/panel:p1/balloon:b1", "script:scene:1"}
+	if got := search("roof"); !equalStrings(got, want) {
+		t.Fatalf("Scene=roof: %v, want %v", got, want)
+	}
+	// The panel notes never mention the alley; only the link through the scene finds them
+	if got := search("back alley"); !equalStrings(got, []string{"bible:location:Back Alley", "issue:1/page:1/panel:p2", "script:scene:4"}) {
+		t.Fatalf("Scene=back alley: %v", got)
+	}
+	if got := search("docks"); !equalStrings(got, []string{"issue:1/page:1/panel:p3", "script:scene:7", "script:script.txt"}) {
+		t.Fatalf("unlinked Scene=docks: %v", got)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Failures callers can tell apart with errors.Is. Storage functions wrap them together with the
+// underlying cause, so the message keeps the technical detail (paths, OS and SQLite errors) and
+// errors.Is still matches both, e.g. ErrNotAProject and fs.ErrNotExist.
+var (
+	// ErrNotAProject means the folder has no project manifest (comic.json) and no backup of one.
+	ErrNotAProject = errors.New("not a project folder")
+	// ErrManifestCorrupt means a manifest or manifest backup could not be parsed.
+	ErrManifestCorrupt = errors.New("project manifest is corrupt")
+	// ErrBackupNotFound means a manifest backup, or any backup to fall back to, does not exist.
+	ErrBackupNotFound = errors.New("backup not found")
+	// ErrBackupChainBroken means a delta backup cannot be reconstructed because a backup it builds on is
+	// missing or damaged.
+	ErrBackupChainBroken = errors.New("backup chain is broken")
+	// ErrIndexCorrupt means SQLite reported the index database as damaged or not a database, and it
+	// could not be rebuilt.
+	ErrIndexCorrupt = errors.New("search index is corrupt")
+	// ErrIndexNewerThanApp means the index database has a newer schema than this app understands,
+	// because a newer app version wrote it. The index is left untouched; ResetIndex replaces it.
+	ErrIndexNewerThanApp = errors.New("search index was written by a newer app version")
+	// ErrPageNotFound means no page has the requested number.
+	ErrPageNotFound = errors.New("page not found")
+	// ErrPanelNotFound means the page has no panel with the requested ID.
+	ErrPanelNotFound = errors.New("panel not found")
+	// ErrPanelLocked means the panel is locked and the change was not forced.
+	ErrPanelLocked = errors.New("panel is locked")
+	// ErrManifestLocked means another program kept the manifest open while saving, so it could not be
+	// replaced; the previous manifest is still in place.
+	ErrManifestLocked = errors.New("project manifest is in use by another program")
+	// ErrArchiveCorrupt means a project archive is unreadable, incomplete or fails its checksum.
+	ErrArchiveCorrupt = errors.New("project archive is damaged")
+	// ErrArchiveUnsafePath means a project archive has an entry that would be extracted outside the
+	// project folder or outside the folders an archive may contain.
+	ErrArchiveUnsafePath = errors.New("project archive has an unsafe entry")
+)
+
+// SQLite primary result codes of a damaged index file.
+const (
+	sqliteCorrupt  = 11 // SQLITE_CORRUPT
+	sqliteNotADB   = 26 // SQLITE_NOTADB
+	sqlitePrimMask = 0xff
+)
+
+// SQLite's own texts for those codes, for errors that carry no code.
+var sqliteCorruptTexts = []string{"database disk image is malformed", "file is not a database"}
+
+// pageNotFound reports that no page is numbered n.
+func pageNotFound(n int) error {
+	return fmt.Errorf("%w: %d", ErrPageNotFound, n)
+}
+
+// panelNotFound reports that page n has no panel id.
+func panelNotFound(id string, n int) error {
+	return fmt.Errorf("%w: %s on page %d", ErrPanelNotFound, id, n)
+}
+
+// panelLocked reports that panel id on page n is locked.
+func panelLocked(id string, n int) error {
+	return fmt.Errorf("%w: %s on page %d", ErrPanelLocked, id, n)
+}
+
+// isIndexCorruption reports whether err is SQLite's report of a damaged or foreign database file.
+func isIndexCorruption(err error) bool {
+	var coded interface{ Code() int }
+	if errors.As(err, &coded) {
+		code := coded.Code() & sqlitePrimMask
+		return code == sqliteCorrupt || code == sqliteNotADB
+	}
+	msg := err.Error()
+	for _, t := range sqliteCorruptTexts {
+		if strings.Contains(msg, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexErr wraps err with ErrIndexCorrupt when it reports a damaged index file.
+func indexErr(err error) error {
+	if err != nil && isIndexCorruption(err) && !errors.Is(err, ErrIndexCorrupt) {
+		return fmt.Errorf("%w: %w", ErrIndexCorrupt, err)
+	}
+	return err
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestOpen_ErrorKinds(t *testing.T) {
+	// An empty folder is not a project; the OS error stays matchable too
+	_, err := Open(t.TempDir())
+	if !errors.Is(err, ErrNotAProject) || !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("empty folder: %v", err)
+	}
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ManifestFileName), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(root); !errors.Is(err, ErrManifestCorrupt) || errors.Is(err, ErrNotAProject) {
+		t.Fatalf("corrupt manifest: %v", err)
+	}
+	if _, _, err := openFromLatestBackup(root); !errors.Is(err, ErrBackupNotFound) {
+		t.Fatalf("no backups dir: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, BackupsDirName), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := openFromLatestBackup(root); !errors.Is(err, ErrBackupNotFound) {
+		t.Fatalf("empty backups dir: %v", err)
+	}
+	bad := filepath.Join(root, BackupsDirName, ManifestFileName+".20250101-000000.bak")
+	if err := os.WriteFile(bad, []byte("]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := openFromLatestBackup(root); !errors.Is(err, ErrManifestCorrupt) {
+		t.Fatalf("corrupt backup: %v", err)
+	}
+}
+
+func TestBackups_ErrorKinds(t *testing.T) {
+	root := t.TempDir()
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName)}
+	missing := filepath.Join(root, BackupsDirName, ManifestFileName+".20250101-000000.bak")
+	if _, err := ReadBackupSummary(missing); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("ReadBackupSummary: %v", err)
+	}
+	if _, err := RestoreBackup(ph, missing); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("RestoreBackup: %v", err)
+	}
+	if err := DeleteBackup(root, missing); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("DeleteBackup: %v", err)
+	}
+
+	bad := writeBackup(t, root, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), domain.Project{})
+	if err := os.WriteFile(bad, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadBackupSummary(bad); !errors.Is(err, ErrManifestCorrupt) {
+		t.Errorf("ReadBackupSummary corrupt: %v", err)
+	}
+	if _, err := RestoreBackup(ph, bad); !errors.Is(err, ErrManifestCorrupt) {
+		t.Errorf("RestoreBackup corrupt: %v", err)
+	}
+}
+
+func TestIndex_ErrorKinds(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, IndexDirName), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	junk := make([]byte, 4096)
+	copy(junk, "this is not an sqlite database")
+	if err := os.WriteFile(IndexPath(root), junk, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := InitOrOpenIndex(root)
+	if err == nil {
+		_ = db.Close()
+		t.Fatal("expected an error for a junk index file")
+	}
+	if !errors.Is(err, ErrIndexCorrupt) {
+		t.Fatalf("junk index: %v", err)
+	}
+	// DetectAndRebuildIndex replaces the junk file with a fresh index
+	rebuilt, err := DetectAndRebuildIndex(context.Background(), root, domain.Project{})
+	if err != nil || !rebuilt {
+		t.Fatalf("rebuild = %v, %v", rebuilt, err)
+	}
+	if isIndexCorruption(errors.New("database is locked")) {
+		t.Error("plain errors are not corruption")
+	}
+}
+
+func TestPagePanel_ErrorKinds(t *testing.T) {
+	newPH := func() *ProjectHandle {
+		return &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{
+			{Number: 1, Panels: []domain.Panel{{ID: "p1"}, {ID: "p2"}}},
+			{Number: 2},
+		}}}}}
+	}
+	cases := []struct {
+		name string
+		call func(ph *ProjectHandle, page int, panel string) error
+	}{
+		{"MovePanelZ", func(ph *ProjectHandle, pg int, id string) error { return MovePanelZ(ph, pg, id, 1, false) }},
+		{"UpdatePanelMeta", func(ph *ProjectHandle, pg int, id string) error { return UpdatePanelMeta(ph, pg, id, "", "n", false) }},
+		{"SetPanelBorder", func(ph *ProjectHandle, pg int, id string) error { return SetPanelBorder(ph, pg, id, nil) }},
+		{"SetPanelAnnotations", func(ph *ProjectHandle, pg int, id string) error {
+			return SetPanelAnnotations(ph, pg, id, &domain.PanelAnnotations{})
+		}},
+		{"MapBeatToPanel", func(ph *ProjectHandle, pg int, id string) error { return MapBeatToPanel(ph, pg, id, "b:1") }},
+		{"AssignCaptionLine", func(ph *ProjectHandle, pg int, id string) error {
+			_, err := AssignCaptionLine(ph, pg, id, "", "c:1", "x")
+			return err
+		}},
+		{"DeletePanels", func(ph *ProjectHandle, pg int, id string) error {
+			return DeletePanels(ph, pg, []string{"p1", id}, false)
+		}},
+		{"SetPanelsNotes", func(ph *ProjectHandle, pg int, id string) error {
+			return SetPanelsNotes(ph, pg, []string{id}, "n", false)
+		}},
+		{"OffsetPanels", func(ph *ProjectHandle, pg int, id string) error {
+			return OffsetPanels(ph, pg, []string{id}, 1, 1, false)
+		}},
+		{"DistributePanels", func(ph *ProjectHandle, pg int, id string) error {
+			return DistributePanels(ph, pg, []string{"p1", "p2", id}, true, false)
+		}},
+		{"UpdateBalloonText", func(ph *ProjectHandle, pg int, id string) error { return UpdateBalloonText(ph, pg, id, "b1", nil) }},
+		{"FindBalloon", func(ph *ProjectHandle, pg int, id string) error {
+			_, err := FindBalloon(ph, pg, id, "b1")
+			return err
+		}},
+	}
+	for _, c := range cases {
+		if err := c.call(newPH(), 9, "p1"); !errors.Is(err, ErrPageNotFound) {
+			t.Errorf("%s on a missing page: %v", c.name, err)
+		}
+		if err := c.call(newPH(), 1, "p9"); !errors.Is(err, ErrPanelNotFound) || errors.Is(err, ErrPageNotFound) {
+			t.Errorf("%s on a missing panel: %v", c.name, err)
+		}
+	}
+
+	if _, err := FixReadingOrder(newPH(), 0, 9); !errors.Is(err, ErrPageNotFound) {
+		t.Errorf("FixReadingOrder: %v", err)
+	}
+	if _, err := FixReadingOrder(&ProjectHandle{}, 0, 1); !errors.Is(err, ErrPageNotFound) {
+		t.Errorf("FixReadingOrder without issues: %v", err)
+	}
+	iss := newPH().Project.Issues[0]
+	if err := SetSpread(&iss, 2, 3); !errors.Is(err, ErrPageNotFound) {
+		t.Errorf("SetSpread: %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// ExportPresetFormats lists the formats an export preset may target.
+var ExportPresetFormats = []string{"pdf", "png", "svg", "cbz", "epub"}
+
+// ValidateExportPreset checks the name, format and page selection of a preset.
+func ValidateExportPreset(p domain.ExportPreset) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New("preset name is required")
+	}
+	ok := false
+	for _, f := range ExportPresetFormats {
+		if p.Format == f {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("unknown export format %q", p.Format)
+	}
+	if p.DPI < 0 {
+		return fmt.Errorf("invalid dpi %d", p.DPI)
+	}
+	if _, err := parsePageRanges(p.Pages); err != nil {
+		return err
+	}
+	if p.PageOrder != "" && p.PageOrder != "reading" && p.PageOrder != "selection" {
+		return fmt.Errorf("unknown page order %q", p.PageOrder)
+	}
+	return nil
+}
+
+// FindExportPreset returns the preset with the given name (case-insensitive).
+func FindExportPreset(ph *ProjectHandle, name string) (domain.ExportPreset, bool) {
+	if ph == nil {
+		return domain.ExportPreset{}, false
+	}
+	if i := exportPresetIndex(ph.Project.ExportPresets, name); i >= 0 {
+		return ph.Project.ExportPresets[i], true
+	}
+	return domain.ExportPreset{}, false
+}
+
+// PutExportPreset adds the preset or replaces an existing one with the same name.
+// The change is in memory only; call Save to persist it.
+func PutExportPreset(ph *ProjectHandle, p domain.ExportPreset) error {
+	if ph == nil {
+		return errors.New("nil ProjectHandle")
+	}
+	p.Name = strings.TrimSpace(p.Name)
+	p.Format = strings.ToLower(strings.TrimSpace(p.Format))
+	p.Pages = strings.TrimSpace(p.Pages)
+	if err := ValidateExportPreset(p); err != nil {
+		return err
+	}
+	if i := exportPresetIndex(ph.Project.ExportPresets, p.Name); i >= 0 {
+		ph.Project.ExportPresets[i] = p
+		return nil
+	}
+	ph.Project.ExportPresets = append(ph.Project.ExportPresets, p)
+	return nil
+}
+
+// DeleteExportPreset removes the named preset. The change is in memory only; call Save to persist it.
+func DeleteExportPreset(ph *ProjectHandle, name string) error {
+	if ph == nil {
+		return errors.New("nil ProjectHandle")
+	}
+	i := exportPresetIndex(ph.Project.ExportPresets, name)
+	if i < 0 {
+		return fmt.Errorf("export preset %q not found", name)
+	}
+	ph.Project.ExportPresets = append(ph.Project.ExportPresets[:i], ph.Project.ExportPresets[i+1:]...)
+	return nil
+}
+
+func exportPresetIndex(list []domain.ExportPreset, name string) int {
+	name = strings.TrimSpace(name)
+	for i, p := range list {
+		if strings.EqualFold(p.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ParsePageSelection resolves a page selection such as "1-3, 5, 8-" against the issue's page numbers
+// and returns the matching zero-based page indexes in issue order. An empty expression selects nothing
+// (nil), which exporters treat as "all pages".
+func ParsePageSelection(expr string, iss domain.Issue) ([]int, error) {
+	ranges, err := parsePageRanges(expr)
+	if err != nil || len(ranges) == 0 {
+		return nil, err
+	}
+	var out []int
+	for i, pg := range iss.Pages {
+		for _, r := range ranges {
+			if pg.Number >= r[0] && (r[1] == 0 || pg.Number <= r[1]) {
+				out = append(out, i)
+				break
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("page selection %q matches no pages", expr)
+	}
+	return out, nil
+}
+
+// parsePageRanges parses comma-separated page numbers and ranges ("a-b", open-ended "a-").
+// An upper bound of 0 means unbounded.
+func parsePageRanges(expr string) ([][2]int, error) {
+	var out [][2]int
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		a, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil || a < 1 {
+			return nil, fmt.Errorf("invalid page selection %q", part)
+		}
+		b := a
+		if isRange {
+			if hi = strings.TrimSpace(hi); hi == "" {
+				b = 0
+			} else if b, err = strconv.Atoi(hi); err != nil || b < a {
+				return nil, fmt.Errorf("invalid page selection %q", part)
+			}
+		}
+		out = append(out, [2]int{a, b})
+	}
+	return out, nil
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestExportPresetsCRUD(t *testing.T) {
+	ph := &ProjectHandle{}
+	if err := PutExportPreset(ph, domain.ExportPreset{Name: " Web ", Format: "PNG", DPI: 72, Pages: "1-3"}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := PutExportPreset(ph, domain.ExportPreset{Name: "Print", Format: "pdf", IncludeGuides: true}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	got, ok := FindExportPreset(ph, "web")
+	if !ok || got.Name != "Web" || got.Format != "png" {
+		t.Fatalf("find: %+v %v", got, ok)
+	}
+	// Same name (case-insensitive) replaces.
+	if err := PutExportPreset(ph, domain.ExportPreset{Name: "WEB", Format: "cbz"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if len(ph.Project.ExportPresets) != 2 || ph.Project.ExportPresets[0].Format != "cbz" {
+		t.Fatalf("expected in-place update: %+v", ph.Project.ExportPresets)
+	}
+	for _, bad := range []domain.ExportPreset{
+		{Name: "", Format: "pdf"},
+		{Name: "x", Format: "tiff"},
+		{Name: "x", Format: "pdf", Pages: "3-1"},
+		{Name: "x", Format: "pdf", Pages: "a"},
+		{Name: "x", Format: "png", DPI: -1},
+		{Name: "x", Format: "cbz", PageOrder: "backwards"},
+	} {
+		if err := PutExportPreset(ph, bad); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+	if err := DeleteExportPreset(ph, "print"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := DeleteExportPreset(ph, "print"); err == nil {
+		t.Fatalf("expected error deleting missing preset")
+	}
+	if len(ph.Project.ExportPresets) != 1 {
+		t.Fatalf("unexpected presets: %+v", ph.Project.ExportPresets)
+	}
+}
+
+func TestExportPresetsSurviveSaveOpen(t *testing.T) {
+	// Save/Open start background index work; use a manually cleaned dir to avoid cleanup races.
+	root, err := os.MkdirTemp("", "gcw-presets-")
+	if err != nil {
+		t.Fatalf("mkdtemp: %v", err)
+	}
+	t.Cleanup(func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = os.RemoveAll(root)
+	})
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName), Project: domain.Project{Name: "Presets"}}
+	want := []domain.ExportPreset{
+		{Name: "Print", Format: "pdf", IncludeGuides: true, Pages: "1-"},
+		{Name: "Store", Format: "epub", DPI: 150, Pages: "2, 4-6", EPUB: &domain.EPUBMetadata{
+			Title: "Special", Language: "de", Series: "S", SeriesIndex: 3, Reflowable: true,
+		}},
+	}
+	for _, p := range want {
+		if err := PutExportPreset(ph, p); err != nil {
+			t.Fatalf("put: %v", err)
+		}
+	}
+	if err := Save(ph); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	re, err := Open(root)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if !reflect.DeepEqual(re.Project.ExportPresets, want) {
+		t.Fatalf("presets after reopen = %+v, want %+v", re.Project.ExportPresets, want)
+	}
+}
+
+func TestParsePageSelection(t *testing.T) {
+	iss := domain.Issue{Pages: []domain.Page{{Number: 1}, {Number: 2}, {Number: 3}, {Number: 5}, {Number: 8}}}
+	cases := []struct {
+		expr string
+		want []int
+	}{
+		{"", nil},
+		{"2", []int{1}},
+		{"1-3", []int{0, 1, 2}},
+		{"5, 1", []int{0, 3}},
+		{"3-", []int{2, 3, 4}},
+		{"2,2-3", []int{1, 2}},
+	}
+	for _, tc := range cases {
+		got, err := ParsePageSelection(tc.expr, iss)
+		if err != nil {
+			t.Fatalf("ParsePageSelection(%q): %v", tc.expr, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParsePageSelection(%q) = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+	for _, bad := range []string{"0", "x", "4-2", "1--3", "10-12"} {
+		if _, err := ParsePageSelection(bad, iss); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
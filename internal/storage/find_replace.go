@@ -0,0 +1,350 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gocomicwriter/internal/domain"
+)
+
+// Object types of TextField.Kind: the texts FindText searches and ReplaceText changes.
+const (
+	TextBalloon    = "balloon"     // one text run of a balloon
+	TextCaption    = "caption"     // caption text
+	TextPanelNotes = "panel_notes" // panel notes
+	TextPageNotes  = "page_notes"  // page notes
+	TextBibleNotes = "bible_notes" // notes of a character, location or tag in the bible
+)
+
+// Bible entry kinds of TextField.Entry.
+const (
+	BibleCharacterEntry = "character"
+	BibleLocationEntry  = "location"
+	BibleTagEntry       = "tag"
+)
+
+// textMatchContext is how many runes of the surrounding text a TextMatch carries on each side.
+const textMatchContext = 32
+
+// FindOptions control how FindText compares. Without CaseSensitive letters are compared by Unicode
+// case folding; with WholeWord a match must not continue a word at either end.
+type FindOptions struct {
+	CaseSensitive bool
+	WholeWord     bool
+}
+
+// TextField locates one searchable text in the manifest.
+type TextField struct {
+	Kind       string
+	IssueIndex int    // balloons, captions, panel and page notes
+	PageNumber int    // balloons, captions, panel and page notes
+	PanelID    string // balloons, captions and panel notes
+	ObjectID   string // balloon or caption ID
+	Run        int    // text run of a balloon
+	Entry      string // bible notes: BibleCharacterEntry, BibleLocationEntry or BibleTagEntry
+	Name       string // bible notes: name of the entry
+}
+
+// TextMatch is one occurrence of the find text. Start and End are byte offsets into the field's text
+// when it was found; Before and After hold some of the surrounding text for previews.
+type TextMatch struct {
+	Field      TextField
+	Start, End int
+	Before     string
+	Match      string
+	After      string
+	// Locked is set for texts of a locked panel, which ReplaceText leaves alone unless forced.
+	Locked bool
+
+	source string // the field's text when the match was found
+}
+
+// ReplaceSummary reports what ReplaceText changed.
+type ReplaceSummary struct {
+	Replaced int            // occurrences replaced
+	ByKind   map[string]int // occurrences replaced per TextField.Kind
+	Skipped  int            // occurrences in texts that changed since FindText
+}
+
+// findDocTypes are the index document types holding the texts FindText searches.
+var findDocTypes = []string{"balloon", "caption", "panel_notes", "page_notes", "character_notes", "location_notes", "tag_notes"}
+
+// textField is a searchable text of the manifest with the index document it belongs to.
+type textField struct {
+	TextField
+	text    *string
+	locked  bool
+	docPath string
+}
+
+// textFields lists the searchable texts of the project in manifest order.
+func textFields(p *domain.Project) []textField {
+	var out []textField
+	bible := func(entry, docType, name string, notes *string) {
+		out = append(out, textField{TextField: TextField{Kind: TextBibleNotes, Entry: entry, Name: name}, text: notes, docPath: "bible:" + docType + ":" + name})
+	}
+	for i := range p.Bible.Characters {
+		bible(BibleCharacterEntry, "character_notes", p.Bible.Characters[i].Name, &p.Bible.Characters[i].Notes)
+	}
+	for i := range p.Bible.Locations {
+		bible(BibleLocationEntry, "location_notes", p.Bible.Locations[i].Name, &p.Bible.Locations[i].Notes)
+	}
+	for i := range p.Bible.Tags {
+		bible(BibleTagEntry, "tag_notes", p.Bible.Tags[i].Name, &p.Bible.Tags[i].Notes)
+	}
+	for ii := range p.Issues {
+		for pi := range p.Issues[ii].Pages {
+			pg := &p.Issues[ii].Pages[pi]
+			pagePath := fmt.Sprintf("issue:1/page:%d", pg.Number)
+			out = append(out, textField{TextField: TextField{Kind: TextPageNotes, IssueIndex: ii, PageNumber: pg.Number}, text: &pg.Notes, docPath: pagePath})
+			for k := range pg.Panels {
+				pn := &pg.Panels[k]
+				at := TextField{IssueIndex: ii, PageNumber: pg.Number, PanelID: pn.ID}
+				panelPath := pagePath + "/panel:" + pn.ID
+				f := at
+				f.Kind = TextPanelNotes
+				out = append(out, textField{TextField: f, text: &pn.Notes, locked: pn.Locked, docPath: panelPath})
+				for b := range pn.Balloons {
+					bl := &pn.Balloons[b]
+					for r := range bl.TextRuns {
+						f := at
+						f.Kind, f.ObjectID, f.Run = TextBalloon, bl.ID, r
+						out = append(out, textField{TextField: f, text: &bl.TextRuns[r].Content, locked: pn.Locked, docPath: panelPath + "/balloon:" + bl.ID})
+					}
+				}
+				for c := range pn.Captions {
+					f := at
+					f.Kind, f.ObjectID = TextCaption, pn.Captions[c].ID
+					out = append(out, textField{TextField: f, text: &pn.Captions[c].Text, locked: pn.Locked, docPath: panelPath + "/caption:" + pn.Captions[c].ID})
+				}
+			}
+		}
+	}
+	return out
+}
+
+// FindText finds every occurrence of find in the balloon texts, captions, panel and page notes and
+// bible notes of the project, in manifest order. The search index narrows down the texts to look at
+// when it can answer the query: for whole word searches, over the documents it holds as they are in
+// the manifest. Every match is verified against the manifest, so a stale or missing index only costs
+// time.
+func FindText(ctx context.Context, ph *ProjectHandle, find string, opt FindOptions) ([]TextMatch, error) {
+	if ph == nil {
+		return nil, fmt.Errorf("project handle is nil")
+	}
+	if find == "" {
+		return nil, nil
+	}
+	cands, ok := indexCandidates(ctx, ph, find, opt)
+	var out []TextMatch
+	for _, f := range textFields(&ph.Project) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if ok && !cands[f.docPath] {
+			continue
+		}
+		text := *f.text
+		for _, m := range findOccurrences(text, find, opt) {
+			out = append(out, TextMatch{
+				Field:  f.TextField,
+				Start:  m[0],
+				End:    m[1],
+				Before: contextBefore(text[:m[0]]),
+				Match:  text[m[0]:m[1]],
+				After:  contextAfter(text[m[1]:]),
+				Locked: f.locked,
+				source: text,
+			})
+		}
+	}
+	return out, nil
+}
+
+// indexCandidates returns the paths of the documents that may contain find: those the index finds
+// the words of find in, and those whose indexed rows differ from the manifest's. ok is false when the
+// index cannot narrow the search down: for substring searches, which full-text search does not
+// support, for text without words, and when the index fails.
+func indexCandidates(ctx context.Context, ph *ProjectHandle, find string, opt FindOptions) (cands map[string]bool, ok bool) {
+	if !opt.WholeWord || !strings.ContainsFunc(find, findWordRune) {
+		return nil, false
+	}
+	ix, done := projectIndex(ph)
+	defer done()
+	db, err := ix.acquire()
+	if err != nil {
+		return nil, false
+	}
+	stored, _, err := readFingerprints(ctx, db)
+	ix.release()
+	if err != nil {
+		return nil, false
+	}
+	cands = make(map[string]bool)
+	for path, rs := range groupRows(manifestIndexRows(ph.Root, ph.Project)) {
+		if slices.Contains(findDocTypes, rs[0].typeStr) && !sameFingerprints(stored[path], rs) {
+			cands[path] = true
+		}
+	}
+	// Quotes would end the phrase; the tokenizer splits words at them anyway.
+	phrase := `"` + strings.ReplaceAll(find, `"`, " ") + `"`
+	res, err := ix.Search(ctx, SearchQuery{Text: phrase, Types: findDocTypes, Limit: math.MaxInt32})
+	if err != nil {
+		return nil, false
+	}
+	for _, r := range res {
+		cands[r.Path] = true
+	}
+	return cands, true
+}
+
+// findOccurrences returns the byte ranges of the non-overlapping occurrences of find in text.
+func findOccurrences(text, find string, opt FindOptions) [][2]int {
+	var out [][2]int
+	for i := 0; i < len(text); {
+		n, ok := matchAt(text[i:], find, opt.CaseSensitive)
+		if ok && (!opt.WholeWord || wordBounded(text, i, i+n)) {
+			out = append(out, [2]int{i, i + n})
+			i += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	return out
+}
+
+// matchAt reports whether s starts with find and how many bytes of s the match spans, which can
+// differ from len(find) when letters are compared by case folding.
+func matchAt(s, find string, caseSensitive bool) (int, bool) {
+	if caseSensitive {
+		return len(find), strings.HasPrefix(s, find)
+	}
+	n := 0
+	for _, fr := range find {
+		if n >= len(s) {
+			return 0, false
+		}
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if r != fr && !equalFoldRune(r, fr) {
+			return 0, false
+		}
+		n += size
+	}
+	return n, true
+}
+
+// equalFoldRune reports whether a and b are equal under simple Unicode case folding.
+func equalFoldRune(a, b rune) bool {
+	for f := unicode.SimpleFold(a); f != a; f = unicode.SimpleFold(f) {
+		if f == b {
+			return true
+		}
+	}
+	return false
+}
+
+// wordBounded reports whether text[start:end] is neither preceded nor followed by a word rune, as
+// whole words are matched in the script editor's find bar.
+func wordBounded(text string, start, end int) bool {
+	if r, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && findWordRune(r) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && findWordRune(r) {
+		return false
+	}
+	return true
+}
+
+// findWordRune reports whether r is part of a word for whole word searches. Unlike in cross
+// references, hyphens separate words here, as they do for the search index.
+func findWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// contextBefore returns the end of s, on the same line, for a match preview.
+func contextBefore(s string) string {
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		s = s[i+1:]
+	}
+	if n := utf8.RuneCountInString(s); n > textMatchContext {
+		r := []rune(s)
+		s = "…" + string(r[n-textMatchContext:])
+	}
+	return s
+}
+
+// contextAfter returns the start of s, on the same line, for a match preview.
+func contextAfter(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if r := []rune(s); len(r) > textMatchContext {
+		s = string(r[:textMatchContext]) + "…"
+	}
+	return s
+}
+
+// ReplaceText replaces the given matches, as returned by FindText, with repl in one pass over the
+// manifest. Matches in a text that changed since FindText are skipped and counted, so stale results
+// cannot corrupt edited text. Unless force is set, a match in a locked panel fails the call with
+// ErrPanelLocked before anything changes. The change is in memory: callers persist it via Save, which
+// also updates the search index.
+func ReplaceText(ph *ProjectHandle, matches []TextMatch, repl string, force bool) (ReplaceSummary, error) {
+	sum := ReplaceSummary{ByKind: map[string]int{}}
+	if ph == nil {
+		return sum, fmt.Errorf("project handle is nil")
+	}
+	byField := make(map[TextField][]TextMatch)
+	for _, m := range matches {
+		byField[m.Field] = append(byField[m.Field], m)
+	}
+	// Look the fields up first so a locked panel fails the call before anything changes.
+	var work []textField
+	for _, f := range textFields(&ph.Project) {
+		if len(byField[f.TextField]) == 0 {
+			continue
+		}
+		if f.locked && !force {
+			return sum, panelLocked(f.PanelID, f.PageNumber)
+		}
+		work = append(work, f)
+	}
+	for _, f := range work {
+		ms := byField[f.TextField]
+		delete(byField, f.TextField)
+		// Replace from the end so the offsets of the earlier matches stay valid.
+		sort.Slice(ms, func(i, j int) bool { return ms[i].Start > ms[j].Start })
+		text := *f.text
+		limit := len(text)
+		for _, m := range ms {
+			if m.source != *f.text || m.End > limit {
+				sum.Skipped++
+				continue
+			}
+			text = text[:m.Start] + repl + text[m.End:]
+			limit = m.Start
+			sum.Replaced++
+			sum.ByKind[f.Kind]++
+		}
+		*f.text = text
+	}
+	for _, ms := range byField {
+		sum.Skipped += len(ms)
+	}
+	return sum, nil
+}
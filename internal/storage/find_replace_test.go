@@ -0,0 +1,184 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func findReplaceProject() domain.Project {
+	return domain.Project{
+		Name: "Find Test",
+		Bible: domain.Bible{
+			Characters: []domain.BibleCharacter{{Name: "Mara", Notes: "Keeps the lamp; fears the lamplighter."}},
+			Tags:       []domain.BibleTag{{Name: "storm", Notes: "no lamp here"}},
+		},
+		Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Notes: "Lamp light should glow.", Panels: []domain.Panel{
+			{ID: "P1", Notes: "lighthouse at dusk\nthe LAMP turns",
+				Balloons: []domain.Balloon{{ID: "B1", Type: "speech", TextRuns: []domain.TextRun{{Content: "keep the lamp burning"}, {Content: "lamp, lamp!"}}}},
+				Captions: []domain.Caption{{ID: "C1", Text: "Lamps everywhere."}}},
+			{ID: "P2", Locked: true, Balloons: []domain.Balloon{{ID: "B2", Type: "speech", TextRuns: []domain.TextRun{{Content: "Put out the lamp."}}}}},
+		}}}}},
+	}
+}
+
+func matchFields(ms []TextMatch) []string {
+	var out []string
+	for _, m := range ms {
+		f := m.Field
+		out = append(out, f.Kind+":"+f.PanelID+f.ObjectID+f.Name+":"+m.Match)
+	}
+	return out
+}
+
+func TestFindOccurrences(t *testing.T) {
+	for _, c := range []struct {
+		text, find string
+		opt        FindOptions
+		want       [][2]int
+	}{
+		{"lamp Lamp LAMP", "lamp", FindOptions{}, [][2]int{{0, 4}, {5, 9}, {10, 14}}},
+		{"lamp Lamp LAMP", "lamp", FindOptions{CaseSensitive: true}, [][2]int{{0, 4}}},
+		{"lamps lamp lamplighter", "lamp", FindOptions{WholeWord: true}, [][2]int{{6, 10}}},
+		{"Spider-Man", "spider", FindOptions{WholeWord: true}, [][2]int{{0, 6}}},
+		{"aaaa", "aa", FindOptions{}, [][2]int{{0, 2}, {2, 4}}},
+		{"STRASSE straße", "STRASSE", FindOptions{}, [][2]int{{0, 7}}},
+		{"Ärger ärger", "ärger", FindOptions{WholeWord: true}, [][2]int{{0, 6}, {7, 13}}},
+		{"x_y x", "x", FindOptions{WholeWord: true}, [][2]int{{4, 5}}},
+		{"", "x", FindOptions{}, nil},
+	} {
+		if got := findOccurrences(c.text, c.find, c.opt); !slices.Equal(got, c.want) {
+			t.Errorf("findOccurrences(%q, %q, %+v) = %v, want %v", c.text, c.find, c.opt, got, c.want)
+		}
+	}
+}
+
+func TestFindText_SearchesAllTextKinds(t *testing.T) {
+	ph := &ProjectHandle{Root: t.TempDir(), Project: findReplaceProject()}
+	ms, err := FindText(context.Background(), ph, "lamp", FindOptions{})
+	if err != nil {
+		t.Fatalf("FindText: %v", err)
+	}
+	want := []string{
+		"bible_notes:Mara:lamp", "bible_notes:Mara:lamp", "bible_notes:storm:lamp",
+		"page_notes::Lamp",
+		"panel_notes:P1:LAMP",
+		"balloon:P1B1:lamp", "balloon:P1B1:lamp", "balloon:P1B1:lamp",
+		"caption:P1C1:Lamp",
+		"balloon:P2B2:lamp",
+	}
+	if got := matchFields(ms); !slices.Equal(got, want) {
+		t.Fatalf("matches = %v, want %v", got, want)
+	}
+	m := ms[4]
+	if m.Before != "the " || m.After != " turns" || m.Locked {
+		t.Errorf("panel notes match = %+v, want context on its own line", m)
+	}
+	if b := ms[6]; b.Field.Run != 1 || b.Start != 0 || b.After != ", lamp!" {
+		t.Errorf("second run match = %+v", b)
+	}
+	if !ms[9].Locked {
+		t.Errorf("match in locked panel P2 not flagged: %+v", ms[9])
+	}
+}
+
+func TestFindText_WholeWordUsesIndexAndSeesUnindexedEdits(t *testing.T) {
+	proj := findReplaceProject()
+	root, ctx := buildSnapshotTestIndex(t, proj)
+	ph := &ProjectHandle{Root: root, Project: proj}
+	defer func() { _ = ph.Close() }()
+	// An edit that has not reached the index yet
+	ph.Project.Issues[0].Pages[0].Panels[1].Notes = "a lamp on the sill"
+
+	ms, err := FindText(ctx, ph, "lamp", FindOptions{WholeWord: true})
+	if err != nil {
+		t.Fatalf("FindText: %v", err)
+	}
+	want := []string{
+		"bible_notes:Mara:lamp", "bible_notes:storm:lamp",
+		"page_notes::Lamp",
+		"panel_notes:P1:LAMP",
+		"balloon:P1B1:lamp", "balloon:P1B1:lamp", "balloon:P1B1:lamp",
+		"panel_notes:P2:lamp",
+		"balloon:P2B2:lamp",
+	}
+	if got := matchFields(ms); !slices.Equal(got, want) {
+		t.Fatalf("matches = %v, want %v", got, want)
+	}
+	cands, ok := indexCandidates(ctx, ph, "lamp", FindOptions{WholeWord: true})
+	if !ok || cands["issue:1/page:1/panel:P1/caption:C1"] || !cands["issue:1/page:1/panel:P2"] {
+		t.Fatalf("candidates = %v (ok %v), want the unindexed panel notes but not the caption", cands, ok)
+	}
+}
+
+func TestReplaceText(t *testing.T) {
+	ph := &ProjectHandle{Root: t.TempDir(), Project: findReplaceProject()}
+	ctx := context.Background()
+	ms, err := FindText(ctx, ph, "lamp", FindOptions{WholeWord: true})
+	if err != nil {
+		t.Fatalf("FindText: %v", err)
+	}
+	if _, err := ReplaceText(ph, ms, "lantern", false); !errors.Is(err, ErrPanelLocked) {
+		t.Fatalf("ReplaceText with a locked match = %v, want ErrPanelLocked", err)
+	}
+	if got := ph.Project.Issues[0].Pages[0].Panels[0].Balloons[0].TextRuns[0].Content; got != "keep the lamp burning" {
+		t.Fatalf("refused replace changed text: %q", got)
+	}
+
+	var sel []TextMatch
+	for _, m := range ms {
+		if !m.Locked && m.Field.Kind != TextPageNotes {
+			sel = append(sel, m)
+		}
+	}
+	// A text edited since the search keeps its edit
+	ph.Project.Bible.Tags[0].Notes = "no lamps here"
+	sum, err := ReplaceText(ph, sel, "lantern", false)
+	if err != nil {
+		t.Fatalf("ReplaceText: %v", err)
+	}
+	wantKinds := map[string]int{TextBalloon: 3, TextPanelNotes: 1, TextBibleNotes: 1}
+	if sum.Replaced != 5 || sum.Skipped != 1 || len(sum.ByKind) != len(wantKinds) {
+		t.Fatalf("summary = %+v, want 5 replaced and 1 skipped", sum)
+	}
+	for k, n := range wantKinds {
+		if sum.ByKind[k] != n {
+			t.Errorf("ByKind[%s] = %d, want %d", k, sum.ByKind[k], n)
+		}
+	}
+	p := ph.Project
+	pn := p.Issues[0].Pages[0].Panels[0]
+	for got, want := range map[string]string{
+		pn.Balloons[0].TextRuns[0].Content: "keep the lantern burning",
+		pn.Balloons[0].TextRuns[1].Content: "lantern, lantern!",
+		pn.Notes:                           "lighthouse at dusk\nthe lantern turns",
+		p.Bible.Characters[0].Notes:        "Keeps the lantern; fears the lamplighter.",
+		p.Bible.Tags[0].Notes:              "no lamps here",
+		p.Issues[0].Pages[0].Notes:         "Lamp light should glow.",
+		pn.Captions[0].Text:                "Lamps everywhere.",
+	} {
+		if got != want {
+			t.Errorf("text = %q, want %q", got, want)
+		}
+	}
+
+	// Forced, the locked panel changes too
+	ms, _ = FindText(ctx, ph, "the lamp", FindOptions{})
+	if sum, err := ReplaceText(ph, ms, "the lantern", true); err != nil || sum.ByKind[TextBalloon] != 1 {
+		t.Fatalf("forced ReplaceText = %+v, %v", sum, err)
+	}
+	if got := p.Issues[0].Pages[0].Panels[1].Balloons[0].TextRuns[0].Content; got != "Put out the lantern." {
+		t.Errorf("locked balloon = %q", got)
+	}
+}
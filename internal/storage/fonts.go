@@ -0,0 +1,184 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// FontsDirName is the folder under <project>/styles holding the project's lettering fonts. Style packs
+// carry it along with the rest of styles/.
+const FontsDirName = "fonts"
+
+// ProjectFont is a TrueType or OpenType file in the project's fonts folder.
+type ProjectFont struct {
+	Family    string // family name from the font's name table
+	Subfamily string // style name, e.g. Regular or Bold Italic
+	Path      string // relative to the project root, slash separated, e.g. styles/fonts/Lettering.ttf
+	Format    string // ttf | otf
+	CFF       bool   // PostScript (CFF) outlines, which PDF export cannot embed
+	License   string // license description or, failing that, copyright notice; may be empty
+}
+
+// Bold reports whether the face is a bold style.
+func (f ProjectFont) Bold() bool {
+	s := strings.ToLower(f.Subfamily)
+	return strings.Contains(s, "bold") || strings.Contains(s, "black") || strings.Contains(s, "heavy")
+}
+
+// Italic reports whether the face is an italic or oblique style.
+func (f ProjectFont) Italic() bool {
+	s := strings.ToLower(f.Subfamily)
+	return strings.Contains(s, "italic") || strings.Contains(s, "oblique")
+}
+
+// FontsDir returns <root>/styles/fonts.
+func FontsDir(root string) string {
+	return filepath.Join(root, "styles", FontsDirName)
+}
+
+// ListProjectFonts enumerates the .ttf and .otf files below <root>/styles/fonts with their parsed
+// family names, sorted by family, then regular before bold and italic faces, then path. A missing
+// folder yields no fonts; files that are not valid fonts are skipped.
+func ListProjectFonts(root string) ([]ProjectFont, error) {
+	dir := FontsDir(root)
+	var out []ProjectFont
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && os.IsNotExist(err) {
+				return fs.SkipAll
+			}
+			return nil
+		}
+		if d.IsDir() || IsTransientAssetName(d.Name()) {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".ttf" && ext != ".otf" {
+			return nil
+		}
+		data, rerr := os.ReadFile(path)
+		if rerr != nil {
+			return nil
+		}
+		pf, perr := parseProjectFont(data)
+		if perr != nil {
+			return nil
+		}
+		rel, rerr := filepath.Rel(root, path)
+		if rerr != nil {
+			return nil
+		}
+		pf.Path = filepath.ToSlash(rel)
+		pf.Format = strings.TrimPrefix(ext, ".")
+		out = append(out, pf)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list project fonts: %w", err)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if fa, fb := strings.ToLower(a.Family), strings.ToLower(b.Family); fa != fb {
+			return fa < fb
+		}
+		if ra, rb := faceRank(a), faceRank(b); ra != rb {
+			return ra < rb
+		}
+		return a.Path < b.Path
+	})
+	return out, nil
+}
+
+// faceRank orders faces of a family: regular, bold, italic, bold italic.
+func faceRank(f ProjectFont) int {
+	r := 0
+	if f.Bold() {
+		r++
+	}
+	if f.Italic() {
+		r += 2
+	}
+	return r
+}
+
+// parseProjectFont reads the names of a font file. The typographic family wins over the legacy
+// family name, which some fonts suffix with the style (e.g. "Lettering Bold").
+func parseProjectFont(data []byte) (ProjectFont, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return ProjectFont{}, err
+	}
+	var buf sfnt.Buffer
+	name := func(ids ...sfnt.NameID) string {
+		for _, id := range ids {
+			if s, err := f.Name(&buf, id); err == nil && strings.TrimSpace(s) != "" {
+				return strings.TrimSpace(s)
+			}
+		}
+		return ""
+	}
+	pf := ProjectFont{
+		Family:    name(sfnt.NameIDTypographicFamily, sfnt.NameIDFamily),
+		Subfamily: name(sfnt.NameIDTypographicSubfamily, sfnt.NameIDSubfamily),
+		License:   name(sfnt.NameIDLicense, sfnt.NameIDCopyright),
+		CFF:       bytes.HasPrefix(data, []byte("OTTO")),
+	}
+	if pf.Family == "" {
+		return ProjectFont{}, fmt.Errorf("font has no family name")
+	}
+	return pf, nil
+}
+
+// FontFamilies returns the distinct family names of fonts in their listing order.
+func FontFamilies(fonts []ProjectFont) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, f := range fonts {
+		k := strings.ToLower(f.Family)
+		if !seen[k] {
+			seen[k] = true
+			out = append(out, f.Family)
+		}
+	}
+	return out
+}
+
+// FindFontFace returns the face of family (case-insensitive) that best matches bold: an upright face
+// of the requested weight, else any upright face, else any face of the family.
+func FindFontFace(fonts []ProjectFont, family string, bold bool) (ProjectFont, bool) {
+	var candidates []ProjectFont
+	for _, f := range fonts {
+		if strings.EqualFold(f.Family, strings.TrimSpace(family)) {
+			candidates = append(candidates, f)
+		}
+	}
+	if len(candidates) == 0 {
+		return ProjectFont{}, false
+	}
+	for _, f := range candidates {
+		if !f.Italic() && f.Bold() == bold {
+			return f, true
+		}
+	}
+	for _, f := range candidates {
+		if !f.Italic() {
+			return f, true
+		}
+	}
+	return candidates[0], true
+}
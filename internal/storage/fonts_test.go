@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestListProjectFonts(t *testing.T) {
+	root := t.TempDir()
+	if fonts, err := ListProjectFonts(root); err != nil || len(fonts) != 0 {
+		t.Fatalf("no fonts folder: %v, %v", fonts, err)
+	}
+	dir := FontsDir(root)
+	files := map[string][]byte{
+		"b.ttf":          gobold.TTF,
+		"a.TTF":          goregular.TTF,
+		"sub/italic.ttf": goitalic.TTF,
+		"mono.ttf":       gomono.TTF,
+		"broken.ttf":     []byte("not a font"),
+		"readme.txt":     []byte("fonts go here"),
+		".hidden.ttf":    goregular.TTF,
+	}
+	for name, data := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fonts, err := ListProjectFonts(root)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var got []string
+	for _, f := range fonts {
+		got = append(got, f.Family+"/"+f.Subfamily+"@"+f.Path)
+		if f.Format != "ttf" || f.CFF || f.License == "" {
+			t.Fatalf("font %+v: format, outlines or license not read", f)
+		}
+	}
+	want := []string{
+		"Go/Regular@styles/fonts/a.TTF",
+		"Go/Bold@styles/fonts/b.ttf",
+		"Go/Italic@styles/fonts/sub/italic.ttf",
+		"Go Mono/Regular@styles/fonts/mono.ttf",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	if fam := FontFamilies(fonts); len(fam) != 2 || fam[0] != "Go" || fam[1] != "Go Mono" {
+		t.Fatalf("families %v", fam)
+	}
+	if f, ok := FindFontFace(fonts, "go", true); !ok || f.Path != "styles/fonts/b.ttf" {
+		t.Fatalf("bold face: %+v %v", f, ok)
+	}
+	// A family without a bold face falls back to its regular face
+	if f, ok := FindFontFace(fonts, "Go Mono", true); !ok || f.Path != "styles/fonts/mono.ttf" {
+		t.Fatalf("mono bold fallback: %+v %v", f, ok)
+	}
+	if _, ok := FindFontFace(fonts, "Comic Sans MS", false); ok {
+		t.Fatalf("unknown family found")
+	}
+}
@@ -0,0 +1,321 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	applog "gocomicwriter/internal/log"
+)
+
+// GCCategory names a kind of stale data GarbageCollect removes.
+type GCCategory string
+
+const (
+	GCPreviews       GCCategory = "previews"        // preview cache rows of pages that are no longer in the manifest
+	GCAssetRows      GCCategory = "asset_rows"      // asset index rows whose file is gone
+	GCTempFiles      GCCategory = "temp_files"      // leftovers of interrupted saves and exports
+	GCBackups        GCCategory = "backups"         // manifest backups beyond the retention policy
+	GCCrashAutosaves GCCategory = "crash_autosaves" // crash autosaves older than the recovery retention
+)
+
+// gcCategories lists the categories in report order.
+var gcCategories = []GCCategory{GCPreviews, GCAssetRows, GCTempFiles, GCBackups, GCCrashAutosaves}
+
+// DefaultGCTempAge is how old a temp file must be before GarbageCollect removes it, so that a save
+// running at the same time keeps its file.
+const DefaultGCTempAge = 24 * time.Hour
+
+// GCOptions configure GarbageCollect. The zero value removes everything stale under the current
+// backup retention.
+type GCOptions struct {
+	// DryRun only reports what would be removed.
+	DryRun bool
+	// Now is the reference time for ages; zero means time.Now().
+	Now time.Time
+	// TempAge is the minimum age of removed temp files; 0 means DefaultGCTempAge.
+	TempAge time.Duration
+	// Backups is the backup retention; the zero value means the one set by SetBackupRetention.
+	Backups BackupRetention
+	// RecoveryRetention is how long crash autosaves are kept; 0 means DefaultRecoveryRetention.
+	RecoveryRetention time.Duration
+}
+
+// GCItem is one piece of stale data. Path is a file path relative to the project root, or for index
+// rows a description such as "previews: page 7 thumb 240×340".
+type GCItem struct {
+	Category GCCategory
+	Path     string
+	Bytes    int64
+}
+
+// GCCategoryTotal sums the items of one category.
+type GCCategoryTotal struct {
+	Category GCCategory
+	Items    int
+	Bytes    int64
+}
+
+// GCReport lists what GarbageCollect removed, or would remove in a dry run. Totals has one entry per
+// category in a fixed order, including empty ones.
+type GCReport struct {
+	DryRun bool
+	Items  []GCItem
+	Totals []GCCategoryTotal
+}
+
+// Bytes is the total size of all items.
+func (r GCReport) Bytes() int64 {
+	var n int64
+	for _, t := range r.Totals {
+		n += t.Bytes
+	}
+	return n
+}
+
+// GarbageCollect finds, and unless opts.DryRun removes, the project's stale derived data: cached
+// previews of pages no longer in the manifest, asset index rows without a file, temp files of
+// interrupted saves and exports, backups beyond retention and old crash autosaves. It only looks at
+// the index, the .gcw folder, the project root's manifest temp files, exports/ and backups/; user
+// content under assets/, pages/ and script/ is never touched.
+func GarbageCollect(ctx context.Context, ph *ProjectHandle, opts GCOptions) (GCReport, error) {
+	l := applog.WithOperation(applog.WithComponent("storage"), "gc")
+	if ph == nil {
+		return GCReport{}, errors.New("nil ProjectHandle")
+	}
+	if strings.TrimSpace(ph.Root) == "" {
+		return GCReport{}, errors.New("project root is required")
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.TempAge <= 0 {
+		opts.TempAge = DefaultGCTempAge
+	}
+	if opts.Backups == (BackupRetention{}) {
+		opts.Backups = currentBackupRetention()
+	}
+	if opts.RecoveryRetention <= 0 {
+		opts.RecoveryRetention = DefaultRecoveryRetention
+	}
+	rep := GCReport{DryRun: opts.DryRun}
+
+	ix, done := projectIndex(ph)
+	defer done()
+	rows, err := ix.gcIndexRows(ctx, ph.Project.Issues, opts.DryRun)
+	if err != nil {
+		return GCReport{}, err
+	}
+	rep.Items = append(rep.Items, rows...)
+
+	files, err := staleFiles(ph.Root, opts)
+	if err != nil {
+		return GCReport{}, err
+	}
+	for _, it := range files {
+		if !opts.DryRun {
+			if rerr := os.Remove(filepath.Join(ph.Root, it.Path)); rerr != nil && !os.IsNotExist(rerr) {
+				return GCReport{}, fmt.Errorf("remove %s: %w", it.Path, rerr)
+			}
+		}
+		rep.Items = append(rep.Items, it)
+	}
+
+	rep.Totals = gcTotals(rep.Items)
+	l.Info("garbage collected", slog.Bool("dry_run", opts.DryRun), slog.Int("items", len(rep.Items)), slog.Int64("bytes", rep.Bytes()))
+	return rep, nil
+}
+
+func gcTotals(items []GCItem) []GCCategoryTotal {
+	out := make([]GCCategoryTotal, 0, len(gcCategories))
+	for _, c := range gcCategories {
+		t := GCCategoryTotal{Category: c}
+		for _, it := range items {
+			if it.Category == c {
+				t.Items++
+				t.Bytes += it.Bytes
+			}
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// gcIndexRows finds preview rows of page numbers that no issue has and asset rows whose file is gone,
+// and deletes them unless dryRun.
+func (ix *IndexHandle) gcIndexRows(ctx context.Context, issues []domain.Issue, dryRun bool) ([]GCItem, error) {
+	pages := map[int]bool{}
+	for _, iss := range issues {
+		for _, pg := range iss.Pages {
+			pages[pg.Number] = true
+		}
+	}
+	db, err := ix.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer ix.release()
+
+	var items []GCItem
+	var previewIDs []int64
+	rows, err := db.QueryContext(ctx, `SELECT id, page_id, COALESCE(kind, 'thumb'), COALESCE(w, 0), COALESCE(h, 0),
+		COALESCE(NULLIF(size, 0), length(thumb_blob), length(geom_blob), 0) FROM previews ORDER BY page_id, id`)
+	if err != nil {
+		return nil, fmt.Errorf("list previews: %w", err)
+	}
+	for rows.Next() {
+		var id, size int64
+		var page, w, h int
+		var kind string
+		if err := rows.Scan(&id, &page, &kind, &w, &h, &size); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		if pages[page] {
+			continue
+		}
+		previewIDs = append(previewIDs, id)
+		items = append(items, GCItem{Category: GCPreviews, Path: fmt.Sprintf("previews: page %d %s %d×%d", page, kind, w, h), Bytes: size})
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	type assetRow struct{ hash, path string }
+	var assets []assetRow
+	arows, err := db.QueryContext(ctx, `SELECT hash, path FROM assets ORDER BY path`)
+	if err != nil {
+		return nil, fmt.Errorf("list assets: %w", err)
+	}
+	for arows.Next() {
+		var a assetRow
+		if err := arows.Scan(&a.hash, &a.path); err != nil {
+			_ = arows.Close()
+			return nil, err
+		}
+		if _, serr := os.Stat(filepath.Join(ix.root, filepath.FromSlash(a.path))); errors.Is(serr, fs.ErrNotExist) {
+			assets = append(assets, a)
+			items = append(items, GCItem{Category: GCAssetRows, Path: "assets: " + a.path, Bytes: int64(len(a.hash) + len(a.path))})
+		}
+	}
+	if err := arows.Close(); err != nil {
+		return nil, err
+	}
+
+	if dryRun || len(items) == 0 {
+		return items, nil
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, id := range previewIDs {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM previews WHERE id = ?`, id); err != nil {
+			return nil, fmt.Errorf("delete preview: %w", err)
+		}
+	}
+	for _, a := range assets {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM assets WHERE hash = ?`, a.hash); err != nil {
+			return nil, fmt.Errorf("delete asset row: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	// Give the space back to the file system; the rows are gone either way.
+	if _, err := db.ExecContext(ctx, `VACUUM`); err != nil {
+		applog.WithComponent("storage").Warn("vacuum index failed", slog.Any("err", err))
+	}
+	return items, nil
+}
+
+// staleFiles lists the stale files under root, with paths relative to root.
+func staleFiles(root string, opts GCOptions) ([]GCItem, error) {
+	var out []GCItem
+	add := func(c GCCategory, path string, size int64) {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+		out = append(out, GCItem{Category: c, Path: filepath.ToSlash(rel), Bytes: size})
+	}
+	tempCutoff := opts.Now.Add(-opts.TempAge)
+	oldTemp := func(info fs.FileInfo) bool { return info.ModTime().Before(tempCutoff) }
+
+	// Manifest temp files in the root (.comic.json.tmp-*) and settings temp files in .gcw (*.tmp-*)
+	for _, dir := range []struct {
+		path   string
+		isTemp func(string) bool
+	}{
+		{root, func(n string) bool { return strings.HasPrefix(n, "."+ManifestFileName+".tmp-") }},
+		{filepath.Join(root, IndexDirName), func(n string) bool { return strings.Contains(n, ".tmp-") }},
+	} {
+		ents, err := os.ReadDir(dir.path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("read %s: %w", dir.path, err)
+		}
+		for _, e := range ents {
+			if !e.Type().IsRegular() || !dir.isTemp(e.Name()) {
+				continue
+			}
+			if info, ierr := e.Info(); ierr == nil && oldTemp(info) {
+				add(GCTempFiles, filepath.Join(dir.path, e.Name()), info.Size())
+			}
+		}
+	}
+	// Partial export files (*.tmp) anywhere under exports/
+	exports := filepath.Join(root, "exports")
+	err := filepath.WalkDir(exports, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == exports && os.IsNotExist(err) {
+				return fs.SkipAll
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !strings.HasSuffix(d.Name(), ".tmp") {
+			return nil
+		}
+		if info, ierr := d.Info(); ierr == nil && oldTemp(info) {
+			add(GCTempFiles, path, info.Size())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan exports: %w", err)
+	}
+
+	backups, err := ListBackups(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range backupsBeyondRetention(backups, opts.Backups, opts.Now) {
+		add(GCBackups, b.Path, b.Size)
+	}
+	snaps, err := listRecoverySnapshots(root)
+	if err != nil {
+		return nil, err
+	}
+	cutoff := opts.Now.Add(-opts.RecoveryRetention)
+	for _, s := range snaps {
+		if s.ModTime.Before(cutoff) {
+			add(GCCrashAutosaves, s.Path, s.Size)
+		}
+	}
+	return out, nil
+}
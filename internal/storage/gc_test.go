@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+// messyProject builds a project with stale data of every GC category next to user content that
+// looks similar but must survive. It returns the handle and the paths GarbageCollect must remove.
+func messyProject(t *testing.T, now time.Time) (*ProjectHandle, []string) {
+	t.Helper()
+	root := t.TempDir()
+	old := now.Add(-48 * time.Hour)
+	write := func(rel string, mtime time.Time) {
+		t.Helper()
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("data of "+rel), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	proj := domain.Project{Name: "Messy", Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1}, {Number: 2}}}}}
+	write(ManifestFileName, now)
+	// User content, including temp-looking files, is never touched
+	write("assets/hero.png", old)
+	write("assets/gone.png", old)
+	write("assets/draft.tmp", old)
+	write("pages/page-1.tmp", old)
+	write("script/script.txt", old)
+	write("exports/book.pdf", old)
+	// Temp files: old ones go, a fresh one may belong to a running save
+	write(".comic.json.tmp-11-22", old)
+	write(".comic.json.tmp-33-44", now)
+	write(".gcw/settings.json.tmp-5-6", old)
+	write("exports/issue-1/report.json.tmp", old)
+	// Backups: the two newest are kept, older ones are beyond retention
+	for _, ago := range []time.Duration{time.Hour, 2 * time.Hour, 10 * 24 * time.Hour, 11 * 24 * time.Hour} {
+		ts := now.Add(-ago)
+		write("backups/"+ManifestFileName+"."+ts.Format(backupStampLayout)+".bak", ts)
+	}
+	write("backups/"+ManifestFileName+".crash-1.autosave", now.Add(-20*24*time.Hour))
+	write("backups/"+ManifestFileName+".crash-2.autosave", now)
+
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName), Project: proj}
+	t.Cleanup(func() { _ = ph.Close() })
+	ctx := context.Background()
+	if err := ph.Index().RebuildIndex(ctx, proj); err != nil {
+		t.Fatalf("rebuild index: %v", err)
+	}
+	if err := os.Remove(filepath.Join(root, "assets", "gone.png")); err != nil {
+		t.Fatal(err)
+	}
+	for _, page := range []int{1, 2, 3} {
+		if err := ph.Index().PutPreview(ctx, page, sql.NullInt64{}, PreviewKindThumb, 10, 10, "", []byte("png bytes")); err != nil {
+			t.Fatalf("put preview: %v", err)
+		}
+	}
+	stale := []string{
+		".comic.json.tmp-11-22",
+		".gcw/settings.json.tmp-5-6",
+		"exports/issue-1/report.json.tmp",
+		"backups/" + ManifestFileName + "." + now.Add(-10*24*time.Hour).Format(backupStampLayout) + ".bak",
+		"backups/" + ManifestFileName + "." + now.Add(-11*24*time.Hour).Format(backupStampLayout) + ".bak",
+		"backups/" + ManifestFileName + ".crash-1.autosave",
+	}
+	return ph, stale
+}
+
+func gcPaths(rep GCReport) []string {
+	var out []string
+	for _, it := range rep.Items {
+		out = append(out, it.Path)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestGarbageCollect(t *testing.T) {
+	now := time.Now()
+	ph, stale := messyProject(t, now)
+	ctx := context.Background()
+	opts := GCOptions{DryRun: true, Now: now, Backups: BackupRetention{KeepLast: 2, KeepDailyDays: 1}}
+
+	want := append([]string{"assets: " + filepath.Join("assets", "gone.png"), "previews: page 3 thumb 10×10"}, stale...)
+	sort.Strings(want)
+	dry, err := GarbageCollect(ctx, ph, opts)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if got := gcPaths(dry); !reflect.DeepEqual(got, want) {
+		t.Fatalf("dry run items =\n%v\nwant\n%v", got, want)
+	}
+	for _, rel := range stale {
+		if _, err := os.Stat(filepath.Join(ph.Root, rel)); err != nil {
+			t.Fatalf("dry run removed %s", rel)
+		}
+	}
+	wantTotals := map[GCCategory]int{GCPreviews: 1, GCAssetRows: 1, GCTempFiles: 3, GCBackups: 2, GCCrashAutosaves: 1}
+	for _, tot := range dry.Totals {
+		if tot.Items != wantTotals[tot.Category] || (tot.Items > 0) != (tot.Bytes > 0) {
+			t.Fatalf("totals = %+v", dry.Totals)
+		}
+	}
+	if len(dry.Totals) != len(gcCategories) || dry.Bytes() <= 0 {
+		t.Fatalf("totals = %+v, bytes %d", dry.Totals, dry.Bytes())
+	}
+
+	opts.DryRun = false
+	rep, err := GarbageCollect(ctx, ph, opts)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if got := gcPaths(rep); !reflect.DeepEqual(got, want) || rep.Bytes() != dry.Bytes() {
+		t.Fatalf("removed =\n%v\nwant\n%v", got, want)
+	}
+	for _, rel := range stale {
+		if _, err := os.Stat(filepath.Join(ph.Root, rel)); !os.IsNotExist(err) {
+			t.Fatalf("%s was not removed: %v", rel, err)
+		}
+	}
+	for _, rel := range []string{"assets/hero.png", "assets/draft.tmp", "pages/page-1.tmp", "script/script.txt", "exports/book.pdf", ".comic.json.tmp-33-44", ManifestFileName} {
+		if _, err := os.Stat(filepath.Join(ph.Root, filepath.FromSlash(rel))); err != nil {
+			t.Fatalf("%s must be kept: %v", rel, err)
+		}
+	}
+	if snaps, _ := listRecoverySnapshots(ph.Root); len(snaps) != 1 {
+		t.Fatalf("crash autosaves left: %+v", snaps)
+	}
+	if b, _ := ListBackups(ph.Root); len(b) != 2 {
+		t.Fatalf("backups left: %+v", b)
+	}
+	for _, page := range []int{1, 2} {
+		if data, err := ph.Index().GetPreview(ctx, page, sql.NullInt64{}, PreviewKindThumb, 10, 10, ""); err != nil || data == nil {
+			t.Fatalf("preview of page %d: %v", page, err)
+		}
+	}
+
+	again, err := GarbageCollect(ctx, ph, GCOptions{DryRun: true, Now: now, Backups: opts.Backups})
+	if err != nil || len(again.Items) != 0 || again.Bytes() != 0 {
+		t.Fatalf("second run: %+v %v", again, err)
+	}
+}
@@ -0,0 +1,293 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"gocomicwriter/internal/domain"
+)
+
+// ErrNotGridLayout is returned by PageGutters and NormalizeGutters for pages whose panels cannot be
+// split into rows and columns by straight gutters, such as insets, overlaps or diagonal layouts.
+var ErrNotGridLayout = errors.New("panels do not form rows and columns")
+
+// gutterOverlapPt is how far two panels may overlap across a gutter and still count as separated by it,
+// so hand-placed panels that touch or overlap slightly still form a grid.
+const gutterOverlapPt = 1.0
+
+// gutterSnapPt is how close a panel edge must be to the edge of its row or column to be aligned with it
+// when gutters are normalized. Edges further in are kept where they are relative to the row or column.
+const gutterSnapPt = 6.0
+
+// GutterStats describes the gutters between neighbouring panels of a page, in points.
+type GutterStats struct {
+	Count  int // number of facing panel pairs measured
+	Min    float64
+	Max    float64
+	Median float64
+}
+
+// gutterNode is a group of panels in the guillotine split of a page: a single panel, or groups
+// separated by gutters that run across the whole group.
+type gutterNode struct {
+	panels   []int       // indexes of the panels in the group
+	box      domain.Rect // bounding box of the panels
+	vertical bool        // children are stacked top to bottom, else left to right
+	children []*gutterNode
+}
+
+// axisSpan returns the start and length of r along the vertical or horizontal axis.
+func axisSpan(r domain.Rect, vertical bool) (float64, float64) {
+	if vertical {
+		return r.Y, r.Height
+	}
+	return r.X, r.Width
+}
+
+// boundsOf returns the bounding box of the rects at idx.
+func boundsOf(rects []domain.Rect, idx []int) domain.Rect {
+	r := rects[idx[0]]
+	x0, y0, x1, y1 := r.X, r.Y, r.X+r.Width, r.Y+r.Height
+	for _, i := range idx[1:] {
+		r := rects[i]
+		x0, y0 = math.Min(x0, r.X), math.Min(y0, r.Y)
+		x1, y1 = math.Max(x1, r.X+r.Width), math.Max(y1, r.Y+r.Height)
+	}
+	return domain.Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// splitAtGutters splits the rects at idx into groups separated by gutters that run across all of
+// them, ordered top to bottom (vertical) or left to right. A single group means no such gutter exists.
+func splitAtGutters(rects []domain.Rect, idx []int, vertical bool) [][]int {
+	order := append([]int(nil), idx...)
+	sort.SliceStable(order, func(a, b int) bool {
+		sa, _ := axisSpan(rects[order[a]], vertical)
+		sb, _ := axisSpan(rects[order[b]], vertical)
+		return sa < sb
+	})
+	var groups [][]int
+	end := 0.0 // trailing edge of the last group
+	for _, i := range order {
+		s, l := axisSpan(rects[i], vertical)
+		if n := len(groups); n > 0 && s < end-gutterOverlapPt {
+			groups[n-1] = append(groups[n-1], i)
+			end = math.Max(end, s+l)
+			continue
+		}
+		groups = append(groups, []int{i})
+		end = s + l
+	}
+	return groups
+}
+
+// buildGutterTree splits the rects at idx recursively into rows and columns, trying rows first.
+// It returns ErrNotGridLayout when a group of several panels has no gutter running across it.
+func buildGutterTree(rects []domain.Rect, idx []int) (*gutterNode, error) {
+	n := &gutterNode{panels: idx, box: boundsOf(rects, idx)}
+	if len(idx) == 1 {
+		return n, nil
+	}
+	for _, vertical := range []bool{true, false} {
+		groups := splitAtGutters(rects, idx, vertical)
+		if len(groups) < 2 {
+			continue
+		}
+		n.vertical = vertical
+		for _, g := range groups {
+			child, err := buildGutterTree(rects, g)
+			if err != nil {
+				return nil, err
+			}
+			n.children = append(n.children, child)
+		}
+		return n, nil
+	}
+	return nil, ErrNotGridLayout
+}
+
+// place lays out the group in box with gutter g between its children and stores each panel's new
+// rect in out. Children keep their share of the group's length; an edge within gutterSnapPt of the
+// group's side is aligned with it, other edges keep their relative position.
+func (n *gutterNode) place(out []domain.Rect, box domain.Rect, g float64) error {
+	if n.children == nil {
+		out[n.panels[0]] = box
+		return nil
+	}
+	lo, length := axisSpan(box, n.vertical)
+	total := 0.0
+	for _, c := range n.children {
+		_, l := axisSpan(c.box, n.vertical)
+		total += l
+	}
+	content := length - g*float64(len(n.children)-1)
+	if content <= 0 || total <= 0 {
+		return fmt.Errorf("a %.1f pt gutter leaves no room for the panels", g)
+	}
+	p0, pl := axisSpan(n.box, !n.vertical)
+	q0, ql := axisSpan(box, !n.vertical)
+	cross := func(v, edge, to float64) float64 {
+		if math.Abs(v-edge) <= gutterSnapPt || pl <= 0 {
+			return to
+		}
+		return q0 + (v-p0)*ql/pl
+	}
+	at := lo
+	for _, c := range n.children {
+		_, l := axisSpan(c.box, n.vertical)
+		l = l * content / total
+		c0, cl := axisSpan(c.box, !n.vertical)
+		start, end := cross(c0, p0, q0), cross(c0+cl, p0+pl, q0+ql)
+		r := domain.Rect{X: start, Y: at, Width: end - start, Height: l}
+		if !n.vertical {
+			r = domain.Rect{X: at, Y: start, Width: l, Height: end - start}
+		}
+		if err := c.place(out, r, g); err != nil {
+			return err
+		}
+		at += l + g
+	}
+	return nil
+}
+
+// pageGutterTree returns the panel rects of pg and their split into rows and columns.
+func pageGutterTree(pg domain.Page) ([]domain.Rect, *gutterNode, error) {
+	rects := make([]domain.Rect, len(pg.Panels))
+	idx := make([]int, len(pg.Panels))
+	for i, pn := range pg.Panels {
+		rects[i] = pn.Geometry
+		idx[i] = i
+	}
+	if len(idx) == 0 {
+		return rects, nil, nil
+	}
+	tree, err := buildGutterTree(rects, idx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: page %d", err, pg.Number)
+	}
+	return rects, tree, nil
+}
+
+// PageGutters measures the gutters between facing panels of pg: panels side by side or one above
+// the other, sharing part of their edge, with no panel in between. Pages with fewer than two panels
+// have no gutters; pages that do not form rows and columns fail with ErrNotGridLayout.
+func PageGutters(pg domain.Page) (GutterStats, error) {
+	rects, _, err := pageGutterTree(pg)
+	if err != nil {
+		return GutterStats{}, err
+	}
+	var gaps []float64
+	for _, vertical := range []bool{true, false} {
+		for a := range rects {
+			for b := range rects {
+				if gap, ok := facingGap(rects, a, b, vertical); ok {
+					gaps = append(gaps, gap)
+				}
+			}
+		}
+	}
+	if len(gaps) == 0 {
+		return GutterStats{}, nil
+	}
+	sort.Float64s(gaps)
+	st := GutterStats{Count: len(gaps), Min: gaps[0], Max: gaps[len(gaps)-1]}
+	if m := len(gaps) / 2; len(gaps)%2 == 1 {
+		st.Median = gaps[m]
+	} else {
+		st.Median = (gaps[m-1] + gaps[m]) / 2
+	}
+	return st, nil
+}
+
+// facingGap returns the gutter from rect a to rect b below it (vertical) or to its right, and whether
+// the two face each other across it.
+func facingGap(rects []domain.Rect, a, b int, vertical bool) (float64, bool) {
+	if a == b {
+		return 0, false
+	}
+	as, al := axisSpan(rects[a], vertical)
+	bs, _ := axisSpan(rects[b], vertical)
+	gap := bs - (as + al)
+	if gap < -gutterOverlapPt || bs <= as {
+		return 0, false
+	}
+	// The shared stretch of edge along the other axis.
+	ac, acl := axisSpan(rects[a], !vertical)
+	bc, bcl := axisSpan(rects[b], !vertical)
+	lo, hi := math.Max(ac, bc), math.Min(ac+acl, bc+bcl)
+	if hi-lo <= gutterOverlapPt {
+		return 0, false
+	}
+	for c := range rects {
+		if c == a || c == b {
+			continue
+		}
+		cs, cl := axisSpan(rects[c], vertical)
+		cc, ccl := axisSpan(rects[c], !vertical)
+		if cs >= as+al-gutterOverlapPt && cs+cl <= bs+gutterOverlapPt && cc < hi && cc+ccl > lo {
+			return 0, false
+		}
+	}
+	return gap, true
+}
+
+// NormalizeGutters evens out the gutters of a page so that every gutter between rows and columns is
+// gutterPt wide. The outer edges of the panel block stay where they are; rows and columns keep their
+// share of the space, and panel edges close to a row or column edge are aligned with it. Balloons,
+// captions and SFX move with their panel's top-left corner. It returns how many panels changed.
+// Pages that do not form rows and columns fail with ErrNotGridLayout and are left untouched, as are
+// pages with a locked panel that would change (ErrPanelLocked). Callers persist the change via Save.
+func NormalizeGutters(ph *ProjectHandle, pageNumber int, gutterPt float64) (int, error) {
+	if ph == nil {
+		return 0, fmt.Errorf("project handle is nil")
+	}
+	if gutterPt < 0 || math.IsNaN(gutterPt) || math.IsInf(gutterPt, 0) {
+		return 0, fmt.Errorf("gutter %g pt must not be negative", gutterPt)
+	}
+	var pg *domain.Page
+	for i := range ph.Project.Issues {
+		if j := pageIndexByNumber(ph.Project.Issues[i], pageNumber); j >= 0 {
+			pg = &ph.Project.Issues[i].Pages[j]
+			break
+		}
+	}
+	if pg == nil {
+		return 0, pageNotFound(pageNumber)
+	}
+	rects, tree, err := pageGutterTree(*pg)
+	if err != nil || tree == nil || tree.children == nil {
+		return 0, err
+	}
+	out := make([]domain.Rect, len(rects))
+	if err := tree.place(out, tree.box, gutterPt); err != nil {
+		return 0, fmt.Errorf("page %d: %w", pageNumber, err)
+	}
+	const eps = 1e-6
+	var changed []int
+	for i, r := range rects {
+		o := out[i]
+		if math.Abs(o.X-r.X) > eps || math.Abs(o.Y-r.Y) > eps || math.Abs(o.Width-r.Width) > eps || math.Abs(o.Height-r.Height) > eps {
+			if pg.Panels[i].Locked {
+				return 0, panelLocked(pg.Panels[i].ID, pageNumber)
+			}
+			changed = append(changed, i)
+		}
+	}
+	for _, i := range changed {
+		pn := &pg.Panels[i]
+		translatePanel(pn, out[i].X-rects[i].X, out[i].Y-rects[i].Y)
+		pn.Geometry = out[i]
+	}
+	return len(changed), nil
+}
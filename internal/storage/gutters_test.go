@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+// sloppyPage is a hand-built page: a row of two panels with an 11pt gutter, the right one 2pt
+// taller, above a full-width panel 13pt below the taller one. A balloon sits in the left panel.
+func sloppyPage() *ProjectHandle {
+	pg := domain.Page{Number: 2, Panels: []domain.Panel{
+		{ID: "a", Geometry: domain.Rect{X: 20, Y: 20, Width: 100, Height: 100},
+			Balloons: []domain.Balloon{{ID: "b1", Shape: domain.Shape{Rect: domain.Rect{X: 30, Y: 30, Width: 40, Height: 20}}}}},
+		{ID: "b", Geometry: domain.Rect{X: 131, Y: 20, Width: 89, Height: 102}, ZOrder: 1},
+		{ID: "c", Geometry: domain.Rect{X: 20, Y: 135, Width: 200, Height: 85}, ZOrder: 2},
+	}}
+	return &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{pg}}}}}
+}
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-6 }
+
+func TestPageGutters(t *testing.T) {
+	st, err := PageGutters(sloppyPage().Project.Issues[0].Pages[0])
+	if err != nil {
+		t.Fatalf("PageGutters: %v", err)
+	}
+	// a|b 11pt; a over c 15pt; b over c 13pt.
+	if st.Count != 3 || st.Min != 11 || st.Max != 15 || st.Median != 13 {
+		t.Fatalf("stats = %+v, want 3 gutters 11..15, median 13", st)
+	}
+	st, err = PageGutters(gridPage().Project.Issues[0].Pages[0])
+	if err != nil || st.Count != 12 || st.Min != 10 || st.Max != 10 {
+		t.Fatalf("grid stats = %+v, %v; want 12 gutters of 10pt", st, err)
+	}
+	if st, err := PageGutters(domain.Page{Panels: []domain.Panel{{ID: "p1", Geometry: domain.Rect{Width: 10, Height: 10}}}}); err != nil || st.Count != 0 {
+		t.Fatalf("single panel: %+v, %v", st, err)
+	}
+}
+
+func TestNormalizeGutters(t *testing.T) {
+	ph := sloppyPage()
+	n, err := NormalizeGutters(ph, 2, 12)
+	if err != nil {
+		t.Fatalf("NormalizeGutters: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("changed %d panels, want 3", n)
+	}
+	pg := ph.Project.Issues[0].Pages[0]
+	a, b, c := pg.Panels[0].Geometry, pg.Panels[1].Geometry, pg.Panels[2].Geometry
+	// The outer edges of the block stay at 20..220 both ways.
+	if !near(a.X, 20) || !near(a.Y, 20) || !near(b.X+b.Width, 220) || !near(c.Y+c.Height, 220) || !near(c.X, 20) || !near(c.Width, 200) {
+		t.Fatalf("outer margins moved: a=%+v b=%+v c=%+v", a, b, c)
+	}
+	// The short panel is aligned with the row, and every gutter is 12pt.
+	if !near(a.Height, b.Height) || !near(b.X-(a.X+a.Width), 12) || !near(c.Y-(a.Y+a.Height), 12) {
+		t.Fatalf("gutters not even: a=%+v b=%+v c=%+v", a, b, c)
+	}
+	if bl := pg.Panels[0].Balloons[0].Shape.Rect; bl.X != 30 || bl.Y != 30 || bl.Width != 40 {
+		t.Fatalf("balloon of an unmoved corner changed: %+v", bl)
+	}
+	st, err := PageGutters(pg)
+	if err != nil || !near(st.Min, 12) || !near(st.Max, 12) {
+		t.Fatalf("after normalizing: %+v, %v", st, err)
+	}
+	if n, err := NormalizeGutters(ph, 2, 12); err != nil || n != 0 {
+		t.Fatalf("second run changed %d panels, %v; want none", n, err)
+	}
+
+	// An even grid whose gutters already match is left alone; a wider gutter shrinks the panels.
+	ph = gridPage()
+	if n, err := NormalizeGutters(ph, 1, 10); err != nil || n != 0 {
+		t.Fatalf("even grid: changed %d, %v", n, err)
+	}
+	if n, err := NormalizeGutters(ph, 1, 25); err != nil || n != 9 {
+		t.Fatalf("wider gutter: changed %d, %v", n, err)
+	}
+	if g := ph.Project.Issues[0].Pages[0].Panels[4].Geometry; !near(g.X, 115) || !near(g.Width, 90) || !near(g.Y, 115) {
+		t.Fatalf("middle panel = %+v, want x 115, width 90", g)
+	}
+	if _, err := NormalizeGutters(ph, 1, 200); err == nil {
+		t.Fatalf("a gutter wider than the page should fail")
+	}
+}
+
+func TestNormalizeGutters_Refuses(t *testing.T) {
+	// A splash with an inset panel and a pinwheel do not form rows and columns.
+	layouts := map[string][]domain.Rect{
+		"inset": {{X: 0, Y: 0, Width: 300, Height: 300}, {X: 200, Y: 200, Width: 80, Height: 80}},
+		"pinwheel": {
+			{X: 0, Y: 0, Width: 200, Height: 90},
+			{X: 210, Y: 0, Width: 90, Height: 200},
+			{X: 100, Y: 210, Width: 200, Height: 90},
+			{X: 0, Y: 100, Width: 90, Height: 200},
+		},
+	}
+	for name, rects := range layouts {
+		pg := domain.Page{Number: 1}
+		for i, r := range rects {
+			pg.Panels = append(pg.Panels, domain.Panel{ID: NextPanelID(&pg), Geometry: r, ZOrder: i})
+		}
+		ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{pg}}}}}
+		if _, err := PageGutters(pg); !errors.Is(err, ErrNotGridLayout) {
+			t.Errorf("%s: PageGutters err = %v, want ErrNotGridLayout", name, err)
+		}
+		if _, err := NormalizeGutters(ph, 1, 12); !errors.Is(err, ErrNotGridLayout) {
+			t.Errorf("%s: NormalizeGutters err = %v, want ErrNotGridLayout", name, err)
+		}
+		for i, pn := range ph.Project.Issues[0].Pages[0].Panels {
+			if pn.Geometry != rects[i] {
+				t.Errorf("%s: panel %s moved to %+v", name, pn.ID, pn.Geometry)
+			}
+		}
+	}
+
+	ph := sloppyPage()
+	ph.Project.Issues[0].Pages[0].Panels[1].Locked = true
+	if _, err := NormalizeGutters(ph, 2, 12); !errors.Is(err, ErrPanelLocked) {
+		t.Fatalf("locked panel: err = %v, want ErrPanelLocked", err)
+	}
+	if g := ph.Project.Issues[0].Pages[0].Panels[0].Geometry; g.Width != 100 {
+		t.Fatalf("page changed despite the locked panel: %+v", g)
+	}
+	if _, err := NormalizeGutters(ph, 9, 12); !errors.Is(err, ErrPageNotFound) {
+		t.Fatalf("missing page: err = %v", err)
+	}
+}
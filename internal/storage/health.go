@@ -0,0 +1,263 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+// HealthStatus is the outcome of one project health check.
+type HealthStatus string
+
+const (
+	HealthOK      HealthStatus = "ok"
+	HealthWarning HealthStatus = "warning"
+	HealthError   HealthStatus = "error"
+)
+
+// HealthCheck names a project health check.
+type HealthCheck string
+
+const (
+	HealthIndex           HealthCheck = "index"             // index integrity and indexed manifest documents
+	HealthBackups         HealthCheck = "backups"           // age of the newest manifest backup
+	HealthCrashAutosaves  HealthCheck = "crash_autosaves"   // crash autosaves newer than the manifest, awaiting review
+	HealthOrphanPreviews  HealthCheck = "orphaned_previews" // cached previews of pages no longer in the manifest
+	HealthValidation      HealthCheck = "validation"        // ValidateProject findings
+	HealthScriptSnapshots HealthCheck = "script_snapshots"  // script history snapshots in the index
+	HealthSync            HealthCheck = "sync"              // last sync round that reached the server
+)
+
+// HealthAction names the fix the app offers for a health item.
+type HealthAction string
+
+const (
+	HealthActionNone           HealthAction = ""
+	HealthActionRebuildIndex   HealthAction = "rebuild_index"
+	HealthActionOpenBackups    HealthAction = "open_backups"
+	HealthActionRunMaintenance HealthAction = "run_maintenance"
+	HealthActionReviewCrash    HealthAction = "review_crash_autosaves"
+)
+
+// HealthBackupMaxAge is the backup age above which the backups check warns.
+const HealthBackupMaxAge = 7 * 24 * time.Hour
+
+// HealthSyncMaxAge is the time since the last successful sync above which the sync check warns.
+const HealthSyncMaxAge = 24 * time.Hour
+
+// HealthItem is the result of one check. Count and Expected carry its numbers (documents, autosaves,
+// bytes, issues, snapshots, unsent ops), Time its timestamp (newest backup, last sync) and Detail an
+// English summary; the app formats its own text from the fields. Action is set where a fix applies;
+// backups always offer to open the backup list.
+type HealthItem struct {
+	Check    HealthCheck
+	Status   HealthStatus
+	Action   HealthAction
+	Count    int64
+	Expected int64
+	Warnings int64 // validation warnings, or failed sync ops
+	Time     time.Time
+	Detail   string
+}
+
+func (it HealthItem) String() string {
+	return fmt.Sprintf("%-7s %-17s %s", it.Status, it.Check, it.Detail)
+}
+
+// ProjectHealth is the result of HealthReport: one item per check, in a fixed order. The sync check is only
+// present for projects that have synced with a server or queued ops for one.
+type ProjectHealth struct {
+	Items []HealthItem
+}
+
+// Status is the worst status of all items.
+func (r ProjectHealth) Status() HealthStatus {
+	st := HealthOK
+	for _, it := range r.Items {
+		if it.Status == HealthError {
+			return HealthError
+		}
+		if it.Status == HealthWarning {
+			st = HealthWarning
+		}
+	}
+	return st
+}
+
+// HealthReport runs the project health checks: index integrity and document count, backup recency,
+// crash autosaves awaiting review, orphaned preview bytes, validation findings, script snapshots and,
+// for server-connected projects, the last successful sync. It only reads; a failing check becomes an
+// error item rather than an error of the report.
+func HealthReport(ctx context.Context, ph *ProjectHandle) (ProjectHealth, error) {
+	return healthReport(ctx, ph, time.Now())
+}
+
+func healthReport(ctx context.Context, ph *ProjectHandle, now time.Time) (ProjectHealth, error) {
+	if ph == nil {
+		return ProjectHealth{}, errors.New("nil ProjectHandle")
+	}
+	if strings.TrimSpace(ph.Root) == "" {
+		return ProjectHealth{}, errors.New("project root is required")
+	}
+	ix, done := projectIndex(ph)
+	defer done()
+	rep := ProjectHealth{Items: []HealthItem{
+		healthIndex(ctx, ix, ph.Project),
+		healthBackups(ph.Root, now),
+		healthCrashAutosaves(ph.Root),
+		healthOrphanPreviews(ctx, ix, ph.Project),
+		healthValidation(ph.Project),
+		healthScriptSnapshots(ctx, ix),
+	}}
+	if it, ok := healthSync(ctx, ix, now); ok {
+		rep.Items = append(rep.Items, it)
+	}
+	return rep, ctx.Err()
+}
+
+func healthFailed(check HealthCheck, action HealthAction, err error) HealthItem {
+	return HealthItem{Check: check, Status: HealthError, Action: action, Detail: err.Error()}
+}
+
+// healthIndex runs PRAGMA quick_check and compares the indexed manifest documents with the manifest;
+// the script, its scenes and the asset folder are indexed on top of those and not counted.
+func healthIndex(ctx context.Context, ix *IndexHandle, proj domain.Project) HealthItem {
+	db, err := ix.acquire()
+	if err != nil {
+		return healthFailed(HealthIndex, HealthActionRebuildIndex, err)
+	}
+	defer ix.release()
+	var chk string
+	if err := db.QueryRowContext(ctx, `PRAGMA quick_check;`).Scan(&chk); err != nil {
+		return healthFailed(HealthIndex, HealthActionRebuildIndex, fmt.Errorf("quick_check: %w", err))
+	}
+	if !strings.EqualFold(strings.TrimSpace(chk), "ok") {
+		return healthFailed(HealthIndex, HealthActionRebuildIndex, fmt.Errorf("quick_check: %s", chk))
+	}
+	it := HealthItem{Check: HealthIndex, Status: HealthOK, Expected: int64(len(ManifestDocuments(proj)))}
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM documents WHERE type NOT IN ('script', 'scene', 'asset')`).Scan(&it.Count); err != nil {
+		return healthFailed(HealthIndex, HealthActionRebuildIndex, fmt.Errorf("count documents: %w", err))
+	}
+	it.Detail = fmt.Sprintf("quick_check ok, %d of %d manifest documents indexed", it.Count, it.Expected)
+	if it.Count != it.Expected {
+		it.Status, it.Action = HealthWarning, HealthActionRebuildIndex
+	}
+	return it
+}
+
+func healthBackups(root string, now time.Time) HealthItem {
+	backups, err := ListBackups(root)
+	if err != nil {
+		return healthFailed(HealthBackups, HealthActionOpenBackups, err)
+	}
+	it := HealthItem{Check: HealthBackups, Status: HealthOK, Action: HealthActionOpenBackups, Count: int64(len(backups))}
+	if len(backups) == 0 {
+		it.Status, it.Detail = HealthWarning, "no backups yet"
+		return it
+	}
+	it.Time = backups[0].Time
+	age := now.Sub(it.Time)
+	it.Detail = fmt.Sprintf("%d backups, newest %s old", len(backups), age.Round(time.Minute))
+	if age > HealthBackupMaxAge {
+		it.Status = HealthWarning
+	}
+	return it
+}
+
+func healthCrashAutosaves(root string) HealthItem {
+	snaps, err := FindRecoverySnapshots(root)
+	if err != nil {
+		return healthFailed(HealthCrashAutosaves, HealthActionOpenBackups, err)
+	}
+	it := HealthItem{Check: HealthCrashAutosaves, Status: HealthOK, Count: int64(len(snaps)), Detail: fmt.Sprintf("%d awaiting review", len(snaps))}
+	if len(snaps) > 0 {
+		it.Status, it.Action = HealthWarning, HealthActionReviewCrash
+	}
+	return it
+}
+
+func healthOrphanPreviews(ctx context.Context, ix *IndexHandle, proj domain.Project) HealthItem {
+	items, err := ix.gcIndexRows(ctx, proj.Issues, true)
+	if err != nil {
+		return healthFailed(HealthOrphanPreviews, HealthActionRunMaintenance, err)
+	}
+	it := HealthItem{Check: HealthOrphanPreviews, Status: HealthOK}
+	for _, gi := range items {
+		if gi.Category == GCPreviews {
+			it.Count += gi.Bytes
+		}
+	}
+	it.Detail = fmt.Sprintf("%d bytes", it.Count)
+	if it.Count > 0 {
+		it.Status, it.Action = HealthWarning, HealthActionRunMaintenance
+	}
+	return it
+}
+
+// healthValidation counts errors in Count and warnings in Warnings.
+func healthValidation(proj domain.Project) HealthItem {
+	it := HealthItem{Check: HealthValidation, Status: HealthOK}
+	for _, v := range ValidateProject(proj) {
+		if v.Severity == SeverityError {
+			it.Count++
+		} else {
+			it.Warnings++
+		}
+	}
+	it.Detail = fmt.Sprintf("%d errors, %d warnings", it.Count, it.Warnings)
+	switch {
+	case it.Count > 0:
+		it.Status = HealthError
+	case it.Warnings > 0:
+		it.Status = HealthWarning
+	}
+	return it
+}
+
+func healthScriptSnapshots(ctx context.Context, ix *IndexHandle) HealthItem {
+	db, err := ix.acquire()
+	if err != nil {
+		return healthFailed(HealthScriptSnapshots, HealthActionNone, err)
+	}
+	defer ix.release()
+	it := HealthItem{Check: HealthScriptSnapshots, Status: HealthOK}
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM script_snapshots`).Scan(&it.Count); err != nil {
+		return healthFailed(HealthScriptSnapshots, HealthActionNone, fmt.Errorf("count script snapshots: %w", err))
+	}
+	it.Detail = fmt.Sprintf("%d snapshots", it.Count)
+	return it
+}
+
+// healthSync reports the last successful sync, with the unsent ops in Count and the failed ones in
+// Warnings. ok is false for projects that never synced and have nothing queued.
+func healthSync(ctx context.Context, ix *IndexHandle, now time.Time) (HealthItem, bool) {
+	st, err := ix.SyncQueueStats(ctx)
+	if err != nil {
+		return healthFailed(HealthSync, HealthActionNone, err), true
+	}
+	if st.LastSuccess.IsZero() && st.Unsent() == 0 && st.Conflicts == 0 {
+		return HealthItem{}, false
+	}
+	it := HealthItem{Check: HealthSync, Status: HealthOK, Count: int64(st.Unsent()), Warnings: int64(st.Failed), Time: st.LastSuccess}
+	if st.LastSuccess.IsZero() {
+		it.Detail = fmt.Sprintf("never synced, %d ops unsent", st.Unsent())
+	} else {
+		it.Detail = fmt.Sprintf("last sync %s ago, %d ops unsent", now.Sub(st.LastSuccess).Round(time.Second), st.Unsent())
+	}
+	if st.LastSuccess.IsZero() || now.Sub(st.LastSuccess) > HealthSyncMaxAge || st.Failed > 0 || st.Conflicts > 0 {
+		it.Status = HealthWarning
+	}
+	return it, true
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+func healthItems(t *testing.T, ph *ProjectHandle, now time.Time) map[HealthCheck]HealthItem {
+	t.Helper()
+	rep, err := healthReport(context.Background(), ph, now)
+	if err != nil {
+		t.Fatalf("health report: %v", err)
+	}
+	items := map[HealthCheck]HealthItem{}
+	for _, it := range rep.Items {
+		items[it.Check] = it
+	}
+	return items
+}
+
+func TestHealthReport(t *testing.T) {
+	now := time.Now()
+	ph, _ := messyProject(t, now)
+	ctx := context.Background()
+	// The newer crash autosave was written after the last save
+	if err := os.Chtimes(ph.ManifestPath, now.Add(-time.Hour), now.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	items := healthItems(t, ph, now)
+	if it := items[HealthIndex]; it.Status != HealthOK || it.Count != it.Expected || it.Expected == 0 {
+		t.Errorf("index = %+v", it)
+	}
+	if it := items[HealthBackups]; it.Status != HealthOK || it.Count != 4 || now.Sub(it.Time) > 2*time.Hour {
+		t.Errorf("backups = %+v", it)
+	}
+	if it := items[HealthCrashAutosaves]; it.Status != HealthWarning || it.Count != 1 || it.Action != HealthActionReviewCrash {
+		t.Errorf("crash autosaves = %+v", it)
+	}
+	if it := items[HealthOrphanPreviews]; it.Status != HealthWarning || it.Count != int64(len("png bytes")) || it.Action != HealthActionRunMaintenance {
+		t.Errorf("orphaned previews = %+v", it)
+	}
+	if it := items[HealthScriptSnapshots]; it.Status != HealthOK || it.Count != 0 {
+		t.Errorf("script snapshots = %+v", it)
+	}
+	if it, ok := items[HealthSync]; ok {
+		t.Errorf("sync reported for a project without a server: %+v", it)
+	}
+	var errs, warns int64
+	for _, v := range ValidateProject(ph.Project) {
+		if v.Severity == SeverityError {
+			errs++
+		} else {
+			warns++
+		}
+	}
+	if it := items[HealthValidation]; it.Count != errs || it.Warnings != warns {
+		t.Errorf("validation = %+v, want %d errors and %d warnings", it, errs, warns)
+	}
+
+	// A week later the backups are stale; an edited manifest leaves the index behind; a duplicate page
+	// number is a validation error; a synced project reports its sync
+	ph.Project.Issues[0].Pages = append(ph.Project.Issues[0].Pages, domain.Page{Number: 2, Notes: "Splash"})
+	if _, err := RecordScriptSnapshot(ctx, ph, "# Scene 1", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := ph.Index().SetSyncLastSuccess(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	later := now.Add(HealthBackupMaxAge + time.Hour)
+	items = healthItems(t, ph, later)
+	if it := items[HealthBackups]; it.Status != HealthWarning {
+		t.Errorf("stale backups = %+v", it)
+	}
+	if it := items[HealthIndex]; it.Status != HealthWarning || it.Count+1 != it.Expected || it.Action != HealthActionRebuildIndex {
+		t.Errorf("stale index = %+v", it)
+	}
+	if it := items[HealthValidation]; it.Status != HealthError || it.Count == 0 {
+		t.Errorf("validation with duplicate page = %+v", it)
+	}
+	if it := items[HealthScriptSnapshots]; it.Count != 1 {
+		t.Errorf("script snapshots = %+v", it)
+	}
+	if it := items[HealthSync]; it.Status != HealthWarning || !it.Time.Equal(now.UTC()) {
+		t.Errorf("sync a week ago = %+v", it)
+	}
+	if it := healthItems(t, ph, now.Add(time.Minute))[HealthSync]; it.Status != HealthOK {
+		t.Errorf("recent sync = %+v", it)
+	}
+}
+
+func TestHealthReportStatusAndBrokenIndex(t *testing.T) {
+	root := t.TempDir()
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName)}
+	t.Cleanup(func() { _ = ph.Close() })
+	if err := os.MkdirAll(filepath.Dir(IndexPath(root)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(IndexPath(root), []byte("not a database"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := HealthReport(context.Background(), ph)
+	if err != nil {
+		t.Fatalf("health report: %v", err)
+	}
+	if rep.Status() != HealthError || rep.Items[0].Check != HealthIndex || rep.Items[0].Status != HealthError || rep.Items[0].Action != HealthActionRebuildIndex {
+		t.Fatalf("broken index report = %+v", rep.Items)
+	}
+	if it := rep.Items[1]; it.Check != HealthBackups || it.Status != HealthWarning {
+		t.Errorf("no backups = %+v", it)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"strconv"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// ID kinds tracked in domain.Project.IDCounters.
+const (
+	IDKindPanel   = "panel"
+	IDKindBalloon = "balloon"
+	IDKindCaption = "caption"
+	IDKindSFX     = "sfx"
+)
+
+// idPrefix is the prefix of generated IDs per kind; the counter value follows it ("p7", "balloon-12").
+var idPrefix = map[string]string{
+	IDKindPanel:   "p",
+	IDKindBalloon: "balloon-",
+	IDKindCaption: "caption-",
+	IDKindSFX:     "sfx-",
+}
+
+// IDRename records an ID changed by DedupeIDs.
+type IDRename struct {
+	Kind       string
+	PageNumber int
+	PanelID    string // owning panel (after renaming) for balloons
+	OldID      string
+	NewID      string
+}
+
+// NewPanelID returns a project-unique panel ID and advances the project's panel counter.
+func NewPanelID(ph *ProjectHandle) string { return nextID(&ph.Project, IDKindPanel) }
+
+// NewBalloonID returns a project-unique balloon ID and advances the project's balloon counter.
+func NewBalloonID(ph *ProjectHandle) string { return nextID(&ph.Project, IDKindBalloon) }
+
+// NewCaptionID returns a project-unique caption ID and advances the project's caption counter.
+func NewCaptionID(ph *ProjectHandle) string { return nextID(&ph.Project, IDKindCaption) }
+
+// NewSFXID returns a project-unique SFX ID and advances the project's SFX counter.
+func NewSFXID(ph *ProjectHandle) string { return nextID(&ph.Project, IDKindSFX) }
+
+// nextID increments the counter for kind and returns the resulting ID. The counter is first raised to the
+// highest number already used with the kind's prefix, so manifests written before counters existed, and IDs
+// typed in by hand, never collide with generated ones.
+func nextID(p *domain.Project, kind string) string {
+	prefix := idPrefix[kind]
+	n := p.IDCounters[kind]
+	used := map[string]bool{}
+	forEachID(p, kind, func(id string) {
+		used[id] = true
+		if v, ok := idNumber(id, prefix); ok && v > n {
+			n = v
+		}
+	})
+	for {
+		n++
+		if id := prefix + strconv.Itoa(n); !used[id] {
+			if p.IDCounters == nil {
+				p.IDCounters = map[string]int{}
+			}
+			p.IDCounters[kind] = n
+			return id
+		}
+	}
+}
+
+// idNumber parses the counter part of id, e.g. 12 for "balloon-12" with prefix "balloon-".
+func idNumber(id, prefix string) (int, bool) {
+	rest, ok := strings.CutPrefix(id, prefix)
+	if !ok || rest == "" {
+		return 0, false
+	}
+	v, err := strconv.Atoi(rest)
+	if err != nil || v < 0 {
+		return 0, false
+	}
+	return v, true
+}
+
+func forEachID(p *domain.Project, kind string, fn func(id string)) {
+	for _, iss := range p.Issues {
+		for _, pg := range iss.Pages {
+			for _, pn := range pg.Panels {
+				switch kind {
+				case IDKindPanel:
+					fn(pn.ID)
+				case IDKindBalloon:
+					for _, b := range pn.Balloons {
+						fn(b.ID)
+					}
+				case IDKindCaption:
+					for _, c := range pn.Captions {
+						fn(c.ID)
+					}
+				case IDKindSFX:
+					for _, fx := range pn.SFX {
+						fn(fx.ID)
+					}
+				}
+			}
+		}
+	}
+}
+
+// DedupeIDs gives fresh IDs to panels that repeat an earlier panel ID on the same page and to balloons
+// that repeat an earlier balloon ID in the same panel. The first occurrence keeps its ID, so comments,
+// search paths and other references that resolved to it before still do. A panel's own BeatIDs and
+// asset tokens in its notes travel with it and need no fixing.
+func DedupeIDs(p *domain.Project) []IDRename {
+	var out []IDRename
+	for i := range p.Issues {
+		for j := range p.Issues[i].Pages {
+			pg := &p.Issues[i].Pages[j]
+			seen := map[string]bool{}
+			for k := range pg.Panels {
+				pn := &pg.Panels[k]
+				if pn.ID != "" && seen[pn.ID] {
+					old := pn.ID
+					pn.ID = nextID(p, IDKindPanel)
+					out = append(out, IDRename{Kind: IDKindPanel, PageNumber: pg.Number, OldID: old, NewID: pn.ID})
+				}
+				seen[pn.ID] = true
+				seenB := map[string]bool{}
+				for b := range pn.Balloons {
+					bl := &pn.Balloons[b]
+					if bl.ID != "" && seenB[bl.ID] {
+						old := bl.ID
+						bl.ID = nextID(p, IDKindBalloon)
+						out = append(out, IDRename{Kind: IDKindBalloon, PageNumber: pg.Number, PanelID: pn.ID, OldID: old, NewID: bl.ID})
+					}
+					seenB[bl.ID] = true
+				}
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+// The old page-local and length-based schemes hand out an ID again after a delete.
+func TestLegacyIDSchemesCollideAfterDelete(t *testing.T) {
+	pg := &domain.Page{Number: 1, Panels: []domain.Panel{{ID: "p1"}, {ID: "p2", BeatIDs: []string{"beat-9"}}}}
+	deleted := pg.Panels[1]
+	pg.Panels = pg.Panels[:1]
+	if id := NextPanelID(pg); id != deleted.ID {
+		t.Fatalf("expected the page-local scheme to reuse %s, got %s", deleted.ID, id)
+	}
+	pn := domain.Panel{Balloons: []domain.Balloon{{ID: "balloon-1"}, {ID: "balloon-2"}}}
+	pn.Balloons = pn.Balloons[1:]
+	if id := fmt.Sprintf("balloon-%d", len(pn.Balloons)+1); id != pn.Balloons[0].ID {
+		t.Fatalf("expected the length-based scheme to duplicate %s, got %s", pn.Balloons[0].ID, id)
+	}
+}
+
+func TestAddPanelNeverReusesIDs(t *testing.T) {
+	ph := &ProjectHandle{Project: domain.Project{Name: "IDs"}}
+	var ids []string
+	for i := 0; i < 3; i++ {
+		pn, err := AddPanel(ph, 1, domain.Panel{})
+		if err != nil {
+			t.Fatalf("AddPanel: %v", err)
+		}
+		ids = append(ids, pn.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"p1", "p2", "p3"}) {
+		t.Fatalf("unexpected ids %v", ids)
+	}
+	pg := &ph.Project.Issues[0].Pages[0]
+	pg.Panels = pg.Panels[:2] // delete p3
+
+	// The counter survives a manifest round trip.
+	b, err := json.Marshal(ph.Project)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var re domain.Project
+	if err := json.Unmarshal(b, &re); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	ph.Project = re
+	pn, err := AddPanel(ph, 1, domain.Panel{})
+	if err != nil {
+		t.Fatalf("AddPanel after delete: %v", err)
+	}
+	if pn.ID != "p4" {
+		t.Fatalf("deleted id reused or counter lost: got %s", pn.ID)
+	}
+	// IDs are unique across pages too.
+	pn, err = AddPanel(ph, 2, domain.Panel{})
+	if err != nil || pn.ID != "p5" {
+		t.Fatalf("expected p5 on page 2, got %s (%v)", pn.ID, err)
+	}
+}
+
+func TestNewIDsSkipExistingIDs(t *testing.T) {
+	// A manifest written before counters existed, with hand-named and generated IDs.
+	ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{
+		{ID: "p7", Balloons: []domain.Balloon{{ID: "balloon-2"}, {ID: "intro"}}},
+		{ID: "splash"},
+	}}}}}}}
+	if id := NewPanelID(ph); id != "p8" {
+		t.Fatalf("expected p8, got %s", id)
+	}
+	if id := NewBalloonID(ph); id != "balloon-3" {
+		t.Fatalf("expected balloon-3, got %s", id)
+	}
+	if id := NewBalloonID(ph); id != "balloon-4" {
+		t.Fatalf("expected balloon-4, got %s", id)
+	}
+	if got := ph.Project.IDCounters; got[IDKindPanel] != 8 || got[IDKindBalloon] != 4 {
+		t.Fatalf("unexpected counters %v", got)
+	}
+}
+
+// Caption and SFX IDs come from the counters too, so deleting one and adding another never repeats an ID.
+func TestCaptionAndSFXIDsAfterDelete(t *testing.T) {
+	ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{{
+		ID:       "p1",
+		Captions: []domain.Caption{{ID: "caption-1", ScriptLine: "c:2"}, {ID: "caption-2", ScriptLine: "c:4"}},
+		SFX:      []domain.SFXItem{{ID: "sfx-1"}, {ID: "sfx-2"}},
+	}}}}}}}}
+	pn := &ph.Project.Issues[0].Pages[0].Panels[0]
+	pn.Captions = pn.Captions[1:] // delete caption-1
+	pn.SFX = pn.SFX[1:]           // delete sfx-1
+	cid, err := AssignCaptionLine(ph, 1, "p1", "", "c:6", "Later")
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	if cid != "caption-3" || len(pn.Captions) != 2 || pn.Captions[0].ID == pn.Captions[1].ID {
+		t.Fatalf("new caption %s; captions %+v", cid, pn.Captions)
+	}
+	if id := NewSFXID(ph); id != "sfx-3" {
+		t.Fatalf("expected sfx-3, got %s", id)
+	}
+	if got := ph.Project.IDCounters; got[IDKindCaption] != 3 || got[IDKindSFX] != 3 {
+		t.Fatalf("unexpected counters %v", got)
+	}
+}
+
+func TestDedupeIDs(t *testing.T) {
+	p := domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{
+		{Number: 1, Panels: []domain.Panel{
+			{ID: "p1", BeatIDs: []string{"a"}},
+			{ID: "p2", BeatIDs: []string{"b"}, Balloons: []domain.Balloon{{ID: "balloon-1"}, {ID: "balloon-1"}}},
+			{ID: "p2", BeatIDs: []string{"c"}, Notes: "asset:assets/x.png"},
+		}},
+		{Number: 2, Panels: []domain.Panel{{ID: "p1"}}},
+	}}}}
+	renames := DedupeIDs(&p)
+	want := []IDRename{
+		{Kind: IDKindBalloon, PageNumber: 1, PanelID: "p2", OldID: "balloon-1", NewID: "balloon-2"},
+		{Kind: IDKindPanel, PageNumber: 1, OldID: "p2", NewID: "p3"},
+	}
+	if !reflect.DeepEqual(renames, want) {
+		t.Fatalf("renames = %+v, want %+v", renames, want)
+	}
+	pg := p.Issues[0].Pages[0]
+	if pg.Panels[1].ID != "p2" || pg.Panels[2].ID != "p3" {
+		t.Fatalf("expected the later duplicate to be renamed: %+v", pg.Panels)
+	}
+	if !reflect.DeepEqual(pg.Panels[2].BeatIDs, []string{"c"}) || pg.Panels[2].Notes != "asset:assets/x.png" {
+		t.Fatalf("renamed panel lost its beats or notes: %+v", pg.Panels[2])
+	}
+	if p.Issues[0].Pages[1].Panels[0].ID != "p1" {
+		t.Fatalf("same id on another page must be kept")
+	}
+	if again := DedupeIDs(&p); len(again) != 0 {
+		t.Fatalf("second pass should be a no-op: %+v", again)
+	}
+}
+
+func TestOpenRenamesDuplicateIDs(t *testing.T) {
+	// Open starts background index work; use a manually cleaned dir to avoid cleanup races.
+	root, err := os.MkdirTemp("", "gcw-ids-")
+	if err != nil {
+		t.Fatalf("mkdtemp: %v", err)
+	}
+	t.Cleanup(func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = os.RemoveAll(root)
+	})
+	writeManifest(t, root, domain.Project{Name: "Dupes", Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{
+		{ID: "p1"}, {ID: "p2"}, {ID: "p2", BeatIDs: []string{"late"}},
+	}}}}}}, time.Now())
+	ph, err := Open(root)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	pns := ph.Project.Issues[0].Pages[0].Panels
+	if pns[1].ID != "p2" || pns[2].ID != "p3" || pns[2].BeatIDs[0] != "late" {
+		t.Fatalf("duplicate not renamed on open: %+v", pns)
+	}
+	if pn, err := AddPanel(ph, 1, domain.Panel{}); err != nil || pn.ID != "p4" {
+		t.Fatalf("expected p4 after migration, got %s (%v)", pn.ID, err)
+	}
+}
+
+func TestUpdatePanelMetaRejectsExistingID(t *testing.T) {
+	ph := &ProjectHandle{Project: domain.Project{Name: "IDs"}}
+	for i := 0; i < 2; i++ {
+		if _, err := AddPanel(ph, 1, domain.Panel{}); err != nil {
+			t.Fatalf("AddPanel: %v", err)
+		}
+	}
+	if err := UpdatePanelMeta(ph, 1, "p2", "p1", "", false); err == nil {
+		t.Fatalf("expected renaming onto an existing id to fail")
+	}
+	if err := UpdatePanelMeta(ph, 1, "p2", "p9", "moved", false); err != nil {
+		t.Fatalf("rename to a free id: %v", err)
+	}
+	if id := NewPanelID(ph); id != "p10" {
+		t.Fatalf("generator should skip past hand-assigned p9, got %s", id)
+	}
+}
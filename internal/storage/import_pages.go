@@ -0,0 +1,308 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // decode imported GIF pages
+	_ "image/jpeg" // decode imported JPEG pages
+	_ "image/png"  // decode imported PNG pages
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// ImportedAssetsDir is the folder, relative to the project root, that imported page images are copied to.
+const ImportedAssetsDir = AssetsDirName + "/imported"
+
+// maxImportedImageSize bounds one page image read from a folder or archive, so a crafted archive
+// cannot fill the disk.
+const maxImportedImageSize = 512 << 20
+
+// PageImportOptions controls PlanPageImport and ImportPages.
+type PageImportOptions struct {
+	// DPI > 0 sizes the issue from the first image: at this resolution the image covers the page
+	// including its bleed, and every imported image is stretched over its page's bleed. 0 keeps the
+	// issue's trim size and letterboxes each image inside the trim box.
+	DPI float64
+}
+
+// PageImportItem is one page an import creates.
+type PageImportItem struct {
+	Source        string // file name in the folder, or entry name in the archive
+	Asset         string // copy of the image, relative to the project root, e.g. assets/imported/001.jpg
+	Number        int    // number of the new page
+	Width, Height int    // pixels
+}
+
+// PageImportPlan is what an import of a folder or CBZ creates. PlanPageImport returns it without
+// changing anything, as a preview.
+type PageImportPlan struct {
+	Items   []PageImportItem
+	Skipped []string // entries that are not PNG, JPEG or GIF images, with the reason
+	// TrimWidth and TrimHeight are the issue's trim size after the import, in points.
+	TrimWidth, TrimHeight float64
+	TrimChanged           bool
+	Fit                   string // fit of the new pages' art references
+}
+
+// pageImportEntry is an image file of an import source.
+type pageImportEntry struct {
+	name string
+	open func() (io.ReadCloser, error)
+}
+
+// openPageImportSource lists the files of a folder (not its subfolders) or of a .cbz/.zip archive.
+// Hidden and temporary files and macOS resource forks are left out. close releases the archive.
+func openPageImportSource(src string) (entries []pageImportEntry, closeFn func(), err error) {
+	st, err := os.Stat(src)
+	if err != nil {
+		return nil, nil, fmt.Errorf("import source: %w", err)
+	}
+	if st.IsDir() {
+		des, err := os.ReadDir(src)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read import folder: %w", err)
+		}
+		for _, de := range des {
+			if !de.Type().IsRegular() || IsTransientAssetName(de.Name()) {
+				continue
+			}
+			p := filepath.Join(src, de.Name())
+			entries = append(entries, pageImportEntry{name: de.Name(), open: func() (io.ReadCloser, error) { return os.Open(p) }})
+		}
+		return entries, func() {}, nil
+	}
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open %s as CBZ: %w", filepath.Base(src), err)
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || IsTransientAssetName(path.Base(f.Name)) {
+			continue
+		}
+		entries = append(entries, pageImportEntry{name: f.Name, open: f.Open})
+	}
+	return entries, func() { _ = zr.Close() }, nil
+}
+
+// PlanPageImport lists the pages an import of src (a folder of images or a .cbz) into the issue would
+// create: one page per image in file name order, numbered after the issue's last page. Only the
+// image headers are read.
+func PlanPageImport(ph *ProjectHandle, issueIndex int, src string, opt PageImportOptions) (PageImportPlan, error) {
+	entries, closeFn, err := openPageImportSource(src)
+	if err != nil {
+		return PageImportPlan{}, err
+	}
+	defer closeFn()
+	return planPageImport(ph, issueIndex, entries, opt)
+}
+
+func planPageImport(ph *ProjectHandle, issueIndex int, entries []pageImportEntry, opt PageImportOptions) (PageImportPlan, error) {
+	if ph == nil {
+		return PageImportPlan{}, errors.New("project handle is nil")
+	}
+	if issueIndex < 0 || issueIndex >= len(ph.Project.Issues) {
+		return PageImportPlan{}, fmt.Errorf("issue index %d out of range", issueIndex)
+	}
+	if opt.DPI < 0 {
+		return PageImportPlan{}, fmt.Errorf("DPI %g must not be negative", opt.DPI)
+	}
+	iss := ph.Project.Issues[issueIndex]
+	slices.SortStableFunc(entries, func(a, b pageImportEntry) int { return naturalCompare(a.name, b.name) })
+
+	plan := PageImportPlan{TrimWidth: iss.TrimWidth, TrimHeight: iss.TrimHeight, Fit: domain.ReferenceFitContain}
+	next := 1
+	for _, pg := range iss.Pages {
+		next = max(next, pg.Number+1)
+	}
+	taken := map[string]bool{}
+	for _, e := range entries {
+		switch strings.ToLower(path.Ext(e.name)) {
+		case ".png", ".jpg", ".jpeg", ".gif":
+		default:
+			plan.Skipped = append(plan.Skipped, e.name+": not a PNG, JPEG or GIF image")
+			continue
+		}
+		cfg, err := imageConfig(e)
+		if err != nil {
+			plan.Skipped = append(plan.Skipped, fmt.Sprintf("%s: %v", e.name, err))
+			continue
+		}
+		asset := importedAssetPath(ph.Root, path.Base(e.name), taken)
+		plan.Items = append(plan.Items, PageImportItem{Source: e.name, Asset: asset, Number: next, Width: cfg.Width, Height: cfg.Height})
+		next++
+	}
+	if len(plan.Items) == 0 {
+		return plan, errors.New("no PNG, JPEG or GIF images to import")
+	}
+	if opt.DPI > 0 {
+		first := plan.Items[0]
+		w := domain.InchesToPoints(float64(first.Width)/opt.DPI) - 2*iss.Bleed
+		h := domain.InchesToPoints(float64(first.Height)/opt.DPI) - 2*iss.Bleed
+		if w <= 0 || h <= 0 {
+			return plan, fmt.Errorf("%s at %g DPI is smaller than the issue's bleed", first.Source, opt.DPI)
+		}
+		plan.TrimWidth, plan.TrimHeight = roundTo(w, 100), roundTo(h, 100)
+		plan.TrimChanged = plan.TrimWidth != iss.TrimWidth || plan.TrimHeight != iss.TrimHeight
+		plan.Fit = domain.ReferenceFitBleed
+	}
+	return plan, nil
+}
+
+// imageConfig reads the dimensions of an image entry.
+func imageConfig(e pageImportEntry) (image.Config, error) {
+	rc, err := e.open()
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer func() { _ = rc.Close() }()
+	cfg, _, err := image.DecodeConfig(rc)
+	if err != nil {
+		return image.Config{}, fmt.Errorf("unreadable image: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return image.Config{}, errors.New("image has no pixels")
+	}
+	return cfg, nil
+}
+
+// importedAssetPath returns a free path under ImportedAssetsDir for base: neither an existing file
+// nor one already in taken, which it is added to. Name clashes get a numeric suffix ("01-2.jpg").
+func importedAssetPath(root, base string, taken map[string]bool) string {
+	ext := path.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	for n := 1; ; n++ {
+		name := base
+		if n > 1 {
+			name = stem + "-" + strconv.Itoa(n) + ext
+		}
+		rel := ImportedAssetsDir + "/" + name
+		if taken[strings.ToLower(rel)] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(rel))); err == nil {
+			continue
+		}
+		taken[strings.ToLower(rel)] = true
+		return rel
+	}
+}
+
+// ImportPages imports the images of src (a folder or .cbz) as new pages of the issue, as planned by
+// PlanPageImport: each image is copied to assets/imported/ and becomes the locked art reference of
+// its page, which exporters draw under the panels. With opt.DPI set the issue's trim size is taken
+// from the first image. The project is changed in memory only; callers must Save. When copying
+// fails, the copies made so far are removed and the project is left unchanged.
+func ImportPages(ph *ProjectHandle, issueIndex int, src string, opt PageImportOptions) (PageImportPlan, error) {
+	entries, closeFn, err := openPageImportSource(src)
+	if err != nil {
+		return PageImportPlan{}, err
+	}
+	defer closeFn()
+	plan, err := planPageImport(ph, issueIndex, entries, opt)
+	if err != nil {
+		return plan, err
+	}
+	byName := make(map[string]pageImportEntry, len(entries))
+	for _, e := range entries {
+		byName[e.name] = e
+	}
+	if err := os.MkdirAll(filepath.Join(ph.Root, filepath.FromSlash(ImportedAssetsDir)), 0o755); err != nil {
+		return plan, fmt.Errorf("create %s: %w", ImportedAssetsDir, err)
+	}
+	var copied []string
+	for _, it := range plan.Items {
+		dst := filepath.Join(ph.Root, filepath.FromSlash(it.Asset))
+		if err := copyImportedImage(byName[it.Source], dst); err != nil {
+			for _, p := range copied {
+				_ = os.Remove(p)
+			}
+			return plan, fmt.Errorf("import %s: %w", it.Source, err)
+		}
+		copied = append(copied, dst)
+	}
+	iss := &ph.Project.Issues[issueIndex]
+	iss.TrimWidth, iss.TrimHeight = plan.TrimWidth, plan.TrimHeight
+	for _, it := range plan.Items {
+		iss.Pages = append(iss.Pages, domain.Page{
+			Number:         it.Number,
+			Panels:         []domain.Panel{},
+			ReferenceImage: &domain.ReferenceImage{Asset: it.Asset, Opacity: 1, Fit: plan.Fit, Locked: true, Art: true},
+		})
+	}
+	return plan, nil
+}
+
+// copyImportedImage copies an image entry to dst, failing on images over maxImportedImageSize.
+func copyImportedImage(e pageImportEntry, dst string) error {
+	rc, err := e.open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, io.LimitReader(rc, maxImportedImageSize+1))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && n > maxImportedImageSize {
+		err = fmt.Errorf("image is larger than %d MiB", maxImportedImageSize>>20)
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+	}
+	return err
+}
+
+// naturalCompare orders file names the way people number pages: case-insensitively, with runs of
+// digits compared by value, so "page2" comes before "page10".
+func naturalCompare(a, b string) int {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	for a != "" && b != "" {
+		da, db := digitPrefix(a), digitPrefix(b)
+		if da != "" && db != "" {
+			na, nb := strings.TrimLeft(da, "0"), strings.TrimLeft(db, "0")
+			if c := len(na) - len(nb); c != 0 {
+				return c
+			}
+			if c := strings.Compare(na, nb); c != 0 {
+				return c
+			}
+			a, b = a[len(da):], b[len(db):]
+			continue
+		}
+		if a[0] != b[0] {
+			return int(a[0]) - int(b[0])
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) - len(b)
+}
+
+// digitPrefix returns the leading ASCII digits of s.
+func digitPrefix(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i]
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func pngBytes(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func importProject(t *testing.T) *ProjectHandle {
+	return &ProjectHandle{Root: t.TempDir(), Project: domain.Project{Issues: []domain.Issue{{
+		TrimWidth: 400, TrimHeight: 600, Bleed: 9,
+		Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{}}, {Number: 2, Panels: []domain.Panel{}}},
+	}}}}
+}
+
+func TestImportPages_FolderInNaturalOrder(t *testing.T) {
+	ph := importProject(t)
+	src := t.TempDir()
+	for _, name := range []string{"page10.png", "page2.png", "Page1.png", ".DS_Store", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(src, name), pngBytes(t, 100, 150), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// An earlier import left a file with the same name
+	if err := os.MkdirAll(filepath.Join(ph.Root, "assets", "imported"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ph.Root, "assets", "imported", "page2.png"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := PlanPageImport(ph, 0, src, PageImportOptions{})
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	want := []PageImportItem{
+		{Source: "Page1.png", Asset: "assets/imported/Page1.png", Number: 3, Width: 100, Height: 150},
+		{Source: "page2.png", Asset: "assets/imported/page2-2.png", Number: 4, Width: 100, Height: 150},
+		{Source: "page10.png", Asset: "assets/imported/page10.png", Number: 5, Width: 100, Height: 150},
+	}
+	if !reflect.DeepEqual(plan.Items, want) {
+		t.Fatalf("items = %+v, want %+v", plan.Items, want)
+	}
+	if len(plan.Skipped) != 1 || !strings.HasPrefix(plan.Skipped[0], "notes.txt") {
+		t.Errorf("skipped = %v", plan.Skipped)
+	}
+	if plan.TrimChanged || plan.Fit != domain.ReferenceFitContain {
+		t.Errorf("issue settings kept: plan = %+v", plan)
+	}
+	if len(ph.Project.Issues[0].Pages) != 2 {
+		t.Fatal("plan changed the project")
+	}
+	if _, err := os.Stat(filepath.Join(ph.Root, "assets", "imported", "Page1.png")); !os.IsNotExist(err) {
+		t.Fatalf("plan copied files: %v", err)
+	}
+
+	if _, err := ImportPages(ph, 0, src, PageImportOptions{}); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	pages := ph.Project.Issues[0].Pages
+	if len(pages) != 5 {
+		t.Fatalf("pages = %d, want 5", len(pages))
+	}
+	ref := pages[3].ReferenceImage
+	if ref == nil || *ref != (domain.ReferenceImage{Asset: "assets/imported/page2-2.png", Opacity: 1, Fit: domain.ReferenceFitContain, Locked: true, Art: true}) {
+		t.Fatalf("page 4 reference = %+v", ref)
+	}
+	if old, _ := os.ReadFile(filepath.Join(ph.Root, "assets", "imported", "page2.png")); string(old) != "old" {
+		t.Error("existing asset overwritten")
+	}
+	for _, it := range want {
+		if _, err := os.Stat(filepath.Join(ph.Root, filepath.FromSlash(it.Asset))); err != nil {
+			t.Errorf("asset %s not copied: %v", it.Asset, err)
+		}
+	}
+	if issues := ValidateProject(ph.Project); slices.ContainsFunc(issues, func(i ValidationIssue) bool { return i.Severity == SeverityError }) {
+		t.Errorf("imported project does not validate: %+v", issues)
+	}
+}
+
+func TestImportPages_CBZSizesIssueFromFirstImage(t *testing.T) {
+	ph := importProject(t)
+	ph.Project.Issues[0].Pages = nil
+	src := filepath.Join(t.TempDir(), "book.cbz")
+	f, err := os.Create(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, e := range []struct {
+		name string
+		data []byte
+	}{
+		{"book/002.png", pngBytes(t, 60, 90)},
+		{"book/001.png", pngBytes(t, 300, 450)},
+		{"__MACOSX/book/._001.png", []byte("fork")},
+		{"ComicInfo.xml", []byte("<ComicInfo/>")},
+	} {
+		w, err := zw.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(e.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := ImportPages(ph, 0, src, PageImportOptions{DPI: 150})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	// 300x450 px at 150 DPI is 144x216 pt including 9 pt of bleed on each side
+	iss := ph.Project.Issues[0]
+	if iss.TrimWidth != 126 || iss.TrimHeight != 198 || !plan.TrimChanged {
+		t.Fatalf("trim = %gx%g, plan %+v", iss.TrimWidth, iss.TrimHeight, plan)
+	}
+	if len(iss.Pages) != 2 || iss.Pages[0].Number != 1 || iss.Pages[0].ReferenceImage.Asset != "assets/imported/001.png" {
+		t.Fatalf("pages = %+v", iss.Pages)
+	}
+	if iss.Pages[1].ReferenceImage.Fit != domain.ReferenceFitBleed {
+		t.Errorf("fit = %q, want bleed", iss.Pages[1].ReferenceImage.Fit)
+	}
+	if len(plan.Skipped) != 1 || !strings.HasPrefix(plan.Skipped[0], "ComicInfo.xml") {
+		t.Errorf("skipped = %v", plan.Skipped)
+	}
+
+	if _, err := PlanPageImport(ph, 0, src, PageImportOptions{DPI: 2000}); err == nil {
+		t.Error("an image smaller than the bleed was accepted")
+	}
+}
+
+func TestImportPages_NothingToImport(t *testing.T) {
+	ph := importProject(t)
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "broken.png"), []byte("not a png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := ImportPages(ph, 0, src, PageImportOptions{})
+	if err == nil || len(ph.Project.Issues[0].Pages) != 2 {
+		t.Fatalf("import of no images: err %v, pages %d", err, len(ph.Project.Issues[0].Pages))
+	}
+	if len(plan.Skipped) != 1 || !strings.Contains(plan.Skipped[0], "unreadable image") {
+		t.Errorf("skipped = %v", plan.Skipped)
+	}
+}
+
+func TestNaturalCompare(t *testing.T) {
+	names := []string{"p10.png", "P2.png", "p1.png", "p01a.png", "cover.png", "p002.png"}
+	slices.SortStableFunc(names, naturalCompare)
+	want := []string{"cover.png", "p1.png", "p01a.png", "P2.png", "p002.png", "p10.png"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("sorted = %v, want %v", names, want)
+	}
+}
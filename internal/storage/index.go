@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -34,7 +35,7 @@ const (
 
 	// schemaVersion tracks the local SQLite schema for the embedded index.
 	// Bump this when you perform breaking schema changes and add migrations.
-	schemaVersion = 2
+	schemaVersion = 6
 )
 
 // IndexPath returns the full path to the project's embedded index database file.
@@ -45,6 +46,8 @@ func IndexPath(projectRoot string) string {
 // InitOrOpenIndex ensures that the per-project SQLite index exists at .gcw/index.sqlite,
 // opens the database, enables WAL mode, and ensures the meta/version tables exist.
 // The returned *sql.DB is ready for use. Callers may close it when no longer needed.
+// An index with a newer schema than schemaVersion is not touched and reported as
+// ErrIndexNewerThanApp, naming both schema versions and the app version that wrote it.
 func InitOrOpenIndex(projectRoot string) (*sql.DB, error) {
 	l := applog.WithOperation(applog.WithComponent("storage"), "index_init").With(
 		slog.String("root", projectRoot),
@@ -78,7 +81,7 @@ func InitOrOpenIndex(projectRoot string) (*sql.DB, error) {
 	if _, err := db.ExecContext(ctx, "PRAGMA journal_mode=WAL;"); err != nil {
 		_ = db.Close()
 		l.Error("enable WAL failed", slog.Any("err", err))
-		return nil, fmt.Errorf("enable WAL: %w", err)
+		return nil, fmt.Errorf("enable WAL: %w", indexErr(err))
 	}
 	// Enforce foreign keys just in case future schema uses them.
 	if _, err := db.ExecContext(ctx, "PRAGMA foreign_keys=ON;"); err != nil {
@@ -87,20 +90,24 @@ func InitOrOpenIndex(projectRoot string) (*sql.DB, error) {
 
 	if err := ensureMetaAndVersion(ctx, db); err != nil {
 		_ = db.Close()
+		if errors.Is(err, ErrIndexNewerThanApp) {
+			l.Warn("index is newer than this app", slog.Any("err", err))
+			return nil, err
+		}
 		l.Error("ensure meta/version failed", slog.Any("err", err))
-		return nil, err
+		return nil, indexErr(err)
 	}
 	// Ensure core index schema exists (documents, FTS, cross-refs, assets, previews, snapshots)
 	if err := ensureIndexSchema(ctx, db); err != nil {
 		_ = db.Close()
 		l.Error("ensure index schema failed", slog.Any("err", err))
-		return nil, err
+		return nil, indexErr(err)
 	}
 	// Run migrations to bring DB schema up to date
 	if err := runMigrations(ctx, db); err != nil {
 		_ = db.Close()
 		l.Error("run migrations failed", slog.Any("err", err))
-		return nil, err
+		return nil, indexErr(err)
 	}
 
 	l.Info("index ready", slog.String("path", path))
@@ -132,7 +139,8 @@ func ensureMetaAndVersion(ctx context.Context, db *sql.DB) error {
 	appv := version.String()
 	// Check if a version row exists
 	var curSchema int
-	err := db.QueryRowContext(ctx, `SELECT schema FROM version WHERE id=1`).Scan(&curSchema)
+	var writtenBy sql.NullString
+	err := db.QueryRowContext(ctx, `SELECT schema, app FROM version WHERE id=1`).Scan(&curSchema, &writtenBy)
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		// Insert new row with current schemaVersion for a fresh DB
@@ -141,8 +149,11 @@ func ensureMetaAndVersion(ctx context.Context, db *sql.DB) error {
 		}
 	case err != nil:
 		return fmt.Errorf("read version: %w", err)
+	case curSchema > schemaVersion:
+		// Leave the index of the newer app as it is, including its record of who wrote it
+		return newerIndexErr(curSchema, writtenBy.String)
 	default:
-		// Update app and timestamp only; keep existing schema for migrations
+		// Record this app as the last writer; keep existing schema for migrations
 		if _, err := db.ExecContext(ctx, `UPDATE version SET app=?, updated_at=? WHERE id=1`, appv, now); err != nil {
 			return fmt.Errorf("update version: %w", err)
 		}
@@ -150,6 +161,16 @@ func ensureMetaAndVersion(ctx context.Context, db *sql.DB) error {
 	return nil
 }
 
+// newerIndexErr reports an index of schema version schema, written by app version app ("" when
+// unknown), that this app cannot use.
+func newerIndexErr(schema int, app string) error {
+	if app == "" {
+		app = "unknown version"
+	}
+	return fmt.Errorf("%w: index schema %d written by %s; this app (%s) reads up to schema %d",
+		ErrIndexNewerThanApp, schema, app, version.String(), schemaVersion)
+}
+
 // runMigrations applies incremental schema migrations up to schemaVersion.
 func runMigrations(ctx context.Context, db *sql.DB) error {
 	var cur int
@@ -157,8 +178,8 @@ func runMigrations(ctx context.Context, db *sql.DB) error {
 		return fmt.Errorf("read schema version: %w", err)
 	}
 	if cur > schemaVersion {
-		// Do not downgrade; just log and continue
-		return nil
+		// Never downgrade; ensureMetaAndVersion normally reports this first
+		return newerIndexErr(cur, "")
 	}
 	for cur < schemaVersion {
 		next := cur + 1
@@ -190,6 +211,74 @@ func runMigrations(ctx context.Context, db *sql.DB) error {
 			if _, err := db.ExecContext(ctx, `PRAGMA optimize;`); err != nil {
 				// best-effort optimize; ignore errors
 			}
+		case 3:
+			// Queue states, retry backoff and conflict detection for the sync outbox
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("begin migration %d: %w", next, err)
+			}
+			if err := ensureSyncQueueMigrated(ctx, tx); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("migration %d: %w", next, err)
+			}
+			if _, err := tx.ExecContext(ctx, `UPDATE version SET schema=?, updated_at=? WHERE id=1`, next, time.Now().UTC().Format(time.RFC3339)); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("migration %d update version: %w", next, err)
+			}
+			if err := tx.Commit(); err != nil {
+				return fmt.Errorf("migration %d commit: %w", next, err)
+			}
+		case 4:
+			// Labels of undo snapshots
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("begin migration %d: %w", next, err)
+			}
+			if err := ensureSnapshotsMigrated(ctx, tx); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("migration %d: %w", next, err)
+			}
+			if _, err := tx.ExecContext(ctx, `UPDATE version SET schema=?, updated_at=? WHERE id=1`, next, time.Now().UTC().Format(time.RFC3339)); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("migration %d update version: %w", next, err)
+			}
+			if err := tx.Commit(); err != nil {
+				return fmt.Errorf("migration %d commit: %w", next, err)
+			}
+		case 5:
+			// Audit log of project changes
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("begin migration %d: %w", next, err)
+			}
+			if err := ensureAuditLog(ctx, tx); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("migration %d: %w", next, err)
+			}
+			if _, err := tx.ExecContext(ctx, `UPDATE version SET schema=?, updated_at=? WHERE id=1`, next, time.Now().UTC().Format(time.RFC3339)); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("migration %d update version: %w", next, err)
+			}
+			if err := tx.Commit(); err != nil {
+				return fmt.Errorf("migration %d commit: %w", next, err)
+			}
+		case 6:
+			// Content hashes of the documents rows for the consistency check
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("begin migration %d: %w", next, err)
+			}
+			if err := ensureDocFingerprints(ctx, tx); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("migration %d: %w", next, err)
+			}
+			if _, err := tx.ExecContext(ctx, `UPDATE version SET schema=?, updated_at=? WHERE id=1`, next, time.Now().UTC().Format(time.RFC3339)); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("migration %d update version: %w", next, err)
+			}
+			if err := tx.Commit(); err != nil {
+				return fmt.Errorf("migration %d commit: %w", next, err)
+			}
 		default:
 			// Unknown future step; break
 		}
@@ -213,6 +302,8 @@ func ensureIndexSchema(ctx context.Context, db *sql.DB) error {
 		// Helpful indices for lookup
 		`CREATE INDEX IF NOT EXISTS idx_documents_path ON documents(path);`,
 		`CREATE INDEX IF NOT EXISTS idx_documents_page ON documents(page_id);`,
+		createDocFingerprintsSQL,
+		`CREATE INDEX IF NOT EXISTS idx_doc_fingerprints_path ON doc_fingerprints(path);`,
 
 		// Contentless FTS5 index fed from documents via triggers.
 		`CREATE VIRTUAL TABLE IF NOT EXISTS fts_documents USING fts5(
@@ -249,11 +340,13 @@ func ensureIndexSchema(ctx context.Context, db *sql.DB) error {
 		`CREATE UNIQUE INDEX IF NOT EXISTS ux_previews_page_panel ON previews(page_id, panel_id);`,
 
 		// Snapshots (history of page changes)
+		// label is added to older databases by ensureSnapshotsMigrated
 		`CREATE TABLE IF NOT EXISTS snapshots (
 			id         INTEGER PRIMARY KEY,
 			page_id    INTEGER NOT NULL,
 			ts         TEXT    NOT NULL,
-			delta_blob BLOB    NOT NULL
+			delta_blob BLOB    NOT NULL,
+			label      TEXT    NOT NULL DEFAULT ''
 		);`,
 		`CREATE INDEX IF NOT EXISTS idx_snapshots_page_ts ON snapshots(page_id, ts);`,
 
@@ -264,6 +357,42 @@ func ensureIndexSchema(ctx context.Context, db *sql.DB) error {
 			text  TEXT    NOT NULL
 		);`,
 		`CREATE INDEX IF NOT EXISTS idx_script_snapshots_ts ON script_snapshots(ts);`,
+
+		// Outbound sync ops waiting to be pushed to the server; not dropped by RebuildIndex.
+		// The queue columns after created_at are added to older databases by ensureSyncQueueMigrated.
+		`CREATE TABLE IF NOT EXISTS sync_outbox (
+			seq             INTEGER PRIMARY KEY AUTOINCREMENT,
+			op_id           TEXT    NOT NULL UNIQUE,
+			op_type         TEXT    NOT NULL,
+			entity_type     TEXT    NOT NULL,
+			entity_id       TEXT    NOT NULL,
+			payload         TEXT    NOT NULL,
+			created_at      TEXT    NOT NULL,
+			state           TEXT    NOT NULL DEFAULT 'pending',
+			attempts        INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TEXT    NOT NULL DEFAULT '',
+			last_error      TEXT    NOT NULL DEFAULT '',
+			base_version    INTEGER NOT NULL DEFAULT 0,
+			server_version  INTEGER NOT NULL DEFAULT 0
+		);`,
+		// Entities changed both locally and on the server, waiting for the user to pick a side; not dropped by RebuildIndex
+		`CREATE TABLE IF NOT EXISTS sync_conflicts (
+			id             INTEGER PRIMARY KEY AUTOINCREMENT,
+			entity_type    TEXT    NOT NULL,
+			entity_id      TEXT    NOT NULL,
+			local_op_id    TEXT    NOT NULL,
+			local_op_type  TEXT    NOT NULL,
+			local_payload  TEXT    NOT NULL,
+			remote_op_id   TEXT    NOT NULL,
+			remote_op_type TEXT    NOT NULL,
+			remote_payload TEXT    NOT NULL,
+			remote_version INTEGER NOT NULL,
+			remote_actor   TEXT    NOT NULL DEFAULT '',
+			detected_at    TEXT    NOT NULL,
+			resolution     TEXT    NOT NULL DEFAULT '',
+			resolved_at    TEXT    NOT NULL DEFAULT ''
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_sync_conflicts_entity ON sync_conflicts(entity_type, entity_id, resolution);`,
 	}
 	for _, q := range ddl {
 		if _, err := db.ExecContext(ctx, q); err != nil {
@@ -292,28 +421,44 @@ func ensureIndexSchema(ctx context.Context, db *sql.DB) error {
 	if err := EnsurePreviewsMigrated(ctx, db); err != nil {
 		return err
 	}
-	return nil
+	if err := ensureSnapshotsMigrated(ctx, db); err != nil {
+		return err
+	}
+	// The audit log of project changes is never synced and not dropped by RebuildIndex
+	if err := ensureAuditLog(ctx, db); err != nil {
+		return err
+	}
+	return ensureSyncQueueMigrated(ctx, db)
 }
 
 // DetectAndRebuildIndex checks for corruption or missing schema and rebuilds the index if needed.
 // It returns true when a rebuild was performed.
 func DetectAndRebuildIndex(ctx context.Context, projectRoot string, proj domain.Project) (bool, error) {
-	path := IndexPath(projectRoot)
+	return withIndex(projectRoot, func(ix *IndexHandle) (bool, error) { return ix.DetectAndRebuildIndex(ctx, proj) })
+}
+
+// DetectAndRebuildIndex checks the index file for corruption or missing schema and rebuilds it if needed.
+// A failed rebuild is reported as ErrIndexCorrupt. The cached connection is dropped first so the check sees the file as it is on disk.
+// An index written by a newer app version is not a defect: it is reported as ErrIndexNewerThanApp and
+// left for the user to replace with ResetIndex.
+func (ix *IndexHandle) DetectAndRebuildIndex(ctx context.Context, proj domain.Project) (bool, error) {
+	path := IndexPath(ix.root)
+	ix.discard()
 	// Try to open DB; if fails, attempt backup+delete+rebuild
-	db, err := InitOrOpenIndex(projectRoot)
+	db, err := ix.acquire()
+	if errors.Is(err, ErrIndexClosed) || errors.Is(err, ErrIndexNewerThanApp) {
+		return false, err
+	}
 	if err != nil {
-		if berr := backupIndexFile(path); berr != nil {
-			// best effort backup; continue even if it fails
-		}
-		if rerr := os.Remove(path); rerr != nil && !os.IsNotExist(rerr) {
+		bak := ix.snapshotBeforeRebuild(ctx, path)
+		if rerr := removeIndexFiles(path); rerr != nil {
 			return false, fmt.Errorf("remove index after open failure: %w", rerr)
 		}
-		if rbErr := RebuildIndex(ctx, projectRoot, proj); rbErr != nil {
-			return false, fmt.Errorf("rebuild after open failure: %w (open err: %v)", rbErr, err)
+		if rbErr := ix.rebuildWithSnapshot(ctx, proj, bak); rbErr != nil {
+			return false, fmt.Errorf("%w: rebuild after open failure: %w (open err: %v)", ErrIndexCorrupt, rbErr, err)
 		}
 		return true, nil
 	}
-	// do not defer close; we may need to close early before deleting file
 	needs := false
 	// quick_check for corruption
 	var chk string
@@ -326,50 +471,73 @@ func DetectAndRebuildIndex(ctx context.Context, projectRoot string, proj domain.
 			needs = true
 		}
 	}
+	ix.release()
 	if !needs {
-		_ = db.Close()
 		return false, nil
 	}
-	// Backup and remove existing DB file
-	_ = db.Close() // ensure file handles are released before removal on Windows
-	if berr := backupIndexFile(path); berr != nil {
-		// best effort backup; continue
-	}
-	// Best-effort remove index and sidecar files, but report unexpected errors
-	if rerr := os.Remove(path); rerr != nil && !os.IsNotExist(rerr) {
-		return false, fmt.Errorf("remove index: %w", rerr)
+	// Snapshot and remove existing DB file
+	ix.discard() // ensure file handles are released before removal on Windows
+	bak := ix.snapshotBeforeRebuild(ctx, path)
+	if rerr := removeIndexFiles(path); rerr != nil {
+		return false, rerr
 	}
-	if rerr := os.Remove(path + "-wal"); rerr != nil && !os.IsNotExist(rerr) {
-		return false, fmt.Errorf("remove index wal: %w", rerr)
-	}
-	if rerr := os.Remove(path + "-shm"); rerr != nil && !os.IsNotExist(rerr) {
-		return false, fmt.Errorf("remove index shm: %w", rerr)
-	}
-	// Rebuild
-	if err := RebuildIndex(ctx, projectRoot, proj); err != nil {
-		return false, err
+	// Rebuild; a verified snapshot is restored if this fails
+	if err := ix.rebuildWithSnapshot(ctx, proj, bak); err != nil {
+		return false, fmt.Errorf("%w: rebuild: %w", ErrIndexCorrupt, err)
 	}
 	return true, nil
 }
 
-// backupIndexFile copies the current index file into a timestamped backup in .gcw/backups.
-// Returns nil if the source index does not exist. Best-effort, but errors are reported.
-func backupIndexFile(indexPath string) error {
-	bdir := filepath.Join(filepath.Dir(indexPath), "backups")
-	if err := os.MkdirAll(bdir, 0o755); err != nil {
-		return fmt.Errorf("mkdir backups: %w", err)
-	}
-	stamp := time.Now().Format("20060102-150405")
-	bak := filepath.Join(bdir, fmt.Sprintf("%s.%s.bak", filepath.Base(indexPath), stamp))
-	data, err := os.ReadFile(indexPath)
+// ResetIndex replaces the project's index with one of this app's schema built from proj, e.g. after
+// ErrIndexNewerThanApp. The old file is snapshotted to .gcw/backups first.
+func ResetIndex(ctx context.Context, projectRoot string, proj domain.Project) error {
+	return runIndex(projectRoot, func(ix *IndexHandle) error { return ix.ResetIndex(ctx, proj) })
+}
+
+// ResetIndex replaces the index file with a fresh one built from proj; see the package-level ResetIndex.
+// Everything stored only in the index, such as sync ops not yet sent to the server, is dropped with it.
+func (ix *IndexHandle) ResetIndex(ctx context.Context, proj domain.Project) error {
+	path := IndexPath(ix.root)
+	ix.discard() // release file handles before removal on Windows
+	bak, err := snapshotIndexFile(ctx, path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil // nothing to back up
+		// an index this app cannot read may still be a valid file for the version that wrote it
+		if perr := preserveCorruptIndex(path); perr != nil {
+			return fmt.Errorf("back up index: %w", perr)
 		}
-		return fmt.Errorf("read index for backup: %w", err)
 	}
-	if err := os.WriteFile(bak, data, 0o644); err != nil {
-		return fmt.Errorf("write backup: %w", err)
+	if err := removeIndexFiles(path); err != nil {
+		return err
+	}
+	return ix.rebuildWithSnapshot(ctx, proj, bak)
+}
+
+// snapshotBeforeRebuild takes a verified snapshot of the index before DetectAndRebuildIndex removes it
+// and returns its path. A file too damaged to snapshot is copied as is instead and "" is returned, so
+// there is nothing to restore. Both are best effort: the rebuild goes ahead either way.
+func (ix *IndexHandle) snapshotBeforeRebuild(ctx context.Context, path string) string {
+	l := applog.WithComponent("storage")
+	bak, err := snapshotIndexFile(ctx, path)
+	if err == nil {
+		return bak
+	}
+	l.Warn("index snapshot failed; keeping a raw copy", slog.Any("err", err))
+	if perr := preserveCorruptIndex(path); perr != nil {
+		l.Warn("copy damaged index failed", slog.Any("err", perr))
+	}
+	return ""
+}
+
+// removeIndexFiles removes the index database and its WAL sidecar files; missing files are fine.
+func removeIndexFiles(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove index: %w", err)
+	}
+	if err := os.Remove(path + "-wal"); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove index wal: %w", err)
+	}
+	if err := os.Remove(path + "-shm"); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove index shm: %w", err)
 	}
 	return nil
 }
@@ -403,16 +571,17 @@ func stringsTrim(s string) string {
 // BuildIndexIfEmpty performs a minimal background index build if the index has no user content.
 // It ensures the DB exists and, if the documents table is empty, populates it from the given manifest and script text.
 func BuildIndexIfEmpty(ctx context.Context, projectRoot string, proj domain.Project) error {
+	return runIndex(projectRoot, func(ix *IndexHandle) error { return ix.BuildIndexIfEmpty(ctx, proj) })
+}
+
+// BuildIndexIfEmpty populates the documents table from proj when it is empty.
+func (ix *IndexHandle) BuildIndexIfEmpty(ctx context.Context, proj domain.Project) error {
 	// Ensure the DB exists and is initialized
-	db, err := InitOrOpenIndex(projectRoot)
+	db, err := ix.acquire()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if cerr := db.Close(); cerr != nil {
-			applog.WithComponent("storage").Warn("db close failed", slog.Any("err", cerr))
-		}
-	}()
+	defer ix.release()
 	// Check if documents has any rows
 	var cnt int
 	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM documents;").Scan(&cnt); err != nil {
@@ -421,36 +590,50 @@ func BuildIndexIfEmpty(ctx context.Context, projectRoot string, proj domain.Proj
 	if cnt > 0 {
 		return nil // already built
 	}
-	return rebuildDocumentsFromProject(ctx, db, projectRoot, proj)
+	return rebuildDocumentsFromProject(ctx, db, ix.root, proj)
 }
 
 // UpdateIndex updates the embedded index with changes from the project manifest.
 // Minimal safe implementation: replace the documents content from the provided manifest.
 func UpdateIndex(ctx context.Context, projectRoot string, proj domain.Project) error {
-	db, err := InitOrOpenIndex(projectRoot)
+	return runIndex(projectRoot, func(ix *IndexHandle) error { return ix.UpdateIndex(ctx, proj) })
+}
+
+// UpdateIndex replaces the documents content from the provided manifest.
+func (ix *IndexHandle) UpdateIndex(ctx context.Context, proj domain.Project) error {
+	db, err := ix.acquire()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if cerr := db.Close(); cerr != nil {
-			applog.WithComponent("storage").Warn("db close failed", slog.Any("err", cerr))
-		}
-	}()
-	return rebuildDocumentsFromProject(ctx, db, projectRoot, proj)
+	defer ix.release()
+	return rebuildDocumentsFromProject(ctx, db, ix.root, proj)
 }
 
 // RebuildIndex drops and recreates core index tables and rebuilds content from the manifest.
 // It preserves meta/version tables. This is a safe operation; the index is derived from comic.json and assets.
 func RebuildIndex(ctx context.Context, projectRoot string, proj domain.Project) error {
-	db, err := InitOrOpenIndex(projectRoot)
+	return runIndex(projectRoot, func(ix *IndexHandle) error { return ix.RebuildIndex(ctx, proj) })
+}
+
+// RebuildIndex drops and recreates core index tables and rebuilds content from the manifest.
+// Searches running on the same handle in the meantime see either the old or the rebuilt tables.
+// A verified snapshot of the index is written to .gcw/backups first; the rebuild does not start
+// without one, and when it fails part way the snapshot is restored so search keeps working.
+func (ix *IndexHandle) RebuildIndex(ctx context.Context, proj domain.Project) error {
+	bak, err := snapshotIndexFile(ctx, IndexPath(ix.root))
+	if err != nil {
+		return fmt.Errorf("snapshot index before rebuild: %w", err)
+	}
+	return ix.rebuildWithSnapshot(ctx, proj, bak)
+}
+
+// rebuildIndex is RebuildIndex without the snapshot.
+func (ix *IndexHandle) rebuildIndex(ctx context.Context, proj domain.Project) error {
+	db, err := ix.acquire()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if cerr := db.Close(); cerr != nil {
-			applog.WithComponent("storage").Warn("db close failed", slog.Any("err", cerr))
-		}
-	}()
+	defer ix.release()
 	// Drop core tables inside a transaction and recreate schema
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
@@ -458,6 +641,7 @@ func RebuildIndex(ctx context.Context, projectRoot string, proj domain.Project)
 	}
 	drops := []string{
 		"DROP TABLE IF EXISTS cross_refs;",
+		"DROP TABLE IF EXISTS doc_fingerprints;",
 		"DROP TABLE IF EXISTS assets;",
 		"DROP TABLE IF EXISTS previews;",
 		"DROP TABLE IF EXISTS snapshots;",
@@ -480,75 +664,91 @@ func RebuildIndex(ctx context.Context, projectRoot string, proj domain.Project)
 	if err := ensureIndexSchema(ctx, db); err != nil {
 		return err
 	}
-	return rebuildDocumentsFromProject(ctx, db, projectRoot, proj)
+	return rebuildDocumentsFromProject(ctx, db, ix.root, proj)
 }
 
-// rebuildDocumentsFromProject replaces the documents table content from the given project manifest and script text.
-func rebuildDocumentsFromProject(ctx context.Context, db *sql.DB, projectRoot string, proj domain.Project) error {
-	// Build list of rows
-	type row struct {
-		typeStr     string
-		path        string
-		pageID      sql.NullInt64
-		characterID sql.NullString
-		text        string
-	}
-	rows := make([]row, 0, 256)
+// IndexDocument is one searchable text of a project as stored in the index: its type, its path
+// (e.g. "issue:1/page:3/panel:p2/balloon:b1"), the page number (0 for none), the speaking character
+// of a balloon and the text itself.
+type IndexDocument struct {
+	Type      string
+	Path      string
+	PageID    int
+	Character string
+	Text      string
+}
+
+// ManifestDocuments returns the index documents derived from the manifest alone: project metadata,
+// bible entries, the page notes and the panel notes, balloons, captions and SFX of all pages. The local index adds the
+// script and the asset folder; the server builds its search documents from this list only.
+func ManifestDocuments(proj domain.Project) []IndexDocument {
+	docs := make([]IndexDocument, 0, 256)
 	// Project-level metadata
 	if s := stringsTrim(proj.Name); s != "" {
-		rows = append(rows, row{typeStr: "project_name", path: "project:name", text: s})
+		docs = append(docs, IndexDocument{Type: "project_name", Path: "project:name", Text: s})
 	}
 	if s := stringsTrim(proj.Metadata.Series); s != "" {
-		rows = append(rows, row{typeStr: "project_series", path: "project:series", text: s})
+		docs = append(docs, IndexDocument{Type: "project_series", Path: "project:series", Text: s})
 	}
 	if s := stringsTrim(proj.Metadata.IssueTitle); s != "" {
-		rows = append(rows, row{typeStr: "issue_title", path: "project:issue_title", text: s})
+		docs = append(docs, IndexDocument{Type: "issue_title", Path: "project:issue_title", Text: s})
 	}
 	if s := stringsTrim(proj.Metadata.Creators); s != "" {
-		rows = append(rows, row{typeStr: "creators", path: "project:creators", text: s})
+		docs = append(docs, IndexDocument{Type: "creators", Path: "project:creators", Text: s})
 	}
 	if s := stringsTrim(proj.Metadata.Notes); s != "" {
-		rows = append(rows, row{typeStr: "project_notes", path: "project:notes", text: s})
+		docs = append(docs, IndexDocument{Type: "project_notes", Path: "project:notes", Text: s})
+	}
+	if s := stringsTrim(proj.Metadata.Genre); s != "" {
+		docs = append(docs, IndexDocument{Type: "project_genre", Path: "project:genre", Text: s})
+	}
+	if s := stringsTrim(proj.Metadata.AgeRating); s != "" {
+		docs = append(docs, IndexDocument{Type: "project_age_rating", Path: "project:age_rating", Text: s})
+	}
+	if s := stringsTrim(proj.Metadata.Web); s != "" {
+		docs = append(docs, IndexDocument{Type: "project_web", Path: "project:web", Text: s})
 	}
 	// Bible entries
 	for _, bc := range proj.Bible.Characters {
 		if s := stringsTrim(bc.Name); s != "" {
-			rows = append(rows, row{typeStr: "character", path: "bible:character:" + s, text: s})
+			docs = append(docs, IndexDocument{Type: "character", Path: "bible:character:" + s, Text: s})
 		}
 		if s := stringsTrim(strings.Join(bc.Aliases, ", ")); s != "" {
-			rows = append(rows, row{typeStr: "character_aliases", path: "bible:character_aliases:" + bc.Name, text: s})
+			docs = append(docs, IndexDocument{Type: "character_aliases", Path: "bible:character_aliases:" + bc.Name, Text: s})
 		}
 		if s := stringsTrim(bc.Notes); s != "" {
-			rows = append(rows, row{typeStr: "character_notes", path: "bible:character_notes:" + bc.Name, text: s})
+			docs = append(docs, IndexDocument{Type: "character_notes", Path: "bible:character_notes:" + bc.Name, Text: s})
 		}
 	}
 	for _, bl := range proj.Bible.Locations {
 		if s := stringsTrim(bl.Name); s != "" {
-			rows = append(rows, row{typeStr: "location", path: "bible:location:" + s, text: s})
+			docs = append(docs, IndexDocument{Type: "location", Path: "bible:location:" + s, Text: s})
 		}
 		if s := stringsTrim(strings.Join(bl.Aliases, ", ")); s != "" {
-			rows = append(rows, row{typeStr: "location_aliases", path: "bible:location_aliases:" + bl.Name, text: s})
+			docs = append(docs, IndexDocument{Type: "location_aliases", Path: "bible:location_aliases:" + bl.Name, Text: s})
 		}
 		if s := stringsTrim(bl.Notes); s != "" {
-			rows = append(rows, row{typeStr: "location_notes", path: "bible:location_notes:" + bl.Name, text: s})
+			docs = append(docs, IndexDocument{Type: "location_notes", Path: "bible:location_notes:" + bl.Name, Text: s})
 		}
 	}
 	for _, bt := range proj.Bible.Tags {
 		if s := stringsTrim(bt.Name); s != "" {
-			rows = append(rows, row{typeStr: "tag", path: "bible:tag:" + s, text: s})
+			docs = append(docs, IndexDocument{Type: "tag", Path: "bible:tag:" + s, Text: s})
 		}
 		if s := stringsTrim(bt.Notes); s != "" {
-			rows = append(rows, row{typeStr: "tag_notes", path: "bible:tag_notes:" + bt.Name, text: s})
+			docs = append(docs, IndexDocument{Type: "tag_notes", Path: "bible:tag_notes:" + bt.Name, Text: s})
 		}
 	}
 	// Issues/pages/panels/balloons
 	for _, iss := range proj.Issues {
 		for _, pg := range iss.Pages {
-			pageID := int64(pg.Number)
-			// Panel notes and balloon texts
+			if s := stringsTrim(pg.Notes); s != "" {
+				docs = append(docs, IndexDocument{Type: "page_notes", Path: fmt.Sprintf("issue:1/page:%d", pg.Number), PageID: pg.Number, Text: s})
+			}
+			// Panel notes, balloon, caption and SFX texts
 			for _, pnl := range pg.Panels {
 				if s := stringsTrim(pnl.Notes); s != "" {
-					rows = append(rows, row{typeStr: "panel_notes", path: fmt.Sprintf("issue:1/page:%d/panel:%s", pg.Number, pnl.ID), pageID: sql.NullInt64{Int64: pageID, Valid: true}, text: s})
+					docs = append(docs, IndexDocument{Type: "panel_notes", Path: fmt.Sprintf("issue:1/page:%d/panel:%s", pg.Number, pnl.ID), PageID: pg.Number, Text: s})
 				}
 				for _, bln := range pnl.Balloons {
 					// Aggregate text runs
@@ -564,28 +764,143 @@ func rebuildDocumentsFromProject(ctx context.Context, db *sql.DB, projectRoot st
 						buf = append(buf, ct...)
 					}
 					if len(buf) > 0 {
-						rows = append(rows, row{typeStr: "balloon", path: fmt.Sprintf("issue:1/page:%d/panel:%s/balloon:%s", pg.Number, pnl.ID, bln.ID), pageID: sql.NullInt64{Int64: pageID, Valid: true}, text: string(buf)})
+						d := IndexDocument{Type: "balloon", Path: fmt.Sprintf("issue:1/page:%d/panel:%s/balloon:%s", pg.Number, pnl.ID, bln.ID), PageID: pg.Number, Text: string(buf)}
+						if ch := stringsTrim(bln.Character); ch != "" {
+							d.Character = ch
+						}
+						docs = append(docs, d)
+					}
+				}
+				for _, c := range pnl.Captions {
+					if s := stringsTrim(c.Text); s != "" {
+						docs = append(docs, IndexDocument{Type: "caption", Path: fmt.Sprintf("issue:1/page:%d/panel:%s/caption:%s", pg.Number, pnl.ID, c.ID), PageID: pg.Number, Text: s})
+					}
+				}
+				for _, fx := range pnl.SFX {
+					if s := stringsTrim(fx.Text); s != "" {
+						docs = append(docs, IndexDocument{Type: "sfx", Path: fmt.Sprintf("issue:1/page:%d/panel:%s/sfx:%s", pg.Number, pnl.ID, fx.ID), PageID: pg.Number, Text: s})
 					}
 				}
 			}
 		}
 	}
-	// Script text (if present)
+	return docs
+}
+
+// indexRow is one documents row as written by rebuildDocumentsFromProject.
+type indexRow struct {
+	typeStr     string
+	path        string
+	pageID      sql.NullInt64
+	characterID sql.NullString
+	text        string
+	location    string // bible location the document is linked to through its scene
+}
+
+// refPaths returns the paths of the documents r refers to: the bible entries and assets its text
+// mentions, its speaking character and the location of its scene.
+func (r indexRow) refPaths(targets []refTarget) []string {
+	if !crossRefSourceTypes[r.typeStr] {
+		return nil
+	}
+	paths := findRefTargets(r.text, targets)
+	if r.characterID.Valid {
+		paths = append(paths, "bible:character:"+r.characterID.String)
+	}
+	if r.location != "" {
+		paths = append(paths, "bible:location:"+r.location)
+	}
+	return paths
+}
+
+// projectIndexRows derives the documents rows of the project from the manifest, the script text and
+// the assets folder, along with the cross reference targets and the asset catalogue.
+func projectIndexRows(projectRoot string, proj domain.Project) ([]indexRow, []refTarget, []AssetEntry, error) {
+	rows := manifestIndexRows(projectRoot, proj)
+	targets := bibleRefTargets(proj.Bible)
+	// Assets folder: catalogue files and index them so placed assets ("asset:<path>" in panel notes) resolve in where-used
+	assets, err := ScanAssets(projectRoot)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, a := range assets {
+		dp := assetDocPath(a.Path)
+		rows = append(rows, indexRow{typeStr: "asset", path: dp, text: a.Path})
+		// Markers written on Windows before paths were normalized may use backslashes
+		terms := []string{strings.ToLower(dp), strings.ToLower(strings.ReplaceAll(dp, "/", `\`))}
+		targets = append(targets, refTarget{path: dp, terms: terms, lineTerm: true})
+	}
+	return rows, targets, assets, nil
+}
+
+// manifestIndexRows derives the documents rows of the manifest and the script text, with the bible
+// locations panels are linked to through their scenes.
+func manifestIndexRows(projectRoot string, proj domain.Project) []indexRow {
+	docs := ManifestDocuments(proj)
+	rows := make([]indexRow, 0, len(docs)+16)
+	for _, d := range docs {
+		r := indexRow{typeStr: d.Type, path: d.Path, text: d.Text}
+		if d.PageID != 0 {
+			r.pageID = sql.NullInt64{Int64: int64(d.PageID), Valid: true}
+		}
+		if d.Character != "" {
+			r.characterID = sql.NullString{String: d.Character, Valid: true}
+		}
+		rows = append(rows, r)
+	}
+	// Script text (if present), and its scenes with the locations they are linked to
+	var sceneLocs map[string]string
 	scriptPath := filepath.Join(projectRoot, "script", "script.txt")
 	if b, err := os.ReadFile(scriptPath); err == nil {
 		if s := stringsTrim(string(b)); s != "" {
-			rows = append(rows, row{typeStr: "script", path: "script:script.txt", text: s})
+			rows = append(rows, indexRow{typeStr: "script", path: "script:script.txt", text: s})
+			var scenes []IndexDocument
+			scenes, sceneLocs = sceneLocationLinks(proj, string(b))
+			for _, d := range scenes {
+				rows = append(rows, indexRow{typeStr: d.Type, path: d.Path, text: d.Text})
+			}
 		}
 	}
+	for i := range rows {
+		rows[i].location = sceneLocs[panelDocPath(rows[i].path)]
+	}
+	return rows
+}
+
+// rebuildDocumentsFromProject replaces the documents table content from the given project manifest and script text.
+func rebuildDocumentsFromProject(ctx context.Context, db *sql.DB, projectRoot string, proj domain.Project) error {
+	rows, targets, assets, err := projectIndexRows(projectRoot, proj)
+	if err != nil {
+		return err
+	}
 	// Write in a transaction: clear documents and insert new rows.
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
 	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM cross_refs;"); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("clear cross_refs: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM doc_fingerprints;"); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("clear doc_fingerprints: %w", err)
+	}
 	if _, err := tx.ExecContext(ctx, "DELETE FROM documents;"); err != nil {
 		_ = tx.Rollback()
 		return fmt.Errorf("clear documents: %w", err)
 	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM assets;"); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("clear assets: %w", err)
+	}
+	for _, a := range assets {
+		// identical content under two names keeps the first path
+		if _, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO assets(hash, path, type) VALUES(?,?,?);", a.Hash, a.Path, a.Type); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("insert asset: %w", err)
+		}
+	}
 	ins, err := tx.PrepareContext(ctx, "INSERT INTO documents(type, path, page_id, character_id, text) VALUES(?,?,?,?,?);")
 	if err != nil {
 		_ = tx.Rollback()
@@ -596,11 +911,61 @@ func rebuildDocumentsFromProject(ctx context.Context, db *sql.DB, projectRoot st
 			applog.WithComponent("storage").Warn("stmt close failed", slog.Any("err", cerr))
 		}
 	}()
-	for _, r := range rows {
-		if _, err := ins.ExecContext(ctx, r.typeStr, r.path, r.pageID, r.characterID, r.text); err != nil {
+	fpIns, err := tx.PrepareContext(ctx, "INSERT INTO doc_fingerprints(doc_id, path, hash) VALUES(?,?,?);")
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("prepare fingerprint insert: %w", err)
+	}
+	defer func() {
+		if cerr := fpIns.Close(); cerr != nil {
+			applog.WithComponent("storage").Warn("stmt close failed", slog.Any("err", cerr))
+		}
+	}()
+	ids := make([]int64, len(rows))
+	byPath := make(map[string]int64, len(rows))
+	for i, r := range rows {
+		res, err := ins.ExecContext(ctx, r.typeStr, r.path, r.pageID, r.characterID, r.text)
+		if err != nil {
 			_ = tx.Rollback()
 			return fmt.Errorf("insert document: %w", err)
 		}
+		if ids[i], err = res.LastInsertId(); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("document id: %w", err)
+		}
+		if _, err := fpIns.ExecContext(ctx, ids[i], r.path, r.fingerprint()); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("insert fingerprint: %w", err)
+		}
+		if _, dup := byPath[r.path]; !dup {
+			byPath[r.path] = ids[i]
+		}
+	}
+	// Cross references from text documents to the bible entries they mention (@tags, names, aliases)
+	// and to the locations of their scenes
+	if len(targets) > 0 || slices.ContainsFunc(rows, func(r indexRow) bool { return r.location != "" }) {
+		refIns, err := tx.PrepareContext(ctx, "INSERT OR IGNORE INTO cross_refs(from_id, to_id) VALUES(?,?);")
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("prepare cross_refs insert: %w", err)
+		}
+		defer func() {
+			if cerr := refIns.Close(); cerr != nil {
+				applog.WithComponent("storage").Warn("stmt close failed", slog.Any("err", cerr))
+			}
+		}()
+		for i, r := range rows {
+			for _, p := range r.refPaths(targets) {
+				to, ok := byPath[p]
+				if !ok {
+					continue
+				}
+				if _, err := refIns.ExecContext(ctx, ids[i], to); err != nil {
+					_ = tx.Rollback()
+					return fmt.Errorf("insert cross_ref: %w", err)
+				}
+			}
+		}
 	}
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("commit: %w", err)
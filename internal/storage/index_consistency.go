@@ -0,0 +1,358 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// createDocFingerprintsSQL holds a content hash per documents row, so the consistency check compares
+// hashes instead of reading back all indexed text.
+const createDocFingerprintsSQL = `CREATE TABLE IF NOT EXISTS doc_fingerprints (
+	doc_id INTEGER PRIMARY KEY REFERENCES documents(doc_id) ON DELETE CASCADE,
+	path   TEXT    NOT NULL,
+	hash   TEXT    NOT NULL
+);`
+
+// ensureDocFingerprints creates the fingerprints table and its index. It is safe to run more than once.
+func ensureDocFingerprints(ctx context.Context, q metaQuerier) error {
+	if _, err := q.ExecContext(ctx, createDocFingerprintsSQL); err != nil {
+		return fmt.Errorf("create doc_fingerprints: %w", err)
+	}
+	if _, err := q.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_doc_fingerprints_path ON doc_fingerprints(path);`); err != nil {
+		return fmt.Errorf("create doc_fingerprints index: %w", err)
+	}
+	return nil
+}
+
+// fingerprint is the content hash of r over everything written to its documents row and the scene
+// location it links to.
+func (r indexRow) fingerprint() string {
+	h := sha256.New()
+	for _, f := range []string{r.typeStr, r.path, fmt.Sprint(r.pageID.Int64), r.characterID.String, r.text, r.location} {
+		h.Write([]byte(f))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// IndexDrift reports where the index differs from the project, by document path: paths whose rows the
+// index lacks, paths the project no longer has and paths whose rows differ.
+type IndexDrift struct {
+	Added   []string
+	Removed []string
+	Changed []string
+	// MissingFingerprints is set when documents rows have no stored fingerprint, as in an index built
+	// before fingerprints were kept. Such an index cannot be compared row by row.
+	MissingFingerprints bool
+	// Repaired is set when the differences were written to the index, Rebuilt when that took
+	// rebuilding all documents rather than only the differing ones.
+	Repaired bool
+	Rebuilt  bool
+}
+
+// Drifted reports whether the index differs from the project.
+func (d IndexDrift) Drifted() bool {
+	return d.MissingFingerprints || len(d.Added)+len(d.Removed)+len(d.Changed) > 0
+}
+
+// Count is the number of document paths that differ.
+func (d IndexDrift) Count() int { return len(d.Added) + len(d.Removed) + len(d.Changed) }
+
+// needsRebuild reports whether the differences can only be repaired by rebuilding all documents:
+// without fingerprints, or when bible entries or assets changed, as those are the targets of the
+// cross references of every other document.
+func (d IndexDrift) needsRebuild() bool {
+	if d.MissingFingerprints {
+		return true
+	}
+	for _, ps := range [][]string{d.Added, d.Removed, d.Changed} {
+		if slices.ContainsFunc(ps, isRefTargetPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRefTargetPath reports whether documents at path can be the target of cross references.
+func isRefTargetPath(path string) bool {
+	return strings.HasPrefix(path, "bible:") || strings.HasPrefix(path, "asset:")
+}
+
+// rowQuerier is the part of *sql.DB and *sql.Tx that reads rows.
+type rowQuerier interface {
+	metaQuerier
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// storedFingerprint is a fingerprint as read from the index.
+type storedFingerprint struct {
+	docID int64
+	hash  string
+}
+
+// VerifyIndexConsistency compares the index of the project at projectRoot with the documents proj
+// would produce and reports the drift. The index is not changed.
+func VerifyIndexConsistency(ctx context.Context, projectRoot string, proj domain.Project) (IndexDrift, error) {
+	var d IndexDrift
+	err := runIndex(projectRoot, func(ix *IndexHandle) error {
+		var err error
+		d, err = ix.VerifyIndexConsistency(ctx, proj)
+		return err
+	})
+	return d, err
+}
+
+// VerifyIndexConsistency compares the index with the documents proj would produce and reports the drift.
+// Only the stored fingerprints are read, not the indexed text.
+func (ix *IndexHandle) VerifyIndexConsistency(ctx context.Context, proj domain.Project) (IndexDrift, error) {
+	db, err := ix.acquire()
+	if err != nil {
+		return IndexDrift{}, err
+	}
+	defer ix.release()
+	rows, _, _, err := projectIndexRows(ix.root, proj)
+	if err != nil {
+		return IndexDrift{}, err
+	}
+	stored, missing, err := readFingerprints(ctx, db)
+	if err != nil {
+		return IndexDrift{}, err
+	}
+	return diffFingerprints(stored, missing, groupRows(rows)), nil
+}
+
+// RepairIndexConsistency compares the index of the project at projectRoot with the documents proj
+// would produce and writes the differing rows.
+func RepairIndexConsistency(ctx context.Context, projectRoot string, proj domain.Project) (IndexDrift, error) {
+	var d IndexDrift
+	err := runIndex(projectRoot, func(ix *IndexHandle) error {
+		var err error
+		d, err = ix.RepairIndexConsistency(ctx, proj)
+		return err
+	})
+	return d, err
+}
+
+// RepairIndexConsistency compares the index with the documents proj would produce and writes only the
+// rows that differ, with their cross references. When bible entries or assets differ, or the index
+// has no fingerprints yet, all documents are rebuilt instead. The returned drift is what was found.
+func (ix *IndexHandle) RepairIndexConsistency(ctx context.Context, proj domain.Project) (IndexDrift, error) {
+	db, err := ix.acquire()
+	if err != nil {
+		return IndexDrift{}, err
+	}
+	defer ix.release()
+	rows, targets, _, err := projectIndexRows(ix.root, proj)
+	if err != nil {
+		return IndexDrift{}, err
+	}
+	want := groupRows(rows)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return IndexDrift{}, fmt.Errorf("begin tx: %w", err)
+	}
+	stored, missing, err := readFingerprints(ctx, tx)
+	if err != nil {
+		_ = tx.Rollback()
+		return IndexDrift{}, err
+	}
+	d := diffFingerprints(stored, missing, want)
+	if !d.Drifted() {
+		_ = tx.Rollback()
+		return d, nil
+	}
+	if d.needsRebuild() {
+		_ = tx.Rollback()
+		if err := rebuildDocumentsFromProject(ctx, db, ix.root, proj); err != nil {
+			return d, err
+		}
+		d.Repaired, d.Rebuilt = true, true
+		return d, nil
+	}
+	if err := applyDrift(ctx, tx, d, stored, want, targets); err != nil {
+		_ = tx.Rollback()
+		return d, err
+	}
+	if err := tx.Commit(); err != nil {
+		return d, fmt.Errorf("commit: %w", err)
+	}
+	d.Repaired = true
+	return d, nil
+}
+
+// groupRows groups rows by path, keeping their order.
+func groupRows(rows []indexRow) map[string][]indexRow {
+	out := make(map[string][]indexRow, len(rows))
+	for _, r := range rows {
+		out[r.path] = append(out[r.path], r)
+	}
+	return out
+}
+
+// readFingerprints returns the stored fingerprints by path in documents order, and whether any
+// documents row has none.
+func readFingerprints(ctx context.Context, q rowQuerier) (map[string][]storedFingerprint, bool, error) {
+	var docs, fps int
+	if err := q.QueryRowContext(ctx, `SELECT (SELECT COUNT(*) FROM documents), (SELECT COUNT(*) FROM doc_fingerprints)`).Scan(&docs, &fps); err != nil {
+		return nil, false, fmt.Errorf("count fingerprints: %w", err)
+	}
+	rs, err := q.QueryContext(ctx, `SELECT doc_id, path, hash FROM doc_fingerprints ORDER BY doc_id`)
+	if err != nil {
+		return nil, false, fmt.Errorf("read fingerprints: %w", err)
+	}
+	defer func() { _ = rs.Close() }()
+	out := make(map[string][]storedFingerprint, fps)
+	for rs.Next() {
+		var f storedFingerprint
+		var path string
+		if err := rs.Scan(&f.docID, &path, &f.hash); err != nil {
+			return nil, false, fmt.Errorf("scan fingerprint: %w", err)
+		}
+		out[path] = append(out[path], f)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, false, fmt.Errorf("read fingerprints: %w", err)
+	}
+	return out, docs != fps, nil
+}
+
+// diffFingerprints compares the stored fingerprints with the wanted rows path by path.
+func diffFingerprints(stored map[string][]storedFingerprint, missing bool, want map[string][]indexRow) IndexDrift {
+	d := IndexDrift{MissingFingerprints: missing}
+	for path, rs := range want {
+		fs, ok := stored[path]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, path)
+		case !sameFingerprints(fs, rs):
+			d.Changed = append(d.Changed, path)
+		}
+	}
+	for path := range stored {
+		if _, ok := want[path]; !ok {
+			d.Removed = append(d.Removed, path)
+		}
+	}
+	slices.Sort(d.Added)
+	slices.Sort(d.Removed)
+	slices.Sort(d.Changed)
+	return d
+}
+
+func sameFingerprints(fs []storedFingerprint, rs []indexRow) bool {
+	if len(fs) != len(rs) {
+		return false
+	}
+	for i := range fs {
+		if fs[i].hash != rs[i].fingerprint() {
+			return false
+		}
+	}
+	return true
+}
+
+// applyDrift writes the rows of the drifted paths: stored rows are updated in place as far as the
+// path still has rows, surplus ones deleted and missing ones inserted. The cross references from the
+// written rows are recomputed; d must not involve reference targets (see needsRebuild).
+func applyDrift(ctx context.Context, tx *sql.Tx, d IndexDrift, stored map[string][]storedFingerprint, want map[string][]indexRow, targets []refTarget) error {
+	type written struct {
+		id  int64
+		row indexRow
+	}
+	var out []written
+	for _, path := range slices.Concat(d.Added, d.Removed, d.Changed) {
+		fs, rs := stored[path], want[path]
+		for i, f := range fs {
+			if i >= len(rs) {
+				if err := deleteDocument(ctx, tx, f.docID); err != nil {
+					return err
+				}
+				continue
+			}
+			r := rs[i]
+			if _, err := tx.ExecContext(ctx, `UPDATE documents SET type=?, page_id=?, character_id=?, text=? WHERE doc_id=?`, r.typeStr, r.pageID, r.characterID, r.text, f.docID); err != nil {
+				return fmt.Errorf("update document: %w", err)
+			}
+			if _, err := tx.ExecContext(ctx, `UPDATE doc_fingerprints SET hash=? WHERE doc_id=?`, r.fingerprint(), f.docID); err != nil {
+				return fmt.Errorf("update fingerprint: %w", err)
+			}
+			if _, err := tx.ExecContext(ctx, `DELETE FROM cross_refs WHERE from_id=?`, f.docID); err != nil {
+				return fmt.Errorf("clear cross_refs: %w", err)
+			}
+			out = append(out, written{f.docID, r})
+		}
+		for _, r := range rs[min(len(fs), len(rs)):] {
+			res, err := tx.ExecContext(ctx, `INSERT INTO documents(type, path, page_id, character_id, text) VALUES(?,?,?,?,?)`, r.typeStr, r.path, r.pageID, r.characterID, r.text)
+			if err != nil {
+				return fmt.Errorf("insert document: %w", err)
+			}
+			id, err := res.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("document id: %w", err)
+			}
+			if _, err := tx.ExecContext(ctx, `INSERT INTO doc_fingerprints(doc_id, path, hash) VALUES(?,?,?)`, id, r.path, r.fingerprint()); err != nil {
+				return fmt.Errorf("insert fingerprint: %w", err)
+			}
+			out = append(out, written{id, r})
+		}
+	}
+	// Reference targets are unchanged, so their ids can be looked up once
+	byPath := map[string]int64{}
+	rs, err := tx.QueryContext(ctx, `SELECT path, MIN(doc_id) FROM documents WHERE path LIKE 'bible:%' OR path LIKE 'asset:%' GROUP BY path`)
+	if err != nil {
+		return fmt.Errorf("read reference targets: %w", err)
+	}
+	for rs.Next() {
+		var path string
+		var id int64
+		if err := rs.Scan(&path, &id); err != nil {
+			_ = rs.Close()
+			return fmt.Errorf("scan reference target: %w", err)
+		}
+		byPath[path] = id
+	}
+	if err := rs.Close(); err != nil {
+		return fmt.Errorf("read reference targets: %w", err)
+	}
+	for _, w := range out {
+		for _, p := range w.row.refPaths(targets) {
+			to, ok := byPath[p]
+			if !ok {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO cross_refs(from_id, to_id) VALUES(?,?)`, w.id, to); err != nil {
+				return fmt.Errorf("insert cross_ref: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// deleteDocument removes a documents row with its fingerprint and cross references.
+func deleteDocument(ctx context.Context, tx *sql.Tx, id int64) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM cross_refs WHERE from_id=? OR to_id=?`, id, id); err != nil {
+		return fmt.Errorf("delete cross_refs: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM doc_fingerprints WHERE doc_id=?`, id); err != nil {
+		return fmt.Errorf("delete fingerprint: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM documents WHERE doc_id=?`, id); err != nil {
+		return fmt.Errorf("delete document: %w", err)
+	}
+	return nil
+}
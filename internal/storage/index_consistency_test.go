@@ -0,0 +1,209 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func consistencyTestProject() domain.Project {
+	proj := snapshotTestProject()
+	proj.Bible.Characters = []domain.BibleCharacter{{Name: "Mara"}}
+	pg := &proj.Issues[0].Pages[0]
+	pg.Panels = append(pg.Panels, domain.Panel{ID: "P2", Notes: "the keeper climbs the stairs",
+		Balloons: []domain.Balloon{{ID: "B2", Type: "speech", TextRuns: []domain.TextRun{{Content: "who goes there"}}}}})
+	return proj
+}
+
+// editManifestBehindIndex writes the manifest of proj with edit applied to root without updating the
+// index, as an outside editor would, and returns the project read back from it.
+func editManifestBehindIndex(t *testing.T, root string, proj domain.Project, edit func(*domain.Project)) domain.Project {
+	t.Helper()
+	b, err := json.Marshal(proj)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var edited domain.Project
+	if err := json.Unmarshal(b, &edited); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	edit(&edited)
+	if b, err = json.MarshalIndent(edited, "", "  "); err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	mpath := filepath.Join(root, ManifestFileName)
+	if err := os.WriteFile(mpath, b, 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	if b, err = os.ReadFile(mpath); err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	var out domain.Project
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("parse manifest: %v", err)
+	}
+	return out
+}
+
+func assertSearchMisses(t *testing.T, ctx context.Context, root, text string) {
+	t.Helper()
+	res, err := Search(ctx, root, SearchQuery{Text: text})
+	if err != nil {
+		t.Fatalf("Search(%q): %v", text, err)
+	}
+	if len(res) != 0 {
+		t.Fatalf("Search(%q) = %+v, want no stale match", text, res)
+	}
+}
+
+func TestVerifyIndexConsistency_NoDriftAfterBuild(t *testing.T) {
+	proj := consistencyTestProject()
+	root, ctx := buildSnapshotTestIndex(t, proj)
+	d, err := VerifyIndexConsistency(ctx, root, proj)
+	if err != nil {
+		t.Fatalf("VerifyIndexConsistency: %v", err)
+	}
+	if d.Drifted() {
+		t.Fatalf("fresh index drifted: %+v", d)
+	}
+}
+
+func TestRepairIndexConsistency_AppliesOnlyDifferingRows(t *testing.T) {
+	proj := consistencyTestProject()
+	root, ctx := buildSnapshotTestIndex(t, proj)
+	edited := editManifestBehindIndex(t, root, proj, func(p *domain.Project) {
+		pg := &p.Issues[0].Pages[0]
+		pg.Panels[0].Balloons[0].TextRuns[0].Content = "Mara lets the lamp go out"
+		pg.Panels[1].Balloons = nil
+		pg.Panels = append(pg.Panels, domain.Panel{ID: "P3", Notes: "gulls over the breakwater"})
+	})
+
+	d, err := VerifyIndexConsistency(ctx, root, edited)
+	if err != nil {
+		t.Fatalf("VerifyIndexConsistency: %v", err)
+	}
+	wantAdded := []string{"issue:1/page:1/panel:P3"}
+	wantRemoved := []string{"issue:1/page:1/panel:P2/balloon:B2"}
+	wantChanged := []string{"issue:1/page:1/panel:P1/balloon:B1"}
+	if !slices.Equal(d.Added, wantAdded) || !slices.Equal(d.Removed, wantRemoved) || !slices.Equal(d.Changed, wantChanged) {
+		t.Fatalf("drift = %+v, want added %v removed %v changed %v", d, wantAdded, wantRemoved, wantChanged)
+	}
+	if d.Repaired || d.MissingFingerprints {
+		t.Fatalf("verify only: %+v", d)
+	}
+	// Verifying does not touch the index
+	assertSearchFinds(t, ctx, root, "burning")
+
+	// Unchanged rows keep their ids when only the differing rows are written
+	keep := docIDByPath(t, ctx, root, "issue:1/page:1/panel:P2")
+	d, err = RepairIndexConsistency(ctx, root, edited)
+	if err != nil {
+		t.Fatalf("RepairIndexConsistency: %v", err)
+	}
+	if !d.Repaired || d.Rebuilt || d.Count() != 3 {
+		t.Fatalf("repair = %+v, want 3 paths repaired in place", d)
+	}
+	if got := docIDByPath(t, ctx, root, "issue:1/page:1/panel:P2"); got != keep {
+		t.Fatalf("unchanged row was rewritten: id %d, was %d", got, keep)
+	}
+	assertSearchFinds(t, ctx, root, "breakwater")
+	assertSearchFinds(t, ctx, root, "lets")
+	assertSearchMisses(t, ctx, root, "burning")
+	assertSearchMisses(t, ctx, root, "who")
+	// The repaired balloon refers to the character it mentions
+	refs, err := WhereUsedByPath(ctx, root, "bible:character:Mara", 10, 0)
+	if err != nil {
+		t.Fatalf("WhereUsedByPath: %v", err)
+	}
+	if !slices.ContainsFunc(refs, func(r SearchResult) bool { return r.Path == wantChanged[0] }) {
+		t.Fatalf("where-used of Mara = %+v, want %s", refs, wantChanged[0])
+	}
+
+	if d, err = VerifyIndexConsistency(ctx, root, edited); err != nil {
+		t.Fatalf("VerifyIndexConsistency after repair: %v", err)
+	}
+	if d.Drifted() {
+		t.Fatalf("drift after repair: %+v", d)
+	}
+}
+
+func TestRepairIndexConsistency_RebuildsWhenBibleChanged(t *testing.T) {
+	proj := consistencyTestProject()
+	root, ctx := buildSnapshotTestIndex(t, proj)
+	edited := editManifestBehindIndex(t, root, proj, func(p *domain.Project) {
+		p.Bible.Characters = append(p.Bible.Characters, domain.BibleCharacter{Name: "Keeper"})
+	})
+	d, err := RepairIndexConsistency(ctx, root, edited)
+	if err != nil {
+		t.Fatalf("RepairIndexConsistency: %v", err)
+	}
+	if !d.Repaired || !d.Rebuilt || !slices.Contains(d.Added, "bible:character:Keeper") {
+		t.Fatalf("repair = %+v, want a rebuild adding the character", d)
+	}
+	// Existing text now refers to the new character
+	refs, err := WhereUsedByPath(ctx, root, "bible:character:Keeper", 10, 0)
+	if err != nil {
+		t.Fatalf("WhereUsedByPath: %v", err)
+	}
+	if !slices.ContainsFunc(refs, func(r SearchResult) bool { return r.Path == "issue:1/page:1/panel:P2" }) {
+		t.Fatalf("where-used of Keeper = %+v, want the panel notes", refs)
+	}
+}
+
+func TestRepairIndexConsistency_MissingFingerprints(t *testing.T) {
+	proj := consistencyTestProject()
+	root, ctx := buildSnapshotTestIndex(t, proj)
+	// An index built before fingerprints were kept
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(2000)", filepath.ToSlash(IndexPath(root))))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `DELETE FROM doc_fingerprints`); err != nil {
+		t.Fatalf("clear fingerprints: %v", err)
+	}
+	_ = db.Close()
+
+	d, err := VerifyIndexConsistency(ctx, root, proj)
+	if err != nil {
+		t.Fatalf("VerifyIndexConsistency: %v", err)
+	}
+	if !d.MissingFingerprints || !d.Drifted() {
+		t.Fatalf("drift = %+v, want missing fingerprints", d)
+	}
+	if d, err = RepairIndexConsistency(ctx, root, proj); err != nil || !d.Rebuilt {
+		t.Fatalf("RepairIndexConsistency = %+v, %v; want a rebuild", d, err)
+	}
+	if d, err = VerifyIndexConsistency(ctx, root, proj); err != nil || d.Drifted() {
+		t.Fatalf("after repair: %+v, %v", d, err)
+	}
+}
+
+func docIDByPath(t *testing.T, ctx context.Context, root, path string) int64 {
+	t.Helper()
+	res, err := Search(ctx, root, SearchQuery{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	for _, r := range res {
+		if r.Path == path {
+			return r.DocID
+		}
+	}
+	t.Fatalf("no document at %s", path)
+	return 0
+}
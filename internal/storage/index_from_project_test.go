@@ -32,6 +32,7 @@ func TestIndexBuildFromProjectFTSAndCrossRef(t *testing.T) {
 		Issues: []domain.Issue{{
 			Pages: []domain.Page{{
 				Number: 1,
+				Notes:  "Colorist: keep the lighthouse warm",
 				Panels: []domain.Panel{{
 					ID:    "P1",
 					Notes: "Intro panel",
@@ -40,6 +41,8 @@ func TestIndexBuildFromProjectFTSAndCrossRef(t *testing.T) {
 						Type:     "speech",
 						TextRuns: []domain.TextRun{{Content: "Hello from Alice @greet at the beach"}},
 					}},
+					Captions: []domain.Caption{{ID: "C1", Text: "Meanwhile, at the lighthouse"}},
+					SFX:      []domain.SFXItem{{ID: "S1", Text: "KRAKOOM"}},
 				}},
 			}},
 		}},
@@ -68,9 +71,62 @@ func TestIndexBuildFromProjectFTSAndCrossRef(t *testing.T) {
 	if err != nil || len(res) == 0 {
 		t.Fatalf("Search tags: %v len=%d", err, len(res))
 	}
+	// Captions and SFX are indexed with their own types
+	res, err = Search(ctx, root, SearchQuery{Text: "lighthouse", Types: []string{"caption"}})
+	if err != nil || len(res) != 1 || res[0].Path != "issue:1/page:1/panel:P1/caption:C1" {
+		t.Fatalf("Search caption: %v res=%+v", err, res)
+	}
+	res, err = Search(ctx, root, SearchQuery{Text: "krakoom", Types: []string{"sfx"}})
+	if err != nil || len(res) != 1 {
+		t.Fatalf("Search sfx: %v res=%+v", err, res)
+	}
+	// Page notes are found on their own and apart from the panel notes
+	res, err = Search(ctx, root, SearchQuery{Text: "lighthouse", Types: []string{"page_notes"}})
+	if err != nil || len(res) != 1 || res[0].Path != "issue:1/page:1" || res[0].PageID != 1 {
+		t.Fatalf("Search page notes: %v res=%+v", err, res)
+	}
+	res, err = Search(ctx, root, SearchQuery{Text: "lighthouse", Types: []string{"panel_notes"}})
+	if err != nil || len(res) != 0 {
+		t.Fatalf("Search panel notes: %v res=%+v", err, res)
+	}
 	// Character filter should find balloon and possibly notes
 	res, err = Search(ctx, root, SearchQuery{Character: "alice"})
 	if err != nil || len(res) == 0 {
 		t.Fatalf("Search character: %v len=%d", err, len(res))
 	}
 }
+
+func TestManifestDocuments(t *testing.T) {
+	proj := domain.Project{
+		Name:  "Docs",
+		Bible: domain.Bible{Characters: []domain.BibleCharacter{{Name: "Bob", Notes: "  "}}},
+		Issues: []domain.Issue{{Pages: []domain.Page{{
+			Number: 4,
+			Notes:  " flat colors ",
+			Panels: []domain.Panel{{
+				ID:    "P1",
+				Notes: " wide shot ",
+				Balloons: []domain.Balloon{{
+					ID: "B1", Character: "Bob",
+					TextRuns: []domain.TextRun{{Content: "Hi"}, {Content: " "}, {Content: "there"}},
+				}},
+			}},
+		}}}},
+	}
+	got := ManifestDocuments(proj)
+	want := []IndexDocument{
+		{Type: "project_name", Path: "project:name", Text: "Docs"},
+		{Type: "character", Path: "bible:character:Bob", Text: "Bob"},
+		{Type: "page_notes", Path: "issue:1/page:4", PageID: 4, Text: "flat colors"},
+		{Type: "panel_notes", Path: "issue:1/page:4/panel:P1", PageID: 4, Text: "wide shot"},
+		{Type: "balloon", Path: "issue:1/page:4/panel:P1/balloon:B1", PageID: 4, Character: "Bob", Text: "Hi there"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("documents = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("document %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sync"
+
+	applog "gocomicwriter/internal/log"
+)
+
+// ErrIndexClosed is returned by IndexHandle methods after Close.
+var ErrIndexClosed = errors.New("index handle closed")
+
+// IndexHandle is a session-scoped connection to a project's index database (.gcw/index.sqlite).
+// The database is opened on first use and kept open until Close, so repeated searches, preview lookups
+// and index updates skip the open, PRAGMA and migration checks that InitOrOpenIndex performs.
+//
+// Methods are safe for concurrent use. The mutex only guards opening and closing; statements are
+// serialized by the single-connection pool, so a background rebuild and a foreground search take
+// turns on the connection instead of waiting on each other's locks.
+type IndexHandle struct {
+	root string
+
+	mu     sync.Mutex
+	db     *sql.DB
+	inUse  int  // calls currently holding db
+	closed bool // set by Close; db is closed once the last call releases it
+}
+
+// NewIndexHandle returns a handle for the index of the project at root without opening the database.
+func NewIndexHandle(root string) *IndexHandle {
+	return &IndexHandle{root: root}
+}
+
+// Root returns the project directory the handle belongs to.
+func (ix *IndexHandle) Root() string { return ix.root }
+
+// acquire returns the open database, opening it on first use. Every successful acquire must be paired
+// with release. Do not acquire again while holding rows from the returned db: the pool has one connection.
+func (ix *IndexHandle) acquire() (*sql.DB, error) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ix.closed {
+		return nil, ErrIndexClosed
+	}
+	if ix.db == nil {
+		db, err := InitOrOpenIndex(ix.root)
+		if err != nil {
+			return nil, err
+		}
+		ix.db = db
+	}
+	ix.inUse++
+	return ix.db, nil
+}
+
+// Check opens the database if it is not open yet and reports why it cannot be used, e.g.
+// ErrIndexNewerThanApp.
+func (ix *IndexHandle) Check() error {
+	if _, err := ix.acquire(); err != nil {
+		return err
+	}
+	ix.release()
+	return nil
+}
+
+func (ix *IndexHandle) release() {
+	ix.mu.Lock()
+	ix.inUse--
+	var db *sql.DB
+	if ix.closed && ix.inUse == 0 {
+		db, ix.db = ix.db, nil
+	}
+	ix.mu.Unlock()
+	closeIndexDB(db)
+}
+
+// discard closes the cached database so the next call reopens the file from disk. Calls still running
+// on the old connection finish or fail with sql.ErrConnDone.
+func (ix *IndexHandle) discard() {
+	ix.mu.Lock()
+	db := ix.db
+	ix.db = nil
+	ix.mu.Unlock()
+	closeIndexDB(db)
+}
+
+// Close releases the database. Calls already running finish first; later calls return ErrIndexClosed.
+// Close is safe to call more than once.
+func (ix *IndexHandle) Close() error {
+	if ix == nil {
+		return nil
+	}
+	ix.mu.Lock()
+	ix.closed = true
+	var db *sql.DB
+	if ix.inUse == 0 {
+		db, ix.db = ix.db, nil
+	}
+	ix.mu.Unlock()
+	if db != nil {
+		return db.Close()
+	}
+	return nil
+}
+
+func closeIndexDB(db *sql.DB) {
+	if db == nil {
+		return
+	}
+	if err := db.Close(); err != nil {
+		applog.WithComponent("storage").Warn("db close failed", slog.Any("err", err))
+	}
+}
+
+// withIndex runs fn with a one-off handle for projectRoot; it backs the package-level index functions.
+func withIndex[T any](projectRoot string, fn func(ix *IndexHandle) (T, error)) (T, error) {
+	ix := NewIndexHandle(projectRoot)
+	defer func() { _ = ix.Close() }()
+	return fn(ix)
+}
+
+// runIndex is withIndex for calls that only return an error.
+func runIndex(projectRoot string, fn func(ix *IndexHandle) error) error {
+	ix := NewIndexHandle(projectRoot)
+	defer func() { _ = ix.Close() }()
+	return fn(ix)
+}
+
+// projectIndex returns the index handle storage functions taking a ProjectHandle work on: ph's session
+// handle when one is open, so they share its connection and serialize with the other index work of the
+// session, or else a one-off handle for ph.Root. Callers defer done, which closes only a one-off handle.
+func projectIndex(ph *ProjectHandle) (ix *IndexHandle, done func()) {
+	if ph.index != nil && ph.index.root == ph.Root {
+		return ph.index, func() {}
+	}
+	ix = NewIndexHandle(ph.Root)
+	return ix, func() { _ = ix.Close() }
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+func handleProject() domain.Project {
+	return domain.Project{
+		Name: "Handle",
+		Issues: []domain.Issue{{
+			Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{{ID: "P1", Balloons: []domain.Balloon{{ID: "B1", Type: "speech", TextRuns: []domain.TextRun{{Content: "Hello world handle"}}}}}}}},
+		}},
+	}
+}
+
+func TestIndexHandle_ReusesConnectionUntilClose(t *testing.T) {
+	root := t.TempDir()
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName), Project: handleProject()}
+	t.Cleanup(func() { _ = ph.Close() })
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ix := ph.Index()
+	if ph.Index() != ix {
+		t.Fatalf("Index should return the same handle")
+	}
+	if ix.db != nil {
+		t.Fatalf("database opened before first use")
+	}
+	if err := ix.UpdateIndex(ctx, ph.Project); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	first := ix.db
+	res, err := ix.Search(ctx, SearchQuery{Text: "hello"})
+	if err != nil || len(res) == 0 {
+		t.Fatalf("search: %v %+v", err, res)
+	}
+	if err := ix.PutPreview(ctx, 1, sql.NullInt64{}, PreviewKindThumb, 8, 8, "", []byte{1, 2, 3}); err != nil {
+		t.Fatalf("put preview: %v", err)
+	}
+	if b, err := ix.GetPreview(ctx, 1, sql.NullInt64{}, PreviewKindThumb, 8, 8, ""); err != nil || len(b) != 3 {
+		t.Fatalf("get preview: %v %v", b, err)
+	}
+	if ix.db != first {
+		t.Fatalf("connection was reopened between calls")
+	}
+	// Snapshot helpers taking the project handle share the session.
+	if got, done := projectIndex(ph); got != ix {
+		t.Fatalf("projectIndex should return the session handle")
+	} else {
+		done()
+	}
+
+	if err := ph.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := ph.Close(); err != nil {
+		t.Fatalf("second close: %v", err)
+	}
+	if _, err := ix.Search(ctx, SearchQuery{Text: "hello"}); !errors.Is(err, ErrIndexClosed) {
+		t.Fatalf("expected ErrIndexClosed, got %v", err)
+	}
+	// A new session opens on demand and sees the same data.
+	if ph.Index() == ix {
+		t.Fatalf("expected a fresh handle after Close")
+	}
+	if res, err := ph.Index().Search(ctx, SearchQuery{Text: "hello"}); err != nil || len(res) == 0 {
+		t.Fatalf("search after reopen: %v %+v", err, res)
+	}
+}
+
+func TestIndexHandle_OneOffWhenNoSession(t *testing.T) {
+	root := t.TempDir()
+	ph := &ProjectHandle{Root: root}
+	ix, done := projectIndex(ph)
+	if ph.index != nil {
+		t.Fatalf("projectIndex must not start a session")
+	}
+	if _, err := ix.TotalPreviewBytes(context.Background()); err != nil {
+		t.Fatalf("total: %v", err)
+	}
+	done()
+	if _, err := ix.TotalPreviewBytes(context.Background()); !errors.Is(err, ErrIndexClosed) {
+		t.Fatalf("one-off handle should be closed by done, got %v", err)
+	}
+}
+
+func TestIndexHandle_BackgroundRebuildAndSearch(t *testing.T) {
+	root := t.TempDir()
+	ix := NewIndexHandle(root)
+	t.Cleanup(func() { _ = ix.Close() })
+	proj := handleProject()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	if err := ix.UpdateIndex(ctx, proj); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	rebuilt := make(chan error, 1)
+	go func() {
+		for i := 0; i < 10; i++ {
+			if err := ix.RebuildIndex(ctx, proj); err != nil {
+				rebuilt <- err
+				return
+			}
+		}
+		rebuilt <- nil
+	}()
+	searches := 0
+	for done := false; !done; {
+		select {
+		case err := <-rebuilt:
+			if err != nil {
+				t.Fatalf("rebuild: %v", err)
+			}
+			done = true
+		default:
+			// Searches that land between the drop and the recreate may fail; they must not hang.
+			_, _ = ix.Search(ctx, SearchQuery{Text: "hello"})
+			searches++
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("rebuild and search did not finish: %d searches", searches)
+		}
+	}
+	res, err := ix.Search(ctx, SearchQuery{Text: "hello"})
+	if err != nil || len(res) == 0 {
+		t.Fatalf("search after rebuilds: %v %+v", err, res)
+	}
+	if ix.inUse != 0 {
+		t.Fatalf("leaked acquisitions: %d", ix.inUse)
+	}
+}
+
+func TestIndexHandle_CloseWaitsForRunningCall(t *testing.T) {
+	root := t.TempDir()
+	ix := NewIndexHandle(root)
+	db, err := ix.acquire()
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if err := ix.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	// The running call keeps a usable connection until it releases it.
+	if _, err := db.Exec(`SELECT 1`); err != nil {
+		t.Fatalf("query during close: %v", err)
+	}
+	ix.release()
+	if ix.db != nil {
+		t.Fatalf("database should be closed after the last release")
+	}
+}
+
+func BenchmarkSearchPerCallOpen(b *testing.B) {
+	root := b.TempDir()
+	ctx := context.Background()
+	if err := UpdateIndex(ctx, root, handleProject()); err != nil {
+		b.Fatalf("update: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Search(ctx, root, SearchQuery{Text: "hello"}); err != nil {
+			b.Fatalf("search: %v", err)
+		}
+	}
+}
+
+func BenchmarkSearchIndexHandle(b *testing.B) {
+	root := b.TempDir()
+	ctx := context.Background()
+	ix := NewIndexHandle(root)
+	defer func() { _ = ix.Close() }()
+	if err := ix.UpdateIndex(ctx, handleProject()); err != nil {
+		b.Fatalf("update: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ix.Search(ctx, SearchQuery{Text: "hello"}); err != nil {
+			b.Fatalf("search: %v", err)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	applog "gocomicwriter/internal/log"
+)
+
+// DefaultIndexBackupsKept is the number of index snapshots kept in .gcw/backups when no other
+// retention is configured.
+const DefaultIndexBackupsKept = 5
+
+// indexSnapshotStampLayout is the timestamp in index.sqlite.<stamp>.bak names; the milliseconds keep
+// two snapshots taken in the same second apart.
+const indexSnapshotStampLayout = "20060102-150405.000"
+
+var indexBackupsKept = DefaultIndexBackupsKept
+
+// SetIndexBackupRetention sets how many index snapshots are kept in .gcw/backups. Non-positive values
+// use DefaultIndexBackupsKept.
+func SetIndexBackupRetention(keep int) {
+	if keep <= 0 {
+		keep = DefaultIndexBackupsKept
+	}
+	retentionMu.Lock()
+	indexBackupsKept = keep
+	retentionMu.Unlock()
+}
+
+func currentIndexBackupsKept() int {
+	retentionMu.Lock()
+	defer retentionMu.Unlock()
+	return indexBackupsKept
+}
+
+// snapshotIndexFile writes a consistent copy of the index at indexPath to .gcw/backups with VACUUM INTO,
+// so pages still in the -wal file are included, and verifies that the copy opens and passes quick_check.
+// It returns the snapshot path, or "" when there is no index to copy. A copy that fails verification is
+// removed and reported as an error; older snapshots beyond the retention are pruned.
+func snapshotIndexFile(ctx context.Context, indexPath string) (string, error) {
+	if _, err := os.Stat(indexPath); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("stat index: %w", err)
+	}
+	bdir := filepath.Join(filepath.Dir(indexPath), "backups")
+	if err := os.MkdirAll(bdir, 0o755); err != nil {
+		return "", fmt.Errorf("mkdir backups: %w", err)
+	}
+	stamp := strings.Replace(time.Now().Format(indexSnapshotStampLayout), ".", "", 1)
+	bak := filepath.Join(bdir, fmt.Sprintf("%s.%s.bak", filepath.Base(indexPath), stamp))
+	tmp := bak + ".tmp"
+	_ = os.Remove(tmp)
+	if err := vacuumIndexInto(ctx, indexPath, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	if err := verifyIndexSnapshot(ctx, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	if err := os.Rename(tmp, bak); err != nil {
+		_ = os.Remove(tmp)
+		return "", fmt.Errorf("rename index snapshot: %w", err)
+	}
+	pruneIndexBackups(bdir, filepath.Base(indexPath), currentIndexBackupsKept())
+	return bak, nil
+}
+
+// vacuumIndexInto copies the database at src to dst on a private connection, so the shared cache of
+// open handles is not involved and a damaged file fails here instead of on the next search.
+func vacuumIndexInto(ctx context.Context, src, dst string) error {
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)", src))
+	if err != nil {
+		return fmt.Errorf("open index for snapshot: %w", err)
+	}
+	defer closeIndexDB(db)
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?;", dst); err != nil {
+		return fmt.Errorf("snapshot index: %w", indexErr(err))
+	}
+	return nil
+}
+
+// verifyIndexSnapshot opens the snapshot at path and runs quick_check on it.
+func verifyIndexSnapshot(ctx context.Context, path string) error {
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=ro", path))
+	if err != nil {
+		return fmt.Errorf("open index snapshot: %w", err)
+	}
+	defer closeIndexDB(db)
+	var chk string
+	if err := db.QueryRowContext(ctx, "PRAGMA quick_check;").Scan(&chk); err != nil {
+		return fmt.Errorf("verify index snapshot: %w", indexErr(err))
+	}
+	if !strings.EqualFold(strings.TrimSpace(chk), "ok") {
+		return fmt.Errorf("%w: index snapshot failed quick_check: %s", ErrIndexCorrupt, chk)
+	}
+	return nil
+}
+
+// restoreIndexSnapshot replaces the index files with the snapshot at bak. The handle's connection is
+// dropped first so the next call opens the restored file.
+func (ix *IndexHandle) restoreIndexSnapshot(bak string) error {
+	path := IndexPath(ix.root)
+	ix.discard()
+	if err := removeIndexFiles(path); err != nil {
+		return err
+	}
+	if err := copyFile(bak, path); err != nil {
+		return fmt.Errorf("restore index snapshot: %w", err)
+	}
+	return nil
+}
+
+// rebuildWithSnapshot runs RebuildIndex and, when it fails, puts the verified snapshot bak back so
+// search keeps working on the old content. An empty bak only rebuilds.
+func (ix *IndexHandle) rebuildWithSnapshot(ctx context.Context, proj domain.Project, bak string) error {
+	err := ix.rebuildIndex(ctx, proj)
+	if err == nil || bak == "" {
+		return err
+	}
+	if rerr := ix.restoreIndexSnapshot(bak); rerr != nil {
+		return fmt.Errorf("%w (restoring snapshot %s: %v)", err, filepath.Base(bak), rerr)
+	}
+	applog.WithComponent("storage").Warn("index rebuild failed; snapshot restored",
+		slog.String("snapshot", bak), slog.Any("err", err))
+	return err
+}
+
+// preserveCorruptIndex copies a damaged index and its -wal file byte for byte to .gcw/backups when no
+// verified snapshot can be made, so the files are kept for inspection. Missing files are skipped.
+func preserveCorruptIndex(indexPath string) error {
+	bdir := filepath.Join(filepath.Dir(indexPath), "backups")
+	if err := os.MkdirAll(bdir, 0o755); err != nil {
+		return fmt.Errorf("mkdir backups: %w", err)
+	}
+	stamp := strings.Replace(time.Now().Format(indexSnapshotStampLayout), ".", "", 1)
+	base := filepath.Join(bdir, fmt.Sprintf("%s.%s.corrupt", filepath.Base(indexPath), stamp))
+	for _, suffix := range []string{"", "-wal"} {
+		if err := copyFile(indexPath+suffix, base+suffix); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("copy damaged index: %w", err)
+		}
+	}
+	pruneIndexBackups(bdir, filepath.Base(indexPath), currentIndexBackupsKept())
+	return nil
+}
+
+// pruneIndexBackups keeps the newest keep index snapshots and damaged copies of name in bdir and
+// removes the rest. Failures are logged; a leftover backup only costs disk space.
+func pruneIndexBackups(bdir, name string, keep int) {
+	entries, err := os.ReadDir(bdir)
+	if err != nil {
+		return
+	}
+	var backups []string
+	for _, e := range entries {
+		n := e.Name()
+		if e.IsDir() || !strings.HasPrefix(n, name+".") {
+			continue
+		}
+		if strings.HasSuffix(n, ".bak") || strings.HasSuffix(n, ".corrupt") {
+			backups = append(backups, n)
+		}
+	}
+	if len(backups) <= keep {
+		return
+	}
+	// the stamp follows the name, so names sort by age
+	sort.Strings(backups)
+	for _, n := range backups[:len(backups)-keep] {
+		for _, suffix := range []string{"", "-wal"} {
+			if err := os.Remove(filepath.Join(bdir, n+suffix)); err != nil && !os.IsNotExist(err) {
+				applog.WithComponent("storage").Warn("prune index backup failed", slog.String("file", n+suffix), slog.Any("err", err))
+			}
+		}
+	}
+}
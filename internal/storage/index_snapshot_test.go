@@ -0,0 +1,222 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+func snapshotTestProject() domain.Project {
+	return domain.Project{
+		Name: "Snapshot Test",
+		Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{{ID: "P1", Notes: "lighthouse at dusk",
+			Balloons: []domain.Balloon{{ID: "B1", Type: "speech", TextRuns: []domain.TextRun{{Content: "keep the lamp burning"}}}}}}}}}},
+	}
+}
+
+// buildSnapshotTestIndex builds the index of proj in a fresh project folder without the background
+// indexing of InitProject.
+func buildSnapshotTestIndex(t *testing.T, proj domain.Project) (string, context.Context) {
+	t.Helper()
+	root := t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	if err := RebuildIndex(ctx, root, proj); err != nil {
+		t.Fatalf("RebuildIndex: %v", err)
+	}
+	return root, ctx
+}
+
+func indexBackups(t *testing.T, root, suffix string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(root, IndexDirName, "backups"))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("read backups: %v", err)
+	}
+	var out []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), IndexFileName+".") && strings.HasSuffix(e.Name(), suffix) {
+			out = append(out, e.Name())
+		}
+	}
+	return out
+}
+
+func assertSearchFinds(t *testing.T, ctx context.Context, root, text string) {
+	t.Helper()
+	res, err := Search(ctx, root, SearchQuery{Text: text})
+	if err != nil {
+		t.Fatalf("Search(%q): %v", text, err)
+	}
+	if len(res) == 0 {
+		t.Fatalf("Search(%q) found nothing", text)
+	}
+}
+
+func TestSnapshotIndexFile_IncludesWAL(t *testing.T) {
+	root, ctx := buildSnapshotTestIndex(t, snapshotTestProject())
+	idx := IndexPath(root)
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(2000)", filepath.ToSlash(idx)))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("conn: %v", err)
+	}
+	defer conn.Close()
+	// Keep the new row in the -wal file only
+	for _, q := range []string{
+		"PRAGMA journal_mode=WAL;",
+		"PRAGMA wal_autocheckpoint=0;",
+		"INSERT INTO documents(type, path, text) VALUES('script', 'script:wal.txt', 'only in the wal');",
+	} {
+		if _, err := conn.ExecContext(ctx, q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	if st, err := os.Stat(idx + "-wal"); err != nil || st.Size() == 0 {
+		t.Fatalf("expected a non-empty -wal file: %v", err)
+	}
+
+	bak, err := snapshotIndexFile(ctx, idx)
+	if err != nil {
+		t.Fatalf("snapshotIndexFile: %v", err)
+	}
+	if bak == "" || !strings.HasSuffix(bak, ".bak") {
+		t.Fatalf("snapshot path = %q", bak)
+	}
+	snap, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=ro", filepath.ToSlash(bak)))
+	if err != nil {
+		t.Fatalf("open snapshot: %v", err)
+	}
+	defer snap.Close()
+	var n int
+	if err := snap.QueryRowContext(ctx, "SELECT COUNT(*) FROM documents WHERE path = 'script:wal.txt';").Scan(&n); err != nil {
+		t.Fatalf("query snapshot: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("snapshot misses the row still in the -wal file")
+	}
+}
+
+func TestSnapshotIndexFile_NoIndex(t *testing.T) {
+	bak, err := snapshotIndexFile(context.Background(), IndexPath(t.TempDir()))
+	if err != nil || bak != "" {
+		t.Fatalf("snapshotIndexFile without an index = %q, %v; want \"\", nil", bak, err)
+	}
+}
+
+func TestRebuildIndex_RestoresSnapshotOnFailure(t *testing.T) {
+	root, ctx := buildSnapshotTestIndex(t, snapshotTestProject())
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(2000)", filepath.ToSlash(IndexPath(root))))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	// Without columnsize the FTS table has no docsize shadow table, so a plain table can take its name:
+	// the index still opens, but the rebuild drops the old tables and then fails to recreate the FTS table
+	for _, q := range []string{
+		"DROP TABLE fts_documents;",
+		"CREATE VIRTUAL TABLE fts_documents USING fts5(text, content='', columnsize=0, tokenize='unicode61');",
+		"INSERT INTO fts_documents(rowid, text) SELECT doc_id, text FROM documents;",
+		"CREATE TABLE fts_documents_docsize(x);",
+	} {
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			_ = db.Close()
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	_ = db.Close()
+
+	if err := RebuildIndex(ctx, root, snapshotTestProject()); err == nil {
+		t.Fatalf("expected the rebuild to fail")
+	}
+	assertSearchFinds(t, ctx, root, "lighthouse")
+	if got := indexBackups(t, root, ".bak"); len(got) != 1 {
+		t.Fatalf("snapshots = %v, want the one taken before the failed rebuild", got)
+	}
+}
+
+func TestDetectAndRebuildIndex_CorruptWALIndex(t *testing.T) {
+	proj := snapshotTestProject()
+	root, ctx := buildSnapshotTestIndex(t, proj)
+	idx := IndexPath(root)
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(2000)", filepath.ToSlash(idx)))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("conn: %v", err)
+	}
+	for _, q := range []string{
+		"PRAGMA wal_autocheckpoint=0;",
+		"INSERT INTO documents(type, path, text) VALUES('script', 'script:wal.txt', 'only in the wal');",
+	} {
+		if _, err := conn.ExecContext(ctx, q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	// Overwrite everything after the first page of the main file while the -wal file is live
+	data, err := os.ReadFile(idx)
+	if err != nil {
+		t.Fatalf("read index: %v", err)
+	}
+	for i := 4096; i < len(data); i++ {
+		data[i] = 0xA5
+	}
+	if err := os.WriteFile(idx, data, 0o644); err != nil {
+		t.Fatalf("corrupt index: %v", err)
+	}
+	_ = conn.Close()
+	_ = db.Close()
+
+	rebuilt, err := DetectAndRebuildIndex(ctx, root, proj)
+	if err != nil {
+		t.Fatalf("DetectAndRebuildIndex: %v", err)
+	}
+	if !rebuilt {
+		t.Fatalf("expected a rebuild of the corrupt index")
+	}
+	assertSearchFinds(t, ctx, root, "lighthouse")
+	if len(indexBackups(t, root, ".bak"))+len(indexBackups(t, root, ".corrupt")) == 0 {
+		t.Fatalf("expected a snapshot or a copy of the damaged index in backups")
+	}
+}
+
+func TestIndexBackupRetention(t *testing.T) {
+	SetIndexBackupRetention(2)
+	t.Cleanup(func() { SetIndexBackupRetention(0) })
+	root, ctx := buildSnapshotTestIndex(t, snapshotTestProject())
+	for i := 0; i < 4; i++ {
+		if err := RebuildIndex(ctx, root, snapshotTestProject()); err != nil {
+			t.Fatalf("RebuildIndex #%d: %v", i, err)
+		}
+	}
+	if got := indexBackups(t, root, ".bak"); len(got) != 2 {
+		t.Fatalf("snapshots kept = %v, want 2", got)
+	}
+	if currentIndexBackupsKept() != 2 {
+		t.Fatalf("retention = %d", currentIndexBackupsKept())
+	}
+	SetIndexBackupRetention(-1)
+	if currentIndexBackupsKept() != DefaultIndexBackupsKept {
+		t.Fatalf("non-positive retention should use the default")
+	}
+}
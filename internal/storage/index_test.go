@@ -13,13 +13,16 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/version"
 
 	_ "modernc.org/sqlite"
 )
@@ -86,3 +89,76 @@ func TestIndexInitCreatesWALAndMetaVersion(t *testing.T) {
 		t.Fatalf("expected FTS to find inserted document")
 	}
 }
+
+func TestIndexNewerThanApp(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, IndexDirName), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// An index as a future app version might leave it: schema 99 and a table this version does not know
+	db, err := sql.Open("sqlite", filepath.ToSlash(IndexPath(root)))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	for _, q := range []string{
+		`CREATE TABLE version (id INTEGER PRIMARY KEY CHECK(id=1), schema INTEGER NOT NULL, app TEXT, created_at TEXT NOT NULL, updated_at TEXT NOT NULL)`,
+		`INSERT INTO version VALUES (1, 99, '9.9.0', 'then', 'then')`,
+		`CREATE TABLE documents_v99 (id INTEGER PRIMARY KEY)`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	_ = db.Close()
+
+	_, err = InitOrOpenIndex(root)
+	if !errors.Is(err, ErrIndexNewerThanApp) {
+		t.Fatalf("InitOrOpenIndex = %v, want ErrIndexNewerThanApp", err)
+	}
+	for _, want := range []string{"schema 99", "9.9.0", fmt.Sprintf("up to schema %d", schemaVersion)} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	proj := domain.Project{Name: "Downgrade", Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Notes: "lighthouse at dusk"}}}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if rebuilt, err := DetectAndRebuildIndex(ctx, root, proj); rebuilt || !errors.Is(err, ErrIndexNewerThanApp) {
+		t.Fatalf("DetectAndRebuildIndex = %v, %v; want the newer index left alone", rebuilt, err)
+	}
+
+	// The newer index was neither migrated nor marked as written by this app
+	db, err = sql.Open("sqlite", filepath.ToSlash(IndexPath(root)))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	var schema, tables int
+	var app string
+	if err := db.QueryRow(`SELECT schema, app FROM version WHERE id=1`).Scan(&schema, &app); err != nil || schema != 99 || app != "9.9.0" {
+		t.Fatalf("version row = %d, %q, %v", schema, app, err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'documents'`).Scan(&tables); err != nil || tables != 0 {
+		t.Fatalf("documents table created in the newer index: %d, %v", tables, err)
+	}
+	_ = db.Close()
+
+	// Resetting builds an index of this version from the project and keeps a backup of the old one
+	if err := ResetIndex(ctx, root, proj); err != nil {
+		t.Fatalf("ResetIndex: %v", err)
+	}
+	db, err = InitOrOpenIndex(root)
+	if err != nil {
+		t.Fatalf("InitOrOpenIndex after reset: %v", err)
+	}
+	if err := db.QueryRow(`SELECT schema, app FROM version WHERE id=1`).Scan(&schema, &app); err != nil || schema != schemaVersion || app != version.String() {
+		t.Fatalf("version row after reset = %d, %q, %v", schema, app, err)
+	}
+	_ = db.Close()
+	if res, err := Search(ctx, root, SearchQuery{Text: "lighthouse"}); err != nil || len(res) != 1 {
+		t.Fatalf("search after reset = %+v, %v", res, err)
+	}
+	backups, _ := filepath.Glob(filepath.Join(root, IndexDirName, "backups", IndexFileName+".*.bak"))
+	if len(backups) != 1 {
+		t.Fatalf("index backups = %v", backups)
+	}
+}
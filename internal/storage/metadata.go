@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// ValidateMetadata checks the fields of the project metadata that exports copy verbatim: LanguageISO
+// must look like a BCP 47 language tag (en, de-DE, zh-Hant) and Web must be an absolute http(s) URL.
+// Empty fields are valid.
+func ValidateMetadata(m domain.Metadata) error {
+	if s := strings.TrimSpace(m.LanguageISO); s != "" && !plausibleLanguageTag(s) {
+		return fmt.Errorf("language %q is not an ISO language code such as en or de-DE", s)
+	}
+	if s := strings.TrimSpace(m.Web); s != "" {
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("web address %q is not an http(s) URL", s)
+		}
+	}
+	return nil
+}
+
+// SetProjectMetadata replaces the project metadata after trimming and validating it.
+func SetProjectMetadata(ph *ProjectHandle, m domain.Metadata) error {
+	if ph == nil {
+		return fmt.Errorf("project handle is nil")
+	}
+	m = domain.Metadata{
+		Series: strings.TrimSpace(m.Series), IssueTitle: strings.TrimSpace(m.IssueTitle),
+		Creators: strings.TrimSpace(m.Creators), Notes: strings.TrimSpace(m.Notes),
+		Genre: strings.TrimSpace(m.Genre), AgeRating: strings.TrimSpace(m.AgeRating),
+		Web: strings.TrimSpace(m.Web), LanguageISO: strings.TrimSpace(m.LanguageISO),
+	}
+	if err := ValidateMetadata(m); err != nil {
+		return err
+	}
+	ph.Project.Metadata = m
+	return nil
+}
+
+// SetIssueNumber sets the number exports show for an issue; an empty number restores its position.
+func SetIssueNumber(ph *ProjectHandle, issueIndex int, number string) error {
+	if ph == nil {
+		return fmt.Errorf("project handle is nil")
+	}
+	if issueIndex < 0 || issueIndex >= len(ph.Project.Issues) {
+		return fmt.Errorf("issue index out of range")
+	}
+	number = strings.TrimSpace(number)
+	if strings.ContainsAny(number, "\n\t") {
+		return fmt.Errorf("issue number %q must be a single line", number)
+	}
+	ph.Project.Issues[issueIndex].Number = number
+	return nil
+}
+
+// SetPageNotes sets the production notes of a page of an issue; surrounding blank space is dropped.
+func SetPageNotes(ph *ProjectHandle, issueIndex, pageNumber int, notes string) error {
+	if ph == nil {
+		return fmt.Errorf("project handle is nil")
+	}
+	if issueIndex < 0 || issueIndex >= len(ph.Project.Issues) {
+		return fmt.Errorf("issue index out of range")
+	}
+	iss := &ph.Project.Issues[issueIndex]
+	i := pageIndexByNumber(*iss, pageNumber)
+	if i < 0 {
+		return pageNotFound(pageNumber)
+	}
+	iss.Pages[i].Notes = strings.TrimSpace(notes)
+	return nil
+}
+
+// plausibleLanguageTag reports whether s has the shape of a BCP 47 tag: a primary language subtag of
+// two or three letters followed by hyphen-separated subtags of one to eight letters or digits.
+func plausibleLanguageTag(s string) bool {
+	parts := strings.Split(s, "-")
+	if n := len(parts[0]); n < 2 || n > 3 || !isASCIIAlnum(parts[0], true) {
+		return false
+	}
+	for _, p := range parts[1:] {
+		if len(p) == 0 || len(p) > 8 || !isASCIIAlnum(p, false) {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCIIAlnum(s string, lettersOnly bool) bool {
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && !lettersOnly:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	for _, m := range []domain.Metadata{
+		{},
+		{LanguageISO: "en"},
+		{LanguageISO: "de-DE", Web: "https://example.com/series"},
+		{LanguageISO: "zh-Hant-TW", Web: "http://example.com"},
+	} {
+		if err := ValidateMetadata(m); err != nil {
+			t.Errorf("ValidateMetadata(%+v) = %v", m, err)
+		}
+	}
+	for _, m := range []domain.Metadata{
+		{LanguageISO: "english"},
+		{LanguageISO: "e"},
+		{LanguageISO: "en_US"},
+		{LanguageISO: "en-"},
+		{Web: "example.com"},
+		{Web: "ftp://example.com"},
+		{Web: "https://"},
+	} {
+		if err := ValidateMetadata(m); err == nil {
+			t.Errorf("ValidateMetadata(%+v) should fail", m)
+		}
+	}
+}
+
+func TestSetProjectMetadata(t *testing.T) {
+	ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{}}}}
+	if err := SetProjectMetadata(ph, domain.Metadata{Series: " Moon ", Genre: "Sci-Fi ", LanguageISO: "fr"}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if m := ph.Project.Metadata; m.Series != "Moon" || m.Genre != "Sci-Fi" || m.LanguageISO != "fr" {
+		t.Fatalf("metadata = %+v", m)
+	}
+	if err := SetProjectMetadata(ph, domain.Metadata{Web: "not a url"}); err == nil {
+		t.Fatal("invalid web address accepted")
+	}
+	if ph.Project.Metadata.Series != "Moon" {
+		t.Fatal("rejected metadata replaced the old one")
+	}
+	if err := SetIssueNumber(ph, 0, " 1.5 "); err != nil || ph.Project.Issues[0].Number != "1.5" {
+		t.Fatalf("issue number = %q, %v", ph.Project.Issues[0].Number, err)
+	}
+	if err := SetIssueNumber(ph, 1, "2"); err == nil {
+		t.Fatal("issue index out of range accepted")
+	}
+}
+
+func TestSetPageNotes(t *testing.T) {
+	ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1}, {Number: 2}}}}}}
+	if err := SetPageNotes(ph, 0, 2, " Flat colors only \n"); err != nil || ph.Project.Issues[0].Pages[1].Notes != "Flat colors only" {
+		t.Fatalf("page notes = %q, %v", ph.Project.Issues[0].Pages[1].Notes, err)
+	}
+	if err := SetPageNotes(ph, 0, 3, "x"); !errors.Is(err, ErrPageNotFound) {
+		t.Fatalf("missing page: %v", err)
+	}
+	if err := SetPageNotes(ph, 1, 1, "x"); err == nil {
+		t.Fatal("issue index out of range accepted")
+	}
+}
+
+func TestPageNotesJSONCompatibility(t *testing.T) {
+	var p domain.Project
+	if err := json.Unmarshal([]byte(`{"name":"Old","issues":[{"pages":[{"number":1,"panels":[]}]}]}`), &p); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if p.Issues[0].Pages[0].Notes != "" {
+		t.Fatalf("old page decoded with notes %q", p.Issues[0].Pages[0].Notes)
+	}
+	data, err := json.Marshal(p.Issues[0].Pages[0])
+	if err != nil || strings.Contains(string(data), "notes") {
+		t.Fatalf("page without notes writes %s, %v", data, err)
+	}
+	p.Issues[0].Pages[0].Notes = "Letterer: tight gutters"
+	data, _ = json.Marshal(p)
+	var back domain.Project
+	if err := json.Unmarshal(data, &back); err != nil || back.Issues[0].Pages[0].Notes != "Letterer: tight gutters" {
+		t.Fatalf("round trip = %+v, %v", back.Issues[0].Pages, err)
+	}
+}
+
+func TestMetadataJSONCompatibility(t *testing.T) {
+	var p domain.Project
+	if err := json.Unmarshal([]byte(`{"name":"Old","metadata":{"series":"S"},"issues":[{"pages":[]}]}`), &p); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if p.Metadata != (domain.Metadata{Series: "S"}) || p.Issues[0].Number != "" {
+		t.Fatalf("old manifest decoded as %+v / %q", p.Metadata, p.Issues[0].Number)
+	}
+	p.Metadata.Genre = "Noir"
+	data, err := json.Marshal(p.Metadata)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if got := string(data); got != `{"series":"S","genre":"Noir"}` {
+		t.Fatalf("metadata JSON = %s", got)
+	}
+	docs := ManifestDocuments(p)
+	if len(docs) != 3 || docs[2] != (IndexDocument{Type: "project_genre", Path: "project:genre", Text: "Noir"}) {
+		t.Fatalf("documents = %+v", docs)
+	}
+}
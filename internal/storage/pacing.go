@@ -0,0 +1,154 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/script"
+)
+
+// PagePacing is the pacing data of one page.
+// Beats counts the beat links of its panels like PageBeatCoverage.TotalBeats; the turn flags are those of
+// ComputePageTurnIndicators. UnmappedBeats counts the script beats without a panel that fall on this page
+// by script order: after a beat mapped to this page and before the next mapped beat.
+type PagePacing struct {
+	PageNumber        int
+	Beats             int
+	IsTurn            bool
+	LastPanelHasBeats bool
+	UnmappedBeats     int
+}
+
+// TurnBeat reports whether the page ends on a beat right before a page turn.
+func (p PagePacing) TurnBeat() bool {
+	return p.IsTurn && p.LastPanelHasBeats
+}
+
+// IssuePacing is the pacing data of one issue, pages in page-number order.
+// LongestRunWithoutTurnBeat is the longest stretch of consecutive pages none of which is a turn beat,
+// starting at page RunStart (0 when the issue has no pages).
+type IssuePacing struct {
+	Issue                     int // index in Project.Issues
+	Pages                     []PagePacing
+	TotalBeats                int
+	AvgBeatsPerPage           float64
+	UnmappedBeats             int // script beats without a panel anywhere in the project
+	LongestRunWithoutTurnBeat int
+	RunStart                  int
+}
+
+// PacingReport holds the pacing data of every issue of a project.
+type PacingReport struct {
+	Issues []IssuePacing
+}
+
+// ComputePacingReport combines beat coverage, page-turn indicators and the script's unmapped beats into a
+// report per issue. It is the single entry point for pacing analytics.
+func ComputePacingReport(p domain.Project, sc script.Script) PacingReport {
+	mapped := MappedBeatSet(p)
+	var beats []string // script order
+	for _, scn := range sc.Scenes {
+		for _, ln := range scn.Lines {
+			if ln.Type == script.LineBeat {
+				beats = append(beats, BeatIDFor(ln))
+			}
+		}
+	}
+	unmapped := 0
+	for _, id := range beats {
+		if _, ok := mapped[id]; !ok {
+			unmapped++
+		}
+	}
+	var rep PacingReport
+	for i, iss := range p.Issues {
+		ip := IssuePacing{Issue: i, UnmappedBeats: unmapped}
+		byNumber := map[int]int{}
+		for _, ti := range ComputePageTurnIndicators(iss) {
+			byNumber[ti.PageNumber] = len(ip.Pages)
+			ip.Pages = append(ip.Pages, PagePacing{PageNumber: ti.PageNumber, IsTurn: ti.IsTurn, LastPanelHasBeats: ti.LastPanelHasBeats})
+		}
+		// A beat linked from several panels counts on the lowest page, as in ComputeSceneStarts
+		beatPage := map[string]int{}
+		for _, pg := range iss.Pages {
+			k := byNumber[pg.Number]
+			for _, pn := range pg.Panels {
+				ip.Pages[k].Beats += len(pn.BeatIDs)
+				for _, id := range pn.BeatIDs {
+					if n, ok := beatPage[id]; id != "" && (!ok || pg.Number < n) {
+						beatPage[id] = pg.Number
+					}
+				}
+			}
+			ip.TotalBeats += ip.Pages[k].Beats
+		}
+		// Unmapped beats go to the page of the mapped beat before them; leading ones to the first mapped page
+		page, pending := 0, 0
+		for _, id := range beats {
+			if n, ok := beatPage[id]; ok {
+				if page == 0 {
+					ip.Pages[byNumber[n]].UnmappedBeats += pending
+				}
+				page = n
+				continue
+			}
+			if _, ok := mapped[id]; ok {
+				continue // mapped in another issue
+			}
+			if page == 0 {
+				pending++
+			} else {
+				ip.Pages[byNumber[page]].UnmappedBeats++
+			}
+		}
+		if len(ip.Pages) > 0 {
+			ip.AvgBeatsPerPage = float64(ip.TotalBeats) / float64(len(ip.Pages))
+		}
+		run, start := 0, 0
+		for _, pp := range ip.Pages {
+			if pp.TurnBeat() {
+				run = 0
+				continue
+			}
+			if run == 0 {
+				start = pp.PageNumber
+			}
+			run++
+			if run > ip.LongestRunWithoutTurnBeat {
+				ip.LongestRunWithoutTurnBeat, ip.RunStart = run, start
+			}
+		}
+		rep.Issues = append(rep.Issues, ip)
+	}
+	return rep
+}
+
+// WriteCSV writes one row per page of every issue, with a header row. Issues are numbered from 1.
+func (r PacingReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"issue", "page", "beats", "turn", "turn_beat", "unmapped_beats"})
+	for _, ip := range r.Issues {
+		for _, pp := range ip.Pages {
+			_ = cw.Write([]string{
+				strconv.Itoa(ip.Issue + 1), strconv.Itoa(pp.PageNumber), strconv.Itoa(pp.Beats),
+				strconv.FormatBool(pp.IsTurn), strconv.FormatBool(pp.TurnBeat()), strconv.Itoa(pp.UnmappedBeats),
+			})
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("write pacing csv: %w", err)
+	}
+	return nil
+}
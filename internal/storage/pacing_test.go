@@ -9,8 +9,12 @@
 package storage
 
 import (
-	"gocomicwriter/internal/domain"
+	"bytes"
+	"strings"
 	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/script"
 )
 
 func TestComputeBeatCoverageAndTurns(t *testing.T) {
@@ -62,3 +66,60 @@ func TestComputeBeatCoverageAndTurns(t *testing.T) {
 		t.Fatalf("expected page 2 to have beats on last panel")
 	}
 }
+
+func TestComputePacingReport(t *testing.T) {
+	sc, errs := script.Parse(`# Scene One
+Beat A
+Beat B
+Beat C
+Beat D
+Beat E`)
+	if len(errs) > 0 {
+		t.Fatalf("parse: %+v", errs)
+	}
+	p := domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{
+		{Number: 1, Panels: []domain.Panel{{ID: "p1", BeatIDs: []string{"b:3"}}, {ID: "p2", ZOrder: 1}}},
+		{Number: 2},
+		{Number: 3, Panels: []domain.Panel{{ID: "p1"}, {ID: "p2", ZOrder: 1, BeatIDs: []string{"b:5"}}}},
+		{Number: 4},
+	}}}}
+	rep := ComputePacingReport(p, sc)
+	if len(rep.Issues) != 1 {
+		t.Fatalf("expected one issue, got %d", len(rep.Issues))
+	}
+	ip := rep.Issues[0]
+	if ip.TotalBeats != 2 || ip.AvgBeatsPerPage != 0.5 || ip.UnmappedBeats != 3 {
+		t.Fatalf("unexpected summary: %+v", ip)
+	}
+	if ip.LongestRunWithoutTurnBeat != 2 || ip.RunStart != 1 {
+		t.Fatalf("unexpected run: %d from page %d", ip.LongestRunWithoutTurnBeat, ip.RunStart)
+	}
+	want := []PagePacing{
+		{PageNumber: 1, Beats: 1, IsTurn: true, UnmappedBeats: 2}, // A before the first mapped beat, C after B
+		{PageNumber: 2},
+		{PageNumber: 3, Beats: 1, IsTurn: true, LastPanelHasBeats: true, UnmappedBeats: 1},
+		{PageNumber: 4},
+	}
+	for i, w := range want {
+		if ip.Pages[i] != w {
+			t.Fatalf("page %d: got %+v want %+v", i+1, ip.Pages[i], w)
+		}
+	}
+	if !ip.Pages[2].TurnBeat() || ip.Pages[0].TurnBeat() {
+		t.Fatalf("turn beats: %+v", ip.Pages)
+	}
+
+	var buf bytes.Buffer
+	if err := rep.WriteCSV(&buf); err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 || lines[0] != "issue,page,beats,turn,turn_beat,unmapped_beats" || lines[3] != "1,3,1,true,true,1" {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+
+	// Without a script, only the panel links count.
+	if ip := ComputePacingReport(p, script.Script{}).Issues[0]; ip.UnmappedBeats != 0 || ip.Pages[0].UnmappedBeats != 0 || ip.TotalBeats != 2 {
+		t.Fatalf("unexpected report without script: %+v", ip)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"gocomicwriter/internal/domain"
+)
+
+// CanonicalPageJSON serializes a page, followed by any other inputs its rendering depends on (trim
+// size, border style, …), in a stable form: object keys sorted, null values and empty arrays and
+// objects left out (so a nil and an empty slice are the same), and numbers in their shortest form with
+// -0 as 0. Equal content therefore serializes equally however it was built or loaded.
+func CanonicalPageJSON(pg domain.Page, extra ...any) ([]byte, error) {
+	parts := append([]any{pg}, extra...)
+	b, err := json.Marshal(parts)
+	if err != nil {
+		return nil, fmt.Errorf("serialize page %d: %w", pg.Number, err)
+	}
+	v, err := decodeJSONValue(b)
+	if err != nil {
+		return nil, fmt.Errorf("serialize page %d: %w", pg.Number, err)
+	}
+	return canonicalJSON(normalizeJSON(v)), nil
+}
+
+// PageContentHash is the hash of CanonicalPageJSON, used to key previews by what they show. It is ""
+// when the page cannot be serialized, which never matches a stored preview's hash.
+func PageContentHash(pg domain.Page, extra ...any) string {
+	b, err := CanonicalPageJSON(pg, extra...)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeJSON applies the rules of CanonicalPageJSON to a decoded value. Array elements are kept even
+// when empty so positions stay meaningful.
+func normalizeJSON(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			if n := normalizeJSON(e); !emptyJSON(n) {
+				out[k] = n
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			out[i] = normalizeJSON(e)
+		}
+		return out
+	case json.Number:
+		f, err := t.Float64()
+		if err != nil {
+			return t
+		}
+		if f == 0 {
+			f = 0 // -0
+		}
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return v
+}
+
+func emptyJSON(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		return len(t) == 0
+	case []any:
+		return len(t) == 0
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestPageContentHashIsStable(t *testing.T) {
+	page := func() domain.Page {
+		return domain.Page{Number: 3, Panels: []domain.Panel{{
+			ID: "p1", Geometry: domain.Rect{X: 10, Y: 20, Width: 100, Height: 50},
+			Annotations: &domain.PanelAnnotations{Status: map[string]bool{"pencils": true, "inks": false, "colors": true}},
+		}}}
+	}
+	base := page()
+	want := PageContentHash(base, 210.0, 297.0)
+	if want == "" || PageContentHash(page(), 210.0, 297.0) != want {
+		t.Fatalf("equal pages must hash equally")
+	}
+
+	// Map insertion order does not matter.
+	for i := 0; i < 20; i++ {
+		p := page()
+		p.Panels[0].Annotations.Status = map[string]bool{}
+		for _, k := range []string{"colors", "inks", "pencils"}[i%3:] {
+			p.Panels[0].Annotations.Status[k] = base.Panels[0].Annotations.Status[k]
+		}
+		for _, k := range []string{"colors", "inks", "pencils"}[:i%3] {
+			p.Panels[0].Annotations.Status[k] = base.Panels[0].Annotations.Status[k]
+		}
+		if PageContentHash(p, 210.0, 297.0) != want {
+			t.Fatalf("map order changed the hash")
+		}
+	}
+
+	// A page decoded from JSON with other key order, whitespace and number spellings matches.
+	raw, _ := json.Marshal(base)
+	var generic map[string]any
+	_ = json.Unmarshal(raw, &generic)
+	reencoded, _ := json.MarshalIndent(generic, "", "   ")
+	var decoded domain.Page
+	if err := json.Unmarshal(reencoded, &decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if PageContentHash(decoded, 210.0, 297.0) != want {
+		t.Fatalf("round trip through JSON changed the hash")
+	}
+	if PageContentHash(base, 210, 297) != want {
+		t.Fatalf("int and float extras should serialize alike")
+	}
+
+	// nil and empty slices, and -0 and 0, render alike.
+	a, b := domain.Page{Number: 1}, domain.Page{Number: 1, Panels: []domain.Panel{}}
+	if PageContentHash(a) != PageContentHash(b) {
+		t.Fatalf("nil and empty panels changed the hash")
+	}
+	a, b = page(), page()
+	a.Panels[0].Geometry.X = 0
+	b.Panels[0].Geometry.X = math.Copysign(0, -1)
+	if PageContentHash(a) != PageContentHash(b) {
+		t.Fatalf("-0 changed the hash")
+	}
+
+	// Real changes do change it.
+	moved := page()
+	moved.Panels[0].Geometry.X++
+	if PageContentHash(moved, 210.0, 297.0) == want || PageContentHash(base, 216.0, 279.0) == want {
+		t.Fatalf("content changes must change the hash")
+	}
+	if got, _ := CanonicalPageJSON(domain.Page{Number: 1}); string(got) != `[{"number":1}]` {
+		t.Fatalf("unexpected canonical form %s", got)
+	}
+}
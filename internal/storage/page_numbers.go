@@ -0,0 +1,211 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// PageNumberProblem lists what is wrong with the page numbers of one issue. Manifests edited by hand
+// or written by older versions may use a number twice or skip one; both confuse the page list,
+// EnsurePage and the pacing overview.
+type PageNumberProblem struct {
+	IssueIndex int
+	Duplicates []int // numbers used by more than one page, ascending
+	Gaps       []int // numbers missing between 1 and the highest page number, ascending
+	Invalid    []int // numbers below 1
+}
+
+// String describes the problem for a prompt, e.g. "page 3 is used twice; page 2 is missing".
+func (p PageNumberProblem) String() string {
+	var parts []string
+	for _, n := range p.Duplicates {
+		parts = append(parts, fmt.Sprintf("page %d is used more than once", n))
+	}
+	if len(p.Gaps) > 0 {
+		parts = append(parts, fmt.Sprintf("%s %s missing", pageList(p.Gaps), isAre(len(p.Gaps))))
+	}
+	if len(p.Invalid) > 0 {
+		parts = append(parts, fmt.Sprintf("%s %s not positive", pageList(p.Invalid), isAre(len(p.Invalid))))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func pageList(ns []int) string {
+	s := make([]string, len(ns))
+	for i, n := range ns {
+		s[i] = fmt.Sprint(n)
+	}
+	if len(ns) == 1 {
+		return "page " + s[0]
+	}
+	return "pages " + strings.Join(s, ", ")
+}
+
+func isAre(n int) string {
+	if n == 1 {
+		return "is"
+	}
+	return "are"
+}
+
+// PageRenumber records a page that NormalizePages gave a new number.
+type PageRenumber struct {
+	IssueIndex int
+	Position   int // index of the page in the issue's page list
+	OldNumber  int
+	NewNumber  int
+}
+
+// FindPageNumberProblems returns the issues whose pages do not use each number from 1 to the page
+// count exactly once. The project is not modified.
+func FindPageNumberProblems(p domain.Project) []PageNumberProblem {
+	var out []PageNumberProblem
+	for ii, iss := range p.Issues {
+		if prob, ok := pageNumberProblem(ii, iss); ok {
+			out = append(out, prob)
+		}
+	}
+	return out
+}
+
+func pageNumberProblem(issueIndex int, iss domain.Issue) (PageNumberProblem, bool) {
+	prob := PageNumberProblem{IssueIndex: issueIndex}
+	count := map[int]int{}
+	maxN := 0
+	for _, pg := range iss.Pages {
+		if pg.Number < 1 {
+			prob.Invalid = append(prob.Invalid, pg.Number)
+			continue
+		}
+		count[pg.Number]++
+		if count[pg.Number] == 2 {
+			prob.Duplicates = append(prob.Duplicates, pg.Number)
+		}
+		maxN = max(maxN, pg.Number)
+	}
+	for n := 1; n < maxN; n++ {
+		if count[n] == 0 {
+			prob.Gaps = append(prob.Gaps, n)
+		}
+	}
+	sort.Ints(prob.Duplicates)
+	sort.Ints(prob.Invalid)
+	return prob, len(prob.Duplicates)+len(prob.Gaps)+len(prob.Invalid) > 0
+}
+
+// NormalizePages renumbers the pages of every issue with page number problems 1..n in the order they
+// appear in the manifest, which is deterministic and keeps the author's page order. References by
+// page number follow their pages: spread partners and the targets of page, panel and balloon comments.
+// Where a duplicated number makes a reference ambiguous, a comment goes to the page holding its panel
+// (or the first page with that number) and a spread partner to the neighbouring page with that
+// number. Spreads that no longer pair adjacent pages are split and reported. Issues without problems
+// are left alone. The change is in memory only; call Save to persist it.
+func NormalizePages(p *domain.Project) ([]PageRenumber, []SpreadWarning) {
+	if p == nil {
+		return nil, nil
+	}
+	var renums []PageRenumber
+	var warns []SpreadWarning
+	for ii := range p.Issues {
+		iss := &p.Issues[ii]
+		if _, bad := pageNumberProblem(ii, *iss); !bad {
+			continue
+		}
+		// Positions of the pages that carried each old number, in manifest order
+		byOld := map[int][]int{}
+		for i, pg := range iss.Pages {
+			byOld[pg.Number] = append(byOld[pg.Number], i)
+		}
+		for i := range iss.Pages {
+			pg := &iss.Pages[i]
+			if pg.SpreadWith != 0 {
+				if at, ok := nearestPosition(byOld[pg.SpreadWith], i); ok {
+					pg.SpreadWith = at + 1
+				}
+			}
+			if pg.Number != i+1 {
+				renums = append(renums, PageRenumber{IssueIndex: ii, Position: i, OldNumber: pg.Number, NewNumber: i + 1})
+			}
+		}
+		for ci := range p.Comments {
+			t := &p.Comments[ci].Target
+			if t.IssueIndex != ii || (t.Kind != "page" && t.Kind != "panel" && t.Kind != "balloon") {
+				continue
+			}
+			if at, ok := commentPosition(*iss, byOld[t.PageNumber], t.PanelID); ok {
+				t.PageNumber = at + 1
+			}
+		}
+		for i := range iss.Pages {
+			iss.Pages[i].Number = i + 1
+		}
+		warns = append(warns, NormalizeSpreads(iss)...)
+	}
+	return renums, warns
+}
+
+// nearestPosition picks the candidate closest to position i, i.e. the facing page of a spread.
+func nearestPosition(candidates []int, i int) (int, bool) {
+	best, found := 0, false
+	for _, c := range candidates {
+		if c == i {
+			continue
+		}
+		if !found || abs(c-i) < abs(best-i) {
+			best, found = c, true
+		}
+	}
+	return best, found
+}
+
+// commentPosition picks the candidate page holding panelID, or the first candidate.
+func commentPosition(iss domain.Issue, candidates []int, panelID string) (int, bool) {
+	if len(candidates) == 0 {
+		return 0, false
+	}
+	if panelID != "" {
+		for _, c := range candidates {
+			for _, pn := range iss.Pages[c].Panels {
+				if pn.ID == panelID {
+					return c, true
+				}
+			}
+		}
+	}
+	return candidates[0], true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// RepairPageNumbers runs NormalizePages on the project and validates it again, so ph.Validation no
+// longer lists the repaired numbers. A note about a backup opened in place of the manifest is kept.
+func RepairPageNumbers(ph *ProjectHandle) ([]PageRenumber, []SpreadWarning) {
+	if ph == nil {
+		return nil, nil
+	}
+	renums, warns := NormalizePages(&ph.Project)
+	var kept []ValidationIssue
+	for _, v := range ph.Validation {
+		if v.Path == ManifestFileName {
+			kept = append(kept, v)
+		}
+	}
+	ph.Validation = append(kept, ValidateProject(ph.Project)...)
+	return renums, warns
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+// brokenNumbersManifest has no page 2 and two pages numbered 3. The second page 3 forms a spread with
+// page 4, and comments point at both pages 3.
+const brokenNumbersManifest = `{
+  "name": "Broken numbers",
+  "issues": [{
+    "trimWidth": 612, "trimHeight": 792, "bleed": 9, "dpi": 300, "readingDirection": "ltr",
+    "pages": [
+      {"number": 1, "panels": [{"id": "p1", "geometry": {"x": 10, "y": 10, "width": 100, "height": 100}, "zOrder": 0, "linkedBeats": ["b:1"], "notes": "opening"}]},
+      {"number": 3, "panels": [{"id": "p2", "geometry": {"x": 10, "y": 10, "width": 100, "height": 100}, "zOrder": 0, "linkedBeats": ["b:2"], "notes": "first three"}]},
+      {"number": 3, "spreadWith": 4, "panels": [{"id": "p3", "geometry": {"x": 10, "y": 10, "width": 100, "height": 100}, "zOrder": 0, "linkedBeats": ["b:3"], "notes": "second three"}]},
+      {"number": 4, "spreadWith": 3, "panels": [{"id": "p4", "geometry": {"x": 10, "y": 10, "width": 100, "height": 100}, "zOrder": 0, "linkedBeats": ["b:4"], "notes": "four"}]}
+    ]
+  }],
+  "comments": [
+    {"id": "c1", "body": "on the first page 3", "target": {"kind": "page", "pageNumber": 3}, "status": "open", "createdAt": "2025-01-01T00:00:00Z"},
+    {"id": "c2", "body": "on panel p3", "target": {"kind": "panel", "pageNumber": 3, "panelId": "p3"}, "status": "open", "createdAt": "2025-01-01T00:00:00Z"},
+    {"id": "c3", "body": "on page 4", "target": {"kind": "page", "pageNumber": 4}, "status": "open", "createdAt": "2025-01-01T00:00:00Z"}
+  ]
+}`
+
+func TestFindPageNumberProblems(t *testing.T) {
+	p := domain.Project{Issues: []domain.Issue{
+		{Pages: []domain.Page{{Number: 1}, {Number: 2}}},
+		{Pages: []domain.Page{{Number: 1}, {Number: 3}, {Number: 3}, {Number: 6}, {Number: 0}}},
+	}}
+	got := FindPageNumberProblems(p)
+	want := []PageNumberProblem{{IssueIndex: 1, Duplicates: []int{3}, Gaps: []int{2, 4, 5}, Invalid: []int{0}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("problems = %+v, want %+v", got, want)
+	}
+	if s := got[0].String(); s != "page 3 is used more than once; pages 2, 4, 5 are missing; page 0 is not positive" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestRepairPageNumbers_DuplicateAndGap(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ManifestFileName), []byte(brokenNumbersManifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ph, err := Open(root)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	var dup, gap bool
+	for _, v := range ph.Validation {
+		dup = dup || (v.Severity == SeverityError && strings.Contains(v.Message, "page number 3 is also used"))
+		gap = gap || (v.Severity == SeverityWarning && v.Path == "issues[0].pages" && strings.Contains(v.Message, "page 2 is missing"))
+	}
+	if !dup || !gap {
+		t.Fatalf("open did not report the duplicate and the gap: %v", ph.Validation)
+	}
+	// Opening reports the problems but does not renumber anything
+	if n := ph.Project.Issues[0].Pages[2].Number; n != 3 {
+		t.Fatalf("open renumbered pages on its own: page 3 became %d", n)
+	}
+
+	renums, warns := RepairPageNumbers(ph)
+	// Only pages whose number changed are listed
+	wantRenums := []PageRenumber{{IssueIndex: 0, Position: 1, OldNumber: 3, NewNumber: 2}}
+	if !reflect.DeepEqual(renums, wantRenums) || len(warns) != 0 {
+		t.Fatalf("repair = %+v, warnings %+v", renums, warns)
+	}
+	if len(ph.Validation) != 0 {
+		t.Fatalf("validation after repair: %v", ph.Validation)
+	}
+	if err := Save(ph); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	ph, err = Open(root)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if len(ph.Validation) != 0 || len(FindPageNumberProblems(ph.Project)) != 0 {
+		t.Fatalf("repaired manifest still has problems: %v", ph.Validation)
+	}
+	iss := ph.Project.Issues[0]
+	var pages []string
+	for _, pg := range iss.Pages {
+		pages = append(pages, pg.Panels[0].ID)
+		if pg.Number != len(pages) {
+			t.Errorf("page with %s has number %d", pg.Panels[0].ID, pg.Number)
+		}
+	}
+	if !reflect.DeepEqual(pages, []string{"p1", "p2", "p3", "p4"}) {
+		t.Errorf("page order changed: %v", pages)
+	}
+	if iss.Pages[2].SpreadWith != 4 || iss.Pages[3].SpreadWith != 3 || iss.Pages[1].SpreadWith != 0 {
+		t.Errorf("spread not kept on pages 3 and 4: %+v", iss.Pages)
+	}
+	targets := map[string]int{}
+	for _, c := range ph.Project.Comments {
+		targets[c.ID] = c.Target.PageNumber
+	}
+	if !reflect.DeepEqual(targets, map[string]int{"c1": 2, "c2": 3, "c3": 4}) {
+		t.Errorf("comment targets = %v", targets)
+	}
+
+	// Beat mappings moved with their panels and can be changed by page number again
+	cov := ComputeBeatCoverage(ph.Project)
+	for i, beat := range []string{"b:1", "b:2", "b:3", "b:4"} {
+		if cov[i].PageNumber != i+1 || cov[i].PanelBeatCounts[pages[i]] != 1 {
+			t.Errorf("beat coverage of page %d = %+v, want %s on %s", i+1, cov[i], beat, pages[i])
+		}
+	}
+	if err := MapBeatToPanel(ph, 2, "p2", "b:5"); err != nil {
+		t.Errorf("map beat on repaired page 2: %v", err)
+	}
+
+	// Every panel path of the search index resolves to exactly one panel
+	seen := map[string]bool{}
+	for _, d := range ManifestDocuments(ph.Project) {
+		if d.Type != "panel_notes" {
+			continue
+		}
+		if seen[d.Path] {
+			t.Errorf("path %s is used twice", d.Path)
+		}
+		seen[d.Path] = true
+		var page int
+		var panel string
+		if _, err := fmt.Sscanf(d.Path, "issue:1/page:%d/panel:%s", &page, &panel); err != nil {
+			t.Fatalf("parse %s: %v", d.Path, err)
+		}
+		if _, _, _, err := findPanel(ph, page, panel); err != nil {
+			t.Errorf("path %s does not resolve: %v", d.Path, err)
+		}
+	}
+	if len(seen) != 4 {
+		t.Errorf("panel paths = %v", seen)
+	}
+}
+
+func TestNormalizePages_LeavesGoodIssuesAlone(t *testing.T) {
+	p := domain.Project{Issues: []domain.Issue{
+		{Pages: []domain.Page{{Number: 2}, {Number: 1}}},
+		{Pages: []domain.Page{{Number: 5}, {Number: 7, SpreadWith: 8}, {Number: 8, SpreadWith: 7}}},
+	}, Comments: []domain.Comment{{ID: "c", Target: domain.CommentTarget{Kind: "page", IssueIndex: 0, PageNumber: 2}}}}
+	renums, _ := NormalizePages(&p)
+	if len(renums) != 3 || renums[0].IssueIndex != 1 {
+		t.Fatalf("renumbered = %+v", renums)
+	}
+	if p.Issues[0].Pages[0].Number != 2 || p.Comments[0].Target.PageNumber != 2 {
+		t.Errorf("issue without problems was changed: %+v", p.Issues[0].Pages)
+	}
+	if got := p.Issues[1].Pages; got[1].Number != 2 || got[1].SpreadWith != 3 || got[2].SpreadWith != 2 {
+		t.Errorf("renumbered issue = %+v", got)
+	}
+}
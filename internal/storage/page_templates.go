@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// PageTemplateFromPage captures the panel layout of the page numbered pageNumber as a template named
+// name. Panel rectangles are stored in zOrder as fractions of the issue's trim box; panels without a
+// size are left out.
+func PageTemplateFromPage(iss domain.Issue, pageNumber int, name string) (domain.PageTemplate, error) {
+	t := domain.PageTemplate{Name: strings.TrimSpace(name)}
+	if t.Name == "" {
+		return t, errors.New("template name is required")
+	}
+	if iss.TrimWidth <= 0 || iss.TrimHeight <= 0 {
+		return t, errors.New("the issue has no trim size; set it in Issue Setup first")
+	}
+	i := pageIndexByNumber(iss, pageNumber)
+	if i < 0 {
+		return t, pageNotFound(pageNumber)
+	}
+	panels := append([]domain.Panel(nil), iss.Pages[i].Panels...)
+	sort.SliceStable(panels, func(a, b int) bool { return panels[a].ZOrder < panels[b].ZOrder })
+	for _, p := range panels {
+		g := p.Geometry
+		if g.Width <= 0 || g.Height <= 0 {
+			continue
+		}
+		t.Panels = append(t.Panels, domain.Rect{
+			X:      roundTo(g.X/iss.TrimWidth, 1e6),
+			Y:      roundTo(g.Y/iss.TrimHeight, 1e6),
+			Width:  roundTo(g.Width/iss.TrimWidth, 1e6),
+			Height: roundTo(g.Height/iss.TrimHeight, 1e6),
+		})
+	}
+	if len(t.Panels) == 0 {
+		return t, fmt.Errorf("page %d has no panels to save as a template", pageNumber)
+	}
+	return t, nil
+}
+
+// ValidatePageTemplate checks that the template has a name and at least one panel, and that every
+// panel has a positive, finite size.
+func ValidatePageTemplate(t domain.PageTemplate) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("template name is required")
+	}
+	if len(t.Panels) == 0 {
+		return fmt.Errorf("page template %q has no panels", t.Name)
+	}
+	for i, r := range t.Panels {
+		for _, v := range []float64{r.X, r.Y, r.Width, r.Height} {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return fmt.Errorf("page template %q: panel %d has an invalid geometry", t.Name, i+1)
+			}
+		}
+		if r.Width <= 0 || r.Height <= 0 {
+			return fmt.Errorf("page template %q: panel %d has no size", t.Name, i+1)
+		}
+	}
+	return nil
+}
+
+// FindPageTemplate returns the template with the given name (case-insensitive).
+func FindPageTemplate(ph *ProjectHandle, name string) (domain.PageTemplate, bool) {
+	if ph == nil {
+		return domain.PageTemplate{}, false
+	}
+	if i := pageTemplateIndex(ph.Project.PageTemplates, name); i >= 0 {
+		return ph.Project.PageTemplates[i], true
+	}
+	return domain.PageTemplate{}, false
+}
+
+// PutPageTemplate adds the template or replaces an existing one with the same name.
+// The change is in memory only; call Save to persist it.
+func PutPageTemplate(ph *ProjectHandle, t domain.PageTemplate) error {
+	if ph == nil {
+		return errors.New("nil ProjectHandle")
+	}
+	t.Name = strings.TrimSpace(t.Name)
+	if err := ValidatePageTemplate(t); err != nil {
+		return err
+	}
+	t.Panels = append([]domain.Rect(nil), t.Panels...)
+	if i := pageTemplateIndex(ph.Project.PageTemplates, t.Name); i >= 0 {
+		ph.Project.PageTemplates[i] = t
+		return nil
+	}
+	ph.Project.PageTemplates = append(ph.Project.PageTemplates, t)
+	return nil
+}
+
+// DeletePageTemplate removes the named template. The change is in memory only; call Save to persist it.
+func DeletePageTemplate(ph *ProjectHandle, name string) error {
+	if ph == nil {
+		return errors.New("nil ProjectHandle")
+	}
+	i := pageTemplateIndex(ph.Project.PageTemplates, name)
+	if i < 0 {
+		return fmt.Errorf("page template %q not found", name)
+	}
+	ph.Project.PageTemplates = append(ph.Project.PageTemplates[:i], ph.Project.PageTemplates[i+1:]...)
+	return nil
+}
+
+// MergePageTemplates adds the templates whose names the project does not use yet, e.g. from a style
+// pack. Existing templates are kept, like existing files when a pack is installed. It returns the
+// names of the added templates and of the skipped ones (existing or invalid).
+// The change is in memory only; call Save to persist it.
+func MergePageTemplates(ph *ProjectHandle, list []domain.PageTemplate) (added, skipped []string) {
+	if ph == nil {
+		return nil, nil
+	}
+	for _, t := range list {
+		t.Name = strings.TrimSpace(t.Name)
+		if pageTemplateIndex(ph.Project.PageTemplates, t.Name) >= 0 || PutPageTemplate(ph, t) != nil {
+			skipped = append(skipped, t.Name)
+			continue
+		}
+		added = append(added, t.Name)
+	}
+	return added, skipped
+}
+
+// ApplyPageTemplate creates the template's panels on the page numbered pageNumber of iss, scaled to
+// the issue's trim size, and returns them. With replace the page's panels (and their lettering) are
+// removed first, which fails with ErrPanelLocked while one of them is locked; otherwise the new panels
+// are stacked above the existing ones. iss must belong to ph, which hands out the panel IDs.
+func ApplyPageTemplate(ph *ProjectHandle, iss *domain.Issue, pageNumber int, t domain.PageTemplate, replace bool) ([]domain.Panel, error) {
+	if ph == nil || iss == nil {
+		return nil, errors.New("nil ProjectHandle or issue")
+	}
+	if err := ValidatePageTemplate(t); err != nil {
+		return nil, err
+	}
+	if iss.TrimWidth <= 0 || iss.TrimHeight <= 0 {
+		return nil, errors.New("the issue has no trim size; set it in Issue Setup first")
+	}
+	i := pageIndexByNumber(*iss, pageNumber)
+	if i < 0 {
+		return nil, pageNotFound(pageNumber)
+	}
+	pg := &iss.Pages[i]
+	if replace {
+		for _, p := range pg.Panels {
+			if p.Locked {
+				return nil, panelLocked(p.ID, pageNumber)
+			}
+		}
+		pg.Panels = []domain.Panel{}
+	}
+	nextZ := 0
+	for _, p := range pg.Panels {
+		if p.ZOrder >= nextZ {
+			nextZ = p.ZOrder + 1
+		}
+	}
+	created := make([]domain.Panel, 0, len(t.Panels))
+	for _, r := range t.Panels {
+		p := domain.Panel{
+			ID: NewPanelID(ph),
+			Geometry: domain.Rect{
+				X:      roundTo(r.X*iss.TrimWidth, 100),
+				Y:      roundTo(r.Y*iss.TrimHeight, 100),
+				Width:  roundTo(r.Width*iss.TrimWidth, 100),
+				Height: roundTo(r.Height*iss.TrimHeight, 100),
+			},
+			ZOrder: nextZ,
+		}
+		nextZ++
+		pg.Panels = append(pg.Panels, p)
+		created = append(created, p)
+	}
+	return created, nil
+}
+
+func pageTemplateIndex(list []domain.PageTemplate, name string) int {
+	name = strings.TrimSpace(name)
+	for i, t := range list {
+		if strings.EqualFold(t.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// roundTo rounds v to 1/scale, which keeps stored fractions and points free of float noise.
+func roundTo(v, scale float64) float64 {
+	return math.Round(v*scale) / scale
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func templateProject() *ProjectHandle {
+	return &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{
+		TrimWidth: 400, TrimHeight: 600,
+		Pages: []domain.Page{
+			{Number: 1, Panels: []domain.Panel{
+				{ID: "p2", ZOrder: 1, Geometry: domain.Rect{X: 0, Y: 300, Width: 400, Height: 300}},
+				{ID: "p1", ZOrder: 0, Geometry: domain.Rect{X: -10, Y: 0, Width: 200, Height: 300}},
+				{ID: "empty", ZOrder: 2},
+			}},
+			{Number: 2, Panels: []domain.Panel{{ID: "p3", Geometry: domain.Rect{X: 10, Y: 10, Width: 50, Height: 50},
+				Balloons: []domain.Balloon{{ID: "b1"}}}}},
+		},
+	}}, IDCounters: map[string]int{IDKindPanel: 3}}}
+}
+
+func TestPageTemplateFromPage_NormalizesToTrimBox(t *testing.T) {
+	ph := templateProject()
+	tpl, err := PageTemplateFromPage(ph.Project.Issues[0], 1, " Two tiers ")
+	if err != nil {
+		t.Fatalf("from page: %v", err)
+	}
+	want := domain.PageTemplate{Name: "Two tiers", Panels: []domain.Rect{
+		{X: -0.025, Y: 0, Width: 0.5, Height: 0.5},
+		{X: 0, Y: 0.5, Width: 1, Height: 0.5},
+	}}
+	if !reflect.DeepEqual(tpl, want) {
+		t.Fatalf("template = %+v, want %+v", tpl, want)
+	}
+	if _, err := PageTemplateFromPage(ph.Project.Issues[0], 9, "x"); !errors.Is(err, ErrPageNotFound) {
+		t.Errorf("missing page: %v", err)
+	}
+	if _, err := PageTemplateFromPage(domain.Issue{Pages: ph.Project.Issues[0].Pages}, 1, "x"); err == nil {
+		t.Errorf("expected an error without a trim size")
+	}
+	if _, err := PageTemplateFromPage(ph.Project.Issues[0], 1, "  "); err == nil {
+		t.Errorf("expected an error without a name")
+	}
+}
+
+func TestApplyPageTemplate_ScalesToTrimSizeAndMergesOrReplaces(t *testing.T) {
+	ph := templateProject()
+	tpl, err := PageTemplateFromPage(ph.Project.Issues[0], 1, "Two tiers")
+	if err != nil {
+		t.Fatalf("from page: %v", err)
+	}
+	// A different trim size after saving the template: the layout scales with it
+	iss := &ph.Project.Issues[0]
+	iss.TrimWidth, iss.TrimHeight = 500, 800
+
+	created, err := ApplyPageTemplate(ph, iss, 2, tpl, false)
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if len(created) != 2 || created[0].ID != "p4" || created[1].ID != "p5" {
+		t.Fatalf("created = %+v", created)
+	}
+	if g := created[0].Geometry; g != (domain.Rect{X: -12.5, Y: 0, Width: 250, Height: 400}) {
+		t.Errorf("first panel geometry = %+v", g)
+	}
+	if g := created[1].Geometry; g != (domain.Rect{X: 0, Y: 400, Width: 500, Height: 400}) {
+		t.Errorf("second panel geometry = %+v", g)
+	}
+	pg := iss.Pages[1]
+	if len(pg.Panels) != 3 || pg.Panels[0].ID != "p3" || pg.Panels[1].ZOrder != 1 || pg.Panels[2].ZOrder != 2 {
+		t.Fatalf("merged page = %+v", pg.Panels)
+	}
+
+	if _, err := ApplyPageTemplate(ph, iss, 2, tpl, true); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	pg = iss.Pages[1]
+	if len(pg.Panels) != 2 || pg.Panels[0].ID != "p6" || pg.Panels[0].ZOrder != 0 || pg.Panels[1].ZOrder != 1 {
+		t.Fatalf("replaced page = %+v", pg.Panels)
+	}
+
+	if _, err := ApplyPageTemplate(ph, iss, 9, tpl, false); !errors.Is(err, ErrPageNotFound) {
+		t.Errorf("missing page: %v", err)
+	}
+	if _, err := ApplyPageTemplate(ph, iss, 2, domain.PageTemplate{Name: "empty"}, false); err == nil {
+		t.Errorf("expected an error for a template without panels")
+	}
+}
+
+func TestPageTemplatesCRUDAndMerge(t *testing.T) {
+	ph := &ProjectHandle{}
+	grid := domain.PageTemplate{Name: "Grid", Panels: []domain.Rect{{Width: 0.5, Height: 0.5}}}
+	if err := PutPageTemplate(ph, grid); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := PutPageTemplate(ph, domain.PageTemplate{Name: "GRID", Panels: []domain.Rect{{Width: 1, Height: 1}}}); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	got, ok := FindPageTemplate(ph, "grid")
+	if !ok || len(ph.Project.PageTemplates) != 1 || got.Panels[0].Width != 1 {
+		t.Fatalf("find = %+v %v; all = %+v", got, ok, ph.Project.PageTemplates)
+	}
+	for _, bad := range []domain.PageTemplate{
+		{Name: "", Panels: grid.Panels},
+		{Name: "x"},
+		{Name: "x", Panels: []domain.Rect{{Width: 0, Height: 1}}},
+	} {
+		if err := PutPageTemplate(ph, bad); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+
+	added, skipped := MergePageTemplates(ph, []domain.PageTemplate{
+		{Name: "grid", Panels: grid.Panels},
+		{Name: "Splash", Panels: []domain.Rect{{Width: 1, Height: 1}}},
+		{Name: "Broken"},
+	})
+	if !reflect.DeepEqual(added, []string{"Splash"}) || !reflect.DeepEqual(skipped, []string{"grid", "Broken"}) {
+		t.Fatalf("merge: added %v, skipped %v", added, skipped)
+	}
+	if got, _ := FindPageTemplate(ph, "Grid"); got.Panels[0].Width != 1 {
+		t.Errorf("merge replaced an existing template: %+v", got)
+	}
+
+	if err := DeletePageTemplate(ph, "splash"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := DeletePageTemplate(ph, "splash"); err == nil {
+		t.Fatalf("expected error deleting a missing template")
+	}
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+
+	"gocomicwriter/internal/domain"
+)
+
+// ResolvePanelBorder merges a panel's border override over the project default. Fields left unset by
+// both stay unset, so exporters can fill them from their own defaults; an empty Style means solid.
+func ResolvePanelBorder(projectDefault, panel *domain.PanelBorder) domain.PanelBorder {
+	var out domain.PanelBorder
+	for _, b := range []*domain.PanelBorder{projectDefault, panel} {
+		if b == nil {
+			continue
+		}
+		if b.Color != nil {
+			c := *b.Color
+			out.Color = &c
+		}
+		if b.Width > 0 {
+			out.Width = b.Width
+		}
+		if b.Style != "" {
+			out.Style = b.Style
+		}
+	}
+	return out
+}
+
+// ValidatePanelBorder rejects a negative width and unknown styles. A nil border is valid.
+func ValidatePanelBorder(b *domain.PanelBorder) error {
+	if b == nil {
+		return nil
+	}
+	if b.Width < 0 {
+		return fmt.Errorf("border width %g must not be negative", b.Width)
+	}
+	if b.Style != "" && b.Style != domain.PanelBorderSolid && b.Style != domain.PanelBorderNone {
+		return fmt.Errorf("unknown border style %q; use solid or none", b.Style)
+	}
+	return nil
+}
+
+// SetPanelBorder replaces the border override of a panel; nil or an empty border makes the panel use
+// the project default again.
+func SetPanelBorder(ph *ProjectHandle, pageNumber int, panelID string, b *domain.PanelBorder) error {
+	if err := ValidatePanelBorder(b); err != nil {
+		return err
+	}
+	_, _, pn, err := findPanel(ph, pageNumber, panelID)
+	if err != nil {
+		return err
+	}
+	pn.Border = cloneBorder(b)
+	return nil
+}
+
+// SetProjectPanelBorder replaces the project's default panel border; nil restores the built-in default.
+func SetProjectPanelBorder(ph *ProjectHandle, b *domain.PanelBorder) error {
+	if ph == nil {
+		return fmt.Errorf("project handle is nil")
+	}
+	if err := ValidatePanelBorder(b); err != nil {
+		return err
+	}
+	ph.Project.PanelBorder = cloneBorder(b)
+	return nil
+}
+
+// cloneBorder copies b, returning nil for nil or a border with no field set.
+func cloneBorder(b *domain.PanelBorder) *domain.PanelBorder {
+	if b == nil || (b.Color == nil && b.Width == 0 && b.Style == "") {
+		return nil
+	}
+	c := ResolvePanelBorder(nil, b)
+	return &c
+}
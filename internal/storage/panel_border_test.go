@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestResolvePanelBorder_PanelOverridesProjectFieldByField(t *testing.T) {
+	red := domain.Color{R: 255, A: 255}
+	def := &domain.PanelBorder{Color: &red, Width: 2}
+	got := ResolvePanelBorder(def, &domain.PanelBorder{Width: 4})
+	if got.Color == nil || *got.Color != red || got.Width != 4 || got.Style != "" {
+		t.Fatalf("merged border: %+v", got)
+	}
+	if got.Color == def.Color {
+		t.Fatalf("color pointer shared with the project default")
+	}
+	got = ResolvePanelBorder(def, &domain.PanelBorder{Style: domain.PanelBorderNone})
+	if got.Style != domain.PanelBorderNone || got.Width != 2 {
+		t.Fatalf("style override: %+v", got)
+	}
+	if got := ResolvePanelBorder(nil, nil); got.Color != nil || got.Width != 0 || got.Style != "" {
+		t.Fatalf("no settings: %+v", got)
+	}
+}
+
+func TestSetPanelBorder_ValidatesAndClears(t *testing.T) {
+	ph := gridPage()
+	if err := SetPanelBorder(ph, 1, "r0c0", &domain.PanelBorder{Style: "dashed"}); err == nil {
+		t.Fatalf("unknown style accepted")
+	}
+	if err := SetPanelBorder(ph, 1, "r0c0", &domain.PanelBorder{Width: -1}); err == nil {
+		t.Fatalf("negative width accepted")
+	}
+	if err := SetPanelBorder(ph, 1, "nope", &domain.PanelBorder{Width: 1}); err == nil {
+		t.Fatalf("missing panel accepted")
+	}
+	if err := SetPanelBorder(ph, 1, "r0c0", &domain.PanelBorder{Width: 3}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if b := ph.Project.Issues[0].Pages[0].Panels[0].Border; b == nil || b.Width != 3 {
+		t.Fatalf("border not set: %+v", b)
+	}
+	if err := SetPanelBorder(ph, 1, "r0c0", &domain.PanelBorder{}); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if b := ph.Project.Issues[0].Pages[0].Panels[0].Border; b != nil {
+		t.Fatalf("empty border kept: %+v", b)
+	}
+	if err := SetProjectPanelBorder(ph, &domain.PanelBorder{Style: domain.PanelBorderNone}); err != nil || ph.Project.PanelBorder == nil {
+		t.Fatalf("project default: %v %+v", err, ph.Project.PanelBorder)
+	}
+}
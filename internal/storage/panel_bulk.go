@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"gocomicwriter/internal/domain"
+)
+
+// ErrPanelsOverlap is returned by DistributePanels when the selected panels are longer in total than the
+// span they cover along the axis, i.e. evenly distributing them would still leave them overlapping.
+var ErrPanelsOverlap = errors.New("selected panels overlap along the distribution axis; select panels in one row or column")
+
+// Bulk operations work on a set of panels of one page. All panel IDs are checked before anything is
+// changed, so a failing call leaves the page untouched and the caller can treat each call as one step.
+// Unless force is set, a locked panel among them fails the call with ErrPanelLocked.
+
+// findPanels returns the page with the given number and the indexes of the given panels on it.
+// Unless force is set, a locked panel among them is an error.
+func findPanels(ph *ProjectHandle, pageNumber int, ids []string, force bool) (*domain.Page, []int, error) {
+	if len(ids) == 0 {
+		return nil, nil, fmt.Errorf("no panels selected")
+	}
+	pg, _, _, err := findPanel(ph, pageNumber, ids[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[string]int, len(pg.Panels))
+	for i, p := range pg.Panels {
+		byID[p.ID] = i
+	}
+	idx := make([]int, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		i, ok := byID[id]
+		if !ok {
+			return nil, nil, panelNotFound(id, pageNumber)
+		}
+		if !seen[id] {
+			seen[id] = true
+			idx = append(idx, i)
+		}
+	}
+	if !force {
+		for _, i := range idx {
+			if pg.Panels[i].Locked {
+				return nil, nil, panelLocked(pg.Panels[i].ID, pageNumber)
+			}
+		}
+	}
+	return pg, idx, nil
+}
+
+// DeletePanels removes the given panels from the page and renumbers the remaining panels' zOrder densely
+// from 0, keeping their relative order.
+func DeletePanels(ph *ProjectHandle, pageNumber int, ids []string, force bool) error {
+	pg, idx, err := findPanels(ph, pageNumber, ids, force)
+	if err != nil {
+		return err
+	}
+	drop := make(map[int]bool, len(idx))
+	for _, i := range idx {
+		drop[i] = true
+	}
+	kept := make([]domain.Panel, 0, len(pg.Panels)-len(idx))
+	for i, p := range pg.Panels {
+		if !drop[i] {
+			kept = append(kept, p)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].ZOrder < kept[j].ZOrder })
+	for i := range kept {
+		kept[i].ZOrder = i
+	}
+	pg.Panels = kept
+	return nil
+}
+
+// SetPanelsNotes replaces the notes of all given panels.
+func SetPanelsNotes(ph *ProjectHandle, pageNumber int, ids []string, notes string, force bool) error {
+	pg, idx, err := findPanels(ph, pageNumber, ids, force)
+	if err != nil {
+		return err
+	}
+	for _, i := range idx {
+		pg.Panels[i].Notes = notes
+	}
+	return nil
+}
+
+// OffsetPanels moves the given panels by dx/dy points. Balloons, captions and SFX use page coordinates
+// and move with their panel.
+func OffsetPanels(ph *ProjectHandle, pageNumber int, ids []string, dx, dy float64, force bool) error {
+	pg, idx, err := findPanels(ph, pageNumber, ids, force)
+	if err != nil {
+		return err
+	}
+	for _, i := range idx {
+		translatePanel(&pg.Panels[i], dx, dy)
+	}
+	return nil
+}
+
+// DistributePanels spaces the given panels evenly along one axis (horizontally when horizontal is true,
+// otherwise vertically). The outer edges of the selection stay where they are and the panels keep their
+// sizes; only the gaps between them are made equal. At least three panels are needed to change anything.
+func DistributePanels(ph *ProjectHandle, pageNumber int, ids []string, horizontal bool, force bool) error {
+	pg, idx, err := findPanels(ph, pageNumber, ids, force)
+	if err != nil {
+		return err
+	}
+	rects := make([]domain.Rect, len(idx))
+	for k, i := range idx {
+		rects[k] = pg.Panels[i].Geometry
+	}
+	out, err := DistributeRects(rects, horizontal)
+	if err != nil {
+		return err
+	}
+	for k, i := range idx {
+		translatePanel(&pg.Panels[i], out[k].X-rects[k].X, out[k].Y-rects[k].Y)
+	}
+	return nil
+}
+
+// DistributeRects returns rects moved along one axis so that the gaps between neighbours are equal.
+// Rects are ordered by their leading edge (ties by trailing edge); the selection's overall extent
+// from the smallest leading edge to the largest trailing edge is preserved, so rects of different
+// sizes get even gutters rather than evenly spaced centres. The result is in input order; the other
+// axis is unchanged. ErrPanelsOverlap is returned when the rects do not fit into their extent.
+func DistributeRects(rects []domain.Rect, horizontal bool) ([]domain.Rect, error) {
+	out := append([]domain.Rect(nil), rects...)
+	if len(rects) < 3 {
+		return out, nil
+	}
+	pos := func(r domain.Rect) (float64, float64) {
+		if horizontal {
+			return r.X, r.Width
+		}
+		return r.Y, r.Height
+	}
+	order := make([]int, len(rects))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		sa, la := pos(rects[order[a]])
+		sb, lb := pos(rects[order[b]])
+		if sa != sb {
+			return sa < sb
+		}
+		return sa+la < sb+lb
+	})
+	start, _ := pos(rects[order[0]])
+	end, total := start, 0.0
+	for _, i := range order {
+		s, l := pos(rects[i])
+		end = max(end, s+l)
+		total += l
+	}
+	gap := (end - start - total) / float64(len(rects)-1)
+	if gap < 0 {
+		return nil, ErrPanelsOverlap
+	}
+	at := start
+	for _, i := range order {
+		_, l := pos(rects[i])
+		if horizontal {
+			out[i].X = at
+		} else {
+			out[i].Y = at
+		}
+		at += l + gap
+	}
+	return out, nil
+}
+
+// translatePanel moves a panel and everything placed on it.
+func translatePanel(p *domain.Panel, dx, dy float64) {
+	move := func(r *domain.Rect) {
+		r.X += dx
+		r.Y += dy
+	}
+	move(&p.Geometry)
+	for i := range p.Balloons {
+		move(&p.Balloons[i].Shape.Rect)
+	}
+	for i := range p.Captions {
+		move(&p.Captions[i].Rect)
+	}
+	for i := range p.SFX {
+		move(&p.SFX[i].Rect)
+	}
+}
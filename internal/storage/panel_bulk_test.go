@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+// gridPage is a page with a 3x3 grid of 100x100 panels and 10pt gutters, IDs r<row>c<col>.
+func gridPage() *ProjectHandle {
+	pg := domain.Page{Number: 1}
+	z := 0
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			pg.Panels = append(pg.Panels, domain.Panel{
+				ID:       string([]byte{'r', byte('0' + r), 'c', byte('0' + c)}),
+				Geometry: domain.Rect{X: float64(c) * 110, Y: float64(r) * 110, Width: 100, Height: 100},
+				ZOrder:   z,
+			})
+			z++
+		}
+	}
+	return &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{pg}}}}}
+}
+
+func TestDistributeRects_EqualGapsForDifferentSizes(t *testing.T) {
+	rects := []domain.Rect{
+		{X: 200, Y: 5, Width: 100, Height: 10}, // rightmost, given first
+		{X: 0, Y: 0, Width: 50, Height: 10},
+		{X: 60, Y: 0, Width: 20, Height: 10},
+	}
+	got, err := DistributeRects(rects, true)
+	if err != nil {
+		t.Fatalf("DistributeRects: %v", err)
+	}
+	// Extent 0..300 holds 170pt of panels: two gaps of 65pt.
+	want := []domain.Rect{
+		{X: 200, Y: 5, Width: 100, Height: 10},
+		{X: 0, Y: 0, Width: 50, Height: 10},
+		{X: 115, Y: 0, Width: 20, Height: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if rects[2].X != 60 {
+		t.Fatalf("input modified")
+	}
+
+	got, err = DistributeRects([]domain.Rect{
+		{Y: 0, Height: 40},
+		{Y: 200, Height: 100},
+		{Y: 45, Height: 20},
+	}, false)
+	if err != nil {
+		t.Fatalf("vertical: %v", err)
+	}
+	if got[0].Y != 0 || got[2].Y != 110 || got[1].Y != 200 {
+		t.Fatalf("vertical = %+v", got)
+	}
+	// A tall panel covering the others leaves no room for gaps.
+	if _, err := DistributeRects([]domain.Rect{{Y: 0, Height: 400}, {Y: 10, Height: 50}, {Y: 300, Height: 50}}, false); !errors.Is(err, ErrPanelsOverlap) {
+		t.Fatalf("expected ErrPanelsOverlap, got %v", err)
+	}
+
+	two := []domain.Rect{{X: 0, Width: 10}, {X: 50, Width: 10}}
+	if got, err := DistributeRects(two, true); err != nil || !reflect.DeepEqual(got, two) {
+		t.Fatalf("two rects changed: %+v %v", got, err)
+	}
+}
+
+func TestDistributePanels_MovesContents(t *testing.T) {
+	ph := gridPage()
+	pg := &ph.Project.Issues[0].Pages[0]
+	pg.Panels[1].Geometry.X = 130 // r0c1 pushed right
+	pg.Panels[1].Captions = []domain.Caption{{ID: "c1", Rect: domain.Rect{X: 140, Y: 10, Width: 20, Height: 10}}}
+	if err := DistributePanels(ph, 1, []string{"r0c0", "r0c1", "r0c2"}, true, false); err != nil {
+		t.Fatalf("DistributePanels: %v", err)
+	}
+	if x := pg.Panels[1].Geometry.X; x != 110 {
+		t.Fatalf("middle panel x = %v, want 110", x)
+	}
+	if x := pg.Panels[1].Captions[0].Rect.X; x != 120 {
+		t.Fatalf("caption x = %v, want 120", x)
+	}
+	if err := DistributePanels(ph, 1, []string{"r0c0", "r1c0", "r2c0"}, true, false); !errors.Is(err, ErrPanelsOverlap) {
+		t.Fatalf("column distributed horizontally: %v", err)
+	}
+}
+
+func TestBulkPanelOps(t *testing.T) {
+	ph := gridPage()
+	pg := &ph.Project.Issues[0].Pages[0]
+	if err := DeletePanels(ph, 1, []string{"r0c1", "nope"}, false); err == nil {
+		t.Fatalf("expected error for unknown panel")
+	}
+	if len(pg.Panels) != 9 {
+		t.Fatalf("failed delete changed the page")
+	}
+	if err := DeletePanels(ph, 1, []string{"r0c1", "r1c1", "r2c1"}, false); err != nil {
+		t.Fatalf("DeletePanels: %v", err)
+	}
+	if len(pg.Panels) != 6 {
+		t.Fatalf("panels left = %d", len(pg.Panels))
+	}
+	for i, p := range pg.Panels {
+		if p.ZOrder != i {
+			t.Fatalf("zOrder not dense: %+v", pg.Panels)
+		}
+	}
+	if err := SetPanelsNotes(ph, 1, []string{"r0c0", "r1c0"}, "establishing", false); err != nil {
+		t.Fatalf("SetPanelsNotes: %v", err)
+	}
+	if pg.Panels[0].Notes != "establishing" || pg.Panels[2].Notes != "establishing" || pg.Panels[1].Notes != "" {
+		t.Fatalf("notes = %+v", pg.Panels)
+	}
+	if err := OffsetPanels(ph, 1, []string{"r0c2", "r1c2", "r2c2"}, -50, 0, false); err != nil {
+		t.Fatalf("OffsetPanels: %v", err)
+	}
+	for _, p := range pg.Panels {
+		if p.ID[3] == '2' && p.Geometry.X != 170 {
+			t.Fatalf("%s x = %v", p.ID, p.Geometry.X)
+		}
+	}
+}
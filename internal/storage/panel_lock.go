@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import "fmt"
+
+// SetPanelsLocked locks or unlocks the given panels of a page. Locking is never refused, so approved
+// panels can always be protected and unprotected.
+func SetPanelsLocked(ph *ProjectHandle, pageNumber int, ids []string, locked bool) error {
+	pg, idx, err := findPanels(ph, pageNumber, ids, true)
+	if err != nil {
+		return err
+	}
+	for _, i := range idx {
+		pg.Panels[i].Locked = locked
+	}
+	return nil
+}
+
+// SetPageLocked locks or unlocks every panel of a page and returns how many panels changed.
+func SetPageLocked(ph *ProjectHandle, pageNumber int, locked bool) (int, error) {
+	if ph == nil {
+		return 0, fmt.Errorf("project handle is nil")
+	}
+	for i := range ph.Project.Issues {
+		iss := &ph.Project.Issues[i]
+		if j := pageIndexByNumber(*iss, pageNumber); j >= 0 {
+			n := 0
+			for k := range iss.Pages[j].Panels {
+				if pn := &iss.Pages[j].Panels[k]; pn.Locked != locked {
+					pn.Locked = locked
+					n++
+				}
+			}
+			return n, nil
+		}
+	}
+	return 0, pageNotFound(pageNumber)
+}
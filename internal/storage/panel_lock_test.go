@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestLockedPanel_RefusesChangesUnlessForced(t *testing.T) {
+	ph := gridPage()
+	if err := SetPanelsLocked(ph, 1, []string{"r1c1"}, true); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	before := append([]domain.Panel(nil), ph.Project.Issues[0].Pages[0].Panels...)
+	calls := map[string]func(force bool) error{
+		"MovePanelZ":       func(f bool) error { return MovePanelZ(ph, 1, "r1c1", 1, f) },
+		"UpdatePanelMeta":  func(f bool) error { return UpdatePanelMeta(ph, 1, "r1c1", "", "approved", f) },
+		"DeletePanels":     func(f bool) error { return DeletePanels(ph, 1, []string{"r0c0", "r1c1"}, f) },
+		"SetPanelsNotes":   func(f bool) error { return SetPanelsNotes(ph, 1, []string{"r0c0", "r1c1"}, "n", f) },
+		"OffsetPanels":     func(f bool) error { return OffsetPanels(ph, 1, []string{"r0c0", "r1c1"}, 5, 5, f) },
+		"DistributePanels": func(f bool) error { return DistributePanels(ph, 1, []string{"r1c0", "r1c1", "r1c2"}, true, f) },
+	}
+	for name, call := range calls {
+		err := call(false)
+		if !errors.Is(err, ErrPanelLocked) || !strings.Contains(err.Error(), "r1c1 on page 1") {
+			t.Errorf("%s = %v, want ErrPanelLocked for r1c1", name, err)
+		}
+	}
+	if got := ph.Project.Issues[0].Pages[0].Panels; !reflect.DeepEqual(got, before) {
+		t.Fatalf("a refused call changed the page")
+	}
+	// Renaming to the same ID with the same notes changes nothing and is allowed
+	if err := UpdatePanelMeta(ph, 1, "r1c1", "r1c1", "", false); err != nil {
+		t.Fatalf("no-op meta update: %v", err)
+	}
+	if err := OffsetPanels(ph, 1, []string{"r1c1"}, 5, 0, true); err != nil {
+		t.Fatalf("forced offset: %v", err)
+	}
+	if pn := ph.Project.Issues[0].Pages[0].Panels[4]; pn.Geometry.X != 115 || !pn.Locked {
+		t.Fatalf("forced offset = %+v", pn)
+	}
+	if err := DeletePanels(ph, 1, []string{"r1c1"}, true); err != nil {
+		t.Fatalf("forced delete: %v", err)
+	}
+}
+
+func TestSetPageLocked(t *testing.T) {
+	ph := gridPage()
+	if err := SetPanelsLocked(ph, 1, []string{"r0c0", "r0c1"}, true); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := SetPageLocked(ph, 1, true); err != nil || n != 7 {
+		t.Fatalf("lock page = %d, %v; want 7 newly locked", n, err)
+	}
+	iss := &ph.Project.Issues[0]
+	iss.TrimWidth, iss.TrimHeight = 330, 330
+	if _, err := ApplyPageTemplate(ph, iss, 1, domain.PageTemplate{Name: "one", Panels: []domain.Rect{{Width: 1, Height: 1}}}, true); !errors.Is(err, ErrPanelLocked) {
+		t.Fatalf("replacing locked panels with a template: %v", err)
+	}
+	if n, err := SetPageLocked(ph, 1, false); err != nil || n != 9 {
+		t.Fatalf("unlock page = %d, %v; want 9", n, err)
+	}
+	if _, err := SetPageLocked(ph, 5, true); !errors.Is(err, ErrPageNotFound) {
+		t.Fatalf("missing page: %v", err)
+	}
+	if err := SetPanelsLocked(ph, 1, []string{"nope"}, true); !errors.Is(err, ErrPanelNotFound) {
+		t.Fatalf("missing panel: %v", err)
+	}
+}
+
+func TestPanelLocked_ManifestRoundTrip(t *testing.T) {
+	b, err := json.Marshal(domain.Panel{ID: "p1", Locked: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pn domain.Panel
+	if err := json.Unmarshal(b, &pn); err != nil || !pn.Locked {
+		t.Fatalf("round trip = %+v, %v (%s)", pn, err, b)
+	}
+	// Unlocked panels keep the manifest unchanged for versions without locking
+	if b, _ := json.Marshal(domain.Panel{ID: "p1"}); strings.Contains(string(b), "locked") {
+		t.Fatalf("unlocked panel writes the field: %s", b)
+	}
+}
@@ -0,0 +1,218 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// SplitAxis is the direction of the cut made by SplitPanel.
+type SplitAxis int
+
+const (
+	// SplitVertical cuts along a vertical line into a left and a right panel.
+	SplitVertical SplitAxis = iota
+	// SplitHorizontal cuts along a horizontal line into a top and a bottom panel.
+	SplitHorizontal
+)
+
+// DefaultPanelGutterPt is the gutter used between split panels on pages whose gutters cannot be
+// measured, e.g. a page with a single panel: 1/8 inch.
+const DefaultPanelGutterPt = 9.0
+
+// ErrMergeOverlap is returned by MergePanels when the bounding rect of the panels would cover a panel
+// that is not merged, i.e. the panels are not next to each other.
+var ErrMergeOverlap = errors.New("merged panel would overlap another panel; select panels next to each other")
+
+// PageGutter returns the gutter to use for new panel edges on pg: the median of its measured gutters,
+// or DefaultPanelGutterPt when the page has none or does not form rows and columns.
+func PageGutter(pg domain.Page) float64 {
+	if st, err := PageGutters(pg); err == nil && st.Count > 0 && st.Median > 0 {
+		return roundTo(st.Median, 100)
+	}
+	return DefaultPanelGutterPt
+}
+
+// SplitPanel cuts a panel into two that fill its rect with gutterPt between them. ratio is the share of
+// the space left after the gutter that goes to the first panel (left or top). The first panel keeps the
+// ID, notes, beats and annotations; the second gets a new ID and is stacked right above the first.
+// Balloons, captions and SFX go to the half they overlap most, the first one on a tie. Locked panels
+// fail with ErrPanelLocked. Callers persist the change via Save.
+func SplitPanel(ph *ProjectHandle, pageNumber int, panelID string, axis SplitAxis, ratio, gutterPt float64) (first, second domain.Panel, err error) {
+	if !(ratio > 0 && ratio < 1) {
+		return first, second, fmt.Errorf("split ratio %g must be between 0 and 1", ratio)
+	}
+	if gutterPt < 0 || math.IsNaN(gutterPt) || math.IsInf(gutterPt, 0) {
+		return first, second, fmt.Errorf("gutter %g pt must not be negative", gutterPt)
+	}
+	pg, k, pn, err := findPanel(ph, pageNumber, panelID)
+	if err != nil {
+		return first, second, err
+	}
+	if pn.Locked {
+		return first, second, panelLocked(pn.ID, pageNumber)
+	}
+	g := pn.Geometry
+	a, b := g, g
+	if axis == SplitHorizontal {
+		avail := g.Height - gutterPt
+		if avail <= 0 {
+			return first, second, fmt.Errorf("panel %s is too short to split with a %g pt gutter", pn.ID, gutterPt)
+		}
+		a.Height = roundTo(avail*ratio, 100)
+		b.Y = roundTo(g.Y+a.Height+gutterPt, 100)
+		b.Height = roundTo(g.Y+g.Height-b.Y, 100)
+	} else {
+		avail := g.Width - gutterPt
+		if avail <= 0 {
+			return first, second, fmt.Errorf("panel %s is too narrow to split with a %g pt gutter", pn.ID, gutterPt)
+		}
+		a.Width = roundTo(avail*ratio, 100)
+		b.X = roundTo(g.X+a.Width+gutterPt, 100)
+		b.Width = roundTo(g.X+g.Width-b.X, 100)
+	}
+
+	first = *pn
+	first.Geometry = a
+	first.Balloons, first.Captions, first.SFX = nil, nil, nil
+	second = domain.Panel{ID: NewPanelID(ph), Geometry: b, ZOrder: pn.ZOrder + 1, Border: pn.Border}
+	if pn.Border != nil {
+		bb := *pn.Border
+		second.Border = &bb
+	}
+	toSecond := func(r domain.Rect) bool { return intersectArea(r, b) > intersectArea(r, a) }
+	for _, bl := range pn.Balloons {
+		if toSecond(bl.Shape.Rect) {
+			second.Balloons = append(second.Balloons, bl)
+		} else {
+			first.Balloons = append(first.Balloons, bl)
+		}
+	}
+	for _, c := range pn.Captions {
+		if toSecond(c.Rect) {
+			second.Captions = append(second.Captions, c)
+		} else {
+			first.Captions = append(first.Captions, c)
+		}
+	}
+	for _, fx := range pn.SFX {
+		if toSecond(fx.Rect) {
+			second.SFX = append(second.SFX, fx)
+		} else {
+			first.SFX = append(first.SFX, fx)
+		}
+	}
+
+	for i := range pg.Panels {
+		if pg.Panels[i].ZOrder > pn.ZOrder {
+			pg.Panels[i].ZOrder++
+		}
+	}
+	pg.Panels[k] = first
+	pg.Panels = append(pg.Panels[:k+1], append([]domain.Panel{second}, pg.Panels[k+1:]...)...)
+	ph.noteAudit(AuditPanelAdd, auditPagePath(ph, pg)+"/panel:"+second.ID, fmt.Sprintf("split panel %s on page %d into %s and %s", panelID, pageNumber, first.ID, second.ID))
+	return first, second, nil
+}
+
+// MergePanels replaces the given panels of a page with one panel covering their bounding rect. The
+// first panel in page order keeps its ID, annotations and place in the stack; the others' balloons,
+// captions and SFX move into it, their beats are added and their notes appended. It fails with
+// ErrMergeOverlap when the bounding rect would cover any other panel of the page and with
+// ErrPanelLocked when one of the panels is locked. Callers persist the change via Save.
+func MergePanels(ph *ProjectHandle, pageNumber int, ids []string) (domain.Panel, error) {
+	pg, idx, err := findPanels(ph, pageNumber, ids, false)
+	if err != nil {
+		return domain.Panel{}, err
+	}
+	if len(idx) < 2 {
+		return domain.Panel{}, fmt.Errorf("select at least two panels to merge")
+	}
+	sort.Ints(idx)
+	rects := make([]domain.Rect, len(idx))
+	for k, i := range idx {
+		rects[k] = pg.Panels[i].Geometry
+	}
+	all := make([]int, len(idx))
+	for k := range all {
+		all[k] = k
+	}
+	box := boundsOf(rects, all)
+	merged := make(map[int]bool, len(idx))
+	for _, i := range idx {
+		merged[i] = true
+	}
+	for i, p := range pg.Panels {
+		if !merged[i] && rectsOverlap(box, p.Geometry) {
+			return domain.Panel{}, fmt.Errorf("%w: %s on page %d", ErrMergeOverlap, p.ID, pageNumber)
+		}
+	}
+
+	out := pg.Panels[idx[0]]
+	out.Geometry = box
+	beats := map[string]bool{}
+	out.BeatIDs = nil
+	var notes, mergedIDs []string
+	for _, i := range idx {
+		p := pg.Panels[i]
+		mergedIDs = append(mergedIDs, p.ID)
+		for _, id := range p.BeatIDs {
+			if !beats[id] {
+				beats[id] = true
+				out.BeatIDs = append(out.BeatIDs, id)
+			}
+		}
+		if n := strings.TrimSpace(p.Notes); n != "" && !slices.Contains(notes, n) {
+			notes = append(notes, n)
+		}
+		if i == idx[0] {
+			continue
+		}
+		out.Balloons = append(out.Balloons, p.Balloons...)
+		out.Captions = append(out.Captions, p.Captions...)
+		out.SFX = append(out.SFX, p.SFX...)
+	}
+	out.Notes = strings.Join(notes, "\n\n")
+
+	kept := make([]domain.Panel, 0, len(pg.Panels)-len(idx)+1)
+	for i, p := range pg.Panels {
+		switch {
+		case i == idx[0]:
+			kept = append(kept, out)
+		case !merged[i]:
+			kept = append(kept, p)
+		}
+	}
+	// keep the stack dense from 0 in its old order
+	order := make([]*domain.Panel, len(kept))
+	for i := range kept {
+		order[i] = &kept[i]
+	}
+	sort.SliceStable(order, func(i, j int) bool { return order[i].ZOrder < order[j].ZOrder })
+	for z, p := range order {
+		p.ZOrder = z
+	}
+	pg.Panels = kept
+	ph.noteAudit(AuditPanelUpdate, auditPagePath(ph, pg)+"/panel:"+out.ID, fmt.Sprintf("merged panels %s on page %d into %s", strings.Join(mergedIDs, ", "), pageNumber, out.ID))
+	return out, nil
+}
+
+// rectsOverlap reports whether a and b share more than a sliver along both axes; panels that only
+// touch, or overlap by less than gutterOverlapPt, do not.
+func rectsOverlap(a, b domain.Rect) bool {
+	w := math.Min(a.X+a.Width, b.X+b.Width) - math.Max(a.X, b.X)
+	h := math.Min(a.Y+a.Height, b.Y+b.Height) - math.Max(a.Y, b.Y)
+	return w > gutterOverlapPt && h > gutterOverlapPt
+}
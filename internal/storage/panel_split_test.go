@@ -0,0 +1,182 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func splitTestHandle(panels ...domain.Panel) *ProjectHandle {
+	return &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Panels: panels}}}}}}
+}
+
+func TestSplitPanel_Vertical(t *testing.T) {
+	ph := splitTestHandle(
+		domain.Panel{ID: "p1", ZOrder: 0, Geometry: domain.Rect{X: 0, Y: 0, Width: 200, Height: 100}, BeatIDs: []string{"b1", "b2"}, Notes: "wide",
+			Balloons: []domain.Balloon{
+				{ID: "balloon-1", Shape: domain.Shape{Rect: domain.Rect{X: 10, Y: 10, Width: 40, Height: 20}}},
+				{ID: "balloon-2", Shape: domain.Shape{Rect: domain.Rect{X: 150, Y: 10, Width: 40, Height: 20}}},
+				// mostly right of the cut
+				{ID: "balloon-3", Shape: domain.Shape{Rect: domain.Rect{X: 90, Y: 50, Width: 60, Height: 20}}},
+			},
+			Captions: []domain.Caption{{ID: "caption-1", Rect: domain.Rect{X: 120, Y: 80, Width: 50, Height: 10}}},
+			SFX:      []domain.SFXItem{{ID: "sfx-1", Rect: domain.Rect{X: 5, Y: 80, Width: 30, Height: 10}}}},
+		domain.Panel{ID: "p2", ZOrder: 1, Geometry: domain.Rect{X: 0, Y: 110, Width: 200, Height: 100}},
+	)
+	a, b, err := SplitPanel(ph, 1, "p1", SplitVertical, 0.5, 10)
+	if err != nil {
+		t.Fatalf("SplitPanel: %v", err)
+	}
+	if a.ID != "p1" || b.ID == "" || b.ID == "p1" || b.ID == "p2" {
+		t.Fatalf("IDs = %q, %q", a.ID, b.ID)
+	}
+	if a.Geometry != (domain.Rect{X: 0, Y: 0, Width: 95, Height: 100}) || b.Geometry != (domain.Rect{X: 105, Y: 0, Width: 95, Height: 100}) {
+		t.Fatalf("geometry = %+v, %+v", a.Geometry, b.Geometry)
+	}
+	if !reflect.DeepEqual(a.BeatIDs, []string{"b1", "b2"}) || b.BeatIDs != nil || a.Notes != "wide" || b.Notes != "" {
+		t.Fatalf("beats or notes not kept by the first half: %+v / %+v", a, b)
+	}
+	if len(a.Balloons) != 1 || a.Balloons[0].ID != "balloon-1" || len(b.Balloons) != 2 || b.Balloons[0].ID != "balloon-2" || b.Balloons[1].ID != "balloon-3" {
+		t.Fatalf("balloons = %+v / %+v", a.Balloons, b.Balloons)
+	}
+	if len(a.SFX) != 1 || len(b.Captions) != 1 || len(a.Captions) != 0 || len(b.SFX) != 0 {
+		t.Fatalf("captions and SFX not assigned by overlap: %+v / %+v", a, b)
+	}
+	pg := ph.Project.Issues[0].Pages[0]
+	if len(pg.Panels) != 3 || pg.Panels[0].ID != "p1" || pg.Panels[1].ID != b.ID || pg.Panels[2].ID != "p2" {
+		t.Fatalf("panels = %+v", pg.Panels)
+	}
+	if pg.Panels[0].ZOrder != 0 || pg.Panels[1].ZOrder != 1 || pg.Panels[2].ZOrder != 2 {
+		t.Fatalf("z orders = %d %d %d", pg.Panels[0].ZOrder, pg.Panels[1].ZOrder, pg.Panels[2].ZOrder)
+	}
+}
+
+func TestSplitPanel_Horizontal(t *testing.T) {
+	ph := splitTestHandle(domain.Panel{ID: "p1", Geometry: domain.Rect{X: 20, Y: 30, Width: 100, Height: 109}})
+	a, b, err := SplitPanel(ph, 1, "p1", SplitHorizontal, 0.25, 9)
+	if err != nil {
+		t.Fatalf("SplitPanel: %v", err)
+	}
+	if a.Geometry != (domain.Rect{X: 20, Y: 30, Width: 100, Height: 25}) || b.Geometry != (domain.Rect{X: 20, Y: 64, Width: 100, Height: 75}) {
+		t.Fatalf("geometry = %+v, %+v", a.Geometry, b.Geometry)
+	}
+}
+
+func TestSplitPanel_Rejects(t *testing.T) {
+	ph := splitTestHandle(
+		domain.Panel{ID: "p1", Geometry: domain.Rect{Width: 100, Height: 100}},
+		domain.Panel{ID: "p2", Geometry: domain.Rect{X: 120, Width: 100, Height: 100}, Locked: true},
+	)
+	for _, ratio := range []float64{0, 1, -0.5, 2} {
+		if _, _, err := SplitPanel(ph, 1, "p1", SplitVertical, ratio, 10); err == nil {
+			t.Errorf("ratio %g accepted", ratio)
+		}
+	}
+	if _, _, err := SplitPanel(ph, 1, "p1", SplitVertical, 0.5, 100); err == nil {
+		t.Error("a gutter as wide as the panel was accepted")
+	}
+	if _, _, err := SplitPanel(ph, 1, "p1", SplitVertical, 0.5, -1); err == nil {
+		t.Error("a negative gutter was accepted")
+	}
+	if _, _, err := SplitPanel(ph, 1, "p2", SplitVertical, 0.5, 10); !errors.Is(err, ErrPanelLocked) {
+		t.Errorf("splitting a locked panel = %v", err)
+	}
+	if _, _, err := SplitPanel(ph, 1, "p9", SplitVertical, 0.5, 10); !errors.Is(err, ErrPanelNotFound) {
+		t.Errorf("splitting a missing panel = %v", err)
+	}
+	if n := len(ph.Project.Issues[0].Pages[0].Panels); n != 2 {
+		t.Fatalf("failed splits changed the page: %d panels", n)
+	}
+}
+
+func TestMergePanels(t *testing.T) {
+	ph := splitTestHandle(
+		domain.Panel{ID: "p1", ZOrder: 0, Geometry: domain.Rect{X: 0, Y: 0, Width: 95, Height: 100}, BeatIDs: []string{"b1"}, Notes: "left",
+			Balloons: []domain.Balloon{{ID: "balloon-1"}}},
+		domain.Panel{ID: "p2", ZOrder: 2, Geometry: domain.Rect{X: 105, Y: 0, Width: 95, Height: 100}, BeatIDs: []string{"b1", "b2"}, Notes: "right",
+			Balloons: []domain.Balloon{{ID: "balloon-2"}}, SFX: []domain.SFXItem{{ID: "sfx-1"}}},
+		domain.Panel{ID: "p3", ZOrder: 1, Geometry: domain.Rect{X: 0, Y: 110, Width: 200, Height: 100}},
+	)
+	m, err := MergePanels(ph, 1, []string{"p2", "p1"})
+	if err != nil {
+		t.Fatalf("MergePanels: %v", err)
+	}
+	if m.ID != "p1" || m.Geometry != (domain.Rect{X: 0, Y: 0, Width: 200, Height: 100}) {
+		t.Fatalf("merged = %+v", m)
+	}
+	if !reflect.DeepEqual(m.BeatIDs, []string{"b1", "b2"}) || m.Notes != "left\n\nright" {
+		t.Fatalf("beats or notes = %v, %q", m.BeatIDs, m.Notes)
+	}
+	if len(m.Balloons) != 2 || m.Balloons[1].ID != "balloon-2" || len(m.SFX) != 1 {
+		t.Fatalf("lettering not moved: %+v", m)
+	}
+	pg := ph.Project.Issues[0].Pages[0]
+	if len(pg.Panels) != 2 || pg.Panels[0].ID != "p1" || pg.Panels[1].ID != "p3" {
+		t.Fatalf("panels = %+v", pg.Panels)
+	}
+	if pg.Panels[0].ZOrder != 0 || pg.Panels[1].ZOrder != 1 {
+		t.Fatalf("z orders = %d %d", pg.Panels[0].ZOrder, pg.Panels[1].ZOrder)
+	}
+}
+
+func TestMergePanels_RejectsNonAdjacent(t *testing.T) {
+	ph := splitTestHandle(
+		domain.Panel{ID: "a", Geometry: domain.Rect{X: 0, Y: 0, Width: 60, Height: 100}},
+		domain.Panel{ID: "b", Geometry: domain.Rect{X: 70, Y: 0, Width: 60, Height: 100}},
+		domain.Panel{ID: "c", Geometry: domain.Rect{X: 140, Y: 0, Width: 60, Height: 100}, Locked: true},
+	)
+	before := append([]domain.Panel(nil), ph.Project.Issues[0].Pages[0].Panels...)
+	if _, err := MergePanels(ph, 1, []string{"a", "c"}); !errors.Is(err, ErrPanelLocked) {
+		t.Fatalf("merging a locked panel = %v", err)
+	}
+	ph.Project.Issues[0].Pages[0].Panels[2].Locked = false
+	before[2].Locked = false
+	if _, err := MergePanels(ph, 1, []string{"a", "c"}); !errors.Is(err, ErrMergeOverlap) {
+		t.Fatalf("merging around a panel = %v, want ErrMergeOverlap", err)
+	}
+	if _, err := MergePanels(ph, 1, []string{"a"}); err == nil {
+		t.Fatal("merging a single panel was accepted")
+	}
+	if !reflect.DeepEqual(ph.Project.Issues[0].Pages[0].Panels, before) {
+		t.Fatal("rejected merges changed the page")
+	}
+}
+
+func TestSplitThenMerge_RestoresGeometry(t *testing.T) {
+	orig := domain.Rect{X: 12.5, Y: 40, Width: 301, Height: 150}
+	ph := splitTestHandle(domain.Panel{ID: "p1", Geometry: orig})
+	_, b, err := SplitPanel(ph, 1, "p1", SplitVertical, 1.0/3, 8.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := MergePanels(ph, 1, []string{"p1", b.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Geometry != orig {
+		t.Fatalf("merged geometry = %+v, want %+v", m.Geometry, orig)
+	}
+}
+
+func TestPageGutter(t *testing.T) {
+	grid := domain.Page{Panels: []domain.Panel{
+		{ID: "a", Geometry: domain.Rect{X: 0, Y: 0, Width: 100, Height: 100}},
+		{ID: "b", Geometry: domain.Rect{X: 106, Y: 0, Width: 100, Height: 100}},
+	}}
+	if g := PageGutter(grid); g != 6 {
+		t.Fatalf("PageGutter = %g, want 6", g)
+	}
+	if g := PageGutter(domain.Page{Panels: grid.Panels[:1]}); g != DefaultPanelGutterPt {
+		t.Fatalf("PageGutter of a single panel = %g, want the default", g)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+// ValidatePanelAnnotations rejects checklist stages other than domain.ArtStages and a due date not in
+// YYYY-MM-DD form. A nil annotation is valid.
+func ValidatePanelAnnotations(a *domain.PanelAnnotations) error {
+	if a == nil {
+		return nil
+	}
+	for stage := range a.Status {
+		if !slices.Contains(domain.ArtStages, stage) {
+			return fmt.Errorf("unknown art stage %q (want one of %s)", stage, strings.Join(domain.ArtStages, ", "))
+		}
+	}
+	if d := strings.TrimSpace(a.Due); d != "" {
+		if _, err := time.Parse(time.DateOnly, d); err != nil {
+			return fmt.Errorf("due date %q is not of the form YYYY-MM-DD", d)
+		}
+	}
+	return nil
+}
+
+// SetPanelAnnotations replaces the checklist of a panel. Stages that are not done are dropped, and an
+// annotation with nothing left removes the field from the manifest.
+func SetPanelAnnotations(ph *ProjectHandle, pageNumber int, panelID string, a *domain.PanelAnnotations) error {
+	if err := ValidatePanelAnnotations(a); err != nil {
+		return err
+	}
+	_, _, pn, err := findPanel(ph, pageNumber, panelID)
+	if err != nil {
+		return err
+	}
+	pn.Annotations = cloneAnnotations(a)
+	return nil
+}
+
+// cloneAnnotations copies a with trimmed fields and only the done stages, or returns nil when no
+// field is left.
+func cloneAnnotations(a *domain.PanelAnnotations) *domain.PanelAnnotations {
+	if a == nil {
+		return nil
+	}
+	out := &domain.PanelAnnotations{Assignee: strings.TrimSpace(a.Assignee), Due: strings.TrimSpace(a.Due)}
+	for stage, done := range a.Status {
+		if done {
+			if out.Status == nil {
+				out.Status = map[string]bool{}
+			}
+			out.Status[stage] = true
+		}
+	}
+	if out.Status == nil && out.Assignee == "" && out.Due == "" {
+		return nil
+	}
+	return out
+}
+
+// ArtStageDone reports whether the checklist of pn marks stage as done.
+func ArtStageDone(pn domain.Panel, stage string) bool {
+	return pn.Annotations != nil && pn.Annotations.Status[stage]
+}
+
+// NextArtStage returns the first stage of domain.ArtStages that pn has not done yet, or "" when the
+// panel is finished.
+func NextArtStage(pn domain.Panel) string {
+	for _, stage := range domain.ArtStages {
+		if !ArtStageDone(pn, stage) {
+			return stage
+		}
+	}
+	return ""
+}
+
+// IssueArtStatus counts the production status of the panels of one issue. Done holds the number of
+// panels per art stage that have it checked; Complete the panels with every stage done; Assigned the
+// panels with an assignee.
+type IssueArtStatus struct {
+	Issue    int // 1-based
+	Panels   int
+	Done     map[string]int
+	Complete int
+	Assigned int
+}
+
+// ComputeArtStatus returns the art status counts of every issue of p, in issue order.
+func ComputeArtStatus(p domain.Project) []IssueArtStatus {
+	out := make([]IssueArtStatus, 0, len(p.Issues))
+	for i, iss := range p.Issues {
+		st := IssueArtStatus{Issue: i + 1, Done: map[string]int{}}
+		for _, pg := range iss.Pages {
+			for _, pn := range pg.Panels {
+				st.Panels++
+				for _, stage := range domain.ArtStages {
+					if ArtStageDone(pn, stage) {
+						st.Done[stage]++
+					}
+				}
+				if NextArtStage(pn) == "" {
+					st.Complete++
+				}
+				if pn.Annotations != nil && pn.Annotations.Assignee != "" {
+					st.Assigned++
+				}
+			}
+		}
+		out = append(out, st)
+	}
+	return out
+}
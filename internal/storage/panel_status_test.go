@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func statusProject() *ProjectHandle {
+	done := func(stages ...string) *domain.PanelAnnotations {
+		a := &domain.PanelAnnotations{Status: map[string]bool{}}
+		for _, s := range stages {
+			a.Status[s] = true
+		}
+		return a
+	}
+	return &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{
+		{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{
+			{ID: "p1"},
+			{ID: "p2", Annotations: done("layout", "pencils")},
+			{ID: "p3", Annotations: done(domain.ArtStages...)},
+		}}}},
+		{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{{ID: "p1", Annotations: &domain.PanelAnnotations{Assignee: "Kim"}}}}}},
+	}}}
+}
+
+func TestSetPanelAnnotations(t *testing.T) {
+	ph := statusProject()
+	a := &domain.PanelAnnotations{Status: map[string]bool{"layout": true, "inks": false}, Assignee: " Sam ", Due: "2026-11-01"}
+	if err := SetPanelAnnotations(ph, 1, "p1", a); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	got := ph.Project.Issues[0].Pages[0].Panels[0].Annotations
+	if got == a || len(got.Status) != 1 || !got.Status["layout"] || got.Assignee != "Sam" || got.Due != "2026-11-01" {
+		t.Fatalf("annotations = %+v", got)
+	}
+	if err := SetPanelAnnotations(ph, 1, "p1", &domain.PanelAnnotations{Status: map[string]bool{"colors": true}}); err == nil {
+		t.Fatal("unknown stage accepted")
+	}
+	if err := SetPanelAnnotations(ph, 1, "p1", &domain.PanelAnnotations{Due: "next week"}); err == nil {
+		t.Fatal("malformed due date accepted")
+	}
+	if err := SetPanelAnnotations(ph, 1, "p1", &domain.PanelAnnotations{Status: map[string]bool{"layout": false}}); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if got := ph.Project.Issues[0].Pages[0].Panels[0].Annotations; got != nil {
+		t.Fatalf("empty checklist kept: %+v", got)
+	}
+}
+
+func TestNextArtStage(t *testing.T) {
+	panels := statusProject().Project.Issues[0].Pages[0].Panels
+	for i, want := range []string{domain.ArtStageLayout, domain.ArtStageInks, ""} {
+		if got := NextArtStage(panels[i]); got != want {
+			t.Errorf("NextArtStage(%s) = %q, want %q", panels[i].ID, got, want)
+		}
+	}
+}
+
+func TestComputeArtStatus(t *testing.T) {
+	stats := ComputeArtStatus(statusProject().Project)
+	if len(stats) != 2 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	first := stats[0]
+	if first.Issue != 1 || first.Panels != 3 || first.Complete != 1 || first.Done["layout"] != 2 || first.Done["inks"] != 1 || first.Assigned != 0 {
+		t.Fatalf("issue 1 = %+v", first)
+	}
+	if second := stats[1]; second.Panels != 1 || second.Complete != 0 || second.Assigned != 1 || len(second.Done) != 0 {
+		t.Fatalf("issue 2 = %+v", second)
+	}
+}
@@ -41,6 +41,7 @@ func EnsurePage(ph *ProjectHandle, pageNumber int) (*domain.Page, error) {
 	// Return pointer to the (potentially moved) page
 	for i := range iss.Pages {
 		if iss.Pages[i].Number == pageNumber {
+			ph.noteAudit(AuditPageCreate, auditPagePath(ph, &iss.Pages[i]), fmt.Sprintf("created page %d", pageNumber))
 			return &iss.Pages[i], nil
 		}
 	}
@@ -48,6 +49,9 @@ func EnsurePage(ph *ProjectHandle, pageNumber int) (*domain.Page, error) {
 }
 
 // NextPanelID returns a unique panel ID like "p1", "p2", ... not used on the given page.
+//
+// Deprecated: IDs from NextPanelID are only unique among the page's current panels and come back after a
+// delete. Use NewPanelID.
 func NextPanelID(pg *domain.Page) string {
 	if pg == nil {
 		return "p1"
@@ -73,14 +77,14 @@ func NextPanelID(pg *domain.Page) string {
 }
 
 // AddPanel creates a new panel on the given page with default geometry if zero and assigns a zOrder after the last.
-// If panel.ID is empty, a unique one will be generated. Returns the created panel.
+// If panel.ID is empty, a project-unique one is generated with NewPanelID. Returns the created panel.
 func AddPanel(ph *ProjectHandle, pageNumber int, panel domain.Panel) (domain.Panel, error) {
 	pg, err := EnsurePage(ph, pageNumber)
 	if err != nil {
 		return domain.Panel{}, err
 	}
 	if panel.ID == "" {
-		panel.ID = NextPanelID(pg)
+		panel.ID = NewPanelID(ph)
 	} else {
 		// ensure unique
 		for _, p := range pg.Panels {
@@ -102,6 +106,7 @@ func AddPanel(ph *ProjectHandle, pageNumber int, panel domain.Panel) (domain.Pan
 	}
 	panel.ZOrder = maxZ + 1
 	pg.Panels = append(pg.Panels, panel)
+	ph.noteAudit(AuditPanelAdd, auditPagePath(ph, pg)+"/panel:"+panel.ID, fmt.Sprintf("added panel %s to page %d", panel.ID, pageNumber))
 	return panel, nil
 }
 
@@ -122,19 +127,23 @@ func findPanel(ph *ProjectHandle, pageNumber int, panelID string) (*domain.Page,
 					return pg, k, &pg.Panels[k], nil
 				}
 			}
-			return pg, -1, nil, fmt.Errorf("panel %s not found on page %d", panelID, pageNumber)
+			return pg, -1, nil, panelNotFound(panelID, pageNumber)
 		}
 	}
-	return nil, -1, nil, fmt.Errorf("page %d not found", pageNumber)
+	return nil, -1, nil, pageNotFound(pageNumber)
 }
 
 // MovePanelZ moves the panel up or down in zOrder by delta (+1 moves up/top, -1 moves down/back).
 // It adjusts other panels' zOrder to keep a dense sequence starting at 0, then resorts slice by zOrder.
-func MovePanelZ(ph *ProjectHandle, pageNumber int, panelID string, delta int) error {
+// A locked panel is not moved and ErrPanelLocked is returned unless force is set.
+func MovePanelZ(ph *ProjectHandle, pageNumber int, panelID string, delta int, force bool) error {
 	pg, _, pn, err := findPanel(ph, pageNumber, panelID)
 	if err != nil {
 		return err
 	}
+	if pn.Locked && !force {
+		return panelLocked(pn.ID, pageNumber)
+	}
 	// Build order list
 	order := make([]*domain.Panel, len(pg.Panels))
 	for i := range pg.Panels {
@@ -176,17 +185,22 @@ func MovePanelZ(ph *ProjectHandle, pageNumber int, panelID string, delta int) er
 	for i, it := range order {
 		it.ZOrder = i
 	}
+	ph.noteAudit(AuditPanelZOrder, auditPagePath(ph, pg)+"/panel:"+panelID, fmt.Sprintf("moved panel %s from z-order %d to %d", panelID, idx, newIdx))
 	// also reorder pg.Panels slice to match zOrder for deterministic serialization
 	sort.Slice(pg.Panels, func(i, j int) bool { return pg.Panels[i].ZOrder < pg.Panels[j].ZOrder })
 	return nil
 }
 
 // UpdatePanelMeta updates panel ID (if non-empty and unique) and Notes. BeatIDs and Balloons are preserved.
-func UpdatePanelMeta(ph *ProjectHandle, pageNumber int, panelID string, newID string, notes string) error {
+// A locked panel is left unchanged and ErrPanelLocked is returned unless force is set.
+func UpdatePanelMeta(ph *ProjectHandle, pageNumber int, panelID string, newID string, notes string, force bool) error {
 	pg, _, pn, err := findPanel(ph, pageNumber, panelID)
 	if err != nil {
 		return err
 	}
+	if pn.Locked && !force && ((newID != "" && newID != pn.ID) || notes != pn.Notes) {
+		return panelLocked(pn.ID, pageNumber)
+	}
 	if newID != "" && newID != pn.ID {
 		// ensure unique on page
 		for _, p := range pg.Panels {
@@ -194,8 +208,12 @@ func UpdatePanelMeta(ph *ProjectHandle, pageNumber int, panelID string, newID st
 				return fmt.Errorf("panel id %s already exists on page %d", newID, pageNumber)
 			}
 		}
+		ph.noteAudit(AuditPanelUpdate, auditPagePath(ph, pg)+"/panel:"+newID, fmt.Sprintf("renamed panel %s to %s", pn.ID, newID))
 		pn.ID = newID
 	}
+	if notes != pn.Notes {
+		ph.noteAudit(AuditPanelUpdate, auditPagePath(ph, pg)+"/panel:"+pn.ID, fmt.Sprintf("changed notes of panel %s", pn.ID))
+	}
 	pn.Notes = notes
 	return nil
 }
@@ -48,7 +48,7 @@ func TestAddPanelAndOrdering(t *testing.T) {
 	}
 
 	// Move middle (p2) up to top
-	if err := MovePanelZ(ph, 1, p2.ID, +1); err != nil {
+	if err := MovePanelZ(ph, 1, p2.ID, +1, false); err != nil {
 		t.Fatalf("MovePanelZ up: %v", err)
 	}
 	// After move, re-check ordering
@@ -64,7 +64,7 @@ func TestAddPanelAndOrdering(t *testing.T) {
 	}
 
 	// Move top down beyond bottom (no change expected)
-	if err := MovePanelZ(ph, 1, p2.ID, +10); err != nil {
+	if err := MovePanelZ(ph, 1, p2.ID, +10, false); err != nil {
 		t.Fatalf("MovePanelZ out-of-range: %v", err)
 	}
 	pg3, _ := EnsurePage(ph, 1)
@@ -79,7 +79,7 @@ func TestUpdatePanelMeta(t *testing.T) {
 		Panels: []domain.Panel{{ID: "p1", ZOrder: 0}, {ID: "p2", ZOrder: 1}},
 	}}}}}}
 	// Rename p1 to pA and set notes
-	if err := UpdatePanelMeta(ph, 1, "p1", "pA", "first panel"); err != nil {
+	if err := UpdatePanelMeta(ph, 1, "p1", "pA", "first panel", false); err != nil {
 		t.Fatalf("UpdatePanelMeta: %v", err)
 	}
 	pg := ph.Project.Issues[0].Pages[0]
@@ -87,7 +87,7 @@ func TestUpdatePanelMeta(t *testing.T) {
 		t.Fatalf("unexpected panel meta: %+v", pg.Panels[0])
 	}
 	// Renaming to duplicate should error
-	if err := UpdatePanelMeta(ph, 1, "pA", "p2", ""); err == nil {
+	if err := UpdatePanelMeta(ph, 1, "pA", "p2", "", false); err == nil {
 		t.Fatalf("expected duplicate rename error")
 	}
 }
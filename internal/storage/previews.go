@@ -17,6 +17,8 @@ import (
 )
 
 // PreviewKind is a type discriminator for previews table rows.
+// Rows also store the content hash of what they were rendered from (see PageContentHash); a lookup with a
+// different hash misses, so a changed page gets a fresh preview that replaces the stale row.
 // - thumb: raster thumbnail image (PNG) for whole page or a panel
 // - geom: geometry cache blob (implementation-defined; JSON or binary)
 const (
@@ -53,6 +55,7 @@ func EnsurePreviewsMigrated(ctx context.Context, db *sql.DB) error {
 				thumb_blob   BLOB,
 				geom_blob    BLOB,
 				size         INTEGER NOT NULL DEFAULT 0,
+				content_hash TEXT    NOT NULL DEFAULT '',
 				updated_at   TEXT    NOT NULL,
 				last_access  TEXT
 			);`,
@@ -125,6 +128,11 @@ func EnsurePreviewsMigrated(ctx context.Context, db *sql.DB) error {
 			return fmt.Errorf("add geom_blob: %w", err)
 		}
 	}
+	if !cols["content_hash"] {
+		if err := alter(`ALTER TABLE previews ADD COLUMN content_hash TEXT NOT NULL DEFAULT ''`); err != nil {
+			return fmt.Errorf("add content_hash: %w", err)
+		}
+	}
 	// Drop old unique index to allow multiple variants, if it exists
 	_, _ = db.ExecContext(ctx, `DROP INDEX IF EXISTS ux_previews_page_panel`)
 	// Create new unique index covering variant and size
@@ -143,12 +151,21 @@ func containsIgnoreCase(s, sub string) bool {
 
 // GetPreview returns the blob bytes for a preview of given key and updates last_access.
 // For kind==thumb, returns the thumb blob; for kind==geom, returns the geometry blob.
-func GetPreview(ctx context.Context, projectRoot string, pageID int, panelID sql.NullInt64, kind string, w int, h int) ([]byte, error) {
-	db, err := InitOrOpenIndex(projectRoot)
+// A preview stored for different content than contentHash is stale and reported as missing (nil).
+func GetPreview(ctx context.Context, projectRoot string, pageID int, panelID sql.NullInt64, kind string, w int, h int, contentHash string) ([]byte, error) {
+	return withIndex(projectRoot, func(ix *IndexHandle) ([]byte, error) {
+		return ix.GetPreview(ctx, pageID, panelID, kind, w, h, contentHash)
+	})
+}
+
+// GetPreview returns the blob bytes for a preview of given key and updates last_access.
+// A preview stored for different content than contentHash is stale and reported as missing (nil).
+func (ix *IndexHandle) GetPreview(ctx context.Context, pageID int, panelID sql.NullInt64, kind string, w int, h int, contentHash string) ([]byte, error) {
+	db, err := ix.acquire()
 	if err != nil {
 		return nil, err
 	}
-	defer db.Close()
+	defer ix.release()
 	if err := EnsurePreviewsMigrated(ctx, db); err != nil {
 		return nil, err
 	}
@@ -156,8 +173,9 @@ func GetPreview(ctx context.Context, projectRoot string, pageID int, panelID sql
 	if kind == PreviewKindGeom {
 		col = "geom_blob"
 	}
-	q := fmt.Sprintf("SELECT %s FROM previews WHERE page_id=? AND panel_id IS ? AND kind=? AND w=? AND h=?", col)
+	q := fmt.Sprintf("SELECT %s, content_hash FROM previews WHERE page_id=? AND panel_id IS ? AND kind=? AND w=? AND h=?", col)
 	var blob []byte
+	var stored string
 	// Note: panelID may be NULL; use IS ? which compares NULLs in SQLite when arg is nil
 	var pn any
 	if panelID.Valid {
@@ -165,27 +183,39 @@ func GetPreview(ctx context.Context, projectRoot string, pageID int, panelID sql
 	} else {
 		pn = nil
 	}
-	err = db.QueryRowContext(ctx, q, pageID, pn, kind, w, h).Scan(&blob)
+	err = db.QueryRowContext(ctx, q, pageID, pn, kind, w, h).Scan(&blob, &stored)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("query preview: %w", err)
 	}
+	if stored != contentHash {
+		// stale: not touched, so it is evicted first unless PutPreview replaces it
+		return nil, nil
+	}
 	// touch
 	now := time.Now().UTC().Format(time.RFC3339)
 	_, _ = db.ExecContext(ctx, `UPDATE previews SET last_access=? WHERE page_id=? AND panel_id IS ? AND kind=? AND w=? AND h=?`, now, pageID, pn, kind, w, h)
 	return blob, nil
 }
 
-// PutPreview upserts a preview blob and enforces the cache size cap via LRU eviction.
-// For kind==thumb, blob bytes should be PNG or JPEG; for kind==geom, arbitrary.
-func PutPreview(ctx context.Context, projectRoot string, pageID int, panelID sql.NullInt64, kind string, w int, h int, blob []byte) error {
-	db, err := InitOrOpenIndex(projectRoot)
+// PutPreview upserts a preview blob rendered from content with the given hash and enforces the cache
+// size cap via LRU eviction. For kind==thumb, blob bytes should be PNG or JPEG; for kind==geom, arbitrary.
+func PutPreview(ctx context.Context, projectRoot string, pageID int, panelID sql.NullInt64, kind string, w int, h int, contentHash string, blob []byte) error {
+	return runIndex(projectRoot, func(ix *IndexHandle) error {
+		return ix.PutPreview(ctx, pageID, panelID, kind, w, h, contentHash, blob)
+	})
+}
+
+// PutPreview upserts a preview blob rendered from content with the given hash and enforces the cache
+// size cap via LRU eviction.
+func (ix *IndexHandle) PutPreview(ctx context.Context, pageID int, panelID sql.NullInt64, kind string, w int, h int, contentHash string, blob []byte) error {
+	db, err := ix.acquire()
 	if err != nil {
 		return err
 	}
-	defer db.Close()
+	defer ix.release()
 	if err := EnsurePreviewsMigrated(ctx, db); err != nil {
 		return err
 	}
@@ -198,17 +228,24 @@ func PutPreview(ctx context.Context, projectRoot string, pageID int, panelID sql
 	}
 	now := time.Now().UTC().Format(time.RFC3339)
 	size := len(blob)
-	// Upsert
-	if kind == PreviewKindThumb {
-		_, err = db.ExecContext(ctx, `INSERT INTO previews(page_id,panel_id,kind,w,h,thumb_blob,size,updated_at,last_access)
-			VALUES(?,?,?,?,?,?,?,?,?)
-			ON CONFLICT(page_id,panel_id,kind,w,h) DO UPDATE SET thumb_blob=excluded.thumb_blob, size=excluded.size, updated_at=excluded.updated_at, last_access=excluded.last_access`,
-			pageID, pn, kind, w, h, blob, size, now, now)
+	// Replace the variant. The unique index does not catch page previews: SQLite treats their NULL
+	// panel_id values as distinct, so ON CONFLICT alone would pile up rows and keep serving the first.
+	col := "thumb_blob"
+	if kind == PreviewKindGeom {
+		col = "geom_blob"
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("upsert preview: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM previews WHERE page_id=? AND panel_id IS ? AND kind=? AND w=? AND h=?`, pageID, pn, kind, w, h); err == nil {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO previews(page_id,panel_id,kind,w,h,%s,size,content_hash,updated_at,last_access)
+			VALUES(?,?,?,?,?,?,?,?,?,?)`, col), pageID, pn, kind, w, h, blob, size, contentHash, now, now)
+	}
+	if err == nil {
+		err = tx.Commit()
 	} else {
-		_, err = db.ExecContext(ctx, `INSERT INTO previews(page_id,panel_id,kind,w,h,geom_blob,size,updated_at,last_access)
-			VALUES(?,?,?,?,?,?,?,?,?)
-			ON CONFLICT(page_id,panel_id,kind,w,h) DO UPDATE SET geom_blob=excluded.geom_blob, size=excluded.size, updated_at=excluded.updated_at, last_access=excluded.last_access`,
-			pageID, pn, kind, w, h, blob, size, now, now)
+		_ = tx.Rollback()
 	}
 	if err != nil {
 		return fmt.Errorf("upsert preview: %w", err)
@@ -223,10 +260,20 @@ func PutPreview(ctx context.Context, projectRoot string, pageID int, panelID sql
 	return nil
 }
 
-// GetOrCreatePreview fetches a preview or generates and stores it using the provided generator.
-func GetOrCreatePreview(ctx context.Context, projectRoot string, pageID int, panelID sql.NullInt64, kind string, w int, h int, gen func(context.Context) ([]byte, error)) ([]byte, error) {
+// GetOrCreatePreview fetches a preview or generates and stores it using the provided generator. The
+// preview is regenerated when contentHash differs from the one it was stored with.
+func GetOrCreatePreview(ctx context.Context, projectRoot string, pageID int, panelID sql.NullInt64, kind string, w int, h int, contentHash string, gen func(context.Context) ([]byte, error)) ([]byte, error) {
+	return withIndex(projectRoot, func(ix *IndexHandle) ([]byte, error) {
+		return ix.GetOrCreatePreview(ctx, pageID, panelID, kind, w, h, contentHash, gen)
+	})
+}
+
+// GetOrCreatePreview fetches a preview or generates and stores it using the provided generator. The
+// preview is regenerated when contentHash differs from the one it was stored with.
+// The connection is not held while gen runs.
+func (ix *IndexHandle) GetOrCreatePreview(ctx context.Context, pageID int, panelID sql.NullInt64, kind string, w int, h int, contentHash string, gen func(context.Context) ([]byte, error)) ([]byte, error) {
 	// Try to get existing first
-	if b, err := GetPreview(ctx, projectRoot, pageID, panelID, kind, w, h); err != nil {
+	if b, err := ix.GetPreview(ctx, pageID, panelID, kind, w, h, contentHash); err != nil {
 		return nil, err
 	} else if b != nil {
 		return b, nil
@@ -241,7 +288,7 @@ func GetOrCreatePreview(ctx context.Context, projectRoot string, pageID int, pan
 	if data == nil {
 		return nil, nil
 	}
-	if err := PutPreview(ctx, projectRoot, pageID, panelID, kind, w, h, data); err != nil {
+	if err := ix.PutPreview(ctx, pageID, panelID, kind, w, h, contentHash, data); err != nil {
 		return nil, err
 	}
 	return data, nil
@@ -310,11 +357,16 @@ func EvictPreviewsToFit(ctx context.Context, db *sql.DB, capBytes int64) error {
 
 // TotalPreviewBytes returns total bytes tracked by previews.size
 func TotalPreviewBytes(ctx context.Context, projectRoot string) (int64, error) {
-	db, err := InitOrOpenIndex(projectRoot)
+	return withIndex(projectRoot, func(ix *IndexHandle) (int64, error) { return ix.TotalPreviewBytes(ctx) })
+}
+
+// TotalPreviewBytes returns total bytes tracked by previews.size
+func (ix *IndexHandle) TotalPreviewBytes(ctx context.Context) (int64, error) {
+	db, err := ix.acquire()
 	if err != nil {
 		return 0, err
 	}
-	defer db.Close()
+	defer ix.release()
 	var total int64
 	if err := db.QueryRowContext(ctx, `SELECT COALESCE(SUM(size),0) FROM previews`).Scan(&total); err != nil {
 		return 0, err
@@ -8,6 +8,7 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"os"
 	"testing"
 	"time"
@@ -35,15 +36,15 @@ func TestPreviewsPutGetAndEvict(t *testing.T) {
 	blobA := make([]byte, 40)
 	blobB := make([]byte, 40)
 	blobC := make([]byte, 40)
-	if err := PutPreview(ctx, ph.Root, 1, pnull, PreviewKindThumb, 100, 100, blobA); err != nil {
+	if err := PutPreview(ctx, ph.Root, 1, pnull, PreviewKindThumb, 100, 100, "", blobA); err != nil {
 		t.Fatalf("put A: %v", err)
 	}
 	time.Sleep(10 * time.Millisecond) // different access times
-	if err := PutPreview(ctx, ph.Root, 1, pnull, PreviewKindThumb, 200, 200, blobB); err != nil {
+	if err := PutPreview(ctx, ph.Root, 1, pnull, PreviewKindThumb, 200, 200, "", blobB); err != nil {
 		t.Fatalf("put B: %v", err)
 	}
 	time.Sleep(10 * time.Millisecond)
-	if err := PutPreview(ctx, ph.Root, 1, pnull, PreviewKindThumb, 300, 300, blobC); err != nil {
+	if err := PutPreview(ctx, ph.Root, 1, pnull, PreviewKindThumb, 300, 300, "", blobC); err != nil {
 		t.Fatalf("put C: %v", err)
 	}
 
@@ -57,9 +58,9 @@ func TestPreviewsPutGetAndEvict(t *testing.T) {
 	}
 
 	// Access the 200x200 one (if present)
-	_, _ = GetPreview(ctx, ph.Root, 1, pnull, PreviewKindThumb, 200, 200)
+	_, _ = GetPreview(ctx, ph.Root, 1, pnull, PreviewKindThumb, 200, 200, "")
 	// Insert another 40-byte; should evict oldest by last_access
-	if err := PutPreview(ctx, ph.Root, 1, pnull, PreviewKindThumb, 400, 400, make([]byte, 40)); err != nil {
+	if err := PutPreview(ctx, ph.Root, 1, pnull, PreviewKindThumb, 400, 400, "", make([]byte, 40)); err != nil {
 		t.Fatalf("put D: %v", err)
 	}
 	if total2, err := TotalPreviewBytes(ctx, ph.Root); err != nil || total2 > 64 {
@@ -80,7 +81,7 @@ func TestGetOrCreatePreview(t *testing.T) {
 	pnull := sql.NullInt64{Valid: false}
 	calls := 0
 	gen := func(context.Context) ([]byte, error) { calls++; return []byte("abcd"), nil }
-	b, err := GetOrCreatePreview(ctx, ph.Root, 2, pnull, PreviewKindGeom, 0, 0, gen)
+	b, err := GetOrCreatePreview(ctx, ph.Root, 2, pnull, PreviewKindGeom, 0, 0, "", gen)
 	if err != nil {
 		t.Fatalf("getOrCreate: %v", err)
 	}
@@ -88,7 +89,7 @@ func TestGetOrCreatePreview(t *testing.T) {
 		t.Fatalf("unexpected data: %q", string(b))
 	}
 	// Second call should hit cache and not call generator
-	b, err = GetOrCreatePreview(ctx, ph.Root, 2, pnull, PreviewKindGeom, 0, 0, gen)
+	b, err = GetOrCreatePreview(ctx, ph.Root, 2, pnull, PreviewKindGeom, 0, 0, "", gen)
 	if err != nil {
 		t.Fatalf("getOrCreate 2: %v", err)
 	}
@@ -96,3 +97,50 @@ func TestGetOrCreatePreview(t *testing.T) {
 		t.Fatalf("generator should be called once, got %d", calls)
 	}
 }
+
+func TestGetOrCreatePreviewRegeneratesChangedPage(t *testing.T) {
+	root := t.TempDir()
+	ph, err := InitProject(root, domain.Project{Name: "Prev Stale"})
+	if err != nil || ph == nil {
+		t.Fatalf("InitProject: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	ph.Project.Issues = []domain.Issue{{Pages: []domain.Page{{Number: 1}}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := AddPanel(ph, 1, domain.Panel{ID: "p1", Geometry: domain.Rect{Width: 10, Height: 10}}); err != nil {
+		t.Fatalf("AddPanel: %v", err)
+	}
+	calls := 0
+	thumb := func() []byte {
+		page := ph.Project.Issues[0].Pages[0]
+		b, err := GetOrCreatePreview(ctx, ph.Root, page.Number, sql.NullInt64{}, PreviewKindThumb, 8, 8, PageContentHash(page), func(context.Context) ([]byte, error) {
+			calls++
+			return json.Marshal(page.Panels)
+		})
+		if err != nil {
+			t.Fatalf("GetOrCreatePreview: %v", err)
+		}
+		return b
+	}
+	first := thumb()
+	if again := thumb(); calls != 1 || string(again) != string(first) {
+		t.Fatalf("unchanged page should hit the cache: calls=%d", calls)
+	}
+
+	// Moving the panel changes what the thumbnail shows.
+	if err := OffsetPanels(ph, 1, []string{"p1"}, 5, 0, false); err != nil {
+		t.Fatalf("OffsetPanels: %v", err)
+	}
+	moved := thumb()
+	if calls != 2 || string(moved) == string(first) {
+		t.Fatalf("changed page should regenerate its preview: calls=%d", calls)
+	}
+	if thumb(); calls != 2 {
+		t.Fatalf("regenerated preview should be cached: calls=%d", calls)
+	}
+	// The stale variant was replaced, not kept next to the new one.
+	if b, err := GetPreview(ctx, ph.Root, 1, sql.NullInt64{}, PreviewKindThumb, 8, 8, PageContentHash(domain.Page{Number: 1})); err != nil || b != nil {
+		t.Fatalf("expected a miss for other content, got %q err=%v", b, err)
+	}
+}
@@ -14,10 +14,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"math/rand"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
@@ -49,10 +49,50 @@ type ProjectHandle struct {
 	Root         string
 	ManifestPath string
 	Project      domain.Project
+	// Validation lists the problems ValidateProject found when the project was opened, led by a warning
+	// when the manifest was unreadable and a backup was opened instead. Open does not fail on them.
+	Validation []ValidationIssue
+
+	index   *IndexHandle   // session index, created by Index and released by Close
+	changes *ChangeTracker // set by TrackChanges; Save then queues sync ops
+	audit   *auditTrail    // edits waiting for the next Save to write them to the audit log
+}
+
+// TrackChanges makes every later Save queue the sync ops for what changed since the manifest as it
+// is now in the index outbox; see ChangeTracker.
+func (ph *ProjectHandle) TrackChanges() {
+	ph.changes = NewChangeTracker(ph.Project)
+}
+
+// Index returns the project's session-scoped index handle, creating it on first call; the database is
+// opened by the first query. Like Project, it is meant to be used from the goroutine that owns the handle.
+// Background work should take the *IndexHandle before it starts.
+func (ph *ProjectHandle) Index() *IndexHandle {
+	if ph.index != nil && ph.index.root != ph.Root {
+		// SaveAs moved the project; the old handle points at the previous index.
+		_ = ph.index.Close()
+		ph.index = nil
+	}
+	if ph.index == nil {
+		ph.index = NewIndexHandle(ph.Root)
+	}
+	return ph.index
+}
+
+// Close releases the project's session index. The handle stays usable for manifest operations and a
+// later Index call opens a new session. Close is safe to call more than once.
+func (ph *ProjectHandle) Close() error {
+	if ph == nil || ph.index == nil {
+		return nil
+	}
+	err := ph.index.Close()
+	ph.index = nil
+	return err
 }
 
 // InitProject creates a new project directory at root (creating it if it doesn't exist),
 // scaffolds the standard subfolders, and writes the given manifest file transactionally.
+// A project without balloon styles gets DefaultBalloonStyles.
 func InitProject(root string, proj domain.Project) (*ProjectHandle, error) {
 	l := applog.WithOperation(applog.WithComponent("storage"), "init").With(slog.String("root", root))
 	if strings.TrimSpace(root) == "" {
@@ -78,6 +118,9 @@ func InitProject(root string, proj domain.Project) (*ProjectHandle, error) {
 		_ = db.Close()
 	}
 
+	if proj.BalloonStyles == nil {
+		proj.BalloonStyles = DefaultBalloonStyles()
+	}
 	ph := &ProjectHandle{
 		Root:         root,
 		ManifestPath: filepath.Join(root, ManifestFileName),
@@ -100,27 +143,31 @@ func InitProject(root string, proj domain.Project) (*ProjectHandle, error) {
 }
 
 // Open loads an existing project from the given root directory.
-// If the current manifest cannot be read or parsed, it will attempt last backup.
+// If the current manifest cannot be read or parsed, it will attempt last backup. Without one, a
+// missing manifest is reported as ErrNotAProject and an unparsable one as ErrManifestCorrupt.
 func Open(root string) (*ProjectHandle, error) {
 	l := applog.WithOperation(applog.WithComponent("storage"), "open").With(slog.String("root", root))
 	mpath := filepath.Join(root, ManifestFileName)
 	b, err := os.ReadFile(mpath)
 	if err != nil {
 		l.Warn("open manifest failed, trying backup", slog.Any("err", err))
-		proj, berr := openFromLatestBackup(root)
+		proj, gap, berr := openFromLatestBackup(root)
 		if berr != nil {
 			l.Error("backup open failed", slog.Any("err", berr))
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("%w: open manifest: %w; backup attempt: %v", ErrNotAProject, err, berr)
+			}
 			return nil, fmt.Errorf("open manifest: %w; backup attempt: %v", err, berr)
 		}
 		l.Info("opened from backup", slog.String("manifest", mpath))
+		migrateOnOpen(proj, l)
 		ph := &ProjectHandle{Root: root, ManifestPath: mpath, Project: *proj}
+		ph.Validation = validateOnOpen(*proj, fmt.Sprintf("%s could not be read (%v); the latest backup was opened instead%s", ManifestFileName, err, gapNote(gap)), l)
 		// Ensure index exists and kick off build if empty
 		go func(p ProjectHandle) {
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
-			if _, ierr := InitOrOpenIndex(p.Root); ierr != nil {
-				l.Warn("index init failed (non-fatal)", slog.Any("err", ierr))
-			} else if err := BuildIndexIfEmpty(ctx, p.Root, p.Project); err != nil {
+			if err := BuildIndexIfEmpty(ctx, p.Root, p.Project); err != nil {
 				l.Warn("index build failed", slog.Any("err", err))
 			}
 		}(*ph)
@@ -129,10 +176,10 @@ func Open(root string) (*ProjectHandle, error) {
 	var p domain.Project
 	if uerr := json.Unmarshal(b, &p); uerr != nil {
 		l.Warn("parse manifest failed, trying backup", slog.Any("err", uerr))
-		proj, berr := openFromLatestBackup(root)
+		proj, gap, berr := openFromLatestBackup(root)
 		if berr != nil {
 			l.Error("backup open failed", slog.Any("err", berr))
-			return nil, fmt.Errorf("parse manifest: %w; backup attempt: %v", uerr, berr)
+			return nil, fmt.Errorf("%w: parse manifest: %s: %w; backup attempt: %v", ErrManifestCorrupt, describeJSONError(b, uerr), uerr, berr)
 		}
 		// Initialize index even when opening from backup
 		if db, ierr := InitOrOpenIndex(root); ierr != nil {
@@ -141,7 +188,9 @@ func Open(root string) (*ProjectHandle, error) {
 			_ = db.Close()
 		}
 		l.Info("opened from backup", slog.String("manifest", mpath))
+		migrateOnOpen(proj, l)
 		ph := &ProjectHandle{Root: root, ManifestPath: mpath, Project: *proj}
+		ph.Validation = validateOnOpen(*proj, fmt.Sprintf("%s could not be parsed: %s; the latest backup was opened instead%s", ManifestFileName, describeJSONError(b, uerr), gapNote(gap)), l)
 		go func(p ProjectHandle) {
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
@@ -158,7 +207,8 @@ func Open(root string) (*ProjectHandle, error) {
 		_ = db.Close()
 	}
 	l.Info("project opened", slog.String("manifest", mpath), slog.String("name", p.Name))
-	ph := &ProjectHandle{Root: root, ManifestPath: mpath, Project: p}
+	migrateOnOpen(&p, l)
+	ph := &ProjectHandle{Root: root, ManifestPath: mpath, Project: p, Validation: validateOnOpen(p, "", l)}
 	go func(p ProjectHandle) {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
@@ -169,6 +219,41 @@ func Open(root string) (*ProjectHandle, error) {
 	return ph, nil
 }
 
+// migrateOnOpen repairs manifests loaded from disk in memory; the fixes are written on the next Save.
+func migrateOnOpen(p *domain.Project, l *slog.Logger) {
+	for _, r := range DedupeIDs(p) {
+		l.Warn("renamed duplicate id", slog.String("kind", r.Kind), slog.Int("page", r.PageNumber),
+			slog.String("panel", r.PanelID), slog.String("old", r.OldID), slog.String("new", r.NewID))
+	}
+	if n := NormalizeAssetRefs(p); n > 0 {
+		l.Info("normalized asset paths", slog.Int("count", n))
+	}
+	for i := range p.Issues {
+		// With duplicate page numbers spread partners are ambiguous; NormalizePages sorts them out
+		// once the author agreed to renumber.
+		if _, bad := pageNumberProblem(i, p.Issues[i]); bad {
+			continue
+		}
+		for _, w := range NormalizeSpreads(&p.Issues[i]) {
+			l.Warn("split inconsistent spread", slog.Int("issue", i+1), slog.Int("page", w.PageNumber))
+		}
+	}
+}
+
+// validateOnOpen runs ValidateProject and logs the result. A non-empty fallback explains why a backup
+// was opened and becomes the first issue.
+func validateOnOpen(p domain.Project, fallback string, l *slog.Logger) []ValidationIssue {
+	var out []ValidationIssue
+	if fallback != "" {
+		out = append(out, ValidationIssue{Path: ManifestFileName, Severity: SeverityWarning, Message: fallback})
+	}
+	out = append(out, ValidateProject(p)...)
+	for _, v := range out {
+		l.Warn("project validation", slog.String("severity", string(v.Severity)), slog.String("path", v.Path), slog.String("msg", v.Message))
+	}
+	return out
+}
+
 // Save writes the current ProjectHandle.Project to disk with transactional semantics
 // and a timestamped backup of the previous manifest (if present).
 func Save(ph *ProjectHandle) error {
@@ -195,16 +280,25 @@ func Save(ph *ProjectHandle) error {
 		return fmt.Errorf("ensure backups dir: %w", err)
 	}
 
-	// If a current manifest exists, copy it to a timestamped backup before replacing
+	// Remember what the manifest held before it is replaced, for the audit log's change count
+	ph.auditBaseline()
+
+	// If a current manifest exists, back it up (full copy or delta) before replacing
+	var bpath string
 	if _, statErr := os.Stat(ph.ManifestPath); statErr == nil {
-		stamp := time.Now().Format("20060102-150405")
-		bname := fmt.Sprintf("%s.%s.bak", ManifestFileName, stamp)
-		bpath := filepath.Join(bdir, bname)
-		l.Debug("backup current manifest", slog.String("backup", bpath))
-		if cerr := copyFile(ph.ManifestPath, bpath); cerr != nil {
+		var cerr error
+		bpath, cerr = backupManifest(ph.Root, ph.ManifestPath, time.Now(), currentBackupRetention().FullEvery)
+		if cerr != nil {
 			l.Error("backup current manifest failed", slog.Any("err", cerr))
 			return fmt.Errorf("backup current manifest: %w", cerr)
 		}
+		l.Debug("backup current manifest", slog.String("backup", bpath))
+		// Retention is best-effort; a failure must not block saving.
+		if n, perr := PruneBackups(ph.Root, currentBackupRetention(), time.Now()); perr != nil {
+			l.Warn("prune backups failed", slog.Any("err", perr))
+		} else if n > 0 {
+			l.Debug("pruned backups", slog.Int("count", n))
+		}
 	}
 
 	// Transactional write: to temp file in same directory, then rename over target
@@ -214,17 +308,42 @@ func Save(ph *ProjectHandle) error {
 		l.Error("write temp manifest failed", slog.Any("err", werr))
 		return fmt.Errorf("write temp manifest: %w", werr)
 	}
-	// On Windows, replace by removing destination first if needed
-	if _, err := os.Stat(ph.ManifestPath); err == nil {
-		_ = os.Remove(ph.ManifestPath)
-	}
-	if rerr := os.Rename(temp, ph.ManifestPath); rerr != nil {
+	if rerr := replaceFile(temp, ph.ManifestPath); rerr != nil {
 		// attempt cleanup temp
 		_ = os.Remove(temp)
 		l.Error("replace manifest failed", slog.Any("err", rerr))
+		if bpath != "" {
+			if _, err := os.Stat(ph.ManifestPath); errors.Is(err, fs.ErrNotExist) {
+				if err := restoreManifest(bpath, ph.ManifestPath); err != nil {
+					l.Error("restore manifest from backup failed", slog.String("backup", bpath), slog.Any("err", err))
+				} else {
+					l.Warn("restored manifest from backup", slog.String("backup", bpath))
+				}
+			}
+		}
 		return fmt.Errorf("replace manifest: %w", rerr)
 	}
 	l.Info("manifest saved", slog.String("path", ph.ManifestPath))
+	{
+		// Like the sync queue, the audit log must not fail a save; unwritten entries go with the next one.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := ph.logSave(ctx); err != nil {
+			l.Warn("write audit log failed", slog.Any("err", err))
+		}
+		cancel()
+	}
+	if ph.changes != nil {
+		// The manifest is saved either way; ops that could not be queued are included in the next save.
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ix, done := projectIndex(ph)
+		if ops, err := ph.changes.Record(ctx, ix, ph.Project); err != nil {
+			l.Warn("queue sync ops failed", slog.Any("err", err))
+		} else if len(ops) > 0 {
+			l.Debug("queued sync ops", slog.Int("count", len(ops)))
+		}
+		done()
+		cancel()
+	}
 	// Trigger background index update (incremental)
 	go func(p ProjectHandle) {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -256,6 +375,8 @@ func SaveAs(ph *ProjectHandle, newRoot string) error {
 			return fmt.Errorf("create subdir %s: %w", d, err)
 		}
 	}
+	// The session index belongs to the old location; the next Index call opens the new one.
+	_ = ph.Close()
 	ph.Root = newRoot
 	ph.ManifestPath = filepath.Join(newRoot, ManifestFileName)
 	if err := Save(ph); err != nil {
@@ -318,34 +439,37 @@ func copyFile(src, dst string) (err error) {
 	return nil
 }
 
-// openFromLatestBackup tries to open the latest timestamped backup.
-func openFromLatestBackup(root string) (*domain.Project, error) {
+// openFromLatestBackup tries to open the latest timestamped backup. When it is a delta that cannot be
+// reconstructed, the newest intact full backup is opened and the gap describes what was lost.
+func openFromLatestBackup(root string) (*domain.Project, *BackupGap, error) {
 	bdir := filepath.Join(root, BackupsDirName)
-	ents, err := os.ReadDir(bdir)
+	if _, err := os.Stat(bdir); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, fmt.Errorf("%w: read backups dir: %w", ErrBackupNotFound, err)
+	}
+	all, err := ListBackups(root)
 	if err != nil {
-		return nil, fmt.Errorf("read backups dir: %w", err)
+		return nil, nil, err
 	}
-	var candidates []string
-	for _, e := range ents {
-		name := e.Name()
-		if strings.HasPrefix(name, ManifestFileName+".") && strings.HasSuffix(name, ".bak") {
-			candidates = append(candidates, filepath.Join(bdir, name))
-		}
+	if len(all) == 0 {
+		return nil, nil, fmt.Errorf("%w: no manifest backups in %s", ErrBackupNotFound, bdir)
 	}
-	if len(candidates) == 0 {
-		return nil, errors.New("no backups found")
+	doc, gap, err := resolveBackup(root, all[0].Path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read latest backup: %w", err)
 	}
-	sort.Strings(candidates) // timestamp in name yields lexicographic order
-	latest := candidates[len(candidates)-1]
-	b, err := os.ReadFile(latest)
+	p, err := projectFromBackup(doc)
 	if err != nil {
-		return nil, fmt.Errorf("read latest backup: %w", err)
+		return nil, nil, err
 	}
-	var p domain.Project
-	if err := json.Unmarshal(b, &p); err != nil {
-		return nil, fmt.Errorf("parse latest backup: %w", err)
+	return &p, gap, nil
+}
+
+// gapNote describes a broken backup chain for the validation message of a project opened from a backup.
+func gapNote(gap *BackupGap) string {
+	if gap == nil {
+		return ""
 	}
-	return &p, nil
+	return ". " + gap.String()
 }
 
 // AutosaveCrashSnapshot writes a crash-recovery snapshot of the current project
@@ -407,7 +531,9 @@ func ReadScript(ph *ProjectHandle) (string, error) {
 	return string(b), nil
 }
 
-// WriteScript writes the given script text to the project's script folder.
+// WriteScript writes the given script text to the project's script folder and records it in the
+// script history unless it equals the latest snapshot. History failures are logged, not returned:
+// the index database is derived and must not block saving the script.
 func WriteScript(ph *ProjectHandle, text string) error {
 	if ph == nil {
 		return errors.New("nil ProjectHandle")
@@ -417,5 +543,22 @@ func WriteScript(ph *ProjectHandle, text string) error {
 		return err
 	}
 	path := ScriptFilePath(ph)
-	return writeFileSync(path, []byte(text))
+	if err := writeFileSync(path, []byte(text)); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	changed, err := RecordScriptSnapshot(ctx, ph, text, time.Now())
+	if err != nil {
+		applog.WithComponent("storage").Warn("record script snapshot failed", slog.String("root", ph.Root), slog.Any("err", err))
+	}
+	if changed || err != nil {
+		ix, done := projectIndex(ph)
+		e := newAuditEntry(AuditScriptWrite, "script", fmt.Sprintf("wrote script (%d lines)", strings.Count(text, "\n")+1))
+		if err := ix.appendAudit(ctx, []AuditEntry{e}, time.Now()); err != nil {
+			applog.WithComponent("storage").Warn("write audit log failed", slog.String("root", ph.Root), slog.Any("err", err))
+		}
+		done()
+	}
+	return nil
 }
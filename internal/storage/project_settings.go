@@ -0,0 +1,207 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+// ProjectSettingsFileName is the per-project view settings file under .gcw.
+const ProjectSettingsFileName = "settings.json"
+
+// ProjectSettings are view and workflow defaults stored with the project rather than in the
+// per-machine app preferences, so they follow the project between machines and collaborators. They
+// live in .gcw/settings.json, which is not part of the manifest backups and may be deleted at any time:
+// the zero value is the default and a missing file loads as such.
+//
+// Keys written by newer versions are kept when an older version loads and saves the file.
+type ProjectSettings struct {
+	BeatOverlay bool `json:"beatOverlay"`
+	// HideReference hides the pages' reference images in the editor.
+	HideReference bool `json:"hideReference"`
+	// LastIssue is the zero-based index of the issue shown last; LastPage its page number (0: first page).
+	LastIssue int `json:"lastIssue"`
+	LastPage  int `json:"lastPage"`
+	// PanelFilter is the text of the panel list filter.
+	PanelFilter string `json:"panelFilter"`
+	// GridPreset is the panel grid offered for new pages, e.g. "3x3"; empty for none.
+	GridPreset string `json:"gridPreset"`
+	// ExportPreset names the export preset used last; it is offered first.
+	ExportPreset string `json:"exportPreset"`
+	// Snap configures snapping while panels are moved, resized or drawn on the page canvas.
+	Snap SnapSettings `json:"snap"`
+	// Overlay colors the panels on the page canvas: OverlayBeats by beat coverage, OverlayArtStatus by
+	// their art checklist; empty shows none. BeatOverlay is the older switch for OverlayBeats.
+	Overlay string `json:"overlay"`
+	// ArtStageFilter limits the panel list to panels that have not done this art stage; empty lists all.
+	ArtStageFilter string `json:"artStageFilter"`
+
+	extra map[string]json.RawMessage
+}
+
+// Page canvas overlays of ProjectSettings.Overlay.
+const (
+	OverlayBeats     = "beats"
+	OverlayArtStatus = "artStatus"
+)
+
+// ActiveOverlay returns the canvas overlay to show, honoring BeatOverlay of settings files written
+// before Overlay existed.
+func (s ProjectSettings) ActiveOverlay() string {
+	if s.Overlay == "" && s.BeatOverlay {
+		return OverlayBeats
+	}
+	return s.Overlay
+}
+
+// DefaultSnapThreshold is the snapping distance in screen pixels used when SnapSettings.Threshold is 0.
+const DefaultSnapThreshold = 8
+
+// SnapSettings configure panel snapping on the page canvas. The zero value snaps panel edges and
+// centers to the other panels and the trim box within DefaultSnapThreshold, without a grid.
+type SnapSettings struct {
+	// Off disables snapping.
+	Off bool `json:"off,omitempty"`
+	// Threshold is the snapping distance in screen pixels; 0 means DefaultSnapThreshold.
+	Threshold float64 `json:"threshold,omitempty"`
+	// GridMM is the spacing of the snap grid in millimetres, counted from the trim box's top-left
+	// corner; 0 disables the grid.
+	GridMM float64 `json:"gridMM,omitempty"`
+	// NoPanels and NoTrim leave the other panels and the trim box out of the snap targets; NoEdges
+	// and NoCenters stop the panel's edges or centers from snapping.
+	NoPanels  bool `json:"noPanels,omitempty"`
+	NoTrim    bool `json:"noTrim,omitempty"`
+	NoEdges   bool `json:"noEdges,omitempty"`
+	NoCenters bool `json:"noCenters,omitempty"`
+}
+
+// ThresholdPx returns the snapping distance in screen pixels.
+func (s SnapSettings) ThresholdPx() float64 {
+	if s.Threshold > 0 {
+		return s.Threshold
+	}
+	return DefaultSnapThreshold
+}
+
+// Validate reports settings the canvas cannot use.
+func (s SnapSettings) Validate() error {
+	switch {
+	case s.Threshold < 0 || s.Threshold > 100:
+		return fmt.Errorf("snap distance %g px is out of range (0–100)", s.Threshold)
+	case s.GridMM < 0 || s.GridMM > 1000:
+		return fmt.Errorf("snap grid spacing %g mm is out of range (0–1000)", s.GridMM)
+	case s.GridMM > 0 && s.GridMM < 0.5:
+		return fmt.Errorf("snap grid spacing %g mm is too fine; use at least 0.5 mm", s.GridMM)
+	}
+	return nil
+}
+
+// projectSettingsFields is ProjectSettings without the JSON methods, for the default encoding.
+type projectSettingsFields ProjectSettings
+
+// UnmarshalJSON decodes the known keys and keeps all others for MarshalJSON.
+func (s *ProjectSettings) UnmarshalJSON(data []byte) error {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	var f projectSettingsFields
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	known, err := settingsKeys(f)
+	if err != nil {
+		return err
+	}
+	for k := range known {
+		delete(all, k)
+	}
+	*s = ProjectSettings(f)
+	s.extra = nil
+	if len(all) > 0 {
+		s.extra = all
+	}
+	return nil
+}
+
+// MarshalJSON encodes the known keys together with any unknown ones read earlier.
+func (s ProjectSettings) MarshalJSON() ([]byte, error) {
+	out, err := settingsKeys(projectSettingsFields(s))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range s.extra {
+		if _, ok := out[k]; !ok {
+			out[k] = v
+		}
+	}
+	return json.Marshal(out)
+}
+
+// settingsKeys encodes the known fields as a key → value map.
+func settingsKeys(f projectSettingsFields) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]json.RawMessage
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
+
+// ProjectSettingsPath returns the path of the project's settings file.
+func ProjectSettingsPath(projectRoot string) string {
+	return filepath.Join(projectRoot, IndexDirName, ProjectSettingsFileName)
+}
+
+// LoadProjectSettings reads the project's settings. A missing file yields the defaults without error;
+// an unreadable or malformed file yields the defaults together with the error, so callers can log it
+// and carry on.
+func LoadProjectSettings(projectRoot string) (ProjectSettings, error) {
+	data, err := os.ReadFile(ProjectSettingsPath(projectRoot))
+	if errors.Is(err, fs.ErrNotExist) {
+		return ProjectSettings{}, nil
+	}
+	if err != nil {
+		return ProjectSettings{}, fmt.Errorf("read project settings: %w", err)
+	}
+	var s ProjectSettings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return ProjectSettings{}, fmt.Errorf("parse project settings: %w", err)
+	}
+	return s, nil
+}
+
+// SaveProjectSettings writes the project's settings, replacing the file atomically.
+func SaveProjectSettings(projectRoot string, s ProjectSettings) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal project settings: %w", err)
+	}
+	data = append(data, '\n')
+	path := ProjectSettingsPath(projectRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("ensure settings dir: %w", err)
+	}
+	temp := fmt.Sprintf("%s.tmp-%d-%d", path, os.Getpid(), rand.Int())
+	if err := writeFileSync(temp, data); err != nil {
+		return fmt.Errorf("write project settings: %w", err)
+	}
+	if err := replaceFile(temp, path); err != nil {
+		_ = os.Remove(temp)
+		return fmt.Errorf("replace project settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProjectSettings_DefaultsWhenMissingOrBroken(t *testing.T) {
+	root := t.TempDir()
+	s, err := LoadProjectSettings(root)
+	if err != nil || s.BeatOverlay || s.LastPage != 0 || s.GridPreset != "" {
+		t.Fatalf("missing file: %+v, %v", s, err)
+	}
+	s.BeatOverlay, s.LastPage, s.GridPreset = true, 4, "3x3"
+	if err := SaveProjectSettings(root, s); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, err := LoadProjectSettings(root)
+	if err != nil || !got.BeatOverlay || got.LastPage != 4 || got.GridPreset != "3x3" {
+		t.Fatalf("round trip: %+v, %v", got, err)
+	}
+
+	if err := os.WriteFile(ProjectSettingsPath(root), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := LoadProjectSettings(root); err == nil || got.BeatOverlay {
+		t.Fatalf("broken file: %+v, %v", got, err)
+	}
+	// Deleting the file, even the whole .gcw folder, is safe; saving recreates it
+	if err := os.RemoveAll(filepath.Join(root, IndexDirName)); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveProjectSettings(root, ProjectSettings{PanelFilter: "splash"}); err != nil {
+		t.Fatalf("save after delete: %v", err)
+	}
+	if got, _ := LoadProjectSettings(root); got.PanelFilter != "splash" {
+		t.Fatalf("recreated settings: %+v", got)
+	}
+}
+
+func TestProjectSettings_PreservesUnknownKeys(t *testing.T) {
+	root := t.TempDir()
+	newer := `{"beatOverlay": true, "lastPage": 2, "timelineZoom": 1.5, "layout": {"inspector": "left"}}`
+	if err := os.MkdirAll(filepath.Join(root, IndexDirName), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ProjectSettingsPath(root), []byte(newer), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadProjectSettings(root)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	s.LastPage = 7
+	if err := SaveProjectSettings(root, s); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	data, err := os.ReadFile(ProjectSettingsPath(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("saved file is not JSON: %v\n%s", err, data)
+	}
+	if m["timelineZoom"] != 1.5 || m["layout"].(map[string]any)["inspector"] != "left" {
+		t.Fatalf("unknown keys lost: %s", data)
+	}
+	if m["lastPage"] != float64(7) || m["beatOverlay"] != true {
+		t.Fatalf("known keys wrong: %s", data)
+	}
+	// A known key is never duplicated by a stale unknown copy
+	if strings.Count(string(data), `"lastPage"`) != 1 {
+		t.Fatalf("duplicate key: %s", data)
+	}
+}
+
+func TestProjectSettings_Snap(t *testing.T) {
+	root := t.TempDir()
+	var s ProjectSettings
+	if s.Snap.ThresholdPx() != DefaultSnapThreshold || s.Snap.Validate() != nil {
+		t.Fatalf("zero snap settings: %+v", s.Snap)
+	}
+	s.Snap = SnapSettings{Threshold: 4, GridMM: 5, NoCenters: true}
+	if err := SaveProjectSettings(root, s); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, err := LoadProjectSettings(root)
+	if err != nil || got.Snap != s.Snap || got.Snap.ThresholdPx() != 4 {
+		t.Fatalf("round trip: %+v, %v", got.Snap, err)
+	}
+	for _, bad := range []SnapSettings{{Threshold: -1}, {Threshold: 500}, {GridMM: -2}, {GridMM: 0.1}, {GridMM: 5000}} {
+		if bad.Validate() == nil {
+			t.Fatalf("%+v should be invalid", bad)
+		}
+	}
+}
+
+func TestProjectSettings_Overlay(t *testing.T) {
+	if got := (ProjectSettings{BeatOverlay: true}).ActiveOverlay(); got != OverlayBeats {
+		t.Fatalf("old beat overlay switch = %q", got)
+	}
+	if got := (ProjectSettings{BeatOverlay: true, Overlay: OverlayArtStatus}).ActiveOverlay(); got != OverlayArtStatus {
+		t.Fatalf("overlay = %q", got)
+	}
+	root := t.TempDir()
+	if err := SaveProjectSettings(root, ProjectSettings{Overlay: OverlayArtStatus, ArtStageFilter: "inks"}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, err := LoadProjectSettings(root)
+	if err != nil || got.ActiveOverlay() != OverlayArtStatus || got.ArtStageFilter != "inks" {
+		t.Fatalf("round trip: %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// Reading-order warning kinds.
+const (
+	ReadingOrderOutOfSequence = "order"   // zOrder disagrees with the spatial reading order
+	ReadingOrderOverlap       = "overlap" // panels partially overlap so their order is ambiguous
+)
+
+// rowOverlapRatio is the fraction of the smaller height two panels must share vertically to be in the same row.
+const rowOverlapRatio = 0.5
+
+// overlapAreaRatio is the fraction of the smaller panel's area that must intersect to count as an ambiguous overlap.
+// Smaller intersections are treated as sloppy borders/gutters and ignored.
+const overlapAreaRatio = 0.1
+
+// ReadingOrderWarning describes a potential reading-order problem on a page.
+type ReadingOrderWarning struct {
+	PageNumber int
+	Kind       string // ReadingOrderOutOfSequence or ReadingOrderOverlap
+	PanelID    string
+	OtherID    string
+	Message    string
+}
+
+// isRTL reports whether the issue reads right-to-left.
+func isRTL(iss domain.Issue) bool {
+	return strings.ToLower(strings.TrimSpace(iss.ReadingDirection)) == "rtl"
+}
+
+// SpatialReadingOrder returns the panel IDs of pg in row-major reading order.
+// Panels are grouped into rows by vertical overlap (top to bottom); within a row they are read
+// left-to-right, or right-to-left when rtl is set. Panels fully containing another (e.g. a splash
+// with insets) sort before the contained panel.
+func SpatialReadingOrder(pg domain.Page, rtl bool) []string {
+	panels := append([]domain.Panel(nil), pg.Panels...)
+	sort.SliceStable(panels, func(i, j int) bool {
+		a, b := panels[i].Geometry, panels[j].Geometry
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	type row struct {
+		top, bottom float64
+		members     []domain.Panel
+	}
+	var rows []*row
+	for _, pn := range panels {
+		g := pn.Geometry
+		if n := len(rows); n > 0 {
+			r := rows[n-1]
+			shared := math.Min(r.bottom, g.Y+g.Height) - math.Max(r.top, g.Y)
+			if shared > 0 && shared >= rowOverlapRatio*math.Min(g.Height, r.bottom-r.top) {
+				r.members = append(r.members, pn)
+				r.bottom = math.Max(r.bottom, g.Y+g.Height)
+				continue
+			}
+		}
+		rows = append(rows, &row{top: g.Y, bottom: g.Y + g.Height, members: []domain.Panel{pn}})
+	}
+	out := make([]string, 0, len(panels))
+	for _, r := range rows {
+		m := r.members
+		sort.SliceStable(m, func(i, j int) bool {
+			a, b := m[i].Geometry, m[j].Geometry
+			if rectContains(a, b) != rectContains(b, a) {
+				return rectContains(a, b)
+			}
+			if rtl {
+				if a.X+a.Width != b.X+b.Width {
+					return a.X+a.Width > b.X+b.Width
+				}
+			} else if a.X != b.X {
+				return a.X < b.X
+			}
+			return a.Y < b.Y
+		})
+		for _, pn := range m {
+			out = append(out, pn.ID)
+		}
+	}
+	return out
+}
+
+// rectContains reports whether a fully contains b.
+func rectContains(a, b domain.Rect) bool {
+	return b.X >= a.X && b.Y >= a.Y && b.X+b.Width <= a.X+a.Width && b.Y+b.Height <= a.Y+a.Height
+}
+
+// intersectArea returns the area shared by a and b.
+func intersectArea(a, b domain.Rect) float64 {
+	w := math.Min(a.X+a.Width, b.X+b.Width) - math.Max(a.X, b.X)
+	h := math.Min(a.Y+a.Height, b.Y+b.Height) - math.Max(a.Y, b.Y)
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	return w * h
+}
+
+// zOrderIDs returns panel IDs sorted by zOrder (ties keep slice order).
+func zOrderIDs(pg domain.Page) []string {
+	panels := append([]domain.Panel(nil), pg.Panels...)
+	sort.SliceStable(panels, func(i, j int) bool { return panels[i].ZOrder < panels[j].ZOrder })
+	ids := make([]string, len(panels))
+	for i, pn := range panels {
+		ids[i] = pn.ID
+	}
+	return ids
+}
+
+// ValidateReadingOrder compares each page's zOrder with the spatial reading order for the issue's
+// reading direction and reports out-of-sequence neighbours and ambiguous partial overlaps.
+// Warnings are ordered by page number, then by reading position.
+func ValidateReadingOrder(iss domain.Issue) []ReadingOrderWarning {
+	rtl := isRTL(iss)
+	dir := "LTR"
+	if rtl {
+		dir = "RTL"
+	}
+	pages := append([]domain.Page(nil), iss.Pages...)
+	sort.SliceStable(pages, func(i, j int) bool { return pages[i].Number < pages[j].Number })
+	var out []ReadingOrderWarning
+	for _, pg := range pages {
+		expected := SpatialReadingOrder(pg, rtl)
+		rank := map[string]int{}
+		for i, id := range zOrderIDs(pg) {
+			rank[id] = i
+		}
+		for k := 0; k+1 < len(expected); k++ {
+			a, b := expected[k], expected[k+1]
+			if rank[b] < rank[a] {
+				out = append(out, ReadingOrderWarning{
+					PageNumber: pg.Number,
+					Kind:       ReadingOrderOutOfSequence,
+					PanelID:    b,
+					OtherID:    a,
+					Message:    fmt.Sprintf("page %d: panel %s comes before %s in zOrder but after it in %s reading order", pg.Number, b, a, dir),
+				})
+			}
+		}
+		for i := 0; i < len(pg.Panels); i++ {
+			for j := i + 1; j < len(pg.Panels); j++ {
+				a, b := pg.Panels[i], pg.Panels[j]
+				if rectContains(a.Geometry, b.Geometry) || rectContains(b.Geometry, a.Geometry) {
+					continue
+				}
+				minArea := math.Min(a.Geometry.Width*a.Geometry.Height, b.Geometry.Width*b.Geometry.Height)
+				if minArea <= 0 || intersectArea(a.Geometry, b.Geometry) < overlapAreaRatio*minArea {
+					continue
+				}
+				out = append(out, ReadingOrderWarning{
+					PageNumber: pg.Number,
+					Kind:       ReadingOrderOverlap,
+					PanelID:    a.ID,
+					OtherID:    b.ID,
+					Message:    fmt.Sprintf("page %d: panels %s and %s overlap; reading order is ambiguous", pg.Number, a.ID, b.ID),
+				})
+			}
+		}
+	}
+	return out
+}
+
+// FixReadingOrder reassigns zOrder on the given page of the issue at issueIndex to follow the spatial
+// reading order (row-major sweep, RTL-aware) and reorders the panel slice to match.
+// It reports whether any zOrder changed. Callers persist the change via Save.
+func FixReadingOrder(ph *ProjectHandle, issueIndex, pageNumber int) (bool, error) {
+	if ph == nil {
+		return false, fmt.Errorf("project handle is nil")
+	}
+	if issueIndex < 0 || issueIndex >= len(ph.Project.Issues) {
+		return false, pageNotFound(pageNumber)
+	}
+	iss := &ph.Project.Issues[issueIndex]
+	var pg *domain.Page
+	for i := range iss.Pages {
+		if iss.Pages[i].Number == pageNumber {
+			pg = &iss.Pages[i]
+			break
+		}
+	}
+	if pg == nil {
+		return false, pageNotFound(pageNumber)
+	}
+	pos := map[string]int{}
+	for i, id := range SpatialReadingOrder(*pg, isRTL(*iss)) {
+		pos[id] = i
+	}
+	changed := false
+	for i := range pg.Panels {
+		z := pos[pg.Panels[i].ID]
+		if pg.Panels[i].ZOrder != z {
+			pg.Panels[i].ZOrder = z
+			changed = true
+		}
+	}
+	sort.SliceStable(pg.Panels, func(i, j int) bool { return pg.Panels[i].ZOrder < pg.Panels[j].ZOrder })
+	return changed, nil
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func pnl(id string, z int, x, y, w, h float64) domain.Panel {
+	return domain.Panel{ID: id, ZOrder: z, Geometry: domain.Rect{X: x, Y: y, Width: w, Height: h}}
+}
+
+func TestReadingOrderHeuristics(t *testing.T) {
+	cases := []struct {
+		name      string
+		dir       string
+		panels    []domain.Panel
+		wantOrder []string
+		wantKinds []string
+	}{
+		{
+			name: "2x2 grid ltr in order",
+			dir:  "ltr",
+			panels: []domain.Panel{
+				pnl("p1", 0, 0, 0, 290, 400), pnl("p2", 1, 310, 0, 290, 400),
+				pnl("p3", 2, 0, 420, 290, 400), pnl("p4", 3, 310, 420, 290, 400),
+			},
+			wantOrder: []string{"p1", "p2", "p3", "p4"},
+		},
+		{
+			name: "2x2 grid ltr swapped",
+			dir:  "ltr",
+			panels: []domain.Panel{
+				pnl("p1", 0, 0, 0, 290, 400), pnl("p2", 2, 310, 0, 290, 400),
+				pnl("p3", 1, 0, 420, 290, 400), pnl("p4", 3, 310, 420, 290, 400),
+			},
+			wantOrder: []string{"p1", "p2", "p3", "p4"},
+			wantKinds: []string{ReadingOrderOutOfSequence},
+		},
+		{
+			name: "2x2 grid rtl with ltr zOrder",
+			dir:  "RTL",
+			panels: []domain.Panel{
+				pnl("p1", 0, 0, 0, 290, 400), pnl("p2", 1, 310, 0, 290, 400),
+				pnl("p3", 2, 0, 420, 290, 400), pnl("p4", 3, 310, 420, 290, 400),
+			},
+			wantOrder: []string{"p2", "p1", "p4", "p3"},
+			wantKinds: []string{ReadingOrderOutOfSequence, ReadingOrderOutOfSequence},
+		},
+		{
+			name: "2x2 grid rtl in order",
+			dir:  "rtl",
+			panels: []domain.Panel{
+				pnl("a", 1, 0, 0, 290, 400), pnl("b", 0, 310, 0, 290, 400),
+				pnl("c", 3, 0, 420, 290, 400), pnl("d", 2, 310, 420, 290, 400),
+			},
+			wantOrder: []string{"b", "a", "d", "c"},
+		},
+		{
+			name: "staircase slight offsets stay in one row",
+			dir:  "ltr",
+			panels: []domain.Panel{
+				pnl("p1", 0, 0, 0, 180, 300), pnl("p2", 1, 200, 40, 180, 300), pnl("p3", 2, 400, 80, 180, 300),
+			},
+			wantOrder: []string{"p1", "p2", "p3"},
+		},
+		{
+			name: "staircase steep steps become rows",
+			dir:  "ltr",
+			panels: []domain.Panel{
+				pnl("p3", 0, 400, 500, 180, 240), pnl("p1", 1, 0, 0, 180, 240), pnl("p2", 2, 200, 250, 180, 240),
+			},
+			wantOrder: []string{"p1", "p2", "p3"},
+			wantKinds: []string{ReadingOrderOutOfSequence},
+		},
+		{
+			name: "staircase descending leftwards in ltr",
+			dir:  "ltr",
+			panels: []domain.Panel{
+				pnl("p1", 0, 400, 0, 180, 240), pnl("p2", 1, 200, 250, 180, 240), pnl("p3", 2, 0, 500, 180, 240),
+			},
+			wantOrder: []string{"p1", "p2", "p3"},
+		},
+		{
+			name:      "splash page single panel",
+			dir:       "ltr",
+			panels:    []domain.Panel{pnl("splash", 0, 0, 0, 600, 900)},
+			wantOrder: []string{"splash"},
+		},
+		{
+			name: "splash with inset is read first",
+			dir:  "ltr",
+			panels: []domain.Panel{
+				pnl("inset", 0, 20, 700, 150, 150), pnl("splash", 1, 0, 0, 600, 900),
+			},
+			wantOrder: []string{"splash", "inset"},
+			wantKinds: []string{ReadingOrderOutOfSequence},
+		},
+		{
+			name: "splash with inset rtl",
+			dir:  "rtl",
+			panels: []domain.Panel{
+				pnl("splash", 0, 0, 0, 600, 900), pnl("inset", 1, 420, 20, 150, 150),
+			},
+			wantOrder: []string{"splash", "inset"},
+		},
+		{
+			name: "partial overlap is ambiguous",
+			dir:  "ltr",
+			panels: []domain.Panel{
+				pnl("p1", 0, 0, 0, 300, 300), pnl("p2", 1, 200, 100, 300, 300),
+			},
+			wantOrder: []string{"p1", "p2"},
+			wantKinds: []string{ReadingOrderOverlap},
+		},
+		{
+			name: "touching borders are not an overlap",
+			dir:  "ltr",
+			panels: []domain.Panel{
+				pnl("p1", 0, 0, 0, 300, 300), pnl("p2", 1, 295, 0, 300, 300),
+			},
+			wantOrder: []string{"p1", "p2"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pg := domain.Page{Number: 1, Panels: tc.panels}
+			iss := domain.Issue{ReadingDirection: tc.dir, Pages: []domain.Page{pg}}
+			got := SpatialReadingOrder(pg, isRTL(iss))
+			if !reflect.DeepEqual(got, tc.wantOrder) {
+				t.Fatalf("order = %v, want %v", got, tc.wantOrder)
+			}
+			var kinds []string
+			for _, w := range ValidateReadingOrder(iss) {
+				if w.PageNumber != 1 || w.Message == "" {
+					t.Fatalf("unexpected warning: %+v", w)
+				}
+				kinds = append(kinds, w.Kind)
+			}
+			if !reflect.DeepEqual(kinds, tc.wantKinds) {
+				t.Fatalf("warnings = %v, want %v", kinds, tc.wantKinds)
+			}
+		})
+	}
+}
+
+func TestFixReadingOrder(t *testing.T) {
+	ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{
+		ReadingDirection: "rtl",
+		Pages: []domain.Page{{Number: 3, Panels: []domain.Panel{
+			pnl("p1", 0, 0, 0, 290, 400), pnl("p2", 1, 310, 0, 290, 400), pnl("p3", 2, 0, 420, 600, 400),
+		}}},
+	}}}}
+	changed, err := FixReadingOrder(ph, 0, 3)
+	if err != nil || !changed {
+		t.Fatalf("fix: changed=%v err=%v", changed, err)
+	}
+	if got := zOrderIDs(ph.Project.Issues[0].Pages[0]); !reflect.DeepEqual(got, []string{"p2", "p1", "p3"}) {
+		t.Fatalf("zOrder after fix = %v", got)
+	}
+	if ws := ValidateReadingOrder(ph.Project.Issues[0]); len(ws) != 0 {
+		t.Fatalf("expected no warnings after fix, got %+v", ws)
+	}
+	if changed, _ := FixReadingOrder(ph, 0, 3); changed {
+		t.Fatalf("second fix should be a no-op")
+	}
+	if _, err := FixReadingOrder(ph, 0, 9); err == nil {
+		t.Fatalf("expected error for missing page")
+	}
+}
+
+func TestFixReadingOrderOfSecondIssue(t *testing.T) {
+	page := func() domain.Page {
+		return domain.Page{Number: 1, Panels: []domain.Panel{pnl("p1", 1, 0, 0, 290, 400), pnl("p2", 0, 310, 0, 290, 400)}}
+	}
+	ph := &ProjectHandle{Project: domain.Project{Issues: []domain.Issue{
+		{Pages: []domain.Page{page()}},
+		{Pages: []domain.Page{page()}},
+	}}}
+	changed, err := FixReadingOrder(ph, 1, 1)
+	if err != nil || !changed {
+		t.Fatalf("fix: changed=%v err=%v", changed, err)
+	}
+	if got := zOrderIDs(ph.Project.Issues[1].Pages[0]); !reflect.DeepEqual(got, []string{"p1", "p2"}) {
+		t.Fatalf("second issue zOrder = %v", got)
+	}
+	if got := zOrderIDs(ph.Project.Issues[0].Pages[0]); !reflect.DeepEqual(got, []string{"p2", "p1"}) {
+		t.Fatalf("first issue was changed: %v", got)
+	}
+	if _, err := FixReadingOrder(ph, 2, 1); !errors.Is(err, ErrPageNotFound) {
+		t.Fatalf("issue out of range: %v", err)
+	}
+}
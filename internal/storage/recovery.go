@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	applog "gocomicwriter/internal/log"
+)
+
+// DefaultRecoveryRetention is how long crash autosave snapshots are kept before pruning.
+const DefaultRecoveryRetention = 14 * 24 * time.Hour
+
+// RecoverySnapshot describes a crash autosave file in the backups folder.
+type RecoverySnapshot struct {
+	Path    string
+	ModTime time.Time
+	Size    int64
+}
+
+// isRecoverySnapshotName reports whether name matches comic.json.crash-<stamp>.autosave.
+func isRecoverySnapshotName(name string) bool {
+	return strings.HasPrefix(name, ManifestFileName+".crash-") && strings.HasSuffix(name, ".autosave")
+}
+
+// listRecoverySnapshots returns all crash autosaves under root, newest first.
+func listRecoverySnapshots(root string) ([]RecoverySnapshot, error) {
+	bdir := filepath.Join(root, BackupsDirName)
+	ents, err := os.ReadDir(bdir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read backups dir: %w", err)
+	}
+	var out []RecoverySnapshot
+	for _, e := range ents {
+		if e.IsDir() || !isRecoverySnapshotName(e.Name()) {
+			continue
+		}
+		info, ierr := e.Info()
+		if ierr != nil {
+			continue
+		}
+		out = append(out, RecoverySnapshot{Path: filepath.Join(bdir, e.Name()), ModTime: info.ModTime(), Size: info.Size()})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ModTime.After(out[j].ModTime) })
+	return out, nil
+}
+
+// FindRecoverySnapshots returns crash autosave snapshots that are newer than the current
+// manifest (i.e. written after the last successful save), newest first.
+// If the manifest is missing, all snapshots are returned.
+func FindRecoverySnapshots(root string) ([]RecoverySnapshot, error) {
+	if strings.TrimSpace(root) == "" {
+		return nil, errors.New("root path is required")
+	}
+	all, err := listRecoverySnapshots(root)
+	if err != nil {
+		return nil, err
+	}
+	info, serr := os.Stat(filepath.Join(root, ManifestFileName))
+	if serr != nil {
+		return all, nil
+	}
+	var out []RecoverySnapshot
+	for _, s := range all {
+		if s.ModTime.After(info.ModTime()) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// RestoreRecoverySnapshot replaces the project manifest with the given crash snapshot.
+// The current manifest is backed up first and the replacement is written atomically via Save.
+// On success ph.Project holds the restored project.
+func RestoreRecoverySnapshot(ph *ProjectHandle, snapshotPath string) error {
+	l := applog.WithOperation(applog.WithComponent("storage"), "restore_snapshot").With(slog.String("snapshot", snapshotPath))
+	if ph == nil {
+		return errors.New("nil ProjectHandle")
+	}
+	if !isRecoverySnapshotName(filepath.Base(snapshotPath)) {
+		return fmt.Errorf("not a recovery snapshot: %s", filepath.Base(snapshotPath))
+	}
+	b, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+	var p domain.Project
+	if err := json.Unmarshal(b, &p); err != nil {
+		l.Error("parse snapshot failed", slog.Any("err", err))
+		return fmt.Errorf("parse snapshot: %w", err)
+	}
+	prev := ph.Project
+	ph.Project = p
+	if err := Save(ph); err != nil {
+		ph.Project = prev
+		l.Error("restore snapshot failed", slog.Any("err", err))
+		return fmt.Errorf("restore snapshot: %w", err)
+	}
+	l.Info("snapshot restored", slog.String("manifest", ph.ManifestPath))
+	return nil
+}
+
+// DeleteRecoverySnapshots removes all crash autosave snapshots for the project and returns the count.
+func DeleteRecoverySnapshots(root string) (int, error) {
+	all, err := listRecoverySnapshots(root)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, s := range all {
+		if rerr := os.Remove(s.Path); rerr != nil && !os.IsNotExist(rerr) {
+			return n, fmt.Errorf("remove snapshot: %w", rerr)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// PruneRecoverySnapshots removes crash autosave snapshots older than retention and returns the count.
+// A non-positive retention uses DefaultRecoveryRetention.
+func PruneRecoverySnapshots(root string, retention time.Duration) (int, error) {
+	if retention <= 0 {
+		retention = DefaultRecoveryRetention
+	}
+	all, err := listRecoverySnapshots(root)
+	if err != nil {
+		return 0, err
+	}
+	cutoff := time.Now().Add(-retention)
+	n := 0
+	for _, s := range all {
+		if !s.ModTime.Before(cutoff) {
+			continue
+		}
+		if rerr := os.Remove(s.Path); rerr != nil && !os.IsNotExist(rerr) {
+			return n, fmt.Errorf("remove snapshot: %w", rerr)
+		}
+		n++
+	}
+	if n > 0 {
+		applog.WithOperation(applog.WithComponent("storage"), "prune_snapshots").Info("pruned crash snapshots", slog.Int("count", n))
+	}
+	return n, nil
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+// writeManifest writes a manifest directly, avoiding Save's background index goroutine.
+func writeManifest(t *testing.T, root string, p domain.Project, mod time.Time) {
+	t.Helper()
+	b, _ := json.Marshal(p)
+	mp := filepath.Join(root, ManifestFileName)
+	if err := os.WriteFile(mp, b, 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	if err := os.Chtimes(mp, mod, mod); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+}
+
+func writeSnapshot(t *testing.T, root, stamp string, p domain.Project, mod time.Time) string {
+	t.Helper()
+	bdir := filepath.Join(root, BackupsDirName)
+	if err := os.MkdirAll(bdir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	b, _ := json.Marshal(p)
+	path := filepath.Join(bdir, ManifestFileName+".crash-"+stamp+".autosave")
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	return path
+}
+
+func TestFindRecoverySnapshotsNewerThanManifest(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	writeManifest(t, root, domain.Project{Name: "Current"}, now.Add(-time.Hour))
+	writeSnapshot(t, root, "old", domain.Project{Name: "Old"}, now.Add(-2*time.Hour))
+	newer := writeSnapshot(t, root, "new", domain.Project{Name: "New"}, now.Add(-time.Minute))
+	newest := writeSnapshot(t, root, "newest", domain.Project{Name: "Newest"}, now)
+	// unrelated backups are ignored
+	_ = os.WriteFile(filepath.Join(root, BackupsDirName, ManifestFileName+".20250101-000000.bak"), []byte("{}"), 0o644)
+
+	got, err := FindRecoverySnapshots(root)
+	if err != nil {
+		t.Fatalf("FindRecoverySnapshots: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d: %+v", len(got), got)
+	}
+	if got[0].Path != newest || got[1].Path != newer {
+		t.Fatalf("unexpected order: %+v", got)
+	}
+
+	// Without backups dir there is nothing to report.
+	empty := t.TempDir()
+	if got, err := FindRecoverySnapshots(empty); err != nil || len(got) != 0 {
+		t.Fatalf("expected no snapshots, got %v err=%v", got, err)
+	}
+}
+
+func TestPruneAndDeleteRecoverySnapshots(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	stale := writeSnapshot(t, root, "stale", domain.Project{}, now.Add(-15*24*time.Hour))
+	fresh := writeSnapshot(t, root, "fresh", domain.Project{}, now.Add(-24*time.Hour))
+
+	n, err := PruneRecoverySnapshots(root, 0)
+	if err != nil || n != 1 {
+		t.Fatalf("prune: n=%d err=%v", n, err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("stale snapshot should be pruned")
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Fatalf("fresh snapshot should remain: %v", err)
+	}
+
+	n, err = DeleteRecoverySnapshots(root)
+	if err != nil || n != 1 {
+		t.Fatalf("delete: n=%d err=%v", n, err)
+	}
+	if _, err := os.Stat(fresh); !os.IsNotExist(err) {
+		t.Fatalf("fresh snapshot should be deleted")
+	}
+}
+
+func TestRestoreRecoverySnapshot(t *testing.T) {
+	// Save starts a background index update; use a manually cleaned dir to avoid cleanup races.
+	root, err := os.MkdirTemp("", "gcw-restore-")
+	if err != nil {
+		t.Fatalf("mkdtemp: %v", err)
+	}
+	t.Cleanup(func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = os.RemoveAll(root)
+	})
+	now := time.Now()
+	writeManifest(t, root, domain.Project{Name: "Current"}, now.Add(-time.Hour))
+	snap := writeSnapshot(t, root, "x", domain.Project{Name: "Recovered"}, now)
+
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName), Project: domain.Project{Name: "Current"}}
+	if err := RestoreRecoverySnapshot(ph, snap); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if ph.Project.Name != "Recovered" {
+		t.Fatalf("handle not updated: %q", ph.Project.Name)
+	}
+	b, err := os.ReadFile(ph.ManifestPath)
+	if err != nil || !strings.Contains(string(b), "Recovered") {
+		t.Fatalf("manifest not replaced: %s err=%v", string(b), err)
+	}
+	// Previous manifest must have been backed up.
+	ents, _ := os.ReadDir(filepath.Join(root, BackupsDirName))
+	foundBak := false
+	for _, e := range ents {
+		if strings.HasSuffix(e.Name(), ".bak") {
+			bb, _ := os.ReadFile(filepath.Join(root, BackupsDirName, e.Name()))
+			foundBak = strings.Contains(string(bb), "Current")
+		}
+	}
+	if !foundBak {
+		t.Fatalf("expected backup of previous manifest")
+	}
+	// After restoring, the snapshot is no longer newer than the manifest.
+	if got, _ := FindRecoverySnapshots(root); len(got) != 0 {
+		t.Fatalf("expected no pending snapshots after restore, got %+v", got)
+	}
+
+	if err := RestoreRecoverySnapshot(ph, ph.ManifestPath); err == nil {
+		t.Fatalf("expected error for non-snapshot path")
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// replaceBackoff controls how replaceFile retries a rename the OS refuses because another program
+// (a virus scanner, a sync client, an editor) holds the destination open.
+var replaceBackoff = struct {
+	Attempts int
+	Initial  time.Duration
+	Max      time.Duration
+}{Attempts: 8, Initial: 25 * time.Millisecond, Max: time.Second}
+
+// Seams for tests: renameFile performs the rename, isReplaceRetryable classifies its error.
+var (
+	renameFile         = os.Rename
+	isReplaceRetryable = isSharingViolation
+)
+
+// replaceFile moves src onto dst in one rename, so dst is never absent: readers see either the old or
+// the new file. os.Rename replaces an existing file on every platform (MoveFileEx with
+// MOVEFILE_REPLACE_EXISTING on Windows). Sharing violations are retried with exponential backoff; when
+// they persist the error wraps ErrManifestLocked. src is left in place on failure.
+func replaceFile(src, dst string) error {
+	wait := replaceBackoff.Initial
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = renameFile(src, dst); err == nil {
+			return nil
+		}
+		if !isReplaceRetryable(err) {
+			return err
+		}
+		if attempt >= replaceBackoff.Attempts {
+			return fmt.Errorf("%w: %d attempts: %w", ErrManifestLocked, attempt, err)
+		}
+		time.Sleep(wait)
+		wait = min(wait*2, replaceBackoff.Max)
+	}
+}
+
+// restoreManifest writes the manifest back from the backup Save made of it, for when a failed replace
+// left no manifest behind. A delta backup is reconstructed first.
+func restoreManifest(backup, manifestPath string) error {
+	if !strings.HasSuffix(backup, backupDeltaSuffix) {
+		return copyFile(backup, manifestPath)
+	}
+	doc, _, err := loadBackup(backup)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileSync(manifestPath, append(data, '\n'))
+}
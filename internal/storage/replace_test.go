@@ -0,0 +1,198 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+// fastReplaceBackoff shortens the retry waits for the test.
+func fastReplaceBackoff(t *testing.T) {
+	t.Helper()
+	old := replaceBackoff
+	replaceBackoff.Initial, replaceBackoff.Max = time.Millisecond, 20*time.Millisecond
+	t.Cleanup(func() { replaceBackoff = old })
+}
+
+// stubRename swaps renameFile and isReplaceRetryable for the test.
+func stubRename(t *testing.T, rename func(src, dst string) error, retryable func(error) bool) {
+	t.Helper()
+	oldRename, oldRetry := renameFile, isReplaceRetryable
+	renameFile, isReplaceRetryable = rename, retryable
+	t.Cleanup(func() { renameFile, isReplaceRetryable = oldRename, oldRetry })
+}
+
+// manifestName reads the manifest and returns the project name in it.
+func manifestName(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	var p domain.Project
+	if err := json.Unmarshal(b, &p); err != nil {
+		t.Fatalf("manifest is not valid JSON: %v", err)
+	}
+	return p.Name
+}
+
+// noTempFiles fails when a temp manifest was left in the project root.
+func noTempFiles(t *testing.T, root string) {
+	t.Helper()
+	ents, _ := os.ReadDir(root)
+	for _, e := range ents {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Errorf("temp file left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestSaveWithManifestHeldOpen(t *testing.T) {
+	fastReplaceBackoff(t)
+	root := t.TempDir()
+	ph, err := InitProject(root, domain.Project{Name: "Before"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(ph.ManifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	ph.Project.Name = "After"
+	err = Save(ph)
+	if runtime.GOOS != "windows" {
+		// POSIX renames replace open files; the lock is a no-op
+		if err != nil {
+			t.Fatalf("Save with open manifest: %v", err)
+		}
+		if got := manifestName(t, ph.ManifestPath); got != "After" {
+			t.Errorf("manifest name = %q, want After", got)
+		}
+		noTempFiles(t, root)
+		return
+	}
+	if !errors.Is(err, ErrManifestLocked) {
+		t.Fatalf("Save with locked manifest = %v, want ErrManifestLocked", err)
+	}
+	if got := manifestName(t, ph.ManifestPath); got != "Before" {
+		t.Errorf("manifest name after failed save = %q, want Before", got)
+	}
+	noTempFiles(t, root)
+
+	// A handle released while Save is backing off lets a retry succeed
+	replaceBackoff.Max = 100 * time.Millisecond
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = f.Close()
+	}()
+	if err := Save(ph); err != nil {
+		t.Fatalf("Save after the handle is released: %v", err)
+	}
+	if got := manifestName(t, ph.ManifestPath); got != "After" {
+		t.Errorf("manifest name = %q, want After", got)
+	}
+}
+
+func TestReplaceFileRetriesSharingViolations(t *testing.T) {
+	fastReplaceBackoff(t)
+	errBusy := errors.New("busy")
+	calls := 0
+	stubRename(t, func(src, dst string) error {
+		if calls++; calls < 3 {
+			return errBusy
+		}
+		return os.Rename(src, dst)
+	}, func(err error) bool { return errors.Is(err, errBusy) })
+
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	if err := os.WriteFile(src, []byte("new"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := replaceFile(src, dst); err != nil {
+		t.Fatalf("replaceFile: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("rename calls = %d, want 3", calls)
+	}
+	if b, _ := os.ReadFile(dst); string(b) != "new" {
+		t.Errorf("dst = %q, want new", b)
+	}
+
+	// Persistent violations give up after the configured attempts
+	calls = -100
+	if err := os.WriteFile(src, []byte("newer"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := replaceFile(src, dst)
+	if !errors.Is(err, ErrManifestLocked) || !errors.Is(err, errBusy) {
+		t.Fatalf("replaceFile = %v, want ErrManifestLocked wrapping the cause", err)
+	}
+	if calls != -100+replaceBackoff.Attempts {
+		t.Errorf("rename calls = %d, want %d", calls+100, replaceBackoff.Attempts)
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Errorf("src should be left in place: %v", err)
+	}
+}
+
+func TestSaveRestoresManifestAfterFailedReplace(t *testing.T) {
+	root := t.TempDir()
+	ph, err := InitProject(root, domain.Project{Name: "Before"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A rename that loses the destination, as a remove-then-rename replace would
+	errGone := errors.New("rename failed")
+	stubRename(t, func(src, dst string) error {
+		_ = os.Remove(dst)
+		return errGone
+	}, func(error) bool { return false })
+
+	ph.Project.Name = "After"
+	if err := Save(ph); !errors.Is(err, errGone) {
+		t.Fatalf("Save = %v, want the rename error", err)
+	}
+	if got := manifestName(t, ph.ManifestPath); got != "Before" {
+		t.Fatalf("manifest name = %q, want Before restored from backup", got)
+	}
+	noTempFiles(t, root)
+}
+
+func TestRestoreManifestFromDelta(t *testing.T) {
+	root := t.TempDir()
+	paths := saveBackups(t, root, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 10, "v0", "v1")
+	if !strings.HasSuffix(paths[1], backupDeltaSuffix) {
+		t.Fatalf("expected a delta, got %s", paths[1])
+	}
+	manifest := filepath.Join(root, ManifestFileName)
+	if err := os.Remove(manifest); err != nil {
+		t.Fatal(err)
+	}
+	if err := restoreManifest(paths[1], manifest); err != nil {
+		t.Fatalf("restoreManifest: %v", err)
+	}
+	if got := manifestName(t, manifest); got != "v1" {
+		t.Errorf("restored name = %q, want v1", got)
+	}
+}
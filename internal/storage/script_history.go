@@ -0,0 +1,383 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gocomicwriter/internal/script"
+)
+
+// language=SQL
+// dialect=SQLite
+const listScriptHistorySQL = `SELECT id, ts, length(CAST(text AS BLOB)), length(text) - length(replace(text, char(10), ''))
+	FROM script_snapshots ORDER BY ts DESC, id DESC LIMIT ?`
+
+// language=SQL
+// dialect=SQLite
+const selectScriptSnapshotTextSQL = `SELECT text FROM script_snapshots WHERE id = ?`
+
+// language=SQL
+// dialect=SQLite
+const listScriptSnapshotAgesSQL = `SELECT id, ts FROM script_snapshots ORDER BY ts DESC, id DESC`
+
+// ScriptSnapshotRetention controls how many script snapshots RecordScriptSnapshot keeps.
+// The newest KeepLast snapshots younger than MaxAge are kept; the latest snapshot is never removed.
+type ScriptSnapshotRetention struct {
+	KeepLast int
+	MaxAge   time.Duration
+}
+
+// DefaultScriptSnapshotRetention keeps the last 200 snapshots for up to 90 days.
+var DefaultScriptSnapshotRetention = ScriptSnapshotRetention{KeepLast: 200, MaxAge: 90 * 24 * time.Hour}
+
+var scriptRetention = DefaultScriptSnapshotRetention
+
+// SetScriptSnapshotRetention sets the retention policy applied by RecordScriptSnapshot. Non-positive fields use the defaults.
+func SetScriptSnapshotRetention(r ScriptSnapshotRetention) {
+	if r.KeepLast <= 0 {
+		r.KeepLast = DefaultScriptSnapshotRetention.KeepLast
+	}
+	if r.MaxAge <= 0 {
+		r.MaxAge = DefaultScriptSnapshotRetention.MaxAge
+	}
+	retentionMu.Lock()
+	scriptRetention = r
+	retentionMu.Unlock()
+}
+
+func currentScriptRetention() ScriptSnapshotRetention {
+	retentionMu.Lock()
+	defer retentionMu.Unlock()
+	return scriptRetention
+}
+
+// ScriptSnapshotInfo describes a stored script snapshot without its text.
+type ScriptSnapshotInfo struct {
+	ID    int64
+	TS    time.Time
+	Bytes int
+	Lines int
+}
+
+// ScriptDiffKind classifies a line in a script diff.
+type ScriptDiffKind string
+
+const (
+	ScriptLineAdded   ScriptDiffKind = "added"
+	ScriptLineRemoved ScriptDiffKind = "removed"
+	ScriptLineChanged ScriptDiffKind = "changed"
+)
+
+// ScriptDiffLine is one changed line between two script versions. Unchanged lines are not reported.
+type ScriptDiffLine struct {
+	Kind    ScriptDiffKind
+	OldLine int // 1-based line in the older text; 0 for added lines
+	NewLine int // 1-based line in the newer text; 0 for removed lines
+	Old     string
+	New     string
+	Scene   string // title of the enclosing scene (from the newer text, or the older one for removed lines)
+}
+
+// maxDiffCells bounds the line-matching table of DiffScriptText. Larger differing regions are reported
+// as one block of changed lines instead of being aligned.
+const maxDiffCells = 4 << 20
+
+// RecordScriptSnapshot stores text as a new script snapshot unless it equals the latest one, then applies the
+// script snapshot retention policy. It reports whether a snapshot was written.
+func RecordScriptSnapshot(ctx context.Context, ph *ProjectHandle, text string, ts time.Time) (bool, error) {
+	if ph == nil {
+		return false, errors.New("nil ProjectHandle")
+	}
+	ix, done := projectIndex(ph)
+	defer done()
+	return ix.RecordScriptSnapshot(ctx, text, ts)
+}
+
+// RecordScriptSnapshot is the package-level RecordScriptSnapshot on this handle's index, for background
+// work that took the handle before it started.
+func (ix *IndexHandle) RecordScriptSnapshot(ctx context.Context, text string, ts time.Time) (bool, error) {
+	db, err := ix.acquire()
+	if err != nil {
+		return false, err
+	}
+	defer ix.release()
+	var latest string
+	err = db.QueryRowContext(ctx, selectLatestScriptSnapshotSQL).Scan(new(string), &latest)
+	switch {
+	case err == nil && latest == text:
+		return false, nil
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		return false, err
+	}
+	if _, err := db.ExecContext(ctx, insertScriptSnapshotSQL, ts.UTC().Format(time.RFC3339Nano), text); err != nil {
+		return false, err
+	}
+	if _, err := pruneScriptSnapshots(ctx, db, currentScriptRetention(), ts); err != nil {
+		return true, fmt.Errorf("prune script snapshots: %w", err)
+	}
+	return true, nil
+}
+
+// pruneScriptSnapshots deletes snapshots beyond r.KeepLast or older than r.MaxAge relative to now,
+// always keeping the latest one, and returns the number removed.
+func pruneScriptSnapshots(ctx context.Context, db *sql.DB, r ScriptSnapshotRetention, now time.Time) (int, error) {
+	rows, err := db.QueryContext(ctx, listScriptSnapshotAgesSQL)
+	if err != nil {
+		return 0, err
+	}
+	var drop []int64
+	cutoff := now.Add(-r.MaxAge)
+	for i := 0; rows.Next(); i++ {
+		var id int64
+		var tsStr string
+		if err := rows.Scan(&id, &tsStr); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		if i == 0 {
+			continue
+		}
+		ts, perr := time.Parse(time.RFC3339Nano, tsStr)
+		if (r.KeepLast > 0 && i >= r.KeepLast) || (r.MaxAge > 0 && perr == nil && ts.Before(cutoff)) {
+			drop = append(drop, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return 0, err
+	}
+	_ = rows.Close()
+	for _, id := range drop {
+		if _, err := db.ExecContext(ctx, `DELETE FROM script_snapshots WHERE id = ?`, id); err != nil {
+			return 0, err
+		}
+	}
+	return len(drop), nil
+}
+
+// ScriptHistory returns metadata of up to limit most recent script snapshots, newest first.
+func ScriptHistory(ctx context.Context, projectRoot string, limit int) ([]ScriptSnapshotInfo, error) {
+	if strings.TrimSpace(projectRoot) == "" {
+		return nil, errors.New("project root is required")
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	return withIndex(projectRoot, func(ix *IndexHandle) ([]ScriptSnapshotInfo, error) {
+		db, err := ix.acquire()
+		if err != nil {
+			return nil, err
+		}
+		defer ix.release()
+		rows, err := db.QueryContext(ctx, listScriptHistorySQL, limit)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = rows.Close() }()
+		out := []ScriptSnapshotInfo{}
+		for rows.Next() {
+			var s ScriptSnapshotInfo
+			var tsStr string
+			var newlines int
+			if err := rows.Scan(&s.ID, &tsStr, &s.Bytes, &newlines); err != nil {
+				return nil, err
+			}
+			s.TS, _ = time.Parse(time.RFC3339Nano, tsStr)
+			if s.Bytes > 0 {
+				s.Lines = newlines + 1
+			}
+			out = append(out, s)
+		}
+		return out, rows.Err()
+	})
+}
+
+// DiffScriptSnapshots returns the line-level changes from snapshot idA to snapshot idB.
+func DiffScriptSnapshots(ctx context.Context, projectRoot string, idA, idB int64) ([]ScriptDiffLine, error) {
+	if strings.TrimSpace(projectRoot) == "" {
+		return nil, errors.New("project root is required")
+	}
+	texts, err := withIndex(projectRoot, func(ix *IndexHandle) ([2]string, error) {
+		var out [2]string
+		db, err := ix.acquire()
+		if err != nil {
+			return out, err
+		}
+		defer ix.release()
+		for i, id := range []int64{idA, idB} {
+			if out[i], err = scriptSnapshotText(ctx, db, id); err != nil {
+				return out, err
+			}
+		}
+		return out, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return DiffScriptText(texts[0], texts[1]), nil
+}
+
+// ScriptSnapshotText returns the full script text stored in snapshot id.
+func ScriptSnapshotText(ctx context.Context, projectRoot string, id int64) (string, error) {
+	if strings.TrimSpace(projectRoot) == "" {
+		return "", errors.New("project root is required")
+	}
+	return withIndex(projectRoot, func(ix *IndexHandle) (string, error) {
+		db, err := ix.acquire()
+		if err != nil {
+			return "", err
+		}
+		defer ix.release()
+		return scriptSnapshotText(ctx, db, id)
+	})
+}
+
+func scriptSnapshotText(ctx context.Context, db *sql.DB, id int64) (string, error) {
+	var text string
+	err := db.QueryRowContext(ctx, selectScriptSnapshotTextSQL, id).Scan(&text)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("script snapshot %d not found", id)
+	}
+	return text, err
+}
+
+// DiffScriptText compares two script versions line by line. A run of removed lines directly followed by
+// added lines is reported pairwise as changed lines; the rest of the longer side as added or removed.
+func DiffScriptText(older, newer string) []ScriptDiffLine {
+	a, b := splitScriptLines(older), splitScriptLines(newer)
+	oldScene, newScene := sceneLookup(older), sceneLookup(newer)
+
+	// Common prefix and suffix need no alignment
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	ops := alignLines(a[pre:len(a)-suf], b[pre:len(b)-suf])
+
+	var out []ScriptDiffLine
+	i, j := pre, pre // indexes into a and b
+	for k := 0; k < len(ops); {
+		if ops[k] == '=' {
+			i, j, k = i+1, j+1, k+1
+			continue
+		}
+		// Collect one run of removals and additions
+		var dels, adds []int
+		for ; k < len(ops) && ops[k] != '='; k++ {
+			if ops[k] == '-' {
+				dels = append(dels, i)
+				i++
+			} else {
+				adds = append(adds, j)
+				j++
+			}
+		}
+		n := min(len(dels), len(adds))
+		for p := 0; p < n; p++ {
+			out = append(out, ScriptDiffLine{Kind: ScriptLineChanged, OldLine: dels[p] + 1, NewLine: adds[p] + 1,
+				Old: a[dels[p]], New: b[adds[p]], Scene: newScene(adds[p] + 1)})
+		}
+		for _, d := range dels[n:] {
+			out = append(out, ScriptDiffLine{Kind: ScriptLineRemoved, OldLine: d + 1, Old: a[d], Scene: oldScene(d + 1)})
+		}
+		for _, ad := range adds[n:] {
+			out = append(out, ScriptDiffLine{Kind: ScriptLineAdded, NewLine: ad + 1, New: b[ad], Scene: newScene(ad + 1)})
+		}
+	}
+	return out
+}
+
+// splitScriptLines splits text into lines without their terminators; empty text has no lines.
+func splitScriptLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), "\n")
+}
+
+// alignLines returns an edit script turning a into b: '=' keeps a line, '-' removes one from a, '+' adds one
+// from b. It follows a longest common subsequence; regions too large to align are replaced wholesale.
+func alignLines(a, b []string) []byte {
+	n, m := len(a), len(b)
+	ops := make([]byte, 0, n+m)
+	if n == 0 || m == 0 || (n+1)*(m+1) > maxDiffCells {
+		for range a {
+			ops = append(ops, '-')
+		}
+		for range b {
+			ops = append(ops, '+')
+		}
+		return ops
+	}
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	w := m + 1
+	lcs := make([]int32, (n+1)*w)
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*w+j] = lcs[(i+1)*w+j+1] + 1
+			} else {
+				lcs[i*w+j] = max(lcs[(i+1)*w+j], lcs[i*w+j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, '=')
+			i, j = i+1, j+1
+		case lcs[(i+1)*w+j] >= lcs[i*w+j+1]:
+			ops = append(ops, '-')
+			i++
+		default:
+			ops = append(ops, '+')
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, '-')
+	}
+	for ; j < m; j++ {
+		ops = append(ops, '+')
+	}
+	return ops
+}
+
+// sceneLookup parses text and returns a function mapping a 1-based line number to the title of the
+// scene heading at or above it, or "" before the first heading.
+func sceneLookup(text string) func(line int) string {
+	sc, _ := script.Parse(text)
+	var heads []script.Scene
+	for _, s := range sc.Scenes {
+		if s.LineNo > 0 {
+			heads = append(heads, s)
+		}
+	}
+	return func(line int) string {
+		k := sort.Search(len(heads), func(i int) bool { return heads[i].LineNo > line })
+		if k == 0 {
+			return ""
+		}
+		return heads[k-1].Title
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffScriptText(t *testing.T) {
+	older := "# Scene 1: Rooftop\nALICE: Hi.\nBOB: Hello.\n\n# Scene 2: Alley\nCAPTION: Later.\nALICE: Run!\n"
+	newer := "# Scene 1: Rooftop\nALICE: Hi there.\nBOB: Hello.\n\n# Scene 2: Alley\nALICE: Run!\nBOB: Where?\n"
+	got := DiffScriptText(older, newer)
+	want := []ScriptDiffLine{
+		{Kind: ScriptLineChanged, OldLine: 2, NewLine: 2, Old: "ALICE: Hi.", New: "ALICE: Hi there.", Scene: "Scene 1: Rooftop"},
+		{Kind: ScriptLineRemoved, OldLine: 6, Old: "CAPTION: Later.", Scene: "Scene 2: Alley"},
+		{Kind: ScriptLineAdded, NewLine: 7, New: "BOB: Where?", Scene: "Scene 2: Alley"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("diff =\n%+v\nwant\n%+v", got, want)
+	}
+	if d := DiffScriptText(older, older); len(d) != 0 {
+		t.Fatalf("identical texts differ: %+v", d)
+	}
+	if d := DiffScriptText("", "ALICE: Hi.\r\n"); len(d) != 1 || d[0].Kind != ScriptLineAdded || d[0].New != "ALICE: Hi." {
+		t.Fatalf("diff from empty = %+v", d)
+	}
+}
+
+func TestRecordScriptSnapshot_SkipsDuplicatesAndPrunes(t *testing.T) {
+	root := t.TempDir()
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName)}
+	ctx := context.Background()
+	defer SetScriptSnapshotRetention(DefaultScriptSnapshotRetention)
+	SetScriptSnapshotRetention(ScriptSnapshotRetention{KeepLast: 3, MaxAge: 24 * time.Hour})
+
+	now := time.Now()
+	// An old snapshot falls out of the age window once a newer one exists
+	if ok, err := RecordScriptSnapshot(ctx, ph, "v0", now.Add(-48*time.Hour)); err != nil || !ok {
+		t.Fatalf("record v0: %v %v", ok, err)
+	}
+	for i, text := range []string{"v1", "v1", "v2", "v3", "v4"} {
+		if _, err := RecordScriptSnapshot(ctx, ph, text, now.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatalf("record %s: %v", text, err)
+		}
+	}
+	hist, err := ScriptHistory(ctx, root, 10)
+	if err != nil {
+		t.Fatalf("ScriptHistory: %v", err)
+	}
+	if len(hist) != 3 || hist[0].Bytes != 2 || hist[0].Lines != 1 {
+		t.Fatalf("history = %+v", hist)
+	}
+	diff, err := DiffScriptSnapshots(ctx, root, hist[2].ID, hist[0].ID)
+	if err != nil {
+		t.Fatalf("DiffScriptSnapshots: %v", err)
+	}
+	if len(diff) != 1 || diff[0].Old != "v2" || diff[0].New != "v4" {
+		t.Fatalf("diff = %+v", diff)
+	}
+	if text, err := ScriptSnapshotText(ctx, root, hist[1].ID); err != nil || text != "v3" {
+		t.Fatalf("ScriptSnapshotText = %q, %v", text, err)
+	}
+	if _, err := DiffScriptSnapshots(ctx, root, hist[0].ID, 9999); err == nil {
+		t.Fatalf("expected error for a missing snapshot")
+	}
+}
+
+func TestRecordScriptSnapshot_ClosedHandle(t *testing.T) {
+	root := t.TempDir()
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName)}
+	ix := ph.Index()
+	if ok, err := ix.RecordScriptSnapshot(context.Background(), "v1", time.Now()); err != nil || !ok {
+		t.Fatalf("record: %v %v", ok, err)
+	}
+	// A snapshot taken after the project was closed fails instead of reopening the index
+	_ = ph.Close()
+	if _, err := ix.RecordScriptSnapshot(context.Background(), "v2", time.Now()); !errors.Is(err, ErrIndexClosed) {
+		t.Fatalf("record after close: %v", err)
+	}
+}
+
+func TestWriteScriptRecordsHistory(t *testing.T) {
+	root := t.TempDir()
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName)}
+	for _, text := range []string{"ALICE: Hi.\n", "ALICE: Hi.\n", "ALICE: Bye.\n"} {
+		if err := WriteScript(ph, text); err != nil {
+			t.Fatalf("WriteScript: %v", err)
+		}
+	}
+	hist, err := ScriptHistory(context.Background(), root, 0)
+	if err != nil || len(hist) != 2 {
+		t.Fatalf("history = %+v (%v)", hist, err)
+	}
+}
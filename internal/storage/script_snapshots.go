@@ -41,12 +41,13 @@ func SaveScriptSnapshot(ctx context.Context, ph *ProjectHandle, text string, ts
 	if ph == nil {
 		return errors.New("nil ProjectHandle")
 	}
-	// Open or init index DB
-	db, err := InitOrOpenIndex(ph.Root)
+	ix, done := projectIndex(ph)
+	defer done()
+	db, err := ix.acquire()
 	if err != nil {
 		return err
 	}
-	defer func() { _ = db.Close() }()
+	defer ix.release()
 	_, err = db.ExecContext(ctx, insertScriptSnapshotSQL, ts.UTC().Format(time.RFC3339Nano), text)
 	return err
 }
@@ -56,11 +57,13 @@ func GetLatestScriptSnapshot(ctx context.Context, ph *ProjectHandle) (string, ti
 	if ph == nil {
 		return "", time.Time{}, errors.New("nil ProjectHandle")
 	}
-	db, err := InitOrOpenIndex(ph.Root)
+	ix, done := projectIndex(ph)
+	defer done()
+	db, err := ix.acquire()
 	if err != nil {
 		return "", time.Time{}, err
 	}
-	defer func() { _ = db.Close() }()
+	defer ix.release()
 	var tsStr string
 	var txt string
 	err = db.QueryRowContext(ctx, selectLatestScriptSnapshotSQL).Scan(&tsStr, &txt)
@@ -88,11 +91,13 @@ func ListScriptSnapshots(ctx context.Context, ph *ProjectHandle, limit int) ([]s
 	if limit <= 0 {
 		limit = 50
 	}
-	db, err := InitOrOpenIndex(ph.Root)
+	ix, done := projectIndex(ph)
+	defer done()
+	db, err := ix.acquire()
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = db.Close() }()
+	defer ix.release()
 	rows, err := db.QueryContext(ctx, listScriptSnapshotsSQL, limit)
 	if err != nil {
 		return nil, err
@@ -125,11 +130,13 @@ func PruneOldScriptSnapshots(ctx context.Context, ph *ProjectHandle, keepLast in
 	if keepLast <= 0 {
 		return 0, nil
 	}
-	db, err := InitOrOpenIndex(ph.Root)
+	ix, done := projectIndex(ph)
+	defer done()
+	db, err := ix.acquire()
 	if err != nil {
 		return 0, err
 	}
-	defer func() { _ = db.Close() }()
+	defer ix.release()
 	res, err := db.ExecContext(ctx, pruneOldScriptSnapshotsSQL, keepLast)
 	if err != nil {
 		return 0, err
@@ -17,11 +17,15 @@ import (
 )
 
 // SearchQuery describes the in-app search request.
-// Text uses SQLite FTS5 syntax (simple terms, phrases in quotes, AND/OR/NOT).
+// Text supports simple terms, phrases in quotes, AND/OR/NOT and prefix* (see SanitizeFTSQuery);
+// it is never passed to FTS5 verbatim.
 // Filters are optional. Tags should be provided without the leading @.
-// Types can restrict to kinds like: balloon, panel_notes, script, character, location, tag, etc.
+// Types can restrict to kinds like: balloon, page_notes, panel_notes, script, character, location, tag, asset, etc.
+// SearchFilterTypes lists the ones offered in the UI.
 // PageFrom/To are inclusive; 0 means unset.
 // Limit/Offset implement pagination; reasonable defaults applied if zero.
+// MarkStart/MarkEnd wrap the matches in SearchResult.Snippet; empty means [ and ]. Callers that parse
+// the snippet should pass SnippetMarkStart/SnippetMarkEnd, which cannot collide with user text.
 type SearchQuery struct {
 	Text      string
 	Character string
@@ -32,10 +36,20 @@ type SearchQuery struct {
 	PageTo    int
 	Limit     int
 	Offset    int
+	MarkStart string
+	MarkEnd   string
 }
 
+// SnippetMarkStart and SnippetMarkEnd are match markers from the Unicode private use area. Unlike
+// the default [ ], they never appear in script or lettering text, so a snippet can be split at them
+// without mistaking a bracket the user typed for a match.
+const (
+	SnippetMarkStart = "\ue000"
+	SnippetMarkEnd   = "\ue001"
+)
+
 // SearchResult represents a single match row.
-// Snippet is an optional highlighted excerpt using [ ] markers when FTS text is used.
+// Snippet is an optional highlighted excerpt with the query's match markers when FTS text is used.
 // PageID is 0 when unknown.
 // DocID can be used with WhereUsed to find references.
 type SearchResult struct {
@@ -52,11 +66,16 @@ func Search(ctx context.Context, projectRoot string, q SearchQuery) ([]SearchRes
 	if strings.TrimSpace(projectRoot) == "" {
 		return nil, errors.New("project root is required")
 	}
-	db, err := InitOrOpenIndex(projectRoot)
+	return withIndex(projectRoot, func(ix *IndexHandle) ([]SearchResult, error) { return ix.Search(ctx, q) })
+}
+
+// Search runs q against the handle's index; see the package-level Search.
+func (ix *IndexHandle) Search(ctx context.Context, q SearchQuery) ([]SearchResult, error) {
+	db, err := ix.acquire()
 	if err != nil {
 		return nil, err
 	}
-	defer db.Close()
+	defer ix.release()
 	return searchDB(ctx, db, q)
 }
 
@@ -65,11 +84,23 @@ func searchDB(ctx context.Context, db *sql.DB, q SearchQuery) ([]SearchResult, e
 	var args []any
 	var sb strings.Builder
 	useFTS := strings.TrimSpace(q.Text) != ""
+	start, end := q.MarkStart, q.MarkEnd
+	if start == "" && end == "" {
+		start, end = "[", "]"
+	}
 	if useFTS {
-		sb.WriteString("SELECT d.doc_id, d.type, d.path, COALESCE(d.page_id,0), snippet(fts_documents, 0, '[', ']', '…', 10)\n")
+		expr, err := SanitizeFTSQuery(q.Text)
+		if err != nil {
+			return nil, err
+		}
+		if expr == "" {
+			// Only punctuation was typed; nothing can match.
+			return []SearchResult{}, nil
+		}
+		sb.WriteString("SELECT d.doc_id, d.type, d.path, COALESCE(d.page_id,0), d.text\n")
 		sb.WriteString("FROM fts_documents JOIN documents d ON fts_documents.rowid = d.doc_id\n")
 		sb.WriteString("WHERE fts_documents MATCH ?\n")
-		args = append(args, q.Text)
+		args = append(args, expr)
 	} else {
 		sb.WriteString("SELECT d.doc_id, d.type, d.path, COALESCE(d.page_id,0), ''\n")
 		sb.WriteString("FROM documents d\nWHERE 1=1\n")
@@ -99,11 +130,31 @@ func searchDB(ctx context.Context, db *sql.DB, q SearchQuery) ([]SearchResult, e
 		sb.WriteString(" AND ( (d.character_id IS NOT NULL AND lower(d.character_id)=?) OR lower(d.text) LIKE ? OR lower(d.path) LIKE ? )\n")
 		args = append(args, ss, likeContains(ss+":"), likeContains("character:"+ss))
 	}
-	// Scene filter: location-related content or text contains the scene token
+	// Scene filter: the bible location of that name or alias and the documents linked to it, else (for
+	// scenes without a bible location) location-related content or text containing the scene token
 	if s := strings.TrimSpace(q.Scene); s != "" {
-		ss := strings.ToLower(s)
-		sb.WriteString(" AND ( lower(d.path) LIKE ? OR lower(d.text) LIKE ? )\n")
-		args = append(args, likeContains("location:"+ss), likeContains(ss))
+		ids, names, err := locationDocsNamed(ctx, db, s)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) > 0 {
+			var paths []any
+			for _, n := range names {
+				paths = append(paths, "bible:location_aliases:"+n, "bible:location_notes:"+n)
+			}
+			in := placeholders(len(ids))
+			sb.WriteString(" AND ( d.doc_id IN (" + in + ") OR d.doc_id IN (SELECT from_id FROM cross_refs WHERE to_id IN (" + in + ")) OR d.path IN (" + placeholders(len(paths)) + ") )\n")
+			for range 2 {
+				for _, id := range ids {
+					args = append(args, id)
+				}
+			}
+			args = append(args, paths...)
+		} else {
+			ss := strings.ToLower(s)
+			sb.WriteString(" AND ( lower(d.path) LIKE ? OR lower(d.text) LIKE ? )\n")
+			args = append(args, likeContains("location:"+ss), likeContains(ss))
+		}
 	}
 	// Tags: require all tags to appear as @tag tokens in text
 	for _, t := range q.Tags {
@@ -142,24 +193,61 @@ func searchDB(ctx context.Context, db *sql.DB, q SearchQuery) ([]SearchResult, e
 		if page.Valid {
 			r.PageID = int(page.Int64)
 		}
-		if sn.Valid {
-			r.Snippet = sn.String
+		if useFTS && sn.Valid {
+			r.Snippet = matchSnippet(sn.String, q.Text, start, end)
 		}
 		out = append(out, r)
 	}
 	return out, rows.Err()
 }
 
+// locationDocsNamed returns the bible location documents whose name or one of its aliases equals name
+// (case-insensitive), and those locations' names.
+func locationDocsNamed(ctx context.Context, db *sql.DB, name string) ([]int64, []string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT d.doc_id, d.text, COALESCE(a.text, '') FROM documents d
+		LEFT JOIN documents a ON a.type = 'location_aliases' AND a.path = 'bible:location_aliases:' || d.text
+		WHERE d.type = 'location'`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("scene locations: %w", err)
+	}
+	defer rows.Close()
+	var ids []int64
+	var names []string
+	for rows.Next() {
+		var id int64
+		var loc, aliases string
+		if err := rows.Scan(&id, &loc, &aliases); err != nil {
+			return nil, nil, fmt.Errorf("scan location: %w", err)
+		}
+		match := strings.EqualFold(strings.TrimSpace(loc), name)
+		for _, a := range strings.Split(aliases, ",") {
+			match = match || strings.EqualFold(strings.TrimSpace(a), name)
+		}
+		if match {
+			ids = append(ids, id)
+			names = append(names, loc)
+		}
+	}
+	return ids, names, rows.Err()
+}
+
 // WhereUsed returns documents that reference the given target document ID using cross_refs.
 func WhereUsed(ctx context.Context, projectRoot string, targetDocID int64, limit, offset int) ([]SearchResult, error) {
 	if strings.TrimSpace(projectRoot) == "" {
 		return nil, errors.New("project root is required")
 	}
-	db, err := InitOrOpenIndex(projectRoot)
+	return withIndex(projectRoot, func(ix *IndexHandle) ([]SearchResult, error) {
+		return ix.WhereUsed(ctx, targetDocID, limit, offset)
+	})
+}
+
+// WhereUsed returns documents that reference the given target document ID using cross_refs.
+func (ix *IndexHandle) WhereUsed(ctx context.Context, targetDocID int64, limit, offset int) ([]SearchResult, error) {
+	db, err := ix.acquire()
 	if err != nil {
 		return nil, err
 	}
-	defer db.Close()
+	defer ix.release()
 	if limit <= 0 {
 		limit = 100
 	}
@@ -197,20 +285,35 @@ func WhereUsedByPath(ctx context.Context, projectRoot string, path string, limit
 	if strings.TrimSpace(path) == "" {
 		return nil, errors.New("path is required")
 	}
-	db, err := InitOrOpenIndex(projectRoot)
+	return withIndex(projectRoot, func(ix *IndexHandle) ([]SearchResult, error) {
+		return ix.WhereUsedByPath(ctx, path, limit, offset)
+	})
+}
+
+// WhereUsedByPath resolves a document by path then returns references to it.
+func (ix *IndexHandle) WhereUsedByPath(ctx context.Context, path string, limit, offset int) ([]SearchResult, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, errors.New("path is required")
+	}
+	id, err := ix.docIDByPath(ctx, path)
+	if errors.Is(err, sql.ErrNoRows) {
+		return []SearchResult{}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer db.Close()
-	var id int64
-	err = db.QueryRowContext(ctx, "SELECT doc_id FROM documents WHERE path=?", path).Scan(&id)
+	return ix.WhereUsed(ctx, id, limit, offset)
+}
+
+func (ix *IndexHandle) docIDByPath(ctx context.Context, path string) (int64, error) {
+	db, err := ix.acquire()
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return []SearchResult{}, nil
-		}
-		return nil, err
+		return 0, err
 	}
-	return WhereUsed(ctx, projectRoot, id, limit, offset)
+	defer ix.release()
+	var id int64
+	err = db.QueryRowContext(ctx, "SELECT doc_id FROM documents WHERE path=?", path).Scan(&id)
+	return id, err
 }
 
 func likeContains(s string) string { return "%" + s + "%" }
@@ -0,0 +1,249 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidQuery is returned (wrapped in a *QueryError) when the search text cannot be
+// turned into a valid FTS5 MATCH expression, e.g. because of an unbalanced quote.
+var ErrInvalidQuery = errors.New("invalid search query")
+
+// QueryError describes a search syntax problem. Pos is the 0-based rune offset into the
+// original query text where the problem was detected.
+type QueryError struct {
+	Pos int
+	Msg string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("invalid search query at position %d: %s", e.Pos, e.Msg)
+}
+
+// Is reports ErrInvalidQuery so callers can use errors.Is without type assertions.
+func (e *QueryError) Is(target error) bool { return target == ErrInvalidQuery }
+
+// queryToken is a lexical item of the user-facing search syntax.
+type queryToken struct {
+	kind   int // tokTerm, tokPhrase or tokOp
+	text   string
+	prefix bool // trailing * on a term for prefix matching
+	pos    int
+}
+
+const (
+	tokTerm = iota
+	tokPhrase
+	tokOp
+)
+
+// SanitizeFTSQuery converts free-form user input into a safe FTS5 MATCH expression.
+//
+// Supported syntax:
+//   - bare words are phrase-quoted, so punctuation like don't or 3x3 is searched literally
+//   - "quoted phrases" are kept as phrases
+//   - AND, OR, NOT (upper-case) are operators; adjacent terms are implicitly ANDed
+//   - a trailing * on a bare word requests a prefix match (e.g. bal*)
+//
+// Everything else, including parentheses and FTS5 keywords like NEAR, is treated as literal text.
+// Words without any letter or digit are dropped because the tokenizer would ignore them anyway.
+// An unbalanced quote or a dangling operator yields a *QueryError wrapping ErrInvalidQuery.
+// The returned expression is empty when the input contains nothing searchable.
+func SanitizeFTSQuery(text string) (string, error) {
+	return buildQuery(text, func(t queryToken) string { return quoteFTS(t.text, t.prefix) }, nil, "")
+}
+
+// tsOperators are the Postgres tsquery forms of the search operators; NOT binds to the term after it.
+var tsOperators = map[string]string{"AND": "&", "OR": "|", "NOT": "& !"}
+
+// TSQuery converts the search syntax of SanitizeFTSQuery into a Postgres tsquery expression for
+// to_tsquery('simple', …), so the server matches what the local index matches: terms of several
+// words and quoted phrases become <-> sequences, a trailing * a :* prefix, and adjacent terms are
+// ANDed. Errors and the empty result are the same as SanitizeFTSQuery's.
+func TSQuery(text string) (string, error) {
+	return buildQuery(text, tsTerm, tsOperators, "&")
+}
+
+// buildQuery checks the operators of text and joins its terms, converted by term, and its operators,
+// renamed by ops when given, with implicit between adjacent terms.
+func buildQuery(text string, term func(queryToken) string, ops map[string]string, implicit string) (string, error) {
+	toks, err := lexQuery(text)
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+	prevOp := true // start of input behaves like "after an operator"
+	var lastOp *queryToken
+	for i := range toks {
+		t := toks[i]
+		if t.kind == tokOp {
+			if prevOp {
+				return "", &QueryError{Pos: t.pos, Msg: fmt.Sprintf("%s needs a search term before it", t.text)}
+			}
+			op := t.text
+			if ops != nil {
+				op = ops[op]
+			}
+			parts = append(parts, op)
+			prevOp = true
+			lastOp = &toks[i]
+			continue
+		}
+		if !prevOp && implicit != "" {
+			parts = append(parts, implicit)
+		}
+		parts = append(parts, term(t))
+		prevOp = false
+	}
+	if prevOp && lastOp != nil {
+		return "", &QueryError{Pos: lastOp.pos, Msg: fmt.Sprintf("%s needs a search term after it", lastOp.text)}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// tsTerm returns a term or phrase as quoted tsquery lexemes, the words in sequence.
+func tsTerm(t queryToken) string {
+	spans := splitWords(t.text)
+	words := make([]string, len(spans))
+	for i, w := range spans {
+		words[i] = "'" + strings.ReplaceAll(t.text[w.start:w.end], "'", "''") + "'"
+	}
+	if t.prefix {
+		words[len(words)-1] += ":*"
+	}
+	if len(words) == 1 {
+		return words[0]
+	}
+	return "(" + strings.Join(words, " <-> ") + ")"
+}
+
+// lexQuery splits the input into terms, phrases and operators, dropping empty terms.
+func lexQuery(text string) ([]queryToken, error) {
+	rs := []rune(text)
+	var out []queryToken
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			start := i
+			j := i + 1
+			for j < len(rs) && rs[j] != '"' {
+				j++
+			}
+			if j >= len(rs) {
+				return nil, &QueryError{Pos: start, Msg: "unbalanced quote; check your quotes"}
+			}
+			phrase := strings.TrimSpace(string(rs[i+1 : j]))
+			if hasSearchable(phrase) {
+				out = append(out, queryToken{kind: tokPhrase, text: phrase, pos: start})
+			}
+			i = j + 1
+		default:
+			start := i
+			j := i
+			for j < len(rs) && !unicode.IsSpace(rs[j]) && rs[j] != '"' {
+				j++
+			}
+			word := string(rs[i:j])
+			i = j
+			switch word {
+			case "AND", "OR", "NOT":
+				out = append(out, queryToken{kind: tokOp, text: word, pos: start})
+				continue
+			}
+			prefix := false
+			if len(word) > 1 && strings.HasSuffix(word, "*") {
+				word = strings.TrimRight(word, "*")
+				prefix = true
+			}
+			if !hasSearchable(word) {
+				continue
+			}
+			out = append(out, queryToken{kind: tokTerm, text: word, prefix: prefix, pos: start})
+		}
+	}
+	return out, nil
+}
+
+// quoteFTS wraps s as an FTS5 string, doubling embedded quotes.
+func quoteFTS(s string, prefix bool) string {
+	q := `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	if prefix {
+		q += "*"
+	}
+	return q
+}
+
+func hasSearchable(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchFilterTypes are the document types a search can be restricted to from the UI, in the order
+// they are offered.
+var SearchFilterTypes = []string{
+	"balloon", "caption", "sfx", "page_notes", "panel_notes", "scene", "script", "character", "location", "tag", "asset",
+}
+
+// typeFilterPrefix starts a type filter in the search text, e.g. type:page_notes or type:balloon,caption.
+const typeFilterPrefix = "type:"
+
+// ParseTypeFilters takes the type filters out of a search text and returns the remaining text and the
+// filtered types in order of appearance. Filters inside quoted phrases are text. A filter naming a type
+// not in SearchFilterTypes, or none, yields a *QueryError wrapping ErrInvalidQuery.
+func ParseTypeFilters(text string) (string, []string, error) {
+	var rest strings.Builder
+	var types []string
+	runes := []rune(text)
+	inQuote := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		atWord := i == 0 || unicode.IsSpace(runes[i-1])
+		n := len(typeFilterPrefix)
+		if inQuote || !atWord || i+n > len(runes) || !strings.EqualFold(string(runes[i:i+n]), typeFilterPrefix) {
+			if r == '"' {
+				inQuote = !inQuote
+			}
+			rest.WriteRune(r)
+			continue
+		}
+		start := i + n
+		end := start
+		for end < len(runes) && !unicode.IsSpace(runes[end]) {
+			end++
+		}
+		if start == end {
+			return "", nil, &QueryError{Pos: i, Msg: "type filter without a type"}
+		}
+		pos := start
+		for _, t := range strings.Split(string(runes[start:end]), ",") {
+			name := strings.ToLower(t)
+			if !slices.Contains(SearchFilterTypes, name) {
+				return "", nil, &QueryError{Pos: pos, Msg: fmt.Sprintf("unknown type %q", t)}
+			}
+			if !slices.Contains(types, name) {
+				types = append(types, name)
+			}
+			pos += len([]rune(t)) + 1
+		}
+		i = end - 1
+	}
+	return strings.Join(strings.Fields(rest.String()), " "), types, nil
+}
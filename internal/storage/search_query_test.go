@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestSanitizeFTSQuery(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+		errPos  int
+	}{
+		{"single term", "hello", `"hello"`, false, 0},
+		{"implicit and", "hello world", `"hello" "world"`, false, 0},
+		{"apostrophe", "don't", `"don't"`, false, 0},
+		{"digits and letters", "3x3", `"3x3"`, false, 0},
+		{"phrase", `"good morning" sun`, `"good morning" "sun"`, false, 0},
+		{"operators", "cat OR dog NOT bird", `"cat" OR "dog" NOT "bird"`, false, 0},
+		{"lowercase operators are words", "cats and dogs", `"cats" "and" "dogs"`, false, 0},
+		{"prefix", "bal*", `"bal"*`, false, 0},
+		{"stray near", "NEAR( foo", `"NEAR(" "foo"`, false, 0},
+		{"column filter is literal", "text:foo", `"text:foo"`, false, 0},
+		{"caret and minus", "^start -minus", `"^start" "-minus"`, false, 0},
+		{"punctuation only", "!!! ... ()", "", false, 0},
+		{"empty phrase dropped", `"" hello`, `"hello"`, false, 0},
+		{"unicode", "Straße über", `"Straße" "über"`, false, 0},
+		{"unbalanced quote", `say "hello`, "", true, 4},
+		{"unbalanced after unicode", `ü "x`, "", true, 2},
+		{"leading operator", "OR cat", "", true, 0},
+		{"trailing operator", "cat AND", "", true, 4},
+		{"double operator", "cat AND OR dog", "", true, 8},
+		{"operator before dropped term", "cat AND ???", "", true, 4},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SanitizeFTSQuery(tc.in)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, got %q", tc.in, got)
+				}
+				if !errors.Is(err, ErrInvalidQuery) {
+					t.Fatalf("expected ErrInvalidQuery, got %v", err)
+				}
+				var qe *QueryError
+				if !errors.As(err, &qe) {
+					t.Fatalf("expected *QueryError, got %T", err)
+				}
+				if qe.Pos != tc.errPos {
+					t.Fatalf("error pos = %d, want %d (%v)", qe.Pos, tc.errPos, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("SanitizeFTSQuery(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTSQuery(t *testing.T) {
+	for in, want := range map[string]string{
+		"hello":               `'hello'`,
+		"hello world":         `'hello' & 'world'`,
+		"don't":               `('don' <-> 't')`,
+		`"good morning" sun`:  `('good' <-> 'morning') & 'sun'`,
+		"cat OR dog NOT bird": `'cat' | 'dog' & ! 'bird'`,
+		"cat AND dog":         `'cat' & 'dog'`,
+		"bal*":                `'bal':*`,
+		"rock'n*":             `('rock' <-> 'n':*)`,
+		"Straße über":         `'Straße' & 'über'`,
+		"!!! ... ()":          "",
+		"text:foo ^start":     `('text' <-> 'foo') & 'start'`,
+	} {
+		got, err := TSQuery(in)
+		if err != nil || got != want {
+			t.Errorf("TSQuery(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{`say "hello`, "OR cat", "cat AND", "cat AND OR dog"} {
+		if _, err := TSQuery(in); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("TSQuery(%q) err = %v, want ErrInvalidQuery", in, err)
+		}
+	}
+}
+
+func TestParseTypeFilters(t *testing.T) {
+	cases := []struct {
+		in     string
+		text   string
+		types  []string
+		errPos int // -1: no error
+	}{
+		{"storm", "storm", nil, -1},
+		{"type:page_notes storm", "storm", []string{"page_notes"}, -1},
+		{"storm TYPE:Panel_Notes", "storm", []string{"panel_notes"}, -1},
+		{"type:page_notes,panel_notes type:page_notes", "", []string{"page_notes", "panel_notes"}, -1},
+		{`"type:balloon" storm`, `"type:balloon" storm`, nil, -1},
+		{"subtype:balloon", "subtype:balloon", nil, -1},
+		{"storm type:", "", nil, 6},
+		{"type:balloon,ballon", "", nil, 13},
+	}
+	for _, tc := range cases {
+		text, types, err := ParseTypeFilters(tc.in)
+		if tc.errPos >= 0 {
+			var qe *QueryError
+			if !errors.As(err, &qe) || !errors.Is(err, ErrInvalidQuery) || qe.Pos != tc.errPos {
+				t.Errorf("ParseTypeFilters(%q) error = %v, want position %d", tc.in, err, tc.errPos)
+			}
+			continue
+		}
+		if err != nil || text != tc.text || fmt.Sprint(types) != fmt.Sprint(tc.types) {
+			t.Errorf("ParseTypeFilters(%q) = %q, %v, %v; want %q, %v", tc.in, text, types, err, tc.text, tc.types)
+		}
+	}
+}
+
+func TestSearchSpecialCharacters(t *testing.T) {
+	root := t.TempDir()
+	ph, err := InitProject(root, domain.Project{Name: "Quotes"})
+	if err != nil || ph == nil {
+		t.Fatalf("InitProject error: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	dsn := fmt.Sprintf("file:%s?cache=shared&_pragma=busy_timeout(2000)", filepath.ToSlash(IndexPath(root)))
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, `INSERT INTO documents(doc_id, type, path, page_id, text) VALUES(?,?,?,?,?)`,
+		2001, "balloon", "issue:1/page:1/panel:P1/balloon:B1", 1, "I don't like the 3x3 grid"); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	for _, q := range []string{"don't", "3x3", `"3x3 grid"`, "(grid)", "like*"} {
+		res, err := Search(ctx, root, SearchQuery{Text: q})
+		if err != nil {
+			t.Fatalf("search %q: %v", q, err)
+		}
+		if len(res) != 1 || res[0].DocID != 2001 {
+			t.Fatalf("search %q: expected doc 2001, got %+v", q, res)
+		}
+	}
+
+	if _, err := Search(ctx, root, SearchQuery{Text: `"unbalanced`}); !errors.Is(err, ErrInvalidQuery) {
+		t.Fatalf("expected ErrInvalidQuery for unbalanced quote, got %v", err)
+	}
+	res, err := Search(ctx, root, SearchQuery{Text: "!!!"})
+	if err != nil || len(res) != 0 {
+		t.Fatalf("punctuation-only search: res=%v err=%v", res, err)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"strings"
+	"unicode"
+)
+
+// snippetWords is the number of words a search excerpt shows.
+const snippetWords = 10
+
+// wordSpan is the byte range of one word within a text.
+type wordSpan struct{ start, end int }
+
+// matchSnippet builds an excerpt of text around the first word that matches query and wraps
+// every matching word in markStart/markEnd. fts_documents is contentless, so FTS5's snippet()
+// has no text to excerpt; this does the same job on the indexed document text.
+//
+// Words are runs of letters and digits compared case-insensitively, like the unicode61
+// tokenizer sees them. The excerpt is cut on word boundaries only, so multi-byte characters
+// are never split, and … marks the side where text was dropped.
+func matchSnippet(text, query, markStart, markEnd string) string {
+	words := splitWords(text)
+	if len(words) == 0 {
+		return ""
+	}
+	needles, prefixes := snippetNeedles(query)
+	matched := make([]bool, len(words))
+	first := -1
+	for i, w := range words {
+		if wordMatches(strings.ToLower(text[w.start:w.end]), needles, prefixes) {
+			matched[i] = true
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+	lo := 0
+	if first > 2 {
+		lo = first - 2
+	}
+	hi := lo + snippetWords
+	if hi > len(words) {
+		hi = len(words)
+		lo = max(0, hi-snippetWords)
+	}
+
+	var sb strings.Builder
+	if lo > 0 {
+		sb.WriteString("…")
+	} else {
+		sb.WriteString(text[:words[0].start])
+	}
+	for i := lo; i < hi; i++ {
+		if i > lo {
+			sb.WriteString(text[words[i-1].end:words[i].start])
+		}
+		w := text[words[i].start:words[i].end]
+		if matched[i] {
+			sb.WriteString(markStart + w + markEnd)
+		} else {
+			sb.WriteString(w)
+		}
+	}
+	if hi < len(words) {
+		sb.WriteString("…")
+	} else {
+		sb.WriteString(text[words[hi-1].end:])
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// splitWords returns the byte ranges of the letter/digit runs in s.
+func splitWords(s string) []wordSpan {
+	var out []wordSpan
+	start := -1
+	for i, r := range s {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			out = append(out, wordSpan{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		out = append(out, wordSpan{start, len(s)})
+	}
+	return out
+}
+
+// snippetNeedles lists the lower-cased words of the query that should be highlighted. Words
+// after NOT are skipped since they never occur in a match; prefix terms go into prefixes.
+func snippetNeedles(query string) (needles, prefixes []string) {
+	toks, err := lexQuery(query)
+	if err != nil {
+		return nil, nil
+	}
+	negated := false
+	for _, t := range toks {
+		if t.kind == tokOp {
+			negated = t.text == "NOT"
+			continue
+		}
+		if negated {
+			negated = false
+			continue
+		}
+		lower := strings.ToLower(t.text)
+		spans := splitWords(lower)
+		for i, w := range spans {
+			word := lower[w.start:w.end]
+			if t.prefix && i == len(spans)-1 {
+				prefixes = append(prefixes, word)
+			} else {
+				needles = append(needles, word)
+			}
+		}
+	}
+	return needles, prefixes
+}
+
+func wordMatches(word string, needles, prefixes []string) bool {
+	for _, n := range needles {
+		if word == n {
+			return true
+		}
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(word, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestMatchSnippet(t *testing.T) {
+	cases := []struct {
+		text, query, want string
+	}{
+		{"Beach scene with waves", "beach", "[Beach] scene with waves"},
+		{"Grüße aus Köln, sagt Jörg!", "köln", "Grüße aus [Köln], sagt Jörg!"},
+		{"東京 タワー 夜景", "タワー", "東京 [タワー] 夜景"},
+		{"Wow 🎉 party time", "party", "Wow 🎉 [party] time"},
+		{"balloon ballad ball", "bal*", "[balloon] [ballad] [ball]"},
+		{"sun and moon", "sun NOT moon", "[sun] and moon"},
+		{"one two three four five six seven eight nine ten eleven twelve thirteen", "nine",
+			"…four five six seven eight [nine] ten eleven twelve thirteen"},
+		{"ä b c d e f g h i j k l", "ä", "[ä] b c d e f g h i j…"},
+	}
+	for _, c := range cases {
+		got := matchSnippet(c.text, c.query, "[", "]")
+		if got != c.want {
+			t.Errorf("matchSnippet(%q, %q) = %q; want %q", c.text, c.query, got, c.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("matchSnippet(%q, %q) returned invalid UTF-8", c.text, c.query)
+		}
+	}
+}
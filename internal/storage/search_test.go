@@ -90,6 +90,20 @@ func TestSearchAndWhereUsed(t *testing.T) {
 		t.Fatalf("expected doc 1001 in results")
 	}
 
+	// Snippet markers: [ ] by default, the private use markers on request
+	for _, c := range []struct {
+		q    SearchQuery
+		want string
+	}{
+		{SearchQuery{Text: "Beach"}, "[Beach] scene with waves"},
+		{SearchQuery{Text: "Beach", MarkStart: SnippetMarkStart, MarkEnd: SnippetMarkEnd}, SnippetMarkStart + "Beach" + SnippetMarkEnd + " scene with waves"},
+	} {
+		res, err := Search(ctx, root, c.q)
+		if err != nil || len(res) != 1 || res[0].Snippet != c.want {
+			t.Fatalf("snippet for %+v = %+v, %v; want %q", c.q, res, err, c.want)
+		}
+	}
+
 	// 2) Tag filter @greet within page range 2..5
 	res, err = Search(ctx, root, SearchQuery{Tags: []string{"greet"}, PageFrom: 2, PageTo: 5})
 	if err != nil {
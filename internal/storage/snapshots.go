@@ -14,12 +14,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 )
 
 // language=SQL
 // dialect=SQLite
-const insertSnapshotSQL = `INSERT INTO snapshots(page_id, ts, delta_blob) VALUES (?, ?, ?)`
+const insertSnapshotSQL = `INSERT INTO snapshots(page_id, ts, delta_blob, label) VALUES (?, ?, ?, ?)`
 
 // language=SQL
 // dialect=SQLite
@@ -27,7 +28,7 @@ const selectLatestSnapshotSQL = `SELECT ts, delta_blob FROM snapshots WHERE page
 
 // language=SQL
 // dialect=SQLite
-const listSnapshotsSQL = `SELECT ts, delta_blob FROM snapshots WHERE page_id = ? ORDER BY ts DESC LIMIT ?`
+const listSnapshotsSQL = `SELECT ts, delta_blob, label FROM snapshots WHERE page_id = ? ORDER BY ts DESC LIMIT ?`
 
 // language=SQL
 // dialect=SQLite
@@ -35,19 +36,21 @@ const pruneOldSnapshotsSQL = `DELETE FROM snapshots WHERE page_id = ? AND id NOT
 	SELECT id FROM snapshots WHERE page_id = ? ORDER BY ts DESC LIMIT ?
 )`
 
-// SaveSnapshot persists a page snapshot delta blob with a timestamp.
+// SaveSnapshot persists a page snapshot delta blob with a timestamp and the label of the change it
+// undoes (e.g. "Delete Page 4"; may be empty).
 // It opens the project's index database if needed and inserts the record.
-func SaveSnapshot(ctx context.Context, ph *ProjectHandle, pageNumber int, delta []byte, ts time.Time) error {
+func SaveSnapshot(ctx context.Context, ph *ProjectHandle, pageNumber int, delta []byte, ts time.Time, label string) error {
 	if ph == nil {
 		return errors.New("nil ProjectHandle")
 	}
-	// Open or init index DB
-	db, err := InitOrOpenIndex(ph.Root)
+	ix, done := projectIndex(ph)
+	defer done()
+	db, err := ix.acquire()
 	if err != nil {
 		return err
 	}
-	defer func() { _ = db.Close() }()
-	_, err = db.ExecContext(ctx, insertSnapshotSQL, pageNumber, ts.UTC().Format(time.RFC3339Nano), delta)
+	defer ix.release()
+	_, err = db.ExecContext(ctx, insertSnapshotSQL, pageNumber, ts.UTC().Format(time.RFC3339Nano), delta, label)
 	return err
 }
 
@@ -56,11 +59,13 @@ func GetLatestSnapshot(ctx context.Context, ph *ProjectHandle, pageNumber int) (
 	if ph == nil {
 		return nil, time.Time{}, errors.New("nil ProjectHandle")
 	}
-	db, err := InitOrOpenIndex(ph.Root)
+	ix, done := projectIndex(ph)
+	defer done()
+	db, err := ix.acquire()
 	if err != nil {
 		return nil, time.Time{}, err
 	}
-	defer func() { _ = db.Close() }()
+	defer ix.release()
 	var tsStr string
 	var blob []byte
 	err = db.QueryRowContext(ctx, selectLatestSnapshotSQL, pageNumber).Scan(&tsStr, &blob)
@@ -77,10 +82,11 @@ func GetLatestSnapshot(ctx context.Context, ph *ProjectHandle, pageNumber int) (
 	return blob, ts, nil
 }
 
-// ListSnapshots returns up to limit most recent snapshots for a page.
+// ListSnapshots returns up to limit most recent snapshots for a page, newest first.
 func ListSnapshots(ctx context.Context, ph *ProjectHandle, pageNumber int, limit int) ([]struct {
-	TS   time.Time
-	Blob []byte
+	TS    time.Time
+	Blob  []byte
+	Label string
 }, error) {
 	if ph == nil {
 		return nil, errors.New("nil ProjectHandle")
@@ -88,35 +94,89 @@ func ListSnapshots(ctx context.Context, ph *ProjectHandle, pageNumber int, limit
 	if limit <= 0 {
 		limit = 50
 	}
-	db, err := InitOrOpenIndex(ph.Root)
+	ix, done := projectIndex(ph)
+	defer done()
+	db, err := ix.acquire()
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = db.Close() }()
+	defer ix.release()
 	rows, err := db.QueryContext(ctx, listSnapshotsSQL, pageNumber, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = rows.Close() }()
 	var out []struct {
-		TS   time.Time
-		Blob []byte
+		TS    time.Time
+		Blob  []byte
+		Label string
 	}
 	for rows.Next() {
-		var tsStr string
+		var tsStr, label string
 		var blob []byte
-		if err := rows.Scan(&tsStr, &blob); err != nil {
+		if err := rows.Scan(&tsStr, &blob, &label); err != nil {
 			return nil, err
 		}
 		ts, _ := time.Parse(time.RFC3339Nano, tsStr)
 		out = append(out, struct {
-			TS   time.Time
-			Blob []byte
-		}{TS: ts, Blob: blob})
+			TS    time.Time
+			Blob  []byte
+			Label string
+		}{TS: ts, Blob: blob, Label: label})
 	}
 	return out, rows.Err()
 }
 
+// language=SQL
+// dialect=SQLite
+const selectSnapshotTimesSQL = `SELECT id, ts FROM snapshots WHERE page_id = ?`
+
+// DeleteSnapshotsAfter deletes the snapshots of a page taken after ts, e.g. the ones an undo took back;
+// a zero ts deletes them all. It returns how many were deleted.
+func DeleteSnapshotsAfter(ctx context.Context, ph *ProjectHandle, pageNumber int, ts time.Time) (int64, error) {
+	if ph == nil {
+		return 0, errors.New("nil ProjectHandle")
+	}
+	ix, done := projectIndex(ph)
+	defer done()
+	db, err := ix.acquire()
+	if err != nil {
+		return 0, err
+	}
+	defer ix.release()
+	// Compare parsed times: RFC3339Nano drops trailing zeros, so the stored text does not sort by time
+	rows, err := db.QueryContext(ctx, selectSnapshotTimesSQL, pageNumber)
+	if err != nil {
+		return 0, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		var tsStr string
+		if err := rows.Scan(&id, &tsStr); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		if t, err := time.Parse(time.RFC3339Nano, tsStr); err != nil || t.After(ts) {
+			ids = append(ids, id)
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	var n int64
+	for _, id := range ids {
+		res, err := db.ExecContext(ctx, `DELETE FROM snapshots WHERE id = ?`, id)
+		if err != nil {
+			return n, err
+		}
+		k, _ := res.RowsAffected()
+		n += k
+	}
+	return n, nil
+}
+
 // PruneOldSnapshots keeps at most keepLast snapshots for the page and deletes older ones.
 func PruneOldSnapshots(ctx context.Context, ph *ProjectHandle, pageNumber int, keepLast int) (int64, error) {
 	if ph == nil {
@@ -125,11 +185,13 @@ func PruneOldSnapshots(ctx context.Context, ph *ProjectHandle, pageNumber int, k
 	if keepLast <= 0 {
 		return 0, nil
 	}
-	db, err := InitOrOpenIndex(ph.Root)
+	ix, done := projectIndex(ph)
+	defer done()
+	db, err := ix.acquire()
 	if err != nil {
 		return 0, err
 	}
-	defer func() { _ = db.Close() }()
+	defer ix.release()
 	// Delete snapshots not in the newest keepLast set
 	res, err := db.ExecContext(ctx, pruneOldSnapshotsSQL, pageNumber, pageNumber, keepLast)
 	if err != nil {
@@ -137,3 +199,31 @@ func PruneOldSnapshots(ctx context.Context, ph *ProjectHandle, pageNumber int, k
 	}
 	return res.RowsAffected()
 }
+
+// ensureSnapshotsMigrated adds the label column to a snapshots table created before schema version 4;
+// existing snapshots get an empty label. It is safe to run more than once.
+func ensureSnapshotsMigrated(ctx context.Context, q sqlQuerier) error {
+	rows, err := q.QueryContext(ctx, `PRAGMA table_info(snapshots);`)
+	if err != nil {
+		return fmt.Errorf("table_info snapshots: %w", err)
+	}
+	found := false
+	for rows.Next() {
+		var cid, notnull, pk int
+		var name, typ string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &typ, &notnull, &dflt, &pk); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan table_info snapshots: %w", err)
+		}
+		found = found || name == "label"
+	}
+	_ = rows.Close()
+	if found {
+		return nil
+	}
+	if _, err := q.ExecContext(ctx, `ALTER TABLE snapshots ADD COLUMN label TEXT NOT NULL DEFAULT ''`); err != nil {
+		return fmt.Errorf("add snapshots.label: %w", err)
+	}
+	return nil
+}
@@ -12,6 +12,8 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -31,7 +33,7 @@ func TestSnapshotsCRUD(t *testing.T) {
 		t.Fatalf("db.Close error: %v", err)
 	}
 	delta1 := []byte("hello")
-	if err := SaveSnapshot(ctx, ph, 1, delta1, time.Now()); err != nil {
+	if err := SaveSnapshot(ctx, ph, 1, delta1, time.Now(), ""); err != nil {
 		t.Fatalf("SaveSnapshot: %v", err)
 	}
 	blob, _, err := GetLatestSnapshot(ctx, ph, 1)
@@ -41,7 +43,7 @@ func TestSnapshotsCRUD(t *testing.T) {
 	// Add more snapshots
 	for i := 0; i < 5; i++ {
 		b := []byte{byte('a' + i)}
-		if err := SaveSnapshot(ctx, ph, 1, b, time.Now().Add(time.Duration(i+1)*time.Millisecond)); err != nil {
+		if err := SaveSnapshot(ctx, ph, 1, b, time.Now().Add(time.Duration(i+1)*time.Millisecond), fmt.Sprintf("Move panel p%d", i)); err != nil {
 			t.Fatalf("SaveSnapshot %d: %v", i, err)
 		}
 	}
@@ -49,6 +51,9 @@ func TestSnapshotsCRUD(t *testing.T) {
 	if err != nil || len(list) != 6 {
 		t.Fatalf("ListSnapshots got %d err %v", len(list), err)
 	}
+	if list[0].Label != "Move panel p4" || string(list[0].Blob) != "e" || list[5].Label != "" {
+		t.Fatalf("ListSnapshots labels newest first = %q … %q", list[0].Label, list[5].Label)
+	}
 	// Prune keep last 3
 	n, err := PruneOldSnapshots(ctx, ph, 1, 3)
 	if err != nil {
@@ -61,6 +66,64 @@ func TestSnapshotsCRUD(t *testing.T) {
 	if err != nil || len(list) != 3 {
 		t.Fatalf("ListSnapshots after prune got %d err %v", len(list), err)
 	}
+	// Delete the two newest, then the rest
+	if n, err := DeleteSnapshotsAfter(ctx, ph, 1, list[2].TS); err != nil || n != 2 {
+		t.Fatalf("DeleteSnapshotsAfter = %d, %v; want 2", n, err)
+	}
+	if n, err := DeleteSnapshotsAfter(ctx, ph, 1, time.Time{}); err != nil || n != 1 {
+		t.Fatalf("DeleteSnapshotsAfter(zero) = %d, %v; want 1", n, err)
+	}
 	// Clean up DB file
 	_ = os.Remove(IndexPath(root))
 }
+
+func TestSnapshots_MigratesVersion3Table(t *testing.T) {
+	root := t.TempDir()
+	idx := IndexPath(root)
+	if err := os.MkdirAll(filepath.Dir(idx), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?cache=shared", filepath.ToSlash(idx)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []string{
+		`CREATE TABLE meta (key TEXT PRIMARY KEY, value TEXT NOT NULL);`,
+		`CREATE TABLE version (id INTEGER PRIMARY KEY CHECK(id=1), schema INTEGER NOT NULL, app TEXT, created_at TEXT NOT NULL, updated_at TEXT NOT NULL);`,
+		`INSERT INTO version VALUES(1, 3, 'test', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z');`,
+		`CREATE TABLE snapshots (id INTEGER PRIMARY KEY, page_id INTEGER NOT NULL, ts TEXT NOT NULL, delta_blob BLOB NOT NULL);`,
+		`INSERT INTO snapshots(page_id, ts, delta_blob) VALUES (0, '2025-01-01T00:00:00Z', x'6f6c64');`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("seed v3: %v (%s)", err, q)
+		}
+	}
+	_ = db.Close()
+
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName)}
+	ctx := context.Background()
+	if err := SaveSnapshot(ctx, ph, 0, []byte("new"), time.Now(), "Delete Page 4"); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+	list, err := ListSnapshots(ctx, ph, 0, 10)
+	if err != nil || len(list) != 2 {
+		t.Fatalf("ListSnapshots got %d err %v", len(list), err)
+	}
+	if list[0].Label != "Delete Page 4" || list[1].Label != "" || string(list[1].Blob) != "old" {
+		t.Fatalf("migrated snapshots = %+v", list)
+	}
+	db, err = InitOrOpenIndex(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+	var schema int
+	_ = db.QueryRow(`SELECT schema FROM version WHERE id=1`).Scan(&schema)
+	if schema != schemaVersion {
+		t.Fatalf("schema %d, want %d", schema, schemaVersion)
+	}
+	// Migrating again is a no-op
+	if err := ensureSnapshotsMigrated(ctx, db); err != nil {
+		t.Fatalf("second migration: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"fmt"
+
+	"gocomicwriter/internal/domain"
+)
+
+// SpreadWarning reports a spread that had to be split, or a panel crossing the spine of a page
+// that is not part of a spread.
+type SpreadWarning struct {
+	PageNumber int
+	PanelID    string // empty for warnings about the spread itself
+	Message    string
+}
+
+// pageIndexByNumber returns the slice index of the page numbered n, or -1.
+func pageIndexByNumber(iss domain.Issue, n int) int {
+	for i := range iss.Pages {
+		if iss.Pages[i].Number == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// validSpread reports whether the page at index i and its SpreadWith partner form a consistent pair:
+// both pages exist, are adjacent in the issue and point at each other.
+func validSpread(iss domain.Issue, i int) bool {
+	pg := iss.Pages[i]
+	if pg.SpreadWith == 0 || pg.SpreadWith == pg.Number {
+		return false
+	}
+	j := pageIndexByNumber(iss, pg.SpreadWith)
+	if j < 0 || (j != i-1 && j != i+1) {
+		return false
+	}
+	return iss.Pages[j].SpreadWith == pg.Number
+}
+
+// SpreadPartner returns the number of the page facing page n in a spread, or 0 when page n is a single page.
+func SpreadPartner(iss domain.Issue, n int) int {
+	i := pageIndexByNumber(iss, n)
+	if i < 0 || !validSpread(iss, i) {
+		return 0
+	}
+	return iss.Pages[i].SpreadWith
+}
+
+// SpreadSides returns the page numbers shown on the left and right of the spread containing page n.
+// Left-to-right issues put the lower page number on the left, right-to-left issues on the right.
+func SpreadSides(iss domain.Issue, n int) (left, right int, ok bool) {
+	p := SpreadPartner(iss, n)
+	if p == 0 {
+		return 0, 0, false
+	}
+	left, right = min(n, p), max(n, p)
+	if isRTL(iss) {
+		left, right = right, left
+	}
+	return left, right, true
+}
+
+// SetSpread joins the adjacent pages a and b into a double-page spread.
+func SetSpread(iss *domain.Issue, a, b int) error {
+	if iss == nil {
+		return fmt.Errorf("issue is nil")
+	}
+	i, j := pageIndexByNumber(*iss, a), pageIndexByNumber(*iss, b)
+	if i < 0 || j < 0 {
+		return fmt.Errorf("%w: %d or %d", ErrPageNotFound, a, b)
+	}
+	if j != i-1 && j != i+1 {
+		return fmt.Errorf("pages %d and %d are not adjacent", a, b)
+	}
+	for _, k := range []int{i, j} {
+		if p := SpreadPartner(*iss, iss.Pages[k].Number); p != 0 && p != a && p != b {
+			return fmt.Errorf("page %d is already part of a spread with page %d", iss.Pages[k].Number, p)
+		}
+	}
+	iss.Pages[i].SpreadWith = b
+	iss.Pages[j].SpreadWith = a
+	return nil
+}
+
+// SplitSpread turns the spread containing page n back into two single pages and returns
+// warnings for panels that now cross the spine.
+func SplitSpread(iss *domain.Issue, n int) []SpreadWarning {
+	if iss == nil {
+		return nil
+	}
+	p := SpreadPartner(*iss, n)
+	if i := pageIndexByNumber(*iss, n); i >= 0 {
+		iss.Pages[i].SpreadWith = 0
+	}
+	if p == 0 {
+		return nil
+	}
+	iss.Pages[pageIndexByNumber(*iss, p)].SpreadWith = 0
+	var out []SpreadWarning
+	for _, w := range SpineCrossings(*iss) {
+		if w.PageNumber == n || w.PageNumber == p {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// NormalizeSpreads clears SpreadWith on pages whose partner is missing, not adjacent or does not
+// point back, so that every remaining spread is a consistent pair.
+func NormalizeSpreads(iss *domain.Issue) []SpreadWarning {
+	if iss == nil {
+		return nil
+	}
+	var broken []int
+	for i := range iss.Pages {
+		if iss.Pages[i].SpreadWith != 0 && !validSpread(*iss, i) {
+			broken = append(broken, i)
+		}
+	}
+	out := make([]SpreadWarning, 0, len(broken))
+	for _, i := range broken {
+		pg := &iss.Pages[i]
+		out = append(out, SpreadWarning{PageNumber: pg.Number, Message: fmt.Sprintf("page %d no longer faces page %d; spread split", pg.Number, pg.SpreadWith)})
+		pg.SpreadWith = 0
+	}
+	return out
+}
+
+// RenumberPages numbers the issue's pages 1..n in slice order, e.g. after a page was removed.
+// Spread partners follow their pages; spreads that lost a page are split and reported.
+func RenumberPages(iss *domain.Issue) []SpreadWarning {
+	if iss == nil {
+		return nil
+	}
+	renum := make(map[int]int, len(iss.Pages))
+	for i := range iss.Pages {
+		renum[iss.Pages[i].Number] = i + 1
+	}
+	var out []SpreadWarning
+	for i := range iss.Pages {
+		pg := &iss.Pages[i]
+		pg.Number = i + 1
+		if pg.SpreadWith == 0 {
+			continue
+		}
+		if nn, ok := renum[pg.SpreadWith]; ok {
+			pg.SpreadWith = nn
+			continue
+		}
+		out = append(out, SpreadWarning{PageNumber: pg.Number, Message: fmt.Sprintf("page %d lost its facing page; spread split", pg.Number)})
+		pg.SpreadWith = 0
+	}
+	return append(out, NormalizeSpreads(iss)...)
+}
+
+// SpineCrossings lists panels extending past the bleed into a neighbouring page. On a spread page the
+// edge towards the facing page is open; everywhere else (single pages and the outer edges of a spread)
+// panels must stay within trim plus bleed.
+func SpineCrossings(iss domain.Issue) []SpreadWarning {
+	if iss.TrimWidth <= 0 {
+		return nil
+	}
+	const eps = 0.01
+	var out []SpreadWarning
+	for _, pg := range iss.Pages {
+		minX, maxX := -iss.Bleed, iss.TrimWidth+iss.Bleed
+		if left, right, ok := SpreadSides(iss, pg.Number); ok {
+			if pg.Number == left {
+				maxX = 2*iss.TrimWidth + iss.Bleed
+			} else if pg.Number == right {
+				minX = -iss.TrimWidth - iss.Bleed
+			}
+		}
+		for _, pn := range pg.Panels {
+			g := pn.Geometry
+			if g.X < minX-eps || g.X+g.Width > maxX+eps {
+				out = append(out, SpreadWarning{PageNumber: pg.Number, PanelID: pn.ID,
+					Message: fmt.Sprintf("panel %s on page %d crosses the page edge; only spread pages may span the spine", pn.ID, pg.Number)})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func spreadIssue(n int) domain.Issue {
+	iss := domain.Issue{TrimWidth: 600, TrimHeight: 900, Bleed: 9}
+	for i := 1; i <= n; i++ {
+		iss.Pages = append(iss.Pages, domain.Page{Number: i})
+	}
+	return iss
+}
+
+func TestSetSpreadAndSides(t *testing.T) {
+	iss := spreadIssue(4)
+	if err := SetSpread(&iss, 2, 4); err == nil {
+		t.Fatalf("expected error for non-adjacent pages")
+	}
+	if err := SetSpread(&iss, 3, 2); err != nil {
+		t.Fatalf("SetSpread: %v", err)
+	}
+	if err := SetSpread(&iss, 3, 4); err == nil {
+		t.Fatalf("expected error joining a page that is already in a spread")
+	}
+	if p := SpreadPartner(iss, 2); p != 3 {
+		t.Fatalf("partner of 2 = %d", p)
+	}
+	if l, r, ok := SpreadSides(iss, 3); !ok || l != 2 || r != 3 {
+		t.Fatalf("ltr sides = %d,%d,%v", l, r, ok)
+	}
+	iss.ReadingDirection = "rtl"
+	if l, r, ok := SpreadSides(iss, 2); !ok || l != 3 || r != 2 {
+		t.Fatalf("rtl sides = %d,%d,%v", l, r, ok)
+	}
+	if _, _, ok := SpreadSides(iss, 1); ok {
+		t.Fatalf("page 1 is not in a spread")
+	}
+}
+
+func TestRenumberPages_SplitsSpreadsThatLostAPage(t *testing.T) {
+	iss := spreadIssue(6)
+	_ = SetSpread(&iss, 2, 3)
+	_ = SetSpread(&iss, 5, 6)
+	// Delete page 3: page 2 loses its partner, the 5-6 spread becomes 4-5.
+	iss.Pages = append(iss.Pages[:2], iss.Pages[3:]...)
+	warns := RenumberPages(&iss)
+	if len(warns) != 1 || warns[0].PageNumber != 2 {
+		t.Fatalf("warnings = %+v", warns)
+	}
+	if iss.Pages[1].SpreadWith != 0 {
+		t.Fatalf("page 2 still in a spread: %d", iss.Pages[1].SpreadWith)
+	}
+	if SpreadPartner(iss, 4) != 5 || SpreadPartner(iss, 5) != 4 {
+		t.Fatalf("spread not renumbered: %+v", iss.Pages)
+	}
+}
+
+func TestNormalizeSpreads(t *testing.T) {
+	iss := spreadIssue(4)
+	iss.Pages[0].SpreadWith = 2 // not reciprocal
+	iss.Pages[1].SpreadWith = 3
+	iss.Pages[2].SpreadWith = 2
+	iss.Pages[3].SpreadWith = 9 // missing
+	warns := NormalizeSpreads(&iss)
+	if len(warns) != 2 || warns[0].PageNumber != 1 || warns[1].PageNumber != 4 {
+		t.Fatalf("warnings = %+v", warns)
+	}
+	if SpreadPartner(iss, 2) != 3 {
+		t.Fatalf("valid spread was split")
+	}
+}
+
+func TestSpineCrossings(t *testing.T) {
+	iss := spreadIssue(3)
+	// A splash starting on page 2 and running across the spine onto page 3.
+	iss.Pages[1].Panels = []domain.Panel{{ID: "splash", Geometry: domain.Rect{X: -9, Y: 0, Width: 1209, Height: 900}}}
+	iss.Pages[0].Panels = []domain.Panel{{ID: "full-bleed", Geometry: domain.Rect{X: -9, Y: -9, Width: 618, Height: 918}}}
+	if w := SpineCrossings(iss); len(w) != 1 || w[0].PanelID != "splash" {
+		t.Fatalf("single pages: %+v", w)
+	}
+	_ = SetSpread(&iss, 2, 3)
+	if w := SpineCrossings(iss); len(w) != 0 {
+		t.Fatalf("ltr spread: %+v", w)
+	}
+	// In right-to-left issues page 2 is the right-hand page, so the splash crosses its outer edge.
+	iss.ReadingDirection = "rtl"
+	if w := SpineCrossings(iss); len(w) != 1 {
+		t.Fatalf("rtl spread: %+v", w)
+	}
+	iss.ReadingDirection = ""
+	if w := SplitSpread(&iss, 3); len(w) != 1 || w[0].PageNumber != 2 {
+		t.Fatalf("split warnings: %+v", w)
+	}
+	if SpreadPartner(iss, 2) != 0 {
+		t.Fatalf("spread not split")
+	}
+}
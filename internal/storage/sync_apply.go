@@ -0,0 +1,236 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// syncParent is the "parent" object of a sync payload; see syncEntities.
+type syncParent struct {
+	Parent struct {
+		Issue int    `json:"issue"`
+		Page  int    `json:"page"`
+		Panel string `json:"panel"`
+	} `json:"parent"`
+}
+
+// ApplyRemoteOps applies pulled ops to ph.Project in order, the reverse of DiffProjects: creates and
+// updates set the entity from its payload, keeping the children pages and panels have locally, and
+// deletes remove it. Ops on entity types this version does not sync, on issues the project does not
+// have, or with a payload it cannot read are skipped and returned as errors after the rest is applied.
+// When ph tracks changes, the applied entities count as recorded, so the next Save does not queue
+// them back to the server. The change is in memory only; call Save to persist it.
+func ApplyRemoteOps(ph *ProjectHandle, ops []RemoteSyncOp) (applied int, skipped []error) {
+	if ph == nil {
+		return 0, nil
+	}
+	type ref struct{ Type, ID string }
+	touched := map[ref]bool{}
+	for _, op := range ops {
+		if err := applySyncOp(&ph.Project, op.SyncOp); err != nil {
+			skipped = append(skipped, fmt.Errorf("%s %s %s: %w", op.OpType, op.EntityType, op.EntityID, err))
+			continue
+		}
+		touched[ref{op.EntityType, op.EntityID}] = true
+		applied++
+	}
+	if ph.changes != nil && applied > 0 {
+		ph.changes.absorb(ph.Project, func(typ, id string) bool { return touched[ref{typ, id}] })
+	}
+	return applied, skipped
+}
+
+// absorb takes the entities for which touched reports true from cur into the tracker's base, so they
+// no longer differ from it.
+func (t *ChangeTracker) absorb(cur domain.Project, touched func(typ, id string) bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	next := make([]syncEntity, 0, len(t.base))
+	for _, e := range t.base {
+		if !touched(e.Type, e.ID) {
+			next = append(next, e)
+		}
+	}
+	for _, e := range syncEntities(cur) {
+		if touched(e.Type, e.ID) {
+			next = append(next, e)
+		}
+	}
+	t.base = next
+}
+
+func applySyncOp(p *domain.Project, op SyncOp) error {
+	var parent syncParent
+	if err := json.Unmarshal(op.Payload, &parent); err != nil {
+		return fmt.Errorf("read payload: %w", err)
+	}
+	del := op.OpType == SyncOpDelete
+	if !del && op.OpType != SyncOpCreate && op.OpType != SyncOpUpdate {
+		return fmt.Errorf("unknown op type %q", op.OpType)
+	}
+	var iss *domain.Issue
+	switch op.EntityType {
+	case SyncEntityPage, SyncEntityPanel, SyncEntityBalloon:
+		if parent.Parent.Issue < 1 || parent.Parent.Issue > len(p.Issues) {
+			return fmt.Errorf("issue %d not found", parent.Parent.Issue)
+		}
+		iss = &p.Issues[parent.Parent.Issue-1]
+	}
+	switch op.EntityType {
+	case SyncEntityPage:
+		var pg domain.Page
+		if err := json.Unmarshal(op.Payload, &pg); err != nil {
+			return fmt.Errorf("read page: %w", err)
+		}
+		i := slices.IndexFunc(iss.Pages, func(x domain.Page) bool { return x.Number == pg.Number })
+		switch {
+		case del:
+			if i >= 0 {
+				iss.Pages = slices.Delete(iss.Pages, i, i+1)
+			}
+		case i >= 0:
+			pg.Panels = iss.Pages[i].Panels
+			iss.Pages[i] = pg
+		default:
+			pg.Panels = nil
+			iss.Pages = append(iss.Pages, pg)
+			slices.SortStableFunc(iss.Pages, func(a, b domain.Page) int { return a.Number - b.Number })
+		}
+	case SyncEntityPanel:
+		var pn domain.Panel
+		if err := json.Unmarshal(op.Payload, &pn); err != nil {
+			return fmt.Errorf("read panel: %w", err)
+		}
+		// An update on the same page keeps the panel's place; a move appends it to the new page
+		if cur := syncPanel(iss, parent.Parent.Page, pn.ID); cur != nil && !del {
+			pn.Balloons = cur.Balloons
+			*cur = pn
+			return nil
+		}
+		if !del && syncPage(iss, parent.Parent.Page) == nil {
+			return fmt.Errorf("page %d: %w", parent.Parent.Page, ErrPageNotFound)
+		}
+		old, found := removeSyncPanel(iss, pn.ID)
+		if del {
+			return nil
+		}
+		pg := syncPage(iss, parent.Parent.Page)
+		pn.Balloons = nil
+		if found {
+			pn.Balloons = old.Balloons
+		}
+		pg.Panels = append(pg.Panels, pn)
+	case SyncEntityBalloon:
+		var b domain.Balloon
+		if err := json.Unmarshal(op.Payload, &b); err != nil {
+			return fmt.Errorf("read balloon: %w", err)
+		}
+		pn := syncPanel(iss, parent.Parent.Page, parent.Parent.Panel)
+		if pn == nil && !del {
+			return fmt.Errorf("panel %s: %w", parent.Parent.Panel, ErrPanelNotFound)
+		}
+		// An update in the same panel keeps the balloon's place in the lettering order
+		for pi := range iss.Pages {
+			for ni := range iss.Pages[pi].Panels {
+				from := &iss.Pages[pi].Panels[ni]
+				i := slices.IndexFunc(from.Balloons, func(x domain.Balloon) bool { return x.ID == b.ID })
+				if i < 0 {
+					continue
+				}
+				if from == pn && !del {
+					from.Balloons[i] = b
+					return nil
+				}
+				from.Balloons = slices.Delete(from.Balloons, i, i+1)
+			}
+		}
+		if del {
+			return nil
+		}
+		pn.Balloons = append(pn.Balloons, b)
+	case SyncEntityBibleCharacter:
+		var c domain.BibleCharacter
+		if err := json.Unmarshal(op.Payload, &c); err != nil {
+			return fmt.Errorf("read character: %w", err)
+		}
+		p.Bible.Characters = applyByName(p.Bible.Characters, c, op.EntityID, del, func(x domain.BibleCharacter) string { return x.Name })
+	case SyncEntityBibleLocation:
+		var l domain.BibleLocation
+		if err := json.Unmarshal(op.Payload, &l); err != nil {
+			return fmt.Errorf("read location: %w", err)
+		}
+		p.Bible.Locations = applyByName(p.Bible.Locations, l, op.EntityID, del, func(x domain.BibleLocation) string { return x.Name })
+	case SyncEntityBibleTag:
+		var t domain.BibleTag
+		if err := json.Unmarshal(op.Payload, &t); err != nil {
+			return fmt.Errorf("read tag: %w", err)
+		}
+		p.Bible.Tags = applyByName(p.Bible.Tags, t, op.EntityID, del, func(x domain.BibleTag) string { return x.Name })
+	default:
+		return fmt.Errorf("unknown entity type %q", op.EntityType)
+	}
+	return nil
+}
+
+// applyByName replaces, appends or removes the bible entry named id.
+func applyByName[T any](list []T, v T, id string, del bool, name func(T) string) []T {
+	i := slices.IndexFunc(list, func(x T) bool { return strings.EqualFold(name(x), id) })
+	switch {
+	case del:
+		if i >= 0 {
+			list = slices.Delete(list, i, i+1)
+		}
+	case i >= 0:
+		list[i] = v
+	default:
+		list = append(list, v)
+	}
+	return list
+}
+
+// removeSyncPanel removes the panel with the given ID from whichever page of iss holds it.
+func removeSyncPanel(iss *domain.Issue, id string) (domain.Panel, bool) {
+	for pi := range iss.Pages {
+		pg := &iss.Pages[pi]
+		if i := slices.IndexFunc(pg.Panels, func(x domain.Panel) bool { return x.ID == id }); i >= 0 {
+			pn := pg.Panels[i]
+			pg.Panels = slices.Delete(pg.Panels, i, i+1)
+			return pn, true
+		}
+	}
+	return domain.Panel{}, false
+}
+
+func syncPage(iss *domain.Issue, number int) *domain.Page {
+	for i := range iss.Pages {
+		if iss.Pages[i].Number == number {
+			return &iss.Pages[i]
+		}
+	}
+	return nil
+}
+
+func syncPanel(iss *domain.Issue, page int, id string) *domain.Panel {
+	pg := syncPage(iss, page)
+	if pg == nil {
+		return nil
+	}
+	for i := range pg.Panels {
+		if pg.Panels[i].ID == id {
+			return &pg.Panels[i]
+		}
+	}
+	return nil
+}
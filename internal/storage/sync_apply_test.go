@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+// asRemote turns generated ops into pulled ones.
+func asRemote(ops []SyncOp) []RemoteSyncOp {
+	out := make([]RemoteSyncOp, len(ops))
+	for i, op := range ops {
+		out[i] = RemoteSyncOp{SyncOp: op, Version: int64(i + 1)}
+	}
+	return out
+}
+
+func TestApplyRemoteOps_ReplaysDiff(t *testing.T) {
+	prev := syncTestProject()
+	cur := syncTestProject()
+	cur.Issues[0].Pages[0].Grid = "2x2"
+	cur.Issues[0].Pages = append(cur.Issues[0].Pages, domain.Page{Number: 2, Panels: []domain.Panel{{ID: "p2", Balloons: []domain.Balloon{{ID: "b3", Type: "thought"}}}}})
+	// b1 moves to the new panel, b2 changes in place, Alice goes, a tag comes
+	pn := &cur.Issues[0].Pages[0].Panels[0]
+	cur.Issues[0].Pages[1].Panels[0].Balloons = append(cur.Issues[0].Pages[1].Panels[0].Balloons, pn.Balloons[0])
+	pn.Balloons = pn.Balloons[1:]
+	pn.Balloons[0].Character = "Bob"
+	cur.Bible.Characters = nil
+	cur.Bible.Tags = []domain.BibleTag{{Name: "Noir"}}
+
+	ph := &ProjectHandle{Project: prev}
+	applied, skipped := ApplyRemoteOps(ph, asRemote(DiffProjects("k", 1, prev, cur)))
+	if len(skipped) > 0 || applied == 0 {
+		t.Fatalf("applied %d, skipped %v", applied, skipped)
+	}
+	if ops := DiffProjects("k", 2, ph.Project, cur); len(ops) != 0 {
+		t.Fatalf("left over after apply: %v", syncOpKeys(ops))
+	}
+	// Deleting page 2 with its panel leaves page 1 as it was
+	ApplyRemoteOps(ph, asRemote(DiffProjects("k", 3, cur, syncTestProject())))
+	if got := len(ph.Project.Issues[0].Pages); got != 1 {
+		t.Fatalf("pages after delete: %d", got)
+	}
+}
+
+func TestApplyRemoteOps_Skips(t *testing.T) {
+	ph := &ProjectHandle{Project: syncTestProject()}
+	ops := []RemoteSyncOp{
+		{SyncOp: SyncOp{OpType: "upsert", EntityType: "manifest", EntityID: "comic.json", Payload: json.RawMessage(`{}`)}},
+		{SyncOp: SyncOp{OpType: SyncOpUpdate, EntityType: SyncEntityPage, EntityID: "issue:3/page:1", Payload: json.RawMessage(`{"number":1,"parent":{"issue":3}}`)}},
+		{SyncOp: SyncOp{OpType: SyncOpCreate, EntityType: SyncEntityBalloon, EntityID: "issue:1/balloon:b9", Payload: json.RawMessage(`{"id":"b9","parent":{"issue":1,"page":1,"panel":"nope"}}`)}},
+		{SyncOp: SyncOp{OpType: SyncOpUpdate, EntityType: SyncEntityPage, EntityID: "issue:1/page:1", Payload: json.RawMessage(`not json`)}},
+		{SyncOp: SyncOp{OpType: SyncOpUpdate, EntityType: SyncEntityBibleTag, EntityID: "Noir", Payload: json.RawMessage(`{"name":"Noir"}`)}},
+	}
+	applied, skipped := ApplyRemoteOps(ph, ops)
+	if applied != 1 || len(skipped) != 4 || len(ph.Project.Bible.Tags) != 1 {
+		t.Fatalf("applied %d, skipped %v", applied, skipped)
+	}
+	if !reflect.DeepEqual(ph.Project.Issues, syncTestProject().Issues) {
+		t.Fatalf("skipped ops changed the issues")
+	}
+}
+
+func TestApplyRemoteOps_NotQueuedBack(t *testing.T) {
+	root := t.TempDir()
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName), Project: syncTestProject()}
+	defer func() { _ = ph.Close() }()
+	ph.TrackChanges()
+	remote := syncTestProject()
+	remote.Issues[0].Pages[0].Grid = "4x4"
+	// An unsaved local edit elsewhere is still queued
+	ph.Project.Bible.Locations[0].Aliases = []string{"Rooftop"}
+	ApplyRemoteOps(ph, asRemote(DiffProjects("k", 1, syncTestProject(), remote)))
+	if err := Save(ph); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	pending, _ := ph.Index().PendingSyncOps(context.Background(), 10)
+	if got := syncOpKeys(pending); !reflect.DeepEqual(got, []string{"update bible_location Roof"}) {
+		t.Fatalf("queued %v", got)
+	}
+	if ph.Project.Issues[0].Pages[0].Grid != "4x4" {
+		t.Fatalf("remote change not applied")
+	}
+}
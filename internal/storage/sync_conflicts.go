@@ -0,0 +1,257 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RemoteSyncOp is an op pulled from the server: Version is its server version and Actor the user who
+// pushed it.
+type RemoteSyncOp struct {
+	SyncOp
+	Version int64
+	Actor   string
+}
+
+// Resolutions of a sync conflict.
+const (
+	SyncKeepLocal  = "local"
+	SyncKeepRemote = "remote"
+)
+
+// SyncConflict is an entity changed locally and, without this client knowing, on the server. Local is
+// the latest local op on the entity, Remote the newest conflicting server op. Resolution is empty while
+// the conflict is open.
+type SyncConflict struct {
+	ID         int64
+	Local      SyncOp
+	Remote     RemoteSyncOp
+	DetectedAt time.Time
+	Resolution string
+}
+
+// language=SQL
+// dialect=SQLite
+const ownSyncOpSQL = `SELECT 1 FROM sync_outbox WHERE op_id = ?`
+
+// conflictingLocalOpSQL finds the latest local op on an entity that was queued without knowing the
+// remote op at version ?3 and either has not reached the server or reached it after the remote op, so
+// last-writer-wins would silently drop one of the two changes.
+//
+// language=SQL
+// dialect=SQLite
+const conflictingLocalOpSQL = `SELECT op_id, op_type, payload FROM sync_outbox
+	WHERE entity_type = ?1 AND entity_id = ?2 AND base_version < ?3
+	  AND (state IN ('pending', 'sent', 'failed') OR (state = 'acked' AND server_version > ?3))
+	ORDER BY seq DESC LIMIT 1`
+
+// language=SQL
+// dialect=SQLite
+const openConflictSQL = `SELECT id, remote_version FROM sync_conflicts
+	WHERE entity_type = ? AND entity_id = ? AND resolution = ''`
+
+// language=SQL
+// dialect=SQLite
+const insertConflictSQL = `INSERT INTO sync_conflicts(entity_type, entity_id, local_op_id, local_op_type, local_payload,
+	remote_op_id, remote_op_type, remote_payload, remote_version, remote_actor, detected_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// language=SQL
+// dialect=SQLite
+const updateConflictRemoteSQL = `UPDATE sync_conflicts
+	SET remote_op_id = ?, remote_op_type = ?, remote_payload = ?, remote_version = ?, remote_actor = ?
+	WHERE id = ?`
+
+// language=SQL
+// dialect=SQLite
+const selectConflictsSQL = `SELECT id, entity_type, entity_id, local_op_id, local_op_type, local_payload,
+	remote_op_id, remote_op_type, remote_payload, remote_version, remote_actor, detected_at, resolution
+	FROM sync_conflicts`
+
+// ReconcilePulledOps sorts pulled ops into those to apply to the local manifest and conflicts. Ops this
+// client pushed itself are skipped. An op on an entity with a conflicting local change (see
+// conflictingLocalOpSQL) or an open conflict is recorded as a conflict instead of being applied; the
+// local ops on that entity are then held back until ResolveSyncConflict. Reconciling the same ops again,
+// e.g. after a restart before the pull was committed, records no further conflicts.
+// It returns the ops to apply, in pull order, and the number of open conflicts afterwards.
+func (ix *IndexHandle) ReconcilePulledOps(ctx context.Context, ops []RemoteSyncOp) ([]RemoteSyncOp, int, error) {
+	db, err := ix.acquire()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer ix.release()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	var apply []RemoteSyncOp
+	for _, op := range ops {
+		var one int
+		switch err := tx.QueryRowContext(ctx, ownSyncOpSQL, op.OpID).Scan(&one); {
+		case err == nil:
+			continue // pushed by this client
+		case !errors.Is(err, sql.ErrNoRows):
+			return nil, 0, fmt.Errorf("look up sync op: %w", err)
+		}
+		var id, version int64
+		switch err := tx.QueryRowContext(ctx, openConflictSQL, op.EntityType, op.EntityID).Scan(&id, &version); {
+		case err == nil:
+			if op.Version > version {
+				if _, err := tx.ExecContext(ctx, updateConflictRemoteSQL, op.OpID, op.OpType, string(op.Payload), op.Version, op.Actor, id); err != nil {
+					return nil, 0, fmt.Errorf("update sync conflict: %w", err)
+				}
+			}
+			continue
+		case !errors.Is(err, sql.ErrNoRows):
+			return nil, 0, fmt.Errorf("look up sync conflict: %w", err)
+		}
+		var local SyncOp
+		var payload string
+		switch err := tx.QueryRowContext(ctx, conflictingLocalOpSQL, op.EntityType, op.EntityID, op.Version).Scan(&local.OpID, &local.OpType, &payload); {
+		case errors.Is(err, sql.ErrNoRows):
+			apply = append(apply, op)
+			continue
+		case err != nil:
+			return nil, 0, fmt.Errorf("look up local sync ops: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, insertConflictSQL, op.EntityType, op.EntityID, local.OpID, local.OpType, payload,
+			op.OpID, op.OpType, string(op.Payload), op.Version, op.Actor, now); err != nil {
+			return nil, 0, fmt.Errorf("record sync conflict: %w", err)
+		}
+	}
+	var open int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM sync_conflicts WHERE resolution = ''`).Scan(&open); err != nil {
+		return nil, 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("commit sync conflicts: %w", err)
+	}
+	return apply, open, nil
+}
+
+// OpenSyncConflicts returns the unresolved conflicts, oldest first.
+func (ix *IndexHandle) OpenSyncConflicts(ctx context.Context) ([]SyncConflict, error) {
+	db, err := ix.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer ix.release()
+	rows, err := db.QueryContext(ctx, selectConflictsSQL+` WHERE resolution = '' ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var out []SyncConflict
+	for rows.Next() {
+		c, err := scanSyncConflict(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSyncConflict(r rowScanner) (SyncConflict, error) {
+	var c SyncConflict
+	var local, remote, detected string
+	err := r.Scan(&c.ID, &c.Local.EntityType, &c.Local.EntityID, &c.Local.OpID, &c.Local.OpType, &local,
+		&c.Remote.OpID, &c.Remote.OpType, &remote, &c.Remote.Version, &c.Remote.Actor, &detected, &c.Resolution)
+	if err != nil {
+		return c, err
+	}
+	c.Remote.EntityType, c.Remote.EntityID = c.Local.EntityType, c.Local.EntityID
+	c.Local.Payload, c.Remote.Payload = json.RawMessage(local), json.RawMessage(remote)
+	c.DetectedAt, _ = time.Parse(time.RFC3339Nano, detected)
+	return c, nil
+}
+
+// ErrSyncConflictResolved is returned by ResolveSyncConflict for a conflict that is not open.
+var ErrSyncConflictResolved = errors.New("sync conflict is already resolved")
+
+// ResolveSyncConflict settles an open conflict with SyncKeepLocal or SyncKeepRemote.
+//
+// Keeping the local change releases the held local ops and rebases them on the remote op, so pushing
+// them overwrites the remote change; when none of them is left to push, the local payload is queued
+// again. Keeping the remote change discards the local ops on the entity that have not been acked and
+// returns the remote op, which the caller applies to the manifest with ApplyRemoteOps.
+func (ix *IndexHandle) ResolveSyncConflict(ctx context.Context, id int64, keep string) (*RemoteSyncOp, error) {
+	if keep != SyncKeepLocal && keep != SyncKeepRemote {
+		return nil, fmt.Errorf("unknown sync conflict resolution %q", keep)
+	}
+	db, err := ix.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer ix.release()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	c, err := scanSyncConflict(tx.QueryRowContext(ctx, selectConflictsSQL+` WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && c.Resolution != "") {
+		return nil, fmt.Errorf("%w: %d", ErrSyncConflictResolved, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read sync conflict: %w", err)
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	var out *RemoteSyncOp
+	if keep == SyncKeepLocal {
+		if _, err := tx.ExecContext(ctx, `UPDATE sync_outbox SET base_version = ?1
+			WHERE entity_type = ?2 AND entity_id = ?3 AND base_version < ?1 AND state != 'discarded'`,
+			c.Remote.Version, c.Local.EntityType, c.Local.EntityID); err != nil {
+			return nil, fmt.Errorf("rebase local sync ops: %w", err)
+		}
+		var unsent int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM sync_outbox
+			WHERE entity_type = ? AND entity_id = ? AND state IN ('pending', 'sent', 'failed')`,
+			c.Local.EntityType, c.Local.EntityID).Scan(&unsent); err != nil {
+			return nil, err
+		}
+		if unsent == 0 {
+			key, err := readMeta(ctx, tx, metaSyncProjectKey)
+			if err != nil {
+				return nil, err
+			}
+			opID := uuidV5(syncOpNamespace, fmt.Sprintf("%s/conflict/%d/%s", key, c.ID, c.Local.OpID))
+			if _, err := tx.ExecContext(ctx, insertSyncOpSQL, opID, c.Local.OpType, c.Local.EntityType, c.Local.EntityID,
+				string(c.Local.Payload), now, c.Remote.Version); err != nil {
+				return nil, fmt.Errorf("queue local change again: %w", err)
+			}
+		}
+	} else {
+		if _, err := tx.ExecContext(ctx, `UPDATE sync_outbox SET state = 'discarded'
+			WHERE entity_type = ? AND entity_id = ? AND state IN ('pending', 'sent', 'failed')`,
+			c.Local.EntityType, c.Local.EntityID); err != nil {
+			return nil, fmt.Errorf("discard local sync ops: %w", err)
+		}
+		out = &c.Remote
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE sync_conflicts SET resolution = ?, resolved_at = ? WHERE id = ?`, keep, now, c.ID); err != nil {
+		return nil, fmt.Errorf("resolve sync conflict: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit sync conflict: %w", err)
+	}
+	return out, nil
+}
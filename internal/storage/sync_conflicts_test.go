@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// remotePageOp is a pulled update of page 1 of issue 1 to the given grid.
+func remotePageOp(opID string, version int64, grid string) RemoteSyncOp {
+	return RemoteSyncOp{
+		SyncOp: SyncOp{
+			OpID: opID, OpType: SyncOpUpdate, EntityType: SyncEntityPage, EntityID: "issue:1/page:1",
+			Payload: json.RawMessage(`{"grid":"` + grid + `","number":1,"parent":{"issue":1}}`),
+		},
+		Version: version, Actor: "bob",
+	}
+}
+
+func TestReconcilePulledOps_Conflicts(t *testing.T) {
+	root := t.TempDir()
+	ctx := context.Background()
+	ix := NewIndexHandle(root)
+	_ = ix.SetSyncPulledVersion(ctx, 5, 3)
+	local := queueTestOps(t, ix, "1x1")
+
+	remote := []RemoteSyncOp{
+		{SyncOp: SyncOp{OpID: local[0].OpID, OpType: SyncOpUpdate, EntityType: SyncEntityPage, EntityID: "issue:1/page:1"}, Version: 4},
+		remotePageOp("r-1", 5, "2x2"),
+		{SyncOp: SyncOp{OpID: "r-2", OpType: SyncOpCreate, EntityType: SyncEntityBibleTag, EntityID: "Noir", Payload: json.RawMessage(`{"name":"Noir"}`)}, Version: 6},
+	}
+	apply, open, err := ix.ReconcilePulledOps(ctx, remote)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if open != 1 || len(apply) != 1 || apply[0].OpID != "r-2" {
+		t.Fatalf("apply %+v, %d open", apply, open)
+	}
+	// The local op is held while the conflict is open
+	if due, _ := ix.DueSyncOps(ctx, farFuture, 10); len(due) != 0 {
+		t.Fatalf("held op is due: %+v", due)
+	}
+	if pending, _ := ix.PendingSyncOps(ctx, 10); len(pending) != 0 {
+		t.Fatalf("held op is pending: %+v", pending)
+	}
+	// A newer remote op on the entity updates the conflict; reconciling again adds nothing
+	if _, open, err := ix.ReconcilePulledOps(ctx, append(remote, remotePageOp("r-3", 7, "4x4"))); err != nil || open != 1 {
+		t.Fatalf("reconcile again: %d %v", open, err)
+	}
+	_ = ix.Close()
+
+	ix = NewIndexHandle(root)
+	defer func() { _ = ix.Close() }()
+	list, err := ix.OpenSyncConflicts(ctx)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("conflicts after reopen: %+v %v", list, err)
+	}
+	c := list[0]
+	if c.Local.OpID != local[0].OpID || c.Remote.OpID != "r-3" || c.Remote.Version != 7 || c.Remote.Actor != "bob" ||
+		c.Local.EntityID != "issue:1/page:1" || c.DetectedAt.IsZero() {
+		t.Fatalf("conflict = %+v", c)
+	}
+	if st, _ := ix.SyncQueueStats(ctx); st.Conflicts != 1 {
+		t.Fatalf("stats %+v", st)
+	}
+}
+
+func TestReconcilePulledOps_NoConflict(t *testing.T) {
+	ix := NewIndexHandle(t.TempDir())
+	defer func() { _ = ix.Close() }()
+	ctx := context.Background()
+	_ = ix.SetSyncPulledVersion(ctx, 5, 3)
+	local := queueTestOps(t, ix, "1x1")
+	// Our op reached the server at version 4, before the remote change at 5: the remote side saw it
+	_ = ix.AckSyncOpSeqs(ctx, []int64{local[0].Seq}, 4)
+	if apply, open, err := ix.ReconcilePulledOps(ctx, []RemoteSyncOp{remotePageOp("r-1", 5, "2x2")}); err != nil || open != 0 || len(apply) != 1 {
+		t.Fatalf("sequential change: %+v %d %v", apply, open, err)
+	}
+	// A remote change the local op was based on
+	_ = ix.SetSyncPulledVersion(ctx, 5, 5)
+	queueTestOps(t, ix, "3x3")
+	if apply, open, err := ix.ReconcilePulledOps(ctx, []RemoteSyncOp{remotePageOp("r-0", 5, "2x2")}); err != nil || open != 0 || len(apply) != 1 {
+		t.Fatalf("known change: %+v %d %v", apply, open, err)
+	}
+}
+
+func TestResolveSyncConflict(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) (*IndexHandle, []SyncOp, SyncConflict) {
+		ix := NewIndexHandle(t.TempDir())
+		t.Cleanup(func() { _ = ix.Close() })
+		local := queueTestOps(t, ix, "1x1", "2x1")
+		if _, _, err := ix.ReconcilePulledOps(ctx, []RemoteSyncOp{remotePageOp("r-1", 9, "4x4")}); err != nil {
+			t.Fatal(err)
+		}
+		list, _ := ix.OpenSyncConflicts(ctx)
+		if len(list) != 1 || list[0].Local.OpID != local[1].OpID {
+			t.Fatalf("conflicts %+v", list)
+		}
+		return ix, local, list[0]
+	}
+	t.Run("local", func(t *testing.T) {
+		ix, local, c := setup(t)
+		if op, err := ix.ResolveSyncConflict(ctx, c.ID, SyncKeepLocal); err != nil || op != nil {
+			t.Fatalf("keep local: %+v %v", op, err)
+		}
+		due, _ := ix.DueSyncOps(ctx, farFuture, 10)
+		if len(due) != 2 || due[0].OpID != local[0].OpID || due[1].BaseVersion != 9 {
+			t.Fatalf("released ops: %+v", due)
+		}
+		// The same remote op pulled again no longer conflicts
+		if apply, open, _ := ix.ReconcilePulledOps(ctx, []RemoteSyncOp{remotePageOp("r-1", 9, "4x4")}); open != 0 || len(apply) != 1 {
+			t.Fatalf("re-pull: %+v %d", apply, open)
+		}
+		if _, err := ix.ResolveSyncConflict(ctx, c.ID, SyncKeepRemote); !errors.Is(err, ErrSyncConflictResolved) {
+			t.Fatalf("resolve twice: %v", err)
+		}
+	})
+	t.Run("local already pushed", func(t *testing.T) {
+		ix, local, c := setup(t)
+		_ = ix.AckSyncOpSeqs(ctx, []int64{local[0].Seq, local[1].Seq}, 10)
+		if _, err := ix.ResolveSyncConflict(ctx, c.ID, SyncKeepLocal); err != nil {
+			t.Fatal(err)
+		}
+		due, _ := ix.DueSyncOps(ctx, farFuture, 10)
+		if len(due) != 1 || due[0].OpID == local[1].OpID || string(due[0].Payload) != string(local[1].Payload) {
+			t.Fatalf("requeued op: %+v", due)
+		}
+	})
+	t.Run("remote", func(t *testing.T) {
+		ix, _, c := setup(t)
+		op, err := ix.ResolveSyncConflict(ctx, c.ID, SyncKeepRemote)
+		if err != nil || op == nil || op.OpID != "r-1" {
+			t.Fatalf("keep remote: %+v %v", op, err)
+		}
+		if st, _ := ix.SyncQueueStats(ctx); st.Unsent() != 0 || st.Conflicts != 0 {
+			t.Fatalf("local ops not discarded: %+v", st)
+		}
+	})
+	ix, _, c := setup(t)
+	if _, err := ix.ResolveSyncConflict(ctx, c.ID, "both"); err == nil {
+		t.Fatal("accepted an unknown resolution")
+	}
+}
@@ -0,0 +1,419 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+// Entity and op types of the sync ops generated from manifest changes.
+const (
+	SyncEntityPage           = "page"
+	SyncEntityPanel          = "panel"
+	SyncEntityBalloon        = "balloon"
+	SyncEntityBibleCharacter = "bible_character"
+	SyncEntityBibleLocation  = "bible_location"
+	SyncEntityBibleTag       = "bible_tag"
+
+	SyncOpCreate = "create"
+	SyncOpUpdate = "update"
+	SyncOpDelete = "delete"
+)
+
+// syncEntityOrder lists the entity types parents first. Creates and updates are emitted in this order,
+// deletes in reverse, so that a receiver never sees a child whose parent is not there.
+var syncEntityOrder = []string{
+	SyncEntityPage, SyncEntityPanel, SyncEntityBalloon,
+	SyncEntityBibleCharacter, SyncEntityBibleLocation, SyncEntityBibleTag,
+}
+
+// syncOpNamespace is the UUID namespace of the version 5 op IDs of generated sync ops.
+var syncOpNamespace = [16]byte{0x3c, 0x0e, 0x5f, 0x7a, 0x91, 0x4d, 0x4b, 0x2e, 0x8a, 0x61, 0x0d, 0xc4, 0x52, 0x7e, 0x19, 0xb3}
+
+// SyncOp is one entity change to push to the server. Seq is the op's position in the outbox and 0
+// for ops that are not queued. The payload of creates and updates is the entity as it is now, the
+// payload of deletes the entity as it was; both name the entity's parents under "parent".
+// The queue fields are set for ops read from the outbox: the SyncState* state, the failed push
+// attempts with the last error, and the server version the change was based on.
+type SyncOp struct {
+	Seq        int64
+	OpID       string
+	OpType     string
+	EntityType string
+	EntityID   string
+	Payload    json.RawMessage
+
+	State       string
+	Attempts    int
+	LastError   string
+	BaseVersion int64
+}
+
+// syncEntity is one syncable entity of a manifest with the payload that describes it.
+type syncEntity struct {
+	Type    string
+	ID      string
+	Payload json.RawMessage
+}
+
+// DiffProjects returns the ops that turn prev into cur: one per page, panel, balloon and bible entry
+// that was created, changed or deleted. The list is deterministic: creates and updates come parents
+// first in manifest order, then deletes children first. Op IDs are UUIDv5 over projectKey, version,
+// entity and op type, so generating the ops again for the same version gives the same IDs.
+// Pages are identified by issue and page number, panels and balloons by issue and ID, bible entries
+// by name. Issue settings and project metadata are not covered.
+func DiffProjects(projectKey string, version int64, prev, cur domain.Project) []SyncOp {
+	return diffSyncEntities(projectKey, version, syncEntities(prev), syncEntities(cur))
+}
+
+func diffSyncEntities(projectKey string, version int64, prev, cur []syncEntity) []SyncOp {
+	type ref struct{ Type, ID string }
+	old := make(map[ref]json.RawMessage, len(prev))
+	for _, e := range prev {
+		// With duplicate IDs the first entity in the manifest wins, as for cur below.
+		if _, ok := old[ref{e.Type, e.ID}]; !ok {
+			old[ref{e.Type, e.ID}] = e.Payload
+		}
+	}
+	seen := make(map[ref]bool, len(cur))
+	var ops []SyncOp
+	for _, t := range syncEntityOrder {
+		for _, e := range cur {
+			k := ref{e.Type, e.ID}
+			if e.Type != t || seen[k] {
+				continue
+			}
+			seen[k] = true
+			before, ok := old[k]
+			switch {
+			case !ok:
+				ops = append(ops, newSyncOp(projectKey, version, SyncOpCreate, e))
+			case !bytes.Equal(before, e.Payload):
+				ops = append(ops, newSyncOp(projectKey, version, SyncOpUpdate, e))
+			}
+		}
+	}
+	for i := len(syncEntityOrder) - 1; i >= 0; i-- {
+		for _, e := range prev {
+			k := ref{e.Type, e.ID}
+			if e.Type != syncEntityOrder[i] || seen[k] {
+				continue
+			}
+			seen[k] = true
+			ops = append(ops, newSyncOp(projectKey, version, SyncOpDelete, e))
+		}
+	}
+	return ops
+}
+
+func newSyncOp(projectKey string, version int64, opType string, e syncEntity) SyncOp {
+	name := fmt.Sprintf("%s/%d/%s/%s/%s", projectKey, version, e.Type, e.ID, opType)
+	return SyncOp{
+		OpID:       uuidV5(syncOpNamespace, name),
+		OpType:     opType,
+		EntityType: e.Type,
+		EntityID:   e.ID,
+		Payload:    e.Payload,
+	}
+}
+
+// syncEntities flattens a manifest into its syncable entities in manifest order. Pages leave out their
+// panels and panels their balloons; those are entities of their own.
+func syncEntities(p domain.Project) []syncEntity {
+	var out []syncEntity
+	for ii, iss := range p.Issues {
+		issue := ii + 1
+		for _, pg := range iss.Pages {
+			page := pg
+			page.Panels = nil
+			out = append(out, syncEntity{
+				Type:    SyncEntityPage,
+				ID:      fmt.Sprintf("issue:%d/page:%d", issue, pg.Number),
+				Payload: syncPayload(page, map[string]any{"issue": issue}, "panels"),
+			})
+			for _, pn := range pg.Panels {
+				panel := pn
+				panel.Balloons = nil
+				out = append(out, syncEntity{
+					Type:    SyncEntityPanel,
+					ID:      fmt.Sprintf("issue:%d/panel:%s", issue, pn.ID),
+					Payload: syncPayload(panel, map[string]any{"issue": issue, "page": pg.Number}),
+				})
+				for _, b := range pn.Balloons {
+					out = append(out, syncEntity{
+						Type:    SyncEntityBalloon,
+						ID:      fmt.Sprintf("issue:%d/balloon:%s", issue, b.ID),
+						Payload: syncPayload(b, map[string]any{"issue": issue, "page": pg.Number, "panel": pn.ID}),
+					})
+				}
+			}
+		}
+	}
+	for _, c := range p.Bible.Characters {
+		out = append(out, syncEntity{Type: SyncEntityBibleCharacter, ID: c.Name, Payload: syncPayload(c, nil)})
+	}
+	for _, l := range p.Bible.Locations {
+		out = append(out, syncEntity{Type: SyncEntityBibleLocation, ID: l.Name, Payload: syncPayload(l, nil)})
+	}
+	for _, t := range p.Bible.Tags {
+		out = append(out, syncEntity{Type: SyncEntityBibleTag, ID: t.Name, Payload: syncPayload(t, nil)})
+	}
+	return out
+}
+
+// syncPayload encodes v as a JSON object with sorted keys, without the drop keys and with parent, if
+// any, under "parent".
+func syncPayload(v any, parent map[string]any, drop ...string) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return json.RawMessage("{}")
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return json.RawMessage("{}")
+	}
+	for _, k := range drop {
+		delete(m, k)
+	}
+	if len(parent) > 0 {
+		m["parent"] = parent
+	}
+	// Maps encode with sorted keys, which keeps payloads byte-comparable.
+	if b, err = json.Marshal(m); err != nil {
+		return json.RawMessage("{}")
+	}
+	return b
+}
+
+// uuidV5 returns the name-based (SHA-1) UUID of name in namespace ns, per RFC 4122.
+func uuidV5(ns [16]byte, name string) string {
+	h := sha1.New()
+	h.Write(ns[:])
+	h.Write([]byte(name))
+	var u [16]byte
+	copy(u[:], h.Sum(nil))
+	u[6] = u[6]&0x0f | 0x50
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
+
+// ChangeTracker turns successive saves of a manifest into queued sync ops. It remembers the entities
+// of the last recorded manifest; Record diffs the next one against them and queues the ops in the
+// project index's outbox, from where backend.Client.PushPending sends them.
+type ChangeTracker struct {
+	mu   sync.Mutex
+	base []syncEntity
+}
+
+// NewChangeTracker returns a tracker whose first Record diffs against base.
+func NewChangeTracker(base domain.Project) *ChangeTracker {
+	return &ChangeTracker{base: syncEntities(base)}
+}
+
+// Record queues the ops from the last recorded manifest to cur under the next local sync version and
+// returns them. Nothing is queued when nothing changed. When queueing fails the tracker keeps its
+// base, so the next Record includes the changes again.
+func (t *ChangeTracker) Record(ctx context.Context, ix *IndexHandle, cur domain.Project) ([]SyncOp, error) {
+	if ix == nil {
+		return nil, errors.New("nil IndexHandle")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	next := syncEntities(cur)
+	ops, err := ix.enqueueSyncDiff(ctx, t.base, next)
+	if err != nil {
+		return nil, err
+	}
+	t.base = next
+	return ops, nil
+}
+
+// Meta keys of the sync state in the index database.
+const (
+	metaSyncProjectKey    = "sync_project_key"
+	metaSyncLocalVersion  = "sync_local_version"
+	metaSyncServerVersion = "sync_server_version:" // + server project ID
+)
+
+// language=SQL
+// dialect=SQLite
+const selectMetaSQL = `SELECT value FROM meta WHERE key = ?`
+
+// language=SQL
+// dialect=SQLite
+const upsertMetaSQL = `INSERT INTO meta(key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value`
+
+// language=SQL
+// dialect=SQLite
+const insertSyncOpSQL = `INSERT OR IGNORE INTO sync_outbox(op_id, op_type, entity_type, entity_id, payload, created_at, base_version)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+// language=SQL
+// dialect=SQLite
+const listPendingSyncOpsSQL = `SELECT ` + syncOpColumns + ` FROM sync_outbox
+	WHERE state IN ('pending', 'sent', 'failed') AND NOT ` + syncOpHeld + ` ORDER BY seq LIMIT ?`
+
+// language=SQL
+// dialect=SQLite
+const ackSyncOpsSQL = `UPDATE sync_outbox SET state = 'acked', last_error = ''
+	WHERE seq <= ? AND state IN ('pending', 'sent', 'failed') AND NOT ` + syncOpHeld
+
+// metaQuerier is the part of *sql.DB and *sql.Tx the meta helpers need.
+type metaQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func readMeta(ctx context.Context, q metaQuerier, key string) (string, error) {
+	var v string
+	err := q.QueryRowContext(ctx, selectMetaSQL, key).Scan(&v)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return v, err
+}
+
+func readMetaInt(ctx context.Context, q metaQuerier, key string) (int64, error) {
+	v, err := readMeta(ctx, q, key)
+	if err != nil || v == "" {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("meta %s: %w", key, err)
+	}
+	return n, nil
+}
+
+func writeMeta(ctx context.Context, q metaQuerier, key, value string) error {
+	_, err := q.ExecContext(ctx, upsertMetaSQL, key, value)
+	return err
+}
+
+// enqueueSyncDiff diffs prev against cur under the next local sync version and queues the ops.
+func (ix *IndexHandle) enqueueSyncDiff(ctx context.Context, prev, cur []syncEntity) ([]SyncOp, error) {
+	db, err := ix.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer ix.release()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	key, err := readMeta(ctx, tx, metaSyncProjectKey)
+	if err != nil {
+		return nil, fmt.Errorf("read sync key: %w", err)
+	}
+	if key == "" {
+		// A random key keeps op IDs of different projects apart on the server, even for equal names.
+		var b [16]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return nil, fmt.Errorf("generate sync key: %w", err)
+		}
+		key = hex.EncodeToString(b[:])
+		if err := writeMeta(ctx, tx, metaSyncProjectKey, key); err != nil {
+			return nil, fmt.Errorf("store sync key: %w", err)
+		}
+	}
+	v, err := readMetaInt(ctx, tx, metaSyncLocalVersion)
+	if err != nil {
+		return nil, err
+	}
+	base, err := readMetaInt(ctx, tx, metaSyncBaseVersion)
+	if err != nil {
+		return nil, err
+	}
+	ops := diffSyncEntities(key, v+1, prev, cur)
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	for i := range ops {
+		r, err := tx.ExecContext(ctx, insertSyncOpSQL, ops[i].OpID, ops[i].OpType, ops[i].EntityType, ops[i].EntityID, string(ops[i].Payload), now, base)
+		if err != nil {
+			return nil, fmt.Errorf("queue sync op: %w", err)
+		}
+		if n, _ := r.RowsAffected(); n == 1 {
+			ops[i].Seq, _ = r.LastInsertId()
+			ops[i].State, ops[i].BaseVersion = SyncStatePending, base
+		}
+	}
+	if err := writeMeta(ctx, tx, metaSyncLocalVersion, strconv.FormatInt(v+1, 10)); err != nil {
+		return nil, fmt.Errorf("store sync version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit sync ops: %w", err)
+	}
+	return ops, nil
+}
+
+// PendingSyncOps returns up to limit ops not yet acked by the server, oldest first, regardless of their
+// retry backoff. Ops of entities with an open conflict are held back.
+func (ix *IndexHandle) PendingSyncOps(ctx context.Context, limit int) ([]SyncOp, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	db, err := ix.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer ix.release()
+	rows, err := db.QueryContext(ctx, listPendingSyncOpsSQL, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanSyncOps(rows)
+}
+
+// AckSyncOps marks the unacked ops up to and including throughSeq acked, e.g. after the server stored
+// them. Held ops, which PendingSyncOps leaves out, stay queued.
+func (ix *IndexHandle) AckSyncOps(ctx context.Context, throughSeq int64) error {
+	db, err := ix.acquire()
+	if err != nil {
+		return err
+	}
+	defer ix.release()
+	_, err = db.ExecContext(ctx, ackSyncOpsSQL, throughSeq)
+	return err
+}
+
+// SyncServerVersion returns the server version of the given server project seen by the last push, or 0.
+func (ix *IndexHandle) SyncServerVersion(ctx context.Context, projectID int64) (int64, error) {
+	db, err := ix.acquire()
+	if err != nil {
+		return 0, err
+	}
+	defer ix.release()
+	return readMetaInt(ctx, db, metaSyncServerVersion+strconv.FormatInt(projectID, 10))
+}
+
+// SetSyncServerVersion records the server version of the given server project after a push.
+func (ix *IndexHandle) SetSyncServerVersion(ctx context.Context, projectID int64, version int64) error {
+	db, err := ix.acquire()
+	if err != nil {
+		return err
+	}
+	defer ix.release()
+	return writeMeta(ctx, db, metaSyncServerVersion+strconv.FormatInt(projectID, 10), strconv.FormatInt(version, 10))
+}
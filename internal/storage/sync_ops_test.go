@@ -0,0 +1,234 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func syncTestProject() domain.Project {
+	return domain.Project{
+		Name: "Sync",
+		Issues: []domain.Issue{{Pages: []domain.Page{{
+			Number: 1,
+			Grid:   "3x3",
+			Panels: []domain.Panel{{
+				ID:       "p1",
+				Geometry: domain.Rect{X: 0, Y: 0, Width: 100, Height: 50},
+				Balloons: []domain.Balloon{
+					{ID: "b1", Type: "speech", TextRuns: []domain.TextRun{{Content: "Hi."}}},
+					{ID: "b2", Type: "speech", TextRuns: []domain.TextRun{{Content: "Bye."}}},
+				},
+			}},
+		}}}},
+		Bible: domain.Bible{
+			Characters: []domain.BibleCharacter{{Name: "Alice"}},
+			Locations:  []domain.BibleLocation{{Name: "Roof"}},
+		},
+	}
+}
+
+// syncOpKeys reduces ops to "op entity_type entity_id" for comparison.
+func syncOpKeys(ops []SyncOp) []string {
+	out := make([]string, 0, len(ops))
+	for _, op := range ops {
+		out = append(out, op.OpType+" "+op.EntityType+" "+op.EntityID)
+	}
+	return out
+}
+
+func TestUUIDv5(t *testing.T) {
+	dns := [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	if got := uuidV5(dns, "www.example.com"); got != "2ed6657d-e927-568b-95e1-2665a8aea6a2" {
+		t.Fatalf("uuidV5 = %s", got)
+	}
+}
+
+func TestDiffProjects_Granularity(t *testing.T) {
+	prev := syncTestProject()
+	cur := syncTestProject()
+	pg := &cur.Issues[0].Pages[0]
+	pg.Grid = "2x3"                                         // page update only
+	pg.Panels[0].Balloons[0].TextRuns[0].Content = "Hello." // balloon update only
+	pg.Panels[0].Balloons = pg.Panels[0].Balloons[:1]       // b2 deleted
+	pg.Panels = append(pg.Panels, domain.Panel{ID: "p2", Balloons: []domain.Balloon{{ID: "b3", Type: "thought"}}})
+	cur.Issues[0].Pages = append(cur.Issues[0].Pages, domain.Page{Number: 2})
+	cur.Bible.Characters[0].Aliases = []string{"Al"}
+	cur.Bible.Locations = nil
+	cur.Bible.Tags = []domain.BibleTag{{Name: "night"}}
+
+	ops := DiffProjects("key", 1, prev, cur)
+	want := []string{
+		"update page issue:1/page:1",
+		"create page issue:1/page:2",
+		"create panel issue:1/panel:p2",
+		"update balloon issue:1/balloon:b1",
+		"create balloon issue:1/balloon:b3",
+		"update bible_character Alice",
+		"create bible_tag night",
+		"delete bible_location Roof",
+		"delete balloon issue:1/balloon:b2",
+	}
+	if got := syncOpKeys(ops); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ops =\n%v\nwant\n%v", got, want)
+	}
+
+	var page map[string]any
+	if err := json.Unmarshal(ops[0].Payload, &page); err != nil {
+		t.Fatalf("page payload: %v", err)
+	}
+	if _, ok := page["panels"]; ok || page["grid"] != "2x3" || !reflect.DeepEqual(page["parent"], map[string]any{"issue": 1.0}) {
+		t.Fatalf("page payload = %s", ops[0].Payload)
+	}
+	var balloon map[string]any
+	if err := json.Unmarshal(ops[4].Payload, &balloon); err != nil {
+		t.Fatalf("balloon payload: %v", err)
+	}
+	if balloon["id"] != "b3" || !reflect.DeepEqual(balloon["parent"], map[string]any{"issue": 1.0, "page": 1.0, "panel": "p2"}) {
+		t.Fatalf("balloon payload = %s", ops[4].Payload)
+	}
+	var panel map[string]any
+	if err := json.Unmarshal(ops[2].Payload, &panel); err != nil {
+		t.Fatalf("panel payload: %v", err)
+	}
+	if _, ok := panel["balloons"]; ok {
+		t.Fatalf("panel payload carries balloons: %s", ops[2].Payload)
+	}
+	// Deletes carry the entity as it was
+	if !json.Valid(ops[8].Payload) || !reflect.DeepEqual(syncOpKeys(ops[8:]), []string{"delete balloon issue:1/balloon:b2"}) {
+		t.Fatalf("delete op = %+v", ops[8])
+	}
+}
+
+func TestDiffProjects_MovesAndDeletesChildrenFirst(t *testing.T) {
+	prev := syncTestProject()
+	cur := syncTestProject()
+	// Moving b2 to a new page's panel updates the balloon; deleting page 1 removes its panel and b1
+	b2 := cur.Issues[0].Pages[0].Panels[0].Balloons[1]
+	cur.Issues[0].Pages = []domain.Page{{Number: 2, Panels: []domain.Panel{{ID: "p5", Balloons: []domain.Balloon{b2}}}}}
+
+	want := []string{
+		"create page issue:1/page:2",
+		"create panel issue:1/panel:p5",
+		"update balloon issue:1/balloon:b2",
+		"delete balloon issue:1/balloon:b1",
+		"delete panel issue:1/panel:p1",
+		"delete page issue:1/page:1",
+	}
+	if got := syncOpKeys(DiffProjects("key", 1, prev, cur)); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ops =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestDiffProjects_Deterministic(t *testing.T) {
+	prev := syncTestProject()
+	cur := syncTestProject()
+	cur.Issues[0].Pages[0].Panels[0].Notes = "wide shot"
+	cur.Bible.Tags = []domain.BibleTag{{Name: "a"}, {Name: "b"}}
+
+	first := DiffProjects("key", 7, prev, cur)
+	if len(first) != 3 {
+		t.Fatalf("ops = %v", syncOpKeys(first))
+	}
+	if again := DiffProjects("key", 7, prev, cur); !reflect.DeepEqual(first, again) {
+		t.Fatalf("diff is not deterministic:\n%+v\n%+v", first, again)
+	}
+	ids := map[string]bool{}
+	for _, op := range first {
+		ids[op.OpID] = true
+	}
+	for _, other := range [][]SyncOp{DiffProjects("key", 8, prev, cur), DiffProjects("other", 7, prev, cur)} {
+		for _, op := range other {
+			if ids[op.OpID] {
+				t.Fatalf("op id %s reused for another version or project", op.OpID)
+			}
+		}
+	}
+	if len(ids) != len(first) {
+		t.Fatalf("op ids are not unique: %+v", first)
+	}
+	if ops := DiffProjects("key", 1, cur, cur); ops != nil {
+		t.Fatalf("unchanged project yields ops: %v", syncOpKeys(ops))
+	}
+}
+
+func TestChangeTracker_QueueSurvivesReopen(t *testing.T) {
+	root := t.TempDir()
+	ctx := context.Background()
+	proj := syncTestProject()
+	tr := NewChangeTracker(proj)
+
+	ix := NewIndexHandle(root)
+	proj.Issues[0].Pages[0].Grid = "1x1"
+	first, err := tr.Record(ctx, ix, proj)
+	if err != nil || len(first) != 1 || first[0].Seq == 0 {
+		t.Fatalf("record: %+v %v", first, err)
+	}
+	if ops, err := tr.Record(ctx, ix, proj); err != nil || ops != nil {
+		t.Fatalf("record without changes: %+v %v", ops, err)
+	}
+	proj.Bible.Characters = nil
+	second, err := tr.Record(ctx, ix, proj)
+	if err != nil || len(second) != 1 {
+		t.Fatalf("record: %+v %v", second, err)
+	}
+	if err := ix.SetSyncServerVersion(ctx, 42, 9); err != nil {
+		t.Fatalf("SetSyncServerVersion: %v", err)
+	}
+	_ = ix.Close()
+
+	ix = NewIndexHandle(root)
+	defer func() { _ = ix.Close() }()
+	pending, err := ix.PendingSyncOps(ctx, 0)
+	if err != nil {
+		t.Fatalf("PendingSyncOps: %v", err)
+	}
+	if got := syncOpKeys(pending); !reflect.DeepEqual(got, []string{"update page issue:1/page:1", "delete bible_character Alice"}) {
+		t.Fatalf("pending = %v", got)
+	}
+	if pending[0].OpID != first[0].OpID || string(pending[0].Payload) != string(first[0].Payload) {
+		t.Fatalf("queued op = %+v, want %+v", pending[0], first[0])
+	}
+	if v, err := ix.SyncServerVersion(ctx, 42); err != nil || v != 9 {
+		t.Fatalf("SyncServerVersion = %d, %v", v, err)
+	}
+	if v, err := ix.SyncServerVersion(ctx, 43); err != nil || v != 0 {
+		t.Fatalf("SyncServerVersion of unknown project = %d, %v", v, err)
+	}
+	if err := ix.AckSyncOps(ctx, pending[0].Seq); err != nil {
+		t.Fatalf("AckSyncOps: %v", err)
+	}
+	if rest, err := ix.PendingSyncOps(ctx, 10); err != nil || len(rest) != 1 || rest[0].OpID != second[0].OpID {
+		t.Fatalf("pending after ack = %+v %v", rest, err)
+	}
+}
+
+func TestSave_TrackChangesQueuesOps(t *testing.T) {
+	root := t.TempDir()
+	ph := &ProjectHandle{Root: root, ManifestPath: filepath.Join(root, ManifestFileName), Project: syncTestProject()}
+	defer func() { _ = ph.Close() }()
+	ph.TrackChanges()
+	ph.Project.Issues[0].Pages[0].Panels[0].Balloons[0].Character = "Alice"
+	if err := Save(ph); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	pending, err := ph.Index().PendingSyncOps(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("PendingSyncOps: %v", err)
+	}
+	if got := syncOpKeys(pending); !reflect.DeepEqual(got, []string{"update balloon issue:1/balloon:b1"}) {
+		t.Fatalf("pending = %v", got)
+	}
+}
@@ -0,0 +1,331 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// States of a queued sync op. New ops are pending; the sync worker marks a batch sent before pushing it
+// and acked once the server stored it, or failed with a time for the next attempt. Ops that lost a
+// conflict resolution are discarded. Acked and discarded ops stay in the outbox until PruneSyncOps, so
+// a pull can tell this client's own ops apart from remote ones.
+const (
+	SyncStatePending   = "pending"
+	SyncStateSent      = "sent"
+	SyncStateAcked     = "acked"
+	SyncStateFailed    = "failed"
+	SyncStateDiscarded = "discarded"
+)
+
+// Meta keys of the pull state in the index database.
+const (
+	metaSyncPulledVersion = "sync_pulled_version:" // + server project ID
+	// metaSyncBaseVersion is the server version the local manifest has caught up with; queued ops
+	// record it as their base to detect conflicts with remote ops above it.
+	metaSyncBaseVersion = "sync_base_version"
+	// metaSyncLastSuccess is the time (RFC 3339) of the last sync round that reached the server.
+	metaSyncLastSuccess = "sync_last_success"
+)
+
+// syncQueueColumns are the sync_outbox columns added in schema version 3.
+var syncQueueColumns = []struct{ name, ddl string }{
+	{"state", `TEXT NOT NULL DEFAULT 'pending'`},
+	{"attempts", `INTEGER NOT NULL DEFAULT 0`},
+	{"next_attempt_at", `TEXT NOT NULL DEFAULT ''`},
+	{"last_error", `TEXT NOT NULL DEFAULT ''`},
+	{"base_version", `INTEGER NOT NULL DEFAULT 0`},
+	{"server_version", `INTEGER NOT NULL DEFAULT 0`},
+}
+
+// sqlQuerier is the part of *sql.DB and *sql.Tx the schema helpers need.
+type sqlQuerier interface {
+	metaQuerier
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// ensureSyncQueueMigrated adds the queue columns to a sync_outbox created before schema version 3,
+// whose ops then count as pending, and creates the queue index. It is safe to run more than once.
+func ensureSyncQueueMigrated(ctx context.Context, q sqlQuerier) error {
+	rows, err := q.QueryContext(ctx, `PRAGMA table_info(sync_outbox);`)
+	if err != nil {
+		return fmt.Errorf("table_info sync_outbox: %w", err)
+	}
+	cols := map[string]bool{}
+	for rows.Next() {
+		var cid, notnull, pk int
+		var name, typ string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &typ, &notnull, &dflt, &pk); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan table_info sync_outbox: %w", err)
+		}
+		cols[name] = true
+	}
+	_ = rows.Close()
+	for _, c := range syncQueueColumns {
+		if cols[c.name] {
+			continue
+		}
+		if _, err := q.ExecContext(ctx, `ALTER TABLE sync_outbox ADD COLUMN `+c.name+` `+c.ddl); err != nil {
+			return fmt.Errorf("add sync_outbox.%s: %w", c.name, err)
+		}
+	}
+	if _, err := q.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_sync_outbox_state ON sync_outbox(state, next_attempt_at);`); err != nil {
+		return fmt.Errorf("create sync queue index: %w", err)
+	}
+	if _, err := q.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_sync_outbox_entity ON sync_outbox(entity_type, entity_id);`); err != nil {
+		return fmt.Errorf("create sync queue index: %w", err)
+	}
+	return nil
+}
+
+// syncOpHeld is the SQL condition for queued ops of an entity with an open conflict; they are not pushed
+// until the user resolves it.
+const syncOpHeld = `EXISTS (SELECT 1 FROM sync_conflicts c
+	WHERE c.entity_type = sync_outbox.entity_type AND c.entity_id = sync_outbox.entity_id AND c.resolution = '')`
+
+// language=SQL
+// dialect=SQLite
+const syncOpColumns = `seq, op_id, op_type, entity_type, entity_id, payload, state, attempts, last_error, base_version`
+
+// language=SQL
+// dialect=SQLite
+const listDueSyncOpsSQL = `SELECT ` + syncOpColumns + ` FROM sync_outbox
+	WHERE state IN ('pending', 'failed') AND next_attempt_at <= ? AND NOT ` + syncOpHeld + `
+	ORDER BY seq LIMIT ?`
+
+// language=SQL
+// dialect=SQLite
+const recoverSyncOpsSQL = `UPDATE sync_outbox SET state = 'pending' WHERE state = 'sent'`
+
+// language=SQL
+// dialect=SQLite
+const pruneSyncOpsSQL = `DELETE FROM sync_outbox WHERE state IN ('acked', 'discarded') AND created_at < ?`
+
+// language=SQL
+// dialect=SQLite
+const pruneSyncConflictsSQL = `DELETE FROM sync_conflicts WHERE resolution != '' AND resolved_at < ?`
+
+func scanSyncOps(rows *sql.Rows) ([]SyncOp, error) {
+	defer func() { _ = rows.Close() }()
+	var out []SyncOp
+	for rows.Next() {
+		var op SyncOp
+		var payload string
+		if err := rows.Scan(&op.Seq, &op.OpID, &op.OpType, &op.EntityType, &op.EntityID, &payload, &op.State, &op.Attempts, &op.LastError, &op.BaseVersion); err != nil {
+			return nil, err
+		}
+		op.Payload = json.RawMessage(payload)
+		out = append(out, op)
+	}
+	return out, rows.Err()
+}
+
+// DueSyncOps returns up to limit queued ops the sync worker should push at now, oldest first: pending
+// ops and failed ones whose backoff has passed. Ops of entities with an open conflict are held back.
+func (ix *IndexHandle) DueSyncOps(ctx context.Context, now time.Time, limit int) ([]SyncOp, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	db, err := ix.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer ix.release()
+	rows, err := db.QueryContext(ctx, listDueSyncOpsSQL, now.UTC().Format(time.RFC3339Nano), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanSyncOps(rows)
+}
+
+// RecoverSyncOps makes ops that were sent but never acked, e.g. because the app quit mid-push, pending
+// again and returns how many there were. The server skips the ones it already stored as duplicates.
+func (ix *IndexHandle) RecoverSyncOps(ctx context.Context) (int, error) {
+	db, err := ix.acquire()
+	if err != nil {
+		return 0, err
+	}
+	defer ix.release()
+	r, err := db.ExecContext(ctx, recoverSyncOpsSQL)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := r.RowsAffected()
+	return int(n), nil
+}
+
+// MarkSyncOpsSent records that the ops are being pushed.
+func (ix *IndexHandle) MarkSyncOpsSent(ctx context.Context, seqs []int64) error {
+	return ix.updateSyncOps(ctx, `state = 'sent'`, nil, seqs)
+}
+
+// AckSyncOpSeqs records that the server stored the ops; serverVersion is the server version after the
+// push that carried them.
+func (ix *IndexHandle) AckSyncOpSeqs(ctx context.Context, seqs []int64, serverVersion int64) error {
+	return ix.updateSyncOps(ctx, `state = 'acked', last_error = '', server_version = ?`, []any{serverVersion}, seqs)
+}
+
+// FailSyncOps records a failed push of the ops: it counts the attempt, keeps the error and schedules the
+// next attempt at next.
+func (ix *IndexHandle) FailSyncOps(ctx context.Context, seqs []int64, cause error, next time.Time) error {
+	msg := ""
+	if cause != nil {
+		msg = cause.Error()
+	}
+	return ix.updateSyncOps(ctx, `state = 'failed', attempts = attempts + 1, last_error = ?, next_attempt_at = ?`,
+		[]any{msg, next.UTC().Format(time.RFC3339Nano)}, seqs)
+}
+
+// updateSyncOps applies set, with its placeholder values args, to the ops with the given seqs.
+func (ix *IndexHandle) updateSyncOps(ctx context.Context, set string, args []any, seqs []int64) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+	for _, s := range seqs {
+		args = append(args, s)
+	}
+	db, err := ix.acquire()
+	if err != nil {
+		return err
+	}
+	defer ix.release()
+	_, err = db.ExecContext(ctx, `UPDATE sync_outbox SET `+set+` WHERE seq IN (?`+strings.Repeat(", ?", len(seqs)-1)+`)`, args...)
+	return err
+}
+
+// PruneSyncOps deletes acked and discarded ops queued before cutoff and conflicts resolved before it.
+func (ix *IndexHandle) PruneSyncOps(ctx context.Context, cutoff time.Time) error {
+	db, err := ix.acquire()
+	if err != nil {
+		return err
+	}
+	defer ix.release()
+	ts := cutoff.UTC().Format(time.RFC3339Nano)
+	if _, err := db.ExecContext(ctx, pruneSyncOpsSQL, ts); err != nil {
+		return fmt.Errorf("prune sync ops: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, pruneSyncConflictsSQL, ts); err != nil {
+		return fmt.Errorf("prune sync conflicts: %w", err)
+	}
+	return nil
+}
+
+// SyncQueueStats counts the queued ops by state and the open conflicts. LastError is the error of the
+// most recent failed op, if any; LastSuccess the time of the last sync round that reached the server.
+type SyncQueueStats struct {
+	Pending, Sent, Failed, Conflicts int
+	LastError                        string
+	LastSuccess                      time.Time
+}
+
+// Unsent is the number of ops still waiting to reach the server.
+func (s SyncQueueStats) Unsent() int { return s.Pending + s.Sent + s.Failed }
+
+// SyncQueueStats returns the counts of the outbox and the open conflicts.
+func (ix *IndexHandle) SyncQueueStats(ctx context.Context) (SyncQueueStats, error) {
+	var st SyncQueueStats
+	db, err := ix.acquire()
+	if err != nil {
+		return st, err
+	}
+	defer ix.release()
+	rows, err := db.QueryContext(ctx, `SELECT state, COUNT(*) FROM sync_outbox GROUP BY state`)
+	if err != nil {
+		return st, err
+	}
+	for rows.Next() {
+		var state string
+		var n int
+		if err := rows.Scan(&state, &n); err != nil {
+			_ = rows.Close()
+			return st, err
+		}
+		switch state {
+		case SyncStatePending:
+			st.Pending = n
+		case SyncStateSent:
+			st.Sent = n
+		case SyncStateFailed:
+			st.Failed = n
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return st, err
+	}
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sync_conflicts WHERE resolution = ''`).Scan(&st.Conflicts); err != nil {
+		return st, err
+	}
+	if st.Failed > 0 {
+		if err := db.QueryRowContext(ctx, `SELECT last_error FROM sync_outbox WHERE state = 'failed' ORDER BY seq DESC LIMIT 1`).Scan(&st.LastError); err != nil {
+			return st, err
+		}
+	}
+	v, err := readMeta(ctx, db, metaSyncLastSuccess)
+	if err != nil {
+		return st, err
+	}
+	if v != "" {
+		if st.LastSuccess, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return st, fmt.Errorf("meta %s: %w", metaSyncLastSuccess, err)
+		}
+	}
+	return st, nil
+}
+
+// SetSyncLastSuccess records t as the time of the last sync round that reached the server.
+func (ix *IndexHandle) SetSyncLastSuccess(ctx context.Context, t time.Time) error {
+	db, err := ix.acquire()
+	if err != nil {
+		return err
+	}
+	defer ix.release()
+	return writeMeta(ctx, db, metaSyncLastSuccess, t.UTC().Format(time.RFC3339Nano))
+}
+
+// SyncPulledVersion returns the server version up to which ops of the given server project were pulled
+// and applied, or 0.
+func (ix *IndexHandle) SyncPulledVersion(ctx context.Context, projectID int64) (int64, error) {
+	db, err := ix.acquire()
+	if err != nil {
+		return 0, err
+	}
+	defer ix.release()
+	return readMetaInt(ctx, db, metaSyncPulledVersion+strconv.FormatInt(projectID, 10))
+}
+
+// SetSyncPulledVersion records that the ops of the given server project up to version were applied.
+// Ops queued from now on are based on that version.
+func (ix *IndexHandle) SetSyncPulledVersion(ctx context.Context, projectID int64, version int64) error {
+	db, err := ix.acquire()
+	if err != nil {
+		return err
+	}
+	defer ix.release()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	v := strconv.FormatInt(version, 10)
+	if err := writeMeta(ctx, tx, metaSyncPulledVersion+strconv.FormatInt(projectID, 10), v); err != nil {
+		return err
+	}
+	if err := writeMeta(ctx, tx, metaSyncBaseVersion, v); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
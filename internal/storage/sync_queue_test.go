@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// queueTestOps queues one update per page grid change and returns the tracker's ops.
+func queueTestOps(t *testing.T, ix *IndexHandle, grids ...string) []SyncOp {
+	t.Helper()
+	proj := syncTestProject()
+	tr := NewChangeTracker(proj)
+	var out []SyncOp
+	for _, g := range grids {
+		proj.Issues[0].Pages[0].Grid = g
+		ops, err := tr.Record(context.Background(), ix, proj)
+		if err != nil {
+			t.Fatalf("record: %v", err)
+		}
+		out = append(out, ops...)
+	}
+	return out
+}
+
+func TestSyncQueue_MigratesVersion2Outbox(t *testing.T) {
+	root := t.TempDir()
+	idx := IndexPath(root)
+	if err := os.MkdirAll(filepath.Dir(idx), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?cache=shared", filepath.ToSlash(idx)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []string{
+		`CREATE TABLE meta (key TEXT PRIMARY KEY, value TEXT NOT NULL);`,
+		`CREATE TABLE version (id INTEGER PRIMARY KEY CHECK(id=1), schema INTEGER NOT NULL, app TEXT, created_at TEXT NOT NULL, updated_at TEXT NOT NULL);`,
+		`INSERT INTO version VALUES(1, 2, 'test', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z');`,
+		`CREATE TABLE sync_outbox (seq INTEGER PRIMARY KEY AUTOINCREMENT, op_id TEXT NOT NULL UNIQUE, op_type TEXT NOT NULL,
+			entity_type TEXT NOT NULL, entity_id TEXT NOT NULL, payload TEXT NOT NULL, created_at TEXT NOT NULL);`,
+		`INSERT INTO sync_outbox(op_id, op_type, entity_type, entity_id, payload, created_at)
+			VALUES ('op-1', 'update', 'page', 'issue:1/page:1', '{}', '2025-01-01T00:00:00Z');`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("seed v2: %v (%s)", err, q)
+		}
+	}
+	_ = db.Close()
+
+	ix := NewIndexHandle(root)
+	defer func() { _ = ix.Close() }()
+	ctx := context.Background()
+	due, err := ix.DueSyncOps(ctx, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("DueSyncOps: %v", err)
+	}
+	if len(due) != 1 || due[0].OpID != "op-1" || due[0].State != SyncStatePending || due[0].Attempts != 0 {
+		t.Fatalf("migrated op = %+v", due)
+	}
+	mdb, err := ix.acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema, tables int
+	_ = mdb.QueryRow(`SELECT schema FROM version WHERE id=1`).Scan(&schema)
+	_ = mdb.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name IN ('sync_conflicts', 'idx_sync_outbox_state')`).Scan(&tables)
+	ix.release()
+	if schema != schemaVersion || tables != 2 {
+		t.Fatalf("schema %d, %d of the new objects", schema, tables)
+	}
+	// Migrating again is a no-op
+	if err := ensureSyncQueueMigrated(ctx, mdb); err != nil {
+		t.Fatalf("second migration: %v", err)
+	}
+}
+
+func TestSyncQueue_StatesAndBackoff(t *testing.T) {
+	ix := NewIndexHandle(t.TempDir())
+	defer func() { _ = ix.Close() }()
+	ctx := context.Background()
+	ops := queueTestOps(t, ix, "1x1", "2x2", "3x3")
+	now := time.Now()
+
+	if err := ix.MarkSyncOpsSent(ctx, []int64{ops[0].Seq, ops[1].Seq}); err != nil {
+		t.Fatal(err)
+	}
+	due, _ := ix.DueSyncOps(ctx, now, 10)
+	if len(due) != 1 || due[0].Seq != ops[2].Seq {
+		t.Fatalf("sent ops are due: %+v", due)
+	}
+	if err := ix.AckSyncOpSeqs(ctx, []int64{ops[0].Seq}, 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := ix.FailSyncOps(ctx, []int64{ops[1].Seq}, errors.New("offline"), now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	st, err := ix.SyncQueueStats(ctx)
+	if err != nil || st.Pending != 1 || st.Failed != 1 || st.Sent != 0 || st.LastError != "offline" || st.Unsent() != 2 {
+		t.Fatalf("stats %+v %v", st, err)
+	}
+	if due, _ := ix.DueSyncOps(ctx, now, 10); len(due) != 1 || due[0].Seq != ops[2].Seq {
+		t.Fatalf("failed op due before its backoff: %+v", due)
+	}
+	due, _ = ix.DueSyncOps(ctx, now.Add(2*time.Minute), 10)
+	if len(due) != 2 || due[0].Seq != ops[1].Seq || due[0].Attempts != 1 || due[0].LastError != "offline" {
+		t.Fatalf("due after backoff: %+v", due)
+	}
+	// A manual push ignores the backoff but never resends acked ops
+	if pending, _ := ix.PendingSyncOps(ctx, 10); len(pending) != 2 {
+		t.Fatalf("pending: %+v", pending)
+	}
+	if err := ix.PruneSyncOps(ctx, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := ix.SyncQueueStats(ctx); st.Unsent() != 2 {
+		t.Fatalf("prune dropped unsent ops: %+v", st)
+	}
+}
+
+func TestSyncQueue_RestartRecovery(t *testing.T) {
+	root := t.TempDir()
+	ctx := context.Background()
+	ix := NewIndexHandle(root)
+	ops := queueTestOps(t, ix, "1x1", "2x2", "3x3")
+	later := time.Now().Add(time.Hour)
+	// The app quits mid-push of the first op, after the second failed
+	_ = ix.MarkSyncOpsSent(ctx, []int64{ops[0].Seq})
+	_ = ix.FailSyncOps(ctx, []int64{ops[1].Seq}, errors.New("timeout"), later)
+	if err := ix.SetSyncPulledVersion(ctx, 5, 12); err != nil {
+		t.Fatal(err)
+	}
+	_ = ix.Close()
+
+	ix = NewIndexHandle(root)
+	defer func() { _ = ix.Close() }()
+	if n, err := ix.RecoverSyncOps(ctx); err != nil || n != 1 {
+		t.Fatalf("recover: %d %v", n, err)
+	}
+	due, _ := ix.DueSyncOps(ctx, time.Now(), 10)
+	if got := []int64{due[0].Seq, due[1].Seq}; len(due) != 2 || !reflect.DeepEqual(got, []int64{ops[0].Seq, ops[2].Seq}) {
+		t.Fatalf("due after restart: %+v", due)
+	}
+	if due, _ := ix.DueSyncOps(ctx, later, 10); len(due) != 3 || due[1].Attempts != 1 {
+		t.Fatalf("failed op lost its backoff: %+v", due)
+	}
+	if v, err := ix.SyncPulledVersion(ctx, 5); err != nil || v != 12 {
+		t.Fatalf("pulled version %d %v", v, err)
+	}
+	// Ops queued now are based on the pulled version
+	if next := queueTestOps(t, ix, "4x4"); len(next) != 1 || next[0].BaseVersion != 12 {
+		t.Fatalf("new op: %+v", next)
+	}
+}
+
+// farFuture is a time by which every backoff has passed.
+var farFuture = time.Now().Add(24 * time.Hour)
@@ -0,0 +1,262 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+)
+
+// Severity ranks a validation issue. Errors make exports fail; warnings are informational.
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// ValidationIssue is one problem found in a project manifest.
+// Path points into comic.json in the form issues[0].pages[2].panels[1].geometry.width.
+type ValidationIssue struct {
+	Path     string   `json:"path"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (v ValidationIssue) String() string {
+	if v.Path == "" {
+		return fmt.Sprintf("%s: %s", v.Severity, v.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", v.Severity, v.Path, v.Message)
+}
+
+// ValidationErrors is returned by operations that refuse to run on a project with errors.
+type ValidationErrors []ValidationIssue
+
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return "project is invalid: " + e[0].String()
+	}
+	return fmt.Sprintf("project is invalid (%d errors); first: %s", len(e), e[0])
+}
+
+// knownShapeKinds are the balloon shape kinds the editor and exporters understand; empty means rect.
+var knownShapeKinds = map[string]bool{"": true, "rect": true, "ellipse": true, "roundedBox": true, "path": true, "burst": true, "cloud": true}
+
+// ValidateProject checks the manifest for values that decode fine but make no sense: duplicate,
+// missing or non-positive page numbers, negative panel geometry, unknown balloon shapes or styles, an unknown reading
+// direction, a missing trim size or DPI, malformed beat links and comments pointing at pages or panels
+// that do not exist. The project is not modified. Issues are returned in document order.
+func ValidateProject(p domain.Project) []ValidationIssue {
+	var out []ValidationIssue
+	add := func(sev Severity, path, format string, args ...any) {
+		out = append(out, ValidationIssue{Path: path, Severity: sev, Message: fmt.Sprintf(format, args...)})
+	}
+	for ii, iss := range p.Issues {
+		ip := fmt.Sprintf("issues[%d]", ii)
+		if iss.TrimWidth <= 0 || iss.TrimHeight <= 0 {
+			add(SeverityError, ip+".trimWidth", "trim size %gx%g must be positive; exports need it", iss.TrimWidth, iss.TrimHeight)
+		}
+		if iss.Bleed < 0 {
+			add(SeverityWarning, ip+".bleed", "bleed %g is negative", iss.Bleed)
+		}
+		if iss.DPI <= 0 {
+			add(SeverityWarning, ip+".dpi", "DPI %d must be positive; raster exports fall back to 300", iss.DPI)
+		}
+		if d := strings.ToLower(strings.TrimSpace(iss.ReadingDirection)); d != "" && d != "ltr" && d != "rtl" {
+			add(SeverityWarning, ip+".readingDirection", "reading direction %q is neither ltr nor rtl; ltr is used", iss.ReadingDirection)
+		}
+		seen := map[int]int{}
+		for pi, pg := range iss.Pages {
+			pp := fmt.Sprintf("%s.pages[%d]", ip, pi)
+			if pg.Number <= 0 {
+				add(SeverityError, pp+".number", "page number %d must be positive", pg.Number)
+			} else if first, dup := seen[pg.Number]; dup {
+				add(SeverityError, pp+".number", "page number %d is also used by pages[%d]", pg.Number, first)
+			} else {
+				seen[pg.Number] = pi
+			}
+			if ref := pg.ReferenceImage; ref != nil {
+				if strings.TrimSpace(ref.Asset) == "" {
+					add(SeverityWarning, pp+".referenceImage.asset", "reference image has no asset path")
+				}
+				if ref.Opacity < 0 || ref.Opacity > 1 {
+					add(SeverityWarning, pp+".referenceImage.opacity", "opacity %g is outside 0..1", ref.Opacity)
+				}
+				if ref.Fit != "" && ref.Fit != domain.ReferenceFitContain && ref.Fit != domain.ReferenceFitStretch && ref.Fit != domain.ReferenceFitBleed {
+					add(SeverityWarning, pp+".referenceImage.fit", "unknown fit %q; contain is used", ref.Fit)
+				}
+			}
+			for ni, pn := range pg.Panels {
+				np := fmt.Sprintf("%s.panels[%d]", pp, ni)
+				g := pn.Geometry
+				switch {
+				case g.Width < 0 || g.Height < 0:
+					add(SeverityError, np+".geometry", "panel %s has negative size %gx%g", pn.ID, g.Width, g.Height)
+				case g.Width == 0 || g.Height == 0:
+					add(SeverityWarning, np+".geometry", "panel %s has zero size %gx%g", pn.ID, g.Width, g.Height)
+				}
+				if g.X < 0 || g.Y < 0 {
+					add(SeverityWarning, np+".geometry", "panel %s starts at negative position %g,%g", pn.ID, g.X, g.Y)
+				}
+				if err := ValidatePanelBorder(pn.Border); err != nil {
+					add(SeverityWarning, np+".border", "panel %s: %v", pn.ID, err)
+				}
+				if err := ValidatePanelAnnotations(pn.Annotations); err != nil {
+					add(SeverityWarning, np+".annotations", "panel %s: %v", pn.ID, err)
+				}
+				beats := map[string]bool{}
+				for bi, id := range pn.BeatIDs {
+					bp := fmt.Sprintf("%s.linkedBeats[%d]", np, bi)
+					if !validBeatID(id) {
+						add(SeverityWarning, bp, "beat link %q is not of the form b:<line>", id)
+					} else if beats[id] {
+						add(SeverityWarning, bp, "beat %s is linked twice", id)
+					}
+					beats[id] = true
+				}
+				for bi, b := range pn.Balloons {
+					if !knownShapeKinds[b.Shape.Kind] {
+						add(SeverityWarning, fmt.Sprintf("%s.balloons[%d].shape.kind", np, bi), "balloon %s has unknown shape %q; it is drawn as a rectangle", b.ID, b.Shape.Kind)
+					}
+					if _, ok := FindBalloonStyle(p.BalloonStyles, b.StyleRef); b.StyleRef != "" && !ok {
+						add(SeverityWarning, fmt.Sprintf("%s.balloons[%d].styleRef", np, bi), "balloon %s uses unknown balloon style %q; it is drawn with the default style", b.ID, b.StyleRef)
+					}
+					if b.Order < 0 {
+						add(SeverityWarning, fmt.Sprintf("%s.balloons[%d].order", np, bi), "balloon %s has negative reading order %d; it is read in panel order", b.ID, b.Order)
+					}
+				}
+			}
+		}
+		if prob, _ := pageNumberProblem(ii, iss); len(prob.Gaps) > 0 {
+			add(SeverityWarning, ip+".pages", "%s %s missing; Issue → Repair Page Numbers… renumbers the pages", pageList(prob.Gaps), isAre(len(prob.Gaps)))
+		}
+	}
+	if err := ValidatePanelBorder(p.PanelBorder); err != nil {
+		add(SeverityWarning, "panelBorder", "%v", err)
+	}
+	for si, s := range p.BalloonStyles {
+		if err := ValidateBalloonStyle(s); err != nil {
+			add(SeverityWarning, fmt.Sprintf("balloonStyles[%d]", si), "%v", err)
+		}
+	}
+	if err := ValidateMetadata(p.Metadata); err != nil {
+		add(SeverityWarning, "metadata", "%v", err)
+	}
+	for ci, c := range p.Comments {
+		if msg := commentTargetProblem(p, c.Target); msg != "" {
+			add(SeverityWarning, fmt.Sprintf("comments[%d].target", ci), "comment %s %s", c.ID, msg)
+		}
+	}
+	return out
+}
+
+// validBeatID reports whether id has the form produced by BeatIDFor.
+func validBeatID(id string) bool {
+	n, ok := strings.CutPrefix(id, "b:")
+	if !ok {
+		return false
+	}
+	v, err := strconv.Atoi(n)
+	return err == nil && v > 0
+}
+
+// commentTargetProblem describes why a page or panel comment target does not resolve, or returns "".
+func commentTargetProblem(p domain.Project, t domain.CommentTarget) string {
+	if t.Kind != "page" && t.Kind != "panel" && t.Kind != "balloon" {
+		return ""
+	}
+	if t.IssueIndex < 0 || t.IssueIndex >= len(p.Issues) {
+		return fmt.Sprintf("targets missing issue index %d", t.IssueIndex)
+	}
+	pi := pageIndexByNumber(p.Issues[t.IssueIndex], t.PageNumber)
+	if pi < 0 {
+		return fmt.Sprintf("targets missing page %d", t.PageNumber)
+	}
+	if t.Kind == "page" {
+		return ""
+	}
+	for _, pn := range p.Issues[t.IssueIndex].Pages[pi].Panels {
+		if pn.ID == t.PanelID {
+			return ""
+		}
+	}
+	return fmt.Sprintf("targets missing panel %s on page %d", t.PanelID, t.PageNumber)
+}
+
+// ValidationErrorsIn returns the issues of severity error, optionally only those inside issue
+// issueIndex (-1 for all), as a ValidationErrors, or nil when there are none.
+func ValidationErrorsIn(issues []ValidationIssue, issueIndex int) error {
+	prefix := ""
+	if issueIndex >= 0 {
+		prefix = fmt.Sprintf("issues[%d].", issueIndex)
+	}
+	var errs ValidationErrors
+	for _, v := range issues {
+		if v.Severity == SeverityError && strings.HasPrefix(v.Path, prefix) {
+			errs = append(errs, v)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// describeJSONError turns a decoding error for data into a message naming the manifest path and the
+// line and column, e.g. "issues[0].pages[1].number: expected int, got string (line 12, column 20)".
+func describeJSONError(data []byte, err error) string {
+	var te *json.UnmarshalTypeError
+	var se *json.SyntaxError
+	switch {
+	case errors.As(err, &te):
+		line, col := lineColumn(data, te.Offset-1)
+		return fmt.Sprintf("%s: expected %s, got %s (line %d, column %d)", jsonFieldPath(te.Field), te.Type, te.Value, line, col)
+	case errors.As(err, &se):
+		line, col := lineColumn(data, se.Offset-1)
+		return fmt.Sprintf("%v (line %d, column %d)", se, line, col)
+	}
+	return err.Error()
+}
+
+// jsonFieldPath rewrites encoding/json's dotted field path ("issues.0.pages.1.number") with
+// bracketed indexes ("issues[0].pages[1].number").
+func jsonFieldPath(field string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(part); err == nil {
+			b.WriteString("[" + part + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(part)
+	}
+	if b.Len() == 0 {
+		return "(document)"
+	}
+	return b.String()
+}
+
+// lineColumn converts a byte offset into 1-based line and column numbers. encoding/json reports the
+// offset after the offending byte, so callers pass Offset-1.
+func lineColumn(data []byte, offset int64) (line, col int) {
+	offset = min(max(offset, 0), int64(len(data)))
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	col = int(offset) - (bytes.LastIndexByte(before, '\n') + 1) + 1
+	return line, col
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func validIssue() domain.Issue {
+	return domain.Issue{TrimWidth: 360, TrimHeight: 540, DPI: 300, ReadingDirection: "ltr", Pages: []domain.Page{
+		{Number: 1, Panels: []domain.Panel{{ID: "p1", Geometry: domain.Rect{X: 10, Y: 10, Width: 100, Height: 80}, BeatIDs: []string{"b:4"},
+			Balloons: []domain.Balloon{{ID: "b1", Shape: domain.Shape{Kind: "ellipse"}}}}}},
+		{Number: 2},
+	}}
+}
+
+func TestValidateProject_Valid(t *testing.T) {
+	p := domain.Project{Issues: []domain.Issue{validIssue()},
+		Comments: []domain.Comment{{ID: "c1", Target: domain.CommentTarget{Kind: "panel", PageNumber: 1, PanelID: "p1"}}}}
+	if got := ValidateProject(p); len(got) != 0 {
+		t.Fatalf("unexpected issues: %v", got)
+	}
+}
+
+func TestValidateProject_Findings(t *testing.T) {
+	iss := validIssue()
+	iss.TrimWidth, iss.DPI, iss.ReadingDirection = 0, 0, "ttb"
+	iss.Pages[1].Number = 1
+	iss.Pages = append(iss.Pages, domain.Page{Number: -3})
+	pn := &iss.Pages[0].Panels[0]
+	pn.Geometry.Width = -5
+	pn.BeatIDs = []string{"b:4", "b:4", "beat-7"}
+	pn.Balloons[0].Shape.Kind = "star"
+	pn.Balloons[0].Order = -1
+	p := domain.Project{Issues: []domain.Issue{iss},
+		Comments: []domain.Comment{{ID: "c1", Target: domain.CommentTarget{Kind: "panel", PageNumber: 1, PanelID: "p9"}}}}
+
+	want := map[string]Severity{
+		"issues[0].trimWidth":                                 SeverityError,
+		"issues[0].dpi":                                       SeverityWarning,
+		"issues[0].readingDirection":                          SeverityWarning,
+		"issues[0].pages[1].number":                           SeverityError,
+		"issues[0].pages[2].number":                           SeverityError,
+		"issues[0].pages[0].panels[0].geometry":               SeverityError,
+		"issues[0].pages[0].panels[0].linkedBeats[1]":         SeverityWarning,
+		"issues[0].pages[0].panels[0].linkedBeats[2]":         SeverityWarning,
+		"issues[0].pages[0].panels[0].balloons[0].shape.kind": SeverityWarning,
+		"issues[0].pages[0].panels[0].balloons[0].order":      SeverityWarning,
+		"comments[0].target":                                  SeverityWarning,
+	}
+	got := ValidateProject(p)
+	for _, v := range got {
+		sev, ok := want[v.Path]
+		if !ok {
+			t.Errorf("unexpected issue %s", v)
+			continue
+		}
+		if v.Severity != sev {
+			t.Errorf("%s: severity %s, want %s", v.Path, v.Severity, sev)
+		}
+		delete(want, v.Path)
+	}
+	for path := range want {
+		t.Errorf("missing issue at %s", path)
+	}
+
+	err := ValidationErrorsIn(got, 0)
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs) != 4 {
+		t.Fatalf("errors in issue 0: %v", err)
+	}
+	if ValidationErrorsIn(got, 1) != nil {
+		t.Fatalf("issue 1 has no errors")
+	}
+}
+
+func TestOpen_ReportsValidationAndParseErrors(t *testing.T) {
+	root := t.TempDir()
+	iss := validIssue()
+	iss.DPI = 0
+	ph, err := InitProject(root, domain.Project{Name: "Checked", Issues: []domain.Issue{iss}})
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	opened, err := Open(root)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if len(opened.Validation) != 1 || opened.Validation[0].Path != "issues[0].dpi" {
+		t.Fatalf("validation = %v", opened.Validation)
+	}
+	_ = opened.Close()
+
+	// A string where a number belongs: the backup opens and the warning names the path and position
+	if err := Save(ph); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	data, err := os.ReadFile(ph.ManifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken := strings.Replace(string(data), `"number": 2`, `"number": "2"`, 1)
+	if broken == string(data) {
+		t.Fatalf("manifest layout changed:\n%s", data)
+	}
+	if err := os.WriteFile(ph.ManifestPath, []byte(broken), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opened, err = Open(root)
+	if err != nil {
+		t.Fatalf("open broken: %v", err)
+	}
+	defer func() { _ = opened.Close() }()
+	first := opened.Validation[0]
+	if first.Path != ManifestFileName || !strings.Contains(first.Message, "issues[0].pages[1].number: expected int, got string (line ") {
+		t.Fatalf("parse warning = %v", first)
+	}
+}
+
+func TestDescribeJSONError(t *testing.T) {
+	data := []byte("{\n  \"name\": \"x\",\n  \"issues\": [}\n")
+	var p domain.Project
+	err := json.Unmarshal(data, &p)
+	if got := describeJSONError(data, err); !strings.Contains(got, "(line 3, column 14)") {
+		t.Fatalf("syntax error = %q", got)
+	}
+	if got := jsonFieldPath("issues.0.pages.12.panels"); got != "issues[0].pages[12].panels" {
+		t.Fatalf("path = %q", got)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package stylepack
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+
+	"gocomicwriter/internal/domain"
+)
+
+// BalloonStylesEntry is the archive entry that carries balloon presets in a style pack:
+//
+//	{"balloonStyles": [{"name": "Whisper", "shape": "ellipse", "strokeStyle": "dashed", "size": 10}, …]}
+//
+// The presets use the fields of the project manifest.
+const BalloonStylesEntry = "templates/balloons.json"
+
+type balloonStylesFile struct {
+	BalloonStyles []domain.BalloonStyle `json:"balloonStyles"`
+}
+
+// writeBalloonStyles adds the presets to the archive as BalloonStylesEntry.
+func writeBalloonStyles(zw *zip.Writer, list []domain.BalloonStyle) error {
+	data, err := json.MarshalIndent(balloonStylesFile{BalloonStyles: list}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode balloon styles: %w", err)
+	}
+	fw, err := zw.Create(BalloonStylesEntry)
+	if err != nil {
+		return fmt.Errorf("add balloon styles: %w", err)
+	}
+	if _, err := fw.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write balloon styles: %w", err)
+	}
+	return nil
+}
+
+// ReadPackBalloonStyles returns the balloon presets of a style pack, or nil if it has none. The presets
+// are not checked; storage.MergeBalloonStyles skips invalid ones.
+func ReadPackBalloonStyles(packZipPath string) ([]domain.BalloonStyle, error) {
+	data, err := readPackEntry(packZipPath, BalloonStylesEntry, "balloon styles")
+	if data == nil || err != nil {
+		return nil, err
+	}
+	var bf balloonStylesFile
+	if err := json.Unmarshal(data, &bf); err != nil {
+		return nil, fmt.Errorf("parse balloon styles: %w", err)
+	}
+	return bf.BalloonStyles, nil
+}
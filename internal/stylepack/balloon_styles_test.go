@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package stylepack
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func TestPackCarriesBalloonStyles(t *testing.T) {
+	src := t.TempDir()
+	plain := filepath.Join(src, "plain.zip")
+	if err := ExportProjectStyles(src, plain); err != nil {
+		t.Fatalf("export without balloon styles: %v", err)
+	}
+	if got, err := ReadPackBalloonStyles(plain); err != nil || got != nil {
+		t.Fatalf("pack without balloon styles: %v %v", got, err)
+	}
+	styles := storage.DefaultBalloonStyles()
+	pack := filepath.Join(src, "balloons.zip")
+	if err := ExportProjectStylesWith(src, pack, ExportOptions{BalloonStyles: styles}); err != nil {
+		t.Fatalf("export with balloon styles: %v", err)
+	}
+	got, err := ReadPackBalloonStyles(pack)
+	if err != nil || !reflect.DeepEqual(got, styles) {
+		t.Fatalf("read balloon styles = %+v, %v", got, err)
+	}
+	if tpl, err := ReadPackPageTemplates(pack); err != nil || tpl != nil {
+		t.Errorf("balloon-only pack has page templates: %v %v", tpl, err)
+	}
+	ph := &storage.ProjectHandle{Project: domain.Project{BalloonStyles: []domain.BalloonStyle{{Name: "speech", Size: 11}}}}
+	added, skipped := storage.MergeBalloonStyles(ph, got)
+	if !reflect.DeepEqual(added, []string{"Thought", "Shout", "Whisper"}) || !reflect.DeepEqual(skipped, []string{"Speech"}) {
+		t.Errorf("merge: added %v, skipped %v", added, skipped)
+	}
+}
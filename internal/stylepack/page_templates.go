@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package stylepack
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gocomicwriter/internal/domain"
+)
+
+// PageTemplatesEntry is the archive entry that carries page templates in a style pack:
+//
+//	{"pageTemplates": [{"name": "Two tiers", "panels": [{"x": 0, "y": 0, "width": 1, "height": 0.5}, …]}]}
+//
+// Panel rectangles are fractions of the trim box, as in the project manifest.
+const PageTemplatesEntry = "templates/pages.json"
+
+type pageTemplatesFile struct {
+	PageTemplates []domain.PageTemplate `json:"pageTemplates"`
+}
+
+// writePageTemplates adds the templates to the archive as PageTemplatesEntry.
+func writePageTemplates(zw *zip.Writer, list []domain.PageTemplate) error {
+	data, err := json.MarshalIndent(pageTemplatesFile{PageTemplates: list}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode page templates: %w", err)
+	}
+	fw, err := zw.Create(PageTemplatesEntry)
+	if err != nil {
+		return fmt.Errorf("add page templates: %w", err)
+	}
+	if _, err := fw.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write page templates: %w", err)
+	}
+	return nil
+}
+
+// ReadPackPageTemplates returns the page templates of a style pack, or nil if it has none. The
+// templates are not checked; storage.MergePageTemplates skips invalid ones.
+func ReadPackPageTemplates(packZipPath string) ([]domain.PageTemplate, error) {
+	data, err := readPackEntry(packZipPath, PageTemplatesEntry, "page templates")
+	if data == nil || err != nil {
+		return nil, err
+	}
+	var pf pageTemplatesFile
+	if err := json.Unmarshal(data, &pf); err != nil {
+		return nil, fmt.Errorf("parse page templates: %w", err)
+	}
+	return pf.PageTemplates, nil
+}
+
+// readPackEntry returns the content of the archive entry name, or nil if the pack has none. what names
+// the entry in errors.
+func readPackEntry(packZipPath, name, what string) ([]byte, error) {
+	r, err := zip.OpenReader(packZipPath)
+	if err != nil {
+		return nil, fmt.Errorf("open pack: %w", err)
+	}
+	defer func() { _ = r.Close() }()
+	for _, f := range r.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", what, err)
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, maxPackEntrySize))
+		_ = rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", what, err)
+		}
+		return data, nil
+	}
+	return nil, nil
+}
+
+// maxPackEntrySize bounds how much of a templates entry is read, so a crafted pack cannot exhaust
+// memory.
+const maxPackEntrySize = 8 << 20
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package stylepack
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func TestPackCarriesPageTemplates(t *testing.T) {
+	src := t.TempDir()
+	templates := []domain.PageTemplate{
+		{Name: "Two tiers", Panels: []domain.Rect{{Width: 1, Height: 0.5}, {Y: 0.5, Width: 1, Height: 0.5}}},
+		{Name: "Splash", Panels: []domain.Rect{{X: -0.02, Y: -0.02, Width: 1.04, Height: 1.04}}},
+	}
+	plain := filepath.Join(src, "plain.zip")
+	if err := ExportProjectStyles(src, plain); err != nil {
+		t.Fatalf("export without templates: %v", err)
+	}
+	if got, err := ReadPackPageTemplates(plain); err != nil || got != nil {
+		t.Fatalf("pack without templates: %v %v", got, err)
+	}
+	pack := filepath.Join(src, "with-templates.zip")
+	if err := ExportProjectStylesWith(src, pack, ExportOptions{PageTemplates: templates}); err != nil {
+		t.Fatalf("export with templates: %v", err)
+	}
+	got, err := ReadPackPageTemplates(pack)
+	if err != nil || !reflect.DeepEqual(got, templates) {
+		t.Fatalf("read templates = %+v, %v", got, err)
+	}
+
+	// Installing the pack leaves the templates to the caller instead of copying them into styles/
+	dst := t.TempDir()
+	if _, err := InstallPack(dst, pack); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "styles", "templates", "pages.json")); !os.IsNotExist(err) {
+		t.Errorf("templates entry was installed as a style file: %v", err)
+	}
+	ph := &storage.ProjectHandle{Project: domain.Project{PageTemplates: []domain.PageTemplate{
+		{Name: "splash", Panels: []domain.Rect{{Width: 1, Height: 1}}},
+	}}}
+	added, skipped := storage.MergePageTemplates(ph, got)
+	if !reflect.DeepEqual(added, []string{"Two tiers"}) || !reflect.DeepEqual(skipped, []string{"Splash"}) {
+		t.Errorf("merge: added %v, skipped %v", added, skipped)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package stylepack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	applog "gocomicwriter/internal/log"
+)
+
+// PalettesFileName is the palette file inside a project's styles directory. It holds named palettes
+// of hex colors, one per semantic slot:
+//
+//	{"palettes": [{"name": "Sepia", "panelStroke": "#3b2a1a", "balloonFill": "#fbf3e4",
+//	  "balloonStroke": "#3b2a1a", "captionFill": "#f1deb8", "guide": "#c0392b"}]}
+//
+// A slot left out keeps the built-in default color.
+const PalettesFileName = "palettes.json"
+
+// Palette is a named set of default colors for panels, lettering and guides.
+type Palette struct {
+	Name          string
+	PanelStroke   domain.Color
+	BalloonFill   domain.Color
+	BalloonStroke domain.Color
+	CaptionFill   domain.Color
+	Guide         domain.Color
+}
+
+// paletteJSON is the file form of a Palette with colors written as #rrggbb.
+type paletteJSON struct {
+	Name          string `json:"name"`
+	PanelStroke   string `json:"panelStroke,omitempty"`
+	BalloonFill   string `json:"balloonFill,omitempty"`
+	BalloonStroke string `json:"balloonStroke,omitempty"`
+	CaptionFill   string `json:"captionFill,omitempty"`
+	Guide         string `json:"guide,omitempty"`
+}
+
+type palettesFile struct {
+	Palettes []paletteJSON `json:"palettes"`
+}
+
+// ClassicPalette matches the exporters' built-in colors: black strokes, white fills and red guides.
+var ClassicPalette = Palette{
+	Name:          "Classic",
+	PanelStroke:   domain.Color{R: 0, G: 0, B: 0, A: 255},
+	BalloonFill:   domain.Color{R: 255, G: 255, B: 255, A: 255},
+	BalloonStroke: domain.Color{R: 0, G: 0, B: 0, A: 255},
+	CaptionFill:   domain.Color{R: 255, G: 255, B: 255, A: 255},
+	Guide:         domain.Color{R: 255, G: 0, B: 0, A: 255},
+}
+
+// samplePalettes are written into exported style packs that do not have a palette file yet.
+var samplePalettes = []paletteJSON{
+	{Name: "Classic", PanelStroke: "#000000", BalloonFill: "#ffffff", BalloonStroke: "#000000", CaptionFill: "#ffffff", Guide: "#ff0000"},
+	{Name: "Sepia", PanelStroke: "#3b2a1a", BalloonFill: "#fbf3e4", BalloonStroke: "#3b2a1a", CaptionFill: "#f1deb8", Guide: "#c0392b"},
+	{Name: "Noir", PanelStroke: "#111111", BalloonFill: "#f4f4f4", BalloonStroke: "#111111", CaptionFill: "#d9d9d9", Guide: "#e67e22"},
+	{Name: "Blueprint", PanelStroke: "#1f3b73", BalloonFill: "#ffffff", BalloonStroke: "#1f3b73", CaptionFill: "#e3ecfa", Guide: "#00a0e0"},
+}
+
+// DefaultPalettes returns the palettes used when a project has no valid palette file.
+func DefaultPalettes() []Palette {
+	return []Palette{ClassicPalette}
+}
+
+// LoadPalettes reads <projectRoot>/styles/palettes.json. A missing file yields DefaultPalettes. A file
+// that cannot be read or parsed also yields DefaultPalettes, together with the error, and is logged as
+// a warning. Colors that are not #rrggbb and palettes without a name are skipped with a warning; the
+// affected slot keeps its Classic color.
+func LoadPalettes(projectRoot string) ([]Palette, error) {
+	l := applog.WithOperation(applog.WithComponent("stylepack"), "palettes").With(slog.String("project", projectRoot))
+	path := filepath.Join(projectRoot, "styles", PalettesFileName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DefaultPalettes(), nil
+	}
+	if err == nil {
+		var f palettesFile
+		if err = json.Unmarshal(data, &f); err == nil {
+			return decodePalettes(f.Palettes, l), nil
+		}
+		err = fmt.Errorf("parse %s: %w", PalettesFileName, err)
+	} else {
+		err = fmt.Errorf("read %s: %w", PalettesFileName, err)
+	}
+	l.Warn("palettes unusable; using defaults", slog.Any("err", err))
+	return DefaultPalettes(), err
+}
+
+// decodePalettes converts the file entries, falling back to DefaultPalettes when none is usable.
+func decodePalettes(in []paletteJSON, l *slog.Logger) []Palette {
+	var out []Palette
+	seen := map[string]bool{}
+	for i, pj := range in {
+		name := strings.TrimSpace(pj.Name)
+		if name == "" || seen[name] {
+			l.Warn("skip palette without a unique name", slog.Int("index", i), slog.String("name", name))
+			continue
+		}
+		seen[name] = true
+		p := ClassicPalette
+		p.Name = name
+		for _, slot := range []struct {
+			key string
+			hex string
+			dst *domain.Color
+		}{
+			{"panelStroke", pj.PanelStroke, &p.PanelStroke},
+			{"balloonFill", pj.BalloonFill, &p.BalloonFill},
+			{"balloonStroke", pj.BalloonStroke, &p.BalloonStroke},
+			{"captionFill", pj.CaptionFill, &p.CaptionFill},
+			{"guide", pj.Guide, &p.Guide},
+		} {
+			if slot.hex == "" {
+				continue
+			}
+			c, err := parseHex(slot.hex)
+			if err != nil {
+				l.Warn("palette color ignored", slog.String("palette", name), slog.String("slot", slot.key), slog.Any("err", err))
+				continue
+			}
+			*slot.dst = c
+		}
+		out = append(out, p)
+	}
+	if len(out) == 0 {
+		l.Warn("no usable palettes; using defaults")
+		return DefaultPalettes()
+	}
+	return out
+}
+
+// parseHex reads an opaque color written as #rrggbb.
+func parseHex(s string) (domain.Color, error) {
+	h := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	v, err := strconv.ParseUint(h, 16, 32)
+	if len(h) != 6 || err != nil {
+		return domain.Color{}, fmt.Errorf("color %q is not of the form #rrggbb", s)
+	}
+	return domain.Color{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}, nil
+}
+
+// ApplyPalette makes p the project's default colors: the panel stroke goes into the project's panel
+// border (width and style are kept), the other slots into its default styles. Per-panel border
+// overrides are left alone.
+func ApplyPalette(proj *domain.Project, p Palette) {
+	b := domain.PanelBorder{}
+	if proj.PanelBorder != nil {
+		b = *proj.PanelBorder
+	}
+	ps := p.PanelStroke
+	b.Color = &ps
+	proj.PanelBorder = &b
+	bf, bs, cf, g := p.BalloonFill, p.BalloonStroke, p.CaptionFill, p.Guide
+	proj.DefaultStyles = &domain.DefaultStyles{Palette: p.Name, BalloonFill: &bf, BalloonStroke: &bs, CaptionFill: &cf, Guide: &g}
+}
+
+// samplePalettesJSON is the palette file added to exported packs without one.
+func samplePalettesJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(palettesFile{Palettes: samplePalettes}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
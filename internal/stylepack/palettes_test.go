@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package stylepack
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func writePalettes(t *testing.T, root, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(root, "styles"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "styles", PalettesFileName), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPalettes_FallsBackToDefaults(t *testing.T) {
+	root := t.TempDir()
+	got, err := LoadPalettes(root)
+	if err != nil || !reflect.DeepEqual(got, DefaultPalettes()) {
+		t.Fatalf("missing file: %+v, %v", got, err)
+	}
+	writePalettes(t, root, `{"palettes": [`)
+	got, err = LoadPalettes(root)
+	if err == nil || !reflect.DeepEqual(got, DefaultPalettes()) {
+		t.Fatalf("malformed file: %+v, %v", got, err)
+	}
+	writePalettes(t, root, `{"palettes": [{"name": ""}]}`)
+	if got, err := LoadPalettes(root); err != nil || !reflect.DeepEqual(got, DefaultPalettes()) {
+		t.Fatalf("no usable palette: %+v, %v", got, err)
+	}
+}
+
+func TestLoadPalettes_ParsesSlotsAndSkipsBadColors(t *testing.T) {
+	root := t.TempDir()
+	writePalettes(t, root, `{"palettes": [
+		{"name": "Sepia", "panelStroke": "#3B2A1A", "balloonFill": "fbf3e4", "guide": "red"},
+		{"name": "Sepia"},
+		{"name": "Ink", "captionFill": "#101010"}
+	]}`)
+	got, err := LoadPalettes(root)
+	if err != nil || len(got) != 2 {
+		t.Fatalf("load: %+v, %v", got, err)
+	}
+	sepia := got[0]
+	if sepia.Name != "Sepia" || sepia.PanelStroke != (domain.Color{R: 0x3b, G: 0x2a, B: 0x1a, A: 255}) ||
+		sepia.BalloonFill != (domain.Color{R: 0xfb, G: 0xf3, B: 0xe4, A: 255}) {
+		t.Fatalf("sepia: %+v", sepia)
+	}
+	// Missing and malformed slots keep the classic colors
+	if sepia.Guide != ClassicPalette.Guide || sepia.BalloonStroke != ClassicPalette.BalloonStroke {
+		t.Fatalf("fallback slots: %+v", sepia)
+	}
+	if got[1].Name != "Ink" || got[1].CaptionFill != (domain.Color{R: 16, G: 16, B: 16, A: 255}) {
+		t.Fatalf("ink: %+v", got[1])
+	}
+}
+
+func TestApplyPalette_KeepsBorderWidthAndStyle(t *testing.T) {
+	proj := domain.Project{PanelBorder: &domain.PanelBorder{Width: 2, Style: domain.PanelBorderSolid}}
+	pal := ClassicPalette
+	pal.Name, pal.PanelStroke = "Blue", domain.Color{B: 200, A: 255}
+	ApplyPalette(&proj, pal)
+	if b := proj.PanelBorder; b.Width != 2 || b.Style != domain.PanelBorderSolid || *b.Color != pal.PanelStroke {
+		t.Fatalf("border: %+v", b)
+	}
+	ds := proj.DefaultStyles
+	if ds == nil || ds.Palette != "Blue" || *ds.Guide != pal.Guide || *ds.CaptionFill != pal.CaptionFill {
+		t.Fatalf("default styles: %+v", ds)
+	}
+}
+
+func TestExportProjectStyles_AddsSamplePalettes(t *testing.T) {
+	proj := t.TempDir()
+	zipPath := filepath.Join(proj, "pack.zip")
+	if err := ExportProjectStyles(proj, zipPath); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	// Installing the pack makes the samples loadable
+	other := t.TempDir()
+	if _, err := InstallPack(other, zipPath); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	pals, err := LoadPalettes(other)
+	if err != nil || len(pals) < 2 || pals[0].Name != "Classic" || pals[0] != ClassicPalette {
+		t.Fatalf("sample palettes: %+v, %v", pals, err)
+	}
+
+	// A project's own palette file is exported as is
+	writePalettes(t, proj, `{"palettes": [{"name": "Own"}]}`)
+	if err := ExportProjectStyles(proj, zipPath); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	n := 0
+	for _, f := range r.File {
+		if f.Name != "styles/"+PalettesFileName {
+			continue
+		}
+		n++
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		if string(data) != `{"palettes": [{"name": "Own"}]}` {
+			t.Fatalf("palette file replaced: %s", data)
+		}
+	}
+	if n != 1 {
+		t.Fatalf("palette file entries: %d", n)
+	}
+}
@@ -21,14 +21,29 @@ import (
 	"strings"
 	"time"
 
+	"gocomicwriter/internal/domain"
 	applog "gocomicwriter/internal/log"
 )
 
 // ExportProjectStyles zips the project's styles directory (<project>/styles) into a single .zip file.
 // The produced archive preserves the directory structure and adds a small manifest file at the root
 // named stylepack.manifest.txt for quick human inspection.
-// If the styles directory does not exist or is empty, it still creates the archive with only the manifest.
+// If the styles directory does not exist or is empty, it still creates the archive with the manifest.
+// A project without styles/palettes.json gets the sample palettes added to the archive in its place.
 func ExportProjectStyles(projectRoot string, destZipPath string) error {
+	return ExportProjectStylesWith(projectRoot, destZipPath, ExportOptions{})
+}
+
+// ExportOptions adds project content that does not live in the styles directory to a style pack.
+type ExportOptions struct {
+	// PageTemplates are written to templates/pages.json when not empty.
+	PageTemplates []domain.PageTemplate
+	// BalloonStyles are written to templates/balloons.json when not empty.
+	BalloonStyles []domain.BalloonStyle
+}
+
+// ExportProjectStylesWith works like ExportProjectStyles and adds what opts selects.
+func ExportProjectStylesWith(projectRoot string, destZipPath string, opts ExportOptions) error {
 	l := applog.WithOperation(applog.WithComponent("stylepack"), "export").With(slog.String("project", projectRoot))
 	if strings.TrimSpace(projectRoot) == "" {
 		return errors.New("projectRoot is required")
@@ -104,12 +119,40 @@ func ExportProjectStyles(projectRoot string, destZipPath string) error {
 		l.Error("zip build failed", slog.Any("err", err))
 		return fmt.Errorf("build zip: %w", err)
 	}
+	if _, err := os.Stat(filepath.Join(stylesDir, PalettesFileName)); os.IsNotExist(err) {
+		data, err := samplePalettesJSON()
+		if err != nil {
+			return fmt.Errorf("sample palettes: %w", err)
+		}
+		fw, err := zw.Create("styles/" + PalettesFileName)
+		if err != nil {
+			return fmt.Errorf("add sample palettes: %w", err)
+		}
+		if _, err := fw.Write(data); err != nil {
+			return fmt.Errorf("write sample palettes: %w", err)
+		}
+		added++
+	}
+	if len(opts.PageTemplates) > 0 {
+		if err := writePageTemplates(zw, opts.PageTemplates); err != nil {
+			return err
+		}
+		added++
+	}
+	if len(opts.BalloonStyles) > 0 {
+		if err := writeBalloonStyles(zw, opts.BalloonStyles); err != nil {
+			return err
+		}
+		added++
+	}
 	l.Info("style pack exported", slog.Int("files", added), slog.String("zip", destZipPath))
 	return nil
 }
 
 // InstallPack extracts the given .zip pack into the project's styles directory.
 // Existing files are not overwritten; if a file already exists, it is skipped.
+// Page templates and balloon styles are not files of the styles directory; read them with
+// ReadPackPageTemplates and ReadPackBalloonStyles.
 // Returns the count of files installed (skipped files are not counted).
 func InstallPack(projectRoot string, packZipPath string) (int, error) {
 	l := applog.WithOperation(applog.WithComponent("stylepack"), "install").With(slog.String("project", projectRoot))
@@ -134,7 +177,7 @@ func InstallPack(projectRoot string, packZipPath string) (int, error) {
 	for _, f := range r.File {
 		name := f.Name
 		// Skip top-level manifest file
-		if name == "stylepack.manifest.txt" {
+		if name == "stylepack.manifest.txt" || name == PageTemplatesEntry {
 			continue
 		}
 		// Only install files that target the styles directory or subfolders
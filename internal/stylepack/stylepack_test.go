@@ -15,6 +15,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"gocomicwriter/internal/storage"
+
+	"golang.org/x/image/font/gofont/goregular"
 )
 
 func TestExportAndInstallPack(t *testing.T) {
@@ -69,3 +73,25 @@ func TestExportAndInstallPack(t *testing.T) {
 		t.Fatalf("expected template installed: %v", err)
 	}
 }
+
+func TestPackCarriesProjectFonts(t *testing.T) {
+	projDir := t.TempDir()
+	if err := os.MkdirAll(storage.FontsDir(projDir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(storage.FontsDir(projDir), "Go-Regular.ttf"), goregular.TTF, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	zipPath := filepath.Join(t.TempDir(), "fonts.zip")
+	if err := ExportProjectStyles(projDir, zipPath); err != nil {
+		t.Fatalf("export pack: %v", err)
+	}
+	proj2 := t.TempDir()
+	if _, err := InstallPack(proj2, zipPath); err != nil {
+		t.Fatalf("install pack: %v", err)
+	}
+	fonts, err := storage.ListProjectFonts(proj2)
+	if err != nil || len(fonts) != 1 || fonts[0].Family != "Go" || fonts[0].Path != "styles/fonts/Go-Regular.ttf" {
+		t.Fatalf("installed fonts %+v (%v)", fonts, err)
+	}
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package text
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Hyphenator finds hyphenation points with Liang's algorithm, the one TeX uses: patterns such as "1ba"
+// or "c2h" put a number between letters, the highest number wins, and odd numbers allow a break.
+// A "." in a pattern matches the start or end of the word.
+//
+// The bundled patterns are small and deliberately conservative; lettering needs a few safe breaks far
+// more than it needs every possible one. A break is never placed so that one side has no vowel.
+type Hyphenator struct {
+	patterns   map[string][]int // letters → number before each letter and after the last
+	maxLen     int
+	exceptions map[string][]int
+	vowels     string
+	// LeftMin and RightMin are the fewest letters kept before and after a break.
+	LeftMin, RightMin int
+}
+
+// NewHyphenator builds a hyphenator from Liang patterns and exception words written with hyphens at
+// their break points ("every-thing"). Letters are matched case-insensitively.
+func NewHyphenator(patterns, exceptions []string, vowels string, leftMin, rightMin int) *Hyphenator {
+	h := &Hyphenator{
+		patterns:   make(map[string][]int, len(patterns)),
+		exceptions: make(map[string][]int, len(exceptions)),
+		vowels:     vowels,
+		LeftMin:    leftMin,
+		RightMin:   rightMin,
+	}
+	for _, p := range patterns {
+		var letters []rune
+		values := []int{0}
+		for _, r := range p {
+			if r >= '0' && r <= '9' {
+				values[len(values)-1] = int(r - '0')
+				continue
+			}
+			letters = append(letters, unicode.ToLower(r))
+			values = append(values, 0)
+		}
+		key := string(letters)
+		if old, ok := h.patterns[key]; ok {
+			for i := range values {
+				values[i] = max(values[i], old[i])
+			}
+		}
+		h.patterns[key] = values
+		h.maxLen = max(h.maxLen, len(letters))
+	}
+	for _, e := range exceptions {
+		var points []int
+		n := 0
+		for _, r := range e {
+			if r == '-' {
+				points = append(points, n)
+				continue
+			}
+			n++
+		}
+		h.exceptions[strings.ToLower(strings.ReplaceAll(e, "-", ""))] = points
+	}
+	return h
+}
+
+// Points returns the rune offsets in word at which it may be hyphenated, in increasing order. Word
+// should consist of letters only; see Hyphenate for text with punctuation.
+func (h *Hyphenator) Points(word string) []int {
+	lower := []rune(strings.ToLower(word))
+	n := len(lower)
+	if n < h.LeftMin+h.RightMin {
+		return nil
+	}
+	if pts, ok := h.exceptions[string(lower)]; ok {
+		return append([]int(nil), pts...)
+	}
+	// values[i] is the number before w[i] where w is the word framed by dots
+	w := make([]rune, 0, n+2)
+	w = append(append(append(w, '.'), lower...), '.')
+	values := make([]int, len(w)+1)
+	for i := range w {
+		for j := i + 1; j <= min(len(w), i+h.maxLen); j++ {
+			if pat, ok := h.patterns[string(w[i:j])]; ok {
+				for k, v := range pat {
+					values[i+k] = max(values[i+k], v)
+				}
+			}
+		}
+	}
+	var out []int
+	for p := h.LeftMin; p <= n-h.RightMin; p++ {
+		// the break before lower[p] sits before w[p+1]
+		if values[p+1]%2 == 1 && h.hasVowel(lower[:p]) && h.hasVowel(lower[p:]) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (h *Hyphenator) hasVowel(rs []rune) bool {
+	for _, r := range rs {
+		if strings.ContainsRune(h.vowels, r) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	hyphenatorsOnce sync.Once
+	hyphenators     map[string]*Hyphenator
+)
+
+// HyphenatorFor returns the bundled hyphenator for a BCP 47 language tag such as "en", "en-GB" or
+// "de_DE", or nil when the language is not supported.
+func HyphenatorFor(lang string) *Hyphenator {
+	hyphenatorsOnce.Do(func() {
+		hyphenators = map[string]*Hyphenator{
+			"en": NewHyphenator(englishPatterns(), englishExceptions, "aeiouy", 2, 3),
+			"de": NewHyphenator(germanPatterns(), germanExceptions, "aeiouyäöü", 2, 2),
+		}
+	})
+	base, _, _ := strings.Cut(strings.ToLower(lang), "-")
+	base, _, _ = strings.Cut(base, "_")
+	return hyphenators[base]
+}
+
+// Hyphenate splits word into the parts between its break points, e.g. "Mädchen" → "Mäd", "chen".
+// Soft hyphens in the word take precedence: when present they are the only break points. Without
+// them, each run of letters is hyphenated for lang, so punctuation and explicit hyphens are kept.
+func Hyphenate(word, lang string) []string {
+	var parts []string
+	last := 0
+	for _, b := range breakPoints(word, HyphenatorFor(lang)) {
+		parts = append(parts, strings.ReplaceAll(word[last:b.at], string(SoftHyphen), ""))
+		last = b.next
+	}
+	return append(parts, strings.ReplaceAll(word[last:], string(SoftHyphen), ""))
+}
+
+// wordBreak is a place where a word may be split: the first line ends before at, the next one starts
+// at next. hyphen is set when a hyphen has to be added to the first part.
+type wordBreak struct {
+	at, next int
+	hyphen   bool
+}
+
+// breakPoints returns the byte positions at which word may be broken across lines, in order. Soft
+// hyphens and explicit hyphens always qualify; with h set, the letter runs are hyphenated unless the
+// word carries soft hyphens.
+func breakPoints(word string, h *Hyphenator) []wordBreak {
+	var out []wordBreak
+	soft := strings.ContainsRune(word, SoftHyphen)
+	runStart := -1
+	flush := func(end int) {
+		if runStart < 0 {
+			return
+		}
+		if h != nil && !soft {
+			run := word[runStart:end]
+			offsets := runeOffsets(run)
+			for _, p := range h.Points(run) {
+				at := runStart + offsets[p]
+				out = append(out, wordBreak{at: at, next: at, hyphen: true})
+			}
+		}
+		runStart = -1
+	}
+	for i, r := range word {
+		switch {
+		case unicode.IsLetter(r):
+			if runStart < 0 {
+				runStart = i
+			}
+			continue
+		case r == SoftHyphen:
+			flush(i)
+			if i > 0 && i+len(string(SoftHyphen)) < len(word) {
+				out = append(out, wordBreak{at: i, next: i + len(string(SoftHyphen)), hyphen: true})
+			}
+			continue
+		}
+		flush(i)
+		if (r == '-' || r == '‐') && i > 0 && i+len(string(r)) < len(word) {
+			out = append(out, wordBreak{at: i + len(string(r)), next: i + len(string(r))})
+		}
+	}
+	flush(len(word))
+	return out
+}
+
+// runeOffsets returns the byte offset of every rune in s, plus len(s).
+func runeOffsets(s string) []int {
+	offs := make([]int, 0, len(s)+1)
+	for i := range s {
+		offs = append(offs, i)
+	}
+	return append(offs, len(s))
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package text
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHyphenate_German(t *testing.T) {
+	cases := map[string]string{
+		"Bäcker":       "Bä-cker",
+		"Mädchen":      "Mäd-chen",
+		"Straße":       "Stra-ße",
+		"größer":       "grö-ßer",
+		"Katze":        "Kat-ze",
+		"Geschichte":   "Ge-schich-te",
+		"Überraschung": "Über-ra-schung",
+		"Rathaus":      "Rat-haus",
+		"HÖHLE":        "HÖH-LE",
+		"Ei":           "Ei",
+		"Strumpf":      "Strumpf",
+	}
+	for word, want := range cases {
+		if got := strings.Join(Hyphenate(word, "de-DE"), "-"); got != want {
+			t.Errorf("%s: got %s, want %s", word, got, want)
+		}
+	}
+}
+
+func TestHyphenate_English(t *testing.T) {
+	cases := map[string]string{
+		"happen":     "hap-pen",
+		"making":     "mak-ing",
+		"running":    "run-ning",
+		"jumping":    "jump-ing",
+		"nation":     "na-tion",
+		"winter":     "win-ter",
+		"table":      "table",
+		"program":    "program",
+		"kindness":   "kind-ness",
+		"everything": "every-thing",
+		"it":         "it",
+	}
+	for word, want := range cases {
+		if got := strings.Join(Hyphenate(word, "en"), "-"); got != want {
+			t.Errorf("%s: got %s, want %s", word, got, want)
+		}
+	}
+}
+
+func TestHyphenate_SoftAndExplicitHyphens(t *testing.T) {
+	// Soft hyphens replace the patterns for that word
+	if got := strings.Join(Hyphenate("Donau\u00addampf\u00adschiff", "de"), "|"); got != "Donau|dampf|schiff" {
+		t.Fatalf("soft hyphens: %s", got)
+	}
+	// Punctuation stays attached and explicit hyphens are break points of their own
+	if got := strings.Join(Hyphenate("Spider-Man!", "en"), "|"); got != "Spider-|Man!" {
+		t.Fatalf("explicit hyphen: %s", got)
+	}
+	if got := strings.Join(Hyphenate("„Mädchen“", "de"), "|"); got != "„Mäd|chen“" {
+		t.Fatalf("quotes: %s", got)
+	}
+	if HyphenatorFor("fr") != nil || HyphenatorFor("") != nil {
+		t.Fatalf("unexpected hyphenator for unsupported language")
+	}
+	if got := Hyphenate("Mädchen", "fr"); len(got) != 1 {
+		t.Fatalf("unsupported language hyphenated: %v", got)
+	}
+}
+
+func TestNewHyphenator_Patterns(t *testing.T) {
+	h := NewHyphenator([]string{"1b", ".a2b"}, []string{"ba-ba-ba"}, "a", 1, 1)
+	// ".a2b" outweighs "1b" at the start of the word only; "aba-b" would leave no vowel
+	if got := h.Points("ababa"); len(got) != 1 || got[0] != 3 {
+		t.Fatalf("Points(ababa) = %v", got)
+	}
+	if got := h.Points("BABABA"); len(got) != 2 || got[0] != 2 || got[1] != 4 {
+		t.Fatalf("exception = %v", got)
+	}
+}
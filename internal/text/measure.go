@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+// Package text measures, hyphenates and wraps lettering text so balloons and captions can be sized to
+// what they hold. Unlike internal/textlayout, which abstracts over real font faces, it works from a
+// bundled metrics table of the default font and needs no font files, so results are identical on every
+// platform and in tests.
+package text
+
+import "strings"
+
+// SoftHyphen (U+00AD) marks where a word may be broken. It is invisible unless the line is broken there,
+// in which case a hyphen is shown.
+const SoftHyphen = '\u00ad'
+
+// LineHeight is the line advance as a multiple of the font size, matching the PDF exporter.
+const LineHeight = 1.2
+
+// fallbackWidth is used for runes the tables do not cover (1/1000 em, the width of "n").
+const fallbackWidth = 556
+
+// face selects the metrics for a font hint.
+type face int
+
+const (
+	faceRegular face = iota
+	faceBold
+	faceMono
+)
+
+// faceFor maps a font hint such as "Helvetica", "Helvetica-Bold", "bold" or "Courier" to one of the
+// bundled faces. Unknown families are measured as Helvetica, which is what the exporter falls back to.
+func faceFor(hint string) face {
+	h := strings.ToLower(hint)
+	switch {
+	case strings.Contains(h, "courier"), strings.Contains(h, "mono"):
+		return faceMono
+	case strings.Contains(h, "bold"), strings.Contains(h, "black"), strings.Contains(h, "heavy"):
+		return faceBold
+	}
+	return faceRegular
+}
+
+// advance returns the width of r in 1/1000 em.
+func (f face) advance(r rune) int {
+	switch {
+	case r == SoftHyphen || r < latin1First:
+		return 0
+	case f == faceMono:
+		return 600
+	case r <= 0xFF:
+		if f == faceBold {
+			return int(helveticaBoldLatin1[r-latin1First])
+		}
+		return int(helveticaLatin1[r-latin1First])
+	}
+	if w, ok := helveticaExtras[r]; ok {
+		if f == faceBold {
+			return int(w[1])
+		}
+		return int(w[0])
+	}
+	return fallbackWidth
+}
+
+// units returns the advance of s in 1/1000 em. Soft hyphens and control characters have no width.
+func (f face) units(s string) int {
+	n := 0
+	for _, r := range s {
+		n += f.advance(r)
+	}
+	return n
+}
+
+// MeasureString returns the width in points of s set in the font described by font at size points.
+// For text with several lines the width of the widest line is returned. Soft hyphens do not count.
+func MeasureString(font string, size float64, s string) float64 {
+	f := faceFor(font)
+	widest := 0
+	for _, line := range strings.Split(s, "\n") {
+		widest = max(widest, f.units(line))
+	}
+	return float64(widest) * size / 1000
+}
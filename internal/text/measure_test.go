@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package text
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestMeasureString(t *testing.T) {
+	// Helvetica: H=722, e=556, l=222, o=556 → 2278/1000 em
+	if got := MeasureString("Helvetica", 10, "Hello"); !near(got, 22.78) {
+		t.Fatalf("Hello = %v, want 22.78", got)
+	}
+	// Bold is wider: H=722, e=556, l=278, o=611
+	if got := MeasureString("Helvetica-Bold", 10, "Hello"); !near(got, 24.45) {
+		t.Fatalf("bold Hello = %v, want 24.45", got)
+	}
+	if got := MeasureString("Courier", 10, "Hi!"); !near(got, 18) {
+		t.Fatalf("Courier = %v, want 18", got)
+	}
+	// Umlauts and ß come from the table rather than the fallback width
+	cases := map[string]float64{"ö": 556, "Ä": 667, "ü": 556, "ß": 611, "„": 333, "…": 1000}
+	for s, units := range cases {
+		if got := MeasureString("", 1000, s); !near(got, units) {
+			t.Errorf("%q = %v, want %v", s, got, units)
+		}
+	}
+	if MeasureString("", 12, "Bäcker") != MeasureString("", 12, "Backer") {
+		t.Errorf("ä and a differ in width")
+	}
+	// Soft hyphens are invisible; the widest line counts
+	if MeasureString("", 12, "Bä\u00adcker") != MeasureString("", 12, "Bäcker") {
+		t.Errorf("soft hyphen has a width")
+	}
+	if MeasureString("", 12, "Hi\nHello") != MeasureString("", 12, "Hello") {
+		t.Errorf("multi-line text is not measured by its widest line")
+	}
+	if got := MeasureString("", 1000, "漢"); got != fallbackWidth {
+		t.Errorf("uncovered rune = %v, want fallback", got)
+	}
+}
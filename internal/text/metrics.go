@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package text
+
+// Bundled advance widths of the default lettering font, in 1/1000 em. The values are the Adobe core
+// font metrics of Helvetica and Helvetica-Bold as shipped with gofpdf, which the PDF exporter uses when
+// no font is embedded, so a balloon measured here matches the exported page.
+
+// latin1First is the first rune covered by the Latin-1 tables; they run up to U+00FF. U+0080..U+009F
+// are control characters and have no width.
+const latin1First = 0x20
+
+var helveticaLatin1 = [224]uint16{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, 350,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	278, 333, 556, 556, 556, 556, 260, 556, 333, 737, 370, 556, 584, 333, 737, 333,
+	400, 584, 333, 333, 333, 556, 537, 278, 333, 333, 365, 556, 834, 834, 834, 611,
+	667, 667, 667, 667, 667, 667, 1000, 722, 667, 667, 667, 667, 278, 278, 278, 278,
+	722, 722, 778, 778, 778, 778, 778, 584, 778, 722, 722, 722, 722, 667, 667, 611,
+	556, 556, 556, 556, 556, 556, 889, 500, 556, 556, 556, 556, 278, 278, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 584, 611, 556, 556, 556, 556, 500, 556, 500,
+}
+
+var helveticaBoldLatin1 = [224]uint16{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584, 350,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	278, 333, 556, 556, 556, 556, 280, 556, 333, 737, 370, 556, 584, 333, 737, 333,
+	400, 584, 333, 333, 333, 611, 556, 278, 333, 333, 365, 556, 834, 834, 834, 611,
+	722, 722, 722, 722, 722, 722, 1000, 722, 667, 667, 667, 667, 278, 278, 278, 278,
+	722, 722, 778, 778, 778, 778, 778, 584, 778, 722, 722, 722, 722, 667, 667, 611,
+	556, 556, 556, 556, 556, 556, 889, 556, 556, 556, 556, 556, 278, 278, 278, 278,
+	611, 611, 611, 611, 611, 611, 611, 584, 611, 611, 611, 611, 611, 556, 611, 556,
+}
+
+// helveticaExtras holds the characters outside Latin-1 that the fonts cover through Windows-1252
+// (typographic quotes, dashes, the ellipsis, the euro sign, ...), as {regular, bold}.
+var helveticaExtras = map[rune][2]uint16{
+	0x20AC: {556, 556},   // €
+	0x201A: {222, 278},   // ‚
+	0x0192: {556, 556},   // ƒ
+	0x201E: {333, 500},   // „
+	0x2026: {1000, 1000}, // …
+	0x2020: {556, 556},   // †
+	0x2021: {556, 556},   // ‡
+	0x02C6: {333, 333},   // ˆ
+	0x2030: {1000, 1000}, // ‰
+	0x0160: {667, 667},   // Š
+	0x2039: {333, 333},   // ‹
+	0x0152: {1000, 1000}, // Œ
+	0x017D: {611, 611},   // Ž
+	0x2018: {222, 278},   // ‘
+	0x2019: {222, 278},   // ’
+	0x201C: {333, 500},   // “
+	0x201D: {333, 500},   // ”
+	0x2022: {350, 350},   // •
+	0x2013: {556, 556},   // –
+	0x2014: {1000, 1000}, // —
+	0x02DC: {333, 333},   // ˜
+	0x2122: {1000, 1000}, // ™
+	0x0161: {500, 556},   // š
+	0x203A: {333, 333},   // ›
+	0x0153: {944, 944},   // œ
+	0x017E: {500, 500},   // ž
+	0x0178: {667, 667},   // Ÿ
+}
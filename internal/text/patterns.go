@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package text
+
+// The bundled patterns are generated from a handful of syllable rules rather than shipped as TeX
+// pattern files; they cover the common cases and rather miss a break than produce a wrong one.
+
+// germanPatterns follows the basic German rule: a single consonant goes to the next syllable
+// (Ma-ma, Bä-cker), of several consonants only the last one does (Kat-ze, Kis-te), and ch, ck, sch,
+// ph and th are never split.
+func germanPatterns() []string {
+	const consonants = "bcdfghjklmnpqrstvwxzß"
+	const vowels = "aeiouyäöü"
+	var pats []string
+	for _, v := range vowels {
+		for _, c := range consonants {
+			pats = append(pats, "1"+string(c)+string(v))
+		}
+		for _, g := range []string{"ch", "ck", "sch", "ph", "th"} {
+			pats = append(pats, "1"+g+string(v))
+		}
+	}
+	return append(pats, "c2h", "c2k", "s2ch", "p2h", "t2h")
+}
+
+// germanExceptions are compounds where a syllable boundary falls inside one of the digraphs or the
+// rules split a prefix wrongly.
+var germanExceptions = []string{
+	"be-ob-ach-ten", "Rat-haus", "Mit-hil-fe", "Gott-heit", "Ur-ein-woh-ner",
+}
+
+// englishPatterns splits between doubled consonants (hap-pen, run-ning), between two consonants
+// surrounded by vowels unless they form a digraph or a blend with l or r (win-ter but ta-ble, pro-gram),
+// and before a few common suffixes (mak-ing, jump-ing, na-tion, kind-ness).
+func englishPatterns() []string {
+	const consonants = "bcdfghjklmnpqrstvwxz"
+	const vowels = "aeiouy"
+	digraphs := map[string]bool{"ch": true, "ck": true, "gh": true, "ng": true, "ph": true, "qu": true, "sh": true, "th": true, "wh": true}
+	var pats []string
+	for _, c := range "bcdfglmnprstz" {
+		cc := string(c) + string(c)
+		pats = append(pats, string(c)+"1"+string(c), cc+"2ing.", cc+"2ings.")
+	}
+	for _, c1 := range consonants {
+		for _, c2 := range consonants {
+			pair := string(c1) + string(c2)
+			if c1 == c2 || digraphs[pair] || c2 == 'l' || c2 == 'r' {
+				continue
+			}
+			for _, v1 := range vowels {
+				for _, v2 := range vowels {
+					pats = append(pats, string(v1)+string(c1)+"1"+string(c2)+string(v2))
+				}
+			}
+			// jump-ing, not jum-ping
+			pats = append(pats, string(c1)+"2"+string(c2)+"ing.", string(c1)+"2"+string(c2)+"ings.")
+		}
+	}
+	return append(pats,
+		"1ing.", "1ings.", "1tion", "1sion", "1ture", "1ment", "1ness.", "1less.", "1ful.",
+		".un1", ".un2i", ".over1", ".out1",
+	)
+}
+
+// englishExceptions are compounds the rules get wrong (ever-ything).
+var englishExceptions = []string{
+	"every-thing", "some-thing", "any-thing", "every-where", "some-where", "any-where",
+	"every-one", "some-one", "any-one", "some-times", "with-out", "your-self", "my-self",
+}
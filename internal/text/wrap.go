@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package text
+
+import "strings"
+
+// WrapOptions configures WrapText and FitBlock.
+type WrapOptions struct {
+	// Font is a font hint as accepted by MeasureString; empty means Helvetica.
+	Font string
+	// Size is the font size in points; 12 when zero.
+	Size float64
+	// Lang is a BCP 47 language tag selecting hyphenation patterns ("en", "de-DE"). Words are only
+	// hyphenated at soft hyphens and explicit hyphens when the language is empty or unsupported.
+	Lang string
+}
+
+func (o WrapOptions) size() float64 {
+	if o.Size <= 0 {
+		return 12
+	}
+	return o.Size
+}
+
+// WrapText breaks text into lines no wider than width points. Lines are filled greedily; a word that
+// does not fit is hyphenated when a break point leaves a part that fits, otherwise it moves to the next
+// line. A word longer than a whole line with no usable break point is left overflowing on its own line.
+// Newlines in text start a new line, runs of spaces collapse, and soft hyphens are removed from the
+// result except where a line is broken at one.
+func WrapText(text string, width float64, opts WrapOptions) []string {
+	w := wrapper{face: faceFor(opts.Font), scale: opts.size() / 1000, width: width, hyph: HyphenatorFor(opts.Lang)}
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		lines = append(lines, w.paragraph(para)...)
+	}
+	return lines
+}
+
+type wrapper struct {
+	face  face
+	scale float64
+	width float64
+	hyph  *Hyphenator
+}
+
+func (w wrapper) fits(s string) bool {
+	return float64(w.face.units(s))*w.scale <= w.width+1e-9
+}
+
+func (w wrapper) paragraph(para string) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(para) {
+		for {
+			sep := ""
+			if line != "" {
+				sep = " "
+			}
+			if w.fits(line + sep + word) {
+				line += sep + word
+				break
+			}
+			if head, rest, ok := w.split(line+sep, word); ok {
+				lines = append(lines, stripSoft(line+sep+head))
+				line, word = "", rest
+				continue
+			}
+			if line != "" {
+				lines = append(lines, stripSoft(line))
+				line = ""
+				continue
+			}
+			line = word // too long for any line and nothing to break at
+			break
+		}
+	}
+	return append(lines, stripSoft(line))
+}
+
+// split finds the last break point in word whose first part, with a hyphen where needed, still fits
+// after prefix. It returns that part and the rest of the word.
+func (w wrapper) split(prefix, word string) (string, string, bool) {
+	bps := breakPoints(word, w.hyph)
+	for i := len(bps) - 1; i >= 0; i-- {
+		head := word[:bps[i].at]
+		if bps[i].hyphen {
+			head = stripSoft(head) + "-"
+		}
+		if w.fits(prefix + head) {
+			return head, word[bps[i].next:], true
+		}
+	}
+	return "", "", false
+}
+
+func stripSoft(s string) string { return strings.ReplaceAll(s, string(SoftHyphen), "") }
+
+// FitBlock wraps text for a box at most maxWidth points wide and returns the lines with the size of
+// the block they fill. The wrap width is narrowed as far as possible without adding lines, so the lines
+// come out evenly filled the way letterers set balloon text rather than one full line and a short one.
+// The height allows LineHeight times the font size per line.
+func FitBlock(text string, maxWidth float64, opts WrapOptions) (lines []string, width, height float64) {
+	lines = WrapText(text, maxWidth, opts)
+	lo, hi := 0.0, maxWidth
+	for range 24 {
+		mid := (lo + hi) / 2
+		if cand := WrapText(text, mid, opts); len(cand) <= len(lines) && blockWidth(cand, opts) <= mid+1e-9 {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	if cand := WrapText(text, hi, opts); len(cand) <= len(lines) {
+		lines = cand
+	}
+	return lines, blockWidth(lines, opts), float64(len(lines)) * opts.size() * LineHeight
+}
+
+func blockWidth(lines []string, opts WrapOptions) float64 {
+	widest := 0.0
+	for _, l := range lines {
+		widest = max(widest, MeasureString(opts.Font, opts.size(), l))
+	}
+	return widest
+}
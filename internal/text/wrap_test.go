@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package text
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWrapText_Greedy(t *testing.T) {
+	opts := WrapOptions{Size: 10}
+	// "the quick" is 41.13pt wide at 10pt
+	got := WrapText("the  quick brown fox\njumps", 45, opts)
+	want := []string{"the quick", "brown fox", "jumps"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for _, l := range got {
+		if w := MeasureString("", 10, l); w > 45 {
+			t.Errorf("%q is %vpt wide", l, w)
+		}
+	}
+	// A word wider than the line overflows on its own line
+	if got := WrapText("a Donaudampfschiff b", 30, opts); !reflect.DeepEqual(got, []string{"a", "Donaudampfschiff", "b"}) {
+		t.Fatalf("overflow: %q", got)
+	}
+	if got := WrapText("", 30, opts); !reflect.DeepEqual(got, []string{""}) {
+		t.Fatalf("empty: %q", got)
+	}
+}
+
+func TestWrapText_Hyphenation(t *testing.T) {
+	// "Das Mädchen" does not fit 50pt at 10pt, "Das Mäd-" does
+	de := WrapOptions{Size: 10, Lang: "de"}
+	got := WrapText("Das Mädchen lacht", 50, de)
+	want := []string{"Das Mäd-", "chen lacht"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	// Without a language the word moves down whole
+	got = WrapText("Das Mädchen lacht", 50, WrapOptions{Size: 10})
+	if !reflect.DeepEqual(got, []string{"Das", "Mädchen", "lacht"}) {
+		t.Fatalf("no language: %q", got)
+	}
+	// Soft hyphens break and show a hyphen only where used, in any language
+	got = WrapText("Donau\u00addampf\u00adschiff", 40, WrapOptions{Size: 10})
+	if !reflect.DeepEqual(got, []string{"Donau-", "dampf-", "schiff"}) {
+		t.Fatalf("soft hyphens: %q", got)
+	}
+	got = WrapText("Donau\u00addampf\u00adschiff", 500, WrapOptions{Size: 10})
+	if !reflect.DeepEqual(got, []string{"Donaudampfschiff"}) {
+		t.Fatalf("unbroken soft hyphens: %q", got)
+	}
+}
+
+func TestFitBlock_BalancesLines(t *testing.T) {
+	opts := WrapOptions{Size: 12, Lang: "en"}
+	txt := "I told you we should never have come down here"
+	greedy := WrapText(txt, 200, opts)
+	lines, w, h := FitBlock(txt, 200, opts)
+	if len(lines) != len(greedy) {
+		t.Fatalf("balanced wrap changed the line count: %q vs %q", lines, greedy)
+	}
+	if w > 200 || w >= blockWidth(greedy, opts) {
+		t.Fatalf("block not narrowed: %v (greedy %v)", w, blockWidth(greedy, opts))
+	}
+	if h != float64(len(lines))*12*LineHeight {
+		t.Fatalf("height = %v", h)
+	}
+	// Deterministic: the same input always gives the same block
+	l2, w2, h2 := FitBlock(txt, 200, opts)
+	if !reflect.DeepEqual(lines, l2) || w != w2 || h != h2 {
+		t.Fatalf("FitBlock not deterministic")
+	}
+	if _, w, _ := FitBlock("", 200, opts); w != 0 {
+		t.Fatalf("empty text width = %v", w)
+	}
+}
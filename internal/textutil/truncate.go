@@ -0,0 +1,32 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+// Package textutil holds small helpers for user-visible text.
+package textutil
+
+import "unicode/utf8"
+
+// TruncateRunes shortens s to its first n runes followed by ellipsis, so a multi-byte character is
+// never cut in half. s is returned unchanged when it has at most n runes; n <= 0 leaves only the
+// ellipsis of a non-empty s. Invalid UTF-8 in s counts one rune per bad byte, as in a range loop.
+func TruncateRunes(s string, n int, ellipsis string) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ellipsis
+	}
+	i := 0
+	for pos := range s {
+		if i == n {
+			return s[:pos] + ellipsis
+		}
+		i++
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package textutil
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateRunes(t *testing.T) {
+	cases := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"", 5, ""},
+		{"short", 5, "short"},
+		{"shorter", 5, "short…"},
+		{"Grüße aus Köln", 4, "Grüß…"},
+		{"Ärger über Öl", 1, "Ä…"},
+		{"🙂🙃😉 wink", 2, "🙂🙃…"},
+		{"👩‍🎨 artist", 1, "👩…"}, // a joined emoji is several runes; each stays valid
+		{"漫画を描く", 3, "漫画を…"},
+		{"漫画を描く", 5, "漫画を描く"},
+		{"abc", 0, "…"},
+		{"abc", -1, "…"},
+	}
+	for _, c := range cases {
+		got := TruncateRunes(c.in, c.n, "…")
+		if got != c.want {
+			t.Errorf("TruncateRunes(%q, %d) = %q, want %q", c.in, c.n, got, c.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("TruncateRunes(%q, %d) = %q is not valid UTF-8", c.in, c.n, got)
+		}
+	}
+	if got := TruncateRunes("Straße", 4, ""); got != "Stra" {
+		t.Errorf("empty ellipsis: %q", got)
+	}
+	if got := TruncateRunes("Straße", 5, "..."); got != "Straß..." {
+		t.Errorf("custom ellipsis: %q", got)
+	}
+}
@@ -13,10 +13,14 @@
 package ui
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
 	"image/color"
 	"io/fs"
 	"log/slog"
@@ -25,6 +29,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -36,6 +41,7 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/lang"
 	fstorage "fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
@@ -45,11 +51,14 @@ import (
 	"gocomicwriter/internal/crash"
 	"gocomicwriter/internal/domain"
 	"gocomicwriter/internal/export"
+	"gocomicwriter/internal/i18n"
 	applog "gocomicwriter/internal/log"
 	"gocomicwriter/internal/script"
 	"gocomicwriter/internal/storage"
 	"gocomicwriter/internal/stylepack"
 	"gocomicwriter/internal/telemetry"
+	"gocomicwriter/internal/textutil"
+	"gocomicwriter/internal/ui/controller"
 	"gocomicwriter/internal/undo"
 	"gocomicwriter/internal/vector"
 	"gocomicwriter/internal/version"
@@ -62,11 +71,17 @@ func Run(projectDir string) error {
 	l.Info("starting UI")
 	// Initialize config and telemetry (env overrides user config).
 	appCfg, backendToken, _ := config.Load()
+	l.Info("UI language", slog.String("locale", i18n.SetLocale(i18n.Detect(appCfg.General.Language))))
 	tCfg := telemetry.FromEnv()
 	if strings.TrimSpace(os.Getenv("GCW_TELEMETRY_OPT_IN")) == "" {
 		tCfg.OptIn = appCfg.General.TelemetryOptIn
 	}
 	telemetry.NewDefault(tCfg)
+	storage.SetBackupRetention(storage.BackupRetention{KeepLast: appCfg.Backups.KeepLast, KeepDailyDays: appCfg.Backups.KeepDailyDays, FullEvery: appCfg.Backups.FullEvery})
+	storage.SetScriptSnapshotRetention(storage.ScriptSnapshotRetention{KeepLast: appCfg.Backups.ScriptKeepLast, MaxAge: appCfg.Backups.ScriptMaxAge()})
+	storage.SetIndexBackupRetention(appCfg.Backups.IndexKeepLast)
+	setLengthUnit(appCfg.General.Units)
+	export.SetMaxPixels(appCfg.Export.MaxPixels)
 	if telemetry.Enabled() {
 		telemetry.Event("app_start", map[string]any{"ui": "fyne"})
 	}
@@ -104,11 +119,31 @@ func Run(projectDir string) error {
 		l = applog.WithComponent("ui")
 	}
 
-	var ph *storage.ProjectHandle
-	defer func() { crash.Recover(ph) }()
+	// Open project, selection and undo history; edits that need no widgets live in the controller
+	ed := controller.NewEditorState()
+	defer func() { crash.Recover(ed.Handle) }()
+	// Every undo snapshot is also persisted in the background, so the history survives a restart and a
+	// change can be undone after a crash
+	ed.Recorded = func(s undo.Snapshot) {
+		go func(ph *storage.ProjectHandle) {
+			if err := controller.PersistUndo(context.Background(), ph, s); err != nil {
+				l.Warn("persist undo snapshot failed", slog.Any("err", err))
+			}
+		}(ed.Handle)
+	}
+	// trimPersistedUndo drops persisted snapshots an undo or a revert took out of the history
+	trimPersistedUndo := func() {
+		ph, hist := ed.Handle, ed.UndoHistory()
+		go func() {
+			if err := controller.TrimPersistedUndo(context.Background(), ph, hist); err != nil {
+				l.Warn("trim persisted undo snapshots failed", slog.Any("err", err))
+			}
+		}()
+	}
 
 	fyneApp := app.NewWithID("gocomicwriter")
-	w := fyneApp.NewWindow("Go Comic Writer")
+	applyTheme(fyneApp, appCfg.General.Theme)
+	w := fyneApp.NewWindow(i18n.T("title.go_comic_writer"))
 	// Restore window size from preferences (with sane minimums)
 	prefs := fyneApp.Preferences()
 	winW := prefs.IntWithFallback("window.width", 1200)
@@ -121,66 +156,45 @@ func Run(projectDir string) error {
 	}
 	w.Resize(fyne.NewSize(float32(winW), float32(winH)))
 
-	status := widget.NewLabel("Ready")
+	status := widget.NewLabel(i18n.T("label.ready"))
 	canvasWidget := NewPageCanvas()
-
-	// Forward declaration for script editor entry used by various callbacks
-	var scriptEntry *widget.Entry
-
-	// Page navigation (left)
-	currentIssueIdx := 0
-	currentPageIdx := 0
-
-	// Undo manager with safeguards (snapshots capture entire Issue for simplicity)
-	undoMgr := undo.NewManager(undo.Config{
-		MaxBytes:    32 * 1024 * 1024, // 32 MiB in-memory cap
-		MaxPerPage:  20,               // keep up to 20 snapshots per page
-		MinInterval: 300 * time.Millisecond,
-	})
-
-	captureIssueSnapshot := func() ([]byte, int, error) {
-		if ph == nil || len(ph.Project.Issues) == 0 {
-			return nil, 0, fmt.Errorf("no project/issue open")
-		}
-		iss := ph.Project.Issues[currentIssueIdx]
-		blob, err := json.Marshal(iss)
-		if err != nil {
-			return nil, 0, err
+	canvasWidget.AssetRoot = func() string {
+		if ed.Handle == nil {
+			return ""
 		}
-		pgNum := 0
-		if len(iss.Pages) > 0 && currentPageIdx >= 0 && currentPageIdx < len(iss.Pages) {
-			pgNum = iss.Pages[currentPageIdx].Number
+		return ed.Handle.Root
+	}
+	canvasWidget.PanelBorder = func() *domain.PanelBorder {
+		if ed.Handle == nil {
+			return nil
 		}
-		return blob, pgNum, nil
+		return ed.Handle.Project.PanelBorder
 	}
 
+	// Forward declaration for script editor entry used by various callbacks
+	var scriptEntry *scriptEditor
+
 	// Forward declarations for UI refreshers referenced before assignment
 	var refreshPagesList func()
 	var refreshPanelsUI func()
 	var refreshStoryboard func()
-
-	applyIssueSnapshot := func(blob []byte) error {
-		if ph == nil {
-			return fmt.Errorf("no project open")
-		}
-		var iss domain.Issue
-		if err := json.Unmarshal(blob, &iss); err != nil {
-			return err
-		}
-		if currentIssueIdx < 0 {
-			currentIssueIdx = 0
-		}
-		if currentIssueIdx >= len(ph.Project.Issues) {
-			ph.Project.Issues = append(ph.Project.Issues, iss)
+	var refreshPacing func()
+	var showPacing func(storage.PacingReport)
+
+	// pacingReport computes the pacing of the open project against the script being edited; the pacing
+	// line under the canvas and the pacing panel both show it.
+	pacingReport := func() storage.PacingReport {
+		if ed.Handle == nil {
+			return storage.PacingReport{}
+		}
+		var txt string
+		if scriptEntry != nil && scriptEntry.Text() != "" {
+			txt = scriptEntry.Text()
 		} else {
-			ph.Project.Issues[currentIssueIdx] = iss
+			txt, _ = storage.ReadScript(ed.Handle)
 		}
-		if err := storage.Save(ph); err != nil {
-			return err
-		}
-		refreshPagesList()
-		refreshPanelsUI()
-		return nil
+		sc, _ := script.Parse(txt)
+		return storage.ComputePacingReport(ed.Handle.Project, sc)
 	}
 
 	// Canvas layout panes
@@ -189,61 +203,283 @@ func Run(projectDir string) error {
 	pageIdxMap := []int{}
 	pagesList := widget.NewList(
 		func() int { return len(pagesDisplay) },
-		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func() fyne.CanvasObject { return newPageListRow() },
 		func(i widget.ListItemID, o fyne.CanvasObject) {
-			if i >= 0 && int(i) < len(pagesDisplay) {
-				o.(*widget.Label).SetText(pagesDisplay[i])
-			} else {
-				o.(*widget.Label).SetText("")
+			row := o.(*pageListRow)
+			if i < 0 || int(i) >= len(pagesDisplay) {
+				row.SetText("")
+				row.OnSecondary = nil
+				return
+			}
+			row.SetText(pagesDisplay[i])
+			idx := pageIdxMap[i]
+			// Context menu: page notes
+			row.OnSecondary = func(pos fyne.Position) {
+				if ed.Handle == nil || ed.IssueIdx >= len(ed.Handle.Project.Issues) || idx >= len(ed.Handle.Project.Issues[ed.IssueIdx].Pages) {
+					return
+				}
+				num := ed.Handle.Project.Issues[ed.IssueIdx].Pages[idx].Number
+				menu := fyne.NewMenu("", fyne.NewMenuItem(i18n.T("menu.page_notes"), func() {
+					showPageNotesDialog(w, ed.Handle, ed.IssueIdx, num, l, status, nil)
+				}))
+				widget.ShowPopUpMenuAtPosition(menu, w.Canvas(), pos)
 			}
 		},
 	)
-	left := container.NewVBox(widget.NewLabel("Pages"), widget.NewSeparator(), pagesList)
+	left := container.NewBorder(container.NewVBox(widget.NewLabel(i18n.T("label.pages")), widget.NewSeparator()), nil, nil, nil, pagesList)
 	// Panel inspector (right)
 	panelDisplay := []string{}
 	panelIDs := []string{}
-	selectedPanel := -1
 	panelFilter := ""
+	panelArtFilter := "" // art stage the listed panels have not done yet; "" lists all
+	// View defaults stored with the project (.gcw/settings.json), loaded on open and written on close
+	var projSettings storage.ProjectSettings
+	// Open review comments per panel path (server feature), loaded in the background for commentsFor
+	// ("<project root>#<page number>"); clearing commentsFor reloads them on the next refresh
+	panelCommentCounts := map[string]int{}
+	commentsFor := ""
+	var loadPanelComments func(pageNumber int)
+	var showPanelComments func(panelID string)
 	panelList := widget.NewList(
 		func() int { return len(panelDisplay) },
 		func() fyne.CanvasObject { return widget.NewLabel("") },
-		func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(panelDisplay[i]) },
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			lbl := o.(*widget.Label)
+			if i < len(panelIDs) && ed.Panels.Has(panelIDs[i]) {
+				lbl.TextStyle.Bold = true
+				lbl.SetText("✓ " + panelDisplay[i])
+				return
+			}
+			lbl.TextStyle.Bold = false
+			lbl.SetText(panelDisplay[i])
+		},
 	)
-	panelHeaderLabel := widget.NewLabel("Panels")
-	panelList.OnSelected = func(id widget.ListItemID) {
-		selectedPanel = int(id)
-		if selectedPanel >= 0 && selectedPanel < len(panelIDs) {
-			l.Info("panel selected", slog.Int("index", selectedPanel), slog.String("panel_id", panelIDs[selectedPanel]))
+	panelHeaderLabel := widget.NewLabel(i18n.T("label.panels"))
+	var updateBulkButtons func()
+	// syncPanelSelection shows the panel selection in the list and on the canvas
+	syncPanelSelection := func() {
+		panelList.Refresh()
+		canvasWidget.HighlightPanelIDs(ed.SelectedPanels(panelIDs))
+		if updateBulkButtons != nil {
+			updateBulkButtons()
+		}
+	}
+	// The selected panel or balloon (ed.Selected) is shared by the canvas, the panel list and the balloon
+	// list below it. The balloon list shows the balloons of the selected panel in reading order; the
+	// selected balloon is described under it and can be moved or deleted.
+	balloonIDs := []string{}
+	balloonDisplay := []string{}
+	balloonList := widget.NewList(
+		func() int { return len(balloonDisplay) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(balloonDisplay[i]) },
+	)
+	balloonHeaderLabel := widget.NewLabel(i18n.T("label.balloons"))
+	balloonInfo := widget.NewLabel(i18n.T("label.no_balloon_selected"))
+	balloonInfo.Wrapping = fyne.TextWrapWord
+	var btnMoveBalloon, btnDeleteBalloon *widget.Button
+	// selectedBalloon returns the selected balloon from the model
+	selectedBalloon := func() (domain.Balloon, bool) {
+		sel := ed.Selected
+		if ed.Handle == nil || !sel.IsBalloon() {
+			return domain.Balloon{}, false
+		}
+		b, err := storage.FindBalloon(ed.Handle, sel.Page, sel.PanelID, sel.BalloonID)
+		return b, err == nil
+	}
+	// refreshBalloonList lists the balloons of the selected panel and shows the selected one
+	refreshBalloonList := func() {
+		balloonIDs, balloonDisplay = balloonIDs[:0], balloonDisplay[:0]
+		sel := ed.Selected
+		if iss := ed.Issue(); iss != nil && sel.PanelID != "" {
+			if pi := slices.IndexFunc(iss.Pages, func(pg domain.Page) bool { return pg.Number == sel.Page }); pi >= 0 {
+				if k := slices.IndexFunc(iss.Pages[pi].Panels, func(pn domain.Panel) bool { return pn.ID == sel.PanelID }); k >= 0 {
+					balloonIDs, balloonDisplay = balloonRows(iss.Pages[pi].Panels[k])
+				}
+			}
+		}
+		balloonList.Refresh()
+		if sel.PanelID != "" {
+			balloonHeaderLabel.SetText(i18n.T("label.balloons") + " — " + sel.PanelID)
 		} else {
-			l.Info("panel selection cleared")
+			balloonHeaderLabel.SetText(i18n.T("label.balloons"))
+		}
+		b, ok := selectedBalloon()
+		if !ok {
+			balloonList.UnselectAll()
+			balloonInfo.SetText(i18n.T("label.no_balloon_selected"))
+			btnMoveBalloon.Disable()
+			btnDeleteBalloon.Disable()
+			return
+		}
+		if i := slices.Index(balloonIDs, b.ID); i >= 0 {
+			balloonList.Select(i)
+		}
+		balloonInfo.SetText(balloonDetails(b))
+		btnMoveBalloon.Enable()
+		btnDeleteBalloon.Enable()
+	}
+	// selectEntity makes s the selection everywhere: the panel is selected in the list when it is shown
+	// there and highlighted on the canvas, the balloon selected on the canvas and in the balloon list.
+	// What no longer exists is dropped, see controller.Selection.Resolve.
+	selectEntity := func(s controller.Selection) {
+		ed.Select(s, panelIDs)
+		syncPanelSelection()
+		sel := ed.Selected
+		if _, ok := ed.SelectedPanel(panelIDs); !ok && sel.PanelID != "" {
+			// a panel of the other page of a spread, or one hidden by the panel filter
+			canvasWidget.HighlightPanelID(sel.PanelID)
+		}
+		canvasWidget.SelectBalloon(sel.PanelID, sel.BalloonID)
+		refreshBalloonList()
+	}
+	balloonList.OnSelected = func(id widget.ListItemID) {
+		if sel := ed.Selected; int(id) < len(balloonIDs) && balloonIDs[id] != sel.BalloonID {
+			selectEntity(controller.Selection{Page: sel.Page, PanelID: sel.PanelID, BalloonID: balloonIDs[id]})
+		}
+	}
+	btnMoveBalloon = widget.NewButton(i18n.T("button.move_balloon"), func() {
+		b, ok := selectedBalloon()
+		if !ok {
+			return
+		}
+		at := ed.Selected
+		u := units()
+		xEntry, yEntry := widget.NewEntry(), widget.NewEntry()
+		xEntry.SetText(u.number(b.Shape.Rect.X))
+		yEntry.SetText(u.number(b.Shape.Rect.Y))
+		dialog.ShowForm(i18n.T("msg.move_balloon", b.ID), i18n.T("msg.move"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(u.label("form.left"), xEntry),
+			widget.NewFormItem(u.label("form.top"), yEntry),
+		}, func(ok bool) {
+			if !ok || ed.Handle == nil {
+				return
+			}
+			x, errX := u.parse(xEntry.Text)
+			y, errY := u.parse(yEntry.Text)
+			if errX != nil || errY != nil {
+				dialog.ShowError(errors.New(i18n.T("msg.position_not_numbers", u.name())), w)
+				return
+			}
+			rect := b.Shape.Rect
+			rect.X, rect.Y = x, y
+			if err := ed.MoveBalloon(at, rect); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			l.Info("balloon moved", slog.Int("page", at.Page), slog.String("panel", at.PanelID), slog.String("balloon", b.ID))
+			refreshPanelsUI()
+			selectEntity(at)
+			status.SetText(i18n.T("status.balloon_moved", b.ID, u.format(x), u.format(y)))
+		}, w)
+	})
+	btnDeleteBalloon = widget.NewButton(i18n.T("button.delete"), func() {
+		b, ok := selectedBalloon()
+		if !ok {
+			return
+		}
+		at := ed.Selected
+		if err := ed.DeleteBalloon(at); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
 		}
+		l.Info("balloon deleted", slog.Int("page", at.Page), slog.String("panel", at.PanelID), slog.String("balloon", b.ID))
+		refreshPanelsUI()
+		status.SetText(i18n.T("status.balloon_deleted", b.ID, at.PanelID))
+	})
+	btnMoveBalloon.Disable()
+	btnDeleteBalloon.Disable()
+	panelList.OnSelected = func(id widget.ListItemID) {
+		ctrl, shift := false, false
+		if drv, ok := fyne.CurrentApp().Driver().(desktop.Driver); ok {
+			mods := drv.CurrentKeyModifiers()
+			ctrl = mods&fyne.KeyModifierShortcutDefault != 0
+			shift = mods&fyne.KeyModifierShift != 0
+		}
+		ed.ClickPanel(panelIDs, int(id), ctrl, shift)
+		// The list itself tracks a single row; clear it so every click, also on the same row, lands here
+		panelList.UnselectAll()
+		l.Info("panel selection changed", slog.Int("index", int(id)), slog.Int("selected", len(ed.SelectedPanels(panelIDs))))
+		syncPanelSelection()
+		canvasWidget.SelectBalloon("", "")
+		refreshBalloonList()
 	}
 	// Pacing/overlay UI controls
 	pacingLabel := widget.NewLabel("")
-	beatOverlayCheck := widget.NewCheck("Beat Coverage Overlay", func(v bool) {
-		canvasWidget.beatOverlay = v
-		l.Info("toggle beat overlay", slog.Bool("enabled", v))
+	// Reading-order warnings badge for the current page (auto-fix is an explicit action)
+	orderWarnings := []storage.ReadingOrderWarning{}
+	orderBadge := widget.NewLabel("")
+	orderBadge.Hide()
+	btnFixOrder := widget.NewButton(i18n.T("button.fix_reading_order"), func() {
+		if ed.Handle == nil || len(orderWarnings) == 0 {
+			return
+		}
+		iss := ed.Handle.Project.Issues[ed.IssueIdx]
+		if ed.PageIdx < 0 || ed.PageIdx >= len(iss.Pages) {
+			return
+		}
+		pageNum := iss.Pages[ed.PageIdx].Number
+		dir := strings.ToUpper(strings.TrimSpace(iss.ReadingDirection))
+		if dir == "" {
+			dir = "LTR"
+		}
+		var sb strings.Builder
+		for _, ow := range orderWarnings {
+			sb.WriteString("• " + ow.Message + "\n")
+		}
+		sb.WriteString("\nReassign zOrder on this page using a row-major sweep (" + dir + ")?")
+		dialog.ShowConfirm(i18n.T("msg.reading_order"), sb.String(), func(ok bool) {
+			if !ok {
+				return
+			}
+			changed, err := ed.FixReadingOrder(pageNum)
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			if !changed {
+				status.SetText(i18n.T("status.reading_order_unchanged"))
+				refreshPanelsUI()
+				return
+			}
+			l.Info("reading order fixed", slog.Int("page", pageNum))
+			refreshPanelsUI()
+			status.SetText(i18n.T("status.reading_order_fixed"))
+		}, w)
+	})
+	btnFixOrder.Disable()
+	// Beat coverage and art status both color the panels, so only one overlay is active at a time
+	overlaySelect := widget.NewSelect(canvasOverlayOptions, func(v string) {
+		canvasWidget.overlay = canvasOverlayKey(v)
+		l.Info("canvas overlay", slog.String("overlay", canvasWidget.overlay))
 		// Re-render current page if available
-		if ph != nil && len(ph.Project.Issues) > 0 {
-			iss := ph.Project.Issues[currentIssueIdx]
-			if currentPageIdx >= 0 && currentPageIdx < len(iss.Pages) {
-				canvasWidget.ShowPanels(iss.Pages[currentPageIdx])
+		if ed.Handle != nil && len(ed.Handle.Project.Issues) > 0 {
+			iss := ed.Handle.Project.Issues[ed.IssueIdx]
+			if ed.PageIdx >= 0 && ed.PageIdx < len(iss.Pages) {
+				canvasWidget.ShowPage(iss, iss.Pages[ed.PageIdx])
+			}
+		}
+	})
+	referenceCheck := widget.NewCheck(i18n.T("check.reference_image"), func(v bool) {
+		canvasWidget.showRefs = v
+		l.Info("toggle reference image", slog.Bool("visible", v))
+		if ed.Handle != nil && len(ed.Handle.Project.Issues) > 0 {
+			iss := ed.Handle.Project.Issues[ed.IssueIdx]
+			if ed.PageIdx >= 0 && ed.PageIdx < len(iss.Pages) {
+				canvasWidget.ShowPage(iss, iss.Pages[ed.PageIdx])
 			}
 		}
 	})
-	// Restore overlay preference
-	savedOverlay := prefs.BoolWithFallback("overlay.beats", false)
-	canvasWidget.beatOverlay = savedOverlay
-	beatOverlayCheck.SetChecked(savedOverlay)
+	referenceCheck.SetChecked(true)
 	// Build/update Pages list from model and respond to selection
 	refreshPagesList = func() {
 		pagesDisplay = pagesDisplay[:0]
 		pageIdxMap = pageIdxMap[:0]
-		if ph == nil || len(ph.Project.Issues) == 0 {
+		if ed.Handle == nil || len(ed.Handle.Project.Issues) == 0 {
 			pagesList.Refresh()
 			return
 		}
-		iss := ph.Project.Issues[currentIssueIdx]
+		iss := ed.Handle.Project.Issues[ed.IssueIdx]
 		// sort by page number
 		type pair struct {
 			idx int
@@ -255,7 +491,12 @@ func Run(projectDir string) error {
 		}
 		sort.Slice(pairs, func(i, j int) bool { return pairs[i].num < pairs[j].num })
 		for _, p := range pairs {
-			pagesDisplay = append(pagesDisplay, fmt.Sprintf("Page %d", p.num))
+			if partner := storage.SpreadPartner(iss, p.num); partner != 0 {
+				pagesDisplay = append(pagesDisplay, i18n.T("label.page_spread_with", p.num, partner))
+				pageIdxMap = append(pageIdxMap, p.idx)
+				continue
+			}
+			pagesDisplay = append(pagesDisplay, i18n.T("label.page_number", p.num))
 			pageIdxMap = append(pageIdxMap, p.idx)
 		}
 		pagesList.Refresh()
@@ -266,7 +507,7 @@ func Run(projectDir string) error {
 		// select current page in view if possible
 		sel := -1
 		for i, pi := range pageIdxMap {
-			if pi == currentPageIdx {
+			if pi == ed.PageIdx {
 				sel = i
 				break
 			}
@@ -276,49 +517,62 @@ func Run(projectDir string) error {
 		}
 	}
 	pagesList.OnSelected = func(id widget.ListItemID) {
-		if ph == nil || len(ph.Project.Issues) == 0 {
-			return
-		}
-		iss := ph.Project.Issues[currentIssueIdx]
-		if id < 0 || int(id) >= len(pageIdxMap) {
+		if ed.Handle == nil || len(ed.Handle.Project.Issues) == 0 {
 			return
 		}
-		idx := pageIdxMap[id]
-		if idx < 0 || idx >= len(iss.Pages) {
+		if id < 0 || int(id) >= len(pageIdxMap) || !ed.ShowPage(pageIdxMap[id]) {
 			return
 		}
-		currentPageIdx = idx
-		selectedPanel = -1
 		canvasWidget.HighlightPanelID("")
 		refreshPanelsUI()
 	}
 	refreshPanelsUI = func() {
 		panelDisplay = panelDisplay[:0]
 		panelIDs = panelIDs[:0]
-		if ph == nil || len(ph.Project.Issues) == 0 {
+		orderWarnings = orderWarnings[:0]
+		orderBadge.Hide()
+		btnFixOrder.Disable()
+		if ed.Handle == nil || len(ed.Handle.Project.Issues) == 0 {
 			panelList.Refresh()
 			pacingLabel.SetText("")
-			panelHeaderLabel.SetText("Panels")
+			panelHeaderLabel.SetText(i18n.T("label.panels"))
+			ed.Selected = controller.Selection{}
+			refreshBalloonList()
 			return
 		}
-		iss := ph.Project.Issues[currentIssueIdx]
+		iss := ed.Handle.Project.Issues[ed.IssueIdx]
 		if len(iss.Pages) == 0 {
 			panelList.Refresh()
 			pacingLabel.SetText("")
-			panelHeaderLabel.SetText("Panels")
+			panelHeaderLabel.SetText(i18n.T("label.panels"))
+			ed.Selected = controller.Selection{}
+			refreshBalloonList()
 			return
 		}
-		if currentPageIdx < 0 || currentPageIdx >= len(iss.Pages) {
-			currentPageIdx = 0
+		if ed.PageIdx < 0 || ed.PageIdx >= len(iss.Pages) {
+			ed.PageIdx = 0
+		}
+		pg := iss.Pages[ed.PageIdx]
+		if key := fmt.Sprintf("%s#%d", ed.Handle.Root, pg.Number); loadPanelComments != nil && key != commentsFor {
+			commentsFor = key
+			loadPanelComments(pg.Number)
 		}
-		pg := iss.Pages[currentPageIdx]
 		// sort by zOrder
 		panels := append([]domain.Panel(nil), pg.Panels...)
 		sort.Slice(panels, func(i, j int) bool { return panels[i].ZOrder < panels[j].ZOrder })
 		for _, p := range panels {
-			d := fmt.Sprintf("z:%d %s (%.0fx%.0f @%.0f,%.0f)", p.ZOrder, p.ID, p.Geometry.Width, p.Geometry.Height, p.Geometry.X, p.Geometry.Y)
+			if !panelMatchesArtFilter(p, panelArtFilter) {
+				continue
+			}
+			d := fmt.Sprintf("%s z:%d %s (%.0fx%.0f @%.0f,%.0f)", artStatusDot(p), p.ZOrder, p.ID, p.Geometry.Width, p.Geometry.Height, p.Geometry.X, p.Geometry.Y)
+			if p.Locked {
+				d = "🔒 " + d
+			}
 			if strings.TrimSpace(p.Notes) != "" {
-				d += " — " + p.Notes
+				d += " — " + textutil.TruncateRunes(strings.TrimSpace(p.Notes), 60, "…")
+			}
+			if n := panelCommentCounts[panelCommentPath(pg.Number, p.ID)]; n > 0 {
+				d += fmt.Sprintf(" — 💬 %d", n)
 			}
 			// Apply filter if set
 			if pf := strings.ToLower(strings.TrimSpace(panelFilter)); pf == "" || strings.Contains(strings.ToLower(d), pf) || strings.Contains(strings.ToLower(p.ID), pf) || strings.Contains(strings.ToLower(p.Notes), pf) {
@@ -327,107 +581,109 @@ func Run(projectDir string) error {
 			}
 		}
 		panelList.Refresh()
-		panelHeaderLabel.SetText(fmt.Sprintf("Panels (Page %d)", pg.Number))
-		// Update canvas rendering from model
-		canvasWidget.ShowPanels(pg)
+		panelHeaderLabel.SetText(i18n.T("status.panels_page", pg.Number))
+		// Update canvas rendering from model, keeping the panel selection highlighted
+		canvasWidget.ShowPage(iss, pg)
+		if ids := ed.SelectedPanels(panelIDs); len(ids) > 0 {
+			canvasWidget.HighlightPanelIDs(ids)
+		}
+		// A deleted panel or balloon drops out of the selection
+		ed.ResolveSelection()
+		sel := ed.Selected
+		if _, ok := ed.SelectedPanel(panelIDs); !ok && sel.PanelID != "" {
+			canvasWidget.HighlightPanelID(sel.PanelID)
+		}
+		canvasWidget.SelectBalloon(sel.PanelID, sel.BalloonID)
+		refreshBalloonList()
+		if updateBulkButtons != nil {
+			updateBulkButtons()
+		}
 		// Update pacing info
-		turns := storage.ComputePageTurnIndicators(iss)
+		pacingRep := pacingReport()
 		turnStr := ""
-		for _, ti := range turns {
-			if ti.PageNumber == pg.Number {
-				turnStr = fmt.Sprintf("Page %d — Turn:%v, Beats:%v, EndPanelBeats:%v", ti.PageNumber, ti.IsTurn, ti.HasBeats, ti.LastPanelHasBeats)
-				break
+		total := 0
+		if ed.IssueIdx < len(pacingRep.Issues) {
+			for _, pp := range pacingRep.Issues[ed.IssueIdx].Pages {
+				if pp.PageNumber == pg.Number {
+					turnStr = i18n.T("label.pacing_turn", pp.PageNumber, pp.IsTurn, pp.Beats > 0, pp.LastPanelHasBeats)
+					total = pp.Beats
+					break
+				}
 			}
 		}
-		cov := storage.ComputeBeatCoverage(ph.Project)
-		total := 0
-		for _, c := range cov {
-			if c.PageNumber == pg.Number {
-				total = c.TotalBeats
-				break
+		for _, ow := range storage.ValidateReadingOrder(iss) {
+			if ow.PageNumber == pg.Number {
+				orderWarnings = append(orderWarnings, ow)
 			}
 		}
+		orderStr := ""
+		if len(orderWarnings) > 0 {
+			orderStr = i18n.T("label.pacing_order_warnings", len(orderWarnings))
+			orderBadge.SetText(i18n.N("status.reading_order_warnings", len(orderWarnings)))
+			orderBadge.Show()
+			btnFixOrder.Enable()
+		}
 		if turnStr != "" {
-			pacingLabel.SetText(turnStr + fmt.Sprintf("; TotalBeats:%d", total))
+			pacingLabel.SetText(turnStr + i18n.T("label.pacing_total_beats", total) + orderStr)
 		} else {
-			pacingLabel.SetText(fmt.Sprintf("Page %d — TotalBeats:%d", pg.Number, total))
+			pacingLabel.SetText(i18n.T("label.pacing_page_total_beats", pg.Number, total) + orderStr)
 		}
-		// Keep storyboard in sync with panel/page updates
+		// Keep storyboard and pacing in sync with panel/page updates
 		if refreshStoryboard != nil {
 			refreshStoryboard()
 		}
-	}
-	btnAddPanel := widget.NewButton("Add Panel", func() {
-		if ph == nil {
-			return
-		}
-		iss := ph.Project.Issues[currentIssueIdx]
-		pageNum := 1
-		if currentPageIdx >= 0 && currentPageIdx < len(iss.Pages) {
-			pageNum = iss.Pages[currentPageIdx].Number
-		} else if len(iss.Pages) == 0 {
-			_, _ = storage.EnsurePage(ph, 1)
-			pageNum = 1
+		if showPacing != nil {
+			showPacing(pacingRep)
 		}
-		if _, err := storage.AddPanel(ph, pageNum, domain.Panel{}); err != nil {
-			dialog.ShowError(err, w)
+	}
+	btnAddPanel := widget.NewButton(i18n.T("button.add_panel"), func() {
+		if ed.Handle == nil {
 			return
 		}
-		if err := storage.Save(ph); err != nil {
-			dialog.ShowError(err, w)
+		if _, err := ed.AddPanel(); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
 			return
 		}
 		refreshPanelsUI()
-		status.SetText("Panel added.")
+		status.SetText(i18n.T("status.panel_added"))
 	})
-	btnUp := widget.NewButton("Move Up", func() {
-		if ph == nil || selectedPanel < 0 || selectedPanel >= len(panelIDs) {
-			return
-		}
-		id := panelIDs[selectedPanel]
-		iss := ph.Project.Issues[currentIssueIdx]
-		pageNum := 1
-		if currentPageIdx >= 0 && currentPageIdx < len(iss.Pages) {
-			pageNum = iss.Pages[currentPageIdx].Number
-		}
-		if err := storage.MovePanelZ(ph, pageNum, id, +1); err != nil {
-			dialog.ShowError(err, w)
+	btnUp := widget.NewButton(i18n.T("button.move_up"), func() {
+		id, ok := ed.SelectedPanel(panelIDs)
+		if ed.Handle == nil || !ok {
 			return
 		}
-		if err := storage.Save(ph); err != nil {
-			dialog.ShowError(err, w)
+		if err := ed.MovePanelZ(id, +1); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
 			return
 		}
 		refreshPanelsUI()
 	})
-	btnDown := widget.NewButton("Move Down", func() {
-		if ph == nil || selectedPanel < 0 || selectedPanel >= len(panelIDs) {
+	btnDown := widget.NewButton(i18n.T("button.move_down"), func() {
+		id, ok := ed.SelectedPanel(panelIDs)
+		if ed.Handle == nil || !ok {
 			return
 		}
-		id := panelIDs[selectedPanel]
-		iss := ph.Project.Issues[currentIssueIdx]
-		pageNum := 1
-		if currentPageIdx >= 0 && currentPageIdx < len(iss.Pages) {
-			pageNum = iss.Pages[currentPageIdx].Number
-		}
-		if err := storage.MovePanelZ(ph, pageNum, id, -1); err != nil {
-			dialog.ShowError(err, w)
+		if err := ed.MovePanelZ(id, -1); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
 			return
 		}
-		if err := storage.Save(ph); err != nil {
-			dialog.ShowError(err, w)
+		refreshPanelsUI()
+	})
+	btnComments := widget.NewButton(i18n.T("button.comments"), func() {
+		id, ok := ed.SelectedPanel(panelIDs)
+		if ed.Handle == nil || !ok || showPanelComments == nil {
 			return
 		}
-		refreshPanelsUI()
+		showPanelComments(id)
 	})
-	btnEdit := widget.NewButton("Edit Metadata", func() {
-		if ph == nil || selectedPanel < 0 || selectedPanel >= len(panelIDs) {
+	btnEdit := widget.NewButton(i18n.T("button.edit_metadata"), func() {
+		id, ok := ed.SelectedPanel(panelIDs)
+		if ed.Handle == nil || !ok {
 			return
 		}
-		id := panelIDs[selectedPanel]
 		// fetch current values
-		iss := ph.Project.Issues[currentIssueIdx]
-		pg := iss.Pages[currentPageIdx]
+		iss := ed.Handle.Project.Issues[ed.IssueIdx]
+		pg := iss.Pages[ed.PageIdx]
 		var cur domain.Panel
 		for _, p := range pg.Panels {
 			if p.ID == id {
@@ -439,254 +695,651 @@ func Run(projectDir string) error {
 		idEntry.SetText(cur.ID)
 		notesEntry := widget.NewMultiLineEntry()
 		notesEntry.SetText(cur.Notes)
-		form := dialog.NewForm("Panel Metadata", "Save", "Cancel", []*widget.FormItem{
-			widget.NewFormItem("ID", idEntry),
-			widget.NewFormItem("Notes", notesEntry),
+		// Art checklist
+		ann := domain.PanelAnnotations{}
+		if cur.Annotations != nil {
+			ann = *cur.Annotations
+		}
+		stageChecks := container.NewHBox()
+		for _, stage := range domain.ArtStages {
+			c := widget.NewCheck(artStageLabels[stage], nil)
+			c.SetChecked(ann.Status[stage])
+			stageChecks.Add(c)
+		}
+		assigneeEntry := widget.NewEntry()
+		assigneeEntry.SetText(ann.Assignee)
+		dueEntry := widget.NewEntry()
+		dueEntry.SetText(ann.Due)
+		dueEntry.SetPlaceHolder(i18n.T("placeholder.yyyy_mm_dd"))
+		dueEntry.Validator = func(s string) error {
+			return storage.ValidatePanelAnnotations(&domain.PanelAnnotations{Due: s})
+		}
+		form := dialog.NewForm(i18n.T("msg.panel_metadata"), i18n.T("msg.save"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.T("form.id"), idEntry),
+			widget.NewFormItem(i18n.T("form.notes"), notesEntry),
+			widget.NewFormItem(i18n.T("form.done"), stageChecks),
+			widget.NewFormItem(i18n.T("form.assignee"), assigneeEntry),
+			{Text: i18n.T("form.due"), Widget: dueEntry, HintText: i18n.T("form.optional")},
 		}, func(ok bool) {
 			if !ok {
 				return
 			}
 			newID := strings.TrimSpace(idEntry.Text)
 			pageNum := pg.Number
-			if err := storage.UpdatePanelMeta(ph, pageNum, id, newID, notesEntry.Text); err != nil {
-				dialog.ShowError(err, w)
-				return
+			next := &domain.PanelAnnotations{Status: map[string]bool{}, Assignee: assigneeEntry.Text, Due: dueEntry.Text}
+			for i, stage := range domain.ArtStages {
+				next.Status[stage] = stageChecks.Objects[i].(*widget.Check).Checked
 			}
-			if err := storage.Save(ph); err != nil {
-				dialog.ShowError(err, w)
+			if err := ed.UpdatePanel(pageNum, id, newID, notesEntry.Text, next); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
 			refreshPanelsUI()
-			status.SetText("Panel updated.")
+			status.SetText(i18n.T("status.panel_updated"))
 		}, w)
 		form.Show()
 	})
-	// Panel quick filter
-	panelFilterEntry := widget.NewEntry()
-	panelFilterEntry.SetPlaceHolder("Filter panels…")
-	panelFilterEntry.OnChanged = func(s string) {
-		panelFilter = strings.ToLower(strings.TrimSpace(s))
-		refreshPanelsUI()
-	}
-
-	// Search state (omnibox + results panel)
-	searchItems := []string{}
-	var searchResults []storage.SearchResult
-	searchList := widget.NewList(
-		func() int { return len(searchItems) },
-		func() fyne.CanvasObject { return widget.NewLabel("") },
-		func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(searchItems[i]) },
-	)
-	// parse page/panel from index path
-	parsePagePanel := func(path string) (int, string) {
-		page := 0
-		panel := ""
-		parts := strings.Split(path, "/")
-		for _, p := range parts {
-			if strings.HasPrefix(p, "page:") {
-				if v, err := strconv.Atoi(strings.TrimPrefix(p, "page:")); err == nil {
-					page = v
-				}
-			} else if strings.HasPrefix(p, "panel:") {
-				panel = strings.TrimPrefix(p, "panel:")
+	// showBorderDialog edits a panel border; blank fields inherit. apply stores the result (nil when
+	// everything inherits) and saves the project; the panels are then redrawn.
+	showBorderDialog := func(title string, cur *domain.PanelBorder, apply func(*domain.PanelBorder) error) {
+		if cur == nil {
+			cur = &domain.PanelBorder{}
+		}
+		styleSelect := widget.NewSelect(borderStyleOptions, nil)
+		styleSelect.SetSelected(borderStyleLabel(cur.Style))
+		widthEntry := widget.NewEntry()
+		widthEntry.SetPlaceHolder(i18n.T("placeholder.inherit_1pt"))
+		if cur.Width > 0 {
+			widthEntry.SetText(strconv.FormatFloat(cur.Width, 'g', -1, 64))
+		}
+		colorEntry := widget.NewEntry()
+		colorEntry.SetPlaceHolder(i18n.T("placeholder.inherit_000000"))
+		colorEntry.SetText(formatHexColor(cur.Color))
+		dialog.ShowForm(title, i18n.T("msg.apply"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.T("form.style"), styleSelect),
+			widget.NewFormItem(i18n.T("form.width_pt"), widthEntry),
+			widget.NewFormItem(i18n.T("form.color"), colorEntry),
+		}, func(ok bool) {
+			if !ok || ed.Handle == nil {
+				return
 			}
-		}
-		return page, panel
+			b, err := parsePanelBorder(styleSelect.Selected, widthEntry.Text, colorEntry.Text)
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			if err := apply(b); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			refreshPanelsUI()
+			status.SetText(i18n.T("status.entry_updated", title))
+		}, w)
 	}
-	// Navigation helper
-	navigateToResult := func(r storage.SearchResult) {
-		page, panel := parsePagePanel(r.Path)
-		if r.PageID > 0 && page == 0 {
-			page = r.PageID
-		}
-		if ph == nil || len(ph.Project.Issues) == 0 {
+	btnBorder := widget.NewButton(i18n.T("button.border"), func() {
+		id, ok := ed.SelectedPanel(panelIDs)
+		if ed.Handle == nil || !ok {
 			return
 		}
-		iss := ph.Project.Issues[0]
-		for _, pg := range iss.Pages {
-			if page == 0 || pg.Number == page {
-				// update selection to this page and refresh UI
-				for i := range iss.Pages {
-					if iss.Pages[i].Number == pg.Number {
-						currentPageIdx = i
-						break
-					}
-				}
-				refreshPanelsUI()
-				// sync pages list selection
-				refreshPagesList()
-				// highlight panel if specified
-				if panel != "" {
-					canvasWidget.HighlightPanelID(panel)
-				} else {
-					canvasWidget.HighlightPanelID("")
-				}
+		pg := ed.Handle.Project.Issues[ed.IssueIdx].Pages[ed.PageIdx]
+		var cur *domain.PanelBorder
+		for _, p := range pg.Panels {
+			if p.ID == id {
+				cur = p.Border
 				break
 			}
 		}
-	}
-	// Omnibox and search executor
-	omniBox := widget.NewEntry()
-	omniBox.SetPlaceHolder("Search project (Ctrl+K)…")
-	runSearch := func(q string) {
-		qq := strings.TrimSpace(q)
-		if qq == "" || ph == nil {
-			searchItems = searchItems[:0]
-			searchResults = searchResults[:0]
-			searchList.Refresh()
+		showBorderDialog(i18n.T("msg.panel_border", id), cur, func(b *domain.PanelBorder) error {
+			if err := ed.SetPanelBorder(pg.Number, id, b); err != nil {
+				return err
+			}
+			l.Info("panel border set", slog.Int("page", pg.Number), slog.String("panel", id))
+			return nil
+		})
+	})
+	btnExportPanel := widget.NewButton(i18n.T("button.export_png"), func() {
+		id, ok := ed.SelectedPanel(panelIDs)
+		if ed.Handle == nil || !ok {
+			dialog.ShowInformation(i18n.T("msg.export_panel_as_png"), i18n.T("msg.select_a_panel_first"), w)
 			return
 		}
-		status.SetText("Searching…")
-		go func(h *storage.ProjectHandle, text string) {
-			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-			defer cancel()
-			res, err := storage.Search(ctx, h.Root, storage.SearchQuery{Text: text, Limit: 200})
-			fyne.Do(func() {
-				if err != nil {
-					l.Error("search failed", slog.Any("err", err))
-					status.SetText("Search failed.")
-					return
-				}
-				searchResults = res
-				searchItems = searchItems[:0]
-				for _, r := range res {
-					page := "-"
-					if r.PageID > 0 {
-						page = fmt.Sprintf("%d", r.PageID)
-					}
-					sn := strings.TrimSpace(r.Snippet)
-					if sn == "" {
-						sn = r.Path
-					}
-					if len(sn) > 120 {
-						sn = sn[:120] + "…"
-					}
-					searchItems = append(searchItems, fmt.Sprintf("p.%s — %s — %s", page, r.Type, sn))
-				}
-				searchList.Refresh()
-				status.SetText(fmt.Sprintf("%d results", len(res)))
-			})
-		}(ph, qq)
-	}
-	omniBox.OnSubmitted = func(s string) { runSearch(s) }
-	searchList.OnSelected = func(id widget.ListItemID) {
-		if id < 0 || int(id) >= len(searchResults) {
+		pg := ed.Handle.Project.Issues[ed.IssueIdx].Pages[ed.PageIdx]
+		showPanelExportDialog(w, ed.Handle, ed.IssueIdx, pg.Number, id, l)
+	})
+	// Split cuts the selected panel in two with the page's gutter between the halves
+	btnSplit := widget.NewButton(i18n.T("button.split"), func() {
+		id, ok := ed.SelectedPanel(panelIDs)
+		if ed.Handle == nil || !ok {
+			dialog.ShowInformation(i18n.T("msg.split_panel"), i18n.T("msg.select_a_panel_first"), w)
 			return
 		}
-		navigateToResult(searchResults[id])
+		pg := ed.Handle.Project.Issues[ed.IssueIdx].Pages[ed.PageIdx]
+		sideBySide, stacked := i18n.T("option.split_side_by_side"), i18n.T("option.split_stacked")
+		axisSelect := widget.NewRadioGroup([]string{sideBySide, stacked}, nil)
+		axisSelect.SetSelected(sideBySide)
+		ratioEntry := widget.NewEntry()
+		ratioEntry.SetText("50")
+		u := units()
+		gutterEntry := widget.NewEntry()
+		gutterEntry.SetText(u.number(storage.PageGutter(pg)))
+		dialog.ShowForm(i18n.T("msg.split_panel_id", id), i18n.T("button.split"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.T("form.split_direction"), axisSelect),
+			widget.NewFormItem(i18n.T("form.split_first_percent"), ratioEntry),
+			widget.NewFormItem(u.label("form.gutter"), gutterEntry),
+		}, func(ok bool) {
+			if !ok || ed.Handle == nil {
+				return
+			}
+			pct, perr := strconv.ParseFloat(strings.TrimSpace(ratioEntry.Text), 64)
+			if perr != nil || pct <= 0 || pct >= 100 {
+				dialog.ShowError(errors.New(i18n.T("msg.invalid_split_ratio")), w)
+				return
+			}
+			gutter, perr := u.parse(gutterEntry.Text)
+			if perr != nil || gutter < 0 {
+				dialog.ShowError(errors.New(i18n.T("msg.invalid_gutter", u.name())), w)
+				return
+			}
+			axis := storage.SplitVertical
+			if axisSelect.Selected == stacked {
+				axis = storage.SplitHorizontal
+			}
+			second, err := ed.SplitPanel(id, axis, pct/100, gutter)
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			l.Info("panel split", slog.Int("page", pg.Number), slog.String("panel", id), slog.String("new", second.ID))
+			refreshPanelsUI()
+			status.SetText(i18n.T("status.panel_split", id, second.ID))
+		}, w)
+	})
+	// Bulk actions on the panel selection; each is a single undo step persisted with one save
+	applyPanelBulk := func(what string, op func(ids []string) error) bool {
+		if ed.Handle == nil || len(ed.Handle.Project.Issues) == 0 {
+			return false
+		}
+		ids := ed.SelectedPanels(panelIDs)
+		if len(ids) == 0 || ed.Page() == nil {
+			dialog.ShowInformation(what, i18n.T("msg.select_panels_first_ctrl_click_or"), w)
+			return false
+		}
+		if err := op(ids); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return false
+		}
+		l.Info("panel bulk action", slog.String("action", what), slog.Int("panels", len(ids)))
+		status.SetText(i18n.N("status.panels_bulk", len(ids), what, len(ids)))
+		return true
 	}
-
-	right := container.NewBorder(nil, nil, nil, nil, container.NewVBox(
-		widget.NewLabel("Search Results"), searchList, widget.NewSeparator(),
-		widget.NewLabel("Inspector"), widget.NewSeparator(),
-		pacingLabel, beatOverlayCheck, widget.NewSeparator(),
-		panelHeaderLabel, panelFilterEntry, panelList,
-		container.NewHBox(btnAddPanel, btnUp, btnDown, btnEdit),
-	))
-	canvasCenter := container.NewMax(canvasWidget)
-	// Wire asset placement callback: append asset token into target panel notes and save
-	canvasWidget.OnPlaceAsset = func(path string, panelID string) {
-		if ph == nil {
+	btnBulkDelete := widget.NewButton(i18n.T("button.delete_selected"), func() {
+		n := len(ed.SelectedPanels(panelIDs))
+		if n == 0 {
 			return
 		}
-		rel := path
-		if abs, err := filepath.Abs(path); err == nil {
-			if r, rerr := filepath.Rel(ph.Root, abs); rerr == nil {
-				rel = r
+		dialog.ShowConfirm(i18n.T("msg.delete_panels"), i18n.N("msg.delete_panels_confirm", n), func(ok bool) {
+			if !ok {
+				return
 			}
-		}
-		iss := ph.Project.Issues[currentIssueIdx]
-		if currentPageIdx < 0 || currentPageIdx >= len(iss.Pages) {
+			applyPanelBulk(i18n.T("status.deleted_panels"), ed.DeletePanels)
+			refreshPanelsUI()
+		}, w)
+	})
+	btnBulkNotes := widget.NewButton(i18n.T("button.set_notes"), func() {
+		ids := ed.SelectedPanels(panelIDs)
+		if ed.Handle == nil || len(ids) == 0 || ed.PageIdx < 0 || ed.PageIdx >= len(ed.Handle.Project.Issues[ed.IssueIdx].Pages) {
 			return
 		}
-		pg := &ph.Project.Issues[currentIssueIdx].Pages[currentPageIdx]
-		for i := range pg.Panels {
-			if pg.Panels[i].ID == panelID {
-				note := strings.TrimSpace(pg.Panels[i].Notes)
-				entry := "asset:" + rel
-				if note == "" {
-					pg.Panels[i].Notes = entry
-				} else if !strings.Contains(note, entry) {
-					pg.Panels[i].Notes = note + "\n" + entry
+		// Prefill when all selected panels share the same notes
+		notes, same := "", true
+		for k, id := range ids {
+			for _, p := range ed.Handle.Project.Issues[ed.IssueIdx].Pages[ed.PageIdx].Panels {
+				if p.ID != id {
+					continue
+				}
+				if k == 0 {
+					notes = p.Notes
+				} else if p.Notes != notes {
+					same = false
 				}
-				break
 			}
 		}
-		if err := storage.Save(ph); err != nil {
-			l.Error("save after place asset", slog.Any("err", err))
-			dialog.ShowError(err, w)
-			return
+		entry := widget.NewMultiLineEntry()
+		if same {
+			entry.SetText(notes)
 		}
-		refreshPanelsUI()
-		status.SetText("Placed asset into panel: " + panelID)
-	}
-	// Review mode controls and quick comment entry (minimal Phase 7)
-	reviewMode := prefs.BoolWithFallback("review.mode", false)
-	reviewCheck := widget.NewCheck("Review Mode", func(b bool) {
-		reviewMode = b
-		prefs.SetBool("review.mode", b)
-	})
-	reviewCheck.SetChecked(reviewMode)
-
-	// Script change tracking toggle and history
-	trackChanges := prefs.BoolWithFallback("script.track", false)
-	trackCheck := widget.NewCheck("Track Changes", func(b bool) {
-		trackChanges = b
-		prefs.SetBool("script.track", b)
+		dialog.ShowForm(i18n.T("msg.set_notes"), i18n.T("msg.apply"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.N("form.notes_panels", len(ids)), entry),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			applyPanelBulk(i18n.T("status.set_notes"), func(ids []string) error {
+				return ed.SetPanelsNotes(ids, entry.Text)
+			})
+			refreshPanelsUI()
+		}, w)
 	})
-	trackCheck.SetChecked(trackChanges)
-
-	scriptHistBtn := widget.NewButton("Script History", func() {
-		if ph == nil {
-			dialog.ShowInformation("No project", "Open a project first.", w)
-			return
-		}
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
-		items, err := storage.ListScriptSnapshots(ctx, ph, 20)
-		if err != nil {
-			dialog.ShowError(err, w)
+	btnBulkOffset := widget.NewButton(i18n.T("button.offset"), func() {
+		if ed.Handle == nil || len(ed.SelectedPanels(panelIDs)) == 0 {
 			return
 		}
-		if len(items) == 0 {
-			dialog.ShowInformation("Script History", "No snapshots yet.", w)
+		u := units()
+		dxEntry := widget.NewEntry()
+		dxEntry.SetText("0")
+		dyEntry := widget.NewEntry()
+		dyEntry.SetText("0")
+		dialog.ShowForm(i18n.T("msg.offset_panels"), i18n.T("msg.move"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(u.label("form.dx"), dxEntry),
+			widget.NewFormItem(u.label("form.dy"), dyEntry),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			dx, errX := u.parse(dxEntry.Text)
+			dy, errY := u.parse(dyEntry.Text)
+			if errX != nil || errY != nil {
+				dialog.ShowError(errors.New(i18n.T("msg.offsets_not_numbers", u.name())), w)
+				return
+			}
+			applyPanelBulk(i18n.T("status.moved_panels"), func(ids []string) error {
+				return ed.OffsetPanels(ids, dx, dy)
+			})
+			refreshPanelsUI()
+		}, w)
+	})
+	distribute := func(horizontal bool) func() {
+		return func() {
+			applyPanelBulk(i18n.T("status.distributed_panels"), func(ids []string) error {
+				return ed.DistributePanels(ids, horizontal)
+			})
+			refreshPanelsUI()
+		}
+	}
+	btnDistH := widget.NewButton(i18n.T("button.distribute_horizontally"), distribute(true))
+	btnDistV := widget.NewButton(i18n.T("button.distribute_vertically"), distribute(false))
+	// Merge replaces the selected panels with one covering them; it is refused when another panel is in between
+	btnMerge := widget.NewButton(i18n.T("button.merge"), func() {
+		var merged domain.Panel
+		if applyPanelBulk(i18n.T("status.merged_panels"), func(ids []string) (err error) {
+			merged, err = ed.MergePanels(ids)
+			return err
+		}) {
+			refreshPanelsUI()
+			selectEntity(controller.Selection{Page: ed.PageNumber(), PanelID: merged.ID})
 			return
 		}
-		box := container.NewVBox()
-		for _, it := range items {
-			ts := it.TS
-			text := it.Text // capture for closure
-			label := ts.Local().Format("2006-01-02 15:04:05")
-			btn := widget.NewButton("Restore "+label, func() {
-				d := dialog.NewConfirm("Restore Script", "Replace editor contents with snapshot from "+label+"?", func(ok bool) {
-					if !ok {
+		refreshPanelsUI()
+	})
+	// selectionLocked reports whether every selected panel is locked, so the lock button unlocks them
+	selectionLocked := func() bool { return ed.PanelsLocked(ed.SelectedPanels(panelIDs)) }
+	btnBulkLock := widget.NewButton(i18n.T("button.lock"), func() {
+		lock := !selectionLocked()
+		what := i18n.T("status.locked_panels")
+		if !lock {
+			what = i18n.T("status.unlocked_panels")
+		}
+		applyPanelBulk(what, func(ids []string) error {
+			return ed.SetPanelsLocked(ids, lock)
+		})
+		refreshPanelsUI()
+	})
+	updateBulkButtons = func() {
+		n := len(ed.SelectedPanels(panelIDs))
+		if selectionLocked() {
+			btnBulkLock.SetText(i18n.T("button.unlock"))
+		} else {
+			btnBulkLock.SetText(i18n.T("button.lock"))
+		}
+		for _, b := range []*widget.Button{btnBulkDelete, btnBulkNotes, btnBulkOffset, btnBulkLock} {
+			if n > 0 {
+				b.Enable()
+			} else {
+				b.Disable()
+			}
+		}
+		if n >= 2 {
+			btnMerge.Enable()
+		} else {
+			btnMerge.Disable()
+		}
+		for _, b := range []*widget.Button{btnDistH, btnDistV} {
+			if n >= 3 {
+				b.Enable()
+			} else {
+				b.Disable()
+			}
+		}
+	}
+	updateBulkButtons()
+	// Panel quick filter
+	panelFilterEntry := widget.NewEntry()
+	panelFilterEntry.SetPlaceHolder(i18n.T("placeholder.filter_panels"))
+	panelFilterEntry.OnChanged = func(s string) {
+		panelFilter = strings.ToLower(strings.TrimSpace(s))
+		refreshPanelsUI()
+	}
+	artFilterSelect := widget.NewSelect(artStageFilterOptions, func(v string) {
+		panelArtFilter = artStageFilterStage(v)
+		refreshPanelsUI()
+	})
+	artFilterSelect.SetSelected(artStageFilterOptions[0])
+
+	// storeProjectSettings writes the view state of the open project to its settings file; it is
+	// called whenever the project is closed or replaced.
+	storeProjectSettings := func() {
+		if ed.Handle == nil {
+			return
+		}
+		projSettings.Overlay = canvasWidget.overlay
+		projSettings.BeatOverlay = canvasWidget.overlay == storage.OverlayBeats
+		projSettings.ArtStageFilter = panelArtFilter
+		projSettings.HideReference = !canvasWidget.showRefs
+		projSettings.PanelFilter = panelFilterEntry.Text
+		projSettings.LastIssue, projSettings.LastPage = ed.IssueIdx, 0
+		if ed.IssueIdx < len(ed.Handle.Project.Issues) && ed.PageIdx >= 0 && ed.PageIdx < len(ed.Handle.Project.Issues[ed.IssueIdx].Pages) {
+			projSettings.LastPage = ed.Handle.Project.Issues[ed.IssueIdx].Pages[ed.PageIdx].Number
+		}
+		if err := storage.SaveProjectSettings(ed.Handle.Root, projSettings); err != nil {
+			l.Warn("save project settings failed", slog.Any("err", err))
+		}
+	}
+	// applyProjectSettings loads the settings of the just opened project and restores the overlay,
+	// panel filter and last shown page. A broken settings file is logged and replaced by the defaults.
+	applyProjectSettings := func() {
+		if ed.Handle == nil {
+			return
+		}
+		var err error
+		if projSettings, err = storage.LoadProjectSettings(ed.Handle.Root); err != nil {
+			l.Warn("load project settings failed; using defaults", slog.Any("err", err))
+		}
+		overlaySelect.SetSelected(canvasOverlayLabel(projSettings.ActiveOverlay()))
+		artFilterSelect.SetSelected(artStageFilterLabel(projSettings.ArtStageFilter))
+		referenceCheck.SetChecked(!projSettings.HideReference)
+		canvasWidget.Snap = projSettings.Snap
+		panelFilterEntry.SetText(projSettings.PanelFilter)
+		if i := projSettings.LastIssue; i > 0 && i < len(ed.Handle.Project.Issues) {
+			ed.IssueIdx = i
+			canvasWidget.ApplyIssue(ed.Handle.Project.Issues[i])
+		}
+		if ed.IssueIdx < len(ed.Handle.Project.Issues) {
+			for i, pg := range ed.Handle.Project.Issues[ed.IssueIdx].Pages {
+				if pg.Number == projSettings.LastPage {
+					ed.PageIdx = i
+				}
+			}
+		}
+		// The undo history of the last session, persisted as it was recorded
+		if n, err := ed.RestoreHistory(context.Background()); err != nil {
+			l.Warn("restore undo history failed", slog.Any("err", err))
+		} else if n > 0 {
+			l.Info("undo history restored", slog.Int("snapshots", n))
+		}
+		refreshPagesList()
+		refreshPanelsUI()
+	}
+
+	// Search results panel: results grouped by page with lazily loaded page thumbnails
+	searchList := newSearchResultList()
+	// Navigation helper
+	navigateToResult := func(r storage.SearchResult) {
+		panel, ok := ed.Navigate(r)
+		if !ok {
+			return
+		}
+		refreshPanelsUI()
+		// sync pages list selection
+		refreshPagesList()
+		// highlight panel if specified
+		canvasWidget.HighlightPanelID(panel)
+	}
+	// Omnibox and search executor
+	searchThumbs := map[int]image.Image{}
+	searchThumbsFor := ""
+	omniBox := widget.NewEntry()
+	omniBox.SetPlaceHolder(i18n.T("placeholder.search_project_ctrl_k"))
+	runSearch := func(q string) {
+		qq := strings.TrimSpace(q)
+		if qq == "" || ed.Handle == nil {
+			searchList.SetResults(nil)
+			return
+		}
+		status.SetText(i18n.T("status.searching"))
+		go func(st controller.EditorState, text string) {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+			defer cancel()
+			res, err := st.RunSearch(ctx, text)
+			fyne.Do(func() {
+				if err != nil {
+					if msg, ok := searchSyntaxMessage(err); ok {
+						status.SetText(msg)
 						return
 					}
-					scriptEntry.SetText(text)
-					status.SetText("Restored script from " + label)
-				}, w)
-				d.Show()
+					l.Error("search failed", slog.Any("err", err))
+					status.SetText(i18n.T("status.search_failed"))
+					return
+				}
+				clear(searchThumbs) // pages may have changed since the last search
+				searchList.SetResults(res)
+				if len(res) > 0 {
+					// Enter in the omnibox hands over to the list for arrow-key browsing
+					searchList.HighlightFirst()
+					w.Canvas().Focus(searchList)
+				}
+				status.SetText(i18n.N("status.results", len(res)))
 			})
-			box.Add(btn)
+		}(*ed, qq)
+	}
+	omniBox.OnSubmitted = func(s string) { runSearch(s) }
+	searchList.OnOpen = navigateToResult
+	searchList.OnEscape = func() { w.Canvas().Focus(omniBox) }
+	// Page thumbnails come from the previews cache, rendered into it on first use. They are loaded in
+	// the background when a row of the page first becomes visible and kept until the next search.
+	searchList.Thumb = func(page int) image.Image {
+		if ed.Handle == nil || len(ed.Handle.Project.Issues) == 0 {
+			return nil
 		}
-		d := dialog.NewCustom("Script History", "Close", box, w)
-		d.Resize(fyne.NewSize(500, 400))
-		d.Show()
+		if searchThumbsFor != ed.Handle.Root {
+			clear(searchThumbs)
+			searchThumbsFor = ed.Handle.Root
+		}
+		if img, seen := searchThumbs[page]; seen {
+			return img
+		}
+		searchThumbs[page] = nil // loading
+		iss := ed.Handle.Project.Issues[0]
+		pi := slices.IndexFunc(iss.Pages, func(pg domain.Page) bool { return pg.Number == page })
+		if pi < 0 {
+			return nil
+		}
+		pg, border, root := iss.Pages[pi], ed.Handle.Project.PanelBorder, ed.Handle.Root
+		tw, th := searchThumbSize(iss.TrimWidth, iss.TrimHeight)
+		// Keyed by everything the thumbnail shows, so an edited page is rendered again
+		hash := storage.PageContentHash(pg, iss.TrimWidth, iss.TrimHeight, border)
+		go func(ix *storage.IndexHandle) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			data, err := ix.GetOrCreatePreview(ctx, page, sql.NullInt64{}, storage.PreviewKindThumb, tw, th, hash, func(context.Context) ([]byte, error) {
+				return renderPageThumbnail(pg, iss.TrimWidth, iss.TrimHeight, border, tw, th)
+			})
+			var img image.Image
+			if err == nil {
+				img, _, err = image.Decode(bytes.NewReader(data))
+			}
+			fyne.Do(func() {
+				if err != nil {
+					l.Debug("search thumbnail unavailable", slog.Int("page", page), slog.Any("err", err))
+					return
+				}
+				if ed.Handle == nil || ed.Handle.Root != root {
+					return
+				}
+				searchThumbs[page] = img
+				searchList.RefreshPage(page)
+			})
+		}(ed.Handle.Index())
+		return nil
+	}
+
+	resultsBox := container.NewVBox(widget.NewLabel(i18n.T("label.search_results")), searchList, widget.NewSeparator())
+	inspectorBox := container.NewVBox(
+		widget.NewLabel(i18n.T("label.inspector")), widget.NewSeparator(),
+		pacingLabel, container.NewHBox(orderBadge, btnFixOrder), container.NewHBox(overlaySelect, referenceCheck), widget.NewSeparator(),
+		panelHeaderLabel, container.NewBorder(nil, nil, nil, artFilterSelect, panelFilterEntry), panelList,
+		container.NewHBox(btnAddPanel, btnUp, btnDown, btnEdit, btnBorder, btnSplit, btnExportPanel, btnComments),
+		container.NewHBox(btnBulkDelete, btnBulkNotes, btnBulkOffset, btnDistH, btnDistV, btnMerge, btnBulkLock),
+		widget.NewSeparator(), balloonHeaderLabel, balloonList, balloonInfo, container.NewHBox(btnMoveBalloon, btnDeleteBalloon),
+	)
+	canvasCenter := container.NewMax(canvasWidget)
+	// Panel draw tool: a drawn panel is added to the page it was drawn on and selected in the inspector
+	canvasWidget.OnDrawStatus = status.SetText
+	canvasWidget.OnLocked = func(panelID string) {
+		status.SetText(i18n.T("status.panel_locked_move", panelID))
+	}
+	canvasWidget.OnDrawPanel = func(side int, geom domain.Rect) {
+		iss := ed.Issue()
+		pageNum := ed.PageNumber()
+		if iss == nil || pageNum == 0 {
+			return
+		}
+		if left, right, ok := storage.SpreadSides(*iss, pageNum); ok {
+			pageNum = left
+			if side == 1 {
+				pageNum = right
+			}
+		}
+		pn, err := ed.DrawPanel(pageNum, geom)
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		l.Info("panel drawn", slog.Int("page", pageNum), slog.String("panel", pn.ID))
+		if pageNum != ed.PageNumber() {
+			ed.SelectPage(pageNum)
+			refreshPagesList()
+		}
+		refreshPanelsUI()
+		selectEntity(controller.Selection{Page: pageNum, PanelID: pn.ID})
+		status.SetText(i18n.T("status.panel_added_size", units().formatSize(geom.Width, geom.Height)))
+	}
+	// A click on the canvas selects the balloon or panel under it in the inspector too
+	canvasWidget.OnSelect = func(side int, panelID, balloonID string) {
+		iss := ed.Issue()
+		pageNum := ed.PageNumber()
+		if iss == nil || pageNum == 0 {
+			return
+		}
+		if left, right, ok := storage.SpreadSides(*iss, pageNum); ok {
+			pageNum = left
+			if side == 1 {
+				pageNum = right
+			}
+		}
+		selectEntity(controller.Selection{Page: pageNum, PanelID: panelID, BalloonID: balloonID})
+	}
+	canvasWidget.OnEditBalloon = func(side int, panelID, balloonID string) {
+		iss := ed.Issue()
+		pageNum := ed.PageNumber()
+		if iss == nil || pageNum == 0 {
+			return
+		}
+		if left, right, ok := storage.SpreadSides(*iss, pageNum); ok {
+			pageNum = left
+			if side == 1 {
+				pageNum = right
+			}
+		}
+		b, err := storage.FindBalloon(ed.Handle, pageNum, panelID, balloonID)
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		showBalloonEditor(w, b, ed.Handle.Project.BalloonStyles, func(runs []domain.TextRun, style string) {
+			at := controller.Selection{Page: pageNum, PanelID: panelID, BalloonID: balloonID}
+			if err := ed.EditBalloon(at, runs, style); err != nil {
+				l.Error("balloon edit", slog.Any("err", err))
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			refreshPanelsUI()
+			status.SetText(i18n.T("status.balloon_updated", balloonID))
+		})
+	}
+	// Wire asset placement callback: append asset token into target panel notes and save
+	canvasWidget.OnPlaceAsset = func(path string, panelID string) {
+		if ed.Handle == nil {
+			return
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		rel := storage.AssetRelPath(ed.Handle.Root, path)
+		iss := ed.Handle.Project.Issues[ed.IssueIdx]
+		if ed.PageIdx < 0 || ed.PageIdx >= len(iss.Pages) {
+			return
+		}
+		pg := &ed.Handle.Project.Issues[ed.IssueIdx].Pages[ed.PageIdx]
+		for i := range pg.Panels {
+			if pg.Panels[i].ID == panelID {
+				note := strings.TrimSpace(pg.Panels[i].Notes)
+				entry := "asset:" + rel
+				if note == "" {
+					pg.Panels[i].Notes = entry
+				} else if !strings.Contains(note, entry) {
+					pg.Panels[i].Notes = note + "\n" + entry
+				}
+				break
+			}
+		}
+		if err := storage.Save(ed.Handle); err != nil {
+			l.Error("save after place asset", slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		refreshPanelsUI()
+		status.SetText(i18n.T("status.placed_asset_into_panel", panelID))
+	}
+	// Review mode controls and quick comment entry (minimal Phase 7)
+	reviewMode := prefs.BoolWithFallback("review.mode", false)
+	reviewCheck := widget.NewCheck(i18n.T("check.review_mode"), func(b bool) {
+		reviewMode = b
+		prefs.SetBool("review.mode", b)
+	})
+	reviewCheck.SetChecked(reviewMode)
+
+	// Script change tracking toggle and history
+	trackChanges := prefs.BoolWithFallback("script.track", false)
+	trackCheck := widget.NewCheck(i18n.T("check.track_changes"), func(b bool) {
+		trackChanges = b
+		prefs.SetBool("script.track", b)
+	})
+	trackCheck.SetChecked(trackChanges)
+
+	scriptHistBtn := widget.NewButton(i18n.T("button.script_history"), func() {
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.no_project"), i18n.T("msg.open_a_project_first"), w)
+			return
+		}
+		showScriptHistoryDialog(w, ed.Handle.Root, scriptEntry.Text, func(text, label string) {
+			scriptEntry.SetText(text)
+			status.SetText(i18n.T("status.restored_script_from", label))
+		})
 	})
 
-	addPageCommentBtn := widget.NewButton("Add Page Comment", func() {
-		if ph == nil || len(ph.Project.Issues) == 0 {
-			dialog.ShowInformation("No project", "Open a project first.", w)
+	addPageCommentBtn := widget.NewButton(i18n.T("button.add_page_comment"), func() {
+		if ed.Handle == nil || len(ed.Handle.Project.Issues) == 0 {
+			dialog.ShowInformation(i18n.T("msg.no_project"), i18n.T("msg.open_a_project_first"), w)
 			return
 		}
-		iss := ph.Project.Issues[currentIssueIdx]
-		if len(iss.Pages) == 0 || currentPageIdx < 0 || currentPageIdx >= len(iss.Pages) {
-			dialog.ShowInformation("No page", "Select a page first.", w)
+		iss := ed.Handle.Project.Issues[ed.IssueIdx]
+		if len(iss.Pages) == 0 || ed.PageIdx < 0 || ed.PageIdx >= len(iss.Pages) {
+			dialog.ShowInformation(i18n.T("msg.no_page"), i18n.T("msg.select_a_page_first"), w)
 			return
 		}
 		entry := widget.NewMultiLineEntry()
-		entry.SetPlaceHolder("Enter a comment for this page…")
-		d := dialog.NewCustomConfirm("New Page Comment", "Add", "Cancel", entry, func(ok bool) {
+		entry.SetPlaceHolder(i18n.T("placeholder.enter_a_comment_for_this_page"))
+		d := dialog.NewCustomConfirm(i18n.T("msg.new_page_comment"), i18n.T("msg.add"), i18n.T("msg.cancel"), entry, func(ok bool) {
 			if !ok {
 				return
 			}
@@ -694,35 +1347,35 @@ func Run(projectDir string) error {
 			if body == "" {
 				return
 			}
-			iss := ph.Project.Issues[currentIssueIdx]
-			pgNum := iss.Pages[currentPageIdx].Number
+			iss := ed.Handle.Project.Issues[ed.IssueIdx]
+			pgNum := iss.Pages[ed.PageIdx].Number
 			c := domain.Comment{
 				ID:        fmt.Sprintf("cmt-%d", time.Now().UnixNano()),
 				Body:      body,
-				Target:    domain.CommentTarget{Kind: "page", IssueIndex: currentIssueIdx, PageNumber: pgNum},
+				Target:    domain.CommentTarget{Kind: "page", IssueIndex: ed.IssueIdx, PageNumber: pgNum},
 				Status:    domain.CommentOpen,
 				CreatedAt: time.Now(),
 			}
-			ph.Project.Comments = append(ph.Project.Comments, c)
-			if err := storage.Save(ph); err != nil {
+			ed.Handle.Project.Comments = append(ed.Handle.Project.Comments, c)
+			if err := storage.Save(ed.Handle); err != nil {
 				l.Error("save after add comment", slog.Any("err", err))
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
-			status.SetText("Added comment to page")
+			status.SetText(i18n.T("status.added_comment_to_page"))
 		}, w)
 		d.Resize(fyne.NewSize(500, 300))
 		d.Show()
 	})
 
-	addScriptCommentBtn := widget.NewButton("Add Script Comment", func() {
-		if ph == nil {
-			dialog.ShowInformation("No project", "Open a project first.", w)
+	addScriptCommentBtn := widget.NewButton(i18n.T("button.add_script_comment"), func() {
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.no_project"), i18n.T("msg.open_a_project_first"), w)
 			return
 		}
 		entry := widget.NewMultiLineEntry()
-		entry.SetPlaceHolder("Enter a comment for the script…")
-		d := dialog.NewCustomConfirm("New Script Comment", "Add", "Cancel", entry, func(ok bool) {
+		entry.SetPlaceHolder(i18n.T("placeholder.enter_a_comment_for_the_script"))
+		d := dialog.NewCustomConfirm(i18n.T("msg.new_script_comment"), i18n.T("msg.add"), i18n.T("msg.cancel"), entry, func(ok bool) {
 			if !ok {
 				return
 			}
@@ -737,20 +1390,20 @@ func Run(projectDir string) error {
 				Status:    domain.CommentOpen,
 				CreatedAt: time.Now(),
 			}
-			ph.Project.Comments = append(ph.Project.Comments, c)
-			if err := storage.Save(ph); err != nil {
+			ed.Handle.Project.Comments = append(ed.Handle.Project.Comments, c)
+			if err := storage.Save(ed.Handle); err != nil {
 				l.Error("save after add script comment", slog.Any("err", err))
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
-			status.SetText("Added comment to script")
+			status.SetText(i18n.T("status.added_comment_to_script"))
 		}, w)
 		d.Resize(fyne.NewSize(500, 300))
 		d.Show()
 	})
 
 	refreshReviewButtons := func() {
-		if reviewMode && ph != nil {
+		if reviewMode && ed.Handle != nil {
 			addPageCommentBtn.Enable()
 			addScriptCommentBtn.Enable()
 		} else {
@@ -759,31 +1412,87 @@ func Run(projectDir string) error {
 		}
 	}
 	refreshReviewButtons()
+	// refreshPresetMenu rebuilds the Export with Preset submenu; assigned once the menus exist.
+	refreshPresetMenu := func() {}
+	// refreshRecent rebuilds File → Open Recent and the dashboard after the recent projects changed or a
+	// project was opened or closed; assigned with the menus.
+	refreshRecent := func() {}
+	// commands holds the menu actions offered by the quick-open palette; each menu registers its items.
+	commands := &commandRegistry{}
+	// offerPageNumberRepair asks to renumber issues with duplicate or missing page numbers; assigned with
+	// the Issue menu. fromMenu also reports when there is nothing to repair.
+	offerPageNumberRepair := func(fromMenu bool) {}
 	reviewCheck.OnChanged = func(b bool) {
 		reviewMode = b
 		prefs.SetBool("review.mode", b)
 		refreshReviewButtons()
 	}
 
-	topBar := container.NewBorder(nil, nil, nil, nil, container.NewHBox(omniBox, reviewCheck, trackCheck, addPageCommentBtn, addScriptCommentBtn, scriptHistBtn))
+	// Validation banner: shown while the open project has manifest problems found by storage.ValidateProject
+	var validationIssues []storage.ValidationIssue
+	validationLabel := widget.NewLabel("")
+	var validationBanner *fyne.Container
+	validationDetailsBtn := widget.NewButton(i18n.T("button.details"), func() {
+		issues := validationIssues
+		lst := widget.NewList(
+			func() int { return len(issues) },
+			func() fyne.CanvasObject {
+				return container.NewBorder(nil, nil, widget.NewIcon(theme.WarningIcon()), nil, widget.NewLabel(""))
+			},
+			func(i widget.ListItemID, o fyne.CanvasObject) {
+				c := o.(*fyne.Container)
+				icon := theme.WarningIcon()
+				if issues[i].Severity == storage.SeverityError {
+					icon = theme.ErrorIcon()
+				}
+				c.Objects[1].(*widget.Icon).SetResource(icon)
+				c.Objects[0].(*widget.Label).SetText(issues[i].Path + " — " + issues[i].Message)
+			},
+		)
+		dialog.NewCustom(validationLabel.Text, i18n.T("msg.close"), container.NewGridWrap(fyne.NewSize(720, 360), lst), w).Show()
+	})
+	validationDismissBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() { validationBanner.Hide() })
+	validationBanner = container.NewHBox(widget.NewIcon(theme.WarningIcon()), validationLabel, validationDetailsBtn, validationDismissBtn)
+	validationBanner.Hide()
+	// showValidation updates the banner for the current project; called whenever a project is opened,
+	// created or closed.
+	showValidation := func() {
+		validationIssues = nil
+		if ed.Handle != nil {
+			validationIssues = ed.Handle.Validation
+		}
+		if len(validationIssues) == 0 {
+			validationBanner.Hide()
+			return
+		}
+		validationLabel.SetText(validationSummary(validationIssues))
+		validationBanner.Show()
+	}
+
+	drawPanelsCheck := widget.NewCheck(i18n.T("check.draw_panels"), func(v bool) {
+		canvasWidget.DrawPanels = v
+		if v {
+			status.SetText(i18n.T("status.drag_on_empty_page_area_to"))
+		}
+	})
+	topBar := container.NewVBox(validationBanner, container.NewBorder(nil, nil, nil, nil, container.NewHBox(omniBox, drawPanelsCheck, reviewCheck, trackCheck, addPageCommentBtn, addScriptCommentBtn, scriptHistBtn)))
 
 	// Assets pane (minimal): shows image files under project/assets and allows arming for placement
 	assetFilterEntry := widget.NewEntry()
-	assetFilterEntry.SetPlaceHolder("Filter assets")
+	assetFilterEntry.SetPlaceHolder(i18n.T("placeholder.filter_assets"))
 	assetsGrid := container.NewGridWrap(fyne.NewSize(96, 96))
 	assetsScroll := container.NewVScroll(assetsGrid)
 	assetsScroll.SetMinSize(fyne.NewSize(0, 150))
-	assetsHeader := container.NewHBox(widget.NewLabel("Assets"), widget.NewSeparator(), assetFilterEntry)
-	assetsPane := container.NewBorder(assetsHeader, nil, nil, nil, assetsScroll)
+	assetsHeader := container.NewHBox(widget.NewLabel(i18n.T("label.assets")), widget.NewSeparator(), assetFilterEntry)
 	// Refresh function to scan and build tiles
 	refreshAssets := func() {
 		tiles := []fyne.CanvasObject{}
-		if ph == nil {
+		if ed.Handle == nil {
 			assetsGrid.Objects = tiles
 			assetsGrid.Refresh()
 			return
 		}
-		root := ph.Root
+		root := ed.Handle.Root
 		dir := filepath.Join(root, "assets")
 		filter := strings.ToLower(strings.TrimSpace(assetFilterEntry.Text))
 		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
@@ -809,12 +1518,12 @@ func Run(projectDir string) error {
 					res := fyne.NewStaticResource(filepath.Base(p), data)
 					btn = widget.NewButtonWithIcon("", res, func() {
 						canvasWidget.armedAssetPath = p
-						status.SetText("Armed asset: " + filepath.Base(p) + " — click a panel to place")
+						status.SetText(i18n.T("status.armed_asset_click_a_panel_to", filepath.Base(p)))
 					})
 				} else {
 					btn = widget.NewButton(filepath.Base(p), func() {
 						canvasWidget.armedAssetPath = p
-						status.SetText("Armed asset: " + filepath.Base(p) + " — click a panel to place")
+						status.SetText(i18n.T("status.armed_asset_click_a_panel_to", filepath.Base(p)))
 					})
 				}
 				return container.NewVBox(btn, widget.NewLabel(name))
@@ -826,17 +1535,126 @@ func Run(projectDir string) error {
 		assetsGrid.Refresh()
 	}
 	assetFilterEntry.OnChanged = func(string) { refreshAssets() }
+	// Watch the open project's assets folder: refresh the pane and re-catalogue assets in the index.
+	var assetsWatch *assetsWatcher
+	restartAssetsWatcher := func() {
+		assetsWatch.Close()
+		assetsWatch = nil
+		if ed.Handle == nil {
+			return
+		}
+		assetsWatch = startAssetsWatcher(ed.Handle.Root, l, func() {
+			fyne.Do(func() {
+				if ed.Handle == nil {
+					return
+				}
+				refreshAssets()
+				go func(ix *storage.IndexHandle, proj domain.Project) {
+					ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+					defer cancel()
+					if err := ix.UpdateIndex(ctx, proj); err != nil {
+						l.Warn("index update after assets change failed", slog.Any("err", err))
+					}
+				}(ed.Handle.Index(), ed.Handle.Project)
+			})
+		})
+	}
+
+	// Index consistency: a while after a project is opened, the index is compared with the manifest in
+	// the background and rows that drifted from it are repaired
+	var indexChk *indexCheck
+	restartIndexCheck := func() {
+		indexChk.Close()
+		indexChk = nil
+		if ed.Handle == nil {
+			return
+		}
+		h := ed.Handle
+		indexChk = startIndexCheck(h.Index(), indexCheckDelay, l, func() (proj domain.Project, ok bool) {
+			fyne.DoAndWait(func() {
+				if ok = ed.Handle == h; ok {
+					proj = h.Project
+				}
+			})
+			return proj, ok
+		}, func(d storage.IndexDrift) {
+			if d.Count() == 0 {
+				// Only the fingerprints of an older index were missing; nothing the author would notice
+				return
+			}
+			fyne.Do(func() {
+				if ed.Handle != h {
+					return
+				}
+				msg := i18n.N("status.index_drift_repaired", d.Count())
+				status.SetText(msg)
+				time.AfterFunc(indexDriftStatusFor, func() {
+					fyne.Do(func() {
+						if status.Text == msg {
+							status.SetText("")
+						}
+					})
+				})
+			})
+		})
+	}
+
+	// Pacing side panel: beats per page of the current issue, recomputed on page, mapping and script changes
+	pacing := newPacingPanel(w)
+	if !prefs.BoolWithFallback("view.pacing", false) {
+		pacing.box.Hide()
+	}
+	pacing.chart.OnPage = func(n int) {
+		if ed.Handle == nil || ed.IssueIdx >= len(ed.Handle.Project.Issues) {
+			return
+		}
+		if i := slices.IndexFunc(ed.Handle.Project.Issues[ed.IssueIdx].Pages, func(pg domain.Page) bool { return pg.Number == n }); i >= 0 {
+			ed.PageIdx = i
+			refreshPagesList()
+			refreshPanelsUI()
+		}
+	}
+	pacing.chart.OnHover = func(s string) {
+		if s != "" {
+			status.SetText(s)
+		}
+	}
+	showPacing = func(rep storage.PacingReport) {
+		if !pacing.box.Visible() {
+			return
+		}
+		pacing.show(rep, ed.IssueIdx, ed.PageNumber())
+	}
+	refreshPacing = func() {
+		if pacing.box.Visible() {
+			showPacing(pacingReport())
+		}
+	}
 
-	canvasPane := container.NewBorder(topBar, assetsPane, left, right, canvasCenter)
+	// Workspace: draggable splits between the Pages column, the canvas, the right column and the Assets
+	// pane; the View menu collapses the sections, and the layout is restored on the next launch.
+	workspace := newWorkspaceView(loadWorkspaceLayout(prefs), left, canvasCenter, assetsHeader, assetsScroll, pacing.box, resultsBox, inspectorBox)
+	refreshWorkspaceMenu := func() {} // assigned with the View menu
+	workspace.OnChanged = func() {
+		workspace.Store(prefs)
+		refreshWorkspaceMenu()
+	}
+	canvasPane := container.NewBorder(topBar, nil, nil, nil, workspace.box)
 
 	// Shortcut: focus omnibox with Ctrl+K
 	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyK, Modifier: fyne.KeyModifierControl}, func(sc fyne.Shortcut) {
 		w.Canvas().Focus(omniBox)
 	})
+	// Shortcut: quick open with Ctrl+P; assigned once the menus exist.
+	openQuickOpen := func() {}
+	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyP, Modifier: fyne.KeyModifierControl}, func(sc fyne.Shortcut) {
+		openQuickOpen()
+	})
 
 	// Script editor UI
-	scriptEntry = widget.NewMultiLineEntry()
-	scriptEntry.SetPlaceHolder("Type your script here. Use scene headers like \"# Scene Title\" and character lines like \"ALICE: Hello\". Indent continuation lines with two spaces.")
+	scriptEntry = newScriptEditor()
+	scriptEntry.SetPlaceHolder(i18n.T("placeholder.type_your_script_here_use_scene"))
+	scriptFind := newScriptFindBar(scriptEntry)
 	// Change tracking state (debounced snapshots)
 	var lastScriptSnapTS time.Time
 	var lastScriptSnapText string
@@ -845,6 +1663,7 @@ func Run(projectDir string) error {
 		kind      string   // scene, dialogue, caption, beat
 		display   string   // final display string
 		character string   // for dialogue
+		location  string   // bible location of the item's scene, upper-case
 		tags      []string // extracted @tags from parser
 	}
 	outlineItems := []outlineItem{}
@@ -892,6 +1711,12 @@ func Run(projectDir string) error {
 						match = false
 						break
 					}
+				} else if strings.HasPrefix(tok, "loc:") {
+					name := strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(tok, "loc:")))
+					if it.location != name {
+						match = false
+						break
+					}
 				} else if strings.HasPrefix(tok, "is:") || strings.HasPrefix(tok, "type:") {
 					idx := strings.Index(tok, ":")
 					typeVal := tok[idx+1:]
@@ -914,51 +1739,166 @@ func Run(projectDir string) error {
 	}
 	// Search/filter entry for outline
 	outlineSearch := widget.NewEntry()
-	outlineSearch.SetPlaceHolder("Filter outline (text, @tag, char:NAME, is:beat|dialogue|caption|scene)")
+	outlineSearch.SetPlaceHolder(i18n.T("placeholder.filter_outline_text_tag_char_name"))
 	outlineSearch.OnChanged = func(q string) {
 		outlineFilter = strings.ToLower(strings.TrimSpace(q))
 		applyOutlineFilter()
 	}
 
-	scriptErr := widget.NewLabel("")
-	scriptErr.Wrapping = fyne.TextWrapWord
+	// Parse errors: clicking one moves the caret to its line
+	var scriptErrs []script.Error
+	scriptErrList := widget.NewList(
+		func() int { return len(scriptErrs) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText("✖ " + scriptErrorLabel(scriptErrs[i]))
+		},
+	)
+	scriptErrList.OnSelected = func(id widget.ListItemID) {
+		if id < 0 || int(id) >= len(scriptErrs) {
+			return
+		}
+		e := scriptErrs[id]
+		scriptErrList.UnselectAll()
+		scriptEntry.GoToLine(e.Line, e.Column)
+	}
+	scriptErrScroll := container.NewVScroll(scriptErrList)
+	scriptErrScroll.SetMinSize(fyne.NewSize(0, 80))
+	scriptErrScroll.Hide()
+
+	// Dialogue validation against the bible: warnings list with jump-to-line and quick fixes
+	var scriptWarnings []script.Warning
+	scriptWarnList := widget.NewList(
+		func() int { return len(scriptWarnings) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText("⚠ " + scriptWarnings[i].Message)
+		},
+	)
+	scriptWarnScroll := container.NewVScroll(scriptWarnList)
+	scriptWarnScroll.SetMinSize(fyne.NewSize(0, 80))
+	scriptWarnScroll.Hide()
+	validateScript := func(sc script.Script) {
+		scriptWarnings = nil
+		if ed.Handle != nil {
+			scriptWarnings = script.ValidateAgainstBible(sc, ed.Handle.Project.Bible)
+		}
+		scriptWarnList.UnselectAll()
+		scriptWarnList.Refresh()
+		if len(scriptWarnings) > 0 {
+			scriptWarnScroll.Show()
+		} else {
+			scriptWarnScroll.Hide()
+		}
+	}
 
 	// Bible data and UI state
 	charNames := []string{}
 	locNames := []string{}
 	tagNames := []string{}
+	// List rows show bibleLabel text; *Idx map a row to its entry in ed.Handle.Project.Bible.
+	var charLabels, locLabels, tagLabels []string
+	var charIdx, locIdx, tagIdx []int
 	var charList *widget.List
 	var locList *widget.List
 	var tagList *widget.List
 	selectedChar := -1
 	selectedLoc := -1
 	selectedTag := -1
+	// tabs is created further below; Where Used navigation switches tabs.
+	var tabs *container.AppTabs
 
-	refreshBible := func() {
-		if ph == nil {
-			charNames = charNames[:0]
-			locNames = locNames[:0]
-			tagNames = tagNames[:0]
-		} else {
-			charNames = charNames[:0]
-			for _, c := range ph.Project.Bible.Characters {
-				n := strings.TrimSpace(c.Name)
-				if n != "" {
-					charNames = append(charNames, n)
-				}
-			}
-			locNames = locNames[:0]
-			for _, c := range ph.Project.Bible.Locations {
+	// showWhereUsed lists index documents referencing a bible entry and navigates on selection.
+	showWhereUsed := func(title, path string) {
+		if ed.Handle == nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		res, err := ed.Handle.Index().WhereUsedByPath(ctx, path, 500, 0)
+		if err != nil {
+			l.Error("where used failed", slog.String("path", path), slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		if len(res) == 0 {
+			dialog.ShowInformation(i18n.T("msg.where_used"), i18n.T("msg.is_not_referenced_yet", title), w)
+			return
+		}
+		items := make([]string, len(res))
+		for i, r := range res {
+			if r.PageID > 0 {
+				items[i] = fmt.Sprintf("p.%d — %s — %s", r.PageID, r.Type, r.Path)
+			} else {
+				items[i] = fmt.Sprintf("%s — %s", r.Type, r.Path)
+			}
+		}
+		var dlg dialog.Dialog
+		lst := widget.NewList(
+			func() int { return len(items) },
+			func() fyne.CanvasObject { return widget.NewLabel("") },
+			func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(items[i]) },
+		)
+		lst.OnSelected = func(id widget.ListItemID) {
+			if id < 0 || int(id) >= len(res) {
+				return
+			}
+			r := res[id]
+			if r.Type == "script" {
+				tabs.SelectIndex(2)
+			} else if line, ok := strings.CutPrefix(r.Path, "script:scene:"); ok {
+				tabs.SelectIndex(2)
+				if n, err := strconv.Atoi(line); err == nil {
+					scriptEntry.GoToLine(n, 1)
+				}
+			} else {
+				tabs.SelectIndex(0)
+				navigateToResult(r)
+			}
+			dlg.Hide()
+		}
+		dlg = dialog.NewCustom(i18n.T("msg.where_used_title", title, len(res)), i18n.T("msg.close"), container.NewGridWrap(fyne.NewSize(520, 320), lst), w)
+		dlg.Show()
+	}
+
+	// The relationships tab is derived from the script and the beat mappings; it is recomputed with
+	// the bible lists and whenever the bible tab is shown.
+	relations := newRelationshipsView()
+	refreshRelations := func(sc script.Script) {
+		if ed.Handle == nil {
+			relations.SetReport(storage.AppearanceReport{}, 0)
+			return
+		}
+		relations.SetReport(storage.ComputeAppearances(ed.Handle.Project, sc), len(ed.Handle.Project.Issues))
+	}
+
+	refreshBible := func() {
+		charNames, charLabels, charIdx = charNames[:0], charLabels[:0], charIdx[:0]
+		locNames, locLabels, locIdx = locNames[:0], locLabels[:0], locIdx[:0]
+		tagNames, tagLabels, tagIdx = tagNames[:0], tagLabels[:0], tagIdx[:0]
+		if ed.Handle != nil {
+			for i, c := range ed.Handle.Project.Bible.Characters {
+				n := strings.TrimSpace(c.Name)
+				if n != "" {
+					charNames = append(charNames, n)
+					charLabels = append(charLabels, bibleLabel(n, len(c.Aliases), c.Notes))
+					charIdx = append(charIdx, i)
+				}
+			}
+			for i, c := range ed.Handle.Project.Bible.Locations {
 				n := strings.TrimSpace(c.Name)
 				if n != "" {
 					locNames = append(locNames, n)
+					locLabels = append(locLabels, bibleLabel(n, len(c.Aliases), c.Notes))
+					locIdx = append(locIdx, i)
 				}
 			}
-			tagNames = tagNames[:0]
-			for _, t := range ph.Project.Bible.Tags {
+			for i, t := range ed.Handle.Project.Bible.Tags {
 				n := strings.TrimSpace(t.Name)
 				if n != "" {
 					tagNames = append(tagNames, n)
+					tagLabels = append(tagLabels, bibleLabel(n, 0, t.Notes))
+					tagIdx = append(tagIdx, i)
 				}
 			}
 		}
@@ -971,6 +1911,10 @@ func Run(projectDir string) error {
 		if tagList != nil {
 			tagList.Refresh()
 		}
+		// bible edits can resolve or introduce unknown speakers
+		sc, _ := script.Parse(scriptEntry.Text())
+		validateScript(sc)
+		refreshRelations(sc)
 	}
 
 	var updateOutline func(string)
@@ -980,7 +1924,7 @@ func Run(projectDir string) error {
 		if strings.TrimSpace(name) == "" {
 			return
 		}
-		txt := scriptEntry.Text
+		txt := scriptEntry.Text()
 		if len(txt) > 0 && !strings.HasSuffix(txt, "\n") {
 			txt += "\n"
 		}
@@ -992,7 +1936,7 @@ func Run(projectDir string) error {
 		if strings.TrimSpace(tag) == "" {
 			return
 		}
-		txt := scriptEntry.Text
+		txt := scriptEntry.Text()
 		if len(txt) > 0 && !strings.HasSuffix(txt, " ") && !strings.HasSuffix(txt, "\n") {
 			txt += " "
 		}
@@ -1005,35 +1949,32 @@ func Run(projectDir string) error {
 		sc, errs := script.Parse(txt)
 		// build outline items and compute unmapped beat warnings
 		mapped := map[string]struct{}{}
-		if ph != nil {
-			mapped = storage.MappedBeatSet(ph.Project)
+		if ed.Handle != nil {
+			mapped = storage.MappedBeatSet(ed.Handle.Project)
+			sc = script.LinkLocations(sc, ed.Handle.Project.Bible)
 		}
 		totalBeats := 0
 		unmappedBeats := 0
 		outlineItems = outlineItems[:0]
 		for _, scn := range sc.Scenes {
 			st := strings.TrimSpace(scn.Title)
-			outlineItems = append(outlineItems, outlineItem{kind: "scene", display: "Scene: " + st})
+			loc := strings.ToUpper(scn.Location)
+			if scn.Location != "" {
+				outlineItems = append(outlineItems, outlineItem{kind: "scene", display: i18n.T("label.outline_scene_location", st, scn.Location), location: loc})
+			} else {
+				outlineItems = append(outlineItems, outlineItem{kind: "scene", display: i18n.T("label.outline_scene", st)})
+			}
 			for _, ln := range scn.Lines {
 				switch ln.Type {
 				case script.LineDialogue:
-					preview := ln.Text
-					if len(preview) > 60 {
-						preview = preview[:60] + "…"
-					}
-					outlineItems = append(outlineItems, outlineItem{kind: "dialogue", display: "  " + ln.Character + ": " + preview, character: ln.Character, tags: ln.Tags})
+					preview := textutil.TruncateRunes(ln.Text, 60, "…")
+					outlineItems = append(outlineItems, outlineItem{kind: "dialogue", display: "  " + ln.Character + ": " + preview, character: ln.Character, location: loc, tags: ln.Tags})
 				case script.LineCaption:
-					preview := ln.Text
-					if len(preview) > 60 {
-						preview = preview[:60] + "…"
-					}
-					outlineItems = append(outlineItems, outlineItem{kind: "caption", display: "  [CAPTION] " + preview, tags: ln.Tags})
+					preview := textutil.TruncateRunes(ln.Text, 60, "…")
+					outlineItems = append(outlineItems, outlineItem{kind: "caption", display: "  [CAPTION] " + preview, location: loc, tags: ln.Tags})
 				case script.LineBeat:
 					totalBeats++
-					preview := ln.Text
-					if len(preview) > 60 {
-						preview = preview[:60] + "…"
-					}
+					preview := textutil.TruncateRunes(ln.Text, 60, "…")
 					id := storage.BeatIDFor(ln)
 					display := "  [" + ln.Character + "] " + preview
 					if _, ok := mapped[id]; !ok {
@@ -1041,7 +1982,7 @@ func Run(projectDir string) error {
 						unmappedBeats++
 						display += "  ⚠ unmapped"
 					}
-					outlineItems = append(outlineItems, outlineItem{kind: "beat", display: display, tags: ln.Tags})
+					outlineItems = append(outlineItems, outlineItem{kind: "beat", display: display, location: loc, tags: ln.Tags})
 				default:
 					// skip notes/unknown in outline for now
 				}
@@ -1049,67 +1990,78 @@ func Run(projectDir string) error {
 		}
 		// apply filter to build visible data
 		applyOutlineFilter()
+		validateScript(sc)
+		scriptErrs = errs
+		scriptErrList.UnselectAll()
+		scriptErrList.Refresh()
+		scriptEntry.SetMarks(errs)
 		if len(errs) > 0 {
-			scriptErr.SetText(errs[0].Message)
+			scriptErrScroll.Show()
 		} else {
-			scriptErr.SetText("")
+			scriptErrScroll.Hide()
 		}
 		// Update status with beat coverage information
 		if totalBeats > 0 {
-			status.SetText(fmt.Sprintf("Script: %d beats (%d unmapped)", totalBeats, unmappedBeats))
+			status.SetText(i18n.T("status.script_beats_unmapped", totalBeats, unmappedBeats))
 		} else {
-			status.SetText("Script: no beats detected")
+			status.SetText(i18n.T("status.script_no_beats_detected"))
 		}
-		// Keep storyboard in sync when outline updates
+		// Keep storyboard and pacing in sync when outline updates
 		if refreshStoryboard != nil {
 			refreshStoryboard()
 		}
+		if refreshPacing != nil {
+			refreshPacing()
+		}
 	}
+	// Re-parsing the whole script is deferred until typing pauses; highlighting follows every keystroke.
+	var outlineTimer *time.Timer
 	scriptEntry.OnChanged = func(s string) {
-		updateOutline(s)
-		if trackChanges && ph != nil {
+		if outlineTimer != nil {
+			outlineTimer.Stop()
+		}
+		outlineTimer = time.AfterFunc(200*time.Millisecond, func() {
+			fyne.Do(func() { updateOutline(scriptEntry.Text()) })
+		})
+		if trackChanges && ed.Handle != nil {
 			// Debounce: only snapshot if at least 2s passed and content changed
 			if time.Since(lastScriptSnapTS) > 2*time.Second && s != lastScriptSnapText {
-				localText := s
-				go func() {
+				go func(ix *storage.IndexHandle, text string) {
 					ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 					defer cancel()
-					_ = storage.SaveScriptSnapshot(ctx, ph, localText, time.Now())
-				}()
+					_, _ = ix.RecordScriptSnapshot(ctx, text, time.Now())
+				}(ed.Handle.Index(), s)
 				lastScriptSnapTS = time.Now()
 				lastScriptSnapText = s
 			}
 		}
-		if refreshStoryboard != nil {
-			refreshStoryboard()
-		}
 	}
 
 	// Script insertion controls leveraging the bible
-	insertCharBtn := widget.NewButton("Insert Character", func() {
-		if ph == nil || len(ph.Project.Bible.Characters) == 0 {
-			dialog.ShowInformation("Insert Character", "No project open or no characters in bible.", w)
+	insertCharBtn := widget.NewButton(i18n.T("button.insert_character"), func() {
+		if ed.Handle == nil || len(ed.Handle.Project.Bible.Characters) == 0 {
+			dialog.ShowInformation(i18n.T("button.insert_character"), i18n.T("msg.no_project_open_or_no_characters"), w)
 			return
 		}
 		// ensure names are current
 		refreshBible()
 		sel := widget.NewSelect(charNames, nil)
-		sel.PlaceHolder = "Choose character"
-		dialog.NewCustomConfirm("Insert Character", "Insert", "Cancel", sel, func(ok bool) {
+		sel.PlaceHolder = i18n.T("placeholder.choose_character")
+		dialog.NewCustomConfirm(i18n.T("button.insert_character"), i18n.T("msg.insert"), i18n.T("msg.cancel"), sel, func(ok bool) {
 			if ok && sel.Selected != "" {
 				insertCharacterLine(sel.Selected)
 			}
 		}, w).Show()
 	})
-	insertTagBtn := widget.NewButton("Insert @Tag", func() {
-		if ph == nil || len(ph.Project.Bible.Tags) == 0 {
-			dialog.ShowInformation("Insert Tag", "No project open or no tags in bible.", w)
+	insertTagBtn := widget.NewButton(i18n.T("button.insert_tag"), func() {
+		if ed.Handle == nil || len(ed.Handle.Project.Bible.Tags) == 0 {
+			dialog.ShowInformation(i18n.T("msg.insert_tag"), i18n.T("msg.no_project_open_or_no_tags"), w)
 			return
 		}
 		refreshBible()
 		sel := widget.NewSelect(tagNames, nil)
-		sel.PlaceHolder = "Choose tag"
-		dialog.NewCustomConfirm("Insert Tag", "Insert", "Cancel", sel, func(ok bool) {
+		sel.PlaceHolder = i18n.T("placeholder.choose_tag")
+		dialog.NewCustomConfirm(i18n.T("msg.insert_tag"), i18n.T("msg.insert"), i18n.T("msg.cancel"), sel, func(ok bool) {
 			if ok && sel.Selected != "" {
 				insertTag(sel.Selected)
 			}
@@ -1117,11 +2069,86 @@ func Run(projectDir string) error {
 	})
 	scriptControls := container.NewHBox(insertCharBtn, insertTagBtn)
 
+	// Selecting a warning jumps to its line and offers quick fixes
+	scriptWarnList.OnSelected = func(id widget.ListItemID) {
+		if id < 0 || int(id) >= len(scriptWarnings) {
+			return
+		}
+		wrn := scriptWarnings[id]
+		scriptWarnList.UnselectAll()
+		scriptEntry.GoToLine(wrn.Line, 1)
+		var dlg dialog.Dialog
+		buttons := container.NewHBox()
+		if wrn.Location != "" {
+			if wrn.Suggestion != "" {
+				buttons.Add(widget.NewButton(i18n.T("button.replace_with", wrn.Suggestion), func() {
+					dlg.Hide()
+					txt, ok := script.ReplaceLocationRef(scriptEntry.Text(), wrn.Line, wrn.Location, wrn.Suggestion)
+					if !ok {
+						status.SetText(i18n.T("status.line_changed_re_check_warnings"))
+						return
+					}
+					scriptEntry.SetText(txt)
+					status.SetText(i18n.T("status.replaced_with_on_line", wrn.Location, wrn.Suggestion, wrn.Line))
+				}))
+			}
+			buttons.Add(widget.NewButton(i18n.T("button.add_location_to_bible"), func() {
+				dlg.Hide()
+				if ed.Handle == nil {
+					return
+				}
+				l.Info("add location", slog.String("name", wrn.Location))
+				ed.Handle.Project.Bible.Locations = append(ed.Handle.Project.Bible.Locations, domain.BibleLocation{Name: wrn.Location})
+				if err := storage.Save(ed.Handle); err != nil {
+					l.Error("save after add location", slog.Any("err", err))
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				refreshBible()
+				updateOutline(scriptEntry.Text())
+				status.SetText(i18n.T("status.location_added"))
+			}))
+			msg := widget.NewLabel(i18n.T("label.is_not_a_location_or_alias", wrn.Location))
+			dlg = dialog.NewCustom(i18n.T("msg.unknown_location_line", wrn.Line), i18n.T("msg.cancel"), container.NewVBox(msg, buttons), w)
+			dlg.Show()
+			return
+		}
+		if wrn.Suggestion != "" {
+			buttons.Add(widget.NewButton(i18n.T("button.replace_with", wrn.Suggestion), func() {
+				dlg.Hide()
+				txt, ok := script.ReplaceCharacter(scriptEntry.Text(), wrn.Line, wrn.Character, wrn.Suggestion)
+				if !ok {
+					status.SetText(i18n.T("status.line_changed_re_check_warnings"))
+					return
+				}
+				scriptEntry.SetText(txt)
+				status.SetText(i18n.T("status.replaced_with_on_line", wrn.Character, wrn.Suggestion, wrn.Line))
+			}))
+		}
+		buttons.Add(widget.NewButton(i18n.T("button.ignore_in_project"), func() {
+			dlg.Hide()
+			if ed.Handle == nil {
+				return
+			}
+			ed.Handle.Project.Bible.IgnoredCharacters = append(ed.Handle.Project.Bible.IgnoredCharacters, wrn.Character)
+			if err := storage.Save(ed.Handle); err != nil {
+				l.Error("save after ignoring character", slog.String("character", wrn.Character), slog.Any("err", err))
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			refreshBible()
+			status.SetText(i18n.T("status.will_no_longer_be_flagged", wrn.Character))
+		}))
+		msg := widget.NewLabel(i18n.T("label.is_not_a_character_or_alias", wrn.Character))
+		dlg = dialog.NewCustom(i18n.T("msg.unknown_character_line", wrn.Line), i18n.T("msg.cancel"), container.NewVBox(msg, buttons), w)
+		dlg.Show()
+	}
+
 	// script pane
-	outlineBox := container.NewBorder(container.NewVBox(widget.NewLabel("Outline"), outlineSearch), nil, nil, nil, scriptOutline)
-	scriptSplit := container.NewHSplit(scriptEntry, outlineBox)
+	outlineBox := container.NewBorder(container.NewVBox(widget.NewLabel(i18n.T("label.outline")), outlineSearch), nil, nil, nil, scriptOutline)
+	scriptSplit := container.NewHSplit(container.NewBorder(scriptFind.box, nil, nil, nil, scriptEntry), outlineBox)
 	scriptSplit.Offset = 0.7
-	scriptPane := container.NewBorder(scriptControls, scriptErr, nil, nil, scriptSplit)
+	scriptPane := container.NewBorder(scriptControls, container.NewVBox(scriptErrScroll, scriptWarnScroll), nil, nil, scriptSplit)
 
 	// Bible management UI
 	// helper to compute min width for at least 20 characters
@@ -1132,10 +2159,130 @@ func Run(projectDir string) error {
 		return w20 + 24
 	}
 
+	// bibleImageAssets lists the project's image assets as portrait choices.
+	bibleImageAssets := func() []string {
+		var out []string
+		if ed.Handle == nil {
+			return out
+		}
+		root := ed.Handle.Root
+		_ = filepath.WalkDir(filepath.Join(root, storage.AssetsDirName), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || storage.IsTransientAssetName(d.Name()) || storage.AssetType(path) != "image" {
+				return nil
+			}
+			if rel, rerr := filepath.Rel(root, path); rerr == nil {
+				out = append(out, filepath.ToSlash(rel))
+			}
+			return nil
+		})
+		return out
+	}
+	// armedAssetRel returns the asset armed in the assets pane relative to the project root.
+	armedAssetRel := func() string {
+		if ed.Handle == nil || canvasWidget.armedAssetPath == "" {
+			return ""
+		}
+		rel, err := filepath.Rel(ed.Handle.Root, canvasWidget.armedAssetPath)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return ""
+		}
+		return filepath.ToSlash(rel)
+	}
+	// saveBibleEdit persists an edited entry; proj guards against the project being switched while the dialog was open.
+	saveBibleEdit := func(proj *storage.ProjectHandle, what string) bool {
+		if ed.Handle == nil || ed.Handle != proj {
+			return false
+		}
+		if err := storage.Save(ed.Handle); err != nil {
+			l.Error("save after edit "+strings.ToLower(what), slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+			return false
+		}
+		refreshBible()
+		status.SetText(i18n.T("status.entry_updated", what))
+		return true
+	}
+	// offerScriptRename asks whether dialogue lines spoken by a renamed character should follow the new name.
+	offerScriptRename := func(oldName, newName string) {
+		from, to := strings.ToUpper(oldName), strings.ToUpper(newName)
+		if _, n := script.RenameCharacter(scriptEntry.Text(), from, to); n == 0 {
+			return
+		}
+		dialog.ShowConfirm(i18n.T("msg.rename_in_script"),
+			i18n.T("msg.also_change_dialogue_lines_from_to", from, to),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				txt, n := script.RenameCharacter(scriptEntry.Text(), from, to)
+				scriptEntry.SetText(txt)
+				status.SetText(i18n.T("status.renamed_to_on_script_lines", from, to, n))
+			}, w)
+	}
+	editCharacter := func(row int) {
+		if ed.Handle == nil || row < 0 || row >= len(charIdx) {
+			return
+		}
+		proj, bi := ed.Handle, charIdx[row]
+		c := ed.Handle.Project.Bible.Characters[bi]
+		cur := bibleFields{Name: c.Name, Aliases: strings.Join(c.Aliases, ", "), Notes: c.Notes, Portrait: c.Portrait}
+		form := bibleForm{Kind: i18n.T("label.character"), WithAliases: true, WithPortrait: true}
+		showBibleEntryDialog(w, form, cur, bibleImageAssets(), armedAssetRel(), func(f bibleFields) {
+			if ed.Handle != proj || bi >= len(ed.Handle.Project.Bible.Characters) {
+				return
+			}
+			oldName := c.Name
+			c.Name, c.Aliases, c.Notes, c.Portrait = f.Name, splitAliases(f.Aliases), f.Notes, f.Portrait
+			ed.Handle.Project.Bible.Characters[bi] = c
+			l.Info("edit character", slog.String("name", c.Name))
+			if saveBibleEdit(proj, i18n.T("label.character")) && !strings.EqualFold(oldName, c.Name) {
+				offerScriptRename(oldName, c.Name)
+			}
+		})
+	}
+	editLocation := func(row int) {
+		if ed.Handle == nil || row < 0 || row >= len(locIdx) {
+			return
+		}
+		proj, bi := ed.Handle, locIdx[row]
+		loc := ed.Handle.Project.Bible.Locations[bi]
+		cur := bibleFields{Name: loc.Name, Aliases: strings.Join(loc.Aliases, ", "), Notes: loc.Notes}
+		showBibleEntryDialog(w, bibleForm{Kind: i18n.T("label.location"), WithAliases: true}, cur, nil, "", func(f bibleFields) {
+			if ed.Handle != proj || bi >= len(ed.Handle.Project.Bible.Locations) {
+				return
+			}
+			loc.Name, loc.Aliases, loc.Notes = f.Name, splitAliases(f.Aliases), f.Notes
+			ed.Handle.Project.Bible.Locations[bi] = loc
+			l.Info("edit location", slog.String("name", loc.Name))
+			saveBibleEdit(proj, i18n.T("label.location"))
+		})
+	}
+	editTag := func(row int) {
+		if ed.Handle == nil || row < 0 || row >= len(tagIdx) {
+			return
+		}
+		proj, bi := ed.Handle, tagIdx[row]
+		tg := ed.Handle.Project.Bible.Tags[bi]
+		showBibleEntryDialog(w, bibleForm{Kind: i18n.T("label.tag")}, bibleFields{Name: tg.Name, Notes: tg.Notes}, nil, "", func(f bibleFields) {
+			if ed.Handle != proj || bi >= len(ed.Handle.Project.Bible.Tags) {
+				return
+			}
+			tg.Name, tg.Notes = f.Name, f.Notes
+			ed.Handle.Project.Bible.Tags[bi] = tg
+			l.Info("edit tag", slog.String("name", tg.Name))
+			saveBibleEdit(proj, i18n.T("label.tag"))
+		})
+	}
+
 	charList = widget.NewList(
-		func() int { return len(charNames) },
-		func() fyne.CanvasObject { return widget.NewLabel("") },
-		func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(charNames[i]) },
+		func() int { return len(charLabels) },
+		func() fyne.CanvasObject { return newTapLabel() },
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			row := o.(*tapLabel)
+			row.SetText(charLabels[i])
+			row.onTapped = func() { charList.Select(i) }
+			row.onDoubleTapped = func() { charList.Select(i); editCharacter(int(i)) }
+		},
 	)
 	charList.OnSelected = func(id widget.ListItemID) {
 		selectedChar = int(id)
@@ -1146,63 +2293,76 @@ func Run(projectDir string) error {
 		}
 	}
 	addCharEntry := widget.NewEntry()
-	addCharEntry.SetPlaceHolder("Add character name")
+	addCharEntry.SetPlaceHolder(i18n.T("placeholder.add_character_name"))
 	addChar := func(name string) {
-		if ph == nil {
-			dialog.ShowInformation("Characters", "Open a project first.", w)
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.characters"), i18n.T("msg.open_a_project_first"), w)
 			return
 		}
 		name = strings.TrimSpace(name)
 		if name == "" {
-			dialog.ShowInformation("Characters", "Please enter a character name.", w)
+			dialog.ShowInformation(i18n.T("msg.characters"), i18n.T("msg.please_enter_a_character_name"), w)
 			w.Canvas().Focus(addCharEntry)
 			return
 		}
 		l.Info("add character", slog.String("name", name))
-		ph.Project.Bible.Characters = append(ph.Project.Bible.Characters, domain.BibleCharacter{Name: name})
-		if err := storage.Save(ph); err != nil {
+		ed.Handle.Project.Bible.Characters = append(ed.Handle.Project.Bible.Characters, domain.BibleCharacter{Name: name})
+		if err := storage.Save(ed.Handle); err != nil {
 			l.Error("save after add character", slog.Any("err", err))
-			dialog.ShowError(err, w)
+			dialog.ShowError(FriendlyError(err), w)
 			return
 		}
 		addCharEntry.SetText("")
 		refreshBible()
-		status.SetText("Character added.")
+		status.SetText(i18n.T("status.character_added"))
 	}
 	addCharEntry.OnSubmitted = func(s string) { addChar(s) }
 	// ensure entry has room for at least 20 characters
 	charEntryWrap := container.NewGridWrap(fyne.NewSize(calcEntryMinWidth(), addCharEntry.MinSize().Height), addCharEntry)
-	addCharBtn := widget.NewButton("Add", func() { addChar(addCharEntry.Text) })
-	delCharBtn := widget.NewButton("Delete", func() {
-		if ph == nil || selectedChar < 0 || selectedChar >= len(ph.Project.Bible.Characters) {
+	addCharBtn := widget.NewButton(i18n.T("msg.add"), func() { addChar(addCharEntry.Text) })
+	delCharBtn := widget.NewButton(i18n.T("button.delete"), func() {
+		if ed.Handle == nil || selectedChar < 0 || selectedChar >= len(charIdx) {
 			return
 		}
-		name := ph.Project.Bible.Characters[selectedChar].Name
+		bi := charIdx[selectedChar]
+		name := ed.Handle.Project.Bible.Characters[bi].Name
 		l.Info("delete character", slog.Int("index", selectedChar), slog.String("name", name))
-		ph.Project.Bible.Characters = append(ph.Project.Bible.Characters[:selectedChar], ph.Project.Bible.Characters[selectedChar+1:]...)
-		if err := storage.Save(ph); err != nil {
+		ed.Handle.Project.Bible.Characters = append(ed.Handle.Project.Bible.Characters[:bi], ed.Handle.Project.Bible.Characters[bi+1:]...)
+		if err := storage.Save(ed.Handle); err != nil {
 			l.Error("save after delete character", slog.Any("err", err))
-			dialog.ShowError(err, w)
+			dialog.ShowError(FriendlyError(err), w)
 			return
 		}
 		selectedChar = -1
 		refreshBible()
-		status.SetText("Character deleted.")
+		status.SetText(i18n.T("status.character_deleted"))
+	})
+	whereCharBtn := widget.NewButton(i18n.T("msg.where_used"), func() {
+		if selectedChar < 0 || selectedChar >= len(charNames) {
+			return
+		}
+		showWhereUsed(charNames[selectedChar], "bible:character:"+charNames[selectedChar])
 	})
 	// Layout: label, list, delete button below list, entry full-width, add button below entry
+	editCharBtn := widget.NewButton(i18n.T("button.edit"), func() { editCharacter(selectedChar) })
 	charBox := container.NewVBox(
-		widget.NewLabel("Characters"),
+		widget.NewLabel(i18n.T("msg.characters")),
 		charList,
-		container.NewHBox(delCharBtn),
+		container.NewHBox(editCharBtn, delCharBtn, whereCharBtn),
 		charEntryWrap,
 		container.NewHBox(addCharBtn),
 	)
 
 	// Locations
 	locList = widget.NewList(
-		func() int { return len(locNames) },
-		func() fyne.CanvasObject { return widget.NewLabel("") },
-		func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(locNames[i]) },
+		func() int { return len(locLabels) },
+		func() fyne.CanvasObject { return newTapLabel() },
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			row := o.(*tapLabel)
+			row.SetText(locLabels[i])
+			row.onTapped = func() { locList.Select(i) }
+			row.onDoubleTapped = func() { locList.Select(i); editLocation(int(i)) }
+		},
 	)
 	locList.OnSelected = func(id widget.ListItemID) {
 		selectedLoc = int(id)
@@ -1213,63 +2373,76 @@ func Run(projectDir string) error {
 		}
 	}
 	addLocEntry := widget.NewEntry()
-	addLocEntry.SetPlaceHolder("Add location name")
+	addLocEntry.SetPlaceHolder(i18n.T("placeholder.add_location_name"))
 	addLocation := func(name string) {
-		if ph == nil {
-			dialog.ShowInformation("Locations", "Open a project first.", w)
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.locations"), i18n.T("msg.open_a_project_first"), w)
 			return
 		}
 		name = strings.TrimSpace(name)
 		if name == "" {
-			dialog.ShowInformation("Locations", "Please enter a location name.", w)
+			dialog.ShowInformation(i18n.T("msg.locations"), i18n.T("msg.please_enter_a_location_name"), w)
 			w.Canvas().Focus(addLocEntry)
 			return
 		}
 		l.Info("add location", slog.String("name", name))
-		ph.Project.Bible.Locations = append(ph.Project.Bible.Locations, domain.BibleLocation{Name: name})
-		if err := storage.Save(ph); err != nil {
+		ed.Handle.Project.Bible.Locations = append(ed.Handle.Project.Bible.Locations, domain.BibleLocation{Name: name})
+		if err := storage.Save(ed.Handle); err != nil {
 			l.Error("save after add location", slog.Any("err", err))
-			dialog.ShowError(err, w)
+			dialog.ShowError(FriendlyError(err), w)
 			return
 		}
 		addLocEntry.SetText("")
 		refreshBible()
-		status.SetText("Location added.")
+		status.SetText(i18n.T("status.location_added"))
 	}
 	addLocEntry.OnSubmitted = func(s string) { addLocation(s) }
 	// ensure entry has room for at least 20 characters
 	locEntryWrap := container.NewGridWrap(fyne.NewSize(calcEntryMinWidth(), addLocEntry.MinSize().Height), addLocEntry)
-	addLocBtn := widget.NewButton("Add", func() { addLocation(addLocEntry.Text) })
-	delLocBtn := widget.NewButton("Delete", func() {
-		if ph == nil || selectedLoc < 0 || selectedLoc >= len(ph.Project.Bible.Locations) {
+	addLocBtn := widget.NewButton(i18n.T("msg.add"), func() { addLocation(addLocEntry.Text) })
+	delLocBtn := widget.NewButton(i18n.T("button.delete"), func() {
+		if ed.Handle == nil || selectedLoc < 0 || selectedLoc >= len(locIdx) {
 			return
 		}
-		name := ph.Project.Bible.Locations[selectedLoc].Name
+		bi := locIdx[selectedLoc]
+		name := ed.Handle.Project.Bible.Locations[bi].Name
 		l.Info("delete location", slog.Int("index", selectedLoc), slog.String("name", name))
-		ph.Project.Bible.Locations = append(ph.Project.Bible.Locations[:selectedLoc], ph.Project.Bible.Locations[selectedLoc+1:]...)
-		if err := storage.Save(ph); err != nil {
+		ed.Handle.Project.Bible.Locations = append(ed.Handle.Project.Bible.Locations[:bi], ed.Handle.Project.Bible.Locations[bi+1:]...)
+		if err := storage.Save(ed.Handle); err != nil {
 			l.Error("save after delete location", slog.Any("err", err))
-			dialog.ShowError(err, w)
+			dialog.ShowError(FriendlyError(err), w)
 			return
 		}
 		selectedLoc = -1
 		refreshBible()
-		status.SetText("Location deleted.")
+		status.SetText(i18n.T("status.location_deleted"))
+	})
+	whereLocBtn := widget.NewButton(i18n.T("msg.where_used"), func() {
+		if selectedLoc < 0 || selectedLoc >= len(locNames) {
+			return
+		}
+		showWhereUsed(locNames[selectedLoc], "bible:location:"+locNames[selectedLoc])
 	})
 	// Layout: label, list, delete button below list, entry full-width, add button below entry
+	editLocBtn := widget.NewButton(i18n.T("button.edit"), func() { editLocation(selectedLoc) })
 	locBox := container.NewVBox(
-		widget.NewLabel("Locations"),
+		widget.NewLabel(i18n.T("msg.locations")),
 		locList,
-		container.NewHBox(delLocBtn),
+		container.NewHBox(editLocBtn, delLocBtn, whereLocBtn),
 		locEntryWrap,
 		container.NewHBox(addLocBtn),
 	)
 
 	// Tags
 	tagList = widget.NewList(
-		func() int { return len(tagNames) },
-		func() fyne.CanvasObject { return widget.NewLabel("") },
-		func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(tagNames[i]) },
+		func() int { return len(tagLabels) },
+		func() fyne.CanvasObject { return newTapLabel() },
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			row := o.(*tapLabel)
+			row.SetText(tagLabels[i])
+			row.onTapped = func() { tagList.Select(i) }
+			row.onDoubleTapped = func() { tagList.Select(i); editTag(int(i)) }
+		},
 	)
 	tagList.OnSelected = func(id widget.ListItemID) {
 		selectedTag = int(id)
@@ -1280,58 +2453,88 @@ func Run(projectDir string) error {
 		}
 	}
 	addTagEntry := widget.NewEntry()
-	addTagEntry.SetPlaceHolder("Add tag")
+	addTagEntry.SetPlaceHolder(i18n.T("placeholder.add_tag"))
 	addTag := func(name string) {
-		if ph == nil {
-			dialog.ShowInformation("Tags", "Open a project first.", w)
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.tags"), i18n.T("msg.open_a_project_first"), w)
 			return
 		}
 		name = strings.TrimSpace(name)
 		if name == "" {
-			dialog.ShowInformation("Tags", "Please enter a tag.", w)
+			dialog.ShowInformation(i18n.T("msg.tags"), i18n.T("msg.please_enter_a_tag"), w)
 			w.Canvas().Focus(addTagEntry)
 			return
 		}
 		l.Info("add tag", slog.String("name", name))
-		ph.Project.Bible.Tags = append(ph.Project.Bible.Tags, domain.BibleTag{Name: name})
-		if err := storage.Save(ph); err != nil {
+		ed.Handle.Project.Bible.Tags = append(ed.Handle.Project.Bible.Tags, domain.BibleTag{Name: name})
+		if err := storage.Save(ed.Handle); err != nil {
 			l.Error("save after add tag", slog.Any("err", err))
-			dialog.ShowError(err, w)
+			dialog.ShowError(FriendlyError(err), w)
 			return
 		}
 		addTagEntry.SetText("")
 		refreshBible()
-		status.SetText("Tag added.")
+		status.SetText(i18n.T("status.tag_added"))
 	}
 	addTagEntry.OnSubmitted = func(s string) { addTag(s) }
 	// ensure entry has room for at least 20 characters
 	tagEntryWrap := container.NewGridWrap(fyne.NewSize(calcEntryMinWidth(), addTagEntry.MinSize().Height), addTagEntry)
-	addTagBtn := widget.NewButton("Add", func() { addTag(addTagEntry.Text) })
-	delTagBtn := widget.NewButton("Delete", func() {
-		if ph == nil || selectedTag < 0 || selectedTag >= len(ph.Project.Bible.Tags) {
+	addTagBtn := widget.NewButton(i18n.T("msg.add"), func() { addTag(addTagEntry.Text) })
+	delTagBtn := widget.NewButton(i18n.T("button.delete"), func() {
+		if ed.Handle == nil || selectedTag < 0 || selectedTag >= len(tagIdx) {
 			return
 		}
-		name := ph.Project.Bible.Tags[selectedTag].Name
+		bi := tagIdx[selectedTag]
+		name := ed.Handle.Project.Bible.Tags[bi].Name
 		l.Info("delete tag", slog.Int("index", selectedTag), slog.String("name", name))
-		ph.Project.Bible.Tags = append(ph.Project.Bible.Tags[:selectedTag], ph.Project.Bible.Tags[selectedTag+1:]...)
-		if err := storage.Save(ph); err != nil {
+		ed.Handle.Project.Bible.Tags = append(ed.Handle.Project.Bible.Tags[:bi], ed.Handle.Project.Bible.Tags[bi+1:]...)
+		if err := storage.Save(ed.Handle); err != nil {
 			l.Error("save after delete tag", slog.Any("err", err))
-			dialog.ShowError(err, w)
+			dialog.ShowError(FriendlyError(err), w)
 			return
 		}
 		selectedTag = -1
 		refreshBible()
-		status.SetText("Tag deleted.")
+		status.SetText(i18n.T("status.tag_deleted"))
 	})
+	whereTagBtn := widget.NewButton(i18n.T("msg.where_used"), func() {
+		if selectedTag < 0 || selectedTag >= len(tagNames) {
+			return
+		}
+		showWhereUsed("@"+strings.TrimPrefix(tagNames[selectedTag], "@"), "bible:tag:"+tagNames[selectedTag])
+	})
+	editTagBtn := widget.NewButton(i18n.T("button.edit"), func() { editTag(selectedTag) })
 	tagBox := container.NewVBox(
-		widget.NewLabel("Tags"),
+		widget.NewLabel(i18n.T("msg.tags")),
 		tagList,
-		container.NewHBox(delTagBtn),
+		container.NewHBox(editTagBtn, delTagBtn, whereTagBtn),
 		tagEntryWrap,
 		container.NewHBox(addTagBtn),
 	)
 
-	biblePane := container.NewGridWithColumns(3, charBox, locBox, tagBox)
+	relations.OnPage = func(p storage.PageRef) {
+		if ed.Handle == nil || p.Issue >= len(ed.Handle.Project.Issues) {
+			return
+		}
+		if p.Issue != ed.IssueIdx {
+			ed.IssueIdx = p.Issue
+			canvasWidget.ApplyIssue(ed.Handle.Project.Issues[p.Issue])
+		}
+		if !ed.SelectPage(p.Page) {
+			return
+		}
+		refreshPagesList()
+		refreshPanelsUI()
+		tabs.SelectIndex(0)
+	}
+	relations.OnScene = func(line int) {
+		tabs.SelectIndex(2)
+		scriptEntry.GoToLine(line, 1)
+	}
+	biblePane := container.NewAppTabs(
+		container.NewTabItem(i18n.T("tab.bible_entries"), container.NewGridWithColumns(3, charBox, locBox, tagBox)),
+		container.NewTabItem(i18n.T("tab.relationships"), relations.content),
+	)
 
 	// Colorization tab UI
 	// RGBA sliders and stroke width
@@ -1351,13 +2554,13 @@ func Run(projectDir string) error {
 	aS := widget.NewSlider(0, 255)
 	aS.Step = 1
 	aS.Value = 255
-	strokeWidthLbl := widget.NewLabel("Stroke: 2")
+	strokeWidthLbl := widget.NewLabel(i18n.T("label.stroke_width", 2.0))
 	strokeWidth := widget.NewSlider(0, 20)
 	strokeWidth.Step = 0.5
 	strokeWidth.Value = 2
-	fillEnabled := widget.NewCheck("Fill Enabled", nil)
+	fillEnabled := widget.NewCheck(i18n.T("check.fill_enabled"), nil)
 	fillEnabled.SetChecked(true)
-	strokeEnabled := widget.NewCheck("Stroke Enabled", nil)
+	strokeEnabled := widget.NewCheck(i18n.T("check.stroke_enabled"), nil)
 	strokeEnabled.SetChecked(true)
 	// Swatch
 	swatch := canvas.NewRectangle(color.RGBA{R: uint8(rS.Value), G: uint8(gS.Value), B: uint8(bS.Value), A: uint8(aS.Value)})
@@ -1369,7 +2572,7 @@ func Run(projectDir string) error {
 		gLbl.SetText(fmt.Sprintf("G: %d", int(gS.Value)))
 		bLbl.SetText(fmt.Sprintf("B: %d", int(bS.Value)))
 		aLbl.SetText(fmt.Sprintf("A: %d", int(aS.Value)))
-		strokeWidthLbl.SetText(fmt.Sprintf("Stroke: %.1f", strokeWidth.Value))
+		strokeWidthLbl.SetText(i18n.T("label.stroke_width", strokeWidth.Value))
 		swatch.FillColor = color.RGBA{R: uint8(rS.Value), G: uint8(gS.Value), B: uint8(bS.Value), A: uint8(aS.Value)}
 		swatch.Refresh()
 	}
@@ -1386,10 +2589,10 @@ func Run(projectDir string) error {
 		}
 		return canvasWidget.scene[canvasWidget.selected]
 	}
-	applyFillBtn := widget.NewButton("Apply Fill to Selected", func() {
+	applyFillBtn := widget.NewButton(i18n.T("button.apply_fill_to_selected"), func() {
 		n := getSelNode()
 		if n == nil {
-			dialog.ShowInformation("Colorize", "Select a shape on Canvas first.", w)
+			dialog.ShowInformation(i18n.T("msg.colorize"), i18n.T("msg.select_a_shape_on_canvas_first"), w)
 			return
 		}
 		f := n.Fill()
@@ -1397,12 +2600,12 @@ func Run(projectDir string) error {
 		f.Color = vector.Color{R: uint8(rS.Value), G: uint8(gS.Value), B: uint8(bS.Value), A: uint8(aS.Value)}
 		n.SetFill(f)
 		canvasWidget.Refresh()
-		status.SetText("Applied fill color")
+		status.SetText(i18n.T("status.applied_fill_color"))
 	})
-	applyStrokeBtn := widget.NewButton("Apply Stroke to Selected", func() {
+	applyStrokeBtn := widget.NewButton(i18n.T("button.apply_stroke_to_selected"), func() {
 		n := getSelNode()
 		if n == nil {
-			dialog.ShowInformation("Colorize", "Select a shape on Canvas first.", w)
+			dialog.ShowInformation(i18n.T("msg.colorize"), i18n.T("msg.select_a_shape_on_canvas_first"), w)
 			return
 		}
 		s := n.Stroke()
@@ -1411,12 +2614,12 @@ func Run(projectDir string) error {
 		s.Width = float32(strokeWidth.Value)
 		n.SetStroke(s)
 		canvasWidget.Refresh()
-		status.SetText("Applied stroke color")
+		status.SetText(i18n.T("status.applied_stroke_color"))
 	})
-	pickFromSelBtn := widget.NewButton("Pick From Selected", func() {
+	pickFromSelBtn := widget.NewButton(i18n.T("button.pick_from_selected"), func() {
 		n := getSelNode()
 		if n == nil {
-			dialog.ShowInformation("Colorize", "Select a shape first.", w)
+			dialog.ShowInformation(i18n.T("msg.colorize"), i18n.T("msg.select_a_shape_first"), w)
 			return
 		}
 		f := n.Fill()
@@ -1439,7 +2642,7 @@ func Run(projectDir string) error {
 		updateLabels()
 	})
 	colorizePane := container.NewVBox(
-		widget.NewLabel("Colorization"),
+		widget.NewLabel(i18n.T("label.colorization")),
 		container.NewGridWithColumns(2,
 			container.NewVBox(rLbl, rS),
 			container.NewVBox(gLbl, gS),
@@ -1448,7 +2651,7 @@ func Run(projectDir string) error {
 			container.NewVBox(bLbl, bS),
 			container.NewVBox(aLbl, aS),
 		),
-		container.NewHBox(widget.NewLabel("Preview:"), swatch),
+		container.NewHBox(widget.NewLabel(i18n.T("label.preview")), swatch),
 		widget.NewSeparator(),
 		container.NewGridWithColumns(2,
 			container.NewVBox(strokeWidthLbl, strokeWidth),
@@ -1468,8 +2671,8 @@ func Run(projectDir string) error {
 		sbPanelIDs := []string{}
 		sbSelectedPanel := -1
 		sbNotes := widget.NewMultiLineEntry()
-		sbNotes.SetPlaceHolder("Storyboard notes for selected panel…")
-		sbLinkedBeats := widget.NewLabel("Linked beats: —")
+		sbNotes.SetPlaceHolder(i18n.T("placeholder.storyboard_notes_for_selected_panel"))
+		sbLinkedBeats := widget.NewLabel(i18n.T("label.linked_beats"))
 		// Unmapped beats controls
 		sbUnmapped := []string{}
 		sbUnmappedList := widget.NewList(
@@ -1485,14 +2688,31 @@ func Run(projectDir string) error {
 		)
 		sbSelectedUnmapped := -1
 		sbUnmappedList.OnSelected = func(id widget.ListItemID) { sbSelectedUnmapped = int(id) }
+		// Unassigned caption lines (script CAPTION/NARRATION lines not linked to a caption object)
+		sbCaptionLines := []string{}
+		sbCaptionText := map[string]string{}
+		sbCaptionList := widget.NewList(
+			func() int { return len(sbCaptionLines) },
+			func() fyne.CanvasObject { return widget.NewLabel("") },
+			func(i widget.ListItemID, o fyne.CanvasObject) {
+				if i >= 0 && int(i) < len(sbCaptionLines) {
+					id := sbCaptionLines[i]
+					o.(*widget.Label).SetText(id + " — " + sbCaptionText[id])
+				} else {
+					o.(*widget.Label).SetText("")
+				}
+			},
+		)
+		sbSelectedCaption := -1
+		sbCaptionList.OnSelected = func(id widget.ListItemID) { sbSelectedCaption = int(id) }
 
 		refreshStoryboardPages := func() {
-			if ph == nil || len(ph.Project.Issues) == 0 {
+			if ed.Handle == nil || len(ed.Handle.Project.Issues) == 0 {
 				sbPageSelect.Options = []string{}
 				sbPageSelect.SetSelected("")
 				return
 			}
-			iss := ph.Project.Issues[currentIssueIdx]
+			iss := ed.Handle.Project.Issues[ed.IssueIdx]
 			opts := make([]string, 0, len(iss.Pages))
 			for _, pg := range iss.Pages {
 				opts = append(opts, strconv.Itoa(pg.Number))
@@ -1511,12 +2731,12 @@ func Run(projectDir string) error {
 			sbPanelIDs = sbPanelIDs[:0]
 			sbSelectedPanel = -1
 			sbNotes.SetText("")
-			sbLinkedBeats.SetText("Linked beats: —")
-			if ph == nil || len(ph.Project.Issues) == 0 || strings.TrimSpace(sbPageSelect.Selected) == "" {
+			sbLinkedBeats.SetText(i18n.T("label.linked_beats"))
+			if ed.Handle == nil || len(ed.Handle.Project.Issues) == 0 || strings.TrimSpace(sbPageSelect.Selected) == "" {
 				return
 			}
 			pageNum, _ := strconv.Atoi(sbPageSelect.Selected)
-			iss := ph.Project.Issues[currentIssueIdx]
+			iss := ed.Handle.Project.Issues[ed.IssueIdx]
 			for _, pg := range iss.Pages {
 				if pg.Number != pageNum {
 					continue
@@ -1549,11 +2769,11 @@ func Run(projectDir string) error {
 		sbPanelList.OnSelected = func(id widget.ListItemID) {
 			sbSelectedPanel = int(id)
 			// populate details
-			if ph == nil || sbSelectedPanel < 0 || sbSelectedPanel >= len(sbPanelIDs) {
+			if ed.Handle == nil || sbSelectedPanel < 0 || sbSelectedPanel >= len(sbPanelIDs) {
 				return
 			}
 			pageNum, _ := strconv.Atoi(sbPageSelect.Selected)
-			iss := ph.Project.Issues[currentIssueIdx]
+			iss := ed.Handle.Project.Issues[ed.IssueIdx]
 			for _, pg := range iss.Pages {
 				if pg.Number != pageNum {
 					continue
@@ -1562,9 +2782,9 @@ func Run(projectDir string) error {
 					if p.ID == sbPanelIDs[sbSelectedPanel] {
 						sbNotes.SetText(p.Notes)
 						if len(p.BeatIDs) == 0 {
-							sbLinkedBeats.SetText("Linked beats: —")
+							sbLinkedBeats.SetText(i18n.T("label.linked_beats"))
 						} else {
-							sbLinkedBeats.SetText("Linked beats: " + strings.Join(p.BeatIDs, ", "))
+							sbLinkedBeats.SetText(i18n.T("status.linked_beats", strings.Join(p.BeatIDs, ", ")))
 						}
 						break
 					}
@@ -1573,15 +2793,15 @@ func Run(projectDir string) error {
 			}
 		}
 
-		sbSaveNotes := widget.NewButton("Save Notes", func() {
-			if ph == nil || sbSelectedPanel < 0 || sbSelectedPanel >= len(sbPanelIDs) {
+		sbSaveNotes := widget.NewButton(i18n.T("button.save_notes"), func() {
+			if ed.Handle == nil || sbSelectedPanel < 0 || sbSelectedPanel >= len(sbPanelIDs) {
 				return
 			}
 			pageNum, _ := strconv.Atoi(sbPageSelect.Selected)
 			id := sbPanelIDs[sbSelectedPanel]
 			// update model
-			for i := range ph.Project.Issues {
-				iss := &ph.Project.Issues[i]
+			for i := range ed.Handle.Project.Issues {
+				iss := &ed.Handle.Project.Issues[i]
 				for j := range iss.Pages {
 					pg := &iss.Pages[j]
 					if pg.Number != pageNum {
@@ -1595,39 +2815,51 @@ func Run(projectDir string) error {
 					}
 				}
 			}
-			if err := storage.Save(ph); err != nil {
-				dialog.ShowError(err, w)
+			if err := storage.Save(ed.Handle); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
 			refreshStoryboardPanels()
 			sbPanelList.Refresh()
-			status.SetText("Storyboard notes saved.")
+			status.SetText(i18n.T("status.storyboard_notes_saved"))
 		})
 
 		// Unmapped beats refresh
 		refreshUnmappedBeats := func() {
 			sbUnmapped = sbUnmapped[:0]
-			if ph == nil {
+			if ed.Handle == nil {
 				sbUnmappedList.Refresh()
 				return
 			}
 			// Parse from current script editor text if available, else try storage.ReadScript
 			var txt string
-			if scriptEntry != nil && scriptEntry.Text != "" {
-				txt = scriptEntry.Text
-			} else if ph != nil {
-				t, _ := storage.ReadScript(ph)
+			if scriptEntry != nil && scriptEntry.Text() != "" {
+				txt = scriptEntry.Text()
+			} else if ed.Handle != nil {
+				t, _ := storage.ReadScript(ed.Handle)
 				txt = t
 			}
 			sc, _ := script.Parse(txt)
-			ids := storage.ComputeUnmappedBeats(sc, ph.Project)
+			ids := storage.ComputeUnmappedBeats(sc, ed.Handle.Project)
 			sbUnmapped = append(sbUnmapped, ids...)
 			sbSelectedUnmapped = -1
 			sbUnmappedList.Refresh()
+			// Caption lines share the parse
+			sbCaptionLines = storage.ComputeUnassignedCaptionLines(sc, ed.Handle.Project)
+			sbCaptionText = map[string]string{}
+			for _, scn := range sc.Scenes {
+				for _, ln := range scn.Lines {
+					if ln.Type == script.LineCaption {
+						sbCaptionText[storage.CaptionLineIDFor(ln)] = ln.Text
+					}
+				}
+			}
+			sbSelectedCaption = -1
+			sbCaptionList.Refresh()
 		}
 
-		btnMapBeat := widget.NewButton("Map Selected Beat to Panel", func() {
-			if ph == nil || sbSelectedPanel < 0 || sbSelectedPanel >= len(sbPanelIDs) {
+		btnMapBeat := widget.NewButton(i18n.T("button.map_selected_beat_to_panel"), func() {
+			if ed.Handle == nil || sbSelectedPanel < 0 || sbSelectedPanel >= len(sbPanelIDs) {
 				return
 			}
 			if sbSelectedUnmapped < 0 || sbSelectedUnmapped >= len(sbUnmapped) {
@@ -1636,33 +2868,98 @@ func Run(projectDir string) error {
 			pageNum, _ := strconv.Atoi(sbPageSelect.Selected)
 			panelID := sbPanelIDs[sbSelectedPanel]
 			beatID := sbUnmapped[sbSelectedUnmapped]
-			if err := storage.MapBeatToPanel(ph, pageNum, panelID, beatID); err != nil {
-				dialog.ShowError(err, w)
+			if err := storage.MapBeatToPanel(ed.Handle, pageNum, panelID, beatID); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
-			if err := storage.Save(ph); err != nil {
-				dialog.ShowError(err, w)
+			if err := storage.Save(ed.Handle); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
 			refreshStoryboardPanels()
 			sbPanelList.Refresh()
 			refreshUnmappedBeats()
-			status.SetText("Beat mapped to panel.")
+			if refreshPacing != nil {
+				refreshPacing()
+			}
+			status.SetText(i18n.T("status.beat_mapped_to_panel"))
+		})
+		// Suggest Mappings proposes panels for the unmapped beats; accepted ones are one undo step
+		btnSuggestBeats := widget.NewButton(i18n.T("button.suggest_beat_mappings"), func() {
+			if ed.Handle == nil {
+				return
+			}
+			var txt string
+			if scriptEntry != nil && scriptEntry.Text() != "" {
+				txt = scriptEntry.Text()
+			} else {
+				txt, _ = storage.ReadScript(ed.Handle)
+			}
+			sc, _ := script.Parse(txt)
+			h := ed.Handle
+			showBeatSuggestionsDialog(w, h, sc, l, status, func(ss []storage.BeatSuggestion) (int, error) {
+				if ed.Handle != h {
+					return 0, nil
+				}
+				var n int
+				err := ed.Change(func() (string, error) {
+					var err error
+					if n, err = applyBeatSuggestions(h, ss); n == 0 {
+						return "", err
+					}
+					return i18n.T("undo.accept_beat_suggestions"), err
+				})
+				if n == 0 {
+					return 0, err
+				}
+				refreshStoryboardPanels()
+				sbPanelList.Refresh()
+				refreshUnmappedBeats()
+				if refreshPacing != nil {
+					refreshPacing()
+				}
+				return n, err
+			})
+		})
+		btnAssignCaption := widget.NewButton(i18n.T("button.assign_selected_caption_to_panel"), func() {
+			if ed.Handle == nil || sbSelectedPanel < 0 || sbSelectedPanel >= len(sbPanelIDs) {
+				return
+			}
+			if sbSelectedCaption < 0 || sbSelectedCaption >= len(sbCaptionLines) {
+				return
+			}
+			pageNum, _ := strconv.Atoi(sbPageSelect.Selected)
+			lineID := sbCaptionLines[sbSelectedCaption]
+			cid, err := storage.AssignCaptionLine(ed.Handle, pageNum, sbPanelIDs[sbSelectedPanel], "", lineID, sbCaptionText[lineID])
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			if err := storage.Save(ed.Handle); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			refreshUnmappedBeats()
+			status.SetText(i18n.T("status.caption_line_assigned_to", cid))
 		})
 
 		// Layout
-		left := container.NewBorder(container.NewVBox(widget.NewLabel("Page"), sbPageSelect), nil, nil, nil, sbPanelList)
+		left := container.NewBorder(container.NewVBox(widget.NewLabel(i18n.T("label.page")), sbPageSelect), nil, nil, nil, sbPanelList)
 		right := container.NewVBox(
-			widget.NewLabel("Panel Details"),
+			widget.NewLabel(i18n.T("label.panel_details")),
 			sbLinkedBeats,
 			widget.NewSeparator(),
-			widget.NewLabel("Notes"),
+			widget.NewLabel(i18n.T("form.notes")),
 			sbNotes,
 			container.NewHBox(sbSaveNotes),
 			widget.NewSeparator(),
-			widget.NewLabel("Unmapped Beats (from Script)"),
+			widget.NewLabel(i18n.T("label.unmapped_beats_from_script")),
 			sbUnmappedList,
-			container.NewHBox(btnMapBeat),
+			container.NewHBox(btnMapBeat, btnSuggestBeats),
+			widget.NewSeparator(),
+			widget.NewLabel(i18n.T("label.unassigned_captions_from_script")),
+			sbCaptionList,
+			container.NewHBox(btnAssignCaption),
 		)
 		sp := container.NewHSplit(left, right)
 		sp.Offset = 0.35
@@ -1685,15 +2982,28 @@ func Run(projectDir string) error {
 	}
 
 	// Tabs
-	var tabs *container.AppTabs
 	tabs = container.NewAppTabs(
-		container.NewTabItem("Canvas", canvasPane),
-		container.NewTabItem("Colorize", colorizePane),
-		container.NewTabItem("Script", scriptPane),
-		container.NewTabItem("Storyboard", storyboardPane),
-		container.NewTabItem("Bible", biblePane),
+		container.NewTabItem(i18n.T("tab.canvas"), canvasPane),
+		container.NewTabItem(i18n.T("msg.colorize"), colorizePane),
+		container.NewTabItem(i18n.T("tab.script"), scriptPane),
+		container.NewTabItem(i18n.T("tab.storyboard"), storyboardPane),
+		container.NewTabItem(i18n.T("tab.bible"), biblePane),
 	)
-	editorContent := container.NewBorder(nil, status, nil, nil, tabs)
+	tabs.OnSelected = func(ti *container.TabItem) {
+		if ti.Content == biblePane {
+			sc, _ := script.Parse(scriptEntry.Text())
+			refreshRelations(sc)
+		}
+	}
+	presenceInd := newPresenceIndicator(w.Canvas())
+	syncBtn := widget.NewButton("", nil)
+	syncBtn.Importance = widget.LowImportance
+	syncBtn.Hide()
+	conflictsBtn := widget.NewButton("", nil)
+	conflictsBtn.Importance = widget.WarningImportance
+	conflictsBtn.Hide()
+	statusRight := container.NewHBox(syncBtn, conflictsBtn, presenceInd)
+	editorContent := container.NewBorder(nil, container.NewBorder(nil, nil, nil, statusRight, status), nil, nil, tabs)
 	root := container.NewMax(editorContent)
 	w.SetContent(root)
 
@@ -1710,81 +3020,365 @@ func Run(projectDir string) error {
 		return appCfg.General.EnableServer
 	}
 
-	showServerBrowserWindow := func(client *backend.Client) {
-		win := fyneApp.NewWindow("Server: Projects (Read-only)")
-		win.Resize(fyne.NewSize(900, 600))
-
-		// Left: project list with filter
-		var projects []backend.Project
-		var filtered []backend.Project
-
-		filterEntry := widget.NewEntry()
-		filterEntry.SetPlaceHolder("Filter projects…")
-
-		list := widget.NewList(
-			func() int { return len(filtered) },
-			func() fyne.CanvasObject { return widget.NewLabel("") },
-			func(i widget.ListItemID, o fyne.CanvasObject) {
-				if i >= 0 && int(i) < len(filtered) {
-					o.(*widget.Label).SetText(fmt.Sprintf("%s (v%d)", filtered[i].Name, filtered[i].Version))
-				} else {
-					o.(*widget.Label).SetText("")
-				}
-			},
-		)
-
-		// Right: snapshot JSON view + search
-		snapshotTitle := widget.NewLabel("Select a project to view its index snapshot")
-		jsonView := widget.NewMultiLineEntry()
-		jsonView.Wrapping = fyne.TextWrapWord
-		jsonView.SetMinRowsVisible(10)
-		jsonView.Disable()
-		jsonSearch := widget.NewEntry()
-		jsonSearch.SetPlaceHolder("Search in snapshot text…")
-		matchLabel := widget.NewLabel("")
-
-		updateFilter := func() {
-			q := strings.ToLower(strings.TrimSpace(filterEntry.Text))
-			if q == "" {
-				filtered = append(filtered[:0], projects...)
-			} else {
-				filtered = filtered[:0]
-				for _, p := range projects {
-					if strings.Contains(strings.ToLower(p.Name), q) || strings.Contains(strings.ToLower(p.StableID), q) {
-						filtered = append(filtered, p)
-					}
-				}
+	// newServerClient creates a client for the server feature. Tokens the client refreshes replace the
+	// stored one, in the preferences and through the config token store (OS keyring).
+	newServerClient := func(base, tok string) *backend.Client {
+		cl := backend.NewClient(base, tok)
+		cl.OnTokenRefreshed = func(tok string, exp time.Time) {
+			prefs.SetString("server.token", tok)
+			if err := config.SaveToken(tok); err != nil {
+				l.Warn("store refreshed token failed", slog.Any("err", err))
 			}
-			list.Refresh()
+			l.Info("server token refreshed", slog.Time("expires_at", exp))
 		}
-		filterEntry.OnChanged = func(string) { updateFilter() }
+		return cl
+	}
 
-		jsonSearch.OnChanged = func(s string) {
-			text := strings.ToLower(jsonView.Text)
-			term := strings.ToLower(strings.TrimSpace(s))
-			if term == "" {
-				matchLabel.SetText("")
-				return
-			}
-			count := strings.Count(text, term)
-			matchLabel.SetText(fmt.Sprintf("Matches: %d", count))
+	// trackSyncChanges makes saves of the open project queue sync ops for Server → Push Local Changes.
+	trackSyncChanges := func() {
+		if serverFeatureEnabled() && ed.Handle != nil {
+			ed.Handle.TrackChanges()
 		}
+	}
 
-		list.OnSelected = func(id widget.ListItemID) {
-			if id < 0 || int(id) >= len(filtered) {
-				return
-			}
-			proj := filtered[id]
-			ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
-			defer cancel()
-			env, err := client.GetIndexSnapshot(ctx, proj.ID)
-			if err != nil {
-				dialog.ShowError(err, win)
-				return
-			}
-			snapshotTitle.SetText(fmt.Sprintf("Project: %s — Version %d — Snapshot at %s", proj.Name, env.Version, env.CreatedAt))
-			b, _ := json.MarshalIndent(env.Snapshot, "", "  ")
-			jsonView.SetText(string(b))
+	// Review comments (server feature). Each project folder is linked once to the server project that
+	// stores its comments; the server URL and token come from Server → Connect to Server….
+	commentsProjectKey := func() string { return "server.comments_project." + ed.Handle.Root }
+	commentsClient := func() (*backend.Client, int64) {
+		base := strings.TrimSpace(prefs.StringWithFallback("server.url", ""))
+		tok := strings.TrimSpace(prefs.StringWithFallback("server.token", ""))
+		if ed.Handle == nil || base == "" || tok == "" {
+			return nil, 0
+		}
+		return newServerClient(base, tok), int64(prefs.IntWithFallback(commentsProjectKey(), 0))
+	}
+	var restartPresence, restartSync func()
+	linkCommentsProject := func(cl *backend.Client, then func()) {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		plist, err := cl.ListProjects(ctx)
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		if len(plist) == 0 {
+			dialog.ShowInformation(i18n.T("msg.comments"), i18n.T("msg.you_are_not_a_member_of"), w)
+			return
+		}
+		labels := make([]string, len(plist))
+		for i, p := range plist {
+			labels[i] = fmt.Sprintf("%s (id:%d)", p.Name, p.ID)
+		}
+		sel := widget.NewSelect(labels, nil)
+		sel.PlaceHolder = i18n.T("placeholder.choose_server_project")
+		msg := widget.NewLabel(i18n.T("label.review_comments_for_this_project_are"))
+		dialog.NewCustomConfirm(i18n.T("msg.link_server_project"), i18n.T("msg.link"), i18n.T("msg.cancel"), container.NewVBox(msg, sel), func(ok bool) {
+			if !ok || sel.SelectedIndex() < 0 {
+				return
+			}
+			prefs.SetInt(commentsProjectKey(), int(plist[sel.SelectedIndex()].ID))
+			restartPresence()
+			restartSync()
+			commentsFor = ""
+			refreshPanelsUI()
+			then()
+		}, w).Show()
+	}
+	// Presence (server feature): while a project linked to a server project is open, heartbeats tell
+	// the server this client views it, and the status bar shows who else does.
+	var presence *presenceBeacon
+	presenceClientID := newPresenceClientID()
+	restartPresence = func() {
+		presence.Close()
+		presence = nil
+		presenceInd.SetOthers(nil)
+		cl, pid := commentsClient()
+		if !serverFeatureEnabled() || cl == nil || pid == 0 {
+			return
+		}
+		var b *presenceBeacon
+		b = startPresenceBeacon(cl, pid, presenceClientID, presenceInterval, l, func(others []string) {
+			fyne.Do(func() {
+				if presence == b {
+					presenceInd.SetOthers(others)
+				}
+			})
+		})
+		presence = b
+	}
+	// Sync (server feature): while a project linked to a server project is open, a background worker
+	// pulls the changes of others into the project and pushes the local ones, queueing them while the
+	// server is unreachable. Changes both sides made to the same entity wait as conflicts for the user.
+	var syncWorker *backend.SyncWorker
+	var syncState backend.SyncStatus
+	showSyncStatus := func(st backend.SyncStatus) {
+		syncState = st
+		if txt := syncStatusLabel(st); txt != "" {
+			syncBtn.SetText(txt)
+			syncBtn.Show()
+		} else {
+			syncBtn.Hide()
+		}
+		if txt := syncConflictsLabel(st.Conflicts); txt != "" {
+			conflictsBtn.SetText(txt)
+			conflictsBtn.Show()
+		} else {
+			conflictsBtn.Hide()
+		}
+	}
+	restartSync = func() {
+		syncWorker.Close()
+		syncWorker = nil
+		showSyncStatus(backend.SyncStatus{})
+		cl, pid := commentsClient()
+		if !serverFeatureEnabled() || cl == nil || pid == 0 {
+			return
+		}
+		ph := ed.Handle
+		var wk *backend.SyncWorker
+		current := func() bool { return syncWorker == wk && ed.Handle == ph }
+		wk = backend.StartSyncWorker(cl, ph.Index(), pid, backend.SyncWorkerConfig{}, backend.SyncHandlers{
+			// Pulled ops are applied on the UI goroutine, like any other edit
+			Apply: func(ctx context.Context, ops []storage.RemoteSyncOp) error {
+				errc := make(chan error, 1)
+				fyne.Do(func() {
+					if !current() {
+						errc <- context.Canceled
+						return
+					}
+					n, skipped, err := ed.ApplyRemoteOps(ops)
+					for _, e := range skipped {
+						l.Warn("pulled sync op skipped", slog.Any("err", e))
+					}
+					if err == nil && n > 0 {
+						refreshPagesList()
+						refreshPanelsUI()
+						status.SetText(i18n.N("status.applied_server_changes", n, n))
+					}
+					errc <- err
+				})
+				select {
+				case err := <-errc:
+					return err
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			},
+			Status: func(st backend.SyncStatus) {
+				fyne.Do(func() {
+					if current() {
+						showSyncStatus(st)
+					}
+				})
+			},
+		}, l)
+		syncWorker = wk
+	}
+	syncBtn.OnTapped = func() {
+		if txt := syncStatusTooltip(syncState); txt != "" {
+			status.SetText(txt)
+		}
+		syncWorker.Trigger()
+	}
+	conflictsBtn.OnTapped = func() {
+		if ed.Handle == nil {
+			return
+		}
+		showSyncConflicts(w, ed.Handle.Index(), l, func(op *storage.RemoteSyncOp) {
+			if op != nil {
+				if _, _, err := ed.ApplyRemoteOps([]storage.RemoteSyncOp{*op}); err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+				}
+				refreshPagesList()
+				refreshPanelsUI()
+			}
+			syncWorker.Trigger()
+		})
+	}
+	loadPanelComments = func(pageNumber int) {
+		clear(panelCommentCounts)
+		cl, pid := commentsClient()
+		if !serverFeatureEnabled() || cl == nil || pid == 0 {
+			return
+		}
+		want := commentsFor
+		open := false
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+			defer cancel()
+			list, err := cl.ListComments(ctx, pid, backend.CommentFilter{PathPrefix: pageCommentPrefix(pageNumber), Resolved: &open})
+			fyne.Do(func() {
+				if err != nil {
+					l.Warn("load review comments failed", slog.Int("page", pageNumber), slog.Any("err", err))
+					return
+				}
+				if commentsFor != want {
+					return // page or project changed meanwhile
+				}
+				panelCommentCounts = openCommentCounts(list)
+				refreshPanelsUI()
+			})
+		}()
+	}
+	showPanelComments = func(panelID string) {
+		cl, pid := commentsClient()
+		if cl == nil {
+			dialog.ShowInformation(i18n.T("msg.comments"), i18n.T("msg.connect_to_the_server_first_via"), w)
+			return
+		}
+		if pid == 0 {
+			linkCommentsProject(cl, func() { showPanelComments(panelID) })
+			return
+		}
+		path := panelCommentPath(ed.Handle.Project.Issues[ed.IssueIdx].Pages[ed.PageIdx].Number, panelID)
+		changed := func() {
+			commentsFor = ""
+			refreshPanelsUI()
+		}
+		thread := container.NewVBox()
+		var reload func()
+		reload = func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+			defer cancel()
+			list, err := cl.ListComments(ctx, pid, backend.CommentFilter{PathPrefix: path})
+			thread.Objects = nil
+			if err != nil {
+				thread.Add(widget.NewLabel(i18n.T("label.could_not_load_comments", err.Error())))
+				return
+			}
+			if len(list) == 0 {
+				thread.Add(widget.NewLabel(i18n.T("label.no_comments_yet")))
+			}
+			for _, c := range list {
+				body := widget.NewLabel(c.Body)
+				body.Wrapping = fyne.TextWrapWord
+				thread.Add(widget.NewLabelWithStyle(commentHeader(c), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+				thread.Add(body)
+				if !c.Resolved() {
+					id := c.ID
+					thread.Add(container.NewHBox(widget.NewButton(i18n.T("button.resolve"), func() {
+						ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+						defer cancel()
+						if _, err := cl.ResolveComment(ctx, pid, id); err != nil {
+							dialog.ShowError(FriendlyError(err), w)
+							return
+						}
+						reload()
+						changed()
+					})))
+				}
+				thread.Add(widget.NewSeparator())
+			}
+			thread.Refresh()
+		}
+		input := widget.NewMultiLineEntry()
+		input.SetPlaceHolder(i18n.T("placeholder.add_a_comment"))
+		input.SetMinRowsVisible(3)
+		addBtn := widget.NewButton(i18n.T("button.add_comment"), func() {
+			text := strings.TrimSpace(input.Text)
+			if text == "" {
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+			defer cancel()
+			if _, err := cl.AddComment(ctx, pid, path, text); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			input.SetText("")
+			reload()
+			changed()
+		})
+		reload()
+		content := container.NewBorder(nil, container.NewVBox(input, addBtn), nil, nil, container.NewVScroll(thread))
+		d := dialog.NewCustom(i18n.T("msg.comments_title", panelID), i18n.T("msg.close"), content, w)
+		d.Resize(fyne.NewSize(520, 480))
+		d.Show()
+	}
+	if !serverFeatureEnabled() {
+		btnComments.Hide()
+	}
+
+	showServerBrowserWindow := func(client *backend.Client) {
+		win := fyneApp.NewWindow(i18n.T("title.server_projects_read_only"))
+		win.Resize(fyne.NewSize(900, 600))
+
+		// Left: project list with filter
+		var projects []backend.Project
+		var filtered []backend.Project
+		snapshots := map[int64]*backend.IndexSnapshotEnvelope{} // last fetched, shown again on 304
+
+		filterEntry := widget.NewEntry()
+		filterEntry.SetPlaceHolder(i18n.T("placeholder.filter_projects"))
+
+		list := widget.NewList(
+			func() int { return len(filtered) },
+			func() fyne.CanvasObject { return widget.NewLabel("") },
+			func(i widget.ListItemID, o fyne.CanvasObject) {
+				if i >= 0 && int(i) < len(filtered) {
+					o.(*widget.Label).SetText(fmt.Sprintf("%s (v%d)", filtered[i].Name, filtered[i].Version))
+				} else {
+					o.(*widget.Label).SetText("")
+				}
+			},
+		)
+
+		// Right: snapshot JSON view + search
+		snapshotTitle := widget.NewLabel(i18n.T("label.select_a_project_to_view_its"))
+		jsonView := widget.NewMultiLineEntry()
+		jsonView.Wrapping = fyne.TextWrapWord
+		jsonView.SetMinRowsVisible(10)
+		jsonView.Disable()
+		jsonSearch := widget.NewEntry()
+		jsonSearch.SetPlaceHolder(i18n.T("placeholder.search_in_snapshot_text"))
+		matchLabel := widget.NewLabel("")
+
+		updateFilter := func() {
+			q := strings.ToLower(strings.TrimSpace(filterEntry.Text))
+			if q == "" {
+				filtered = append(filtered[:0], projects...)
+			} else {
+				filtered = filtered[:0]
+				for _, p := range projects {
+					if strings.Contains(strings.ToLower(p.Name), q) || strings.Contains(strings.ToLower(p.StableID), q) {
+						filtered = append(filtered, p)
+					}
+				}
+			}
+			list.Refresh()
+		}
+		filterEntry.OnChanged = func(string) { updateFilter() }
+
+		jsonSearch.OnChanged = func(s string) {
+			text := strings.ToLower(jsonView.Text)
+			term := strings.ToLower(strings.TrimSpace(s))
+			if term == "" {
+				matchLabel.SetText("")
+				return
+			}
+			count := strings.Count(text, term)
+			matchLabel.SetText(i18n.T("status.matches", count))
+		}
+
+		list.OnSelected = func(id widget.ListItemID) {
+			if id < 0 || int(id) >= len(filtered) {
+				return
+			}
+			proj := filtered[id]
+			ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+			defer cancel()
+			env, err := client.GetIndexSnapshot(ctx, proj.ID)
+			if errors.Is(err, backend.ErrNotModified) && snapshots[proj.ID] != nil {
+				env, err = snapshots[proj.ID], nil
+			}
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), win)
+				return
+			}
+			snapshots[proj.ID] = env
+			snapshotTitle.SetText(i18n.T("status.project_version_snapshot_at", proj.Name, env.Version, env.CreatedAt))
+			b, _ := json.MarshalIndent(env.Snapshot, "", "  ")
+			jsonView.SetText(string(b))
 			jsonSearch.SetText("")
 			matchLabel.SetText("")
 		}
@@ -1801,7 +3395,7 @@ func Run(projectDir string) error {
 		defer cancel()
 		plist, err := client.ListProjects(ctx)
 		if err != nil {
-			fyne.CurrentApp().SendNotification(&fyne.Notification{Title: "Server", Content: fmt.Sprintf("List projects failed: %v", err)})
+			fyne.CurrentApp().SendNotification(&fyne.Notification{Title: i18n.T("msg.server"), Content: i18n.T("msg.list_projects_failed", err)})
 		} else {
 			projects = plist
 			filtered = append(filtered[:0], projects...)
@@ -1813,9 +3407,9 @@ func Run(projectDir string) error {
 
 	showServerConnectDialog := func() {
 		urlEntry := widget.NewEntry()
-		urlEntry.SetPlaceHolder("https://server:8080")
+		urlEntry.SetPlaceHolder(i18n.T("placeholder.https_server_8080"))
 		tokEntry := widget.NewPasswordEntry()
-		tokEntry.SetPlaceHolder("Bearer token")
+		tokEntry.SetPlaceHolder(i18n.T("placeholder.bearer_token"))
 
 		// Prefill from preferences if available
 		if u := prefs.StringWithFallback("server.url", ""); u != "" {
@@ -1825,9 +3419,9 @@ func Run(projectDir string) error {
 			tokEntry.SetText(t)
 		}
 
-		form := dialog.NewForm("Connect to Server", "Connect", "Cancel", []*widget.FormItem{
-			widget.NewFormItem("URL", urlEntry),
-			widget.NewFormItem("Token", tokEntry),
+		form := dialog.NewForm(i18n.T("msg.connect_to_server"), i18n.T("msg.connect"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.T("form.url"), urlEntry),
+			widget.NewFormItem(i18n.T("form.token"), tokEntry),
 		}, func(ok bool) {
 			if !ok {
 				return
@@ -1835,31 +3429,76 @@ func Run(projectDir string) error {
 			base := strings.TrimSpace(urlEntry.Text)
 			tok := strings.TrimSpace(tokEntry.Text)
 			if base == "" || tok == "" {
-				dialog.ShowInformation("Connect to Server", "Please enter URL and token.", w)
+				dialog.ShowInformation(i18n.T("msg.connect_to_server"), i18n.T("msg.please_enter_url_and_token"), w)
 				return
 			}
 			prefs.SetString("server.url", base)
 			prefs.SetString("server.token", tok)
-			cl := backend.NewClient(base, tok)
+			cl := newServerClient(base, tok)
+			// Renew a token about to expire up front; requests renew it on a 401 as well
+			ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+			if err := cl.EnsureValidToken(ctx, nil); err != nil {
+				l.Warn("renew server token failed", slog.Any("err", err))
+			}
+			cancel()
+			restartPresence()
+			restartSync()
 			showServerBrowserWindow(cl)
 		}, w)
 		form.Show()
 	}
 
+	// showPushChanges drains the open project's sync outbox into the linked server project.
+	var showPushChanges func()
+	showPushChanges = func() {
+		cl, pid := commentsClient()
+		if cl == nil {
+			dialog.ShowInformation(i18n.T("msg.server"), i18n.T("msg.open_a_project_and_connect_to"), w)
+			return
+		}
+		if pid == 0 {
+			linkCommentsProject(cl, func() { showPushChanges() })
+			return
+		}
+		cl.Outbox = ed.Handle.Index()
+		status.SetText(i18n.T("status.pushing_local_changes"))
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+			defer cancel()
+			res, err := cl.PushPending(ctx, pid)
+			fyne.Do(func() {
+				if err != nil {
+					l.Error("push local changes failed", slog.Int64("project_id", pid), slog.Any("err", err))
+					status.SetText(i18n.T("status.push_failed"))
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				l.Info("pushed local changes", slog.Int64("project_id", pid), slog.Int("accepted", res.Accepted), slog.Int("duplicates", res.Duplicates), slog.Int64("server_version", res.ServerVersion))
+				msg := i18n.N("status.pushed_changes", res.Accepted, res.Accepted, res.ServerVersion)
+				if res.Duplicates > 0 {
+					msg = i18n.N("status.pushed_changes_duplicates", res.Accepted, res.Accepted, res.Duplicates, res.ServerVersion)
+				}
+				status.SetText(msg)
+				syncWorker.Trigger()
+				dialog.ShowInformation(i18n.T("msg.push_local_changes"), msg, w)
+			})
+		}()
+	}
+
 	showGrantAccessDialog := func() {
 		base := strings.TrimSpace(prefs.StringWithFallback("server.url", ""))
 		tok := strings.TrimSpace(prefs.StringWithFallback("server.token", ""))
 		if base == "" || tok == "" {
-			dialog.ShowInformation("Server", "Connect to the server first via Server → Connect to Server…", w)
+			dialog.ShowInformation(i18n.T("msg.server"), i18n.T("msg.connect_to_the_server_first_via"), w)
 			return
 		}
-		cl := backend.NewClient(base, tok)
+		cl := newServerClient(base, tok)
 		cl.AdminAPIKey = prefs.StringWithFallback("server.admin_key", "")
 		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
 		defer cancel()
 		plist, err := cl.ListProjects(ctx)
 		if err != nil {
-			dialog.ShowError(err, w)
+			dialog.ShowError(FriendlyError(err), w)
 			return
 		}
 		type projOpt struct {
@@ -1887,28 +3526,28 @@ func Run(projectDir string) error {
 			selProjectID = opts[0].ID
 		}
 		emailEntry := widget.NewEntry()
-		emailEntry.SetPlaceHolder("alice@example.com")
+		emailEntry.SetPlaceHolder(i18n.T("placeholder.alice_example_com"))
 		nameEntry := widget.NewEntry()
-		nameEntry.SetPlaceHolder("Alice (optional)")
+		nameEntry.SetPlaceHolder(i18n.T("placeholder.alice_optional"))
 		roleSelect := widget.NewSelect([]string{"owner", "editor", "viewer"}, nil)
 		roleSelect.SetSelected("owner")
 		adminKeyEntry := widget.NewPasswordEntry()
-		adminKeyEntry.SetPlaceHolder("Admin API Key (for static mode)")
+		adminKeyEntry.SetPlaceHolder(i18n.T("placeholder.admin_api_key_for_static_mode"))
 		if k := cl.AdminAPIKey; k != "" {
 			adminKeyEntry.SetText(k)
 		}
-		form := dialog.NewForm("Grant Project Access", "Grant", "Cancel", []*widget.FormItem{
-			widget.NewFormItem("Project", projectSelect),
-			widget.NewFormItem("Email", emailEntry),
-			widget.NewFormItem("Display Name", nameEntry),
-			widget.NewFormItem("Role", roleSelect),
-			widget.NewFormItem("Admin API Key", adminKeyEntry),
+		form := dialog.NewForm(i18n.T("msg.grant_project_access"), i18n.T("msg.grant"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.T("form.project"), projectSelect),
+			widget.NewFormItem(i18n.T("form.email"), emailEntry),
+			widget.NewFormItem(i18n.T("form.display_name"), nameEntry),
+			widget.NewFormItem(i18n.T("form.role"), roleSelect),
+			widget.NewFormItem(i18n.T("form.admin_api_key"), adminKeyEntry),
 		}, func(ok bool) {
 			if !ok {
 				return
 			}
 			if selProjectID == 0 || strings.TrimSpace(emailEntry.Text) == "" {
-				dialog.ShowInformation("Grant Project Access", "Please select a project and enter an email.", w)
+				dialog.ShowInformation(i18n.T("msg.grant_project_access"), i18n.T("msg.please_select_a_project_and_enter"), w)
 				return
 			}
 			cl.AdminAPIKey = strings.TrimSpace(adminKeyEntry.Text)
@@ -1918,17 +3557,17 @@ func Run(projectDir string) error {
 			defer cancel2()
 			res, err := cl.AdminGrantMembership(ctx2, req)
 			if err != nil {
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
-			dialog.ShowInformation("Grant Project Access", fmt.Sprintf("Granted %s as %s on project %d.", res.User, res.Role, res.ProjectID), w)
+			dialog.ShowInformation(i18n.T("msg.grant_project_access"), i18n.T("msg.granted_as_on_project", res.User, res.Role, res.ProjectID), w)
 		}, w)
 		form.Show()
 	}
 
 	// Build menus
 	var closeProjItem *fyne.MenuItem
-	newItem := fyne.NewMenuItem("New…", func() {
+	newItem := fyne.NewMenuItem(i18n.T("menu.new"), func() {
 		l.Info("menu: new project")
 		// Step 1: choose a folder for the new project
 		fd := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
@@ -1944,12 +3583,12 @@ func Run(projectDir string) error {
 			l.Info("new project folder selected", slog.String("root", abs))
 			// Step 2: prompt for project name and template
 			nameEntry := widget.NewEntry()
-			nameEntry.SetPlaceHolder("Project Name")
+			nameEntry.SetPlaceHolder(i18n.T("placeholder.project_name"))
 			templateSelect := widget.NewSelect([]string{"Blank", "3x3 Grid"}, nil)
 			templateSelect.SetSelected("Blank")
-			form := dialog.NewForm("New Project", "Create", "Cancel", []*widget.FormItem{
-				widget.NewFormItem("Name", nameEntry),
-				widget.NewFormItem("Template", templateSelect),
+			form := dialog.NewForm(i18n.T("msg.new_project"), i18n.T("msg.create"), i18n.T("msg.cancel"), []*widget.FormItem{
+				widget.NewFormItem(i18n.T("form.name"), nameEntry),
+				widget.NewFormItem(i18n.T("form.template"), templateSelect),
 			}, func(ok bool) {
 				if !ok {
 					l.Info("new project canceled at name prompt")
@@ -1957,7 +3596,7 @@ func Run(projectDir string) error {
 				}
 				name := strings.TrimSpace(nameEntry.Text)
 				if name == "" {
-					dialog.ShowInformation("New Project", "Please enter a project name.", w)
+					dialog.ShowInformation(i18n.T("msg.new_project"), i18n.T("msg.please_enter_a_project_name"), w)
 					return
 				}
 				l.Info("creating project", slog.String("name", name), slog.String("root", abs))
@@ -1965,11 +3604,22 @@ func Run(projectDir string) error {
 				h, ierr := storage.InitProject(abs, proj)
 				if ierr != nil {
 					l.Error("init project failed", slog.Any("err", ierr))
-					dialog.ShowError(ierr, w)
+					dialog.ShowError(FriendlyError(ierr), w)
 					return
 				}
-				ph = h
+				storeProjectSettings()
+				_ = ed.Handle.Close()
+				ed.Handle = h
+				trackSyncChanges()
+				projSettings = storage.ProjectSettings{}
+				canvasWidget.Snap = projSettings.Snap
 				refreshReviewButtons()
+				refreshPresetMenu()
+				restartAssetsWatcher()
+				restartIndexCheck()
+				restartPresence()
+				restartSync()
+				showValidation()
 				// Apply template selection
 				tmpl := templateSelect.Selected
 				if tmpl == "3x3 Grid" {
@@ -1986,19 +3636,20 @@ func Run(projectDir string) error {
 					for i, n := range nodes {
 						r := n.Bounds()
 						pg.Panels = append(pg.Panels, domain.Panel{
-							ID:       fmt.Sprintf("p%d", i+1),
+							ID:       storage.NewPanelID(ed.Handle),
 							Geometry: domain.Rect{X: float64(r.X), Y: float64(r.Y), Width: float64(r.W), Height: float64(r.H)},
 							ZOrder:   i,
 						})
 					}
 					issue.Pages = []domain.Page{pg}
-					ph.Project.Issues = []domain.Issue{issue}
-					if err := storage.Save(ph); err != nil {
+					ed.Handle.Project.Issues = []domain.Issue{issue}
+					projSettings.GridPreset = "3x3"
+					if err := storage.Save(ed.Handle); err != nil {
 						l.Error("save after template failed", slog.Any("err", err))
 					}
 				}
-				w.SetTitle(fmt.Sprintf("Go Comic Writer — %s", h.Project.Name))
-				status.SetText(fmt.Sprintf("Created project: %s", abs))
+				w.SetTitle(i18n.T("title.go_comic_writer_project", h.Project.Name))
+				status.SetText(i18n.T("status.created_project", abs))
 				// Enable Close Project now that a project is open
 				closeProjItem.Disabled = false
 				// Clear any existing script in the editor for a fresh start
@@ -2008,17 +3659,18 @@ func Run(projectDir string) error {
 				updateOutline("")
 				refreshBible()
 				// If an issue was created by template, apply it; otherwise prompt setup
-				if len(ph.Project.Issues) > 0 {
-					canvasWidget.ApplyIssue(ph.Project.Issues[0])
-					currentIssueIdx = 0
-					currentPageIdx = 0
+				if len(ed.Handle.Project.Issues) > 0 {
+					canvasWidget.ApplyIssue(ed.Handle.Project.Issues[0])
+					ed.IssueIdx = 0
+					ed.PageIdx = 0
 					refreshPagesList()
 					refreshPanelsUI()
 					refreshAssets()
 				} else {
-					showIssueSetupDialog(w, ph, canvasWidget, status, l)
+					showIssueSetupDialog(w, ed.Handle, canvasWidget, status, l)
 				}
 				addRecentProject(prefs, abs)
+				refreshRecent()
 				showEditor()
 			}, w)
 			form.Show()
@@ -2026,7 +3678,88 @@ func Run(projectDir string) error {
 		fd.Show()
 	})
 
-	openItem := fyne.NewMenuItem("Open…", func() {
+	// checkCrashRecovery prunes stale crash autosaves and offers to restore newer ones.
+	checkCrashRecovery := func() {
+		if ed.Handle == nil {
+			return
+		}
+		offerCrashRecovery(w, ed.Handle, appCfg.Backups.CrashRetention(), l, status, func() {
+			if len(ed.Handle.Project.Issues) > 0 {
+				canvasWidget.ApplyIssue(ed.Handle.Project.Issues[0])
+				ed.IssueIdx = 0
+				ed.PageIdx = 0
+				refreshPagesList()
+			}
+			refreshPanelsUI()
+			refreshBible()
+		})
+	}
+
+	// checkIndexVersion opens the index of the just opened project in the background and offers to
+	// rebuild it when a newer app version wrote it.
+	checkIndexVersion := func() {
+		if ed.Handle == nil {
+			return
+		}
+		go func(ph *storage.ProjectHandle) {
+			err := ph.Index().Check()
+			fyne.Do(func() {
+				if ed.Handle == ph {
+					offerIndexReset(w, ph, err, l, status, nil)
+				}
+			})
+		}(ed.Handle)
+	}
+
+	// openProjectFolder opens the project at abs and loads it into the editor
+	openProjectFolder := func(abs string) {
+		storeProjectSettings()
+		if err := openProject(abs, &ed.Handle, w, l, status); err != nil {
+			l.Error("open project failed", slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+		} else {
+			trackSyncChanges()
+			checkCrashRecovery()
+			checkIndexVersion()
+		}
+		// Load script text after successful open
+		if ed.Handle != nil {
+			if txt, rerr := storage.ReadScript(ed.Handle); rerr == nil {
+				scriptEntry.SetText(txt)
+				lastScriptSnapText = txt
+				lastScriptSnapTS = time.Now()
+				updateOutline(txt)
+				refreshBible()
+				if len(ed.Handle.Project.Issues) > 0 {
+					canvasWidget.ApplyIssue(ed.Handle.Project.Issues[0])
+					// initialize pages list and select first page
+					ed.IssueIdx = 0
+					ed.PageIdx = 0
+					refreshPagesList()
+					refreshPanelsUI()
+					refreshAssets()
+					refreshReviewButtons()
+				}
+				applyProjectSettings()
+				refreshPresetMenu()
+				restartAssetsWatcher()
+				restartIndexCheck()
+				restartPresence()
+				restartSync()
+				showValidation()
+				offerPageNumberRepair(false)
+				l.Info("project opened", slog.String("name", ed.Handle.Project.Name))
+				// Enable Close Project as a project is now open
+				closeProjItem.Disabled = false
+				addRecentProject(prefs, abs)
+				refreshRecent()
+				showEditor()
+			} else {
+				l.Error("read script failed", slog.Any("err", rerr))
+			}
+		}
+	}
+	openItem := fyne.NewMenuItem(i18n.T("menu.open"), func() {
 		l.Info("menu: open project")
 		fd := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
 			if err != nil {
@@ -2039,78 +3772,85 @@ func Run(projectDir string) error {
 			}
 			abs := uri.Path()
 			l.Info("open project folder selected", slog.String("root", abs))
-			if err := openProject(abs, &ph, w, l, status); err != nil {
-				l.Error("open project failed", slog.Any("err", err))
-				dialog.ShowError(err, w)
+			openProjectFolder(abs)
+		}, w)
+		fd.Show()
+	})
+	// locateRecentProject asks where the missing recent project at old went, stores the new path in its
+	// place and opens it.
+	locateRecentProject := func(old string) {
+		l.Info("locate recent project", slog.String("path", old))
+		fd := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
 			}
-			// Load script text after successful open
-			if ph != nil {
-				if txt, rerr := storage.ReadScript(ph); rerr == nil {
-					scriptEntry.SetText(txt)
-					lastScriptSnapText = txt
-					lastScriptSnapTS = time.Now()
-					updateOutline(txt)
-					refreshBible()
-					if len(ph.Project.Issues) > 0 {
-						canvasWidget.ApplyIssue(ph.Project.Issues[0])
-						// initialize pages list and select first page
-						currentIssueIdx = 0
-						currentPageIdx = 0
-						refreshPagesList()
-						refreshPanelsUI()
-						refreshAssets()
-						refreshReviewButtons()
-					}
-					l.Info("project opened", slog.String("name", ph.Project.Name))
-					// Enable Close Project as a project is now open
-					closeProjItem.Disabled = false
-					addRecentProject(prefs, abs)
-					showEditor()
-				} else {
-					l.Error("read script failed", slog.Any("err", rerr))
-				}
+			if uri == nil {
+				return
+			}
+			abs := uri.Path()
+			if _, err := os.Stat(filepath.Join(abs, storage.ManifestFileName)); err != nil {
+				dialog.ShowError(FriendlyError(fmt.Errorf("%w: %s: %w", storage.ErrNotAProject, abs, err)), w)
+				return
 			}
+			loadRecentList(prefs).relocate(old, abs).save(prefs)
+			l.Info("recent project relocated", slog.String("from", old), slog.String("to", abs))
+			refreshRecent()
+			openProjectFolder(abs)
 		}, w)
 		fd.Show()
-	})
-	saveItem := fyne.NewMenuItem("Save", func() {
+	}
+	openRecentItem := fyne.NewMenuItem(i18n.T("menu.open_recent"), nil)
+	openRecentItem.ChildMenu = fyne.NewMenu("")
+	saveItem := fyne.NewMenuItem(i18n.T("msg.save"), func() {
 		l.Info("menu: save")
-		if ph == nil {
-			dialog.ShowInformation("Save", "No project open.", w)
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.save"), i18n.T("msg.no_project_open"), w)
 			return
 		}
-		if err := storage.Save(ph); err != nil {
+		if err := storage.Save(ed.Handle); err != nil {
 			l.Error("save failed", slog.Any("err", err))
-			dialog.ShowError(err, w)
+			dialog.ShowError(FriendlyError(err), w)
 			return
 		}
-		if err := storage.WriteScript(ph, scriptEntry.Text); err != nil {
+		if err := storage.WriteScript(ed.Handle, scriptEntry.Text()); err != nil {
 			l.Error("save script failed", slog.Any("err", err))
-			dialog.ShowError(err, w)
+			dialog.ShowError(FriendlyError(err), w)
 			return
 		}
-		l.Info("save completed", slog.String("manifest", ph.ManifestPath))
-		status.SetText("Saved project (manifest + script).")
+		l.Info("save completed", slog.String("manifest", ed.Handle.ManifestPath))
+		status.SetText(i18n.T("status.saved_project_manifest_script"))
 	})
-	closeProjItem = fyne.NewMenuItem("Close Project", func() {
-		if ph == nil {
+	closeProjItem = fyne.NewMenuItem(i18n.T("menu.close_project"), func() {
+		if ed.Handle == nil {
 			return
 		}
 		l.Info("menu: close project")
+		storeProjectSettings()
 		// Clear project state and UI without closing the window
-		ph = nil
+		syncWorker.Close()
+		_ = ed.Handle.Close()
+		ed.Handle = nil
 		refreshReviewButtons()
-		w.SetTitle("Go Comic Writer")
-		status.SetText("Project closed.")
+		refreshPresetMenu()
+		restartAssetsWatcher()
+		restartIndexCheck()
+		restartPresence()
+		restartSync()
+		showValidation()
+		w.SetTitle(i18n.T("title.go_comic_writer"))
+		status.SetText(i18n.T("status.project_closed"))
 		// Clear editors and lists
 		scriptEntry.SetText("")
 		lastScriptSnapText = ""
 		lastScriptSnapTS = time.Now()
 		updateOutline("")
 		panelFilter = ""
+		panelArtFilter = ""
 		panelIDs = panelIDs[:0]
 		panelDisplay = panelDisplay[:0]
-		selectedPanel = -1
+		ed.ClearSelection()
+		refreshBalloonList()
 		panelList.Refresh()
 		pacingLabel.SetText("")
 		// Clear canvas content
@@ -2119,6 +3859,7 @@ func Run(projectDir string) error {
 		canvasWidget.Refresh()
 		// Disable this menu entry as no project is open now
 		closeProjItem.Disabled = true
+		refreshRecent()
 		showDashboard()
 	})
 	// Initially disabled when no project is open
@@ -2136,60 +3877,62 @@ func Run(projectDir string) error {
 		root.Refresh()
 	}
 	buildDashboard := func() fyne.CanvasObject {
-		title := widget.NewLabel("Project Dashboard")
+		title := widget.NewLabel(i18n.T("label.project_dashboard"))
 		title.TextStyle = fyne.TextStyle{Bold: true}
 		title.Alignment = fyne.TextAlignLeading
 
-		newBtn := widget.NewButton("New Project…", func() { newItem.Action() })
-		openBtn := widget.NewButton("Open Project…", func() { openItem.Action() })
+		newBtn := widget.NewButton(i18n.T("button.new_project"), func() { newItem.Action() })
+		openBtn := widget.NewButton(i18n.T("button.open_project"), func() { openItem.Action() })
 
-		recent := loadRecentProjects(prefs)
+		recent := loadRecentList(prefs).entries()
 		recList := widget.NewList(
 			func() int { return len(recent) },
-			func() fyne.CanvasObject { return widget.NewLabel("") },
+			func() fyne.CanvasObject {
+				return container.NewBorder(nil, nil, nil, container.NewHBox(widget.NewButton("", nil), widget.NewButton("", nil)), widget.NewLabel(""))
+			},
 			func(i widget.ListItemID, o fyne.CanvasObject) {
-				if i >= 0 && int(i) < len(recent) {
-					o.(*widget.Label).SetText(recent[i])
-				} else {
-					o.(*widget.Label).SetText("")
+				if i < 0 || int(i) >= len(recent) {
+					return
+				}
+				e := recent[i]
+				c := o.(*fyne.Container)
+				lbl := c.Objects[0].(*widget.Label)
+				btns := c.Objects[1].(*fyne.Container)
+				locateBtn, pinBtn := btns.Objects[0].(*widget.Button), btns.Objects[1].(*widget.Button)
+				lbl.SetText(e.Path)
+				lbl.Importance = widget.MediumImportance
+				locateBtn.Hide()
+				if e.Missing {
+					lbl.SetText(i18n.T("label.recent_missing", e.Path))
+					lbl.Importance = widget.LowImportance
+					locateBtn.SetText(i18n.T("button.locate"))
+					locateBtn.OnTapped = func() { locateRecentProject(e.Path) }
+					locateBtn.Show()
+				}
+				lbl.Refresh()
+				pinBtn.SetText(i18n.T("button.pin"))
+				if e.Pinned {
+					pinBtn.SetText(i18n.T("button.unpin"))
+				}
+				pinBtn.OnTapped = func() {
+					loadRecentList(prefs).pin(e.Path, !e.Pinned).save(prefs)
+					refreshRecent()
 				}
 			},
 		)
 		recList.OnSelected = func(id widget.ListItemID) {
+			recList.UnselectAll()
 			if id < 0 || int(id) >= len(recent) {
 				return
 			}
-			path := recent[id]
-			if err := openProject(path, &ph, w, l, status); err != nil {
-				dialog.ShowError(err, w)
-				return
-			}
-			// Load script text after successful open
-			if ph != nil {
-				if txt, rerr := storage.ReadScript(ph); rerr == nil {
-					scriptEntry.SetText(txt)
-					lastScriptSnapText = txt
-					lastScriptSnapTS = time.Now()
-					updateOutline(txt)
-					refreshBible()
-					if len(ph.Project.Issues) > 0 {
-						canvasWidget.ApplyIssue(ph.Project.Issues[0])
-						currentIssueIdx = 0
-						currentPageIdx = 0
-						refreshPagesList()
-						refreshPanelsUI()
-						refreshReviewButtons()
-					}
-					closeProjItem.Disabled = false
-					addRecentProject(prefs, path)
-					showEditor()
-				} else {
-					l.Error("read script failed", slog.Any("err", rerr))
-				}
+			if e := recent[id]; e.Missing {
+				locateRecentProject(e.Path)
+			} else {
+				openProjectFolder(e.Path)
 			}
 		}
 
-		header := widget.NewLabel("Recent Projects")
+		header := widget.NewLabel(i18n.T("label.recent_projects"))
 		return container.NewBorder(
 			container.NewVBox(title, widget.NewSeparator(), container.NewHBox(newBtn, openBtn)),
 			nil, nil, nil,
@@ -2204,49 +3947,75 @@ func Run(projectDir string) error {
 		root.Refresh()
 	}
 
-	homeItem := fyne.NewMenuItem("Home", func() { showDashboard() })
+	homeItem := fyne.NewMenuItem(i18n.T("menu.home"), func() { showDashboard() })
 
-	rebuildIndexItem := fyne.NewMenuItem("Rebuild Index", func() {
-		if ph == nil {
+	rebuildIndexItem := fyne.NewMenuItem(i18n.T("menu.rebuild_index"), func() {
+		if ed.Handle == nil {
 			l.Info("menu: rebuild index (no project)")
-			dialog.ShowInformation("Rebuild Index", "No project open.", w)
+			dialog.ShowInformation(i18n.T("menu.rebuild_index"), i18n.T("msg.no_project_open"), w)
 			return
 		}
 		l.Info("menu: rebuild index")
-		status.SetText("Rebuilding index…")
-		go func(h *storage.ProjectHandle) {
+		status.SetText(i18n.T("status.rebuilding_index"))
+		go func(ix *storage.IndexHandle, proj domain.Project) {
 			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 			defer cancel()
-			err := storage.RebuildIndex(ctx, h.Root, h.Project)
+			err := ix.RebuildIndex(ctx, proj)
 			fyne.Do(func() {
-				if err != nil {
+				if offerIndexReset(w, ed.Handle, err, l, status, nil) {
+					return
+				}
+				if err != nil {
 					l.Error("rebuild index failed", slog.Any("err", err))
-					dialog.ShowError(err, w)
-					status.SetText("Rebuild failed.")
+					dialog.ShowError(FriendlyError(err), w)
+					status.SetText(i18n.T("status.rebuild_failed"))
 				} else {
-					status.SetText("Index rebuilt.")
-					dialog.ShowInformation("Rebuild Index", "Index rebuilt successfully.", w)
+					status.SetText(i18n.T("status.index_rebuilt"))
+					dialog.ShowInformation(i18n.T("menu.rebuild_index"), i18n.T("msg.index_rebuilt_successfully"), w)
 				}
 			})
-		}(ph)
+		}(ed.Handle.Index(), ed.Handle.Project)
+	})
+
+	metadataItem := fyne.NewMenuItem(i18n.T("menu.project_metadata"), func() {
+		if ed.Handle == nil {
+			l.Info("menu: project metadata (no project)")
+			dialog.ShowInformation(i18n.T("msg.project_metadata"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		l.Info("menu: project metadata")
+		showProjectMetadataDialog(w, ed.Handle, ed.IssueIdx, l, status)
+	})
+
+	maintenanceItem := fyne.NewMenuItem(i18n.T("menu.project_maintenance"), func() {
+		if ed.Handle == nil {
+			l.Info("menu: project maintenance (no project)")
+			dialog.ShowInformation(i18n.T("msg.project_maintenance"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		l.Info("menu: project maintenance")
+		showMaintenanceDialog(w, ed.Handle, l, status)
 	})
 
-	searchItem := fyne.NewMenuItem("Search…", func() {
-		if ph == nil {
+	searchItem := fyne.NewMenuItem(i18n.T("menu.search"), func() {
+		if ed.Handle == nil {
 			l.Info("menu: search (no project)")
-			dialog.ShowInformation("Search", "No project open.", w)
+			dialog.ShowInformation(i18n.T("msg.search"), i18n.T("msg.no_project_open"), w)
 			return
 		}
 		qEntry := widget.NewEntry()
-		qEntry.SetPlaceHolder("Search terms (FTS5; use quotes for phrases)")
+		qEntry.SetPlaceHolder(i18n.T("placeholder.search_terms_use_quotes_for_phrases"))
 		fromEntry := widget.NewEntry()
-		fromEntry.SetPlaceHolder("From page #")
+		fromEntry.SetPlaceHolder(i18n.T("placeholder.from_page"))
 		toEntry := widget.NewEntry()
-		toEntry.SetPlaceHolder("To page #")
-		form := dialog.NewForm("Search", "Run", "Cancel", []*widget.FormItem{
-			widget.NewFormItem("Query", qEntry),
-			widget.NewFormItem("Page From", fromEntry),
-			widget.NewFormItem("Page To", toEntry),
+		toEntry.SetPlaceHolder(i18n.T("placeholder.to_page"))
+		typeSelect := widget.NewSelect(searchTypeOptions(), nil)
+		typeSelect.SetSelectedIndex(0)
+		form := dialog.NewForm(i18n.T("msg.search"), i18n.T("msg.run"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.T("form.query"), qEntry),
+			widget.NewFormItem(i18n.T("form.search_type"), typeSelect),
+			widget.NewFormItem(i18n.T("form.page_from"), fromEntry),
+			widget.NewFormItem(i18n.T("form.page_to"), toEntry),
 		}, func(ok bool) {
 			if !ok {
 				return
@@ -2262,19 +4031,24 @@ func Run(projectDir string) error {
 					pto = v
 				}
 			}
-			status.SetText("Searching…")
-			go func(h *storage.ProjectHandle, sq storage.SearchQuery) {
+			status.SetText(i18n.T("status.searching"))
+			go func(ix *storage.IndexHandle, sq storage.SearchQuery) {
 				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 				defer cancel()
-				res, err := storage.Search(ctx, h.Root, sq)
+				res, err := ix.Search(ctx, sq)
 				fyne.Do(func() {
 					if err != nil {
+						if msg, ok := searchSyntaxMessage(err); ok {
+							dialog.ShowInformation(i18n.T("msg.search"), msg, w)
+							status.SetText(msg)
+							return
+						}
 						l.Error("search failed", slog.Any("err", err))
-						dialog.ShowError(err, w)
-						status.SetText("Search failed.")
+						dialog.ShowError(FriendlyError(err), w)
+						status.SetText(i18n.T("status.search_failed"))
 						return
 					}
-					status.SetText(fmt.Sprintf("%d results", len(res)))
+					status.SetText(i18n.N("status.results", len(res)))
 					items := make([]string, len(res))
 					for i, r := range res {
 						page := "-"
@@ -2285,9 +4059,7 @@ func Run(projectDir string) error {
 						if sn == "" {
 							sn = r.Path
 						}
-						if len(sn) > 120 {
-							sn = sn[:120] + "…"
-						}
+						sn = textutil.TruncateRunes(sn, 120, "…")
 						items[i] = fmt.Sprintf("p.%s — %s — %s", page, r.Type, sn)
 					}
 					list := widget.NewList(func() int { return len(items) }, func() fyne.CanvasObject { return widget.NewLabel("") }, func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(items[i]) })
@@ -2305,39 +4077,38 @@ func Run(projectDir string) error {
 								break
 							}
 						}
-						if r.PageID > 0 && ph != nil && len(ph.Project.Issues) > 0 {
-							iss := ph.Project.Issues[0]
+						if r.PageID > 0 && ed.Handle != nil && len(ed.Handle.Project.Issues) > 0 {
+							iss := ed.Handle.Project.Issues[0]
 							for _, pg := range iss.Pages {
 								if pg.Number == r.PageID {
-									canvasWidget.ShowPanels(pg)
-									if panel != "" {
-										canvasWidget.HighlightPanelID(panel)
-									}
+									canvasWidget.ShowPage(iss, pg)
+									// Page notes hits have no panel; clear an earlier highlight
+									canvasWidget.HighlightPanelID(panel)
 									break
 								}
 							}
 						}
 					}
-					d := dialog.NewCustom("Search Results", "Close", container.NewMax(list), w)
+					d := dialog.NewCustom(i18n.T("label.search_results"), i18n.T("msg.close"), container.NewMax(list), w)
 					d.Resize(fyne.NewSize(700, 400))
 					d.Show()
 				})
-			}(ph, storage.SearchQuery{Text: strings.TrimSpace(qEntry.Text), PageFrom: pfrom, PageTo: pto})
+			}(ed.Handle.Index(), storage.SearchQuery{Text: strings.TrimSpace(qEntry.Text), Types: searchTypeFilter(typeSelect.Selected), PageFrom: pfrom, PageTo: pto})
 		}, w)
 		form.Resize(fyne.NewSize(600, 200))
 		form.Show()
 	})
 
 	// Style Pack manager menu items
-	importStylePackItem := fyne.NewMenuItem("Import Style Pack…", func() {
-		if ph == nil {
+	importStylePackItem := fyne.NewMenuItem(i18n.T("menu.import_style_pack"), func() {
+		if ed.Handle == nil {
 			l.Info("menu: import style pack (no project)")
-			dialog.ShowInformation("Import Style Pack", "No project open.", w)
+			dialog.ShowInformation(i18n.T("msg.import_style_pack"), i18n.T("msg.no_project_open"), w)
 			return
 		}
 		open := dialog.NewFileOpen(func(ur fyne.URIReadCloser, err error) {
 			if err != nil {
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
 			if ur == nil {
@@ -2345,72 +4116,344 @@ func Run(projectDir string) error {
 			}
 			path := ur.URI().Path()
 			_ = ur.Close()
-			installed, ierr := stylepack.InstallPack(ph.Root, path)
+			installed, ierr := stylepack.InstallPack(ed.Handle.Root, path)
 			if ierr != nil {
-				dialog.ShowError(ierr, w)
+				dialog.ShowError(FriendlyError(ierr), w)
+				return
+			}
+			msg := i18n.N("msg.installed_style_files", installed)
+			templates, terr := stylepack.ReadPackPageTemplates(path)
+			if terr != nil {
+				l.Warn("style pack page templates unreadable", slog.String("pack", path), slog.Any("err", terr))
+			}
+			balloons, berr := stylepack.ReadPackBalloonStyles(path)
+			if berr != nil {
+				l.Warn("style pack balloon styles unreadable", slog.String("pack", path), slog.Any("err", berr))
+			}
+			// Page templates and balloon styles go into the manifest, so they are only added on request.
+			// The balloon styles are offered last; text is what happened so far, shown when show is set.
+			offerBalloonStyles := func(text string, show bool) {
+				if len(balloons) == 0 {
+					if show {
+						dialog.ShowInformation(i18n.T("msg.import_style_pack"), text, w)
+					}
+					return
+				}
+				dialog.ShowConfirm(i18n.T("msg.import_style_pack"), i18n.N("msg.pack_balloon_styles", len(balloons), text, len(balloons), strings.Join(balloonStyleNames(balloons), ", ")), func(ok bool) {
+					if !ok || ed.Handle == nil {
+						if show {
+							dialog.ShowInformation(i18n.T("msg.import_style_pack"), text, w)
+						}
+						return
+					}
+					added, skipped := storage.MergeBalloonStyles(ed.Handle, balloons)
+					if len(added) > 0 {
+						if err := storage.Save(ed.Handle); err != nil {
+							dialog.ShowError(FriendlyError(err), w)
+							return
+						}
+					}
+					l.Info("style pack balloon styles added", slog.Int("added", len(added)), slog.Int("skipped", len(skipped)))
+					res := i18n.N("msg.added_balloon_styles", len(added))
+					if len(skipped) > 0 {
+						res += i18n.T("msg.skipped_balloon_styles", strings.Join(skipped, ", "))
+					}
+					dialog.ShowInformation(i18n.T("msg.import_style_pack"), res, w)
+				}, w)
+			}
+			if len(templates) == 0 {
+				offerBalloonStyles(msg, true)
 				return
 			}
-			dialog.ShowInformation("Import Style Pack", fmt.Sprintf("Installed %d files into styles/", installed), w)
+			dialog.ShowConfirm(i18n.T("msg.import_style_pack"), i18n.N("msg.pack_page_templates", len(templates), msg, len(templates), strings.Join(templateNames(templates), ", ")), func(ok bool) {
+				if !ok || ed.Handle == nil {
+					offerBalloonStyles(msg, false)
+					return
+				}
+				added, skipped := storage.MergePageTemplates(ed.Handle, templates)
+				if len(added) > 0 {
+					if err := storage.Save(ed.Handle); err != nil {
+						dialog.ShowError(FriendlyError(err), w)
+						return
+					}
+				}
+				l.Info("style pack page templates added", slog.Int("added", len(added)), slog.Int("skipped", len(skipped)))
+				text := i18n.N("msg.added_page_templates", len(added))
+				if len(skipped) > 0 {
+					text += i18n.T("msg.skipped_page_templates", strings.Join(skipped, ", "))
+				}
+				offerBalloonStyles(text, true)
+			}, w)
 		}, w)
 		open.SetFilter(fstorage.NewExtensionFileFilter([]string{".zip"}))
 		open.Show()
 	})
-	exportStylePackItem := fyne.NewMenuItem("Export Styles as Pack…", func() {
-		if ph == nil {
+	exportStylePackItem := fyne.NewMenuItem(i18n.T("menu.export_styles_as_pack"), func() {
+		if ed.Handle == nil {
 			l.Info("menu: export style pack (no project)")
-			dialog.ShowInformation("Export Style Pack", "No project open.", w)
+			dialog.ShowInformation(i18n.T("msg.export_style_pack"), i18n.T("msg.no_project_open"), w)
 			return
 		}
-		save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
-			if err != nil {
-				dialog.ShowError(err, w)
+		exportPack := func(opts stylepack.ExportOptions) {
+			save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+				if err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				if uc == nil {
+					return
+				}
+				outPath := uc.URI().Path()
+				_ = uc.Close()
+				if !strings.HasSuffix(strings.ToLower(outPath), ".zip") {
+					outPath += ".zip"
+				}
+				if err := stylepack.ExportProjectStylesWith(ed.Handle.Root, outPath, opts); err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				dialog.ShowInformation(i18n.T("msg.export_style_pack"), i18n.T("msg.exported_to", outPath), w)
+			}, w)
+			save.SetFileName("styles-pack.zip")
+			save.SetFilter(fstorage.NewExtensionFileFilter([]string{".zip"}))
+			save.Show()
+		}
+		templates, balloons := ed.Handle.Project.PageTemplates, ed.Handle.Project.BalloonStyles
+		if len(templates) == 0 && len(balloons) == 0 {
+			exportPack(stylepack.ExportOptions{})
+			return
+		}
+		prompt := i18n.N("msg.include_page_templates", len(templates))
+		switch {
+		case len(templates) == 0:
+			prompt = i18n.N("msg.include_balloon_styles", len(balloons))
+		case len(balloons) > 0:
+			prompt = i18n.T("msg.include_page_templates_and_balloon_styles", i18n.N("label.page_templates", len(templates)), i18n.N("label.balloon_styles", len(balloons)))
+		}
+		ask := dialog.NewConfirm(i18n.T("msg.export_style_pack"), prompt, func(include bool) {
+			opts := stylepack.ExportOptions{}
+			if include {
+				opts.PageTemplates = templates
+				opts.BalloonStyles = balloons
+			}
+			exportPack(opts)
+		}, w)
+		ask.SetConfirmText(i18n.T("button.include"))
+		ask.SetDismissText(i18n.T("button.styles_only"))
+		ask.Show()
+	})
+	// Import Pages from Images: one new page per image of a folder or .cbz, with the image as page art
+	importPagesItem := fyne.NewMenuItem(i18n.T("menu.import_pages_from_images"), func() {
+		if ed.Handle == nil || len(ed.Handle.Project.Issues) == 0 {
+			l.Info("menu: import pages (no project)")
+			dialog.ShowInformation(i18n.T("msg.import_pages_from_images"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		issIdx := ed.IssueIdx
+		showImportPages(w, ed.Handle, issIdx, l, status, func() {
+			ed.PushUndo(i18n.T("undo.import_pages"))
+		}, func(first int) {
+			iss := ed.Handle.Project.Issues[issIdx]
+			canvasWidget.ApplyIssue(iss)
+			ed.IssueIdx = issIdx
+			if i := slices.IndexFunc(iss.Pages, func(pg domain.Page) bool { return pg.Number == first }); i >= 0 {
+				ed.PageIdx = i
+			}
+			refreshPagesList()
+			refreshPanelsUI()
+		})
+	})
+
+	backupsItem := fyne.NewMenuItem(i18n.T("menu.backups"), func() {
+		if ed.Handle == nil {
+			l.Info("menu: backups (no project)")
+			dialog.ShowInformation(i18n.T("msg.backups"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		l.Info("menu: backups")
+		showBackupsDialog(w, ed.Handle, l, status, func() {
+			if len(ed.Handle.Project.Issues) > 0 {
+				canvasWidget.ApplyIssue(ed.Handle.Project.Issues[0])
+				ed.IssueIdx = 0
+				ed.PageIdx = 0
+				refreshPagesList()
+			}
+			refreshPanelsUI()
+			refreshBible()
+		})
+	})
+
+	compareBackupItem := fyne.NewMenuItem(i18n.T("menu.compare_with_backup"), func() {
+		if ed.Handle == nil {
+			l.Info("menu: compare with backup (no project)")
+			dialog.ShowInformation(i18n.T("msg.compare_with_backup"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		l.Info("menu: compare with backup")
+		showCompareBackup(w, ed.Handle, ed.IssueIdx, l, status)
+	})
+
+	// Project Health runs the integrity checks; its rows trigger the menu actions that fix them
+	projectHealthItem := fyne.NewMenuItem(i18n.T("menu.project_health"), func() {
+		if ed.Handle == nil {
+			l.Info("menu: project health (no project)")
+			dialog.ShowInformation(i18n.T("msg.project_health"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		l.Info("menu: project health")
+		showProjectHealthDialog(w, ed.Handle, l, status, map[storage.HealthAction]func(){
+			storage.HealthActionRebuildIndex:   rebuildIndexItem.Action,
+			storage.HealthActionRunMaintenance: maintenanceItem.Action,
+			storage.HealthActionOpenBackups:    backupsItem.Action,
+			storage.HealthActionReviewCrash:    checkCrashRecovery,
+		})
+	})
+	// Scan for Broken Asset Links lists missing and unused asset files; relinks are undoable and saved
+	scanAssetLinksItem := fyne.NewMenuItem(i18n.T("menu.scan_asset_links"), func() {
+		if ed.Handle == nil {
+			l.Info("menu: scan asset links (no project)")
+			dialog.ShowInformation(i18n.T("msg.asset_links"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		l.Info("menu: scan asset links")
+		h := ed.Handle
+		showAssetLinksDialog(w, h, l, status, func(label string, change func() (int, error)) (int, error) {
+			if ed.Handle != h {
+				return 0, nil
+			}
+			var n int
+			if err := ed.Change(func() (string, error) {
+				var err error
+				if n, err = change(); err != nil || n == 0 {
+					return "", err
+				}
+				return label, nil
+			}); err != nil || n == 0 {
+				return n, err
+			}
+			l.Info("asset links fixed", slog.Int("refs", n))
+			refreshPanelsUI()
+			return n, nil
+		})
+	})
+	auditLogItem := fyne.NewMenuItem(i18n.T("menu.audit_log"), func() {
+		if ed.Handle == nil {
+			l.Info("menu: audit log (no project)")
+			dialog.ShowInformation(i18n.T("msg.audit_log"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		l.Info("menu: audit log")
+		showAuditLogDialog(w, ed.Handle.Root)
+	})
+	exportArchiveItem := fyne.NewMenuItem(i18n.T("menu.export_project_archive"), func() {
+		if ed.Handle == nil {
+			l.Info("menu: export project archive (no project)")
+			dialog.ShowInformation(i18n.T("msg.export_project_archive"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		hint := widget.NewLabel(i18n.T("label.export_archive_hint"))
+		hint.Wrapping = fyne.TextWrapWord
+		backupCheck := widget.NewCheck(i18n.T("check.include_latest_backup"), nil)
+		dialog.ShowCustomConfirm(i18n.T("msg.export_project_archive"), i18n.T("msg.continue"), i18n.T("msg.cancel"), container.NewVBox(hint, backupCheck), func(ok bool) {
+			if !ok || ed.Handle == nil {
 				return
 			}
-			if uc == nil {
+			opts := storage.ArchiveOptions{IncludeLatestBackup: backupCheck.Checked}
+			save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+				if err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				if uc == nil {
+					return
+				}
+				outPath := uc.URI().Path()
+				_ = uc.Close()
+				if !strings.HasSuffix(strings.ToLower(outPath), storage.ArchiveExt) {
+					outPath += storage.ArchiveExt
+				}
+				if _, err := storage.ExportArchive(ed.Handle, outPath, opts); err != nil {
+					l.Error("export project archive failed", slog.String("path", outPath), slog.Any("err", err))
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				l.Info("project archive exported", slog.String("path", outPath))
+				dialog.ShowInformation(i18n.T("msg.export_project_archive"), i18n.T("msg.exported_to", outPath), w)
+			}, w)
+			save.SetFileName(archiveFileName(ed.Handle.Project.Name))
+			save.SetFilter(fstorage.NewExtensionFileFilter([]string{storage.ArchiveExt}))
+			save.Show()
+		}, w)
+	})
+	importArchiveItem := fyne.NewMenuItem(i18n.T("menu.import_project_archive"), func() {
+		l.Info("menu: import project archive")
+		open := dialog.NewFileOpen(func(ur fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
-			outPath := uc.URI().Path()
-			_ = uc.Close()
-			if !strings.HasSuffix(strings.ToLower(outPath), ".zip") {
-				outPath += ".zip"
+			if ur == nil {
+				return
 			}
-			if err := stylepack.ExportProjectStyles(ph.Root, outPath); err != nil {
-				dialog.ShowError(err, w)
+			src := ur.URI().Path()
+			_ = ur.Close()
+			// Check the archive before asking for a destination, so a broken file fails early.
+			if _, err := storage.ReadArchiveManifest(src); err != nil {
+				l.Error("read project archive failed", slog.String("path", src), slog.Any("err", err))
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
-			dialog.ShowInformation("Export Style Pack", "Exported to "+outPath, w)
+			fd := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+				if err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				if uri == nil {
+					return
+				}
+				dest := archiveImportDir(uri.Path(), src)
+				if _, err := storage.ImportArchive(src, dest); err != nil {
+					l.Error("import project archive failed", slog.String("path", src), slog.String("dest", dest), slog.Any("err", err))
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				l.Info("project archive imported", slog.String("path", src), slog.String("dest", dest))
+				openProjectFolder(dest)
+				status.SetText(i18n.T("status.imported_project_archive", filepath.Base(src), dest))
+			}, w)
+			info := dialog.NewInformation(i18n.T("msg.import_project_archive"), i18n.T("msg.choose_archive_destination"), w)
+			info.SetOnClosed(fd.Show)
+			info.Show()
 		}, w)
-		save.SetFileName("styles-pack.zip")
-		save.SetFilter(fstorage.NewExtensionFileFilter([]string{".zip"}))
-		save.Show()
+		open.SetFilter(fstorage.NewExtensionFileFilter([]string{storage.ArchiveExt}))
+		open.Show()
 	})
-
-	fileMenu := fyne.NewMenu("File", homeItem, newItem, openItem, saveItem, fyne.NewMenuItemSeparator(), searchItem, rebuildIndexItem, importStylePackItem, exportStylePackItem, fyne.NewMenuItemSeparator(), closeProjItem)
+	fileMenu := fyne.NewMenu(i18n.T("menu.file"), homeItem, newItem, openItem, openRecentItem, saveItem, backupsItem, compareBackupItem, metadataItem, fyne.NewMenuItemSeparator(), searchItem, rebuildIndexItem, maintenanceItem, projectHealthItem, scanAssetLinksItem, auditLogItem, importPagesItem, importStylePackItem, exportStylePackItem, exportArchiveItem, importArchiveItem, fyne.NewMenuItemSeparator(), closeProjItem)
 
 	// Settings dialog and menu item
 	showSettingsDialog := func() {
 		// Helper to suffix labels when an env override is present
 		withOverride := func(label, env string) string {
 			if strings.TrimSpace(os.Getenv(env)) != "" {
-				return label + " (overridden by " + env + ")"
+				return i18n.T("form.overridden_by", label, env)
 			}
 			return label
 		}
 
-		baseLabel := "Base URL"
+		baseLabel := i18n.T("form.base_url")
 		if env, ok := config.EnvOverrideFor("backend.base_url"); ok {
-			baseLabel += " (overridden by " + env + ")"
+			baseLabel = i18n.T("form.overridden_by", baseLabel, env)
 		}
 		baseURLEntry := widget.NewEntry()
 		baseURLEntry.SetText(appCfg.Backend.BaseURL)
 		timeoutEntry := widget.NewEntry()
 		timeoutEntry.SetText(fmt.Sprintf("%d", appCfg.Backend.TimeoutMs))
-		tlsChk := widget.NewCheck("Allow insecure TLS (skip certificate verification)", nil)
+		tlsChk := widget.NewCheck(i18n.T("check.allow_insecure_tls_skip_certificate_verification"), nil)
 		tlsChk.SetChecked(appCfg.Backend.TLSInsecure)
-		teleChk := widget.NewCheck("Enable anonymous telemetry (opt-in)", nil)
+		teleChk := widget.NewCheck(i18n.T("check.enable_anonymous_telemetry_opt_in"), nil)
 		teleChk.SetChecked(appCfg.General.TelemetryOptIn)
 		tokenEntry := widget.NewPasswordEntry()
-		tokenEntry.SetPlaceHolder("Access token (leave blank to keep stored token)")
+		tokenEntry.SetPlaceHolder(i18n.T("placeholder.access_token_leave_blank_to_keep"))
 
 		// Logging configuration (GCW_LOG_*) with env overrides; persist user-selected values to config
 		levels := []string{"debug", "info", "warn", "error"}
@@ -2435,7 +4478,7 @@ func Run(projectDir string) error {
 		}
 		logFormatSelect := widget.NewSelect(formats, nil)
 		logFormatSelect.SetSelected(logFormat)
-		logSourceChk := widget.NewCheck("Include source in logs", nil)
+		logSourceChk := widget.NewCheck(i18n.T("check.include_source_in_logs"), nil)
 		if v := strings.TrimSpace(os.Getenv("GCW_LOG_SOURCE")); v != "" {
 			ls := strings.ToLower(v)
 			logSourceChk.SetChecked(ls == "1" || ls == "true" || ls == "on" || ls == "yes")
@@ -2443,7 +4486,7 @@ func Run(projectDir string) error {
 			logSourceChk.SetChecked(appCfg.Logging.Source)
 		}
 		logFileEntry := widget.NewEntry()
-		logFileEntry.SetPlaceHolder("Path to log file (optional)")
+		logFileEntry.SetPlaceHolder(i18n.T("placeholder.path_to_log_file_optional"))
 		if v := strings.TrimSpace(os.Getenv("GCW_LOG_FILE")); v != "" {
 			logFileEntry.SetText(v)
 		} else {
@@ -2455,11 +4498,11 @@ func Run(projectDir string) error {
 		if cgoVal == "" {
 			cgoVal = "(unset)"
 		}
-		cgoLabel := widget.NewLabel("CGO_ENABLED = " + cgoVal + " — build-time/env; read-only")
+		cgoLabel := widget.NewLabel(i18n.T("label.cgo_enabled_build_time_env_read", cgoVal))
 
 		// Test connection button
 		resultLabel := widget.NewLabel("")
-		testBtn := widget.NewButton("Test connection", func() {
+		testBtn := widget.NewButton(i18n.T("button.test_connection"), func() {
 			url := strings.TrimSpace(baseURLEntry.Text)
 			tok := strings.TrimSpace(tokenEntry.Text)
 			if tok == "" {
@@ -2476,7 +4519,7 @@ func Run(projectDir string) error {
 			}
 			resp, err := hc.Do(req)
 			if err != nil {
-				resultLabel.SetText("Failed: " + err.Error())
+				resultLabel.SetText(i18n.T("status.failed", err.Error()))
 				return
 			}
 			defer resp.Body.Close()
@@ -2485,12 +4528,12 @@ func Run(projectDir string) error {
 			_ = dec.Decode(&body)
 			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 				if body.Status != "" {
-					resultLabel.SetText("OK: " + body.Status + " (" + body.Version + ")")
+					resultLabel.SetText(i18n.T("status.connection_ok_version", body.Status, body.Version))
 				} else {
-					resultLabel.SetText("OK: " + resp.Status)
+					resultLabel.SetText(i18n.T("status.connection_ok", resp.Status))
 				}
 			} else {
-				resultLabel.SetText("Failed: " + resp.Status)
+				resultLabel.SetText(i18n.T("status.failed", resp.Status))
 			}
 		})
 
@@ -2499,7 +4542,7 @@ func Run(projectDir string) error {
 			mkRow := func(name, note string) fyne.CanvasObject {
 				val := strings.TrimSpace(os.Getenv(name))
 				if val == "" {
-					val = "(unset)"
+					val = i18n.T("label.unset")
 				}
 				lbl := widget.NewLabel(name + " = " + val)
 				if note != "" {
@@ -2509,76 +4552,76 @@ func Run(projectDir string) error {
 			}
 			// Grouped containers
 			loggingBox := container.NewVBox(
-				widget.NewLabelWithStyle("Logging", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+				widget.NewLabelWithStyle(i18n.T("label.logging"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 				mkRow("GCW_LOG_LEVEL", ""),
 				mkRow("GCW_LOG_FORMAT", ""),
 				mkRow("GCW_LOG_SOURCE", ""),
 				mkRow("GCW_LOG_FILE", ""),
 			)
 			appBox := container.NewVBox(
-				widget.NewLabelWithStyle("Desktop app", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+				widget.NewLabelWithStyle(i18n.T("label.desktop_app"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 				mkRow("GCW_TELEMETRY_OPT_IN", ""),
 				mkRow("GCW_BACKEND_URL", ""),
 				mkRow("GCW_BACKEND_TIMEOUT_MS", ""),
 				mkRow("GCW_TLS_INSECURE", ""),
-				mkRow("GCW_ENABLE_SERVER", "Feature flag for Server menu"),
+				mkRow("GCW_ENABLE_SERVER", i18n.T("label.env_server_flag")),
 			)
 			teleBox := container.NewVBox(
-				widget.NewLabelWithStyle("Telemetry & crash", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-				mkRow("GCW_TELEMETRY_URL", "events endpoint"),
-				mkRow("GCW_CRASH_UPLOAD_URL", "crash upload endpoint"),
+				widget.NewLabelWithStyle(i18n.T("label.telemetry_crash"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+				mkRow("GCW_TELEMETRY_URL", i18n.T("label.env_events_endpoint")),
+				mkRow("GCW_CRASH_UPLOAD_URL", i18n.T("label.env_crash_endpoint")),
 				mkRow("GCW_TELEMETRY_TIMEOUT_MS", "ms"),
-				mkRow("GCW_TELEMETRY_DEBUG", "non-empty to enable debug"),
+				mkRow("GCW_TELEMETRY_DEBUG", i18n.T("label.env_debug")),
 			)
 			serverBox := container.NewVBox(
-				widget.NewLabelWithStyle("Server (gcwserver)", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-				mkRow("GCW_PG_DSN", "server-only"),
-				mkRow("DATABASE_URL", "server-only"),
-				mkRow("ADDR", "server-only"),
-				mkRow("PORT", "server-only"),
-				mkRow("GCW_TLS_ENABLE", "server-only"),
-				mkRow("GCW_TLS_CERT_FILE", "server-only"),
-				mkRow("GCW_TLS_KEY_FILE", "server-only"),
-				mkRow("GCW_AUTH_MODE", "server-only"),
-				mkRow("GCW_AUTH_SECRET", "server-only"),
-				mkRow("GCW_ADMIN_API_KEY", "server-only"),
-				mkRow("GCW_MINIO_ENDPOINT", "server-only"),
-				mkRow("GCW_OBJECT_HEALTH_URL", "server-only"),
-				mkRow("GCW_OBJECT_HEALTH_REQUIRED", "server-only"),
+				widget.NewLabelWithStyle(i18n.T("label.server_gcwserver"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+				mkRow("GCW_PG_DSN", i18n.T("label.env_server_only")),
+				mkRow("DATABASE_URL", i18n.T("label.env_server_only")),
+				mkRow("ADDR", i18n.T("label.env_server_only")),
+				mkRow("PORT", i18n.T("label.env_server_only")),
+				mkRow("GCW_TLS_ENABLE", i18n.T("label.env_server_only")),
+				mkRow("GCW_TLS_CERT_FILE", i18n.T("label.env_server_only")),
+				mkRow("GCW_TLS_KEY_FILE", i18n.T("label.env_server_only")),
+				mkRow("GCW_AUTH_MODE", i18n.T("label.env_server_only")),
+				mkRow("GCW_AUTH_SECRET", i18n.T("label.env_server_only")),
+				mkRow("GCW_ADMIN_API_KEY", i18n.T("label.env_server_only")),
+				mkRow("GCW_MINIO_ENDPOINT", i18n.T("label.env_server_only")),
+				mkRow("GCW_OBJECT_HEALTH_URL", i18n.T("label.env_server_only")),
+				mkRow("GCW_OBJECT_HEALTH_REQUIRED", i18n.T("label.env_server_only")),
 			)
 			cgoBox := container.NewVBox(
-				widget.NewLabelWithStyle("Build/toolchain", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-				mkRow("CGO_ENABLED", "build-time/env; UI requires cgo when using Fyne"),
+				widget.NewLabelWithStyle(i18n.T("label.build_toolchain"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+				mkRow("CGO_ENABLED", i18n.T("label.env_cgo")),
 			)
 			content := container.NewVScroll(container.NewVBox(loggingBox, widget.NewSeparator(), appBox, widget.NewSeparator(), teleBox, widget.NewSeparator(), serverBox, widget.NewSeparator(), cgoBox))
-			dialog.ShowCustom("Environment Variables", "Close", content, w)
+			dialog.ShowCustom(i18n.T("msg.environment_variables"), i18n.T("msg.close"), content, w)
 		}
-		envBtn := widget.NewButton("Environment variables…", showEnvOverview)
+		envBtn := widget.NewButton(i18n.T("button.environment_variables"), showEnvOverview)
 
 		// Feature flag: Server menu
-		serverChk := widget.NewCheck("Enable Server features (Server menu)", nil)
+		serverChk := widget.NewCheck(i18n.T("check.enable_server_features_server_menu"), nil)
 		serverChk.SetChecked(appCfg.General.EnableServer)
 
 		items := []*widget.FormItem{
 			// Backend
 			widget.NewFormItem(baseLabel, baseURLEntry),
-			widget.NewFormItem("Timeout (ms)", timeoutEntry),
-			widget.NewFormItem("TLS", tlsChk),
-			widget.NewFormItem(withOverride("Server features", "GCW_ENABLE_SERVER"), serverChk),
-			widget.NewFormItem("Telemetry", teleChk),
-			widget.NewFormItem("Access token", tokenEntry),
+			widget.NewFormItem(i18n.T("form.timeout_ms"), timeoutEntry),
+			widget.NewFormItem(i18n.T("form.tls"), tlsChk),
+			widget.NewFormItem(withOverride(i18n.T("form.server_features"), "GCW_ENABLE_SERVER"), serverChk),
+			widget.NewFormItem(i18n.T("form.telemetry"), teleChk),
+			widget.NewFormItem(i18n.T("form.access_token"), tokenEntry),
 			widget.NewFormItem("", container.NewHBox(testBtn, resultLabel)),
 			// Logging
-			widget.NewFormItem(withOverride("Log level", "GCW_LOG_LEVEL"), logLevelSelect),
-			widget.NewFormItem(withOverride("Log format", "GCW_LOG_FORMAT"), logFormatSelect),
-			widget.NewFormItem(withOverride("Log source", "GCW_LOG_SOURCE"), logSourceChk),
-			widget.NewFormItem(withOverride("Log file", "GCW_LOG_FILE"), logFileEntry),
+			widget.NewFormItem(withOverride(i18n.T("form.log_level"), "GCW_LOG_LEVEL"), logLevelSelect),
+			widget.NewFormItem(withOverride(i18n.T("form.log_format"), "GCW_LOG_FORMAT"), logFormatSelect),
+			widget.NewFormItem(withOverride(i18n.T("form.log_source"), "GCW_LOG_SOURCE"), logSourceChk),
+			widget.NewFormItem(withOverride(i18n.T("form.log_file"), "GCW_LOG_FILE"), logFileEntry),
 			// Toolchain
-			widget.NewFormItem("CGO", cgoLabel),
+			widget.NewFormItem(i18n.T("form.cgo"), cgoLabel),
 			// Environment overview
 			widget.NewFormItem("", envBtn),
 		}
-		d := dialog.NewForm("Settings", "Save", "Cancel", items, func(ok bool) {
+		d := dialog.NewForm(i18n.T("msg.settings"), i18n.T("msg.save"), i18n.T("msg.cancel"), items, func(ok bool) {
 			if !ok {
 				return
 			}
@@ -2596,7 +4639,7 @@ func Run(projectDir string) error {
 			appCfg.Logging.Source = logSourceChk.Checked
 			appCfg.Logging.File = strings.TrimSpace(logFileEntry.Text)
 			if err := config.Save(appCfg, strings.TrimSpace(tokenEntry.Text)); err != nil {
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
 			if strings.TrimSpace(tokenEntry.Text) != "" {
@@ -2632,226 +4675,906 @@ func Run(projectDir string) error {
 			}
 			applog.Init(applog.Options{Level: effectiveLevel, Format: effectiveFormat, AddSource: useSource, File: effectiveFile})
 
-			dialog.ShowInformation("Settings", "Saved.", w)
+			dialog.ShowInformation(i18n.T("msg.settings"), i18n.T("msg.saved"), w)
 		}, w)
 		d.Resize(fyne.NewSize(560, 0))
 		d.Show()
 	}
-	settingsItem := fyne.NewMenuItem("Settings…", func() { showSettingsDialog() })
+	settingsItem := fyne.NewMenuItem(i18n.T("menu.settings"), func() { showSettingsDialog() })
+	// The canvas follows the theme, also when the system switches between light and dark
+	refreshCanvasPalette := func() {
+		canvasWidget.SetPalette(canvasPaletteFor(appCfg.Canvas, appThemeIsDark(fyneApp, appCfg.General.Theme)))
+		refreshPanelsUI()
+	}
+	fyneApp.Settings().AddListener(func(fyne.Settings) { refreshCanvasPalette() })
+	refreshCanvasPalette()
+	preferencesItem := fyne.NewMenuItem(i18n.T("menu.preferences"), func() {
+		showPreferences(w, &appCfg, func() {
+			l.Info("preferences saved", slog.String("theme", appCfg.General.Theme), slog.Bool("high_contrast", appCfg.Canvas.HighContrast), slog.String("units", appCfg.General.Units))
+			applyTheme(fyneApp, appCfg.General.Theme)
+			setLengthUnit(appCfg.General.Units)
+			export.SetMaxPixels(appCfg.Export.MaxPixels)
+			refreshCanvasPalette()
+		})
+	})
 
 	// Edit menu (Undo/Redo)
-	undoMenuItem := fyne.NewMenuItem("Undo", func() {
-		if ph == nil {
-			dialog.ShowInformation("Undo", "No project open.", w)
+	applyUndo := func(title string, step func() (bool, error), nothing, done string) {
+		ok, err := step()
+		switch {
+		case errors.Is(err, controller.ErrNoProject):
+			dialog.ShowInformation(title, i18n.T("msg.no_project_open"), w)
+		case err != nil:
+			dialog.ShowError(FriendlyError(err), w)
+		case !ok:
+			dialog.ShowInformation(title, nothing, w)
+		default:
+			refreshPagesList()
+			refreshPanelsUI()
+			status.SetText(done)
+		}
+	}
+	undoMenuItem := fyne.NewMenuItem(i18n.T("menu.undo"), func() {
+		applyUndo(i18n.T("menu.undo"), ed.Undo, i18n.T("msg.nothing_to_undo"), i18n.T("status.undid_last_action"))
+		trimPersistedUndo()
+	})
+	redoMenuItem := fyne.NewMenuItem(i18n.T("menu.redo"), func() {
+		applyUndo(i18n.T("menu.redo"), ed.Redo, i18n.T("msg.nothing_to_redo"), i18n.T("status.redid_last_action"))
+	})
+	// Undo History lists the labeled undo steps and reverts several of them at once
+	undoHistoryItem := fyne.NewMenuItem(i18n.T("menu.undo_history"), func() {
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.undo_history"), i18n.T("msg.no_project_open"), w)
 			return
 		}
-		if s, ok := undoMgr.Undo(0); ok {
-			if err := applyIssueSnapshot(s.Blob); err != nil {
-				dialog.ShowError(err, w)
-				return
+		hist := ed.UndoHistory()
+		showUndoHistoryDialog(w, hist, func(index int) {
+			n := len(hist) - index
+			applyUndo(i18n.T("msg.undo_history"), func() (bool, error) { return ed.RevertTo(index) }, i18n.T("msg.nothing_to_undo"), i18n.N("status.reverted_undo_history", n, n))
+			trimPersistedUndo()
+			l.Info("undo history reverted", slog.Int("changes", n))
+		})
+	})
+	// Find in Script opens the find bar over the script editor, switching to the Script tab
+	findScriptItem := fyne.NewMenuItem(i18n.T("menu.find_in_script"), func() {
+		tabs.SelectIndex(2)
+		scriptFind.Open(w.Canvas())
+	})
+	findScriptItem.Shortcut = &desktop.CustomShortcut{KeyName: fyne.KeyF, Modifier: fyne.KeyModifierControl}
+	// Find and Replace in Project searches the lettering and notes of the whole project; a replace is
+	// one undo step and one save, which also brings the search index up to date
+	findReplaceItem := fyne.NewMenuItem(i18n.T("menu.find_replace_project"), func() {
+		if ed.Handle == nil {
+			l.Info("menu: find and replace in project (no project)")
+			dialog.ShowInformation(i18n.T("msg.find_replace_project"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		l.Info("menu: find and replace in project")
+		h := ed.Handle
+		showFindReplaceDialog(w, h, l, status, func(label string, change func() (storage.ReplaceSummary, error)) (storage.ReplaceSummary, error) {
+			if ed.Handle != h {
+				return storage.ReplaceSummary{}, nil
+			}
+			var sum storage.ReplaceSummary
+			if err := ed.Change(func() (string, error) {
+				var err error
+				if sum, err = change(); err != nil || sum.Replaced == 0 {
+					return "", err
+				}
+				return label, nil
+			}); err != nil || sum.Replaced == 0 {
+				return sum, err
 			}
-			status.SetText("Undid last action")
+			l.Info("replaced in project", slog.Int("occurrences", sum.Replaced), slog.Int("skipped", sum.Skipped))
+			refreshPanelsUI()
+			refreshBible()
+			return sum, nil
+		})
+	})
+	findReplaceItem.Shortcut = &desktop.CustomShortcut{KeyName: fyne.KeyF, Modifier: fyne.KeyModifierControl | fyne.KeyModifierShift}
+
+	// View menu: Reader Preview shows the pages as facing pairs, starting at the current page
+	// Panel Review shows the issue's panels one at a time for pacing checks; leaving it selects the
+	// panel viewed last. The auto-advance interval is remembered in the app preferences.
+	panelReviewItem := fyne.NewMenuItem(i18n.T("menu.panel_review"), func() {
+		iss := ed.Issue()
+		if iss == nil || !slices.ContainsFunc(iss.Pages, func(pg domain.Page) bool { return len(pg.Panels) > 0 }) {
+			dialog.ShowInformation(i18n.T("msg.panel_review"), i18n.T("msg.no_panels_to_review"), w)
+			return
+		}
+		var txt string
+		if scriptEntry != nil && scriptEntry.Text() != "" {
+			txt = scriptEntry.Text()
 		} else {
-			dialog.ShowInformation("Undo", "Nothing to undo.", w)
+			txt, _ = storage.ReadScript(ed.Handle)
+		}
+		start, sel := ed.PageIdx, ed.Selected
+		if i := slices.IndexFunc(iss.Pages, func(pg domain.Page) bool { return pg.Number == sel.Page }); i >= 0 && sel.PanelID != "" {
+			start = i
+		}
+		h, issIdx := ed.Handle, ed.IssueIdx
+		l.Info("menu: panel review", slog.Int("issue", issIdx+1))
+		showPanelReview(fyneApp, h, issIdx, start, sel.PanelID, panelReviewOptions{
+			Script:    txt,
+			Seconds:   prefs.FloatWithFallback("panel_review.seconds", 0),
+			OnSeconds: func(s float64) { prefs.SetFloat("panel_review.seconds", s) },
+			OnExit: func(pageIdx int, panelID string) {
+				if ed.Handle != h || ed.IssueIdx != issIdx || pageIdx >= len(ed.Handle.Project.Issues[issIdx].Pages) {
+					return
+				}
+				ed.PageIdx = pageIdx
+				refreshPagesList()
+				refreshPanelsUI()
+				selectEntity(controller.Selection{Page: ed.PageNumber(), PanelID: panelID})
+			},
+		})
+	})
+	readerPreviewItem := fyne.NewMenuItem(i18n.T("menu.reader_preview"), func() {
+		iss := ed.Issue()
+		if iss == nil || len(iss.Pages) == 0 {
+			dialog.ShowInformation(i18n.T("msg.reader_preview"), i18n.T("msg.open_a_project_with_pages_first"), w)
+			return
+		}
+		if iss.TrimWidth <= 0 || iss.TrimHeight <= 0 {
+			dialog.ShowInformation(i18n.T("msg.reader_preview"), i18n.T("msg.set_the_issue_s_trim_size"), w)
+			return
 		}
+		showReaderPreview(fyneApp, ed.Handle.Project, ed.IssueIdx, ed.PageIdx)
 	})
-	redoMenuItem := fyne.NewMenuItem("Redo", func() {
-		if ph == nil {
-			dialog.ShowInformation("Redo", "No project open.", w)
+	// Snapping settings of the page canvas, stored with the project
+	snappingItem := fyne.NewMenuItem(i18n.T("menu.snapping"), func() {
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.snapping"), i18n.T("msg.open_a_project_first_snapping_is"), w)
 			return
 		}
-		if s, ok := undoMgr.Redo(0); ok {
-			if err := applyIssueSnapshot(s.Blob); err != nil {
-				dialog.ShowError(err, w)
+		cur := projSettings.Snap
+		onCheck := widget.NewCheck(i18n.T("check.snap_moved_resized_and_drawn_panels"), nil)
+		onCheck.SetChecked(!cur.Off)
+		thresholdEntry := widget.NewEntry()
+		thresholdEntry.SetPlaceHolder(fmt.Sprintf("%d", storage.DefaultSnapThreshold))
+		if cur.Threshold > 0 {
+			thresholdEntry.SetText(strconv.FormatFloat(cur.Threshold, 'g', -1, 64))
+		}
+		u := units()
+		gridEntry := widget.NewEntry()
+		gridEntry.SetPlaceHolder(i18n.T("placeholder.no_grid"))
+		if cur.GridMM > 0 {
+			gridEntry.SetText(trimNumber(u.fromMM(cur.GridMM), u.decimals()+2))
+		}
+		panelsCheck := widget.NewCheck(i18n.T("check.other_panels"), nil)
+		panelsCheck.SetChecked(!cur.NoPanels)
+		trimCheck := widget.NewCheck(i18n.T("check.trim_box"), nil)
+		trimCheck.SetChecked(!cur.NoTrim)
+		edgesCheck := widget.NewCheck(i18n.T("check.edges"), nil)
+		edgesCheck.SetChecked(!cur.NoEdges)
+		centersCheck := widget.NewCheck(i18n.T("check.centers"), nil)
+		centersCheck.SetChecked(!cur.NoCenters)
+		dialog.ShowForm(i18n.T("msg.snapping"), i18n.T("msg.apply"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem("", onCheck),
+			widget.NewFormItem(i18n.T("form.distance_px"), thresholdEntry),
+			widget.NewFormItem(u.label("form.grid"), gridEntry),
+			widget.NewFormItem(i18n.T("form.snap_to"), container.NewHBox(panelsCheck, trimCheck)),
+			widget.NewFormItem(i18n.T("form.snap_panel"), container.NewHBox(edgesCheck, centersCheck)),
+		}, func(ok bool) {
+			if !ok || ed.Handle == nil {
 				return
 			}
-			status.SetText("Redid last action")
+			snap, err := parseSnapSettings(onCheck.Checked, thresholdEntry.Text, gridEntry.Text, panelsCheck.Checked, trimCheck.Checked, edgesCheck.Checked, centersCheck.Checked)
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			projSettings.Snap = snap
+			canvasWidget.Snap = snap
+			storeProjectSettings()
+			l.Info("snap settings changed", slog.Bool("off", snap.Off), slog.Float64("threshold", snap.Threshold), slog.Float64("grid_mm", snap.GridMM))
+			status.SetText(snapStatus(snap))
+		}, w)
+	})
+	quickOpenItem := fyne.NewMenuItem(i18n.T("menu.quick_open"), func() { openQuickOpen() })
+	quickOpenItem.Shortcut = &desktop.CustomShortcut{KeyName: fyne.KeyP, Modifier: fyne.KeyModifierControl}
+	var pacingItem *fyne.MenuItem
+	pacingItem = fyne.NewMenuItem(i18n.T("menu.pacing_panel"), func() {
+		if pacing.box.Visible() {
+			pacing.box.Hide()
 		} else {
-			dialog.ShowInformation("Redo", "Nothing to redo.", w)
+			pacing.box.Show()
+			refreshPacing()
 		}
+		pacingItem.Checked = pacing.box.Visible()
+		prefs.SetBool("view.pacing", pacingItem.Checked)
+		workspace.Arrange()
+		w.MainMenu().Refresh()
+	})
+	pacingItem.Checked = pacing.box.Visible()
+	// Balloon order numbers on the canvas
+	var balloonOrderItem *fyne.MenuItem
+	balloonOrderItem = fyne.NewMenuItem(i18n.T("menu.balloon_order_numbers"), func() {
+		canvasWidget.ShowBalloonOrder = !canvasWidget.ShowBalloonOrder
+		balloonOrderItem.Checked = canvasWidget.ShowBalloonOrder
+		prefs.SetBool("view.balloon_order", canvasWidget.ShowBalloonOrder)
+		canvasWidget.Refresh()
+		w.MainMenu().Refresh()
 	})
-	editMenu := fyne.NewMenu("Edit", undoMenuItem, redoMenuItem, fyne.NewMenuItemSeparator(), settingsItem)
+	canvasWidget.ShowBalloonOrder = prefs.BoolWithFallback("view.balloon_order", false)
+	balloonOrderItem.Checked = canvasWidget.ShowBalloonOrder
+	// Section toggles of the canvas tab (Ctrl+Shift+1 to 4); a checked item is a shown section
+	sectionItems := map[*fyne.MenuItem]*bool{}
+	var sectionMenu []*fyne.MenuItem
+	for _, sec := range []struct {
+		label  string
+		key    fyne.KeyName
+		hidden *bool
+	}{
+		{"menu.show_pages", fyne.Key1, &workspace.Layout.HidePages},
+		{"menu.show_inspector", fyne.Key2, &workspace.Layout.HideInspector},
+		{"menu.show_search_results", fyne.Key3, &workspace.Layout.HideResults},
+		{"menu.show_assets", fyne.Key4, &workspace.Layout.HideAssets},
+	} {
+		toggle := func() {
+			workspace.Toggle(sec.hidden)
+			workspace.OnChanged()
+		}
+		item := fyne.NewMenuItem(i18n.T(sec.label), toggle)
+		sc := &desktop.CustomShortcut{KeyName: sec.key, Modifier: fyne.KeyModifierControl | fyne.KeyModifierShift}
+		item.Shortcut = sc
+		w.Canvas().AddShortcut(sc, func(fyne.Shortcut) { toggle() })
+		sectionItems[item] = sec.hidden
+		sectionMenu = append(sectionMenu, item)
+	}
+	refreshWorkspaceMenu = func() {
+		for item, hidden := range sectionItems {
+			item.Checked = !*hidden
+		}
+		if mm := w.MainMenu(); mm != nil {
+			mm.Refresh()
+		}
+	}
+	refreshWorkspaceMenu()
+	viewMenu := fyne.NewMenu(i18n.T("menu.view"), append(append([]*fyne.MenuItem{quickOpenItem, fyne.NewMenuItemSeparator()}, sectionMenu...),
+		fyne.NewMenuItemSeparator(), readerPreviewItem, panelReviewItem, pacingItem, balloonOrderItem, snappingItem)...)
 
 	// Issue menu with setup dialog
-	issueSetupItem := fyne.NewMenuItem("Issue Setup…", func() {
-		if ph == nil {
+	issueSetupItem := fyne.NewMenuItem(i18n.T("menu.issue_setup"), func() {
+		if ed.Handle == nil {
 			l.Info("menu: issue setup (no project)")
-			dialog.ShowInformation("Issue Setup", "No project open.", w)
+			dialog.ShowInformation(i18n.T("msg.issue_setup"), i18n.T("msg.no_project_open"), w)
 			return
 		}
 		l.Info("menu: issue setup")
-		showIssueSetupDialog(w, ph, canvasWidget, status, l)
+		showIssueSetupDialog(w, ed.Handle, canvasWidget, status, l)
 	})
+	// showSpreadWarnings lists spreads that were split and panels left crossing the spine, if any.
+	showSpreadWarnings := func(title string, warns []storage.SpreadWarning) {
+		if len(warns) == 0 {
+			return
+		}
+		lines := make([]string, 0, len(warns))
+		for _, sw := range warns {
+			lines = append(lines, "• "+sw.Message)
+		}
+		dialog.ShowInformation(title, strings.Join(lines, "\n"), w)
+	}
 	// Minimal Add Page… command wraps storage.EnsurePage
-	addPageItem := fyne.NewMenuItem("Add Page…", func() {
-		if ph == nil {
+	addPageItem := fyne.NewMenuItem(i18n.T("menu.add_page"), func() {
+		if ed.Handle == nil {
 			l.Info("menu: add page (no project)")
-			dialog.ShowInformation("Add Page", "No project open.", w)
+			dialog.ShowInformation(i18n.T("msg.add_page"), i18n.T("msg.no_project_open"), w)
 			return
 		}
-		// Compute default next page number
-		next := 1
-		if len(ph.Project.Issues) > 0 {
-			iss := ph.Project.Issues[0]
-			for _, pg := range iss.Pages {
-				if pg.Number >= next {
-					next = pg.Number + 1
-				}
-			}
-		}
+		next := ed.NextPageNumber()
 		entry := widget.NewEntry()
 		entry.SetText(fmt.Sprintf("%d", next))
-		form := dialog.NewForm("Add Page", "Add", "Cancel", []*widget.FormItem{
-			widget.NewFormItem("Page Number", entry),
+		// The grid choice is remembered per project as the default for the next page
+		grids := []string{"None", "2x2", "2x3", "3x2", "3x3", "4x3"}
+		if g := projSettings.GridPreset; g != "" && !slices.Contains(grids, g) {
+			grids = append(grids, g)
+		}
+		gridSelect := widget.NewSelect(grids, nil)
+		gridSelect.SetSelected("None")
+		if projSettings.GridPreset != "" {
+			gridSelect.SetSelected(projSettings.GridPreset)
+		}
+		form := dialog.NewForm(i18n.T("msg.add_page"), i18n.T("msg.add"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.T("form.page_number"), entry),
+			widget.NewFormItem(i18n.T("form.panel_grid"), gridSelect),
 		}, func(ok bool) {
 			if !ok {
 				return
 			}
 			n, err := strconv.Atoi(strings.TrimSpace(entry.Text))
 			if err != nil || n <= 0 {
-				dialog.ShowError(fmt.Errorf("Please enter a positive page number."), w)
+				dialog.ShowError(errors.New(i18n.T("msg.enter_positive_page_number")), w)
 				return
 			}
-			if _, err := storage.EnsurePage(ph, n); err != nil {
-				dialog.ShowError(err, w)
-				return
-			}
-			if err := storage.Save(ph); err != nil {
-				dialog.ShowError(err, w)
+			spreadWarns, err := ed.AddPage(n, gridSelect.Selected)
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
-			status.SetText(fmt.Sprintf("Added page %d", n))
-			currentIssueIdx = 0
-			if len(ph.Project.Issues) > 0 {
-				iss := ph.Project.Issues[currentIssueIdx]
-				for i, pg := range iss.Pages {
-					if pg.Number == n {
-						currentPageIdx = i
-						break
-					}
-				}
+			projSettings.GridPreset = ""
+			if g := gridSelect.Selected; g != "None" {
+				projSettings.GridPreset = g
 			}
+			status.SetText(i18n.T("status.added_page", n))
+			showSpreadWarnings(i18n.T("msg.add_page"), spreadWarns)
 			refreshPagesList()
 			refreshPanelsUI()
 		}, w)
 		form.Show()
 	})
 	// Delete current page menu item
-	deletePageItem := fyne.NewMenuItem("Delete Current Page…", func() {
-		if ph == nil {
-			dialog.ShowInformation("Delete Page", "No project open.", w)
+	deletePageItem := fyne.NewMenuItem(i18n.T("menu.delete_current_page"), func() {
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.delete_page"), i18n.T("msg.no_project_open"), w)
 			return
 		}
-		if len(ph.Project.Issues) == 0 || len(ph.Project.Issues[currentIssueIdx].Pages) == 0 {
-			dialog.ShowInformation("Delete Page", "No pages to delete.", w)
+		if len(ed.Handle.Project.Issues) == 0 || len(ed.Handle.Project.Issues[ed.IssueIdx].Pages) == 0 {
+			dialog.ShowInformation(i18n.T("msg.delete_page"), i18n.T("msg.no_pages_to_delete"), w)
 			return
 		}
-		iss := &ph.Project.Issues[currentIssueIdx]
-		if currentPageIdx < 0 || currentPageIdx >= len(iss.Pages) {
-			dialog.ShowInformation("Delete Page", "Invalid current page.", w)
+		iss := &ed.Handle.Project.Issues[ed.IssueIdx]
+		if ed.PageIdx < 0 || ed.PageIdx >= len(iss.Pages) {
+			dialog.ShowInformation(i18n.T("msg.delete_page"), i18n.T("msg.invalid_current_page"), w)
 			return
 		}
-		pg := iss.Pages[currentPageIdx]
-		confirm := dialog.NewConfirm("Delete Page", fmt.Sprintf("Delete Page %d? You can Undo this action.", pg.Number), func(ok bool) {
+		pg := iss.Pages[ed.PageIdx]
+		confirm := dialog.NewConfirm(i18n.T("msg.delete_page"), i18n.T("msg.delete_page_you_can_undo_this", pg.Number), func(ok bool) {
 			if !ok {
 				return
 			}
-			// Capture snapshot before mutation (in-memory and persisted)
-			if blob, _, err := captureIssueSnapshot(); err == nil {
-				s := undo.Snapshot{PageNumber: 0, Blob: blob, TS: time.Now()}
-				undoMgr.PushSnapshot(s)
-				go storage.SaveSnapshot(context.Background(), ph, 0, blob, s.TS)
+			del, err := ed.DeletePage()
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
 			}
-			// Remove page from slice
-			iss.Pages = append(iss.Pages[:currentPageIdx], iss.Pages[currentPageIdx+1:]...)
-			// Renumber remaining pages so they start at 1 with no gaps
-			for i := range iss.Pages {
-				iss.Pages[i].Number = i + 1
+			status.SetText(i18n.T("status.deleted_page", del.Number))
+			showSpreadWarnings(i18n.T("msg.delete_page"), del.SpreadWarnings)
+			refreshPagesList()
+			refreshPanelsUI()
+		}, w)
+		confirm.SetDismissText(i18n.T("msg.cancel"))
+		confirm.SetConfirmText(i18n.T("button.delete"))
+		confirm.Show()
+	})
+	// currentSpreadIssue returns the issue and current page for the spread menu items, or nil after telling the user why.
+	currentSpreadIssue := func(title string) (*domain.Issue, int) {
+		if ed.Handle == nil {
+			dialog.ShowInformation(title, i18n.T("msg.no_project_open"), w)
+			return nil, 0
+		}
+		if len(ed.Handle.Project.Issues) == 0 || ed.PageIdx < 0 || ed.PageIdx >= len(ed.Handle.Project.Issues[ed.IssueIdx].Pages) {
+			dialog.ShowInformation(title, i18n.T("msg.no_current_page"), w)
+			return nil, 0
+		}
+		iss := &ed.Handle.Project.Issues[ed.IssueIdx]
+		return iss, iss.Pages[ed.PageIdx].Number
+	}
+	makeSpreadItem := fyne.NewMenuItem(i18n.T("menu.make_spread_with_next_page"), func() {
+		iss, n := currentSpreadIssue(i18n.T("msg.make_spread"))
+		if iss == nil {
+			return
+		}
+		if ed.PageIdx+1 >= len(iss.Pages) {
+			dialog.ShowInformation(i18n.T("msg.make_spread"), i18n.T("msg.page_is_the_last_page", n), w)
+			return
+		}
+		next := iss.Pages[ed.PageIdx+1].Number
+		if err := ed.MakeSpread(n, next); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		l.Info("spread created", slog.Int("page", n))
+		status.SetText(i18n.T("status.pages_and_form_a_spread", n, next))
+		refreshPagesList()
+		refreshPanelsUI()
+	})
+	splitSpreadItem := fyne.NewMenuItem(i18n.T("menu.split_spread"), func() {
+		iss, n := currentSpreadIssue(i18n.T("menu.split_spread"))
+		if iss == nil {
+			return
+		}
+		if storage.SpreadPartner(*iss, n) == 0 {
+			dialog.ShowInformation(i18n.T("menu.split_spread"), i18n.T("msg.page_is_not_part_of_a", n), w)
+			return
+		}
+		warns, err := ed.SplitSpread(n)
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		l.Info("spread split", slog.Int("page", n), slog.Int("warnings", len(warns)))
+		status.SetText(i18n.T("status.split_the_spread_of_page", n))
+		showSpreadWarnings(i18n.T("menu.split_spread"), warns)
+		refreshPagesList()
+		refreshPanelsUI()
+	})
+	offerPageNumberRepair = func(fromMenu bool) {
+		if ed.Handle == nil {
+			if fromMenu {
+				dialog.ShowInformation(i18n.T("msg.repair_page_numbers"), i18n.T("msg.no_project_open"), w)
 			}
-			// Adjust current page index
-			if currentPageIdx >= len(iss.Pages) {
-				currentPageIdx = len(iss.Pages) - 1
+			return
+		}
+		probs := storage.FindPageNumberProblems(ed.Handle.Project)
+		if len(probs) == 0 {
+			if fromMenu {
+				dialog.ShowInformation(i18n.T("msg.repair_page_numbers"), i18n.T("msg.every_issue_numbers_its_pages_from"), w)
 			}
-			if currentPageIdx < 0 {
-				currentPageIdx = 0
+			return
+		}
+		var lines []string
+		for _, pr := range probs {
+			lines = append(lines, i18n.T("msg.repair_issue_line", pr.IssueIndex+1, pr))
+		}
+		// Page numbers may mean something to the author, so they are never changed without asking
+		ask := dialog.NewConfirm(i18n.T("msg.repair_page_numbers"), i18n.T("msg.renumber_the_pages_of_these_issues", strings.Join(lines, "\n")), func(ok bool) {
+			if !ok || ed.Handle == nil {
+				return
 			}
-			if err := storage.Save(ph); err != nil {
-				dialog.ShowError(err, w)
+			renums, warns, err := ed.RepairPageNumbers()
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
-			status.SetText(fmt.Sprintf("Deleted Page %d", pg.Number))
+			for _, r := range renums {
+				l.Info("page renumbered", slog.Int("issue", r.IssueIndex+1), slog.Int("old", r.OldNumber), slog.Int("new", r.NewNumber))
+			}
+			status.SetText(i18n.N("status.renumbered_pages", len(renums)))
+			go func(ix *storage.IndexHandle, proj domain.Project) {
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				defer cancel()
+				if err := ix.UpdateIndex(ctx, proj); err != nil {
+					l.Warn("index update after page repair failed", slog.Any("err", err))
+				}
+			}(ed.Handle.Index(), ed.Handle.Project)
+			showValidation()
 			refreshPagesList()
 			refreshPanelsUI()
+			showSpreadWarnings(i18n.T("msg.repair_page_numbers"), warns)
 		}, w)
-		confirm.SetDismissText("Cancel")
-		confirm.SetConfirmText("Delete")
-		confirm.Show()
+		ask.SetConfirmText(i18n.T("button.renumber"))
+		ask.SetDismissText(i18n.T("button.keep_numbers"))
+		ask.Show()
+	}
+	repairPagesItem := fyne.NewMenuItem(i18n.T("menu.repair_page_numbers"), func() { offerPageNumberRepair(true) })
+	savePageTemplateItem := fyne.NewMenuItem(i18n.T("menu.save_page_as_template"), func() {
+		if iss, n := currentSpreadIssue(i18n.T("msg.save_page_as_template_plain")); iss != nil {
+			showSavePageTemplate(w, ed.Handle, ed.IssueIdx, n, l, status)
+		}
 	})
-	issueMenu := fyne.NewMenu("Issue", issueSetupItem, addPageItem, deletePageItem)
-
-	// Insert menu (Balloon auto-placement)
-	insertBalloonItem := fyne.NewMenuItem("Balloon…", func() {
-		if ph == nil {
-			l.Info("menu: insert balloon (no project)")
-			dialog.ShowInformation("Insert Balloon", "No project open.", w)
+	applyPageTemplateItem := fyne.NewMenuItem(i18n.T("menu.apply_page_template"), func() {
+		iss, n := currentSpreadIssue(i18n.T("msg.apply_page_template"))
+		if iss == nil {
+			return
+		}
+		showApplyPageTemplate(w, ed.Handle, ed.IssueIdx, n, l, status, func() {
+			ed.PushUndo(i18n.T("undo.apply_page_template", n))
+		}, func() {
+			refreshPagesList()
+			refreshPanelsUI()
+		})
+	})
+	deletePageTemplateItem := fyne.NewMenuItem(i18n.T("menu.delete_page_template"), func() {
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.delete_page_template"), i18n.T("msg.no_project_open"), w)
 			return
 		}
-		if len(ph.Project.Issues) == 0 || len(ph.Project.Issues[0].Pages) == 0 {
-			dialog.ShowInformation("Insert Balloon", "No pages in the current project.", w)
+		showDeletePageTemplate(w, ed.Handle, l, status)
+	})
+	// Set Reference Image: pick a sketch or thumbnail from the assets to trace on the current page.
+	// It is only drawn in the editor unless it is marked as page art (as imported pages are), which
+	// every export draws under the panels.
+	setReferenceItem := fyne.NewMenuItem(i18n.T("menu.set_reference_image"), func() {
+		iss, n := currentSpreadIssue(i18n.T("msg.set_reference_image"))
+		if iss == nil {
 			return
 		}
-		iss := ph.Project.Issues[currentIssueIdx]
-		pg := &ph.Project.Issues[currentIssueIdx].Pages[currentPageIdx]
-		// Choose target panel: currently selected in the list, else first panel
-		var targetPanel *domain.Panel
-		if selectedPanel >= 0 && selectedPanel < len(panelIDs) {
-			pid := panelIDs[selectedPanel]
-			for i := range pg.Panels {
-				if pg.Panels[i].ID == pid {
-					targetPanel = &pg.Panels[i]
-					break
+		issIdx := ed.IssueIdx
+		cur := domain.ReferenceImage{Fit: domain.ReferenceFitContain}
+		if ref := iss.Pages[ed.PageIdx].ReferenceImage; ref != nil {
+			cur = *ref
+		}
+		const none = "(none)"
+		options := append([]string{none}, bibleImageAssets()...)
+		selected := cur.Asset
+		if armed := armedAssetRel(); armed != "" && !cur.Locked {
+			selected = armed
+		}
+		if selected == "" {
+			selected = none
+		} else if !slices.Contains(options, selected) {
+			options = append(options, selected)
+		}
+		assetSelect := widget.NewSelect(options, nil)
+		assetSelect.SetSelected(selected)
+		opacityLabel := widget.NewLabel("")
+		opacity := widget.NewSlider(minReferenceOpacity*100, 100)
+		opacity.Step = 5
+		opacity.OnChanged = func(v float64) { opacityLabel.SetText(fmt.Sprintf("%.0f%%", v)) }
+		opacity.SetValue(referenceOpacity(cur.Opacity) * 100)
+		fitSelect := widget.NewSelect([]string{"Contain", "Stretch", "Bleed"}, nil)
+		fitSelect.SetSelected("Contain")
+		switch cur.Fit {
+		case domain.ReferenceFitStretch:
+			fitSelect.SetSelected("Stretch")
+		case domain.ReferenceFitBleed:
+			fitSelect.SetSelected("Bleed")
+		}
+		artCheck := widget.NewCheck(i18n.T("check.page_art_included_in_exports"), nil)
+		artCheck.SetChecked(cur.Art)
+		// A locked reference keeps its settings until it is unlocked here
+		lockCheck := widget.NewCheck(i18n.T("check.locked"), func(locked bool) {
+			for _, wdg := range []fyne.Disableable{assetSelect, opacity, fitSelect, artCheck} {
+				if locked {
+					wdg.Disable()
+				} else {
+					wdg.Enable()
 				}
 			}
-		}
-		if targetPanel == nil {
-			if len(pg.Panels) == 0 {
-				dialog.ShowInformation("Insert Balloon", "No panels on this page.", w)
+		})
+		lockCheck.SetChecked(cur.Locked)
+		lockCheck.OnChanged(cur.Locked)
+		dialog.ShowForm(i18n.T("msg.reference_image_page", n), i18n.T("msg.apply"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.T("form.asset"), assetSelect),
+			widget.NewFormItem(i18n.T("form.opacity"), container.NewBorder(nil, nil, nil, opacityLabel, opacity)),
+			widget.NewFormItem(i18n.T("form.fit"), fitSelect),
+			widget.NewFormItem("", artCheck),
+			widget.NewFormItem("", lockCheck),
+		}, func(ok bool) {
+			// The reference is set on the page of the issue it was chosen for
+			if !ok || ed.Handle == nil || ed.IssueIdx != issIdx {
 				return
 			}
-			targetPanel = &pg.Panels[0]
-		}
-
-		// Default content box size for the dialog text (placeholder)
-		contentSz := vector.Size{W: 140, H: 80}
-		panelRect := vector.R(float32(targetPanel.Geometry.X), float32(targetPanel.Geometry.Y), float32(targetPanel.Geometry.Width), float32(targetPanel.Geometry.Height))
-		// Obstacles: existing balloons in this panel (approx by their rects)
-		var obstacles []vector.Rect
-		for _, b := range targetPanel.Balloons {
-			obstacles = append(obstacles, vector.R(float32(b.Shape.Rect.X), float32(b.Shape.Rect.Y), float32(b.Shape.Rect.Width), float32(b.Shape.Rect.Height)))
+			var ref *domain.ReferenceImage
+			if a := assetSelect.Selected; a != "" && a != none {
+				ref = &domain.ReferenceImage{Asset: a, Opacity: opacity.Value / 100, Fit: domain.ReferenceFitContain, Locked: lockCheck.Checked, Art: artCheck.Checked}
+				switch fitSelect.Selected {
+				case "Stretch":
+					ref.Fit = domain.ReferenceFitStretch
+				case "Bleed":
+					ref.Fit = domain.ReferenceFitBleed
+				}
+			}
+			if err := ed.SetReferenceImage(n, ref); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			if ref == nil {
+				l.Info("reference image removed", slog.Int("page", n))
+				status.SetText(i18n.T("status.removed_the_reference_image_of_page", n))
+			} else {
+				l.Info("reference image set", slog.Int("page", n), slog.String("asset", ref.Asset))
+				status.SetText(i18n.T("status.reference_image_of_page", n, ref.Asset))
+			}
+			refreshPanelsUI()
+		}, w)
+	})
+	// Default Panel Border: the border of every panel that does not override it (Border… in the inspector)
+	panelBorderItem := fyne.NewMenuItem(i18n.T("menu.default_panel_border"), func() {
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.default_panel_border"), i18n.T("msg.no_project_open"), w)
+			return
 		}
-		opts := vector.SuggestOptions{Padding: 8, Margin: 8, GridStep: 8, ReadingDirection: strings.ToLower(strings.TrimSpace(iss.ReadingDirection))}
-		if opts.ReadingDirection == "" {
-			opts.ReadingDirection = "ltr"
+		showBorderDialog(i18n.T("msg.default_panel_border"), ed.Handle.Project.PanelBorder, func(b *domain.PanelBorder) error {
+			if err := storage.SetProjectPanelBorder(ed.Handle, b); err != nil {
+				return err
+			}
+			l.Info("default panel border set")
+			return storage.Save(ed.Handle)
+		})
+	})
+	// Palette: apply a named palette from styles/palettes.json to the project's default colors
+	paletteItem := fyne.NewMenuItem(i18n.T("menu.palette"), func() {
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.palette"), i18n.T("msg.no_project_open"), w)
+			return
 		}
-		rect, _ := vector.SuggestBalloonLayout(panelRect, contentSz, obstacles, opts)
+		pals, err := stylepack.LoadPalettes(ed.Handle.Root)
+		if err != nil {
+			status.SetText(i18n.T("status.palette_file_unusable_showing_the_default"))
+		}
+		names := make([]string, len(pals))
+		for i, p := range pals {
+			names[i] = p.Name
+		}
+		swatches := container.NewHBox()
+		showSwatches := func(p stylepack.Palette) {
+			swatches.RemoveAll()
+			for _, s := range []struct {
+				label string
+				c     domain.Color
+			}{{i18n.T("label.swatch_panel"), p.PanelStroke}, {i18n.T("label.swatch_balloon"), p.BalloonFill}, {i18n.T("label.swatch_outline"), p.BalloonStroke}, {i18n.T("label.swatch_caption"), p.CaptionFill}, {i18n.T("label.swatch_guide"), p.Guide}} {
+				r := canvas.NewRectangle(color.RGBA{R: s.c.R, G: s.c.G, B: s.c.B, A: s.c.A})
+				r.StrokeColor = color.RGBA{R: 30, G: 30, B: 30, A: 255}
+				r.StrokeWidth = 1
+				r.SetMinSize(fyne.NewSize(24, 24))
+				swatches.Add(container.NewVBox(r, widget.NewLabel(s.label)))
+			}
+		}
+		sel := widget.NewSelect(names, func(name string) {
+			if i := slices.Index(names, name); i >= 0 {
+				showSwatches(pals[i])
+			}
+		})
+		cur := names[0]
+		if ds := ed.Handle.Project.DefaultStyles; ds != nil && slices.Contains(names, ds.Palette) {
+			cur = ds.Palette
+		}
+		sel.SetSelected(cur)
+		dialog.ShowForm(i18n.T("msg.palette"), i18n.T("msg.apply"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.T("msg.palette"), sel),
+			widget.NewFormItem("", swatches),
+		}, func(ok bool) {
+			i := slices.Index(names, sel.Selected)
+			if !ok || ed.Handle == nil || i < 0 {
+				return
+			}
+			stylepack.ApplyPalette(&ed.Handle.Project, pals[i])
+			if err := storage.Save(ed.Handle); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			l.Info("palette applied", slog.String("palette", pals[i].Name))
+			refreshPanelsUI()
+			status.SetText(i18n.T("status.palette_applied", pals[i].Name))
+		}, w)
+	})
+	// Balloon Styles: the named presets Insert → Balloon and the balloon editor apply
+	balloonStylesItem := fyne.NewMenuItem(i18n.T("menu.balloon_styles"), func() {
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.balloon_styles"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		showBalloonStyles(w, ed.Handle, l, status, refreshPanelsUI)
+	})
+	// Lock/Unlock All Panels on Page: protect an approved page from accidental panel edits, one undo step
+	lockPagePanels := func(title string, lock bool) func() {
+		return func() {
+			_, n := currentSpreadIssue(title)
+			if n == 0 {
+				return
+			}
+			changed, err := ed.LockPage(n, lock)
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			verb := i18n.T("status.unlocked")
+			if lock {
+				verb = i18n.T("status.locked")
+			}
+			if changed == 0 {
+				status.SetText(i18n.T("status.nothing_to_change_on_page", title, n))
+				return
+			}
+			l.Info("page panels lock changed", slog.Int("page", n), slog.Bool("locked", lock), slog.Int("panels", changed))
+			status.SetText(i18n.N("status.panels_on_page", changed, verb, changed, n))
+			refreshPanelsUI()
+		}
+	}
+	lockPageItem := fyne.NewMenuItem(i18n.T("menu.lock_all_panels_on_page"), lockPagePanels(i18n.T("msg.lock_all_panels"), true))
+	unlockPageItem := fyne.NewMenuItem(i18n.T("menu.unlock_all_panels_on_page"), lockPagePanels(i18n.T("msg.unlock_all_panels"), false))
+	artStatusItem := fyne.NewMenuItem(i18n.T("menu.art_status"), func() {
+		if ed.Handle == nil {
+			l.Info("menu: art status (no project)")
+			dialog.ShowInformation(i18n.T("msg.art_status"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		l.Info("menu: art status")
+		dialog.ShowInformation(i18n.T("msg.art_status"), artStatusReport(storage.ComputeArtStatus(ed.Handle.Project)), w)
+	})
+	normalizeGuttersItem := fyne.NewMenuItem(i18n.T("menu.normalize_gutters"), func() {
+		title := i18n.T("msg.normalize_gutters")
+		iss, n := currentSpreadIssue(title)
+		if iss == nil {
+			return
+		}
+		st, err := storage.PageGutters(iss.Pages[ed.PageIdx])
+		switch {
+		case errors.Is(err, storage.ErrNotGridLayout):
+			l.Info("normalize gutters: not a grid layout", slog.Int("page", n))
+			dialog.ShowInformation(title, i18n.T("msg.gutters_not_grid", n), w)
+			return
+		case err != nil:
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		case st.Count == 0:
+			dialog.ShowInformation(title, i18n.T("msg.gutters_none", n), w)
+			return
+		}
+		u := units()
+		hint := widget.NewLabel(i18n.T("label.gutters_detected", n, u.format(max(st.Min, 0)), u.format(st.Max), u.format(st.Median)))
+		hint.Wrapping = fyne.TextWrapWord
+		gutterEntry := widget.NewEntry()
+		gutterEntry.SetText(u.number(max(st.Median, 0)))
+		form := dialog.NewForm(title, i18n.T("msg.continue"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem("", hint),
+			widget.NewFormItem(u.label("form.gutter"), gutterEntry),
+		}, func(ok bool) {
+			if !ok || ed.Handle == nil {
+				return
+			}
+			gutter, perr := u.parse(gutterEntry.Text)
+			if perr != nil || gutter < 0 {
+				dialog.ShowError(errors.New(i18n.T("msg.invalid_gutter", u.name())), w)
+				return
+			}
+			changed, err := ed.NormalizeGutters(n, gutter)
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			if changed == 0 {
+				status.SetText(i18n.T("status.nothing_to_change_on_page", title, n))
+				return
+			}
+			l.Info("gutters normalized", slog.Int("page", n), slog.Float64("mm", domain.PointsToMM(gutter)), slog.Int("panels", changed))
+			status.SetText(i18n.N("status.gutters_normalized", changed, n, u.format(gutter), changed))
+			refreshPanelsUI()
+		}, w)
+		form.Resize(fyne.NewSize(460, 260))
+		form.Show()
+	})
+	issueMenu := fyne.NewMenu(i18n.T("menu.issue"), issueSetupItem, addPageItem, deletePageItem, repairPagesItem, fyne.NewMenuItemSeparator(), makeSpreadItem, splitSpreadItem, fyne.NewMenuItemSeparator(), savePageTemplateItem, applyPageTemplateItem, deletePageTemplateItem, fyne.NewMenuItemSeparator(), setReferenceItem, panelBorderItem, normalizeGuttersItem, paletteItem, balloonStylesItem, fyne.NewMenuItemSeparator(), lockPageItem, unlockPageItem, artStatusItem)
 
-		// Add a visual ellipse node to the canvas for immediate feedback
-		fill := vector.Fill{Enabled: true, Color: vector.Color{R: 255, G: 255, B: 255, A: 255}}
-		stroke := vector.Stroke{Enabled: true, Color: vector.Black, Width: 2}
-		ellipse := vector.NewEllipse(rect, fill, stroke)
-		canvasWidget.scene = append(canvasWidget.scene, ellipse)
-		canvasWidget.selected = len(canvasWidget.scene) - 1
-		canvasWidget.Refresh()
+	// insertTargetPanel resolves the panel that Insert menu items act on: the selected panel, else the first one.
+	insertTargetPanel := func(title string) *domain.Panel {
+		if ed.Handle == nil {
+			l.Info("menu: insert (no project)", slog.String("item", title))
+			dialog.ShowInformation(title, i18n.T("msg.no_project_open"), w)
+			return nil
+		}
+		if len(ed.Handle.Project.Issues) == 0 || len(ed.Handle.Project.Issues[0].Pages) == 0 {
+			dialog.ShowInformation(title, i18n.T("msg.no_pages_in_the_current_project"), w)
+			return nil
+		}
+		pg := &ed.Handle.Project.Issues[ed.IssueIdx].Pages[ed.PageIdx]
+		if pid, ok := ed.SelectedPanel(panelIDs); ok {
+			for i := range pg.Panels {
+				if pg.Panels[i].ID == pid {
+					return &pg.Panels[i]
+				}
+			}
+		}
+		if len(pg.Panels) == 0 {
+			dialog.ShowInformation(title, i18n.T("msg.no_panels_on_this_page"), w)
+			return nil
+		}
+		return &pg.Panels[0]
+	}
+	// suggestInPanel finds a free spot of the given size inside the panel, avoiding existing lettering.
+	// With measure set, the size comes from the measured text and contentSz is only the fallback.
+	suggestInPanel := func(pn *domain.Panel, contentSz vector.Size, measure func(float32) vector.Size) vector.Rect {
+		iss := ed.Handle.Project.Issues[ed.IssueIdx]
+		panelRect := vector.R(float32(pn.Geometry.X), float32(pn.Geometry.Y), float32(pn.Geometry.Width), float32(pn.Geometry.Height))
+		// Obstacles: existing balloons, captions and SFX in this panel (approx by their rects)
+		var obstacles []vector.Rect
+		for _, b := range pn.Balloons {
+			obstacles = append(obstacles, vector.R(float32(b.Shape.Rect.X), float32(b.Shape.Rect.Y), float32(b.Shape.Rect.Width), float32(b.Shape.Rect.Height)))
+		}
+		for _, c := range pn.Captions {
+			obstacles = append(obstacles, vector.R(float32(c.Rect.X), float32(c.Rect.Y), float32(c.Rect.Width), float32(c.Rect.Height)))
+		}
+		for _, fx := range pn.SFX {
+			obstacles = append(obstacles, vector.R(float32(fx.Rect.X), float32(fx.Rect.Y), float32(fx.Rect.Width), float32(fx.Rect.Height)))
+		}
+		opts := vector.SuggestOptions{Padding: 8, Margin: 8, GridStep: 8, ReadingDirection: strings.ToLower(strings.TrimSpace(iss.ReadingDirection)), ContentSize: measure}
+		if opts.ReadingDirection == "" {
+			opts.ReadingDirection = "ltr"
+		}
+		rect, _ := vector.SuggestBalloonLayout(panelRect, contentSz, obstacles, opts)
+		return rect
+	}
+
+	// Lettering is hyphenated for the user's language when sizing balloons and captions to their text
+	letteringLang := lang.SystemLocale().LanguageString()
 
-		// Update the domain model (store ellipse balloon)
-		newID := fmt.Sprintf("balloon-%d", len(targetPanel.Balloons)+1)
-		bshape := domain.Shape{Kind: "ellipse", Rect: domain.Rect{X: float64(rect.X), Y: float64(rect.Y), Width: float64(rect.W), Height: float64(rect.H)}}
-		ball := domain.Balloon{ID: newID, Type: "speech", TextRuns: []domain.TextRun{{Content: "", Font: "", Size: 12}}, Shape: bshape}
-		targetPanel.Balloons = append(targetPanel.Balloons, ball)
-		status.SetText("Inserted balloon in panel " + targetPanel.ID)
+	// Insert menu (Balloon auto-placement); the balloon style picked last is preselected
+	lastBalloonStyle := ""
+	insertBalloonItem := fyne.NewMenuItem(i18n.T("menu.balloon"), func() {
+		targetPanel := insertTargetPanel(i18n.T("msg.insert_balloon"))
+		if targetPanel == nil {
+			return
+		}
+		entry := widget.NewMultiLineEntry()
+		entry.SetPlaceHolder(i18n.T("placeholder.balloon_text_optional"))
+		fontSel := newFontPicker(ed.Handle.Root)
+		styles := ed.Handle.Project.BalloonStyles
+		styleSel := widget.NewSelect(balloonStyleNames(styles), nil)
+		if s, ok := storage.FindBalloonStyle(styles, lastBalloonStyle); ok {
+			styleSel.SetSelected(s.Name)
+		} else if len(styles) > 0 {
+			styleSel.SetSelected(styles[0].Name)
+		}
+		form := container.NewVBox(entry, widget.NewForm(
+			widget.NewFormItem(i18n.T("form.balloon_style"), styleSel),
+			widget.NewFormItem(i18n.T("form.font"), fontSel),
+		))
+		dialog.NewCustomConfirm(i18n.T("msg.insert_balloon"), i18n.T("msg.insert"), i18n.T("msg.cancel"), form, func(ok bool) {
+			if !ok {
+				return
+			}
+			lastBalloonStyle = styleSel.Selected
+			txt := strings.TrimSpace(entry.Text)
+			size := float64(defaultBalloonSize)
+			if s, ok := storage.FindBalloonStyle(styles, styleSel.Selected); ok && s.Size > 0 {
+				size = s.Size
+			}
+			// Sized to the text; an empty balloon gets a default content box
+			rect := suggestInPanel(targetPanel, vector.Size{W: 140, H: 80}, measuredContent(txt, size, letteringLang, true))
+			newID := storage.NewBalloonID(ed.Handle)
+			ball := newStyledBalloon(newID, txt, fontFromOption(fontSel.Selected), styles, styleSel.Selected, rect)
+
+			// Update the domain model; the new balloon is read last in its panel and selected for editing
+			ball.Order = storage.NextBalloonOrder(targetPanel.Balloons)
+			targetPanel.Balloons = append(targetPanel.Balloons, ball)
+			refreshPanelsUI()
+			selectEntity(controller.Selection{Page: ed.PageNumber(), PanelID: targetPanel.ID, BalloonID: ball.ID})
+			status.SetText(i18n.T("status.inserted_balloon_in_panel", targetPanel.ID))
+		}, w).Show()
+	})
+	insertCaptionItem := fyne.NewMenuItem(i18n.T("menu.caption"), func() {
+		targetPanel := insertTargetPanel(i18n.T("msg.insert_caption"))
+		if targetPanel == nil {
+			return
+		}
+		entry := widget.NewMultiLineEntry()
+		entry.SetPlaceHolder(i18n.T("placeholder.caption_text"))
+		fontSel := newFontPicker(ed.Handle.Root)
+		form := container.NewVBox(entry, widget.NewForm(widget.NewFormItem(i18n.T("form.font"), fontSel)))
+		dialog.NewCustomConfirm(i18n.T("msg.insert_caption"), i18n.T("msg.insert"), i18n.T("msg.cancel"), form, func(ok bool) {
+			if !ok {
+				return
+			}
+			rect := suggestInPanel(targetPanel, vector.Size{W: 200, H: 40}, measuredContent(entry.Text, 11, letteringLang, false))
+			fill := vector.Fill{Enabled: true, Color: vector.Color{R: 255, G: 255, B: 255, A: 255}}
+			stroke := vector.Stroke{Enabled: true, Color: vector.Black, Width: 1}
+			canvasWidget.scene = append(canvasWidget.scene, vector.NewRect(rect, fill, stroke))
+			canvasWidget.selected = len(canvasWidget.scene) - 1
+			canvasWidget.Refresh()
+			c := domain.Caption{
+				ID:   storage.NewCaptionID(ed.Handle),
+				Text: strings.TrimSpace(entry.Text),
+				Font: fontFromOption(fontSel.Selected),
+				Size: 11,
+				Rect: domain.Rect{X: float64(rect.X), Y: float64(rect.Y), Width: float64(rect.W), Height: float64(rect.H)},
+			}
+			targetPanel.Captions = append(targetPanel.Captions, c)
+			status.SetText(i18n.T("status.inserted_caption_in_panel", targetPanel.ID))
+		}, w).Show()
+	})
+	insertSFXItem := fyne.NewMenuItem(i18n.T("menu.sfx"), func() {
+		targetPanel := insertTargetPanel(i18n.T("msg.insert_sfx"))
+		if targetPanel == nil {
+			return
+		}
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder(i18n.T("placeholder.e_g_krakoom"))
+		fontSel := newFontPicker(ed.Handle.Root)
+		form := container.NewVBox(entry, widget.NewForm(widget.NewFormItem(i18n.T("form.font"), fontSel)))
+		dialog.NewCustomConfirm(i18n.T("msg.insert_sfx"), i18n.T("msg.insert"), i18n.T("msg.cancel"), form, func(ok bool) {
+			if !ok || strings.TrimSpace(entry.Text) == "" {
+				return
+			}
+			rect := suggestInPanel(targetPanel, vector.Size{W: 160, H: 50}, nil)
+			stroke := vector.Stroke{Enabled: true, Color: vector.Color{R: 200, G: 0, B: 0, A: 255}, Width: 1}
+			canvasWidget.scene = append(canvasWidget.scene, vector.NewRect(rect, vector.Fill{}, stroke))
+			canvasWidget.selected = len(canvasWidget.scene) - 1
+			canvasWidget.Refresh()
+			fx := domain.SFXItem{
+				ID:   storage.NewSFXID(ed.Handle),
+				Text: strings.TrimSpace(entry.Text),
+				Font: fontFromOption(fontSel.Selected),
+				Size: 36,
+				Rect: domain.Rect{X: float64(rect.X), Y: float64(rect.Y), Width: float64(rect.W), Height: float64(rect.H)},
+			}
+			targetPanel.SFX = append(targetPanel.SFX, fx)
+			status.SetText(i18n.T("status.inserted_sfx_in_panel", targetPanel.ID))
+		}, w).Show()
 	})
 	// Vector insert items (make internal/vector shapes accessible via Insert menu)
-	insertRectItem := fyne.NewMenuItem("Rectangle", func() {
+	insertRectItem := fyne.NewMenuItem(i18n.T("menu.rectangle"), func() {
 		// Insert a default rectangle centered on the page
 		w0, h0 := float32(140), float32(90)
 		cx, cy := canvasWidget.pageW/2, canvasWidget.pageH/2
@@ -2862,9 +5585,9 @@ func Run(projectDir string) error {
 		canvasWidget.scene = append(canvasWidget.scene, n)
 		canvasWidget.selected = len(canvasWidget.scene) - 1
 		canvasWidget.Refresh()
-		status.SetText("Inserted rectangle")
+		status.SetText(i18n.T("status.inserted_rectangle"))
 	})
-	insertEllipseItem := fyne.NewMenuItem("Ellipse", func() {
+	insertEllipseItem := fyne.NewMenuItem(i18n.T("menu.ellipse"), func() {
 		// Insert a default ellipse centered on the page
 		w0, h0 := float32(140), float32(100)
 		cx, cy := canvasWidget.pageW/2, canvasWidget.pageH/2
@@ -2875,9 +5598,9 @@ func Run(projectDir string) error {
 		canvasWidget.scene = append(canvasWidget.scene, n)
 		canvasWidget.selected = len(canvasWidget.scene) - 1
 		canvasWidget.Refresh()
-		status.SetText("Inserted ellipse")
+		status.SetText(i18n.T("status.inserted_ellipse"))
 	})
-	insertRoundRectItem := fyne.NewMenuItem("Rounded Rectangle", func() {
+	insertRoundRectItem := fyne.NewMenuItem(i18n.T("menu.rounded_rectangle"), func() {
 		w0, h0 := float32(180), float32(110)
 		radius := float32(12)
 		cx, cy := canvasWidget.pageW/2, canvasWidget.pageH/2
@@ -2888,9 +5611,9 @@ func Run(projectDir string) error {
 		canvasWidget.scene = append(canvasWidget.scene, n)
 		canvasWidget.selected = len(canvasWidget.scene) - 1
 		canvasWidget.Refresh()
-		status.SetText("Inserted rounded rectangle")
+		status.SetText(i18n.T("status.inserted_rounded_rectangle"))
 	})
-	insertPathItem := fyne.NewMenuItem("Path (Triangle)", func() {
+	insertPathItem := fyne.NewMenuItem(i18n.T("menu.path_triangle"), func() {
 		// Create a simple triangle path near page center
 		cx, cy := canvasWidget.pageW/2, canvasWidget.pageH/2
 		s := float32(80)
@@ -2905,34 +5628,224 @@ func Run(projectDir string) error {
 		canvasWidget.scene = append(canvasWidget.scene, n)
 		canvasWidget.selected = len(canvasWidget.scene) - 1
 		canvasWidget.Refresh()
-		status.SetText("Inserted path")
+		status.SetText(i18n.T("status.inserted_path"))
 	})
-	vectorSub := fyne.NewMenuItem("Vector", nil)
-	vectorSub.ChildMenu = fyne.NewMenu("Vector", insertRectItem, insertEllipseItem, insertRoundRectItem, insertPathItem)
+	vectorSub := fyne.NewMenuItem(i18n.T("menu.vector"), nil)
+	vectorSub.ChildMenu = fyne.NewMenu(i18n.T("menu.vector"), insertRectItem, insertEllipseItem, insertRoundRectItem, insertPathItem)
 	// Delete selected object (vector node) from canvas
-	deleteSelectedItem := fyne.NewMenuItem("Delete Selected", func() {
+	// Delete Selected removes the selected balloon, else the selected panels after asking; anything else
+	// selected on the canvas is only a shape drawn there
+	deleteSelectedItem := fyne.NewMenuItem(i18n.T("button.delete_selected"), func() {
+		if ed.Selected.IsBalloon() {
+			btnDeleteBalloon.OnTapped()
+			return
+		}
+		if len(ed.SelectedPanels(panelIDs)) > 0 {
+			btnBulkDelete.OnTapped()
+			return
+		}
 		if canvasWidget.selected < 0 || canvasWidget.selected >= len(canvasWidget.scene) {
-			dialog.ShowInformation("Delete Selected", "Nothing selected.", w)
+			dialog.ShowInformation(i18n.T("button.delete_selected"), i18n.T("msg.nothing_selected"), w)
 			return
 		}
 		idx := canvasWidget.selected
+		if id := canvasWidget.lockedAt(idx); id != "" {
+			status.SetText(i18n.T("status.panel_locked_delete", id))
+			return
+		}
 		canvasWidget.scene = append(canvasWidget.scene[:idx], canvasWidget.scene[idx+1:]...)
 		canvasWidget.selected = -1
+		canvasWidget.marked = nil
 		canvasWidget.Refresh()
-		status.SetText("Deleted selection")
+		status.SetText(i18n.T("status.deleted_selection"))
+	})
+	// Balloon reading order: the balloon clicked last moves earlier or later in its panel, and
+	// auto-ordering numbers balloons by position in the issue's reading direction
+	// balloonPage is the number of the page a balloon clicked on the canvas is on, which is the right
+	// page of a spread for balloons on its right side
+	balloonPage := func(iss *domain.Issue, b canvasBalloon) int {
+		pageNum := ed.PageNumber()
+		if left, right, spread := storage.SpreadSides(*iss, pageNum); spread {
+			pageNum = left
+			if b.Side == 1 {
+				pageNum = right
+			}
+		}
+		return pageNum
+	}
+	moveBalloonOrder := func(delta int) {
+		title := i18n.T("msg.balloon_reading_order")
+		b, ok := canvasWidget.SelectedBalloon()
+		iss := ed.Issue()
+		if ed.Handle == nil || iss == nil || !ok {
+			dialog.ShowInformation(title, i18n.T("msg.select_a_balloon_first"), w)
+			return
+		}
+		pageNum := balloonPage(iss, b)
+		moved, err := ed.MoveBalloonOrder(pageNum, b.PanelID, b.BalloonID, delta)
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		if !moved {
+			status.SetText(i18n.T("status.balloon_order_unchanged", b.BalloonID))
+			return
+		}
+		refreshPanelsUI()
+		if nb, err := storage.FindBalloon(ed.Handle, pageNum, b.PanelID, b.BalloonID); err == nil {
+			status.SetText(i18n.T("status.balloon_order_moved", b.BalloonID, nb.Order))
+		}
+	}
+	autoOrderBalloons := func(panelOnly bool) {
+		title := i18n.T("msg.auto_order_balloons")
+		iss, n := currentSpreadIssue(title)
+		if iss == nil {
+			return
+		}
+		panelID := ""
+		if panelOnly {
+			id, ok := ed.SelectedPanel(panelIDs)
+			if !ok {
+				dialog.ShowInformation(title, i18n.T("msg.select_a_panel_first"), w)
+				return
+			}
+			panelID = id
+		}
+		changed, err := ed.AutoOrderBalloons(n, panelID)
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		if changed == 0 {
+			status.SetText(i18n.T("status.nothing_to_change_on_page", title, n))
+			return
+		}
+		l.Info("balloons auto-ordered", slog.Int("page", n), slog.String("panel", panelID), slog.Int("changed", changed))
+		refreshPanelsUI()
+		status.SetText(i18n.N("status.balloons_auto_ordered", changed, changed, n))
+	}
+	balloonEarlierItem := fyne.NewMenuItem(i18n.T("menu.move_balloon_earlier"), func() { moveBalloonOrder(-1) })
+	balloonLaterItem := fyne.NewMenuItem(i18n.T("menu.move_balloon_later"), func() { moveBalloonOrder(1) })
+	autoOrderPanelItem := fyne.NewMenuItem(i18n.T("menu.auto_order_balloons_panel"), func() { autoOrderBalloons(true) })
+	autoOrderPageItem := fyne.NewMenuItem(i18n.T("menu.auto_order_balloons_page"), func() { autoOrderBalloons(false) })
+	balloonOrderSub := fyne.NewMenuItem(i18n.T("menu.balloon_reading_order"), nil)
+	balloonOrderSub.ChildMenu = fyne.NewMenu(i18n.T("menu.balloon_reading_order"), balloonEarlierItem, balloonLaterItem, fyne.NewMenuItemSeparator(), autoOrderPanelItem, autoOrderPageItem)
+	insertMenu := fyne.NewMenu(i18n.T("msg.insert"), insertBalloonItem, insertCaptionItem, insertSFXItem, vectorSub, balloonOrderSub, deleteSelectedItem)
+
+	// Clipboard: Copy takes the balloon clicked on the canvas, else the selected panel, and Paste adds it
+	// to the current page. The copy is kept as JSON for the whole session, so it can be pasted into
+	// another project too.
+	var clipboard []byte
+	copyItem := fyne.NewMenuItem(i18n.T("menu.copy"), func() {
+		title := i18n.T("menu.copy")
+		iss := ed.Issue()
+		if ed.Handle == nil || iss == nil {
+			dialog.ShowInformation(title, i18n.T("msg.no_project_open"), w)
+			return
+		}
+		if b, ok := canvasWidget.SelectedBalloon(); ok {
+			ball, err := storage.FindBalloon(ed.Handle, balloonPage(iss, b), b.PanelID, b.BalloonID)
+			var data []byte
+			if err == nil {
+				data, err = storage.CopyBalloon(ball)
+			}
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			clipboard = data
+			status.SetText(i18n.T("status.copied_balloon", ball.ID))
+			return
+		}
+		pg := ed.Page()
+		id, ok := ed.SelectedPanel(panelIDs)
+		if pg == nil || !ok {
+			dialog.ShowInformation(title, i18n.T("msg.select_a_panel_or_balloon_first"), w)
+			return
+		}
+		for _, pn := range pg.Panels {
+			if pn.ID != id {
+				continue
+			}
+			data, err := storage.CopyPanel(pn)
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			clipboard = data
+			status.SetText(i18n.N("status.copied_panel", len(pn.Balloons), pn.ID, len(pn.Balloons)))
+			return
+		}
 	})
-	insertMenu := fyne.NewMenu("Insert", insertBalloonItem, vectorSub, deleteSelectedItem)
+	pasteItem := fyne.NewMenuItem(i18n.T("menu.paste"), func() {
+		title := i18n.T("menu.paste")
+		if ed.Handle == nil {
+			dialog.ShowInformation(title, i18n.T("msg.no_project_open"), w)
+			return
+		}
+		c, err := storage.ParseClipboard(clipboard)
+		if err != nil {
+			dialog.ShowInformation(title, i18n.T("msg.clipboard_empty"), w)
+			return
+		}
+		pg := ed.Page()
+		if pg == nil {
+			dialog.ShowInformation(title, i18n.T("msg.no_pages_in_the_current_project"), w)
+			return
+		}
+		pageNum := pg.Number
+		var target *domain.Panel
+		if c.Balloon != nil {
+			if target = insertTargetPanel(title); target == nil {
+				return
+			}
+		}
+		var pasted controller.Selection
+		var done string
+		var next []byte
+		if c.Panel != nil {
+			pn, err := ed.PastePanel(pageNum, *c.Panel)
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			pasted = controller.Selection{Page: pageNum, PanelID: pn.ID}
+			done = i18n.T("status.pasted_panel", pn.ID, pageNum)
+			next, _ = storage.CopyPanel(pn)
+		} else {
+			b, err := ed.PasteBalloon(pageNum, target.ID, *c.Balloon)
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			pasted = controller.Selection{Page: pageNum, PanelID: target.ID, BalloonID: b.ID}
+			done = i18n.T("status.pasted_balloon", b.ID, target.ID)
+			next, _ = storage.CopyBalloon(b)
+		}
+		// Pasting again lands one offset further instead of on top of this copy
+		if next != nil {
+			clipboard = next
+		}
+		refreshPanelsUI()
+		selectEntity(pasted)
+		status.SetText(done)
+	})
+	// Ctrl+C and Ctrl+V reach these only while no text field has the focus, which keeps its own copy and
+	// paste; a menu shortcut would take precedence over the text field
+	w.Canvas().AddShortcut(&fyne.ShortcutCopy{}, func(fyne.Shortcut) { copyItem.Action() })
+	w.Canvas().AddShortcut(&fyne.ShortcutPaste{}, func(fyne.Shortcut) { pasteItem.Action() })
+	editMenu := fyne.NewMenu(i18n.T("button.edit"), undoMenuItem, redoMenuItem, undoHistoryItem, fyne.NewMenuItemSeparator(), copyItem, pasteItem, fyne.NewMenuItemSeparator(), findScriptItem, findReplaceItem, fyne.NewMenuItemSeparator(), preferencesItem, settingsItem)
 
 	// Export menu
-	exportPDFItem := fyne.NewMenuItem("Export Issue as PDF…", func() {
-		if ph == nil {
+	exportPDFItem := fyne.NewMenuItem(i18n.T("menu.export_issue_as_pdf"), func() {
+		if ed.Handle == nil {
 			l.Info("menu: export pdf (no project)")
-			dialog.ShowInformation("Export PDF", "No project open.", w)
+			dialog.ShowInformation(i18n.T("msg.export_pdf"), i18n.T("msg.no_project_open"), w)
 			return
 		}
 		save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
 			if err != nil {
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
 			if uc == nil {
@@ -2941,31 +5854,88 @@ func Run(projectDir string) error {
 			outPath := uc.URI().Path()
 			_ = uc.Close()
 			// Run synchronously on the UI thread to avoid Driver().RunOnMain incompatibilities
-			err = export.ExportIssuePDF(ph, 0, outPath, export.PDFOptions{IncludeGuides: true})
+			opt := export.DefaultPDFOptions()
+			opt.IncludeGuides = true
+			err = export.ExportIssuePDF(ed.Handle, 0, outPath, opt)
 			if err != nil {
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 			} else {
-				dialog.ShowInformation("Export PDF", "Exported to "+outPath, w)
+				dialog.ShowInformation(i18n.T("msg.export_pdf"), i18n.T("msg.exported_to", outPath), w)
 			}
 		}, w)
 		defName := "issue-1.pdf"
-		if ph != nil && len(ph.Project.Issues) > 0 {
+		if ed.Handle != nil && len(ed.Handle.Project.Issues) > 0 {
 			defName = fmt.Sprintf("issue-%d.pdf", 1)
 		}
 		save.SetFileName(defName)
 		save.SetFilter(fstorage.NewExtensionFileFilter([]string{".pdf"}))
-		save.Show()
+		showPreflight(w, ed.Handle, 0, save.Show)
 	})
 
-	exportPNGItem := fyne.NewMenuItem("Export Issue as PNG pages…", func() {
-		if ph == nil {
+	exportPDFXItem := fyne.NewMenuItem(i18n.T("menu.export_issue_as_pdf_x_1a"), func() {
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.export_pdf_x_1a"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		iccEntry := widget.NewEntry()
+		iccEntry.SetPlaceHolder(i18n.T("placeholder.bundled_naive_cmyk_profile"))
+		iccBrowse := widget.NewButton(i18n.T("button.browse"), func() {
+			od := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+				if err != nil || rc == nil {
+					return
+				}
+				iccEntry.SetText(rc.URI().Path())
+				_ = rc.Close()
+			}, w)
+			od.SetFilter(fstorage.NewExtensionFileFilter([]string{".icc", ".icm"}))
+			od.Show()
+		})
+		condEntry := widget.NewEntry()
+		condEntry.SetPlaceHolder(i18n.T("placeholder.e_g_fogra39_optional"))
+		items := []*widget.FormItem{
+			widget.NewFormItem(i18n.T("form.cmyk_icc_profile"), container.NewBorder(nil, nil, nil, iccBrowse, iccEntry)),
+			widget.NewFormItem(i18n.T("form.output_condition"), condEntry),
+		}
+		dialog.ShowForm(i18n.T("msg.export_pdf_x_1a"), i18n.T("msg.choose_file"), i18n.T("msg.cancel"), items, func(ok bool) {
+			if !ok {
+				return
+			}
+			opt := export.DefaultPDFOptions()
+			opt.OutputProfile = export.OutputProfilePDFX1a
+			opt.ICCProfilePath = strings.TrimSpace(iccEntry.Text)
+			opt.OutputCondition = strings.TrimSpace(condEntry.Text)
+			save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+				if err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				if uc == nil {
+					return
+				}
+				outPath := uc.URI().Path()
+				_ = uc.Close()
+				if err := export.ExportIssuePDF(ed.Handle, 0, outPath, opt); err != nil {
+					l.Error("export pdf/x-1a failed", slog.String("path", outPath), slog.Any("err", err))
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				dialog.ShowInformation(i18n.T("msg.export_pdf_x_1a"), i18n.T("msg.exported_to", outPath), w)
+			}, w)
+			save.SetFileName("issue-1-print.pdf")
+			save.SetFilter(fstorage.NewExtensionFileFilter([]string{".pdf"}))
+			showPreflight(w, ed.Handle, 0, save.Show)
+		}, w)
+	})
+
+	exportPNGItem := fyne.NewMenuItem(i18n.T("menu.export_issue_as_png_pages"), func() {
+		if ed.Handle == nil {
 			l.Info("menu: export png (no project)")
-			dialog.ShowInformation("Export PNG", "No project open.", w)
+			dialog.ShowInformation(i18n.T("msg.export_png"), i18n.T("msg.no_project_open"), w)
 			return
 		}
 		fd := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
 			if err != nil {
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
 			if uri == nil {
@@ -2973,25 +5943,25 @@ func Run(projectDir string) error {
 			}
 			outDir := uri.Path()
 			// Run synchronously on the UI thread
-			err = export.ExportIssuePNGPages(ph, 0, outDir, export.PNGOptions{IncludeGuides: true})
+			err = export.ExportIssuePNGPages(ed.Handle, 0, outDir, export.PNGOptions{IncludeGuides: true})
 			if err != nil {
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 			} else {
-				dialog.ShowInformation("Export PNG", "Exported pages to "+outDir, w)
+				dialog.ShowInformation(i18n.T("msg.export_png"), i18n.T("msg.exported_pages_to", outDir), w)
 			}
 		}, w)
 		fd.Show()
 	})
 
-	exportSVGItem := fyne.NewMenuItem("Export Issue as SVG pages…", func() {
-		if ph == nil {
+	exportSVGItem := fyne.NewMenuItem(i18n.T("menu.export_issue_as_svg_pages"), func() {
+		if ed.Handle == nil {
 			l.Info("menu: export svg (no project)")
-			dialog.ShowInformation("Export SVG", "No project open.", w)
+			dialog.ShowInformation(i18n.T("msg.export_svg"), i18n.T("msg.no_project_open"), w)
 			return
 		}
 		fd := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
 			if err != nil {
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
 			if uri == nil {
@@ -2999,25 +5969,25 @@ func Run(projectDir string) error {
 			}
 			outDir := uri.Path()
 			// Run synchronously on the UI thread
-			err = export.ExportIssueSVGPages(ph, 0, outDir, export.SVGOptions{IncludeGuides: true})
+			err = export.ExportIssueSVGPages(ed.Handle, 0, outDir, export.SVGOptions{IncludeGuides: true})
 			if err != nil {
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 			} else {
-				dialog.ShowInformation("Export SVG", "Exported pages to "+outDir, w)
+				dialog.ShowInformation(i18n.T("msg.export_svg"), i18n.T("msg.exported_pages_to", outDir), w)
 			}
 		}, w)
 		fd.Show()
 	})
 
-	exportCBZItem := fyne.NewMenuItem("Export Issue as CBZ…", func() {
-		if ph == nil {
+	exportCBZItem := fyne.NewMenuItem(i18n.T("menu.export_issue_as_cbz"), func() {
+		if ed.Handle == nil {
 			l.Info("menu: export cbz (no project)")
-			dialog.ShowInformation("Export CBZ", "No project open.", w)
+			dialog.ShowInformation(i18n.T("msg.export_cbz"), i18n.T("msg.no_project_open"), w)
 			return
 		}
 		save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
 			if err != nil {
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
 			if uc == nil {
@@ -3026,32 +5996,91 @@ func Run(projectDir string) error {
 			outPath := uc.URI().Path()
 			_ = uc.Close()
 			// Run synchronously on the UI thread
-			err = export.ExportIssueCBZ(ph, 0, outPath, export.CBZOptions{IncludeGuides: true})
+			err = export.ExportIssueCBZ(ed.Handle, 0, outPath, export.CBZOptions{IncludeGuides: true})
 			if err != nil {
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 			} else {
-				dialog.ShowInformation("Export CBZ", "Exported to "+outPath, w)
+				dialog.ShowInformation(i18n.T("msg.export_cbz"), i18n.T("msg.exported_to", outPath), w)
 			}
 		}, w)
 		defName := "issue-1.cbz"
-		if ph != nil && len(ph.Project.Issues) > 0 {
+		if ed.Handle != nil && len(ed.Handle.Project.Issues) > 0 {
 			defName = fmt.Sprintf("issue-%d.cbz", 1)
 		}
 		save.SetFileName(defName)
 		save.SetFilter(fstorage.NewExtensionFileFilter([]string{".cbz"}))
-		save.Show()
+		showPreflight(w, ed.Handle, 0, save.Show)
+	})
+
+	// Webtoon export: all pages stacked into one tall PNG at a fixed width
+	exportWebtoonItem := fyne.NewMenuItem(i18n.T("menu.export_issue_as_webtoon_strip"), func() {
+		if ed.Handle == nil {
+			l.Info("menu: export webtoon (no project)")
+			dialog.ShowInformation(i18n.T("msg.export_webtoon_strip"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		widthEntry := widget.NewEntry()
+		widthEntry.SetText("800")
+		gapEntry := widget.NewEntry()
+		gapEntry.SetText("0")
+		dialog.ShowForm(i18n.T("msg.export_webtoon_strip"), i18n.T("msg.next"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.T("form.width_px"), widthEntry),
+			widget.NewFormItem(i18n.T("form.gap_between_pages_px"), gapEntry),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			width, errW := strconv.Atoi(strings.TrimSpace(widthEntry.Text))
+			gap, errG := strconv.Atoi(strings.TrimSpace(gapEntry.Text))
+			if errW != nil || errG != nil || width <= 0 || gap < 0 {
+				dialog.ShowError(errors.New(i18n.T("msg.invalid_strip_width")), w)
+				return
+			}
+			save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+				if err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				if uc == nil {
+					return
+				}
+				outPath := uc.URI().Path()
+				_ = uc.Close()
+				// Run synchronously on the UI thread
+				files, err := export.ExportIssueWebtoonStrip(ed.Handle, 0, outPath, export.WebtoonOptions{Width: width, Gap: gap})
+				if err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				if !slices.Contains(files, outPath) {
+					// Split into parts or given a .png suffix: drop the empty file the save dialog created
+					_ = os.Remove(outPath)
+				}
+				if len(files) > 1 {
+					dialog.ShowInformation(i18n.T("msg.export_webtoon_strip"), i18n.T("msg.the_strip_exceeds_px_and_was", export.WebtoonMaxHeight, len(files), strings.Join(files, "\n")), w)
+					return
+				}
+				dialog.ShowInformation(i18n.T("msg.export_webtoon_strip"), i18n.T("msg.exported_to", files[0]), w)
+			}, w)
+			save.SetFileName("issue-1-webtoon.png")
+			save.SetFilter(fstorage.NewExtensionFileFilter([]string{".png"}))
+			save.Show()
+		}, w)
 	})
 
 	// EPUB export menu entry
-	exportEPUBItem := fyne.NewMenuItem("Export Issue as EPUB…", func() {
-		if ph == nil {
+	exportEPUBItem := fyne.NewMenuItem(i18n.T("menu.export_issue_as_epub"), func() {
+		if ed.Handle == nil {
 			l.Info("menu: export epub (no project)")
-			dialog.ShowInformation("Export EPUB", "No project open.", w)
+			dialog.ShowInformation(i18n.T("msg.export_epub"), i18n.T("msg.no_project_open"), w)
 			return
 		}
+		fixedOpt, reflowOpt := i18n.T("option.fixed_layout"), i18n.T("option.reflowable")
+		layoutRadio := widget.NewRadioGroup([]string{fixedOpt, reflowOpt}, nil)
+		layoutRadio.SetSelected(fixedOpt)
 		save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
 			if err != nil {
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 				return
 			}
 			if uc == nil {
@@ -3060,119 +6089,699 @@ func Run(projectDir string) error {
 			outPath := uc.URI().Path()
 			_ = uc.Close()
 			// Run synchronously on the UI thread
-			err = export.ExportIssueEPUB(ph, 0, outPath, export.EPUBOptions{IncludeGuides: true, Language: "en", FixedLayout: true})
+			fixed := layoutRadio.Selected != reflowOpt
+			err = export.ExportIssueEPUB(ed.Handle, 0, outPath, export.EPUBOptions{IncludeGuides: fixed, FixedLayout: fixed})
 			if err != nil {
-				dialog.ShowError(err, w)
+				dialog.ShowError(FriendlyError(err), w)
 			} else {
-				dialog.ShowInformation("Export EPUB", "Exported to "+outPath, w)
+				dialog.ShowInformation(i18n.T("msg.export_epub"), i18n.T("msg.exported_to", outPath), w)
 			}
 		}, w)
 		defName := "issue-1.epub"
-		if ph != nil && len(ph.Project.Issues) > 0 {
+		if ed.Handle != nil && len(ed.Handle.Project.Issues) > 0 {
 			defName = fmt.Sprintf("issue-%d.epub", 1)
 		}
 		save.SetFileName(defName)
 		save.SetFilter(fstorage.NewExtensionFileFilter([]string{".epub"}))
-		save.Show()
+		savePresetBtn := widget.NewButton(i18n.T("button.save_as_preset"), func() {
+			fixed := layoutRadio.Selected != reflowOpt
+			initial := domain.ExportPreset{Format: "epub", IncludeGuides: fixed, EPUB: &domain.EPUBMetadata{Language: ed.Handle.Project.Metadata.LanguageISO, Reflowable: !fixed}}
+			showExportPresetForm(w, ed.Handle, l, status, initial, refreshPresetMenu)
+		})
+		dialog.ShowCustomConfirm(i18n.T("msg.export_epub"), i18n.T("msg.continue"), i18n.T("msg.cancel"), container.NewVBox(layoutRadio, savePresetBtn), func(ok bool) {
+			if ok {
+				save.Show()
+			}
+		}, w)
+	})
+
+	// Lettering script: the script laid out by page and panel for the letterer
+	exportLetteringItem := fyne.NewMenuItem(i18n.T("menu.export_lettering_script"), func() {
+		if ed.Handle == nil {
+			l.Info("menu: export lettering script (no project)")
+			dialog.ShowInformation(i18n.T("msg.export_lettering_script"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		formats := map[string]export.LetteringFormat{
+			i18n.T("option.lettering_pdf"):      export.LetteringPDF,
+			i18n.T("option.lettering_text"):     export.LetteringText,
+			i18n.T("option.lettering_markdown"): export.LetteringMarkdown,
+		}
+		formatRadio := widget.NewRadioGroup([]string{i18n.T("option.lettering_pdf"), i18n.T("option.lettering_text"), i18n.T("option.lettering_markdown")}, nil)
+		formatRadio.SetSelected(i18n.T("option.lettering_pdf"))
+		formatRadio.Required = true
+		dialog.ShowCustomConfirm(i18n.T("msg.export_lettering_script"), i18n.T("msg.continue"), i18n.T("msg.cancel"), formatRadio, func(ok bool) {
+			if !ok {
+				return
+			}
+			format := formats[formatRadio.Selected]
+			issueIdx := ed.IssueIdx
+			save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+				if err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				if uc == nil {
+					return
+				}
+				outPath := uc.URI().Path()
+				_ = uc.Close()
+				// Run synchronously on the UI thread
+				if err := export.ExportLetteringScript(ed.Handle, issueIdx, outPath, format); err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				dialog.ShowInformation(i18n.T("msg.export_lettering_script"), i18n.T("msg.exported_to", outPath), w)
+			}, w)
+			save.SetFileName(fmt.Sprintf("issue-%d-lettering.%s", issueIdx+1, format))
+			save.SetFilter(fstorage.NewExtensionFileFilter([]string{"." + string(format)}))
+			save.Show()
+		}, w)
 	})
 
-	exportMenu := fyne.NewMenu("Export", exportPDFItem, exportPNGItem, exportSVGItem, exportCBZItem, exportEPUBItem)
+	// Export with Preset: project-level presets stored in the manifest
+	runPreset := func(p domain.ExportPreset) {
+		if ed.Handle == nil {
+			dialog.ShowInformation(i18n.T("msg.export_with_preset"), i18n.T("msg.no_project_open"), w)
+			return
+		}
+		l.Info("menu: export with preset", slog.String("preset", p.Name), slog.String("format", p.Format))
+		done := func(outPath string) {
+			// Run synchronously on the UI thread
+			if err := export.ExportWithPreset(ed.Handle, ed.IssueIdx, p, outPath); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			projSettings.ExportPreset = p.Name
+			refreshPresetMenu()
+			dialog.ShowInformation(i18n.T("msg.export_with_preset"), i18n.T("msg.exported_name_to", p.Name, outPath), w)
+		}
+		if p.Format == "png" || p.Format == "svg" {
+			fd := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+				if err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				if uri != nil {
+					done(uri.Path())
+				}
+			}, w)
+			fd.Show()
+			return
+		}
+		save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			if uc == nil {
+				return
+			}
+			outPath := uc.URI().Path()
+			_ = uc.Close()
+			done(outPath)
+		}, w)
+		save.SetFileName(fmt.Sprintf("issue-%d.%s", ed.IssueIdx+1, p.Format))
+		save.SetFilter(fstorage.NewExtensionFileFilter([]string{"." + p.Format}))
+		if p.Format == "pdf" || p.Format == "cbz" {
+			showPreflight(w, ed.Handle, ed.IssueIdx, save.Show)
+			return
+		}
+		save.Show()
+	}
+	exportPresetItem := fyne.NewMenuItem(i18n.T("msg.export_with_preset"), nil)
+	exportPresetItem.ChildMenu = fyne.NewMenu("")
+	var mainMenu *fyne.MainMenu
+	refreshPresetMenu = func() {
+		var items []*fyne.MenuItem
+		if ed.Handle != nil {
+			// The preset used last in this project comes first
+			for _, p := range ed.Handle.Project.ExportPresets {
+				item := fyne.NewMenuItem(fmt.Sprintf("%s (%s)", p.Name, strings.ToUpper(p.Format)), func() { runPreset(p) })
+				if strings.EqualFold(p.Name, projSettings.ExportPreset) {
+					item.Label += " — last used"
+					items = append([]*fyne.MenuItem{item}, items...)
+					continue
+				}
+				items = append(items, item)
+			}
+		}
+		if len(items) == 0 {
+			none := fyne.NewMenuItem(i18n.T("menu.no_presets"), nil)
+			none.Disabled = true
+			items = append(items, none)
+		}
+		newItem := fyne.NewMenuItem(i18n.T("menu.new_preset"), func() {
+			if ed.Handle == nil {
+				dialog.ShowInformation(i18n.T("msg.export_preset"), i18n.T("msg.no_project_open"), w)
+				return
+			}
+			showExportPresetForm(w, ed.Handle, l, status, domain.ExportPreset{Format: "pdf", IncludeGuides: true}, refreshPresetMenu)
+		})
+		deleteItem := fyne.NewMenuItem(i18n.T("menu.delete_preset"), func() {
+			if ed.Handle == nil || len(ed.Handle.Project.ExportPresets) == 0 {
+				dialog.ShowInformation(i18n.T("msg.delete_preset"), i18n.T("msg.there_are_no_presets_to_delete"), w)
+				return
+			}
+			var names []string
+			for _, p := range ed.Handle.Project.ExportPresets {
+				names = append(names, p.Name)
+			}
+			sel := widget.NewSelect(names, nil)
+			sel.SetSelected(names[0])
+			dialog.ShowCustomConfirm(i18n.T("msg.delete_preset"), i18n.T("button.delete"), i18n.T("msg.cancel"), sel, func(ok bool) {
+				if !ok || sel.Selected == "" {
+					return
+				}
+				if err := storage.DeleteExportPreset(ed.Handle, sel.Selected); err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				if err := storage.Save(ed.Handle); err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				status.SetText(i18n.T("status.deleted_export_preset", sel.Selected))
+				refreshPresetMenu()
+			}, w)
+		})
+		items = append(items, fyne.NewMenuItemSeparator(), newItem, deleteItem)
+		exportPresetItem.ChildMenu.Items = items
+		commands.setGroup(exportPresetItem.Label, menuCommands(exportPresetItem.ChildMenu))
+		if mainMenu != nil {
+			mainMenu.Refresh()
+		}
+	}
+	refreshPresetMenu()
+	refreshRecent = func() {
+		var items []*fyne.MenuItem
+		entries := loadRecentList(prefs).entries()
+		for i, e := range entries {
+			if i > 0 && entries[i-1].Pinned && !e.Pinned {
+				items = append(items, fyne.NewMenuItemSeparator())
+			}
+			path := e.Path
+			if e.Missing {
+				item := fyne.NewMenuItem(i18n.T("label.recent_missing", path), nil)
+				item.ChildMenu = fyne.NewMenu("",
+					fyne.NewMenuItem(i18n.T("menu.locate"), func() { locateRecentProject(path) }),
+					fyne.NewMenuItem(i18n.T("menu.remove_from_list"), func() {
+						loadRecentList(prefs).remove(path).save(prefs)
+						refreshRecent()
+					}),
+				)
+				items = append(items, item)
+				continue
+			}
+			item := fyne.NewMenuItem(path, func() {
+				l.Info("menu: open recent project", slog.String("root", path))
+				openProjectFolder(path)
+			})
+			item.Checked = e.Pinned
+			items = append(items, item)
+		}
+		if len(items) == 0 {
+			none := fyne.NewMenuItem(i18n.T("menu.no_recent_projects"), nil)
+			none.Disabled = true
+			items = append(items, none)
+		}
+		pinItem := fyne.NewMenuItem(i18n.T("menu.pin_current_project"), nil)
+		if ed.Handle == nil {
+			pinItem.Disabled = true
+		} else {
+			projRoot := ed.Handle.Root
+			pinned := loadRecentList(prefs).isPinned(projRoot)
+			if pinned {
+				pinItem.Label = i18n.T("menu.unpin_current_project")
+			}
+			pinItem.Action = func() {
+				loadRecentList(prefs).pin(projRoot, !pinned).save(prefs)
+				refreshRecent()
+			}
+		}
+		items = append(items, fyne.NewMenuItemSeparator(), pinItem)
+		openRecentItem.ChildMenu.Items = items
+		commands.setGroup(openRecentItem.Label, menuCommands(openRecentItem.ChildMenu))
+		// The dashboard lists the same projects; rebuild it, right away when it is showing.
+		showing := dashboard != nil && len(root.Objects) > 0 && root.Objects[0] == dashboard
+		dashboard = nil
+		if showing {
+			showDashboard()
+		}
+		if mainMenu != nil {
+			mainMenu.Refresh()
+		}
+	}
+	refreshRecent()
+
+	preflightItem := fyne.NewMenuItem(i18n.T("menu.preflight"), func() {
+		if ed.Handle == nil || len(ed.Handle.Project.Issues) == 0 {
+			dialog.ShowInformation(i18n.T("msg.preflight"), i18n.T("msg.no_issue_open"), w)
+			return
+		}
+		l.Info("menu: preflight", slog.Int("issue", ed.IssueIdx+1))
+		showPreflight(w, ed.Handle, ed.IssueIdx, nil)
+	})
+	exportMenu := fyne.NewMenu(i18n.T("menu.export"), preflightItem, fyne.NewMenuItemSeparator(), exportPDFItem, exportPDFXItem, exportPNGItem, exportSVGItem, exportCBZItem, exportEPUBItem, exportWebtoonItem, exportLetteringItem, fyne.NewMenuItemSeparator(), exportPresetItem)
 
-	aboutItem := fyne.NewMenuItem("About Go Comic Writer", func() {
+	aboutItem := fyne.NewMenuItem(i18n.T("menu.about_go_comic_writer"), func() {
 		l.Info("menu: about")
 		exe, _ := os.Executable()
 		cwd, _ := os.Getwd()
-		info := fmt.Sprintf("Go Comic Writer\nVersion: %s\nOS: %s\nArch: %s\nGo: %s\nExecutable: %s\nWorking Dir: %s",
+		info := i18n.T("msg.installation_environment_info",
 			version.String(), runtime.GOOS, runtime.GOARCH, runtime.Version(), exe, cwd)
-		dialog.ShowInformation("Installation Environment", info, w)
+		dialog.ShowInformation(i18n.T("msg.installation_environment"), info, w)
 	})
-	copyrightItem := fyne.NewMenuItem("Copyright…", func() {
+	copyrightItem := fyne.NewMenuItem(i18n.T("menu.copyright"), func() {
 		l.Info("menu: copyright")
 		currentYear := time.Now().Year()
-		msg := fmt.Sprintf("Go Comic Writer\nCopyright © 2023-%d The Go Comic Writer Authors\n\nLicensed under the Apache License, Version 2.0.\nSee the LICENSE file for details.", currentYear)
-		dialog.ShowInformation("Copyright", msg, w)
+		msg := i18n.T("msg.copyright_info", currentYear)
+		dialog.ShowInformation(i18n.T("msg.copyright"), msg, w)
 	})
-	aboutMenu := fyne.NewMenu("About", aboutItem, copyrightItem)
+	aboutMenu := fyne.NewMenu(i18n.T("menu.about"), aboutItem, copyrightItem)
 
-	menus := []*fyne.Menu{fileMenu, editMenu, issueMenu, insertMenu, exportMenu}
+	menus := []*fyne.Menu{fileMenu, editMenu, viewMenu, issueMenu, insertMenu, exportMenu}
 	if serverFeatureEnabled() {
-		connectItem := fyne.NewMenuItem("Connect to Server…", func() { showServerConnectDialog() })
-		grantItem := fyne.NewMenuItem("Grant Project Access…", func() { showGrantAccessDialog() })
-		serverMenu := fyne.NewMenu("Server", connectItem, grantItem)
+		connectItem := fyne.NewMenuItem(i18n.T("menu.connect_to_server"), func() { showServerConnectDialog() })
+		grantItem := fyne.NewMenuItem(i18n.T("menu.grant_project_access"), func() { showGrantAccessDialog() })
+		pushItem := fyne.NewMenuItem(i18n.T("msg.push_local_changes"), func() { showPushChanges() })
+		serverMenu := fyne.NewMenu(i18n.T("msg.server"), connectItem, grantItem, pushItem)
 		menus = append(menus, serverMenu)
 	}
 	menus = append(menus, aboutMenu)
-	w.SetMainMenu(fyne.NewMainMenu(menus...))
+	for _, m := range menus {
+		commands.setGroup(m.Label, menuCommands(m, exportPresetItem, quickOpenItem))
+	}
+	openQuickOpen = func() {
+		cmds := commands.enabled()
+		var items []quickItem
+		if ed.Handle != nil {
+			items = quickOpenItems(ed.Issue(), ed.Handle.Project.Bible, cmds)
+		} else {
+			items = quickOpenItems(nil, domain.Bible{}, cmds)
+		}
+		showQuickOpen(w, items, func(it quickItem) {
+			selectRow := func(list *widget.List, idx []int) {
+				for row, bi := range idx {
+					if bi == it.Index {
+						list.Select(row)
+						list.ScrollTo(row)
+						return
+					}
+				}
+			}
+			switch it.Kind {
+			case quickPage, quickPanel:
+				tabs.SelectIndex(0)
+				r := storage.SearchResult{PageID: it.Page}
+				if it.PanelID != "" {
+					r.Path = panelCommentPath(it.Page, it.PanelID)
+				}
+				navigateToResult(r)
+			case quickCharacter:
+				tabs.SelectIndex(4)
+				selectRow(charList, charIdx)
+			case quickLocation:
+				tabs.SelectIndex(4)
+				selectRow(locList, locIdx)
+			case quickTag:
+				tabs.SelectIndex(4)
+				selectRow(tagList, tagIdx)
+			case quickCommand:
+				if it.Index >= 0 && it.Index < len(cmds) {
+					l.Info("quick open: command", slog.String("group", cmds[it.Index].Group), slog.String("title", cmds[it.Index].Title))
+					cmds[it.Index].Run()
+				}
+			}
+		})
+	}
+	mainMenu = fyne.NewMainMenu(menus...)
+	w.SetMainMenu(mainMenu)
 
 	// Persist preferences on close
 	w.SetCloseIntercept(func() {
 		sz := w.Canvas().Size()
 		prefs.SetInt("window.width", int(sz.Width))
 		prefs.SetInt("window.height", int(sz.Height))
-		prefs.SetBool("overlay.beats", canvasWidget.beatOverlay)
+		workspace.Store(prefs)
+		storeProjectSettings()
+		assetsWatch.Close()
+		indexChk.Close()
+		presence.Close()
+		syncWorker.Close()
 		w.Close()
 	})
 
 	// Try to open a project if provided
 	if projectDir != "" {
-		if err := openProject(projectDir, &ph, w, l, status); err != nil {
+		if err := openProject(projectDir, &ed.Handle, w, l, status); err != nil {
 			l.Error("auto-open project failed", slog.Any("err", err))
 			// not fatal; continue
 		} else {
-			if txt, rerr := storage.ReadScript(ph); rerr == nil {
+			trackSyncChanges()
+			checkCrashRecovery()
+			checkIndexVersion()
+			if txt, rerr := storage.ReadScript(ed.Handle); rerr == nil {
 				scriptEntry.SetText(txt)
 				lastScriptSnapText = txt
 				lastScriptSnapTS = time.Now()
 				updateOutline(txt)
 				refreshBible()
-				if len(ph.Project.Issues) > 0 {
-					canvasWidget.ApplyIssue(ph.Project.Issues[0])
-					currentIssueIdx = 0
-					currentPageIdx = 0
+				if len(ed.Handle.Project.Issues) > 0 {
+					canvasWidget.ApplyIssue(ed.Handle.Project.Issues[0])
+					ed.IssueIdx = 0
+					ed.PageIdx = 0
 					refreshPagesList()
 				}
 				refreshPanelsUI()
+				applyProjectSettings()
+				refreshPresetMenu()
+				restartAssetsWatcher()
+				restartIndexCheck()
+				restartPresence()
+				restartSync()
+				showValidation()
+				offerPageNumberRepair(false)
 				addRecentProject(prefs, projectDir)
+				refreshRecent()
 			} else {
 				l.Error("read script failed", slog.Any("err", rerr))
 			}
 		}
 	}
 
+	if ed.Handle == nil {
+		showDashboard()
+	}
+
+	w.ShowAndRun()
+	_ = ed.Handle.Close()
+	return nil
+}
+
+func openProject(dir string, ph **storage.ProjectHandle, w fyne.Window, l *slog.Logger, status *widget.Label) error {
+	abs, _ := filepath.Abs(dir)
+	l.Info("open project", slog.String("root", abs))
+	h, err := storage.Open(abs)
+	if err != nil {
+		return err
+	}
+	_ = (*ph).Close()
+	*ph = h
+	w.SetTitle(i18n.T("title.go_comic_writer_project", h.Project.Name))
+	status.SetText(i18n.T("status.opened_project", abs))
+	// Anonymous telemetry event (no PII): include basic project stats only.
+	if telemetry.Enabled() {
+		issueCount := len(h.Project.Issues)
+		pageCount := 0
+		if issueCount > 0 {
+			for _, iss := range h.Project.Issues {
+				pageCount += len(iss.Pages)
+			}
+		}
+		telemetry.Event("project_open", map[string]any{"issues": issueCount, "pages": pageCount})
+	}
+	return nil
+}
+
+// offerCrashRecovery prunes crash autosaves older than retention and, if any remaining snapshot
+// is newer than the manifest, asks whether to restore it, keep the current manifest, or delete the snapshots.
+func offerCrashRecovery(w fyne.Window, ph *storage.ProjectHandle, retention time.Duration, l *slog.Logger, status *widget.Label, onRestored func()) {
+	if ph == nil {
+		return
+	}
+	if _, err := storage.PruneRecoverySnapshots(ph.Root, retention); err != nil {
+		l.Warn("prune crash snapshots failed", slog.Any("err", err))
+	}
+	snaps, err := storage.FindRecoverySnapshots(ph.Root)
+	if err != nil {
+		l.Warn("find crash snapshots failed", slog.Any("err", err))
+		return
+	}
+	if len(snaps) == 0 {
+		return
+	}
+	selected := 0
+	list := widget.NewList(
+		func() int { return len(snaps) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			s := snaps[id]
+			o.(*widget.Label).SetText(i18n.T("label.autosave_entry", s.ModTime.Format("2006-01-02 15:04:05"), filepath.Base(s.Path), (s.Size+1023)/1024))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = int(id) }
+	list.Select(0)
+	info := widget.NewLabel(i18n.T("label.go_comic_writer_did_not_shut"))
+	info.Wrapping = fyne.TextWrapWord
+
+	var d dialog.Dialog
+	restoreBtn := widget.NewButton(i18n.T("button.restore"), func() {
+		if selected < 0 || selected >= len(snaps) {
+			return
+		}
+		if err := storage.RestoreRecoverySnapshot(ph, snaps[selected].Path); err != nil {
+			l.Error("restore crash snapshot failed", slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		d.Hide()
+		status.SetText(i18n.T("status.restored_autosave_from", snaps[selected].ModTime.Format("2006-01-02 15:04:05")))
+		if onRestored != nil {
+			onRestored()
+		}
+	})
+	restoreBtn.Importance = widget.HighImportance
+	keepBtn := widget.NewButton(i18n.T("button.keep_current"), func() {
+		d.Hide()
+		status.SetText(i18n.T("status.kept_current_project_autosave_snapshots_left"))
+	})
+	deleteBtn := widget.NewButton(i18n.T("button.delete_snapshots"), func() {
+		n, err := storage.DeleteRecoverySnapshots(ph.Root)
+		if err != nil {
+			l.Error("delete crash snapshots failed", slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		d.Hide()
+		status.SetText(i18n.N("status.deleted_autosave_snapshots", n))
+	})
+	content := container.NewBorder(info, container.NewHBox(deleteBtn, keepBtn, restoreBtn), nil, nil, list)
+	d = dialog.NewCustomWithoutButtons(i18n.T("msg.recover_unsaved_work"), content, w)
+	d.Resize(fyne.NewSize(560, 320))
+	d.Show()
+}
+
+// showBackupsDialog lists the project's manifest backups (newest first) with a metadata preview
+// and lets the user restore one (the current manifest is backed up first) or delete one after confirmation.
+// showExportPresetForm edits a project export preset prefilled from initial and saves it to the manifest.
+func showExportPresetForm(w fyne.Window, ph *storage.ProjectHandle, l *slog.Logger, status *widget.Label, initial domain.ExportPreset, onSaved func()) {
+	if ph == nil {
+		return
+	}
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(initial.Name)
+	formatSel := widget.NewSelect(storage.ExportPresetFormats, nil)
+	formatSel.SetSelected(initial.Format)
+	dpiEntry := widget.NewEntry()
+	dpiEntry.SetPlaceHolder(i18n.T("placeholder.issue_default"))
+	if initial.DPI > 0 {
+		dpiEntry.SetText(strconv.Itoa(initial.DPI))
+	}
+	guidesCheck := widget.NewCheck(i18n.T("check.include_guides"), nil)
+	guidesCheck.SetChecked(initial.IncludeGuides)
+	pagesEntry := widget.NewEntry()
+	pagesEntry.SetPlaceHolder(i18n.T("placeholder.all_pages_or_e_g_1"))
+	pagesEntry.SetText(initial.Pages)
+	meta := domain.EPUBMetadata{}
+	if initial.EPUB != nil {
+		meta = *initial.EPUB
+	}
+	titleEntry := widget.NewEntry()
+	titleEntry.SetText(meta.Title)
+	authorEntry := widget.NewEntry()
+	authorEntry.SetText(meta.Author)
+	langEntry := widget.NewEntry()
+	langEntry.SetText(meta.Language)
+	reflowCheck := widget.NewCheck(i18n.T("check.reflowable_text"), nil)
+	reflowCheck.SetChecked(meta.Reflowable)
+	items := []*widget.FormItem{
+		widget.NewFormItem(i18n.T("form.name"), nameEntry),
+		widget.NewFormItem(i18n.T("form.format"), formatSel),
+		widget.NewFormItem(i18n.T("form.dpi"), dpiEntry),
+		widget.NewFormItem("", guidesCheck),
+		widget.NewFormItem(i18n.T("label.pages"), pagesEntry),
+		widget.NewFormItem(i18n.T("form.epub_title"), titleEntry),
+		widget.NewFormItem(i18n.T("form.epub_author"), authorEntry),
+		widget.NewFormItem(i18n.T("form.epub_language"), langEntry),
+		widget.NewFormItem("", reflowCheck),
+	}
+	dialog.ShowForm(i18n.T("msg.save_export_preset"), i18n.T("msg.save"), i18n.T("msg.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		p := domain.ExportPreset{
+			Name:          nameEntry.Text,
+			Format:        formatSel.Selected,
+			IncludeGuides: guidesCheck.Checked,
+			Pages:         pagesEntry.Text,
+		}
+		if txt := strings.TrimSpace(dpiEntry.Text); txt != "" {
+			dpi, err := strconv.Atoi(txt)
+			if err != nil || dpi <= 0 {
+				dialog.ShowError(errors.New(i18n.T("msg.invalid_dpi", txt)), w)
+				return
+			}
+			p.DPI = dpi
+		}
+		if p.Format == "epub" {
+			m := domain.EPUBMetadata{
+				Title:      strings.TrimSpace(titleEntry.Text),
+				Author:     strings.TrimSpace(authorEntry.Text),
+				Language:   strings.TrimSpace(langEntry.Text),
+				Reflowable: reflowCheck.Checked,
+			}
+			m.Publisher, m.Description, m.Series, m.SeriesIndex = meta.Publisher, meta.Description, meta.Series, meta.SeriesIndex
+			if m != (domain.EPUBMetadata{}) {
+				p.EPUB = &m
+			}
+		}
+		if err := storage.PutExportPreset(ph, p); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		if err := storage.Save(ph); err != nil {
+			l.Error("save export preset failed", slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		l.Info("export preset saved", slog.String("preset", strings.TrimSpace(p.Name)), slog.String("format", p.Format))
+		status.SetText(i18n.T("status.saved_export_preset", strings.TrimSpace(p.Name)))
+		if onSaved != nil {
+			onSaved()
+		}
+	}, w)
+}
+
+func showBackupsDialog(w fyne.Window, ph *storage.ProjectHandle, l *slog.Logger, status *widget.Label, onRestored func()) {
 	if ph == nil {
-		showDashboard()
+		return
 	}
-
-	w.ShowAndRun()
-	return nil
-}
-
-func openProject(dir string, ph **storage.ProjectHandle, w fyne.Window, l *slog.Logger, status *widget.Label) error {
-	abs, _ := filepath.Abs(dir)
-	l.Info("open project", slog.String("root", abs))
-	h, err := storage.Open(abs)
+	backups, err := storage.ListBackups(ph.Root)
 	if err != nil {
-		return err
+		l.Error("list backups failed", slog.Any("err", err))
+		dialog.ShowError(FriendlyError(err), w)
+		return
 	}
-	*ph = h
-	w.SetTitle(fmt.Sprintf("Go Comic Writer — %s", h.Project.Name))
-	status.SetText(fmt.Sprintf("Opened project: %s", abs))
-	// Anonymous telemetry event (no PII): include basic project stats only.
-	if telemetry.Enabled() {
-		issueCount := len(h.Project.Issues)
-		pageCount := 0
-		if issueCount > 0 {
-			for _, iss := range h.Project.Issues {
-				pageCount += len(iss.Pages)
+	if len(backups) == 0 {
+		dialog.ShowInformation(i18n.T("msg.backups"), i18n.T("msg.no_backups_yet_a_backup_is"), w)
+		return
+	}
+	selected := -1
+	preview := widget.NewLabel(i18n.T("label.select_a_backup_to_preview_it"))
+	preview.Wrapping = fyne.TextWrapWord
+	var restoreBtn, deleteBtn *widget.Button
+	list := widget.NewList(
+		func() int { return len(backups) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			b := backups[id]
+			kind := "full"
+			if b.Delta {
+				kind = "Δ changes"
+			}
+			o.(*widget.Label).SetText(i18n.T("label.backup_entry", b.Time.Format("2006-01-02 15:04:05"), kind, (b.Size+1023)/1024))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = int(id)
+		sum, serr := storage.ReadBackupSummary(backups[selected].Path)
+		if errors.Is(serr, storage.ErrBackupChainBroken) {
+			// Restoring still works: it falls back to the newest intact full backup before this one
+			preview.SetText(i18n.T("status.this_backup_only_stores_changes_and", serr.Error()))
+			restoreBtn.Enable()
+		} else if serr != nil {
+			preview.SetText(i18n.T("status.cannot_read_backup", serr.Error()))
+			restoreBtn.Disable()
+		} else {
+			name := sum.Name
+			if strings.TrimSpace(name) == "" {
+				name = "(unnamed)"
 			}
+			preview.SetText(i18n.T("status.project_issues_pages_panels_file", name, sum.Issues, sum.Pages, sum.Panels, filepath.Base(backups[selected].Path)))
+			restoreBtn.Enable()
 		}
-		telemetry.Event("project_open", map[string]any{"issues": issueCount, "pages": pageCount})
+		deleteBtn.Enable()
 	}
-	return nil
+
+	var d dialog.Dialog
+	restoreBtn = widget.NewButton(i18n.T("button.restore"), func() {
+		if selected < 0 || selected >= len(backups) {
+			return
+		}
+		b := backups[selected]
+		dialog.ShowConfirm(i18n.T("msg.restore_backup"), i18n.T("msg.replace_the_current_project_with_the", b.Time.Format("2006-01-02 15:04:05")), func(ok bool) {
+			if !ok {
+				return
+			}
+			gap, err := storage.RestoreBackup(ph, b.Path)
+			if err != nil {
+				l.Error("restore backup failed", slog.Any("err", err))
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			d.Hide()
+			status.SetText(i18n.T("status.restored_backup_from", b.Time.Format("2006-01-02 15:04:05")))
+			if gap != nil {
+				dialog.ShowInformation(i18n.T("msg.backup_partly_lost"), gap.String(), w)
+			}
+			if onRestored != nil {
+				onRestored()
+			}
+		}, w)
+	})
+	restoreBtn.Importance = widget.HighImportance
+	restoreBtn.Disable()
+	deleteBtn = widget.NewButton(i18n.T("button.delete"), func() {
+		if selected < 0 || selected >= len(backups) {
+			return
+		}
+		b := backups[selected]
+		dialog.ShowConfirm(i18n.T("msg.delete_backup"), i18n.T("msg.permanently_delete_the_backup_from", b.Time.Format("2006-01-02 15:04:05")), func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := storage.DeleteBackup(ph.Root, b.Path); err != nil {
+				l.Error("delete backup failed", slog.Any("err", err))
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			// The backup after it may have become a full copy, so list them again
+			if bs, lerr := storage.ListBackups(ph.Root); lerr == nil {
+				backups = bs
+			} else {
+				backups = append(backups[:selected], backups[selected+1:]...)
+			}
+			selected = -1
+			list.UnselectAll()
+			list.Refresh()
+			preview.SetText(i18n.T("status.backup_deleted"))
+			restoreBtn.Disable()
+			deleteBtn.Disable()
+		}, w)
+	})
+	deleteBtn.Disable()
+	closeBtn := widget.NewButton(i18n.T("msg.close"), func() { d.Hide() })
+	content := container.NewBorder(nil, container.NewVBox(widget.NewSeparator(), preview, container.NewHBox(deleteBtn, closeBtn, restoreBtn)), nil, nil, list)
+	d = dialog.NewCustomWithoutButtons(i18n.T("msg.backups_count", len(backups)), content, w)
+	d.Resize(fyne.NewSize(560, 420))
+	d.Show()
 }
 
-// showIssueSetupDialog opens a modal dialog to edit issue settings (trim, bleed, DPI, reading direction).
-// Sizes are input in millimeters, converted to points for storage.
+// showIssueSetupDialog opens a modal dialog to edit issue settings (trim, bleed, DPI, reading direction,
+// export title page). Sizes are input in the unit of the preferences, converted to points for storage;
+// the resulting page size in pixels is shown while typing, and sizes above the export pixel limit are
+// rejected.
 func showIssueSetupDialog(w fyne.Window, ph *storage.ProjectHandle, pc *PageCanvas, status *widget.Label, l *slog.Logger) {
 	var init domain.Issue
 	if len(ph.Project.Issues) > 0 {
@@ -3187,50 +6796,79 @@ func showIssueSetupDialog(w fyne.Window, ph *storage.ProjectHandle, pc *PageCanv
 			Pages:            []domain.Page{},
 		}
 	}
+	u := units()
 	wEntry := widget.NewEntry()
-	wEntry.SetText(fmt.Sprintf("%.2f", ptToMM(init.TrimWidth)))
+	wEntry.SetText(u.number(init.TrimWidth))
 	hEntry := widget.NewEntry()
-	hEntry.SetText(fmt.Sprintf("%.2f", ptToMM(init.TrimHeight)))
+	hEntry.SetText(u.number(init.TrimHeight))
 	bEntry := widget.NewEntry()
-	bEntry.SetText(fmt.Sprintf("%.2f", ptToMM(init.Bleed)))
+	bEntry.SetText(u.number(init.Bleed))
 	dpiEntry := widget.NewEntry()
 	dpiEntry.SetText(fmt.Sprintf("%d", init.DPI))
+	pixelLabel := widget.NewLabel("")
+	pixelLabel.Wrapping = fyne.TextWrapWord
+	updatePixels := func(string) {
+		pixelLabel.SetText(issuePixelPreview(u, wEntry.Text, hEntry.Text, bEntry.Text, dpiEntry.Text))
+	}
+	for _, e := range []*widget.Entry{wEntry, hEntry, bEntry, dpiEntry} {
+		e.OnChanged = updatePixels
+	}
+	updatePixels("")
 	rdir := init.ReadingDirection
 	if strings.TrimSpace(rdir) == "" {
 		rdir = "ltr"
 	}
 	rdSelect := widget.NewSelect([]string{"ltr", "rtl"}, nil)
 	rdSelect.SetSelected(rdir)
+	var fm domain.FrontMatter
+	if init.FrontMatter != nil {
+		fm = *init.FrontMatter
+	}
+	creditsEntry := widget.NewMultiLineEntry()
+	creditsEntry.SetPlaceHolder(i18n.T("placeholder.series_issuetitle_creators", export.DefaultCreditsTemplate))
+	creditsEntry.SetText(fm.Credits)
+	creditsEntry.SetMinRowsVisible(3)
+	titlePageCheck := widget.NewCheck(i18n.T("check.add_title_credits_page_before_page"), func(on bool) {
+		if on {
+			creditsEntry.Enable()
+		} else {
+			creditsEntry.Disable()
+		}
+	})
+	titlePageCheck.SetChecked(fm.TitlePage)
+	if !fm.TitlePage {
+		creditsEntry.Disable()
+	}
 
-	form := dialog.NewForm("Issue Setup", "Save", "Cancel", []*widget.FormItem{
-		widget.NewFormItem("Trim Width (mm)", wEntry),
-		widget.NewFormItem("Trim Height (mm)", hEntry),
-		widget.NewFormItem("Bleed (mm)", bEntry),
-		widget.NewFormItem("DPI", dpiEntry),
-		widget.NewFormItem("Reading Direction", rdSelect),
+	form := dialog.NewForm(i18n.T("msg.issue_setup"), i18n.T("msg.save"), i18n.T("msg.cancel"), []*widget.FormItem{
+		widget.NewFormItem(u.label("form.trim_width"), wEntry),
+		widget.NewFormItem(u.label("form.trim_height"), hEntry),
+		widget.NewFormItem(u.label("form.bleed"), bEntry),
+		widget.NewFormItem(i18n.T("form.dpi"), dpiEntry),
+		widget.NewFormItem(i18n.T("form.pixel_size"), pixelLabel),
+		widget.NewFormItem(i18n.T("form.reading_direction"), rdSelect),
+		widget.NewFormItem(i18n.T("form.front_matter"), titlePageCheck),
+		widget.NewFormItem(i18n.T("form.credits"), creditsEntry),
 	}, func(ok bool) {
 		if !ok {
 			return
 		}
-		wMM, errW := strconv.ParseFloat(strings.TrimSpace(wEntry.Text), 64)
-		hMM, errH := strconv.ParseFloat(strings.TrimSpace(hEntry.Text), 64)
-		bMM, errB := strconv.ParseFloat(strings.TrimSpace(bEntry.Text), 64)
-		dpi, errD := strconv.Atoi(strings.TrimSpace(dpiEntry.Text))
-		rdirSel := rdSelect.Selected
-		if errW != nil || errH != nil || errB != nil || errD != nil || wMM <= 0 || hMM <= 0 || dpi <= 0 {
-			dialog.ShowError(fmt.Errorf("Please enter valid positive numbers for width/height/bleed and DPI."), w)
+		newIssue, err := parseIssueSize(u, wEntry.Text, hEntry.Text, bEntry.Text, dpiEntry.Text)
+		if err == nil {
+			err = checkIssuePixels(newIssue)
+		}
+		if err != nil {
+			dialog.ShowError(err, w)
 			return
 		}
+		rdirSel := rdSelect.Selected
 		if rdirSel != "ltr" && rdirSel != "rtl" {
 			rdirSel = "ltr"
 		}
-		newIssue := domain.Issue{
-			TrimWidth:        mmToPT(wMM),
-			TrimHeight:       mmToPT(hMM),
-			Bleed:            mmToPT(bMM),
-			DPI:              dpi,
-			ReadingDirection: rdirSel,
-			Pages:            nil,
+		newIssue.ReadingDirection = rdirSel
+		// Keep the credits text around while the title page is switched off
+		if credits := strings.TrimSpace(creditsEntry.Text); titlePageCheck.Checked || credits != "" {
+			newIssue.FrontMatter = &domain.FrontMatter{TitlePage: titlePageCheck.Checked, Credits: credits}
 		}
 		if len(ph.Project.Issues) > 0 {
 			newIssue.Pages = ph.Project.Issues[0].Pages
@@ -3241,17 +6879,24 @@ func showIssueSetupDialog(w fyne.Window, ph *storage.ProjectHandle, pc *PageCanv
 		}
 		if err := storage.Save(ph); err != nil {
 			l.Error("save manifest after issue setup", slog.Any("err", err))
-			dialog.ShowError(err, w)
+			dialog.ShowError(FriendlyError(err), w)
 			return
 		}
 		pc.ApplyIssue(newIssue)
-		status.SetText("Issue settings saved.")
+		status.SetText(i18n.T("status.issue_settings_saved"))
 	}, w)
+	form.Resize(fyne.NewSize(520, 0))
 	form.Show()
 }
 
-func ptToMM(pt float64) float64 { return pt * 25.4 / 72.0 }
-func mmToPT(mm float64) float64 { return mm * 72.0 / 25.4 }
+// searchSyntaxMessage turns a storage query syntax error into a short hint for the status bar.
+func searchSyntaxMessage(err error) (string, bool) {
+	var qe *storage.QueryError
+	if !errors.As(err, &qe) {
+		return "", false
+	}
+	return i18n.T("status.search_syntax", qe.Msg, qe.Pos+1), true
+}
 
 // parseGridSpec parses simple grid templates like "3x3" or custom key-value strings like
 // "rows:3,cols:2,mx:12,my:12,gx:6,gy:6". Units default to points; suffix "mm" is supported.
@@ -3314,11 +6959,16 @@ func parseGridSpec(spec string) (rows int, cols int, mx, my, gx, gy float32, ok
 		if !ok {
 			return def
 		}
-		// support mm suffix
+		// support mm and in suffixes
 		if strings.HasSuffix(val, "mm") {
 			f, e := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(val, "mm")), 64)
 			if e == nil {
-				return float32(mmToPT(f))
+				return float32(domain.MMToPoints(f))
+			}
+		} else if strings.HasSuffix(val, "in") {
+			f, e := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(val, "in")), 64)
+			if e == nil {
+				return float32(domain.InchesToPoints(f))
 			}
 		} else {
 			f, e := strconv.ParseFloat(val, 64)
@@ -3404,25 +7054,71 @@ type PageCanvas struct {
 	trimMargin  float32
 	gutterSize  float32 // inner margin width
 	gutterLeft  bool    // if false, gutter is drawn on the right
+	spread      bool    // showing two facing pages side by side, each pageW wide
 
 	// Scene graph (demo) and selection
 	scene    []vector.Node
-	selected int // index into scene, -1 if none
+	selected int   // index into scene, -1 if none
+	marked   []int // scene indexes highlighted as a multi-selection (no transform handles)
 	// Interaction state for transforms
 	dragMode  dragMode
 	startPage vector.Pt
 	startXf   vector.Affine2D
 	// For scale/rotate operations
 	anchor vector.Pt
-
-	// Overlays
-	beatOverlay bool
+	// Last scene raster and when it was drawn; reused to throttle redraws while dragging
+	sceneImg   *image.RGBA
+	sceneDrawn time.Time
+
+	// Overlay colors the panels by beat coverage or art status (storage.OverlayBeats, OverlayArtStatus)
+	overlay string
+	// palette colors the surround, page edge and guides; see SetPalette
+	palette canvasPalette
+	// Reference images under the panels, one per displayed page ([1] is the right page of a spread),
+	// drawn while showRefs is set. AssetRoot returns the project root their asset paths are relative to.
+	refs      [2]pageReference
+	showRefs  bool
+	AssetRoot func() string
+	// PanelBorder returns the project's default panel border; panel outlines preview the resolved borders.
+	PanelBorder func() *domain.PanelBorder
 	// Mapping of scene nodes to panel IDs (parallel to scene)
 	panelIDs []string
+	// Locked panels can be selected but not moved, resized or rotated; OnLocked is told when a drag on
+	// one is refused.
+	lockedIDs map[string]bool
+	OnLocked  func(panelID string)
+	// Balloons drawn over the panels; double-clicking one calls OnEditBalloon with the page side it
+	// lies on (side 1 is the right page of a spread). Clicking one selects it with its panel for the
+	// balloon order commands; OnSelect is told what a click selected, balloonID "" for a panel and
+	// both "" for nothing. ShowBalloonOrder numbers the balloons in reading order.
+	balloons         []canvasBalloon
+	OnEditBalloon    func(side int, panelID, balloonID string)
+	OnSelect         func(side int, panelID, balloonID string)
+	taps             tapTracker
+	selBalloon       canvasBalloon // Side, PanelID and BalloonID of the selected balloon
+	ShowBalloonOrder bool
 
 	// Asset placement (minimal UX): when armed, next click on a panel will place the asset
 	armedAssetPath string
 	OnPlaceAsset   func(path string, panelID string)
+
+	// Panel draw tool: while DrawPanels is set, or Alt is held as the drag starts, dragging on empty page
+	// area draws a new panel snapped to the trim box and panel edges. OnDrawPanel receives its geometry
+	// and the page it lies on (side 1 is the right page of a spread); OnDrawStatus shows the live size.
+	DrawPanels   bool
+	OnDrawPanel  func(side int, geom domain.Rect)
+	OnDrawStatus func(text string)
+	drawSide     int
+	drawStart    vector.Pt
+	drawRect     vector.Rect // sheet coordinates
+
+	// Snap configures snapping of moved, resized and drawn panels; holding Alt while moving or resizing
+	// turns it off. guides are the snap lines shown during the drag.
+	Snap        storage.SnapSettings
+	snap        panelSnap
+	guides      []vector.GuideLine
+	startBounds vector.Rect // bounds of the dragged node when the drag started
+	startCorner vector.Pt   // dragged corner when a resize started
 }
 
 // dragMode represents current interaction kind
@@ -3439,6 +7135,9 @@ const (
 	dragScaleSW
 	dragScaleSE
 	dragRotate
+	dragDraw          // drawing a new panel
+	dragDrawCancelled // Escape ended the draw; the rest of the drag is ignored
+	dragLocked        // the selected panel is locked; the rest of the drag is ignored
 )
 
 func NewPageCanvas() *PageCanvas {
@@ -3451,6 +7150,8 @@ func NewPageCanvas() *PageCanvas {
 		gutterSize:  18,  // ~0.25in inner margin
 		gutterLeft:  true,
 		selected:    -1,
+		showRefs:    true,
+		palette:     canvasPaletteFor(config.CanvasConfig{}, true),
 	}
 	// Demo scene: two rectangles
 	r1 := vector.NewRect(vector.R(100, 100, 160, 120), vector.Fill{Enabled: true, Color: vector.Color{R: 220, G: 120, B: 120, A: 255}}, vector.Stroke{Enabled: true, Color: vector.Black, Width: 2})
@@ -3465,42 +7166,43 @@ func NewPageCanvas() *PageCanvas {
 
 // CreateRenderer builds the simple vector-like objects we position manually.
 func (p *PageCanvas) CreateRenderer() fyne.WidgetRenderer {
-	// Background
-	bg := canvas.NewRectangle(color.RGBA{R: 30, G: 30, B: 34, A: 255})
-
-	// Page base and guides
+	// Background, page base and guides; their colors come from the palette, see applyPalette
+	bg := canvas.NewRectangle(color.Transparent)
 	page := canvas.NewRectangle(color.White)
-	page.StrokeColor = color.RGBA{R: 20, G: 20, B: 20, A: 255}
 	page.StrokeWidth = 2
+	trim := canvas.NewRectangle(color.Transparent)
+	bleed := canvas.NewRectangle(color.Transparent)
+	gutter := canvas.NewRectangle(color.Transparent)
+	spine := canvas.NewLine(color.Transparent)
+	spine.Hide()
+
+	// Reference images lie on the page under guides and panels; a placeholder stands in for a file
+	// that is missing or unreadable
+	var refImg [2]*canvas.Image
+	var refPH [2]*canvas.Rectangle
+	var refMsg [2]*canvas.Text
+	for i := range refImg {
+		refImg[i] = &canvas.Image{ScaleMode: canvas.ImageScaleSmooth}
+		refImg[i].Hide()
+		refPH[i] = canvas.NewRectangle(color.RGBA{R: 200, G: 200, B: 200, A: 90})
+		refPH[i].StrokeColor = color.RGBA{R: 150, G: 150, B: 150, A: 200}
+		refPH[i].StrokeWidth = 1
+		refPH[i].Hide()
+		refMsg[i] = canvas.NewText("", color.RGBA{R: 90, G: 90, B: 90, A: 255})
+		refMsg[i].Hide()
+	}
 
-	trim := canvas.NewRectangle(color.RGBA{R: 0, G: 0, B: 0, A: 0})
-	trim.StrokeColor = color.RGBA{R: 200, G: 0, B: 0, A: 200}
-	trim.StrokeWidth = 1
-
-	bleed := canvas.NewRectangle(color.RGBA{R: 0, G: 0, B: 0, A: 0})
-	bleed.StrokeColor = color.RGBA{R: 0, G: 120, B: 255, A: 180}
-	bleed.StrokeWidth = 1
-
-	gutter := canvas.NewRectangle(color.RGBA{R: 0, G: 0, B: 0, A: 0})
-	gutter.StrokeColor = color.RGBA{R: 120, G: 200, B: 0, A: 200}
-	gutter.FillColor = color.RGBA{R: 120, G: 200, B: 0, A: 40}
-	gutter.StrokeWidth = 1
+	// Scene nodes are rasterized in software so transforms (rotation, shear) render exactly
+	nodes := canvas.NewRaster(p.renderScene)
 
-	// Node rectangles (use Rectangle instead of Polygon to match Fyne v2.6 API)
-	var rects []*canvas.Rectangle
-	for j := 0; j < len(p.scene); j++ {
-		r := canvas.NewRectangle(color.RGBA{R: 220, G: 220, B: 220, A: 255})
-		r.StrokeColor = color.RGBA{R: 30, G: 30, B: 30, A: 255}
-		r.StrokeWidth = 1
-		rects = append(rects, r)
+	// Selection overlay: outline of the transformed node, 4 corner handles + rotation handle
+	var outline [4]*canvas.Line
+	for i := range outline {
+		outline[i] = canvas.NewLine(color.RGBA{R: 0, G: 170, B: 255, A: 255})
+		outline[i].StrokeWidth = 1
+		outline[i].Hide()
 	}
 
-	// Selection overlay: bbox and 4 corner handles + rotation handle
-	bbox := canvas.NewRectangle(color.RGBA{0, 0, 0, 0})
-	bbox.StrokeColor = color.RGBA{R: 0, G: 170, B: 255, A: 255}
-	bbox.StrokeWidth = 1
-	bbox.Hide()
-
 	handles := []*canvas.Rectangle{
 		canvas.NewRectangle(color.RGBA{R: 0, G: 170, B: 255, A: 255}),
 		canvas.NewRectangle(color.RGBA{R: 0, G: 170, B: 255, A: 255}),
@@ -3512,19 +7214,44 @@ func (p *PageCanvas) CreateRenderer() fyne.WidgetRenderer {
 	}
 	rot := canvas.NewCircle(color.RGBA{R: 255, G: 170, B: 0, A: 255})
 	rot.Hide()
+	// Outline of a panel being drawn
+	drawn := canvas.NewRectangle(color.RGBA{R: 0, G: 170, B: 255, A: 40})
+	drawn.StrokeColor = color.RGBA{R: 0, G: 170, B: 255, A: 255}
+	drawn.StrokeWidth = 1
+	drawn.Hide()
+	// Snap guides of the current drag, at most one per axis
+	var guides [2]*canvas.Line
+	for i := range guides {
+		guides[i] = canvas.NewLine(color.Transparent)
+		guides[i].Hide()
+	}
 
-	// Draw order: background, bleed (outside), page base, then guides, then nodes and selection overlay on top
-	objs := []fyne.CanvasObject{bg, bleed, page, trim, gutter}
-	for _, r := range rects {
-		objs = append(objs, r)
+	// Draw order: background, bleed (outside), page base, reference images, then guides, then nodes and
+	// selection overlay on top
+	objs := []fyne.CanvasObject{bg, bleed, page}
+	for i := range refImg {
+		objs = append(objs, refImg[i], refPH[i], refMsg[i])
+	}
+	objs = append(objs, trim, gutter, spine, nodes)
+	for _, l := range outline {
+		objs = append(objs, l)
 	}
-	objs = append(objs, bbox)
 	for _, h := range handles {
 		objs = append(objs, h)
 	}
-	objs = append(objs, rot)
+	objs = append(objs, rot, drawn, guides[0], guides[1])
+
+	r := &pageCanvasRenderer{pc: p, objects: objs, bg: bg, page: page, refImg: refImg, refPH: refPH, refMsg: refMsg, trim: trim, bleed: bleed, gutter: gutter, spine: spine, nodes: nodes, outline: outline, handles: handles, rot: rot, drawn: drawn, guides: guides}
+	r.applyPalette()
+	return r
+}
 
-	return &pageCanvasRenderer{pc: p, objects: objs, bg: bg, page: page, trim: trim, bleed: bleed, gutter: gutter, rects: rects, bbox: bbox, handles: handles, rot: rot}
+// SetPalette changes the colors of the surround, page edge, guides and beat overlay, e.g. after the
+// theme or the canvas settings changed. Call ShowPage or ShowPanels afterwards to recolor the overlay.
+func (p *PageCanvas) SetPalette(pal canvasPalette) {
+	p.palette = pal
+	p.sceneImg = nil
+	p.Refresh()
 }
 
 // PreferredSize sets a decent default size for the widget.
@@ -3552,6 +7279,7 @@ func (p *PageCanvas) ApplyIssue(is domain.Issue) {
 	// Apply per-page grid to build panels for the first page (until page switching UI exists)
 	if len(is.Pages) > 0 {
 		pg := is.Pages[0]
+		p.refs = [2]pageReference{p.reference(pg)}
 		if len(pg.Panels) > 0 {
 			p.ShowPanels(pg)
 		} else if strings.TrimSpace(pg.Grid) != "" {
@@ -3567,42 +7295,126 @@ func (p *PageCanvas) ApplyIssue(is domain.Issue) {
 
 // ShowPanels renders the given page's panels using their geometry and zOrder.
 func (p *PageCanvas) ShowPanels(pg domain.Page) {
-	// build nodes in z-order ascending so later items draw on top
-	s := make([]vector.Node, 0, len(pg.Panels))
-	ids := make([]string, 0, len(pg.Panels))
+	p.spread = false
+	p.refs = [2]pageReference{p.reference(pg)}
+	p.scene, p.panelIDs = p.panelNodes(pg, 0, nil, nil)
+	p.lockedIDs = lockedPanelIDs(nil, pg)
+	p.balloons = canvasBalloons(pg, 0, 0, nil)
+	p.selected = -1
+	p.marked = nil
+	p.Refresh()
+}
+
+// ShowSpread renders two facing pages side by side; the right page's panels are shifted by one page width,
+// so a panel of the left page running past its trim edge continues onto the right page.
+func (p *PageCanvas) ShowSpread(left, right domain.Page) {
+	p.spread = true
+	p.refs = [2]pageReference{p.reference(left), p.reference(right)}
+	s, ids := p.panelNodes(left, 0, nil, nil)
+	p.scene, p.panelIDs = p.panelNodes(right, p.pageW, s, ids)
+	p.lockedIDs = lockedPanelIDs(lockedPanelIDs(nil, left), right)
+	p.balloons = canvasBalloons(right, p.pageW, 1, canvasBalloons(left, 0, 0, nil))
+	p.selected = -1
+	p.marked = nil
+	p.Refresh()
+}
+
+// ShowPage renders pg, or the whole spread side by side when pg is part of one.
+func (p *PageCanvas) ShowPage(iss domain.Issue, pg domain.Page) {
+	if l, r, ok := storage.SpreadSides(iss, pg.Number); ok {
+		var left, right domain.Page
+		for _, q := range iss.Pages {
+			switch q.Number {
+			case l:
+				left = q
+			case r:
+				right = q
+			}
+		}
+		p.ShowSpread(left, right)
+		return
+	}
+	p.ShowPanels(pg)
+}
+
+// lockedPanelIDs adds the IDs of pg's locked panels to m, creating it when needed.
+func lockedPanelIDs(m map[string]bool, pg domain.Page) map[string]bool {
+	for _, pn := range pg.Panels {
+		if pn.Locked {
+			if m == nil {
+				m = map[string]bool{}
+			}
+			m[pn.ID] = true
+		}
+	}
+	return m
+}
+
+// lockedAt returns the ID of the panel at scene index idx when it is locked, or "".
+func (p *PageCanvas) lockedAt(idx int) string {
+	if idx >= 0 && idx < len(p.panelIDs) && p.lockedIDs[p.panelIDs[idx]] {
+		return p.panelIDs[idx]
+	}
+	return ""
+}
+
+// reference resolves pg's reference image against the project root.
+func (p *PageCanvas) reference(pg domain.Page) pageReference {
+	root := ""
+	if p.AssetRoot != nil {
+		root = p.AssetRoot()
+	}
+	return resolveReference(root, pg.ReferenceImage)
+}
+
+// sheetW is the displayed width in points: one page, or two in spread mode.
+func (p *PageCanvas) sheetW() float32 {
+	if p.spread {
+		return 2 * p.pageW
+	}
+	return p.pageW
+}
+
+// panelNodes appends the page's panels, shifted right by dx points, to s and ids in z-order ascending
+// so later items draw on top.
+func (p *PageCanvas) panelNodes(pg domain.Page, dx float32, s []vector.Node, ids []string) ([]vector.Node, []string) {
 	// Sort copy by zOrder
 	tmp := append([]domain.Panel(nil), pg.Panels...)
 	sort.Slice(tmp, func(i, j int) bool { return tmp[i].ZOrder < tmp[j].ZOrder })
+	slot := 0
+	if dx > 0 {
+		slot = 1
+	}
+	// Panels over a visible reference image are see-through so it can be traced
+	traced := p.showRefs && p.refs[slot].path != ""
+	var border *domain.PanelBorder
+	if p.PanelBorder != nil {
+		border = p.PanelBorder()
+	}
 	for _, pn := range tmp {
-		rect := vector.R(float32(pn.Geometry.X), float32(pn.Geometry.Y), float32(pn.Geometry.Width), float32(pn.Geometry.Height))
-		// Color based on beat coverage overlay
+		rect := vector.R(float32(pn.Geometry.X)+dx, float32(pn.Geometry.Y), float32(pn.Geometry.Width), float32(pn.Geometry.Height))
+		// Color based on the active overlay
 		fill := vector.Color{R: 240, G: 240, B: 240, A: 255}
-		if p.beatOverlay {
-			beats := len(pn.BeatIDs)
-			if beats <= 0 {
-				fill = vector.Color{R: 240, G: 220, B: 220, A: 255} // light red hint for no beats
-			} else if beats == 1 {
-				fill = vector.Color{R: 210, G: 240, B: 210, A: 255}
-			} else if beats == 2 {
-				fill = vector.Color{R: 190, G: 235, B: 190, A: 255}
-			} else {
-				fill = vector.Color{R: 160, G: 230, B: 160, A: 255}
-			}
+		switch p.overlay {
+		case storage.OverlayArtStatus:
+			fill = artStatusFill(pn)
+		case storage.OverlayBeats:
+			fill = beatOverlayFill(len(pn.BeatIDs), p.palette.Dark)
 		}
-		n := vector.NewRect(rect, vector.Fill{Enabled: true, Color: fill}, vector.Stroke{Enabled: true, Color: vector.Color{R: 40, G: 40, B: 40, A: 255}, Width: 1})
+		if traced {
+			fill.A = 80
+		}
+		n := vector.NewRect(rect, vector.Fill{Enabled: true, Color: fill}, panelCanvasStroke(border, pn))
 		s = append(s, n)
 		ids = append(ids, pn.ID)
 	}
-	p.scene = s
-	p.panelIDs = ids
-	p.selected = -1
-	p.Refresh()
+	return s, ids
 }
 
 // Coordinate helpers: page <-> screen mapping
 func (p *PageCanvas) pageOriginAndScale() (cx, cy, scale float32) {
 	size := p.Size()
-	scaledW := p.pageW * p.zoom
+	scaledW := p.sheetW() * p.zoom
 	scaledH := p.pageH * p.zoom
 	cx = float32(size.Width)/2 - scaledW/2 + p.offsetX
 	cy = float32(size.Height)/2 - scaledH/2 + p.offsetY
@@ -3634,38 +7446,43 @@ type fRect struct{ X, Y, Width, Height float32 }
 
 func newFRect(x, y, w, h float32) fRect { return fRect{X: x, Y: y, Width: w, Height: h} }
 
-// Handle rectangles in screen coords around selection bbox
-func (p *PageCanvas) handleRects() (bbox fRect, corners [4]fRect, rot fRect, ok bool) {
+// Handle rectangles in screen coords on the corners of the selected node's transformed outline.
+// outline and corners are in NW, NE, SW, SE order of the untransformed node.
+func (p *PageCanvas) handleRects() (outline [4]fyne.Position, corners [4]fRect, rot fRect, ok bool) {
 	if p.selected < 0 || p.selected >= len(p.scene) {
-		return fRect{}, [4]fRect{}, fRect{}, false
-	}
-	b := p.scene[p.selected].Bounds() // page coords
-	p0 := p.toScreen(vector.Pt{X: b.X, Y: b.Y})
-	p1 := p.toScreen(vector.Pt{X: b.X + b.W, Y: b.Y + b.H})
-	bx := float32ToFixed(p0.X)
-	by := float32ToFixed(p0.Y)
-	bw := float32ToFixed(float32(p1.X - p0.X))
-	bh := float32ToFixed(float32(p1.Y - p0.Y))
-	bbox = newFRect(bx, by, bw, bh)
+		return [4]fyne.Position{}, [4]fRect{}, fRect{}, false
+	}
 	sz := float32(8)
-	hh := sz
-	hw := sz
-	corners = [4]fRect{
-		newFRect(bx-hw/2, by-hh/2, hw, hh),       // NW
-		newFRect(bx+bw-hw/2, by-hh/2, hw, hh),    // NE
-		newFRect(bx-hw/2, by+bh-hh/2, hw, hh),    // SW
-		newFRect(bx+bw-hw/2, by+bh-hh/2, hw, hh), // SE
-	}
-	// Rotation handle above top center
-	rcx := bx + bw/2
-	rcy := by - 24
+	for i, c := range vector.Corners(p.scene[p.selected]) { // page coords
+		outline[i] = p.toScreen(c)
+		corners[i] = newFRect(outline[i].X-sz/2, outline[i].Y-sz/2, sz, sz)
+	}
+	// Rotation handle beyond the middle of the top edge, pointing away from the centre
+	topX, topY := (outline[0].X+outline[1].X)/2, (outline[0].Y+outline[1].Y)/2
+	cX, cY := (outline[0].X+outline[3].X)/2, (outline[0].Y+outline[3].Y)/2
+	dx, dy := topX-cX, topY-cY
+	if l := float32(math.Hypot(float64(dx), float64(dy))); l > 0 {
+		dx, dy = dx/l, dy/l
+	} else {
+		dx, dy = 0, -1
+	}
+	rcx := topX + dx*24
+	rcy := topY + dy*24
 	rot = newFRect(rcx-6, rcy-6, 12, 12)
-	return bbox, corners, rot, true
+	return outline, corners, rot, true
 }
 
-// Tapped selects a node using hit testing, or places an armed asset into a panel
+// Tapped selects a node using hit testing, places an armed asset into a panel, or opens the balloon
+// under a double-click for editing
 func (p *PageCanvas) Tapped(e *fyne.PointEvent) {
 	pagePt := p.toPage(e.Position)
+	if p.taps.double(time.Now(), e.Position.X, e.Position.Y) && p.OnEditBalloon != nil {
+		if i := balloonAt(p.balloons, pagePt); i >= 0 {
+			b := p.balloons[i]
+			p.OnEditBalloon(b.Side, b.PanelID, b.BalloonID)
+			return
+		}
+	}
 	// If an asset is armed, try to place into the panel under cursor
 	if strings.TrimSpace(p.armedAssetPath) != "" && p.OnPlaceAsset != nil {
 		idx := p.hitTest(pagePt)
@@ -3677,10 +7494,29 @@ func (p *PageCanvas) Tapped(e *fyne.PointEvent) {
 			return
 		}
 	}
-	idx := p.hitTest(pagePt)
+	// Balloons lie on top of the panels, so they are hit first; the balloon's panel is selected with it
+	p.selBalloon = canvasBalloon{}
+	idx := -1
+	if i := balloonAt(p.balloons, pagePt); i >= 0 {
+		p.selBalloon = p.balloons[i]
+		idx = slices.Index(p.panelIDs, p.selBalloon.PanelID)
+	} else {
+		idx = p.hitTest(pagePt)
+	}
 	p.selected = idx
 	p.dragMode = dragNone
 	p.Refresh()
+	if p.OnSelect == nil {
+		return
+	}
+	side, panelID := p.selBalloon.Side, p.selBalloon.PanelID
+	if panelID == "" && idx >= 0 && idx < len(p.panelIDs) {
+		panelID = p.panelIDs[idx]
+		if b := p.scene[idx].Bounds(); p.spread && b.X+b.W/2 >= p.pageW {
+			side = 1
+		}
+	}
+	p.OnSelect(side, panelID, p.selBalloon.BalloonID)
 }
 
 // Dragging and scrolling support
@@ -3705,20 +7541,47 @@ func (p *PageCanvas) Dragged(e *fyne.DragEvent) {
 			}
 		}
 		if p.dragMode == dragNone {
-			// If hit on selection body -> move; else pan
+			// If hit on selection body -> move; on empty page area with the draw tool -> draw; else pan
 			pagePt := p.toPage(pos)
+			// pos is already past the drag threshold; the drawn panel starts where the drag began
+			start := p.toPage(fyne.NewPos(pos.X-e.Dragged.DX, pos.Y-e.Dragged.DY))
 			if p.selected >= 0 && p.scene[p.selected].Hit(pagePt) {
 				p.dragMode = dragMove
+			} else if p.drawToolActive() && p.hitTest(start) < 0 && p.onSheet(start) {
+				p.beginDraw(start)
 			} else {
 				p.dragMode = dragPan
 			}
 		}
+		switch p.dragMode {
+		case dragMove, dragScaleNW, dragScaleNE, dragScaleSW, dragScaleSE, dragRotate:
+			if id := p.lockedAt(p.selected); id != "" {
+				p.dragMode = dragLocked
+				if p.OnLocked != nil {
+					p.OnLocked(id)
+				}
+				return
+			}
+		}
 		p.startPage = p.toPage(pos)
 		if p.selected >= 0 {
 			p.startXf = p.scene[p.selected].Transform()
 			b := p.scene[p.selected].Bounds()
-			// default anchor: center; for scale set based on handle later
+			// default anchor: center; scaling pins the corner opposite the dragged handle
 			p.anchor = vector.Pt{X: b.X + b.W/2, Y: b.Y + b.H/2}
+			c := vector.Corners(p.scene[p.selected])
+			switch p.dragMode {
+			case dragScaleNW:
+				p.anchor, p.startCorner = c[3], c[0]
+			case dragScaleNE:
+				p.anchor, p.startCorner = c[2], c[1]
+			case dragScaleSW:
+				p.anchor, p.startCorner = c[1], c[2]
+			case dragScaleSE:
+				p.anchor, p.startCorner = c[0], c[3]
+			}
+			p.startBounds = b
+			p.snap = p.panelSnapFor(p.selected)
 		}
 	}
 
@@ -3726,47 +7589,54 @@ func (p *PageCanvas) Dragged(e *fyne.DragEvent) {
 	case dragPan:
 		p.offsetX += float32(e.Dragged.DX)
 		p.offsetY += float32(e.Dragged.DY)
+	case dragDraw:
+		p.updateDraw(p.toPage(pos))
 	case dragMove:
 		cur := p.toPage(pos)
 		dx := cur.X - p.startPage.X
 		dy := cur.Y - p.startPage.Y
+		p.guides = nil
+		if !altHeld() {
+			moved := vector.R(p.startBounds.X+dx, p.startBounds.Y+dy, p.startBounds.W, p.startBounds.H)
+			var snapped vector.Rect
+			snapped, p.guides = p.snap.rect(moved)
+			dx += snapped.X - moved.X
+			dy += snapped.Y - moved.Y
+		}
 		if p.selected >= 0 {
 			newXf := vector.Translate(dx, dy).Mul(p.startXf)
 			p.scene[p.selected].SetTransform(newXf)
 		}
 	case dragScaleNW, dragScaleNE, dragScaleSW, dragScaleSE:
 		if p.selected >= 0 {
-			b := p.scene[p.selected].Bounds()
-			// Set anchor to opposite corner
-			var ax, ay float32
-			switch p.dragMode {
-			case dragScaleNW:
-				ax, ay = b.X+b.W, b.Y+b.H
-			case dragScaleNE:
-				ax, ay = b.X, b.Y+b.H
-			case dragScaleSW:
-				ax, ay = b.X+b.W, b.Y
-			case dragScaleSE:
-				ax, ay = b.X, b.Y
+			// Scale along the node's own axes so rotated nodes are resized rather than sheared
+			inv := p.startXf.Inverse()
+			a := inv.Apply(p.anchor)
+			s0 := inv.Apply(p.startPage)
+			cur := inv.Apply(p.toPage(pos))
+			p.guides = nil
+			if !altHeld() && axisAligned(p.startXf) {
+				// Snap the dragged corner rather than the pointer, which grabbed the handle off-center
+				pt := p.toPage(pos)
+				corner := vector.Pt{X: p.startCorner.X + pt.X - p.startPage.X, Y: p.startCorner.Y + pt.Y - p.startPage.Y}
+				corner, p.guides = p.snap.point(corner)
+				s0, cur = inv.Apply(p.startCorner), inv.Apply(corner)
 			}
-			p.anchor = vector.Pt{X: ax, Y: ay}
-			cur := p.toPage(pos)
-			// Compute scale factors relative to bbox
 			var sx, sy float32 = 1, 1
-			if b.W != 0 {
-				sx = (cur.X - p.anchor.X) / (p.startPage.X - p.anchor.X)
+			if s0.X != a.X {
+				sx = (cur.X - a.X) / (s0.X - a.X)
 			}
-			if b.H != 0 {
-				sy = (cur.Y - p.anchor.Y) / (p.startPage.Y - p.anchor.Y)
+			if s0.Y != a.Y {
+				sy = (cur.Y - a.Y) / (s0.Y - a.Y)
 			}
-			// Guard against NaN or inf
+			// Guard against a singular transform
 			if sx == 0 {
 				sx = 0.001
 			}
 			if sy == 0 {
 				sy = 0.001
 			}
-			xf := vector.Translate(p.anchor.X, p.anchor.Y).Mul(vector.Scale(sx, sy)).Mul(vector.Translate(-p.anchor.X, -p.anchor.Y)).Mul(p.startXf)
+			xf := p.startXf.Mul(vector.Translate(a.X, a.Y)).Mul(vector.Scale(sx, sy)).Mul(vector.Translate(-a.X, -a.Y))
 			p.scene[p.selected].SetTransform(xf)
 		}
 	case dragRotate:
@@ -3788,10 +7658,128 @@ func (p *PageCanvas) Dragged(e *fyne.DragEvent) {
 	}
 	p.Refresh()
 }
-func (p *PageCanvas) DragEnd() { p.dragMode = dragNone }
+func (p *PageCanvas) DragEnd() {
+	if p.dragMode == dragDraw {
+		p.finishDraw()
+	}
+	p.dragMode = dragNone
+	p.guides = nil
+	// redraw at full resolution
+	p.Refresh()
+}
+
+// drawToolActive reports whether a drag on empty page area draws a panel.
+func (p *PageCanvas) drawToolActive() bool {
+	return p.DrawPanels || altHeld()
+}
+
+// altHeld reports whether the Alt key is down.
+func altHeld() bool {
+	if drv, ok := fyne.CurrentApp().Driver().(desktop.Driver); ok {
+		return drv.CurrentKeyModifiers()&fyne.KeyModifierAlt != 0
+	}
+	return false
+}
+
+// panelSnapFor returns the snapping of scene node idx: to the trim box of the page it lies on, the
+// other nodes and the grid, as configured in Snap.
+func (p *PageCanvas) panelSnapFor(idx int) panelSnap {
+	b := p.scene[idx].Bounds()
+	var dx float32
+	if p.spread && b.X+b.W/2 >= p.pageW {
+		dx = p.pageW
+	}
+	trim := vector.R(dx+p.trimMargin, p.trimMargin, p.pageW-2*p.trimMargin, p.pageH-2*p.trimMargin)
+	others := make([]vector.Rect, 0, len(p.scene))
+	for i, n := range p.scene {
+		if i != idx {
+			others = append(others, n.Bounds())
+		}
+	}
+	return newPanelSnap(p.Snap, p.zoom, trim, others)
+}
+
+// onSheet reports whether a point in sheet coordinates lies on the displayed page(s) or their bleed.
+func (p *PageCanvas) onSheet(pt vector.Pt) bool {
+	b := p.bleedMargin
+	return pt.X >= -b && pt.X <= p.sheetW()+b && pt.Y >= -b && pt.Y <= p.pageH+b
+}
+
+// beginDraw starts drawing a panel at start and takes the keyboard focus so Escape can cancel it.
+func (p *PageCanvas) beginDraw(start vector.Pt) {
+	p.dragMode = dragDraw
+	p.drawSide = 0
+	if p.spread && start.X >= p.pageW {
+		p.drawSide = 1
+	}
+	p.drawStart = start
+	p.selected = -1
+	p.marked = nil
+	if c := fyne.CurrentApp().Driver().CanvasForObject(p); c != nil {
+		c.Focus(p)
+	}
+	p.updateDraw(start)
+}
+
+// updateDraw spans the drawn panel from the drag start to cur, snapped and kept on the sheet.
+func (p *PageCanvas) updateDraw(cur vector.Pt) {
+	b := p.bleedMargin
+	cur.X = max(-b, min(cur.X, p.sheetW()+b))
+	cur.Y = max(-b, min(cur.Y, p.pageH+b))
+	dx := float32(p.drawSide) * p.pageW
+	trim := vector.R(dx+p.trimMargin, p.trimMargin, p.pageW-2*p.trimMargin, p.pageH-2*p.trimMargin)
+	panels := make([]vector.Rect, 0, len(p.scene))
+	for _, n := range p.scene {
+		panels = append(panels, n.Bounds())
+	}
+	var anchors []vector.Anchor
+	if !p.Snap.Off {
+		anchors = panelDrawAnchors(trim, panels)
+	}
+	p.drawRect = drawnPanelRect(p.drawStart, cur, anchors, float32(p.Snap.ThresholdPx())/p.zoom)
+	if p.OnDrawStatus != nil {
+		p.OnDrawStatus(panelDrawStatus(p.drawRect))
+	}
+}
+
+// finishDraw hands a drawn panel of sufficient size to OnDrawPanel, in the coordinates of its page.
+func (p *PageCanvas) finishDraw() {
+	r := p.drawRect
+	p.drawRect = vector.Rect{}
+	if !panelDrawBigEnough(r) {
+		if p.OnDrawStatus != nil {
+			p.OnDrawStatus(i18n.T("status.panel_too_small", panelDrawMinSize, panelDrawMinSize))
+		}
+		return
+	}
+	r.X -= float32(p.drawSide) * p.pageW
+	if p.OnDrawPanel != nil {
+		p.OnDrawPanel(p.drawSide, panelDrawGeometry(r))
+	}
+}
+
+// FocusGained, FocusLost and TypedRune complete fyne.Focusable; the canvas takes the focus while a
+// panel is drawn.
+func (p *PageCanvas) FocusGained()   {}
+func (p *PageCanvas) FocusLost()     {}
+func (p *PageCanvas) TypedRune(rune) {}
+
+// TypedKey cancels drawing a panel on Escape.
+func (p *PageCanvas) TypedKey(e *fyne.KeyEvent) {
+	if e.Name != fyne.KeyEscape || p.dragMode != dragDraw {
+		return
+	}
+	p.dragMode = dragDrawCancelled
+	p.drawRect = vector.Rect{}
+	if p.OnDrawStatus != nil {
+		p.OnDrawStatus(i18n.T("status.panel_drawing_cancelled"))
+	}
+	p.Refresh()
+}
 
 // HighlightPanelID selects the panel with the given ID (if present) and refreshes the canvas.
 func (p *PageCanvas) HighlightPanelID(panelID string) {
+	p.marked = nil
 	if strings.TrimSpace(panelID) == "" {
 		p.selected = -1
 		p.Refresh()
@@ -3808,6 +7796,27 @@ func (p *PageCanvas) HighlightPanelID(panelID string) {
 	p.Refresh()
 }
 
+// HighlightPanelIDs highlights all panels with the given IDs. A single panel is selected with its transform
+// handles like HighlightPanelID; several panels are only tinted, as the handles act on one node.
+func (p *PageCanvas) HighlightPanelIDs(panelIDs []string) {
+	if len(panelIDs) <= 1 {
+		id := ""
+		if len(panelIDs) == 1 {
+			id = panelIDs[0]
+		}
+		p.HighlightPanelID(id)
+		return
+	}
+	p.selected = -1
+	p.marked = p.marked[:0]
+	for i, id := range p.panelIDs {
+		if slices.Contains(panelIDs, id) {
+			p.marked = append(p.marked, i)
+		}
+	}
+	p.Refresh()
+}
+
 // Scroll changes zoom when Ctrl pressed, else pans vertically.
 func (p *PageCanvas) Scrolled(e *fyne.ScrollEvent) {
 	// Fyne v2.6 does not expose modifier keys on ScrollEvent; keep it simple and
@@ -3830,26 +7839,68 @@ type pageCanvasRenderer struct {
 	bg, page    *canvas.Rectangle
 	trim, bleed *canvas.Rectangle
 	gutter      *canvas.Rectangle
+	spine       *canvas.Line // centre line between the pages in spread mode
+	// reference images per displayed page, their missing-file placeholders, and what refImg shows
+	refImg   [2]*canvas.Image
+	refPH    [2]*canvas.Rectangle
+	refMsg   [2]*canvas.Text
+	refShown [2]pageReference
 	// scene visuals
-	rects []*canvas.Rectangle
+	nodes *canvas.Raster
 	// selection visuals
-	bbox    *canvas.Rectangle
+	outline [4]*canvas.Line
 	handles []*canvas.Rectangle
 	rot     *canvas.Circle
+	drawn   *canvas.Rectangle // panel being drawn
+	guides  [2]*canvas.Line   // snap guides
+	shown   canvasPalette     // palette the objects are colored with
 }
 
 func (r *pageCanvasRenderer) Destroy()                     {}
 func (r *pageCanvasRenderer) Objects() []fyne.CanvasObject { return r.objects }
 func (r *pageCanvasRenderer) MinSize() fyne.Size           { return r.pc.PreferredSize() }
-func (r *pageCanvasRenderer) Refresh()                     { r.Layout(r.pc.Size()); canvas.Refresh(r.pc) }
+
+func (r *pageCanvasRenderer) Refresh() {
+	r.applyPalette()
+	r.Layout(r.pc.Size())
+	canvas.Refresh(r.pc)
+}
+
+// applyPalette colors the surround, page edge and guides from the canvas palette when it changed.
+func (r *pageCanvasRenderer) applyPalette() {
+	pal := r.pc.palette
+	if pal == r.shown {
+		return
+	}
+	r.shown = pal
+	r.bg.FillColor = pal.Surround
+	r.page.StrokeColor = pal.PageStroke
+	for _, g := range []struct {
+		rect *canvas.Rectangle
+		c    color.NRGBA
+	}{{r.trim, pal.Trim}, {r.bleed, pal.Bleed}, {r.gutter, pal.Gutter}} {
+		g.rect.StrokeColor = g.c
+		g.rect.StrokeWidth = pal.GuideWidth
+		g.rect.Refresh()
+	}
+	r.gutter.FillColor = pal.GutterFill
+	r.spine.StrokeColor = pal.Spine
+	r.spine.StrokeWidth = pal.GuideWidth
+	for _, l := range r.guides {
+		l.StrokeColor = pal.Guide
+		l.StrokeWidth = pal.GuideWidth
+	}
+	r.bg.Refresh()
+	r.page.Refresh()
+}
 
 func (r *pageCanvasRenderer) Layout(size fyne.Size) {
 	// Fill background
 	r.bg.Resize(size)
 	r.bg.Move(fyne.NewPos(0, 0))
 
-	// Define logical page and margins from widget configuration.
-	logicalW := r.pc.pageW
+	// Define logical page (or spread) and margins from widget configuration.
+	logicalW := r.pc.sheetW()
 	logicalH := r.pc.pageH
 	bleedMargin := r.pc.bleedMargin
 	trimMargin := r.pc.trimMargin
@@ -3866,6 +7917,7 @@ func (r *pageCanvasRenderer) Layout(size fyne.Size) {
 	// Page rectangle
 	r.page.Resize(fyne.NewSize(float32ToFixed(scaledW), float32ToFixed(scaledH)))
 	r.page.Move(fyne.NewPos(float32ToFixed(cx), float32ToFixed(cy)))
+	r.layoutReferences(cx, cy)
 
 	// Trim and bleed boxes
 	trimW := (logicalW - 2*trimMargin) * r.pc.zoom
@@ -3884,91 +7936,47 @@ func (r *pageCanvasRenderer) Layout(size fyne.Size) {
 	r.bleed.Resize(fyne.NewSize(float32ToFixed(bleedW), float32ToFixed(bleedH)))
 	r.bleed.Move(fyne.NewPos(float32ToFixed(bleedX), float32ToFixed(bleedY)))
 
-	// Gutter guide: inner margin strip on left or right inside the page
+	// Gutter guide: inner margin strip on left or right inside the page; on a spread it straddles the spine
 	gW := gutterSize * r.pc.zoom
 	gH := scaledH
 	var gX float32
-	if r.pc.gutterLeft {
+	switch {
+	case r.pc.spread:
+		spineX := cx + r.pc.pageW*r.pc.zoom
+		gX = spineX - gW
+		gW *= 2
+		r.spine.Position1 = fyne.NewPos(float32ToFixed(spineX), float32ToFixed(cy))
+		r.spine.Position2 = fyne.NewPos(float32ToFixed(spineX), float32ToFixed(cy+scaledH))
+		r.spine.Show()
+		r.spine.Refresh()
+	case r.pc.gutterLeft:
 		gX = cx
-	} else {
+	default:
 		gX = cx + scaledW - gW
 	}
+	if !r.pc.spread {
+		r.spine.Hide()
+	}
 	gY := cy
 	r.gutter.Resize(fyne.NewSize(float32ToFixed(gW), float32ToFixed(gH)))
 	r.gutter.Move(fyne.NewPos(float32ToFixed(gX), float32ToFixed(gY)))
 
-	// Ensure we have enough rectangle visuals for the current scene
-	need := len(r.pc.scene)
-	if need > len(r.rects) {
-		// Find insertion point before bbox in draw order
-		ins := -1
-		for i, obj := range r.objects {
-			if obj == r.bbox {
-				ins = i
-				break
-			}
-		}
-		if ins < 0 {
-			ins = len(r.objects)
-		}
-		add := need - len(r.rects)
-		newRects := make([]*canvas.Rectangle, 0, add)
-		for j := 0; j < add; j++ {
-			rr := canvas.NewRectangle(color.RGBA{R: 220, G: 220, B: 220, A: 255})
-			rr.StrokeColor = color.RGBA{R: 30, G: 30, B: 30, A: 255}
-			rr.StrokeWidth = 1
-			newRects = append(newRects, rr)
-		}
-		// Insert new rects into objects before bbox
-		objs := make([]fyne.CanvasObject, 0, len(r.objects)+len(newRects))
-		objs = append(objs, r.objects[:ins]...)
-		for _, rr := range newRects {
-			objs = append(objs, rr)
-		}
-		objs = append(objs, r.objects[ins:]...)
-		r.objects = objs
-		r.rects = append(r.rects, newRects...)
-	}
-	// Scene nodes as axis-aligned rectangles using their Bounds()
-	for i, n := range r.pc.scene {
-		if i >= len(r.rects) {
-			break
-		}
-		b := n.Bounds()
-		p0 := r.pc.toScreen(vector.Pt{X: b.X, Y: b.Y})
-		p1 := r.pc.toScreen(vector.Pt{X: b.X + b.W, Y: b.Y + b.H})
-		rc := r.rects[i]
-		rc.Show()
-		rc.Resize(fyne.NewSize(float32ToFixed(float32(p1.X-p0.X)), float32ToFixed(float32(p1.Y-p0.Y))))
-		rc.Move(fyne.NewPos(float32ToFixed(p0.X), float32ToFixed(p0.Y)))
-		// Apply node style
-		f := n.Fill()
-		if f.Enabled {
-			rc.FillColor = color.RGBA{R: f.Color.R, G: f.Color.G, B: f.Color.B, A: f.Color.A}
-		} else {
-			rc.FillColor = color.RGBA{R: 0, G: 0, B: 0, A: 0}
-		}
-		s := n.Stroke()
-		if s.Enabled {
-			rc.StrokeColor = color.RGBA{R: s.Color.R, G: s.Color.G, B: s.Color.B, A: s.Color.A}
-			rc.StrokeWidth = float32ToFixed(s.Width)
-		} else {
-			rc.StrokeWidth = 0
-		}
-		rc.Refresh()
-	}
-	// Hide any surplus rectangles
-	for j := need; j < len(r.rects); j++ {
-		r.rects[j].Hide()
-	}
+	// Scene nodes: one raster over the whole widget, regenerated by renderScene
+	r.nodes.Resize(size)
+	r.nodes.Move(fyne.NewPos(0, 0))
+	r.nodes.Refresh()
 
 	// Selection overlay
 	if r.pc.selected >= 0 {
-		bbox, corners, rot, ok := r.pc.handleRects()
+		outline, corners, rot, ok := r.pc.handleRects()
 		if ok {
-			r.bbox.Show()
-			r.bbox.Resize(fyne.NewSize(bbox.Width, bbox.Height))
-			r.bbox.Move(fyne.NewPos(bbox.X, bbox.Y))
+			// NW-NE, NE-SE, SE-SW, SW-NW
+			for i, e := range [4][2]int{{0, 1}, {1, 3}, {3, 2}, {2, 0}} {
+				r.outline[i].Position1 = outline[e[0]]
+				r.outline[i].Position2 = outline[e[1]]
+				r.outline[i].Show()
+				r.outline[i].Refresh()
+			}
 			for i := 0; i < len(r.handles); i++ {
 				r.handles[i].Show()
 				r.handles[i].Resize(fyne.NewSize(corners[i].Width, corners[i].Height))
@@ -3979,68 +7987,133 @@ func (r *pageCanvasRenderer) Layout(size fyne.Size) {
 			r.rot.Move(fyne.NewPos(rot.X, rot.Y))
 		}
 	} else {
-		r.bbox.Hide()
+		for _, l := range r.outline {
+			l.Hide()
+		}
 		for _, h := range r.handles {
 			h.Hide()
 		}
 		r.rot.Hide()
 	}
-}
-
-func float32ToFixed(v float32) float32 { return fyne.NewSize(v, 0).Width }
 
-// Recent project persistence helpers for dashboard
-const recentPrefsKey = "recent.projects"
-const recentMax = 10
+	// Panel being drawn
+	if r.pc.dragMode == dragDraw {
+		d := r.pc.drawRect
+		tl := r.pc.toScreen(vector.Pt{X: d.X, Y: d.Y})
+		r.drawn.Move(tl)
+		r.drawn.Resize(fyne.NewSize(float32ToFixed(d.W*r.pc.zoom), float32ToFixed(d.H*r.pc.zoom)))
+		r.drawn.Show()
+	} else {
+		r.drawn.Hide()
+	}
 
-func loadRecentProjects(p fyne.Preferences) []string {
-	raw := p.StringWithFallback(recentPrefsKey, "")
-	var items []string
-	if strings.TrimSpace(raw) != "" {
-		var tmp []string
-		if err := json.Unmarshal([]byte(raw), &tmp); err == nil {
-			items = tmp
+	// Snap guides
+	for i, line := range r.guides {
+		if i >= len(r.pc.guides) {
+			line.Hide()
+			continue
 		}
+		line.Position1 = r.pc.toScreen(r.pc.guides[i].From)
+		line.Position2 = r.pc.toScreen(r.pc.guides[i].To)
+		line.Show()
+		line.Refresh()
 	}
-	if items == nil {
-		items = []string{}
-	}
-	// Filter out non-existing paths
-	out := make([]string, 0, len(items))
-	for _, s := range items {
-		s = strings.TrimSpace(s)
-		if s == "" {
+}
+
+// layoutReferences places each displayed page's reference image, or its placeholder, on that page
+// (cx, cy is the top-left of the sheet). The image file is only reloaded when the reference changes.
+func (r *pageCanvasRenderer) layoutReferences(cx, cy float32) {
+	z := r.pc.zoom
+	size := fyne.NewSize(float32ToFixed(r.pc.pageW*z), float32ToFixed(r.pc.pageH*z))
+	for i, ref := range r.pc.refs {
+		img, ph, msg := r.refImg[i], r.refPH[i], r.refMsg[i]
+		if !r.pc.showRefs || ref.path == "" {
+			img.Hide()
+			ph.Hide()
+			msg.Hide()
+			continue
+		}
+		pos := fyne.NewPos(float32ToFixed(cx+float32(i)*r.pc.pageW*z), float32ToFixed(cy))
+		if ref.missing {
+			img.Hide()
+			ph.Resize(size)
+			ph.Move(pos)
+			ph.Show()
+			msg.Text = i18n.T("label.reference_image_missing", filepath.Base(ref.path))
+			ms := msg.MinSize()
+			msg.Resize(ms)
+			msg.Move(fyne.NewPos(pos.X+(size.Width-ms.Width)/2, pos.Y+(size.Height-ms.Height)/2))
+			msg.Show()
+			msg.Refresh()
 			continue
 		}
-		if _, err := os.Stat(s); err == nil {
-			out = append(out, s)
+		ph.Hide()
+		msg.Hide()
+		if ref.bleed {
+			// Page art covers the bleed too; on a spread it overlaps the spine by the bleed width
+			b := r.pc.bleedMargin * z
+			img.Resize(fyne.NewSize(float32ToFixed(size.Width+2*b), float32ToFixed(size.Height+2*b)))
+			img.Move(fyne.NewPos(float32ToFixed(pos.X-b), float32ToFixed(pos.Y-b)))
+		} else {
+			img.Resize(size)
+			img.Move(pos)
+		}
+		if ref != r.refShown[i] {
+			img.File = ref.path
+			img.FillMode = canvas.ImageFillContain
+			if ref.stretch {
+				img.FillMode = canvas.ImageFillStretch
+			}
+			img.Translucency = 1 - ref.opacity
+			r.refShown[i] = ref
+			img.Refresh()
 		}
+		img.Show()
 	}
-	return out
 }
 
-func saveRecentProjects(p fyne.Preferences, items []string) {
-	if len(items) > recentMax {
-		items = items[:recentMax]
-	}
-	b, _ := json.Marshal(items)
-	p.SetString(recentPrefsKey, string(b))
-}
+// sceneFrameInterval throttles scene rasterization while dragging (~30 fps).
+const sceneFrameInterval = 33 * time.Millisecond
 
-func addRecentProject(p fyne.Preferences, path string) {
-	if strings.TrimSpace(path) == "" {
-		return
+// renderScene rasterizes the scene nodes for a w x h pixel raster covering the widget, honouring
+// each node's full transform. While a drag is in progress frames are drawn at half resolution and
+// at most every sceneFrameInterval; DragEnd triggers a full-resolution redraw.
+func (p *PageCanvas) renderScene(w, h int) image.Image {
+	size := p.Size()
+	if w <= 0 || h <= 0 || size.Width <= 0 || size.Height <= 0 {
+		return image.NewRGBA(image.Rect(0, 0, 1, 1))
+	}
+	interacting := p.dragMode != dragNone
+	if interacting && p.sceneImg != nil && time.Since(p.sceneDrawn) < sceneFrameInterval {
+		return p.sceneImg
 	}
-	abs, _ := filepath.Abs(path)
-	rec := loadRecentProjects(p)
-	out := make([]string, 0, 1+len(rec))
-	out = append(out, abs)
-	for _, s := range rec {
-		// de-dup (case-insensitive on Windows)
-		if strings.EqualFold(s, abs) {
+	if interacting {
+		w, h = max(1, w/2), max(1, h/2)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	cx, cy, zoom := p.pageOriginAndScale()
+	// widget units -> raster pixels, then page -> widget
+	m := vector.Scale(float32(w)/size.Width, float32(h)/size.Height).
+		Mul(vector.Translate(cx, cy)).
+		Mul(vector.Scale(zoom, zoom))
+	for _, n := range p.scene {
+		vector.RasterizeNode(img, n, m)
+	}
+	p.drawBalloons(img, m)
+	// Multi-selection tint: a unit square mapped onto each marked node's (possibly rotated) box
+	tint := vector.Fill{Enabled: true, Color: vector.Color{R: 0, G: 170, B: 255, A: 70}}
+	for _, i := range p.marked {
+		if i < 0 || i >= len(p.scene) {
 			continue
 		}
-		out = append(out, s)
+		c := vector.Corners(p.scene[i])
+		hl := vector.NewRect(vector.R(0, 0, 1, 1), tint, vector.Stroke{})
+		hl.SetTransform(vector.Affine2D{A: c[1].X - c[0].X, B: c[1].Y - c[0].Y, C: c[2].X - c[0].X, D: c[2].Y - c[0].Y, E: c[0].X, F: c[0].Y})
+		vector.RasterizeNode(img, hl, m)
 	}
-	saveRecentProjects(p, out)
+	p.sceneImg = img
+	p.sceneDrawn = time.Now()
+	return img
 }
+
+func float32ToFixed(v float32) float32 { return fyne.NewSize(v, 0).Width }
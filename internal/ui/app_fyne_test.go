@@ -23,6 +23,9 @@ import (
 	"testing"
 
 	"fyne.io/fyne/v2"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/vector"
 )
 
 func almostEqual(a, b, eps float32) bool {
@@ -100,3 +103,55 @@ func TestPageCanvas_LayoutGeometry(t *testing.T) {
 		t.Fatalf("expected page to move with offsets; before (%v,%v), after (%v,%v)", oldX, oldY, newX, newY)
 	}
 }
+
+func TestPageCanvas_HandlesFollowRotation(t *testing.T) {
+	pc := NewPageCanvas()
+	pc.Resize(fyne.NewSize(1000, 800))
+	pc.selected = 1 // demo rect rotated by 0.2 rad
+	outline, corners, _, ok := pc.handleRects()
+	if !ok {
+		t.Fatalf("expected handles for selection")
+	}
+	if almostEqual(outline[0].Y, outline[1].Y, 0.5) {
+		t.Fatalf("top edge should be tilted for a rotated node: %v %v", outline[0], outline[1])
+	}
+	for i, c := range corners {
+		if !almostEqual(c.X+c.Width/2, outline[i].X, 0.01) || !almostEqual(c.Y+c.Height/2, outline[i].Y, 0.01) {
+			t.Fatalf("handle %d not centred on outline corner: %+v vs %v", i, c, outline[i])
+		}
+	}
+	img := pc.renderScene(1000, 800)
+	if img.Bounds().Dx() != 1000 {
+		t.Fatalf("unexpected raster size %v", img.Bounds())
+	}
+}
+
+func TestPageCanvas_LockedPanelRefusesDrag(t *testing.T) {
+	pc := NewPageCanvas()
+	pc.Resize(fyne.NewSize(1000, 800))
+	pc.ShowPanels(domain.Page{Number: 1, Panels: []domain.Panel{
+		{ID: "p1", Geometry: domain.Rect{X: 50, Y: 50, Width: 200, Height: 200}, Locked: true},
+		{ID: "p2", Geometry: domain.Rect{X: 300, Y: 50, Width: 200, Height: 200}, ZOrder: 1},
+	}})
+	var refused []string
+	pc.OnLocked = func(id string) { refused = append(refused, id) }
+	drag := func(idx int) {
+		pc.selected = idx
+		b := pc.scene[idx].Bounds()
+		at := pc.toScreen(vector.Pt{X: b.X + b.W/2, Y: b.Y + b.H/2})
+		pc.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: fyne.NewPos(at.X+20, at.Y)}, Dragged: fyne.Delta{DX: 20}})
+		pc.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: fyne.NewPos(at.X+40, at.Y)}, Dragged: fyne.Delta{DX: 20}})
+		pc.DragEnd()
+	}
+	drag(0)
+	if len(refused) != 1 || refused[0] != "p1" {
+		t.Fatalf("refused = %v, want [p1]", refused)
+	}
+	if b := pc.scene[0].Bounds(); b.X != 50 {
+		t.Fatalf("locked panel moved to %v", b)
+	}
+	drag(1)
+	if b := pc.scene[1].Bounds(); b.X <= 300 || len(refused) != 1 {
+		t.Fatalf("unlocked panel at %v, refused %v", b, refused)
+	}
+}
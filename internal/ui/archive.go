@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gocomicwriter/internal/storage"
+)
+
+// archiveFileName suggests the file name of a project archive: the project name without characters
+// file systems reject, or "project" without a name.
+func archiveFileName(projectName string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(projectName))
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		name = "project"
+	}
+	return name + storage.ArchiveExt
+}
+
+// archiveImportDir returns the folder to extract the archive at archivePath into when the user chose
+// the folder chosen: the folder itself when it is missing or empty, else a new subfolder named after
+// the archive, numbered when that name is taken.
+func archiveImportDir(chosen, archivePath string) string {
+	if dirFree(chosen) {
+		return chosen
+	}
+	base := strings.TrimSuffix(filepath.Base(archivePath), filepath.Ext(archivePath))
+	dir := filepath.Join(chosen, base)
+	for n := 2; !dirFree(dir); n++ {
+		dir = filepath.Join(chosen, fmt.Sprintf("%s-%d", base, n))
+	}
+	return dir
+}
+
+// dirFree reports whether dir does not exist or is an empty folder.
+func dirFree(dir string) bool {
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		_, serr := os.Stat(dir)
+		return os.IsNotExist(serr)
+	}
+	return len(ents) == 0
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchiveFileName(t *testing.T) {
+	for name, want := range map[string]string{
+		"Night Shift":       "Night Shift.gcwz",
+		"  A/B: C?  ":       "A-B- C-.gcwz",
+		"":                  "project.gcwz",
+		"...":               "project.gcwz",
+		"Issue <1> \"pre\"": "Issue -1- -pre-.gcwz",
+	} {
+		if got := archiveFileName(name); got != want {
+			t.Errorf("archiveFileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestArchiveImportDir(t *testing.T) {
+	parent := t.TempDir()
+	archive := filepath.Join(t.TempDir(), "Night Shift.gcwz")
+	if got := archiveImportDir(parent, archive); got != parent {
+		t.Errorf("empty folder: got %q, want the folder itself", got)
+	}
+	missing := filepath.Join(parent, "new")
+	if got := archiveImportDir(missing, archive); got != missing {
+		t.Errorf("missing folder: got %q, want it to be created", got)
+	}
+	if err := os.WriteFile(filepath.Join(parent, "other.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := archiveImportDir(parent, archive), filepath.Join(parent, "Night Shift"); got != want {
+		t.Errorf("non-empty folder: got %q, want %q", got, want)
+	}
+	if err := os.MkdirAll(filepath.Join(parent, "Night Shift", "script"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := archiveImportDir(parent, archive), filepath.Join(parent, "Night Shift-2"); got != want {
+		t.Errorf("taken subfolder: got %q, want %q", got, want)
+	}
+}
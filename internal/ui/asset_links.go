@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// brokenAsset is a missing asset file and the references to it.
+type brokenAsset struct {
+	Path string // normalized
+	Refs []storage.AssetRef
+}
+
+// brokenAssets groups the broken references of rep by file, in the order they were found.
+func brokenAssets(rep storage.AssetLinkReport) []brokenAsset {
+	var out []brokenAsset
+	at := map[string]int{}
+	for _, r := range rep.Broken {
+		p := storage.NormalizeAssetPath(r.Path)
+		i, ok := at[p]
+		if !ok {
+			i = len(out)
+			at[p] = i
+			out = append(out, brokenAsset{Path: p})
+		}
+		out[i].Refs = append(out[i].Refs, r)
+	}
+	return out
+}
+
+// assetRefLocation says where in the project an asset reference is stored.
+func assetRefLocation(r storage.AssetRef) string {
+	switch r.Kind {
+	case storage.AssetRefPanelNote:
+		return i18n.T("assets.ref_panel", r.PageNumber, r.PanelID)
+	case storage.AssetRefPageImage:
+		return i18n.T("assets.ref_page_image", r.PageNumber)
+	case storage.AssetRefPortrait:
+		return i18n.T("assets.ref_portrait", r.Character)
+	}
+	return r.Kind
+}
+
+// brokenAssetDetail lists the places referencing a missing file, e.g. "Page 3 panel p2, Portrait of Ada".
+func brokenAssetDetail(b brokenAsset) string {
+	locs := make([]string, 0, len(b.Refs))
+	for _, r := range b.Refs {
+		if loc := assetRefLocation(r); !slices.Contains(locs, loc) {
+			locs = append(locs, loc)
+		}
+	}
+	return strings.Join(locs, ", ")
+}
@@ -0,0 +1,155 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+package ui
+
+import (
+	"log/slog"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	fstorage "fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// assetLinkFix applies change, which rewrites asset references in the manifest, as one undoable step
+// and saves; it returns the number of references changed.
+type assetLinkFix func(undoLabel string, change func() (int, error)) (int, error)
+
+// showAssetLinksDialog scans the project's asset references in the background and lists the missing
+// files, each with a Relink button to pick its replacement, and the files in assets/ nothing uses. When
+// the missing files turn up in another folder, Fix All rewrites their folder; Rewrite Folder… does the
+// same for folders the author enters. The list is rescanned after every fix.
+func showAssetLinksDialog(w fyne.Window, ph *storage.ProjectHandle, l *slog.Logger, status *widget.Label, fix assetLinkFix) {
+	if ph == nil {
+		return
+	}
+	content := container.NewVBox()
+	var rescan func()
+	apply := func(label string, change func() (int, error)) {
+		n, err := fix(label, change)
+		if err != nil {
+			l.Error("fix asset links failed", slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		if n == 0 {
+			dialog.ShowInformation(i18n.T("msg.asset_links"), i18n.T("msg.nothing_relinked"), w)
+			return
+		}
+		status.SetText(i18n.N("status.assets_relinked", n, n))
+		rescan()
+	}
+	relink := func(b brokenAsset) {
+		open := dialog.NewFileOpen(func(ur fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			if ur == nil {
+				return
+			}
+			to := storage.AssetRelPath(ph.Root, ur.URI().Path())
+			_ = ur.Close()
+			apply(i18n.T("undo.relink_asset", b.Path), func() (int, error) { return storage.RelinkAsset(ph, b.Path, to) })
+		}, w)
+		if dir, err := fstorage.ListerForURI(fstorage.NewFileURI(filepath.Join(ph.Root, storage.AssetsDirName))); err == nil {
+			open.SetLocation(dir)
+		}
+		open.Show()
+	}
+	rewriteFolder := func(from, to string) {
+		oldEntry, newEntry := widget.NewEntry(), widget.NewEntry()
+		oldEntry.SetText(from)
+		newEntry.SetText(to)
+		dialog.ShowForm(i18n.T("msg.rewrite_asset_folder"), i18n.T("msg.apply"), i18n.T("msg.cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.T("form.old_folder"), oldEntry),
+			widget.NewFormItem(i18n.T("form.new_folder"), newEntry),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			from, to := oldEntry.Text, newEntry.Text
+			apply(i18n.T("undo.rewrite_asset_folder", from), func() (int, error) { return storage.RewriteAssetPrefix(ph, from, to) })
+		}, w)
+	}
+	render := func(rep storage.AssetLinkReport) {
+		content.RemoveAll()
+		summary := widget.NewLabel(i18n.T("msg.asset_links_summary", rep.Refs, len(rep.Broken), len(rep.Unreferenced)))
+		if len(rep.Broken) == 0 && len(rep.Unreferenced) == 0 {
+			summary.SetText(i18n.T("msg.asset_links_ok"))
+		}
+		summary.Wrapping = fyne.TextWrapWord
+		content.Add(summary)
+		from, to, moved := storage.SuggestAssetPrefix(rep)
+		if moved {
+			hint := widget.NewLabel(i18n.T("msg.asset_moved", from, to))
+			hint.Wrapping = fyne.TextWrapWord
+			fixAll := widget.NewButton(i18n.T("button.fix_all"), func() {
+				apply(i18n.T("undo.rewrite_asset_folder", from), func() (int, error) { return storage.RewriteAssetPrefix(ph, from, to) })
+			})
+			fixAll.Importance = widget.HighImportance
+			content.Add(container.NewBorder(nil, nil, nil, fixAll, hint))
+		}
+		if broken := brokenAssets(rep); len(broken) > 0 {
+			content.Add(container.NewBorder(nil, nil, widget.NewLabelWithStyle(i18n.T("label.broken_links"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), widget.NewButton(i18n.T("button.rewrite_folder"), func() {
+				rewriteFolder(from, to)
+			})))
+			for _, b := range broken {
+				name := widget.NewLabel(b.Path)
+				name.Truncation = fyne.TextTruncateEllipsis
+				detail := widget.NewLabel(brokenAssetDetail(b))
+				detail.Wrapping = fyne.TextWrapWord
+				detail.Importance = widget.LowImportance
+				content.Add(container.NewBorder(nil, nil, nil, widget.NewButton(i18n.T("button.relink"), func() { relink(b) }), container.NewVBox(name, detail)))
+			}
+		}
+		if len(rep.Unreferenced) > 0 {
+			content.Add(widget.NewLabelWithStyle(i18n.T("label.unreferenced_files"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			for _, f := range rep.Unreferenced {
+				name := widget.NewLabel(f.Path)
+				name.Truncation = fyne.TextTruncateEllipsis
+				content.Add(name)
+			}
+		}
+	}
+	// scan runs the scan off the UI thread and hands the report to done there.
+	scan := func(done func(storage.AssetLinkReport)) {
+		status.SetText(i18n.T("status.scanning_asset_links"))
+		h := *ph
+		go func() {
+			rep, err := storage.ScanAssetLinks(&h)
+			fyne.Do(func() {
+				if err != nil {
+					l.Error("scan asset links failed", slog.Any("err", err))
+					dialog.ShowError(FriendlyError(err), w)
+					status.SetText("")
+					return
+				}
+				l.Info("asset links scanned", slog.Int("refs", rep.Refs), slog.Int("broken", len(rep.Broken)), slog.Int("unreferenced", len(rep.Unreferenced)))
+				status.SetText(i18n.T("status.asset_links", len(rep.Broken), len(rep.Unreferenced)))
+				done(rep)
+			})
+		}()
+	}
+	rescan = func() { scan(render) }
+	scan(func(rep storage.AssetLinkReport) {
+		render(rep)
+		scroll := container.NewVScroll(content)
+		scroll.SetMinSize(fyne.NewSize(620, 360))
+		dialog.NewCustom(i18n.T("msg.asset_links"), i18n.T("msg.close"), scroll, w).Show()
+	})
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/storage"
+)
+
+func TestBrokenAssets(t *testing.T) {
+	rep := storage.AssetLinkReport{Broken: []storage.AssetRef{
+		{Kind: storage.AssetRefPanelNote, Path: "assets/chars/hero.png", PageNumber: 3, PanelID: "p2"},
+		{Kind: storage.AssetRefPageImage, Path: `assets\thumbs\p04.jpg`, PageNumber: 4},
+		{Kind: storage.AssetRefPortrait, Path: `assets\chars\hero.png`, Character: "Ada"},
+		{Kind: storage.AssetRefPanelNote, Path: "assets/chars/hero.png", PageNumber: 3, PanelID: "p2"},
+	}}
+	got := brokenAssets(rep)
+	if len(got) != 2 || got[0].Path != "assets/chars/hero.png" || len(got[0].Refs) != 3 || got[1].Path != "assets/thumbs/p04.jpg" {
+		t.Fatalf("brokenAssets = %+v", got)
+	}
+	if d := brokenAssetDetail(got[0]); d != "Page 3 panel p2, Portrait of Ada" {
+		t.Fatalf("detail = %q", d)
+	}
+	if d := brokenAssetDetail(got[1]); d != "Page 4 reference image" {
+		t.Fatalf("detail = %q", d)
+	}
+}
@@ -0,0 +1,166 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gocomicwriter/internal/storage"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// assetsDebounce coalesces bursts of filesystem events (copying many files, editors saving via temp files).
+	assetsDebounce = 500 * time.Millisecond
+	// assetsPollInterval is used when the platform cannot watch the folder.
+	assetsPollInterval = 30 * time.Second
+)
+
+// assetsWatcher watches <project>/assets and calls onChange after files were added, changed or removed.
+// It falls back to polling when a filesystem watcher cannot be set up.
+type assetsWatcher struct {
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// startAssetsWatcher starts watching the project's assets folder. onChange runs on the watcher goroutine.
+func startAssetsWatcher(root string, l *slog.Logger, onChange func()) *assetsWatcher {
+	aw := &assetsWatcher{stop: make(chan struct{}), done: make(chan struct{})}
+	dir := filepath.Join(root, storage.AssetsDirName)
+	l = l.With(slog.String("component", "assets_watch"), slog.String("dir", dir))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		l.Warn("ensure assets dir failed", slog.Any("err", err))
+	}
+	fw, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err = addWatchTree(fw, dir); err != nil {
+			_ = fw.Close()
+		}
+	}
+	if err != nil {
+		l.Warn("assets watch unavailable, polling instead", slog.Any("err", err), slog.Duration("interval", assetsPollInterval))
+		go aw.poll(dir, onChange)
+		return aw
+	}
+	go aw.watch(fw, l, onChange)
+	return aw
+}
+
+// Close stops the watcher and waits for its goroutine to exit. It is safe to call more than once.
+func (aw *assetsWatcher) Close() {
+	if aw == nil {
+		return
+	}
+	aw.stopOnce.Do(func() { close(aw.stop) })
+	<-aw.done
+}
+
+func (aw *assetsWatcher) watch(fw *fsnotify.Watcher, l *slog.Logger, onChange func()) {
+	defer close(aw.done)
+	defer func() { _ = fw.Close() }()
+	timer := time.NewTimer(assetsDebounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-aw.stop:
+			return
+		case ev, ok := <-fw.Events:
+			if !ok {
+				return
+			}
+			if storage.IsTransientAssetName(filepath.Base(ev.Name)) {
+				continue
+			}
+			// fsnotify is not recursive: pick up new subfolders as they appear
+			if ev.Has(fsnotify.Create) {
+				if fi, err := os.Stat(ev.Name); err == nil && fi.IsDir() {
+					if err := addWatchTree(fw, ev.Name); err != nil {
+						l.Warn("watch subfolder failed", slog.String("path", ev.Name), slog.Any("err", err))
+					}
+				}
+			}
+			timer.Reset(assetsDebounce)
+		case err, ok := <-fw.Errors:
+			if !ok {
+				return
+			}
+			// transient (e.g. event queue overflow); keep watching and refresh to resync
+			l.Warn("assets watch error", slog.Any("err", err))
+			timer.Reset(assetsDebounce)
+		case <-timer.C:
+			onChange()
+		}
+	}
+}
+
+func (aw *assetsWatcher) poll(dir string, onChange func()) {
+	defer close(aw.done)
+	t := time.NewTicker(assetsPollInterval)
+	defer t.Stop()
+	last := assetsSignature(dir)
+	for {
+		select {
+		case <-aw.stop:
+			return
+		case <-t.C:
+			if sig := assetsSignature(dir); sig != last {
+				last = sig
+				onChange()
+			}
+		}
+	}
+}
+
+// addWatchTree adds dir and its non-hidden subfolders to the watcher.
+func addWatchTree(fw *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			return fs.SkipDir
+		}
+		return fw.Add(path)
+	})
+}
+
+// assetsSignature summarizes names, sizes and modification times of the files under dir.
+func assetsSignature(dir string) string {
+	var b strings.Builder
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || storage.IsTransientAssetName(d.Name()) {
+			return nil
+		}
+		if fi, ierr := d.Info(); ierr == nil {
+			fmt.Fprintf(&b, "%s|%d|%d\n", path, fi.Size(), fi.ModTime().UnixNano())
+		}
+		return nil
+	})
+	return b.String()
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// auditRanges are the time ranges of the Audit Log dialog as message keys, with how far back each reaches;
+// zero means all entries.
+var auditRanges = []struct {
+	Key  string
+	Back time.Duration
+}{
+	{"option.audit_last_day", 24 * time.Hour},
+	{"option.audit_last_week", 7 * 24 * time.Hour},
+	{"option.audit_last_30_days", 30 * 24 * time.Hour},
+	{"option.audit_all", 0},
+}
+
+// auditFilter returns the audit log filter for the time range with index r in auditRanges and an
+// entity path typed by the user.
+func auditFilter(r int, entity string, now time.Time) storage.AuditFilter {
+	f := storage.AuditFilter{Entity: strings.Trim(strings.TrimSpace(entity), "/"), Limit: 500}
+	if r >= 0 && r < len(auditRanges) && auditRanges[r].Back > 0 {
+		f.Since = now.Add(-auditRanges[r].Back)
+	}
+	return f
+}
+
+// auditOperationText names an audit log operation for display; unknown ones are shown as stored.
+func auditOperationText(op string) string {
+	switch op {
+	case storage.AuditPageCreate:
+		return i18n.T("audit.page_create")
+	case storage.AuditPanelAdd:
+		return i18n.T("audit.panel_add")
+	case storage.AuditPanelUpdate:
+		return i18n.T("audit.panel_update")
+	case storage.AuditPanelZOrder:
+		return i18n.T("audit.panel_zorder")
+	case storage.AuditSave:
+		return i18n.T("audit.save")
+	case storage.AuditScriptWrite:
+		return i18n.T("audit.script_write")
+	}
+	return op
+}
+
+// auditEntryText describes an audit log entry: when and by whom, the operation, the entity (if any)
+// and the summary.
+func auditEntryText(e storage.AuditEntry) string {
+	parts := []string{e.TS.Local().Format("2006-01-02 15:04:05"), e.User, auditOperationText(e.Operation)}
+	if e.Entity != "" {
+		parts = append(parts, e.Entity)
+	}
+	if e.Summary != "" {
+		parts = append(parts, e.Summary)
+	}
+	return strings.Join(parts, " — ")
+}
@@ -0,0 +1,71 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+package ui
+
+import (
+	"context"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// showAuditLogDialog lists the recent audit log entries of the project at root, narrowed by a time
+// range and an entity path.
+func showAuditLogDialog(w fyne.Window, root string) {
+	var entries []storage.AuditEntry
+	empty := widget.NewLabel(i18n.T("label.audit_log_empty"))
+	list := widget.NewList(
+		func() int { return len(entries) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(auditEntryText(entries[id]))
+		},
+	)
+	ranges := make([]string, len(auditRanges))
+	for i, r := range auditRanges {
+		ranges[i] = i18n.T(r.Key)
+	}
+	rangeSel := widget.NewSelect(ranges, nil)
+	entity := widget.NewEntry()
+	entity.SetPlaceHolder(i18n.T("placeholder.audit_entity"))
+	reload := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		got, err := storage.AuditLog(ctx, root, auditFilter(rangeSel.SelectedIndex(), entity.Text, time.Now()))
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		entries = got
+		empty.Hidden = len(entries) > 0
+		empty.Refresh()
+		list.Refresh()
+		list.ScrollToTop()
+	}
+	rangeSel.OnChanged = func(string) { reload() }
+	entity.OnSubmitted = func(string) { reload() }
+	rangeSel.SetSelectedIndex(1) // loads the last week
+
+	hint := widget.NewLabel(i18n.T("label.audit_log_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	filters := container.NewBorder(nil, nil, rangeSel, nil, entity)
+	content := container.NewBorder(container.NewVBox(hint, filters), nil, nil, nil, container.NewStack(list, empty))
+	d := dialog.NewCustom(i18n.T("msg.audit_log"), i18n.T("msg.close"), content, w)
+	d.Resize(fyne.NewSize(760, 480))
+	d.Show()
+}
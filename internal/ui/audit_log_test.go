@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/storage"
+)
+
+func TestAuditFilter(t *testing.T) {
+	now := time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC)
+	f := auditFilter(1, " issue:1/page:3/ ", now)
+	if f.Entity != "issue:1/page:3" || !f.Since.Equal(now.Add(-7*24*time.Hour)) || !f.Until.IsZero() || f.Limit != 500 {
+		t.Fatalf("last week filter = %+v", f)
+	}
+	if f := auditFilter(len(auditRanges)-1, "", now); !f.Since.IsZero() || f.Entity != "" {
+		t.Fatalf("all filter = %+v", f)
+	}
+}
+
+func TestAuditEntryText(t *testing.T) {
+	ts := time.Date(2025, 3, 4, 10, 30, 0, 0, time.Local)
+	e := storage.AuditEntry{TS: ts, User: "ann", Operation: storage.AuditPanelAdd, Entity: "issue:1/page:2/panel:p3", Summary: "added panel p3 to page 2"}
+	if got, want := auditEntryText(e), "2025-03-04 10:30:00 — ann — Panel added — issue:1/page:2/panel:p3 — added panel p3 to page 2"; got != want {
+		t.Errorf("entry text = %q, want %q", got, want)
+	}
+	save := storage.AuditEntry{TS: ts, User: "ann", Operation: storage.AuditSave, Summary: "2 entities changed"}
+	if got, want := auditEntryText(save), "2025-03-04 10:30:00 — ann — Saved — 2 entities changed"; got != want {
+		t.Errorf("save text = %q, want %q", got, want)
+	}
+	if got := auditOperationText("custom.op"); got != "custom.op" {
+		t.Errorf("unknown op = %q", got)
+	}
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"path/filepath"
+	"time"
+
+	"gocomicwriter/internal/export"
+	"gocomicwriter/internal/i18n"
+)
+
+// compareDPI renders backup comparisons at screen resolution; print DPI would only make them slow.
+const compareDPI = 72
+
+// backupCompareDir is the folder under the project exports that receives the comparison with the
+// backup taken at t.
+func backupCompareDir(t time.Time) string {
+	return filepath.Join("compare", "backup-"+t.Format("20060102-150405"))
+}
+
+// backupCompareStatus sums up a comparison for the status bar.
+func backupCompareStatus(d *export.RenderDiff) string {
+	if len(d.ChangedPages) == 0 {
+		return i18n.N("status.backup_compare_same", len(d.Pages))
+	}
+	return i18n.T("status.backup_compare_changed", len(d.ChangedPages), len(d.Pages))
+}
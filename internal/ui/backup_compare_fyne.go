@@ -0,0 +1,88 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"log/slog"
+	"net/url"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	fstorage "fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/export"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// showCompareBackup asks for one of the project's backups, renders the differences between it and the
+// open project for issue issueIdx into the exports folder and opens that folder when done.
+func showCompareBackup(w fyne.Window, ph *storage.ProjectHandle, issueIdx int, l *slog.Logger, status *widget.Label) {
+	if ph == nil {
+		return
+	}
+	backups, err := storage.ListBackups(ph.Root)
+	if err != nil {
+		l.Error("list backups failed", slog.Any("err", err))
+		dialog.ShowError(FriendlyError(err), w)
+		return
+	}
+	if len(backups) == 0 {
+		dialog.ShowInformation(i18n.T("msg.compare_with_backup"), i18n.T("msg.no_backups_yet_a_backup_is"), w)
+		return
+	}
+	labels := make([]string, len(backups))
+	for i, b := range backups {
+		labels[i] = b.Time.Format("2006-01-02 15:04:05")
+	}
+	sel := widget.NewSelect(labels, nil)
+	sel.SetSelectedIndex(0)
+	dialog.ShowForm(i18n.T("msg.compare_with_backup"), i18n.T("button.compare"), i18n.T("msg.cancel"), []*widget.FormItem{
+		widget.NewFormItem(i18n.T("form.backup"), sel),
+	}, func(ok bool) {
+		i := sel.SelectedIndex()
+		if !ok || i < 0 {
+			return
+		}
+		b := backups[i]
+		p, gap, err := storage.LoadBackupProject(ph.Root, b.Path)
+		if err != nil {
+			l.Error("read backup failed", slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		old := &storage.ProjectHandle{Root: ph.Root, ManifestPath: ph.ManifestPath, Project: p}
+		d, err := export.DiffIssueRender(old, ph, issueIdx, backupCompareDir(b.Time), export.DiffOptions{DPI: compareDPI})
+		if err != nil {
+			l.Error("compare with backup failed", slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		l.Info("compared with backup", slog.String("backup", b.Path), slog.Int("changed_pages", len(d.ChangedPages)))
+		status.SetText(backupCompareStatus(d))
+		if gap != nil {
+			dialog.ShowInformation(i18n.T("msg.backup_partly_lost"), gap.String(), w)
+		}
+		dir := filepath.Join(ph.Root, "exports", backupCompareDir(b.Time))
+		u, err := url.Parse(fstorage.NewFileURI(dir).String())
+		if err == nil {
+			err = fyne.CurrentApp().OpenURL(u)
+		}
+		if err != nil {
+			l.Warn("open compare folder failed", slog.Any("err", err))
+			dialog.ShowInformation(i18n.T("msg.compare_with_backup"), i18n.T("msg.compare_written_to", dir), w)
+		}
+	}, w)
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/export"
+	"gocomicwriter/internal/i18n"
+)
+
+func TestBackupCompareDir(t *testing.T) {
+	at := time.Date(2025, 7, 1, 9, 5, 3, 0, time.Local)
+	if got, want := backupCompareDir(at), filepath.Join("compare", "backup-20250701-090503"); got != want {
+		t.Fatalf("dir = %q, want %q", got, want)
+	}
+}
+
+func TestBackupCompareStatus(t *testing.T) {
+	i18n.SetLocale("en")
+	d := &export.RenderDiff{Pages: make([]export.PageDiff, 4)}
+	if got := backupCompareStatus(d); got != "No differences on 4 pages" {
+		t.Errorf("unchanged: %q", got)
+	}
+	d.ChangedPages = []int{2}
+	if got := backupCompareStatus(d); got != "1 of 4 pages changed" {
+		t.Errorf("one changed: %q", got)
+	}
+	d.ChangedPages = []int{2, 3}
+	if got := backupCompareStatus(d); got != "2 of 4 pages changed" {
+		t.Errorf("two changed: %q", got)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/textutil"
+	"gocomicwriter/internal/vector"
+)
+
+// defaultBalloonSize is the font size exporters use for a run without one.
+const defaultBalloonSize = 12
+
+// balloonSizeOptions are the font sizes offered when editing balloon text.
+var balloonSizeOptions = []string{"8", "9", "10", "11", "12", "14", "16", "18", "20", "24"}
+
+// canvasBalloon is a balloon as shown on the page canvas, in sheet coordinates. Side 1 is the right
+// page of a spread.
+type canvasBalloon struct {
+	Side      int
+	PanelID   string
+	BalloonID string
+	Kind      string // ellipse, roundedBox, rect, burst or cloud
+	Rect      vector.Rect
+	Text      string
+	Order     int // reading position in the panel from 1
+}
+
+// balloonText joins a balloon's runs for editing, one run per line as the exporters draw them.
+func balloonText(runs []domain.TextRun) string {
+	parts := make([]string, len(runs))
+	for i, r := range runs {
+		parts[i] = r.Content
+	}
+	return strings.Join(parts, "\n")
+}
+
+// balloonRunsFromText turns edited text back into a single run. The font and the other typographic
+// settings of the first previous run are kept; size 0 keeps its size too.
+func balloonRunsFromText(text string, prev []domain.TextRun, size float64) []domain.TextRun {
+	run := domain.TextRun{Size: defaultBalloonSize}
+	if len(prev) > 0 {
+		run = prev[0]
+	}
+	run.Content = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if size > 0 {
+		run.Size = size
+	}
+	return []domain.TextRun{run}
+}
+
+// balloonFontSize is the size of a balloon's first run, or the exporters' default.
+func balloonFontSize(runs []domain.TextRun) float64 {
+	if len(runs) > 0 && runs[0].Size > 0 {
+		return runs[0].Size
+	}
+	return defaultBalloonSize
+}
+
+// balloonPreviewLine is the canvas preview of a balloon's text: its first non-empty line, cut to
+// maxRunes with an ellipsis.
+func balloonPreviewLine(text string, maxRunes int) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return textutil.TruncateRunes(line, maxRunes, "…")
+		}
+	}
+	return ""
+}
+
+// canvasBalloons lists the balloons of pg's panels, shifted right by dx points, for side.
+func canvasBalloons(pg domain.Page, dx float32, side int, out []canvasBalloon) []canvasBalloon {
+	for _, pn := range pg.Panels {
+		order := storage.BalloonOrderNumbers(pn.Balloons)
+		for _, b := range pn.Balloons {
+			r := b.Shape.Rect
+			out = append(out, canvasBalloon{
+				Side: side, PanelID: pn.ID, BalloonID: b.ID, Kind: b.Shape.Kind,
+				Rect: vector.R(float32(r.X)+dx, float32(r.Y), float32(r.Width), float32(r.Height)),
+				Text: balloonText(b.TextRuns), Order: order[b.ID],
+			})
+		}
+	}
+	return out
+}
+
+// balloonAt returns the index of the top-most balloon under pt, or -1. Ellipses are hit inside their
+// outline only.
+func balloonAt(list []canvasBalloon, pt vector.Pt) int {
+	for i := len(list) - 1; i >= 0; i-- {
+		r := list[i].Rect
+		if pt.X < r.X || pt.Y < r.Y || pt.X > r.X+r.W || pt.Y > r.Y+r.H {
+			continue
+		}
+		if list[i].Kind == "ellipse" && r.W > 0 && r.H > 0 {
+			nx := (pt.X - r.X - r.W/2) / (r.W / 2)
+			ny := (pt.Y - r.Y - r.H/2) / (r.H / 2)
+			if nx*nx+ny*ny > 1 {
+				continue
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// Double-click detection on the canvas: two taps within doubleTapInterval and doubleTapSlop screen
+// units. The canvas detects it itself because implementing fyne's DoubleTappable delays every single
+// tap, which would make selecting panels sluggish.
+const (
+	doubleTapInterval = 400 * time.Millisecond
+	doubleTapSlop     = 6
+)
+
+// tapTracker remembers the previous tap to recognise a double tap.
+type tapTracker struct {
+	at   time.Time
+	x, y float32
+}
+
+// double records a tap at (x, y) and reports whether it completes a double tap. A double tap resets
+// the tracker, so a third tap starts over.
+func (t *tapTracker) double(now time.Time, x, y float32) bool {
+	dx, dy := x-t.x, y-t.y
+	ok := !t.at.IsZero() && now.Sub(t.at) <= doubleTapInterval && dx*dx+dy*dy <= doubleTapSlop*doubleTapSlop
+	if ok {
+		*t = tapTracker{}
+		return true
+	}
+	*t = tapTracker{at: now, x: x, y: y}
+	return false
+}
@@ -0,0 +1,171 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"image"
+	"image/color"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/render"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/vector"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Preview text uses the 7x13 bitmap face.
+const (
+	balloonGlyphW = 7
+	balloonGlyphH = 13
+)
+
+// drawBalloons draws the balloons over the panels with m, each with the first line of its text where
+// it fits.
+func (p *PageCanvas) drawBalloons(img *image.RGBA, m vector.Affine2D) {
+	fill := vector.Fill{Enabled: true, Color: vector.White}
+	stroke := vector.Stroke{Enabled: true, Color: vector.Black, Width: 1}
+	ink := color.RGBA{A: 255}
+	selStroke := vector.Stroke{Enabled: true, Color: vector.Color{R: 0, G: 120, B: 215, A: 255}, Width: 2}
+	for _, b := range p.balloons {
+		st := stroke
+		if p.isSelectedBalloon(b) {
+			st = selStroke
+		}
+		n := balloonCanvasNode(b.Kind, b.Rect, fill, st)
+		inset := float32(0.08)
+		switch b.Kind {
+		case storage.BalloonShapeEllipse:
+			inset = 0.15 // the widest usable line of an ellipse is narrower than its box
+		case storage.BalloonShapeBurst, storage.BalloonShapeCloud:
+			inset = 0.2
+		}
+		vector.RasterizeNode(img, n, m)
+		tl := m.Apply(vector.Pt{X: b.Rect.X, Y: b.Rect.Y})
+		br := m.Apply(vector.Pt{X: b.Rect.X + b.Rect.W, Y: b.Rect.Y + b.Rect.H})
+		w, h := br.X-tl.X, br.Y-tl.Y
+		if p.ShowBalloonOrder && b.Order > 0 {
+			drawOrderBadge(img, tl, b.Order)
+		}
+		if h < balloonGlyphH {
+			continue
+		}
+		line := balloonPreviewLine(b.Text, int(w*(1-2*inset))/balloonGlyphW)
+		if line == "" {
+			continue
+		}
+		x := tl.X + (w-float32(balloonGlyphW*utf8.RuneCountInString(line)))/2
+		y := tl.Y + h/2 + balloonGlyphH/2 - 2 // baseline roughly centring the cap height
+		render.Text(img, int(x), int(y), line, ink)
+	}
+}
+
+// drawOrderBadge draws a balloon's reading position as a white number on a blue disc centred on
+// the balloon's top-left corner at pt (raster pixels).
+func drawOrderBadge(img *image.RGBA, pt vector.Pt, order int) {
+	label := strconv.Itoa(order)
+	d := float32(max(balloonGlyphH+4, balloonGlyphW*len(label)+6))
+	disc := vector.NewEllipse(vector.R(pt.X-d/2, pt.Y-d/2, d, d),
+		vector.Fill{Enabled: true, Color: vector.Color{R: 0, G: 90, B: 180, A: 230}}, vector.Stroke{})
+	vector.RasterizeNode(img, disc, vector.Scale(1, 1))
+	x := pt.X - float32(balloonGlyphW*len(label))/2
+	render.Text(img, int(x), int(pt.Y+balloonGlyphH/2-2), label, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+}
+
+// isSelectedBalloon reports whether b is the balloon selected by the last click.
+func (p *PageCanvas) isSelectedBalloon(b canvasBalloon) bool {
+	s := p.selBalloon
+	return s.BalloonID != "" && s.BalloonID == b.BalloonID && s.PanelID == b.PanelID && s.Side == b.Side
+}
+
+// SelectedBalloon returns the balloon selected by the last click while it is shown.
+func (p *PageCanvas) SelectedBalloon() (canvasBalloon, bool) {
+	for _, b := range p.balloons {
+		if p.isSelectedBalloon(b) {
+			return b, true
+		}
+	}
+	return canvasBalloon{}, false
+}
+
+// SelectBalloon selects the shown balloon with the given IDs, or none when balloonID is empty or the
+// balloon is not shown, and refreshes the canvas.
+func (p *PageCanvas) SelectBalloon(panelID, balloonID string) {
+	p.selBalloon = canvasBalloon{}
+	if balloonID != "" {
+		if i := slices.IndexFunc(p.balloons, func(b canvasBalloon) bool { return b.PanelID == panelID && b.BalloonID == balloonID }); i >= 0 {
+			p.selBalloon = p.balloons[i]
+		}
+	}
+	p.Refresh()
+}
+
+// showBalloonEditor edits a balloon's text, font size and balloon style; onSave receives the runs to
+// store and the name of the style, "" for none. Picking another style suggests its font size, and its
+// font goes into the runs.
+func showBalloonEditor(w fyne.Window, b domain.Balloon, styles []domain.BalloonStyle, onSave func(runs []domain.TextRun, style string)) {
+	entry := widget.NewMultiLineEntry()
+	entry.SetText(balloonText(b.TextRuns))
+	entry.SetMinRowsVisible(4)
+	size := strconv.FormatFloat(balloonFontSize(b.TextRuns), 'f', -1, 64)
+	sizes := balloonSizeOptions
+	if !slices.Contains(sizes, size) {
+		sizes = append([]string{size}, sizes...)
+	}
+	sizeSel := widget.NewSelect(sizes, nil)
+	sizeSel.SetSelected(size)
+	noStyle := i18n.T("option.no_balloon_style")
+	styleSel := widget.NewSelect(append([]string{noStyle}, balloonStyleNames(styles)...), nil)
+	styleSel.SetSelected(noStyle)
+	if s, ok := storage.FindBalloonStyle(styles, b.StyleRef); ok && b.StyleRef != "" {
+		styleSel.SetSelected(s.Name)
+	}
+	styleSel.OnChanged = func(name string) {
+		if s, ok := storage.FindBalloonStyle(styles, name); ok && s.Size > 0 {
+			v := strconv.FormatFloat(s.Size, 'f', -1, 64)
+			if !slices.Contains(sizeSel.Options, v) {
+				sizeSel.Options = append([]string{v}, sizeSel.Options...)
+			}
+			sizeSel.SetSelected(v)
+		}
+	}
+	form := dialog.NewForm(i18n.T("msg.edit_balloon", b.ID), i18n.T("msg.save"), i18n.T("msg.cancel"), []*widget.FormItem{
+		widget.NewFormItem(i18n.T("form.text"), entry),
+		widget.NewFormItem(i18n.T("form.balloon_style"), styleSel),
+		widget.NewFormItem(i18n.T("form.font_size"), sizeSel),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		style := styleSel.Selected
+		if style == noStyle {
+			style = ""
+		}
+		nb := domain.Balloon{TextRuns: b.TextRuns}
+		if s, ok := storage.FindBalloonStyle(styles, style); ok && style != "" && !strings.EqualFold(style, b.StyleRef) {
+			storage.ApplyBalloonStyle(&nb, s)
+		}
+		pt, _ := strconv.ParseFloat(sizeSel.Selected, 64)
+		onSave(balloonRunsFromText(entry.Text, nb.TextRuns, pt), style)
+	}, w)
+	form.Resize(fyne.NewSize(420, 260))
+	form.Show()
+	w.Canvas().Focus(entry)
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/vector"
+)
+
+func TestBalloonTextRoundTrip(t *testing.T) {
+	prev := []domain.TextRun{{Content: "Hello", Font: "Comic", Size: 10}, {Content: "there!", Size: 14}}
+	text := balloonText(prev)
+	if text != "Hello\nthere!" {
+		t.Fatalf("text = %q", text)
+	}
+	got := balloonRunsFromText(" Hi\r\nthere \n", prev, 0)
+	if want := []domain.TextRun{{Content: "Hi\nthere", Font: "Comic", Size: 10}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("runs = %+v, want %+v", got, want)
+	}
+	if got := balloonRunsFromText("x", prev, 16); got[0].Size != 16 || got[0].Font != "Comic" {
+		t.Errorf("resized run = %+v", got[0])
+	}
+	if got := balloonRunsFromText("x", nil, 0); got[0].Size != defaultBalloonSize {
+		t.Errorf("new run size = %v", got[0].Size)
+	}
+	if balloonFontSize(nil) != defaultBalloonSize || balloonFontSize(prev) != 10 {
+		t.Error("balloonFontSize")
+	}
+}
+
+func TestBalloonPreviewLine(t *testing.T) {
+	if got := balloonPreviewLine("\n  Where are we?\nNo idea.", 40); got != "Where are we?" {
+		t.Errorf("preview = %q", got)
+	}
+	if got := balloonPreviewLine("Where are we?", 5); got != "Where…" {
+		t.Errorf("truncated = %q", got)
+	}
+	if got := balloonPreviewLine(" \n", 6); got != "" {
+		t.Errorf("blank = %q", got)
+	}
+}
+
+func TestCanvasBalloonsAndHitTest(t *testing.T) {
+	pg := domain.Page{Panels: []domain.Panel{{ID: "p1", Balloons: []domain.Balloon{
+		{ID: "b1", Order: 2, Shape: domain.Shape{Kind: "rect", Rect: domain.Rect{X: 10, Y: 10, Width: 100, Height: 50}}},
+		{ID: "b2", Order: 1, Shape: domain.Shape{Kind: "ellipse", Rect: domain.Rect{X: 50, Y: 20, Width: 100, Height: 100}}},
+	}}}}
+	list := canvasBalloons(pg, 200, 1, nil)
+	if len(list) != 2 || list[0].Rect.X != 210 || list[1].Side != 1 || list[1].PanelID != "p1" || list[0].Order != 2 || list[1].Order != 1 {
+		t.Fatalf("balloons = %+v", list)
+	}
+	cases := []struct {
+		pt   vector.Pt
+		want int
+	}{
+		{vector.Pt{X: 300, Y: 70}, 1},   // ellipse centre, drawn on top
+		{vector.Pt{X: 252, Y: 22}, 0},   // ellipse bounding-box corner falls through to the rect
+		{vector.Pt{X: 345, Y: 115}, -1}, // ellipse corner with nothing beneath
+		{vector.Pt{X: 20, Y: 20}, -1},   // left page
+	}
+	for _, c := range cases {
+		if got := balloonAt(list, c.pt); got != c.want {
+			t.Errorf("balloonAt(%v) = %d, want %d", c.pt, got, c.want)
+		}
+	}
+}
+
+func TestTapTrackerDouble(t *testing.T) {
+	var tt tapTracker
+	t0 := time.Unix(1000, 0)
+	if tt.double(t0, 10, 10) {
+		t.Fatal("first tap reported as double")
+	}
+	if !tt.double(t0.Add(200*time.Millisecond), 12, 11) {
+		t.Fatal("second nearby tap not a double")
+	}
+	if tt.double(t0.Add(300*time.Millisecond), 12, 11) {
+		t.Error("third tap should start over")
+	}
+	if tt.double(t0.Add(time.Second), 12, 11) {
+		t.Error("slow tap reported as double")
+	}
+	if tt.double(t0.Add(1100*time.Millisecond), 40, 11) {
+		t.Error("distant tap reported as double")
+	}
+}
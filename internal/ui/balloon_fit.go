@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"math"
+	"strings"
+
+	"gocomicwriter/internal/text"
+	"gocomicwriter/internal/vector"
+)
+
+// measuredContent returns a vector.SuggestOptions.ContentSize hook that sizes a balloon or caption to
+// its text, wrapped and hyphenated for lang. Text set in an ellipse only fits when the ellipse is √2
+// times the text block in each direction, so the block is wrapped to the correspondingly narrower
+// width and scaled up. Empty text measures as zero, which keeps the default size.
+func measuredContent(txt string, size float64, lang string, ellipse bool) func(maxWidth float32) vector.Size {
+	return func(maxWidth float32) vector.Size {
+		if strings.TrimSpace(txt) == "" {
+			return vector.Size{}
+		}
+		scale := 1.0
+		if ellipse {
+			scale = math.Sqrt2
+		}
+		_, w, h := text.FitBlock(txt, float64(maxWidth)/scale, text.WrapOptions{Size: size, Lang: lang})
+		return vector.Size{W: float32(math.Ceil(w * scale)), H: float32(math.Ceil(h * scale))}
+	}
+}
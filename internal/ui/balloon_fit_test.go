@@ -0,0 +1,36 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/vector"
+)
+
+func TestMeasuredContent(t *testing.T) {
+	if sz := measuredContent("  ", 12, "en", true)(200); sz != (vector.Size{}) {
+		t.Fatalf("empty text measured as %+v", sz)
+	}
+	rect := measuredContent("Wait for me!", 12, "en", false)(200)
+	if rect.W <= 0 || rect.W > 200 || rect.H != 15 { // one line: ceil(12 * 1.2)
+		t.Fatalf("caption size = %+v", rect)
+	}
+	oval := measuredContent("Wait for me!", 12, "en", true)(200)
+	if oval.W <= rect.W || oval.H <= rect.H {
+		t.Fatalf("ellipse %+v not larger than text block %+v", oval, rect)
+	}
+	// Long text wraps to the available width instead of growing past it
+	long := measuredContent("Das ist die längste Geschichte, die ich je gehört habe, ehrlich.", 12, "de", true)(150)
+	if long.W > 150 || long.H < 2*15 {
+		t.Fatalf("wrapped size = %+v", long)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/render"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/vector"
+)
+
+// balloonShapeKeys and balloonStrokeKeys are the message keys of the choices in the balloon style
+// dialog; the dialog shows and compares them translated.
+var (
+	balloonShapeKeys = map[string]string{
+		storage.BalloonShapeEllipse:    "option.balloon_shape_ellipse",
+		storage.BalloonShapeRoundedBox: "option.balloon_shape_rounded_box",
+		storage.BalloonShapeRect:       "option.balloon_shape_rect",
+		storage.BalloonShapeBurst:      "option.balloon_shape_burst",
+		storage.BalloonShapeCloud:      "option.balloon_shape_cloud",
+	}
+	balloonStrokeKeys = map[string]string{
+		domain.BalloonStrokeSolid:  "option.balloon_stroke_solid",
+		domain.BalloonStrokeDashed: "option.balloon_stroke_dashed",
+		domain.BalloonStrokeNone:   "option.balloon_stroke_none",
+	}
+)
+
+// balloonShapeLabels and balloonStrokeLabels list the translated choices in the dialog's order.
+func balloonShapeLabels() []string { return choiceLabels(storage.BalloonShapes, balloonShapeKeys) }
+func balloonStrokeLabels() []string {
+	return choiceLabels(storage.BalloonStrokeStyles, balloonStrokeKeys)
+}
+
+func choiceLabels(values []string, keys map[string]string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = i18n.T(keys[v])
+	}
+	return out
+}
+
+// choiceValue returns the value whose translated label is label, or "" when none matches.
+func choiceValue(label string, values []string, keys map[string]string) string {
+	for _, v := range values {
+		if i18n.T(keys[v]) == label {
+			return v
+		}
+	}
+	return ""
+}
+
+// balloonStyleForm holds the fields of the balloon style dialog as text. Shape and StrokeStyle are
+// stored values; colors are #rrggbb and blank numbers, colors and font mean "use the default".
+type balloonStyleForm struct {
+	Name, Shape, Radius, Stroke, StrokeWidth, StrokeStyle, Fill, Font, Size, Padding string
+}
+
+// balloonStyleFields fills the dialog from a preset.
+func balloonStyleFields(s domain.BalloonStyle) balloonStyleForm {
+	num := func(v float64) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	f := balloonStyleForm{
+		Name: s.Name, Shape: s.Shape, Radius: num(s.Radius), Stroke: formatHexColor(s.Stroke),
+		StrokeWidth: num(s.StrokeWidth), StrokeStyle: s.StrokeStyle, Fill: formatHexColor(s.Fill),
+		Font: s.Font, Size: num(s.Size), Padding: num(s.Padding),
+	}
+	if f.Shape == "" {
+		f.Shape = storage.BalloonShapeEllipse
+	}
+	if f.StrokeStyle == "" {
+		f.StrokeStyle = domain.BalloonStrokeSolid
+	}
+	return f
+}
+
+// parseBalloonStyle builds a preset from the dialog fields and validates it.
+func parseBalloonStyle(f balloonStyleForm) (domain.BalloonStyle, error) {
+	s := domain.BalloonStyle{Name: strings.TrimSpace(f.Name), Shape: f.Shape, StrokeStyle: f.StrokeStyle, Font: strings.TrimSpace(f.Font)}
+	if s.StrokeStyle == domain.BalloonStrokeSolid {
+		s.StrokeStyle = ""
+	}
+	for _, n := range []struct {
+		label, text string
+		dst         *float64
+	}{{"corner radius", f.Radius, &s.Radius}, {"stroke width", f.StrokeWidth, &s.StrokeWidth}, {"font size", f.Size, &s.Size}, {"padding", f.Padding, &s.Padding}} {
+		t := strings.TrimSpace(n.text)
+		if t == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(t, 64)
+		if err != nil || v < 0 {
+			return s, fmt.Errorf("%s %q must be a non-negative number of points", n.label, t)
+		}
+		*n.dst = v
+	}
+	for _, c := range []struct {
+		text string
+		dst  **domain.Color
+	}{{f.Stroke, &s.Stroke}, {f.Fill, &s.Fill}} {
+		if strings.TrimSpace(c.text) == "" {
+			continue
+		}
+		col, err := parseHexColor(c.text)
+		if err != nil {
+			return s, err
+		}
+		*c.dst = &col
+	}
+	return s, storage.ValidateBalloonStyle(s)
+}
+
+// balloonStyleNames lists the names of the balloon presets in project order.
+func balloonStyleNames(list []domain.BalloonStyle) []string {
+	names := make([]string, 0, len(list))
+	for _, s := range list {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+// newStyledBalloon is the balloon Insert → Balloon adds: one run of text in rect, shaped and lettered
+// by the named preset when the project has it. A font picked in the dialog overrides the preset's.
+func newStyledBalloon(id, text, font string, styles []domain.BalloonStyle, style string, rect vector.Rect) domain.Balloon {
+	b := domain.Balloon{
+		ID: id, Type: "speech",
+		TextRuns: []domain.TextRun{{Content: text, Size: defaultBalloonSize}},
+		Shape:    domain.Shape{Kind: storage.BalloonShapeEllipse, Rect: domain.Rect{X: float64(rect.X), Y: float64(rect.Y), Width: float64(rect.W), Height: float64(rect.H)}},
+	}
+	if s, ok := storage.FindBalloonStyle(styles, style); ok && style != "" {
+		storage.ApplyBalloonStyle(&b, s)
+	}
+	if font != "" {
+		b.TextRuns[0].Font = font
+	}
+	return b
+}
+
+// balloonCanvasNode is the canvas shape of a balloon of the given kind: an ellipse, the outline of a
+// burst or cloud, or its box for every other kind.
+func balloonCanvasNode(kind string, r vector.Rect, fill vector.Fill, stroke vector.Stroke) vector.Node {
+	switch kind {
+	case storage.BalloonShapeEllipse:
+		return vector.NewEllipse(r, fill, stroke)
+	case storage.BalloonShapeBurst, storage.BalloonShapeCloud:
+		var p vector.Path
+		for i, pt := range render.BalloonPolygon(kind, float64(r.X), float64(r.Y), float64(r.W), float64(r.H)) {
+			if i == 0 {
+				p.MoveTo(float32(pt[0]), float32(pt[1]))
+			} else {
+				p.LineTo(float32(pt[0]), float32(pt[1]))
+			}
+		}
+		p.Close()
+		return vector.NewPath(p, fill, stroke)
+	}
+	return vector.NewRect(r, fill, stroke)
+}
@@ -0,0 +1,161 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"log/slog"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// showBalloonStyles lists the balloon presets of the project next to a form to edit the selected one.
+// Saving under a name the project does not use yet adds a preset, so renaming keeps the old one; every
+// change is saved to the manifest right away. onChanged runs after a save, e.g. to redraw the page.
+func showBalloonStyles(w fyne.Window, ph *storage.ProjectHandle, l *slog.Logger, status *widget.Label, onChanged func()) {
+	nameEntry := widget.NewEntry()
+	shapeSel := widget.NewSelect(balloonShapeLabels(), nil)
+	radiusEntry := widget.NewEntry()
+	strokeEntry := widget.NewEntry()
+	strokeEntry.SetPlaceHolder("#000000")
+	widthEntry := widget.NewEntry()
+	strokeSel := widget.NewSelect(balloonStrokeLabels(), nil)
+	fillEntry := widget.NewEntry()
+	fillEntry.SetPlaceHolder("#ffffff")
+	fontSel := newFontPicker(ph.Root)
+	sizeEntry := widget.NewEntry()
+	paddingEntry := widget.NewEntry()
+
+	show := func(s domain.BalloonStyle) {
+		f := balloonStyleFields(s)
+		nameEntry.SetText(f.Name)
+		shapeSel.SetSelected(i18n.T(balloonShapeKeys[f.Shape]))
+		radiusEntry.SetText(f.Radius)
+		strokeEntry.SetText(f.Stroke)
+		widthEntry.SetText(f.StrokeWidth)
+		strokeSel.SetSelected(i18n.T(balloonStrokeKeys[f.StrokeStyle]))
+		fillEntry.SetText(f.Fill)
+		if f.Font == "" {
+			fontSel.SetSelected(fontDefaultOption)
+		} else {
+			if !slices.Contains(fontSel.Options, f.Font) {
+				fontSel.Options = append(fontSel.Options, f.Font)
+			}
+			fontSel.SetSelected(f.Font)
+		}
+		sizeEntry.SetText(f.Size)
+		paddingEntry.SetText(f.Padding)
+	}
+	selected := -1
+	list := widget.NewList(
+		func() int { return len(ph.Project.BalloonStyles) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(ph.Project.BalloonStyles[id].Name)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		show(ph.Project.BalloonStyles[id])
+	}
+	persist := func(msg string, attrs ...any) bool {
+		if err := storage.Save(ph); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return false
+		}
+		l.Info(msg, attrs...)
+		list.Refresh()
+		if onChanged != nil {
+			onChanged()
+		}
+		return true
+	}
+
+	newBtn := widget.NewButton(i18n.T("button.new_balloon_style"), func() {
+		list.UnselectAll()
+		selected = -1
+		show(domain.BalloonStyle{})
+	})
+	saveBtn := widget.NewButton(i18n.T("button.save_balloon_style"), func() {
+		s, err := parseBalloonStyle(balloonStyleForm{
+			Name: nameEntry.Text, Shape: choiceValue(shapeSel.Selected, storage.BalloonShapes, balloonShapeKeys),
+			Radius: radiusEntry.Text, Stroke: strokeEntry.Text, StrokeWidth: widthEntry.Text,
+			StrokeStyle: choiceValue(strokeSel.Selected, storage.BalloonStrokeStyles, balloonStrokeKeys),
+			Fill:        fillEntry.Text, Font: fontFromOption(fontSel.Selected), Size: sizeEntry.Text, Padding: paddingEntry.Text,
+		})
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		if err := storage.PutBalloonStyle(ph, s); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		if persist("balloon style saved", slog.String("style", s.Name)) {
+			status.SetText(i18n.T("status.balloon_style_saved", s.Name))
+			if i := slices.IndexFunc(ph.Project.BalloonStyles, func(x domain.BalloonStyle) bool { return x.Name == s.Name }); i >= 0 {
+				list.Select(i)
+			}
+		}
+	})
+	deleteBtn := widget.NewButton(i18n.T("button.delete"), func() {
+		if selected < 0 || selected >= len(ph.Project.BalloonStyles) {
+			return
+		}
+		name := ph.Project.BalloonStyles[selected].Name
+		dialog.ShowConfirm(i18n.T("msg.balloon_styles"), i18n.T("msg.delete_the_balloon_style", name), func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := storage.DeleteBalloonStyle(ph, name); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			if persist("balloon style deleted", slog.String("style", name)) {
+				status.SetText(i18n.T("status.balloon_style_deleted", name))
+				list.UnselectAll()
+				selected = -1
+				show(domain.BalloonStyle{})
+			}
+		}, w)
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem(i18n.T("form.name"), nameEntry),
+		widget.NewFormItem(i18n.T("form.shape"), shapeSel),
+		widget.NewFormItem(i18n.T("form.corner_radius"), radiusEntry),
+		widget.NewFormItem(i18n.T("form.stroke_color"), strokeEntry),
+		widget.NewFormItem(i18n.T("form.stroke_width"), widthEntry),
+		widget.NewFormItem(i18n.T("form.stroke_style"), strokeSel),
+		widget.NewFormItem(i18n.T("form.fill_color"), fillEntry),
+		widget.NewFormItem(i18n.T("form.font"), fontSel),
+		widget.NewFormItem(i18n.T("form.font_size"), sizeEntry),
+		widget.NewFormItem(i18n.T("form.padding"), paddingEntry),
+	)
+	split := container.NewHSplit(list, container.NewVScroll(container.NewVBox(form, container.NewHBox(newBtn, saveBtn, deleteBtn))))
+	split.Offset = 0.3
+	d := dialog.NewCustom(i18n.T("msg.balloon_styles"), i18n.T("msg.close"), split, w)
+	d.Resize(fyne.NewSize(640, 480))
+	show(domain.BalloonStyle{})
+	if len(ph.Project.BalloonStyles) > 0 {
+		list.Select(0)
+	}
+	d.Show()
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/vector"
+)
+
+func TestBalloonStyleFormRoundTrip(t *testing.T) {
+	for _, s := range storage.DefaultBalloonStyles() {
+		f := balloonStyleFields(s)
+		got, err := parseBalloonStyle(f)
+		if err != nil {
+			t.Fatalf("%s: %v", s.Name, err)
+		}
+		if got.Name != s.Name || got.Shape != s.Shape || got.StrokeStyle != s.StrokeStyle || got.Size != s.Size || got.Padding != s.Padding || got.StrokeWidth != s.StrokeWidth {
+			t.Fatalf("%s: got %+v", s.Name, got)
+		}
+	}
+	red := domain.Color{R: 255, A: 255}
+	f := balloonStyleFields(domain.BalloonStyle{Name: "Radio", Shape: storage.BalloonShapeRoundedBox, Radius: 8, Stroke: &red})
+	if f.Stroke != "#ff0000" || f.Radius != "8" || f.StrokeStyle != domain.BalloonStrokeSolid || f.Fill != "" {
+		t.Fatalf("fields: %+v", f)
+	}
+	s, err := parseBalloonStyle(f)
+	if err != nil || s.Stroke == nil || *s.Stroke != red || s.Fill != nil || s.StrokeStyle != "" {
+		t.Fatalf("parse: %+v, %v", s, err)
+	}
+}
+
+func TestParseBalloonStyleRejects(t *testing.T) {
+	base := balloonStyleForm{Name: "X", Shape: storage.BalloonShapeEllipse, StrokeStyle: domain.BalloonStrokeSolid}
+	for _, mod := range []func(*balloonStyleForm){
+		func(f *balloonStyleForm) { f.Name = " " },
+		func(f *balloonStyleForm) { f.Shape = "star" },
+		func(f *balloonStyleForm) { f.Size = "big" },
+		func(f *balloonStyleForm) { f.Padding = "-2" },
+		func(f *balloonStyleForm) { f.Fill = "white" },
+	} {
+		f := base
+		mod(&f)
+		if _, err := parseBalloonStyle(f); err == nil {
+			t.Fatalf("accepted %+v", f)
+		}
+	}
+}
+
+func TestBalloonChoiceLabels(t *testing.T) {
+	labels := balloonShapeLabels()
+	if len(labels) != len(storage.BalloonShapes) || len(balloonStrokeLabels()) != len(storage.BalloonStrokeStyles) {
+		t.Fatalf("labels: %v", labels)
+	}
+	for i, v := range storage.BalloonShapes {
+		if labels[i] == balloonShapeKeys[v] {
+			t.Fatalf("untranslated %s", labels[i])
+		}
+		if got := choiceValue(labels[i], storage.BalloonShapes, balloonShapeKeys); got != v {
+			t.Fatalf("choiceValue(%q) = %q, want %q", labels[i], got, v)
+		}
+	}
+	if got := choiceValue(i18n.T(balloonStrokeKeys[domain.BalloonStrokeDashed]), storage.BalloonStrokeStyles, balloonStrokeKeys); got != domain.BalloonStrokeDashed {
+		t.Fatalf("stroke choice: %q", got)
+	}
+	if got := choiceValue("nope", storage.BalloonShapes, balloonShapeKeys); got != "" {
+		t.Fatalf("unknown label: %q", got)
+	}
+}
+
+func TestNewStyledBalloon(t *testing.T) {
+	styles := storage.DefaultBalloonStyles()
+	r := vector.Rect{X: 10, Y: 20, W: 100, H: 60}
+	b := newStyledBalloon("b1", "AAARGH", "", styles, "shout", r)
+	if b.StyleRef != "Shout" || b.Shape.Kind != storage.BalloonShapeBurst || b.TextRuns[0].Size != 16 || b.Shape.Rect.Width != 100 {
+		t.Fatalf("shout: %+v", b)
+	}
+	b = newStyledBalloon("b2", "hi", "Comic Neue", styles, "Whisper", r)
+	if b.TextRuns[0].Font != "Comic Neue" || b.TextRuns[0].Size != 10 {
+		t.Fatalf("font override: %+v", b.TextRuns)
+	}
+	b = newStyledBalloon("b3", "hi", "", styles, "Missing", r)
+	if b.StyleRef != "" || b.Shape.Kind != storage.BalloonShapeEllipse || b.TextRuns[0].Size != defaultBalloonSize {
+		t.Fatalf("unknown style: %+v", b)
+	}
+}
+
+func TestBalloonCanvasNode(t *testing.T) {
+	r := vector.Rect{W: 100, H: 50}
+	if _, ok := balloonCanvasNode(storage.BalloonShapeEllipse, r, vector.Fill{}, vector.Stroke{}).(*vector.EllipseNode); !ok {
+		t.Fatal("ellipse")
+	}
+	for _, k := range []string{storage.BalloonShapeBurst, storage.BalloonShapeCloud} {
+		if _, ok := balloonCanvasNode(k, r, vector.Fill{}, vector.Stroke{}).(*vector.PathNode); !ok {
+			t.Fatalf("%s is not a path", k)
+		}
+	}
+	if _, ok := balloonCanvasNode(storage.BalloonShapeRoundedBox, r, vector.Fill{}, vector.Stroke{}).(*vector.RectNode); !ok {
+		t.Fatal("rounded box")
+	}
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"math"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// beatSuggestionBeat shows the beat of a suggestion as it reads in the script, led by its scene,
+// e.g. "Harbor · PANEL 2: Mara climbs the stairs.".
+func beatSuggestionBeat(s storage.BeatSuggestion) string {
+	beat := s.Marker
+	if s.Text != "" {
+		beat += ": " + s.Text
+	}
+	if s.Scene != "" {
+		beat = s.Scene + " · " + beat
+	}
+	return beat
+}
+
+// beatSuggestionTarget shows the panel a suggestion proposes and its confidence in percent.
+func beatSuggestionTarget(s storage.BeatSuggestion) string {
+	return i18n.T("beats.suggestion", s.PageNumber, s.PanelID, int(math.Round(s.Confidence*100)))
+}
+
+// applyBeatSuggestions maps the suggested beats to their panels with MapBeatToPanel and returns how
+// many were mapped. It stops at the first failure, e.g. a panel deleted since the suggestions were made.
+func applyBeatSuggestions(ph *storage.ProjectHandle, ss []storage.BeatSuggestion) (int, error) {
+	for i, s := range ss {
+		if err := storage.MapBeatToPanel(ph, s.PageNumber, s.PanelID, s.BeatID); err != nil {
+			return i, err
+		}
+	}
+	return len(ss), nil
+}
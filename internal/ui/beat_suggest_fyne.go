@@ -0,0 +1,117 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+package ui
+
+import (
+	"log/slog"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/script"
+	"gocomicwriter/internal/storage"
+)
+
+// beatSuggestionsApply maps the accepted suggestions as one undoable step and saves; it returns how
+// many were mapped.
+type beatSuggestionsApply func(ss []storage.BeatSuggestion) (int, error)
+
+// showBeatSuggestionsDialog lists the panels storage.SuggestBeatMappings proposes for the unmapped
+// beats of sc, each with a checkbox. Accept Selected maps the checked beats and Accept All every one,
+// in one step; changing the beats per panel suggests again.
+func showBeatSuggestionsDialog(w fyne.Window, ph *storage.ProjectHandle, sc script.Script, l *slog.Logger, status *widget.Label, apply beatSuggestionsApply) {
+	if ph == nil {
+		return
+	}
+	suggestions := storage.SuggestBeatMappings(ph.Project, sc, storage.DefaultBeatsPerPanel)
+	if len(suggestions) == 0 {
+		dialog.ShowInformation(i18n.T("msg.beat_suggestions"), i18n.T("msg.no_beat_suggestions"), w)
+		return
+	}
+	count := widget.NewLabel("")
+	rows := container.NewVBox()
+	var checks []*widget.Check
+	var acceptSel, acceptAll *widget.Button
+	render := func() {
+		rows.RemoveAll()
+		checks = checks[:0]
+		count.SetText(i18n.N("beats.suggestion_count", len(suggestions), len(suggestions)))
+		if len(suggestions) == 0 {
+			count.SetText(i18n.T("msg.no_beat_suggestions"))
+		}
+		for _, s := range suggestions {
+			beat := widget.NewLabel(beatSuggestionBeat(s))
+			beat.Truncation = fyne.TextTruncateEllipsis
+			target := widget.NewLabel(beatSuggestionTarget(s))
+			target.Importance = widget.LowImportance
+			check := widget.NewCheck("", nil)
+			check.SetChecked(true)
+			checks = append(checks, check)
+			rows.Add(container.NewBorder(nil, nil, check, nil, container.NewVBox(beat, target)))
+		}
+		if len(suggestions) == 0 {
+			acceptSel.Disable()
+			acceptAll.Disable()
+		} else {
+			acceptSel.Enable()
+			acceptAll.Enable()
+		}
+	}
+	perPanel := widget.NewSelect([]string{"1", "2", "3", "4"}, func(v string) {
+		n, _ := strconv.Atoi(v)
+		suggestions = storage.SuggestBeatMappings(ph.Project, sc, n)
+		render()
+	})
+	var d dialog.Dialog
+	accept := func(ss []storage.BeatSuggestion) {
+		if len(ss) == 0 {
+			return
+		}
+		n, err := apply(ss)
+		if n > 0 {
+			l.Info("beat suggestions accepted", slog.Int("beats", n), slog.Int("suggested", len(suggestions)))
+			status.SetText(i18n.N("status.beat_suggestions_accepted", n, n))
+		}
+		if err != nil {
+			l.Error("accept beat suggestions failed", slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		d.Hide()
+	}
+	acceptSel = widget.NewButton(i18n.T("button.accept_selected"), func() {
+		var ss []storage.BeatSuggestion
+		for i, c := range checks {
+			if c.Checked {
+				ss = append(ss, suggestions[i])
+			}
+		}
+		accept(ss)
+	})
+	acceptAll = widget.NewButton(i18n.T("button.accept_all"), func() { accept(suggestions) })
+	acceptAll.Importance = widget.HighImportance
+	perPanel.SetSelectedIndex(storage.DefaultBeatsPerPanel - 1)
+	help := widget.NewLabel(i18n.T("msg.beat_suggestions_help"))
+	help.Wrapping = fyne.TextWrapWord
+	top := container.NewVBox(help,
+		container.NewBorder(nil, nil, widget.NewLabel(i18n.T("form.beats_per_panel")), nil, container.NewHBox(perPanel)),
+		container.NewBorder(nil, nil, count, container.NewHBox(acceptSel, acceptAll)),
+	)
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(620, 360))
+	d = dialog.NewCustom(i18n.T("msg.beat_suggestions"), i18n.T("msg.close"), container.NewBorder(top, nil, nil, nil, scroll), w)
+	d.Show()
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func TestBeatSuggestionText(t *testing.T) {
+	s := storage.BeatSuggestion{BeatID: "b:3", Marker: "PANEL 2", Text: "Mara climbs the stairs.", Scene: "Harbor", PageNumber: 1, PanelID: "a2", Confidence: 0.846}
+	if got := beatSuggestionBeat(s); got != "Harbor · PANEL 2: Mara climbs the stairs." {
+		t.Errorf("beat = %q", got)
+	}
+	if got := beatSuggestionTarget(s); got != "Page 1 panel a2 · 85%" {
+		t.Errorf("target = %q", got)
+	}
+	if got := beatSuggestionBeat(storage.BeatSuggestion{Marker: "BEAT"}); got != "BEAT" {
+		t.Errorf("bare beat = %q", got)
+	}
+}
+
+func TestApplyBeatSuggestions(t *testing.T) {
+	ph := &storage.ProjectHandle{Project: domain.Project{Issues: []domain.Issue{{Pages: []domain.Page{{Number: 1, Panels: []domain.Panel{
+		{ID: "a1", BeatIDs: []string{"b:2"}}, {ID: "a2"},
+	}}}}}}}
+	n, err := applyBeatSuggestions(ph, []storage.BeatSuggestion{
+		{BeatID: "b:3", PageNumber: 1, PanelID: "a1"},
+		{BeatID: "b:4", PageNumber: 1, PanelID: "a2"},
+		{BeatID: "b:5", PageNumber: 1, PanelID: "gone"},
+	})
+	if n != 2 || !errors.Is(err, storage.ErrPanelNotFound) {
+		t.Fatalf("applyBeatSuggestions = %d, %v; want 2 and ErrPanelNotFound", n, err)
+	}
+	pns := ph.Project.Issues[0].Pages[0].Panels
+	if !slices.Equal(pns[0].BeatIDs, []string{"b:2", "b:3"}) || !slices.Equal(pns[1].BeatIDs, []string{"b:4"}) {
+		t.Fatalf("beats = %v, %v", pns[0].BeatIDs, pns[1].BeatIDs)
+	}
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"strings"
+
+	"gocomicwriter/internal/i18n"
+)
+
+// splitAliases parses a comma-separated alias field, dropping blanks and case-insensitive duplicates.
+func splitAliases(s string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ",") {
+		a := strings.TrimSpace(part)
+		if a == "" || seen[strings.ToLower(a)] {
+			continue
+		}
+		seen[strings.ToLower(a)] = true
+		out = append(out, a)
+	}
+	return out
+}
+
+// bibleLabel formats a bible list row: the name followed by the alias count and a notes marker,
+// e.g. "Alice (2 aliases, notes)".
+func bibleLabel(name string, aliases int, notes string) string {
+	var parts []string
+	if aliases > 0 {
+		parts = append(parts, i18n.N("bible.aliases", aliases))
+	}
+	if strings.TrimSpace(notes) != "" {
+		parts = append(parts, i18n.T("bible.notes"))
+	}
+	if len(parts) == 0 {
+		return name
+	}
+	return name + " (" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,134 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+)
+
+// noPortrait is the portrait choice that clears the field.
+const noPortrait = "(none)"
+
+// bibleFields are the editable fields of a character, location or tag entry.
+type bibleFields struct {
+	Name     string
+	Aliases  string // comma-separated, see splitAliases
+	Notes    string
+	Portrait string // asset path relative to the project root
+}
+
+// bibleForm selects the fields shown for an entry kind.
+type bibleForm struct {
+	Kind         string // Character, Location or Tag
+	WithAliases  bool
+	WithPortrait bool
+}
+
+// showBibleEntryDialog edits one bible entry. images are the project's image assets offered as portraits;
+// armed is the asset currently armed in the assets pane (relative path, may be empty).
+func showBibleEntryDialog(w fyne.Window, form bibleForm, cur bibleFields, images []string, armed string, onSave func(bibleFields)) {
+	name := widget.NewEntry()
+	name.SetText(cur.Name)
+	name.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return errors.New("name required")
+		}
+		return nil
+	}
+	items := []*widget.FormItem{widget.NewFormItem(i18n.T("form.name"), name)}
+
+	aliases := widget.NewEntry()
+	aliases.SetText(cur.Aliases)
+	aliases.SetPlaceHolder(i18n.T("placeholder.comma_separated_e_g_al_ali"))
+	if form.WithAliases {
+		items = append(items, widget.NewFormItem(i18n.T("form.aliases"), aliases))
+	}
+
+	notes := widget.NewMultiLineEntry()
+	notes.Wrapping = fyne.TextWrapWord
+	notes.SetMinRowsVisible(6)
+	notes.SetText(cur.Notes)
+	items = append(items, widget.NewFormItem(i18n.T("form.notes"), notes))
+
+	var portrait *widget.Select
+	if form.WithPortrait {
+		opts := append([]string{noPortrait}, images...)
+		for _, p := range []string{cur.Portrait, armed} {
+			if p != "" && !slices.Contains(opts, p) {
+				opts = append(opts, p)
+			}
+		}
+		portrait = widget.NewSelect(opts, nil)
+		if cur.Portrait != "" {
+			portrait.SetSelected(cur.Portrait)
+		} else {
+			portrait.SetSelected(noPortrait)
+		}
+		useArmed := widget.NewButton(i18n.T("button.use_armed_asset"), func() { portrait.SetSelected(armed) })
+		if armed == "" {
+			useArmed.Disable()
+		}
+		items = append(items, widget.NewFormItem(i18n.T("form.portrait"), container.NewBorder(nil, nil, nil, useArmed, portrait)))
+	}
+
+	d := dialog.NewForm(i18n.T("msg.edit", form.Kind), i18n.T("msg.save"), i18n.T("msg.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		f := bibleFields{Name: strings.TrimSpace(name.Text), Notes: strings.TrimRight(notes.Text, " \t\n")}
+		if form.WithAliases {
+			f.Aliases = aliases.Text
+		}
+		if portrait != nil && portrait.Selected != noPortrait {
+			f.Portrait = portrait.Selected
+		}
+		onSave(f)
+	}, w)
+	d.Resize(fyne.NewSize(520, 420))
+	d.Show()
+}
+
+// tapLabel is a list row label that reports taps and double taps, so lists can open an editor on
+// double-click while single clicks still select the row.
+type tapLabel struct {
+	widget.Label
+	onTapped       func()
+	onDoubleTapped func()
+}
+
+func newTapLabel() *tapLabel {
+	t := &tapLabel{}
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+func (t *tapLabel) Tapped(*fyne.PointEvent) {
+	if t.onTapped != nil {
+		t.onTapped()
+	}
+}
+
+func (t *tapLabel) DoubleTapped(*fyne.PointEvent) {
+	if t.onDoubleTapped != nil {
+		t.onDoubleTapped()
+	}
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitAliases(t *testing.T) {
+	got := splitAliases(" Al , Ali,, al ,Lissy ")
+	want := []string{"Al", "Ali", "Lissy"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitAliases = %v, want %v", got, want)
+	}
+	if got := splitAliases("  "); got != nil {
+		t.Fatalf("expected nil for blank input, got %v", got)
+	}
+}
+
+func TestBibleLabel(t *testing.T) {
+	cases := []struct {
+		name    string
+		aliases int
+		notes   string
+		want    string
+	}{
+		{"Alice", 0, "", "Alice"},
+		{"Alice", 1, "", "Alice (1 alias)"},
+		{"Alice", 3, "tall", "Alice (3 aliases, notes)"},
+		{"Docks", 0, " \n", "Docks"},
+		{"noir", 0, "mood", "noir (notes)"},
+	}
+	for _, tc := range cases {
+		if got := bibleLabel(tc.name, tc.aliases, tc.notes); got != tc.want {
+			t.Errorf("bibleLabel(%q, %d, %q) = %q, want %q", tc.name, tc.aliases, tc.notes, got, tc.want)
+		}
+	}
+}
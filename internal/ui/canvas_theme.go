@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"image/color"
+
+	"gocomicwriter/internal/config"
+	"gocomicwriter/internal/vector"
+)
+
+// themeKeys are the message keys of the theme choices in the preferences dialog.
+var (
+	themeValues = []string{config.ThemeSystem, config.ThemeLight, config.ThemeDark}
+	themeKeys   = map[string]string{
+		config.ThemeSystem: "option.theme_system",
+		config.ThemeLight:  "option.theme_light",
+		config.ThemeDark:   "option.theme_dark",
+	}
+)
+
+// themeIsDark reports whether the theme setting shows the dark variant; any setting other than light
+// and dark follows the system.
+func themeIsDark(setting string, systemDark bool) bool {
+	switch setting {
+	case config.ThemeLight:
+		return false
+	case config.ThemeDark:
+		return true
+	}
+	return systemDark
+}
+
+// canvasPalette holds the colors PageCanvas draws everything but the panels with. The page itself is
+// always white like paper; the surround and guides follow the theme and the canvas settings.
+type canvasPalette struct {
+	Dark       bool // drawn for the dark theme; also picks the beat overlay ramp
+	Surround   color.NRGBA
+	PageStroke color.NRGBA
+	Trim       color.NRGBA
+	Bleed      color.NRGBA
+	Gutter     color.NRGBA
+	GutterFill color.NRGBA
+	Spine      color.NRGBA
+	Guide      color.NRGBA // snap guides
+	GuideWidth float32     // stroke width of the trim, bleed, gutter and snap guides
+}
+
+// canvasSurrounds are the surround and page edge colors of the dark and the light theme.
+var canvasSurrounds = map[bool][2]color.NRGBA{
+	true:  {{R: 30, G: 30, B: 34, A: 255}, {R: 20, G: 20, B: 20, A: 255}},
+	false: {{R: 208, G: 208, B: 212, A: 255}, {R: 96, G: 96, B: 96, A: 255}},
+}
+
+// canvasPaletteFor returns the canvas colors for the dark or the light theme. High contrast makes the
+// guides opaque, more saturated and thicker; colors set in cfg replace the defaults of their guide.
+func canvasPaletteFor(cfg config.CanvasConfig, dark bool) canvasPalette {
+	surround := canvasSurrounds[dark != cfg.InvertSurround]
+	p := canvasPalette{
+		Dark:       dark,
+		Surround:   surround[0],
+		PageStroke: surround[1],
+		Trim:       color.NRGBA{R: 200, G: 0, B: 0, A: 200},
+		Bleed:      color.NRGBA{R: 0, G: 120, B: 255, A: 180},
+		Gutter:     color.NRGBA{R: 120, G: 200, B: 0, A: 200},
+		GutterFill: color.NRGBA{R: 120, G: 200, B: 0, A: 40},
+		Spine:      color.NRGBA{R: 120, G: 200, B: 0, A: 220},
+		Guide:      color.NRGBA{R: 255, G: 0, B: 200, A: 230},
+		GuideWidth: 1,
+	}
+	if cfg.HighContrast {
+		p.PageStroke = color.NRGBA{A: 255}
+		if dark != cfg.InvertSurround {
+			p.PageStroke = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+		}
+		p.Trim = color.NRGBA{R: 230, G: 0, B: 0, A: 255}
+		p.Bleed = color.NRGBA{R: 0, G: 90, B: 255, A: 255}
+		p.Gutter = color.NRGBA{R: 0, G: 150, B: 0, A: 255}
+		p.GutterFill = color.NRGBA{R: 0, G: 150, B: 0, A: 60}
+		p.Spine = p.Gutter
+		p.Guide = color.NRGBA{R: 255, G: 0, B: 255, A: 255}
+		p.GuideWidth = 2.5
+	}
+	for _, c := range []struct {
+		hex string
+		dst *color.NRGBA
+	}{{cfg.TrimColor, &p.Trim}, {cfg.BleedColor, &p.Bleed}, {cfg.GuideColor, &p.Guide}} {
+		if c.hex == "" {
+			continue
+		}
+		if v, err := parseHexColor(c.hex); err == nil {
+			*c.dst = color.NRGBA{R: v.R, G: v.G, B: v.B, A: 255}
+		}
+	}
+	return p
+}
+
+// beatOverlayFill is the panel fill of the beat coverage overlay: a red hint for panels without beats
+// and greens that darken with each further beat, up to three. The steps are far enough apart to tell
+// on the white page in both themes; the dark theme's ramp is deeper so it does not glare.
+func beatOverlayFill(beats int, dark bool) vector.Color {
+	ramp := [4]vector.Color{
+		{R: 245, G: 205, B: 205, A: 255},
+		{R: 215, G: 240, B: 210, A: 255},
+		{R: 170, G: 222, B: 165, A: 255},
+		{R: 120, G: 200, B: 115, A: 255},
+	}
+	if dark {
+		ramp = [4]vector.Color{
+			{R: 235, G: 175, B: 175, A: 255},
+			{R: 195, G: 228, B: 188, A: 255},
+			{R: 145, G: 208, B: 138, A: 255},
+			{R: 92, G: 182, B: 88, A: 255},
+		}
+	}
+	return ramp[max(0, min(beats, 3))]
+}
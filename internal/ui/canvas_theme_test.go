@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"image/color"
+	"testing"
+
+	"gocomicwriter/internal/config"
+	"gocomicwriter/internal/vector"
+)
+
+func TestThemeIsDark(t *testing.T) {
+	for _, tc := range []struct {
+		setting    string
+		systemDark bool
+		want       bool
+	}{
+		{config.ThemeSystem, true, true},
+		{config.ThemeSystem, false, false},
+		{"", true, true},
+		{config.ThemeLight, true, false},
+		{config.ThemeDark, false, true},
+	} {
+		if got := themeIsDark(tc.setting, tc.systemDark); got != tc.want {
+			t.Errorf("themeIsDark(%q, %v) = %v", tc.setting, tc.systemDark, got)
+		}
+	}
+}
+
+func TestCanvasPaletteFor(t *testing.T) {
+	dark := canvasPaletteFor(config.CanvasConfig{}, true)
+	light := canvasPaletteFor(config.CanvasConfig{}, false)
+	// The dark default keeps the canvas as it always looked
+	if dark.Surround != (color.NRGBA{R: 30, G: 30, B: 34, A: 255}) || dark.GuideWidth != 1 || !dark.Dark {
+		t.Fatalf("dark palette = %+v", dark)
+	}
+	if luminance(light.Surround) <= luminance(dark.Surround) || light.Dark {
+		t.Fatalf("light surround %v is not lighter than %v", light.Surround, dark.Surround)
+	}
+	if inv := canvasPaletteFor(config.CanvasConfig{InvertSurround: true}, true); inv.Surround != light.Surround || !inv.Dark {
+		t.Fatalf("inverted dark palette = %+v", inv)
+	}
+
+	hc := canvasPaletteFor(config.CanvasConfig{HighContrast: true}, true)
+	if hc.GuideWidth <= dark.GuideWidth {
+		t.Fatalf("high contrast guide width %v", hc.GuideWidth)
+	}
+	for name, c := range map[string]color.NRGBA{"trim": hc.Trim, "bleed": hc.Bleed, "gutter": hc.Gutter, "guide": hc.Guide} {
+		if c.A != 255 {
+			t.Errorf("high contrast %s color %v is not opaque", name, c)
+		}
+	}
+	// The page edge contrasts with the surround
+	if d := luminance(hc.PageStroke) - luminance(hc.Surround); d < 0.5 {
+		t.Errorf("page edge %v too close to the surround %v", hc.PageStroke, hc.Surround)
+	}
+
+	custom := canvasPaletteFor(config.CanvasConfig{TrimColor: "#00ff00", BleedColor: "bad", GuideColor: "ffaa00"}, false)
+	if custom.Trim != (color.NRGBA{G: 255, A: 255}) || custom.Guide != (color.NRGBA{R: 255, G: 170, A: 255}) {
+		t.Fatalf("custom colors not applied: %+v", custom)
+	}
+	if custom.Bleed != light.Bleed {
+		t.Fatalf("unreadable bleed color replaced the default: %v", custom.Bleed)
+	}
+}
+
+func TestBeatOverlayFillReadable(t *testing.T) {
+	for _, dark := range []bool{false, true} {
+		prev := 1.0 // the white page
+		for beats := 1; beats <= 3; beats++ {
+			l := luminance(vectorNRGBA(beatOverlayFill(beats, dark)))
+			if prev-l < 0.05 {
+				t.Errorf("dark=%v: %d beats (luminance %.2f) too close to the step before (%.2f)", dark, beats, l, prev)
+			}
+			prev = l
+		}
+		if beatOverlayFill(7, dark) != beatOverlayFill(3, dark) || beatOverlayFill(-1, dark) != beatOverlayFill(0, dark) {
+			t.Errorf("dark=%v: ramp not clamped", dark)
+		}
+		if none := beatOverlayFill(0, dark); none.R <= none.G {
+			t.Errorf("dark=%v: panels without beats are not reddish: %v", dark, none)
+		}
+	}
+}
+
+func vectorNRGBA(c vector.Color) color.NRGBA { return color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A} }
+
+// luminance is the relative luminance of c from 0 (black) to 1 (white), ignoring alpha.
+func luminance(c color.NRGBA) float64 {
+	return (0.2126*float64(c.R) + 0.7152*float64(c.G) + 0.0722*float64(c.B)) / 255
+}
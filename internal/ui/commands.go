@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import "strings"
+
+// command is an action of the application that the menus and the quick-open palette share.
+type command struct {
+	Group string // menu the command appears in, e.g. "Export"
+	Title string // menu label without a trailing ellipsis, e.g. "Export Issue as CBZ"
+	Run   func()
+	// Enabled reports whether the command can run now; nil means always.
+	Enabled func() bool
+}
+
+// commandRegistry holds the commands in registration order. Each menu registers its items as one
+// group; a menu whose items change (e.g. the export presets) registers its group again.
+type commandRegistry struct {
+	cmds []command
+}
+
+// setGroup replaces the commands of group with cmds, keeping the group's place in the order. The
+// group of each command is set to group.
+func (r *commandRegistry) setGroup(group string, cmds []command) {
+	at := -1
+	kept := r.cmds[:0:0]
+	for _, c := range r.cmds {
+		if c.Group == group {
+			if at < 0 {
+				at = len(kept)
+			}
+			continue
+		}
+		kept = append(kept, c)
+	}
+	if at < 0 {
+		at = len(kept)
+	}
+	add := make([]command, 0, len(cmds))
+	for _, c := range cmds {
+		if c.Run == nil || strings.TrimSpace(c.Title) == "" {
+			continue
+		}
+		c.Group = group
+		add = append(add, c)
+	}
+	r.cmds = append(kept[:at:at], append(add, kept[at:]...)...)
+}
+
+// enabled returns the commands that can run now.
+func (r *commandRegistry) enabled() []command {
+	var out []command
+	for _, c := range r.cmds {
+		if c.Enabled == nil || c.Enabled() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// commandTitle turns a menu label into a command title: "Export Issue as PDF…" → "Export Issue as PDF".
+func commandTitle(label string) string {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(label), "…"))
+}
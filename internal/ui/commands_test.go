@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func commandTitles(cmds []command) []string {
+	var out []string
+	for _, c := range cmds {
+		out = append(out, c.Group+"/"+c.Title)
+	}
+	return out
+}
+
+func TestCommandRegistry_SetGroupKeepsOrder(t *testing.T) {
+	var r commandRegistry
+	noop := func() {}
+	r.setGroup("File", []command{{Title: "Open", Run: noop}, {Title: "Save", Run: noop}})
+	r.setGroup("Export", []command{{Title: "Export Issue as PDF", Run: noop}, {Title: "Separator"}})
+	r.setGroup("Help", []command{{Title: "About", Run: noop}})
+	// A changed group is replaced in place
+	r.setGroup("Export", []command{{Title: "Export Issue as CBZ", Run: noop}, {Group: "Other", Title: "Web preset", Run: noop}})
+	want := []string{"File/Open", "File/Save", "Export/Export Issue as CBZ", "Export/Web preset", "Help/About"}
+	if got := commandTitles(r.cmds); !reflect.DeepEqual(got, want) {
+		t.Fatalf("commands = %v, want %v", got, want)
+	}
+
+	saving := false
+	r.setGroup("File", []command{{Title: "Open", Run: noop}, {Title: "Save", Run: noop, Enabled: func() bool { return saving }}})
+	if got := commandTitles(r.enabled()); len(got) != 4 || got[1] != "Export/Export Issue as CBZ" {
+		t.Errorf("enabled = %v", got)
+	}
+	saving = true
+	if got := len(r.enabled()); got != 5 {
+		t.Errorf("enabled after enabling Save = %d commands", got)
+	}
+}
+
+func TestCommandTitle(t *testing.T) {
+	for in, want := range map[string]string{
+		"Export Issue as PDF…": "Export Issue as PDF",
+		" Save ":               "Save",
+		"Search…":              "Search",
+	} {
+		if got := commandTitle(in); got != want {
+			t.Errorf("commandTitle(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"gocomicwriter/internal/backend"
+)
+
+// pageCommentPrefix is the path prefix of all review comments on a page. The trailing slash keeps
+// page 3 from matching page 30.
+func pageCommentPrefix(pageNumber int) string {
+	return fmt.Sprintf("issue:1/page:%d/", pageNumber)
+}
+
+// panelCommentPath is the index document path of a panel, which review comments on it are anchored to.
+func panelCommentPath(pageNumber int, panelID string) string {
+	return pageCommentPrefix(pageNumber) + "panel:" + panelID
+}
+
+// openCommentCounts counts unresolved comments per panel path. Comments on a panel's balloons,
+// captions or SFX count towards the panel.
+func openCommentCounts(list []backend.Comment) map[string]int {
+	counts := map[string]int{}
+	for _, c := range list {
+		if c.Resolved() {
+			continue
+		}
+		i := strings.Index(c.Path, "/panel:")
+		if i < 0 {
+			continue
+		}
+		p := c.Path
+		if j := strings.IndexByte(p[i+1:], '/'); j >= 0 {
+			p = p[:i+1+j]
+		}
+		counts[p]++
+	}
+	return counts
+}
+
+// commentHeader is the author and time line shown above a comment in the thread dialog.
+func commentHeader(c backend.Comment) string {
+	s := c.Author + " — " + c.CreatedAt.Local().Format("2006-01-02 15:04")
+	if c.Resolved() {
+		s += " (resolved)"
+	}
+	return s
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/backend"
+)
+
+func TestPanelCommentPath(t *testing.T) {
+	if got := panelCommentPath(3, "p2"); got != "issue:1/page:3/panel:p2" {
+		t.Fatalf("path = %q", got)
+	}
+	if strings.HasPrefix(panelCommentPath(30, "p1"), pageCommentPrefix(3)) {
+		t.Fatalf("page 3 prefix must not match page 30")
+	}
+}
+
+func TestOpenCommentCounts(t *testing.T) {
+	now := time.Now()
+	list := []backend.Comment{
+		{Path: "issue:1/page:3/panel:p1"},
+		{Path: "issue:1/page:3/panel:p1/balloon:balloon-4"},
+		{Path: "issue:1/page:3/panel:p2"},
+		{Path: "issue:1/page:3/panel:p2", ResolvedAt: &now},
+		{Path: "project:issue_title"},
+	}
+	want := map[string]int{"issue:1/page:3/panel:p1": 2, "issue:1/page:3/panel:p2": 1}
+	if got := openCommentCounts(list); !reflect.DeepEqual(got, want) {
+		t.Fatalf("counts = %v, want %v", got, want)
+	}
+}
+
+func TestCommentHeader(t *testing.T) {
+	c := backend.Comment{Author: "ed@example.com", CreatedAt: time.Date(2025, 1, 2, 3, 4, 0, 0, time.Local)}
+	if got := commentHeader(c); got != "ed@example.com — 2025-01-02 03:04" {
+		t.Fatalf("header = %q", got)
+	}
+	c.ResolvedAt = &c.CreatedAt
+	if !strings.HasSuffix(commentHeader(c), "(resolved)") {
+		t.Fatalf("resolved comment not marked: %q", commentHeader(c))
+	}
+}
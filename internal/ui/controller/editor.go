@@ -0,0 +1,387 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+// Package controller holds the editor state of the desktop UI and the edits made through it, free of
+// any widget code so it builds and tests without the fyne build tag. The Fyne layer keeps one
+// EditorState, calls its methods from widget callbacks and refreshes the views afterwards.
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/undo"
+)
+
+// ErrNoProject is returned by edits made while no project is open.
+var ErrNoProject = errors.New("no project open")
+
+// searchLimit caps the results of one omnibox search.
+const searchLimit = 200
+
+// EditorState is the open project and what is selected in it. Undo snapshots hold the whole current
+// issue and are kept under page 0 of History.
+type EditorState struct {
+	Handle   *storage.ProjectHandle
+	IssueIdx int
+	PageIdx  int
+	History  *undo.Manager
+	// Selected is the panel or balloon selected on the canvas and in the inspector, Panels the panels
+	// selected in the panel list.
+	Selected Selection
+	Panels   PanelSelection
+	// Recorded, when set, is called with every snapshot recorded in History, e.g. to persist it with
+	// PersistUndo in the background.
+	Recorded func(undo.Snapshot)
+}
+
+// undoDepth is the number of undo snapshots kept in memory and in the index.
+const undoDepth = 20
+
+// NewEditorState returns a state without a project and with the editor's undo limits.
+func NewEditorState() *EditorState {
+	return &EditorState{History: undo.NewManager(undo.Config{
+		MaxBytes:    32 * 1024 * 1024, // 32 MiB in-memory cap
+		MaxPerPage:  undoDepth,        // keep up to 20 snapshots per page
+		MinInterval: 300 * time.Millisecond,
+	})}
+}
+
+// Issue returns the current issue, or nil when no project or issue is open.
+func (e *EditorState) Issue() *domain.Issue {
+	if e.Handle == nil || e.IssueIdx < 0 || e.IssueIdx >= len(e.Handle.Project.Issues) {
+		return nil
+	}
+	return &e.Handle.Project.Issues[e.IssueIdx]
+}
+
+// Page returns the current page, or nil when there is none.
+func (e *EditorState) Page() *domain.Page {
+	iss := e.Issue()
+	if iss == nil || e.PageIdx < 0 || e.PageIdx >= len(iss.Pages) {
+		return nil
+	}
+	return &iss.Pages[e.PageIdx]
+}
+
+// PageNumber returns the number of the current page, or 0 when there is none.
+func (e *EditorState) PageNumber() int {
+	if pg := e.Page(); pg != nil {
+		return pg.Number
+	}
+	return 0
+}
+
+// SelectPage makes the page with the given number current and reports whether the issue has it.
+func (e *EditorState) SelectPage(number int) bool {
+	iss := e.Issue()
+	if iss == nil {
+		return false
+	}
+	for i, pg := range iss.Pages {
+		if pg.Number == number {
+			e.PageIdx = i
+			return true
+		}
+	}
+	return false
+}
+
+// CaptureSnapshot encodes the current issue for the undo history, together with the current page number.
+func (e *EditorState) CaptureSnapshot() ([]byte, int, error) {
+	if e.Handle == nil || len(e.Handle.Project.Issues) == 0 {
+		return nil, 0, errors.New("no project/issue open")
+	}
+	blob, err := json.Marshal(e.Handle.Project.Issues[e.IssueIdx])
+	if err != nil {
+		return nil, 0, err
+	}
+	return blob, e.PageNumber(), nil
+}
+
+// PushUndo records the current issue in the undo history under label, the change about to be made
+// (e.g. "Delete Page 4"), and returns the snapshot so the caller can also persist it; ok is false when
+// nothing could be captured.
+func (e *EditorState) PushUndo(label string) (s undo.Snapshot, ok bool) {
+	blob, _, err := e.CaptureSnapshot()
+	if err != nil {
+		return undo.Snapshot{}, false
+	}
+	return e.RecordUndo(blob, label), true
+}
+
+// RecordUndo records a blob from CaptureSnapshot taken before a change in the undo history under
+// label and returns the snapshot.
+func (e *EditorState) RecordUndo(blob []byte, label string) undo.Snapshot {
+	s := undo.Snapshot{PageNumber: 0, Blob: blob, TS: time.Now(), Label: label}
+	e.History.PushSnapshot(s)
+	if e.Recorded != nil {
+		e.Recorded(s)
+	}
+	return s
+}
+
+// PersistUndo stores an undo snapshot in the index of ph, so the history survives a restart, and drops
+// persisted snapshots beyond the in-memory depth. It takes the handle rather than reading the editor
+// state so it can run in the background.
+func PersistUndo(ctx context.Context, ph *storage.ProjectHandle, s undo.Snapshot) error {
+	if ph == nil {
+		return ErrNoProject
+	}
+	if err := storage.SaveSnapshot(ctx, ph, s.PageNumber, s.Blob, s.TS, s.Label); err != nil {
+		return err
+	}
+	_, err := storage.PruneOldSnapshots(ctx, ph, s.PageNumber, undoDepth)
+	return err
+}
+
+// TrimPersistedUndo deletes the persisted snapshots newer than the last entry of hist, the undo
+// history after an undo or a revert, so a restart does not bring back what was taken back.
+func TrimPersistedUndo(ctx context.Context, ph *storage.ProjectHandle, hist []undo.Entry) error {
+	if ph == nil {
+		return ErrNoProject
+	}
+	var last time.Time
+	if len(hist) > 0 {
+		last = hist[len(hist)-1].TS
+	}
+	_, err := storage.DeleteSnapshotsAfter(ctx, ph, 0, last)
+	return err
+}
+
+// RestoreHistory replaces the undo history with the snapshots persisted by PersistUndo, e.g. after
+// the project was opened. It returns how many were restored.
+func (e *EditorState) RestoreHistory(ctx context.Context) (int, error) {
+	if e.Handle == nil {
+		return 0, ErrNoProject
+	}
+	list, err := storage.ListSnapshots(ctx, e.Handle, 0, undoDepth)
+	if err != nil {
+		return 0, err
+	}
+	snaps := make([]undo.Snapshot, len(list))
+	for i, s := range list {
+		// Listed newest first; the history is oldest first
+		snaps[len(list)-1-i] = undo.Snapshot{Blob: s.Blob, TS: s.TS, Label: s.Label}
+	}
+	e.History.Restore(0, snaps)
+	return len(snaps), nil
+}
+
+// UndoHistory returns the undo history, oldest first; the last entry is undone first.
+func (e *EditorState) UndoHistory() []undo.Entry {
+	return e.History.History(0)
+}
+
+// RevertTo undoes every change from the history entry at index on in one step by applying its
+// snapshot; the redo history is dropped. ok is false when there is no such entry.
+func (e *EditorState) RevertTo(index int) (ok bool, err error) {
+	if e.Handle == nil {
+		return false, ErrNoProject
+	}
+	s, ok := e.History.RevertTo(0, index)
+	if !ok {
+		return false, nil
+	}
+	return true, e.ApplySnapshot(s.Blob)
+}
+
+// ApplySnapshot replaces the current issue with a captured one and saves the project.
+func (e *EditorState) ApplySnapshot(blob []byte) error {
+	if e.Handle == nil {
+		return ErrNoProject
+	}
+	var iss domain.Issue
+	if err := json.Unmarshal(blob, &iss); err != nil {
+		return err
+	}
+	if e.IssueIdx < 0 {
+		e.IssueIdx = 0
+	}
+	if e.IssueIdx >= len(e.Handle.Project.Issues) {
+		e.Handle.Project.Issues = append(e.Handle.Project.Issues, iss)
+	} else {
+		e.Handle.Project.Issues[e.IssueIdx] = iss
+	}
+	return storage.Save(e.Handle)
+}
+
+// Undo applies the last undo snapshot; ok is false when there is nothing to undo.
+func (e *EditorState) Undo() (ok bool, err error) {
+	if e.Handle == nil {
+		return false, ErrNoProject
+	}
+	s, ok := e.History.Undo(0)
+	if !ok {
+		return false, nil
+	}
+	return true, e.ApplySnapshot(s.Blob)
+}
+
+// Redo applies the last undone snapshot again; ok is false when there is nothing to redo.
+func (e *EditorState) Redo() (ok bool, err error) {
+	if e.Handle == nil {
+		return false, ErrNoProject
+	}
+	s, ok := e.History.Redo(0)
+	if !ok {
+		return false, nil
+	}
+	return true, e.ApplySnapshot(s.Blob)
+}
+
+// AddPanel appends an empty panel to the current page, creating page 1 first when the issue has no
+// pages, and saves the project.
+func (e *EditorState) AddPanel() (domain.Panel, error) {
+	if e.Handle == nil {
+		return domain.Panel{}, ErrNoProject
+	}
+	pageNum := e.PageNumber()
+	if pageNum == 0 {
+		if iss := e.Issue(); iss != nil && len(iss.Pages) > 0 {
+			pageNum = iss.Pages[0].Number
+		} else {
+			_, _ = storage.EnsurePage(e.Handle, 1)
+			pageNum = 1
+		}
+	}
+	pn, err := storage.AddPanel(e.Handle, pageNum, domain.Panel{})
+	if err != nil {
+		return domain.Panel{}, err
+	}
+	return pn, storage.Save(e.Handle)
+}
+
+// DrawPanel adds a panel with the given geometry to the page with the given number, e.g. one drawn on
+// the canvas, after recording an undo snapshot, and saves the project.
+func (e *EditorState) DrawPanel(pageNumber int, geom domain.Rect) (domain.Panel, error) {
+	if e.Handle == nil {
+		return domain.Panel{}, ErrNoProject
+	}
+	if geom.Width <= 0 || geom.Height <= 0 {
+		return domain.Panel{}, errors.New("panel needs a positive width and height")
+	}
+	e.PushUndo(i18n.T("undo.draw_panel", pageNumber))
+	pn, err := storage.AddPanel(e.Handle, pageNumber, domain.Panel{Geometry: geom})
+	if err != nil {
+		return domain.Panel{}, err
+	}
+	return pn, storage.Save(e.Handle)
+}
+
+// PageDeletion describes a deleted page.
+type PageDeletion struct {
+	Number int
+	// Snapshot is the undo snapshot taken before the deletion; HasSnapshot is false if none was taken.
+	Snapshot    undo.Snapshot
+	HasSnapshot bool
+	// SpreadWarnings lists the spreads split by renumbering.
+	SpreadWarnings []storage.SpreadWarning
+}
+
+// DeletePage removes the current page after recording an undo snapshot, renumbers the remaining
+// pages from 1 without gaps and saves the project. The page at the same position becomes current, or
+// the new last page when the last one was deleted.
+func (e *EditorState) DeletePage() (PageDeletion, error) {
+	if e.Handle == nil {
+		return PageDeletion{}, ErrNoProject
+	}
+	iss, pg := e.Issue(), e.Page()
+	if pg == nil {
+		return PageDeletion{}, errors.New("no current page")
+	}
+	d := PageDeletion{Number: pg.Number}
+	d.Snapshot, d.HasSnapshot = e.PushUndo(i18n.T("undo.delete_page", pg.Number))
+	iss.Pages = append(iss.Pages[:e.PageIdx], iss.Pages[e.PageIdx+1:]...)
+	d.SpreadWarnings = storage.RenumberPages(iss)
+	e.PageIdx = max(0, min(e.PageIdx, len(iss.Pages)-1))
+	return d, storage.Save(e.Handle)
+}
+
+// ApplyRemoteOps applies ops pulled from the server to the open project and saves it when any was
+// applied. The current page stays current by number, or the first page becomes current when it was
+// deleted. Ops that could not be applied are returned as skipped; see storage.ApplyRemoteOps.
+func (e *EditorState) ApplyRemoteOps(ops []storage.RemoteSyncOp) (applied int, skipped []error, err error) {
+	if e.Handle == nil {
+		return 0, nil, ErrNoProject
+	}
+	num := e.PageNumber()
+	applied, skipped = storage.ApplyRemoteOps(e.Handle, ops)
+	if applied == 0 {
+		return 0, skipped, nil
+	}
+	if !e.SelectPage(num) {
+		e.PageIdx = 0
+	}
+	return applied, skipped, storage.Save(e.Handle)
+}
+
+// ResultLocation returns the page number and panel ID in a search result's index path
+// ("issue:1/page:3/panel:p2/…"); missing parts are 0 and "".
+func ResultLocation(path string) (page int, panel string) {
+	for _, p := range strings.Split(path, "/") {
+		if v, ok := strings.CutPrefix(p, "page:"); ok {
+			if n, err := strconv.Atoi(v); err == nil {
+				page = n
+			}
+		} else if v, ok := strings.CutPrefix(p, "panel:"); ok {
+			panel = v
+		}
+	}
+	return page, panel
+}
+
+// Navigate makes the page of a search result current and returns the panel to highlight ("" for the
+// page itself). Results outside pages go to the first page; ok is false when there is no page to show.
+func (e *EditorState) Navigate(r storage.SearchResult) (panel string, ok bool) {
+	iss := e.Issue()
+	if iss == nil || len(iss.Pages) == 0 {
+		return "", false
+	}
+	page, panel := ResultLocation(r.Path)
+	if r.PageID > 0 && page == 0 {
+		page = r.PageID
+	}
+	if page == 0 {
+		e.PageIdx = 0
+		return panel, true
+	}
+	if !e.SelectPage(page) {
+		return "", false
+	}
+	return panel, true
+}
+
+// RunSearch runs an omnibox query against the project index. type: filters in the text restrict the
+// document types (see storage.ParseTypeFilters). A blank query has no results. It reads only the
+// project handle, so a copy of the state may run it off the UI goroutine.
+func (e *EditorState) RunSearch(ctx context.Context, text string) ([]storage.SearchResult, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	if e.Handle == nil {
+		return nil, ErrNoProject
+	}
+	text, types, err := storage.ParseTypeFilters(text)
+	if err != nil {
+		return nil, err
+	}
+	return e.Handle.Index().Search(ctx, storage.SearchQuery{
+		Text: text, Types: types, Limit: searchLimit, MarkStart: storage.SnippetMarkStart, MarkEnd: storage.SnippetMarkEnd,
+	})
+}
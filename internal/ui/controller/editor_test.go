@@ -0,0 +1,365 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/undo"
+)
+
+// newEditor opens a project with pages 1..n, page i holding one panel "p<i>" noted "note <i>".
+func newEditor(t *testing.T, n int) *EditorState {
+	t.Helper()
+	iss := domain.Issue{TrimWidth: 600, TrimHeight: 900}
+	for i := 1; i <= n; i++ {
+		iss.Pages = append(iss.Pages, domain.Page{Number: i, Panels: []domain.Panel{{
+			ID:    "p" + string(rune('0'+i)),
+			Notes: "note " + string(rune('0'+i)),
+		}}})
+	}
+	ph, err := storage.InitProject(t.TempDir(), domain.Project{Name: "Controller", Issues: []domain.Issue{iss}})
+	if err != nil {
+		t.Fatalf("InitProject: %v", err)
+	}
+	ed := NewEditorState()
+	ed.Handle = ph
+	t.Cleanup(func() {
+		// Saves update the index in the background; let them finish before the directory goes away
+		time.Sleep(200 * time.Millisecond)
+		_ = ph.Close()
+	})
+	return ed
+}
+
+func pageNumbers(iss *domain.Issue) []int {
+	var out []int
+	for _, pg := range iss.Pages {
+		out = append(out, pg.Number)
+	}
+	return out
+}
+
+func TestDeletePageRenumbersAndKeepsSelection(t *testing.T) {
+	ed := newEditor(t, 4)
+	ed.PageIdx = 1 // page 2
+	d, err := ed.DeletePage()
+	if err != nil {
+		t.Fatalf("DeletePage: %v", err)
+	}
+	if d.Number != 2 || !d.HasSnapshot {
+		t.Fatalf("deletion = %+v, want page 2 with snapshot", d)
+	}
+	if got := pageNumbers(ed.Issue()); len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Fatalf("pages after delete = %v, want [1 2 3]", got)
+	}
+	// The former page 3 moved into the deleted page's position and is current
+	if pg := ed.Page(); pg == nil || pg.Number != 2 || pg.Panels[0].ID != "p3" {
+		t.Fatalf("current page = %+v, want renumbered page 2 with panel p3", pg)
+	}
+	// Deleting the last page selects the new last page
+	ed.PageIdx = 2
+	if _, err := ed.DeletePage(); err != nil {
+		t.Fatalf("DeletePage last: %v", err)
+	}
+	if ed.PageIdx != 1 || ed.PageNumber() != 2 {
+		t.Fatalf("after deleting last page: idx %d page %d, want idx 1 page 2", ed.PageIdx, ed.PageNumber())
+	}
+	// The deletion is saved
+	reopened, err := storage.Open(ed.Handle.Root)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if got := pageNumbers(&reopened.Project.Issues[0]); len(got) != 2 {
+		t.Fatalf("saved pages = %v, want 2 pages", got)
+	}
+}
+
+func TestDeletePageWithoutProjectOrPage(t *testing.T) {
+	if _, err := NewEditorState().DeletePage(); !errors.Is(err, ErrNoProject) {
+		t.Fatalf("err = %v, want ErrNoProject", err)
+	}
+	ed := newEditor(t, 0)
+	if _, err := ed.DeletePage(); err == nil {
+		t.Fatal("expected an error for an issue without pages")
+	}
+}
+
+func TestUndoRestoresDeletedPage(t *testing.T) {
+	ed := newEditor(t, 3)
+	ed.PageIdx = 0
+	if _, err := ed.DeletePage(); err != nil {
+		t.Fatalf("DeletePage: %v", err)
+	}
+	ok, err := ed.Undo()
+	if err != nil || !ok {
+		t.Fatalf("Undo = %v, %v", ok, err)
+	}
+	if got := pageNumbers(ed.Issue()); len(got) != 3 || ed.Issue().Pages[0].Panels[0].ID != "p1" {
+		t.Fatalf("pages after undo = %v, want page 1 restored", got)
+	}
+	if ok, err := ed.Undo(); ok || err != nil {
+		t.Fatalf("second Undo = %v, %v; want nothing to undo", ok, err)
+	}
+	if ok, err := ed.Redo(); !ok || err != nil {
+		t.Fatalf("Redo = %v, %v", ok, err)
+	}
+	if _, err := NewEditorState().Undo(); !errors.Is(err, ErrNoProject) {
+		t.Fatalf("Undo without project: %v", err)
+	}
+}
+
+func TestAddPanel(t *testing.T) {
+	ed := newEditor(t, 2)
+	ed.PageIdx = 1
+	pn, err := ed.AddPanel()
+	if err != nil {
+		t.Fatalf("AddPanel: %v", err)
+	}
+	if pg := ed.Page(); len(pg.Panels) != 2 || pg.Panels[1].ID != pn.ID {
+		t.Fatalf("page 2 panels = %+v, want the new panel %q appended", pg.Panels, pn.ID)
+	}
+	// Without pages, page 1 is created
+	empty := newEditor(t, 0)
+	if _, err := empty.AddPanel(); err != nil {
+		t.Fatalf("AddPanel on empty issue: %v", err)
+	}
+	if got := pageNumbers(empty.Issue()); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("pages = %v, want [1]", got)
+	}
+}
+
+func TestDrawPanel(t *testing.T) {
+	ed := newEditor(t, 2)
+	geom := domain.Rect{X: 20, Y: 30, Width: 200, Height: 150}
+	pn, err := ed.DrawPanel(2, geom)
+	if err != nil {
+		t.Fatalf("DrawPanel: %v", err)
+	}
+	pg := &ed.Issue().Pages[1]
+	if len(pg.Panels) != 2 || pg.Panels[1].ID != pn.ID || pg.Panels[1].Geometry != geom {
+		t.Fatalf("page 2 panels = %+v, want the drawn panel with %+v", pg.Panels, geom)
+	}
+	// Drawing is undoable
+	if ok, err := ed.Undo(); !ok || err != nil {
+		t.Fatalf("Undo = %v, %v", ok, err)
+	}
+	if n := len(ed.Issue().Pages[1].Panels); n != 1 {
+		t.Fatalf("panels after undo = %d, want 1", n)
+	}
+	if _, err := ed.DrawPanel(1, domain.Rect{Width: 0, Height: 10}); err == nil {
+		t.Fatal("expected an error for an empty rect")
+	}
+}
+
+func TestResultLocation(t *testing.T) {
+	cases := []struct {
+		path  string
+		page  int
+		panel string
+	}{
+		{"issue:1/page:3/panel:p2/balloon:b1", 3, "p2"},
+		{"issue:1/page:7/panel:p1", 7, "p1"},
+		{"script:script.txt", 0, ""},
+		{"issue:1/page:x/panel:", 0, ""},
+	}
+	for _, c := range cases {
+		if page, panel := ResultLocation(c.path); page != c.page || panel != c.panel {
+			t.Errorf("ResultLocation(%q) = %d, %q; want %d, %q", c.path, page, panel, c.page, c.panel)
+		}
+	}
+}
+
+func TestNavigate(t *testing.T) {
+	ed := newEditor(t, 3)
+	panel, ok := ed.Navigate(storage.SearchResult{Path: "issue:1/page:3/panel:p3"})
+	if !ok || panel != "p3" || ed.PageNumber() != 3 {
+		t.Fatalf("Navigate = %q, %v at page %d; want p3 on page 3", panel, ok, ed.PageNumber())
+	}
+	// The page ID stands in for a path without a page
+	if _, ok := ed.Navigate(storage.SearchResult{Path: "character:bob", PageID: 2}); !ok || ed.PageNumber() != 2 {
+		t.Fatalf("Navigate by page ID: ok %v page %d", ok, ed.PageNumber())
+	}
+	// Hits outside pages go to the first page
+	if panel, ok := ed.Navigate(storage.SearchResult{Path: "script:script.txt"}); !ok || panel != "" || ed.PageIdx != 0 {
+		t.Fatalf("Navigate script hit = %q, %v at idx %d", panel, ok, ed.PageIdx)
+	}
+	// A page that no longer exists keeps the selection
+	ed.PageIdx = 1
+	if _, ok := ed.Navigate(storage.SearchResult{Path: "issue:1/page:9/panel:p1"}); ok || ed.PageIdx != 1 {
+		t.Fatalf("Navigate to missing page: ok %v idx %d", ok, ed.PageIdx)
+	}
+}
+
+func TestRunSearchNavigates(t *testing.T) {
+	ed := newEditor(t, 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// The project's initial index is built in the background and may lock the table meanwhile
+	var hit *storage.SearchResult
+	var err error
+	for hit == nil && ctx.Err() == nil {
+		var res []storage.SearchResult
+		res, err = ed.RunSearch(ctx, "  note  ")
+		for i := range res {
+			if res[i].Path == "issue:1/page:2/panel:p2" {
+				hit = &res[i]
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if hit == nil {
+		t.Fatalf("no hit for panel p2 (last error: %v)", err)
+	}
+	if panel, ok := ed.Navigate(*hit); !ok || panel != "p2" || ed.PageNumber() != 2 {
+		t.Fatalf("Navigate(hit) = %q, %v at page %d", panel, ok, ed.PageNumber())
+	}
+	if res, err := ed.RunSearch(ctx, " "); res != nil || err != nil {
+		t.Fatalf("blank query = %v, %v; want no results", res, err)
+	}
+	if _, err := NewEditorState().RunSearch(ctx, "note"); !errors.Is(err, ErrNoProject) {
+		t.Fatalf("RunSearch without project: %v", err)
+	}
+
+	// A page notes hit selects its page without a panel to highlight
+	ed.Handle.Project.Issues[0].Pages[2].Notes = "note for the colorist"
+	if err := storage.RebuildIndex(ctx, ed.Handle.Root, ed.Handle.Project); err != nil {
+		t.Fatalf("RebuildIndex: %v", err)
+	}
+	res, err := ed.RunSearch(ctx, "type:page_notes note")
+	if err != nil || len(res) != 1 || res[0].Path != "issue:1/page:3" {
+		t.Fatalf("page notes search = %+v, %v", res, err)
+	}
+	if panel, ok := ed.Navigate(res[0]); !ok || panel != "" || ed.PageNumber() != 3 {
+		t.Fatalf("Navigate(page notes) = %q, %v at page %d", panel, ok, ed.PageNumber())
+	}
+	if res, err := ed.RunSearch(ctx, "type:panel_notes note"); err != nil || len(res) != 3 {
+		t.Fatalf("panel notes search = %+v, %v", res, err)
+	}
+	if _, err := ed.RunSearch(ctx, "type:pages note"); !errors.Is(err, storage.ErrInvalidQuery) {
+		t.Fatalf("unknown type: %v", err)
+	}
+}
+
+func TestApplyRemoteOpsKeepsCurrentPage(t *testing.T) {
+	ed := newEditor(t, 3)
+	ed.PageIdx = 2 // page 3
+	del := storage.RemoteSyncOp{SyncOp: storage.SyncOp{
+		OpID: "r-1", OpType: storage.SyncOpDelete, EntityType: storage.SyncEntityPage, EntityID: "issue:1/page:2",
+		Payload: []byte(`{"number":2,"parent":{"issue":1}}`),
+	}, Version: 1}
+	unknown := storage.RemoteSyncOp{SyncOp: storage.SyncOp{
+		OpID: "r-2", OpType: storage.SyncOpUpdate, EntityType: "script", EntityID: "main", Payload: []byte(`{}`),
+	}, Version: 2}
+	applied, skipped, err := ed.ApplyRemoteOps([]storage.RemoteSyncOp{del, unknown})
+	if err != nil || applied != 1 || len(skipped) != 1 {
+		t.Fatalf("applied %d, skipped %v, err %v", applied, skipped, err)
+	}
+	if got := pageNumbers(ed.Issue()); len(got) != 2 || ed.PageNumber() != 3 {
+		t.Fatalf("pages %v, current %d", got, ed.PageNumber())
+	}
+	// Deleting the current page selects the first one
+	del.OpID, del.EntityID, del.Payload = "r-3", "issue:1/page:3", []byte(`{"number":3,"parent":{"issue":1}}`)
+	if _, _, err := ed.ApplyRemoteOps([]storage.RemoteSyncOp{del}); err != nil || ed.PageIdx != 0 || ed.PageNumber() != 1 {
+		t.Fatalf("current page %d after deleting it, err %v", ed.PageNumber(), err)
+	}
+	if _, _, err := NewEditorState().ApplyRemoteOps(nil); !errors.Is(err, ErrNoProject) {
+		t.Fatalf("expected ErrNoProject, got %v", err)
+	}
+}
+
+func TestUndoHistoryAndRevertTo(t *testing.T) {
+	ed := newEditor(t, 4)
+	ed.History = undo.NewManager(undo.Config{MaxPerPage: undoDepth, MinInterval: time.Nanosecond})
+	if _, err := ed.DrawPanel(1, domain.Rect{Width: 10, Height: 10}); err != nil {
+		t.Fatal(err)
+	}
+	ed.PageIdx = 3
+	if _, err := ed.DeletePage(); err != nil {
+		t.Fatal(err)
+	}
+	ed.PageIdx = 0
+	if _, err := ed.DeletePage(); err != nil {
+		t.Fatal(err)
+	}
+	hist := ed.UndoHistory()
+	if len(hist) != 3 || hist[0].Label != "Draw panel on page 1" || hist[1].Label != "Delete Page 4" || hist[2].Label != "Delete Page 1" {
+		t.Fatalf("history = %+v", hist)
+	}
+	// Revert to before the first deletion: both deletions are undone, the drawn panel stays
+	ok, err := ed.RevertTo(1)
+	if !ok || err != nil {
+		t.Fatalf("RevertTo = %v, %v", ok, err)
+	}
+	if got := pageNumbers(ed.Issue()); len(got) != 4 || len(ed.Issue().Pages[0].Panels) != 2 {
+		t.Fatalf("pages after revert = %v, page 1 panels %d", got, len(ed.Issue().Pages[0].Panels))
+	}
+	if n := len(ed.UndoHistory()); n != 1 {
+		t.Fatalf("history after revert = %d entries, want 1", n)
+	}
+	if ok, _ := ed.Redo(); ok {
+		t.Fatal("redo after revert, want none")
+	}
+	if ok, err := ed.RevertTo(5); ok || err != nil {
+		t.Fatalf("RevertTo out of range = %v, %v", ok, err)
+	}
+	if _, err := NewEditorState().RevertTo(0); !errors.Is(err, ErrNoProject) {
+		t.Fatalf("RevertTo without project: %v", err)
+	}
+}
+
+func TestPersistAndRestoreHistory(t *testing.T) {
+	ed := newEditor(t, 2)
+	ctx := context.Background()
+	t0 := time.Now().Add(-time.Minute)
+	deadline := time.Now().Add(5 * time.Second)
+	for i, label := range []string{"Make spread", "Split spread", "Apply template"} {
+		s := undo.Snapshot{Blob: []byte(`{"pages":[]}`), TS: t0.Add(time.Duration(i) * time.Second), Label: label}
+		// The project's initial index is built in the background and may lock the table meanwhile
+		err := PersistUndo(ctx, ed.Handle, s)
+		for err != nil && time.Now().Before(deadline) {
+			time.Sleep(50 * time.Millisecond)
+			err = PersistUndo(ctx, ed.Handle, s)
+		}
+		if err != nil {
+			t.Fatalf("PersistUndo: %v", err)
+		}
+	}
+	// A new session starts with an empty history and restores the persisted one
+	next := NewEditorState()
+	next.Handle = ed.Handle
+	n, err := next.RestoreHistory(ctx)
+	if err != nil || n != 3 {
+		t.Fatalf("RestoreHistory = %d, %v", n, err)
+	}
+	hist := next.UndoHistory()
+	if hist[0].Label != "Make spread" || hist[2].Label != "Apply template" {
+		t.Fatalf("restored history = %+v", hist)
+	}
+	// After a revert only the retained snapshots stay persisted
+	next.History.RevertTo(0, 1)
+	if err := TrimPersistedUndo(ctx, next.Handle, next.UndoHistory()); err != nil {
+		t.Fatalf("TrimPersistedUndo: %v", err)
+	}
+	again := NewEditorState()
+	again.Handle = ed.Handle
+	if n, _ := again.RestoreHistory(ctx); n != 1 {
+		t.Fatalf("restored after trim = %d, want 1", n)
+	}
+	if _, err := NewEditorState().RestoreHistory(ctx); !errors.Is(err, ErrNoProject) {
+		t.Fatalf("RestoreHistory without project: %v", err)
+	}
+}
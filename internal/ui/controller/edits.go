@@ -0,0 +1,447 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package controller
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// ErrNoPanelsSelected is returned by bulk panel edits without panels to act on.
+var ErrNoPanelsSelected = errors.New("no panels selected")
+
+// errNoPage is returned by edits of the current page while the issue has none.
+var errNoPage = errors.New("no current page")
+
+// Change makes an undoable edit. change modifies the open project and returns the undo label of what
+// it did, or "" when it changed nothing. The issue as it was before is then recorded in the undo
+// history under that label and the project saved. An error of change is returned after that, so a
+// change that partly succeeded is saved and can be undone.
+func (e *EditorState) Change(change func() (label string, err error)) error {
+	if e.Handle == nil {
+		return ErrNoProject
+	}
+	blob, _, snapErr := e.CaptureSnapshot()
+	label, err := change()
+	if label == "" {
+		return err
+	}
+	if snapErr == nil {
+		e.RecordUndo(blob, label)
+	}
+	if serr := storage.Save(e.Handle); serr != nil && err == nil {
+		err = serr
+	}
+	return err
+}
+
+// currentPageNumber is the number of the page edits of the current page apply to.
+func (e *EditorState) currentPageNumber() (int, error) {
+	if e.Handle == nil {
+		return 0, ErrNoProject
+	}
+	n := e.PageNumber()
+	if n == 0 {
+		return 0, errNoPage
+	}
+	return n, nil
+}
+
+// NextPageNumber returns the number after the highest page of the first issue, the issue pages are
+// added to; 1 when there is none.
+func (e *EditorState) NextPageNumber() int {
+	next := 1
+	if e.Handle != nil && len(e.Handle.Project.Issues) > 0 {
+		for _, pg := range e.Handle.Project.Issues[0].Pages {
+			next = max(next, pg.Number+1)
+		}
+	}
+	return next
+}
+
+// AddPage adds the page numbered n to the first issue unless it has one, like storage.EnsurePage, and
+// makes it current. grid ("" or "None" for none) becomes the panel grid of an empty new page. A page
+// inserted between the two pages of a spread splits it; the warnings say which. The project is saved.
+func (e *EditorState) AddPage(n int, grid string) ([]storage.SpreadWarning, error) {
+	if e.Handle == nil {
+		return nil, ErrNoProject
+	}
+	pg, err := storage.EnsurePage(e.Handle, n)
+	if err != nil {
+		return nil, err
+	}
+	// Only an empty page takes the grid; existing panels are never replaced
+	if grid != "" && grid != "None" && len(pg.Panels) == 0 && strings.TrimSpace(pg.Grid) == "" {
+		pg.Grid = grid
+	}
+	warns := storage.NormalizeSpreads(&e.Handle.Project.Issues[0])
+	if err := storage.Save(e.Handle); err != nil {
+		return warns, err
+	}
+	e.IssueIdx = 0
+	e.SelectPage(n)
+	return warns, nil
+}
+
+// MovePanelZ moves a panel of the current page delta steps up the stacking order and saves the project.
+func (e *EditorState) MovePanelZ(panelID string, delta int) error {
+	n, err := e.currentPageNumber()
+	if err != nil {
+		return err
+	}
+	if err := storage.MovePanelZ(e.Handle, n, panelID, delta, false); err != nil {
+		return err
+	}
+	return storage.Save(e.Handle)
+}
+
+// SplitPanel cuts a panel of the current page in two along axis, the first part taking ratio of it and
+// gutter points between the parts, as one undo step. It returns the new second panel.
+func (e *EditorState) SplitPanel(panelID string, axis storage.SplitAxis, ratio, gutter float64) (second domain.Panel, err error) {
+	n, err := e.currentPageNumber()
+	if err != nil {
+		return domain.Panel{}, err
+	}
+	err = e.Change(func() (string, error) {
+		var err error
+		if _, second, err = storage.SplitPanel(e.Handle, n, panelID, axis, ratio, gutter); err != nil {
+			return "", err
+		}
+		return i18n.T("undo.split_panel", panelID), nil
+	})
+	return second, err
+}
+
+// SetPanelBorder sets the border of a panel on the page numbered n; nil inherits the project's.
+func (e *EditorState) SetPanelBorder(n int, panelID string, b *domain.PanelBorder) error {
+	return e.Change(func() (string, error) {
+		if err := storage.SetPanelBorder(e.Handle, n, panelID, b); err != nil {
+			return "", err
+		}
+		return i18n.T("undo.panel_border", panelID), nil
+	})
+}
+
+// UpdatePanel renames a panel on the page numbered n to newID ("" keeps its ID), sets its notes and
+// replaces its art annotations, then saves the project. The selection follows a renamed panel.
+func (e *EditorState) UpdatePanel(n int, panelID, newID, notes string, ann *domain.PanelAnnotations) error {
+	if e.Handle == nil {
+		return ErrNoProject
+	}
+	if err := storage.ValidatePanelAnnotations(ann); err != nil {
+		return err
+	}
+	if err := storage.UpdatePanelMeta(e.Handle, n, panelID, newID, notes, false); err != nil {
+		return err
+	}
+	if newID != "" && newID != panelID {
+		if e.Selected.Page == n && e.Selected.PanelID == panelID {
+			e.Selected.PanelID = newID
+		}
+		if n == e.PageNumber() {
+			e.Panels.rename(panelID, newID)
+		}
+		panelID = newID
+	}
+	if err := storage.SetPanelAnnotations(e.Handle, n, panelID, ann); err != nil {
+		return err
+	}
+	return storage.Save(e.Handle)
+}
+
+// EditBalloon replaces the text of the balloon at with runs, switches it to the balloon style named
+// style unless it already has it (case-insensitively) and saves the project.
+func (e *EditorState) EditBalloon(at Selection, runs []domain.TextRun, style string) error {
+	if e.Handle == nil {
+		return ErrNoProject
+	}
+	b, err := storage.FindBalloon(e.Handle, at.Page, at.PanelID, at.BalloonID)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(style, b.StyleRef) {
+		if err := storage.SetBalloonStyle(e.Handle, at.Page, at.PanelID, at.BalloonID, style); err != nil {
+			return err
+		}
+	}
+	if err := storage.UpdateBalloonText(e.Handle, at.Page, at.PanelID, at.BalloonID, runs); err != nil {
+		return err
+	}
+	return storage.Save(e.Handle)
+}
+
+// FixReadingOrder renumbers the stacking order of the page numbered n of the current issue in reading
+// order; see storage.FixReadingOrder. The project is saved when that changed anything.
+func (e *EditorState) FixReadingOrder(n int) (changed bool, err error) {
+	if e.Handle == nil {
+		return false, ErrNoProject
+	}
+	if changed, err = storage.FixReadingOrder(e.Handle, e.IssueIdx, n); err != nil || !changed {
+		return changed, err
+	}
+	return true, storage.Save(e.Handle)
+}
+
+// MoveBalloon moves the balloon at to rect, keeping its type and speaker.
+func (e *EditorState) MoveBalloon(at Selection, rect domain.Rect) error {
+	if e.Handle == nil {
+		return ErrNoProject
+	}
+	b, err := storage.FindBalloon(e.Handle, at.Page, at.PanelID, at.BalloonID)
+	if err != nil {
+		return err
+	}
+	return e.Change(func() (string, error) {
+		meta := storage.BalloonMeta{Type: b.Type, Character: b.Character, Rect: rect}
+		if err := storage.UpdateBalloonMeta(e.Handle, at.Page, at.PanelID, b.ID, meta); err != nil {
+			return "", err
+		}
+		return i18n.T("undo.move_balloon", b.ID), nil
+	})
+}
+
+// DeleteBalloon deletes the balloon at; its panel stays selected.
+func (e *EditorState) DeleteBalloon(at Selection) error {
+	err := e.Change(func() (string, error) {
+		if err := storage.DeleteBalloon(e.Handle, at.Page, at.PanelID, at.BalloonID); err != nil {
+			return "", err
+		}
+		return i18n.T("undo.delete_balloon", at.BalloonID), nil
+	})
+	e.ResolveSelection()
+	return err
+}
+
+// panelBulk runs op on the panels ids of the current page as one undo step labelled with what.
+func (e *EditorState) panelBulk(what string, ids []string, op func(n int) error) error {
+	n, err := e.currentPageNumber()
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return ErrNoPanelsSelected
+	}
+	return e.Change(func() (string, error) {
+		if err := op(n); err != nil {
+			return "", err
+		}
+		return i18n.N("undo.panel_bulk", len(ids), what, len(ids)), nil
+	})
+}
+
+// DeletePanels deletes the panels ids of the current page and clears the panel selection.
+func (e *EditorState) DeletePanels(ids []string) error {
+	err := e.panelBulk(i18n.T("status.deleted_panels"), ids, func(n int) error {
+		return storage.DeletePanels(e.Handle, n, ids, false)
+	})
+	if err == nil {
+		e.Panels.Reset()
+		e.ResolveSelection()
+	}
+	return err
+}
+
+// SetPanelsNotes replaces the notes of the panels ids of the current page.
+func (e *EditorState) SetPanelsNotes(ids []string, notes string) error {
+	return e.panelBulk(i18n.T("status.set_notes"), ids, func(n int) error {
+		return storage.SetPanelsNotes(e.Handle, n, ids, notes, false)
+	})
+}
+
+// OffsetPanels moves the panels ids of the current page by dx, dy points.
+func (e *EditorState) OffsetPanels(ids []string, dx, dy float64) error {
+	return e.panelBulk(i18n.T("status.moved_panels"), ids, func(n int) error {
+		return storage.OffsetPanels(e.Handle, n, ids, dx, dy, false)
+	})
+}
+
+// DistributePanels spaces the panels ids of the current page evenly, side by side or stacked.
+func (e *EditorState) DistributePanels(ids []string, horizontal bool) error {
+	return e.panelBulk(i18n.T("status.distributed_panels"), ids, func(n int) error {
+		return storage.DistributePanels(e.Handle, n, ids, horizontal, false)
+	})
+}
+
+// MergePanels replaces the panels ids of the current page with one covering them and clears the panel
+// selection. It returns the merged panel.
+func (e *EditorState) MergePanels(ids []string) (merged domain.Panel, err error) {
+	err = e.panelBulk(i18n.T("status.merged_panels"), ids, func(n int) error {
+		var err error
+		merged, err = storage.MergePanels(e.Handle, n, ids)
+		return err
+	})
+	if err == nil {
+		e.Panels.Reset()
+	}
+	return merged, err
+}
+
+// SetPanelsLocked locks or unlocks the panels ids of the current page.
+func (e *EditorState) SetPanelsLocked(ids []string, locked bool) error {
+	what := i18n.T("status.locked_panels")
+	if !locked {
+		what = i18n.T("status.unlocked_panels")
+	}
+	return e.panelBulk(what, ids, func(n int) error {
+		return storage.SetPanelsLocked(e.Handle, n, ids, locked)
+	})
+}
+
+// PanelsLocked reports whether every one of the panels ids on the current page is locked, so a lock
+// action unlocks them; false without panels.
+func (e *EditorState) PanelsLocked(ids []string) bool {
+	pg := e.Page()
+	if pg == nil || len(ids) == 0 {
+		return false
+	}
+	for _, p := range pg.Panels {
+		if slices.Contains(ids, p.ID) && !p.Locked {
+			return false
+		}
+	}
+	return true
+}
+
+// MoveBalloonOrder moves a balloon delta places in the reading order of its panel on the page numbered
+// n. It reports whether the order changed.
+func (e *EditorState) MoveBalloonOrder(n int, panelID, balloonID string, delta int) (moved bool, err error) {
+	err = e.Change(func() (string, error) {
+		var err error
+		if moved, err = storage.MoveBalloonOrder(e.Handle, n, panelID, balloonID, delta); err != nil || !moved {
+			return "", err
+		}
+		return i18n.T("undo.balloon_order", balloonID), nil
+	})
+	return moved, err
+}
+
+// AutoOrderBalloons orders the balloons of one panel, or of every panel when panelID is "", on the page
+// numbered n by their position. It returns how many balloons changed place.
+func (e *EditorState) AutoOrderBalloons(n int, panelID string) (changed int, err error) {
+	err = e.Change(func() (string, error) {
+		var err error
+		if changed, err = storage.AutoOrderBalloons(e.Handle, n, panelID); err != nil || changed == 0 {
+			return "", err
+		}
+		return i18n.T("undo.auto_order_balloons", n), nil
+	})
+	return changed, err
+}
+
+// LockPage locks or unlocks every panel on the page numbered n and returns how many changed.
+func (e *EditorState) LockPage(n int, locked bool) (changed int, err error) {
+	title := i18n.T("msg.lock_all_panels")
+	if !locked {
+		title = i18n.T("msg.unlock_all_panels")
+	}
+	err = e.Change(func() (string, error) {
+		var err error
+		if changed, err = storage.SetPageLocked(e.Handle, n, locked); err != nil || changed == 0 {
+			return "", err
+		}
+		return i18n.T("undo.lock_page", title, n), nil
+	})
+	return changed, err
+}
+
+// NormalizeGutters sets every gutter of the grid on the page numbered n to gutter points and returns
+// how many panels moved.
+func (e *EditorState) NormalizeGutters(n int, gutter float64) (changed int, err error) {
+	err = e.Change(func() (string, error) {
+		var err error
+		if changed, err = storage.NormalizeGutters(e.Handle, n, gutter); err != nil || changed == 0 {
+			return "", err
+		}
+		return i18n.T("undo.normalize_gutters", n), nil
+	})
+	return changed, err
+}
+
+// MakeSpread makes the pages numbered a and b of the current issue a double-page spread.
+func (e *EditorState) MakeSpread(a, b int) error {
+	return e.Change(func() (string, error) {
+		iss := e.Issue()
+		if iss == nil {
+			return "", errNoPage
+		}
+		if err := storage.SetSpread(iss, a, b); err != nil {
+			return "", err
+		}
+		return i18n.T("undo.make_spread", a, b), nil
+	})
+}
+
+// SplitSpread splits the spread the page numbered n of the current issue is part of.
+func (e *EditorState) SplitSpread(n int) (warns []storage.SpreadWarning, err error) {
+	err = e.Change(func() (string, error) {
+		iss := e.Issue()
+		if iss == nil {
+			return "", errNoPage
+		}
+		warns = storage.SplitSpread(iss, n)
+		return i18n.T("undo.split_spread", n), nil
+	})
+	return warns, err
+}
+
+// RepairPageNumbers renumbers the pages of every issue from 1 without gaps; see storage.RepairPageNumbers.
+func (e *EditorState) RepairPageNumbers() (renums []storage.PageRenumber, warns []storage.SpreadWarning, err error) {
+	err = e.Change(func() (string, error) {
+		renums, warns = storage.RepairPageNumbers(e.Handle)
+		return i18n.T("undo.repair_page_numbers"), nil
+	})
+	return renums, warns, err
+}
+
+// SetReferenceImage sets the reference image of the page numbered n of the current issue; nil removes it.
+func (e *EditorState) SetReferenceImage(n int, ref *domain.ReferenceImage) error {
+	return e.Change(func() (string, error) {
+		iss := e.Issue()
+		if iss == nil {
+			return "", errNoPage
+		}
+		pi := slices.IndexFunc(iss.Pages, func(pg domain.Page) bool { return pg.Number == n })
+		if pi < 0 {
+			return "", errNoPage
+		}
+		iss.Pages[pi].ReferenceImage = ref
+		return i18n.T("undo.reference_image", n), nil
+	})
+}
+
+// PastePanel adds a copy of p to the page numbered n and returns it; see storage.PastePanel.
+func (e *EditorState) PastePanel(n int, p domain.Panel) (pasted domain.Panel, err error) {
+	err = e.Change(func() (string, error) {
+		var err error
+		if pasted, err = storage.PastePanel(e.Handle, n, p); err != nil {
+			return "", err
+		}
+		return i18n.T("undo.paste_panel", pasted.ID), nil
+	})
+	return pasted, err
+}
+
+// PasteBalloon adds a copy of b to a panel on the page numbered n and returns it; see storage.PasteBalloon.
+func (e *EditorState) PasteBalloon(n int, panelID string, b domain.Balloon) (pasted domain.Balloon, err error) {
+	err = e.Change(func() (string, error) {
+		var err error
+		if pasted, err = storage.PasteBalloon(e.Handle, n, panelID, b); err != nil {
+			return "", err
+		}
+		return i18n.T("undo.paste_balloon", pasted.ID), nil
+	})
+	return pasted, err
+}
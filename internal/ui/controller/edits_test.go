@@ -0,0 +1,271 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package controller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/undo"
+)
+
+// newRowEditor opens a project whose page 1 holds panels a, b and c side by side, each 100pt wide with
+// a 10pt gutter, and records every undo step.
+func newRowEditor(t *testing.T) *EditorState {
+	t.Helper()
+	ed := newEditor(t, 2)
+	ed.History = undo.NewManager(undo.Config{MaxPerPage: undoDepth, MinInterval: time.Nanosecond})
+	ed.Issue().Pages[0].Panels = []domain.Panel{
+		{ID: "a", Geometry: domain.Rect{X: 0, Y: 0, Width: 100, Height: 100}},
+		{ID: "b", Geometry: domain.Rect{X: 110, Y: 0, Width: 100, Height: 100}},
+		{ID: "c", Geometry: domain.Rect{X: 220, Y: 0, Width: 100, Height: 100}},
+	}
+	return ed
+}
+
+func panelIDs(pg *domain.Page) []string {
+	var out []string
+	for _, p := range pg.Panels {
+		out = append(out, p.ID)
+	}
+	return out
+}
+
+func TestChangeRecordsUndoAndSaves(t *testing.T) {
+	ed := newRowEditor(t)
+	var recorded []string
+	ed.Recorded = func(s undo.Snapshot) { recorded = append(recorded, s.Label) }
+
+	// Nothing changed: no undo step
+	if err := ed.Change(func() (string, error) { return "", nil }); err != nil {
+		t.Fatalf("Change: %v", err)
+	}
+	boom := errors.New("boom")
+	if err := ed.Change(func() (string, error) { return "", boom }); !errors.Is(err, boom) {
+		t.Fatalf("Change error = %v, want boom", err)
+	}
+	if len(recorded) != 0 || len(ed.UndoHistory()) != 0 {
+		t.Fatalf("recorded %v, want nothing", recorded)
+	}
+
+	if err := ed.Change(func() (string, error) {
+		ed.Issue().Pages[0].Notes = "changed"
+		return "Edit notes", nil
+	}); err != nil {
+		t.Fatalf("Change: %v", err)
+	}
+	if !reflect.DeepEqual(recorded, []string{"Edit notes"}) {
+		t.Fatalf("recorded = %v", recorded)
+	}
+	reopened, err := storage.Open(ed.Handle.Root)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if got := reopened.Project.Issues[0].Pages[0].Notes; got != "changed" {
+		t.Fatalf("saved notes = %q, want the change saved", got)
+	}
+	if ok, err := ed.Undo(); !ok || err != nil || ed.Issue().Pages[0].Notes != "" {
+		t.Fatalf("Undo = %v, %v; notes %q", ok, err, ed.Issue().Pages[0].Notes)
+	}
+	if err := NewEditorState().Change(func() (string, error) { return "x", nil }); !errors.Is(err, ErrNoProject) {
+		t.Fatalf("Change without project: %v", err)
+	}
+}
+
+func TestAddPage(t *testing.T) {
+	ed := newEditor(t, 2)
+	if n := ed.NextPageNumber(); n != 3 {
+		t.Fatalf("NextPageNumber = %d, want 3", n)
+	}
+	if _, err := ed.AddPage(3, "2x2"); err != nil {
+		t.Fatalf("AddPage: %v", err)
+	}
+	if pg := ed.Page(); pg == nil || pg.Number != 3 || pg.Grid != "2x2" {
+		t.Fatalf("current page = %+v, want new page 3 with the 2x2 grid", pg)
+	}
+	// An existing page with panels keeps them and takes no grid
+	if _, err := ed.AddPage(1, "3x3"); err != nil {
+		t.Fatalf("AddPage existing: %v", err)
+	}
+	if pg := ed.Page(); pg.Number != 1 || pg.Grid != "" || len(pg.Panels) != 1 {
+		t.Fatalf("page 1 = %+v, want it unchanged and current", pg)
+	}
+	if _, err := ed.AddPage(4, "None"); err != nil || ed.Page().Grid != "" {
+		t.Fatalf("AddPage without grid: %v, grid %q", err, ed.Page().Grid)
+	}
+	if n := NewEditorState().NextPageNumber(); n != 1 {
+		t.Fatalf("NextPageNumber without project = %d, want 1", n)
+	}
+	if _, err := NewEditorState().AddPage(1, ""); !errors.Is(err, ErrNoProject) {
+		t.Fatalf("AddPage without project: %v", err)
+	}
+}
+
+func TestPanelBulkEdits(t *testing.T) {
+	ed := newRowEditor(t)
+	shown := panelIDs(ed.Page())
+	ed.ClickPanel(shown, 0, false, false)
+	ed.ClickPanel(shown, 1, false, true)
+	ids := ed.SelectedPanels(shown)
+	if !reflect.DeepEqual(ids, []string{"a", "b"}) {
+		t.Fatalf("selected = %v", ids)
+	}
+
+	if err := ed.SetPanelsNotes(ids, "wide"); err != nil {
+		t.Fatalf("SetPanelsNotes: %v", err)
+	}
+	if pg := ed.Page(); pg.Panels[0].Notes != "wide" || pg.Panels[1].Notes != "wide" || pg.Panels[2].Notes != "" {
+		t.Fatalf("notes = %+v", pg.Panels)
+	}
+	if ed.PanelsLocked(ids) {
+		t.Fatal("panels locked before locking")
+	}
+	if err := ed.SetPanelsLocked(ids, true); err != nil || !ed.PanelsLocked(ids) {
+		t.Fatalf("SetPanelsLocked: %v, locked %v", err, ed.PanelsLocked(ids))
+	}
+	if err := ed.SetPanelsLocked(ids, false); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+
+	merged, err := ed.MergePanels(ids)
+	if err != nil {
+		t.Fatalf("MergePanels: %v", err)
+	}
+	if got := panelIDs(ed.Page()); len(got) != 2 || merged.Geometry.Width != 210 {
+		t.Fatalf("panels after merge = %v, merged %+v", got, merged)
+	}
+	if len(ed.SelectedPanels(panelIDs(ed.Page()))) != 0 {
+		t.Fatal("merge kept the panel selection")
+	}
+	if err := ed.DeletePanels(nil); !errors.Is(err, ErrNoPanelsSelected) {
+		t.Fatalf("DeletePanels without panels: %v", err)
+	}
+
+	// Deleting the selected panel drops it from the selection
+	shown = panelIDs(ed.Page())
+	ed.ClickPanel(shown, 1, false, false)
+	if err := ed.DeletePanels(ed.SelectedPanels(shown)); err != nil {
+		t.Fatalf("DeletePanels: %v", err)
+	}
+	if _, ok := ed.SelectedPanel(panelIDs(ed.Page())); ok || ed.Selected != (Selection{}) {
+		t.Fatalf("selection after delete = %+v", ed.Selected)
+	}
+	// Each bulk edit is one undo step
+	if n := len(ed.UndoHistory()); n != 5 {
+		t.Fatalf("undo steps = %d, want 5", n)
+	}
+	if ok, err := ed.Undo(); !ok || err != nil || len(ed.Page().Panels) != 2 {
+		t.Fatalf("Undo delete = %v, %v; %d panels", ok, err, len(ed.Page().Panels))
+	}
+}
+
+func TestSplitPanelAndBalloons(t *testing.T) {
+	ed := newRowEditor(t)
+	second, err := ed.SplitPanel("c", storage.SplitHorizontal, 0.5, 10)
+	if err != nil {
+		t.Fatalf("SplitPanel: %v", err)
+	}
+	if pg := ed.Page(); len(pg.Panels) != 4 || second.Geometry.Y <= 0 {
+		t.Fatalf("panels after split = %v, second %+v", panelIDs(pg), second)
+	}
+
+	b, err := ed.PasteBalloon(1, "a", domain.Balloon{Type: "speech", Shape: domain.Shape{Kind: "ellipse", Rect: domain.Rect{X: 10, Y: 10, Width: 40, Height: 20}}})
+	if err != nil {
+		t.Fatalf("PasteBalloon: %v", err)
+	}
+	at := Selection{Page: 1, PanelID: "a", BalloonID: b.ID}
+	ed.Select(at, panelIDs(ed.Page()))
+	if id, ok := ed.SelectedPanel(panelIDs(ed.Page())); !ok || id != "a" || !ed.Selected.IsBalloon() {
+		t.Fatalf("selected = %+v", ed.Selected)
+	}
+	rect := domain.Rect{X: 30, Y: 40, Width: 40, Height: 20}
+	if err := ed.MoveBalloon(at, rect); err != nil {
+		t.Fatalf("MoveBalloon: %v", err)
+	}
+	if got, _ := storage.FindBalloon(ed.Handle, 1, "a", b.ID); got.Shape.Rect != rect || got.Type != "speech" {
+		t.Fatalf("moved balloon = %+v", got)
+	}
+	if err := ed.DeleteBalloon(at); err != nil {
+		t.Fatalf("DeleteBalloon: %v", err)
+	}
+	if ed.Selected != (Selection{Page: 1, PanelID: "a"}) {
+		t.Fatalf("selection after deleting the balloon = %+v, want its panel", ed.Selected)
+	}
+	if n := len(ed.UndoHistory()); n != 4 {
+		t.Fatalf("undo steps = %d, want 4", n)
+	}
+}
+
+func TestPageEdits(t *testing.T) {
+	ed := newRowEditor(t)
+	changed, err := ed.LockPage(1, true)
+	if err != nil || changed != 3 {
+		t.Fatalf("LockPage = %d, %v; want 3", changed, err)
+	}
+	// Nothing left to lock: no undo step
+	if changed, err := ed.LockPage(1, true); err != nil || changed != 0 {
+		t.Fatalf("LockPage again = %d, %v", changed, err)
+	}
+	if n := len(ed.UndoHistory()); n != 1 {
+		t.Fatalf("undo steps = %d, want 1", n)
+	}
+
+	if err := ed.MakeSpread(1, 2); err != nil {
+		t.Fatalf("MakeSpread: %v", err)
+	}
+	if p := storage.SpreadPartner(*ed.Issue(), 1); p != 2 {
+		t.Fatalf("spread partner of 1 = %d, want 2", p)
+	}
+	if _, err := ed.SplitSpread(2); err != nil {
+		t.Fatalf("SplitSpread: %v", err)
+	}
+	if p := storage.SpreadPartner(*ed.Issue(), 1); p != 0 {
+		t.Fatalf("spread partner after split = %d", p)
+	}
+	if err := ed.MakeSpread(1, 7); err == nil {
+		t.Fatal("expected an error for a missing page")
+	}
+
+	ref := &domain.ReferenceImage{Asset: "assets/thumbs/p02.jpg"}
+	if err := ed.SetReferenceImage(2, ref); err != nil || ed.Issue().Pages[1].ReferenceImage == nil {
+		t.Fatalf("SetReferenceImage: %v", err)
+	}
+	if err := ed.SetReferenceImage(9, ref); err == nil {
+		t.Fatal("expected an error for a missing page")
+	}
+	if n := len(ed.UndoHistory()); n != 4 {
+		t.Fatalf("undo steps = %d, want 4", n)
+	}
+}
+
+func TestUpdatePanelFollowsRename(t *testing.T) {
+	ed := newRowEditor(t)
+	shown := panelIDs(ed.Page())
+	ed.ClickPanel(shown, 1, false, false)
+	ann := &domain.PanelAnnotations{Assignee: "kim"}
+	if err := ed.UpdatePanel(1, "b", "b2", "close-up", ann); err != nil {
+		t.Fatalf("UpdatePanel: %v", err)
+	}
+	pn := ed.Page().Panels[1]
+	if pn.ID != "b2" || pn.Notes != "close-up" || pn.Annotations == nil || pn.Annotations.Assignee != "kim" {
+		t.Fatalf("panel = %+v", pn)
+	}
+	if id, ok := ed.SelectedPanel(panelIDs(ed.Page())); !ok || id != "b2" {
+		t.Fatalf("selected panel = %q, %v; want the renamed panel", id, ok)
+	}
+	if err := ed.UpdatePanel(1, "b2", "", "", &domain.PanelAnnotations{Due: "soon"}); err == nil {
+		t.Fatal("expected an error for an invalid due date")
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package controller
+
+import (
+	"slices"
+
+	"gocomicwriter/internal/domain"
+)
+
+// PanelSelection is the set of panels selected in the panel list, by ID. A plain click selects one
+// panel, Ctrl toggles a panel and Shift extends from the last clicked panel (the anchor) like in a
+// file manager.
+type PanelSelection struct {
+	ids    map[string]bool
+	anchor string
+}
+
+// Click applies a click on row i of the list of panel IDs currently shown.
+func (s *PanelSelection) Click(list []string, i int, ctrl, shift bool) {
+	if i < 0 || i >= len(list) {
+		return
+	}
+	if s.ids == nil {
+		s.ids = map[string]bool{}
+	}
+	id := list[i]
+	a := slices.Index(list, s.anchor)
+	switch {
+	case shift && a >= 0:
+		if !ctrl {
+			clear(s.ids)
+		}
+		for j := min(a, i); j <= max(a, i); j++ {
+			s.ids[list[j]] = true
+		}
+		return // the anchor stays put so the range can be adjusted
+	case ctrl:
+		if s.ids[id] {
+			delete(s.ids, id)
+		} else {
+			s.ids[id] = true
+		}
+	default:
+		clear(s.ids)
+		s.ids[id] = true
+	}
+	s.anchor = id
+}
+
+// Has reports whether the panel is selected.
+func (s *PanelSelection) Has(id string) bool { return s.ids[id] }
+
+// rename keeps a renamed panel selected, and the anchor when it was.
+func (s *PanelSelection) rename(oldID, newID string) {
+	if s.ids[oldID] {
+		delete(s.ids, oldID)
+		s.ids[newID] = true
+	}
+	if s.anchor == oldID {
+		s.anchor = newID
+	}
+}
+
+// Reset clears the selection, e.g. when another page is shown.
+func (s *PanelSelection) Reset() {
+	clear(s.ids)
+	s.anchor = ""
+}
+
+// Selected returns the selected panels among list, in list order. Panels hidden by the panel filter are
+// not included, so bulk actions only touch what the user can see.
+func (s *PanelSelection) Selected(list []string) []string {
+	out := []string{}
+	for _, id := range list {
+		if s.ids[id] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// Selection is the one entity selected on the page canvas and in the inspector: a panel, or a
+// balloon of that panel, by ID on the page numbered Page. The canvas, the panel list and the balloon
+// list all show it. The zero value selects nothing.
+type Selection struct {
+	Page      int
+	PanelID   string
+	BalloonID string
+}
+
+// IsBalloon reports whether a balloon is selected.
+func (s Selection) IsBalloon() bool { return s.BalloonID != "" }
+
+// Resolve returns what is left of s in iss after an edit: a deleted balloon leaves its panel
+// selected, and a deleted panel or page clears the selection.
+func (s Selection) Resolve(iss *domain.Issue) Selection {
+	if iss == nil || s.PanelID == "" {
+		return Selection{}
+	}
+	pg := slices.IndexFunc(iss.Pages, func(p domain.Page) bool { return p.Number == s.Page })
+	if pg < 0 {
+		return Selection{}
+	}
+	pn := slices.IndexFunc(iss.Pages[pg].Panels, func(p domain.Panel) bool { return p.ID == s.PanelID })
+	if pn < 0 {
+		return Selection{}
+	}
+	if s.BalloonID != "" && !slices.ContainsFunc(iss.Pages[pg].Panels[pn].Balloons, func(b domain.Balloon) bool { return b.ID == s.BalloonID }) {
+		s.BalloonID = ""
+	}
+	return s
+}
+
+// Select makes s the selection. What no longer exists is dropped (see Selection.Resolve), and the
+// panel becomes the only one selected in the panel list when shown, the panel IDs the list shows,
+// has it on the current page.
+func (e *EditorState) Select(s Selection, shown []string) {
+	e.Selected = s.Resolve(e.Issue())
+	e.Panels.Reset()
+	if i := slices.Index(shown, e.Selected.PanelID); i >= 0 && e.Selected.Page == e.PageNumber() {
+		e.Panels.Click(shown, i, false, false)
+	}
+}
+
+// ClickPanel applies a click on row i of the panel list showing shown. The clicked panel is the
+// selection while the click leaves it selected; otherwise nothing is.
+func (e *EditorState) ClickPanel(shown []string, i int, ctrl, shift bool) {
+	e.Panels.Click(shown, i, ctrl, shift)
+	e.Selected = Selection{}
+	if i >= 0 && i < len(shown) && e.Panels.Has(shown[i]) {
+		e.Selected = Selection{Page: e.PageNumber(), PanelID: shown[i]}
+	}
+}
+
+// SelectedPanel returns the panel single-panel actions apply to: the selected panel while it is
+// selected in the panel list, which shows shown for the current page.
+func (e *EditorState) SelectedPanel(shown []string) (id string, ok bool) {
+	id = e.Selected.PanelID
+	if id == "" || e.Selected.Page != e.PageNumber() || !e.Panels.Has(id) || !slices.Contains(shown, id) {
+		return "", false
+	}
+	return id, true
+}
+
+// SelectedPanels returns the panels bulk actions apply to: those selected among shown, in list order.
+func (e *EditorState) SelectedPanels(shown []string) []string {
+	return e.Panels.Selected(shown)
+}
+
+// ShowPage makes the page at index idx of the current issue current and clears the selection. It
+// reports whether the issue has such a page.
+func (e *EditorState) ShowPage(idx int) bool {
+	iss := e.Issue()
+	if iss == nil || idx < 0 || idx >= len(iss.Pages) {
+		return false
+	}
+	e.PageIdx = idx
+	e.ClearSelection()
+	return true
+}
+
+// ClearSelection selects nothing, e.g. when the project is closed.
+func (e *EditorState) ClearSelection() {
+	e.Panels.Reset()
+	e.Selected = Selection{}
+}
+
+// ResolveSelection drops what an edit deleted from the selection; see Selection.Resolve.
+func (e *EditorState) ResolveSelection() {
+	e.Selected = e.Selected.Resolve(e.Issue())
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestPanelSelectionClicks(t *testing.T) {
+	list := []string{"p1", "p2", "p3", "p4", "p5"}
+	var s PanelSelection
+	check := func(step string, want ...string) {
+		t.Helper()
+		if got := s.Selected(list); !reflect.DeepEqual(got, append([]string{}, want...)) {
+			t.Fatalf("%s: selected = %v, want %v", step, got, want)
+		}
+	}
+	s.Click(list, 1, false, false)
+	check("click", "p2")
+	s.Click(list, 3, false, true)
+	check("shift-click", "p2", "p3", "p4")
+	s.Click(list, 0, false, true)
+	check("shift-click moves the range end, not the anchor", "p1", "p2")
+	s.Click(list, 4, true, false)
+	check("ctrl-click adds", "p1", "p2", "p5")
+	s.Click(list, 1, true, false)
+	check("ctrl-click removes", "p1", "p5")
+	s.Click(list, 2, true, true)
+	check("ctrl-shift-click extends from the ctrl-clicked anchor", "p1", "p2", "p3", "p5")
+	s.Click(list, 2, false, false)
+	check("plain click replaces", "p3")
+
+	// Filtered-out panels stay selected but are not acted upon
+	s.Click(list, 4, true, false)
+	if got := s.Selected([]string{"p1", "p5"}); !reflect.DeepEqual(got, []string{"p5"}) {
+		t.Fatalf("filtered selection = %v", got)
+	}
+	s.Reset()
+	check("reset")
+	s.Click(list, 2, false, true) // shift without an anchor acts as a plain click
+	check("shift without anchor", "p3")
+}
+
+func TestPageSelectionResolve(t *testing.T) {
+	iss := &domain.Issue{Pages: []domain.Page{{Number: 3, Panels: []domain.Panel{{ID: "p1", Balloons: []domain.Balloon{{ID: "b1"}}}}}}}
+	for _, tc := range []struct {
+		name string
+		sel  Selection
+		want Selection
+	}{
+		{"balloon kept", Selection{3, "p1", "b1"}, Selection{3, "p1", "b1"}},
+		{"deleted balloon leaves its panel", Selection{3, "p1", "b9"}, Selection{3, "p1", ""}},
+		{"deleted panel clears", Selection{3, "p9", "b1"}, Selection{}},
+		{"other page clears", Selection{4, "p1", ""}, Selection{}},
+		{"nothing stays nothing", Selection{}, Selection{}},
+	} {
+		if got := tc.sel.Resolve(iss); got != tc.want {
+			t.Errorf("%s: resolve = %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+	if got := (Selection{3, "p1", "b1"}).Resolve(nil); got != (Selection{}) {
+		t.Errorf("no issue: resolve = %+v", got)
+	}
+}
+
+func TestEditorSelection(t *testing.T) {
+	ed := newEditor(t, 2)
+	ed.Issue().Pages[0].Panels = append(ed.Issue().Pages[0].Panels, domain.Panel{ID: "q1"})
+	shown := []string{"p1", "q1"}
+
+	ed.ClickPanel(shown, 1, false, false)
+	if id, ok := ed.SelectedPanel(shown); !ok || id != "q1" || ed.Selected != (Selection{Page: 1, PanelID: "q1"}) {
+		t.Fatalf("after click: %q, %v, %+v", id, ok, ed.Selected)
+	}
+	// Hidden by the panel filter, the panel is not acted upon
+	if _, ok := ed.SelectedPanel([]string{"p1"}); ok {
+		t.Fatal("filtered-out panel is the selected panel")
+	}
+	// Ctrl-clicking it again deselects it
+	ed.ClickPanel(shown, 1, true, false)
+	if _, ok := ed.SelectedPanel(shown); ok || ed.Selected != (Selection{}) {
+		t.Fatalf("after ctrl-click: %+v", ed.Selected)
+	}
+
+	// Selecting on the canvas selects the panel in the list too
+	ed.Select(Selection{Page: 1, PanelID: "p1", BalloonID: "gone"}, shown)
+	if ed.Selected != (Selection{Page: 1, PanelID: "p1"}) || !reflect.DeepEqual(ed.SelectedPanels(shown), []string{"p1"}) {
+		t.Fatalf("after select: %+v, list %v", ed.Selected, ed.SelectedPanels(shown))
+	}
+	// A panel of another page is not the selected panel
+	ed.Select(Selection{Page: 2, PanelID: "p2"}, []string{"p2"})
+	if _, ok := ed.SelectedPanel([]string{"p2"}); ok || len(ed.SelectedPanels(shown)) != 0 {
+		t.Fatalf("panel of page 2 selected while page 1 is shown: %+v", ed.Selected)
+	}
+
+	if !ed.ShowPage(1) || ed.PageNumber() != 2 || ed.Selected != (Selection{}) {
+		t.Fatalf("ShowPage: page %d, selection %+v", ed.PageNumber(), ed.Selected)
+	}
+	if ed.ShowPage(5) || ed.PageIdx != 1 {
+		t.Fatalf("ShowPage out of range moved to idx %d", ed.PageIdx)
+	}
+	ed.Select(Selection{Page: 2, PanelID: "p2"}, []string{"p2"})
+	ed.Issue().Pages[1].Panels = nil
+	ed.ResolveSelection()
+	if ed.Selected != (Selection{}) {
+		t.Fatalf("selection of a deleted panel = %+v", ed.Selected)
+	}
+}
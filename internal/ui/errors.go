@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+
+	"gocomicwriter/internal/export"
+	"gocomicwriter/internal/i18n"
+	applog "gocomicwriter/internal/log"
+	"gocomicwriter/internal/storage"
+)
+
+// friendlyMessages maps failures to the message key of what the user can do about them; the first
+// match wins.
+var friendlyMessages = []struct {
+	err error
+	key string
+}{
+	{storage.ErrNotAProject, "error.not_a_project"},
+	{storage.ErrManifestCorrupt, "error.manifest_corrupt"},
+	{storage.ErrBackupNotFound, "error.backup_not_found"},
+	{storage.ErrBackupChainBroken, "error.backup_chain_broken"},
+	{storage.ErrIndexCorrupt, "error.index_corrupt"},
+	{storage.ErrIndexNewerThanApp, "error.index_newer_than_app"},
+	{storage.ErrPageNotFound, "error.page_not_found"},
+	{storage.ErrPanelNotFound, "error.panel_not_found"},
+	{storage.ErrPanelLocked, "error.panel_locked"},
+	{storage.ErrMergeOverlap, "error.merge_overlap"},
+	{storage.ErrManifestLocked, "error.manifest_locked"},
+	{storage.ErrArchiveUnsafePath, "error.archive_unsafe_path"},
+	{storage.ErrArchiveCorrupt, "error.archive_corrupt"},
+	{export.ErrImageTooLarge, "error.image_too_large"},
+	{fs.ErrPermission, "error.permission"},
+}
+
+// friendlyError shows an actionable message and unwraps to the technical error.
+type friendlyError struct {
+	msg string
+	err error
+}
+
+func (e *friendlyError) Error() string { return e.msg }
+func (e *friendlyError) Unwrap() error { return e.err }
+
+// FriendlyError returns err with a message telling the user what to do, for error dialogs, and logs the
+// technical detail it replaces. errors.Is and errors.As still see err. Errors without a known cause are
+// returned unchanged, as is nil.
+func FriendlyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var fe *friendlyError
+	if errors.As(err, &fe) {
+		return err
+	}
+	for _, m := range friendlyMessages {
+		if errors.Is(err, m.err) {
+			applog.WithComponent("ui").Warn("error shown to user", slog.String("message", m.key), slog.Any("err", err))
+			return &friendlyError{msg: i18n.T(m.key), err: err}
+		}
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/storage"
+)
+
+func TestFriendlyError(t *testing.T) {
+	if FriendlyError(nil) != nil {
+		t.Fatal("nil error mapped")
+	}
+	plain := errors.New("encode png: short write")
+	if got := FriendlyError(plain); got != plain {
+		t.Errorf("unknown error changed: %v", got)
+	}
+
+	_, err := storage.Open(t.TempDir())
+	got := FriendlyError(err)
+	if !strings.Contains(got.Error(), "File → New") || strings.Contains(got.Error(), "comic.json:") {
+		t.Errorf("not a project: %q", got)
+	}
+	if !errors.Is(got, storage.ErrNotAProject) || !errors.Is(got, fs.ErrNotExist) {
+		t.Errorf("mapped error lost its cause: %v", got)
+	}
+	if again := FriendlyError(got); again != got {
+		t.Errorf("mapping twice changed the error: %v", again)
+	}
+
+	for _, c := range []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("parse: %w", storage.ErrManifestCorrupt), "File → Backups…"},
+		{storage.ErrBackupNotFound, "backup no longer exists"},
+		{fmt.Errorf("%w: comic.json.20250101-000000.delta: x", storage.ErrBackupChainBroken), "older full backup"},
+		{fmt.Errorf("%w: open: %w", storage.ErrIndexCorrupt, fs.ErrNotExist), "Rebuild Index"},
+		{fmt.Errorf("%w: 4", storage.ErrPageNotFound), "page list"},
+		{fmt.Errorf("%w: p2 on page 1", storage.ErrPanelNotFound), "panel list"},
+		{fmt.Errorf("%w: p2 on page 1", storage.ErrPanelLocked), "Unlock"},
+		{fmt.Errorf("replace manifest: %w: 8 attempts: x", storage.ErrManifestLocked), "save again"},
+		{fmt.Errorf("%w: ../evil.txt", storage.ErrArchiveUnsafePath), "outside the project folder"},
+		{fmt.Errorf("%w: checksum mismatch", storage.ErrArchiveCorrupt), "new copy"},
+		{fmt.Errorf("write: %w", fs.ErrPermission), "permissions"},
+	} {
+		if got := FriendlyError(c.err); !strings.Contains(got.Error(), c.want) || !errors.Is(got, c.err) {
+			t.Errorf("FriendlyError(%v) = %q, want it to mention %q", c.err, got, c.want)
+		}
+	}
+}
+
+// TestMessageKeysExist checks that every literal key passed to i18n.T or i18n.N in this package is in
+// the English catalog, which would otherwise show the bare key in the UI.
+func TestMessageKeysExist(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("..", "i18n", "locales", "en.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var en map[string]json.RawMessage
+	if err := json.Unmarshal(b, &en); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob("*.go")
+	keyRe := regexp.MustCompile(`i18n\.[TN]\("([^"]+)"`)
+	n := 0
+	for _, f := range files {
+		src, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range keyRe.FindAllStringSubmatch(string(src), -1) {
+			n++
+			if _, ok := en[m[1]]; !ok {
+				t.Errorf("%s: unknown message key %q", f, m[1])
+			}
+		}
+	}
+	if n == 0 {
+		t.Fatal("no message keys found")
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"strings"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// replaceKinds are the object types of a project-wide replace in summary order, with the message
+// counting the occurrences replaced in each.
+var replaceKinds = []struct{ kind, key string }{
+	{storage.TextBalloon, "find.in_balloons"},
+	{storage.TextCaption, "find.in_captions"},
+	{storage.TextPanelNotes, "find.in_panel_notes"},
+	{storage.TextPageNotes, "find.in_page_notes"},
+	{storage.TextBibleNotes, "find.in_bible_notes"},
+}
+
+// textFieldLocation says where in the project a text found by find and replace is stored, e.g.
+// "Page 3 panel p2, balloon b1".
+func textFieldLocation(f storage.TextField) string {
+	switch f.Kind {
+	case storage.TextBalloon:
+		return i18n.T("find.loc_balloon", f.PageNumber, f.PanelID, f.ObjectID)
+	case storage.TextCaption:
+		return i18n.T("find.loc_caption", f.PageNumber, f.PanelID, f.ObjectID)
+	case storage.TextPanelNotes:
+		return i18n.T("find.loc_panel_notes", f.PageNumber, f.PanelID)
+	case storage.TextPageNotes:
+		return i18n.T("find.loc_page_notes", f.PageNumber)
+	case storage.TextBibleNotes:
+		switch f.Entry {
+		case storage.BibleCharacterEntry:
+			return i18n.T("find.loc_character_notes", f.Name)
+		case storage.BibleLocationEntry:
+			return i18n.T("find.loc_location_notes", f.Name)
+		case storage.BibleTagEntry:
+			return i18n.T("find.loc_tag_notes", f.Name)
+		}
+	}
+	return f.Kind
+}
+
+// lockedMatches counts the matches in locked panels.
+func lockedMatches(ms []storage.TextMatch) int {
+	n := 0
+	for _, m := range ms {
+		if m.Locked {
+			n++
+		}
+	}
+	return n
+}
+
+// replaceSummary describes what a project-wide replace changed, e.g. "Replaced 4 occurrences: 3 in
+// balloons, 1 in panel notes.", followed by the occurrences skipped because their text had changed.
+func replaceSummary(sum storage.ReplaceSummary) string {
+	if sum.Replaced == 0 && sum.Skipped == 0 {
+		return i18n.T("status.nothing_replaced")
+	}
+	var parts []string
+	for _, k := range replaceKinds {
+		if n := sum.ByKind[k.kind]; n > 0 {
+			parts = append(parts, i18n.T(k.key, n))
+		}
+	}
+	var out []string
+	if sum.Replaced > 0 {
+		out = append(out, i18n.N("status.replaced_in_project", sum.Replaced, sum.Replaced, strings.Join(parts, ", ")))
+	} else {
+		out = append(out, i18n.T("status.nothing_replaced"))
+	}
+	if sum.Skipped > 0 {
+		out = append(out, i18n.N("find.skipped_changed", sum.Skipped, sum.Skipped))
+	}
+	return strings.Join(out, " ")
+}
@@ -0,0 +1,164 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+package ui
+
+import (
+	"context"
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// textReplace applies change, which replaces text in the manifest, as one undoable step and saves.
+type textReplace func(undoLabel string, change func() (storage.ReplaceSummary, error)) (storage.ReplaceSummary, error)
+
+// showFindReplaceDialog finds text in the balloons, captions, panel and page notes and bible notes of
+// the project and lists every occurrence with its context and a checkbox. Replace Selected replaces
+// the checked ones in one step; occurrences in locked panels start unchecked and are only replaced
+// after a confirmation. The search runs again after each replace.
+func showFindReplaceDialog(w fyne.Window, ph *storage.ProjectHandle, l *slog.Logger, status *widget.Label, replace textReplace) {
+	if ph == nil {
+		return
+	}
+	findEntry, replEntry := widget.NewEntry(), widget.NewEntry()
+	findEntry.SetPlaceHolder(i18n.T("placeholder.find_in_project"))
+	replEntry.SetPlaceHolder(i18n.T("placeholder.replace_with"))
+	matchCs := widget.NewCheck(i18n.T("label.match_case"), nil)
+	whole := widget.NewCheck(i18n.T("label.whole_word"), nil)
+	count := widget.NewLabel("")
+	results := container.NewVBox()
+	var matches []storage.TextMatch
+	var checks []*widget.Check
+	var replaceBtn *widget.Button
+	setAll := func(on bool) {
+		for _, c := range checks {
+			c.SetChecked(on)
+		}
+	}
+	selectAll := widget.NewButton(i18n.T("button.select_all"), func() { setAll(true) })
+	selectNone := widget.NewButton(i18n.T("button.select_none"), func() { setAll(false) })
+	render := func(query string) {
+		results.RemoveAll()
+		checks = checks[:0]
+		switch {
+		case query == "":
+			count.SetText("")
+		case len(matches) == 0:
+			count.SetText(i18n.T("find.no_matches"))
+		default:
+			count.SetText(i18n.N("find.match_total", len(matches), len(matches)))
+		}
+		for _, m := range matches {
+			loc := textFieldLocation(m.Field)
+			if m.Locked {
+				loc += " · " + i18n.T("find.locked_panel")
+			}
+			where := widget.NewLabel(loc)
+			where.Importance = widget.LowImportance
+			preview := widget.NewRichText(
+				&widget.TextSegment{Text: m.Before, Style: widget.RichTextStyleInline},
+				&widget.TextSegment{Text: m.Match, Style: widget.RichTextStyleStrong},
+				&widget.TextSegment{Text: m.After, Style: widget.RichTextStyleInline},
+			)
+			preview.Truncation = fyne.TextTruncateEllipsis
+			check := widget.NewCheck("", nil)
+			check.SetChecked(!m.Locked)
+			checks = append(checks, check)
+			results.Add(container.NewBorder(nil, nil, check, nil, container.NewVBox(where, preview)))
+		}
+		if len(matches) == 0 {
+			replaceBtn.Disable()
+			selectAll.Disable()
+			selectNone.Disable()
+		} else {
+			replaceBtn.Enable()
+			selectAll.Enable()
+			selectNone.Enable()
+		}
+	}
+	// search runs FindText off the UI thread and lists the matches there.
+	search := func() {
+		query := findEntry.Text
+		opt := storage.FindOptions{CaseSensitive: matchCs.Checked, WholeWord: whole.Checked}
+		h := *ph
+		go func() {
+			ms, err := storage.FindText(context.Background(), &h, query, opt)
+			fyne.Do(func() {
+				if err != nil {
+					l.Error("find in project failed", slog.Any("err", err))
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				matches = ms
+				render(query)
+			})
+		}()
+	}
+	run := func(query string, selected []storage.TextMatch, repl string, force bool) {
+		sum, err := replace(i18n.T("undo.find_replace", query), func() (storage.ReplaceSummary, error) {
+			return storage.ReplaceText(ph, selected, repl, force)
+		})
+		if err != nil {
+			l.Error("replace in project failed", slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		msg := replaceSummary(sum)
+		status.SetText(msg)
+		dialog.ShowInformation(i18n.T("msg.find_replace_project"), msg, w)
+		search()
+	}
+	replaceBtn = widget.NewButton(i18n.T("button.replace_selected"), func() {
+		var selected []storage.TextMatch
+		for i, c := range checks {
+			if c.Checked && i < len(matches) {
+				selected = append(selected, matches[i])
+			}
+		}
+		if len(selected) == 0 {
+			return
+		}
+		query, repl := findEntry.Text, replEntry.Text
+		if n := lockedMatches(selected); n > 0 {
+			dialog.ShowConfirm(i18n.T("msg.find_replace_project"), i18n.N("msg.replace_in_locked_panels", n, n), func(ok bool) {
+				if ok {
+					run(query, selected, repl, true)
+				}
+			}, w)
+			return
+		}
+		run(query, selected, repl, false)
+	})
+	replaceBtn.Importance = widget.HighImportance
+	findEntry.OnSubmitted = func(string) { search() }
+	matchCs.OnChanged = func(bool) { search() }
+	whole.OnChanged = func(bool) { search() }
+	findBtn := widget.NewButton(i18n.T("button.find"), search)
+	render("")
+
+	top := container.NewVBox(
+		container.NewBorder(nil, nil, nil, container.NewHBox(matchCs, whole, findBtn), findEntry),
+		container.NewBorder(nil, nil, nil, replaceBtn, replEntry),
+		container.NewBorder(nil, nil, count, container.NewHBox(selectAll, selectNone)),
+	)
+	scroll := container.NewVScroll(results)
+	scroll.SetMinSize(fyne.NewSize(680, 360))
+	d := dialog.NewCustom(i18n.T("msg.find_replace_project"), i18n.T("msg.close"), container.NewBorder(top, nil, nil, nil, scroll), w)
+	d.Show()
+	w.Canvas().Focus(findEntry)
+}
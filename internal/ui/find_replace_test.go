@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/storage"
+)
+
+func TestTextFieldLocation(t *testing.T) {
+	for _, c := range []struct {
+		f    storage.TextField
+		want string
+	}{
+		{storage.TextField{Kind: storage.TextBalloon, PageNumber: 3, PanelID: "p2", ObjectID: "b1", Run: 1}, "Page 3 panel p2, balloon b1"},
+		{storage.TextField{Kind: storage.TextCaption, PageNumber: 3, PanelID: "p2", ObjectID: "c1"}, "Page 3 panel p2, caption c1"},
+		{storage.TextField{Kind: storage.TextPanelNotes, PageNumber: 3, PanelID: "p2"}, "Page 3 panel p2, notes"},
+		{storage.TextField{Kind: storage.TextPageNotes, PageNumber: 4}, "Page 4, notes"},
+		{storage.TextField{Kind: storage.TextBibleNotes, Entry: storage.BibleCharacterEntry, Name: "Ada"}, "Character Ada, notes"},
+		{storage.TextField{Kind: storage.TextBibleNotes, Entry: storage.BibleTagEntry, Name: "storm"}, "Tag storm, notes"},
+	} {
+		if got := textFieldLocation(c.f); got != c.want {
+			t.Errorf("textFieldLocation(%+v) = %q, want %q", c.f, got, c.want)
+		}
+	}
+}
+
+func TestReplaceSummary(t *testing.T) {
+	for _, c := range []struct {
+		sum  storage.ReplaceSummary
+		want string
+	}{
+		{storage.ReplaceSummary{}, "Nothing replaced."},
+		{storage.ReplaceSummary{Replaced: 1, ByKind: map[string]int{storage.TextCaption: 1}}, "Replaced 1 occurrence: 1 in captions."},
+		{storage.ReplaceSummary{Replaced: 6, ByKind: map[string]int{storage.TextBibleNotes: 2, storage.TextBalloon: 3, storage.TextPanelNotes: 1}, Skipped: 1},
+			"Replaced 6 occurrences: 3 in balloons, 1 in panel notes, 2 in bible notes. 1 skipped because its text changed since the search."},
+		{storage.ReplaceSummary{Skipped: 2}, "Nothing replaced. 2 skipped because their text changed since the search."},
+	} {
+		if got := replaceSummary(c.sum); got != c.want {
+			t.Errorf("replaceSummary(%+v) = %q, want %q", c.sum, got, c.want)
+		}
+	}
+	if n := lockedMatches([]storage.TextMatch{{Locked: true}, {}, {Locked: true}}); n != 2 {
+		t.Errorf("lockedMatches = %d, want 2", n)
+	}
+}
@@ -0,0 +1,29 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import "gocomicwriter/internal/storage"
+
+// fontDefaultOption is the font picker entry that leaves lettering in the exports' default font.
+const fontDefaultOption = "(Default font)"
+
+// fontPickerOptions lists the default entry followed by the families of the project's fonts.
+func fontPickerOptions(fonts []storage.ProjectFont) []string {
+	return append([]string{fontDefaultOption}, storage.FontFamilies(fonts)...)
+}
+
+// fontFromOption returns the font family stored for a picker entry; the default entry stores none.
+func fontFromOption(opt string) string {
+	if opt == fontDefaultOption {
+		return ""
+	}
+	return opt
+}
@@ -0,0 +1,34 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"log/slog"
+
+	applog "gocomicwriter/internal/log"
+	"gocomicwriter/internal/storage"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+// newFontPicker returns a select of the font families in the project's styles/fonts folder, set to
+// the default entry. A folder that cannot be read leaves only the default.
+func newFontPicker(root string) *widget.Select {
+	fonts, err := storage.ListProjectFonts(root)
+	if err != nil {
+		applog.WithComponent("ui").Warn("list project fonts failed", slog.Any("err", err))
+	}
+	sel := widget.NewSelect(fontPickerOptions(fonts), nil)
+	sel.SetSelected(fontDefaultOption)
+	return sel
+}
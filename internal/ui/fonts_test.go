@@ -0,0 +1,35 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/storage"
+)
+
+func TestFontPickerOptions(t *testing.T) {
+	if got := fontPickerOptions(nil); len(got) != 1 || fontFromOption(got[0]) != "" {
+		t.Fatalf("no fonts: %v", got)
+	}
+	fonts := []storage.ProjectFont{
+		{Family: "Anime Ace", Subfamily: "Regular"},
+		{Family: "Anime Ace", Subfamily: "Bold"},
+		{Family: "Blambot", Subfamily: "Regular"},
+	}
+	got := fontPickerOptions(fonts)
+	if len(got) != 3 || got[1] != "Anime Ace" || got[2] != "Blambot" {
+		t.Fatalf("options %v", got)
+	}
+	if fontFromOption(got[2]) != "Blambot" {
+		t.Fatalf("family of %q = %q", got[2], fontFromOption(got[2]))
+	}
+}
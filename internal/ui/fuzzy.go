@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Fuzzy match scores: every matched rune counts, runs of adjacent runes and runes that start a word
+// count much more, and each rune of the text before the first match or left unmatched costs a little,
+// so that of two equally good matches the shorter text wins.
+const (
+	fuzzyMatchScore     = 16
+	fuzzyAdjacentBonus  = 96
+	fuzzyWordStartBonus = 128
+	fuzzyLeadingPenalty = 2 // per rune before the first match, up to fuzzyMaxLeading runes
+	fuzzyMaxLeading     = 10
+	fuzzyUnmatchedCost  = 1 // per rune of the text not matched
+)
+
+// fuzzyScore matches pattern against text case-insensitively as a subsequence: "expcbz" matches
+// "Export Issue as CBZ". Whitespace in the pattern is ignored. It returns the best score over all
+// starting points of the first pattern rune and whether the pattern matches at all; an empty pattern
+// matches everything with score 0.
+func fuzzyScore(pattern, text string) (int, bool) {
+	var pat []rune
+	for _, r := range strings.ToLower(pattern) {
+		if !unicode.IsSpace(r) {
+			pat = append(pat, r)
+		}
+	}
+	if len(pat) == 0 {
+		return 0, true
+	}
+	txt := []rune(text)
+	low := []rune(strings.ToLower(text))
+	if len(low) != len(txt) {
+		// Lower-casing changed the length (rare special cases); fall back to rune-wise lower-casing
+		low = make([]rune, len(txt))
+		for i, r := range txt {
+			low[i] = unicode.ToLower(r)
+		}
+	}
+	best, found := 0, false
+	for start := range low {
+		if low[start] != pat[0] {
+			continue
+		}
+		if s, ok := fuzzyFrom(pat, txt, low, start); ok && (!found || s > best) {
+			best, found = s, true
+		}
+	}
+	return best, found
+}
+
+// fuzzyFrom matches pat greedily from position start of the text, where pat[0] is known to match.
+func fuzzyFrom(pat, txt, low []rune, start int) (int, bool) {
+	score := -min(start, fuzzyMaxLeading) * fuzzyLeadingPenalty
+	prev := -2
+	ti := start
+	for _, pr := range pat {
+		for ti < len(low) && low[ti] != pr {
+			ti++
+		}
+		if ti == len(low) {
+			return 0, false
+		}
+		score += fuzzyMatchScore
+		if ti == prev+1 {
+			score += fuzzyAdjacentBonus
+		}
+		if isWordStart(txt, ti) {
+			score += fuzzyWordStartBonus
+		}
+		prev = ti
+		ti++
+	}
+	return score - (len(txt)-len(pat))*fuzzyUnmatchedCost, true
+}
+
+// isWordStart reports whether the rune at i begins a word: it follows a non-alphanumeric rune, a
+// lower-case letter followed by an upper-case one ("camelCase"), or a letter followed by a digit.
+func isWordStart(txt []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, cur := txt[i-1], txt[i]
+	switch {
+	case !unicode.IsLetter(prev) && !unicode.IsDigit(prev):
+		return true
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return true
+	case unicode.IsLetter(prev) && unicode.IsDigit(cur):
+		return true
+	}
+	return false
+}
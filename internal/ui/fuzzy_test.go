@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import "testing"
+
+func TestFuzzyScore_Matches(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, text string
+		ok            bool
+	}{
+		{"", "anything", true},
+		{"expcbz", "Export Issue as CBZ", true},
+		{"export cbz", "Export Issue as CBZ", true},
+		{"ALICE", "Alice", true},
+		{"p3", "Panel p3", true},
+		{"page 14", "Page 14", true},
+		{"cbzx", "Export Issue as CBZ", false},
+		{"zc", "CBZ", false},
+	} {
+		if _, ok := fuzzyScore(tc.pattern, tc.text); ok != tc.ok {
+			t.Errorf("fuzzyScore(%q, %q) ok = %v, want %v", tc.pattern, tc.text, ok, tc.ok)
+		}
+	}
+}
+
+func TestFuzzyScore_Ranking(t *testing.T) {
+	// Each pair: the first text should score higher than the second for the pattern
+	for _, tc := range []struct{ pattern, better, worse string }{
+		{"page 14", "Page 14", "Page 140"},                                   // shorter text
+		{"cbz", "Export Issue as CBZ", "Cab Bazaar"},                         // adjacent runes
+		{"ep", "Export PDF", "Deep"},                                         // word starts
+		{"p3", "Panel p3", "Panel p13"},                                      // adjacent beats scattered
+		{"alice", "Alice", "Malice"},                                         // match at the start
+		{"eipdf", "Export Issue as PDF", "Export Issue as PNG pages, draft"}, // only one matches
+	} {
+		b, okB := fuzzyScore(tc.pattern, tc.better)
+		w, okW := fuzzyScore(tc.pattern, tc.worse)
+		if !okB || (okW && b <= w) {
+			t.Errorf("%q: %q scored %d (%v), %q scored %d (%v)", tc.pattern, tc.better, b, okB, tc.worse, w, okW)
+		}
+	}
+}
+
+func TestFuzzyScore_BestStart(t *testing.T) {
+	// Matching greedily from the first "c" scatters the pattern over "Cover" and "CBZ"; the match
+	// starting at "CBZ" is better and is the one reported
+	text := "Cover — Export as CBZ"
+	greedy, ok := fuzzyFrom([]rune("cbz"), []rune(text), []rune("cover — export as cbz"), 0)
+	got, _ := fuzzyScore("cbz", text)
+	if !ok || got <= greedy {
+		t.Errorf("fuzzyScore = %d, greedy from the first rune = %d (%v)", got, greedy, ok)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// healthCheckLabel names a project health check in the Project Health dialog.
+func healthCheckLabel(c storage.HealthCheck) string {
+	switch c {
+	case storage.HealthIndex:
+		return i18n.T("health.index")
+	case storage.HealthBackups:
+		return i18n.T("health.backups")
+	case storage.HealthCrashAutosaves:
+		return i18n.T("health.crash_autosaves")
+	case storage.HealthOrphanPreviews:
+		return i18n.T("health.orphaned_previews")
+	case storage.HealthValidation:
+		return i18n.T("health.validation")
+	case storage.HealthScriptSnapshots:
+		return i18n.T("health.script_snapshots")
+	case storage.HealthSync:
+		return i18n.T("health.sync")
+	}
+	return string(c)
+}
+
+// healthStatusLabel names the outcome of a health check.
+func healthStatusLabel(s storage.HealthStatus) string {
+	switch s {
+	case storage.HealthOK:
+		return i18n.T("health.status_ok")
+	case storage.HealthWarning:
+		return i18n.T("health.status_warning")
+	}
+	return i18n.T("health.status_error")
+}
+
+// healthActionLabel is the caption of the button offering an item's fix, or "" for none.
+func healthActionLabel(a storage.HealthAction) string {
+	switch a {
+	case storage.HealthActionRebuildIndex:
+		return i18n.T("menu.rebuild_index")
+	case storage.HealthActionOpenBackups:
+		return i18n.T("menu.backups")
+	case storage.HealthActionRunMaintenance:
+		return i18n.T("menu.project_maintenance")
+	case storage.HealthActionReviewCrash:
+		return i18n.T("button.review_autosaves")
+	}
+	return ""
+}
+
+// healthDetail describes the result of a health check. Failed checks show their error.
+func healthDetail(it storage.HealthItem) string {
+	if it.Status == storage.HealthError && it.Check != storage.HealthValidation {
+		return i18n.T("health.check_failed", it.Detail)
+	}
+	switch it.Check {
+	case storage.HealthIndex:
+		return i18n.T("health.index_detail", it.Count, it.Expected)
+	case storage.HealthBackups:
+		if it.Count == 0 {
+			return i18n.T("health.no_backups")
+		}
+		return i18n.N("health.backups_detail", int(it.Count), it.Count, it.Time.Local().Format("2006-01-02 15:04"))
+	case storage.HealthCrashAutosaves:
+		return i18n.N("health.crash_autosaves_detail", int(it.Count), it.Count)
+	case storage.HealthOrphanPreviews:
+		return formatBytes(it.Count)
+	case storage.HealthValidation:
+		return i18n.T("health.validation_detail", it.Count, it.Warnings)
+	case storage.HealthScriptSnapshots:
+		return i18n.N("health.script_snapshots_detail", int(it.Count), it.Count)
+	case storage.HealthSync:
+		if it.Time.IsZero() {
+			return i18n.T("health.never_synced", it.Count)
+		}
+		return i18n.T("health.sync_detail", it.Time.Local().Format("2006-01-02 15:04"), it.Count)
+	}
+	return it.Detail
+}
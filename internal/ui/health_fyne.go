@@ -0,0 +1,86 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// healthStatusIcon is the colored icon for the outcome of a health check.
+func healthStatusIcon(s storage.HealthStatus) fyne.Resource {
+	switch s {
+	case storage.HealthOK:
+		return theme.NewSuccessThemedResource(theme.ConfirmIcon())
+	case storage.HealthWarning:
+		return theme.NewWarningThemedResource(theme.WarningIcon())
+	}
+	return theme.NewErrorThemedResource(theme.ErrorIcon())
+}
+
+// showProjectHealthDialog runs storage.HealthReport in the background and lists one row per check
+// with its severity and result. Rows whose fix has a handler in actions get a button for it, which
+// closes the dialog first.
+func showProjectHealthDialog(w fyne.Window, ph *storage.ProjectHandle, l *slog.Logger, status *widget.Label, actions map[storage.HealthAction]func()) {
+	if ph == nil {
+		return
+	}
+	ph.Index()
+	h := *ph
+	status.SetText(i18n.T("status.checking_project_health"))
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		rep, err := storage.HealthReport(ctx, &h)
+		fyne.Do(func() {
+			if err != nil {
+				l.Error("health check failed", slog.Any("err", err))
+				dialog.ShowError(FriendlyError(err), w)
+				status.SetText(i18n.T("status.health_check_failed"))
+				return
+			}
+			l.Info("project health", slog.String("status", string(rep.Status())))
+			status.SetText(i18n.T("status.project_health", healthStatusLabel(rep.Status())))
+			var d *dialog.CustomDialog
+			rows := container.NewVBox()
+			for _, it := range rep.Items {
+				icon := widget.NewIcon(healthStatusIcon(it.Status))
+				name := widget.NewLabelWithStyle(healthCheckLabel(it.Check), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+				detail := widget.NewLabel(healthDetail(it))
+				detail.Wrapping = fyne.TextWrapWord
+				var right fyne.CanvasObject
+				if run := actions[it.Action]; run != nil {
+					right = widget.NewButton(healthActionLabel(it.Action), func() {
+						d.Hide()
+						run()
+					})
+				}
+				rows.Add(container.NewBorder(nil, nil, container.NewHBox(icon, name), right, detail))
+			}
+			scroll := container.NewVScroll(rows)
+			scroll.SetMinSize(fyne.NewSize(620, 320))
+			d = dialog.NewCustom(i18n.T("msg.project_health"), i18n.T("msg.close"), scroll, w)
+			d.Show()
+		})
+	}()
+}
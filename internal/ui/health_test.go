@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/storage"
+)
+
+func TestHealthDetail(t *testing.T) {
+	ts := time.Date(2025, 3, 4, 5, 6, 0, 0, time.Local)
+	cases := []struct {
+		it   storage.HealthItem
+		want string
+	}{
+		{storage.HealthItem{Check: storage.HealthIndex, Status: storage.HealthWarning, Count: 9, Expected: 10}, "Integrity OK, 9 of 10 manifest documents indexed"},
+		{storage.HealthItem{Check: storage.HealthIndex, Status: storage.HealthError, Detail: "quick_check: bad page"}, "Check failed: quick_check: bad page"},
+		{storage.HealthItem{Check: storage.HealthBackups, Status: storage.HealthWarning}, "No backups yet"},
+		{storage.HealthItem{Check: storage.HealthBackups, Count: 1, Time: ts}, "1 backup, newest from 2025-03-04 05:06"},
+		{storage.HealthItem{Check: storage.HealthCrashAutosaves, Count: 2}, "2 autosaves awaiting review"},
+		{storage.HealthItem{Check: storage.HealthOrphanPreviews, Count: 2048}, "2.0 KB"},
+		{storage.HealthItem{Check: storage.HealthValidation, Status: storage.HealthError, Count: 1, Warnings: 3}, "1 errors, 3 warnings"},
+		{storage.HealthItem{Check: storage.HealthScriptSnapshots, Count: 1}, "1 snapshot"},
+		{storage.HealthItem{Check: storage.HealthSync, Count: 4}, "Never synced, 4 changes unsent"},
+		{storage.HealthItem{Check: storage.HealthSync, Time: ts}, "Last sync 2025-03-04 05:06, 0 changes unsent"},
+	}
+	for _, c := range cases {
+		if got := healthDetail(c.it); got != c.want {
+			t.Errorf("healthDetail(%s) = %q, want %q", c.it.Check, got, c.want)
+		}
+	}
+}
+
+func TestHealthLabels(t *testing.T) {
+	for _, c := range []storage.HealthCheck{storage.HealthIndex, storage.HealthBackups, storage.HealthCrashAutosaves, storage.HealthOrphanPreviews,
+		storage.HealthValidation, storage.HealthScriptSnapshots, storage.HealthSync} {
+		if healthCheckLabel(c) == string(c) {
+			t.Errorf("no label for check %s", c)
+		}
+	}
+	for _, a := range []storage.HealthAction{storage.HealthActionRebuildIndex, storage.HealthActionOpenBackups, storage.HealthActionRunMaintenance, storage.HealthActionReviewCrash} {
+		if healthActionLabel(a) == "" {
+			t.Errorf("no button for action %s", a)
+		}
+	}
+	if healthActionLabel(storage.HealthActionNone) != "" {
+		t.Error("button for no action")
+	}
+}
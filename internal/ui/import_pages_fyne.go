@@ -0,0 +1,151 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	fstorage "fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// The choices below are message keys; the dialogs show and compare them translated.
+const (
+	importFromFolder = "option.folder_of_images"
+	importFromCBZ    = "option.cbz_archive"
+	importSizeImage  = "option.size_from_first_image"
+	importSizeIssue  = "option.size_keep_issue"
+)
+
+// showImportPages asks for a folder of images or a .cbz and adds one page per image to the issue, with
+// the image as the page's art. A preview lists the pages before anything is changed. beforeChange runs
+// right before the issue is changed, e.g. to record an undo snapshot; onImported runs after the project
+// was saved, with the number of the first new page.
+func showImportPages(w fyne.Window, ph *storage.ProjectHandle, issueIdx int, l *slog.Logger, status *widget.Label, beforeChange func(), onImported func(first int)) {
+	sourceRadio := widget.NewRadioGroup([]string{i18n.T(importFromFolder), i18n.T(importFromCBZ)}, nil)
+	sourceRadio.SetSelected(i18n.T(importFromFolder))
+	dpiEntry := widget.NewEntry()
+	dpiEntry.SetText("300")
+	sizeRadio := widget.NewRadioGroup([]string{i18n.T(importSizeImage), i18n.T(importSizeIssue)}, func(s string) {
+		if s == i18n.T(importSizeImage) {
+			dpiEntry.Enable()
+		} else {
+			dpiEntry.Disable()
+		}
+	})
+	sizeRadio.SetSelected(i18n.T(importSizeImage))
+	dialog.ShowForm(i18n.T("msg.import_pages_from_images"), i18n.T("msg.choose"), i18n.T("msg.cancel"), []*widget.FormItem{
+		widget.NewFormItem(i18n.T("form.source"), sourceRadio),
+		widget.NewFormItem(i18n.T("form.page_size"), sizeRadio),
+		widget.NewFormItem(i18n.T("form.dpi"), dpiEntry),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		var opt storage.PageImportOptions
+		if sizeRadio.Selected == i18n.T(importSizeImage) {
+			dpi, err := strconv.ParseFloat(strings.TrimSpace(dpiEntry.Text), 64)
+			if err != nil || dpi <= 0 {
+				dialog.ShowError(errors.New(i18n.T("msg.enter_positive_dpi")), w)
+				return
+			}
+			opt.DPI = dpi
+		}
+		picked := func(src string) {
+			plan, err := storage.PlanPageImport(ph, issueIdx, src, opt)
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			showImportPagesPreview(w, plan, func() {
+				beforeChange()
+				plan, err := storage.ImportPages(ph, issueIdx, src, opt)
+				if err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				if err := storage.Save(ph); err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				l.Info("pages imported from images", slog.String("source", src), slog.Int("pages", len(plan.Items)), slog.Int("skipped", len(plan.Skipped)))
+				status.SetText(i18n.N("status.imported_pages", len(plan.Items), len(plan.Items), plan.Items[0].Number, plan.Items[len(plan.Items)-1].Number))
+				onImported(plan.Items[0].Number)
+			})
+		}
+		if sourceRadio.Selected == i18n.T(importFromFolder) {
+			dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+				if err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				if uri != nil {
+					picked(uri.Path())
+				}
+			}, w)
+			return
+		}
+		open := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			if rc == nil {
+				return
+			}
+			src := rc.URI().Path()
+			_ = rc.Close()
+			picked(src)
+		}, w)
+		open.SetFilter(fstorage.NewExtensionFileFilter([]string{".cbz", ".zip"}))
+		open.Show()
+	}, w)
+}
+
+// showImportPagesPreview lists the pages an import would create, the skipped files and a trim change,
+// and runs apply when confirmed.
+func showImportPagesPreview(w fyne.Window, plan storage.PageImportPlan, apply func()) {
+	var b strings.Builder
+	for _, it := range plan.Items {
+		b.WriteString(i18n.T("label.import_page_line", it.Number, it.Source, it.Width, it.Height, it.Asset))
+	}
+	if len(plan.Skipped) > 0 {
+		b.WriteString(i18n.T("label.import_skipped"))
+		for _, s := range plan.Skipped {
+			b.WriteString("  " + s + "\n")
+		}
+	}
+	summary := i18n.N("msg.import_pages_summary", len(plan.Items), len(plan.Items), plan.Items[0].Number, plan.Items[len(plan.Items)-1].Number)
+	if plan.TrimChanged {
+		summary += i18n.T("msg.import_trim_change", plan.TrimWidth, plan.TrimHeight)
+	}
+	list := widget.NewLabel(strings.TrimRight(b.String(), "\n"))
+	list.TextStyle = fyne.TextStyle{Monospace: true}
+	scroll := container.NewScroll(list)
+	scroll.SetMinSize(fyne.NewSize(560, 320))
+	d := dialog.NewCustomConfirm(i18n.T("msg.import_pages_preview"), i18n.T("msg.import"), i18n.T("msg.cancel"), container.NewBorder(widget.NewLabel(summary), nil, nil, nil, scroll), func(ok bool) {
+		if ok {
+			apply()
+		}
+	}, w)
+	d.Show()
+}
@@ -0,0 +1,84 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+const (
+	// indexCheckDelay keeps the consistency check out of the way of the initial index build and the
+	// first interactions after a project is opened.
+	indexCheckDelay = 10 * time.Second
+	// indexCheckTimeout bounds a check including a full rebuild of the documents.
+	indexCheckTimeout = 60 * time.Second
+	// indexDriftStatusFor is how long the status message about a repaired index stays.
+	indexDriftStatusFor = 8 * time.Second
+)
+
+// indexCheck is a pending or running background check of the index against the open project.
+type indexCheck struct {
+	cancel context.CancelFunc
+}
+
+// startIndexCheck waits delay, then compares the index of ix with the project returned by current and
+// repairs the rows that drifted (e.g. after comic.json was edited outside the app or the app
+// stopped between saving and updating the index). current returns false when the project is no
+// longer open. onRepaired is called with the drift when anything was repaired. Both run on the
+// check's goroutine.
+func startIndexCheck(ix *storage.IndexHandle, delay time.Duration, l *slog.Logger, current func() (domain.Project, bool), onRepaired func(storage.IndexDrift)) *indexCheck {
+	ctx, cancel := context.WithCancel(context.Background())
+	l = l.With(slog.String("component", "index_check"))
+	go func() {
+		defer cancel()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		proj, ok := current()
+		if !ok {
+			return
+		}
+		cctx, ccancel := context.WithTimeout(ctx, indexCheckTimeout)
+		defer ccancel()
+		d, err := ix.RepairIndexConsistency(cctx, proj)
+		if err != nil {
+			if ctx.Err() == nil {
+				l.Warn("index consistency check failed", slog.Any("err", err))
+			}
+			return
+		}
+		if !d.Repaired {
+			l.Debug("index consistent")
+			return
+		}
+		l.Info("index drift repaired", slog.Int("added", len(d.Added)), slog.Int("removed", len(d.Removed)),
+			slog.Int("changed", len(d.Changed)), slog.Bool("missing_fingerprints", d.MissingFingerprints), slog.Bool("rebuilt", d.Rebuilt))
+		onRepaired(d)
+	}()
+	return &indexCheck{cancel: cancel}
+}
+
+// Close cancels the check if it has not finished; it does not wait for a running repair to stop.
+// It is safe on a nil check.
+func (c *indexCheck) Close() {
+	if c != nil {
+		c.cancel()
+	}
+}
@@ -0,0 +1,67 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// offerIndexReset handles err when it is storage.ErrIndexNewerThanApp: it explains that a newer app
+// version wrote the index and offers to rebuild it for this version, then calls onRebuilt. It reports
+// whether err was handled.
+func offerIndexReset(w fyne.Window, ph *storage.ProjectHandle, err error, l *slog.Logger, status *widget.Label, onRebuilt func()) bool {
+	if !errors.Is(err, storage.ErrIndexNewerThanApp) || ph == nil {
+		return false
+	}
+	l.Warn("index written by a newer app version", slog.Any("err", err))
+	msg := widget.NewLabel(i18n.T("msg.index_newer_than_app_detail", err.Error()))
+	msg.Wrapping = fyne.TextWrapWord
+	d := dialog.NewCustomConfirm(i18n.T("msg.index_newer_than_app"), i18n.T("button.rebuild_index_for_app"), i18n.T("msg.cancel"), msg, func(ok bool) {
+		if !ok {
+			return
+		}
+		status.SetText(i18n.T("status.rebuilding_index"))
+		go func(ix *storage.IndexHandle, proj domain.Project) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+			err := ix.ResetIndex(ctx, proj)
+			fyne.Do(func() {
+				if err != nil {
+					l.Error("reset index failed", slog.Any("err", err))
+					dialog.ShowError(FriendlyError(err), w)
+					status.SetText(i18n.T("status.rebuild_failed"))
+					return
+				}
+				l.Info("index rebuilt for this app version")
+				status.SetText(i18n.T("status.index_rebuilt"))
+				if onRebuilt != nil {
+					onRebuilt()
+				}
+			})
+		}(ph.Index(), ph.Project)
+	}, w)
+	d.Resize(fyne.NewSize(560, 0))
+	d.Show()
+	return true
+}
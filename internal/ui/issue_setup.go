@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/export"
+	"gocomicwriter/internal/i18n"
+)
+
+// parseIssueSize reads the size fields of the Issue Setup dialog, typed in unit u, into the trim,
+// bleed and DPI of an issue. Width, height and DPI must be positive and the bleed zero or more.
+func parseIssueSize(u lengthUnit, width, height, bleed, dpi string) (domain.Issue, error) {
+	w, errW := u.parse(width)
+	h, errH := u.parse(height)
+	b, errB := u.parse(bleed)
+	d, errD := strconv.Atoi(strings.TrimSpace(dpi))
+	if errW != nil || errH != nil || errB != nil || errD != nil || w <= 0 || h <= 0 || b < 0 || d <= 0 {
+		return domain.Issue{}, errors.New(i18n.T("msg.invalid_issue_setup"))
+	}
+	return domain.Issue{TrimWidth: w, TrimHeight: h, Bleed: b, DPI: d}, nil
+}
+
+// checkIssuePixels rejects an issue whose pages including bleed would be larger at its DPI than the
+// raster exports render, which is usually a typo such as 3500 mm for 350 mm.
+func checkIssuePixels(iss domain.Issue) error {
+	w, h := export.MediaPixels(iss, iss.DPI)
+	if export.CheckPixels("page", w, h, iss.DPI) != nil {
+		return errors.New(i18n.T("msg.issue_too_large", w, h, iss.DPI, export.MaxPixels()))
+	}
+	return nil
+}
+
+// issuePixelPreview is the line under the Issue Setup size fields with the pixel size of a page
+// including bleed, updated while typing.
+func issuePixelPreview(u lengthUnit, width, height, bleed, dpi string) string {
+	iss, err := parseIssueSize(u, width, height, bleed, dpi)
+	if err != nil {
+		return i18n.T("label.issue_pixels_incomplete")
+	}
+	w, h := export.MediaPixels(iss, iss.DPI)
+	if checkIssuePixels(iss) != nil {
+		return i18n.T("label.issue_pixels_too_large", w, h, iss.DPI, export.MaxPixels())
+	}
+	return i18n.T("label.issue_pixels", w, h, iss.DPI)
+}
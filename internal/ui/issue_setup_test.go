@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/export"
+)
+
+func TestParseIssueSize(t *testing.T) {
+	iss, err := parseIssueSize(unitInch, "6.625", "10.25", "0.125", "300")
+	if err != nil || iss.TrimWidth != 477 || iss.TrimHeight != 738 || iss.Bleed != 9 || iss.DPI != 300 {
+		t.Fatalf("inch issue = %+v, %v", iss, err)
+	}
+	if iss, err := parseIssueSize(unitMM, "210", "297", "0", "600"); err != nil || iss.DPI != 600 || iss.Bleed != 0 {
+		t.Fatalf("mm issue = %+v, %v", iss, err)
+	}
+	for _, bad := range [][4]string{{"0", "297", "3", "300"}, {"210", "297", "-3", "300"}, {"210", "x", "3", "300"}, {"210", "297", "3", "0"}} {
+		if _, err := parseIssueSize(unitMM, bad[0], bad[1], bad[2], bad[3]); err == nil {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+}
+
+func TestIssuePixelLimit(t *testing.T) {
+	t.Cleanup(func() { export.SetMaxPixels(0) })
+	iss, _ := parseIssueSize(unitInch, "6.625", "10.25", "0.125", "300")
+	if err := checkIssuePixels(iss); err != nil {
+		t.Fatalf("comic page rejected: %v", err)
+	}
+	if got := issuePixelPreview(unitInch, "6.625", "10.25", "0.125", "300"); got != "Pages export at 2063 × 3150 px including bleed at 300 dpi." {
+		t.Errorf("preview = %q", got)
+	}
+	// 3500 mm instead of 350 mm
+	huge, _ := parseIssueSize(unitMM, "3500", "260", "3", "300")
+	err := checkIssuePixels(huge)
+	if err == nil || !strings.Contains(err.Error(), "41409 × 3142 px at 300 dpi") || !strings.Contains(err.Error(), "20000 px") {
+		t.Fatalf("huge page: %v", err)
+	}
+	if got := issuePixelPreview(unitMM, "3500", "260", "3", "300"); !strings.Contains(got, "above the limit") {
+		t.Errorf("huge preview = %q", got)
+	}
+	if got := issuePixelPreview(unitMM, "", "260", "3", "300"); !strings.HasPrefix(got, "Enter the trim size") {
+		t.Errorf("incomplete preview = %q", got)
+	}
+	export.SetMaxPixels(2000)
+	if checkIssuePixels(iss) == nil {
+		t.Error("lower pixel limit not applied")
+	}
+}
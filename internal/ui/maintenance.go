@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// gcCategoryLabel names a garbage collection category in the Project Maintenance dialog.
+func gcCategoryLabel(c storage.GCCategory) string {
+	switch c {
+	case storage.GCPreviews:
+		return i18n.T("maintenance.previews")
+	case storage.GCAssetRows:
+		return i18n.T("maintenance.asset_rows")
+	case storage.GCTempFiles:
+		return i18n.T("maintenance.temp_files")
+	case storage.GCBackups:
+		return i18n.T("maintenance.backups")
+	case storage.GCCrashAutosaves:
+		return i18n.T("maintenance.crash_autosaves")
+	}
+	return string(c)
+}
+
+// formatBytes formats a size as "512 B", "1.5 KB" or "2.3 MB".
+func formatBytes(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d B", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	}
+}
+
+// gcSummary describes a garbage collection report per category, with the total at the end.
+func gcSummary(rep storage.GCReport) string {
+	if len(rep.Items) == 0 {
+		return i18n.T("status.nothing_to_clean_up")
+	}
+	var b strings.Builder
+	for _, t := range rep.Totals {
+		if t.Items == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %d (%s)\n", gcCategoryLabel(t.Category), t.Items, formatBytes(t.Bytes))
+	}
+	key := "maintenance.reclaimed"
+	if rep.DryRun {
+		key = "maintenance.reclaimable"
+	}
+	b.WriteString(i18n.N(key, len(rep.Items), formatBytes(rep.Bytes()), len(rep.Items)))
+	return b.String()
+}
@@ -0,0 +1,94 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// showMaintenanceDialog runs a dry garbage collection of the project and lists what
+// would be removed; only after confirmation are the items actually deleted.
+func showMaintenanceDialog(w fyne.Window, ph *storage.ProjectHandle, l *slog.Logger, status *widget.Label) {
+	if ph == nil {
+		return
+	}
+	ph.Index()
+	h := *ph
+	status.SetText(i18n.T("status.scanning_for_orphaned_data"))
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		rep, err := storage.GarbageCollect(ctx, &h, storage.GCOptions{DryRun: true})
+		fyne.Do(func() {
+			if err != nil {
+				l.Error("maintenance scan failed", slog.Any("err", err))
+				dialog.ShowError(FriendlyError(err), w)
+				status.SetText(i18n.T("status.maintenance_scan_failed"))
+				return
+			}
+			if len(rep.Items) == 0 {
+				status.SetText(i18n.T("status.nothing_to_clean_up"))
+				dialog.ShowInformation(i18n.T("msg.project_maintenance"), i18n.T("status.nothing_to_clean_up"), w)
+				return
+			}
+			status.SetText(i18n.T("status.reclaimable", formatBytes(rep.Bytes())))
+			items := widget.NewList(
+				func() int { return len(rep.Items) },
+				func() fyne.CanvasObject { return widget.NewLabel("") },
+				func(i widget.ListItemID, o fyne.CanvasObject) {
+					it := rep.Items[i]
+					o.(*widget.Label).SetText(fmt.Sprintf("%s  (%s)", it.Path, formatBytes(it.Bytes)))
+				},
+			)
+			scroll := container.NewVScroll(items)
+			scroll.SetMinSize(fyne.NewSize(520, 240))
+			note := widget.NewLabel(i18n.T("label.user_content_under_assets_pages_and"))
+			note.Wrapping = fyne.TextWrapWord
+			content := container.NewBorder(widget.NewLabel(gcSummary(rep)), note, nil, nil, scroll)
+			d := dialog.NewCustomConfirm(i18n.T("msg.project_maintenance"), i18n.T("msg.remove"), i18n.T("msg.cancel"), content, func(ok bool) {
+				if !ok {
+					return
+				}
+				l.Info("maintenance: removing orphaned data", slog.Int("items", len(rep.Items)))
+				status.SetText(i18n.T("status.removing_orphaned_data"))
+				go func() {
+					ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+					defer cancel()
+					done, err := storage.GarbageCollect(ctx, &h, storage.GCOptions{})
+					fyne.Do(func() {
+						if err != nil {
+							l.Error("maintenance failed", slog.Any("err", err))
+							dialog.ShowError(FriendlyError(err), w)
+							status.SetText(i18n.T("status.maintenance_failed"))
+							return
+						}
+						status.SetText(i18n.T("status.reclaimed", formatBytes(done.Bytes())))
+						dialog.ShowInformation(i18n.T("msg.project_maintenance"), gcSummary(done), w)
+					})
+				}()
+			}, w)
+			d.Show()
+		})
+	}()
+}
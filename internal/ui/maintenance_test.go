@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/storage"
+)
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{0: "0 B", 512: "512 B", 1536: "1.5 KB", 5 << 20: "5.0 MB"} {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestGCSummary(t *testing.T) {
+	if got := gcSummary(storage.GCReport{DryRun: true}); got != "Nothing to clean up." {
+		t.Fatalf("empty summary = %q", got)
+	}
+	rep := storage.GCReport{
+		DryRun: true,
+		Items:  make([]storage.GCItem, 3),
+		Totals: []storage.GCCategoryTotal{
+			{Category: storage.GCPreviews, Items: 2, Bytes: 2048},
+			{Category: storage.GCAssetRows},
+			{Category: storage.GCBackups, Items: 1, Bytes: 100},
+		},
+	}
+	want := "Previews of deleted pages: 2 (2.0 KB)\nBackups beyond retention: 1 (100 B)\nReclaimable: 2.1 KB in 3 items"
+	if got := gcSummary(rep); got != want {
+		t.Fatalf("summary =\n%s\nwant\n%s", got, want)
+	}
+	rep.DryRun = false
+	if got := gcSummary(rep); got[len(got)-len("Reclaimed: 2.1 KB in 3 items"):] != "Reclaimed: 2.1 KB in 3 items" {
+		t.Fatalf("summary = %q", got)
+	}
+}
@@ -0,0 +1,104 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"log/slog"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// comicInfoAgeRatings are the AgeRating values of the ComicInfo schema, offered as suggestions; any
+// other text is kept as typed.
+var comicInfoAgeRatings = []string{
+	"Everyone", "Everyone 10+", "Early Childhood", "Kids to Adults", "G", "PG", "Teen",
+	"M", "MA15+", "Mature 17+", "R18+", "Adults Only 18+", "X18+", "Rating Pending", "Unknown",
+}
+
+// showProjectMetadataDialog edits the project metadata that CBZ and EPUB exports embed, plus the
+// export number of the issue at issueIdx. The project is saved (and so re-indexed) on confirm.
+func showProjectMetadataDialog(w fyne.Window, ph *storage.ProjectHandle, issueIdx int, l *slog.Logger, status *widget.Label) {
+	m := ph.Project.Metadata
+	entry := func(text, placeholder string) *widget.Entry {
+		e := widget.NewEntry()
+		e.SetText(text)
+		e.SetPlaceHolder(placeholder)
+		return e
+	}
+	seriesEntry := entry(m.Series, ph.Project.Name)
+	titleEntry := entry(m.IssueTitle, i18n.T("form.issue_title"))
+	creatorsEntry := entry(m.Creators, i18n.T("placeholder.creators_example"))
+	genreEntry := entry(m.Genre, i18n.T("placeholder.genre_example"))
+	ageEntry := widget.NewSelectEntry(comicInfoAgeRatings)
+	ageEntry.SetText(m.AgeRating)
+	ageEntry.SetPlaceHolder(i18n.T("placeholder.not_rated"))
+	webEntry := entry(m.Web, "https://…")
+	webEntry.Validator = func(s string) error { return storage.ValidateMetadata(domain.Metadata{Web: s}) }
+	langEntry := entry(m.LanguageISO, "en")
+	langEntry.Validator = func(s string) error { return storage.ValidateMetadata(domain.Metadata{LanguageISO: s}) }
+	notesEntry := widget.NewMultiLineEntry()
+	notesEntry.SetText(m.Notes)
+	notesEntry.SetMinRowsVisible(3)
+	var numberEntry *widget.Entry
+	items := []*widget.FormItem{
+		widget.NewFormItem(i18n.T("form.series"), seriesEntry),
+		widget.NewFormItem(i18n.T("form.issue_title"), titleEntry),
+		widget.NewFormItem(i18n.T("form.creators"), creatorsEntry),
+		widget.NewFormItem(i18n.T("form.genre"), genreEntry),
+		{Text: i18n.T("form.age_rating"), Widget: ageEntry, HintText: i18n.T("form.age_rating_hint")},
+		{Text: i18n.T("form.web"), Widget: webEntry, HintText: i18n.T("form.web_hint")},
+		{Text: i18n.T("form.language"), Widget: langEntry, HintText: i18n.T("form.language_hint")},
+	}
+	if issueIdx >= 0 && issueIdx < len(ph.Project.Issues) {
+		numberEntry = widget.NewEntry()
+		numberEntry.SetText(ph.Project.Issues[issueIdx].Number)
+		numberEntry.SetPlaceHolder(strconv.Itoa(issueIdx + 1))
+		items = append(items, &widget.FormItem{Text: i18n.T("form.issue_number"), Widget: numberEntry, HintText: i18n.T("form.issue_number_hint")})
+	}
+	items = append(items, &widget.FormItem{Text: i18n.T("form.notes"), Widget: notesEntry, HintText: i18n.T("form.notes_hint")})
+	form := dialog.NewForm(i18n.T("msg.project_metadata"), i18n.T("msg.save"), i18n.T("msg.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		next := domain.Metadata{
+			Series: seriesEntry.Text, IssueTitle: titleEntry.Text, Creators: creatorsEntry.Text, Notes: notesEntry.Text,
+			Genre: genreEntry.Text, AgeRating: ageEntry.Text, Web: webEntry.Text, LanguageISO: langEntry.Text,
+		}
+		if err := storage.SetProjectMetadata(ph, next); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		if numberEntry != nil {
+			if err := storage.SetIssueNumber(ph, issueIdx, numberEntry.Text); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+		}
+		if err := storage.Save(ph); err != nil {
+			l.Error("save project metadata failed", slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		l.Info("project metadata updated")
+		status.SetText(i18n.T("status.project_metadata_updated"))
+	}, w)
+	form.Resize(fyne.NewSize(560, 0))
+	form.Show()
+}
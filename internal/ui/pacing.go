@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"slices"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// Pacing chart geometry in pixels: one column per page, bars grow up from the baseline with
+// pacingBeatHeight per beat, clamped to pacingChartHeight.
+const (
+	pacingColumnWidth = 18
+	pacingColumnGap   = 4
+	pacingChartHeight = 160
+	pacingBeatHeight  = 16
+)
+
+// pacingBar is the column of one page in the pacing chart.
+type pacingBar struct {
+	X, H     float32
+	Page     storage.PagePacing
+	Turn     bool // page turn: marked under the bar
+	Hatched  bool // the page has unmapped beats
+	TurnBeat bool // the page ends on a beat before a turn
+}
+
+// pacingBars lays out the columns of the issue's pages from left to right.
+func pacingBars(ip storage.IssuePacing) []pacingBar {
+	out := make([]pacingBar, 0, len(ip.Pages))
+	for i, pp := range ip.Pages {
+		h := float32(min(pp.Beats*pacingBeatHeight, pacingChartHeight))
+		out = append(out, pacingBar{
+			X: float32(i * (pacingColumnWidth + pacingColumnGap)), H: h, Page: pp,
+			Turn: pp.IsTurn, Hatched: pp.UnmappedBeats > 0, TurnBeat: pp.TurnBeat(),
+		})
+	}
+	return out
+}
+
+// pacingChartWidth is the width of the chart of n pages.
+func pacingChartWidth(n int) float32 {
+	if n <= 0 {
+		return 0
+	}
+	return float32(n*(pacingColumnWidth+pacingColumnGap) - pacingColumnGap)
+}
+
+// pacingPageAt returns the page number of the column at x, or 0 between and outside the columns.
+func pacingPageAt(ip storage.IssuePacing, x float32) int {
+	if x < 0 {
+		return 0
+	}
+	i := int(x) / (pacingColumnWidth + pacingColumnGap)
+	if i >= len(ip.Pages) || int(x)%(pacingColumnWidth+pacingColumnGap) >= pacingColumnWidth {
+		return 0
+	}
+	return ip.Pages[i].PageNumber
+}
+
+// pacingSummary is the issue-level summary under the pacing chart.
+func pacingSummary(ip storage.IssuePacing) string {
+	if len(ip.Pages) == 0 {
+		return i18n.T("pacing.no_pages")
+	}
+	s := i18n.N("pacing.summary", len(ip.Pages), len(ip.Pages), ip.TotalBeats, ip.AvgBeatsPerPage)
+	if ip.LongestRunWithoutTurnBeat > 0 {
+		last := ip.RunStart
+		if i := slices.IndexFunc(ip.Pages, func(pp storage.PagePacing) bool { return pp.PageNumber == ip.RunStart }); i >= 0 {
+			last = ip.Pages[min(i+ip.LongestRunWithoutTurnBeat, len(ip.Pages))-1].PageNumber
+		}
+		if ip.LongestRunWithoutTurnBeat == 1 {
+			s += i18n.T("pacing.longest_run_one", ip.RunStart)
+		} else {
+			s += i18n.T("pacing.longest_run", ip.LongestRunWithoutTurnBeat, ip.RunStart, last)
+		}
+	}
+	if ip.UnmappedBeats > 0 {
+		s += i18n.T("pacing.unmapped", ip.UnmappedBeats)
+	}
+	return s
+}
+
+// pacingTooltip describes a page of the pacing chart.
+func pacingTooltip(pp storage.PagePacing) string {
+	s := i18n.N("pacing.tooltip", pp.Beats, pp.PageNumber, pp.Beats)
+	if pp.TurnBeat() {
+		s += i18n.T("pacing.turn_beat")
+	} else if pp.IsTurn {
+		s += i18n.T("pacing.page_turn")
+	}
+	if pp.UnmappedBeats > 0 {
+		s += i18n.T("pacing.tooltip_unmapped", pp.UnmappedBeats)
+	}
+	return s
+}
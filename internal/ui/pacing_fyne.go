@@ -0,0 +1,211 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"image/color"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	fstorage "fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+var pacingHatchColor = color.NRGBA{R: 220, G: 40, B: 40, A: 160}
+
+// pacingChart draws one bar per page: its height is the page's beat count, turn pages are marked under
+// the bar in the reader preview's turn color, and pages with unmapped beats are hatched. Tapping a bar
+// calls OnPage with its page number.
+type pacingChart struct {
+	widget.BaseWidget
+	issue   storage.IssuePacing
+	current int // page number outlined as the current page
+	OnPage  func(page int)
+	OnHover func(text string) // "" when the pointer leaves the bars
+}
+
+func newPacingChart() *pacingChart {
+	c := &pacingChart{}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// SetIssue shows the pacing of an issue with the given page outlined.
+func (c *pacingChart) SetIssue(ip storage.IssuePacing, current int) {
+	c.issue, c.current = ip, current
+	c.Refresh()
+}
+
+func (c *pacingChart) Tapped(ev *fyne.PointEvent) {
+	if n := pacingPageAt(c.issue, ev.Position.X); n > 0 && c.OnPage != nil {
+		c.OnPage(n)
+	}
+}
+
+func (c *pacingChart) MouseIn(ev *desktop.MouseEvent) { c.MouseMoved(ev) }
+
+func (c *pacingChart) MouseMoved(ev *desktop.MouseEvent) {
+	if c.OnHover == nil {
+		return
+	}
+	n := pacingPageAt(c.issue, ev.Position.X)
+	for _, pp := range c.issue.Pages {
+		if pp.PageNumber == n {
+			c.OnHover(pacingTooltip(pp))
+			return
+		}
+	}
+	c.OnHover("")
+}
+
+func (c *pacingChart) MouseOut() {
+	if c.OnHover != nil {
+		c.OnHover("")
+	}
+}
+
+func (c *pacingChart) CreateRenderer() fyne.WidgetRenderer {
+	return &pacingChartRenderer{c: c}
+}
+
+type pacingChartRenderer struct {
+	c       *pacingChart
+	objects []fyne.CanvasObject
+}
+
+func (r *pacingChartRenderer) Destroy()                     {}
+func (r *pacingChartRenderer) Objects() []fyne.CanvasObject { return r.objects }
+func (r *pacingChartRenderer) Refresh()                     { r.Layout(r.c.Size()); canvas.Refresh(r.c) }
+
+func (r *pacingChartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(pacingChartWidth(len(r.c.issue.Pages)), pacingChartHeight+24)
+}
+
+func (r *pacingChartRenderer) Layout(fyne.Size) {
+	r.objects = r.objects[:0]
+	const w = pacingColumnWidth
+	for _, b := range pacingBars(r.c.issue) {
+		bg := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+		bg.Move(fyne.NewPos(b.X, 0))
+		bg.Resize(fyne.NewSize(w, pacingChartHeight))
+		r.objects = append(r.objects, bg)
+		if b.Hatched {
+			for y := float32(0); y+w <= pacingChartHeight; y += 8 {
+				ln := canvas.NewLine(pacingHatchColor)
+				ln.Position1 = fyne.NewPos(b.X, y+w)
+				ln.Position2 = fyne.NewPos(b.X+w, y)
+				r.objects = append(r.objects, ln)
+			}
+		}
+		if b.H > 0 {
+			bar := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+			bar.Move(fyne.NewPos(b.X+2, pacingChartHeight-b.H))
+			bar.Resize(fyne.NewSize(w-4, b.H))
+			r.objects = append(r.objects, bar)
+		}
+		if b.Turn {
+			mark := canvas.NewRectangle(readerTurnColor)
+			if !b.TurnBeat {
+				// a turn without a beat on its last panel is only outlined
+				mark.FillColor = color.Transparent
+				mark.StrokeColor, mark.StrokeWidth = readerTurnColor, 1
+			}
+			mark.Move(fyne.NewPos(b.X, pacingChartHeight+2))
+			mark.Resize(fyne.NewSize(w, 5))
+			r.objects = append(r.objects, mark)
+		}
+		num := canvas.NewText(strconv.Itoa(b.Page.PageNumber), theme.Color(theme.ColorNameForeground))
+		num.TextSize = 9
+		num.Alignment = fyne.TextAlignCenter
+		num.Move(fyne.NewPos(b.X, pacingChartHeight+8))
+		num.Resize(fyne.NewSize(w, 14))
+		r.objects = append(r.objects, num)
+		if b.Page.PageNumber == r.c.current {
+			cur := canvas.NewRectangle(color.Transparent)
+			cur.StrokeColor, cur.StrokeWidth = theme.Color(theme.ColorNameForeground), 2
+			cur.Move(fyne.NewPos(b.X-1, -1))
+			cur.Resize(fyne.NewSize(w+2, pacingChartHeight+2))
+			r.objects = append(r.objects, cur)
+		}
+	}
+}
+
+// pacingPanel is the toggleable side panel of the canvas tab with the pacing chart of the current
+// issue, its summary and a CSV export of the whole report.
+type pacingPanel struct {
+	chart   *pacingChart
+	summary *widget.Label
+	report  storage.PacingReport
+	box     *fyne.Container
+}
+
+func newPacingPanel(w fyne.Window) *pacingPanel {
+	p := &pacingPanel{chart: newPacingChart(), summary: widget.NewLabel("")}
+	p.summary.Wrapping = fyne.TextWrapWord
+	legend := widget.NewLabel(i18n.T("label.bar_beats_orange_mark_page_turn"))
+	legend.Wrapping = fyne.TextWrapWord
+	legend.Importance = widget.LowImportance
+	exportBtn := widget.NewButton(i18n.T("button.export_csv"), func() { exportPacingCSV(w, p.report) })
+	scroll := container.NewHScroll(container.NewPadded(p.chart))
+	scroll.SetMinSize(fyne.NewSize(260, pacingChartHeight+40))
+	p.box = container.NewVBox(widget.NewLabel(i18n.T("label.pacing")), scroll, p.summary, legend, container.NewHBox(exportBtn))
+	return p
+}
+
+// show displays the pacing of the issue at issueIdx of rep with the given page outlined.
+func (p *pacingPanel) show(rep storage.PacingReport, issueIdx, current int) {
+	p.report = rep
+	var ip storage.IssuePacing
+	if issueIdx >= 0 && issueIdx < len(rep.Issues) {
+		ip = rep.Issues[issueIdx]
+	}
+	p.chart.SetIssue(ip, current)
+	p.summary.SetText(pacingSummary(ip))
+}
+
+// exportPacingCSV asks for a file and writes the pacing report to it as CSV.
+func exportPacingCSV(w fyne.Window, rep storage.PacingReport) {
+	if len(rep.Issues) == 0 {
+		dialog.ShowInformation(i18n.T("msg.export_pacing"), i18n.T("msg.no_project_open"), w)
+		return
+	}
+	save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		if uc == nil {
+			return
+		}
+		werr := rep.WriteCSV(uc)
+		if cerr := uc.Close(); werr == nil {
+			werr = cerr
+		}
+		if werr != nil {
+			dialog.ShowError(FriendlyError(werr), w)
+			return
+		}
+		dialog.ShowInformation(i18n.T("msg.export_pacing"), i18n.N("msg.exported_issues_to", len(rep.Issues), len(rep.Issues), uc.URI().Path()), w)
+	}, w)
+	save.SetFileName("pacing.csv")
+	save.SetFilter(fstorage.NewExtensionFileFilter([]string{".csv"}))
+	save.Show()
+}
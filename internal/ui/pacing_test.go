@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/storage"
+)
+
+func TestPacingBarsAndHitTest(t *testing.T) {
+	ip := storage.IssuePacing{Pages: []storage.PagePacing{
+		{PageNumber: 1, Beats: 2, IsTurn: true, LastPanelHasBeats: true},
+		{PageNumber: 2, UnmappedBeats: 1},
+		{PageNumber: 3, Beats: 40, IsTurn: true},
+	}}
+	bars := pacingBars(ip)
+	if len(bars) != 3 {
+		t.Fatalf("expected 3 bars, got %d", len(bars))
+	}
+	if bars[0].H != 2*pacingBeatHeight || !bars[0].TurnBeat || bars[0].Hatched {
+		t.Fatalf("bar 1: %+v", bars[0])
+	}
+	if bars[1].H != 0 || !bars[1].Hatched || bars[1].Turn {
+		t.Fatalf("bar 2: %+v", bars[1])
+	}
+	if bars[2].H != pacingChartHeight || !bars[2].Turn || bars[2].TurnBeat {
+		t.Fatalf("bar 3 should be clamped and a plain turn: %+v", bars[2])
+	}
+	step := float32(pacingColumnWidth + pacingColumnGap)
+	if pacingChartWidth(3) != 3*step-pacingColumnGap || pacingChartWidth(0) != 0 {
+		t.Fatalf("unexpected width %v", pacingChartWidth(3))
+	}
+	for _, c := range []struct {
+		x    float32
+		want int
+	}{{0, 1}, {step + 1, 2}, {step - 1, 0}, {2*step + pacingColumnWidth - 1, 3}, {3 * step, 0}, {-1, 0}} {
+		if got := pacingPageAt(ip, c.x); got != c.want {
+			t.Fatalf("pacingPageAt(%v) = %d, want %d", c.x, got, c.want)
+		}
+	}
+}
+
+func TestPacingSummary(t *testing.T) {
+	ip := storage.IssuePacing{
+		Pages:      []storage.PagePacing{{PageNumber: 1}, {PageNumber: 2}, {PageNumber: 4}, {PageNumber: 5}},
+		TotalBeats: 6, AvgBeatsPerPage: 1.5, UnmappedBeats: 2,
+		LongestRunWithoutTurnBeat: 3, RunStart: 2,
+	}
+	s := pacingSummary(ip)
+	for _, want := range []string{"4 pages · 6 beats · 1.5 beats/page", "3 pages (pages 2–5)", "Unmapped script beats: 2"} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("summary %q lacks %q", s, want)
+		}
+	}
+	ip.LongestRunWithoutTurnBeat, ip.RunStart = 1, 4
+	if s := pacingSummary(ip); !strings.Contains(s, "1 page (page 4)") {
+		t.Fatalf("unexpected summary %q", s)
+	}
+	if pacingSummary(storage.IssuePacing{}) != "No pages." {
+		t.Fatalf("empty issue summary")
+	}
+	if got := pacingTooltip(storage.PagePacing{PageNumber: 3, Beats: 2, IsTurn: true, UnmappedBeats: 1}); got != "Page 3: 2 beats · page turn · 1 unmapped" {
+		t.Fatalf("tooltip %q", got)
+	}
+}
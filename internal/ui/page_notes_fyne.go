@@ -0,0 +1,83 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// pageListRow is a row of the Pages list. A secondary tap calls OnSecondary with the absolute position
+// of the tap, where the list shows the page's context menu.
+type pageListRow struct {
+	widget.Label
+	OnSecondary func(pos fyne.Position)
+}
+
+func newPageListRow() *pageListRow {
+	r := &pageListRow{}
+	r.ExtendBaseWidget(r)
+	return r
+}
+
+// TappedSecondary implements fyne.SecondaryTappable.
+func (r *pageListRow) TappedSecondary(ev *fyne.PointEvent) {
+	if r.OnSecondary != nil {
+		r.OnSecondary(ev.AbsolutePosition)
+	}
+}
+
+// showPageNotesDialog edits the production notes of a page and saves the project; onSaved runs after
+// a successful save.
+func showPageNotesDialog(w fyne.Window, ph *storage.ProjectHandle, issueIdx, pageNumber int, l *slog.Logger, status *widget.Label, onSaved func()) {
+	notesEntry := widget.NewMultiLineEntry()
+	notesEntry.SetPlaceHolder(i18n.T("placeholder.page_notes"))
+	notesEntry.SetMinRowsVisible(6)
+	notesEntry.Wrapping = fyne.TextWrapWord
+	if issueIdx >= 0 && issueIdx < len(ph.Project.Issues) {
+		for _, pg := range ph.Project.Issues[issueIdx].Pages {
+			if pg.Number == pageNumber {
+				notesEntry.SetText(pg.Notes)
+			}
+		}
+	}
+	form := dialog.NewForm(i18n.T("msg.page_notes", pageNumber), i18n.T("msg.save"), i18n.T("msg.cancel"), []*widget.FormItem{
+		widget.NewFormItem(i18n.T("form.notes"), notesEntry),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := storage.SetPageNotes(ph, issueIdx, pageNumber, notesEntry.Text); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		if err := storage.Save(ph); err != nil {
+			l.Error("save page notes failed", slog.Int("page", pageNumber), slog.Any("err", err))
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		l.Info("page notes updated", slog.Int("page", pageNumber))
+		status.SetText(i18n.T("status.page_notes_saved", pageNumber))
+		if onSaved != nil {
+			onSaved()
+		}
+	}, w)
+	form.Resize(fyne.NewSize(520, 0))
+	form.Show()
+}
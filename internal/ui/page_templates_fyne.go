@@ -0,0 +1,178 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// The choices below are message keys; the dialogs show and compare them translated.
+const (
+	templateMerge   = "option.template_merge"
+	templateReplace = "option.template_replace"
+)
+
+// showSavePageTemplate asks for a name and stores the panel layout of the page as a page template of
+// the project. An existing template of the same name is only replaced after confirmation.
+func showSavePageTemplate(w fyne.Window, ph *storage.ProjectHandle, issueIdx, pageNumber int, l *slog.Logger, status *widget.Label) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder(i18n.T("placeholder.e_g_five_panels_wide_bottom"))
+	dialog.ShowForm(i18n.T("msg.save_page_as_template", pageNumber), i18n.T("msg.save"), i18n.T("msg.cancel"), []*widget.FormItem{
+		widget.NewFormItem(i18n.T("form.name"), nameEntry),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		t, err := storage.PageTemplateFromPage(ph.Project.Issues[issueIdx], pageNumber, nameEntry.Text)
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		save := func() {
+			if err := storage.PutPageTemplate(ph, t); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			if err := storage.Save(ph); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			l.Info("page template saved", slog.String("template", t.Name), slog.Int("page", pageNumber), slog.Int("panels", len(t.Panels)))
+			status.SetText(i18n.T("status.saved_page_as_template_panels", pageNumber, t.Name, len(t.Panels)))
+		}
+		if old, exists := storage.FindPageTemplate(ph, t.Name); exists {
+			dialog.ShowConfirm(i18n.T("msg.save_page_as_template_plain"), i18n.T("msg.replace_the_template", old.Name), func(ok bool) {
+				if ok {
+					save()
+				}
+			}, w)
+			return
+		}
+		save()
+	}, w)
+}
+
+// showApplyPageTemplate lets the user pick a page template and creates its panels on the page. When the
+// page already has panels it asks whether to keep or replace them. beforeChange runs right before the
+// page is changed, e.g. to record an undo snapshot; onApplied runs after the project was saved.
+func showApplyPageTemplate(w fyne.Window, ph *storage.ProjectHandle, issueIdx, pageNumber int, l *slog.Logger, status *widget.Label, beforeChange, onApplied func()) {
+	if len(ph.Project.PageTemplates) == 0 {
+		dialog.ShowInformation(i18n.T("msg.apply_page_template"), i18n.T("msg.this_project_has_no_page_templates"), w)
+		return
+	}
+	iss := &ph.Project.Issues[issueIdx]
+	names := templateNames(ph.Project.PageTemplates)
+	info := widget.NewLabel("")
+	sel := widget.NewSelect(names, func(name string) {
+		if t, ok := storage.FindPageTemplate(ph, name); ok {
+			info.SetText(i18n.N("label.template_panels", len(t.Panels)))
+		}
+	})
+	sel.SetSelected(names[0])
+	items := []*widget.FormItem{
+		widget.NewFormItem(i18n.T("form.template"), container.NewVBox(sel, info)),
+	}
+	mode := widget.NewRadioGroup([]string{i18n.T(templateMerge), i18n.T(templateReplace)}, nil)
+	mode.SetSelected(i18n.T(templateMerge))
+	existing := 0
+	for _, pg := range iss.Pages {
+		if pg.Number == pageNumber {
+			existing = len(pg.Panels)
+		}
+	}
+	if existing > 0 {
+		items = append(items, widget.NewFormItem(i18n.N("form.page_has_panels", existing), mode))
+	}
+	dialog.ShowForm(i18n.T("msg.apply_page_template_to_page", pageNumber), i18n.T("msg.apply"), i18n.T("msg.cancel"), items, func(ok bool) {
+		if !ok || sel.Selected == "" {
+			return
+		}
+		t, found := storage.FindPageTemplate(ph, sel.Selected)
+		if !found {
+			return
+		}
+		replace := existing > 0 && mode.Selected == i18n.T(templateReplace)
+		apply := func() {
+			if beforeChange != nil {
+				beforeChange()
+			}
+			created, err := storage.ApplyPageTemplate(ph, iss, pageNumber, t, replace)
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			if err := storage.Save(ph); err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			l.Info("page template applied", slog.String("template", t.Name), slog.Int("page", pageNumber), slog.Int("panels", len(created)), slog.Bool("replace", replace))
+			status.SetText(i18n.T("status.applied_template_to_page_panels", t.Name, pageNumber, len(created)))
+			if onApplied != nil {
+				onApplied()
+			}
+		}
+		if replace {
+			dialog.ShowConfirm(i18n.T("msg.apply_page_template"), i18n.N("msg.remove_page_panels", existing, existing, pageNumber), func(ok bool) {
+				if ok {
+					apply()
+				}
+			}, w)
+			return
+		}
+		apply()
+	}, w)
+}
+
+// showDeletePageTemplate lets the user pick a page template to remove from the project.
+func showDeletePageTemplate(w fyne.Window, ph *storage.ProjectHandle, l *slog.Logger, status *widget.Label) {
+	if len(ph.Project.PageTemplates) == 0 {
+		dialog.ShowInformation(i18n.T("msg.delete_page_template"), i18n.T("msg.there_are_no_page_templates_to"), w)
+		return
+	}
+	names := templateNames(ph.Project.PageTemplates)
+	sel := widget.NewSelect(names, nil)
+	sel.SetSelected(names[0])
+	dialog.ShowCustomConfirm(i18n.T("msg.delete_page_template"), i18n.T("button.delete"), i18n.T("msg.cancel"), sel, func(ok bool) {
+		if !ok || sel.Selected == "" {
+			return
+		}
+		if err := storage.DeletePageTemplate(ph, sel.Selected); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		if err := storage.Save(ph); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		l.Info("page template deleted", slog.String("template", sel.Selected))
+		status.SetText(i18n.T("status.deleted_page_template", sel.Selected))
+	}, w)
+}
+
+// templateNames lists the names of the page templates in project order.
+func templateNames(list []domain.PageTemplate) []string {
+	names := make([]string, 0, len(list))
+	for _, t := range list {
+		names = append(names, t.Name)
+	}
+	return names
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/vector"
+)
+
+// Border style choices of the panel border dialog; the first inherits the style.
+const (
+	borderStyleInherit = "Inherit"
+	borderStyleSolid   = "Solid"
+	borderStyleNone    = "None (borderless)"
+)
+
+var borderStyleOptions = []string{borderStyleInherit, borderStyleSolid, borderStyleNone}
+
+// editorPanelStroke is the panel outline on the canvas when no border is configured.
+var editorPanelStroke = vector.Stroke{Enabled: true, Color: vector.Color{R: 40, G: 40, B: 40, A: 255}, Width: 1}
+
+// borderStyleLabel returns the dialog choice for a stored border style.
+func borderStyleLabel(style string) string {
+	switch style {
+	case domain.PanelBorderSolid:
+		return borderStyleSolid
+	case domain.PanelBorderNone:
+		return borderStyleNone
+	}
+	return borderStyleInherit
+}
+
+// parsePanelBorder builds a border from the dialog fields. A blank width or color inherits; nil is
+// returned when every field inherits.
+func parsePanelBorder(styleLabel, width, hex string) (*domain.PanelBorder, error) {
+	var b domain.PanelBorder
+	switch styleLabel {
+	case borderStyleSolid:
+		b.Style = domain.PanelBorderSolid
+	case borderStyleNone:
+		b.Style = domain.PanelBorderNone
+	}
+	if s := strings.TrimSpace(width); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("border width %q must be a positive number of points", s)
+		}
+		b.Width = v
+	}
+	if s := strings.TrimSpace(hex); s != "" {
+		c, err := parseHexColor(s)
+		if err != nil {
+			return nil, err
+		}
+		b.Color = &c
+	}
+	if b.Style == "" && b.Width == 0 && b.Color == nil {
+		return nil, nil
+	}
+	return &b, nil
+}
+
+// parseHexColor reads an opaque color written as #rrggbb or rrggbb.
+func parseHexColor(s string) (domain.Color, error) {
+	h := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	v, err := strconv.ParseUint(h, 16, 32)
+	if len(h) != 6 || err != nil {
+		return domain.Color{}, fmt.Errorf("color %q is not of the form #rrggbb", s)
+	}
+	return domain.Color{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}, nil
+}
+
+// formatHexColor writes c as #rrggbb, or "" for nil.
+func formatHexColor(c *domain.Color) string {
+	if c == nil {
+		return ""
+	}
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// panelCanvasStroke is the outline PageCanvas draws for pn: the color and width of its resolved border,
+// the editor's dark hairline where none is set, and a faint hairline for borderless panels so they can
+// still be seen and selected.
+func panelCanvasStroke(projectDefault *domain.PanelBorder, pn domain.Panel) vector.Stroke {
+	b := storage.ResolvePanelBorder(projectDefault, pn.Border)
+	s := editorPanelStroke
+	if b.Style == domain.PanelBorderNone {
+		s.Color.A = 60
+		return s
+	}
+	if b.Color != nil {
+		s.Color = vector.Color{R: b.Color.R, G: b.Color.G, B: b.Color.B, A: b.Color.A}
+	}
+	if b.Width > 0 {
+		s.Width = float32(b.Width)
+	}
+	return s
+}
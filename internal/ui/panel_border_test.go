@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/vector"
+)
+
+func TestParsePanelBorder(t *testing.T) {
+	if b, err := parsePanelBorder(borderStyleInherit, " ", ""); err != nil || b != nil {
+		t.Fatalf("all inherited: %+v, %v", b, err)
+	}
+	b, err := parsePanelBorder(borderStyleSolid, "2.5", "#FF8000")
+	if err != nil || b == nil {
+		t.Fatalf("solid: %+v, %v", b, err)
+	}
+	if b.Style != domain.PanelBorderSolid || b.Width != 2.5 || *b.Color != (domain.Color{R: 255, G: 128, A: 255}) {
+		t.Fatalf("solid: %+v %+v", b, *b.Color)
+	}
+	if got := formatHexColor(b.Color); got != "#ff8000" {
+		t.Fatalf("format: %s", got)
+	}
+	if b, err := parsePanelBorder(borderStyleNone, "", ""); err != nil || b.Style != domain.PanelBorderNone {
+		t.Fatalf("none: %+v, %v", b, err)
+	}
+	for _, bad := range [][2]string{{"0", ""}, {"-1", ""}, {"thick", ""}, {"", "red"}, {"", "#12345"}} {
+		if _, err := parsePanelBorder(borderStyleInherit, bad[0], bad[1]); err == nil {
+			t.Fatalf("accepted width %q color %q", bad[0], bad[1])
+		}
+	}
+	for _, s := range []string{"", domain.PanelBorderSolid, domain.PanelBorderNone} {
+		if got, _ := parsePanelBorder(borderStyleLabel(s), "1", ""); got.Style != s {
+			t.Fatalf("style %q round trip: %q", s, got.Style)
+		}
+	}
+}
+
+func TestPanelCanvasStroke(t *testing.T) {
+	if s := panelCanvasStroke(nil, domain.Panel{}); s != editorPanelStroke {
+		t.Fatalf("default: %+v", s)
+	}
+	red := domain.Color{R: 255, A: 255}
+	s := panelCanvasStroke(&domain.PanelBorder{Color: &red}, domain.Panel{Border: &domain.PanelBorder{Width: 3}})
+	if s.Color != (vector.Color{R: 255, A: 255}) || s.Width != 3 || !s.Enabled {
+		t.Fatalf("styled: %+v", s)
+	}
+	s = panelCanvasStroke(nil, domain.Panel{Border: &domain.PanelBorder{Style: domain.PanelBorderNone}})
+	if !s.Enabled || s.Color.A >= editorPanelStroke.Color.A {
+		t.Fatalf("borderless panels stay faintly visible: %+v", s)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"math"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/vector"
+)
+
+// panelDrawMinSize is the smallest width and height in points of a panel drawn on the canvas; smaller
+// drags are taken for slips and create nothing.
+const panelDrawMinSize = 12
+
+// panelDrawAnchors are the edges a drawn panel snaps to: the page's trim box and its panels.
+func panelDrawAnchors(trim vector.Rect, panels []vector.Rect) []vector.Anchor {
+	out := make([]vector.Anchor, 0, len(panels)+1)
+	out = append(out, vector.Anchor{Rect: trim, Weight: 1})
+	for _, r := range panels {
+		out = append(out, vector.Anchor{Rect: r, Weight: 1})
+	}
+	return out
+}
+
+// snapDrawPoint moves a corner of a drawn panel onto the nearest anchor edge within threshold, in X
+// and Y independently.
+func snapDrawPoint(pt vector.Pt, anchors []vector.Anchor, threshold float32) vector.Pt {
+	r, _ := vector.ComputeSmartGuides(vector.R(pt.X, pt.Y, 0, 0), anchors, vector.SnapOptions{Threshold: threshold, SnapToEdges: true})
+	return vector.Pt{X: r.X, Y: r.Y}
+}
+
+// drawnPanelRect is the panel spanned by a drag from start to cur, both corners snapped to the anchors.
+func drawnPanelRect(start, cur vector.Pt, anchors []vector.Anchor, threshold float32) vector.Rect {
+	a := snapDrawPoint(start, anchors, threshold)
+	b := snapDrawPoint(cur, anchors, threshold)
+	x0, x1 := min(a.X, b.X), max(a.X, b.X)
+	y0, y1 := min(a.Y, b.Y), max(a.Y, b.Y)
+	return vector.R(x0, y0, x1-x0, y1-y0)
+}
+
+// panelDrawBigEnough reports whether a drawn rect is large enough to become a panel.
+func panelDrawBigEnough(r vector.Rect) bool {
+	return r.W >= panelDrawMinSize && r.H >= panelDrawMinSize
+}
+
+// panelDrawStatus is the status bar text while drawing a panel.
+func panelDrawStatus(r vector.Rect) string {
+	s := i18n.T("status.new_panel_size", units().formatSize(float64(r.W), float64(r.H)))
+	if !panelDrawBigEnough(r) {
+		s += i18n.T("status.new_panel_too_small")
+	}
+	return s
+}
+
+// panelDrawGeometry converts a drawn rect to panel geometry, rounded to 0.01 pt.
+func panelDrawGeometry(r vector.Rect) domain.Rect {
+	round := func(v float32) float64 { return math.Round(float64(v)*100) / 100 }
+	return domain.Rect{X: round(r.X), Y: round(r.Y), Width: round(r.W), Height: round(r.H)}
+}
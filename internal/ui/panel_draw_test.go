@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/vector"
+)
+
+func TestDrawnPanelRectSnapsToTrimAndPanels(t *testing.T) {
+	trim := vector.R(9, 9, 577, 824)
+	anchors := panelDrawAnchors(trim, []vector.Rect{vector.R(9, 9, 200, 150)})
+	// Dragged up-left from near the existing panel's right edge to near the trim corner
+	got := drawnPanelRect(vector.Pt{X: 300, Y: 155}, vector.Pt{X: 213, Y: 12}, anchors, 6)
+	want := vector.R(209, 9, 91, 150)
+	if got != want {
+		t.Fatalf("rect = %+v, want %+v", got, want)
+	}
+	// Far from all edges nothing snaps
+	got = drawnPanelRect(vector.Pt{X: 300, Y: 300}, vector.Pt{X: 400, Y: 420}, anchors, 6)
+	if got != vector.R(300, 300, 100, 120) {
+		t.Fatalf("unsnapped rect = %+v", got)
+	}
+}
+
+func TestPanelDrawMinimumSize(t *testing.T) {
+	if panelDrawBigEnough(vector.R(0, 0, 2, 2)) || panelDrawBigEnough(vector.R(0, 0, 200, 11)) {
+		t.Fatal("slips below the minimum size must not become panels")
+	}
+	if !panelDrawBigEnough(vector.R(0, 0, panelDrawMinSize, panelDrawMinSize)) {
+		t.Fatal("a rect of the minimum size is a panel")
+	}
+	if s := panelDrawStatus(vector.R(0, 0, 72, 36)); s != "New panel: 25.4 × 12.7 mm" {
+		t.Fatalf("status = %q", s)
+	}
+	if s := panelDrawStatus(vector.R(0, 0, 2, 2)); !strings.HasSuffix(s, "(too small)") {
+		t.Fatalf("status of a slip = %q", s)
+	}
+}
+
+func TestPanelDrawGeometry(t *testing.T) {
+	got := panelDrawGeometry(vector.R(10.004, 20.5, 100.126, 50))
+	if got != (domain.Rect{X: 10, Y: 20.5, Width: 100.13, Height: 50}) {
+		t.Fatalf("geometry = %+v", got)
+	}
+}
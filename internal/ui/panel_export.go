@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gocomicwriter/internal/export"
+)
+
+// parsePanelCropOptions reads the DPI and margin fields of the panel export dialog. An empty DPI
+// keeps the issue DPI; an empty margin means none.
+func parsePanelCropOptions(dpi, margin string) (export.PanelPNGOptions, error) {
+	var opt export.PanelPNGOptions
+	if s := strings.TrimSpace(dpi); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 18 || v > 2400 {
+			return opt, fmt.Errorf("DPI must be a whole number between 18 and 2400")
+		}
+		opt.DPI = v
+	}
+	if s := strings.TrimSpace(margin); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 || v > 144 {
+			return opt, fmt.Errorf("margin must be between 0 and 144 pt")
+		}
+		opt.Margin = v
+	}
+	return opt, nil
+}
@@ -0,0 +1,104 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	fstorage "fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/export"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// The choices below are message keys; the dialogs show and compare them translated.
+const (
+	panelExportOne = "option.this_panel"
+	panelExportAll = "option.all_panels_on_page"
+)
+
+// showPanelExportDialog asks for the resolution and margin of a panel crop, then for the file (one
+// panel) or folder (all panels of the page) to write it to.
+func showPanelExportDialog(w fyne.Window, ph *storage.ProjectHandle, issueIdx, pageNumber int, panelID string, l *slog.Logger) {
+	scope := widget.NewRadioGroup([]string{i18n.T(panelExportOne), i18n.T(panelExportAll)}, nil)
+	scope.SetSelected(i18n.T(panelExportOne))
+	dpiEntry := widget.NewEntry()
+	dpiEntry.SetPlaceHolder(i18n.T("placeholder.issue_dpi"))
+	marginEntry := widget.NewEntry()
+	marginEntry.SetText("0")
+	dialog.ShowForm(i18n.T("msg.export_panel_as_png_title", panelID), i18n.T("msg.export"), i18n.T("msg.cancel"), []*widget.FormItem{
+		widget.NewFormItem(i18n.T("menu.export"), scope),
+		widget.NewFormItem(i18n.T("form.dpi"), dpiEntry),
+		widget.NewFormItem(i18n.T("form.margin_pt"), marginEntry),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		opt, err := parsePanelCropOptions(dpiEntry.Text, marginEntry.Text)
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		if scope.Selected == i18n.T(panelExportAll) {
+			fd := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+				if err != nil {
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				if uri == nil {
+					return
+				}
+				written, err := export.ExportPagePanelsPNG(ph, issueIdx, pageNumber, uri.Path(), opt)
+				if err != nil {
+					l.Error("export page panels failed", slog.Int("page", pageNumber), slog.Any("err", err))
+					dialog.ShowError(FriendlyError(err), w)
+					return
+				}
+				l.Info("page panels exported", slog.Int("page", pageNumber), slog.Int("files", len(written)))
+				dialog.ShowInformation(i18n.T("msg.export_panels"), i18n.T("msg.exported_panels_to", uri.Path()), w)
+			}, w)
+			fd.Show()
+			return
+		}
+		save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			if uc == nil {
+				return
+			}
+			outPath := uc.URI().Path()
+			_ = uc.Close()
+			if !strings.HasSuffix(strings.ToLower(outPath), ".png") {
+				outPath += ".png"
+			}
+			if err := export.ExportPanelPNG(ph, issueIdx, pageNumber, panelID, outPath, opt); err != nil {
+				l.Error("export panel failed", slog.Int("page", pageNumber), slog.String("panel", panelID), slog.Any("err", err))
+				dialog.ShowError(FriendlyError(err), w)
+				return
+			}
+			l.Info("panel exported", slog.Int("page", pageNumber), slog.String("panel", panelID))
+			dialog.ShowInformation(i18n.T("msg.export_panel"), i18n.T("msg.exported_to", filepath.Base(outPath)), w)
+		}, w)
+		save.SetFileName(export.PanelCropName(pageNumber, panelID))
+		save.SetFilter(fstorage.NewExtensionFileFilter([]string{".png"}))
+		save.Show()
+	}, w)
+}
@@ -0,0 +1,29 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import "testing"
+
+func TestParsePanelCropOptions(t *testing.T) {
+	opt, err := parsePanelCropOptions(" 600 ", "9")
+	if err != nil || opt.DPI != 600 || opt.Margin != 9 {
+		t.Fatalf("got %+v, %v", opt, err)
+	}
+	opt, err = parsePanelCropOptions("", "")
+	if err != nil || opt.DPI != 0 || opt.Margin != 0 {
+		t.Fatalf("empty fields: got %+v, %v", opt, err)
+	}
+	for _, bad := range [][2]string{{"abc", ""}, {"5", ""}, {"300", "-1"}, {"300", "x"}} {
+		if _, err := parsePanelCropOptions(bad[0], bad[1]); err == nil {
+			t.Errorf("parsePanelCropOptions(%q, %q) should fail", bad[0], bad[1])
+		}
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/script"
+	"gocomicwriter/internal/storage"
+)
+
+// Auto-advance interval of the panel review in seconds: the default when none was set, the
+// range the +/- keys keep it in and their step.
+const (
+	defaultPanelReviewSeconds = 4.0
+	minPanelReviewSeconds     = 1.0
+	maxPanelReviewSeconds     = 30.0
+	panelReviewSecondsStep    = 0.5
+)
+
+// panelReviewPixels is the longer side, in pixels, panels are rendered at in the panel review.
+const panelReviewPixels = 1400
+
+// panelReviewSeconds returns the stored interval s clamped to the allowed range, or the default for 0.
+func panelReviewSeconds(s float64) float64 {
+	if s <= 0 {
+		return defaultPanelReviewSeconds
+	}
+	return min(max(s, minPanelReviewSeconds), maxPanelReviewSeconds)
+}
+
+// panelReviewInterval is the auto-advance interval for s seconds.
+func panelReviewInterval(s float64) time.Duration {
+	return time.Duration(panelReviewSeconds(s) * float64(time.Second))
+}
+
+// panelReviewSlide is one panel of the panel review and its place in the issue.
+type panelReviewSlide struct {
+	PageIdx    int
+	PageNumber int
+	PanelID    string
+	Pos        int // 1-based reading position on the page
+	OnPage     int // panels on the page
+}
+
+// panelReviewSlides lists the issue's panels in reading order across its pages: pages in issue order and
+// the panels of each page in storage.SpatialReadingOrder for the issue's reading direction, the order
+// the EPUB reflow export reads them in.
+func panelReviewSlides(iss domain.Issue) []panelReviewSlide {
+	rtl := readerRTL(iss)
+	var out []panelReviewSlide
+	for pi, pg := range iss.Pages {
+		ids := storage.SpatialReadingOrder(pg, rtl)
+		for k, id := range ids {
+			out = append(out, panelReviewSlide{PageIdx: pi, PageNumber: pg.Number, PanelID: id, Pos: k + 1, OnPage: len(ids)})
+		}
+	}
+	return out
+}
+
+// panelReviewSlideOf returns the slide of the panel panelID on the page at index pageIdx, else the first
+// slide of that page, else 0.
+func panelReviewSlideOf(slides []panelReviewSlide, pageIdx int, panelID string) int {
+	first := -1
+	for i, s := range slides {
+		if s.PageIdx != pageIdx {
+			continue
+		}
+		if s.PanelID == panelID {
+			return i
+		}
+		if first < 0 {
+			first = i
+		}
+	}
+	return max(first, 0)
+}
+
+// panelReviewScale is the pixels per point that render a panel of geometry g with its longer side at
+// panelReviewPixels.
+func panelReviewScale(g domain.Rect) float64 {
+	if side := max(g.Width, g.Height); side > 0 {
+		return panelReviewPixels / side
+	}
+	return 1
+}
+
+// panelReviewPosition is the indicator over the panel, e.g. "Page 3 · Panel 2/5 · 14/60 · Auto 4 s".
+func panelReviewPosition(s panelReviewSlide, i, n int, secs float64, playing bool) string {
+	pos := i18n.T("panel_review.position", s.PageNumber, s.Pos, s.OnPage, i+1, n)
+	if !playing {
+		return pos + " · " + i18n.T("panel_review.paused")
+	}
+	return pos + " · " + i18n.T("panel_review.auto", strconv.FormatFloat(panelReviewSeconds(secs), 'f', -1, 64))
+}
+
+// panelReviewBeat is a beat of the script with the dialogue and captions written under it.
+type panelReviewBeat struct {
+	Text  string   // "PANEL 1: text"
+	Lines []string // "NAME: text"
+}
+
+// scriptBeats parses src and returns its beats by beat ID (see storage.BeatIDFor), each with the
+// dialogue and caption lines up to the next beat or scene.
+func scriptBeats(src string) map[string]panelReviewBeat {
+	sc, _ := script.Parse(src)
+	out := map[string]panelReviewBeat{}
+	for _, scn := range sc.Scenes {
+		id := ""
+		for _, ln := range scn.Lines {
+			switch ln.Type {
+			case script.LineBeat:
+				id = storage.BeatIDFor(ln)
+				text := ln.Character
+				if t := strings.TrimSpace(strings.TrimPrefix(ln.Text, ":")); t != "" {
+					text += ": " + t
+				}
+				out[id] = panelReviewBeat{Text: text}
+			case script.LineDialogue, script.LineCaption:
+				if b, ok := out[id]; ok && id != "" {
+					b.Lines = append(b.Lines, ln.Character+": "+ln.Text)
+					out[id] = b
+				}
+			}
+		}
+	}
+	return out
+}
+
+// panelReviewText is the text under a panel in the panel review: each mapped beat followed by its dialogue.
+func panelReviewText(pn domain.Panel, beats map[string]panelReviewBeat) string {
+	if len(pn.BeatIDs) == 0 {
+		return i18n.T("panel_review.no_beats")
+	}
+	var lines []string
+	for _, id := range pn.BeatIDs {
+		b, ok := beats[id]
+		if !ok {
+			lines = append(lines, "▸ "+i18n.T("panel_review.beat_not_in_script", id))
+			continue
+		}
+		lines = append(lines, "▸ "+b.Text)
+		for _, d := range b.Lines {
+			lines = append(lines, "    "+d)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
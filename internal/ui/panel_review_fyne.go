@@ -0,0 +1,172 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+package ui
+
+import (
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/export"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// panelReviewOptions configure showPanelReview.
+type panelReviewOptions struct {
+	// Script is the script source the mapped beats are looked up in.
+	Script string
+	// Seconds is the auto-advance interval (see panelReviewSeconds); OnSeconds receives it when changed.
+	Seconds   float64
+	OnSeconds func(float64)
+	// OnExit receives the page index and panel shown last when the window closes.
+	OnExit func(pageIdx int, panelID string)
+}
+
+// showPanelReview opens a full-screen window that shows the issue's panels one at a time in reading
+// order across pages (see panelReviewSlides), starting at panelID on the page at index pageIdx. Panels are
+// rendered like panel crops with the page art under them; the mapped beats and their dialogue are
+// shown below. It is operated by keyboard only: the arrow keys step through the panels (the arrow
+// pointing in reading direction goes forward), Space starts and stops the auto-advance, +/- change its
+// interval and Esc closes the window. The window shows the issue as it was when opened.
+func showPanelReview(a fyne.App, ph *storage.ProjectHandle, issueIdx, pageIdx int, panelID string, opt panelReviewOptions) {
+	if ph == nil || issueIdx < 0 || issueIdx >= len(ph.Project.Issues) {
+		return
+	}
+	iss := ph.Project.Issues[issueIdx]
+	slides := panelReviewSlides(iss)
+	if len(slides) == 0 {
+		return
+	}
+	preview := export.NewPanelPreview(ph)
+	beats := scriptBeats(opt.Script)
+	secs := panelReviewSeconds(opt.Seconds)
+
+	win := a.NewWindow(i18n.T("msg.panel_review"))
+	img := canvas.NewImageFromImage(nil)
+	img.FillMode = canvas.ImageFillContain
+	position := widget.NewLabel("")
+	position.Alignment = fyne.TextAlignCenter
+	position.TextStyle = fyne.TextStyle{Bold: true}
+	text := widget.NewLabel("")
+	text.Wrapping = fyne.TextWrapWord
+	hint := widget.NewLabel(i18n.T("panel_review.keys"))
+	hint.Alignment = fyne.TextAlignCenter
+	hint.Importance = widget.LowImportance
+
+	cur := panelReviewSlideOf(slides, pageIdx, panelID)
+	playing, closed := false, false
+	var timer *time.Timer
+	var step func(d int)
+	// schedule (re)starts the countdown to the next panel while playing.
+	schedule := func() {
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+		if !playing {
+			return
+		}
+		timer = time.AfterFunc(panelReviewInterval(secs), func() {
+			fyne.Do(func() {
+				if !closed {
+					step(1)
+				}
+			})
+		})
+	}
+	show := func() {
+		s := slides[cur]
+		pg := iss.Pages[s.PageIdx]
+		var pn domain.Panel
+		for _, p := range pg.Panels {
+			if p.ID == s.PanelID {
+				pn = p
+			}
+		}
+		img.Image = preview.Render(iss, s.PageIdx, s.PanelID, panelReviewScale(pn.Geometry))
+		img.Refresh()
+		position.SetText(panelReviewPosition(s, cur, len(slides), secs, playing))
+		text.SetText(panelReviewText(pn, beats))
+		schedule()
+	}
+	step = func(d int) {
+		n := cur + d
+		if n < 0 || n >= len(slides) {
+			// The last panel stops the auto-advance
+			if playing && n >= len(slides) {
+				playing = false
+				show()
+			}
+			return
+		}
+		cur = n
+		show()
+	}
+	setSeconds := func(d float64) {
+		s := min(max(secs+d, minPanelReviewSeconds), maxPanelReviewSeconds)
+		if s == secs {
+			return
+		}
+		secs = s
+		if opt.OnSeconds != nil {
+			opt.OnSeconds(secs)
+		}
+		show()
+	}
+	rtl := readerRTL(iss)
+	win.Canvas().SetOnTypedKey(func(ev *fyne.KeyEvent) {
+		switch ev.Name {
+		case fyne.KeyLeft:
+			step(readerArrowStep(rtl, false))
+		case fyne.KeyRight:
+			step(readerArrowStep(rtl, true))
+		case fyne.KeyUp, fyne.KeyPageUp:
+			step(-1)
+		case fyne.KeyDown, fyne.KeyPageDown:
+			step(1)
+		case fyne.KeyHome:
+			step(-cur)
+		case fyne.KeyEnd:
+			step(len(slides) - 1 - cur)
+		case fyne.KeySpace:
+			playing = !playing
+			show()
+		case fyne.KeyPlus, fyne.KeyEqual:
+			setSeconds(panelReviewSecondsStep)
+		case fyne.KeyMinus:
+			setSeconds(-panelReviewSecondsStep)
+		case fyne.KeyEscape:
+			win.Close()
+		}
+	})
+	win.SetOnClosed(func() {
+		playing, closed = false, true
+		schedule()
+		if opt.OnExit != nil {
+			opt.OnExit(slides[cur].PageIdx, slides[cur].PanelID)
+		}
+	})
+
+	bg := canvas.NewRectangle(color.NRGBA{R: 40, G: 40, B: 40, A: 255})
+	bottom := container.NewVBox(text, hint)
+	win.SetContent(container.NewBorder(position, bottom, nil, nil, container.NewStack(bg, container.NewPadded(img))))
+	win.Resize(fyne.NewSize(1024, 768))
+	show()
+	win.SetFullScreen(true)
+	win.Show()
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/domain"
+)
+
+func panelReviewIssue(dir string) domain.Issue {
+	r := func(x, y float64) domain.Rect { return domain.Rect{X: x, Y: y, Width: 100, Height: 100} }
+	return domain.Issue{ReadingDirection: dir, Pages: []domain.Page{
+		// zOrder disagrees with the layout; the review follows the layout
+		{Number: 1, Panels: []domain.Panel{{ID: "c", Geometry: r(0, 120), ZOrder: 0}, {ID: "b", Geometry: r(120, 0), ZOrder: 1}, {ID: "a", Geometry: r(0, 0), ZOrder: 2}}},
+		{Number: 2},
+		{Number: 3, Panels: []domain.Panel{{ID: "d", Geometry: r(0, 0)}}},
+	}}
+}
+
+func TestPanelReviewSlides(t *testing.T) {
+	ids := func(ss []panelReviewSlide) (out []string) {
+		for _, s := range ss {
+			out = append(out, s.PanelID)
+		}
+		return out
+	}
+	ltr := panelReviewSlides(panelReviewIssue(""))
+	if got := ids(ltr); !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+		t.Fatalf("ltr order = %v", got)
+	}
+	if s := ltr[1]; s.PageIdx != 0 || s.PageNumber != 1 || s.Pos != 2 || s.OnPage != 3 {
+		t.Fatalf("slide b = %+v", s)
+	}
+	if s := ltr[3]; s.PageIdx != 2 || s.PageNumber != 3 || s.Pos != 1 || s.OnPage != 1 {
+		t.Fatalf("slide d = %+v", s)
+	}
+	if got := ids(panelReviewSlides(panelReviewIssue("rtl"))); !slices.Equal(got, []string{"b", "a", "c", "d"}) {
+		t.Fatalf("rtl order = %v", got)
+	}
+
+	if i := panelReviewSlideOf(ltr, 0, "c"); i != 2 {
+		t.Fatalf("slide of c = %d", i)
+	}
+	if i := panelReviewSlideOf(ltr, 2, ""); i != 3 {
+		t.Fatalf("first slide of page 3 = %d", i)
+	}
+	if i := panelReviewSlideOf(ltr, 1, "x"); i != 0 {
+		t.Fatalf("page without panels = %d", i)
+	}
+}
+
+func TestPanelReviewSeconds(t *testing.T) {
+	for in, want := range map[float64]float64{0: defaultPanelReviewSeconds, -3: defaultPanelReviewSeconds, 0.2: minPanelReviewSeconds, 2.5: 2.5, 600: maxPanelReviewSeconds} {
+		if got := panelReviewSeconds(in); got != want {
+			t.Errorf("panelReviewSeconds(%g) = %g, want %g", in, got, want)
+		}
+	}
+	if d := panelReviewInterval(2.5); d != 2500*time.Millisecond {
+		t.Fatalf("interval = %v", d)
+	}
+	if s := panelReviewScale(domain.Rect{Width: 700, Height: 350}); s != 2 {
+		t.Fatalf("scale = %g", s)
+	}
+	s := panelReviewSlide{PageNumber: 3, Pos: 2, OnPage: 5}
+	if got := panelReviewPosition(s, 13, 60, 2.5, true); got != "Page 3 · Panel 2/5 · 14/60 · Auto every 2.5 s" {
+		t.Fatalf("position = %q", got)
+	}
+	if got := panelReviewPosition(s, 13, 60, 0, false); got != "Page 3 · Panel 2/5 · 14/60 · Paused" {
+		t.Fatalf("paused position = %q", got)
+	}
+}
+
+func TestPanelReviewText(t *testing.T) {
+	src := "INT. ALLEY - NIGHT\nPanel 1: Rain on the bins.\nCAPTION: Midnight.\nALICE: Who's there?\nPanel 2: A cat.\n"
+	beats := scriptBeats(src)
+	if len(beats) != 2 || beats["b:2"].Text != "PANEL 1: Rain on the bins." || len(beats["b:2"].Lines) != 2 || len(beats["b:5"].Lines) != 0 {
+		t.Fatalf("beats = %+v", beats)
+	}
+	got := panelReviewText(domain.Panel{BeatIDs: []string{"b:2", "b:9"}}, beats)
+	want := "▸ PANEL 1: Rain on the bins.\n    CAPTION: Midnight.\n    ALICE: Who's there?\n▸ b:9 (not in the script)"
+	if got != want {
+		t.Fatalf("text = %q", got)
+	}
+	if got := panelReviewText(domain.Panel{}, beats); got != "No beats mapped to this panel." {
+		t.Fatalf("no beats = %q", got)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/textutil"
+)
+
+// balloonRows lists the balloons of pn for the inspector in reading order: their IDs and a row each
+// with the reading position, ID, type and the start of the text.
+func balloonRows(pn domain.Panel) (ids, rows []string) {
+	for n, b := range storage.BalloonsInOrder(pn.Balloons) {
+		ids = append(ids, b.ID)
+		row := fmt.Sprintf("%d. %s [%s]", n+1, b.ID, b.Type)
+		if txt := strings.Join(strings.Fields(balloonText(b.TextRuns)), " "); txt != "" {
+			row += " " + textutil.TruncateRunes(txt, 40, "…")
+		}
+		rows = append(rows, row)
+	}
+	return ids, rows
+}
+
+// balloonDetails describes the selected balloon in the inspector: ID, type and speaking character,
+// then one line per text run with its font, size and the start of its text.
+func balloonDetails(b domain.Balloon) string {
+	head := []string{b.ID, b.Type}
+	if b.Character != "" {
+		head = append(head, b.Character)
+	}
+	lines := []string{strings.Join(head, " · ")}
+	for _, r := range b.TextRuns {
+		font := r.Font
+		if font == "" {
+			font = "—"
+		}
+		size := r.Size
+		if size <= 0 {
+			size = defaultBalloonSize
+		}
+		txt := strings.Join(strings.Fields(r.Content), " ")
+		lines = append(lines, fmt.Sprintf("%s %gpt: %s", font, size, textutil.TruncateRunes(txt, 48, "…")))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestBalloonRowsAndDetails(t *testing.T) {
+	pn := domain.Panel{ID: "p1", Balloons: []domain.Balloon{
+		{ID: "b1", Type: "speech", Order: 2, TextRuns: []domain.TextRun{{Content: "Second\nline"}}},
+		{ID: "b2", Type: "caption", Order: 1},
+	}}
+	ids, rows := balloonRows(pn)
+	if want := []string{"b2", "b1"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("ids = %v, want %v", ids, want)
+	}
+	if want := []string{"1. b2 [caption]", "2. b1 [speech] Second line"}; !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows = %q, want %q", rows, want)
+	}
+	b := domain.Balloon{ID: "b7", Type: "thought", Character: "Mara", TextRuns: []domain.TextRun{
+		{Content: "Quiet.", Font: "Comic", Size: 14}, {Content: "too quiet"},
+	}}
+	if got, want := balloonDetails(b), "b7 · thought · Mara\nComic 14pt: Quiet.\n— 12pt: too quiet"; got != want {
+		t.Fatalf("details = %q, want %q", got, want)
+	}
+}
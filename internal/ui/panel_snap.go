@@ -0,0 +1,198 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/vector"
+)
+
+// panelSnap snaps a panel moved or resized on the canvas, in sheet coordinates. The zero value snaps
+// nothing.
+type panelSnap struct {
+	on        bool
+	threshold float32 // points
+	anchors   []vector.Anchor
+	grid      snapGrid
+	edges     bool
+	centers   bool
+}
+
+// snapGrid is a square grid of lines step apart from the top-left corner of bounds, limited to
+// bounds; a step of 0 is no grid.
+type snapGrid struct {
+	bounds vector.Rect
+	step   float32
+}
+
+// newPanelSnap resolves the project's snap settings for a drag at the given zoom: the panel snaps to
+// trim, the trim box of its page, and to others, the other panels, and to a grid over trim.
+func newPanelSnap(s storage.SnapSettings, zoom float32, trim vector.Rect, others []vector.Rect) panelSnap {
+	if s.Off || zoom <= 0 {
+		return panelSnap{}
+	}
+	ps := panelSnap{on: true, threshold: float32(s.ThresholdPx()) / zoom, edges: !s.NoEdges, centers: !s.NoCenters}
+	if !s.NoTrim {
+		ps.anchors = append(ps.anchors, vector.Anchor{Rect: trim, Weight: 1})
+	}
+	if !s.NoPanels {
+		for _, r := range others {
+			ps.anchors = append(ps.anchors, vector.Anchor{Rect: r, Weight: 1})
+		}
+	}
+	if s.GridMM > 0 {
+		ps.grid = snapGrid{bounds: trim, step: float32(domain.MMToPoints(s.GridMM))}
+	}
+	return ps
+}
+
+// rect snaps a moved panel: its edges and centers, as configured, to the anchors and the grid, in X and
+// Y independently. It returns the snapped rect and the guides to show.
+func (s panelSnap) rect(r vector.Rect) (vector.Rect, []vector.GuideLine) {
+	if !s.on {
+		return r, nil
+	}
+	out, guides := vector.ComputeSmartGuides(r, s.anchors, vector.SnapOptions{Threshold: s.threshold, SnapToEdges: s.edges, SnapToCenters: s.centers})
+	var xs, ys []float32
+	if s.edges {
+		xs, ys = append(xs, r.X, r.X+r.W), append(ys, r.Y, r.Y+r.H)
+	}
+	if s.centers {
+		xs, ys = append(xs, r.X+r.W/2), append(ys, r.Y+r.H/2)
+	}
+	return s.snapToGrid(r, out, guides, xs, ys)
+}
+
+// point snaps the dragged corner of a resized panel to the anchors' edges and the grid.
+func (s panelSnap) point(pt vector.Pt) (vector.Pt, []vector.GuideLine) {
+	if !s.on || !s.edges {
+		return pt, nil
+	}
+	r := vector.R(pt.X, pt.Y, 0, 0)
+	out, guides := vector.ComputeSmartGuides(r, s.anchors, vector.SnapOptions{Threshold: s.threshold, SnapToEdges: true})
+	out, guides = s.snapToGrid(r, out, guides, []float32{pt.X}, []float32{pt.Y})
+	return vector.Pt{X: out.X, Y: out.Y}, guides
+}
+
+// snapToGrid replaces the anchor snap of r (out and its guides) per axis by a grid snap of the xs or ys
+// of r when that one is closer.
+func (s panelSnap) snapToGrid(r, out vector.Rect, guides []vector.GuideLine, xs, ys []float32) (vector.Rect, []vector.GuideLine) {
+	if s.grid.step <= 0 {
+		return out, guides
+	}
+	b := s.grid.bounds
+	if d, x, ok := snapGridAxis(xs, b.X, b.X+b.W, s.grid.step, s.threshold); ok && (!hasGuide(guides, "vertical") || abs32(d) < abs32(out.X-r.X)) {
+		out.X = vector.FloatRound(r.X+d, 3)
+		guides = append(withoutGuide(guides, "vertical"), vector.GuideLine{
+			Orientation: "vertical", Kind: "grid", Position: x, From: vector.Pt{X: x, Y: b.Y}, To: vector.Pt{X: x, Y: b.Y + b.H},
+		})
+	}
+	if d, y, ok := snapGridAxis(ys, b.Y, b.Y+b.H, s.grid.step, s.threshold); ok && (!hasGuide(guides, "horizontal") || abs32(d) < abs32(out.Y-r.Y)) {
+		out.Y = vector.FloatRound(r.Y+d, 3)
+		guides = append(withoutGuide(guides, "horizontal"), vector.GuideLine{
+			Orientation: "horizontal", Kind: "grid", Position: y, From: vector.Pt{X: b.X, Y: y}, To: vector.Pt{X: b.X + b.W, Y: y},
+		})
+	}
+	return out, guides
+}
+
+// snapGridAxis returns the shift d that moves the value of vals closest to a grid line (lines step
+// apart from lo, up to hi) onto that line, and the line, when it is within threshold.
+func snapGridAxis(vals []float32, lo, hi, step, threshold float32) (d, line float32, ok bool) {
+	best := threshold
+	for _, v := range vals {
+		l := lo + float32(math.Round(float64((v-lo)/step)))*step
+		if l < lo || l > hi+0.001 {
+			continue
+		}
+		if dist := abs32(l - v); dist <= best {
+			best, d, line, ok = dist, l-v, vector.FloatRound(l, 3), true
+		}
+	}
+	return d, line, ok
+}
+
+func hasGuide(guides []vector.GuideLine, orientation string) bool {
+	for _, g := range guides {
+		if g.Orientation == orientation {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutGuide(guides []vector.GuideLine, orientation string) []vector.GuideLine {
+	var out []vector.GuideLine
+	for _, g := range guides {
+		if g.Orientation != orientation {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+func abs32(v float32) float32 { return float32(math.Abs(float64(v))) }
+
+// snapStatus describes the snap settings for the status bar and the settings dialog.
+func snapStatus(s storage.SnapSettings) string {
+	if s.Off {
+		return i18n.T("snap.off")
+	}
+	var targets []string
+	if !s.NoPanels {
+		targets = append(targets, i18n.T("snap.panels"))
+	}
+	if !s.NoTrim {
+		targets = append(targets, i18n.T("snap.trim"))
+	}
+	if s.GridMM > 0 {
+		u := units()
+		targets = append(targets, i18n.T("snap.grid", trimNumber(u.fromMM(s.GridMM), u.decimals()+1)+" "+u.symbol()))
+	}
+	if len(targets) == 0 || (s.NoEdges && s.NoCenters) {
+		return i18n.T("snap.nothing")
+	}
+	return i18n.T("snap.to", strings.Join(targets, ", "), s.ThresholdPx())
+}
+
+// parseSnapSettings builds snap settings from the snapping dialog's fields; empty numbers are the
+// defaults (8 px, no grid). The grid is typed in the unit of size fields and kept in millimetres.
+func parseSnapSettings(on bool, threshold, grid string, panels, trim, edges, centers bool) (storage.SnapSettings, error) {
+	s := storage.SnapSettings{Off: !on, NoPanels: !panels, NoTrim: !trim, NoEdges: !edges, NoCenters: !centers}
+	var err error
+	if t := strings.TrimSpace(threshold); t != "" {
+		if s.Threshold, err = strconv.ParseFloat(t, 64); err != nil {
+			return storage.SnapSettings{}, fmt.Errorf("snap distance %q is not a number", t)
+		}
+	}
+	if g := strings.TrimSpace(grid); g != "" {
+		if s.GridMM, err = strconv.ParseFloat(strings.ReplaceAll(g, ",", "."), 64); err != nil {
+			return storage.SnapSettings{}, fmt.Errorf("grid spacing %q is not a number", g)
+		}
+		s.GridMM = units().toMM(s.GridMM)
+	}
+	if err := s.Validate(); err != nil {
+		return storage.SnapSettings{}, err
+	}
+	return s, nil
+}
+
+// axisAligned reports whether m neither rotates nor shears, so a node's corners stay on its bounds.
+func axisAligned(m vector.Affine2D) bool {
+	return abs32(m.B) < 1e-6 && abs32(m.C) < 1e-6
+}
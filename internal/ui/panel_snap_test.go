@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/vector"
+)
+
+func TestPanelSnapRectToPanelsAndTrim(t *testing.T) {
+	trim := vector.R(9, 9, 577, 824)
+	other := vector.R(9, 9, 200, 150)
+	s := newPanelSnap(storage.SnapSettings{}, 1, trim, []vector.Rect{other})
+	// Left edge 0.3pt right of the other panel's right edge, top 2pt below the trim top
+	got, guides := s.rect(vector.R(209.3, 11, 100, 100))
+	if got != vector.R(209, 9, 100, 100) {
+		t.Fatalf("rect = %+v", got)
+	}
+	if len(guides) != 2 || guides[0].Orientation != "vertical" || guides[0].Position != 209 || guides[1].Position != 9 {
+		t.Fatalf("guides = %+v", guides)
+	}
+	// Thresholds are screen pixels: at zoom 4 the 8px default is 2pt, too short for a 3pt offset
+	if got, guides := newPanelSnap(storage.SnapSettings{}, 4, trim, []vector.Rect{other}).rect(vector.R(212, 300, 100, 100)); got.X != 212 || len(guides) != 0 {
+		t.Fatalf("zoomed in: %+v %+v", got, guides)
+	}
+	// Without panels as targets only the trim box remains
+	if got, _ := newPanelSnap(storage.SnapSettings{NoPanels: true}, 1, trim, []vector.Rect{other}).rect(vector.R(209.3, 11, 100, 100)); got != vector.R(209.3, 9, 100, 100) {
+		t.Fatalf("no panels: %+v", got)
+	}
+	if got, guides := newPanelSnap(storage.SnapSettings{Off: true}, 1, trim, []vector.Rect{other}).rect(vector.R(209.3, 11, 100, 100)); got != vector.R(209.3, 11, 100, 100) || guides != nil {
+		t.Fatalf("snapping off: %+v %+v", got, guides)
+	}
+}
+
+func TestPanelSnapGrid(t *testing.T) {
+	trim := vector.R(9, 9, 577, 824)
+	step := float32(domain.MMToPoints(10)) // 28.3465pt
+	s := newPanelSnap(storage.SnapSettings{GridMM: 10, NoTrim: true, NoCenters: true}, 1, trim, nil)
+	got, guides := s.rect(vector.R(9+2*step+1.5, 9+14*step+14, 2*step, 2*step)) // Y edges halfway between lines
+	if want := vector.FloatRound(9+2*step, 3); got.X != want {
+		t.Fatalf("x = %v, want %v", got.X, want)
+	}
+	if len(guides) != 1 || guides[0].Kind != "grid" || guides[0].From.Y != 9 || guides[0].To.Y != 833 {
+		t.Fatalf("guides = %+v", guides)
+	}
+	// A closer panel edge wins over the grid line
+	other := vector.R(9+2*step+1, 500, 10, 10)
+	got, guides = newPanelSnap(storage.SnapSettings{GridMM: 10, NoTrim: true}, 1, trim, []vector.Rect{other}).rect(vector.R(9+2*step+1.5, 9+14*step+14, 2*step, 2*step))
+	if got.X != vector.FloatRound(other.X, 3) || len(guides) != 1 || guides[0].Kind != "edge" {
+		t.Fatalf("panel vs grid: %+v %+v", got, guides)
+	}
+}
+
+func TestPanelSnapPoint(t *testing.T) {
+	trim := vector.R(9, 9, 577, 824)
+	s := newPanelSnap(storage.SnapSettings{}, 1, trim, nil)
+	pt, guides := s.point(vector.Pt{X: 585, Y: 300})
+	if pt != (vector.Pt{X: 586, Y: 300}) || len(guides) != 1 {
+		t.Fatalf("corner = %+v, guides %+v", pt, guides)
+	}
+	if pt, _ := newPanelSnap(storage.SnapSettings{NoEdges: true}, 1, trim, nil).point(vector.Pt{X: 585, Y: 300}); pt.X != 585 {
+		t.Fatalf("corner without edge snapping = %+v", pt)
+	}
+}
+
+func TestSnapStatus(t *testing.T) {
+	cases := map[string]storage.SnapSettings{
+		"Snapping to panels, trim within 8 px (hold Alt to move freely)":    {},
+		"Snapping to trim, 5 mm grid within 4 px (hold Alt to move freely)": {NoPanels: true, GridMM: 5, Threshold: 4},
+		"Snapping off":        {Off: true},
+		"Snapping to nothing": {NoPanels: true, NoTrim: true},
+	}
+	for want, s := range cases {
+		if got := snapStatus(s); got != want {
+			t.Errorf("snapStatus(%+v) = %q, want %q", s, got, want)
+		}
+	}
+}
+
+func TestParseSnapSettings(t *testing.T) {
+	s, err := parseSnapSettings(true, "", " 2,5 ", true, false, true, true)
+	if err != nil || s != (storage.SnapSettings{GridMM: 2.5, NoTrim: true}) {
+		t.Fatalf("parse = %+v, %v", s, err)
+	}
+	if s, err := parseSnapSettings(false, "12", "", true, true, true, false); err != nil || !s.Off || s.Threshold != 12 || !s.NoCenters {
+		t.Fatalf("parse = %+v, %v", s, err)
+	}
+	for _, bad := range [][2]string{{"x", ""}, {"", "ten"}, {"-3", ""}, {"", "0.1"}} {
+		if _, err := parseSnapSettings(true, bad[0], bad[1], true, true, true, true); err == nil {
+			t.Fatalf("%q should be rejected", bad)
+		}
+	}
+}
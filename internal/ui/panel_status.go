@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/vector"
+)
+
+// artStageLabels are the checklist captions of the art stages in the panel metadata dialog.
+var artStageLabels = map[string]string{
+	domain.ArtStageLayout:  "Layout",
+	domain.ArtStagePencils: "Pencils",
+	domain.ArtStageInks:    "Inks",
+	domain.ArtStageLetters: "Letters",
+}
+
+// artStageFilterOptions are the choices of the panel list's status filter; each but the first lists
+// the panels that have not done the stage in artStageFilterStages at the same position.
+var (
+	artStageFilterOptions = []string{"All panels", "Layout not done", "Not yet penciled", "Not yet inked", "Not yet lettered"}
+	artStageFilterStages  = []string{"", domain.ArtStageLayout, domain.ArtStagePencils, domain.ArtStageInks, domain.ArtStageLetters}
+)
+
+// artStageFilterLabel returns the filter option of stage, or the "all panels" option for an unknown stage.
+func artStageFilterLabel(stage string) string {
+	for i, s := range artStageFilterStages {
+		if s == stage {
+			return artStageFilterOptions[i]
+		}
+	}
+	return artStageFilterOptions[0]
+}
+
+// artStageFilterStage returns the stage the filter option label stands for; "" lists all panels.
+func artStageFilterStage(label string) string {
+	for i, o := range artStageFilterOptions {
+		if o == label {
+			return artStageFilterStages[i]
+		}
+	}
+	return ""
+}
+
+// panelMatchesArtFilter reports whether pn is listed under the status filter for stage.
+func panelMatchesArtFilter(pn domain.Panel, stage string) bool {
+	return stage == "" || !storage.ArtStageDone(pn, stage)
+}
+
+// canvasOverlayOptions are the choices of the overlay selector above the panel list, matching the
+// storage.ProjectSettings overlays in canvasOverlayKeys.
+var (
+	canvasOverlayOptions = []string{"No overlay", "Beat coverage", "Art status"}
+	canvasOverlayKeys    = []string{"", storage.OverlayBeats, storage.OverlayArtStatus}
+)
+
+// canvasOverlayLabel returns the selector option of an overlay key.
+func canvasOverlayLabel(key string) string {
+	for i, k := range canvasOverlayKeys {
+		if k == key {
+			return canvasOverlayOptions[i]
+		}
+	}
+	return canvasOverlayOptions[0]
+}
+
+// canvasOverlayKey returns the overlay key of a selector option.
+func canvasOverlayKey(label string) string {
+	for i, o := range canvasOverlayOptions {
+		if o == label {
+			return canvasOverlayKeys[i]
+		}
+	}
+	return ""
+}
+
+// artStatusDot is the colored marker of a panel's progress in the panel list: white before layout,
+// then red, orange and yellow while pencils, inks and letters are pending, green when finished.
+func artStatusDot(pn domain.Panel) string {
+	switch storage.NextArtStage(pn) {
+	case domain.ArtStageLayout:
+		return "⚪"
+	case domain.ArtStagePencils:
+		return "🔴"
+	case domain.ArtStageInks:
+		return "🟠"
+	case domain.ArtStageLetters:
+		return "🟡"
+	}
+	return "🟢"
+}
+
+// artStatusFill is the panel fill of the art status overlay, a light tint of the artStatusDot color.
+func artStatusFill(pn domain.Panel) vector.Color {
+	switch storage.NextArtStage(pn) {
+	case domain.ArtStageLayout:
+		return vector.Color{R: 240, G: 240, B: 240, A: 255}
+	case domain.ArtStagePencils:
+		return vector.Color{R: 245, G: 205, B: 205, A: 255}
+	case domain.ArtStageInks:
+		return vector.Color{R: 250, G: 220, B: 180, A: 255}
+	case domain.ArtStageLetters:
+		return vector.Color{R: 250, G: 240, B: 170, A: 255}
+	}
+	return vector.Color{R: 190, G: 235, B: 190, A: 255}
+}
+
+// artStatusReport formats storage.ComputeArtStatus counts, one block per issue.
+func artStatusReport(stats []storage.IssueArtStatus) string {
+	if len(stats) == 0 {
+		return i18n.T("art.no_issues")
+	}
+	var b strings.Builder
+	for i, st := range stats {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(i18n.N("art.issue_summary", st.Panels, st.Issue, st.Panels, st.Complete))
+		for _, stage := range domain.ArtStages {
+			fmt.Fprintf(&b, "\n  %s: %d/%d", artStageLabels[stage], st.Done[stage], st.Panels)
+		}
+		b.WriteString(i18n.T("art.assigned", st.Assigned))
+	}
+	return b.String()
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func TestArtStageFilter(t *testing.T) {
+	inked := domain.Panel{ID: "p1", Annotations: &domain.PanelAnnotations{Status: map[string]bool{"layout": true, "pencils": true, "inks": true}}}
+	fresh := domain.Panel{ID: "p2"}
+	stage := artStageFilterStage("Not yet inked")
+	if stage != domain.ArtStageInks || artStageFilterLabel(stage) != "Not yet inked" {
+		t.Fatalf("filter option maps to %q", stage)
+	}
+	if panelMatchesArtFilter(inked, stage) || !panelMatchesArtFilter(fresh, stage) {
+		t.Fatal("inks filter lists the wrong panels")
+	}
+	if !panelMatchesArtFilter(inked, artStageFilterStage("All panels")) {
+		t.Fatal("all panels filter hides a panel")
+	}
+	if artStageFilterLabel("colors") != "All panels" {
+		t.Fatal("unknown stage should fall back to all panels")
+	}
+}
+
+func TestCanvasOverlayOptions(t *testing.T) {
+	for _, key := range []string{"", storage.OverlayBeats, storage.OverlayArtStatus} {
+		if got := canvasOverlayKey(canvasOverlayLabel(key)); got != key {
+			t.Errorf("overlay %q round trips to %q", key, got)
+		}
+	}
+}
+
+func TestArtStatusDotAndFill(t *testing.T) {
+	fresh := domain.Panel{}
+	done := domain.Panel{Annotations: &domain.PanelAnnotations{Status: map[string]bool{}}}
+	for _, s := range domain.ArtStages {
+		done.Annotations.Status[s] = true
+	}
+	if artStatusDot(fresh) != "⚪" || artStatusDot(done) != "🟢" {
+		t.Fatalf("dots = %s %s", artStatusDot(fresh), artStatusDot(done))
+	}
+	if artStatusFill(fresh) == artStatusFill(done) {
+		t.Fatal("finished and fresh panels share a fill")
+	}
+}
+
+func TestArtStatusReport(t *testing.T) {
+	got := artStatusReport([]storage.IssueArtStatus{{Issue: 1, Panels: 4, Complete: 1, Assigned: 2, Done: map[string]int{"layout": 3, "inks": 1}}})
+	for _, want := range []string{"Issue 1 — 4 panels, 1 finished", "Layout: 3/4", "Pencils: 0/4", "Inks: 1/4", "Assigned: 2"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report lacks %q:\n%s", want, got)
+		}
+	}
+}
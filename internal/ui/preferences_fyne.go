@@ -0,0 +1,110 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+package ui
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/config"
+	"gocomicwriter/internal/export"
+	"gocomicwriter/internal/i18n"
+)
+
+// showPreferences edits the appearance settings of cfg: the theme, the length unit of size fields, the
+// pixel limit of raster exports and the page canvas colors. Saving
+// writes the config file and calls onApply, which applies the settings without a restart.
+func showPreferences(w fyne.Window, cfg *config.AppConfig, onApply func()) {
+	themeSel := widget.NewSelect(choiceLabels(themeValues, themeKeys), nil)
+	if _, ok := themeKeys[cfg.General.Theme]; ok {
+		themeSel.SetSelected(i18n.T(themeKeys[cfg.General.Theme]))
+	} else {
+		themeSel.SetSelected(i18n.T(themeKeys[config.ThemeSystem]))
+	}
+	unitSel := widget.NewSelect(choiceLabels(unitValues, unitKeys), nil)
+	unitSel.SetSelected(i18n.T(unitKeys[string(lengthUnitOf(cfg.General.Units))]))
+	maxPixelsEntry := widget.NewEntry()
+	maxPixelsEntry.SetPlaceHolder(strconv.Itoa(export.DefaultMaxPixels))
+	if cfg.Export.MaxPixels > 0 {
+		maxPixelsEntry.SetText(strconv.Itoa(cfg.Export.MaxPixels))
+	}
+	maxPixelsEntry.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return nil
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(s)); err != nil || n <= 0 {
+			return errors.New(i18n.T("msg.invalid_max_pixels"))
+		}
+		return nil
+	}
+	contrastChk := widget.NewCheck(i18n.T("check.high_contrast_canvas"), nil)
+	contrastChk.SetChecked(cfg.Canvas.HighContrast)
+	invertChk := widget.NewCheck(i18n.T("check.invert_page_surround"), nil)
+	invertChk.SetChecked(cfg.Canvas.InvertSurround)
+	colorEntry := func(v string) *widget.Entry {
+		e := widget.NewEntry()
+		e.SetPlaceHolder(i18n.T("placeholder.default_color"))
+		e.SetText(v)
+		e.Validator = func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return nil
+			}
+			_, err := parseHexColor(s)
+			return err
+		}
+		return e
+	}
+	trimEntry := colorEntry(cfg.Canvas.TrimColor)
+	bleedEntry := colorEntry(cfg.Canvas.BleedColor)
+	guideEntry := colorEntry(cfg.Canvas.GuideColor)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(i18n.T("form.theme"), themeSel),
+		widget.NewFormItem(i18n.T("form.units"), unitSel),
+		widget.NewFormItem(i18n.T("form.max_pixels"), maxPixelsEntry),
+		widget.NewFormItem(i18n.T("form.page_canvas"), contrastChk),
+		widget.NewFormItem("", invertChk),
+		widget.NewFormItem(i18n.T("form.trim_color"), trimEntry),
+		widget.NewFormItem(i18n.T("form.bleed_color"), bleedEntry),
+		widget.NewFormItem(i18n.T("form.guide_color"), guideEntry),
+	}
+	d := dialog.NewForm(i18n.T("msg.preferences"), i18n.T("msg.save"), i18n.T("msg.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		if v := choiceValue(themeSel.Selected, themeValues, themeKeys); v != "" {
+			cfg.General.Theme = v
+		}
+		if v := choiceValue(unitSel.Selected, unitValues, unitKeys); v != "" {
+			cfg.General.Units = v
+		}
+		cfg.Export.MaxPixels, _ = strconv.Atoi(strings.TrimSpace(maxPixelsEntry.Text))
+		cfg.Canvas = config.CanvasConfig{
+			HighContrast:   contrastChk.Checked,
+			InvertSurround: invertChk.Checked,
+			TrimColor:      strings.TrimSpace(trimEntry.Text),
+			BleedColor:     strings.TrimSpace(bleedEntry.Text),
+			GuideColor:     strings.TrimSpace(guideEntry.Text),
+		}
+		if err := config.Save(*cfg, ""); err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+		}
+		onApply()
+	}, w)
+	d.Resize(fyne.NewSize(460, 0))
+	d.Show()
+}
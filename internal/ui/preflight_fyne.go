@@ -0,0 +1,65 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+package ui
+
+import (
+	"gocomicwriter/internal/export"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showPreflight checks the issue for print problems. With onExport set it runs before an export:
+// a clean issue goes straight to onExport, otherwise the findings are listed with "Export Anyway" and
+// "Cancel". Without onExport it is the standalone Preflight… report.
+func showPreflight(w fyne.Window, ph *storage.ProjectHandle, issueIdx int, onExport func()) {
+	ws, err := export.PreflightIssue(ph, issueIdx)
+	if err != nil {
+		if onExport != nil {
+			onExport() // the exporter reports the problem itself
+			return
+		}
+		dialog.ShowError(FriendlyError(err), w)
+		return
+	}
+	if len(ws) == 0 {
+		if onExport != nil {
+			onExport()
+			return
+		}
+		dialog.ShowInformation(i18n.T("msg.preflight"), i18n.T("msg.no_print_problems_found"), w)
+		return
+	}
+	list := widget.NewList(
+		func() int { return len(ws) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(ws[i].String()) },
+	)
+	summary := widget.NewLabelWithStyle(export.PreflightSummary(ws), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	content := container.NewBorder(summary, nil, nil, nil, list)
+	var d dialog.Dialog
+	if onExport != nil {
+		d = dialog.NewCustomConfirm(i18n.T("msg.preflight"), i18n.T("msg.export_anyway"), i18n.T("msg.cancel"), content, func(ok bool) {
+			if ok {
+				onExport()
+			}
+		}, w)
+	} else {
+		d = dialog.NewCustom(i18n.T("msg.preflight"), i18n.T("msg.close"), content, w)
+	}
+	d.Resize(fyne.NewSize(640, 420))
+	d.Show()
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gocomicwriter/internal/backend"
+	"gocomicwriter/internal/version"
+)
+
+const (
+	// presenceInterval is the time between heartbeats while a linked project is open.
+	presenceInterval = 30 * time.Second
+	// presenceTTL lets the server drop this client after a few missed heartbeats.
+	presenceTTL = 90 * time.Second
+	// presenceTimeout bounds each presence request, including the leave on close.
+	presenceTimeout = 5 * time.Second
+)
+
+// presenceAPI is the part of backend.Client the presence beacon uses.
+type presenceAPI interface {
+	Heartbeat(ctx context.Context, projectID int64, hb backend.PresenceHeartbeat) (*backend.PresenceMember, error)
+	ListPresence(ctx context.Context, projectID int64) ([]backend.PresenceMember, error)
+	LeavePresence(ctx context.Context, projectID int64, clientID string) error
+}
+
+// newPresenceClientID returns a random id that tells this app instance apart from the user's other
+// windows and machines.
+func newPresenceClientID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// presenceClientInfo describes this app for other viewers, e.g. "gocomicwriter 2025.12 (darwin)".
+func presenceClientInfo() string {
+	return fmt.Sprintf("gocomicwriter %s (%s)", version.String(), runtime.GOOS)
+}
+
+// presenceOthers returns the sorted, distinct subjects viewing the project in clients other than clientID.
+func presenceOthers(list []backend.PresenceMember, clientID string) []string {
+	seen := map[string]bool{}
+	var others []string
+	for _, m := range list {
+		if m.ClientID == clientID || seen[m.Subject] {
+			continue
+		}
+		seen[m.Subject] = true
+		others = append(others, m.Subject)
+	}
+	sort.Strings(others)
+	return others
+}
+
+// presenceLabel is the status bar text for the other viewers: empty when there are none.
+func presenceLabel(others []string) string {
+	switch len(others) {
+	case 0:
+		return ""
+	case 1:
+		return "1 other viewing"
+	default:
+		return fmt.Sprintf("%d others viewing", len(others))
+	}
+}
+
+// presenceTooltip lists the other viewers, one per line.
+func presenceTooltip(others []string) string {
+	return strings.Join(others, "\n")
+}
+
+// presenceBeacon announces an open project to the server and reports who else is viewing it. Failures
+// are logged once per outage and clear the viewers; they never interrupt the user.
+type presenceBeacon struct {
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// startPresenceBeacon sends a heartbeat now and every interval until Close. After each heartbeat it
+// lists the viewers and passes the others to onUpdate, which runs on the beacon goroutine.
+func startPresenceBeacon(api presenceAPI, pid int64, clientID string, interval time.Duration, l *slog.Logger, onUpdate func(others []string)) *presenceBeacon {
+	b := &presenceBeacon{stop: make(chan struct{}), done: make(chan struct{})}
+	l = l.With(slog.String("component", "presence"), slog.Int64("project_id", pid))
+	go b.run(api, pid, clientID, interval, l, onUpdate)
+	return b
+}
+
+func (b *presenceBeacon) run(api presenceAPI, pid int64, clientID string, interval time.Duration, l *slog.Logger, onUpdate func(others []string)) {
+	defer close(b.done)
+	failing := false
+	beat := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+		defer cancel()
+		var list []backend.PresenceMember
+		_, err := api.Heartbeat(ctx, pid, backend.PresenceHeartbeat{ClientID: clientID, Client: presenceClientInfo(), TTL: presenceTTL})
+		if err == nil {
+			list, err = api.ListPresence(ctx, pid)
+		}
+		if err != nil {
+			if !failing {
+				l.Warn("presence heartbeat failed", slog.Any("err", err))
+			}
+			failing = true
+			onUpdate(nil)
+			return
+		}
+		if failing {
+			l.Info("presence heartbeat recovered")
+		}
+		failing = false
+		onUpdate(presenceOthers(list, clientID))
+	}
+	beat()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-b.stop:
+			ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+			defer cancel()
+			if err := api.LeavePresence(ctx, pid, clientID); err != nil {
+				l.Debug("presence leave failed", slog.Any("err", err))
+			}
+			return
+		case <-t.C:
+			beat()
+		}
+	}
+}
+
+// Close stops the heartbeats, tells the server this client left and waits for the beacon goroutine.
+// It is safe to call more than once and on a nil beacon.
+func (b *presenceBeacon) Close() {
+	if b == nil {
+		return
+	}
+	b.stopOnce.Do(func() { close(b.stop) })
+	<-b.done
+}
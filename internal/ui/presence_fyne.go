@@ -0,0 +1,68 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// presenceIndicator is the status bar label showing how many others view the project. Hovering it
+// lists their names in a popup.
+type presenceIndicator struct {
+	widget.Label
+	canvas fyne.Canvas
+	tip    string
+	popup  *widget.PopUp
+}
+
+func newPresenceIndicator(c fyne.Canvas) *presenceIndicator {
+	p := &presenceIndicator{canvas: c}
+	p.ExtendBaseWidget(p)
+	p.Hide()
+	return p
+}
+
+// SetOthers shows the other viewers, or hides the indicator when there are none.
+func (p *presenceIndicator) SetOthers(others []string) {
+	p.tip = presenceTooltip(others)
+	p.SetText(presenceLabel(others))
+	if len(others) == 0 {
+		p.hidePopup()
+		p.Hide()
+		return
+	}
+	p.Show()
+}
+
+func (p *presenceIndicator) MouseIn(*desktop.MouseEvent) {
+	if p.tip == "" {
+		return
+	}
+	p.hidePopup()
+	p.popup = widget.NewPopUp(widget.NewLabel(p.tip), p.canvas)
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(p)
+	size := p.popup.MinSize()
+	p.popup.ShowAtPosition(fyne.NewPos(pos.X+p.Size().Width-size.Width, pos.Y-size.Height))
+}
+
+func (p *presenceIndicator) MouseMoved(*desktop.MouseEvent) {}
+
+func (p *presenceIndicator) MouseOut() { p.hidePopup() }
+
+func (p *presenceIndicator) hidePopup() {
+	if p.popup != nil {
+		p.popup.Hide()
+		p.popup = nil
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/backend"
+)
+
+func TestPresenceOthersAndLabel(t *testing.T) {
+	list := []backend.PresenceMember{
+		{Subject: "me@example.com", ClientID: "self"},
+		{Subject: "me@example.com", ClientID: "laptop"},
+		{Subject: "zoe@example.com", ClientID: "a"},
+		{Subject: "ann@example.com", ClientID: "b"},
+		{Subject: "ann@example.com", ClientID: "c"},
+	}
+	others := presenceOthers(list, "self")
+	if want := []string{"ann@example.com", "me@example.com", "zoe@example.com"}; !reflect.DeepEqual(others, want) {
+		t.Fatalf("others = %v, want %v", others, want)
+	}
+	if got := presenceLabel(others); got != "3 others viewing" {
+		t.Errorf("label = %q", got)
+	}
+	if got := presenceLabel(others[:1]); got != "1 other viewing" {
+		t.Errorf("label = %q", got)
+	}
+	if got := presenceLabel(presenceOthers(list[:1], "self")); got != "" {
+		t.Errorf("alone: label = %q", got)
+	}
+	if got := presenceTooltip(others[:2]); got != "ann@example.com\nme@example.com" {
+		t.Errorf("tooltip = %q", got)
+	}
+}
+
+// fakePresence records the beacon's calls and fails while err is set.
+type fakePresence struct {
+	mu     sync.Mutex
+	err    error
+	beats  int
+	left   []string
+	others []backend.PresenceMember
+}
+
+func (f *fakePresence) Heartbeat(_ context.Context, _ int64, hb backend.PresenceHeartbeat) (*backend.PresenceMember, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.beats++
+	return &backend.PresenceMember{ClientID: hb.ClientID}, nil
+}
+
+func (f *fakePresence) ListPresence(context.Context, int64) ([]backend.PresenceMember, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]backend.PresenceMember{{Subject: "me", ClientID: "self"}}, f.others...), nil
+}
+
+func (f *fakePresence) LeavePresence(_ context.Context, _ int64, clientID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.left = append(f.left, clientID)
+	return nil
+}
+
+func TestPresenceBeacon(t *testing.T) {
+	api := &fakePresence{err: errors.New("connection refused"), others: []backend.PresenceMember{{Subject: "ann", ClientID: "x"}}}
+	updates := make(chan []string, 100)
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+	b := startPresenceBeacon(api, 7, "self", 5*time.Millisecond, l, func(others []string) { updates <- others })
+
+	if got := <-updates; got != nil {
+		t.Fatalf("failed heartbeat should clear the viewers, got %v", got)
+	}
+	api.mu.Lock()
+	api.err = nil
+	api.mu.Unlock()
+	deadline := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case got := <-updates:
+			done = reflect.DeepEqual(got, []string{"ann"})
+		case <-deadline:
+			t.Fatal("beacon did not recover")
+		}
+	}
+
+	b.Close()
+	b.Close()
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if api.beats == 0 || !reflect.DeepEqual(api.left, []string{"self"}) {
+		t.Fatalf("beats %d, left %v", api.beats, api.left)
+	}
+	var nilBeacon *presenceBeacon
+	nilBeacon.Close()
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/textutil"
+)
+
+// Kinds of quick-open items, shown next to each item.
+const (
+	quickPage      = "Page"
+	quickPanel     = "Panel"
+	quickCharacter = "Character"
+	quickLocation  = "Location"
+	quickTag       = "Tag"
+	quickCommand   = "Command"
+)
+
+// quickOpenLimit caps the rows of the quick-open list.
+const quickOpenLimit = 50
+
+// quickNoteRunes is how much of a panel's notes the context of its item shows.
+const quickNoteRunes = 40
+
+// quickItem is one navigable entry of the quick-open palette.
+type quickItem struct {
+	Kind    string
+	Title   string // matched against the query, e.g. "Page 14", "Panel p3", "ALICE"
+	Context string // where the item lives, e.g. "Page 7 — hero enters"
+	Page    int    // pages and panels: page number
+	PanelID string // panels
+	Index   int    // bible entries: index into their bible list; commands: index into the commands
+}
+
+// label is the row text of the item, e.g. "Panel p3 — Page 7".
+func (it quickItem) label() string {
+	if it.Context == "" {
+		return it.Title
+	}
+	return it.Title + " — " + it.Context
+}
+
+// quickOpenItems lists the pages and panels of iss (nil for none), the bible entries and the commands,
+// in that order.
+func quickOpenItems(iss *domain.Issue, bible domain.Bible, cmds []command) []quickItem {
+	var out []quickItem
+	if iss != nil {
+		for _, pg := range iss.Pages {
+			ctx := i18n.N("quick.panels", len(pg.Panels))
+			out = append(out, quickItem{Kind: quickPage, Title: i18n.T("label.page_number", pg.Number), Context: ctx, Page: pg.Number})
+		}
+		for _, pg := range iss.Pages {
+			for _, pn := range pg.Panels {
+				ctx := i18n.T("label.page_number", pg.Number)
+				if notes := strings.Join(strings.Fields(pn.Notes), " "); notes != "" {
+					ctx += " — " + textutil.TruncateRunes(notes, quickNoteRunes, "…")
+				}
+				out = append(out, quickItem{Kind: quickPanel, Title: i18n.T("quick.panel", pn.ID), Context: ctx, Page: pg.Number, PanelID: pn.ID})
+			}
+		}
+	}
+	for i, c := range bible.Characters {
+		if n := strings.TrimSpace(c.Name); n != "" {
+			out = append(out, quickItem{Kind: quickCharacter, Title: n, Context: bibleContext(quickCharacter, c.Aliases), Index: i})
+		}
+	}
+	for i, loc := range bible.Locations {
+		if n := strings.TrimSpace(loc.Name); n != "" {
+			out = append(out, quickItem{Kind: quickLocation, Title: n, Context: bibleContext(quickLocation, loc.Aliases), Index: i})
+		}
+	}
+	for i, t := range bible.Tags {
+		if n := strings.TrimSpace(t.Name); n != "" {
+			out = append(out, quickItem{Kind: quickTag, Title: n, Context: "Tag", Index: i})
+		}
+	}
+	for i, c := range cmds {
+		out = append(out, quickItem{Kind: quickCommand, Title: c.Title, Context: c.Group + " menu", Index: i})
+	}
+	return out
+}
+
+// bibleContext describes a bible entry, e.g. "Character, also Al, Ally".
+func bibleContext(kind string, aliases []string) string {
+	if len(aliases) == 0 {
+		return kind
+	}
+	return kind + ", also " + strings.Join(aliases, ", ")
+}
+
+// quickKindPenalty is subtracted when the query only matches an item together with its kind and
+// context, e.g. "character alice" or "export menu pdf", so matches of the title alone rank first.
+const quickKindPenalty = 128
+
+// rankQuickItems returns up to limit items matching query, best first; ties keep the order of items.
+// An empty query returns the first limit items unchanged.
+func rankQuickItems(items []quickItem, query string, limit int) []quickItem {
+	if strings.TrimSpace(query) == "" {
+		return items[:min(limit, len(items))]
+	}
+	type scored struct {
+		item  quickItem
+		score int
+	}
+	var hits []scored
+	for _, it := range items {
+		s, ok := fuzzyScore(query, it.Title)
+		if !ok {
+			if s, ok = fuzzyScore(query, it.Kind+" "+it.Title+" "+it.Context); !ok {
+				continue
+			}
+			s -= quickKindPenalty
+		}
+		hits = append(hits, scored{it, s})
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+	out := make([]quickItem, 0, min(limit, len(hits)))
+	for _, h := range hits[:min(limit, len(hits))] {
+		out = append(out, h.item)
+	}
+	return out
+}
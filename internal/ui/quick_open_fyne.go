@@ -0,0 +1,162 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+)
+
+// quickOpenEntry is the query field of the quick-open palette. Up and Down move through the list,
+// Enter opens the highlighted item and Escape closes the palette; other keys edit the query.
+type quickOpenEntry struct {
+	widget.Entry
+	onKey func(ev *fyne.KeyEvent) bool // reports whether it handled the key
+}
+
+func newQuickOpenEntry() *quickOpenEntry {
+	e := &quickOpenEntry{}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// TypedKey hands navigation keys to onKey before the entry sees them.
+func (e *quickOpenEntry) TypedKey(ev *fyne.KeyEvent) {
+	if e.onKey != nil && e.onKey(ev) {
+		return
+	}
+	e.Entry.TypedKey(ev)
+}
+
+// showQuickOpen shows the quick-open palette at the top of the window. The list ranks items with
+// rankQuickItems as the query changes; Enter or a click closes the palette and passes the item to
+// onPick.
+func showQuickOpen(w fyne.Window, items []quickItem, onPick func(quickItem)) {
+	shown := rankQuickItems(items, "", quickOpenLimit)
+	cursor := 0
+	keyNav := false
+	var pop *widget.PopUp
+
+	entry := newQuickOpenEntry()
+	entry.SetPlaceHolder(i18n.T("placeholder.go_to_page_panel_bible_entry"))
+	list := widget.NewList(
+		func() int { return len(shown) },
+		func() fyne.CanvasObject {
+			text := widget.NewLabel("")
+			text.Truncation = fyne.TextTruncateEllipsis
+			kind := widget.NewLabel("")
+			kind.Importance = widget.LowImportance
+			return container.NewBorder(nil, nil, nil, kind, text)
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			if id < 0 || id >= len(shown) {
+				return
+			}
+			c := o.(*fyne.Container)
+			c.Objects[0].(*widget.Label).SetText(shown[id].label())
+			c.Objects[1].(*widget.Label).SetText(shown[id].Kind)
+		},
+	)
+	pick := func(i int) {
+		if i < 0 || i >= len(shown) {
+			return
+		}
+		it := shown[i]
+		pop.Hide()
+		onPick(it)
+	}
+	moveTo := func(i int) {
+		if len(shown) == 0 {
+			return
+		}
+		cursor = max(0, min(i, len(shown)-1))
+		keyNav = true
+		list.Select(cursor)
+		list.ScrollTo(cursor)
+		keyNav = false
+	}
+	list.OnSelected = func(id widget.ListItemID) {
+		cursor = id
+		if !keyNav {
+			pick(id)
+		}
+	}
+	entry.OnChanged = func(q string) {
+		shown = rankQuickItems(items, q, quickOpenLimit)
+		list.UnselectAll()
+		list.Refresh()
+		moveTo(0)
+	}
+	entry.onKey = func(ev *fyne.KeyEvent) bool {
+		switch ev.Name {
+		case fyne.KeyDown:
+			moveTo(cursor + 1)
+		case fyne.KeyUp:
+			moveTo(cursor - 1)
+		case fyne.KeyReturn, fyne.KeyEnter:
+			pick(cursor)
+		case fyne.KeyEscape:
+			pop.Hide()
+		default:
+			return false
+		}
+		return true
+	}
+
+	content := container.NewBorder(entry, nil, nil, nil, list)
+	pop = widget.NewPopUp(content, w.Canvas())
+	size := fyne.NewSize(min(600, w.Canvas().Size().Width-40), min(380, w.Canvas().Size().Height-80))
+	pop.Resize(size)
+	pop.ShowAtPosition(fyne.NewPos((w.Canvas().Size().Width-size.Width)/2, 40))
+	moveTo(0)
+	w.Canvas().Focus(entry)
+}
+
+// menuCommands registers the items of a menu as commands, including the items of submenus with the
+// submenu's label in front ("Vector › Rectangle"). Separators, items without an action and the items
+// in skip are left out; a disabled item's command is disabled too.
+func menuCommands(m *fyne.Menu, skip ...*fyne.MenuItem) []command {
+	var out []command
+	var walk func(prefix string, items []*fyne.MenuItem)
+	walk = func(prefix string, items []*fyne.MenuItem) {
+	next:
+		for _, it := range items {
+			for _, s := range skip {
+				if it == s {
+					continue next
+				}
+			}
+			if it.IsSeparator {
+				continue
+			}
+			if it.ChildMenu != nil {
+				walk(prefix+commandTitle(it.Label)+" › ", it.ChildMenu.Items)
+				continue
+			}
+			if it.Action == nil {
+				continue
+			}
+			item := it
+			out = append(out, command{
+				Title:   prefix + commandTitle(item.Label),
+				Run:     func() { item.Action() },
+				Enabled: func() bool { return !item.Disabled },
+			})
+		}
+	}
+	walk("", m.Items)
+	return out
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func quickFixture() []quickItem {
+	iss := &domain.Issue{Pages: []domain.Page{
+		{Number: 7, Panels: []domain.Panel{{ID: "p3", Notes: "Alice  enters\nthe bar"}, {ID: "p13"}}},
+		{Number: 14},
+	}}
+	bible := domain.Bible{
+		Characters: []domain.BibleCharacter{{Name: "ALICE", Aliases: []string{"Al"}}, {Name: " "}},
+		Locations:  []domain.BibleLocation{{Name: "The Bar"}},
+		Tags:       []domain.BibleTag{{Name: "flashback"}},
+	}
+	cmds := []command{{Group: "Export", Title: "Export Issue as CBZ"}, {Group: "File", Title: "Save"}}
+	return quickOpenItems(iss, bible, cmds)
+}
+
+func TestQuickOpenItems(t *testing.T) {
+	var got []string
+	for _, it := range quickFixture() {
+		got = append(got, it.Kind+": "+it.label())
+	}
+	want := []string{
+		"Page: Page 7 — 2 panels",
+		"Page: Page 14 — 0 panels",
+		"Panel: Panel p3 — Page 7 — Alice enters the bar",
+		"Panel: Panel p13 — Page 7",
+		"Character: ALICE — Character, also Al",
+		"Location: The Bar — Location",
+		"Tag: flashback — Tag",
+		"Command: Export Issue as CBZ — Export menu",
+		"Command: Save — File menu",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("items =\n%v\nwant\n%v", got, want)
+	}
+	if quickOpenItems(nil, domain.Bible{}, nil) != nil {
+		t.Errorf("expected no items without an issue, bible or commands")
+	}
+}
+
+func TestRankQuickItems(t *testing.T) {
+	items := quickFixture()
+	first := func(q string) quickItem {
+		t.Helper()
+		got := rankQuickItems(items, q, quickOpenLimit)
+		if len(got) == 0 {
+			t.Fatalf("%q matched nothing", q)
+		}
+		return got[0]
+	}
+	if it := first("page 14"); it.Kind != quickPage || it.Page != 14 {
+		t.Errorf("page 14 → %+v", it)
+	}
+	if it := first("p3"); it.Kind != quickPanel || it.PanelID != "p3" || it.Page != 7 {
+		t.Errorf("p3 → %+v", it)
+	}
+	if it := first("alice"); it.Kind != quickCharacter || it.Index != 0 {
+		t.Errorf("alice → %+v", it)
+	}
+	if it := first("export cbz"); it.Kind != quickCommand || it.Index != 0 {
+		t.Errorf("export cbz → %+v", it)
+	}
+	// Matches through the kind or context rank below title matches
+	if got := rankQuickItems(items, "location", quickOpenLimit); len(got) != 1 || got[0].Title != "The Bar" {
+		t.Errorf("location → %+v", got)
+	}
+	if got := rankQuickItems(items, "", 3); len(got) != 3 || got[0].Title != "Page 7" {
+		t.Errorf("empty query → %+v", got)
+	}
+	if got := rankQuickItems(items, "zzz", quickOpenLimit); len(got) != 0 {
+		t.Errorf("zzz → %+v", got)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// readerPageHeight is the pixel height pages are rendered at in the reader preview.
+const readerPageHeight = 640
+
+// readerSpread is one view of the reader preview: the indexes of the pages on the left and the right,
+// -1 for an empty side.
+type readerSpread struct {
+	Left, Right int
+}
+
+// readerSpreads pairs the issue's pages as a reader sees them. Page 1 is a recto on its own, then pages
+// face each other in pairs 2-3, 4-5, …; left-to-right issues show the lower page on the left,
+// right-to-left issues mirror that.
+func readerSpreads(iss domain.Issue) []readerSpread {
+	n := len(iss.Pages)
+	if n == 0 {
+		return nil
+	}
+	rtl := readerRTL(iss)
+	pair := func(first, second int) readerSpread {
+		if rtl {
+			return readerSpread{Left: second, Right: first}
+		}
+		return readerSpread{Left: first, Right: second}
+	}
+	out := []readerSpread{pair(-1, 0)}
+	for i := 1; i < n; i += 2 {
+		next := i + 1
+		if next >= n {
+			next = -1
+		}
+		out = append(out, pair(i, next))
+	}
+	return out
+}
+
+func readerRTL(iss domain.Issue) bool {
+	return strings.EqualFold(strings.TrimSpace(iss.ReadingDirection), "rtl")
+}
+
+// readerSpreadOf returns the spread showing the page at index pageIdx, or 0.
+func readerSpreadOf(spreads []readerSpread, pageIdx int) int {
+	for i, s := range spreads {
+		if s.Left == pageIdx || s.Right == pageIdx {
+			return i
+		}
+	}
+	return 0
+}
+
+// readerArrowStep is the spread step of the left or right arrow key: the arrow pointing in reading
+// direction turns forward.
+func readerArrowStep(rtl, right bool) int {
+	if right != rtl {
+		return 1
+	}
+	return -1
+}
+
+// readerMarks returns the page-turn flags of the issue's pages by page number.
+func readerMarks(iss domain.Issue) map[int]storage.PageTurnInfo {
+	out := make(map[int]storage.PageTurnInfo, len(iss.Pages))
+	for _, ti := range storage.ComputePageTurnIndicators(iss) {
+		out[ti.PageNumber] = ti
+	}
+	return out
+}
+
+// readerPageLabel is the caption under a page of the reader preview.
+func readerPageLabel(number int, ti storage.PageTurnInfo) string {
+	parts := []string{i18n.T("label.page_number", number)}
+	if ti.IsTurn {
+		parts = append(parts, i18n.T("reader.page_turn"))
+	}
+	if !ti.HasBeats {
+		parts = append(parts, i18n.T("reader.no_mapped_beats"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// readerTitle describes the spread at position i of n, e.g. "Pages 2–3 (2/4, RTL)".
+func readerTitle(iss domain.Issue, s readerSpread, i, n int) string {
+	var nums []int
+	for _, idx := range []int{s.Left, s.Right} {
+		if idx >= 0 && idx < len(iss.Pages) {
+			nums = append(nums, iss.Pages[idx].Number)
+		}
+	}
+	dir := "LTR"
+	if readerRTL(iss) {
+		dir = "RTL"
+	}
+	pages := "—"
+	switch len(nums) {
+	case 1:
+		pages = i18n.T("label.page_number", nums[0])
+	case 2:
+		pages = i18n.T("reader.pages", min(nums[0], nums[1]), max(nums[0], nums[1]))
+	}
+	return fmt.Sprintf("%s (%d/%d, %s)", pages, i+1, n, dir)
+}
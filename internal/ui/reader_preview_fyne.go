@@ -0,0 +1,142 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/export"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/render"
+	"gocomicwriter/internal/storage"
+)
+
+var (
+	readerTurnColor    = color.NRGBA{R: 230, G: 140, B: 0, A: 255}
+	readerNoBeatsColor = color.NRGBA{R: 220, G: 40, B: 40, A: 70}
+)
+
+// readerSide is one page of the reader preview: the page as exported, framed when it is a page turn
+// and tinted when none of its panels has a mapped beat.
+type readerSide struct {
+	img   *canvas.Image
+	tint  *canvas.Rectangle
+	frame *canvas.Rectangle
+	label *widget.Label
+	box   fyne.CanvasObject
+}
+
+func newReaderSide() *readerSide {
+	s := &readerSide{
+		img:   canvas.NewImageFromImage(nil),
+		tint:  canvas.NewRectangle(color.Transparent),
+		frame: canvas.NewRectangle(color.Transparent),
+		label: widget.NewLabel(""),
+	}
+	s.img.FillMode = canvas.ImageFillContain
+	s.frame.StrokeWidth = 4
+	s.label.Alignment = fyne.TextAlignCenter
+	s.box = container.NewBorder(nil, s.label, nil, nil, container.NewStack(s.tint, container.NewPadded(s.img), s.frame))
+	return s
+}
+
+// show renders the page at index idx of iss, or leaves the side empty for -1.
+func (s *readerSide) show(iss domain.Issue, idx int, marks map[int]storage.PageTurnInfo, scale float64, st render.Style) {
+	s.img.Image = nil
+	s.tint.FillColor, s.frame.StrokeColor = color.Transparent, color.Transparent
+	s.label.SetText("")
+	if idx >= 0 && idx < len(iss.Pages) {
+		pg := iss.Pages[idx]
+		ti := marks[pg.Number]
+		s.img.Image = render.PageImage(pg, iss.TrimWidth, iss.TrimHeight, scale, st)
+		if ti.IsTurn {
+			s.frame.StrokeColor = readerTurnColor
+		}
+		if !ti.HasBeats {
+			s.tint.FillColor = readerNoBeatsColor
+		}
+		s.label.SetText(readerPageLabel(pg.Number, ti))
+	}
+	s.img.Refresh()
+	s.tint.Refresh()
+	s.frame.Refresh()
+}
+
+// showReaderPreview opens a window showing the issue's pages as facing pairs in reading order, starting
+// at the spread of the page at index pageIdx. The arrow keys turn the pages; pages are rendered like
+// the raster exports. The window shows the issue as it was when opened.
+func showReaderPreview(a fyne.App, proj domain.Project, issueIdx, pageIdx int) {
+	if issueIdx < 0 || issueIdx >= len(proj.Issues) {
+		return
+	}
+	iss := proj.Issues[issueIdx]
+	spreads := readerSpreads(iss)
+	if len(spreads) == 0 || iss.TrimWidth <= 0 || iss.TrimHeight <= 0 {
+		return
+	}
+	st := export.PreviewStyle(proj)
+	marks := readerMarks(iss)
+	scale := readerPageHeight / iss.TrimHeight
+	pageSize := fyne.NewSize(float32(iss.TrimWidth*scale*0.75), float32(readerPageHeight*0.75))
+
+	win := a.NewWindow(i18n.T("msg.reader_preview"))
+	left, right := newReaderSide(), newReaderSide()
+	left.img.SetMinSize(pageSize)
+	right.img.SetMinSize(pageSize)
+	title := widget.NewLabel("")
+	title.Alignment = fyne.TextAlignCenter
+	legend := widget.NewLabel(i18n.T("label.orange_frame_page_turn_red_tint"))
+
+	cur := readerSpreadOf(spreads, pageIdx)
+	show := func() {
+		s := spreads[cur]
+		left.show(iss, s.Left, marks, scale, st)
+		right.show(iss, s.Right, marks, scale, st)
+		title.SetText(readerTitle(iss, s, cur, len(spreads)))
+	}
+	step := func(d int) {
+		if n := cur + d; n >= 0 && n < len(spreads) && n != cur {
+			cur = n
+			show()
+		}
+	}
+	rtl := readerRTL(iss)
+	prevBtn := widget.NewButton("◀", func() { step(readerArrowStep(rtl, false)) })
+	nextBtn := widget.NewButton("▶", func() { step(readerArrowStep(rtl, true)) })
+	win.Canvas().SetOnTypedKey(func(ev *fyne.KeyEvent) {
+		switch ev.Name {
+		case fyne.KeyLeft:
+			step(readerArrowStep(rtl, false))
+		case fyne.KeyRight:
+			step(readerArrowStep(rtl, true))
+		case fyne.KeyHome:
+			step(-cur)
+		case fyne.KeyEnd:
+			step(len(spreads) - 1 - cur)
+		case fyne.KeyEscape:
+			win.Close()
+		}
+	})
+
+	top := container.NewBorder(nil, nil, prevBtn, nextBtn, title)
+	pages := container.NewGridWithColumns(2, left.box, right.box)
+	win.SetContent(container.NewBorder(top, legend, nil, nil, container.NewCenter(pages)))
+	show()
+	win.Show()
+}
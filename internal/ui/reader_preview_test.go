@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func readerIssue(n int, dir string) domain.Issue {
+	iss := domain.Issue{ReadingDirection: dir}
+	for i := 1; i <= n; i++ {
+		iss.Pages = append(iss.Pages, domain.Page{Number: i})
+	}
+	return iss
+}
+
+func TestReaderSpreads(t *testing.T) {
+	cases := []struct {
+		n    int
+		dir  string
+		want string
+	}{
+		{0, "ltr", "[]"},
+		{1, "ltr", "[{-1 0}]"},
+		{5, "ltr", "[{-1 0} {1 2} {3 4}]"},
+		{4, "", "[{-1 0} {1 2} {3 -1}]"},
+		{5, "RTL", "[{0 -1} {2 1} {4 3}]"},
+		{4, "rtl", "[{0 -1} {2 1} {-1 3}]"},
+	}
+	for _, c := range cases {
+		if got := fmt.Sprint(readerSpreads(readerIssue(c.n, c.dir))); got != c.want {
+			t.Errorf("readerSpreads(%d, %q) = %s, want %s", c.n, c.dir, got, c.want)
+		}
+	}
+	sp := readerSpreads(readerIssue(5, "ltr"))
+	if readerSpreadOf(sp, 3) != 2 || readerSpreadOf(sp, 0) != 0 || readerSpreadOf(sp, 9) != 0 {
+		t.Fatalf("readerSpreadOf = %d %d %d", readerSpreadOf(sp, 3), readerSpreadOf(sp, 0), readerSpreadOf(sp, 9))
+	}
+}
+
+func TestReaderArrowStep(t *testing.T) {
+	if readerArrowStep(false, true) != 1 || readerArrowStep(false, false) != -1 {
+		t.Fatal("LTR: right should turn forward")
+	}
+	if readerArrowStep(true, false) != 1 || readerArrowStep(true, true) != -1 {
+		t.Fatal("RTL: left should turn forward")
+	}
+}
+
+func TestReaderLabels(t *testing.T) {
+	iss := readerIssue(3, "ltr")
+	iss.Pages[1].Panels = []domain.Panel{{ID: "p1", BeatIDs: []string{"b1"}}}
+	marks := readerMarks(iss)
+	if got := readerPageLabel(1, marks[1]); got != "Page 1 · page turn · no mapped beats" {
+		t.Fatalf("label of page 1 = %q", got)
+	}
+	if got := readerPageLabel(2, marks[2]); got != "Page 2" {
+		t.Fatalf("label of page 2 = %q", got)
+	}
+	if got := readerTitle(iss, readerSpread{Left: 1, Right: 2}, 1, 2); got != "Pages 2–3 (2/2, LTR)" {
+		t.Fatalf("title = %q", got)
+	}
+	rtl := readerIssue(3, "rtl")
+	if got := readerTitle(rtl, readerSpread{Left: 0, Right: -1}, 0, 2); got != "Page 1 (1/2, RTL)" {
+		t.Fatalf("rtl title = %q", got)
+	}
+}
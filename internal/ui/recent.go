@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Preference keys of the recent projects. recent.projects predates pinning and keeps its format, a
+// JSON list of paths, so older versions still read it.
+const (
+	recentPrefsKey = "recent.projects"
+	pinnedPrefsKey = "recent.pinned"
+)
+
+// recentMax caps the unpinned recent projects; pinned projects do not count towards it.
+const recentMax = 10
+
+// prefStore is the part of fyne.Preferences the recent projects and the workspace layout are kept
+// in.
+type prefStore interface {
+	StringWithFallback(key, fallback string) string
+	SetString(key, value string)
+}
+
+// recentList holds the pinned projects in the order they were pinned and the other recently opened
+// projects, newest first. A path is in at most one of the two lists.
+type recentList struct {
+	Pinned []string
+	Recent []string
+}
+
+// recentEntry is one project shown in Open Recent and on the dashboard.
+type recentEntry struct {
+	Path    string
+	Pinned  bool
+	Missing bool // the folder no longer exists, e.g. it was renamed or moved
+}
+
+// samePath compares project paths; case is ignored so that Windows paths typed differently match.
+func samePath(a, b string) bool { return strings.EqualFold(a, b) }
+
+// decodePaths parses a JSON list of paths, dropping blanks and duplicates. Malformed input is empty.
+func decodePaths(raw string) []string {
+	var tmp []string
+	if strings.TrimSpace(raw) != "" {
+		_ = json.Unmarshal([]byte(raw), &tmp)
+	}
+	out := make([]string, 0, len(tmp))
+	for _, s := range tmp {
+		if s = strings.TrimSpace(s); s != "" && indexPath(out, s) < 0 {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// indexPath returns the position of path in paths, or -1.
+func indexPath(paths []string, path string) int {
+	for i, s := range paths {
+		if samePath(s, path) {
+			return i
+		}
+	}
+	return -1
+}
+
+// withoutPath returns paths without path.
+func withoutPath(paths []string, path string) []string {
+	out := make([]string, 0, len(paths))
+	for _, s := range paths {
+		if !samePath(s, path) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// loadRecentList reads the recent projects, including those whose folder is gone.
+func loadRecentList(p prefStore) recentList {
+	r := recentList{
+		Pinned: decodePaths(p.StringWithFallback(pinnedPrefsKey, "")),
+		Recent: decodePaths(p.StringWithFallback(recentPrefsKey, "")),
+	}
+	for _, s := range r.Pinned {
+		r.Recent = withoutPath(r.Recent, s)
+	}
+	return r
+}
+
+// save stores the list, capping the unpinned projects at recentMax.
+func (r recentList) save(p prefStore) {
+	if len(r.Recent) > recentMax {
+		r.Recent = r.Recent[:recentMax]
+	}
+	enc := func(paths []string) string {
+		if paths == nil {
+			paths = []string{}
+		}
+		b, _ := json.Marshal(paths)
+		return string(b)
+	}
+	p.SetString(recentPrefsKey, enc(r.Recent))
+	p.SetString(pinnedPrefsKey, enc(r.Pinned))
+}
+
+// add puts path first among the recent projects; a pinned project keeps its place.
+func (r recentList) add(path string) recentList {
+	if indexPath(r.Pinned, path) >= 0 {
+		return r
+	}
+	r.Recent = append([]string{path}, withoutPath(r.Recent, path)...)
+	if len(r.Recent) > recentMax {
+		r.Recent = r.Recent[:recentMax]
+	}
+	return r
+}
+
+// pin pins path after the other pinned projects, or unpins it back to the top of the recent ones.
+func (r recentList) pin(path string, pinned bool) recentList {
+	if !pinned {
+		if indexPath(r.Pinned, path) < 0 {
+			return r
+		}
+		r.Pinned = withoutPath(r.Pinned, path)
+		return r.add(path)
+	}
+	r.Recent = withoutPath(r.Recent, path)
+	if indexPath(r.Pinned, path) < 0 {
+		r.Pinned = append(append([]string(nil), r.Pinned...), path)
+	}
+	return r
+}
+
+// relocate replaces the path of a moved project with its new place, keeping its position and pin. If
+// the new path is listed already, the old entry is dropped instead.
+func (r recentList) relocate(from, to string) recentList {
+	if samePath(from, to) {
+		return r
+	}
+	move := func(paths []string) []string {
+		out := make([]string, 0, len(paths))
+		for _, s := range paths {
+			if !samePath(s, from) {
+				out = append(out, s)
+			} else if indexPath(r.Pinned, to) < 0 && indexPath(r.Recent, to) < 0 {
+				out = append(out, to)
+			}
+		}
+		return out
+	}
+	return recentList{Pinned: move(r.Pinned), Recent: move(r.Recent)}
+}
+
+// remove drops path from the list.
+func (r recentList) remove(path string) recentList {
+	return recentList{Pinned: withoutPath(r.Pinned, path), Recent: withoutPath(r.Recent, path)}
+}
+
+// isPinned reports whether path is pinned.
+func (r recentList) isPinned(path string) bool { return indexPath(r.Pinned, path) >= 0 }
+
+// entries lists the pinned projects, then the recent ones, marking folders that do not exist.
+func (r recentList) entries() []recentEntry {
+	out := make([]recentEntry, 0, len(r.Pinned)+len(r.Recent))
+	for i, paths := range [][]string{r.Pinned, r.Recent} {
+		for _, s := range paths {
+			_, err := os.Stat(s)
+			out = append(out, recentEntry{Path: s, Pinned: i == 0, Missing: err != nil})
+		}
+	}
+	return out
+}
+
+// addRecentProject records path as the most recently opened project.
+func addRecentProject(p prefStore, path string) {
+	if strings.TrimSpace(path) == "" {
+		return
+	}
+	abs, _ := filepath.Abs(path)
+	loadRecentList(p).add(abs).save(p)
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// mapPrefs is an in-memory prefStore.
+type mapPrefs map[string]string
+
+func (m mapPrefs) StringWithFallback(key, fallback string) string {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	return fallback
+}
+
+func (m mapPrefs) SetString(key, value string) { m[key] = value }
+
+func TestRecentList_PinnedOutlastCap(t *testing.T) {
+	p := mapPrefs{recentPrefsKey: `["/a", " ", "/b", "/A"]`}
+	r := loadRecentList(p)
+	if !reflect.DeepEqual(r.Recent, []string{"/a", "/b"}) || len(r.Pinned) != 0 {
+		t.Fatalf("loaded %+v", r)
+	}
+	r = r.pin("/b", true)
+	for i := 0; i < recentMax+3; i++ {
+		r = r.add(fmt.Sprintf("/p%d", i))
+	}
+	r = r.add("/b") // opening a pinned project does not list it twice
+	if len(r.Recent) != recentMax || !reflect.DeepEqual(r.Pinned, []string{"/b"}) || indexPath(r.Recent, "/b") >= 0 {
+		t.Fatalf("after many opens: %+v", r)
+	}
+	if r.Recent[0] != "/p12" || indexPath(r.Recent, "/a") >= 0 {
+		t.Fatalf("recent order: %v", r.Recent)
+	}
+	r.save(p)
+	if got := loadRecentList(p); !reflect.DeepEqual(got, r) {
+		t.Fatalf("reloaded %+v, want %+v", got, r)
+	}
+	if p[recentPrefsKey][0] != '[' {
+		t.Fatalf("recent projects no longer a JSON list: %q", p[recentPrefsKey])
+	}
+
+	r = r.pin("/b", false)
+	if len(r.Pinned) != 0 || r.Recent[0] != "/b" || len(r.Recent) != recentMax {
+		t.Fatalf("after unpinning: %+v", r)
+	}
+}
+
+func TestRecentList_MissingAndRelocate(t *testing.T) {
+	dir := t.TempDir()
+	here := filepath.Join(dir, "here")
+	if err := os.Mkdir(here, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	gone := filepath.Join(dir, "gone")
+	r := recentList{Pinned: []string{gone}, Recent: []string{here}}
+	want := []recentEntry{{Path: gone, Pinned: true, Missing: true}, {Path: here}}
+	if got := r.entries(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("entries = %+v, want %+v", got, want)
+	}
+	moved := filepath.Join(dir, "moved")
+	r = r.relocate(gone, moved)
+	if !reflect.DeepEqual(r.Pinned, []string{moved}) || !r.isPinned(moved) {
+		t.Fatalf("relocated %+v", r)
+	}
+	// Locating a project at a path already listed merges the two entries.
+	r = r.relocate(moved, here)
+	if len(r.Pinned) != 0 || !reflect.DeepEqual(r.Recent, []string{here}) {
+		t.Fatalf("merged %+v", r)
+	}
+	if r = r.remove(here); len(r.entries()) != 0 {
+		t.Fatalf("removed %+v", r)
+	}
+}
+
+func TestAddRecentProject(t *testing.T) {
+	p := mapPrefs{}
+	addRecentProject(p, "")
+	if len(p) != 0 {
+		t.Fatalf("blank path stored: %v", p)
+	}
+	dir := t.TempDir()
+	addRecentProject(p, dir)
+	addRecentProject(p, filepath.Join(dir, "other"))
+	addRecentProject(p, dir)
+	if got := loadRecentList(p).Recent; !reflect.DeepEqual(got, []string{dir, filepath.Join(dir, "other")}) {
+		t.Fatalf("recent = %v", got)
+	}
+}
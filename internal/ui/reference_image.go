@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+// minReferenceOpacity keeps a reference image from being set fully invisible by accident.
+const minReferenceOpacity = 0.05
+
+// pageReference is a page's reference image resolved for display. The zero value means none.
+type pageReference struct {
+	path    string // absolute file path
+	opacity float64
+	stretch bool
+	bleed   bool // stretched over the trim box plus bleed, like imported page art
+	missing bool // the file is gone or cannot be decoded; a placeholder is shown instead
+}
+
+// resolveReference resolves ref against the project root and checks that the file can be shown.
+// Only the image header is read, so this is cheap enough to call on every page switch.
+func resolveReference(root string, ref *domain.ReferenceImage) pageReference {
+	if ref == nil || strings.TrimSpace(ref.Asset) == "" {
+		return pageReference{}
+	}
+	path := storage.AssetFilePath(root, ref.Asset)
+	return pageReference{
+		path:    path,
+		opacity: referenceOpacity(ref.Opacity),
+		stretch: ref.Fit == domain.ReferenceFitStretch || ref.Fit == domain.ReferenceFitBleed,
+		bleed:   ref.Fit == domain.ReferenceFitBleed,
+		missing: !referenceReadable(path),
+	}
+}
+
+// referenceOpacity applies the default to an unset opacity and clamps it to [minReferenceOpacity, 1].
+func referenceOpacity(o float64) float64 {
+	if o <= 0 {
+		return domain.DefaultReferenceOpacity
+	}
+	return min(max(o, minReferenceOpacity), 1)
+}
+
+// referenceReadable reports whether path is a file that can be decoded as an image. SVG files are
+// only checked for existence.
+func referenceReadable(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = f.Close() }()
+	if st, err := f.Stat(); err != nil || st.IsDir() {
+		return false
+	}
+	if strings.EqualFold(filepath.Ext(path), ".svg") {
+		return true
+	}
+	_, _, err = image.DecodeConfig(f)
+	return err == nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+)
+
+func TestResolveReference(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "assets"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(root, "assets", "thumb.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, image.NewGray(image.Rect(0, 0, 4, 6))); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+	if err := os.WriteFile(filepath.Join(root, "assets", "broken.jpg"), []byte("not an image"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := resolveReference(root, nil); r != (pageReference{}) {
+		t.Fatalf("nil reference resolved to %+v", r)
+	}
+	r := resolveReference(root, &domain.ReferenceImage{Asset: "assets/thumb.png"})
+	if r.missing || r.path != filepath.Join(root, "assets", "thumb.png") || r.opacity != domain.DefaultReferenceOpacity || r.stretch {
+		t.Fatalf("thumb = %+v", r)
+	}
+	r = resolveReference(root, &domain.ReferenceImage{Asset: "assets/thumb.png", Opacity: 0.8, Fit: domain.ReferenceFitStretch})
+	if r.opacity != 0.8 || !r.stretch || r.bleed {
+		t.Fatalf("stretched thumb = %+v", r)
+	}
+	if r = resolveReference(root, &domain.ReferenceImage{Asset: "assets/thumb.png", Fit: domain.ReferenceFitBleed}); !r.stretch || !r.bleed {
+		t.Fatalf("bleed thumb = %+v", r)
+	}
+	// A deleted or corrupt file degrades to a placeholder instead of failing
+	for _, asset := range []string{"assets/gone.png", "assets/broken.jpg", "assets"} {
+		if r := resolveReference(root, &domain.ReferenceImage{Asset: asset}); !r.missing || r.path == "" {
+			t.Errorf("%s: %+v", asset, r)
+		}
+	}
+}
+
+func TestReferenceOpacity(t *testing.T) {
+	for in, want := range map[float64]float64{0: domain.DefaultReferenceOpacity, -1: domain.DefaultReferenceOpacity, 0.01: minReferenceOpacity, 0.3: 0.3, 1.5: 1} {
+		if got := referenceOpacity(in); got != want {
+			t.Errorf("referenceOpacity(%v) = %v, want %v", in, got, want)
+		}
+	}
+}
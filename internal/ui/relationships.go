@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// appearanceSummary sums up a character's appearances over the relationship lists.
+func appearanceSummary(a storage.Appearance) string {
+	return i18n.T("relations.summary", i18n.N("relations.lines", a.Count), i18n.N("relations.scenes", len(a.Scenes)), i18n.N("relations.pages", len(a.Pages)))
+}
+
+// pageRefLabel names a page in the relationships tab; the issue is only given when the project has
+// more than one.
+func pageRefLabel(p storage.PageRef, issues int) string {
+	if issues > 1 {
+		return i18n.T("relations.issue_page", p.Issue+1, p.Page)
+	}
+	return i18n.T("relations.page", p.Page)
+}
+
+// sceneRefLabel names a scene by its heading and line.
+func sceneRefLabel(s storage.SceneRef) string {
+	return i18n.T("relations.scene", s.Title, s.LineNo)
+}
+
+// coAppearanceLabel is a row of the co-appearing characters list.
+func coAppearanceLabel(c storage.CoAppearance) string {
+	return i18n.N("relations.co_appearance", c.SharedScenes, c.Name, c.SharedScenes)
+}
@@ -0,0 +1,134 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// relationshipsView is the Relationships tab of the bible pane. For the character picked in its
+// selector it lists the scenes and pages they appear on and the characters they share scenes with.
+// Selecting a page calls OnPage, selecting a scene OnScene with the line of its heading.
+type relationshipsView struct {
+	OnPage  func(storage.PageRef)
+	OnScene func(lineNo int)
+
+	report  storage.AppearanceReport
+	issues  int
+	current storage.Appearance
+	co      []storage.CoAppearance
+
+	charSel *widget.Select
+	summary *widget.Label
+	scenes  *widget.List
+	pages   *widget.List
+	others  *widget.List
+	content fyne.CanvasObject
+}
+
+func newRelationshipsView() *relationshipsView {
+	v := &relationshipsView{}
+	v.charSel = widget.NewSelect(nil, v.show)
+	v.charSel.PlaceHolder = i18n.T("placeholder.select_character")
+	v.summary = widget.NewLabel("")
+	v.summary.Wrapping = fyne.TextWrapWord
+	v.scenes = widget.NewList(
+		func() int { return len(v.current.Scenes) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(sceneRefLabel(v.current.Scenes[id]))
+		},
+	)
+	v.scenes.OnSelected = func(id widget.ListItemID) {
+		v.scenes.Unselect(id)
+		if v.OnScene != nil && id < len(v.current.Scenes) {
+			v.OnScene(v.current.Scenes[id].LineNo)
+		}
+	}
+	v.pages = widget.NewList(
+		func() int { return len(v.current.Pages) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(pageRefLabel(v.current.Pages[id], v.issues))
+		},
+	)
+	v.pages.OnSelected = func(id widget.ListItemID) {
+		v.pages.Unselect(id)
+		if v.OnPage != nil && id < len(v.current.Pages) {
+			v.OnPage(v.current.Pages[id])
+		}
+	}
+	v.others = widget.NewList(
+		func() int { return len(v.co) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(coAppearanceLabel(v.co[id]))
+		},
+	)
+	v.others.OnSelected = func(id widget.ListItemID) {
+		v.others.Unselect(id)
+		if id < len(v.co) {
+			v.charSel.SetSelected(v.co[id].Name)
+		}
+	}
+	column := func(title string, list *widget.List) fyne.CanvasObject {
+		return container.NewBorder(widget.NewLabel(title), nil, nil, nil, list)
+	}
+	top := container.NewVBox(widget.NewForm(widget.NewFormItem(i18n.T("label.character"), v.charSel)), v.summary)
+	v.content = container.NewBorder(top, nil, nil, nil, container.NewGridWithColumns(3,
+		column(i18n.T("relations.scenes_title"), v.scenes),
+		column(i18n.T("relations.pages_title"), v.pages),
+		column(i18n.T("relations.co_title"), v.others)))
+	return v
+}
+
+// SetReport shows a freshly computed report of a project with the given number of issues, keeping the
+// selected character when they are still in it.
+func (v *relationshipsView) SetReport(rep storage.AppearanceReport, issues int) {
+	v.report, v.issues = rep, issues
+	names := make([]string, len(rep.Characters))
+	for i, a := range rep.Characters {
+		names[i] = a.Name
+	}
+	sel := v.charSel.Selected
+	v.charSel.Options = names
+	v.charSel.Refresh()
+	if _, ok := rep.Character(sel); ok {
+		v.show(sel)
+		return
+	}
+	v.charSel.ClearSelected()
+	v.show("")
+}
+
+// show fills the lists for the character named name.
+func (v *relationshipsView) show(name string) {
+	v.current, v.co = storage.Appearance{}, nil
+	switch a, ok := v.report.Character(name); {
+	case ok:
+		v.current, v.co = a, v.report.CoAppearances(name)
+		v.summary.SetText(appearanceSummary(a))
+	case len(v.report.Characters) == 0:
+		v.summary.SetText(i18n.T("relations.no_characters"))
+	default:
+		v.summary.SetText("")
+	}
+	v.scenes.Refresh()
+	v.pages.Refresh()
+	v.others.Refresh()
+}
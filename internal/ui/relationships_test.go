@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+func TestRelationshipLabels(t *testing.T) {
+	i18n.SetLocale("en")
+	a := storage.Appearance{Name: "Alice", Count: 1, Scenes: []storage.SceneRef{{Title: "Roof", LineNo: 3}}, Pages: []storage.PageRef{{Page: 2}, {Page: 3}}}
+	if got, want := appearanceSummary(a), "1 line in 1 scene on 2 pages"; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+	if got := pageRefLabel(storage.PageRef{Issue: 1, Page: 4}, 1); got != "Page 4" {
+		t.Errorf("single issue page = %q", got)
+	}
+	if got := pageRefLabel(storage.PageRef{Issue: 1, Page: 4}, 2); got != "Issue 2, page 4" {
+		t.Errorf("issue page = %q", got)
+	}
+	if got := sceneRefLabel(a.Scenes[0]); got != "Roof (line 3)" {
+		t.Errorf("scene = %q", got)
+	}
+	if got := coAppearanceLabel(storage.CoAppearance{Name: "Bob", SharedScenes: 2}); got != "Bob — 2 shared scenes" {
+		t.Errorf("co-appearance = %q", got)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"strings"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/script"
+)
+
+// hlLine is the cached highlighting of one script line.
+type hlLine struct {
+	text  string
+	spans []script.Span
+	cont  bool // continuation state after this line, see script.HighlightLine
+}
+
+// scriptHighlighter keeps per-line highlighting of the script editor. An edit only re-highlights the
+// lines it changed, plus following lines whose continuation state flipped, so typing stays cheap in
+// long scripts.
+type scriptHighlighter struct {
+	lines []hlLine
+	// highlighted counts the lines highlighted by the last update (for tests and diagnostics).
+	highlighted int
+}
+
+// update re-highlights text and returns the range [from, to) of lines whose highlighting may differ
+// from before (including lines that moved).
+func (h *scriptHighlighter) update(text string) (from, to int) {
+	src := strings.Split(text, "\n")
+	old := h.lines
+	// Unchanged head and tail of the document
+	head := 0
+	for head < len(src) && head < len(old) && src[head] == old[head].text {
+		head++
+	}
+	tail := 0
+	for tail < len(src)-head && tail < len(old)-head && src[len(src)-1-tail] == old[len(old)-1-tail].text {
+		tail++
+	}
+	lines := make([]hlLine, len(src))
+	copy(lines, old[:head])
+	cont := head > 0 && lines[head-1].cont
+	h.highlighted = 0
+	i := head
+	for ; i < len(src); i++ {
+		if i >= len(src)-tail {
+			// Past the edit: reuse the cached line once it starts from the state it was highlighted with
+			o := len(old) - (len(src) - i)
+			if prev := o > 0 && old[o-1].cont; prev == cont {
+				copy(lines[i:], old[o:])
+				break
+			}
+		}
+		lines[i].text = src[i]
+		lines[i].spans, lines[i].cont = script.HighlightLine(src[i], cont)
+		cont = lines[i].cont
+		h.highlighted++
+	}
+	h.lines = lines
+	to = i
+	if len(src) != len(old) {
+		to = len(src) // lines after the edit moved
+	}
+	return head, to
+}
+
+// line returns the cached highlighting of line i (0-based), or nil past the end.
+func (h *scriptHighlighter) line(i int) *hlLine {
+	if i < 0 || i >= len(h.lines) {
+		return nil
+	}
+	return &h.lines[i]
+}
+
+// scriptErrorLabel formats a parse error for the editor's error list.
+func scriptErrorLabel(e script.Error) string {
+	if e.Column > 1 {
+		return i18n.T("script.error_at_column", e.Line, e.Column, e.Message)
+	}
+	return i18n.T("script.error_at_line", e.Line, e.Message)
+}
+
+// visibleLines returns the 0-based range [first, last) of lines of height lineH that intersect a
+// viewport starting at offsetY (measured from the first line's top) with the given height.
+func visibleLines(offsetY, viewH, lineH float32, count int) (first, last int) {
+	if lineH <= 0 || count == 0 {
+		return 0, 0
+	}
+	first = max(int(offsetY/lineH), 0)
+	last = min(int((offsetY+viewH)/lineH)+1, count)
+	return min(first, last), last
+}
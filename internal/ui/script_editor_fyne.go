@@ -0,0 +1,322 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"image/color"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"gocomicwriter/internal/script"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// spanColors are the translucent highlight colors per span kind, drawn over the entry text.
+var spanColors = map[script.SpanKind]color.Color{
+	script.SpanScene:     color.RGBA{R: 0, G: 120, B: 255, A: 60},
+	script.SpanCharacter: color.RGBA{R: 60, G: 170, B: 90, A: 60},
+	script.SpanCaption:   color.RGBA{R: 150, G: 90, B: 200, A: 60},
+	script.SpanBeat:      color.RGBA{R: 255, G: 160, B: 0, A: 60},
+	script.SpanNote:      color.RGBA{R: 128, G: 128, B: 128, A: 50},
+	script.SpanTag:       color.RGBA{R: 0, G: 170, B: 170, A: 70},
+}
+
+// errorBand marks lines that have parse errors.
+var errorBand = color.RGBA{R: 220, G: 0, B: 0, A: 40}
+
+// findColor and findCurrentColor mark the find bar's matches and the one it is on.
+var (
+	findColor        = color.RGBA{R: 255, G: 220, B: 0, A: 70}
+	findCurrentColor = color.RGBA{R: 255, G: 140, B: 0, A: 140}
+)
+
+// scriptEditor is the script text editor: a monospace entry with a line-number gutter and syntax
+// highlighting. The entry never scrolls itself; it sits with the gutter and the highlight overlay in
+// one scroll container so all three move together. Gutter and overlay only draw the rows in view.
+type scriptEditor struct {
+	widget.BaseWidget
+	entry   *widget.Entry
+	scroll  *container.Scroll
+	gutter  *scriptLayer
+	overlay *scriptLayer
+	hl      scriptHighlighter
+	marks   map[int]bool // 1-based lines with errors
+	finds   []findMatch  // find bar matches in text order
+	findCur int          // index of the current match in finds, or -1
+
+	// Row metrics of the entry text, measured once the theme is available
+	lineH, top, left, charW float32
+
+	// OnChanged is called after every edit, like widget.Entry.OnChanged.
+	OnChanged func(string)
+	// onEdit is the find bar's hook, called after every edit before OnChanged.
+	onEdit func(string)
+}
+
+func newScriptEditor() *scriptEditor {
+	ed := &scriptEditor{marks: map[int]bool{}, findCur: -1}
+	ed.entry = widget.NewMultiLineEntry()
+	ed.entry.TextStyle = fyne.TextStyle{Monospace: true}
+	ed.entry.Wrapping = fyne.TextWrapOff
+	ed.entry.Scroll = container.ScrollNone
+	ed.entry.OnChanged = func(s string) {
+		ed.sync(s)
+		if ed.onEdit != nil {
+			ed.onEdit(s)
+		}
+		if ed.OnChanged != nil {
+			ed.OnChanged(s)
+		}
+	}
+	ed.entry.OnCursorChanged = func() { ed.reveal(ed.entry.CursorRow, ed.entry.CursorColumn) }
+	ed.gutter = newScriptLayer(ed, ed.drawGutter, ed.gutterWidth)
+	ed.overlay = newScriptLayer(ed, ed.drawOverlay, nil)
+	content := container.NewBorder(nil, nil, ed.gutter, nil, container.NewStack(ed.entry, ed.overlay))
+	ed.scroll = container.NewScroll(content)
+	ed.scroll.OnScrolled = func(fyne.Position) {
+		ed.gutter.Refresh()
+		ed.overlay.Refresh()
+	}
+	ed.hl.update("")
+	ed.ExtendBaseWidget(ed)
+	return ed
+}
+
+func (ed *scriptEditor) CreateRenderer() fyne.WidgetRenderer {
+	ed.measure()
+	return widget.NewSimpleRenderer(ed.scroll)
+}
+
+// Text returns the script text.
+func (ed *scriptEditor) Text() string { return ed.entry.Text }
+
+// SetText replaces the script text.
+func (ed *scriptEditor) SetText(s string) {
+	ed.entry.SetText(s)
+	ed.sync(s)
+}
+
+// SetPlaceHolder sets the hint shown while the script is empty.
+func (ed *scriptEditor) SetPlaceHolder(s string) { ed.entry.SetPlaceHolder(s) }
+
+// SetMarks flags the lines of errs in the gutter and the text.
+func (ed *scriptEditor) SetMarks(errs []script.Error) {
+	marks := make(map[int]bool, len(errs))
+	for _, e := range errs {
+		marks[e.Line] = true
+	}
+	ed.marks = marks
+	ed.gutter.Refresh()
+	ed.overlay.Refresh()
+}
+
+// GoToLine moves the caret to the 1-based line and column, scrolls it into view and focuses the editor.
+func (ed *scriptEditor) GoToLine(line, col int) {
+	ed.entry.CursorRow = max(line-1, 0)
+	ed.entry.CursorColumn = max(col-1, 0)
+	ed.entry.Refresh()
+	ed.reveal(ed.entry.CursorRow, ed.entry.CursorColumn)
+	if c := fyne.CurrentApp().Driver().CanvasForObject(ed.entry); c != nil {
+		c.Focus(ed.entry)
+	}
+}
+
+// SetFindMatches highlights the find bar's matches, cur being the current one (-1 for none).
+func (ed *scriptEditor) SetFindMatches(ms []findMatch, cur int) {
+	ed.finds, ed.findCur = ms, cur
+	ed.overlay.Refresh()
+}
+
+// ShowMatch moves the caret to the start of m and scrolls it into view, leaving the focus where it is.
+func (ed *scriptEditor) ShowMatch(m findMatch) {
+	ed.entry.CursorRow, ed.entry.CursorColumn = m.Row, m.Col
+	ed.entry.Refresh()
+	ed.reveal(m.Row, m.Col+m.Runes) // the whole match if it fits, its start in any case
+	ed.reveal(m.Row, m.Col)
+}
+
+// sync re-highlights the lines an edit changed and redraws only if they, or the line count, matter
+// for what is in view.
+func (ed *scriptEditor) sync(s string) {
+	n := len(ed.hl.lines)
+	from, to := ed.hl.update(s)
+	if len(strconv.Itoa(n)) != len(strconv.Itoa(len(ed.hl.lines))) {
+		ed.scroll.Content.Refresh() // the gutter changed width
+	} else if len(ed.hl.lines) != n {
+		ed.gutter.Refresh()
+	}
+	if first, last := ed.visible(); from < last && to > first {
+		ed.overlay.Refresh()
+	}
+}
+
+// measure calibrates row metrics against an entry of the same style, so the overlay lines up with
+// the text whatever the theme's padding.
+func (ed *scriptEditor) measure() {
+	probe := widget.NewMultiLineEntry()
+	probe.TextStyle = ed.entry.TextStyle
+	probe.Wrapping = fyne.TextWrapOff
+	probe.Scroll = container.ScrollNone
+	probe.SetMinRowsVisible(1)
+	probe.SetText("M")
+	one := probe.MinSize()
+	probe.SetText("M\nM")
+	two := probe.MinSize()
+	char := fyne.MeasureText("M", theme.TextSize(), ed.entry.TextStyle)
+	ed.charW = char.Width
+	ed.lineH = two.Height - one.Height
+	ed.top = (one.Height - ed.lineH) / 2
+	ed.left = (one.Width - ed.charW) / 2
+	if ed.lineH <= 0 {
+		ed.lineH = char.Height + theme.LineSpacing()
+		ed.top = theme.InnerPadding()
+	}
+	if ed.left <= 0 {
+		ed.left = theme.InnerPadding()
+	}
+}
+
+// visible returns the 0-based range of lines in view.
+func (ed *scriptEditor) visible() (first, last int) {
+	if ed.scroll == nil {
+		return 0, 0
+	}
+	return visibleLines(ed.scroll.Offset.Y-ed.top, ed.scroll.Size().Height, ed.lineH, len(ed.hl.lines))
+}
+
+// reveal scrolls so the caret at row and col is in view.
+func (ed *scriptEditor) reveal(row, col int) {
+	if ed.lineH <= 0 {
+		return
+	}
+	off, view := ed.scroll.Offset, ed.scroll.Size()
+	y := ed.top + float32(row)*ed.lineH
+	if y < off.Y {
+		off.Y = y
+	} else if y+ed.lineH > off.Y+view.Height {
+		off.Y = y + ed.lineH - view.Height
+	}
+	x := ed.gutterWidth() + ed.left + float32(col)*ed.charW
+	if x < off.X {
+		off.X = float32(col) * ed.charW // keep the caret just right of the gutter
+	} else if x+ed.charW > off.X+view.Width {
+		off.X = x + ed.charW - view.Width
+	}
+	if off == ed.scroll.Offset {
+		return
+	}
+	ed.scroll.Offset = off
+	ed.scroll.Refresh()
+	ed.gutter.Refresh()
+	ed.overlay.Refresh()
+}
+
+func (ed *scriptEditor) gutterWidth() float32 {
+	digits := max(len(strconv.Itoa(len(ed.hl.lines))), 3)
+	return float32(digits)*ed.charW + 2*theme.InnerPadding()
+}
+
+// drawGutter draws the numbers of the lines in view, in the error color for lines with errors.
+func (ed *scriptEditor) drawGutter(size fyne.Size) []fyne.CanvasObject {
+	first, last := ed.visible()
+	objs := make([]fyne.CanvasObject, 0, last-first)
+	for i := first; i < last; i++ {
+		col := theme.Color(theme.ColorNameDisabled)
+		if ed.marks[i+1] {
+			col = theme.Color(theme.ColorNameError)
+		}
+		t := canvas.NewText(strconv.Itoa(i+1), col)
+		t.TextStyle = ed.entry.TextStyle
+		t.Alignment = fyne.TextAlignTrailing
+		t.Move(fyne.NewPos(0, ed.top+float32(i)*ed.lineH))
+		t.Resize(fyne.NewSize(size.Width-theme.InnerPadding(), ed.lineH))
+		objs = append(objs, t)
+	}
+	return objs
+}
+
+// drawOverlay draws error bands, highlight spans and find matches for the lines in view.
+func (ed *scriptEditor) drawOverlay(size fyne.Size) []fyne.CanvasObject {
+	first, last := ed.visible()
+	var objs []fyne.CanvasObject
+	for i := sort.Search(len(ed.finds), func(i int) bool { return ed.finds[i].Row >= first }); i < len(ed.finds) && ed.finds[i].Row < last; i++ {
+		m, col := ed.finds[i], findColor
+		if i == ed.findCur {
+			col = findCurrentColor
+		}
+		r := canvas.NewRectangle(col)
+		r.Move(fyne.NewPos(ed.left+float32(m.Col)*ed.charW, ed.top+float32(m.Row)*ed.lineH))
+		r.Resize(fyne.NewSize(float32(m.Runes)*ed.charW, ed.lineH))
+		objs = append(objs, r)
+	}
+	for i := first; i < last; i++ {
+		y := ed.top + float32(i)*ed.lineH
+		if ed.marks[i+1] {
+			band := canvas.NewRectangle(errorBand)
+			band.Move(fyne.NewPos(0, y))
+			band.Resize(fyne.NewSize(size.Width, ed.lineH))
+			objs = append(objs, band)
+		}
+		ln := ed.hl.line(i)
+		for _, sp := range ln.spans {
+			r := canvas.NewRectangle(spanColors[sp.Kind])
+			x := ed.left + float32(utf8.RuneCountInString(ln.text[:sp.Start]))*ed.charW
+			r.Move(fyne.NewPos(x, y))
+			r.Resize(fyne.NewSize(float32(utf8.RuneCountInString(ln.text[sp.Start:sp.End]))*ed.charW, ed.lineH))
+			objs = append(objs, r)
+		}
+	}
+	return objs
+}
+
+// scriptLayer is a passive widget that draws per-line decorations of a scriptEditor. It handles no
+// input, so taps and typing reach the entry underneath.
+type scriptLayer struct {
+	widget.BaseWidget
+	ed    *scriptEditor
+	draw  func(size fyne.Size) []fyne.CanvasObject
+	width func() float32 // fixed minimum width, or nil
+}
+
+func newScriptLayer(ed *scriptEditor, draw func(fyne.Size) []fyne.CanvasObject, width func() float32) *scriptLayer {
+	l := &scriptLayer{ed: ed, draw: draw, width: width}
+	l.ExtendBaseWidget(l)
+	return l
+}
+
+func (l *scriptLayer) CreateRenderer() fyne.WidgetRenderer {
+	return &scriptLayerRenderer{l: l}
+}
+
+type scriptLayerRenderer struct {
+	l       *scriptLayer
+	objects []fyne.CanvasObject
+}
+
+func (r *scriptLayerRenderer) Destroy()                     {}
+func (r *scriptLayerRenderer) Objects() []fyne.CanvasObject { return r.objects }
+func (r *scriptLayerRenderer) Layout(size fyne.Size)        { r.objects = r.l.draw(size) }
+func (r *scriptLayerRenderer) Refresh()                     { r.Layout(r.l.Size()); canvas.Refresh(r.l) }
+
+func (r *scriptLayerRenderer) MinSize() fyne.Size {
+	if r.l.width == nil {
+		return fyne.Size{}
+	}
+	return fyne.NewSize(r.l.width(), 0)
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/script"
+)
+
+// bigScript builds a script of n scenes with a dialogue line and an indented continuation each.
+func bigScript(n int) []string {
+	var lines []string
+	for i := 0; i < n; i++ {
+		lines = append(lines, fmt.Sprintf("# Scene %d", i), "ALICE: Hello @Bob", "  still talking", "")
+	}
+	return lines
+}
+
+func TestScriptHighlighterIncremental(t *testing.T) {
+	lines := bigScript(2500) // 10k lines
+	var h scriptHighlighter
+	h.update(strings.Join(lines, "\n"))
+	if h.highlighted != len(lines) {
+		t.Fatalf("initial update highlighted %d lines, want %d", h.highlighted, len(lines))
+	}
+
+	// Typing inside a dialogue line touches only that line
+	lines[5] = "ALICE: Hello @Bob!"
+	from, to := h.update(strings.Join(lines, "\n"))
+	if h.highlighted != 1 || from != 5 || to != 6 {
+		t.Fatalf("edit re-highlighted %d lines, range [%d,%d)", h.highlighted, from, to)
+	}
+
+	// Breaking the dialogue turns the continuation below into plain text; the blank line after it is
+	// re-checked with the new state, then the rest is reused
+	lines[5] = "just text"
+	h.update(strings.Join(lines, "\n"))
+	if h.highlighted != 3 {
+		t.Fatalf("state change re-highlighted %d lines, want 3", h.highlighted)
+	}
+	if got := h.line(6).spans; got != nil {
+		t.Fatalf("orphaned continuation should not be highlighted, got %v", got)
+	}
+
+	// Inserting a line shifts the rest without re-highlighting it
+	lines = append(lines[:9], append([]string{"; note"}, lines[9:]...)...)
+	from, to = h.update(strings.Join(lines, "\n"))
+	if h.highlighted != 1 || from != 9 || to != len(lines) {
+		t.Fatalf("insert re-highlighted %d lines, range [%d,%d)", h.highlighted, from, to)
+	}
+
+	// The cache always matches highlighting from scratch
+	var fresh scriptHighlighter
+	fresh.update(strings.Join(lines, "\n"))
+	if !reflect.DeepEqual(h.lines, fresh.lines) {
+		t.Fatalf("incremental highlighting diverged from a full pass")
+	}
+	if h.line(-1) != nil || h.line(len(lines)) != nil {
+		t.Fatalf("line out of range should be nil")
+	}
+}
+
+func TestScriptErrorLabel(t *testing.T) {
+	if got := scriptErrorLabel(script.Error{Line: 12, Column: 3, Message: "dialogue has no text"}); got != "Line 12:3: dialogue has no text" {
+		t.Fatalf("label = %q", got)
+	}
+	if got := scriptErrorLabel(script.Error{Line: 4, Column: 1, Message: "scene heading has no title"}); got != "Line 4: scene heading has no title" {
+		t.Fatalf("label = %q", got)
+	}
+}
+
+func TestVisibleLines(t *testing.T) {
+	cases := []struct {
+		off, view, lineH float32
+		count            int
+		first, last      int
+	}{
+		{0, 100, 20, 50, 0, 6},
+		{45, 100, 20, 50, 2, 8},
+		{900, 200, 20, 50, 45, 50},
+		{2000, 100, 20, 50, 50, 50},
+		{0, 100, 0, 50, 0, 0},
+	}
+	for _, c := range cases {
+		if f, l := visibleLines(c.off, c.view, c.lineH, c.count); f != c.first || l != c.last {
+			t.Errorf("visibleLines(%v,%v,%v,%d) = %d,%d want %d,%d", c.off, c.view, c.lineH, c.count, f, l, c.first, c.last)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// findOptions are the toggles of the script find bar.
+type findOptions struct {
+	CaseSensitive bool
+	WholeWord     bool
+}
+
+// findMatch is one match in the script: byte offsets [Start, End) into the text, and the 0-based row
+// and rune column of its start and its length in runes, as the entry counts them.
+type findMatch struct {
+	Start, End int
+	Row, Col   int
+	Runes      int
+}
+
+// matcher finds the next match of a query in text at or after byte offset from. Literal queries are
+// the only kind for now; a regular-expression matcher only has to implement this too.
+type matcher interface {
+	next(text string, from int) (start, end int, ok bool)
+}
+
+// literalMatcher matches the query as plain text.
+type literalMatcher struct {
+	query string
+	fold  *regexp.Regexp // case-insensitive matching, nil when case matters
+}
+
+func newLiteralMatcher(query string, caseSensitive bool) literalMatcher {
+	m := literalMatcher{query: query}
+	if !caseSensitive {
+		m.fold = regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+	}
+	return m
+}
+
+func (m literalMatcher) next(text string, from int) (int, int, bool) {
+	if m.fold != nil {
+		loc := m.fold.FindStringIndex(text[from:])
+		if loc == nil {
+			return 0, 0, false
+		}
+		return from + loc[0], from + loc[1], true
+	}
+	i := strings.Index(text[from:], m.query)
+	if i < 0 {
+		return 0, 0, false
+	}
+	return from + i, from + i + len(m.query), true
+}
+
+// findMatches returns the non-overlapping matches of query in text, in text order. An empty query
+// matches nothing. With WholeWord a match must not touch a letter, digit or underscore on either side.
+func findMatches(text, query string, opts findOptions) []findMatch {
+	if query == "" {
+		return nil
+	}
+	return collectMatches(text, newLiteralMatcher(query, opts.CaseSensitive), opts.WholeWord)
+}
+
+func collectMatches(text string, m matcher, wholeWord bool) []findMatch {
+	var out []findMatch
+	row, col, pos := 0, 0, 0 // position reached by the row and column count
+	for from := 0; from <= len(text); {
+		start, end, ok := m.next(text, from)
+		if !ok {
+			break
+		}
+		if end == start || (wholeWord && !isWordBoundary(text, start, end)) {
+			// Retry one rune further so an empty or partial-word match cannot stall the scan
+			_, size := utf8.DecodeRuneInString(text[start:])
+			from = start + max(size, 1)
+			continue
+		}
+		for _, r := range text[pos:start] {
+			if r == '\n' {
+				row, col = row+1, 0
+			} else {
+				col++
+			}
+		}
+		pos = start
+		out = append(out, findMatch{Start: start, End: end, Row: row, Col: col, Runes: utf8.RuneCountInString(text[start:end])})
+		from = end
+	}
+	return out
+}
+
+// isWordBoundary reports whether text[start:end] is neither preceded nor followed by a word rune.
+func isWordBoundary(text string, start, end int) bool {
+	if r, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isWordRune(r) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isWordRune(r) {
+		return false
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// replaceMatches returns text with every match in ms, which must be in text order, replaced by repl.
+// It copies the text once, so replacing thousands of matches in a long script stays linear.
+func replaceMatches(text string, ms []findMatch, repl string) string {
+	if len(ms) == 0 {
+		return text
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	last := 0
+	for _, m := range ms {
+		b.WriteString(text[last:m.Start])
+		b.WriteString(repl)
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// matchAfter returns the index of the first match starting at or after row and col, wrapping to the
+// first match; -1 when there are none.
+func matchAfter(ms []findMatch, row, col int) int {
+	for i, m := range ms {
+		if m.Row > row || (m.Row == row && m.Col >= col) {
+			return i
+		}
+	}
+	if len(ms) == 0 {
+		return -1
+	}
+	return 0
+}
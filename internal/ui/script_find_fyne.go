@@ -0,0 +1,199 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+)
+
+// scriptFindBar is the find and replace bar over the script editor. It searches as the query
+// changes, highlights every match in the editor and steps through them. Replacing goes through the
+// editor's SetText, so the outline, beats and snapshots follow as they do for typing.
+type scriptFindBar struct {
+	ed      *scriptEditor
+	box     *fyne.Container
+	query   *quickOpenEntry
+	repl    *quickOpenEntry
+	count   *widget.Label
+	matchCs *widget.Check
+	whole   *widget.Check
+	replBtn *widget.Button
+	allBtn  *widget.Button
+	matches []findMatch
+	cur     int
+}
+
+func newScriptFindBar(ed *scriptEditor) *scriptFindBar {
+	b := &scriptFindBar{ed: ed, cur: -1}
+	b.query = newQuickOpenEntry()
+	b.query.SetPlaceHolder(i18n.T("placeholder.find_in_script"))
+	b.query.OnChanged = func(string) { b.search(true) }
+	b.query.onKey = b.key(b.next)
+	b.repl = newQuickOpenEntry()
+	b.repl.SetPlaceHolder(i18n.T("placeholder.replace_with"))
+	b.repl.onKey = b.key(b.replace)
+	b.count = widget.NewLabel("")
+	b.matchCs = widget.NewCheck(i18n.T("label.match_case"), func(bool) { b.search(true) })
+	b.whole = widget.NewCheck(i18n.T("label.whole_word"), func(bool) { b.search(true) })
+	prev := widget.NewButtonWithIcon("", theme.MoveUpIcon(), b.prev)
+	next := widget.NewButtonWithIcon("", theme.MoveDownIcon(), b.next)
+	b.replBtn = widget.NewButton(i18n.T("button.replace"), b.replace)
+	b.allBtn = widget.NewButton(i18n.T("button.replace_all"), b.replaceAll)
+	closeBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), b.Close)
+	closeBtn.Importance = widget.LowImportance
+	findRow := container.NewBorder(nil, nil, nil, container.NewHBox(b.count, prev, next, b.matchCs, b.whole, closeBtn), b.query)
+	replRow := container.NewBorder(nil, nil, nil, container.NewHBox(b.replBtn, b.allBtn), b.repl)
+	b.box = container.NewVBox(findRow, replRow)
+	b.box.Hide()
+	ed.onEdit = func(string) {
+		if b.box.Visible() {
+			b.search(false)
+		}
+	}
+	return b
+}
+
+// key makes the entries' key handler: Enter runs onEnter, Escape closes the bar.
+func (b *scriptFindBar) key(onEnter func()) func(*fyne.KeyEvent) bool {
+	return func(ev *fyne.KeyEvent) bool {
+		switch ev.Name {
+		case fyne.KeyReturn, fyne.KeyEnter:
+			onEnter()
+		case fyne.KeyEscape:
+			b.Close()
+		default:
+			return false
+		}
+		return true
+	}
+}
+
+// Open shows the bar and focuses the query, seeded with the editor's selection if it is one line.
+func (b *scriptFindBar) Open(c fyne.Canvas) {
+	if sel := b.ed.entry.SelectedText(); sel != "" && !strings.ContainsRune(sel, '\n') {
+		b.query.SetText(sel)
+	}
+	b.box.Show()
+	b.search(true)
+	c.Focus(b.query)
+}
+
+// Close hides the bar, clears the highlights and hands the focus back to the editor.
+func (b *scriptFindBar) Close() {
+	b.box.Hide()
+	b.matches, b.cur = nil, -1
+	b.ed.SetFindMatches(nil, -1)
+	if c := fyne.CurrentApp().Driver().CanvasForObject(b.ed.entry); c != nil {
+		c.Focus(b.ed.entry)
+	}
+}
+
+// search finds the query in the script. With jump it moves to the first match at or after the
+// caret; otherwise, after an edit, it keeps the current match as far as it still exists.
+func (b *scriptFindBar) search(jump bool) {
+	b.matches = findMatches(b.ed.Text(), b.query.Text, findOptions{CaseSensitive: b.matchCs.Checked, WholeWord: b.whole.Checked})
+	switch {
+	case jump:
+		b.cur = matchAfter(b.matches, b.ed.entry.CursorRow, b.ed.entry.CursorColumn)
+	case b.cur >= len(b.matches):
+		b.cur = len(b.matches) - 1
+	}
+	b.show(jump)
+}
+
+// show highlights the matches, updates the count and, with reveal, scrolls to the current match.
+func (b *scriptFindBar) show(reveal bool) {
+	switch {
+	case b.query.Text == "":
+		b.count.SetText("")
+	case len(b.matches) == 0:
+		b.count.SetText(i18n.T("find.no_matches"))
+	default:
+		b.count.SetText(i18n.T("find.match_count", b.cur+1, len(b.matches)))
+	}
+	if len(b.matches) == 0 {
+		b.replBtn.Disable()
+		b.allBtn.Disable()
+	} else {
+		b.replBtn.Enable()
+		b.allBtn.Enable()
+	}
+	b.ed.SetFindMatches(b.matches, b.cur)
+	if reveal && b.cur >= 0 {
+		b.ed.ShowMatch(b.matches[b.cur])
+	}
+}
+
+func (b *scriptFindBar) next() { b.step(1) }
+func (b *scriptFindBar) prev() { b.step(-1) }
+
+// step moves to the next (1) or previous (-1) match, wrapping around.
+func (b *scriptFindBar) step(d int) {
+	if len(b.matches) == 0 {
+		return
+	}
+	b.cur = (b.cur + d + len(b.matches)) % len(b.matches)
+	b.show(true)
+}
+
+// replace replaces the current match and moves to the next one after it.
+func (b *scriptFindBar) replace() {
+	if b.cur < 0 || b.cur >= len(b.matches) {
+		return
+	}
+	m, with := b.matches[b.cur], b.repl.Text
+	b.ed.SetText(replaceMatches(b.ed.Text(), b.matches[b.cur:b.cur+1], with))
+	// The edit re-ran the search; continue behind the replacement so it is not matched again
+	b.cur = -1
+	for i, n := range b.matches {
+		if n.Start >= m.Start+len(with) {
+			b.cur = i
+			break
+		}
+	}
+	if b.cur < 0 && len(b.matches) > 0 {
+		b.cur = 0
+	}
+	b.show(true)
+}
+
+// replaceAll replaces every match. The new text is built off the UI goroutine so long scripts do
+// not stall the window; it is dropped if the script changed meanwhile.
+func (b *scriptFindBar) replaceAll() {
+	text, ms, with := b.ed.Text(), b.matches, b.repl.Text
+	if len(ms) == 0 {
+		return
+	}
+	b.replBtn.Disable()
+	b.allBtn.Disable()
+	go func() {
+		out := replaceMatches(text, ms, with)
+		fyne.Do(func() {
+			if b.ed.Text() != text {
+				b.search(false)
+				return
+			}
+			b.ed.SetText(out)
+			b.cur = min(0, len(b.matches)-1)
+			b.show(false)
+			b.count.SetText(i18n.T("find.replaced", len(ms)))
+		})
+	}()
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFindMatches(t *testing.T) {
+	text := "ALICE: Hello Bob\n  bob? BOBBY and Bob_2\nÄrger über Bob"
+	cases := []struct {
+		name  string
+		query string
+		opts  findOptions
+		want  []string // row:col:len
+	}{
+		{"ignore case", "bob", findOptions{}, []string{"0:13:3", "1:2:3", "1:7:3", "1:17:3", "2:11:3"}},
+		{"case sensitive", "Bob", findOptions{CaseSensitive: true}, []string{"0:13:3", "1:17:3", "2:11:3"}},
+		{"whole word", "bob", findOptions{WholeWord: true}, []string{"0:13:3", "1:2:3", "2:11:3"}},
+		{"both", "Bob", findOptions{CaseSensitive: true, WholeWord: true}, []string{"0:13:3", "2:11:3"}},
+		{"unicode columns", "über", findOptions{}, []string{"2:6:4"}},
+		{"empty query", "", findOptions{}, nil},
+		{"no match", "carol", findOptions{}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var got []string
+			for _, m := range findMatches(text, c.query, c.opts) {
+				if !strings.EqualFold(text[m.Start:m.End], c.query) {
+					t.Errorf("match %q at %d:%d, want %q", text[m.Start:m.End], m.Start, m.End, c.query)
+				}
+				got = append(got, fmt.Sprintf("%d:%d:%d", m.Row, m.Col, m.Runes))
+			}
+			if strings.Join(got, " ") != strings.Join(c.want, " ") {
+				t.Errorf("matches = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestReplaceMatches(t *testing.T) {
+	text := "Bob met bob. Bobby left."
+	ms := findMatches(text, "bob", findOptions{WholeWord: true})
+	if got, want := replaceMatches(text, ms, "Robert"), "Robert met Robert. Bobby left."; got != want {
+		t.Errorf("replace all = %q, want %q", got, want)
+	}
+	if got, want := replaceMatches(text, ms[1:2], ""), "Bob met . Bobby left."; got != want {
+		t.Errorf("replace one = %q, want %q", got, want)
+	}
+	if got := replaceMatches(text, nil, "x"); got != text {
+		t.Errorf("replace without matches = %q", got)
+	}
+}
+
+func TestMatchAfter(t *testing.T) {
+	ms := findMatches("a x\nx a x\na", "a", findOptions{})
+	for _, c := range []struct{ row, col, want int }{{0, 0, 0}, {0, 1, 1}, {1, 2, 1}, {1, 3, 2}, {5, 0, 0}} {
+		if got := matchAfter(ms, c.row, c.col); got != c.want {
+			t.Errorf("matchAfter(%d, %d) = %d, want %d", c.row, c.col, got, c.want)
+		}
+	}
+	if got := matchAfter(nil, 0, 0); got != -1 {
+		t.Errorf("matchAfter without matches = %d, want -1", got)
+	}
+}
+
+func TestReplaceAllLargeScript(t *testing.T) {
+	text := strings.Join(bigScript(2500), "\n") // 10k lines
+	ms := findMatches(text, "alice", findOptions{WholeWord: true})
+	if len(ms) != 2500 {
+		t.Fatalf("found %d matches, want 2500", len(ms))
+	}
+	out := replaceMatches(text, ms, "CAROL")
+	if strings.Contains(out, "ALICE") || strings.Count(out, "CAROL:") != 2500 {
+		t.Errorf("replace all left %d ALICE lines", strings.Count(out, "ALICE"))
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+
+	"gocomicwriter/internal/storage"
+)
+
+// scriptDiffRow is one display row of the script history diff. Kind is empty for scene headers.
+type scriptDiffRow struct {
+	Kind storage.ScriptDiffKind
+	Text string
+}
+
+// snapshotLabel is the list entry of a script snapshot in the history dialog.
+func snapshotLabel(s storage.ScriptSnapshotInfo) string {
+	return fmt.Sprintf("%s — %d lines", s.TS.Local().Format("2006-01-02 15:04:05"), s.Lines)
+}
+
+// scriptDiffRows lays out a script diff for display: a header row whenever the scene changes, then
+// "-" rows for old text and "+" rows for new text, prefixed with their line numbers. A changed line
+// shows as a removed row followed by an added row.
+func scriptDiffRows(diff []storage.ScriptDiffLine) []scriptDiffRow {
+	rows := make([]scriptDiffRow, 0, len(diff)+4)
+	scene := ""
+	for i, d := range diff {
+		if i == 0 || d.Scene != scene {
+			scene = d.Scene
+			title := scene
+			if title == "" {
+				title = "(before first scene)"
+			}
+			rows = append(rows, scriptDiffRow{Text: "— " + title + " —"})
+		}
+		if d.Kind != storage.ScriptLineAdded {
+			rows = append(rows, scriptDiffRow{Kind: storage.ScriptLineRemoved, Text: fmt.Sprintf("- %4d  %s", d.OldLine, d.Old)})
+		}
+		if d.Kind != storage.ScriptLineRemoved {
+			rows = append(rows, scriptDiffRow{Kind: storage.ScriptLineAdded, Text: fmt.Sprintf("+ %4d  %s", d.NewLine, d.New)})
+		}
+	}
+	return rows
+}
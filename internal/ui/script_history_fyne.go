@@ -0,0 +1,176 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"context"
+	"time"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// The choices below are message keys; the dialogs show and compare them translated.
+const (
+	comparePrevious = "option.previous_snapshot"
+	compareCurrent  = "option.current_script"
+)
+
+// showScriptHistoryDialog lists the script snapshots of the project at root and shows what changed in the
+// selected one, compared with the snapshot before it, the editor contents (current) or any other snapshot.
+// onRestore receives the text of a snapshot the user chose to restore.
+func showScriptHistoryDialog(w fyne.Window, root string, current func() string, onRestore func(text, label string)) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	items, err := storage.ScriptHistory(ctx, root, 50)
+	if err != nil {
+		dialog.ShowError(FriendlyError(err), w)
+		return
+	}
+	if len(items) == 0 {
+		dialog.ShowInformation(i18n.T("msg.script_history"), i18n.T("msg.no_snapshots_yet_snapshots_are_taken"), w)
+		return
+	}
+
+	labels := make([]string, len(items))
+	for i, it := range items {
+		labels[i] = snapshotLabel(it)
+	}
+	selected := -1
+	var rows []scriptDiffRow
+
+	summary := widget.NewLabel(i18n.T("label.select_a_snapshot_to_see_what"))
+	diffList := widget.NewList(
+		func() int { return len(rows) },
+		func() fyne.CanvasObject {
+			t := canvas.NewText("", theme.Color(theme.ColorNameForeground))
+			t.TextStyle = fyne.TextStyle{Monospace: true}
+			return t
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			t := o.(*canvas.Text)
+			r := rows[id]
+			t.Text = r.Text
+			t.TextStyle = fyne.TextStyle{Monospace: r.Kind != "", Bold: r.Kind == ""}
+			switch r.Kind {
+			case storage.ScriptLineAdded:
+				t.Color = theme.Color(theme.ColorNameSuccess)
+			case storage.ScriptLineRemoved:
+				t.Color = theme.Color(theme.ColorNameError)
+			default:
+				t.Color = theme.Color(theme.ColorNameForeground)
+			}
+			t.Refresh()
+		},
+	)
+
+	compare := widget.NewSelect(append([]string{i18n.T(comparePrevious), i18n.T(compareCurrent)}, labels...), nil)
+	compare.SetSelected(i18n.T(comparePrevious))
+
+	refreshDiff := func() {
+		rows = nil
+		if selected < 0 {
+			diffList.Refresh()
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		var diff []storage.ScriptDiffLine
+		var err error
+		against := ""
+		switch i := compare.SelectedIndex(); {
+		case i <= 0: // previous snapshot; the oldest one is compared with an empty script
+			if selected+1 < len(items) {
+				diff, err = storage.DiffScriptSnapshots(ctx, root, items[selected+1].ID, items[selected].ID)
+				against = labels[selected+1]
+			} else {
+				var text string
+				text, err = storage.ScriptSnapshotText(ctx, root, items[selected].ID)
+				diff = storage.DiffScriptText("", text)
+				against = i18n.T("status.empty_script")
+			}
+		case i == 1:
+			var text string
+			text, err = storage.ScriptSnapshotText(ctx, root, items[selected].ID)
+			diff = storage.DiffScriptText(text, current())
+			against = i18n.T("status.current_script")
+		default:
+			// Items are newest first; always diff from the older to the newer snapshot
+			older, newer := max(i-2, selected), min(i-2, selected)
+			diff, err = storage.DiffScriptSnapshots(ctx, root, items[older].ID, items[newer].ID)
+			against = labels[i-2]
+		}
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			diffList.Refresh()
+			return
+		}
+		rows = scriptDiffRows(diff)
+		if len(diff) == 0 {
+			summary.SetText(i18n.T("status.no_changes_against", against))
+		} else {
+			summary.SetText(i18n.N("status.snapshot_diff", len(diff), labels[selected], against, len(diff)))
+		}
+		diffList.Refresh()
+		diffList.ScrollToTop()
+	}
+	compare.OnChanged = func(string) { refreshDiff() }
+
+	snapList := widget.NewList(
+		func() int { return len(items) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) { o.(*widget.Label).SetText(labels[id]) },
+	)
+	snapList.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		refreshDiff()
+	}
+
+	var d dialog.Dialog
+	restoreBtn := widget.NewButton(i18n.T("button.restore_selected"), func() {
+		if selected < 0 {
+			return
+		}
+		label := labels[selected]
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		text, err := storage.ScriptSnapshotText(ctx, root, items[selected].ID)
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		dialog.ShowConfirm(i18n.T("msg.restore_script"), i18n.T("msg.replace_editor_contents_with_snapshot_from", label), func(ok bool) {
+			if !ok {
+				return
+			}
+			onRestore(text, label)
+			d.Hide()
+		}, w)
+	})
+
+	top := container.NewBorder(nil, nil, widget.NewLabel(i18n.T("label.compare_with")), restoreBtn, compare)
+	right := container.NewBorder(container.NewVBox(top, summary), nil, nil, nil, diffList)
+	split := container.NewHSplit(snapList, right)
+	split.Offset = 0.3
+	d = dialog.NewCustom(i18n.T("msg.script_history"), i18n.T("msg.close"), split, w)
+	d.Resize(fyne.NewSize(900, 560))
+	d.Show()
+	snapList.Select(0)
+}
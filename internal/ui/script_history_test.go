@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/storage"
+)
+
+func TestScriptDiffRows(t *testing.T) {
+	diff := []storage.ScriptDiffLine{
+		{Kind: storage.ScriptLineAdded, NewLine: 1, New: "Cold open."},
+		{Kind: storage.ScriptLineChanged, OldLine: 3, NewLine: 4, Old: "ALICE: Hi.", New: "ALICE: Hi there.", Scene: "Rooftop"},
+		{Kind: storage.ScriptLineRemoved, OldLine: 5, Old: "BOB: Hello.", Scene: "Rooftop"},
+	}
+	want := []scriptDiffRow{
+		{Text: "— (before first scene) —"},
+		{Kind: storage.ScriptLineAdded, Text: "+    1  Cold open."},
+		{Text: "— Rooftop —"},
+		{Kind: storage.ScriptLineRemoved, Text: "-    3  ALICE: Hi."},
+		{Kind: storage.ScriptLineAdded, Text: "+    4  ALICE: Hi there."},
+		{Kind: storage.ScriptLineRemoved, Text: "-    5  BOB: Hello."},
+	}
+	if got := scriptDiffRows(diff); !reflect.DeepEqual(got, want) {
+		t.Fatalf("rows =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := scriptDiffRows(nil); len(got) != 0 {
+		t.Fatalf("empty diff rows = %+v", got)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"math"
+	"strings"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/vector"
+)
+
+// Search result thumbnails are cached in the previews table at this width; the height follows the
+// page's aspect ratio. The list shows them at half size for sharp rendering on HiDPI screens.
+const (
+	searchThumbW       = 72
+	searchSnippetRunes = 120
+)
+
+// searchRow is one row of the search results list: a page header or a result.
+type searchRow struct {
+	header bool
+	page   int // page number; 0 for hits outside pages (script, bible)
+	count  int // headers: number of results on the page
+	result int // results: index into the search results
+}
+
+// groupSearchResults orders the results by page, pages in the order of their best-ranked hit and hits
+// in rank order within a page, with a header row before each page's hits.
+func groupSearchResults(res []storage.SearchResult) []searchRow {
+	var order []int
+	byPage := map[int][]int{}
+	for i, r := range res {
+		p := max(r.PageID, 0)
+		if _, ok := byPage[p]; !ok {
+			order = append(order, p)
+		}
+		byPage[p] = append(byPage[p], i)
+	}
+	rows := make([]searchRow, 0, len(res)+len(order))
+	for _, p := range order {
+		rows = append(rows, searchRow{header: true, page: p, count: len(byPage[p]), result: -1})
+		for _, i := range byPage[p] {
+			rows = append(rows, searchRow{page: p, result: i})
+		}
+	}
+	return rows
+}
+
+// searchHeaderText is the label of a page header row.
+func searchHeaderText(r searchRow) string {
+	where := i18n.T("label.page_number", r.page)
+	if r.page == 0 {
+		where = i18n.T("search.script_and_bible")
+	}
+	return i18n.N("search.header", r.count, where, r.count)
+}
+
+// moveSearchCursor returns the next result row from cur in direction delta (+1 or -1), skipping
+// headers. It returns cur when there is none; from -1 moving down it finds the first result.
+func moveSearchCursor(rows []searchRow, cur, delta int) int {
+	for i := cur + delta; i >= 0 && i < len(rows); i += delta {
+		if !rows[i].header {
+			return i
+		}
+	}
+	return cur
+}
+
+// snippetSegment is a piece of a search snippet; match marks the text the index wrapped in match markers.
+type snippetSegment struct {
+	text  string
+	match bool
+}
+
+// snippetSegments splits a search snippet at its storage.SnippetMarkStart/End markers, dropping them, and
+// cuts the visible text after maxRunes runes with an ellipsis. Brackets in the text are plain text.
+func snippetSegments(snippet string, maxRunes int) []snippetSegment {
+	var out []snippetSegment
+	var cur strings.Builder
+	match, n := false, 0
+	flush := func() {
+		if cur.Len() > 0 {
+			out = append(out, snippetSegment{text: cur.String(), match: match})
+			cur.Reset()
+		}
+	}
+	for _, r := range strings.TrimSpace(snippet) {
+		switch {
+		case string(r) == storage.SnippetMarkStart && !match:
+			flush()
+			match = true
+			continue
+		case string(r) == storage.SnippetMarkEnd && match:
+			flush()
+			match = false
+			continue
+		case r == '\n' || r == '\r' || r == '\t':
+			r = ' '
+		}
+		if n == maxRunes {
+			cur.WriteString("…")
+			break
+		}
+		cur.WriteRune(r)
+		n++
+	}
+	flush()
+	return out
+}
+
+// searchThumbSize returns the thumbnail pixel size of a page with the given trim size.
+func searchThumbSize(trimW, trimH float64) (int, int) {
+	if trimW <= 0 || trimH <= 0 {
+		return searchThumbW, searchThumbW * 3 / 2
+	}
+	return searchThumbW, max(1, int(math.Round(searchThumbW*trimH/trimW)))
+}
+
+// renderPageThumbnail draws pg's panel layout, outlined in their resolved border colors, on a white
+// page of w×h pixels and encodes it as PNG for the previews cache.
+func renderPageThumbnail(pg domain.Page, trimW, trimH float64, border *domain.PanelBorder, w, h int) ([]byte, error) {
+	if trimW <= 0 || trimH <= 0 {
+		return nil, fmt.Errorf("page %d has no trim size", pg.Number)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	m := vector.Scale(float32(float64(w)/trimW), float32(float64(h)/trimH))
+	fill := vector.Fill{Enabled: true, Color: vector.Color{R: 240, G: 240, B: 240, A: 255}}
+	for _, pn := range pg.Panels {
+		g := pn.Geometry
+		s := panelCanvasStroke(border, pn)
+		// Keep outlines visible at thumbnail scale
+		s.Width = max(s.Width, float32(trimW/float64(w)))
+		n := vector.NewRect(vector.R(float32(g.X), float32(g.Y), float32(g.Width), float32(g.Height)), fill, s)
+		vector.RasterizeNode(img, n, m)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// searchTypeKeys are the message keys of the search dialog's type choices.
+var searchTypeKeys = map[string]string{
+	"balloon":     "search.type.balloon",
+	"caption":     "search.type.caption",
+	"sfx":         "search.type.sfx",
+	"page_notes":  "search.type.page_notes",
+	"panel_notes": "search.type.panel_notes",
+	"scene":       "search.type.scene",
+	"script":      "search.type.script",
+	"character":   "search.type.character",
+	"location":    "search.type.location",
+	"tag":         "search.type.tag",
+	"asset":       "search.type.asset",
+}
+
+// searchTypeOptions returns the choices of the search dialog's type filter: all types, then each of
+// storage.SearchFilterTypes by its label.
+func searchTypeOptions() []string {
+	return append([]string{i18n.T("option.all_types")}, choiceLabels(storage.SearchFilterTypes, searchTypeKeys)...)
+}
+
+// searchTypeFilter returns the document types of a searchTypeOptions choice; nil means all types.
+func searchTypeFilter(option string) []string {
+	if t := choiceValue(option, storage.SearchFilterTypes, searchTypeKeys); t != "" {
+		return []string{t}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"image"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/storage"
+)
+
+// searchResultList shows search results grouped under page headers, each result with a thumbnail of
+// its page and the snippet with the matches in bold. Up and Down move between results, skipping the
+// headers; Enter opens the highlighted result and Escape hands focus back via OnEscape. A click opens
+// the result at once.
+type searchResultList struct {
+	widget.List
+
+	results []storage.SearchResult
+	rows    []searchRow
+	cursor  int  // highlighted row, -1 for none
+	keyNav  bool // set while the keyboard moves the selection, so it does not open the result
+
+	// Thumb returns the thumbnail of a page, or nil while it is not loaded; it is asked for the
+	// visible rows only, so loading can start there.
+	Thumb    func(page int) image.Image
+	OnOpen   func(r storage.SearchResult)
+	OnEscape func()
+}
+
+func newSearchResultList() *searchResultList {
+	l := &searchResultList{cursor: -1}
+	l.Length = func() int { return len(l.rows) }
+	l.CreateItem = func() fyne.CanvasObject {
+		thumb := canvas.NewImageFromImage(nil)
+		thumb.FillMode = canvas.ImageFillContain
+		thumb.SetMinSize(fyne.NewSize(searchThumbW/2, searchThumbW*3/4))
+		text := widget.NewRichText()
+		text.Truncation = fyne.TextTruncateEllipsis
+		return container.NewBorder(nil, nil, thumb, nil, text)
+	}
+	l.UpdateItem = func(id widget.ListItemID, o fyne.CanvasObject) {
+		if id < 0 || id >= len(l.rows) {
+			return
+		}
+		c := o.(*fyne.Container)
+		var text *widget.RichText
+		var thumb *canvas.Image
+		for _, obj := range c.Objects {
+			switch v := obj.(type) {
+			case *widget.RichText:
+				text = v
+			case *canvas.Image:
+				thumb = v
+			}
+		}
+		row := l.rows[id]
+		if row.header {
+			thumb.Hide()
+			text.Segments = []widget.RichTextSegment{&widget.TextSegment{Text: searchHeaderText(row), Style: widget.RichTextStyleStrong}}
+			text.Refresh()
+			return
+		}
+		r := l.results[row.result]
+		thumb.Image = nil
+		if l.Thumb != nil && row.page > 0 {
+			thumb.Image = l.Thumb(row.page)
+		}
+		thumb.Show()
+		thumb.Refresh()
+		segs := []widget.RichTextSegment{&widget.TextSegment{Text: r.Type + "  ", Style: widget.RichTextStyleEmphasis}}
+		parts := snippetSegments(r.Snippet, searchSnippetRunes)
+		if len(parts) == 0 {
+			parts = []snippetSegment{{text: r.Path}}
+		}
+		for _, p := range parts {
+			st := widget.RichTextStyleInline
+			if p.match {
+				st = widget.RichTextStyleStrong
+			}
+			segs = append(segs, &widget.TextSegment{Text: p.text, Style: st})
+		}
+		text.Segments = segs
+		text.Refresh()
+	}
+	l.List.OnSelected = func(id widget.ListItemID) {
+		if id < 0 || id >= len(l.rows) {
+			return
+		}
+		if l.rows[id].header {
+			l.Unselect(id)
+			if l.cursor >= 0 {
+				l.keyNav = true
+				l.Select(l.cursor)
+				l.keyNav = false
+			}
+			return
+		}
+		l.cursor = id
+		if !l.keyNav && l.OnOpen != nil {
+			l.OnOpen(l.results[l.rows[id].result])
+		}
+	}
+	l.ExtendBaseWidget(l)
+	return l
+}
+
+// SetResults replaces the listed results and clears the highlight.
+func (l *searchResultList) SetResults(res []storage.SearchResult) {
+	l.results = res
+	l.rows = groupSearchResults(res)
+	l.cursor = -1
+	// Headers need no room for a thumbnail
+	headerH := widget.NewLabel("").MinSize().Height
+	for i, r := range l.rows {
+		if r.header {
+			l.SetItemHeight(i, headerH)
+		}
+	}
+	l.UnselectAll()
+	l.Refresh()
+	l.ScrollToTop()
+}
+
+// HighlightFirst highlights the first result without opening it.
+func (l *searchResultList) HighlightFirst() {
+	if first := moveSearchCursor(l.rows, -1, 1); first >= 0 {
+		l.keyNav = true
+		l.Select(first)
+		l.keyNav = false
+	}
+}
+
+// RefreshPage redraws the rows of results on the given page, e.g. once its thumbnail has loaded.
+func (l *searchResultList) RefreshPage(page int) {
+	for i, r := range l.rows {
+		if !r.header && r.page == page {
+			l.RefreshItem(i)
+		}
+	}
+}
+
+// TypedKey implements the keyboard navigation; other keys keep the list's default handling.
+func (l *searchResultList) TypedKey(ev *fyne.KeyEvent) {
+	switch ev.Name {
+	case fyne.KeyDown, fyne.KeyUp:
+		delta := 1
+		if ev.Name == fyne.KeyUp {
+			delta = -1
+		}
+		if next := moveSearchCursor(l.rows, l.cursor, delta); next != l.cursor {
+			l.keyNav = true
+			l.Select(next)
+			l.keyNav = false
+		}
+	case fyne.KeyReturn, fyne.KeyEnter:
+		if l.cursor >= 0 && l.cursor < len(l.rows) && l.OnOpen != nil {
+			l.OnOpen(l.results[l.rows[l.cursor].result])
+		}
+	case fyne.KeyEscape:
+		if l.OnEscape != nil {
+			l.OnEscape()
+		}
+	default:
+		l.List.TypedKey(ev)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"reflect"
+	"testing"
+
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/storage"
+)
+
+func TestGroupSearchResults(t *testing.T) {
+	res := []storage.SearchResult{{PageID: 3}, {PageID: 1}, {PageID: 3}, {PageID: 0}, {PageID: 1}}
+	got := groupSearchResults(res)
+	want := []searchRow{
+		{header: true, page: 3, count: 2, result: -1}, {page: 3, result: 0}, {page: 3, result: 2},
+		{header: true, page: 1, count: 2, result: -1}, {page: 1, result: 1}, {page: 1, result: 4},
+		{header: true, page: 0, count: 1, result: -1}, {page: 0, result: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+	if s := searchHeaderText(got[0]); s != "Page 3 — 2 results" {
+		t.Fatalf("header: %q", s)
+	}
+	if s := searchHeaderText(got[6]); s != "Script & Bible — 1 result" {
+		t.Fatalf("header: %q", s)
+	}
+	if groupSearchResults(nil) == nil || len(groupSearchResults(nil)) != 0 {
+		t.Fatalf("empty results")
+	}
+}
+
+func TestMoveSearchCursor_SkipsHeaders(t *testing.T) {
+	rows := groupSearchResults([]storage.SearchResult{{PageID: 1}, {PageID: 2}})
+	// rows: h1 r0 h2 r1
+	steps := []struct{ from, delta, want int }{{-1, 1, 1}, {1, 1, 3}, {3, 1, 3}, {3, -1, 1}, {1, -1, 1}}
+	for _, s := range steps {
+		if got := moveSearchCursor(rows, s.from, s.delta); got != s.want {
+			t.Fatalf("from %d by %d: got %d want %d", s.from, s.delta, got, s.want)
+		}
+	}
+}
+
+func TestSnippetSegments(t *testing.T) {
+	m := func(s string) string { return storage.SnippetMarkStart + s + storage.SnippetMarkEnd }
+	got := snippetSegments("  the "+m("hero")+" meets\nthe "+m("villain")+"…", 100)
+	want := []snippetSegment{{"the ", false}, {"hero", true}, {" meets the ", false}, {"villain", true}, {"…", false}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v", got)
+	}
+	// Truncation counts runes of visible text, not bytes or markers
+	got = snippetSegments("äö"+m("üß")+"xyz", 3)
+	want = []snippetSegment{{"äö", false}, {"ü…", true}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("truncated: %+v", got)
+	}
+	// Brackets typed by the user and an unbalanced marker are kept as text
+	got = snippetSegments("[note] "+m("🎉")+" a"+storage.SnippetMarkEnd+"b", 20)
+	want = []snippetSegment{{"[note] ", false}, {"🎉", true}, {" a" + storage.SnippetMarkEnd + "b", false}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("brackets: %+v", got)
+	}
+}
+
+func TestRenderPageThumbnail(t *testing.T) {
+	w, h := searchThumbSize(100, 150)
+	if w != searchThumbW || h != 108 {
+		t.Fatalf("size %dx%d", w, h)
+	}
+	red := domain.Color{R: 255, A: 255}
+	pg := domain.Page{Number: 2, Panels: []domain.Panel{{ID: "p1", Geometry: domain.Rect{X: 10, Y: 10, Width: 80, Height: 60}, Border: &domain.PanelBorder{Color: &red, Width: 2}}}}
+	data, err := renderPageThumbnail(pg, 100, 150, nil, w, h)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil || img.Bounds().Dx() != w || img.Bounds().Dy() != h {
+		t.Fatalf("decode: %v %v", img.Bounds(), err)
+	}
+	// Outside the panel the page is white; on its left edge (x = 10pt = 7.2px) the border is red
+	if c := color.RGBAModel.Convert(img.At(2, 100)).(color.RGBA); c != (color.RGBA{255, 255, 255, 255}) {
+		t.Fatalf("page background: %v", c)
+	}
+	if c := color.RGBAModel.Convert(img.At(7, 40)).(color.RGBA); c.R < 200 || c.G > 120 {
+		t.Fatalf("panel border: %v", c)
+	}
+	if _, err := renderPageThumbnail(pg, 0, 0, nil, w, h); err == nil {
+		t.Fatalf("missing trim size accepted")
+	}
+}
+
+func TestSearchTypeFilter(t *testing.T) {
+	opts := searchTypeOptions()
+	if len(opts) != len(storage.SearchFilterTypes)+1 || opts[0] != "All types" {
+		t.Fatalf("options = %v", opts)
+	}
+	for _, typ := range storage.SearchFilterTypes {
+		if searchTypeKeys[typ] == "" {
+			t.Errorf("type %s has no label", typ)
+		}
+	}
+	if got := searchTypeFilter(opts[0]); got != nil {
+		t.Fatalf("all types = %v", got)
+	}
+	for _, c := range []struct{ option, want string }{{"Page notes", "page_notes"}, {"Panel notes", "panel_notes"}, {"Balloons", "balloon"}} {
+		if got := searchTypeFilter(c.option); !reflect.DeepEqual(got, []string{c.want}) {
+			t.Errorf("searchTypeFilter(%q) = %v, want [%s]", c.option, got, c.want)
+		}
+	}
+}
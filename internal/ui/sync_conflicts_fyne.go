@@ -0,0 +1,125 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// showSyncConflicts lists the open sync conflicts of the project and shows the local and the remote
+// version of the selected one side by side. Keeping mine pushes the local change over the remote one;
+// keeping theirs drops the local change and passes the remote op to onResolved to apply it. onResolved
+// also runs after keeping mine, with a nil op, e.g. to update the status bar and wake the sync.
+func showSyncConflicts(w fyne.Window, ix *storage.IndexHandle, l *slog.Logger, onResolved func(remote *storage.RemoteSyncOp)) {
+	var conflicts []storage.SyncConflict
+	load := func() bool {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		list, err := ix.OpenSyncConflicts(ctx)
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return false
+		}
+		conflicts = list
+		return true
+	}
+	if !load() {
+		return
+	}
+	if len(conflicts) == 0 {
+		dialog.ShowInformation(i18n.T("msg.sync_conflicts"), i18n.T("msg.no_sync_conflicts"), w)
+		return
+	}
+
+	title := widget.NewLabel("")
+	title.Wrapping = fyne.TextWrapWord
+	mine := widget.NewLabel("")
+	mine.TextStyle = fyne.TextStyle{Monospace: true}
+	theirs := widget.NewLabel("")
+	theirs.TextStyle = fyne.TextStyle{Monospace: true}
+	selected := -1
+	show := func(id int) {
+		selected = id
+		if id < 0 || id >= len(conflicts) {
+			title.SetText(i18n.T("label.select_a_conflict"))
+			mine.SetText("")
+			theirs.SetText("")
+			return
+		}
+		c := conflicts[id]
+		title.SetText(syncConflictTitle(c))
+		mine.SetText(syncPayloadText(c.Local))
+		theirs.SetText(syncPayloadText(c.Remote.SyncOp))
+	}
+	list := widget.NewList(
+		func() int { return len(conflicts) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			c := conflicts[id]
+			o.(*widget.Label).SetText(c.Local.EntityType + " " + c.Local.EntityID)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { show(id) }
+
+	var d *dialog.CustomDialog
+	resolve := func(keep string) {
+		if selected < 0 || selected >= len(conflicts) {
+			return
+		}
+		c := conflicts[selected]
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		op, err := ix.ResolveSyncConflict(ctx, c.ID, keep)
+		if err != nil {
+			dialog.ShowError(FriendlyError(err), w)
+			return
+		}
+		l.Info("sync conflict resolved", slog.Int64("conflict", c.ID), slog.String("entity", c.Local.EntityID), slog.String("keep", keep))
+		if onResolved != nil {
+			onResolved(op)
+		}
+		if !load() || len(conflicts) == 0 {
+			d.Hide()
+			return
+		}
+		list.UnselectAll()
+		list.Refresh()
+		list.Select(0)
+	}
+	keepMine := widget.NewButton(i18n.T("button.keep_mine"), func() { resolve(storage.SyncKeepLocal) })
+	keepTheirs := widget.NewButton(i18n.T("button.keep_theirs"), func() { resolve(storage.SyncKeepRemote) })
+	keepMine.Importance = widget.HighImportance
+
+	sides := container.NewGridWithColumns(2,
+		widget.NewCard(i18n.T("label.my_version"), "", container.NewScroll(mine)),
+		widget.NewCard(i18n.T("label.server_version"), "", container.NewScroll(theirs)),
+	)
+	detail := container.NewBorder(title, container.NewHBox(keepMine, keepTheirs), nil, nil, sides)
+	split := container.NewHSplit(list, detail)
+	split.Offset = 0.3
+	d = dialog.NewCustom(i18n.T("msg.sync_conflicts"), i18n.T("msg.close"), split, w)
+	d.Resize(fyne.NewSize(820, 520))
+	show(-1)
+	list.Select(0)
+	d.Show()
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"gocomicwriter/internal/backend"
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// syncConflictsLabel is the text of the status bar button that opens the sync conflicts: empty when
+// there are none.
+func syncConflictsLabel(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return i18n.T("sync.conflicts_button", n)
+}
+
+// syncStatusLabel is the status bar text for the sync queue: empty while everything is pushed, the
+// number of queued changes otherwise, marked offline when the last round did not reach the server.
+func syncStatusLabel(st backend.SyncStatus) string {
+	n := st.Unsent()
+	switch {
+	case !st.Online():
+		return i18n.N("sync.offline_queued", n, n)
+	case n > 0:
+		return i18n.N("sync.queued", n, n)
+	}
+	return ""
+}
+
+// syncStatusTooltip explains the sync status: the last error, or nothing when the sync is fine.
+func syncStatusTooltip(st backend.SyncStatus) string {
+	switch {
+	case st.Err != nil:
+		return FriendlyError(st.Err).Error()
+	case st.LastError != "":
+		return i18n.T("sync.last_error", st.LastError)
+	}
+	return ""
+}
+
+// syncConflictTitle names the entity of a conflict and who changed it on the server, e.g.
+// "page issue:1/page:3 — changed by bob in version 42".
+func syncConflictTitle(c storage.SyncConflict) string {
+	who := c.Remote.Actor
+	if who == "" {
+		who = i18n.T("sync.someone")
+	}
+	return i18n.T("sync.conflict_title", c.Local.EntityType, c.Local.EntityID, who, c.Remote.Version)
+}
+
+// syncPayloadText shows an op payload for comparison: indented JSON without the parent reference, or
+// "(deleted)" for a delete.
+func syncPayloadText(op storage.SyncOp) string {
+	if op.OpType == storage.SyncOpDelete {
+		return i18n.T("sync.deleted")
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(op.Payload, &m); err == nil {
+		delete(m, "parent")
+		if b, err := json.MarshalIndent(m, "", "  "); err == nil {
+			return string(b)
+		}
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, op.Payload, "", "  "); err != nil {
+		return strings.TrimSpace(string(op.Payload))
+	}
+	return strings.TrimSpace(buf.String())
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"gocomicwriter/internal/backend"
+	"gocomicwriter/internal/storage"
+)
+
+func TestSyncStatusLabels(t *testing.T) {
+	if got := syncConflictsLabel(0); got != "" {
+		t.Errorf("no conflicts: %q", got)
+	}
+	if got := syncConflictsLabel(2); got != "Conflicts (2)" {
+		t.Errorf("conflicts label = %q", got)
+	}
+	for _, tc := range []struct {
+		st        backend.SyncStatus
+		label     string
+		tipPrefix string
+	}{
+		{backend.SyncStatus{}, "", ""},
+		{backend.SyncStatus{SyncQueueStats: storage.SyncQueueStats{Pending: 1}}, "1 change to sync", ""},
+		{backend.SyncStatus{SyncQueueStats: storage.SyncQueueStats{Pending: 1, Failed: 2, LastError: "bad op"}}, "3 changes to sync", "Last push failed: bad op"},
+		{backend.SyncStatus{SyncQueueStats: storage.SyncQueueStats{Pending: 2}, Err: errors.New("dial tcp: connection refused")}, "Offline – 2 changes queued", "dial tcp"},
+	} {
+		if got := syncStatusLabel(tc.st); got != tc.label {
+			t.Errorf("label for %+v = %q, want %q", tc.st, got, tc.label)
+		}
+		if got := syncStatusTooltip(tc.st); !strings.HasPrefix(got, tc.tipPrefix) || (tc.tipPrefix == "") != (got == "") {
+			t.Errorf("tooltip for %+v = %q", tc.st, got)
+		}
+	}
+}
+
+func TestSyncConflictTexts(t *testing.T) {
+	c := storage.SyncConflict{
+		Local: storage.SyncOp{OpType: storage.SyncOpUpdate, EntityType: storage.SyncEntityPage, EntityID: "issue:1/page:3",
+			Payload: json.RawMessage(`{"grid":"2x2","number":3,"parent":{"issue":1}}`)},
+		Remote: storage.RemoteSyncOp{SyncOp: storage.SyncOp{OpType: storage.SyncOpDelete}, Version: 42, Actor: "bob"},
+	}
+	if got := syncConflictTitle(c); got != "page issue:1/page:3 — changed by bob in version 42" {
+		t.Errorf("title = %q", got)
+	}
+	if got := syncPayloadText(c.Local); got != "{\n  \"grid\": \"2x2\",\n  \"number\": 3\n}" {
+		t.Errorf("local payload = %q", got)
+	}
+	if got := syncPayloadText(c.Remote.SyncOp); got != "(deleted)" {
+		t.Errorf("remote payload = %q", got)
+	}
+	if got := syncPayloadText(storage.SyncOp{Payload: json.RawMessage(` [1, 2] `)}); got != "[\n  1,\n  2\n]" {
+		t.Errorf("array payload = %q", got)
+	}
+}
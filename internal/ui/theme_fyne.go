@@ -0,0 +1,49 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+package ui
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+
+	"gocomicwriter/internal/config"
+)
+
+// variantTheme is the default theme pinned to one variant, for the light and dark theme settings.
+type variantTheme struct {
+	fyne.Theme
+	variant fyne.ThemeVariant
+}
+
+func (t variantTheme) Color(n fyne.ThemeColorName, _ fyne.ThemeVariant) color.Color {
+	return t.Theme.Color(n, t.variant)
+}
+
+// applyTheme sets the app theme for a theme setting: the default theme, pinned to light or dark, or
+// following the system. Settings listeners run afterwards, so widgets drawn with theme colors refresh.
+func applyTheme(a fyne.App, setting string) {
+	switch setting {
+	case config.ThemeLight:
+		a.Settings().SetTheme(variantTheme{Theme: theme.DefaultTheme(), variant: theme.VariantLight})
+	case config.ThemeDark:
+		a.Settings().SetTheme(variantTheme{Theme: theme.DefaultTheme(), variant: theme.VariantDark})
+	default:
+		a.Settings().SetTheme(theme.DefaultTheme())
+	}
+}
+
+// appThemeIsDark reports whether the app shows the dark variant with the given theme setting.
+func appThemeIsDark(a fyne.App, setting string) bool {
+	return themeIsDark(setting, a.Settings().ThemeVariant() == theme.VariantDark)
+}
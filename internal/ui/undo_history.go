@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/undo"
+)
+
+// undoHistoryRows returns the undo history for the Undo History dialog, newest first: the change that
+// Undo takes back comes first.
+func undoHistoryRows(hist []undo.Entry) []undo.Entry {
+	out := make([]undo.Entry, len(hist))
+	for i, e := range hist {
+		out[len(hist)-1-i] = e
+	}
+	return out
+}
+
+// undoEntryText describes an undo history entry: its label, when it was recorded and its size.
+// Snapshots persisted before labels existed are shown as "Change".
+func undoEntryText(e undo.Entry) string {
+	label := e.Label
+	if label == "" {
+		label = i18n.T("undo.unlabeled")
+	}
+	return i18n.T("undo.entry", label, e.TS.Local().Format("2006-01-02 15:04:05"), formatBytes(int64(e.Bytes)))
+}
+
+// undoRevertPrompt asks whether to revert to before the history entry at index, saying how many
+// changes that takes back.
+func undoRevertPrompt(hist []undo.Entry, index int) string {
+	e := hist[index]
+	label := e.Label
+	if label == "" {
+		label = i18n.T("undo.unlabeled")
+	}
+	return i18n.N("msg.revert_undo_history", len(hist)-index, label, len(hist)-index)
+}
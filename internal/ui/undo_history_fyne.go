@@ -0,0 +1,66 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/undo"
+)
+
+// showUndoHistoryDialog lists the undo history, newest first. Reverting to a selected entry asks
+// first and then calls revert with the entry's History index.
+func showUndoHistoryDialog(w fyne.Window, hist []undo.Entry, revert func(index int)) {
+	if len(hist) == 0 {
+		dialog.ShowInformation(i18n.T("msg.undo_history"), i18n.T("msg.undo_history_empty"), w)
+		return
+	}
+	rows := undoHistoryRows(hist)
+	selected := -1
+	var d *dialog.CustomDialog
+	revertBtn := widget.NewButton(i18n.T("button.revert_to_here"), func() {
+		if selected < 0 || selected >= len(rows) {
+			return
+		}
+		index := rows[selected].Index
+		dialog.ShowConfirm(i18n.T("msg.undo_history"), undoRevertPrompt(hist, index), func(ok bool) {
+			if ok {
+				d.Hide()
+				revert(index)
+			}
+		}, w)
+	})
+	revertBtn.Importance = widget.WarningImportance
+	revertBtn.Disable()
+	list := widget.NewList(
+		func() int { return len(rows) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(undoEntryText(rows[id]))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = int(id)
+		revertBtn.Enable()
+	}
+	hint := widget.NewLabel(i18n.T("label.undo_history_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	content := container.NewBorder(hint, container.NewHBox(revertBtn), nil, nil, list)
+	d = dialog.NewCustom(i18n.T("msg.undo_history"), i18n.T("msg.close"), content, w)
+	d.Resize(fyne.NewSize(560, 420))
+	d.Show()
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gocomicwriter/internal/undo"
+)
+
+func TestUndoHistoryRowsAndText(t *testing.T) {
+	ts := time.Date(2025, 3, 4, 10, 30, 0, 0, time.Local)
+	hist := []undo.Entry{
+		{Index: 0, Label: "Delete Page 4", TS: ts, Bytes: 2048},
+		{Index: 1, TS: ts.Add(time.Minute), Bytes: 100},
+		{Index: 2, Label: "Split spread of page 2", TS: ts.Add(2 * time.Minute), Bytes: 10},
+	}
+	rows := undoHistoryRows(hist)
+	if len(rows) != 3 || rows[0].Index != 2 || rows[2].Index != 0 {
+		t.Fatalf("rows = %+v, want newest first", rows)
+	}
+	if got, want := undoEntryText(hist[0]), "Delete Page 4 — 2025-03-04 10:30:00 — 2.0 KB"; got != want {
+		t.Errorf("entry text = %q, want %q", got, want)
+	}
+	if got := undoEntryText(hist[1]); !strings.HasPrefix(got, "Change — ") {
+		t.Errorf("unlabeled entry text = %q", got)
+	}
+	if got, want := undoRevertPrompt(hist, 0), `Take back "Delete Page 4" and every later change? (3 changes)`; got != want {
+		t.Errorf("prompt = %q, want %q", got, want)
+	}
+	if got, want := undoRevertPrompt(hist, 2), `Take back "Split spread of page 2"? (1 change)`; got != want {
+		t.Errorf("prompt = %q, want %q", got, want)
+	}
+}
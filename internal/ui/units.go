@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gocomicwriter/internal/config"
+	"gocomicwriter/internal/domain"
+	"gocomicwriter/internal/i18n"
+)
+
+// lengthUnit is the unit size fields are entered in and lengths are shown in. The manifest keeps
+// points either way; only the text changes.
+type lengthUnit string
+
+const (
+	unitMM   lengthUnit = config.UnitsMM
+	unitInch lengthUnit = config.UnitsInch
+)
+
+// unitKeys are the message keys of the unit choices in the preferences dialog.
+var (
+	unitValues = []string{config.UnitsMM, config.UnitsInch}
+	unitKeys   = map[string]string{
+		config.UnitsMM:   "option.units_mm",
+		config.UnitsInch: "option.units_inch",
+	}
+)
+
+// lengthUnitOf is the unit of a config value; anything but inches is millimetres.
+func lengthUnitOf(s string) lengthUnit {
+	if lengthUnit(strings.ToLower(strings.TrimSpace(s))) == unitInch {
+		return unitInch
+	}
+	return unitMM
+}
+
+var (
+	unitMu      sync.Mutex
+	currentUnit = unitMM
+)
+
+// setLengthUnit sets the unit of size fields and labels from the config value s.
+func setLengthUnit(s string) {
+	unitMu.Lock()
+	currentUnit = lengthUnitOf(s)
+	unitMu.Unlock()
+}
+
+// units is the unit set by setLengthUnit, millimetres until then.
+func units() lengthUnit {
+	unitMu.Lock()
+	defer unitMu.Unlock()
+	return currentUnit
+}
+
+func (u lengthUnit) fromPoints(pt float64) float64 {
+	if u == unitInch {
+		return domain.PointsToInches(pt)
+	}
+	return domain.PointsToMM(pt)
+}
+
+func (u lengthUnit) toPoints(v float64) float64 {
+	if u == unitInch {
+		return domain.InchesToPoints(v)
+	}
+	return domain.MMToPoints(v)
+}
+
+// fromMM and toMM convert for settings kept in millimetres, such as the snap grid; millimetres pass
+// through unchanged.
+func (u lengthUnit) fromMM(mm float64) float64 {
+	if u == unitInch {
+		return mm / domain.MMPerInch
+	}
+	return mm
+}
+
+func (u lengthUnit) toMM(v float64) float64 {
+	if u == unitInch {
+		return v * domain.MMPerInch
+	}
+	return v
+}
+
+// symbol is the unit's abbreviation, "mm" or "in".
+func (u lengthUnit) symbol() string { return string(u) }
+
+// name is the unit spelled out for messages, e.g. "millimetres".
+func (u lengthUnit) name() string {
+	if u == unitInch {
+		return i18n.T("units.inches")
+	}
+	return i18n.T("units.millimetres")
+}
+
+// decimals is the precision lengths are shown with: 0.1 mm, or 0.01 in (0.25 mm).
+func (u lengthUnit) decimals() int {
+	if u == unitInch {
+		return 2
+	}
+	return 1
+}
+
+// trimNumber formats v with at most prec decimals and drops trailing zeros: 170, 6.63, 0.13.
+func trimNumber(v float64, prec int) string {
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+// number is the text of a size entry field holding pt points, one decimal more precise than the
+// labels so sizes such as 1/8 in bleed survive a round trip.
+func (u lengthUnit) number(pt float64) string {
+	return trimNumber(u.fromPoints(pt), u.decimals()+1)
+}
+
+// format is a length for labels and status messages, e.g. "12.7 mm" or "0.5 in".
+func (u lengthUnit) format(pt float64) string {
+	return trimNumber(u.fromPoints(pt), u.decimals()) + " " + u.symbol()
+}
+
+// formatSize is a width and height with the unit once, e.g. "25.4 × 12.7 mm".
+func (u lengthUnit) formatSize(w, h float64) string {
+	return trimNumber(u.fromPoints(w), u.decimals()) + " × " + trimNumber(u.fromPoints(h), u.decimals()) + " " + u.symbol()
+}
+
+// label is a form label with the unit appended, e.g. "Gutter (mm)".
+func (u lengthUnit) label(key string) string {
+	return fmt.Sprintf("%s (%s)", i18n.T(key), u.symbol())
+}
+
+// parse reads a size entry field in points. A decimal comma is accepted, and so is a trailing "mm",
+// "in" or '"' that overrides the unit for this value.
+func (u lengthUnit) parse(s string) (float64, error) {
+	t := strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case strings.HasSuffix(t, "mm"):
+		u, t = unitMM, strings.TrimSpace(strings.TrimSuffix(t, "mm"))
+	case strings.HasSuffix(t, "in"):
+		u, t = unitInch, strings.TrimSpace(strings.TrimSuffix(t, "in"))
+	case strings.HasSuffix(t, `"`):
+		u, t = unitInch, strings.TrimSpace(strings.TrimSuffix(t, `"`))
+	}
+	v, err := strconv.ParseFloat(strings.ReplaceAll(t, ",", "."), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a length", strings.TrimSpace(s))
+	}
+	return u.toPoints(v), nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"math"
+	"testing"
+
+	"gocomicwriter/internal/config"
+	"gocomicwriter/internal/storage"
+	"gocomicwriter/internal/vector"
+)
+
+func TestLengthUnitOf(t *testing.T) {
+	for in, want := range map[string]lengthUnit{"": unitMM, "mm": unitMM, " IN ": unitInch, "cm": unitMM, config.UnitsInch: unitInch} {
+		if got := lengthUnitOf(in); got != want {
+			t.Errorf("lengthUnitOf(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLengthUnitFormat(t *testing.T) {
+	// 6.625 × 10.25 in, the US comic trim
+	if got := unitInch.formatSize(477, 738); got != "6.62 × 10.25 in" {
+		t.Errorf("inch size = %q", got)
+	}
+	if got := unitMM.formatSize(477, 738); got != "168.3 × 260.4 mm" {
+		t.Errorf("mm size = %q", got)
+	}
+	if got := unitInch.number(9); got != "0.125" {
+		t.Errorf("inch entry = %q", got)
+	}
+	if got := unitMM.number(9); got != "3.17" {
+		t.Errorf("mm entry = %q", got)
+	}
+	if got := unitMM.format(72); got != "25.4 mm" {
+		t.Errorf("mm label = %q", got)
+	}
+	if got := unitMM.number(0); got != "0" {
+		t.Errorf("zero = %q", got)
+	}
+	if got := unitInch.label("form.gutter"); got != "Gutter (in)" {
+		t.Errorf("label = %q", got)
+	}
+}
+
+func TestLengthUnitParse(t *testing.T) {
+	cases := []struct {
+		u    lengthUnit
+		in   string
+		want float64
+	}{
+		{unitMM, " 25,4 ", 72},
+		{unitInch, "0.125", 9},
+		{unitInch, "12.7mm", 36},
+		{unitMM, `1"`, 72},
+		{unitMM, "2 in", 144},
+	}
+	for _, c := range cases {
+		got, err := c.u.parse(c.in)
+		if err != nil || math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("%s.parse(%q) = %g, %v; want %g", c.u, c.in, got, err, c.want)
+		}
+	}
+	if _, err := unitMM.parse("ten"); err == nil {
+		t.Error("parse accepted text")
+	}
+	// A length survives the entry text round trip
+	for _, u := range []lengthUnit{unitMM, unitInch} {
+		for _, pt := range []float64{9, 477, 738, 595.28} {
+			got, err := u.parse(u.number(pt))
+			if err != nil || math.Abs(got-pt) > 0.05 {
+				t.Errorf("%s round trip of %g pt = %g, %v", u, pt, got, err)
+			}
+		}
+	}
+}
+
+func TestInchUnitInLabels(t *testing.T) {
+	setLengthUnit(config.UnitsInch)
+	t.Cleanup(func() { setLengthUnit("") })
+	if s := panelDrawStatus(vector.R(0, 0, 72, 36)); s != "New panel: 1 × 0.5 in" {
+		t.Errorf("draw status = %q", s)
+	}
+	if got := snapStatus(storage.SnapSettings{NoPanels: true, GridMM: 12.7, Threshold: 4}); got != "Snapping to trim, 0.5 in grid within 4 px (hold Alt to move freely)" {
+		t.Errorf("snap status = %q", got)
+	}
+	s, err := parseSnapSettings(true, "", "0.25", true, true, true, true)
+	if err != nil || math.Abs(s.GridMM-6.35) > 1e-9 {
+		t.Errorf("inch grid = %g mm, %v", s.GridMM, err)
+	}
+}
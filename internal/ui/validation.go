@@ -0,0 +1,35 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"gocomicwriter/internal/i18n"
+	"gocomicwriter/internal/storage"
+)
+
+// validationSummary is the banner text for the validation issues found when a project was opened,
+// e.g. "Project has 3 validation warnings" or "Project has 1 validation error and 2 warnings".
+func validationSummary(issues []storage.ValidationIssue) string {
+	errs := 0
+	for _, v := range issues {
+		if v.Severity == storage.SeverityError {
+			errs++
+		}
+	}
+	warns := len(issues) - errs
+	switch {
+	case errs == 0:
+		return i18n.N("validation.warnings", warns)
+	case warns == 0:
+		return i18n.N("validation.errors", errs)
+	}
+	return i18n.T("validation.errors_and_warnings", i18n.N("validation.error_count", errs), i18n.N("validation.warning_count", warns))
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"testing"
+
+	"gocomicwriter/internal/storage"
+)
+
+func TestValidationSummary(t *testing.T) {
+	warn := storage.ValidationIssue{Severity: storage.SeverityWarning}
+	bad := storage.ValidationIssue{Severity: storage.SeverityError}
+	cases := []struct {
+		issues []storage.ValidationIssue
+		want   string
+	}{
+		{[]storage.ValidationIssue{warn}, "Project has 1 validation warning"},
+		{[]storage.ValidationIssue{warn, warn, warn}, "Project has 3 validation warnings"},
+		{[]storage.ValidationIssue{bad, bad}, "Project has 2 validation errors; exports are blocked until fixed"},
+		{[]storage.ValidationIssue{warn, bad, warn}, "Project has 1 validation error and 2 warnings; exports are blocked until fixed"},
+	}
+	for _, c := range cases {
+		if got := validationSummary(c.issues); got != c.want {
+			t.Errorf("got %q, want %q", got, c.want)
+		}
+	}
+}
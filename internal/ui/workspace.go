@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// workspacePrefsKey holds the canvas tab's workspace layout as JSON.
+const workspacePrefsKey = "workspace.layout"
+
+// Default divider offsets of the canvas tab, as fractions of the split's width or height, and the
+// range a stored offset is clamped to so no side can be restored as a sliver. The splits also keep
+// each side at its minimum size while dragging.
+const (
+	defaultPagesOffset  = 0.15
+	defaultSideOffset   = 0.75
+	defaultAssetsOffset = 0.8
+	minWorkspaceOffset  = 0.05
+	maxWorkspaceOffset  = 0.95
+)
+
+// workspaceLayout is the arrangement of the canvas tab: which side sections are collapsed and where
+// the dividers between the Pages column, the canvas, the right column and the Assets pane are.
+// The zero value is the default layout.
+type workspaceLayout struct {
+	HidePages     bool `json:"hide_pages,omitempty"`
+	HideInspector bool `json:"hide_inspector,omitempty"`
+	HideResults   bool `json:"hide_results,omitempty"`
+	HideAssets    bool `json:"hide_assets,omitempty"`
+	// PagesOffset divides the Pages column from the rest, SideOffset the canvas from the right
+	// column and AssetsOffset the canvas from the Assets pane; 0 is the default.
+	PagesOffset  float64 `json:"pages_offset,omitempty"`
+	SideOffset   float64 `json:"side_offset,omitempty"`
+	AssetsOffset float64 `json:"assets_offset,omitempty"`
+}
+
+// loadWorkspaceLayout reads the stored layout; a missing or unreadable one gives the default.
+func loadWorkspaceLayout(p prefStore) workspaceLayout {
+	var wl workspaceLayout
+	if s := p.StringWithFallback(workspacePrefsKey, ""); s != "" {
+		if err := json.Unmarshal([]byte(s), &wl); err != nil {
+			wl = workspaceLayout{}
+		}
+	}
+	return wl.normalized()
+}
+
+// save stores the layout.
+func (wl workspaceLayout) save(p prefStore) {
+	b, _ := json.Marshal(wl.normalized())
+	p.SetString(workspacePrefsKey, string(b))
+}
+
+// normalized fills unset offsets with their defaults and clamps the others to the allowed range.
+func (wl workspaceLayout) normalized() workspaceLayout {
+	wl.PagesOffset = workspaceOffset(wl.PagesOffset, defaultPagesOffset)
+	wl.SideOffset = workspaceOffset(wl.SideOffset, defaultSideOffset)
+	wl.AssetsOffset = workspaceOffset(wl.AssetsOffset, defaultAssetsOffset)
+	return wl
+}
+
+// workspaceOffset returns v clamped to the allowed divider range, or def when v is unset or invalid.
+func workspaceOffset(v, def float64) float64 {
+	switch {
+	case v <= 0 || math.IsNaN(v):
+		return def
+	case v < minWorkspaceOffset:
+		return minWorkspaceOffset
+	case v > maxWorkspaceOffset:
+		return maxWorkspaceOffset
+	}
+	return v
+}
@@ -0,0 +1,142 @@
+//go:build fyne && cgo
+
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Minimum sizes of the canvas tab's sections; the splits do not let a divider squeeze them further.
+var (
+	workspacePagesMin  = fyne.NewSize(140, 0)
+	workspaceCanvasMin = fyne.NewSize(240, 200)
+	workspaceSideMin   = fyne.NewSize(220, 0)
+)
+
+// workspaceView arranges the canvas tab: the Pages column, the canvas with the Assets pane below it and
+// the right column (pacing, search results, inspector) sit in draggable splits. A collapsed section is
+// taken out of the arrangement and the same objects are put back when it is shown again, so lists keep
+// their selection. The Assets pane collapses to its header bar.
+type workspaceView struct {
+	Layout workspaceLayout
+	// OnChanged is called after a section was collapsed or shown from the Assets bar's button.
+	OnChanged func()
+
+	box                                *fyne.Container // the arrangement, below the top bar
+	pages, canvas, assets, side        fyne.CanvasObject
+	assetsBody, extra                  fyne.CanvasObject
+	results, inspector                 fyne.CanvasObject
+	assetsToggle                       *widget.Button
+	pagesSplit, sideSplit, assetsSplit *container.Split
+}
+
+// newWorkspaceView arranges the sections by layout. extra is shown left of the search results and
+// inspector in the right column while it is visible (the pacing panel).
+func newWorkspaceView(layout workspaceLayout, pages, canvasArea, assetsHeader, assetsBody, extra, results, inspector fyne.CanvasObject) *workspaceView {
+	v := &workspaceView{
+		Layout:     layout.normalized(),
+		box:        container.NewStack(),
+		pages:      withMinSize(pages, workspacePagesMin),
+		canvas:     withMinSize(canvasArea, workspaceCanvasMin),
+		assetsBody: assetsBody,
+		extra:      extra,
+		results:    results,
+		inspector:  inspector,
+	}
+	v.assetsToggle = widget.NewButtonWithIcon("", theme.MenuDropDownIcon(), func() {
+		v.Toggle(&v.Layout.HideAssets)
+		if v.OnChanged != nil {
+			v.OnChanged()
+		}
+	})
+	v.assetsToggle.Importance = widget.LowImportance
+	v.assets = container.NewBorder(container.NewBorder(nil, nil, v.assetsToggle, nil, assetsHeader), nil, nil, nil, assetsBody)
+	v.side = withMinSize(container.NewBorder(nil, nil, extra, nil, container.NewVBox(results, inspector)), workspaceSideMin)
+	v.assetsSplit = container.NewVSplit(v.canvas, v.assets)
+	v.sideSplit = container.NewHSplit(v.assetsSplit, v.side)
+	v.pagesSplit = container.NewHSplit(v.pages, v.sideSplit)
+	v.assetsSplit.Offset = v.Layout.AssetsOffset
+	v.sideSplit.Offset = v.Layout.SideOffset
+	v.pagesSplit.Offset = v.Layout.PagesOffset
+	v.Arrange()
+	return v
+}
+
+// Toggle collapses the section whose hidden flag is given (one of v.Layout's) or shows it again.
+func (v *workspaceView) Toggle(hidden *bool) {
+	*hidden = !*hidden
+	v.Arrange()
+}
+
+// Arrange rebuilds the arrangement from v.Layout and the visibility of the extra side object.
+func (v *workspaceView) Arrange() {
+	setVisible(v.results, !v.Layout.HideResults)
+	setVisible(v.inspector, !v.Layout.HideInspector)
+	setVisible(v.assetsBody, !v.Layout.HideAssets)
+	if v.Layout.HideAssets {
+		v.assetsToggle.SetIcon(theme.MenuExpandIcon())
+	} else {
+		v.assetsToggle.SetIcon(theme.MenuDropDownIcon())
+	}
+
+	var center fyne.CanvasObject = v.assetsSplit
+	if v.Layout.HideAssets {
+		center = container.NewBorder(nil, v.assets, nil, nil, v.canvas)
+	} else {
+		v.assetsSplit.Leading, v.assetsSplit.Trailing = v.canvas, v.assets
+	}
+	main := center
+	if !v.Layout.HideResults || !v.Layout.HideInspector || v.extra.Visible() {
+		v.sideSplit.Leading, v.sideSplit.Trailing = center, v.side
+		main = v.sideSplit
+	}
+	body := main
+	if !v.Layout.HidePages {
+		v.pagesSplit.Leading, v.pagesSplit.Trailing = v.pages, main
+		body = v.pagesSplit
+	}
+	for _, sp := range []*container.Split{v.assetsSplit, v.sideSplit, v.pagesSplit} {
+		sp.Refresh()
+	}
+	v.box.Objects = []fyne.CanvasObject{body}
+	v.box.Refresh()
+}
+
+// Store saves the layout with the current divider offsets.
+func (v *workspaceView) Store(p prefStore) {
+	v.Layout.PagesOffset = v.pagesSplit.Offset
+	v.Layout.SideOffset = v.sideSplit.Offset
+	v.Layout.AssetsOffset = v.assetsSplit.Offset
+	v.Layout.save(p)
+}
+
+// withMinSize returns obj in a container that is at least min in size.
+func withMinSize(obj fyne.CanvasObject, min fyne.Size) fyne.CanvasObject {
+	spacer := canvas.NewRectangle(color.Transparent)
+	spacer.SetMinSize(min)
+	return container.NewStack(spacer, obj)
+}
+
+func setVisible(obj fyne.CanvasObject, visible bool) {
+	if visible {
+		obj.Show()
+	} else {
+		obj.Hide()
+	}
+}
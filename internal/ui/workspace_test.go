@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package ui
+
+import "testing"
+
+func TestWorkspaceLayout_Defaults(t *testing.T) {
+	for _, stored := range []string{"", "not json", `{"pages_offset":-1}`} {
+		p := mapPrefs{}
+		if stored != "" {
+			p[workspacePrefsKey] = stored
+		}
+		wl := loadWorkspaceLayout(p)
+		want := workspaceLayout{PagesOffset: defaultPagesOffset, SideOffset: defaultSideOffset, AssetsOffset: defaultAssetsOffset}
+		if wl != want {
+			t.Errorf("%q: loaded %+v, want %+v", stored, wl, want)
+		}
+	}
+}
+
+func TestWorkspaceLayout_RoundTripAndClamp(t *testing.T) {
+	p := mapPrefs{}
+	workspaceLayout{HideAssets: true, HideResults: true, PagesOffset: 0.01, SideOffset: 0.6, AssetsOffset: 1.5}.save(p)
+	wl := loadWorkspaceLayout(p)
+	want := workspaceLayout{HideAssets: true, HideResults: true, PagesOffset: minWorkspaceOffset, SideOffset: 0.6, AssetsOffset: maxWorkspaceOffset}
+	if wl != want {
+		t.Fatalf("loaded %+v, want %+v", wl, want)
+	}
+}
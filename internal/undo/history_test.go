@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the
+ *  specific language governing permissions and limitations under the License.
+ */
+
+package undo
+
+import (
+	"testing"
+	"time"
+)
+
+func labels(es []Entry) []string {
+	out := make([]string, len(es))
+	for i, e := range es {
+		out[i] = e.Label
+	}
+	return out
+}
+
+func TestHistoryAndRevertTo(t *testing.T) {
+	m := NewManager(Config{MaxBytes: 1024, MaxPerPage: 10, MinInterval: time.Millisecond})
+	t0 := time.Now()
+	for i, l := range []string{"Add panel", "Move panel p2", "Delete Page 4"} {
+		m.PushSnapshot(Snapshot{PageNumber: 0, Blob: []byte{byte('a' + i)}, TS: t0.Add(time.Duration(i) * time.Second), Label: l})
+	}
+	h := m.History(0)
+	if got := labels(h); len(got) != 3 || got[0] != "Add panel" || got[2] != "Delete Page 4" {
+		t.Fatalf("History labels = %v", got)
+	}
+	if h[1].Index != 1 || h[1].Bytes != 1 || !h[1].TS.Equal(t0.Add(time.Second)) {
+		t.Fatalf("entry = %+v", h[1])
+	}
+	// Undo one step, then revert two more: the redo branch is dropped
+	if _, ok := m.Undo(0); !ok {
+		t.Fatal("undo failed")
+	}
+	s, ok := m.RevertTo(0, 0)
+	if !ok || string(s.Blob) != "a" || s.Label != "Add panel" {
+		t.Fatalf("RevertTo = %+v, %v", s, ok)
+	}
+	if n := len(m.History(0)); n != 0 {
+		t.Fatalf("history after revert = %d entries, want 0", n)
+	}
+	if _, ok := m.Redo(0); ok {
+		t.Fatal("redo after revert, want the redo branch dropped")
+	}
+	if tb, _, _ := m.Stats(); tb != 0 {
+		t.Fatalf("bytes after revert = %d", tb)
+	}
+	if _, ok := m.RevertTo(0, 0); ok {
+		t.Fatal("RevertTo on an empty stack succeeded")
+	}
+}
+
+func TestHistoryLabelsFollowEviction(t *testing.T) {
+	// Depth cap: the oldest snapshots go with their labels
+	m := NewManager(Config{MaxBytes: 1024, MaxPerPage: 2, MinInterval: time.Millisecond})
+	t0 := time.Now()
+	for i, l := range []string{"one", "two", "three"} {
+		m.PushSnapshot(Snapshot{PageNumber: 0, Blob: []byte(l), TS: t0.Add(time.Duration(i) * time.Second), Label: l})
+	}
+	if got := labels(m.History(0)); len(got) != 2 || got[0] != "two" || got[1] != "three" {
+		t.Fatalf("labels after depth cap = %v", got)
+	}
+	s, _ := m.RevertTo(0, 0)
+	if string(s.Blob) != "two" || s.Label != "two" {
+		t.Fatalf("revert after eviction = %+v", s)
+	}
+
+	// Memory cap across pages
+	m = NewManager(Config{MaxBytes: 8, MinInterval: time.Millisecond})
+	m.PushSnapshot(Snapshot{PageNumber: 1, Blob: []byte("xxxx"), TS: t0, Label: "old"})
+	m.PushSnapshot(Snapshot{PageNumber: 0, Blob: []byte("yyyy"), TS: t0.Add(time.Second), Label: "mid"})
+	m.PushSnapshot(Snapshot{PageNumber: 0, Blob: []byte("zzzz"), TS: t0.Add(2 * time.Second), Label: "new"})
+	if got := labels(m.History(1)); len(got) != 0 {
+		t.Fatalf("page 1 labels after prune = %v", got)
+	}
+	if got := labels(m.History(0)); len(got) != 2 || got[0] != "mid" || got[1] != "new" {
+		t.Fatalf("page 0 labels after prune = %v", got)
+	}
+
+	// Coalescing keeps the newer label with the newer blob
+	m = NewManager(Config{MaxBytes: 1024, MinInterval: time.Second})
+	m.PushSnapshot(Snapshot{PageNumber: 0, Blob: []byte("a"), TS: t0, Label: "Move panel p1"})
+	m.PushSnapshot(Snapshot{PageNumber: 0, Blob: []byte("b"), TS: t0.Add(time.Millisecond), Label: "Move panel p1 again"})
+	if h := m.History(0); len(h) != 1 || h[0].Label != "Move panel p1 again" {
+		t.Fatalf("coalesced history = %+v", h)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	m := NewManager(Config{MaxBytes: 1024, MaxPerPage: 2, MinInterval: time.Millisecond})
+	m.PushSnapshot(Snapshot{PageNumber: 0, Blob: []byte("current"), TS: time.Now(), Label: "x"})
+	t0 := time.Now().Add(-time.Hour)
+	m.Restore(0, []Snapshot{
+		{Blob: []byte("a"), TS: t0, Label: "one"},
+		{Blob: []byte("b"), TS: t0.Add(time.Second), Label: "two"},
+		{Blob: []byte("c"), TS: t0.Add(2 * time.Second), Label: "three"},
+	})
+	if got := labels(m.History(0)); len(got) != 2 || got[0] != "two" || got[1] != "three" {
+		t.Fatalf("restored labels = %v", got)
+	}
+	if tb, pages, total := m.Stats(); tb != 2 || pages != 1 || total != 2 {
+		t.Fatalf("stats = %d, %d, %d", tb, pages, total)
+	}
+	s, ok := m.Undo(0)
+	if !ok || s.PageNumber != 0 || s.Label != "three" {
+		t.Fatalf("undo after restore = %+v, %v", s, ok)
+	}
+	m.Restore(0, nil)
+	if _, pages, _ := m.Stats(); pages != 0 {
+		t.Fatalf("pages after empty restore = %d", pages)
+	}
+}
@@ -17,11 +17,22 @@ import (
 
 // Snapshot represents a reversible state blob for a page.
 // Blob content is opaque to the manager; size is estimated as len(Blob).
-// TS is when the snapshot was captured.
+// TS is when the snapshot was captured. Label names the change the snapshot undoes, e.g.
+// "Delete Page 4" or "Move panel p2"; it may be empty.
 type Snapshot struct {
 	PageNumber int
 	Blob       []byte
 	TS         time.Time
+	Label      string
+}
+
+// Entry describes a snapshot on a page's undo stack. Index is its position, 0 being the oldest
+// retained snapshot, and is what RevertTo takes.
+type Entry struct {
+	Index int
+	Label string
+	TS    time.Time
+	Bytes int
 }
 
 // Config controls memory and depth caps and coalescing behavior.
@@ -59,7 +70,7 @@ func NewManager(cfg Config) *Manager {
 }
 
 // PushSnapshot records a snapshot for a page. If within MinInterval from the last
-// snapshot on the same page, it replaces the last one. Clears redo stack for that page.
+// snapshot on the same page, it replaces the last one, label included. Clears redo stack for that page.
 func (m *Manager) PushSnapshot(s Snapshot) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -117,6 +128,60 @@ func (m *Manager) Redo(pageNumber int) (Snapshot, bool) {
 	return s, true
 }
 
+// History returns the undo stack of a page, oldest first; the last entry is undone first.
+func (m *Manager) History(pageNumber int) []Entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stack := m.undo[pageNumber]
+	out := make([]Entry, len(stack))
+	for i, s := range stack {
+		out[i] = Entry{Index: i, Label: s.Label, TS: s.TS, Bytes: len(s.Blob)}
+	}
+	return out
+}
+
+// RevertTo undoes every change from the History entry at index on: it returns that snapshot and drops
+// it and all newer ones from the undo stack. The redo stack is cleared, as the reverted changes are
+// not redone one by one. ok is false when index is out of range.
+func (m *Manager) RevertTo(pageNumber, index int) (Snapshot, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stack := m.undo[pageNumber]
+	if index < 0 || index >= len(stack) {
+		return Snapshot{}, false
+	}
+	s := stack[index]
+	for _, d := range stack[index:] {
+		m.totalBytes -= len(d.Blob)
+	}
+	m.undo[pageNumber] = stack[:index]
+	m.redo[pageNumber] = nil
+	return s, true
+}
+
+// Restore replaces the stacks of a page with snaps, oldest first, e.g. snapshots persisted in an
+// earlier session. The caps apply as for pushed snapshots.
+func (m *Manager) Restore(pageNumber int, snaps []Snapshot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, s := range m.undo[pageNumber] {
+		m.totalBytes -= len(s.Blob)
+	}
+	stack := make([]Snapshot, len(snaps))
+	for i, s := range snaps {
+		s.PageNumber = pageNumber
+		stack[i] = s
+		m.totalBytes += len(s.Blob)
+	}
+	if len(stack) == 0 {
+		delete(m.undo, pageNumber)
+	} else {
+		m.undo[pageNumber] = stack
+	}
+	m.redo[pageNumber] = nil
+	m.enforceCapsLocked(pageNumber)
+}
+
 // ClearPage clears undo/redo stacks for a page to free memory.
 func (m *Manager) ClearPage(pageNumber int) {
 	m.mu.Lock()
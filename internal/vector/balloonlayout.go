@@ -29,6 +29,10 @@ import (
 // If no collision-free placement exists, the algorithm returns the least-overlapping candidate.
 // In all cases, the returned rect is clamped to be within the panel inset by Margin.
 // Attempts is the number of candidates evaluated.
+//
+// ContentSize, when set, measures the content instead of using the fixed content size: it is called
+// with the widest content box the panel allows (inner width minus padding) and returns the size of
+// the text wrapped to at most that width. A zero size (e.g. empty text) falls back to content.
 type SuggestOptions struct {
 	ReadingDirection string
 	Padding          float32
@@ -36,6 +40,7 @@ type SuggestOptions struct {
 	GridStep         float32
 	Anchor           Pt
 	HasAnchor        bool
+	ContentSize      func(maxWidth float32) Size
 }
 
 // SuggestBalloonLayout proposes a placement Rect for a balloon given:
@@ -59,6 +64,11 @@ func SuggestBalloonLayout(panel Rect, content Size, obstacles []Rect, opts Sugge
 	}
 
 	inner := panel.Inset(opts.Margin, opts.Margin)
+	if opts.ContentSize != nil {
+		if sz := opts.ContentSize(max(0, inner.W-2*opts.Padding)); sz.W > 0 && sz.H > 0 {
+			content = sz
+		}
+	}
 	bw := max(0, content.W+2*opts.Padding)
 	bh := max(0, content.H+2*opts.Padding)
 	if bw > inner.W {
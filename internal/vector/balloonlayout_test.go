@@ -88,3 +88,25 @@ func TestSuggestBalloonLayout_FallbackWithinBounds(t *testing.T) {
 		t.Fatalf("expected result within inner bounds; got %+v vs inner %+v", pos, inner)
 	}
 }
+
+func TestSuggestBalloonLayout_ContentSizeHook(t *testing.T) {
+	panel := R(0, 0, 300, 200)
+	var asked float32
+	opts := SuggestOptions{ContentSize: func(maxWidth float32) Size {
+		asked = maxWidth
+		return Size{W: 50, H: 30}
+	}}
+	pos, _ := SuggestBalloonLayout(panel, Size{W: 140, H: 80}, nil, opts)
+	// Inner width 284 minus 2*8 padding
+	if asked != 268 {
+		t.Fatalf("expected max width 268, got %.1f", asked)
+	}
+	if pos.W != 66 || pos.H != 46 {
+		t.Fatalf("expected measured size 66x46, got %.1fx%.1f", pos.W, pos.H)
+	}
+	// Nothing measured: the fixed content size is used
+	opts.ContentSize = func(float32) Size { return Size{} }
+	if pos, _ = SuggestBalloonLayout(panel, Size{W: 140, H: 80}, nil, opts); pos.W != 156 || pos.H != 96 {
+		t.Fatalf("expected fallback size 156x96, got %.1fx%.1f", pos.W, pos.H)
+	}
+}
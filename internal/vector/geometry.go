@@ -75,6 +75,9 @@ func (m Affine2D) Apply(p Pt) Pt {
 	}
 }
 
+// Inverse returns the inverse transform, or Identity when m is singular.
+func (m Affine2D) Inverse() Affine2D { return invert(m) }
+
 func Translate(tx, ty float32) Affine2D { return Affine2D{A: 1, D: 1, E: tx, F: ty} }
 func Scale(sx, sy float32) Affine2D     { return Affine2D{A: sx, D: sy} }
 func Rotate(rad float32) Affine2D {
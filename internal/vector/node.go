@@ -173,11 +173,20 @@ func (n *RoundedRectNode) Hit(p Pt) bool {
 type PathNode struct {
 	baseNode
 	path Path
-	bbox Rect // cached approx bounds
+	bbox Rect    // cached approx bounds
+	tol  float32 // curve flattening tolerance for hit-testing
 }
 
 func NewPath(p Path, f Fill, s Stroke) *PathNode {
-	return &PathNode{baseNode: baseNode{xf: Identity, fill: f, stroke: s}, path: p, bbox: p.Bounds()}
+	return &PathNode{baseNode: baseNode{xf: Identity, fill: f, stroke: s}, path: p, bbox: p.Bounds(), tol: DefaultFlattenTolerance}
+}
+
+// SetFlattenTolerance sets the maximum curve deviation used when hit-testing (in path units).
+func (n *PathNode) SetFlattenTolerance(tol float32) {
+	if tol <= 0 {
+		tol = DefaultFlattenTolerance
+	}
+	n.tol = tol
 }
 
 func (n *PathNode) Bounds() Rect {
@@ -205,10 +214,17 @@ func (n *PathNode) Bounds() Rect {
 }
 
 func (n *PathNode) Hit(p Pt) bool {
-	// Simple bbox hit; exporters or advanced tools can do point-in-path later.
 	inv := invert(n.xf)
 	q := inv.Apply(p)
-	return n.bbox.Contains(q)
+	// quick reject outside the bbox grown by half the stroke width
+	edge := float32(0)
+	if n.stroke.Enabled {
+		edge = n.stroke.Width / 2
+	}
+	if !n.bbox.Inset(-edge, -edge).Contains(q) {
+		return false
+	}
+	return n.path.Contains(q, n.fill.Rule, n.tol, edge)
 }
 
 // Group is a container for child nodes with its own transform.
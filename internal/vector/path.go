@@ -8,6 +8,11 @@
 
 package vector
 
+import (
+	"iter"
+	"math"
+)
+
 // Path commands and shapes.
 
 type PathOp uint8
@@ -108,3 +113,166 @@ func (p *Path) Bounds() Rect {
 	}
 	return Rect{X: minX, Y: minY, W: maxX - minX, H: maxY - minY}
 }
+
+// DefaultFlattenTolerance is the maximum distance (in path units) a flattened curve may deviate
+// from the true curve when no explicit tolerance is given.
+const DefaultFlattenTolerance float32 = 0.25
+
+// maxCurveSegments caps the subdivision of a single curve command.
+const maxCurveSegments = 256
+
+// Segment is a straight line piece of a flattened path.
+type Segment struct{ A, B Pt }
+
+// Segments returns an iterator over the path's line segments with quadratic and cubic curves
+// flattened so that they deviate at most tol from the true curve (tol <= 0 uses
+// DefaultFlattenTolerance). Close yields the segment back to the subpath start; open subpaths
+// are not closed.
+func (p *Path) Segments(tol float32) iter.Seq[Segment] {
+	return func(yield func(Segment) bool) { p.flatten(tol, false, yield) }
+}
+
+// flatten walks the flattened segments; with closeOpen, open subpaths are closed implicitly
+// as they are when filling.
+func (p *Path) flatten(tol float32, closeOpen bool, yield func(Segment) bool) {
+	if tol <= 0 {
+		tol = DefaultFlattenTolerance
+	}
+	var start, cur Pt
+	open := false // current subpath has segments not yet closed
+	emit := func(to Pt) bool {
+		if to == cur {
+			return true
+		}
+		s := Segment{A: cur, B: to}
+		cur = to
+		open = true
+		return yield(s)
+	}
+	closeSub := func() bool {
+		ok := true
+		if open && cur != start {
+			ok = yield(Segment{A: cur, B: start})
+		}
+		cur = start
+		open = false
+		return ok
+	}
+	for _, c := range p.Cmds {
+		switch c.Op {
+		case MoveTo:
+			if closeOpen && !closeSub() {
+				return
+			}
+			start = Pt{c.Data[0], c.Data[1]}
+			cur = start
+			open = false
+		case LineTo:
+			if !emit(Pt{c.Data[0], c.Data[1]}) {
+				return
+			}
+		case QuadTo:
+			p0, p1, p2 := cur, Pt{c.Data[0], c.Data[1]}, Pt{c.Data[2], c.Data[3]}
+			// Uniform subdivision error is bounded by |p0-2p1+p2| / (4n²).
+			n := curveSegments(hypot(p0.X-2*p1.X+p2.X, p0.Y-2*p1.Y+p2.Y)/4, tol)
+			for i := 1; i <= n; i++ {
+				t := float32(i) / float32(n)
+				u := 1 - t
+				q := Pt{u*u*p0.X + 2*u*t*p1.X + t*t*p2.X, u*u*p0.Y + 2*u*t*p1.Y + t*t*p2.Y}
+				if i == n {
+					q = p2
+				}
+				if !emit(q) {
+					return
+				}
+			}
+		case CubicTo:
+			p0, p1, p2, p3 := cur, Pt{c.Data[0], c.Data[1]}, Pt{c.Data[2], c.Data[3]}, Pt{c.Data[4], c.Data[5]}
+			// Uniform subdivision error is bounded by 3/4 · max second difference / n².
+			dd := max(hypot(p0.X-2*p1.X+p2.X, p0.Y-2*p1.Y+p2.Y), hypot(p1.X-2*p2.X+p3.X, p1.Y-2*p2.Y+p3.Y))
+			n := curveSegments(dd*3/4, tol)
+			for i := 1; i <= n; i++ {
+				t := float32(i) / float32(n)
+				u := 1 - t
+				a, b, cc, d := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+				q := Pt{a*p0.X + b*p1.X + cc*p2.X + d*p3.X, a*p0.Y + b*p1.Y + cc*p2.Y + d*p3.Y}
+				if i == n {
+					q = p3
+				}
+				if !emit(q) {
+					return
+				}
+			}
+		case Close:
+			if !closeSub() {
+				return
+			}
+		}
+	}
+	if closeOpen {
+		closeSub()
+	}
+}
+
+// curveSegments returns the number of uniform segments needed so that err/n² <= tol.
+func curveSegments(err, tol float32) int {
+	n := int(math.Ceil(math.Sqrt(float64(err / tol))))
+	if n < 1 {
+		return 1
+	}
+	if n > maxCurveSegments {
+		return maxCurveSegments
+	}
+	return n
+}
+
+// Contains reports whether pt lies inside the filled area of the path under the given fill
+// rule. Open subpaths are closed implicitly. Points within edgeTol of an edge count as inside,
+// so clicks exactly on an outline always hit. tol is the curve flattening tolerance.
+func (p *Path) Contains(pt Pt, rule FillRule, tol, edgeTol float32) bool {
+	if edgeTol <= 0 {
+		edgeTol = 1e-4
+	}
+	winding, crossings := 0, 0
+	onEdge := false
+	p.flatten(tol, true, func(s Segment) bool {
+		if distToSegment(pt, s) <= edgeTol {
+			onEdge = true
+			return false
+		}
+		// Half-open rule on Y so a vertex shared by two edges is counted once.
+		if s.A.Y <= pt.Y {
+			if s.B.Y > pt.Y && cross(s, pt) > 0 {
+				winding++
+				crossings++
+			}
+		} else if s.B.Y <= pt.Y && cross(s, pt) < 0 {
+			winding--
+			crossings++
+		}
+		return true
+	})
+	if onEdge {
+		return true
+	}
+	if rule == EvenOdd {
+		return crossings%2 == 1
+	}
+	return winding != 0
+}
+
+// cross is positive when pt lies left of the directed segment A→B.
+func cross(s Segment, pt Pt) float32 {
+	return (s.B.X-s.A.X)*(pt.Y-s.A.Y) - (pt.X-s.A.X)*(s.B.Y-s.A.Y)
+}
+
+func distToSegment(pt Pt, s Segment) float32 {
+	dx, dy := s.B.X-s.A.X, s.B.Y-s.A.Y
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return hypot(pt.X-s.A.X, pt.Y-s.A.Y)
+	}
+	t := ((pt.X-s.A.X)*dx + (pt.Y-s.A.Y)*dy) / l2
+	t = max(0, min(1, t))
+	return hypot(pt.X-(s.A.X+t*dx), pt.Y-(s.A.Y+t*dy))
+}
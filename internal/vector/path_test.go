@@ -25,9 +25,11 @@ func TestPathNode_BoundsAndHit(t *testing.T) {
 		t.Fatalf("unexpected bounds: %+v", b)
 	}
 
-	// Hit uses bbox for now
 	if !n.Hit(Pt{1, 1}) {
-		t.Fatalf("expected hit inside bbox")
+		t.Fatalf("expected hit inside triangle")
+	}
+	if n.Hit(Pt{9, 9}) {
+		t.Fatalf("did not expect hit in the empty bbox corner")
 	}
 	if n.Hit(Pt{20, 20}) {
 		t.Fatalf("did not expect hit far away")
@@ -43,3 +45,149 @@ func TestPathNode_BoundsAndHit(t *testing.T) {
 		t.Fatalf("unexpected transformed bounds: %+v", bb)
 	}
 }
+
+func TestPath_ContainsConcave(t *testing.T) {
+	// A "U" shape: the notch between the arms is outside.
+	var p Path
+	p.MoveTo(0, 0)
+	p.LineTo(10, 0)
+	p.LineTo(10, 30)
+	p.LineTo(20, 30)
+	p.LineTo(20, 0)
+	p.LineTo(30, 0)
+	p.LineTo(30, 40)
+	p.LineTo(0, 40)
+	p.Close()
+	cases := []struct {
+		pt   Pt
+		want bool
+	}{
+		{Pt{5, 5}, true},
+		{Pt{25, 5}, true},
+		{Pt{15, 35}, true},
+		{Pt{15, 10}, false}, // in the notch
+		{Pt{35, 10}, false},
+		{Pt{15, 30}, true}, // exactly on the notch floor
+	}
+	for _, rule := range []FillRule{NonZero, EvenOdd} {
+		for _, tc := range cases {
+			if got := p.Contains(tc.pt, rule, 0, 0); got != tc.want {
+				t.Errorf("rule %d: Contains(%v) = %v, want %v", rule, tc.pt, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestPath_ContainsHoles(t *testing.T) {
+	square := func(p *Path, x, y, s float32, ccw bool) {
+		p.MoveTo(x, y)
+		if ccw {
+			p.LineTo(x, y+s)
+			p.LineTo(x+s, y+s)
+			p.LineTo(x+s, y)
+		} else {
+			p.LineTo(x+s, y)
+			p.LineTo(x+s, y+s)
+			p.LineTo(x, y+s)
+		}
+		p.Close()
+	}
+	// Opposite winding: a hole under both rules.
+	var opp Path
+	square(&opp, 0, 0, 30, false)
+	square(&opp, 10, 10, 10, true)
+	// Same winding: a hole only under even-odd.
+	var same Path
+	square(&same, 0, 0, 30, false)
+	square(&same, 10, 10, 10, false)
+
+	inRing, inHole := Pt{5, 15}, Pt{15, 15}
+	if !opp.Contains(inRing, NonZero, 0, 0) || opp.Contains(inHole, NonZero, 0, 0) {
+		t.Fatalf("nonzero with opposite winding should leave a hole")
+	}
+	if !opp.Contains(inRing, EvenOdd, 0, 0) || opp.Contains(inHole, EvenOdd, 0, 0) {
+		t.Fatalf("even-odd should leave a hole")
+	}
+	if !same.Contains(inHole, NonZero, 0, 0) {
+		t.Fatalf("nonzero with equal winding should fill the inner square")
+	}
+	if same.Contains(inHole, EvenOdd, 0, 0) {
+		t.Fatalf("even-odd should leave a hole regardless of winding")
+	}
+	// The hole's outline itself counts as a hit.
+	if !opp.Contains(Pt{10, 15}, NonZero, 0, 0) {
+		t.Fatalf("point on the hole edge should hit")
+	}
+}
+
+func TestPath_ContainsEdgesAndVertices(t *testing.T) {
+	var p Path
+	p.MoveTo(0, 0)
+	p.LineTo(10, 0)
+	p.LineTo(5, 10)
+	// left open: implicitly closed for filling
+	for _, pt := range []Pt{{0, 0}, {10, 0}, {5, 10}, {5, 0}, {7.5, 5}, {2.5, 5}} {
+		if !p.Contains(pt, NonZero, 0, 0) {
+			t.Errorf("expected point on edge/vertex %v to hit", pt)
+		}
+	}
+	// Rays through a vertex must not double count.
+	if p.Contains(Pt{-1, 0}, EvenOdd, 0, 0) || p.Contains(Pt{-1, 10}, EvenOdd, 0, 0) {
+		t.Errorf("points left of vertices should not hit")
+	}
+	if !p.Contains(Pt{5, 5}, EvenOdd, 0, 0) {
+		t.Errorf("expected interior hit")
+	}
+}
+
+func TestPath_SegmentsFlattenCurves(t *testing.T) {
+	var p Path
+	p.MoveTo(0, 0)
+	p.QuadTo(50, 100, 100, 0)
+	p.Close()
+	count := func(tol float32) int {
+		n := 0
+		for range p.Segments(tol) {
+			n++
+		}
+		return n
+	}
+	coarse, fine := count(5), count(0.05)
+	if coarse < 3 || fine <= coarse {
+		t.Fatalf("expected finer tolerance to produce more segments: coarse=%d fine=%d", coarse, fine)
+	}
+	var last Segment
+	for s := range p.Segments(0.05) {
+		last = s
+	}
+	if last.B != (Pt{0, 0}) {
+		t.Fatalf("close should return to the subpath start, got %v", last.B)
+	}
+	// Apex of the curve is at y=50; the control point at y=100 is outside the shape.
+	if !p.Contains(Pt{50, 45}, NonZero, 0.05, 0) || p.Contains(Pt{50, 60}, NonZero, 0.05, 0) {
+		t.Fatalf("curve flattening should follow the true curve")
+	}
+}
+
+func TestPathNode_HitBurstCorners(t *testing.T) {
+	// Diamond: clicks in the bbox corners must miss, also after rotating the node.
+	var p Path
+	p.MoveTo(10, 0)
+	p.LineTo(20, 10)
+	p.LineTo(10, 20)
+	p.LineTo(0, 10)
+	p.Close()
+	n := NewPath(p, Fill{Enabled: true}, Stroke{})
+	if !n.Hit(Pt{10, 10}) || n.Hit(Pt{1, 1}) || n.Hit(Pt{19, 19}) {
+		t.Fatalf("diamond hit-test should ignore bbox corners")
+	}
+	n.SetTransform(Translate(100, 0))
+	if !n.Hit(Pt{110, 10}) || n.Hit(Pt{101, 1}) {
+		t.Fatalf("hit-test should use the inverse transform")
+	}
+	n.SetTransform(Identity)
+	n.SetStroke(Stroke{Enabled: true, Width: 4})
+	if !n.Hit(Pt{4, 4}) { // just outside the edge but within half the stroke width
+		t.Fatalf("stroke width should widen the hit area")
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package vector
+
+import (
+	"image"
+	"math"
+)
+
+// Software rasterization of scene nodes for preview canvases. Shapes are drawn from a signed
+// distance to their outline in node-local space, so rotated, scaled and sheared nodes render
+// exactly and edges get one pixel of anti-aliasing.
+
+// Corners returns the node's outline corners in parent coordinates, in NW, NE, SW, SE order of the
+// untransformed geometry. Unlike Bounds, the corners follow rotation.
+func Corners(n Node) [4]Pt {
+	r, xf := localBox(n)
+	return [4]Pt{
+		xf.Apply(Pt{r.X, r.Y}),
+		xf.Apply(Pt{r.X + r.W, r.Y}),
+		xf.Apply(Pt{r.X, r.Y + r.H}),
+		xf.Apply(Pt{r.X + r.W, r.Y + r.H}),
+	}
+}
+
+// localBox returns the untransformed geometry box of n and its transform.
+func localBox(n Node) (Rect, Affine2D) {
+	switch v := n.(type) {
+	case *RectNode:
+		return v.rect, v.xf
+	case *EllipseNode:
+		return v.rect, v.xf
+	case *RoundedRectNode:
+		return v.rect, v.xf
+	case *PathNode:
+		return v.bbox, v.xf
+	}
+	return n.Bounds(), Identity
+}
+
+// RasterizeNode draws n onto dst, where m maps the node's parent coordinates to dst pixels.
+// Fill is painted first, then the stroke centred on the outline.
+func RasterizeNode(dst *image.RGBA, n Node, m Affine2D) {
+	if g, ok := n.(*Group); ok {
+		xf := m.Mul(g.xf)
+		for _, c := range g.Children {
+			RasterizeNode(dst, c, xf)
+		}
+		return
+	}
+	dist := signedDistance(n)
+	if dist == nil {
+		return
+	}
+	r, xf := localBox(n)
+	total := m.Mul(xf)
+	det := total.A*total.D - total.B*total.C
+	if det == 0 {
+		return
+	}
+	inv := invert(total)
+	// local units per device pixel; drives the anti-aliasing ramp
+	aa := float32(1 / math.Sqrt(math.Abs(float64(det))))
+	fill, stroke := n.Fill(), n.Stroke()
+	hw := float32(0)
+	if stroke.Enabled {
+		hw = stroke.Width / 2
+	}
+	grow := hw + aa
+	area := deviceRect(total, r.Inset(-grow, -grow)).Intersect(dst.Bounds())
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			d := dist(inv.Apply(Pt{float32(x) + 0.5, float32(y) + 0.5}))
+			if fill.Enabled {
+				blendPixel(dst, x, y, fill.Color, coverage(d, aa))
+			}
+			if stroke.Enabled && hw > 0 {
+				blendPixel(dst, x, y, stroke.Color, coverage(abs32(d)-hw, aa))
+			}
+		}
+	}
+}
+
+// signedDistance returns a function giving the distance from a node-local point to the node's
+// outline, negative inside. Nil means the node type cannot be rasterized.
+func signedDistance(n Node) func(Pt) float32 {
+	switch v := n.(type) {
+	case *RectNode:
+		r := v.rect
+		return func(q Pt) float32 { return roundedBoxDistance(q, r, 0) }
+	case *RoundedRectNode:
+		r, rad := v.rect, v.r
+		return func(q Pt) float32 { return roundedBoxDistance(q, r, rad) }
+	case *EllipseNode:
+		r := v.rect
+		return func(q Pt) float32 { return ellipseDistance(q, r) }
+	case *PathNode:
+		var segs []Segment
+		for s := range v.path.Segments(v.tol) {
+			segs = append(segs, s)
+		}
+		path, rule, tol := v.path, v.fill.Rule, v.tol
+		return func(q Pt) float32 {
+			d := float32(math.MaxFloat32)
+			for _, s := range segs {
+				if sd := distToSegment(q, s); sd < d {
+					d = sd
+				}
+			}
+			if path.Contains(q, rule, tol, 0) {
+				return -d
+			}
+			return d
+		}
+	}
+	return nil
+}
+
+func roundedBoxDistance(q Pt, r Rect, rad float32) float32 {
+	hx, hy := abs32(r.W)/2, abs32(r.H)/2
+	rad = max(0, min(rad, min(hx, hy)))
+	dx := abs32(q.X-(r.X+r.W/2)) - (hx - rad)
+	dy := abs32(q.Y-(r.Y+r.H/2)) - (hy - rad)
+	outside := float32(math.Hypot(float64(max(dx, 0)), float64(max(dy, 0))))
+	return outside + min(max(dx, dy), 0) - rad
+}
+
+// ellipseDistance uses the usual first-order approximation, exact on the outline and good to a
+// fraction of a pixel in the anti-aliasing band.
+func ellipseDistance(q Pt, r Rect) float32 {
+	rx, ry := abs32(r.W)/2, abs32(r.H)/2
+	if rx == 0 || ry == 0 {
+		return math.MaxFloat32
+	}
+	px, py := q.X-(r.X+r.W/2), q.Y-(r.Y+r.H/2)
+	k0 := float32(math.Hypot(float64(px/rx), float64(py/ry)))
+	k1 := float32(math.Hypot(float64(px/(rx*rx)), float64(py/(ry*ry))))
+	if k1 == 0 {
+		return -min(rx, ry)
+	}
+	return k0 * (k0 - 1) / k1
+}
+
+// coverage maps a signed distance to a pixel coverage in [0,1] with a one-pixel ramp.
+func coverage(d, aa float32) float32 {
+	c := 0.5 - d/aa
+	return max(0, min(1, c))
+}
+
+// deviceRect returns the integer pixel rectangle covering r transformed by m.
+func deviceRect(m Affine2D, r Rect) image.Rectangle {
+	minX, minY := float32(math.MaxFloat32), float32(math.MaxFloat32)
+	maxX, maxY := float32(-math.MaxFloat32), float32(-math.MaxFloat32)
+	for _, c := range []Pt{{r.X, r.Y}, {r.X + r.W, r.Y}, {r.X, r.Y + r.H}, {r.X + r.W, r.Y + r.H}} {
+		p := m.Apply(c)
+		minX, minY = min(minX, p.X), min(minY, p.Y)
+		maxX, maxY = max(maxX, p.X), max(maxY, p.Y)
+	}
+	return image.Rect(int(math.Floor(float64(minX))), int(math.Floor(float64(minY))), int(math.Ceil(float64(maxX))), int(math.Ceil(float64(maxY))))
+}
+
+// blendPixel composites c with the given coverage over the premultiplied pixel at (x, y).
+func blendPixel(dst *image.RGBA, x, y int, c Color, cov float32) {
+	if cov <= 0 || c.A == 0 {
+		return
+	}
+	a := float32(c.A) / 255 * cov
+	i := dst.PixOffset(x, y)
+	px := dst.Pix[i : i+4 : i+4]
+	px[0] = uint8(float32(c.R)*a + float32(px[0])*(1-a) + 0.5)
+	px[1] = uint8(float32(c.G)*a + float32(px[1])*(1-a) + 0.5)
+	px[2] = uint8(float32(c.B)*a + float32(px[2])*(1-a) + 0.5)
+	px[3] = uint8(255*a + float32(px[3])*(1-a) + 0.5)
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
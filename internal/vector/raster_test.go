@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package vector
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+var red = Color{R: 255, A: 255}
+
+func rotatedAbout(n Node, cx, cy, rad float32) {
+	n.SetTransform(Translate(cx, cy).Mul(Rotate(rad)).Mul(Translate(-cx, -cy)))
+}
+
+func TestCorners_FollowRotation(t *testing.T) {
+	n := NewRect(R(0, 0, 10, 10), Fill{}, Stroke{})
+	rotatedAbout(n, 5, 5, math.Pi/4)
+	c := Corners(n)
+	// NW corner of a square rotated 45° about its centre points straight up
+	if math.Abs(float64(c[0].X-5)) > 1e-3 || math.Abs(float64(c[0].Y-(5-5*math.Sqrt2))) > 1e-3 {
+		t.Fatalf("unexpected NW corner %+v", c[0])
+	}
+	b := n.Bounds()
+	for _, p := range c {
+		if p.X < b.X-1e-3 || p.X > b.X+b.W+1e-3 || p.Y < b.Y-1e-3 || p.Y > b.Y+b.H+1e-3 {
+			t.Fatalf("corner %+v outside bounds %+v", p, b)
+		}
+	}
+}
+
+func TestRasterizeNode_RotatedRect(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	n := NewRect(R(10, 10, 20, 20), Fill{Enabled: true, Color: red}, Stroke{})
+	rotatedAbout(n, 20, 20, math.Pi/4)
+	RasterizeNode(dst, n, Identity)
+	if a := dst.RGBAAt(20, 20).A; a != 255 {
+		t.Fatalf("centre not filled: alpha %d", a)
+	}
+	// bbox corners of the rotated square lie outside the diamond
+	b := n.Bounds()
+	if a := dst.RGBAAt(int(b.X)+1, int(b.Y)+1).A; a != 0 {
+		t.Fatalf("bbox corner painted: alpha %d", a)
+	}
+	// tip of the diamond is inside
+	if a := dst.RGBAAt(20, int(b.Y)+2).A; a == 0 {
+		t.Fatalf("diamond tip not painted")
+	}
+}
+
+func TestRasterizeNode_StrokeAndScale(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	n := NewRect(R(0, 0, 10, 10), Fill{}, Stroke{Enabled: true, Color: red, Width: 1})
+	// page -> pixels at 2x zoom with an offset
+	RasterizeNode(dst, n, Translate(5, 5).Mul(Scale(2, 2)))
+	if a := dst.RGBAAt(5, 15).A; a == 0 {
+		t.Fatalf("left edge stroke missing")
+	}
+	if a := dst.RGBAAt(15, 15).A; a != 0 {
+		t.Fatalf("interior painted without fill: alpha %d", a)
+	}
+	if a := dst.RGBAAt(30, 30).A; a != 0 {
+		t.Fatalf("outside painted: alpha %d", a)
+	}
+}
+
+func TestRasterizeNode_EllipseAndGroup(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	e := NewEllipse(R(0, 0, 20, 10), Fill{Enabled: true, Color: red}, Stroke{})
+	g := NewGroup(e)
+	g.SetTransform(Translate(10, 10))
+	RasterizeNode(dst, g, Identity)
+	if a := dst.RGBAAt(20, 15).A; a != 255 {
+		t.Fatalf("ellipse centre alpha %d", a)
+	}
+	if a := dst.RGBAAt(10, 10).A; a != 0 {
+		t.Fatalf("ellipse bbox corner painted: alpha %d", a)
+	}
+}
+
+func TestRasterizeNode_AntiAliasedEdge(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	n := NewRect(R(0, 0, 4.5, 10), Fill{Enabled: true, Color: red}, Stroke{})
+	RasterizeNode(dst, n, Identity)
+	if a := dst.RGBAAt(4, 5).A; a < 100 || a > 155 {
+		t.Fatalf("expected half coverage on the edge pixel, got %d", a)
+	}
+}
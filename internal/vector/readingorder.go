@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package vector
+
+import "sort"
+
+// ReadingOrder returns the indices of rects in reading order: rows from top to bottom and, within a
+// row, left to right, or right to left when rtl is set. Rows are formed in order of the rects'
+// vertical centers: a rect joins the current row when its center lies within the vertical extent of
+// the row's first rect, so balloons that are nearly level are read across before going down. Equal
+// positions keep their index order, which makes the result deterministic.
+func ReadingOrder(rects []Rect, rtl bool) []int {
+	idx := make([]int, len(rects))
+	for i := range idx {
+		idx[i] = i
+	}
+	cy := func(i int) float32 { return rects[i].Y + rects[i].H/2 }
+	cx := func(i int) float32 { return rects[i].X + rects[i].W/2 }
+	sort.SliceStable(idx, func(a, b int) bool { return cy(idx[a]) < cy(idx[b]) })
+
+	out := make([]int, 0, len(idx))
+	for start := 0; start < len(idx); {
+		first := rects[idx[start]]
+		end := start + 1
+		for end < len(idx) && cy(idx[end]) <= first.Y+first.H {
+			end++
+		}
+		row := idx[start:end]
+		sort.SliceStable(row, func(a, b int) bool {
+			if rtl {
+				return cx(row[a]) > cx(row[b])
+			}
+			return cx(row[a]) < cx(row[b])
+		})
+		out = append(out, row...)
+		start = end
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2025 by Alexander Drost, Oldenburg, Germany.
+ * This file is licensed to you under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.  You may obtain a copy of the License at
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ * Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  See the License for the specific language governing permissions and limitations under the License.
+ */
+
+package vector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadingOrder(t *testing.T) {
+	cases := []struct {
+		name  string
+		rects []Rect
+		rtl   bool
+		want  []int
+	}{
+		{"empty", nil, false, []int{}},
+		{"level_ltr", []Rect{R(120, 10, 80, 40), R(10, 14, 80, 40)}, false, []int{1, 0}},
+		{"level_rtl", []Rect{R(120, 10, 80, 40), R(10, 14, 80, 40)}, true, []int{0, 1}},
+		{"stacked", []Rect{R(10, 100, 80, 40), R(100, 10, 80, 40)}, false, []int{1, 0}},
+		// The lower-left balloon starts below the first one's middle: read top-right first
+		{"staggered", []Rect{R(10, 40, 80, 40), R(120, 0, 80, 40)}, false, []int{1, 0}},
+		// Two rows: the right balloon of the top row is nearly level with its left neighbour
+		{"rows", []Rect{R(10, 120, 60, 30), R(10, 10, 60, 30), R(100, 20, 60, 30), R(100, 118, 60, 30)}, false, []int{1, 2, 0, 3}},
+		{"rows_rtl", []Rect{R(10, 120, 60, 30), R(10, 10, 60, 30), R(100, 20, 60, 30), R(100, 118, 60, 30)}, true, []int{2, 1, 3, 0}},
+		{"ties_keep_index", []Rect{R(10, 10, 40, 40), R(10, 10, 40, 40), R(10, 10, 40, 40)}, true, []int{0, 1, 2}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ReadingOrder(tc.rects, tc.rtl); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("ReadingOrder = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}